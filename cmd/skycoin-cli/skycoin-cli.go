@@ -16,6 +16,9 @@ import (
 	_ "github.com/skycoin/skycoin/src/wallet/bip44wallet"
 	_ "github.com/skycoin/skycoin/src/wallet/collection"
 	_ "github.com/skycoin/skycoin/src/wallet/deterministic"
+	_ "github.com/skycoin/skycoin/src/wallet/hardwarewallet"
+	_ "github.com/skycoin/skycoin/src/wallet/multisig"
+	_ "github.com/skycoin/skycoin/src/wallet/watchwallet"
 	_ "github.com/skycoin/skycoin/src/wallet/xpubwallet"
 )
 
@@ -34,7 +37,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := skyCLI.Execute(); err != nil {
-		os.Exit(1)
+	if c, err := skyCLI.ExecuteC(); err != nil {
+		cli.PrintError(c, err)
+		os.Exit(cli.ExitCode(err))
 	}
 }
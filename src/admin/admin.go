@@ -0,0 +1,220 @@
+/*
+Package admin performs the runtime node management operations of the admin API
+*/
+package admin
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/logging"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+var (
+	// ErrAdminAPIDisabled is returned while trying to perform an admin operation while
+	// the EnableAdminAPI option is false
+	ErrAdminAPIDisabled = NewError(errors.New("Admin API is disabled"))
+	// ErrDBVerificationRunning is returned when starting a DB verification while one is running
+	ErrDBVerificationRunning = NewError(errors.New("DB verification is already running"))
+	// ErrLogToFileDisabled is returned when rotating the log file while the node does not log to a file
+	ErrLogToFileDisabled = NewError(errors.New("Logging to a file is disabled"))
+	// ErrInvalidLogLevel is returned when setting an unknown log level
+	ErrInvalidLogLevel = NewError(errors.New("Invalid log level, must be debug, info, warn, error, fatal or panic"))
+
+	logger = logging.MustGetLogger("admin")
+)
+
+// LogRotator rotates the log file of the node
+type LogRotator interface {
+	// RotateLogFile closes the log file and continues logging to a new one.
+	// Returns the path of the new log file.
+	RotateLogFile() (string, error)
+}
+
+// DBVerification is the state of the last DB verification
+type DBVerification struct {
+	Running    bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Error is the error of a failed verification, empty if the DB is valid
+	Error string
+}
+
+// Admin performs the node management operations that otherwise need a restart of the node:
+// verifying the DB, rotating the log file, changing the log level and shutting down the node.
+type Admin struct {
+	config     Config
+	db         *dbutil.DB
+	logRotator LogRotator
+	// checkDatabase verifies the DB, replaced in tests
+	checkDatabase func(db *dbutil.DB, pubkey cipher.PubKey, quit chan struct{}) error
+
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+	quit         chan struct{}
+	wg           sync.WaitGroup
+
+	sync.Mutex
+	verification *DBVerification
+}
+
+// NewAdmin creates an Admin. logRotator is nil if the node does not log to a file.
+func NewAdmin(c Config, db *dbutil.DB, logRotator LogRotator) *Admin {
+	if !c.EnableAdminAPI {
+		logger.Info("Admin API is disabled")
+	}
+
+	return &Admin{
+		config:        c,
+		db:            db,
+		logRotator:    logRotator,
+		checkDatabase: visor.CheckDatabase,
+		shutdown:      make(chan struct{}),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Shutdown stops a running DB verification and waits for it to return
+func (a *Admin) Shutdown() {
+	close(a.quit)
+	a.wg.Wait()
+}
+
+// ShutdownRequested returns a channel that is closed when a shutdown of the node is requested
+func (a *Admin) ShutdownRequested() <-chan struct{} {
+	return a.shutdown
+}
+
+// RequestShutdown requests a clean shutdown of the node. The node shuts down
+// as if it was interrupted, after the API responses in progress are sent.
+func (a *Admin) RequestShutdown() error {
+	if !a.config.EnableAdminAPI {
+		return ErrAdminAPIDisabled
+	}
+
+	a.shutdownOnce.Do(func() {
+		logger.Critical().Info("Shutdown requested by the admin API")
+		close(a.shutdown)
+	})
+
+	return nil
+}
+
+// VerifyDB starts a verification of the block signatures and the history of the DB in the background,
+// as done by the -verify-db option at startup. Returns the state of the started verification.
+func (a *Admin) VerifyDB() (*DBVerification, error) {
+	if !a.config.EnableAdminAPI {
+		return nil, ErrAdminAPIDisabled
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if a.verification != nil && a.verification.Running {
+		return nil, ErrDBVerificationRunning
+	}
+
+	a.verification = &DBVerification{
+		Running:   true,
+		StartedAt: time.Now().UTC(),
+	}
+
+	logger.Info("DB verification started by the admin API")
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		err := a.checkDatabase(a.db, a.config.BlockchainPubkey, a.quit)
+
+		a.Lock()
+		defer a.Unlock()
+
+		a.verification.Running = false
+		a.verification.FinishedAt = time.Now().UTC()
+
+		switch err {
+		case nil:
+			logger.Info("DB verification succeeded")
+		case visor.ErrVerifyStopped:
+			a.verification.Error = err.Error()
+			logger.Info("DB verification stopped")
+		default:
+			a.verification.Error = err.Error()
+			logger.Critical().WithError(err).Error("DB verification failed")
+		}
+	}()
+
+	v := *a.verification
+	return &v, nil
+}
+
+// GetDBVerification returns the state of the last DB verification started by VerifyDB,
+// nil if no verification was started
+func (a *Admin) GetDBVerification() (*DBVerification, error) {
+	if !a.config.EnableAdminAPI {
+		return nil, ErrAdminAPIDisabled
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if a.verification == nil {
+		return nil, nil
+	}
+
+	v := *a.verification
+	return &v, nil
+}
+
+// RotateLogFile closes the log file of the node and continues logging to a new one.
+// Returns the path of the new log file.
+func (a *Admin) RotateLogFile() (string, error) {
+	if !a.config.EnableAdminAPI {
+		return "", ErrAdminAPIDisabled
+	}
+
+	if a.logRotator == nil {
+		return "", ErrLogToFileDisabled
+	}
+
+	path, err := a.logRotator.RotateLogFile()
+	if err != nil {
+		return "", err
+	}
+
+	logger.WithField("path", path).Info("Log file rotated by the admin API")
+
+	return path, nil
+}
+
+// GetLogLevel returns the log level of the node
+func (a *Admin) GetLogLevel() (string, error) {
+	if !a.config.EnableAdminAPI {
+		return "", ErrAdminAPIDisabled
+	}
+
+	return logging.GetLevel().String(), nil
+}
+
+// SetLogLevel changes the log level of the node until it is restarted
+func (a *Admin) SetLogLevel(level string) error {
+	if !a.config.EnableAdminAPI {
+		return ErrAdminAPIDisabled
+	}
+
+	l, err := logging.LevelFromString(level)
+	if err != nil {
+		return ErrInvalidLogLevel
+	}
+
+	logging.SetLevel(l)
+
+	logger.Critical().Infof("Log level set to %s by the admin API", l)
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/logging"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+type fakeLogRotator struct {
+	path string
+	err  error
+}
+
+func (f fakeLogRotator) RotateLogFile() (string, error) {
+	return f.path, f.err
+}
+
+func enabledConfig() Config {
+	c := NewConfig()
+	c.EnableAdminAPI = true
+	return c
+}
+
+// waitVerification waits for the DB verification to finish
+func waitVerification(t *testing.T, a *Admin) *DBVerification {
+	for i := 0; i < 100; i++ {
+		v, err := a.GetDBVerification()
+		require.NoError(t, err)
+		if !v.Running {
+			return v
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("DB verification did not finish")
+	return nil
+}
+
+func TestAdminDisabled(t *testing.T) {
+	a := NewAdmin(NewConfig(), nil, fakeLogRotator{})
+	defer a.Shutdown()
+
+	_, err := a.VerifyDB()
+	require.Equal(t, ErrAdminAPIDisabled, err)
+	_, err = a.GetDBVerification()
+	require.Equal(t, ErrAdminAPIDisabled, err)
+	_, err = a.RotateLogFile()
+	require.Equal(t, ErrAdminAPIDisabled, err)
+	_, err = a.GetLogLevel()
+	require.Equal(t, ErrAdminAPIDisabled, err)
+	require.Equal(t, ErrAdminAPIDisabled, a.SetLogLevel("debug"))
+	require.Equal(t, ErrAdminAPIDisabled, a.RequestShutdown())
+	_, err = a.VerifyChain(Checkpoint{})
+	require.Equal(t, ErrAdminAPIDisabled, err)
+}
+
+func TestAdminVerifyDB(t *testing.T) {
+	a := NewAdmin(enabledConfig(), nil, nil)
+	defer a.Shutdown()
+
+	v, err := a.GetDBVerification()
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	release := make(chan error)
+	a.checkDatabase = func(db *dbutil.DB, pubkey cipher.PubKey, quit chan struct{}) error {
+		return <-release
+	}
+
+	v, err = a.VerifyDB()
+	require.NoError(t, err)
+	require.True(t, v.Running)
+	require.False(t, v.StartedAt.IsZero())
+
+	_, err = a.VerifyDB()
+	require.Equal(t, ErrDBVerificationRunning, err)
+
+	release <- nil
+	v = waitVerification(t, a)
+	require.False(t, v.FinishedAt.IsZero())
+	require.Empty(t, v.Error)
+
+	// A finished verification can be started again
+	_, err = a.VerifyDB()
+	require.NoError(t, err)
+
+	release <- errors.New("signature verification failed")
+	v = waitVerification(t, a)
+	require.Equal(t, "signature verification failed", v.Error)
+}
+
+func TestAdminShutdownStopsVerifyDB(t *testing.T) {
+	a := NewAdmin(enabledConfig(), nil, nil)
+
+	a.checkDatabase = func(db *dbutil.DB, pubkey cipher.PubKey, quit chan struct{}) error {
+		<-quit
+		return visor.ErrVerifyStopped
+	}
+
+	_, err := a.VerifyDB()
+	require.NoError(t, err)
+
+	a.Shutdown()
+
+	v, err := a.GetDBVerification()
+	require.NoError(t, err)
+	require.False(t, v.Running)
+	require.Equal(t, visor.ErrVerifyStopped.Error(), v.Error)
+}
+
+func TestAdminRotateLogFile(t *testing.T) {
+	a := NewAdmin(enabledConfig(), nil, nil)
+	defer a.Shutdown()
+
+	_, err := a.RotateLogFile()
+	require.Equal(t, ErrLogToFileDisabled, err)
+
+	a = NewAdmin(enabledConfig(), nil, fakeLogRotator{path: "logs/new.log"})
+	defer a.Shutdown()
+
+	path, err := a.RotateLogFile()
+	require.NoError(t, err)
+	require.Equal(t, "logs/new.log", path)
+
+	a = NewAdmin(enabledConfig(), nil, fakeLogRotator{err: errors.New("disk full")})
+	defer a.Shutdown()
+
+	_, err = a.RotateLogFile()
+	require.EqualError(t, err, "disk full")
+}
+
+func TestAdminLogLevel(t *testing.T) {
+	level := logging.GetLevel()
+	defer logging.SetLevel(level)
+
+	a := NewAdmin(enabledConfig(), nil, nil)
+	defer a.Shutdown()
+
+	require.NoError(t, a.SetLogLevel("warn"))
+	l, err := a.GetLogLevel()
+	require.NoError(t, err)
+	require.Equal(t, "warning", l)
+
+	require.Equal(t, ErrInvalidLogLevel, a.SetLogLevel("verbose"))
+	l, err = a.GetLogLevel()
+	require.NoError(t, err)
+	require.Equal(t, "warning", l)
+}
+
+func TestAdminRequestShutdown(t *testing.T) {
+	a := NewAdmin(enabledConfig(), nil, nil)
+	defer a.Shutdown()
+
+	select {
+	case <-a.ShutdownRequested():
+		t.Fatal("shutdown requested")
+	default:
+	}
+
+	require.NoError(t, a.RequestShutdown())
+	// Requesting a shutdown again does not close the channel twice
+	require.NoError(t, a.RequestShutdown())
+
+	select {
+	case <-a.ShutdownRequested():
+	default:
+		t.Fatal("shutdown not requested")
+	}
+}
+
+func makeChain(t *testing.T, n int) []*coin.SignedBlock {
+	gb, err := coin.NewGenesisBlock(testutil.MakeAddress(), 100e6, 1e9)
+	require.NoError(t, err)
+
+	blocks := []*coin.SignedBlock{{Block: *gb}}
+	for i := 1; i < n; i++ {
+		var txn coin.Transaction
+		require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 1e6, uint64(i)))
+
+		b, err := coin.NewBlock(blocks[i-1].Block, 1e9+uint64(i)*10, testutil.RandSHA256(t), coin.Transactions{txn}, func(*coin.Transaction) (uint64, error) {
+			return 0, nil
+		})
+		require.NoError(t, err)
+		blocks = append(blocks, &coin.SignedBlock{Block: *b})
+	}
+
+	return blocks
+}
+
+func TestVerifyChainHeaders(t *testing.T) {
+	blocks := makeChain(t, 5)
+	getBlock := func(blocks []*coin.SignedBlock) func(seq uint64) (*coin.SignedBlock, error) {
+		return func(seq uint64) (*coin.SignedBlock, error) {
+			if seq >= uint64(len(blocks)) || blocks[seq] == nil {
+				return nil, errors.New("not found")
+			}
+			return blocks[seq], nil
+		}
+	}
+
+	checkpoint := Checkpoint{
+		Seq:  3,
+		Hash: blocks[3].HashHeader(),
+	}
+
+	v, err := verifyChainHeaders(getBlock(blocks), checkpoint, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), v.Verified)
+	require.Empty(t, v.Mismatches)
+
+	// A different checkpoint hash
+	v, err = verifyChainHeaders(getBlock(blocks), Checkpoint{Seq: 3, Hash: blocks[2].HashHeader()}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ChainMismatch{{
+		Seq:   3,
+		Error: fmt.Sprintf("block hash %s does not match the checkpoint hash %s", blocks[3].HashHeader().Hex(), blocks[2].HashHeader().Hex()),
+	}}, v.Mismatches)
+
+	// A block whose transactions were changed
+	tampered := makeChain(t, 5)
+	tampered[1].Body.Transactions[0].Out[0].Coins = 2e6
+	v, err = verifyChainHeaders(getBlock(tampered), Checkpoint{Seq: 3, Hash: tampered[3].HashHeader()}, nil)
+	require.NoError(t, err)
+	require.Len(t, v.Mismatches, 1)
+	require.Equal(t, uint64(1), v.Mismatches[0].Seq)
+	require.Contains(t, v.Mismatches[0].Error, "does not match the hash")
+
+	// A block replaced by a block of another chain
+	other := makeChain(t, 5)
+	replaced := append([]*coin.SignedBlock{}, blocks...)
+	replaced[2] = other[2]
+	v, err = verifyChainHeaders(getBlock(replaced), checkpoint, nil)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 3}, mismatchSeqs(v.Mismatches))
+
+	// A missing block
+	missing := append([]*coin.SignedBlock{}, blocks...)
+	missing[1] = nil
+	v, err = verifyChainHeaders(getBlock(missing), checkpoint, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ChainMismatch{{Seq: 1, Error: "block not found"}}, v.Mismatches)
+
+	// The verification stops with the node
+	quit := make(chan struct{})
+	close(quit)
+	_, err = verifyChainHeaders(getBlock(blocks), checkpoint, quit)
+	require.Equal(t, ErrChainVerificationStopped, err)
+}
+
+func mismatchSeqs(ms []ChainMismatch) []uint64 {
+	seqs := make([]uint64, len(ms))
+	for i, m := range ms {
+		seqs[i] = m.Seq
+	}
+	return seqs
+}
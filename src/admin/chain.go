@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+// MaxChainMismatches is the number of mismatches after which a chain verification stops
+const MaxChainMismatches = 100
+
+var (
+	// ErrCheckpointNotReached is returned when verifying the chain up to a checkpoint above the head block
+	ErrCheckpointNotReached = NewError(errors.New("The checkpoint height is above the head block of the blockchain"))
+	// ErrChainVerificationStopped is returned when the node shuts down during a chain verification
+	ErrChainVerificationStopped = errors.New("Chain verification stopped")
+)
+
+// Checkpoint is the trusted hash of the block at a height of the blockchain
+type Checkpoint struct {
+	Seq  uint64
+	Hash cipher.SHA256
+}
+
+// ChainMismatch is a block that does not match the chain of the checkpoint
+type ChainMismatch struct {
+	Seq   uint64
+	Error string
+}
+
+// ChainVerification is the result of the verification of the block headers up to a checkpoint
+type ChainVerification struct {
+	Checkpoint Checkpoint
+	HeadSeq    uint64
+	// Verified is the number of blocks verified, less than the checkpoint height
+	// if the verification stopped after MaxChainMismatches mismatches
+	Verified   uint64
+	Mismatches []ChainMismatch
+}
+
+// VerifyChain verifies that the block headers of the DB, from the genesis block to the checkpoint,
+// form a chain ending with the hash of the checkpoint: every header links to the hash of the previous
+// header and matches the hash of its body. The signatures of the blocks are not verified, the chain
+// is trusted as far as the checkpoint is.
+func (a *Admin) VerifyChain(c Checkpoint) (*ChainVerification, error) {
+	if !a.config.EnableAdminAPI {
+		return nil, ErrAdminAPIDisabled
+	}
+
+	bc, err := blockdb.NewBlockchain(a.db, visor.DefaultWalker)
+	if err != nil {
+		return nil, err
+	}
+
+	var v *ChainVerification
+	if err := a.db.View("VerifyChain", func(tx *dbutil.Tx) error {
+		headSeq, ok, err := bc.HeadSeq(tx)
+		if err != nil {
+			return err
+		}
+		if !ok || headSeq < c.Seq {
+			return ErrCheckpointNotReached
+		}
+
+		v, err = verifyChainHeaders(func(seq uint64) (*coin.SignedBlock, error) {
+			return bc.GetSignedBlockBySeq(tx, seq)
+		}, c, a.quit)
+		if err != nil {
+			return err
+		}
+
+		v.HeadSeq = headSeq
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(v.Mismatches) == 0 {
+		logger.WithField("seq", c.Seq).Info("Chain verified up to the checkpoint")
+	} else {
+		logger.Critical().WithField("seq", c.Seq).Errorf("Chain verification found %d mismatches", len(v.Mismatches))
+	}
+
+	return v, nil
+}
+
+// verifyChainHeaders verifies the headers of the blocks returned by getBlock, from the genesis block to the checkpoint
+func verifyChainHeaders(getBlock func(seq uint64) (*coin.SignedBlock, error), c Checkpoint, quit <-chan struct{}) (*ChainVerification, error) {
+	v := &ChainVerification{
+		Checkpoint: c,
+	}
+
+	mismatch := func(seq uint64, format string, args ...interface{}) {
+		v.Mismatches = append(v.Mismatches, ChainMismatch{
+			Seq:   seq,
+			Error: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// The hash of the previous block header, nil if the previous block could not be read
+	prevHash := &cipher.SHA256{}
+
+	for seq := uint64(0); seq <= c.Seq && len(v.Mismatches) < MaxChainMismatches; seq++ {
+		select {
+		case <-quit:
+			return nil, ErrChainVerificationStopped
+		default:
+		}
+
+		v.Verified++
+
+		b, err := getBlock(seq)
+		if err != nil || b == nil {
+			mismatch(seq, "block not found")
+			prevHash = nil
+			continue
+		}
+
+		if b.Head.BkSeq != seq {
+			mismatch(seq, "block seq %d stored at height %d", b.Head.BkSeq, seq)
+		}
+
+		if prevHash != nil && b.Head.PrevHash != *prevHash {
+			mismatch(seq, "previous block hash %s does not match the hash %s of the previous block", b.Head.PrevHash.Hex(), prevHash.Hex())
+		}
+
+		if bodyHash := b.Body.Hash(); b.Head.BodyHash != bodyHash {
+			mismatch(seq, "body hash %s does not match the hash %s of the transactions", b.Head.BodyHash.Hex(), bodyHash.Hex())
+		}
+
+		hash := b.HashHeader()
+		if seq == c.Seq && hash != c.Hash {
+			mismatch(seq, "block hash %s does not match the checkpoint hash %s", hash.Hex(), c.Hash.Hex())
+		}
+
+		prevHash = &hash
+	}
+
+	return v, nil
+}
@@ -0,0 +1,16 @@
+package admin
+
+import "github.com/skycoin/skycoin/src/cipher"
+
+// Config is a configuration for the admin operations
+type Config struct {
+	// EnableAdminAPI enables the admin operations
+	EnableAdminAPI bool
+	// BlockchainPubkey verifies the signatures of the blocks when the DB is verified
+	BlockchainPubkey cipher.PubKey
+}
+
+// NewConfig creates a default config
+func NewConfig() Config {
+	return Config{}
+}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/visor"
 )
 
 // VerifyAddressRequest is the request data for POST /api/v2/address/verify
@@ -12,9 +16,13 @@ type VerifyAddressRequest struct {
 	Address string `json:"address"`
 }
 
-// VerifyAddressResponse is returned by POST /api/v2/address/verify
+// VerifyAddressResponse is returned by POST /api/v2/address/verify.
+// For an invalid address, it is returned with the 422 error and describes why the address is invalid.
 type VerifyAddressResponse struct {
-	Version byte `json:"version"`
+	Version     byte     `json:"version"`
+	Reason      string   `json:"reason,omitempty"`
+	Coin        string   `json:"coin,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // addressVerifyHandler verifies a Skycoin address
@@ -40,17 +48,179 @@ func addressVerifyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addr, err := cipher.DecodeBase58Address(req.Address)
+	v := cipher.ValidateAddress(req.Address)
 
-	if err != nil {
-		resp := NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error())
+	if !v.Valid {
+		resp := NewHTTPErrorResponse(http.StatusUnprocessableEntity, v.Message())
+		resp.Data = VerifyAddressResponse{
+			Version:     v.Version,
+			Reason:      string(v.Reason),
+			Coin:        v.Coin,
+			Suggestions: v.Suggestions,
+		}
 		writeHTTPResponse(w, resp)
 		return
 	}
 
 	writeHTTPResponse(w, HTTPResponse{
 		Data: VerifyAddressResponse{
-			Version: addr.Version,
+			Version: v.Version,
 		},
 	})
 }
+
+// VerifyMessageRequest is the request data for POST /api/v2/address/message/verify
+type VerifyMessageRequest struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// addressVerifyMessageHandler verifies that a message was signed with the secret key of a Skycoin address,
+// e.g. by POST /api/v2/wallet/message/sign
+// Method: POST
+// URI: /api/v2/address/message/verify
+func addressVerifyMessageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	var req VerifyMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if req.Address == "" {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "address is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if req.Signature == "" {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "signature is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	addr, err := cipher.DecodeBase58Address(req.Address)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid address: %v", err))
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	sig, err := cipher.SigFromHex(req.Signature)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid signature: %v", err))
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if err := cipher.VerifyMessage(addr, sig, []byte(req.Message)); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{Data: struct{}{}})
+}
+
+// AddressBalanceAtResponse is returned by GET /api/v2/address/balance-at
+type AddressBalanceAtResponse struct {
+	Address   string `json:"address"`
+	BlockSeq  uint64 `json:"block_seq"`
+	BlockTime uint64 `json:"block_time"`
+	// Balance has the coin hours of the unspent outputs of the address at the time of the block
+	Balance readable.Balance `json:"balance"`
+	Outputs int              `json:"outputs"`
+}
+
+// addressBalanceAtHandler returns the balance of an address after a block was executed,
+// for tax reporting and auditing
+// Method: GET
+// URI: /api/v2/address/balance-at
+// Args:
+//	address: address [required]
+//	height: the seq of the block [required if timestamp is not set]
+//	timestamp: unix time, the balance is that of the last block created at or before the time
+//		[required if height is not set]
+func addressBalanceAtHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		addrStr := r.FormValue("address")
+		if addrStr == "" {
+			writeError400Response(w, "address is required")
+			return
+		}
+
+		addr, err := cipher.DecodeBase58Address(addrStr)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid address: %v", err))
+			return
+		}
+
+		heightStr := r.FormValue("height")
+		timestampStr := r.FormValue("timestamp")
+
+		var balance *visor.HistoricalBalance
+		switch {
+		case heightStr != "" && timestampStr != "":
+			writeError400Response(w, "height and timestamp cannot be combined")
+			return
+		case heightStr != "":
+			height, err := strconv.ParseUint(heightStr, 10, 64)
+			if err != nil {
+				writeError400Response(w, "invalid height")
+				return
+			}
+
+			balance, err = gateway.GetAddressBalanceAtSeq(addr, height)
+			if err != nil {
+				writeAddressBalanceAtError(w, "gateway.GetAddressBalanceAtSeq", err)
+				return
+			}
+		case timestampStr != "":
+			timestamp, err := strconv.ParseUint(timestampStr, 10, 64)
+			if err != nil {
+				writeError400Response(w, "invalid timestamp")
+				return
+			}
+
+			balance, err = gateway.GetAddressBalanceAtTime(addr, timestamp)
+			if err != nil {
+				writeAddressBalanceAtError(w, "gateway.GetAddressBalanceAtTime", err)
+				return
+			}
+		default:
+			writeError400Response(w, "height or timestamp is required")
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: AddressBalanceAtResponse{
+				Address:   balance.Address.String(),
+				BlockSeq:  balance.BlockSeq,
+				BlockTime: balance.BlockTime,
+				Balance:   readable.NewBalance(balance.Balance),
+				Outputs:   balance.Outputs,
+			},
+		})
+	}
+}
+
+func writeAddressBalanceAtError(w http.ResponseWriter, method string, err error) {
+	if err == visor.ErrBalanceBlockNotFound {
+		writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusNotFound, err.Error()))
+		return
+	}
+
+	writeError500Response(w, fmt.Sprintf("%s failed: %v", method, err))
+}
@@ -2,12 +2,19 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
 )
 
 func toJSON(t *testing.T, r interface{}) string {
@@ -65,7 +72,51 @@ func TestVerifyAddress(t *testing.T) {
 			httpBody: toJSON(t, VerifyAddressRequest{
 				Address: "7apQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
 			}),
-			httpResponse: NewHTTPErrorResponse(http.StatusUnprocessableEntity, "Invalid checksum"),
+			httpResponse: HTTPResponse{
+				Error: &HTTPError{
+					Code:    http.StatusUnprocessableEntity,
+					Message: "Invalid checksum, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?",
+				},
+				Data: VerifyAddressResponse{
+					Reason:      "invalid_checksum",
+					Suggestions: []string{"7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD"},
+				},
+			},
+		},
+		{
+			name:   "422 - Bitcoin address",
+			method: http.MethodPost,
+			status: http.StatusUnprocessableEntity,
+			httpBody: toJSON(t, VerifyAddressRequest{
+				Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			}),
+			httpResponse: HTTPResponse{
+				Error: &HTTPError{
+					Code:    http.StatusUnprocessableEntity,
+					Message: "This is a bitcoin address, not a Skycoin address",
+				},
+				Data: VerifyAddressResponse{
+					Reason: "wrong_coin",
+					Coin:   "bitcoin",
+				},
+			},
+		},
+		{
+			name:   "422 - Invalid length",
+			method: http.MethodPost,
+			status: http.StatusUnprocessableEntity,
+			httpBody: toJSON(t, VerifyAddressRequest{
+				Address: "7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8f",
+			}),
+			httpResponse: HTTPResponse{
+				Error: &HTTPError{
+					Code:    http.StatusUnprocessableEntity,
+					Message: "Invalid address length, the address may be incomplete or have extra characters",
+				},
+				Data: VerifyAddressResponse{
+					Reason: "invalid_length",
+				},
+			},
 		},
 		{
 			name:   "200",
@@ -146,3 +197,293 @@ func TestVerifyAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyMessage(t *testing.T) {
+	p, s := cipher.GenerateKeyPair()
+	addr := cipher.AddressFromPubKey(p)
+	sig := cipher.MustSignMessage(s, []byte("foo"))
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		httpBody     string
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - EOF",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "400 - Missing address",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, VerifyMessageRequest{Signature: sig.Hex()}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address is required"),
+		},
+		{
+			name:         "400 - Missing signature",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, VerifyMessageRequest{Address: addr.String()}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "signature is required"),
+		},
+		{
+			name:   "400 - Invalid address",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, VerifyMessageRequest{
+				Address:   "7apQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
+				Signature: sig.Hex(),
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid address: Invalid checksum, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?"),
+		},
+		{
+			name:   "400 - Invalid signature",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, VerifyMessageRequest{
+				Address:   addr.String(),
+				Signature: "abcd",
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid signature: Invalid signature length"),
+		},
+		{
+			name:   "422 - Wrong message",
+			method: http.MethodPost,
+			status: http.StatusUnprocessableEntity,
+			httpBody: toJSON(t, VerifyMessageRequest{
+				Address:   addr.String(),
+				Message:   "bar",
+				Signature: sig.Hex(),
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusUnprocessableEntity, cipher.ErrInvalidAddressForSig.Error()),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			httpBody: toJSON(t, VerifyMessageRequest{
+				Address:   addr.String(),
+				Message:   "foo",
+				Signature: sig.Hex(),
+			}),
+			httpResponse: HTTPResponse{
+				Data: struct{}{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := "/api/v2/address/message/verify"
+			gateway := &MockGatewayer{}
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+			}
+		})
+	}
+}
+
+func TestAddressBalanceAt(t *testing.T) {
+	newUint64Ptr := func(n uint64) *uint64 {
+		return &n
+	}
+
+	addr := testutil.MakeAddress()
+	balance := &visor.HistoricalBalance{
+		Address:   addr,
+		BlockSeq:  10,
+		BlockTime: 1500000000,
+		Balance: wallet.Balance{
+			Coins: 2e6,
+			Hours: 100,
+		},
+		Outputs: 2,
+	}
+
+	cases := []struct {
+		name             string
+		method           string
+		query            string
+		seq              *uint64
+		time             *uint64
+		gatewayBalance   *visor.HistoricalBalance
+		gatewayErr       error
+		status           int
+		err              string
+		addrBalanceAtRsp *AddressBalanceAtResponse
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - missing address",
+			method: http.MethodGet,
+			query:  "?height=10",
+			status: http.StatusBadRequest,
+			err:    "address is required",
+		},
+		{
+			name:   "400 - invalid address",
+			method: http.MethodGet,
+			query:  "?address=foo&height=10",
+			status: http.StatusBadRequest,
+			err:    "invalid address: Invalid address length",
+		},
+		{
+			name:   "400 - missing height and timestamp",
+			method: http.MethodGet,
+			query:  "?address=" + addr.String(),
+			status: http.StatusBadRequest,
+			err:    "height or timestamp is required",
+		},
+		{
+			name:   "400 - height and timestamp",
+			method: http.MethodGet,
+			query:  "?address=" + addr.String() + "&height=10&timestamp=1500000000",
+			status: http.StatusBadRequest,
+			err:    "height and timestamp cannot be combined",
+		},
+		{
+			name:   "400 - invalid height",
+			method: http.MethodGet,
+			query:  "?address=" + addr.String() + "&height=-1",
+			status: http.StatusBadRequest,
+			err:    "invalid height",
+		},
+		{
+			name:   "400 - invalid timestamp",
+			method: http.MethodGet,
+			query:  "?address=" + addr.String() + "&timestamp=foo",
+			status: http.StatusBadRequest,
+			err:    "invalid timestamp",
+		},
+		{
+			name:       "404 - block not found",
+			method:     http.MethodGet,
+			query:      "?address=" + addr.String() + "&height=11",
+			seq:        newUint64Ptr(11),
+			gatewayErr: visor.ErrBalanceBlockNotFound,
+			status:     http.StatusNotFound,
+			err:        "block not found",
+		},
+		{
+			name:       "500 - gateway.GetAddressBalanceAtTime failed",
+			method:     http.MethodGet,
+			query:      "?address=" + addr.String() + "&timestamp=1500000000",
+			time:       newUint64Ptr(1500000000),
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "gateway.GetAddressBalanceAtTime failed: failed",
+		},
+		{
+			name:           "200 - height",
+			method:         http.MethodGet,
+			query:          "?address=" + addr.String() + "&height=10",
+			seq:            newUint64Ptr(10),
+			gatewayBalance: balance,
+			status:         http.StatusOK,
+			addrBalanceAtRsp: &AddressBalanceAtResponse{
+				Address:   addr.String(),
+				BlockSeq:  10,
+				BlockTime: 1500000000,
+				Balance: readable.Balance{
+					Coins: 2e6,
+					Hours: 100,
+				},
+				Outputs: 2,
+			},
+		},
+		{
+			name:           "200 - timestamp",
+			method:         http.MethodGet,
+			query:          "?address=" + addr.String() + "&timestamp=1500000100",
+			time:           newUint64Ptr(1500000100),
+			gatewayBalance: balance,
+			status:         http.StatusOK,
+			addrBalanceAtRsp: &AddressBalanceAtResponse{
+				Address:   addr.String(),
+				BlockSeq:  10,
+				BlockTime: 1500000000,
+				Balance: readable.Balance{
+					Coins: 2e6,
+					Hours: 100,
+				},
+				Outputs: 2,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.seq != nil {
+				gateway.On("GetAddressBalanceAtSeq", addr, *tc.seq).Return(tc.gatewayBalance, tc.gatewayErr)
+			}
+			if tc.time != nil {
+				gateway.On("GetAddressBalanceAtTime", addr, *tc.time).Return(tc.gatewayBalance, tc.gatewayErr)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/address/balance-at"+tc.query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			if tc.status != http.StatusOK {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+
+			var addrBalanceAtRsp AddressBalanceAtResponse
+			err = json.Unmarshal(rsp.Data, &addrBalanceAtRsp)
+			require.NoError(t, err)
+			require.Equal(t, *tc.addrBalanceAtRsp, addrBalanceAtRsp)
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/admin"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/daemon"
+)
+
+// adminErrorResponse maps an admin error to an HTTP error response
+func adminErrorResponse(err error) HTTPResponse {
+	switch err {
+	case admin.ErrAdminAPIDisabled:
+		return NewHTTPErrorResponse(http.StatusForbidden, "")
+	case admin.ErrDBVerificationRunning, admin.ErrLogToFileDisabled:
+		return NewHTTPErrorResponse(http.StatusConflict, err.Error())
+	}
+
+	if _, ok := err.(admin.Error); ok {
+		return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	return NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+}
+
+// DBVerificationResponse is the state of the last DB verification started by the admin API
+type DBVerificationResponse struct {
+	Running    bool  `json:"running"`
+	StartedAt  int64 `json:"started_at"`
+	FinishedAt int64 `json:"finished_at,omitempty"`
+	// Error is the error of a failed verification, empty if the DB is valid
+	Error string `json:"error,omitempty"`
+}
+
+// NewDBVerificationResponse creates a DBVerificationResponse
+func NewDBVerificationResponse(v *admin.DBVerification) DBVerificationResponse {
+	r := DBVerificationResponse{
+		Running:   v.Running,
+		StartedAt: v.StartedAt.Unix(),
+		Error:     v.Error,
+	}
+
+	if !v.FinishedAt.IsZero() {
+		r.FinishedAt = v.FinishedAt.Unix()
+	}
+
+	return r
+}
+
+// Dispatches /admin/db/verify endpoint.
+// Method: GET, POST
+// URI: /api/v2/admin/db/verify
+func adminDBVerifyHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getDBVerificationHandler(w, gateway)
+		case http.MethodPost:
+			verifyDBHandler(w, gateway)
+		default:
+			writeError405Response(w)
+		}
+	}
+}
+
+// Returns the state of the last DB verification
+func getDBVerificationHandler(w http.ResponseWriter, gateway Gatewayer) {
+	v, err := gateway.GetDBVerification()
+	if err != nil {
+		writeHTTPResponse(w, adminErrorResponse(err))
+		return
+	}
+
+	if v == nil {
+		writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusNotFound, "no DB verification was started"))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: NewDBVerificationResponse(v),
+	})
+}
+
+// Starts a verification of the DB in the background. Its result is returned by GET.
+func verifyDBHandler(w http.ResponseWriter, gateway Gatewayer) {
+	v, err := gateway.VerifyDB()
+	if err != nil {
+		writeHTTPResponse(w, adminErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: NewDBVerificationResponse(v),
+	})
+}
+
+// ChainVerifyRequest is the request data for POST /api/v2/admin/chain/verify
+type ChainVerifyRequest struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// ChainCheckpoint is the trusted hash of the block at a height of the blockchain
+type ChainCheckpoint struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// ChainMismatch is a block that does not match the chain of the checkpoint
+type ChainMismatch struct {
+	Seq   uint64 `json:"seq"`
+	Error string `json:"error"`
+}
+
+// ChainVerificationResponse is the response data for POST /api/v2/admin/chain/verify
+type ChainVerificationResponse struct {
+	Checkpoint ChainCheckpoint `json:"checkpoint"`
+	HeadSeq    uint64          `json:"head_seq"`
+	Verified   uint64          `json:"verified"`
+	Valid      bool            `json:"valid"`
+	Mismatches []ChainMismatch `json:"mismatches"`
+}
+
+// NewChainVerificationResponse creates a ChainVerificationResponse
+func NewChainVerificationResponse(v *admin.ChainVerification) ChainVerificationResponse {
+	r := ChainVerificationResponse{
+		Checkpoint: ChainCheckpoint{
+			Seq:  v.Checkpoint.Seq,
+			Hash: v.Checkpoint.Hash.Hex(),
+		},
+		HeadSeq:    v.HeadSeq,
+		Verified:   v.Verified,
+		Valid:      len(v.Mismatches) == 0,
+		Mismatches: make([]ChainMismatch, len(v.Mismatches)),
+	}
+
+	for i, m := range v.Mismatches {
+		r.Mismatches[i] = ChainMismatch{
+			Seq:   m.Seq,
+			Error: m.Error,
+		}
+	}
+
+	return r
+}
+
+// Verifies the block headers of the DB from the genesis block up to a trusted checkpoint
+// Method: POST
+// URI: /api/v2/admin/chain/verify
+// Body: ChainVerifyRequest
+func adminChainVerifyHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		var req ChainVerifyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError400Response(w, err.Error())
+			return
+		}
+
+		if req.Hash == "" {
+			writeError400Response(w, "hash is required")
+			return
+		}
+
+		hash, err := cipher.SHA256FromHex(req.Hash)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid hash: %v", err))
+			return
+		}
+
+		v, err := gateway.VerifyChain(admin.Checkpoint{
+			Seq:  req.Seq,
+			Hash: hash,
+		})
+		if err != nil {
+			writeHTTPResponse(w, adminErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewChainVerificationResponse(v),
+		})
+	}
+}
+
+// LogRotateResponse is the response data for POST /api/v2/admin/log/rotate
+type LogRotateResponse struct {
+	Path string `json:"path"`
+}
+
+// Closes the log file and continues logging to a new one
+// Method: POST
+// URI: /api/v2/admin/log/rotate
+func adminLogRotateHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		path, err := gateway.RotateLogFile()
+		if err != nil {
+			writeHTTPResponse(w, adminErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: LogRotateResponse{
+				Path: path,
+			},
+		})
+	}
+}
+
+// LogLevelRequest is the request data for POST /api/v2/admin/log/level
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelResponse is the response data for /api/v2/admin/log/level
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// Dispatches /admin/log/level endpoint.
+// Method: GET, POST
+// URI: /api/v2/admin/log/level
+func adminLogLevelHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getLogLevelHandler(w, gateway)
+		case http.MethodPost:
+			setLogLevelHandler(w, r, gateway)
+		default:
+			writeError405Response(w)
+		}
+	}
+}
+
+// Returns the log level of the node
+func getLogLevelHandler(w http.ResponseWriter, gateway Gatewayer) {
+	level, err := gateway.GetLogLevel()
+	if err != nil {
+		writeHTTPResponse(w, adminErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: LogLevelResponse{
+			Level: level,
+		},
+	})
+}
+
+// Sets the log level of the node until it is restarted
+// Body: LogLevelRequest
+func setLogLevelHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError400Response(w, err.Error())
+		return
+	}
+
+	if req.Level == "" {
+		writeError400Response(w, "level is required")
+		return
+	}
+
+	if err := gateway.SetLogLevel(req.Level); err != nil {
+		writeHTTPResponse(w, adminErrorResponse(err))
+		return
+	}
+
+	level, err := gateway.GetLogLevel()
+	if err != nil {
+		writeHTTPResponse(w, adminErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: LogLevelResponse{
+			Level: level,
+		},
+	})
+}
+
+// PeersReconnectResponse is the response data for POST /api/v2/admin/peers/reconnect
+type PeersReconnectResponse struct {
+	Disconnected int `json:"disconnected"`
+}
+
+// Disconnects all outgoing connections, which are then replaced by new outgoing connections
+// Method: POST
+// URI: /api/v2/admin/peers/reconnect
+func adminPeersReconnectHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		n, err := gateway.ReconnectPeers()
+		if err != nil {
+			switch err {
+			case daemon.ErrNetworkingDisabled:
+				writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusServiceUnavailable, err.Error()))
+			default:
+				writeError500Response(w, fmt.Sprintf("gateway.ReconnectPeers failed: %v", err))
+			}
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: PeersReconnectResponse{
+				Disconnected: n,
+			},
+		})
+	}
+}
+
+// BannedPeer is an IP address banned by the node operator
+type BannedPeer struct {
+	IP       string `json:"ip"`
+	BannedAt int64  `json:"banned_at"`
+}
+
+// PeerBanRequest is the request data for POST /api/v2/admin/peers/ban
+type PeerBanRequest struct {
+	IP string `json:"ip"`
+}
+
+// PeerBanResponse is the response data for POST /api/v2/admin/peers/ban
+type PeerBanResponse struct {
+	IP           string `json:"ip"`
+	Disconnected int    `json:"disconnected"`
+}
+
+// Dispatches /admin/peers/ban endpoint.
+// Method: GET, POST, DELETE
+// URI: /api/v2/admin/peers/ban
+func adminPeersBanHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getBannedPeersHandler(w, gateway)
+		case http.MethodPost:
+			banPeerHandler(w, r, gateway)
+		case http.MethodDelete:
+			unbanPeerHandler(w, r, gateway)
+		default:
+			writeError405Response(w)
+		}
+	}
+}
+
+// Returns the banned IP addresses, sorted by IP
+func getBannedPeersHandler(w http.ResponseWriter, gateway Gatewayer) {
+	banned := gateway.GetBannedPeers()
+
+	peers := make([]BannedPeer, len(banned))
+	for i, p := range banned {
+		peers[i] = BannedPeer{
+			IP:       p.IP,
+			BannedAt: p.BannedAt.Unix(),
+		}
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: peers,
+	})
+}
+
+// Bans an IP address until the node is restarted and disconnects its connections
+// Body: PeerBanRequest
+func banPeerHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	var req PeerBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError400Response(w, err.Error())
+		return
+	}
+
+	if req.IP == "" {
+		writeError400Response(w, "ip is required")
+		return
+	}
+
+	n, err := gateway.BanPeer(req.IP)
+	if err != nil {
+		switch err {
+		case daemon.ErrInvalidBanIP:
+			writeError400Response(w, err.Error())
+		default:
+			writeError500Response(w, fmt.Sprintf("gateway.BanPeer failed: %v", err))
+		}
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: PeerBanResponse{
+			IP:           req.IP,
+			Disconnected: n,
+		},
+	})
+}
+
+// Lifts the ban of an IP address
+// Args:
+//     ip: banned IP address [required]
+func unbanPeerHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	ip := r.FormValue("ip")
+	if ip == "" {
+		writeError400Response(w, "ip is required")
+		return
+	}
+
+	if err := gateway.UnbanPeer(ip); err != nil {
+		switch err {
+		case daemon.ErrInvalidBanIP:
+			writeError400Response(w, err.Error())
+		case daemon.ErrPeerNotBanned:
+			writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusNotFound, err.Error()))
+		default:
+			writeError500Response(w, fmt.Sprintf("gateway.UnbanPeer failed: %v", err))
+		}
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{})
+}
+
+// Shuts down the node cleanly, after the response is sent
+// Method: POST
+// URI: /api/v2/admin/shutdown
+func adminShutdownHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		if err := gateway.RequestShutdown(); err != nil {
+			writeHTTPResponse(w, adminErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{})
+	}
+}
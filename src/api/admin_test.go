@@ -0,0 +1,485 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/admin"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+type adminHandlerCase struct {
+	name         string
+	method       string
+	body         string
+	gateway      func(*MockGatewayer)
+	status       int
+	httpResponse HTTPResponse
+}
+
+func runAdminHandlerCases(t *testing.T, endpoint string, tt []adminHandlerCase) {
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.gateway != nil {
+				tc.gateway(gateway)
+			}
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			expected, err := json.MarshalIndent(tc.httpResponse, "", "    ")
+			require.NoError(t, err)
+			require.Equal(t, string(expected), rr.Body.String())
+
+			gateway.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminDBVerifyHandler(t *testing.T) {
+	startedAt := time.Unix(1571109310, 0)
+	running := &admin.DBVerification{
+		Running:   true,
+		StartedAt: startedAt,
+	}
+	failed := &admin.DBVerification{
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(time.Minute),
+		Error:      "signature verification failed",
+	}
+
+	runAdminHandlerCases(t, "/api/v2/admin/db/verify", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodDelete,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "GET 403 admin API disabled",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetDBVerification").Return(nil, admin.ErrAdminAPIDisabled)
+			},
+			status:       http.StatusForbidden,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "GET 404 no verification",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetDBVerification").Return(nil, nil)
+			},
+			status:       http.StatusNotFound,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "no DB verification was started"),
+		},
+		{
+			name:   "GET 200",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetDBVerification").Return(failed, nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: DBVerificationResponse{
+					StartedAt:  1571109310,
+					FinishedAt: 1571109370,
+					Error:      "signature verification failed",
+				},
+			},
+		},
+		{
+			name:   "POST 409 running",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyDB").Return(nil, admin.ErrDBVerificationRunning)
+			},
+			status:       http.StatusConflict,
+			httpResponse: NewHTTPErrorResponse(http.StatusConflict, admin.ErrDBVerificationRunning.Error()),
+		},
+		{
+			name:   "POST 200",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyDB").Return(running, nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: DBVerificationResponse{
+					Running:   true,
+					StartedAt: 1571109310,
+				},
+			},
+		},
+	})
+}
+
+func TestAdminChainVerifyHandler(t *testing.T) {
+	hash := testutil.RandSHA256(t)
+	checkpoint := admin.Checkpoint{
+		Seq:  100,
+		Hash: hash,
+	}
+
+	runAdminHandlerCases(t, "/api/v2/admin/chain/verify", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 missing hash",
+			method:       http.MethodPost,
+			body:         `{"seq":100}`,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "hash is required"),
+		},
+		{
+			name:         "400 invalid hash",
+			method:       http.MethodPost,
+			body:         `{"seq":100,"hash":"abcd"}`,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid hash: Invalid hex length"),
+		},
+		{
+			name:   "400 checkpoint not reached",
+			method: http.MethodPost,
+			body:   `{"seq":100,"hash":"` + hash.Hex() + `"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyChain", checkpoint).Return(nil, admin.ErrCheckpointNotReached)
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, admin.ErrCheckpointNotReached.Error()),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			body:   `{"seq":100,"hash":"` + hash.Hex() + `"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyChain", checkpoint).Return(&admin.ChainVerification{
+					Checkpoint: checkpoint,
+					HeadSeq:    120,
+					Verified:   101,
+					Mismatches: []admin.ChainMismatch{
+						{
+							Seq:   100,
+							Error: "block hash does not match the checkpoint hash",
+						},
+					},
+				}, nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: ChainVerificationResponse{
+					Checkpoint: ChainCheckpoint{
+						Seq:  100,
+						Hash: hash.Hex(),
+					},
+					HeadSeq:  120,
+					Verified: 101,
+					Mismatches: []ChainMismatch{
+						{
+							Seq:   100,
+							Error: "block hash does not match the checkpoint hash",
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAdminLogRotateHandler(t *testing.T) {
+	runAdminHandlerCases(t, "/api/v2/admin/log/rotate", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "409 not logging to a file",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("RotateLogFile").Return("", admin.ErrLogToFileDisabled)
+			},
+			status:       http.StatusConflict,
+			httpResponse: NewHTTPErrorResponse(http.StatusConflict, admin.ErrLogToFileDisabled.Error()),
+		},
+		{
+			name:   "500",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("RotateLogFile").Return("", errors.New("disk full"))
+			},
+			status:       http.StatusInternalServerError,
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "disk full"),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("RotateLogFile").Return("logs/2019-10-15-031510-v0.27.1.log", nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: LogRotateResponse{
+					Path: "logs/2019-10-15-031510-v0.27.1.log",
+				},
+			},
+		},
+	})
+}
+
+func TestAdminLogLevelHandler(t *testing.T) {
+	runAdminHandlerCases(t, "/api/v2/admin/log/level", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodDelete,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "GET 200",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetLogLevel").Return("info", nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: LogLevelResponse{
+					Level: "info",
+				},
+			},
+		},
+		{
+			name:         "POST 400 invalid body",
+			method:       http.MethodPost,
+			body:         "{",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "unexpected EOF"),
+		},
+		{
+			name:         "POST 400 missing level",
+			method:       http.MethodPost,
+			body:         "{}",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "level is required"),
+		},
+		{
+			name:   "POST 400 invalid level",
+			method: http.MethodPost,
+			body:   `{"level":"verbose"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("SetLogLevel", "verbose").Return(admin.ErrInvalidLogLevel)
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, admin.ErrInvalidLogLevel.Error()),
+		},
+		{
+			name:   "POST 200",
+			method: http.MethodPost,
+			body:   `{"level":"warn"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("SetLogLevel", "warn").Return(nil)
+				g.On("GetLogLevel").Return("warning", nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: LogLevelResponse{
+					Level: "warning",
+				},
+			},
+		},
+	})
+}
+
+func TestAdminPeersReconnectHandler(t *testing.T) {
+	runAdminHandlerCases(t, "/api/v2/admin/peers/reconnect", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "503 networking disabled",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("ReconnectPeers").Return(0, daemon.ErrNetworkingDisabled)
+			},
+			status:       http.StatusServiceUnavailable,
+			httpResponse: NewHTTPErrorResponse(http.StatusServiceUnavailable, daemon.ErrNetworkingDisabled.Error()),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("ReconnectPeers").Return(3, nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: PeersReconnectResponse{
+					Disconnected: 3,
+				},
+			},
+		},
+	})
+}
+
+func TestAdminPeersBanHandler(t *testing.T) {
+	bannedAt := time.Unix(1571109310, 0)
+
+	runAdminHandlerCases(t, "/api/v2/admin/peers/ban", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodPut,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "GET 200",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetBannedPeers").Return([]daemon.BannedPeer{
+					{
+						IP:       "10.0.0.1",
+						BannedAt: bannedAt,
+					},
+				})
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: []BannedPeer{
+					{
+						IP:       "10.0.0.1",
+						BannedAt: bannedAt.Unix(),
+					},
+				},
+			},
+		},
+		{
+			name:   "GET 200 no bans",
+			method: http.MethodGet,
+			gateway: func(g *MockGatewayer) {
+				g.On("GetBannedPeers").Return([]daemon.BannedPeer{})
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: []BannedPeer{},
+			},
+		},
+		{
+			name:         "POST 400 invalid body",
+			method:       http.MethodPost,
+			body:         "{",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "unexpected EOF"),
+		},
+		{
+			name:         "POST 400 missing ip",
+			method:       http.MethodPost,
+			body:         "{}",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "ip is required"),
+		},
+		{
+			name:   "POST 400 invalid ip",
+			method: http.MethodPost,
+			body:   `{"ip":"10.0.0"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("BanPeer", "10.0.0").Return(0, daemon.ErrInvalidBanIP)
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, daemon.ErrInvalidBanIP.Error()),
+		},
+		{
+			name:   "POST 200",
+			method: http.MethodPost,
+			body:   `{"ip":"10.0.0.1"}`,
+			gateway: func(g *MockGatewayer) {
+				g.On("BanPeer", "10.0.0.1").Return(2, nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: PeerBanResponse{
+					IP:           "10.0.0.1",
+					Disconnected: 2,
+				},
+			},
+		},
+		{
+			name:         "DELETE 400 missing ip",
+			method:       http.MethodDelete,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "ip is required"),
+		},
+	})
+
+	runAdminHandlerCases(t, "/api/v2/admin/peers/ban?ip=10.0.0.1", []adminHandlerCase{
+		{
+			name:   "DELETE 404 not banned",
+			method: http.MethodDelete,
+			gateway: func(g *MockGatewayer) {
+				g.On("UnbanPeer", "10.0.0.1").Return(daemon.ErrPeerNotBanned)
+			},
+			status:       http.StatusNotFound,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, daemon.ErrPeerNotBanned.Error()),
+		},
+		{
+			name:   "DELETE 200",
+			method: http.MethodDelete,
+			gateway: func(g *MockGatewayer) {
+				g.On("UnbanPeer", "10.0.0.1").Return(nil)
+			},
+			status:       http.StatusOK,
+			httpResponse: HTTPResponse{},
+		},
+	})
+}
+
+func TestAdminShutdownHandler(t *testing.T) {
+	runAdminHandlerCases(t, "/api/v2/admin/shutdown", []adminHandlerCase{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:   "403 admin API disabled",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("RequestShutdown").Return(admin.ErrAdminAPIDisabled)
+			},
+			status:       http.StatusForbidden,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			gateway: func(g *MockGatewayer) {
+				g.On("RequestShutdown").Return(nil)
+			},
+			status:       http.StatusOK,
+			httpResponse: HTTPResponse{},
+		},
+	})
+}
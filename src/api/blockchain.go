@@ -284,10 +284,14 @@ func blocksHandler(gateway Gatewayer) http.HandlerFunc {
 			if len(seqs) > 0 {
 				blocks, inputs, err = gateway.GetBlocksVerbose(seqs)
 			} else {
-				blocks, inputs, err = gateway.GetBlocksInRangeVerbose(start, end)
+				blocks, inputs, err = gateway.GetBlocksInRangeVerbose(r.Context(), start, end)
 			}
 
 			if err != nil {
+				if writeContextError(w, apiVersion1, err) {
+					return
+				}
+
 				switch err.(type) {
 				case visor.ErrBlockNotExist:
 					wh.Error404(w, err.Error())
@@ -311,10 +315,14 @@ func blocksHandler(gateway Gatewayer) http.HandlerFunc {
 			if len(seqs) > 0 {
 				blocks, err = gateway.GetBlocks(seqs)
 			} else {
-				blocks, err = gateway.GetBlocksInRange(start, end)
+				blocks, err = gateway.GetBlocksInRange(r.Context(), start, end)
 			}
 
 			if err != nil {
+				if writeContextError(w, apiVersion1, err) {
+					return
+				}
+
 				switch err.(type) {
 				case visor.ErrBlockNotExist:
 					wh.Error404(w, err.Error())
@@ -17,7 +17,9 @@ import (
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
 	"github.com/skycoin/skycoin/src/kvstorage"
+	"github.com/skycoin/skycoin/src/notify"
 	"github.com/skycoin/skycoin/src/readable"
+	wh "github.com/skycoin/skycoin/src/util/http"
 )
 
 const (
@@ -29,6 +31,8 @@ const (
 	ContentTypeJSON = "application/json"
 	// ContentTypeForm form data content type header
 	ContentTypeForm = "application/x-www-form-urlencoded"
+	// ContentTypeCSV csv content type header
+	ContentTypeCSV = "text/csv"
 )
 
 // ClientError is used for non-200 API responses
@@ -134,6 +138,28 @@ func (c *Client) Get(endpoint string, obj interface{}) error {
 	return d.Decode(obj)
 }
 
+// GetRaw makes a GET request to an endpoint and returns the response body.
+// If the response is not 200 OK, returns an error
+func (c *Client) GetRaw(endpoint string) ([]byte, error) {
+	resp, err := c.get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewClientError(resp.Status, resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
 // get makes a GET request to an endpoint. Caller must close response body.
 func (c *Client) get(endpoint string) (*http.Response, error) {
 	return c.makeRequestWithoutBody(endpoint, http.MethodGet)
@@ -588,6 +614,21 @@ func (c *Client) Balance(addrs []string) (*BalanceResponse, error) {
 	return &b, nil
 }
 
+// BalanceV2 makes a request to POST /api/v2/balance, for up to MaxBalanceAddresses addresses
+func (c *Client) BalanceV2(addrs []string) (*BalanceResponse, error) {
+	req := BalanceRequest{
+		Addrs: addrs,
+	}
+
+	var b BalanceResponse
+	ok, err := c.PostJSONV2("/api/v2/balance", req, &b)
+	if ok {
+		return &b, err
+	}
+
+	return nil, err
+}
+
 // UxOut makes a request to GET /api/v1/uxout?uxid=xxx
 func (c *Client) UxOut(uxID string) (*readable.SpentOutput, error) {
 	v := url.Values{}
@@ -647,6 +688,8 @@ type CreateWalletOptions struct {
 	Password       string
 	ScanN          uint64
 	XPub           string
+	XPubAccount    bool
+	Addresses      []string
 	Encrypt        bool
 	Bip44Coin      *bip44.CoinType
 }
@@ -680,6 +723,14 @@ func (c *Client) CreateWallet(o CreateWalletOptions) (*WalletResponse, error) {
 		v.Add("xpub", o.XPub)
 	}
 
+	if o.XPubAccount {
+		v.Add("xpub-account", "true")
+	}
+
+	if len(o.Addresses) != 0 {
+		v.Add("addresses", strings.Join(o.Addresses, ","))
+	}
+
 	var w WalletResponse
 	if err := c.PostForm("/api/v1/wallet/create", strings.NewReader(v.Encode()), &w); err != nil {
 		return nil, err
@@ -747,6 +798,8 @@ type CreateTransactionRequest struct {
 	To                []Receiver     `json:"to"`
 	UxOuts            []string       `json:"unspents,omitempty"`
 	Addresses         []string       `json:"addresses,omitempty"`
+	ExcludeUxOuts     []string       `json:"exclude_unspents,omitempty"`
+	AvoidAddressReuse bool           `json:"avoid_address_reuse"`
 }
 
 // HoursSelection defines options for hours distribution
@@ -758,9 +811,11 @@ type HoursSelection struct {
 
 // Receiver specifies a spend destination
 type Receiver struct {
-	Address string `json:"address"`
-	Coins   string `json:"coins"`
-	Hours   string `json:"hours,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Name is resolved to the address by the node, if it has an address resolver
+	Name  string `json:"name,omitempty"`
+	Coins string `json:"coins"`
+	Hours string `json:"hours,omitempty"`
 }
 
 // WalletCreateTransactionRequest is sent to /api/v1/wallet/transaction
@@ -793,6 +848,30 @@ func (c *Client) WalletSignTransaction(req WalletSignTransactionRequest) (*Creat
 	return nil, err
 }
 
+// WalletsCreateTransactionRequest is sent to /api/v2/wallets/transaction
+type WalletsCreateTransactionRequest struct {
+	Wallets []WalletSpendRequest `json:"wallets"`
+	CreateTransactionRequest
+}
+
+// WalletSpendRequest is a wallet spending its outputs in a transaction created by /api/v2/wallets/transaction
+type WalletSpendRequest struct {
+	ID        string   `json:"id"`
+	Password  string   `json:"password"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// WalletsCreateTransaction makes a request to POST /api/v2/wallets/transaction
+func (c *Client) WalletsCreateTransaction(req WalletsCreateTransactionRequest) (*CreateTransactionResponse, error) {
+	var r CreateTransactionResponse
+	endpoint := "/api/v2/wallets/transaction"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
 // CreateTransaction makes a request to POST /api/v2/transaction
 func (c *Client) CreateTransaction(req CreateTransactionRequest) (*CreateTransactionResponse, error) {
 	var r CreateTransactionResponse
@@ -804,6 +883,45 @@ func (c *Client) CreateTransaction(req CreateTransactionRequest) (*CreateTransac
 	return nil, err
 }
 
+// ExportTransaction makes a request to POST /api/v2/transaction/export
+func (c *Client) ExportTransaction(req CreateTransactionRequest) (*UnsignedTransactionExport, error) {
+	var r UnsignedTransactionExport
+	endpoint := "/api/v2/transaction/export"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// ImportTransaction makes a request to POST /api/v2/transaction/import
+func (c *Client) ImportTransaction(req ImportTransactionRequest) (*ImportTransactionResponse, error) {
+	var r ImportTransactionResponse
+	endpoint := "/api/v2/transaction/import"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// EstimateTransactionRequest is sent to /api/v2/transaction/estimate
+type EstimateTransactionRequest struct {
+	WalletID string `json:"wallet_id,omitempty"`
+	CreateTransactionRequest
+}
+
+// EstimateTransaction makes a request to POST /api/v2/transaction/estimate
+func (c *Client) EstimateTransaction(req EstimateTransactionRequest) (*EstimateTransactionResponse, error) {
+	var r EstimateTransactionResponse
+	endpoint := "/api/v2/transaction/estimate"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
 // WalletUnconfirmedTransactions makes a request to GET /api/v1/wallet/transactions
 func (c *Client) WalletUnconfirmedTransactions(id string) (*UnconfirmedTxnsResponse, error) {
 	v := url.Values{}
@@ -980,6 +1098,11 @@ func (c *Client) PendingTransactionsVerbose() ([]readable.UnconfirmedTransaction
 	return v, nil
 }
 
+// PendingTransactionsCSV makes a request to GET /api/v1/pendingTxs?format=csv
+func (c *Client) PendingTransactionsCSV() ([]byte, error) {
+	return c.GetRaw("/api/v1/pendingTxs?format=csv")
+}
+
 // Transaction makes a request to GET /api/v1/transaction
 func (c *Client) Transaction(txid string) (*readable.TransactionWithStatus, error) {
 	v := url.Values{}
@@ -1062,6 +1185,14 @@ func (c *Client) UnconfirmedTransactions(addrs []string) ([]readable.Transaction
 	return r, nil
 }
 
+// TransactionsCSV makes a request to GET /api/v1/transactions?format=csv
+func (c *Client) TransactionsCSV(addrs []string) ([]byte, error) {
+	v := url.Values{}
+	v.Add("addrs", strings.Join(addrs, ","))
+	v.Add("format", "csv")
+	return c.GetRaw("/api/v1/transactions?" + v.Encode())
+}
+
 // TransactionsVerbose makes a request to POST /api/v1/transactions?verbose=1
 func (c *Client) TransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error) {
 	v := url.Values{}
@@ -1185,6 +1316,93 @@ func (c *Client) VerifyTransaction(req VerifyTransactionRequest) (*VerifyTransac
 	return nil, err
 }
 
+// DecodeTransaction makes a request to POST /api/v2/transaction/decode
+func (c *Client) DecodeTransaction(encodedTxn string) (*DecodeTransactionResponse, error) {
+	req := DecodeTransactionRequest{
+		EncodedTransaction: encodedTxn,
+	}
+
+	var rsp DecodeTransactionResponse
+	ok, err := c.PostJSONV2("/api/v2/transaction/decode", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// TransactionStatus makes a request to GET /api/v2/transaction/status
+func (c *Client) TransactionStatus(txid string) (*TransactionStatusResponse, error) {
+	v := url.Values{}
+	v.Add("txid", txid)
+
+	var r TransactionStatusResponse
+	ok, err := c.GetV2("/api/v2/transaction/status?"+v.Encode(), &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
+// RebroadcastTransaction makes a request to POST /api/v2/transaction/rebroadcast
+func (c *Client) RebroadcastTransaction(txid string) (*RebroadcastTransactionResponse, error) {
+	req := RebroadcastTransactionRequest{
+		Txid: txid,
+	}
+
+	var r RebroadcastTransactionResponse
+	ok, err := c.PostJSONV2("/api/v2/transaction/rebroadcast", req, &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
+// Mempool makes a request to GET /api/v2/mempool
+func (c *Client) Mempool(addrs []string) (*MempoolResponse, error) {
+	v := url.Values{}
+	if len(addrs) != 0 {
+		v.Add("addrs", strings.Join(addrs, ","))
+	}
+
+	endpoint := "/api/v2/mempool"
+	if len(v) != 0 {
+		endpoint += "?" + v.Encode()
+	}
+
+	var r MempoolResponse
+	ok, err := c.GetV2(endpoint, &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
+// MempoolEvictions makes a request to GET /api/v2/mempool/evictions
+func (c *Client) MempoolEvictions() (*MempoolEvictionsResponse, error) {
+	var r MempoolEvictionsResponse
+	ok, err := c.GetV2("/api/v2/mempool/evictions", &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
+// GraphQL makes a request to POST /api/v2/graphql.
+// The data of the response is decoded as a generic JSON value.
+func (c *Client) GraphQL(req GraphQLRequest) (*GraphQLResponse, error) {
+	var r GraphQLResponse
+	if err := c.PostJSON("/api/v2/graphql", req, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
 // VerifyAddress makes a request to POST /api/v2/address/verify
 // The API may respond with an error but include data useful for processing,
 // so both return values may be non-nil.
@@ -1202,10 +1420,46 @@ func (c *Client) VerifyAddress(addr string) (*VerifyAddressResponse, error) {
 	return nil, err
 }
 
+// VerifyMessage makes a request to POST /api/v2/address/message/verify
+func (c *Client) VerifyMessage(req VerifyMessageRequest) error {
+	_, err := c.PostJSONV2("/api/v2/address/message/verify", req, &struct{}{})
+	return err
+}
+
+// AddressBalanceAtHeight makes a request to GET /api/v2/address/balance-at to get the balance
+// of an address after the block at a height was executed
+func (c *Client) AddressBalanceAtHeight(addr string, height uint64) (*AddressBalanceAtResponse, error) {
+	v := url.Values{}
+	v.Add("address", addr)
+	v.Add("height", fmt.Sprint(height))
+	return c.addressBalanceAt(v)
+}
+
+// AddressBalanceAtTime makes a request to GET /api/v2/address/balance-at to get the balance
+// of an address after the last block created at or before a unix time was executed
+func (c *Client) AddressBalanceAtTime(addr string, timestamp uint64) (*AddressBalanceAtResponse, error) {
+	v := url.Values{}
+	v.Add("address", addr)
+	v.Add("timestamp", fmt.Sprint(timestamp))
+	return c.addressBalanceAt(v)
+}
+
+func (c *Client) addressBalanceAt(v url.Values) (*AddressBalanceAtResponse, error) {
+	var r AddressBalanceAtResponse
+	ok, err := c.GetV2("/api/v2/address/balance-at?"+v.Encode(), &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
 // RichlistParams are arguments to the /richlist endpoint
 type RichlistParams struct {
 	N                   int
+	Page                int
 	IncludeDistribution bool
+	Exclude             []string
 }
 
 // Richlist makes a request to GET /api/v1/richlist
@@ -1215,7 +1469,13 @@ func (c *Client) Richlist(params *RichlistParams) (*Richlist, error) {
 	if params != nil {
 		v := url.Values{}
 		v.Add("n", fmt.Sprint(params.N))
+		if params.Page != 0 {
+			v.Add("page", fmt.Sprint(params.Page))
+		}
 		v.Add("include-distribution", fmt.Sprint(params.IncludeDistribution))
+		if len(params.Exclude) != 0 {
+			v.Add("exclude", strings.Join(params.Exclude, ","))
+		}
 		endpoint = "/api/v1/richlist?" + v.Encode()
 	}
 
@@ -1255,6 +1515,22 @@ func (c *Client) Health() (*HealthResponse, error) {
 	return &r, nil
 }
 
+// HealthV2 makes a request to GET /api/v2/health. If the node is not ready,
+// the response is returned along with the 503 error.
+// maxBlocksBehind is the number of blocks the node can be behind its peers and be ready.
+func (c *Client) HealthV2(maxBlocksBehind uint64) (*HealthV2Response, error) {
+	v := url.Values{}
+	v.Add("max_blocks_behind", fmt.Sprint(maxBlocksBehind))
+
+	var r HealthV2Response
+	ok, err := c.GetV2("/api/v2/health?"+v.Encode(), &r)
+	if !ok {
+		return nil, err
+	}
+
+	return &r, err
+}
+
 // EncryptWallet makes a request to POST /api/v1/wallet/encrypt to encrypt a specific wallet with the given password
 func (c *Client) EncryptWallet(id, password string) (*WalletResponse, error) {
 	v := url.Values{}
@@ -1294,6 +1570,88 @@ func (c *Client) RecoverWallet(req WalletRecoverRequest) (*WalletResponse, error
 	return nil, err
 }
 
+// UnlockWallet makes a request to POST /api/v2/wallet/unlock to start an unlock session of an encrypted wallet.
+// Returns the expiry of the session.
+func (c *Client) UnlockWallet(id, password string, ttl time.Duration) (time.Time, error) {
+	var rsp WalletUnlockResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/unlock", WalletUnlockRequest{
+		ID:       id,
+		Password: password,
+		TTL:      wh.FromDuration(ttl),
+	}, &rsp)
+	if !ok {
+		return time.Time{}, err
+	}
+	return time.Unix(rsp.UnlockedUntil, 0), err
+}
+
+// LockWallet makes a request to POST /api/v2/wallet/lock to end the unlock session of a wallet
+func (c *Client) LockWallet(id string) error {
+	_, err := c.PostJSONV2("/api/v2/wallet/lock", WalletLockRequest{
+		ID: id,
+	}, &struct{}{})
+	return err
+}
+
+// WalletLabels makes a request to GET /api/v2/wallet/labels to get the labels, notes and tags
+// of the addresses of a wallet
+func (c *Client) WalletLabels(id string) ([]WalletAddressLabel, error) {
+	v := url.Values{}
+	v.Add("id", id)
+
+	var rsp WalletLabelsResponse
+	ok, err := c.GetV2("/api/v2/wallet/labels?"+v.Encode(), &rsp)
+	if !ok {
+		return nil, err
+	}
+	return rsp.Labels, err
+}
+
+// ImportWalletLabels makes a request to POST /api/v2/wallet/labels/import to set the labels, notes and tags
+// of the addresses of a wallet
+func (c *Client) ImportWalletLabels(id string, labels []WalletAddressLabel) (*WalletLabelsImportResponse, error) {
+	var rsp WalletLabelsImportResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/labels/import", WalletLabelsImportRequest{
+		ID:     id,
+		Labels: labels,
+	}, &rsp)
+	if !ok {
+		return nil, err
+	}
+	return &rsp, err
+}
+
+// VerifySeedPassphrase makes a request to POST /api/v2/wallet/seed-passphrase/verify to verify
+// the seed passphrase of a bip44 wallet. The password is required if the wallet is encrypted.
+func (c *Client) VerifySeedPassphrase(req VerifySeedPassphraseRequest) (bool, error) {
+	ok, err := c.PostJSONV2("/api/v2/wallet/seed-passphrase/verify", req, &struct{}{})
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// WalletSeedMatch makes a request to POST /api/v2/wallet/seed/match to check
+// whether a seed and seed passphrase are the ones of a wallet
+func (c *Client) WalletSeedMatch(req WalletSeedMatchRequest) (bool, error) {
+	var rsp WalletSeedMatchResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/seed/match", req, &rsp)
+	if !ok {
+		return false, err
+	}
+	return rsp.Match, err
+}
+
+// SignMessage makes a request to POST /api/v2/wallet/message/sign
+func (c *Client) SignMessage(req WalletSignMessageRequest) (*WalletSignMessageResponse, error) {
+	var rsp WalletSignMessageResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/message/sign", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+	return nil, err
+}
+
 // Disconnect disconnect a connections by ID
 func (c *Client) Disconnect(id uint64) error {
 	v := url.Values{}
@@ -1347,6 +1705,236 @@ func (c *Client) RemoveStorageValue(storageType kvstorage.Type, key string) erro
 	return err
 }
 
+// AddWebhook makes a POST request to /api/v2/webhooks to register a webhook.
+// The secret of the webhook is only returned by this request.
+func (c *Client) AddWebhook(req WebhookRequest) (*notify.Webhook, error) {
+	var w notify.Webhook
+	ok, err := c.PostJSONV2("/api/v2/webhooks", req, &w)
+	if ok {
+		return &w, err
+	}
+
+	return nil, err
+}
+
+// Webhooks makes a GET request to /api/v2/webhooks to get the registered webhooks
+func (c *Client) Webhooks() ([]notify.Webhook, error) {
+	var ws []notify.Webhook
+	ok, err := c.GetV2("/api/v2/webhooks", &ws)
+	if !ok {
+		return nil, err
+	}
+
+	return ws, err
+}
+
+// RemoveWebhook makes a DELETE request to /api/v2/webhooks to remove a webhook
+func (c *Client) RemoveWebhook(id string) error {
+	v := url.Values{}
+	v.Add("id", id)
+
+	_, err := c.DeleteV2("/api/v2/webhooks?"+v.Encode(), nil)
+	return err
+}
+
+// WebhookDeliveries makes a GET request to /api/v2/webhooks/deliveries to get the recent deliveries of a webhook
+func (c *Client) WebhookDeliveries(id string) ([]notify.Delivery, error) {
+	v := url.Values{}
+	v.Add("id", id)
+
+	var ds []notify.Delivery
+	ok, err := c.GetV2("/api/v2/webhooks/deliveries?"+v.Encode(), &ds)
+	if !ok {
+		return nil, err
+	}
+
+	return ds, err
+}
+
+// WatchedAddresses makes a GET request to /api/v2/watch to get the addresses of the watch list
+func (c *Client) WatchedAddresses() (*WatchListResponse, error) {
+	var r WatchListResponse
+	ok, err := c.GetV2("/api/v2/watch", &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// AddWatchedAddresses makes a POST request to /api/v2/watch to add addresses to the watch list
+func (c *Client) AddWatchedAddresses(addrs []string) (*WatchListResponse, error) {
+	req := WatchListRequest{
+		Addresses: addrs,
+	}
+
+	var r WatchListResponse
+	ok, err := c.PostJSONV2("/api/v2/watch", req, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// RemoveWatchedAddresses makes a DELETE request to /api/v2/watch to remove addresses from the watch list
+func (c *Client) RemoveWatchedAddresses(addrs []string) (*WatchListResponse, error) {
+	v := url.Values{}
+	v.Add("addrs", strings.Join(addrs, ","))
+
+	var r WatchListResponse
+	ok, err := c.DeleteV2("/api/v2/watch?"+v.Encode(), &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// WatchTransactions makes a GET request to /api/v2/watch/transactions to get the transactions of the watched addresses
+func (c *Client) WatchTransactions(args ...RequestArg) (*TransactionsWithStatusV2, error) {
+	v := url.Values{}
+	for _, arg := range args {
+		v.Add(arg.Key, arg.Value)
+	}
+
+	var obj TransactionsWithStatusV2
+	ok, err := c.GetV2("/api/v2/watch/transactions?"+v.Encode(), &obj)
+	if ok {
+		return &obj, err
+	}
+
+	return nil, err
+}
+
+// VerifyDB makes a POST request to /api/v2/admin/db/verify to start a verification of the DB
+func (c *Client) VerifyDB() (*DBVerificationResponse, error) {
+	var r DBVerificationResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/db/verify", nil, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// DBVerification makes a GET request to /api/v2/admin/db/verify to get the state of the last DB verification
+func (c *Client) DBVerification() (*DBVerificationResponse, error) {
+	var r DBVerificationResponse
+	ok, err := c.GetV2("/api/v2/admin/db/verify", &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// VerifyChain makes a POST request to /api/v2/admin/chain/verify to verify the block headers
+// of the node up to a trusted checkpoint
+func (c *Client) VerifyChain(seq uint64, hash string) (*ChainVerificationResponse, error) {
+	req := ChainVerifyRequest{
+		Seq:  seq,
+		Hash: hash,
+	}
+
+	var r ChainVerificationResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/chain/verify", req, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// RotateLogFile makes a POST request to /api/v2/admin/log/rotate to continue logging to a new log file
+func (c *Client) RotateLogFile() (*LogRotateResponse, error) {
+	var r LogRotateResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/log/rotate", nil, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// LogLevel makes a GET request to /api/v2/admin/log/level to get the log level of the node
+func (c *Client) LogLevel() (string, error) {
+	var r LogLevelResponse
+	ok, err := c.GetV2("/api/v2/admin/log/level", &r)
+	if !ok {
+		return "", err
+	}
+
+	return r.Level, err
+}
+
+// SetLogLevel makes a POST request to /api/v2/admin/log/level to set the log level of the node
+func (c *Client) SetLogLevel(level string) (string, error) {
+	req := LogLevelRequest{
+		Level: level,
+	}
+
+	var r LogLevelResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/log/level", req, &r)
+	if !ok {
+		return "", err
+	}
+
+	return r.Level, err
+}
+
+// ReconnectPeers makes a POST request to /api/v2/admin/peers/reconnect to replace the outgoing connections
+func (c *Client) ReconnectPeers() (*PeersReconnectResponse, error) {
+	var r PeersReconnectResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/peers/reconnect", nil, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// BannedPeers makes a GET request to /api/v2/admin/peers/ban to get the banned IP addresses
+func (c *Client) BannedPeers() ([]BannedPeer, error) {
+	var r []BannedPeer
+	ok, err := c.GetV2("/api/v2/admin/peers/ban", &r)
+	if !ok {
+		return nil, err
+	}
+
+	return r, err
+}
+
+// BanPeer makes a POST request to /api/v2/admin/peers/ban to ban an IP address
+func (c *Client) BanPeer(ip string) (*PeerBanResponse, error) {
+	req := PeerBanRequest{
+		IP: ip,
+	}
+
+	var r PeerBanResponse
+	ok, err := c.PostJSONV2("/api/v2/admin/peers/ban", req, &r)
+	if ok {
+		return &r, err
+	}
+
+	return nil, err
+}
+
+// UnbanPeer makes a DELETE request to /api/v2/admin/peers/ban to lift the ban of an IP address
+func (c *Client) UnbanPeer(ip string) error {
+	v := url.Values{}
+	v.Add("ip", ip)
+
+	_, err := c.DeleteV2("/api/v2/admin/peers/ban?"+v.Encode(), nil)
+	return err
+}
+
+// Shutdown makes a POST request to /api/v2/admin/shutdown to shut down the node
+func (c *Client) Shutdown() error {
+	_, err := c.PostJSONV2("/api/v2/admin/shutdown", nil, nil)
+	return err
+}
+
 // RequestArg is the general data type for sending request
 type RequestArg struct {
 	Key   string
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// corsAllowedHeaders are the request headers that cross-origin requests can send
+var corsAllowedHeaders = []string{"Origin", "Accept", "Content-Type", "X-Requested-With", CSRFHeaderName, "Authorization"}
+
+// corsMethods are the methods that the CORS rules can allow
+var corsMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+// corsAPISets are the API sets that the CORS rules can allow
+var corsAPISets = []string{
+	EndpointsRead,
+	EndpointsStatus,
+	EndpointsTransaction,
+	EndpointsWallet,
+	EndpointsInsecureWalletSeed,
+	EndpointsNetCtrl,
+	EndpointsStorage,
+	EndpointsWebhook,
+	EndpointsGraphQL,
+}
+
+// CORSRule allows a browser origin to make cross-origin requests to the API
+type CORSRule struct {
+	// Origin is the scheme, host and port of the origin, e.g. https://wallet.example.com, or * for any origin
+	Origin string
+	// APISets are the API sets of the endpoints the origin can access. If empty, the origin can access all endpoints.
+	APISets []string
+	// Methods are the HTTP methods the origin can use
+	Methods []string
+}
+
+// ParseCORSRules parses a comma-separated list of CORS rules, in the format
+// <origin>=<API set>[+<API set>...][:<method>[+<method>...]], e.g. "https://wallet.example.com=READ+WALLET:GET+POST".
+// The methods default to GET. The wildcard origin * can only be allowed the READ and STATUS API sets.
+func ParseCORSRules(s string) ([]CORSRule, error) {
+	var rules []CORSRule
+	seen := make(map[string]struct{})
+
+	for _, r := range splitCommaString(s) {
+		pts := strings.SplitN(r, "=", 2)
+		if len(pts) != 2 || pts[0] == "" || pts[1] == "" {
+			return nil, fmt.Errorf("invalid CORS rule %q, must be <origin>=<API set>[+<API set>...][:<method>[+<method>...]]", r)
+		}
+
+		origin, err := parseCORSOrigin(pts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[origin]; ok {
+			return nil, fmt.Errorf("duplicate CORS rule origin %q", origin)
+		}
+		seen[origin] = struct{}{}
+
+		setsAndMethods := strings.SplitN(pts[1], ":", 2)
+
+		var apiSets []string
+		for _, k := range strings.Split(setsAndMethods[0], "+") {
+			k = strings.ToUpper(strings.TrimSpace(k))
+			if !containsString(corsAPISets, k) {
+				return nil, fmt.Errorf("invalid API set %q of CORS rule %q", k, origin)
+			}
+
+			if origin == "*" && k != EndpointsRead && k != EndpointsStatus {
+				return nil, fmt.Errorf("the CORS rule of origin * can only allow the %s and %s API sets", EndpointsRead, EndpointsStatus)
+			}
+
+			apiSets = append(apiSets, k)
+		}
+
+		methods := []string{http.MethodGet}
+		if len(setsAndMethods) == 2 {
+			methods = nil
+			for _, m := range strings.Split(setsAndMethods[1], "+") {
+				m = strings.ToUpper(strings.TrimSpace(m))
+				if !containsString(corsMethods, m) {
+					return nil, fmt.Errorf("invalid method %q of CORS rule %q, must be %s", m, origin, strings.Join(corsMethods, ", "))
+				}
+				methods = append(methods, m)
+			}
+		}
+
+		rules = append(rules, CORSRule{
+			Origin:  origin,
+			APISets: apiSets,
+			Methods: methods,
+		})
+	}
+
+	return rules, nil
+}
+
+// parseCORSOrigin validates an origin and returns it in lowercase
+func parseCORSOrigin(origin string) (string, error) {
+	if origin == "*" {
+		return origin, nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", fmt.Errorf("invalid CORS rule origin %q: %v", origin, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid CORS rule origin %q, must be http(s)://<host>[:<port>]", origin)
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s://%s", u.Scheme, u.Host)), nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// corsAllows returns true if a rule allows the origin to request the endpoint method of methodAPISets.
+// A nil methodAPISets is an endpoint that is always enabled, such as /api/v1/csrf. Only the rule of the origin
+// allows it, never the "*" rule, otherwise any website could read the CSRF token.
+func corsAllows(rules []CORSRule, origin, method string, methodAPISets map[string][]string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, rule := range rules {
+		if rule.Origin != "*" && rule.Origin != origin {
+			continue
+		}
+
+		if rule.Origin == "*" && methodAPISets == nil {
+			continue
+		}
+
+		if !containsString(rule.Methods, method) {
+			continue
+		}
+
+		if len(rule.APISets) == 0 || methodAPISets == nil {
+			return true
+		}
+
+		for _, k := range methodAPISets[method] {
+			if containsString(rule.APISets, k) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// corsHeaderAllowed returns true if cross-origin requests can send the header
+func corsHeaderAllowed(header string) bool {
+	for _, h := range corsAllowedHeaders {
+		if strings.EqualFold(h, header) {
+			return true
+		}
+	}
+	return false
+}
+
+type corsAllowedKey struct{}
+
+// corsAllowed returns true if the request is a cross-origin request allowed by the CORS rules
+func corsAllowed(r *http.Request) bool {
+	allowed, _ := r.Context().Value(corsAllowedKey{}).(bool)
+	return allowed
+}
+
+// corsHandler answers the CORS preflight requests and sets the CORS headers of the cross-origin requests
+// allowed by the rules to the endpoint methods of methodAPISets.
+// The allowed requests are marked in the request context, so that the Origin header check accepts them.
+func corsHandler(apiVersion string, rules []CORSRule, methodAPISets map[string][]string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && requestMethod != "" {
+			requestHeaders := splitCommaString(r.Header.Get("Access-Control-Request-Headers"))
+			for _, h := range requestHeaders {
+				if !corsHeaderAllowed(h) {
+					writeError(w, apiVersion, http.StatusForbidden, fmt.Sprintf("CORS request header %q is not allowed", h))
+					return
+				}
+			}
+
+			if !corsAllows(rules, origin, requestMethod, methodAPISets) {
+				writeError(w, apiVersion, http.StatusForbidden, "CORS request is not allowed")
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", requestMethod)
+			if len(requestHeaders) != 0 {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(requestHeaders, ", "))
+			}
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if !corsAllows(rules, origin, r.Method, methodAPISets) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), corsAllowedKey{}, true)))
+	})
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCORSRules(t *testing.T) {
+	cases := []struct {
+		name  string
+		s     string
+		rules []CORSRule
+		err   string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "one rule",
+			s:    "https://wallet.example.com=READ",
+			rules: []CORSRule{
+				{
+					Origin:  "https://wallet.example.com",
+					APISets: []string{EndpointsRead},
+					Methods: []string{http.MethodGet},
+				},
+			},
+		},
+		{
+			name: "many rules",
+			s:    "HTTPS://Wallet.example.com/=read+wallet:get+post, http://localhost:3000=WEBHOOK:GET+DELETE,*=READ+STATUS",
+			rules: []CORSRule{
+				{
+					Origin:  "https://wallet.example.com",
+					APISets: []string{EndpointsRead, EndpointsWallet},
+					Methods: []string{http.MethodGet, http.MethodPost},
+				},
+				{
+					Origin:  "http://localhost:3000",
+					APISets: []string{EndpointsWebhook},
+					Methods: []string{http.MethodGet, http.MethodDelete},
+				},
+				{
+					Origin:  "*",
+					APISets: []string{EndpointsRead, EndpointsStatus},
+					Methods: []string{http.MethodGet},
+				},
+			},
+		},
+		{
+			name: "missing API sets",
+			s:    "https://wallet.example.com",
+			err:  `invalid CORS rule "https://wallet.example.com", must be <origin>=<API set>[+<API set>...][:<method>[+<method>...]]`,
+		},
+		{
+			name: "invalid origin",
+			s:    "wallet.example.com=READ",
+			err:  `invalid CORS rule origin "wallet.example.com", must be http(s)://<host>[:<port>]`,
+		},
+		{
+			name: "origin with path",
+			s:    "https://example.com/wallet=READ",
+			err:  `invalid CORS rule origin "https://example.com/wallet", must be http(s)://<host>[:<port>]`,
+		},
+		{
+			name: "duplicate origin",
+			s:    "https://wallet.example.com=READ,https://WALLET.example.com=WALLET",
+			err:  `duplicate CORS rule origin "https://wallet.example.com"`,
+		},
+		{
+			name: "invalid API set",
+			s:    "https://wallet.example.com=READ+FOO",
+			err:  `invalid API set "FOO" of CORS rule "https://wallet.example.com"`,
+		},
+		{
+			name: "invalid method",
+			s:    "https://wallet.example.com=READ:GET+PATCH",
+			err:  `invalid method "PATCH" of CORS rule "https://wallet.example.com", must be GET, POST, PUT, DELETE`,
+		},
+		{
+			name: "wildcard origin with wallet",
+			s:    "*=READ+WALLET",
+			err:  "the CORS rule of origin * can only allow the READ and STATUS API sets",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := ParseCORSRules(tc.s)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestCORSRules(t *testing.T) {
+	rules, err := ParseCORSRules("https://wallet.example.com=READ+WALLET:GET+POST,*=READ")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		method        string
+		endpoint      string
+		origin        string
+		preflight     bool
+		headers       string
+		status        int
+		allowedOrigin bool
+	}{
+		{
+			name:          "read endpoint from wallet origin",
+			method:        http.MethodGet,
+			endpoint:      "/api/v1/balance",
+			origin:        "https://wallet.example.com",
+			status:        http.StatusBadRequest,
+			allowedOrigin: true,
+		},
+		{
+			name:          "read endpoint from any origin",
+			method:        http.MethodGet,
+			endpoint:      "/api/v1/balance",
+			origin:        "https://example.com",
+			status:        http.StatusBadRequest,
+			allowedOrigin: true,
+		},
+		{
+			name:          "always enabled endpoint from wallet origin",
+			method:        http.MethodGet,
+			endpoint:      "/api/v1/csrf",
+			origin:        "https://wallet.example.com",
+			status:        http.StatusOK,
+			allowedOrigin: true,
+		},
+		{
+			name:     "always enabled endpoint from any origin",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/csrf",
+			origin:   "https://example.com",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:      "preflight always enabled endpoint from any origin",
+			method:    http.MethodGet,
+			endpoint:  "/api/v2/spec",
+			origin:    "https://example.com",
+			preflight: true,
+			status:    http.StatusForbidden,
+		},
+		{
+			name:          "wallet endpoint from wallet origin",
+			method:        http.MethodPost,
+			endpoint:      "/api/v1/wallet/create",
+			origin:        "https://wallet.example.com",
+			status:        http.StatusBadRequest,
+			allowedOrigin: true,
+		},
+		{
+			name:     "wallet endpoint from other origin",
+			method:   http.MethodPost,
+			endpoint: "/api/v1/wallet/create",
+			origin:   "https://example.com",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "method not allowed for wallet origin",
+			method:   http.MethodDelete,
+			endpoint: "/api/v2/webhooks",
+			origin:   "https://wallet.example.com",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:          "preflight allowed",
+			method:        http.MethodPost,
+			endpoint:      "/api/v1/wallet/create",
+			origin:        "https://wallet.example.com",
+			preflight:     true,
+			headers:       "content-type, x-csrf-token, authorization",
+			status:        http.StatusNoContent,
+			allowedOrigin: true,
+		},
+		{
+			name:      "preflight other origin",
+			method:    http.MethodPost,
+			endpoint:  "/api/v1/wallet/create",
+			origin:    "https://example.com",
+			preflight: true,
+			status:    http.StatusForbidden,
+		},
+		{
+			name:      "preflight method not allowed",
+			method:    http.MethodDelete,
+			endpoint:  "/api/v2/webhooks",
+			origin:    "https://wallet.example.com",
+			preflight: true,
+			status:    http.StatusForbidden,
+		},
+		{
+			name:      "preflight header not allowed",
+			method:    http.MethodGet,
+			endpoint:  "/api/v1/balance",
+			origin:    "https://wallet.example.com",
+			preflight: true,
+			headers:   "x-foo",
+			status:    http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+			cfg.corsRules = rules
+
+			method := tc.method
+			if tc.preflight {
+				method = http.MethodOptions
+			}
+
+			req, err := http.NewRequest(method, tc.endpoint, strings.NewReader("{}"))
+			require.NoError(t, err)
+			req.Header.Set("Origin", tc.origin)
+			if tc.preflight {
+				req.Header.Set("Access-Control-Request-Method", tc.method)
+				req.Header.Set("Access-Control-Request-Headers", tc.headers)
+			} else {
+				setCSRFParameters(t, tokenValid, req)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(cfg, &MockGatewayer{})
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			if !tc.allowedOrigin {
+				require.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+
+			require.Equal(t, tc.origin, rr.Header().Get("Access-Control-Allow-Origin"))
+			if tc.preflight {
+				require.Equal(t, tc.method, rr.Header().Get("Access-Control-Allow-Methods"))
+				require.Equal(t, tc.headers, rr.Header().Get("Access-Control-Allow-Headers"))
+			}
+			require.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+		})
+	}
+}
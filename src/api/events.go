@@ -0,0 +1,243 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// sseKeepAliveInterval is the interval of the comments that keep the stream alive through proxies
+	sseKeepAliveInterval = 30 * time.Second
+	// sseMaxResumeBlocks is the maximum number of blocks replayed when a stream is resumed
+	sseMaxResumeBlocks = 1000
+	// sseEventBufferSize is the size of the blockchain event buffer of a stream.
+	// The events are buffered while the missed blocks are replayed.
+	sseEventBufferSize = 1000
+	// sseWriteTimeout is the write deadline of an event, it replaces the WriteTimeout of the server,
+	// which would end the stream
+	sseWriteTimeout = 30 * time.Second
+)
+
+// sseStream writes the events of a wsSession as server-sent events
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	// conn is the connection of the stream, nil if the server doesn't save it in the request context
+	conn net.Conn
+	// id is the ID of the written events, the sequence of the block of the event,
+	// or of the head block for the unconfirmed transaction events
+	id uint64
+}
+
+func (s *sseStream) write(e WSEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := s.extendWriteDeadline(); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", s.id, e.Type, data); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseStream) keepAlive() error {
+	if err := s.extendWriteDeadline(); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+// extendWriteDeadline sets the write deadline of the connection for the next write.
+// The deadline set by the WriteTimeout of the server is for the whole response.
+func (s *sseStream) extendWriteDeadline() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+}
+
+// Streams blockchain events as server-sent events, for the clients that can't use /api/v2/ws
+// URI: /api/v2/events
+// Method: GET
+// Args:
+//	addrs: comma-separated list of addresses to subscribe to [optional]
+//	wallets: comma-separated list of wallet IDs to subscribe to [optional]
+//	watch: [bool] subscribe to the addresses of the watch list of the node [optional]
+//	last_event_id: the ID of the last event received, replaces the Last-Event-ID header [optional]
+// The events and subscriptions are those of /api/v2/ws, the subscriptions can't be changed.
+// The ID of an event is the sequence of its block, or of the head block for the unconfirmed transactions.
+// A stream resumed with the Last-Event-ID header replays the blocks after the ID, up to sseMaxResumeBlocks,
+// then the transactions of the unconfirmed pool.
+func eventsHandler(gateway Gatewayer, walletAPIEnabled bool, quit <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, "Streaming is not supported")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var lastEventID *uint64
+		lastEventIDStr := r.Header.Get("Last-Event-ID")
+		if lastEventIDStr == "" {
+			lastEventIDStr = r.FormValue("last_event_id")
+		}
+		if lastEventIDStr != "" {
+			id, err := strconv.ParseUint(lastEventIDStr, 10, 64)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "invalid Last-Event-ID")
+				writeHTTPResponse(w, resp)
+				return
+			}
+			lastEventID = &id
+		}
+
+		s, resp := newWSSession(r, gateway, walletAPIEnabled)
+		if s == nil {
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		// Subscribe before reading the head block, so that no block is missed between the replay and the stream
+		events, unsubscribe := gateway.SubscribeBlockchain(sseEventBufferSize)
+		defer unsubscribe()
+
+		headSeq, hasHead, err := gateway.HeadBkSeq()
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("gateway.HeadBkSeq failed: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		// Disables the response buffering of nginx
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		conn, _ := r.Context().Value(connContextKey{}).(net.Conn)
+		stream := &sseStream{
+			w:       w,
+			flusher: flusher,
+			conn:    conn,
+			id:      headSeq,
+		}
+		s.send = stream.write
+
+		if lastEventID != nil && hasHead {
+			if err := resumeEvents(s, stream, *lastEventID, headSeq); err != nil {
+				logger.WithError(err).WithField("remoteAddr", r.RemoteAddr).Debug("Event stream resume failed")
+				return
+			}
+		}
+
+		if err := streamEvents(s, stream, events, headSeq, hasHead, r, quit); err != nil {
+			logger.WithError(err).WithField("remoteAddr", r.RemoteAddr).Debug("Event stream closed")
+		}
+	}
+}
+
+// resumeEvents replays the blocks after lastEventID up to headSeq, then the transactions of the unconfirmed pool
+func resumeEvents(s *wsSession, stream *sseStream, lastEventID, headSeq uint64) error {
+	if lastEventID < headSeq {
+		start := lastEventID + 1
+		if headSeq-lastEventID > sseMaxResumeBlocks {
+			start = headSeq - sseMaxResumeBlocks + 1
+		}
+
+		blocks, err := s.gateway.GetBlocksInRange(s.ctx, start, headSeq)
+		if err != nil {
+			return err
+		}
+
+		for i := range blocks {
+			stream.id = blocks[i].Head.BkSeq
+			if err := s.handleEvent(visor.Event{
+				Type:  visor.EventBlockExecuted,
+				Block: &blocks[i],
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	txns, err := s.gateway.GetAllUnconfirmedTransactions()
+	if err != nil {
+		return err
+	}
+
+	stream.id = headSeq
+	for i := range txns {
+		if err := s.handleEvent(visor.Event{
+			Type:        visor.EventUnconfirmedTxnInjected,
+			Transaction: &txns[i].Transaction,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamEvents streams the blockchain events until the client disconnects or quit is closed.
+// If hasHead is true, the blocks up to headSeq are skipped, they precede the stream or were replayed.
+func streamEvents(s *wsSession, stream *sseStream, events <-chan visor.Event, headSeq uint64, hasHead bool, r *http.Request, quit <-chan struct{}) error {
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return nil
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if e.Type == visor.EventBlockExecuted {
+				if hasHead && e.Block.Head.BkSeq <= headSeq {
+					continue
+				}
+				headSeq = e.Block.Head.BkSeq
+				hasHead = true
+			}
+			stream.id = headSeq
+
+			if err := s.handleEvent(e); err != nil {
+				return err
+			}
+		case <-keepAlive.C:
+			if err := stream.keepAlive(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestEventsHandlerErrors(t *testing.T) {
+	tt := []struct {
+		name         string
+		method       string
+		query        string
+		lastEventID  string
+		headBkSeqErr error
+		status       int
+		err          string
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:        "400 invalid Last-Event-ID",
+			method:      http.MethodGet,
+			lastEventID: "foo",
+			status:      http.StatusBadRequest,
+			err:         "invalid Last-Event-ID",
+		},
+		{
+			name:   "400 invalid last_event_id",
+			method: http.MethodGet,
+			query:  "?last_event_id=-1",
+			status: http.StatusBadRequest,
+			err:    "invalid Last-Event-ID",
+		},
+		{
+			name:   "400 invalid address",
+			method: http.MethodGet,
+			query:  "?addrs=foo",
+			status: http.StatusBadRequest,
+			err:    "address \"foo\" is invalid: Invalid address length",
+		},
+		{
+			name:   "400 invalid watch",
+			method: http.MethodGet,
+			query:  "?watch=foo",
+			status: http.StatusBadRequest,
+			err:    "invalid value for watch",
+		},
+		{
+			name:         "500 gateway.HeadBkSeq failed",
+			method:       http.MethodGet,
+			headBkSeqErr: errors.New("failed"),
+			status:       http.StatusInternalServerError,
+			err:          "gateway.HeadBkSeq failed: failed",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("SubscribeBlockchain", sseEventBufferSize).Return((<-chan visor.Event)(make(chan visor.Event)), func() {})
+			gateway.On("HeadBkSeq").Return(uint64(10), true, tc.headBkSeqErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/events"+tc.query, nil)
+			require.NoError(t, err)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code)
+
+			var resp ReceivedHTTPResponse
+			err = json.NewDecoder(rr.Body).Decode(&resp)
+			require.NoError(t, err)
+			require.NotNil(t, resp.Error)
+			require.Equal(t, tc.err, resp.Error.Message)
+		})
+	}
+}
+
+type sseEvent struct {
+	id    string
+	event string
+	data  WSEvent
+}
+
+func readSSEEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	var e sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			if e.event != "" {
+				return e
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment
+		case strings.HasPrefix(line, "id: "):
+			e.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			e.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e.data)
+			require.NoError(t, err)
+		default:
+			t.Fatalf("invalid event stream line %q", line)
+		}
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	confirmed := prepareTxnAndInputs(t)
+	unconfirmed := prepareTxnAndInputs(t)
+	other := prepareTxnAndInputs(t)
+	addr := confirmed.txn.Out[0].Address
+	unconfirmed.txn.Out[0].Address = addr
+	err := unconfirmed.txn.UpdateHeader()
+	require.NoError(t, err)
+
+	newBlock := func(seq uint64, txns ...coin.Transaction) coin.SignedBlock {
+		return coin.SignedBlock{
+			Block: coin.Block{
+				Head: coin.BlockHeader{
+					BkSeq: seq,
+				},
+				Body: coin.BlockBody{
+					Transactions: txns,
+				},
+			},
+		}
+	}
+
+	gateway := &MockGatewayer{}
+
+	events := make(chan visor.Event, 8)
+	unsubscribed := make(chan struct{})
+	gateway.On("SubscribeBlockchain", sseEventBufferSize).Return((<-chan visor.Event)(events), func() {
+		close(unsubscribed)
+	})
+	gateway.On("HeadBkSeq").Return(uint64(10), true, nil)
+
+	// The stream is resumed after block 8, blocks 9 and 10 are replayed, then the unconfirmed transactions
+	gateway.On("GetBlocksInRange", mock.Anything, uint64(9), uint64(10)).Return([]coin.SignedBlock{
+		newBlock(9, other.txn),
+		newBlock(10, confirmed.txn),
+	}, nil)
+	gateway.On("GetAllUnconfirmedTransactions").Return([]visor.UnconfirmedTransaction{
+		{
+			Transaction: unconfirmed.txn,
+		},
+	}, nil)
+
+	for _, x := range []transactionAndInputs{confirmed, unconfirmed, other} {
+		gateway.On("GetTransactionWithInputs", x.txn.Hash()).Return(&visor.Transaction{
+			Transaction: x.txn,
+		}, x.inputs, nil)
+	}
+
+	balance := wallet.BalancePair{
+		Confirmed: wallet.Balance{Coins: 1e6, Hours: 50},
+		Predicted: wallet.Balance{Coins: 2e6, Hours: 100},
+	}
+	gateway.On("GetBalanceOfAddresses", []cipher.Address{addr}).Return([]wallet.BalancePair{balance}, nil)
+
+	cfg := defaultMuxConfig()
+	cfg.disableHeaderCheck = true
+	server := httptest.NewServer(newServerMux(cfg, gateway))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v2/events?addrs="+addr.String(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "8")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rsp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer rsp.Body.Close() //nolint:errcheck
+
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	require.Equal(t, "text/event-stream", rsp.Header.Get("Content-Type"))
+	require.Empty(t, rsp.Header.Get("Content-Encoding"))
+
+	r := bufio.NewReader(rsp.Body)
+
+	// Replayed blocks, the transaction of block 9 is not streamed because it has no subscribed address
+	e := readSSEEvent(t, r)
+	require.Equal(t, "9", e.id)
+	require.Equal(t, WSEventBlock, e.event)
+	require.Equal(t, uint64(9), e.data.Block.Head.BkSeq)
+
+	e = readSSEEvent(t, r)
+	require.Equal(t, "10", e.id)
+	require.Equal(t, WSEventBlock, e.event)
+
+	e = readSSEEvent(t, r)
+	require.Equal(t, "10", e.id)
+	require.Equal(t, WSEventTransaction, e.event)
+	require.Equal(t, confirmed.txn.Hash().Hex(), e.data.Transaction.Transaction.Hash)
+
+	rb := readable.NewBalancePair(balance)
+	e = readSSEEvent(t, r)
+	require.Equal(t, "10", e.id)
+	require.Equal(t, WSEvent{
+		Type:    WSEventAddressBalance,
+		Address: addr.String(),
+		Balance: &rb,
+	}, e.data)
+
+	// Replayed unconfirmed transactions
+	e = readSSEEvent(t, r)
+	require.Equal(t, "10", e.id)
+	require.Equal(t, WSEventUnconfirmedTransaction, e.event)
+	require.Equal(t, unconfirmed.txn.Hash().Hex(), e.data.Transaction.Transaction.Hash)
+
+	// The blocks that were replayed are skipped, the new blocks are streamed
+	block10 := newBlock(10, confirmed.txn)
+	block11 := newBlock(11)
+	events <- visor.Event{
+		Type:  visor.EventBlockExecuted,
+		Block: &block10,
+	}
+	events <- visor.Event{
+		Type:  visor.EventBlockExecuted,
+		Block: &block11,
+	}
+
+	e = readSSEEvent(t, r)
+	require.Equal(t, "11", e.id)
+	require.Equal(t, WSEventBlock, e.event)
+	require.Equal(t, uint64(11), e.data.Block.Head.BkSeq)
+
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &other.txn,
+	}
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &unconfirmed.txn,
+	}
+
+	e = readSSEEvent(t, r)
+	require.Equal(t, "11", e.id)
+	require.Equal(t, WSEventUnconfirmedTransaction, e.event)
+	require.Equal(t, unconfirmed.txn.Hash().Hex(), e.data.Transaction.Transaction.Hash)
+
+	// The subscription to the blockchain events ends with the connection
+	err = rsp.Body.Close()
+	require.NoError(t, err)
+	<-unsubscribed
+}
+
+func TestEventsHandlerWriteTimeout(t *testing.T) {
+	gateway := &MockGatewayer{}
+
+	events := make(chan visor.Event, 8)
+	gateway.On("SubscribeBlockchain", sseEventBufferSize).Return((<-chan visor.Event)(events), func() {})
+	gateway.On("HeadBkSeq").Return(uint64(10), true, nil)
+
+	cfg := defaultMuxConfig()
+	cfg.disableHeaderCheck = true
+	server := httptest.NewUnstartedServer(newServerMux(cfg, gateway))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Config.ConnContext = connContext
+	server.Start()
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL + "/api/v2/events")
+	require.NoError(t, err)
+	defer rsp.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// The events are streamed after the write timeout of the server
+	time.Sleep(3 * server.Config.WriteTimeout)
+
+	r := bufio.NewReader(rsp.Body)
+	for seq := uint64(11); seq <= 12; seq++ {
+		events <- visor.Event{
+			Type: visor.EventBlockExecuted,
+			Block: &coin.SignedBlock{
+				Block: coin.Block{
+					Head: coin.BlockHeader{
+						BkSeq: seq,
+					},
+				},
+			},
+		}
+
+		e := readSSEEvent(t, r)
+		require.Equal(t, strconv.FormatUint(seq, 10), e.id)
+		require.Equal(t, WSEventBlock, e.event)
+
+		time.Sleep(2 * server.Config.WriteTimeout)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/skycoin/skycoin/src/util/droplet"
 	wh "github.com/skycoin/skycoin/src/util/http"
 	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor"
 )
 
 // CoinSupply records the coin supply info
@@ -48,8 +49,12 @@ func coinSupplyHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		allUnspents, err := gateway.GetUnspentOutputsSummary(nil)
+		allUnspents, err := gateway.GetUnspentOutputsSummary(r.Context(), nil)
 		if err != nil {
+			if writeContextError(w, apiVersion1, err) {
+				return
+			}
+
 			err = fmt.Errorf("gateway.GetUnspentOutputsSummary failed: %v", err)
 			wh.Error500(w, err.Error())
 			return
@@ -158,14 +163,17 @@ func coinSupplyHandler(gateway Gatewayer) http.HandlerFunc {
 // Richlist contains top address balances
 type Richlist struct {
 	Richlist []readable.RichlistBalance `json:"richlist"`
+	PageInfo readable.PageInfo          `json:"page_info"`
 }
 
 // richlistHandler returns the top skycoin holders
 // Method: GET
-// URI: /richlist?n=${number}&include-distribution=${bool}
+// URI: /richlist?n=${number}&page=${number}&include-distribution=${bool}&exclude=${addresses}
 // Args:
-//	n [int, number of results to include]
+//	n [int, number of results to include per page, all results if 0]
+//	page [int, page number, starting from 1]
 //  include-distribution [bool, include the distribution addresses in the richlist]
+//	exclude [comma-separated addresses to leave out of the richlist]
 func richlistHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -185,6 +193,31 @@ func richlistHandler(gateway Gatewayer) http.HandlerFunc {
 				return
 			}
 		}
+		if topn < 0 {
+			topn = 0
+		}
+
+		page := uint64(1)
+		pageStr := r.FormValue("page")
+		if pageStr != "" {
+			var err error
+			page, err = strconv.ParseUint(pageStr, 10, 64)
+			if err != nil || page == 0 {
+				wh.Error400(w, "invalid page")
+				return
+			}
+		}
+
+		if topn == 0 && page != 1 {
+			wh.Error400(w, "page requires n")
+			return
+		}
+
+		offset, err := mathutil.MultUint64(page-1, uint64(topn))
+		if err != nil {
+			wh.Error400(w, "invalid page")
+			return
+		}
 
 		var includeDistribution bool
 		includeDistributionStr := r.FormValue("include-distribution")
@@ -199,24 +232,50 @@ func richlistHandler(gateway Gatewayer) http.HandlerFunc {
 			}
 		}
 
-		richlist, err := gateway.GetRichlist(includeDistribution)
+		exclude, err := parseAddressesFromStr(r.FormValue("exclude"))
 		if err != nil {
-			wh.Error500(w, err.Error())
+			wh.Error400(w, err.Error())
 			return
 		}
 
-		if topn > 0 && topn < len(richlist) {
-			richlist = richlist[:topn]
+		richlistPage, err := gateway.GetRichlist(visor.RichlistParams{
+			IncludeDistribution: includeDistribution,
+			Exclude:             exclude,
+			Offset:              offset,
+			Limit:               uint64(topn),
+		})
+		if err != nil {
+			wh.Error500(w, err.Error())
+			return
 		}
 
-		readableRichlist, err := readable.NewRichlistBalances(richlist)
+		readableRichlist, err := readable.NewRichlistBalances(richlistPage.Richlist)
 		if err != nil {
 			wh.Error500(w, err.Error())
 			return
 		}
 
+		pageSize := uint64(topn)
+		if pageSize == 0 {
+			pageSize = richlistPage.Total
+		}
+
+		var totalPages uint64
+		if pageSize != 0 {
+			totalPages = richlistPage.Total / pageSize
+			if richlistPage.Total%pageSize != 0 {
+				totalPages++
+			}
+		}
+
 		wh.SendJSONOr500(logger, w, Richlist{
 			Richlist: readableRichlist,
+			PageInfo: readable.PageInfo{
+				TotalPages:  totalPages,
+				PageSize:    pageSize,
+				CurrentPage: page,
+				Total:       richlistPage.Total,
+			},
 		})
 	}
 }
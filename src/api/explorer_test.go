@@ -206,7 +206,7 @@ func TestCoinSupply(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			endpoint := "/api/v1/coinSupply"
 			gateway := &MockGatewayer{}
-			gateway.On("GetUnspentOutputsSummary", mock.Anything).Return(tc.gatewayGetUnspentOutputsResult, tc.gatewayGetUnspentOutputsErr)
+			gateway.On("GetUnspentOutputsSummary", mock.Anything, mock.Anything).Return(tc.gatewayGetUnspentOutputsResult, tc.gatewayGetUnspentOutputsErr)
 			gateway.On("VisorConfig").Return(visor.Config{
 				Distribution: params.MainNetDistribution,
 			})
@@ -246,16 +246,75 @@ func TestCoinSupply(t *testing.T) {
 func TestGetRichlist(t *testing.T) {
 	type httpParams struct {
 		topn                string
+		page                string
 		includeDistribution string
+		exclude             string
 	}
+
+	richlist := visor.Richlist{
+		{
+			Address: cipher.MustDecodeBase58Address("2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF"),
+			Coins:   1000000e6,
+			Locked:  false,
+		},
+		{
+			Address: cipher.MustDecodeBase58Address("27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1"),
+			Coins:   500000e6,
+			Locked:  false,
+		},
+		{
+			Address: cipher.MustDecodeBase58Address("2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW"),
+			Coins:   500000e6,
+			Locked:  false,
+		},
+		{
+			Address: cipher.MustDecodeBase58Address("2TmvdBWJgxMwGs84R4drS9p5fYkva4dGdfs"),
+			Coins:   244458e6,
+			Locked:  true,
+		},
+		{
+			Address: cipher.MustDecodeBase58Address("24gvUHXHtSg5drKiFsMw7iMgoN2PbLub53C"),
+			Coins:   195503e6,
+			Locked:  false,
+		},
+	}
+
+	readableRichlist := []readable.RichlistBalance{
+		{
+			Address: "2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF",
+			Coins:   "1000000.000000",
+			Locked:  false,
+		},
+		{
+			Address: "27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1",
+			Coins:   "500000.000000",
+			Locked:  false,
+		},
+		{
+			Address: "2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW",
+			Coins:   "500000.000000",
+			Locked:  false,
+		},
+		{
+			Address: "2TmvdBWJgxMwGs84R4drS9p5fYkva4dGdfs",
+			Coins:   "244458.000000",
+			Locked:  true,
+		},
+		{
+			Address: "24gvUHXHtSg5drKiFsMw7iMgoN2PbLub53C",
+			Coins:   "195503.000000",
+			Locked:  false,
+		},
+	}
+
 	tt := []struct {
 		name                     string
 		method                   string
 		status                   int
 		err                      string
 		httpParams               *httpParams
-		includeDistribution      bool
-		gatewayGetRichlistResult visor.Richlist
+		gatewayGetRichlistParams visor.RichlistParams
+		gatewayGetRichlistResult *visor.RichlistPage
 		gatewayGetRichlistErr    error
 		result                   Richlist
 		csrfDisabled             bool
@@ -275,6 +334,44 @@ func TestGetRichlist(t *testing.T) {
 				topn: "bad topn",
 			},
 		},
+		{
+			name:   "400 - bad page param",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid page",
+			httpParams: &httpParams{
+				page: "bad page",
+			},
+		},
+		{
+			name:   "400 - zero page param",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid page",
+			httpParams: &httpParams{
+				page: "0",
+			},
+		},
+		{
+			name:   "400 - page overflow",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid page",
+			httpParams: &httpParams{
+				topn: "20",
+				page: "18446744073709551615",
+			},
+		},
+		{
+			name:   "400 - page without n",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - page requires n",
+			httpParams: &httpParams{
+				topn: "0",
+				page: "2",
+			},
+		},
 		{
 			name:   "400 - include-distribution",
 			method: http.MethodGet,
@@ -285,6 +382,15 @@ func TestGetRichlist(t *testing.T) {
 				includeDistribution: "bad include-distribution",
 			},
 		},
+		{
+			name:   "400 - bad exclude param",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - address \"bad\" is invalid: Invalid address length",
+			httpParams: &httpParams{
+				exclude: "bad",
+			},
+		},
 		{
 			name:   "500 - gw GetRichlist error",
 			method: http.MethodGet,
@@ -294,60 +400,89 @@ func TestGetRichlist(t *testing.T) {
 				topn:                "1",
 				includeDistribution: "false",
 			},
+			gatewayGetRichlistParams: visor.RichlistParams{
+				Exclude: []cipher.Address{},
+				Limit:   1,
+			},
 			gatewayGetRichlistErr: errors.New("gatewayGetRichlistErr"),
 		},
+		{
+			name:   "200 default",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			gatewayGetRichlistParams: visor.RichlistParams{
+				Exclude: []cipher.Address{},
+				Limit:   20,
+			},
+			gatewayGetRichlistResult: &visor.RichlistPage{
+				Richlist: richlist,
+				Total:    5,
+			},
+			result: Richlist{
+				Richlist: readableRichlist,
+				PageInfo: readable.PageInfo{
+					TotalPages:  1,
+					PageSize:    20,
+					CurrentPage: 1,
+					Total:       5,
+				},
+			},
+		},
 		{
 			name:   "200",
 			method: http.MethodGet,
 			status: http.StatusOK,
 			httpParams: &httpParams{
 				topn:                "3",
-				includeDistribution: "false",
+				includeDistribution: "true",
 			},
-			gatewayGetRichlistResult: visor.Richlist{
-				{
-					Address: cipher.MustDecodeBase58Address("2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF"),
-					Coins:   1000000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1"),
-					Coins:   500000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW"),
-					Coins:   500000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("2TmvdBWJgxMwGs84R4drS9p5fYkva4dGdfs"),
-					Coins:   244458e6,
-					Locked:  false,
+			gatewayGetRichlistParams: visor.RichlistParams{
+				IncludeDistribution: true,
+				Exclude:             []cipher.Address{},
+				Limit:               3,
+			},
+			gatewayGetRichlistResult: &visor.RichlistPage{
+				Richlist: richlist[:3],
+				Total:    5,
+			},
+			result: Richlist{
+				Richlist: readableRichlist[:3],
+				PageInfo: readable.PageInfo{
+					TotalPages:  2,
+					PageSize:    3,
+					CurrentPage: 1,
+					Total:       5,
 				},
-				{
-					Address: cipher.MustDecodeBase58Address("24gvUHXHtSg5drKiFsMw7iMgoN2PbLub53C"),
-					Coins:   195503e6,
-					Locked:  false,
+			},
+		},
+		{
+			name:   "200 page and exclude",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			httpParams: &httpParams{
+				topn:    "2",
+				page:    "2",
+				exclude: "27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1,2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW",
+			},
+			gatewayGetRichlistParams: visor.RichlistParams{
+				Exclude: []cipher.Address{
+					cipher.MustDecodeBase58Address("27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1"),
+					cipher.MustDecodeBase58Address("2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW"),
 				},
+				Offset: 2,
+				Limit:  2,
+			},
+			gatewayGetRichlistResult: &visor.RichlistPage{
+				Richlist: richlist[4:],
+				Total:    3,
 			},
 			result: Richlist{
-				Richlist: []readable.RichlistBalance{
-					{
-						Address: "2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF",
-						Coins:   "1000000.000000",
-						Locked:  false,
-					},
-					{
-						Address: "27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1",
-						Coins:   "500000.000000",
-						Locked:  false,
-					},
-					{
-						Address: "2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW",
-						Coins:   "500000.000000",
-						Locked:  false,
-					},
+				Richlist: readableRichlist[4:],
+				PageInfo: readable.PageInfo{
+					TotalPages:  2,
+					PageSize:    2,
+					CurrentPage: 2,
+					Total:       3,
 				},
 			},
 		},
@@ -359,60 +494,40 @@ func TestGetRichlist(t *testing.T) {
 				topn:                "0",
 				includeDistribution: "false",
 			},
-			gatewayGetRichlistResult: visor.Richlist{
-				{
-					Address: cipher.MustDecodeBase58Address("2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF"),
-					Coins:   1000000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1"),
-					Coins:   500000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW"),
-					Coins:   500000e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("2TmvdBWJgxMwGs84R4drS9p5fYkva4dGdfs"),
-					Coins:   244458e6,
-					Locked:  false,
-				},
-				{
-					Address: cipher.MustDecodeBase58Address("24gvUHXHtSg5drKiFsMw7iMgoN2PbLub53C"),
-					Coins:   195503e6,
-					Locked:  false,
+			gatewayGetRichlistParams: visor.RichlistParams{
+				Exclude: []cipher.Address{},
+			},
+			gatewayGetRichlistResult: &visor.RichlistPage{
+				Richlist: richlist,
+				Total:    5,
+			},
+			result: Richlist{
+				Richlist: readableRichlist,
+				PageInfo: readable.PageInfo{
+					TotalPages:  1,
+					PageSize:    5,
+					CurrentPage: 1,
+					Total:       5,
 				},
 			},
+		},
+		{
+			name:   "200 empty",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			httpParams: &httpParams{
+				topn: "0",
+			},
+			gatewayGetRichlistParams: visor.RichlistParams{
+				Exclude: []cipher.Address{},
+			},
+			gatewayGetRichlistResult: &visor.RichlistPage{
+				Richlist: visor.Richlist{},
+			},
 			result: Richlist{
-				Richlist: []readable.RichlistBalance{
-					{
-						Address: "2fGC7kwAM9yZyEF1QqBqp8uo9RUsF6ENGJF",
-						Coins:   "1000000.000000",
-						Locked:  false,
-					},
-					{
-						Address: "27jg25DZX21MXMypVbKJMmgCJ5SPuEunMF1",
-						Coins:   "500000.000000",
-						Locked:  false,
-					},
-					{
-						Address: "2fGi2jhvp6ppHg3DecguZgzqvpJj2Gd4KHW",
-						Coins:   "500000.000000",
-						Locked:  false,
-					},
-					{
-						Address: "2TmvdBWJgxMwGs84R4drS9p5fYkva4dGdfs",
-						Coins:   "244458.000000",
-						Locked:  false,
-					},
-					{
-						Address: "24gvUHXHtSg5drKiFsMw7iMgoN2PbLub53C",
-						Coins:   "195503.000000",
-						Locked:  false,
-					},
+				Richlist: []readable.RichlistBalance{},
+				PageInfo: readable.PageInfo{
+					CurrentPage: 1,
 				},
 			},
 		},
@@ -422,16 +537,22 @@ func TestGetRichlist(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			endpoint := "/api/v1/richlist"
 			gateway := &MockGatewayer{}
-			gateway.On("GetRichlist", tc.includeDistribution).Return(tc.gatewayGetRichlistResult, tc.gatewayGetRichlistErr)
+			gateway.On("GetRichlist", tc.gatewayGetRichlistParams).Return(tc.gatewayGetRichlistResult, tc.gatewayGetRichlistErr)
 
 			v := url.Values{}
 			if tc.httpParams != nil {
 				if tc.httpParams.topn != "" {
 					v.Add("n", tc.httpParams.topn)
 				}
+				if tc.httpParams.page != "" {
+					v.Add("page", tc.httpParams.page)
+				}
 				if tc.httpParams.includeDistribution != "" {
 					v.Add("include-distribution", tc.httpParams.includeDistribution)
 				}
+				if tc.httpParams.exclude != "" {
+					v.Add("exclude", tc.httpParams.exclude)
+				}
 			}
 			if len(v) > 0 {
 				endpoint += "?" + v.Encode()
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"time"
 
+	"github.com/skycoin/skycoin/src/admin"
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
 	"github.com/skycoin/skycoin/src/kvstorage"
+	"github.com/skycoin/skycoin/src/notify"
 	"github.com/skycoin/skycoin/src/transaction"
 	"github.com/skycoin/skycoin/src/visor"
 	"github.com/skycoin/skycoin/src/visor/historydb"
@@ -15,21 +18,25 @@ import (
 
 //go:generate mockery -name Gatewayer -case underscore -inpkg -testonly
 
-// Gateway bundles daemon.Daemon, Visor, wallet.Service and kvstorage.Manager into a single object
+// Gateway bundles daemon.Daemon, Visor, wallet.Service, kvstorage.Manager, notify.Dispatcher and admin.Admin into a single object
 type Gateway struct {
 	*daemon.Daemon
 	*visor.Visor
 	*wallet.Service
 	*kvstorage.Manager
+	*notify.Dispatcher
+	*admin.Admin
 }
 
 // NewGateway creates a Gateway
-func NewGateway(d *daemon.Daemon, v *visor.Visor, w *wallet.Service, m *kvstorage.Manager) *Gateway {
+func NewGateway(d *daemon.Daemon, v *visor.Visor, w *wallet.Service, m *kvstorage.Manager, n *notify.Dispatcher, a *admin.Admin) *Gateway {
 	return &Gateway{
-		Daemon:  d,
-		Visor:   v,
-		Service: w,
-		Manager: m,
+		Daemon:     d,
+		Visor:      v,
+		Service:    w,
+		Manager:    m,
+		Dispatcher: n,
+		Admin:      a,
 	}
 }
 
@@ -39,6 +46,8 @@ type Gatewayer interface {
 	Visorer
 	Walleter
 	Storer
+	Notifier
+	Adminer
 }
 
 // Daemoner interface for daemon.Daemon methods used by the API
@@ -53,12 +62,21 @@ type Daemoner interface {
 	GetBlockchainProgress(headSeq uint64) *daemon.BlockchainProgress
 	InjectBroadcastTransaction(txn coin.Transaction) error
 	InjectTransaction(txn coin.Transaction) error
+	GetTransactionStatus(txid cipher.SHA256) (*daemon.TransactionStatus, error)
+	RebroadcastTransaction(txid cipher.SHA256) (int, error)
+	GetMempoolTransactions(addrs []cipher.Address) ([]daemon.MempoolTransaction, error)
+	GetMempoolEvictions() []daemon.MempoolEviction
+	ReconnectPeers() (int, error)
+	BanPeer(ip string) (int, error)
+	UnbanPeer(ip string) error
+	GetBannedPeers() []daemon.BannedPeer
 }
 
 // Visorer interface for visor.Visor methods used by the API
 type Visorer interface {
 	VisorConfig() visor.Config
 	StartedAt() time.Time
+	GetDBVerifiedAt() (time.Time, error)
 	HeadBkSeq() (uint64, bool, error)
 	GetBlockchainMetadata() (*visor.BlockchainMetadata, error)
 	ResendUnconfirmedTxns() ([]cipher.SHA256, error)
@@ -68,33 +86,37 @@ type Visorer interface {
 	GetSignedBlockBySeqVerbose(seq uint64) (*coin.SignedBlock, [][]visor.TransactionInput, error)
 	GetBlocks(seqs []uint64) ([]coin.SignedBlock, error)
 	GetBlocksVerbose(seqs []uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error)
-	GetBlocksInRange(start, end uint64) ([]coin.SignedBlock, error)
-	GetBlocksInRangeVerbose(start, end uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error)
+	GetBlocksInRange(ctx context.Context, start, end uint64) ([]coin.SignedBlock, error)
+	GetBlocksInRangeVerbose(ctx context.Context, start, end uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error)
 	GetLastBlocks(num uint64) ([]coin.SignedBlock, error)
 	GetLastBlocksVerbose(num uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error)
-	GetUnspentOutputsSummary(filters []visor.OutputsFilter) (*visor.UnspentOutputsSummary, error)
+	GetUnspentOutputsSummary(ctx context.Context, filters []visor.OutputsFilter) (*visor.UnspentOutputsSummary, error)
 	GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet.BalancePair, error)
 	VerifyTxnVerbose(txn *coin.Transaction, signed visor.TxnSignedFlag) ([]visor.TransactionInput, bool, error)
 	AddressCount() (uint64, error)
 	GetUxOutByID(id cipher.SHA256) (*historydb.UxOut, uint64, error)
 	GetSpentOutputsForAddresses(addr []cipher.Address) ([][]historydb.UxOut, uint64, error)
+	GetAddressBalanceAtSeq(addr cipher.Address, seq uint64) (*visor.HistoricalBalance, error)
+	GetAddressBalanceAtTime(addr cipher.Address, t uint64) (*visor.HistoricalBalance, error)
 	// GetVerboseTransactionsForAddress(a cipher.Address) ([]visor.Transaction, [][]visor.TransactionInput, error)
-	GetRichlist(includeDistribution bool) (visor.Richlist, error)
+	GetRichlist(p visor.RichlistParams) (*visor.RichlistPage, error)
 	GetAllUnconfirmedTransactions() ([]visor.UnconfirmedTransaction, error)
 	GetAllUnconfirmedTransactionsVerbose() ([]visor.UnconfirmedTransaction, [][]visor.TransactionInput, error)
 	GetTransaction(txid cipher.SHA256) (*visor.Transaction, error)
 	GetTransactionWithInputs(txid cipher.SHA256) (*visor.Transaction, []visor.TransactionInput, error)
-	GetTransactions(flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, uint64, error)
-	GetTransactionsWithInputs(flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, [][]visor.TransactionInput, uint64, error)
+	GetTransactions(ctx context.Context, flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, visor.TxnPage, error)
+	GetTransactionsWithInputs(ctx context.Context, flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, [][]visor.TransactionInput, visor.TxnPage, error)
 	GetWalletUnconfirmedTransactions(wltID string) ([]visor.UnconfirmedTransaction, error)
 	GetWalletUnconfirmedTransactionsVerbose(wltID string) ([]visor.UnconfirmedTransaction, [][]visor.TransactionInput, error)
-	GetWalletBalance(wltID string) (wallet.BalancePair, wallet.AddressBalances, error)
+	GetWalletBalance(ctx context.Context, wltID string) (wallet.BalancePair, wallet.AddressBalances, error)
 	CreateTransaction(p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletCreateTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletCreateTransactionSigned(wltID string, password []byte, p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletSignTransaction(wltID string, password []byte, txn *coin.Transaction, signIndexes []int) (*coin.Transaction, []visor.TransactionInput, error)
+	WalletsCreateTransactionSigned(spends []visor.WalletSpend, p transaction.Params, ignoreUnconfirmed bool) (*coin.Transaction, []visor.TransactionInput, error)
 	ScanWalletAddresses(wltID string, password []byte, num uint64) ([]cipher.Address, error)
 	TransactionsFinder() wallet.TransactionsFinder
+	SubscribeBlockchain(bufferSize int) (<-chan visor.Event, func())
 }
 
 // Walleter interface for wallet.Service methods used by the API
@@ -105,11 +127,18 @@ type Walleter interface {
 	GetWalletSeed(wltID string, password []byte) (string, string, error)
 	CreateWallet(wltName string, options wallet.Options) (wallet.Wallet, error)
 	RecoverWallet(wltID, seed, seedPassphrase string, password []byte) (wallet.Wallet, error)
+	VerifySeedPassphrase(wltID string, password []byte, seedPassphrase string) error
+	VerifyWalletSeed(wltID, seed, seedPassphrase string) (bool, error)
+	SignMessage(wltID string, password []byte, addr cipher.Address, msg []byte) (cipher.Sig, error)
 	NewAddresses(wltID string, password []byte, n uint64, options ...wallet.Option) ([]cipher.Address, error)
 	ScanAddresses(wltID string, password []byte, n uint64, tf wallet.TransactionsFinder) ([]cipher.Address, error)
 	GetWallet(wltID string) (wallet.Wallet, error)
 	GetWallets() (wallet.Wallets, error)
 	UpdateWalletLabel(wltID, label string) error
+	GetAddressLabels(wltID string) ([]wallet.AddressLabel, error)
+	SetAddressLabels(wltID string, labels []wallet.AddressLabel) ([]cipher.Addresser, error)
+	UnlockWallet(wltID string, password []byte, ttl time.Duration) (time.Time, error)
+	LockWallet(wltID string) error
 	WalletDir() (string, error)
 }
 
@@ -120,3 +149,26 @@ type Storer interface {
 	AddStorageValue(storageType kvstorage.Type, key, val string) error
 	RemoveStorageValue(storageType kvstorage.Type, key string) error
 }
+
+// Notifier interface for notify.Dispatcher methods used by the API
+type Notifier interface {
+	AddWebhook(w notify.Webhook) (*notify.Webhook, error)
+	RemoveWebhook(id string) error
+	GetWebhooks() ([]notify.Webhook, error)
+	GetWebhookDeliveries(id string) ([]notify.Delivery, error)
+	AddWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error)
+	RemoveWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error)
+	GetWatchedAddresses() ([]cipher.Address, error)
+	IsWatchedAddress(addr cipher.Address) bool
+}
+
+// Adminer interface for admin.Admin methods used by the API
+type Adminer interface {
+	VerifyDB() (*admin.DBVerification, error)
+	GetDBVerification() (*admin.DBVerification, error)
+	VerifyChain(c admin.Checkpoint) (*admin.ChainVerification, error)
+	RotateLogFile() (string, error)
+	GetLogLevel() (string, error)
+	SetLogLevel(level string) error
+	RequestShutdown() error
+}
@@ -0,0 +1,1253 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// maxGraphQLDepth is the maximum depth of the selections of a GraphQL query,
+	// it limits the number of gateway calls a single query can make
+	maxGraphQLDepth = 10
+	// maxGraphQLBlocks is the maximum number of blocks returned by the blocks query
+	maxGraphQLBlocks = 100
+	// defaultGraphQLTransactionsLimit and maxGraphQLTransactionsLimit are the default and maximum
+	// number of transactions returned by the transactions field of an address
+	defaultGraphQLTransactionsLimit = 10
+	maxGraphQLTransactionsLimit     = 100
+	// maxGraphQLCost is the maximum cost of a GraphQL query, the number of fields it can resolve,
+	// see graphqlQueryCost. It limits the breadth of a query, including aliases of the same field.
+	maxGraphQLCost = 10000
+	// defaultGraphQLListSize is the number of items graphqlQueryCost assumes for the lists of objects
+	// without a limit, e.g. the transactions of a block or the inputs of a transaction
+	defaultGraphQLListSize = 10
+)
+
+// GraphQLRequest is the request data for GET and POST /api/v2/graphql
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is returned by GET and POST /api/v2/graphql
+type GraphQLResponse struct {
+	Data   interface{}            `json:"data,omitempty"`
+	Errors []GraphQLResponseError `json:"errors,omitempty"`
+}
+
+// GraphQLResponseError is an error of a GraphQL query
+type GraphQLResponseError struct {
+	Message string `json:"message"`
+	// Path is the path of the field that failed, if the query was executed
+	Path []interface{} `json:"path,omitempty"`
+}
+
+// graphqlHandler executes GraphQL queries over blocks, transactions, outputs and addresses,
+// so that nested data, e.g. the source outputs of the inputs of the transactions of a block,
+// is fetched in a single request
+// Method: GET, POST
+// URI: /api/v2/graphql
+// Args:
+//	query: GraphQL query [required]
+//	operationName: name of the operation to execute, if the query has several operations [optional]
+//	variables: JSON object of the variables of the query [optional]
+func graphqlHandler(gateway Gatewayer) http.HandlerFunc {
+	schema, err := newGraphQLSchema(gateway)
+	if err != nil {
+		logger.WithError(err).Panic("newGraphQLSchema failed")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		switch r.Method {
+		case http.MethodGet:
+			req.Query = r.FormValue("query")
+			req.OperationName = r.FormValue("operationName")
+			if v := r.FormValue("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					writeError400Response(w, fmt.Sprintf("invalid variables: %v", err))
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError400Response(w, err.Error())
+				return
+			}
+		default:
+			writeError405Response(w)
+			return
+		}
+
+		if req.Query == "" {
+			writeError400Response(w, "query is required")
+			return
+		}
+
+		// Queries that do not parse are reported by graphql.Do with the location of the syntax error
+		if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+			if depth := graphqlQueryDepth(doc); depth > maxGraphQLDepth {
+				writeError400Response(w, fmt.Sprintf("query depth %d exceeds the maximum depth %d", depth, maxGraphQLDepth))
+				return
+			}
+
+			if cost := graphqlQueryCost(schema, doc, req.Variables); cost > maxGraphQLCost {
+				writeError400Response(w, fmt.Sprintf("query cost %d exceeds the maximum cost %d", cost, maxGraphQLCost))
+				return
+			}
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        r.Context(),
+		})
+
+		if err := r.Context().Err(); err != nil && writeContextError(w, apiVersion2, err) {
+			return
+		}
+
+		resp := GraphQLResponse{
+			Data: result.Data,
+		}
+		for _, e := range result.Errors {
+			resp.Errors = append(resp.Errors, GraphQLResponseError{
+				Message: e.Message,
+				Path:    e.Path,
+			})
+		}
+
+		out, err := json.MarshalIndent(resp, "", "    ")
+		if err != nil {
+			writeError500Response(w, "json.MarshalIndent failed")
+			return
+		}
+
+		w.Header().Add("Content-Type", ContentTypeJSON)
+		if _, err := w.Write(out); err != nil {
+			logger.WithError(err).Error("http Write failed")
+		}
+	}
+}
+
+// graphqlQueryDepth returns the maximum depth of the selections of the operations of a GraphQL document.
+// Fragments count at the depth they are spread at.
+func graphqlQueryDepth(doc *ast.Document) int {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, d := range doc.Definitions {
+		if f, ok := d.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	// spreading tracks the fragments being spread, to stop at fragment cycles, which fail validation
+	spreading := make(map[string]bool)
+
+	var selectionDepth func(ss *ast.SelectionSet, depth int) int
+	selectionDepth = func(ss *ast.SelectionSet, depth int) int {
+		if ss == nil {
+			return depth
+		}
+
+		max := depth
+		for _, s := range ss.Selections {
+			d := depth
+			switch s := s.(type) {
+			case *ast.Field:
+				d = selectionDepth(s.SelectionSet, depth+1)
+			case *ast.InlineFragment:
+				d = selectionDepth(s.SelectionSet, depth)
+			case *ast.FragmentSpread:
+				if s.Name == nil {
+					continue
+				}
+				f, ok := fragments[s.Name.Value]
+				if !ok || spreading[s.Name.Value] {
+					continue
+				}
+				spreading[s.Name.Value] = true
+				d = selectionDepth(f.SelectionSet, depth)
+				delete(spreading, s.Name.Value)
+			}
+
+			if d > max {
+				max = d
+			}
+		}
+
+		return max
+	}
+
+	max := 0
+	for _, d := range doc.Definitions {
+		if op, ok := d.(*ast.OperationDefinition); ok {
+			if depth := selectionDepth(op.SelectionSet, 0); depth > max {
+				max = depth
+			}
+		}
+	}
+
+	return max
+}
+
+// graphqlQueryCost returns the cost of the most expensive operation of a GraphQL document,
+// which is the number of fields it resolves, aliases included. The fields selected in the
+// items of a list are counted once per item: the number of blocks is read from the arguments
+// of blocks, the number of transactions of an address from its limit argument, and the other
+// lists of objects are assumed to have defaultGraphQLListSize items. The cost saturates at math.MaxUint64.
+func graphqlQueryCost(schema graphql.Schema, doc *ast.Document, variables map[string]interface{}) uint64 {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, d := range doc.Definitions {
+		if f, ok := d.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	// spreading tracks the fragments being spread, to stop at fragment cycles, which fail validation
+	spreading := make(map[string]bool)
+
+	// objectType returns the object type of a type condition, or t if there is none
+	objectType := func(t *graphql.Object, cond *ast.Named) *graphql.Object {
+		if cond == nil || cond.Name == nil {
+			return t
+		}
+		if o, ok := schema.Type(cond.Name.Value).(*graphql.Object); ok {
+			return o
+		}
+		return t
+	}
+
+	var selectionCost func(ss *ast.SelectionSet, t *graphql.Object, n uint64) uint64
+	selectionCost = func(ss *ast.SelectionSet, t *graphql.Object, n uint64) uint64 {
+		if ss == nil {
+			return 0
+		}
+
+		var cost uint64
+		for _, s := range ss.Selections {
+			switch s := s.(type) {
+			case *ast.Field:
+				cost = addUint64Saturated(cost, n)
+				if s.SelectionSet == nil || s.Name == nil {
+					continue
+				}
+
+				// The fields of types that are not in the schema, e.g. of the introspection
+				// fields, are counted without list items
+				var fieldType *graphql.Object
+				items := uint64(1)
+				if t != nil {
+					if f, ok := t.Fields()[s.Name.Value]; ok {
+						ft := f.Type
+						if nn, ok := ft.(*graphql.NonNull); ok {
+							ft = nn.OfType
+						}
+						if l, ok := ft.(*graphql.List); ok {
+							items = graphqlListSize(t.Name(), s, variables)
+							ft = l.OfType
+						}
+						if nn, ok := ft.(*graphql.NonNull); ok {
+							ft = nn.OfType
+						}
+						fieldType, _ = ft.(*graphql.Object)
+					}
+				}
+
+				cost = addUint64Saturated(cost, selectionCost(s.SelectionSet, fieldType, mulUint64Saturated(n, items)))
+			case *ast.InlineFragment:
+				cost = addUint64Saturated(cost, selectionCost(s.SelectionSet, objectType(t, s.TypeCondition), n))
+			case *ast.FragmentSpread:
+				if s.Name == nil {
+					continue
+				}
+				f, ok := fragments[s.Name.Value]
+				if !ok || spreading[s.Name.Value] {
+					continue
+				}
+				spreading[s.Name.Value] = true
+				cost = addUint64Saturated(cost, selectionCost(f.SelectionSet, objectType(t, f.TypeCondition), n))
+				delete(spreading, s.Name.Value)
+			}
+		}
+
+		return cost
+	}
+
+	var max uint64
+	for _, d := range doc.Definitions {
+		if op, ok := d.(*ast.OperationDefinition); ok {
+			if cost := selectionCost(op.SelectionSet, schema.QueryType(), 1); cost > max {
+				max = cost
+			}
+		}
+	}
+
+	return max
+}
+
+// graphqlListSize returns the number of items graphqlQueryCost assumes for the list field f of typeName
+func graphqlListSize(typeName string, f *ast.Field, variables map[string]interface{}) uint64 {
+	switch typeName + "." + f.Name.Value {
+	case "Query.blocks":
+		if last, ok := graphqlUint64Arg(f, "last", variables); ok {
+			if last < maxGraphQLBlocks {
+				return last
+			}
+			return maxGraphQLBlocks
+		}
+
+		start, hasStart := graphqlUint64Arg(f, "start", variables)
+		end, hasEnd := graphqlUint64Arg(f, "end", variables)
+		if hasStart && hasEnd && end >= start && end-start < maxGraphQLBlocks {
+			return end - start + 1
+		}
+		return maxGraphQLBlocks
+	case "Address.transactions":
+		limit, ok := graphqlUint64Arg(f, "limit", variables)
+		if !ok {
+			return defaultGraphQLTransactionsLimit
+		}
+		if limit < maxGraphQLTransactionsLimit {
+			return limit
+		}
+		return maxGraphQLTransactionsLimit
+	default:
+		return defaultGraphQLListSize
+	}
+}
+
+// graphqlUint64Arg returns the value of the integer argument name of f, given as a literal or a variable
+func graphqlUint64Arg(f *ast.Field, name string, variables map[string]interface{}) (uint64, bool) {
+	for _, a := range f.Arguments {
+		if a.Name == nil || a.Name.Value != name {
+			continue
+		}
+
+		var v interface{}
+		switch av := a.Value.(type) {
+		case *ast.IntValue:
+			v = av.Value
+		case *ast.StringValue:
+			v = av.Value
+		case *ast.Variable:
+			if av.Name != nil {
+				v = variables[av.Name.Value]
+			}
+		}
+
+		switch v := v.(type) {
+		case string:
+			n, err := strconv.ParseUint(v, 10, 64)
+			return n, err == nil
+		case float64:
+			if v < 0 || v > math.MaxUint64 {
+				return 0, false
+			}
+			return uint64(v), true
+		}
+		return 0, false
+	}
+
+	return 0, false
+}
+
+func addUint64Saturated(a, b uint64) uint64 {
+	c, err := mathutil.AddUint64(a, b)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return c
+}
+
+func mulUint64Saturated(a, b uint64) uint64 {
+	c, err := mathutil.MultUint64(a, b)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return c
+}
+
+// gqlBlock is the source of the Block type
+type gqlBlock struct {
+	readable.BlockVerbose
+	// headSeq is the sequence of the head block when the block was queried, to compute the height of its transactions
+	headSeq uint64
+}
+
+// gqlTransaction is the source of the Transaction type
+type gqlTransaction struct {
+	readable.BlockTransactionVerbose
+	Status    readable.TransactionStatus
+	Timestamp uint64
+}
+
+// gqlOutput is the source of the Output type. The history of the output is only queried if requested.
+type gqlOutput struct {
+	readable.TransactionOutput
+	SrcTxid string
+
+	historyOnce sync.Once
+	history     *readable.SpentOutput
+	historyErr  error
+}
+
+// getHistory returns the history of the output, nil if the output is not in a block
+func (o *gqlOutput) getHistory(gateway Gatewayer) (*readable.SpentOutput, error) {
+	o.historyOnce.Do(func() {
+		uxid, err := cipher.SHA256FromHex(o.Hash)
+		if err != nil {
+			o.historyErr = err
+			return
+		}
+
+		uxOut, headTime, err := gateway.GetUxOutByID(uxid)
+		if err != nil {
+			o.historyErr = err
+			return
+		}
+		if uxOut == nil {
+			return
+		}
+
+		o.history, o.historyErr = readable.NewSpentOutput(uxOut, headTime)
+	})
+
+	return o.history, o.historyErr
+}
+
+// isSpentOutput returns true if the output history has a spending transaction
+func isSpentOutput(h *readable.SpentOutput) bool {
+	return h != nil && h.SpentTxnID != (cipher.SHA256{}).Hex()
+}
+
+// gqlAddress is the source of the Address type
+type gqlAddress struct {
+	cipher.Address
+}
+
+func newGQLBlock(b *readable.BlockVerbose, headSeq uint64) *gqlBlock {
+	return &gqlBlock{
+		BlockVerbose: *b,
+		headSeq:      headSeq,
+	}
+}
+
+func newGQLBlockTransaction(b *gqlBlock, txn readable.BlockTransactionVerbose) *gqlTransaction {
+	var height uint64
+	if b.headSeq >= b.Head.BkSeq {
+		height = b.headSeq - b.Head.BkSeq + 1
+	}
+
+	return &gqlTransaction{
+		BlockTransactionVerbose: txn,
+		Status: readable.TransactionStatus{
+			Confirmed: true,
+			Height:    height,
+			BlockSeq:  b.Head.BkSeq,
+		},
+		Timestamp: b.Head.Time,
+	}
+}
+
+func newGQLTransaction(txn *visor.Transaction, inputs []visor.TransactionInput) (*gqlTransaction, error) {
+	t, err := readable.NewTransactionVerbose(*txn, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gqlTransaction{
+		BlockTransactionVerbose: t.BlockTransactionVerbose,
+		Status:                  *t.Status,
+		Timestamp:               t.Timestamp,
+	}, nil
+}
+
+// newGraphQLSchema creates the GraphQL schema of the /api/v2/graphql endpoint
+func newGraphQLSchema(gateway Gatewayer) (graphql.Schema, error) {
+	uint64Type := graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Uint64",
+		Description: "Unsigned 64-bit integer, e.g. a number of coin hours or a block sequence",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case uint64:
+				return v
+			case uint32:
+				return uint64(v)
+			case *uint64:
+				if v == nil {
+					return nil
+				}
+				return *v
+			default:
+				return nil
+			}
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case float64:
+				if v < 0 || v != float64(uint64(v)) {
+					return nil
+				}
+				return uint64(v)
+			case int:
+				if v < 0 {
+					return nil
+				}
+				return uint64(v)
+			case string:
+				n, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					return nil
+				}
+				return n
+			default:
+				return nil
+			}
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch v := valueAST.(type) {
+			case *ast.IntValue:
+				n, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil
+				}
+				return n
+			case *ast.StringValue:
+				n, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil
+				}
+				return n
+			default:
+				return nil
+			}
+		},
+	})
+
+	getBlockBySeq := func(seq uint64) (*gqlBlock, error) {
+		b, inputs, err := gateway.GetSignedBlockBySeqVerbose(seq)
+		if err != nil || b == nil {
+			return nil, err
+		}
+
+		headSeq, _, err := gateway.HeadBkSeq()
+		if err != nil {
+			return nil, err
+		}
+
+		rb, err := readable.NewBlockVerbose(b.Block, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		return newGQLBlock(rb, headSeq), nil
+	}
+
+	getTransaction := func(txid string) (*gqlTransaction, error) {
+		h, err := cipher.SHA256FromHex(txid)
+		if err != nil {
+			return nil, err
+		}
+
+		txn, inputs, err := gateway.GetTransactionWithInputs(h)
+		if err != nil || txn == nil {
+			return nil, err
+		}
+
+		return newGQLTransaction(txn, inputs)
+	}
+
+	// The types reference each other, so the fields referencing a type that is not defined yet are added after
+	var blockType, transactionType, inputType, outputType, balanceType, addressType *graphql.Object
+
+	blockType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Block",
+		Description: "A block of the blockchain",
+		Fields: graphql.Fields{
+			"seq": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.BkSeq, nil
+				},
+			},
+			"hash": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.Hash, nil
+				},
+			},
+			"previousHash": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.PreviousHash, nil
+				},
+			},
+			"time": &graphql.Field{
+				Type:        graphql.NewNonNull(uint64Type),
+				Description: "Unix time of the block",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.Time, nil
+				},
+			},
+			"fee": &graphql.Field{
+				Type:        graphql.NewNonNull(uint64Type),
+				Description: "Coin hours burned by the transactions of the block",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.Fee, nil
+				},
+			},
+			"version": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.Version, nil
+				},
+			},
+			"bodyHash": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.BodyHash, nil
+				},
+			},
+			"uxHash": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Head.UxHash, nil
+				},
+			},
+			"size": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlBlock).Size, nil
+				},
+			},
+		},
+	})
+
+	transactionType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Transaction",
+		Description: "A confirmed or unconfirmed transaction",
+		Fields: graphql.Fields{
+			"txid": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).Hash, nil
+				},
+			},
+			"innerHash": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).InnerHash, nil
+				},
+			},
+			"type": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return int(p.Source.(*gqlTransaction).Type), nil
+				},
+			},
+			"length": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).Length, nil
+				},
+			},
+			"timestamp": &graphql.Field{
+				Type:        uint64Type,
+				Description: "Unix time of the block of the transaction, null if the transaction is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					txn := p.Source.(*gqlTransaction)
+					if !txn.Status.Confirmed {
+						return nil, nil
+					}
+					return txn.Timestamp, nil
+				},
+			},
+			"confirmed": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).Status.Confirmed, nil
+				},
+			},
+			"height": &graphql.Field{
+				Type:        uint64Type,
+				Description: "Number of blocks the transaction is deep in the chain, null if the transaction is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					txn := p.Source.(*gqlTransaction)
+					if !txn.Status.Confirmed {
+						return nil, nil
+					}
+					return txn.Status.Height, nil
+				},
+			},
+			"blockSeq": &graphql.Field{
+				Type:        uint64Type,
+				Description: "Sequence of the block of the transaction, null if the transaction is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					txn := p.Source.(*gqlTransaction)
+					if !txn.Status.Confirmed {
+						return nil, nil
+					}
+					return txn.Status.BlockSeq, nil
+				},
+			},
+			"block": &graphql.Field{
+				Type:        blockType,
+				Description: "Block of the transaction, null if the transaction is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					txn := p.Source.(*gqlTransaction)
+					if !txn.Status.Confirmed {
+						return nil, nil
+					}
+					return getBlockBySeq(txn.Status.BlockSeq)
+				},
+			},
+			"fee": &graphql.Field{
+				Type:        graphql.NewNonNull(uint64Type),
+				Description: "Coin hours burned by the transaction",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).Fee, nil
+				},
+			},
+			"signatures": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlTransaction).Sigs, nil
+				},
+			},
+		},
+	})
+
+	inputType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Input",
+		Description: "An input of a transaction, the output it spends",
+		Fields: graphql.Fields{
+			"uxid": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.TransactionInput).Hash, nil
+				},
+			},
+			"address": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.TransactionInput).Address, nil
+				},
+			},
+			"coins": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.TransactionInput).Coins, nil
+				},
+			},
+			"hours": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.TransactionInput).Hours, nil
+				},
+			},
+			"calculatedHours": &graphql.Field{
+				Type:        graphql.NewNonNull(uint64Type),
+				Description: "Coin hours of the input when it was spent, or now if the transaction is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.TransactionInput).CalculatedHours, nil
+				},
+			},
+		},
+	})
+
+	outputType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Output",
+		Description: "An output of a transaction",
+		Fields: graphql.Fields{
+			"uxid": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlOutput).Hash, nil
+				},
+			},
+			"address": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlOutput).Address, nil
+				},
+			},
+			"coins": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlOutput).Coins, nil
+				},
+			},
+			"hours": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlOutput).Hours, nil
+				},
+			},
+			"sourceTxid": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Transaction that created the output",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*gqlOutput).SrcTxid, nil
+				},
+			},
+			"sourceTransaction": &graphql.Field{
+				Type:        transactionType,
+				Description: "Transaction that created the output",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return getTransaction(p.Source.(*gqlOutput).SrcTxid)
+				},
+			},
+			"blockSeq": &graphql.Field{
+				Type:        uint64Type,
+				Description: "Sequence of the block that created the output, null if the output is unconfirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					h, err := p.Source.(*gqlOutput).getHistory(gateway)
+					if err != nil || h == nil {
+						return nil, err
+					}
+					return h.SrcBkSeq, nil
+				},
+			},
+			"spent": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					h, err := p.Source.(*gqlOutput).getHistory(gateway)
+					if err != nil {
+						return nil, err
+					}
+					return isSpentOutput(h), nil
+				},
+			},
+			"spentTxid": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Transaction that spent the output, null if the output is not spent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					h, err := p.Source.(*gqlOutput).getHistory(gateway)
+					if err != nil || !isSpentOutput(h) {
+						return nil, err
+					}
+					return h.SpentTxnID, nil
+				},
+			},
+			"spentBlockSeq": &graphql.Field{
+				Type:        uint64Type,
+				Description: "Sequence of the block that spent the output, null if the output is not spent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					h, err := p.Source.(*gqlOutput).getHistory(gateway)
+					if err != nil || !isSpentOutput(h) {
+						return nil, err
+					}
+					return h.SpentBlockSeq, nil
+				},
+			},
+		},
+	})
+
+	balanceType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Balance",
+		Fields: graphql.Fields{
+			"coins": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return droplet.ToString(p.Source.(readable.Balance).Coins)
+				},
+			},
+			"hours": &graphql.Field{
+				Type: graphql.NewNonNull(uint64Type),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(readable.Balance).Hours, nil
+				},
+			},
+		},
+	})
+
+	getBalance := func(addr cipher.Address) (*readable.BalancePair, error) {
+		bals, err := gateway.GetBalanceOfAddresses([]cipher.Address{addr})
+		if err != nil {
+			return nil, err
+		}
+		if len(bals) != 1 {
+			return nil, errors.New("GetBalanceOfAddresses returned an unexpected number of balances")
+		}
+
+		bp := readable.NewBalancePair(bals[0])
+		return &bp, nil
+	}
+
+	addressType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Address",
+		Fields: graphql.Fields{
+			"address": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(gqlAddress).String(), nil
+				},
+			},
+			"balance": &graphql.Field{
+				Type:        graphql.NewNonNull(balanceType),
+				Description: "Balance of the confirmed outputs of the address",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bp, err := getBalance(p.Source.(gqlAddress).Address)
+					if err != nil {
+						return nil, err
+					}
+					return bp.Confirmed, nil
+				},
+			},
+			"predictedBalance": &graphql.Field{
+				Type:        graphql.NewNonNull(balanceType),
+				Description: "Balance of the address once the unconfirmed transactions are confirmed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bp, err := getBalance(p.Source.(gqlAddress).Address)
+					if err != nil {
+						return nil, err
+					}
+					return bp.Predicted, nil
+				},
+			},
+			"outputs": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(outputType))),
+				Description: "Confirmed unspent outputs of the address",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					summary, err := gateway.GetUnspentOutputsSummary(p.Context, []visor.OutputsFilter{
+						visor.FbyAddresses([]cipher.Address{p.Source.(gqlAddress).Address}),
+					})
+					if err != nil {
+						return nil, err
+					}
+
+					outs := make([]*gqlOutput, len(summary.Confirmed))
+					for i, o := range summary.Confirmed {
+						coins, err := droplet.ToString(o.Body.Coins)
+						if err != nil {
+							return nil, err
+						}
+
+						outs[i] = &gqlOutput{
+							TransactionOutput: readable.TransactionOutput{
+								Hash:    o.Hash().Hex(),
+								Address: o.Body.Address.String(),
+								Coins:   coins,
+								Hours:   o.Body.Hours,
+							},
+							SrcTxid: o.Body.SrcTransaction.Hex(),
+						}
+					}
+					return outs, nil
+				},
+			},
+			"transactions": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(transactionType))),
+				Description: "Transactions of the address, the most recent first",
+				Args: graphql.FieldConfigArgument{
+					"confirmed": &graphql.ArgumentConfig{
+						Type:        graphql.Boolean,
+						Description: "Only the confirmed transactions if true, only the unconfirmed transactions if false",
+					},
+					"page": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: 1,
+					},
+					"limit": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: defaultGraphQLTransactionsLimit,
+						Description:  fmt.Sprintf("Number of transactions per page, at most %d", maxGraphQLTransactionsLimit),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, _ := p.Args["page"].(int)
+					limit, _ := p.Args["limit"].(int)
+					if page < 1 {
+						return nil, errors.New("page must be at least 1")
+					}
+					if limit < 1 || limit > maxGraphQLTransactionsLimit {
+						return nil, fmt.Errorf("limit must be between 1 and %d", maxGraphQLTransactionsLimit)
+					}
+
+					pageIndex, err := visor.NewPageIndex(uint64(limit), uint64(page))
+					if err != nil {
+						return nil, err
+					}
+
+					flts := []visor.TxFilter{visor.NewAddrsFilter([]cipher.Address{p.Source.(gqlAddress).Address})}
+					if confirmed, ok := p.Args["confirmed"].(bool); ok {
+						flts = append(flts, visor.NewConfirmedTxFilter(confirmed))
+					}
+
+					txns, inputs, _, err := gateway.GetTransactionsWithInputs(p.Context, flts, visor.DescOrder, pageIndex)
+					if err != nil {
+						return nil, err
+					}
+
+					gTxns := make([]*gqlTransaction, len(txns))
+					for i := range txns {
+						gTxns[i], err = newGQLTransaction(&txns[i], inputs[i])
+						if err != nil {
+							return nil, err
+						}
+					}
+					return gTxns, nil
+				},
+			},
+		},
+	})
+
+	blockType.AddFieldConfig("transactions", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(transactionType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			b := p.Source.(*gqlBlock)
+			txns := make([]*gqlTransaction, len(b.Body.Transactions))
+			for i, txn := range b.Body.Transactions {
+				txns[i] = newGQLBlockTransaction(b, txn)
+			}
+			return txns, nil
+		},
+	})
+
+	transactionType.AddFieldConfig("inputs", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(inputType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*gqlTransaction).In, nil
+		},
+	})
+
+	transactionType.AddFieldConfig("outputs", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(outputType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			txn := p.Source.(*gqlTransaction)
+			outs := make([]*gqlOutput, len(txn.Out))
+			for i, o := range txn.Out {
+				outs[i] = &gqlOutput{
+					TransactionOutput: o,
+					SrcTxid:           txn.Hash,
+				}
+			}
+			return outs, nil
+		},
+	})
+
+	inputType.AddFieldConfig("sourceOutput", &graphql.Field{
+		Type:        graphql.NewNonNull(outputType),
+		Description: "Output spent by the input",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			in := p.Source.(readable.TransactionInput)
+			return &gqlOutput{
+				TransactionOutput: readable.TransactionOutput{
+					Hash:    in.Hash,
+					Address: in.Address,
+					Coins:   in.Coins,
+					Hours:   in.Hours,
+				},
+				SrcTxid: in.SrcTxid,
+			}, nil
+		},
+	})
+
+	outputType.AddFieldConfig("spentTransaction", &graphql.Field{
+		Type:        transactionType,
+		Description: "Transaction that spent the output, null if the output is not spent",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			h, err := p.Source.(*gqlOutput).getHistory(gateway)
+			if err != nil || !isSpentOutput(h) {
+				return nil, err
+			}
+			return getTransaction(h.SpentTxnID)
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"block": &graphql.Field{
+				Type:        blockType,
+				Description: "Block of a sequence or a hash, null if there is no such block",
+				Args: graphql.FieldConfigArgument{
+					"seq": &graphql.ArgumentConfig{
+						Type: uint64Type,
+					},
+					"hash": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					seq, hasSeq := p.Args["seq"].(uint64)
+					hash, hasHash := p.Args["hash"].(string)
+					switch {
+					case hasSeq && hasHash:
+						return nil, errors.New("seq and hash cannot be combined")
+					case hasSeq:
+						return getBlockBySeq(seq)
+					case hasHash:
+						h, err := cipher.SHA256FromHex(hash)
+						if err != nil {
+							return nil, err
+						}
+
+						b, inputs, err := gateway.GetSignedBlockByHashVerbose(h)
+						if err != nil || b == nil {
+							return nil, err
+						}
+
+						headSeq, _, err := gateway.HeadBkSeq()
+						if err != nil {
+							return nil, err
+						}
+
+						rb, err := readable.NewBlockVerbose(b.Block, inputs)
+						if err != nil {
+							return nil, err
+						}
+
+						return newGQLBlock(rb, headSeq), nil
+					default:
+						return nil, errors.New("seq or hash is required")
+					}
+				},
+			},
+			"blocks": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(blockType))),
+				Description: fmt.Sprintf("Blocks between start and end included, or the last blocks. At most %d blocks are returned.", maxGraphQLBlocks),
+				Args: graphql.FieldConfigArgument{
+					"start": &graphql.ArgumentConfig{
+						Type: uint64Type,
+					},
+					"end": &graphql.ArgumentConfig{
+						Type: uint64Type,
+					},
+					"last": &graphql.ArgumentConfig{
+						Type: uint64Type,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					start, hasStart := p.Args["start"].(uint64)
+					end, hasEnd := p.Args["end"].(uint64)
+					last, hasLast := p.Args["last"].(uint64)
+
+					var blocks []coin.SignedBlock
+					var inputs [][][]visor.TransactionInput
+					var err error
+					switch {
+					case hasLast && (hasStart || hasEnd):
+						return nil, errors.New("last cannot be combined with start or end")
+					case hasLast:
+						if last > maxGraphQLBlocks {
+							return nil, fmt.Errorf("last must be at most %d", maxGraphQLBlocks)
+						}
+						blocks, inputs, err = gateway.GetLastBlocksVerbose(last)
+					case hasStart && hasEnd:
+						if end < start {
+							return nil, errors.New("end must not be less than start")
+						}
+						if end-start >= maxGraphQLBlocks {
+							return nil, fmt.Errorf("at most %d blocks can be queried", maxGraphQLBlocks)
+						}
+						blocks, inputs, err = gateway.GetBlocksInRangeVerbose(p.Context, start, end)
+					default:
+						return nil, errors.New("start and end, or last are required")
+					}
+					if err != nil {
+						return nil, err
+					}
+
+					rbs, err := readable.NewBlocksVerbose(blocks, inputs)
+					if err != nil {
+						return nil, err
+					}
+
+					headSeq, _, err := gateway.HeadBkSeq()
+					if err != nil {
+						return nil, err
+					}
+
+					gBlocks := make([]*gqlBlock, len(rbs.Blocks))
+					for i := range rbs.Blocks {
+						gBlocks[i] = newGQLBlock(&rbs.Blocks[i], headSeq)
+					}
+					return gBlocks, nil
+				},
+			},
+			"transaction": &graphql.Field{
+				Type:        transactionType,
+				Description: "Confirmed or unconfirmed transaction, null if there is no such transaction",
+				Args: graphql.FieldConfigArgument{
+					"txid": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return getTransaction(p.Args["txid"].(string))
+				},
+			},
+			"output": &graphql.Field{
+				Type:        outputType,
+				Description: "Spent or unspent output, null if there is no such output in a block",
+				Args: graphql.FieldConfigArgument{
+					"uxid": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					o := &gqlOutput{
+						TransactionOutput: readable.TransactionOutput{
+							Hash: p.Args["uxid"].(string),
+						},
+					}
+
+					h, err := o.getHistory(gateway)
+					if err != nil || h == nil {
+						return nil, err
+					}
+
+					coins, err := droplet.ToString(h.Coins)
+					if err != nil {
+						return nil, err
+					}
+
+					o.Address = h.OwnerAddress
+					o.Coins = coins
+					o.Hours = h.Hours
+					o.SrcTxid = h.SrcTx
+					return o, nil
+				},
+			},
+			"address": &graphql.Field{
+				Type: addressType,
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					addr, err := cipher.DecodeBase58Address(p.Args["address"].(string))
+					if err != nil {
+						return nil, err
+					}
+					return gqlAddress{addr}, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: queryType,
+	})
+}
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/historydb"
+)
+
+func TestGraphQLQueryDepth(t *testing.T) {
+	tt := []struct {
+		name  string
+		query string
+		depth int
+	}{
+		{
+			name:  "flat",
+			query: "{ block(seq: 1) { seq } }",
+			depth: 2,
+		},
+		{
+			name:  "nested",
+			query: "{ block(seq: 1) { transactions { inputs { sourceOutput { uxid } } } } }",
+			depth: 5,
+		},
+		{
+			name:  "deepest operation",
+			query: "query a { block(seq: 1) { seq } } query b { transaction(txid: \"\") { block { transactions { txid } } } }",
+			depth: 4,
+		},
+		{
+			name:  "fragments",
+			query: "{ block(seq: 1) { ...b } } fragment b on Block { transactions { ... on Transaction { inputs { uxid } } } }",
+			depth: 4,
+		},
+		{
+			name:  "fragment cycle",
+			query: "{ block(seq: 1) { ...a } } fragment a on Block { transactions { block { ...a } } }",
+			depth: 3,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tc.query})
+			require.NoError(t, err)
+			require.Equal(t, tc.depth, graphqlQueryDepth(doc))
+		})
+	}
+}
+
+func TestGraphQLQueryCost(t *testing.T) {
+	schema, err := newGraphQLSchema(&MockGatewayer{})
+	require.NoError(t, err)
+
+	tt := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		cost      uint64
+	}{
+		{
+			name:  "flat",
+			query: "{ block(seq: 1) { seq } }",
+			cost:  2,
+		},
+		{
+			name:  "aliases",
+			query: "{ a: block(seq: 1) { seq } b: block(seq: 2) { seq hash } }",
+			cost:  5,
+		},
+		{
+			name:  "last blocks",
+			query: "{ blocks(last: 5) { seq transactions { txid } } }",
+			cost:  61,
+		},
+		{
+			name:      "blocks range variables",
+			query:     "query ($start: Uint64, $end: Uint64) { blocks(start: $start, end: $end) { seq } }",
+			variables: map[string]interface{}{"start": float64(10), "end": "19"},
+			cost:      11,
+		},
+		{
+			name:  "blocks without arguments",
+			query: "{ blocks { seq } }",
+			cost:  101,
+		},
+		{
+			name:  "address transactions limit",
+			query: `{ address(address: "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv") { transactions(limit: 500) { txid } } }`,
+			cost:  102,
+		},
+		{
+			name:  "fragments",
+			query: "{ block(seq: 1) { ...b } } fragment b on Block { transactions { ... on Transaction { inputs { uxid } } } }",
+			cost:  112,
+		},
+		{
+			name:  "fragment cycle",
+			query: "{ block(seq: 1) { ...a } } fragment a on Block { transactions { block { ...a } } }",
+			cost:  12,
+		},
+		{
+			name:  "most expensive operation",
+			query: "query a { block(seq: 1) { seq } } query b { blocks(last: 2) { seq } }",
+			cost:  3,
+		},
+		{
+			name:  "introspection",
+			query: "{ __schema { types { name } } }",
+			cost:  3,
+		},
+		{
+			name:  "nested lists",
+			query: "{ blocks(last: 100) { transactions { inputs { sourceOutput { sourceTransaction { block { transactions { txid } } } } } } } }",
+			cost:  1 + 100 + 1000 + 4*10000 + 100000,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tc.query})
+			require.NoError(t, err)
+			require.Equal(t, tc.cost, graphqlQueryCost(schema, doc, tc.variables))
+		})
+	}
+}
+
+func TestNewGraphQLSchema(t *testing.T) {
+	_, err := newGraphQLSchema(&MockGatewayer{})
+	require.NoError(t, err)
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	signed := prepareTxnAndInputs(t)
+	txid := signed.txn.Hash()
+	inputUxid := signed.txn.In[0]
+	spender := testutil.RandSHA256(t)
+
+	block := coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				BkSeq: 10,
+				Time:  1539849621,
+			},
+			Body: coin.BlockBody{
+				Transactions: coin.Transactions{signed.txn},
+			},
+		},
+	}
+
+	nestedQuery := `{
+		block(seq: 10) {
+			seq
+			time
+			transactions {
+				txid
+				height
+				inputs {
+					uxid
+					sourceOutput {
+						uxid
+						sourceTxid
+						spent
+						spentTxid
+						spentBlockSeq
+					}
+				}
+				outputs {
+					uxid
+					spent
+					spentTxid
+				}
+			}
+		}
+	}`
+
+	type nestedResponse struct {
+		Block struct {
+			Seq          uint64 `json:"seq"`
+			Time         uint64 `json:"time"`
+			Transactions []struct {
+				Txid   string `json:"txid"`
+				Height uint64 `json:"height"`
+				Inputs []struct {
+					Uxid         string `json:"uxid"`
+					SourceOutput struct {
+						Uxid          string  `json:"uxid"`
+						SourceTxid    string  `json:"sourceTxid"`
+						Spent         bool    `json:"spent"`
+						SpentTxid     *string `json:"spentTxid"`
+						SpentBlockSeq *uint64 `json:"spentBlockSeq"`
+					} `json:"sourceOutput"`
+				} `json:"inputs"`
+				Outputs []struct {
+					Uxid      string  `json:"uxid"`
+					Spent     bool    `json:"spent"`
+					SpentTxid *string `json:"spentTxid"`
+				} `json:"outputs"`
+			} `json:"transactions"`
+		} `json:"block"`
+	}
+
+	tt := []struct {
+		name      string
+		method    string
+		body      string
+		query     url.Values
+		status    int
+		errMsg    string
+		gqlErrors []GraphQLResponseError
+		verify    func(t *testing.T, data json.RawMessage)
+	}{
+		{
+			name:   "405",
+			method: http.MethodDelete,
+			status: http.StatusMethodNotAllowed,
+			errMsg: "Method Not Allowed",
+		},
+		{
+			name:   "400 - invalid json",
+			method: http.MethodPost,
+			body:   "{",
+			status: http.StatusBadRequest,
+			errMsg: "unexpected EOF",
+		},
+		{
+			name:   "400 - missing query",
+			method: http.MethodPost,
+			body:   `{}`,
+			status: http.StatusBadRequest,
+			errMsg: "query is required",
+		},
+		{
+			name:   "400 - invalid variables",
+			method: http.MethodGet,
+			query: url.Values{
+				"query":     []string{"{ block(seq: 1) { seq } }"},
+				"variables": []string{"foo"},
+			},
+			status: http.StatusBadRequest,
+			errMsg: "invalid variables: invalid character 'o' in literal false (expecting 'a')",
+		},
+		{
+			name:   "400 - query too deep",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: "{ block(seq: 1) { transactions { block { transactions { block { transactions { block { transactions { block { transactions { txid } } } } } } } } } } }"}),
+			status: http.StatusBadRequest,
+			errMsg: "query depth 11 exceeds the maximum depth 10",
+		},
+		{
+			name:   "400 - query too expensive",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: "{ blocks(last: 100) { transactions { inputs { sourceOutput { sourceTransaction { block { transactions { txid } } } } } } } }"}),
+			status: http.StatusBadRequest,
+			errMsg: "query cost 141101 exceeds the maximum cost 10000",
+		},
+		{
+			name:   "400 - too many aliases",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: "{" + graphqlAliases(5001, "block(seq: 1) { seq }") + " }"}),
+			status: http.StatusBadRequest,
+			errMsg: "query cost 10002 exceeds the maximum cost 10000",
+		},
+		{
+			name:   "200 - syntax error",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: "{ block(seq: 1) { seq }"}),
+			status: http.StatusOK,
+			gqlErrors: []GraphQLResponseError{
+				{
+					Message: "Syntax Error GraphQL request (1:24) Expected Name, found EOF\n\n1: { block(seq: 1) { seq }\n                          ^\n",
+				},
+			},
+		},
+		{
+			name:   "200 - field error",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: "{ blocks(last: 101) { seq } }"}),
+			status: http.StatusOK,
+			gqlErrors: []GraphQLResponseError{
+				{
+					Message: "last must be at most 100",
+					Path:    []interface{}{"blocks"},
+				},
+			},
+		},
+		{
+			name:   "200 - not found",
+			method: http.MethodGet,
+			query: url.Values{
+				"query":     []string{"query ($txid: String!) { transaction(txid: $txid) { txid } }"},
+				"variables": []string{`{"txid": "` + spender.Hex() + `"}`},
+			},
+			status: http.StatusOK,
+			verify: func(t *testing.T, data json.RawMessage) {
+				require.JSONEq(t, `{"transaction": null}`, string(data))
+			},
+		},
+		{
+			name:   "200 - nested block",
+			method: http.MethodPost,
+			body:   toJSON(t, GraphQLRequest{Query: nestedQuery}),
+			status: http.StatusOK,
+			verify: func(t *testing.T, data json.RawMessage) {
+				var resp nestedResponse
+				require.NoError(t, json.Unmarshal(data, &resp))
+
+				require.Equal(t, uint64(10), resp.Block.Seq)
+				require.Equal(t, uint64(1539849621), resp.Block.Time)
+				require.Len(t, resp.Block.Transactions, 1)
+
+				txn := resp.Block.Transactions[0]
+				require.Equal(t, txid.Hex(), txn.Txid)
+				require.Equal(t, uint64(3), txn.Height)
+
+				require.Len(t, txn.Inputs, 1)
+				in := txn.Inputs[0]
+				require.Equal(t, inputUxid.Hex(), in.Uxid)
+				require.Equal(t, inputUxid.Hex(), in.SourceOutput.Uxid)
+				require.Equal(t, signed.inputs[0].UxOut.Body.SrcTransaction.Hex(), in.SourceOutput.SourceTxid)
+				require.True(t, in.SourceOutput.Spent)
+				require.Equal(t, spender.Hex(), *in.SourceOutput.SpentTxid)
+				require.Equal(t, uint64(11), *in.SourceOutput.SpentBlockSeq)
+
+				require.Len(t, txn.Outputs, 2)
+				for i, o := range txn.Outputs {
+					require.Equal(t, signed.txn.Out[i].UxID(txid).Hex(), o.Uxid)
+					require.False(t, o.Spent)
+					require.Nil(t, o.SpentTxid)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetSignedBlockBySeqVerbose", uint64(10)).Return(&block, [][]visor.TransactionInput{signed.inputs}, nil)
+			gateway.On("HeadBkSeq").Return(uint64(12), true, nil)
+			gateway.On("GetUxOutByID", inputUxid).Return(&historydb.UxOut{
+				Out:           signed.inputs[0].UxOut,
+				SpentTxnID:    spender,
+				SpentBlockSeq: 11,
+			}, uint64(1539849621), nil)
+			gateway.On("GetUxOutByID", mock.Anything).Return(nil, uint64(0), nil)
+			gateway.On("GetTransactionWithInputs", spender).Return(nil, nil, nil)
+
+			endpoint := "/api/v2/graphql"
+			if tc.query != nil {
+				endpoint += "?" + tc.query.Encode()
+			}
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			if tc.status != http.StatusOK {
+				expected, err := json.MarshalIndent(NewHTTPErrorResponse(tc.status, tc.errMsg), "", "    ")
+				require.NoError(t, err)
+				require.Equal(t, string(expected), rr.Body.String())
+				return
+			}
+
+			var resp struct {
+				Data   json.RawMessage        `json:"data"`
+				Errors []GraphQLResponseError `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.gqlErrors, resp.Errors)
+
+			if tc.verify != nil {
+				tc.verify(t, resp.Data)
+			}
+		})
+	}
+}
+
+// graphqlAliases returns n aliases of a field selection
+func graphqlAliases(n int, selection string) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, " a%d: %s", i, selection)
+	}
+	return b.String()
+}
@@ -0,0 +1,667 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/skycoin/skycoin/src/api/grpcpb"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// GRPCConfig configures the gRPC server
+type GRPCConfig struct {
+	// EnabledAPISets are the API sets of the REST API, they enable the gRPC methods of the same sets
+	EnabledAPISets map[string]struct{}
+	// Username and Password are the credentials of the basic authorization metadata, required if either is set
+	Username string
+	Password string
+}
+
+// GRPCServer exposes the blockchain, transaction and wallet services of the gateway over gRPC,
+// see grpcpb/skycoin.proto for the definitions
+type GRPCServer struct {
+	listener net.Listener
+	server   *grpc.Server
+}
+
+// CreateGRPC creates a gRPC server listening on addr
+func CreateGRPC(addr string, c GRPCConfig, gateway Gatewayer) (*GRPCServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	unaryAuth, streamAuth := grpcBasicAuth(c.Username, c.Password)
+	server := grpc.NewServer(grpc.UnaryInterceptor(unaryAuth), grpc.StreamInterceptor(streamAuth))
+
+	s := &grpcServices{
+		gateway:        gateway,
+		enabledAPISets: c.EnabledAPISets,
+	}
+	grpcpb.RegisterBlockchainServiceServer(server, blockchainGRPC{s})
+	grpcpb.RegisterTransactionServiceServer(server, transactionGRPC{s})
+	grpcpb.RegisterWalletServiceServer(server, walletGRPC{s})
+
+	return &GRPCServer{
+		listener: listener,
+		server:   server,
+	}, nil
+}
+
+// Addr returns the listening address of the GRPCServer
+func (s *GRPCServer) Addr() string {
+	if s == nil || s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Serve serves the gRPC services until Shutdown is called
+func (s *GRPCServer) Serve() error {
+	logger.Infof("Starting gRPC interface on %s", s.listener.Addr())
+	defer logger.Info("gRPC interface closed")
+
+	if err := s.server.Serve(s.listener); err != nil && err != grpc.ErrServerStopped {
+		return err
+	}
+	return nil
+}
+
+// Shutdown closes the gRPC server, the open subscription streams are canceled
+func (s *GRPCServer) Shutdown() {
+	if s == nil {
+		return
+	}
+
+	logger.Info("Shutting down gRPC interface")
+	defer logger.Info("gRPC interface shut down")
+	s.server.Stop()
+}
+
+// grpcBasicAuth returns the interceptors that check the basic authorization metadata,
+// with the same rules as the basicAuth middleware of the REST API
+func grpcBasicAuth(username, password string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	needsAuth := username != "" || password != ""
+	usernamePasswordHash := cipher.SumSHA256(append([]byte(username), []byte(password)...))
+
+	check := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		// Reuse the parsing of the Authorization header of net/http
+		r := http.Request{
+			Header: http.Header{
+				"Authorization": md.Get("authorization"),
+			},
+		}
+		user, pass, ok := r.BasicAuth()
+
+		if needsAuth {
+			userPassHash := cipher.SumSHA256(append([]byte(user), []byte(pass)...))
+			if !ok || subtle.ConstantTimeCompare(userPassHash[:], usernamePasswordHash[:]) != 1 {
+				return status.Error(codes.Unauthenticated, "invalid credentials")
+			}
+		} else if user != "" || pass != "" {
+			// Reject credentials if auth is not configured, as the REST API does
+			return status.Error(codes.Unauthenticated, "authentication is not configured")
+		}
+
+		return nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+// grpcError maps a gateway error to a gRPC status error
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	switch err {
+	case wallet.ErrWalletNotExist:
+		return status.Error(codes.NotFound, err.Error())
+	case wallet.ErrWalletAPIDisabled:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	switch err.(type) {
+	case wallet.Error,
+		visor.ErrTxnViolatesUserConstraint,
+		visor.ErrTxnViolatesHardConstraint,
+		visor.ErrTxnViolatesSoftConstraint:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if daemon.IsBroadcastFailure(err) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// grpcServices holds the state shared by the gRPC services
+type grpcServices struct {
+	gateway        Gatewayer
+	enabledAPISets map[string]struct{}
+}
+
+// requireAPISet returns a PermissionDenied error if the API set is disabled
+func (s *grpcServices) requireAPISet(apiSet string) error {
+	if _, ok := s.enabledAPISets[apiSet]; !ok {
+		return status.Error(codes.PermissionDenied, "Endpoint is disabled")
+	}
+	return nil
+}
+
+func parseGRPCAddresses(addrs []string) ([]cipher.Address, error) {
+	parsed := make([]cipher.Address, len(addrs))
+	for i, a := range addrs {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid address %q: %v", a, err)
+		}
+		parsed[i] = addr
+	}
+	return parsed, nil
+}
+
+func newGRPCBlockHeader(b coin.Block) *grpcpb.BlockHeader {
+	return &grpcpb.BlockHeader{
+		Version:  b.Head.Version,
+		Time:     b.Head.Time,
+		Seq:      b.Head.BkSeq,
+		Fee:      b.Head.Fee,
+		Hash:     b.HashHeader().Hex(),
+		PrevHash: b.Head.PrevHash.Hex(),
+		BodyHash: b.Head.BodyHash.Hex(),
+		UxHash:   b.Head.UxHash.Hex(),
+	}
+}
+
+func newGRPCBlock(b coin.Block) (*grpcpb.Block, error) {
+	txns := make([]*grpcpb.Transaction, len(b.Body.Transactions))
+	for i := range b.Body.Transactions {
+		txn, err := newGRPCTransaction(&b.Body.Transactions[i])
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = txn
+	}
+
+	return &grpcpb.Block{
+		Header:       newGRPCBlockHeader(b),
+		Transactions: txns,
+	}, nil
+}
+
+func newGRPCTransaction(txn *coin.Transaction) (*grpcpb.Transaction, error) {
+	raw, err := txn.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	txid := txn.Hash()
+
+	sigs := make([]string, len(txn.Sigs))
+	for i, s := range txn.Sigs {
+		sigs[i] = s.Hex()
+	}
+
+	inputs := make([]string, len(txn.In))
+	for i, in := range txn.In {
+		inputs[i] = in.Hex()
+	}
+
+	outputs := make([]*grpcpb.TransactionOutput, len(txn.Out))
+	for i, o := range txn.Out {
+		outputs[i] = &grpcpb.TransactionOutput{
+			Uxid:    o.UxID(txid).Hex(),
+			Address: o.Address.String(),
+			Coins:   o.Coins,
+			Hours:   o.Hours,
+		}
+	}
+
+	return &grpcpb.Transaction{
+		Hash:       txid.Hex(),
+		InnerHash:  txn.InnerHash.Hex(),
+		Length:     txn.Length,
+		Type:       uint32(txn.Type),
+		Signatures: sigs,
+		Inputs:     inputs,
+		Outputs:    outputs,
+		Raw:        raw,
+	}, nil
+}
+
+func newGRPCTransactionWithStatus(txn *visor.Transaction) (*grpcpb.Transaction, error) {
+	t, err := newGRPCTransaction(&txn.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Status = &grpcpb.TransactionStatus{
+		Confirmed: txn.Status.Confirmed,
+		Height:    txn.Status.Height,
+		BlockSeq:  txn.Status.BlockSeq,
+	}
+	t.Time = txn.Time
+
+	return t, nil
+}
+
+func newGRPCBalancePair(b wallet.BalancePair) *grpcpb.BalancePair {
+	return &grpcpb.BalancePair{
+		Confirmed: &grpcpb.Balance{
+			Coins: b.Confirmed.Coins,
+			Hours: b.Confirmed.Hours,
+		},
+		Predicted: &grpcpb.Balance{
+			Coins: b.Predicted.Coins,
+			Hours: b.Predicted.Hours,
+		},
+	}
+}
+
+// blockchainGRPC implements grpcpb.BlockchainServiceServer
+type blockchainGRPC struct {
+	*grpcServices
+}
+
+// GetMetadata returns the head block header and the unspent and unconfirmed counts
+func (s blockchainGRPC) GetMetadata(ctx context.Context, req *grpcpb.GetMetadataRequest) (*grpcpb.BlockchainMetadata, error) {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return nil, err
+	}
+
+	m, err := s.gateway.GetBlockchainMetadata()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if m == nil {
+		return nil, status.Error(codes.Internal, "blockchain metadata is nil")
+	}
+
+	return &grpcpb.BlockchainMetadata{
+		Head:        newGRPCBlockHeader(m.HeadBlock.Block),
+		Unspents:    m.Unspents,
+		Unconfirmed: m.Unconfirmed,
+	}, nil
+}
+
+// GetBlock returns a block by hash or seq
+func (s blockchainGRPC) GetBlock(ctx context.Context, req *grpcpb.GetBlockRequest) (*grpcpb.Block, error) {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return nil, err
+	}
+
+	var b *coin.SignedBlock
+	switch v := req.Block.(type) {
+	case *grpcpb.GetBlockRequest_Hash:
+		h, err := cipher.SHA256FromHex(v.Hash)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid hash: %v", err)
+		}
+
+		b, err = s.gateway.GetSignedBlockByHash(h)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+	case *grpcpb.GetBlockRequest_Seq:
+		var err error
+		b, err = s.gateway.GetSignedBlockBySeq(v.Seq)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "hash or seq is required")
+	}
+
+	if b == nil {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+
+	block, err := newGRPCBlock(b.Block)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return block, nil
+}
+
+// GetBalance returns the balance of addresses
+func (s blockchainGRPC) GetBalance(ctx context.Context, req *grpcpb.GetBalanceRequest) (*grpcpb.GetBalanceResponse, error) {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return nil, err
+	}
+
+	if len(req.Addresses) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "addresses are required")
+	}
+
+	addrs, err := parseGRPCAddresses(req.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.gateway.GetBalanceOfAddresses(addrs)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	var total wallet.BalancePair
+	resp := &grpcpb.GetBalanceResponse{
+		Addresses: make(map[string]*grpcpb.BalancePair, len(addrs)),
+	}
+	for i, a := range addrs {
+		resp.Addresses[a.String()] = newGRPCBalancePair(balances[i])
+
+		total.Confirmed, err = total.Confirmed.Add(balances[i].Confirmed)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+
+		total.Predicted, err = total.Predicted.Add(balances[i].Predicted)
+		if err != nil {
+			return nil, grpcError(err)
+		}
+	}
+	resp.Balance = newGRPCBalancePair(total)
+
+	return resp, nil
+}
+
+// SubscribeBlocks streams the blocks added to the blockchain
+func (s blockchainGRPC) SubscribeBlocks(req *grpcpb.SubscribeBlocksRequest, stream grpcpb.BlockchainService_SubscribeBlocksServer) error {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return err
+	}
+
+	events, unsubscribe := s.gateway.SubscribeBlockchain(0)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if e.Type != visor.EventBlockExecuted {
+				continue
+			}
+
+			b, err := newGRPCBlock(e.Block.Block)
+			if err != nil {
+				return grpcError(err)
+			}
+			if err := stream.Send(b); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// transactionGRPC implements grpcpb.TransactionServiceServer
+type transactionGRPC struct {
+	*grpcServices
+}
+
+// GetTransaction returns a confirmed or unconfirmed transaction by its hash
+func (s transactionGRPC) GetTransaction(ctx context.Context, req *grpcpb.GetTransactionRequest) (*grpcpb.Transaction, error) {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return nil, err
+	}
+
+	h, err := cipher.SHA256FromHex(req.Txid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid txid: %v", err)
+	}
+
+	txn, err := s.gateway.GetTransaction(h)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if txn == nil {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+
+	t, err := newGRPCTransactionWithStatus(txn)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return t, nil
+}
+
+// InjectTransaction adds a signed transaction to the unconfirmed pool and broadcasts it
+func (s transactionGRPC) InjectTransaction(ctx context.Context, req *grpcpb.InjectTransactionRequest) (*grpcpb.InjectTransactionResponse, error) {
+	if err := s.requireAPISet(EndpointsTransaction); err != nil {
+		return nil, err
+	}
+
+	if len(req.Raw) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "raw is required")
+	}
+
+	txn, err := coin.DeserializeTransaction(req.Raw)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.gateway.InjectBroadcastTransaction(txn); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &grpcpb.InjectTransactionResponse{
+		Txid: txn.Hash().Hex(),
+	}, nil
+}
+
+// SubscribeTransactions streams the transactions added to the unconfirmed pool and confirmed in blocks
+func (s transactionGRPC) SubscribeTransactions(req *grpcpb.SubscribeTransactionsRequest, stream grpcpb.TransactionService_SubscribeTransactionsServer) error {
+	if err := s.requireAPISet(EndpointsRead); err != nil {
+		return err
+	}
+
+	addrs, err := parseGRPCAddresses(req.Addresses)
+	if err != nil {
+		return err
+	}
+	addrsMap := make(map[cipher.Address]struct{}, len(addrs))
+	for _, a := range addrs {
+		addrsMap[a] = struct{}{}
+	}
+
+	events, unsubscribe := s.gateway.SubscribeBlockchain(0)
+	defer unsubscribe()
+
+	send := func(txn coin.Transaction) error {
+		vtxn, inputs, err := s.gateway.GetTransactionWithInputs(txn.Hash())
+		if err != nil {
+			return grpcError(err)
+		}
+		if vtxn == nil {
+			// The transaction was removed from the unconfirmed pool in the meantime
+			return nil
+		}
+
+		if len(addrsMap) != 0 && !transactionHasAddress(txn, inputs, addrsMap) {
+			return nil
+		}
+
+		t, err := newGRPCTransactionWithStatus(vtxn)
+		if err != nil {
+			return grpcError(err)
+		}
+		return stream.Send(t)
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			switch e.Type {
+			case visor.EventBlockExecuted:
+				for _, txn := range e.Block.Block.Body.Transactions {
+					if err := send(txn); err != nil {
+						return err
+					}
+				}
+			case visor.EventUnconfirmedTxnInjected:
+				if err := send(*e.Transaction); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// transactionHasAddress returns true if an input or an output of the transaction is one of the addresses
+func transactionHasAddress(txn coin.Transaction, inputs []visor.TransactionInput, addrs map[cipher.Address]struct{}) bool {
+	for _, in := range inputs {
+		if _, ok := addrs[in.UxOut.Body.Address]; ok {
+			return true
+		}
+	}
+	for _, o := range txn.Out {
+		if _, ok := addrs[o.Address]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walletGRPC implements grpcpb.WalletServiceServer
+type walletGRPC struct {
+	*grpcServices
+}
+
+// ListWallets returns the loaded wallets, sorted by id
+func (s walletGRPC) ListWallets(ctx context.Context, req *grpcpb.ListWalletsRequest) (*grpcpb.ListWalletsResponse, error) {
+	if err := s.requireAPISet(EndpointsWallet); err != nil {
+		return nil, err
+	}
+
+	wlts, err := s.gateway.GetWallets()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &grpcpb.ListWalletsResponse{
+		Wallets: make([]*grpcpb.WalletSummary, 0, len(wlts)),
+	}
+	for _, w := range wlts {
+		addrs, err := w.GetAddresses()
+		if err != nil {
+			return nil, grpcError(err)
+		}
+
+		summary := &grpcpb.WalletSummary{
+			Id:        w.Filename(),
+			Label:     w.Label(),
+			Type:      w.Type(),
+			Encrypted: w.IsEncrypted(),
+			Addresses: make([]string, len(addrs)),
+		}
+		for i, a := range addrs {
+			summary.Addresses[i] = a.String()
+		}
+		resp.Wallets = append(resp.Wallets, summary)
+	}
+
+	sort.Slice(resp.Wallets, func(i, j int) bool {
+		return resp.Wallets[i].Id < resp.Wallets[j].Id
+	})
+
+	return resp, nil
+}
+
+// GetWalletBalance returns the balance of a wallet and of its addresses
+func (s walletGRPC) GetWalletBalance(ctx context.Context, req *grpcpb.GetWalletBalanceRequest) (*grpcpb.GetWalletBalanceResponse, error) {
+	if err := s.requireAPISet(EndpointsWallet); err != nil {
+		return nil, err
+	}
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	balance, addrBalances, err := s.gateway.GetWalletBalance(ctx, req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &grpcpb.GetWalletBalanceResponse{
+		Balance:   newGRPCBalancePair(balance),
+		Addresses: make(map[string]*grpcpb.BalancePair, len(addrBalances)),
+	}
+	for a, b := range addrBalances {
+		resp.Addresses[a] = newGRPCBalancePair(b)
+	}
+
+	return resp, nil
+}
+
+// NewAddresses generates new addresses in a wallet
+func (s walletGRPC) NewAddresses(ctx context.Context, req *grpcpb.NewAddressesRequest) (*grpcpb.NewAddressesResponse, error) {
+	if err := s.requireAPISet(EndpointsWallet); err != nil {
+		return nil, err
+	}
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	num := req.Num
+	if num == 0 {
+		num = 1
+	}
+
+	addrs, err := s.gateway.NewAddresses(req.Id, []byte(req.Password), num)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &grpcpb.NewAddressesResponse{
+		Addresses: make([]string, len(addrs)),
+	}
+	for i, a := range addrs {
+		resp.Addresses[i] = a.String()
+	}
+
+	return resp, nil
+}
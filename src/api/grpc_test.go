@@ -0,0 +1,440 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/skycoin/skycoin/src/api/grpcpb"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func startGRPC(t *testing.T, c GRPCConfig, gateway Gatewayer) (*grpc.ClientConn, func()) {
+	s, err := CreateGRPC("127.0.0.1:0", c, gateway)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.Serve()
+		require.NoError(t, err)
+	}()
+
+	conn, err := grpc.Dial(s.Addr(), grpc.WithInsecure())
+	require.NoError(t, err)
+
+	return conn, func() {
+		conn.Close() //nolint:errcheck
+		s.Shutdown()
+		<-done
+	}
+}
+
+func requireGRPCStatus(t *testing.T, err error, code codes.Code, msg string) {
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+	require.Equal(t, msg, st.Message())
+}
+
+func withBasicAuth(ctx context.Context, username, password string) context.Context {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+auth)
+}
+
+func TestGRPCAuth(t *testing.T) {
+	tt := []struct {
+		name     string
+		username string
+		password string
+		ctx      context.Context
+		code     codes.Code
+		err      string
+	}{
+		{
+			name: "no auth",
+			ctx:  context.Background(),
+			code: codes.OK,
+		},
+		{
+			name: "credentials without configured auth",
+			ctx:  withBasicAuth(context.Background(), "foo", "bar"),
+			code: codes.Unauthenticated,
+			err:  "authentication is not configured",
+		},
+		{
+			name:     "missing credentials",
+			username: "foo",
+			password: "bar",
+			ctx:      context.Background(),
+			code:     codes.Unauthenticated,
+			err:      "invalid credentials",
+		},
+		{
+			name:     "invalid credentials",
+			username: "foo",
+			password: "bar",
+			ctx:      withBasicAuth(context.Background(), "foo", "baz"),
+			code:     codes.Unauthenticated,
+			err:      "invalid credentials",
+		},
+		{
+			name:     "valid credentials",
+			username: "foo",
+			password: "bar",
+			ctx:      withBasicAuth(context.Background(), "foo", "bar"),
+			code:     codes.OK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetBlockchainMetadata").Return(&visor.BlockchainMetadata{}, nil)
+
+			conn, stop := startGRPC(t, GRPCConfig{
+				EnabledAPISets: allAPISetsEnabled,
+				Username:       tc.username,
+				Password:       tc.password,
+			}, gateway)
+			defer stop()
+
+			_, err := grpcpb.NewBlockchainServiceClient(conn).GetMetadata(tc.ctx, &grpcpb.GetMetadataRequest{})
+			if tc.code == codes.OK {
+				require.NoError(t, err)
+				return
+			}
+			requireGRPCStatus(t, err, tc.code, tc.err)
+		})
+	}
+}
+
+func TestGRPCBlockchainService(t *testing.T) {
+	b := coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				Version: 1,
+				Time:    1536000000,
+				BkSeq:   10,
+				Fee:     20,
+			},
+			Body: coin.BlockBody{
+				Transactions: coin.Transactions{prepareTxnAndInputs(t).txn},
+			},
+		},
+	}
+	addr := makeAddress()
+	balance := wallet.BalancePair{
+		Confirmed: wallet.Balance{Coins: 1e6, Hours: 50},
+		Predicted: wallet.Balance{Coins: 2e6, Hours: 100},
+	}
+
+	gateway := &MockGatewayer{}
+	gateway.On("GetBlockchainMetadata").Return(&visor.BlockchainMetadata{
+		HeadBlock:   b,
+		Unspents:    5,
+		Unconfirmed: 2,
+	}, nil)
+	gateway.On("GetSignedBlockBySeq", uint64(10)).Return(&b, nil)
+	gateway.On("GetSignedBlockBySeq", uint64(11)).Return(nil, nil)
+	gateway.On("GetSignedBlockByHash", b.HashHeader()).Return(&b, nil)
+	gateway.On("GetBalanceOfAddresses", []cipher.Address{addr}).Return([]wallet.BalancePair{balance}, nil)
+
+	conn, stop := startGRPC(t, GRPCConfig{
+		EnabledAPISets: allAPISetsEnabled,
+	}, gateway)
+	defer stop()
+
+	client := grpcpb.NewBlockchainServiceClient(conn)
+	ctx := context.Background()
+
+	m, err := client.GetMetadata(ctx, &grpcpb.GetMetadataRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), m.Head.Seq)
+	require.Equal(t, b.HashHeader().Hex(), m.Head.Hash)
+	require.Equal(t, uint64(5), m.Unspents)
+	require.Equal(t, uint64(2), m.Unconfirmed)
+
+	block, err := client.GetBlock(ctx, &grpcpb.GetBlockRequest{
+		Block: &grpcpb.GetBlockRequest_Seq{Seq: 10},
+	})
+	require.NoError(t, err)
+	require.Equal(t, b.HashHeader().Hex(), block.Header.Hash)
+	require.Len(t, block.Transactions, 1)
+
+	txn := b.Body.Transactions[0]
+	require.Equal(t, txn.Hash().Hex(), block.Transactions[0].Hash)
+	require.Equal(t, txn.Out[0].UxID(txn.Hash()).Hex(), block.Transactions[0].Outputs[0].Uxid)
+	raw, err := txn.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, raw, block.Transactions[0].Raw)
+
+	block, err = client.GetBlock(ctx, &grpcpb.GetBlockRequest{
+		Block: &grpcpb.GetBlockRequest_Hash{Hash: b.HashHeader().Hex()},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), block.Header.Seq)
+
+	_, err = client.GetBlock(ctx, &grpcpb.GetBlockRequest{
+		Block: &grpcpb.GetBlockRequest_Seq{Seq: 11},
+	})
+	requireGRPCStatus(t, err, codes.NotFound, "block not found")
+
+	_, err = client.GetBlock(ctx, &grpcpb.GetBlockRequest{
+		Block: &grpcpb.GetBlockRequest_Hash{Hash: "foo"},
+	})
+	requireGRPCStatus(t, err, codes.InvalidArgument, "invalid hash: encoding/hex: invalid byte: U+006F 'o'")
+
+	_, err = client.GetBlock(ctx, &grpcpb.GetBlockRequest{})
+	requireGRPCStatus(t, err, codes.InvalidArgument, "hash or seq is required")
+
+	bal, err := client.GetBalance(ctx, &grpcpb.GetBalanceRequest{
+		Addresses: []string{addr.String()},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2e6), bal.Balance.Predicted.Coins)
+	require.Equal(t, uint64(50), bal.Addresses[addr.String()].Confirmed.Hours)
+
+	_, err = client.GetBalance(ctx, &grpcpb.GetBalanceRequest{
+		Addresses: []string{"foo"},
+	})
+	requireGRPCStatus(t, err, codes.InvalidArgument, "invalid address \"foo\": Invalid address length")
+}
+
+func TestGRPCSubscribeBlocks(t *testing.T) {
+	gateway := &MockGatewayer{}
+
+	events := make(chan visor.Event, 8)
+	unsubscribed := make(chan struct{})
+	gateway.On("SubscribeBlockchain", 0).Return((<-chan visor.Event)(events), func() {
+		close(unsubscribed)
+	})
+
+	conn, stop := startGRPC(t, GRPCConfig{
+		EnabledAPISets: allAPISetsEnabled,
+	}, gateway)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := grpcpb.NewBlockchainServiceClient(conn).SubscribeBlocks(ctx, &grpcpb.SubscribeBlocksRequest{})
+	require.NoError(t, err)
+
+	txn := prepareTxnAndInputs(t).txn
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &txn,
+	}
+
+	b := coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				BkSeq: 3,
+			},
+		},
+	}
+	events <- visor.Event{
+		Type:  visor.EventBlockExecuted,
+		Block: &b,
+	}
+
+	// Only the block events are streamed
+	block, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), block.Header.Seq)
+	require.Equal(t, b.HashHeader().Hex(), block.Header.Hash)
+
+	// The subscription to the blockchain events ends with the stream
+	cancel()
+	<-unsubscribed
+}
+
+func TestGRPCTransactionService(t *testing.T) {
+	subscribed := prepareTxnAndInputs(t)
+	other := prepareTxnAndInputs(t)
+	addr := subscribed.txn.Out[0].Address
+
+	gateway := &MockGatewayer{}
+	gateway.On("GetTransaction", subscribed.txn.Hash()).Return(&visor.Transaction{
+		Transaction: subscribed.txn,
+		Status:      visor.NewConfirmedTransactionStatus(2, 5),
+		Time:        1536000000,
+	}, nil)
+	gateway.On("GetTransaction", other.txn.Hash()).Return(nil, nil)
+	gateway.On("InjectBroadcastTransaction", subscribed.txn).Return(nil)
+	gateway.On("InjectBroadcastTransaction", other.txn).Return(visor.NewErrTxnViolatesUserConstraint(errors.New("bad txn")))
+
+	events := make(chan visor.Event, 8)
+	unsubscribed := make(chan struct{})
+	gateway.On("SubscribeBlockchain", 0).Return((<-chan visor.Event)(events), func() {
+		close(unsubscribed)
+	})
+	for _, x := range []transactionAndInputs{subscribed, other} {
+		gateway.On("GetTransactionWithInputs", x.txn.Hash()).Return(&visor.Transaction{
+			Transaction: x.txn,
+		}, x.inputs, nil)
+	}
+
+	conn, stop := startGRPC(t, GRPCConfig{
+		EnabledAPISets: allAPISetsEnabled,
+	}, gateway)
+	defer stop()
+
+	client := grpcpb.NewTransactionServiceClient(conn)
+	ctx := context.Background()
+
+	txn, err := client.GetTransaction(ctx, &grpcpb.GetTransactionRequest{
+		Txid: subscribed.txn.Hash().Hex(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, subscribed.txn.Hash().Hex(), txn.Hash)
+	require.Equal(t, &grpcpb.TransactionStatus{
+		Confirmed: true,
+		Height:    2,
+		BlockSeq:  5,
+	}, txn.Status)
+	require.Equal(t, uint64(1536000000), txn.Time)
+
+	_, err = client.GetTransaction(ctx, &grpcpb.GetTransactionRequest{
+		Txid: other.txn.Hash().Hex(),
+	})
+	requireGRPCStatus(t, err, codes.NotFound, "transaction not found")
+
+	raw, err := subscribed.txn.Serialize()
+	require.NoError(t, err)
+	injected, err := client.InjectTransaction(ctx, &grpcpb.InjectTransactionRequest{
+		Raw: raw,
+	})
+	require.NoError(t, err)
+	require.Equal(t, subscribed.txn.Hash().Hex(), injected.Txid)
+
+	raw, err = other.txn.Serialize()
+	require.NoError(t, err)
+	_, err = client.InjectTransaction(ctx, &grpcpb.InjectTransactionRequest{
+		Raw: raw,
+	})
+	requireGRPCStatus(t, err, codes.InvalidArgument, "Transaction violates user constraint: bad txn")
+
+	_, err = client.InjectTransaction(ctx, &grpcpb.InjectTransactionRequest{})
+	requireGRPCStatus(t, err, codes.InvalidArgument, "raw is required")
+
+	// Only the transactions of the subscribed address are streamed
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.SubscribeTransactions(streamCtx, &grpcpb.SubscribeTransactionsRequest{
+		Addresses: []string{addr.String()},
+	})
+	require.NoError(t, err)
+
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &other.txn,
+	}
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &subscribed.txn,
+	}
+
+	txn, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, subscribed.txn.Hash().Hex(), txn.Hash)
+
+	cancel()
+	<-unsubscribed
+}
+
+func TestGRPCTransactionServiceDisabled(t *testing.T) {
+	gateway := &MockGatewayer{}
+
+	conn, stop := startGRPC(t, GRPCConfig{
+		EnabledAPISets: map[string]struct{}{
+			EndpointsRead: struct{}{},
+		},
+	}, gateway)
+	defer stop()
+
+	_, err := grpcpb.NewTransactionServiceClient(conn).InjectTransaction(context.Background(), &grpcpb.InjectTransactionRequest{
+		Raw: []byte{1},
+	})
+	requireGRPCStatus(t, err, codes.PermissionDenied, "Endpoint is disabled")
+
+	_, err = grpcpb.NewWalletServiceClient(conn).ListWallets(context.Background(), &grpcpb.ListWalletsRequest{})
+	requireGRPCStatus(t, err, codes.PermissionDenied, "Endpoint is disabled")
+}
+
+func TestGRPCWalletService(t *testing.T) {
+	w, err := wallet.NewWallet(
+		"foo.wlt",
+		"foo",
+		"fooseed",
+		wallet.Options{
+			Type:      wallet.WalletTypeDeterministic,
+			Coin:      wallet.CoinTypeSkycoin,
+			GenerateN: 1,
+		})
+	require.NoError(t, err)
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+	newAddr := makeAddress()
+
+	balance := wallet.BalancePair{
+		Confirmed: wallet.Balance{Coins: 1e6, Hours: 50},
+		Predicted: wallet.Balance{Coins: 1e6, Hours: 50},
+	}
+
+	gateway := &MockGatewayer{}
+	gateway.On("GetWallets").Return(wallet.Wallets{"foo.wlt": w}, nil)
+	gateway.On("GetWalletBalance", mock.Anything, "foo.wlt").Return(balance, wallet.AddressBalances{
+		addrs[0].String(): balance,
+	}, nil)
+	gateway.On("GetWalletBalance", mock.Anything, "bar.wlt").Return(wallet.BalancePair{}, nil, wallet.ErrWalletNotExist)
+	gateway.On("NewAddresses", "foo.wlt", []byte("pwd"), uint64(1)).Return([]cipher.Address{newAddr}, nil)
+
+	conn, stop := startGRPC(t, GRPCConfig{
+		EnabledAPISets: allAPISetsEnabled,
+	}, gateway)
+	defer stop()
+
+	client := grpcpb.NewWalletServiceClient(conn)
+	ctx := context.Background()
+
+	wlts, err := client.ListWallets(ctx, &grpcpb.ListWalletsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []*grpcpb.WalletSummary{
+		{
+			Id:        "foo.wlt",
+			Label:     "foo",
+			Type:      wallet.WalletTypeDeterministic,
+			Addresses: []string{addrs[0].String()},
+		},
+	}, wlts.Wallets)
+
+	bal, err := client.GetWalletBalance(ctx, &grpcpb.GetWalletBalanceRequest{
+		Id: "foo.wlt",
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1e6), bal.Balance.Confirmed.Coins)
+	require.Equal(t, uint64(50), bal.Addresses[addrs[0].String()].Predicted.Hours)
+
+	_, err = client.GetWalletBalance(ctx, &grpcpb.GetWalletBalanceRequest{
+		Id: "bar.wlt",
+	})
+	requireGRPCStatus(t, err, codes.NotFound, wallet.ErrWalletNotExist.Error())
+
+	newAddrs, err := client.NewAddresses(ctx, &grpcpb.NewAddressesRequest{
+		Id:       "foo.wlt",
+		Password: "pwd",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{newAddr.String()}, newAddrs.Addresses)
+}
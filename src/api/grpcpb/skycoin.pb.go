@@ -0,0 +1,1920 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: skycoin.proto
+
+package grpcpb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type BlockHeader struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Time                 uint64   `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Seq                  uint64   `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	Fee                  uint64   `protobuf:"varint,4,opt,name=fee,proto3" json:"fee,omitempty"`
+	Hash                 string   `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	PrevHash             string   `protobuf:"bytes,6,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	BodyHash             string   `protobuf:"bytes,7,opt,name=body_hash,json=bodyHash,proto3" json:"body_hash,omitempty"`
+	UxHash               string   `protobuf:"bytes,8,opt,name=ux_hash,json=uxHash,proto3" json:"ux_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
+func (m *BlockHeader) String() string { return proto.CompactTextString(m) }
+func (*BlockHeader) ProtoMessage()    {}
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{0}
+}
+
+func (m *BlockHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeader.Unmarshal(m, b)
+}
+func (m *BlockHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeader.Marshal(b, m, deterministic)
+}
+func (m *BlockHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeader.Merge(m, src)
+}
+func (m *BlockHeader) XXX_Size() int {
+	return xxx_messageInfo_BlockHeader.Size(m)
+}
+func (m *BlockHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeader proto.InternalMessageInfo
+
+func (m *BlockHeader) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetTime() uint64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetPrevHash() string {
+	if m != nil {
+		return m.PrevHash
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetBodyHash() string {
+	if m != nil {
+		return m.BodyHash
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetUxHash() string {
+	if m != nil {
+		return m.UxHash
+	}
+	return ""
+}
+
+type Block struct {
+	Header               *BlockHeader   `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Transactions         []*Transaction `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{1}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetHeader() *BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Block) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+type TransactionStatus struct {
+	Confirmed bool `protobuf:"varint,1,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	// height is the number of blocks since the block of the transaction, including it
+	Height               uint64   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	BlockSeq             uint64   `protobuf:"varint,3,opt,name=block_seq,json=blockSeq,proto3" json:"block_seq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionStatus) Reset()         { *m = TransactionStatus{} }
+func (m *TransactionStatus) String() string { return proto.CompactTextString(m) }
+func (*TransactionStatus) ProtoMessage()    {}
+func (*TransactionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{2}
+}
+
+func (m *TransactionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionStatus.Unmarshal(m, b)
+}
+func (m *TransactionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionStatus.Marshal(b, m, deterministic)
+}
+func (m *TransactionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionStatus.Merge(m, src)
+}
+func (m *TransactionStatus) XXX_Size() int {
+	return xxx_messageInfo_TransactionStatus.Size(m)
+}
+func (m *TransactionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionStatus proto.InternalMessageInfo
+
+func (m *TransactionStatus) GetConfirmed() bool {
+	if m != nil {
+		return m.Confirmed
+	}
+	return false
+}
+
+func (m *TransactionStatus) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *TransactionStatus) GetBlockSeq() uint64 {
+	if m != nil {
+		return m.BlockSeq
+	}
+	return 0
+}
+
+type TransactionOutput struct {
+	Uxid    string `protobuf:"bytes,1,opt,name=uxid,proto3" json:"uxid,omitempty"`
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// coins are in droplets
+	Coins                uint64   `protobuf:"varint,3,opt,name=coins,proto3" json:"coins,omitempty"`
+	Hours                uint64   `protobuf:"varint,4,opt,name=hours,proto3" json:"hours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionOutput) Reset()         { *m = TransactionOutput{} }
+func (m *TransactionOutput) String() string { return proto.CompactTextString(m) }
+func (*TransactionOutput) ProtoMessage()    {}
+func (*TransactionOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{3}
+}
+
+func (m *TransactionOutput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionOutput.Unmarshal(m, b)
+}
+func (m *TransactionOutput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionOutput.Marshal(b, m, deterministic)
+}
+func (m *TransactionOutput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionOutput.Merge(m, src)
+}
+func (m *TransactionOutput) XXX_Size() int {
+	return xxx_messageInfo_TransactionOutput.Size(m)
+}
+func (m *TransactionOutput) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionOutput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionOutput proto.InternalMessageInfo
+
+func (m *TransactionOutput) GetUxid() string {
+	if m != nil {
+		return m.Uxid
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetCoins() uint64 {
+	if m != nil {
+		return m.Coins
+	}
+	return 0
+}
+
+func (m *TransactionOutput) GetHours() uint64 {
+	if m != nil {
+		return m.Hours
+	}
+	return 0
+}
+
+type Transaction struct {
+	Hash       string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	InnerHash  string   `protobuf:"bytes,2,opt,name=inner_hash,json=innerHash,proto3" json:"inner_hash,omitempty"`
+	Length     uint32   `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Type       uint32   `protobuf:"varint,4,opt,name=type,proto3" json:"type,omitempty"`
+	Signatures []string `protobuf:"bytes,5,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	// inputs are the uxids of the spent outputs
+	Inputs  []string             `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs []*TransactionOutput `protobuf:"bytes,7,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	// status is not set for the transactions of a block
+	Status *TransactionStatus `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	// time is the block time of confirmed transactions, the time it was received of unconfirmed transactions
+	Time uint64 `protobuf:"varint,9,opt,name=time,proto3" json:"time,omitempty"`
+	// raw is the serialized transaction
+	Raw                  []byte   `protobuf:"bytes,10,opt,name=raw,proto3" json:"raw,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{4}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Transaction) GetInnerHash() string {
+	if m != nil {
+		return m.InnerHash
+	}
+	return ""
+}
+
+func (m *Transaction) GetLength() uint32 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *Transaction) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Transaction) GetSignatures() []string {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *Transaction) GetInputs() []string {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetOutputs() []*TransactionOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetStatus() *TransactionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *Transaction) GetTime() uint64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Transaction) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
+type Balance struct {
+	// coins are in droplets
+	Coins                uint64   `protobuf:"varint,1,opt,name=coins,proto3" json:"coins,omitempty"`
+	Hours                uint64   `protobuf:"varint,2,opt,name=hours,proto3" json:"hours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Balance) Reset()         { *m = Balance{} }
+func (m *Balance) String() string { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()    {}
+func (*Balance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{5}
+}
+
+func (m *Balance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Balance.Unmarshal(m, b)
+}
+func (m *Balance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Balance.Marshal(b, m, deterministic)
+}
+func (m *Balance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Balance.Merge(m, src)
+}
+func (m *Balance) XXX_Size() int {
+	return xxx_messageInfo_Balance.Size(m)
+}
+func (m *Balance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Balance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Balance proto.InternalMessageInfo
+
+func (m *Balance) GetCoins() uint64 {
+	if m != nil {
+		return m.Coins
+	}
+	return 0
+}
+
+func (m *Balance) GetHours() uint64 {
+	if m != nil {
+		return m.Hours
+	}
+	return 0
+}
+
+type BalancePair struct {
+	Confirmed *Balance `protobuf:"bytes,1,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	// predicted includes the unconfirmed transactions
+	Predicted            *Balance `protobuf:"bytes,2,opt,name=predicted,proto3" json:"predicted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalancePair) Reset()         { *m = BalancePair{} }
+func (m *BalancePair) String() string { return proto.CompactTextString(m) }
+func (*BalancePair) ProtoMessage()    {}
+func (*BalancePair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{6}
+}
+
+func (m *BalancePair) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancePair.Unmarshal(m, b)
+}
+func (m *BalancePair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancePair.Marshal(b, m, deterministic)
+}
+func (m *BalancePair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancePair.Merge(m, src)
+}
+func (m *BalancePair) XXX_Size() int {
+	return xxx_messageInfo_BalancePair.Size(m)
+}
+func (m *BalancePair) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancePair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalancePair proto.InternalMessageInfo
+
+func (m *BalancePair) GetConfirmed() *Balance {
+	if m != nil {
+		return m.Confirmed
+	}
+	return nil
+}
+
+func (m *BalancePair) GetPredicted() *Balance {
+	if m != nil {
+		return m.Predicted
+	}
+	return nil
+}
+
+type GetMetadataRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMetadataRequest) Reset()         { *m = GetMetadataRequest{} }
+func (m *GetMetadataRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMetadataRequest) ProtoMessage()    {}
+func (*GetMetadataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{7}
+}
+
+func (m *GetMetadataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMetadataRequest.Unmarshal(m, b)
+}
+func (m *GetMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMetadataRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMetadataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMetadataRequest.Merge(m, src)
+}
+func (m *GetMetadataRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMetadataRequest.Size(m)
+}
+func (m *GetMetadataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMetadataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMetadataRequest proto.InternalMessageInfo
+
+type BlockchainMetadata struct {
+	Head                 *BlockHeader `protobuf:"bytes,1,opt,name=head,proto3" json:"head,omitempty"`
+	Unspents             uint64       `protobuf:"varint,2,opt,name=unspents,proto3" json:"unspents,omitempty"`
+	Unconfirmed          uint64       `protobuf:"varint,3,opt,name=unconfirmed,proto3" json:"unconfirmed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BlockchainMetadata) Reset()         { *m = BlockchainMetadata{} }
+func (m *BlockchainMetadata) String() string { return proto.CompactTextString(m) }
+func (*BlockchainMetadata) ProtoMessage()    {}
+func (*BlockchainMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{8}
+}
+
+func (m *BlockchainMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockchainMetadata.Unmarshal(m, b)
+}
+func (m *BlockchainMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockchainMetadata.Marshal(b, m, deterministic)
+}
+func (m *BlockchainMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockchainMetadata.Merge(m, src)
+}
+func (m *BlockchainMetadata) XXX_Size() int {
+	return xxx_messageInfo_BlockchainMetadata.Size(m)
+}
+func (m *BlockchainMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockchainMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockchainMetadata proto.InternalMessageInfo
+
+func (m *BlockchainMetadata) GetHead() *BlockHeader {
+	if m != nil {
+		return m.Head
+	}
+	return nil
+}
+
+func (m *BlockchainMetadata) GetUnspents() uint64 {
+	if m != nil {
+		return m.Unspents
+	}
+	return 0
+}
+
+func (m *BlockchainMetadata) GetUnconfirmed() uint64 {
+	if m != nil {
+		return m.Unconfirmed
+	}
+	return 0
+}
+
+type GetBlockRequest struct {
+	// Types that are valid to be assigned to Block:
+	//	*GetBlockRequest_Hash
+	//	*GetBlockRequest_Seq
+	Block                isGetBlockRequest_Block `protobuf_oneof:"block"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetBlockRequest) Reset()         { *m = GetBlockRequest{} }
+func (m *GetBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockRequest) ProtoMessage()    {}
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{9}
+}
+
+func (m *GetBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockRequest.Unmarshal(m, b)
+}
+func (m *GetBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockRequest.Merge(m, src)
+}
+func (m *GetBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlockRequest.Size(m)
+}
+func (m *GetBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockRequest proto.InternalMessageInfo
+
+type isGetBlockRequest_Block interface {
+	isGetBlockRequest_Block()
+}
+
+type GetBlockRequest_Hash struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3,oneof"`
+}
+
+type GetBlockRequest_Seq struct {
+	Seq uint64 `protobuf:"varint,2,opt,name=seq,proto3,oneof"`
+}
+
+func (*GetBlockRequest_Hash) isGetBlockRequest_Block() {}
+
+func (*GetBlockRequest_Seq) isGetBlockRequest_Block() {}
+
+func (m *GetBlockRequest) GetBlock() isGetBlockRequest_Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *GetBlockRequest) GetHash() string {
+	if x, ok := m.GetBlock().(*GetBlockRequest_Hash); ok {
+		return x.Hash
+	}
+	return ""
+}
+
+func (m *GetBlockRequest) GetSeq() uint64 {
+	if x, ok := m.GetBlock().(*GetBlockRequest_Seq); ok {
+		return x.Seq
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*GetBlockRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*GetBlockRequest_Hash)(nil),
+		(*GetBlockRequest_Seq)(nil),
+	}
+}
+
+type GetBalanceRequest struct {
+	Addresses            []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBalanceRequest) Reset()         { *m = GetBalanceRequest{} }
+func (m *GetBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceRequest) ProtoMessage()    {}
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{10}
+}
+
+func (m *GetBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceRequest.Unmarshal(m, b)
+}
+func (m *GetBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceRequest.Merge(m, src)
+}
+func (m *GetBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceRequest.Size(m)
+}
+func (m *GetBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceRequest proto.InternalMessageInfo
+
+func (m *GetBalanceRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type GetBalanceResponse struct {
+	Balance              *BalancePair            `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Addresses            map[string]*BalancePair `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetBalanceResponse) Reset()         { *m = GetBalanceResponse{} }
+func (m *GetBalanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBalanceResponse) ProtoMessage()    {}
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{11}
+}
+
+func (m *GetBalanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBalanceResponse.Unmarshal(m, b)
+}
+func (m *GetBalanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBalanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBalanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBalanceResponse.Merge(m, src)
+}
+func (m *GetBalanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBalanceResponse.Size(m)
+}
+func (m *GetBalanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBalanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBalanceResponse proto.InternalMessageInfo
+
+func (m *GetBalanceResponse) GetBalance() *BalancePair {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *GetBalanceResponse) GetAddresses() map[string]*BalancePair {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type SubscribeBlocksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeBlocksRequest) Reset()         { *m = SubscribeBlocksRequest{} }
+func (m *SubscribeBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksRequest) ProtoMessage()    {}
+func (*SubscribeBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{12}
+}
+
+func (m *SubscribeBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBlocksRequest.Unmarshal(m, b)
+}
+func (m *SubscribeBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBlocksRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBlocksRequest.Merge(m, src)
+}
+func (m *SubscribeBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBlocksRequest.Size(m)
+}
+func (m *SubscribeBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBlocksRequest proto.InternalMessageInfo
+
+type GetTransactionRequest struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()    {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{13}
+}
+
+func (m *GetTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionRequest.Merge(m, src)
+}
+func (m *GetTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionRequest.Size(m)
+}
+func (m *GetTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionRequest proto.InternalMessageInfo
+
+func (m *GetTransactionRequest) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+type InjectTransactionRequest struct {
+	// raw is the serialized signed transaction
+	Raw                  []byte   `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InjectTransactionRequest) Reset()         { *m = InjectTransactionRequest{} }
+func (m *InjectTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*InjectTransactionRequest) ProtoMessage()    {}
+func (*InjectTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{14}
+}
+
+func (m *InjectTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InjectTransactionRequest.Unmarshal(m, b)
+}
+func (m *InjectTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InjectTransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *InjectTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InjectTransactionRequest.Merge(m, src)
+}
+func (m *InjectTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_InjectTransactionRequest.Size(m)
+}
+func (m *InjectTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InjectTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InjectTransactionRequest proto.InternalMessageInfo
+
+func (m *InjectTransactionRequest) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
+type InjectTransactionResponse struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InjectTransactionResponse) Reset()         { *m = InjectTransactionResponse{} }
+func (m *InjectTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*InjectTransactionResponse) ProtoMessage()    {}
+func (*InjectTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{15}
+}
+
+func (m *InjectTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InjectTransactionResponse.Unmarshal(m, b)
+}
+func (m *InjectTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InjectTransactionResponse.Marshal(b, m, deterministic)
+}
+func (m *InjectTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InjectTransactionResponse.Merge(m, src)
+}
+func (m *InjectTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_InjectTransactionResponse.Size(m)
+}
+func (m *InjectTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InjectTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InjectTransactionResponse proto.InternalMessageInfo
+
+func (m *InjectTransactionResponse) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+type SubscribeTransactionsRequest struct {
+	// addresses filters the transactions with an input or an output of one of the addresses, all transactions are streamed if empty
+	Addresses            []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeTransactionsRequest) Reset()         { *m = SubscribeTransactionsRequest{} }
+func (m *SubscribeTransactionsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeTransactionsRequest) ProtoMessage()    {}
+func (*SubscribeTransactionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{16}
+}
+
+func (m *SubscribeTransactionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeTransactionsRequest.Unmarshal(m, b)
+}
+func (m *SubscribeTransactionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeTransactionsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeTransactionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeTransactionsRequest.Merge(m, src)
+}
+func (m *SubscribeTransactionsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeTransactionsRequest.Size(m)
+}
+func (m *SubscribeTransactionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeTransactionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeTransactionsRequest proto.InternalMessageInfo
+
+func (m *SubscribeTransactionsRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type ListWalletsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWalletsRequest) Reset()         { *m = ListWalletsRequest{} }
+func (m *ListWalletsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWalletsRequest) ProtoMessage()    {}
+func (*ListWalletsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{17}
+}
+
+func (m *ListWalletsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWalletsRequest.Unmarshal(m, b)
+}
+func (m *ListWalletsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWalletsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWalletsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWalletsRequest.Merge(m, src)
+}
+func (m *ListWalletsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWalletsRequest.Size(m)
+}
+func (m *ListWalletsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWalletsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWalletsRequest proto.InternalMessageInfo
+
+type WalletSummary struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label                string   `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Type                 string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Encrypted            bool     `protobuf:"varint,4,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	Addresses            []string `protobuf:"bytes,5,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WalletSummary) Reset()         { *m = WalletSummary{} }
+func (m *WalletSummary) String() string { return proto.CompactTextString(m) }
+func (*WalletSummary) ProtoMessage()    {}
+func (*WalletSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{18}
+}
+
+func (m *WalletSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WalletSummary.Unmarshal(m, b)
+}
+func (m *WalletSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WalletSummary.Marshal(b, m, deterministic)
+}
+func (m *WalletSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WalletSummary.Merge(m, src)
+}
+func (m *WalletSummary) XXX_Size() int {
+	return xxx_messageInfo_WalletSummary.Size(m)
+}
+func (m *WalletSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_WalletSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WalletSummary proto.InternalMessageInfo
+
+func (m *WalletSummary) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WalletSummary) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *WalletSummary) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *WalletSummary) GetEncrypted() bool {
+	if m != nil {
+		return m.Encrypted
+	}
+	return false
+}
+
+func (m *WalletSummary) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type ListWalletsResponse struct {
+	Wallets              []*WalletSummary `protobuf:"bytes,1,rep,name=wallets,proto3" json:"wallets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListWalletsResponse) Reset()         { *m = ListWalletsResponse{} }
+func (m *ListWalletsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWalletsResponse) ProtoMessage()    {}
+func (*ListWalletsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{19}
+}
+
+func (m *ListWalletsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWalletsResponse.Unmarshal(m, b)
+}
+func (m *ListWalletsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWalletsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWalletsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWalletsResponse.Merge(m, src)
+}
+func (m *ListWalletsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWalletsResponse.Size(m)
+}
+func (m *ListWalletsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWalletsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWalletsResponse proto.InternalMessageInfo
+
+func (m *ListWalletsResponse) GetWallets() []*WalletSummary {
+	if m != nil {
+		return m.Wallets
+	}
+	return nil
+}
+
+type GetWalletBalanceRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetWalletBalanceRequest) Reset()         { *m = GetWalletBalanceRequest{} }
+func (m *GetWalletBalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetWalletBalanceRequest) ProtoMessage()    {}
+func (*GetWalletBalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{20}
+}
+
+func (m *GetWalletBalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWalletBalanceRequest.Unmarshal(m, b)
+}
+func (m *GetWalletBalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWalletBalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetWalletBalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWalletBalanceRequest.Merge(m, src)
+}
+func (m *GetWalletBalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetWalletBalanceRequest.Size(m)
+}
+func (m *GetWalletBalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWalletBalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWalletBalanceRequest proto.InternalMessageInfo
+
+func (m *GetWalletBalanceRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetWalletBalanceResponse struct {
+	Balance              *BalancePair            `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Addresses            map[string]*BalancePair `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetWalletBalanceResponse) Reset()         { *m = GetWalletBalanceResponse{} }
+func (m *GetWalletBalanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetWalletBalanceResponse) ProtoMessage()    {}
+func (*GetWalletBalanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{21}
+}
+
+func (m *GetWalletBalanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetWalletBalanceResponse.Unmarshal(m, b)
+}
+func (m *GetWalletBalanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetWalletBalanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetWalletBalanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetWalletBalanceResponse.Merge(m, src)
+}
+func (m *GetWalletBalanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetWalletBalanceResponse.Size(m)
+}
+func (m *GetWalletBalanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetWalletBalanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetWalletBalanceResponse proto.InternalMessageInfo
+
+func (m *GetWalletBalanceResponse) GetBalance() *BalancePair {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *GetWalletBalanceResponse) GetAddresses() map[string]*BalancePair {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type NewAddressesRequest struct {
+	Id  string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Num uint64 `protobuf:"varint,2,opt,name=num,proto3" json:"num,omitempty"`
+	// password is required for encrypted wallets
+	Password             string   `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewAddressesRequest) Reset()         { *m = NewAddressesRequest{} }
+func (m *NewAddressesRequest) String() string { return proto.CompactTextString(m) }
+func (*NewAddressesRequest) ProtoMessage()    {}
+func (*NewAddressesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{22}
+}
+
+func (m *NewAddressesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewAddressesRequest.Unmarshal(m, b)
+}
+func (m *NewAddressesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewAddressesRequest.Marshal(b, m, deterministic)
+}
+func (m *NewAddressesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewAddressesRequest.Merge(m, src)
+}
+func (m *NewAddressesRequest) XXX_Size() int {
+	return xxx_messageInfo_NewAddressesRequest.Size(m)
+}
+func (m *NewAddressesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewAddressesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewAddressesRequest proto.InternalMessageInfo
+
+func (m *NewAddressesRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NewAddressesRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *NewAddressesRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type NewAddressesResponse struct {
+	Addresses            []string `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewAddressesResponse) Reset()         { *m = NewAddressesResponse{} }
+func (m *NewAddressesResponse) String() string { return proto.CompactTextString(m) }
+func (*NewAddressesResponse) ProtoMessage()    {}
+func (*NewAddressesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0db98435da4d5786, []int{23}
+}
+
+func (m *NewAddressesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewAddressesResponse.Unmarshal(m, b)
+}
+func (m *NewAddressesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewAddressesResponse.Marshal(b, m, deterministic)
+}
+func (m *NewAddressesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewAddressesResponse.Merge(m, src)
+}
+func (m *NewAddressesResponse) XXX_Size() int {
+	return xxx_messageInfo_NewAddressesResponse.Size(m)
+}
+func (m *NewAddressesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewAddressesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewAddressesResponse proto.InternalMessageInfo
+
+func (m *NewAddressesResponse) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*BlockHeader)(nil), "skycoin.api.BlockHeader")
+	proto.RegisterType((*Block)(nil), "skycoin.api.Block")
+	proto.RegisterType((*TransactionStatus)(nil), "skycoin.api.TransactionStatus")
+	proto.RegisterType((*TransactionOutput)(nil), "skycoin.api.TransactionOutput")
+	proto.RegisterType((*Transaction)(nil), "skycoin.api.Transaction")
+	proto.RegisterType((*Balance)(nil), "skycoin.api.Balance")
+	proto.RegisterType((*BalancePair)(nil), "skycoin.api.BalancePair")
+	proto.RegisterType((*GetMetadataRequest)(nil), "skycoin.api.GetMetadataRequest")
+	proto.RegisterType((*BlockchainMetadata)(nil), "skycoin.api.BlockchainMetadata")
+	proto.RegisterType((*GetBlockRequest)(nil), "skycoin.api.GetBlockRequest")
+	proto.RegisterType((*GetBalanceRequest)(nil), "skycoin.api.GetBalanceRequest")
+	proto.RegisterType((*GetBalanceResponse)(nil), "skycoin.api.GetBalanceResponse")
+	proto.RegisterMapType((map[string]*BalancePair)(nil), "skycoin.api.GetBalanceResponse.AddressesEntry")
+	proto.RegisterType((*SubscribeBlocksRequest)(nil), "skycoin.api.SubscribeBlocksRequest")
+	proto.RegisterType((*GetTransactionRequest)(nil), "skycoin.api.GetTransactionRequest")
+	proto.RegisterType((*InjectTransactionRequest)(nil), "skycoin.api.InjectTransactionRequest")
+	proto.RegisterType((*InjectTransactionResponse)(nil), "skycoin.api.InjectTransactionResponse")
+	proto.RegisterType((*SubscribeTransactionsRequest)(nil), "skycoin.api.SubscribeTransactionsRequest")
+	proto.RegisterType((*ListWalletsRequest)(nil), "skycoin.api.ListWalletsRequest")
+	proto.RegisterType((*WalletSummary)(nil), "skycoin.api.WalletSummary")
+	proto.RegisterType((*ListWalletsResponse)(nil), "skycoin.api.ListWalletsResponse")
+	proto.RegisterType((*GetWalletBalanceRequest)(nil), "skycoin.api.GetWalletBalanceRequest")
+	proto.RegisterType((*GetWalletBalanceResponse)(nil), "skycoin.api.GetWalletBalanceResponse")
+	proto.RegisterMapType((map[string]*BalancePair)(nil), "skycoin.api.GetWalletBalanceResponse.AddressesEntry")
+	proto.RegisterType((*NewAddressesRequest)(nil), "skycoin.api.NewAddressesRequest")
+	proto.RegisterType((*NewAddressesResponse)(nil), "skycoin.api.NewAddressesResponse")
+}
+
+func init() { proto.RegisterFile("skycoin.proto", fileDescriptor_0db98435da4d5786) }
+
+var fileDescriptor_0db98435da4d5786 = []byte{
+	// 1146 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcb, 0x6e, 0xe3, 0xd4,
+	0x1b, 0x1f, 0xbb, 0xcd, 0xed, 0x4b, 0xdb, 0x99, 0x7e, 0x93, 0xe9, 0xf8, 0x9f, 0x7f, 0xe9, 0x04,
+	0xc3, 0xa0, 0x8e, 0x18, 0x85, 0x12, 0x0a, 0x1a, 0xa1, 0x6e, 0xa8, 0x40, 0x2d, 0xa2, 0x73, 0x91,
+	0x83, 0x40, 0x62, 0x41, 0x75, 0xe2, 0x9c, 0x36, 0xa6, 0x89, 0xed, 0xfa, 0x1c, 0xb7, 0xcd, 0x8e,
+	0x0d, 0xcf, 0xc0, 0x2b, 0xf0, 0x10, 0x48, 0xec, 0x78, 0x1c, 0x1e, 0x80, 0x15, 0x3a, 0x97, 0x38,
+	0xc7, 0x8e, 0xd3, 0x8e, 0xc4, 0x82, 0xdd, 0xf9, 0xee, 0xf7, 0x5f, 0x62, 0x58, 0x67, 0x17, 0x53,
+	0x3f, 0x0a, 0xc2, 0x6e, 0x9c, 0x44, 0x3c, 0xc2, 0xe6, 0x8c, 0x24, 0x71, 0xe0, 0xfe, 0x69, 0x41,
+	0xf3, 0x70, 0x1c, 0xf9, 0x17, 0xc7, 0x94, 0x0c, 0x69, 0x82, 0x0e, 0xd4, 0xae, 0x68, 0xc2, 0x82,
+	0x28, 0x74, 0xac, 0x8e, 0xb5, 0xbb, 0xee, 0xcd, 0x48, 0x44, 0x58, 0xe5, 0xc1, 0x84, 0x3a, 0x76,
+	0xc7, 0xda, 0x5d, 0xf5, 0xe4, 0x1b, 0x1f, 0xc0, 0x0a, 0xa3, 0x97, 0xce, 0x8a, 0x64, 0x89, 0xa7,
+	0xe0, 0x9c, 0x51, 0xea, 0xac, 0x2a, 0xce, 0x19, 0xa5, 0xc2, 0x6e, 0x44, 0xd8, 0xc8, 0xa9, 0x74,
+	0xac, 0xdd, 0x86, 0x27, 0xdf, 0xf8, 0x7f, 0x68, 0xc4, 0x09, 0xbd, 0x3a, 0x95, 0x82, 0xaa, 0x14,
+	0xd4, 0x05, 0xe3, 0x58, 0x0b, 0x07, 0xd1, 0x70, 0xaa, 0x84, 0x35, 0x25, 0x14, 0x0c, 0x29, 0x7c,
+	0x0c, 0xb5, 0xf4, 0x46, 0x89, 0xea, 0x52, 0x54, 0x4d, 0x6f, 0x84, 0xc0, 0xbd, 0x86, 0x8a, 0xac,
+	0x03, 0xf7, 0xa0, 0x3a, 0x92, 0xb5, 0xc8, 0x02, 0x9a, 0x3d, 0xa7, 0x6b, 0xd4, 0xdb, 0x35, 0x6a,
+	0xf5, 0xb4, 0x1e, 0x1e, 0xc0, 0x1a, 0x4f, 0x48, 0xc8, 0x88, 0xcf, 0x83, 0x28, 0x64, 0x8e, 0xdd,
+	0x59, 0x59, 0xb0, 0xfb, 0x76, 0xae, 0xe0, 0xe5, 0xb4, 0xdd, 0x33, 0xd8, 0x34, 0x84, 0x7d, 0x4e,
+	0x78, 0xca, 0x70, 0x1b, 0x1a, 0x7e, 0x14, 0x9e, 0x05, 0xc9, 0x84, 0x0e, 0x65, 0x1e, 0x75, 0x6f,
+	0xce, 0xc0, 0x2d, 0x91, 0x62, 0x70, 0x3e, 0xe2, 0xba, 0x99, 0x9a, 0x92, 0x95, 0x8b, 0xfc, 0x4e,
+	0xe7, 0x4d, 0xad, 0x4b, 0x46, 0x9f, 0x5e, 0xba, 0x93, 0x5c, 0x9c, 0xd7, 0x29, 0x8f, 0x53, 0x2e,
+	0x9a, 0x9b, 0xde, 0x04, 0x2a, 0x44, 0xc3, 0x93, 0x6f, 0x31, 0x42, 0x32, 0x1c, 0x26, 0x94, 0x31,
+	0xe9, 0xbe, 0xe1, 0xcd, 0x48, 0x6c, 0x41, 0x45, 0x14, 0xc4, 0xb4, 0x6f, 0x45, 0x08, 0xee, 0x28,
+	0x4a, 0x13, 0xa6, 0x87, 0xa6, 0x08, 0xf7, 0x77, 0x1b, 0x9a, 0x46, 0xbc, 0x6c, 0x8c, 0x96, 0x31,
+	0xc6, 0x77, 0x00, 0x82, 0x30, 0xa4, 0x89, 0x9a, 0x87, 0x0a, 0xd6, 0x90, 0x1c, 0x39, 0xab, 0x2d,
+	0xa8, 0x8e, 0x69, 0x78, 0xce, 0x47, 0x32, 0xde, 0xba, 0xa7, 0x29, 0xb9, 0x49, 0xd3, 0x58, 0x2d,
+	0xc9, 0xba, 0x27, 0xdf, 0xb8, 0x03, 0xc0, 0x82, 0xf3, 0x90, 0xf0, 0x34, 0xa1, 0xcc, 0xa9, 0x74,
+	0x56, 0x76, 0x1b, 0x9e, 0xc1, 0x11, 0xbe, 0x82, 0x30, 0x4e, 0x39, 0x73, 0xaa, 0x52, 0xa6, 0x29,
+	0x7c, 0x01, 0xb5, 0x48, 0xb6, 0x82, 0x39, 0x35, 0x39, 0xb6, 0x9d, 0x65, 0x63, 0x53, 0x1d, 0xf3,
+	0x66, 0xea, 0xf8, 0x19, 0x54, 0x99, 0x1c, 0x96, 0x5c, 0xa4, 0x5b, 0x0c, 0xd5, 0x48, 0x3d, 0xad,
+	0x9d, 0xdd, 0x41, 0x23, 0x7f, 0x07, 0x09, 0xb9, 0x76, 0xa0, 0x63, 0xed, 0xae, 0x79, 0xe2, 0xe9,
+	0x7e, 0x0a, 0xb5, 0x43, 0x32, 0x26, 0xa1, 0x4f, 0xe7, 0x5d, 0xb7, 0x4a, 0xbb, 0x6e, 0x9b, 0x5d,
+	0x4f, 0xa1, 0xa9, 0xcd, 0xde, 0x90, 0x20, 0xc1, 0x5e, 0x71, 0x8d, 0x9a, 0xbd, 0x56, 0x7e, 0x9d,
+	0x95, 0xb2, 0xb9, 0x5c, 0x3d, 0x79, 0x5b, 0xc3, 0xc0, 0xe7, 0x74, 0xe8, 0xd8, 0xb7, 0xd9, 0x64,
+	0x6a, 0x6e, 0x0b, 0xf0, 0x88, 0xf2, 0x97, 0x94, 0x93, 0x21, 0xe1, 0xc4, 0xa3, 0x97, 0x29, 0x65,
+	0xdc, 0xfd, 0xd9, 0x02, 0x94, 0xf7, 0xe2, 0x8f, 0x48, 0x10, 0xce, 0xa4, 0xf8, 0x1c, 0x56, 0xc5,
+	0xe1, 0xdc, 0x79, 0x5e, 0x52, 0x0b, 0xdb, 0x50, 0x4f, 0x43, 0x16, 0xd3, 0x90, 0xcf, 0x4a, 0xcd,
+	0x68, 0xec, 0x40, 0x33, 0x0d, 0xe7, 0x05, 0xaa, 0xad, 0x34, 0x59, 0xee, 0x97, 0x70, 0xff, 0x88,
+	0x72, 0xe9, 0x55, 0x67, 0x85, 0x2d, 0x73, 0x11, 0x8f, 0xef, 0xe9, 0x55, 0x44, 0x85, 0x44, 0x32,
+	0xc2, 0xf1, 0x3d, 0x89, 0x45, 0x87, 0x35, 0xa8, 0xc8, 0xeb, 0x71, 0x3f, 0x86, 0x4d, 0xe1, 0x45,
+	0xd7, 0xad, 0xfd, 0x6c, 0x43, 0x43, 0xdf, 0x05, 0x15, 0xa3, 0x11, 0x4b, 0x35, 0x67, 0xb8, 0x7f,
+	0x59, 0x80, 0xa6, 0x0d, 0x8b, 0xa3, 0x90, 0x51, 0xec, 0x41, 0x6d, 0xa0, 0x58, 0xe5, 0xe5, 0xcf,
+	0x67, 0xe7, 0xcd, 0x14, 0xf1, 0xc4, 0x0c, 0xa4, 0xb0, 0xa5, 0x9b, 0xb3, 0x5a, 0x8c, 0xd3, 0xfd,
+	0x62, 0x66, 0xf0, 0x55, 0xc8, 0x93, 0xa9, 0x91, 0x58, 0xfb, 0x3b, 0xd8, 0xc8, 0x0b, 0xc5, 0xf2,
+	0x5d, 0xd0, 0xa9, 0x3e, 0x4c, 0xf1, 0xc4, 0x2e, 0x54, 0xae, 0xc8, 0x38, 0xa5, 0x8e, 0x7d, 0x47,
+	0x8e, 0x4a, 0xed, 0x73, 0xfb, 0x85, 0xe5, 0x3a, 0xb0, 0xd5, 0x4f, 0x07, 0xcc, 0x4f, 0x82, 0x01,
+	0x95, 0xfd, 0x66, 0xb3, 0x35, 0xf8, 0x10, 0x1e, 0x1d, 0x51, 0x6e, 0x02, 0xa0, 0xee, 0xa0, 0xb8,
+	0x04, 0x03, 0x7c, 0xc4, 0xdb, 0x7d, 0x0e, 0xce, 0xd7, 0xe1, 0x4f, 0xd4, 0x2f, 0xd3, 0xd7, 0x57,
+	0x62, 0xcd, 0xaf, 0xe4, 0x23, 0xf8, 0x5f, 0x89, 0xb6, 0xee, 0x75, 0x99, 0xfb, 0x03, 0xd8, 0xce,
+	0xb2, 0x34, 0x6c, 0xd8, 0xdb, 0x0d, 0xb5, 0x05, 0x78, 0x12, 0x30, 0xfe, 0x3d, 0x19, 0x8f, 0x29,
+	0xcf, 0xea, 0xfb, 0xc5, 0x82, 0x75, 0xc5, 0xea, 0xa7, 0x93, 0x09, 0x49, 0xa6, 0xb8, 0x01, 0x76,
+	0x16, 0xd7, 0x0e, 0x86, 0xe2, 0x56, 0xc7, 0x64, 0x40, 0xc7, 0x1a, 0xe2, 0x14, 0x91, 0xc1, 0xd8,
+	0x8a, 0xce, 0x4f, 0xc0, 0xd8, 0x36, 0x34, 0x68, 0xe8, 0x27, 0xd3, 0x58, 0x1c, 0xdf, 0xaa, 0xc2,
+	0xfd, 0x8c, 0x91, 0xcf, 0xae, 0x52, 0xcc, 0xee, 0x1b, 0x78, 0x98, 0xcb, 0x4e, 0xb7, 0x61, 0x1f,
+	0x6a, 0xd7, 0x8a, 0x25, 0x0b, 0x6a, 0xf6, 0xda, 0xb9, 0x71, 0xe6, 0x32, 0xf7, 0x66, 0xaa, 0xee,
+	0x33, 0x78, 0x7c, 0x44, 0xb5, 0xaf, 0xc2, 0xe2, 0x17, 0xaa, 0x73, 0xff, 0xb6, 0xc0, 0x59, 0xd4,
+	0xfd, 0x17, 0x0b, 0xef, 0x2d, 0x2e, 0xfc, 0x7e, 0x71, 0xe1, 0x4b, 0xa3, 0xfd, 0x07, 0x6b, 0xdf,
+	0x87, 0x87, 0xaf, 0xe8, 0x75, 0xe6, 0x7a, 0x49, 0x8f, 0x44, 0xb0, 0x30, 0x9d, 0x68, 0x00, 0x13,
+	0x4f, 0x81, 0x6b, 0x31, 0x61, 0xec, 0x3a, 0x4a, 0x86, 0x7a, 0x03, 0x32, 0xda, 0xdd, 0x87, 0x56,
+	0xde, 0xa9, 0x6e, 0xe6, 0xad, 0xdb, 0xd9, 0xfb, 0xc3, 0x86, 0xcd, 0x39, 0xdc, 0xf6, 0x69, 0x72,
+	0x15, 0xf8, 0x14, 0x5f, 0x43, 0xd3, 0x80, 0x66, 0x7c, 0x52, 0x6c, 0x64, 0x01, 0xb4, 0xdb, 0x4f,
+	0x16, 0xf1, 0x38, 0x0f, 0xdf, 0x07, 0x50, 0x9f, 0x41, 0x2a, 0x6e, 0x2f, 0xe0, 0x90, 0x81, 0xb4,
+	0x6d, 0x5c, 0x74, 0x85, 0x2f, 0x01, 0xe6, 0x70, 0x85, 0x3b, 0x4b, 0x71, 0xac, 0x2c, 0x99, 0x12,
+	0x3c, 0x3d, 0x81, 0xfb, 0x05, 0xd4, 0xc1, 0xf7, 0x72, 0x36, 0xe5, 0x98, 0x54, 0x96, 0xda, 0x9e,
+	0xd5, 0xfb, 0xcd, 0x06, 0x34, 0x7f, 0xb8, 0x75, 0x0b, 0x5f, 0xc1, 0x46, 0x1e, 0xc0, 0xd0, 0x2d,
+	0xe6, 0xb5, 0x88, 0x56, 0xed, 0xa5, 0xff, 0xff, 0x70, 0x00, 0x9b, 0x0b, 0xa8, 0x85, 0x4f, 0x73,
+	0xea, 0xcb, 0x30, 0xb0, 0xfd, 0xc1, 0x5d, 0x6a, 0xba, 0x31, 0x3f, 0xc2, 0xa3, 0x52, 0xa0, 0xc3,
+	0x67, 0xe5, 0xed, 0x29, 0x01, 0xc3, 0xe5, 0x15, 0xec, 0x59, 0xbd, 0x5f, 0xed, 0x0c, 0xf4, 0x74,
+	0x97, 0xde, 0x40, 0xd3, 0x80, 0x9f, 0xc2, 0xa2, 0x2d, 0xc2, 0x66, 0xbb, 0xb3, 0x5c, 0x41, 0xd7,
+	0x70, 0x0a, 0x0f, 0x8a, 0x97, 0x8e, 0xef, 0xdf, 0x01, 0x04, 0xca, 0xf7, 0xd3, 0xb7, 0x82, 0x0b,
+	0xec, 0xc3, 0x9a, 0x79, 0x67, 0x98, 0x4f, 0xa9, 0xe4, 0xae, 0xdb, 0xef, 0xde, 0xa2, 0xa1, 0x9c,
+	0x1e, 0xd6, 0x7f, 0xa8, 0x9e, 0x27, 0xb1, 0x1f, 0x0f, 0x06, 0x55, 0xf9, 0xbd, 0xf4, 0xc9, 0x3f,
+	0x03, 0x00, 0x58, 0x93, 0x33, 0x52, 0x40, 0x0d, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BlockchainServiceClient is the client API for BlockchainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BlockchainServiceClient interface {
+	// GetMetadata returns the head block header and the unspent and unconfirmed counts
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*BlockchainMetadata, error)
+	// GetBlock returns a block by hash or seq
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// GetBalance returns the balance of addresses
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	// SubscribeBlocks streams the blocks added to the blockchain
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (BlockchainService_SubscribeBlocksClient, error)
+}
+
+type blockchainServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBlockchainServiceClient(cc *grpc.ClientConn) BlockchainServiceClient {
+	return &blockchainServiceClient{cc}
+}
+
+func (c *blockchainServiceClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*BlockchainMetadata, error) {
+	out := new(BlockchainMetadata)
+	err := c.cc.Invoke(ctx, "/skycoin.api.BlockchainService/GetMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockchainServiceClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/skycoin.api.BlockchainService/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockchainServiceClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/skycoin.api.BlockchainService/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockchainServiceClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (BlockchainService_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlockchainService_serviceDesc.Streams[0], "/skycoin.api.BlockchainService/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockchainServiceSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockchainService_SubscribeBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type blockchainServiceSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockchainServiceSubscribeBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockchainServiceServer is the server API for BlockchainService service.
+type BlockchainServiceServer interface {
+	// GetMetadata returns the head block header and the unspent and unconfirmed counts
+	GetMetadata(context.Context, *GetMetadataRequest) (*BlockchainMetadata, error)
+	// GetBlock returns a block by hash or seq
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	// GetBalance returns the balance of addresses
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	// SubscribeBlocks streams the blocks added to the blockchain
+	SubscribeBlocks(*SubscribeBlocksRequest, BlockchainService_SubscribeBlocksServer) error
+}
+
+// UnimplementedBlockchainServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBlockchainServiceServer struct {
+}
+
+func (*UnimplementedBlockchainServiceServer) GetMetadata(ctx context.Context, req *GetMetadataRequest) (*BlockchainMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (*UnimplementedBlockchainServiceServer) GetBlock(ctx context.Context, req *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (*UnimplementedBlockchainServiceServer) GetBalance(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (*UnimplementedBlockchainServiceServer) SubscribeBlocks(req *SubscribeBlocksRequest, srv BlockchainService_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+
+func RegisterBlockchainServiceServer(s *grpc.Server, srv BlockchainServiceServer) {
+	s.RegisterService(&_BlockchainService_serviceDesc, srv)
+}
+
+func _BlockchainService_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockchainServiceServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.BlockchainService/GetMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockchainServiceServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockchainService_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockchainServiceServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.BlockchainService/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockchainServiceServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockchainService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockchainServiceServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.BlockchainService/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockchainServiceServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockchainService_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockchainServiceServer).SubscribeBlocks(m, &blockchainServiceSubscribeBlocksServer{stream})
+}
+
+type BlockchainService_SubscribeBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type blockchainServiceSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockchainServiceSubscribeBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BlockchainService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "skycoin.api.BlockchainService",
+	HandlerType: (*BlockchainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler:    _BlockchainService_GetMetadata_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _BlockchainService_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _BlockchainService_GetBalance_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _BlockchainService_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "skycoin.proto",
+}
+
+// TransactionServiceClient is the client API for TransactionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TransactionServiceClient interface {
+	// GetTransaction returns a confirmed or unconfirmed transaction by its hash
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	// InjectTransaction adds a signed transaction to the unconfirmed pool and broadcasts it
+	InjectTransaction(ctx context.Context, in *InjectTransactionRequest, opts ...grpc.CallOption) (*InjectTransactionResponse, error)
+	// SubscribeTransactions streams the transactions added to the unconfirmed pool and confirmed in blocks
+	SubscribeTransactions(ctx context.Context, in *SubscribeTransactionsRequest, opts ...grpc.CallOption) (TransactionService_SubscribeTransactionsClient, error)
+}
+
+type transactionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTransactionServiceClient(cc *grpc.ClientConn) TransactionServiceClient {
+	return &transactionServiceClient{cc}
+}
+
+func (c *transactionServiceClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/skycoin.api.TransactionService/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) InjectTransaction(ctx context.Context, in *InjectTransactionRequest, opts ...grpc.CallOption) (*InjectTransactionResponse, error) {
+	out := new(InjectTransactionResponse)
+	err := c.cc.Invoke(ctx, "/skycoin.api.TransactionService/InjectTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) SubscribeTransactions(ctx context.Context, in *SubscribeTransactionsRequest, opts ...grpc.CallOption) (TransactionService_SubscribeTransactionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TransactionService_serviceDesc.Streams[0], "/skycoin.api.TransactionService/SubscribeTransactions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionServiceSubscribeTransactionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionService_SubscribeTransactionsClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type transactionServiceSubscribeTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionServiceSubscribeTransactionsClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransactionServiceServer is the server API for TransactionService service.
+type TransactionServiceServer interface {
+	// GetTransaction returns a confirmed or unconfirmed transaction by its hash
+	GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error)
+	// InjectTransaction adds a signed transaction to the unconfirmed pool and broadcasts it
+	InjectTransaction(context.Context, *InjectTransactionRequest) (*InjectTransactionResponse, error)
+	// SubscribeTransactions streams the transactions added to the unconfirmed pool and confirmed in blocks
+	SubscribeTransactions(*SubscribeTransactionsRequest, TransactionService_SubscribeTransactionsServer) error
+}
+
+// UnimplementedTransactionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedTransactionServiceServer struct {
+}
+
+func (*UnimplementedTransactionServiceServer) GetTransaction(ctx context.Context, req *GetTransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (*UnimplementedTransactionServiceServer) InjectTransaction(ctx context.Context, req *InjectTransactionRequest) (*InjectTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectTransaction not implemented")
+}
+func (*UnimplementedTransactionServiceServer) SubscribeTransactions(req *SubscribeTransactionsRequest, srv TransactionService_SubscribeTransactionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTransactions not implemented")
+}
+
+func RegisterTransactionServiceServer(s *grpc.Server, srv TransactionServiceServer) {
+	s.RegisterService(&_TransactionService_serviceDesc, srv)
+}
+
+func _TransactionService_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.TransactionService/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_InjectTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).InjectTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.TransactionService/InjectTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).InjectTransaction(ctx, req.(*InjectTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionServiceServer).SubscribeTransactions(m, &transactionServiceSubscribeTransactionsServer{stream})
+}
+
+type TransactionService_SubscribeTransactionsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type transactionServiceSubscribeTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionServiceSubscribeTransactionsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TransactionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "skycoin.api.TransactionService",
+	HandlerType: (*TransactionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTransaction",
+			Handler:    _TransactionService_GetTransaction_Handler,
+		},
+		{
+			MethodName: "InjectTransaction",
+			Handler:    _TransactionService_InjectTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTransactions",
+			Handler:       _TransactionService_SubscribeTransactions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "skycoin.proto",
+}
+
+// WalletServiceClient is the client API for WalletService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WalletServiceClient interface {
+	// ListWallets returns the loaded wallets
+	ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error)
+	// GetWalletBalance returns the balance of a wallet and of its addresses
+	GetWalletBalance(ctx context.Context, in *GetWalletBalanceRequest, opts ...grpc.CallOption) (*GetWalletBalanceResponse, error)
+	// NewAddresses generates new addresses in a wallet
+	NewAddresses(ctx context.Context, in *NewAddressesRequest, opts ...grpc.CallOption) (*NewAddressesResponse, error)
+}
+
+type walletServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWalletServiceClient(cc *grpc.ClientConn) WalletServiceClient {
+	return &walletServiceClient{cc}
+}
+
+func (c *walletServiceClient) ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error) {
+	out := new(ListWalletsResponse)
+	err := c.cc.Invoke(ctx, "/skycoin.api.WalletService/ListWallets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetWalletBalance(ctx context.Context, in *GetWalletBalanceRequest, opts ...grpc.CallOption) (*GetWalletBalanceResponse, error) {
+	out := new(GetWalletBalanceResponse)
+	err := c.cc.Invoke(ctx, "/skycoin.api.WalletService/GetWalletBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) NewAddresses(ctx context.Context, in *NewAddressesRequest, opts ...grpc.CallOption) (*NewAddressesResponse, error) {
+	out := new(NewAddressesResponse)
+	err := c.cc.Invoke(ctx, "/skycoin.api.WalletService/NewAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WalletServiceServer is the server API for WalletService service.
+type WalletServiceServer interface {
+	// ListWallets returns the loaded wallets
+	ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error)
+	// GetWalletBalance returns the balance of a wallet and of its addresses
+	GetWalletBalance(context.Context, *GetWalletBalanceRequest) (*GetWalletBalanceResponse, error)
+	// NewAddresses generates new addresses in a wallet
+	NewAddresses(context.Context, *NewAddressesRequest) (*NewAddressesResponse, error)
+}
+
+// UnimplementedWalletServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedWalletServiceServer struct {
+}
+
+func (*UnimplementedWalletServiceServer) ListWallets(ctx context.Context, req *ListWalletsRequest) (*ListWalletsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWallets not implemented")
+}
+func (*UnimplementedWalletServiceServer) GetWalletBalance(ctx context.Context, req *GetWalletBalanceRequest) (*GetWalletBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWalletBalance not implemented")
+}
+func (*UnimplementedWalletServiceServer) NewAddresses(ctx context.Context, req *NewAddressesRequest) (*NewAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewAddresses not implemented")
+}
+
+func RegisterWalletServiceServer(s *grpc.Server, srv WalletServiceServer) {
+	s.RegisterService(&_WalletService_serviceDesc, srv)
+}
+
+func _WalletService_ListWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWalletsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.WalletService/ListWallets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListWallets(ctx, req.(*ListWalletsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetWalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWalletBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetWalletBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.WalletService/GetWalletBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetWalletBalance(ctx, req.(*GetWalletBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_NewAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).NewAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/skycoin.api.WalletService/NewAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).NewAddresses(ctx, req.(*NewAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WalletService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "skycoin.api.WalletService",
+	HandlerType: (*WalletServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListWallets",
+			Handler:    _WalletService_ListWallets_Handler,
+		},
+		{
+			MethodName: "GetWalletBalance",
+			Handler:    _WalletService_GetWalletBalance_Handler,
+		},
+		{
+			MethodName: "NewAddresses",
+			Handler:    _WalletService_NewAddresses_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "skycoin.proto",
+}
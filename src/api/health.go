@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/skycoin/skycoin/src/daemon"
@@ -116,3 +118,211 @@ func healthHandler(c muxConfig, gateway Gatewayer) http.HandlerFunc {
 		wh.SendJSONOr500(logger, w, health)
 	}
 }
+
+// HealthV2Response is returned by the /api/v2/health endpoint. It extends HealthResponse with
+// the state of the dependencies of the node and whether it is ready to serve requests.
+type HealthV2Response struct {
+	HealthResponse
+	Ready bool `json:"ready"`
+	// NotReady are the reasons the node is not ready
+	NotReady []string     `json:"not_ready"`
+	Sync     SyncHealth   `json:"sync"`
+	DB       DBHealth     `json:"db"`
+	Peers    PeersHealth  `json:"peers"`
+	Wallet   WalletHealth `json:"wallet"`
+}
+
+// SyncHealth is the blockchain synchronization state of the node
+type SyncHealth struct {
+	Synced bool `json:"synced"`
+	// Current is the head block seq of the node
+	Current uint64 `json:"current"`
+	// Highest is the highest head block seq reported by the node and its peers
+	Highest      uint64 `json:"highest"`
+	BlocksBehind uint64 `json:"blocks_behind"`
+}
+
+// DBHealth is the state of the database integrity check
+type DBHealth struct {
+	// Verified is false if the database integrity was never checked
+	Verified          bool        `json:"verified"`
+	VerifiedAt        int64       `json:"verified_at"`
+	TimeSinceVerified wh.Duration `json:"time_since_verified"`
+}
+
+// PeersHealth is the distribution of the peer connections of the node
+type PeersHealth struct {
+	// Pending connections have not finished connecting
+	Pending int `json:"pending"`
+	// Connected connections have not finished the introduction handshake
+	Connected int `json:"connected"`
+	// Introduced connections have finished the introduction handshake
+	Introduced int `json:"introduced"`
+	// Trusted is the number of introduced connections to trusted peers
+	Trusted int `json:"trusted"`
+	// Ahead, InSync and Behind are the number of introduced connections with a head block seq
+	// greater than, equal to and lower than the head block seq of the node
+	Ahead  int `json:"ahead"`
+	InSync int `json:"in_sync"`
+	Behind int `json:"behind"`
+}
+
+// WalletHealth is the state of the wallet service
+type WalletHealth struct {
+	Enabled          bool   `json:"enabled"`
+	Wallets          int    `json:"wallets"`
+	EncryptedWallets int    `json:"encrypted_wallets"`
+	Error            string `json:"error,omitempty"`
+}
+
+func newPeersHealth(conns []daemon.Connection, headSeq uint64) PeersHealth {
+	var p PeersHealth
+	for _, c := range conns {
+		switch c.State {
+		case daemon.ConnectionStatePending:
+			p.Pending++
+			continue
+		case daemon.ConnectionStateConnected:
+			p.Connected++
+			continue
+		}
+
+		p.Introduced++
+
+		if c.Pex.Trusted {
+			p.Trusted++
+		}
+
+		switch {
+		case c.Height > headSeq:
+			p.Ahead++
+		case c.Height == headSeq:
+			p.InSync++
+		default:
+			p.Behind++
+		}
+	}
+
+	return p
+}
+
+func getHealthV2Data(c muxConfig, gateway Gatewayer, maxBlocksBehind uint64) (*HealthV2Response, error) {
+	health, err := getHealthData(c, gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	headSeq := health.BlockchainMetadata.Head.BkSeq
+
+	progress := gateway.GetBlockchainProgress(headSeq)
+	sync := SyncHealth{
+		Current: progress.Current,
+		Highest: progress.Highest,
+	}
+	if progress.Highest > progress.Current {
+		sync.BlocksBehind = progress.Highest - progress.Current
+	}
+	sync.Synced = sync.BlocksBehind == 0
+
+	verifiedAt, err := gateway.GetDBVerifiedAt()
+	if err != nil {
+		return nil, fmt.Errorf("gateway.GetDBVerifiedAt failed: %v", err)
+	}
+
+	var db DBHealth
+	if !verifiedAt.IsZero() {
+		db = DBHealth{
+			Verified:          true,
+			VerifiedAt:        verifiedAt.Unix(),
+			TimeSinceVerified: wh.FromDuration(time.Since(verifiedAt)),
+		}
+	}
+
+	conns, err := gateway.GetConnections(func(c daemon.Connection) bool {
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gateway.GetConnections failed: %v", err)
+	}
+	peers := newPeersHealth(conns, headSeq)
+
+	wlt := WalletHealth{
+		Enabled: health.WalletAPIEnabled,
+	}
+	if wlt.Enabled {
+		wlts, err := gateway.GetWallets()
+		if err != nil {
+			wlt.Error = err.Error()
+		} else {
+			wlt.Wallets = len(wlts)
+			for _, w := range wlts {
+				if w.IsEncrypted() {
+					wlt.EncryptedWallets++
+				}
+			}
+		}
+	}
+
+	notReady := []string{}
+	if !gateway.DaemonConfig().DisableNetworking && peers.Introduced == 0 {
+		notReady = append(notReady, "no peer connections")
+	}
+	if sync.BlocksBehind > maxBlocksBehind {
+		notReady = append(notReady, fmt.Sprintf("blockchain is %d blocks behind", sync.BlocksBehind))
+	}
+	if wlt.Error != "" {
+		notReady = append(notReady, fmt.Sprintf("wallet service failed: %s", wlt.Error))
+	}
+
+	return &HealthV2Response{
+		HealthResponse: *health,
+		Ready:          len(notReady) == 0,
+		NotReady:       notReady,
+		Sync:           sync,
+		DB:             db,
+		Peers:          peers,
+		Wallet:         wlt,
+	}, nil
+}
+
+// healthHandlerV2 returns node health data, with a 503 status if the node is not ready
+// URI: /api/v2/health
+// Method: GET
+// Args:
+//	max_blocks_behind: the number of blocks the node can be behind its peers and be ready [optional, default 0]
+func healthHandlerV2(c muxConfig, gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		var maxBlocksBehind uint64
+		if s := r.FormValue("max_blocks_behind"); s != "" {
+			var err error
+			maxBlocksBehind, err = strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				writeError400Response(w, "invalid max_blocks_behind value")
+				return
+			}
+		}
+
+		health, err := getHealthV2Data(c, gateway, maxBlocksBehind)
+		if err != nil {
+			writeError500Response(w, err.Error())
+			return
+		}
+
+		resp := HTTPResponse{
+			Data: health,
+		}
+		if !health.Ready {
+			resp.Error = &HTTPError{
+				Code:    http.StatusServiceUnavailable,
+				Message: "Node is not ready: " + strings.Join(health.NotReady, ", "),
+			}
+		}
+
+		writeHTTPResponse(w, resp)
+	}
+}
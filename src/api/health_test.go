@@ -16,10 +16,13 @@ import (
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/daemon/pex"
 	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/readable"
 	"github.com/skycoin/skycoin/src/util/useragent"
 	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
 
 func TestHealthHandler(t *testing.T) {
@@ -258,3 +261,339 @@ func TestHealthHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthHandlerV2(t *testing.T) {
+	headSeq := uint64(21175)
+	metadata := visor.BlockchainMetadata{
+		HeadBlock: coin.SignedBlock{
+			Block: coin.Block{
+				Head: coin.BlockHeader{
+					BkSeq: headSeq,
+					Time:  1523168686,
+				},
+			},
+		},
+	}
+
+	introduced := func(height uint64, trusted bool) daemon.Connection {
+		return daemon.Connection{
+			Pex: pex.Peer{
+				Trusted: trusted,
+			},
+			ConnectionDetails: daemon.ConnectionDetails{
+				State:  daemon.ConnectionStateIntroduced,
+				Height: height,
+			},
+		}
+	}
+
+	conns := []daemon.Connection{
+		{
+			ConnectionDetails: daemon.ConnectionDetails{
+				State: daemon.ConnectionStatePending,
+			},
+		},
+		{
+			ConnectionDetails: daemon.ConnectionDetails{
+				State: daemon.ConnectionStateConnected,
+			},
+		},
+		introduced(headSeq, true),
+		introduced(headSeq, false),
+		introduced(headSeq-10, false),
+	}
+
+	connsAhead := append(conns, introduced(headSeq+3, false))
+
+	newWallet := func(name string, encrypt bool) wallet.Wallet {
+		opts := wallet.Options{
+			Type:      wallet.WalletTypeDeterministic,
+			Coin:      wallet.CoinTypeSkycoin,
+			GenerateN: 1,
+		}
+		if encrypt {
+			opts.Encrypt = true
+			opts.Password = []byte("pwd")
+			opts.CryptoType = crypto.CryptoTypeSha256Xor
+		}
+
+		w, err := wallet.NewWallet(name, name, name, opts)
+		require.NoError(t, err)
+		return w
+	}
+
+	wlts := wallet.Wallets{
+		"foo.wlt": newWallet("foo.wlt", false),
+		"bar.wlt": newWallet("bar.wlt", true),
+	}
+
+	verifiedAt := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		name               string
+		method             string
+		query              string
+		status             int
+		err                string
+		conns              []daemon.Connection
+		disableNetworking  bool
+		walletAPIDisabled  bool
+		getWalletsErr      error
+		getDBVerifiedAt    time.Time
+		getDBVerifiedAtErr error
+		ready              bool
+		notReady           []string
+		sync               SyncHealth
+		peers              PeersHealth
+		wallet             WalletHealth
+	}{
+		{
+			name:   "405",
+			method: http.MethodDelete,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 invalid max_blocks_behind",
+			method: http.MethodGet,
+			query:  "?max_blocks_behind=-1",
+			status: http.StatusBadRequest,
+			err:    "invalid max_blocks_behind value",
+		},
+		{
+			name:               "500 gateway.GetDBVerifiedAt error",
+			method:             http.MethodGet,
+			conns:              conns,
+			getDBVerifiedAtErr: errors.New("GetDBVerifiedAt failed"),
+			status:             http.StatusInternalServerError,
+			err:                "gateway.GetDBVerifiedAt failed: GetDBVerifiedAt failed",
+		},
+		{
+			name:            "200 ready",
+			method:          http.MethodGet,
+			conns:           conns,
+			getDBVerifiedAt: verifiedAt,
+			status:          http.StatusOK,
+			ready:           true,
+			notReady:        []string{},
+			sync: SyncHealth{
+				Synced:  true,
+				Current: headSeq,
+				Highest: headSeq,
+			},
+			peers: PeersHealth{
+				Pending:    1,
+				Connected:  1,
+				Introduced: 3,
+				Trusted:    1,
+				InSync:     2,
+				Behind:     1,
+			},
+			wallet: WalletHealth{
+				Enabled:          true,
+				Wallets:          2,
+				EncryptedWallets: 1,
+			},
+		},
+		{
+			name:              "200 ready wallet API disabled",
+			method:            http.MethodGet,
+			conns:             conns,
+			walletAPIDisabled: true,
+			status:            http.StatusOK,
+			ready:             true,
+			notReady:          []string{},
+			sync: SyncHealth{
+				Synced:  true,
+				Current: headSeq,
+				Highest: headSeq,
+			},
+			peers: PeersHealth{
+				Pending:    1,
+				Connected:  1,
+				Introduced: 3,
+				Trusted:    1,
+				InSync:     2,
+				Behind:     1,
+			},
+		},
+		{
+			name:   "503 not synced",
+			method: http.MethodGet,
+			conns:  connsAhead,
+			status: http.StatusServiceUnavailable,
+			err:    "Node is not ready: blockchain is 3 blocks behind",
+			ready:  false,
+			notReady: []string{
+				"blockchain is 3 blocks behind",
+			},
+			sync: SyncHealth{
+				Current:      headSeq,
+				Highest:      headSeq + 3,
+				BlocksBehind: 3,
+			},
+			peers: PeersHealth{
+				Pending:    1,
+				Connected:  1,
+				Introduced: 4,
+				Trusted:    1,
+				Ahead:      1,
+				InSync:     2,
+				Behind:     1,
+			},
+			wallet: WalletHealth{
+				Enabled:          true,
+				Wallets:          2,
+				EncryptedWallets: 1,
+			},
+		},
+		{
+			name:     "200 behind less than max_blocks_behind",
+			method:   http.MethodGet,
+			query:    "?max_blocks_behind=3",
+			conns:    connsAhead,
+			status:   http.StatusOK,
+			ready:    true,
+			notReady: []string{},
+			sync: SyncHealth{
+				Current:      headSeq,
+				Highest:      headSeq + 3,
+				BlocksBehind: 3,
+			},
+			peers: PeersHealth{
+				Pending:    1,
+				Connected:  1,
+				Introduced: 4,
+				Trusted:    1,
+				Ahead:      1,
+				InSync:     2,
+				Behind:     1,
+			},
+			wallet: WalletHealth{
+				Enabled:          true,
+				Wallets:          2,
+				EncryptedWallets: 1,
+			},
+		},
+		{
+			name:          "503 no peers and wallet service error",
+			method:        http.MethodGet,
+			getWalletsErr: errors.New("wallet dir is locked"),
+			status:        http.StatusServiceUnavailable,
+			err:           "Node is not ready: no peer connections, wallet service failed: wallet dir is locked",
+			ready:         false,
+			notReady: []string{
+				"no peer connections",
+				"wallet service failed: wallet dir is locked",
+			},
+			sync: SyncHealth{
+				Synced:  true,
+				Current: headSeq,
+				Highest: headSeq,
+			},
+			wallet: WalletHealth{
+				Enabled: true,
+				Error:   "wallet dir is locked",
+			},
+		},
+		{
+			name:              "200 no peers with networking disabled",
+			method:            http.MethodGet,
+			disableNetworking: true,
+			status:            http.StatusOK,
+			ready:             true,
+			notReady:          []string{},
+			sync: SyncHealth{
+				Synced:  true,
+				Current: headSeq,
+				Highest: headSeq,
+			},
+			wallet: WalletHealth{
+				Enabled:          true,
+				Wallets:          2,
+				EncryptedWallets: 1,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := defaultMuxConfig()
+			cfg.health.DaemonUserAgent = useragent.Data{
+				Coin:    "skycoin",
+				Version: "0.25.0",
+			}
+			if tc.walletAPIDisabled {
+				cfg.enabledAPISets = map[string]struct{}{
+					EndpointsRead:   struct{}{},
+					EndpointsStatus: struct{}{},
+				}
+			}
+
+			highest := headSeq
+			for _, c := range tc.conns {
+				if c.Height > highest {
+					highest = c.Height
+				}
+			}
+
+			gateway := &MockGatewayer{}
+			gateway.On("GetBlockchainMetadata").Return(&metadata, nil)
+			gateway.On("GetConnections", mock.Anything).Return(tc.conns, nil)
+			gateway.On("StartedAt").Return(time.Now())
+			gateway.On("DaemonConfig").Return(daemon.DaemonConfig{
+				DisableNetworking: tc.disableNetworking,
+			})
+			gateway.On("GetBlockchainProgress", headSeq).Return(&daemon.BlockchainProgress{
+				Current: headSeq,
+				Highest: highest,
+			})
+			gateway.On("GetDBVerifiedAt").Return(tc.getDBVerifiedAt, tc.getDBVerifiedAtErr)
+			gateway.On("GetWallets").Return(wlts, tc.getWalletsErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/health"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+			} else {
+				require.Nil(t, rsp.Error)
+			}
+
+			if tc.status != http.StatusOK && tc.status != http.StatusServiceUnavailable {
+				require.Nil(t, rsp.Data)
+				return
+			}
+
+			var r HealthV2Response
+			err = json.Unmarshal(rsp.Data, &r)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.ready, r.Ready)
+			require.Equal(t, tc.notReady, r.NotReady)
+			require.Equal(t, tc.sync, r.Sync)
+			require.Equal(t, tc.peers, r.Peers)
+			require.Equal(t, tc.wallet, r.Wallet)
+			require.Equal(t, headSeq, r.BlockchainMetadata.Head.BkSeq)
+
+			if tc.getDBVerifiedAt.IsZero() {
+				require.Equal(t, DBHealth{}, r.DB)
+			} else {
+				require.True(t, r.DB.Verified)
+				require.Equal(t, tc.getDBVerifiedAt.Unix(), r.DB.VerifiedAt)
+				require.True(t, r.DB.TimeSinceVerified.Duration >= time.Hour)
+			}
+		})
+	}
+}
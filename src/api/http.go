@@ -4,6 +4,7 @@ Package api implements the REST API interface
 package api
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -16,7 +17,6 @@ import (
 	"time"
 	"unicode"
 
-	"github.com/rs/cors"
 	"github.com/skycoin/skycoin/src/util/gziphandler"
 
 	"github.com/skycoin/skycoin/src/cipher"
@@ -58,6 +58,14 @@ const (
 	EndpointsNetCtrl = "NET_CTRL"
 	// EndpointsStorage endpoints implement interface for key-value storage for arbitrary data
 	EndpointsStorage = "STORAGE"
+	// EndpointsWebhook endpoints register webhooks that are sent the events of watched addresses,
+	// and the addresses of the watch list of the node
+	EndpointsWebhook = "WEBHOOK"
+	// EndpointsAdmin endpoints manage the node at runtime. They are only enabled by -enable-admin-api,
+	// which requires the web interface auth
+	EndpointsAdmin = "ADMIN"
+	// EndpointsGraphQL endpoints execute GraphQL queries over blocks, transactions, outputs and addresses
+	EndpointsGraphQL = "GRAPHQL"
 )
 
 // Server exposes an HTTP API
@@ -65,6 +73,8 @@ type Server struct {
 	server   *http.Server
 	listener net.Listener
 	done     chan struct{}
+	// quit is closed on shutdown to close the websocket connections, which are not closed by the http.Server
+	quit chan struct{}
 }
 
 // Config configures Server
@@ -79,9 +89,19 @@ type Config struct {
 	IdleTimeout        time.Duration
 	Health             HealthConfig
 	HostWhitelist      []string
-	EnabledAPISets     map[string]struct{}
-	Username           string
-	Password           string
+	// CORSRules allow browser origins to make cross-origin requests to the API, in addition to the host and HostWhitelist
+	CORSRules      []CORSRule
+	EnabledAPISets map[string]struct{}
+	Username       string
+	Password       string
+	// Tokens are the bearer tokens of the API, their scopes limit the API sets they can access
+	Tokens []APIToken
+	// RateLimits limit the requests of each client to the classes of endpoints, the other classes are not limited
+	RateLimits []RateLimit
+	// RequestTimeouts limit the time spent on the requests to the endpoints, the requests are not limited by default
+	RequestTimeouts []RequestTimeout
+	// AddressResolver resolves the receiver names of the send endpoints to addresses, names are not accepted if nil
+	AddressResolver AddressResolver
 }
 
 // HealthConfig configuration data exposed in /health
@@ -101,9 +121,15 @@ type muxConfig struct {
 	disableCSP         bool
 	enabledAPISets     map[string]struct{}
 	hostWhitelist      []string
+	corsRules          []CORSRule
 	username           string
 	password           string
+	tokens             []APIToken
+	rateLimits         []RateLimit
+	requestTimeouts    []RequestTimeout
+	addressResolver    AddressResolver
 	health             HealthConfig
+	quit               <-chan struct{}
 }
 
 // HTTPResponse represents the http response struct
@@ -169,6 +195,15 @@ func writeHTTPResponse(w http.ResponseWriter, resp HTTPResponse) {
 	}
 }
 
+// connContextKey is the context key of the connection of a request
+type connContextKey struct{}
+
+// connContext saves the connection in the context of its requests, for the
+// streaming handlers that reset the write deadline set by the WriteTimeout
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
 func create(host string, c Config, gateway Gatewayer) (*Server, error) {
 	var appLoc string
 	if c.EnableGUI {
@@ -198,7 +233,10 @@ func create(host string, c Config, gateway Gatewayer) (*Server, error) {
 		c.IdleTimeout = defaultIdleTimeout
 	}
 
+	quit := make(chan struct{})
+
 	mc := muxConfig{
+		quit:               quit,
 		host:               host,
 		appLoc:             appLoc,
 		enableGUI:          c.EnableGUI,
@@ -208,8 +246,13 @@ func create(host string, c Config, gateway Gatewayer) (*Server, error) {
 		health:             c.Health,
 		enabledAPISets:     c.EnabledAPISets,
 		hostWhitelist:      c.HostWhitelist,
+		corsRules:          c.CORSRules,
 		username:           c.Username,
 		password:           c.Password,
+		tokens:             c.Tokens,
+		rateLimits:         c.RateLimits,
+		requestTimeouts:    c.RequestTimeouts,
+		addressResolver:    c.AddressResolver,
 	}
 
 	srvMux := newServerMux(mc, gateway)
@@ -218,12 +261,14 @@ func create(host string, c Config, gateway Gatewayer) (*Server, error) {
 		ReadTimeout:  c.ReadTimeout,
 		WriteTimeout: c.WriteTimeout,
 		IdleTimeout:  c.IdleTimeout,
+		ConnContext:  connContext,
 		// MaxHeaderBytes: http.DefaultMaxHeaderBytes, // adjust this to allow longer GET queries
 	}
 
 	return &Server{
 		server: srv,
 		done:   make(chan struct{}),
+		quit:   quit,
 	}, nil
 }
 
@@ -321,6 +366,7 @@ func (s *Server) Shutdown() {
 
 	logger.Info("Shutting down web interface")
 	defer logger.Info("Web interface shut down")
+	close(s.quit)
 	if err := s.listener.Close(); err != nil {
 		logger.WithError(err).Warning("s.listener.Close() error")
 	}
@@ -331,19 +377,19 @@ func (s *Server) Shutdown() {
 func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	allowedOrigins := []string{fmt.Sprintf("http://%s", c.host)}
-	for _, s := range c.hostWhitelist {
-		allowedOrigins = append(allowedOrigins, fmt.Sprintf("http://%s", s))
+	// The host and the whitelisted hosts can make GET and POST requests to all endpoints
+	var corsRules []CORSRule
+	for _, h := range append([]string{c.host}, c.hostWhitelist...) {
+		corsRules = append(corsRules, CORSRule{
+			Origin:  strings.ToLower(fmt.Sprintf("http://%s", h)),
+			Methods: []string{http.MethodGet, http.MethodPost},
+		})
 	}
+	corsRules = append(corsRules, c.corsRules...)
 
-	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:     allowedOrigins,
-		Debug:              false,
-		AllowedMethods:     []string{http.MethodGet, http.MethodPost},
-		AllowedHeaders:     []string{"Origin", "Accept", "Content-Type", "X-Requested-With", CSRFHeaderName},
-		AllowCredentials:   false, // credentials are not used, but it would be safe to enable if necessary
-		OptionsPassthrough: false,
-	})
+	// The rate limiters are shared by the endpoints of their class
+	rateLimiters := newRateLimiters(c.rateLimits)
+	timeouts := requestTimeouts(c.requestTimeouts)
 
 	headerCheck := func(apiVersion, host string, hostWhitelist []string, handler http.Handler) http.Handler {
 		handler = originRefererCheck(apiVersion, host, hostWhitelist, handler)
@@ -379,6 +425,11 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 
 			for _, k := range apiSets {
 				if _, ok := c.enabledAPISets[k]; ok {
+					if !tokenAllows(r, r.Method, apiSets) {
+						writeError(w, apiVersion, http.StatusForbidden, "Token scope does not allow this endpoint")
+						return
+					}
+
 					f.ServeHTTP(w, r)
 					return
 				}
@@ -394,11 +445,9 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		})
 	}
 
-	webHandlerWithOptionals := func(apiVersion, endpoint string, handlerFunc http.Handler, checkCSRF, checkHeaders bool) {
+	webHandlerWithOptionals := func(apiVersion, endpoint string, handlerFunc http.Handler, methodAPISets map[string][]string, checkCSRF, checkHeaders bool) {
 		handler := wh.ElapsedHandler(logger, handlerFunc)
 
-		handler = corsHandler.Handler(handler)
-
 		if checkCSRF {
 			handler = CSRFCheck(apiVersion, c.disableCSRF, handler)
 		}
@@ -411,19 +460,37 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 			handler = ContentTypeJSONRequired(handler)
 		}
 
-		handler = basicAuth(apiVersion, c.username, c.password, "skycoin daemon", handler)
+		handler = requestTimeoutHandler(endpoint, timeouts, handler)
+		handler = tokenAuth(apiVersion, c.tokens, c.username, c.password, "skycoin daemon", handler)
+		handler = rateLimitHandler(apiVersion, endpoint, rateLimiters, methodAPISets, c.tokens, handler)
+		handler = corsHandler(apiVersion, corsRules, methodAPISets, handler)
+		handler = ETagHandler(handler)
 		handler = gziphandler.New(handler)
 		mux.Handle(endpoint, handler)
 	}
 
+	// routes are the API endpoints, documented by the OpenAPI spec
+	var routes []apiRoute
+	addRoute := func(apiVersion, endpoint string, methodAPISets map[string][]string) {
+		if strings.HasPrefix(endpoint, "/api/") {
+			routes = append(routes, apiRoute{
+				apiVersion:    apiVersion,
+				endpoint:      endpoint,
+				methodAPISets: methodAPISets,
+			})
+		}
+	}
+
 	webHandler := func(apiVersion, endpoint string, handler http.Handler, methodAPISets map[string][]string) {
+		addRoute(apiVersion, endpoint, methodAPISets)
+
 		// methodAPISets can be nil to ignore the concept of API sets for an endpoint. It will always be enabled.
 		// Explicitly check nil, caller should not pass empty initialized map
 		if methodAPISets != nil {
 			handler = forMethodAPISets(apiVersion, handler, methodAPISets)
 		}
 
-		webHandlerWithOptionals(apiVersion, endpoint, handler, true, !c.disableHeaderCheck)
+		webHandlerWithOptionals(apiVersion, endpoint, handler, methodAPISets, true, !c.disableHeaderCheck)
 	}
 
 	webHandlerV1 := func(endpoint string, handler http.Handler, methodAPISets map[string][]string) {
@@ -434,6 +501,21 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		webHandler(apiVersion2, "/api/v2"+endpoint, handler, methodAPISets)
 	}
 
+	// The streaming handlers are not wrapped by the handlers that wrap the http.ResponseWriter
+	// or write gzip headers. The websocket handler hijacks the connection, and the
+	// server-sent events handler flushes the events as they are written.
+	streamHandlerV2 := func(endpoint string, handler http.Handler, methodAPISets map[string][]string) {
+		addRoute(apiVersion2, "/api/v2"+endpoint, methodAPISets)
+		handler = forMethodAPISets(apiVersion2, handler, methodAPISets)
+		if !c.disableHeaderCheck {
+			handler = headerCheck(apiVersion2, c.host, c.hostWhitelist, handler)
+		}
+		handler = tokenAuth(apiVersion2, c.tokens, c.username, c.password, "skycoin daemon", handler)
+		handler = rateLimitHandler(apiVersion2, "/api/v2"+endpoint, rateLimiters, methodAPISets, c.tokens, handler)
+		handler = corsHandler(apiVersion2, corsRules, methodAPISets, handler)
+		mux.Handle("/api/v2"+endpoint, handler)
+	}
+
 	indexHandler := newIndexHandler(c.appLoc, c.enableGUI)
 	if !c.disableCSP {
 		indexHandler = CSPHandler(indexHandler, ContentSecurityPolicy)
@@ -463,7 +545,8 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 
 	// get the current CSRF token
 	csrfHandlerV1 := func(endpoint string, handler http.Handler) {
-		webHandlerWithOptionals(apiVersion1, "/api/v1"+endpoint, handler, false, !c.disableHeaderCheck)
+		addRoute(apiVersion1, "/api/v1"+endpoint, nil)
+		webHandlerWithOptionals(apiVersion1, "/api/v1"+endpoint, handler, nil, false, !c.disableHeaderCheck)
 	}
 	csrfHandlerV1("/csrf", getCSRFToken(c.disableCSRF)) // csrf is always available, regardless of the API set
 
@@ -472,6 +555,9 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/health", healthHandler(c, gateway), map[string][]string{
 		http.MethodGet: {EndpointsRead, EndpointsStatus},
 	})
+	webHandlerV2("/health", healthHandlerV2(c, gateway), map[string][]string{
+		http.MethodGet: {EndpointsRead, EndpointsStatus},
+	})
 
 	// Wallet endpoints
 	webHandlerV1("/wallet", walletHandler(gateway), map[string][]string{
@@ -489,7 +575,7 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/wallet/balance", walletBalanceHandler(gateway), map[string][]string{
 		http.MethodGet: {EndpointsWallet},
 	})
-	webHandlerV1("/wallet/transaction", walletCreateTransactionHandler(gateway), map[string][]string{
+	webHandlerV1("/wallet/transaction", walletCreateTransactionHandler(gateway, c.addressResolver), map[string][]string{
 		http.MethodPost: {EndpointsWallet},
 	})
 	webHandlerV2("/wallet/transaction/sign", walletSignTransactionHandler(gateway), map[string][]string{
@@ -507,6 +593,9 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/wallets/folderName", walletFolderHandler(gateway), map[string][]string{
 		http.MethodGet: {EndpointsWallet},
 	})
+	webHandlerV2("/wallets/transaction", walletsCreateTransactionHandler(gateway, c.addressResolver), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
 	webHandlerV1("/wallet/newSeed", newSeedHandler(), map[string][]string{
 		http.MethodGet: {EndpointsWallet},
 	})
@@ -516,6 +605,15 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV2("/wallet/seed/verify", http.HandlerFunc(walletVerifySeedHandler), map[string][]string{
 		http.MethodPost: {EndpointsWallet},
 	})
+	webHandlerV2("/wallet/seed-passphrase/verify", walletVerifySeedPassphraseHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
+	webHandlerV2("/wallet/seed/match", walletSeedMatchHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
+	webHandlerV2("/wallet/message/sign", walletSignMessageHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
 
 	webHandlerV1("/wallet/unload", walletUnloadHandler(gateway), map[string][]string{
 		http.MethodPost: {EndpointsWallet},
@@ -529,6 +627,18 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV2("/wallet/recover", walletRecoverHandler(gateway), map[string][]string{
 		http.MethodPost: {EndpointsWallet},
 	})
+	webHandlerV2("/wallet/unlock", walletUnlockHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
+	webHandlerV2("/wallet/lock", walletLockHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
+	webHandlerV2("/wallet/labels", walletLabelsHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsWallet},
+	})
+	webHandlerV2("/wallet/labels/import", walletLabelsImportHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsWallet},
+	})
 
 	// Blockchain interface
 	webHandlerV1("/blockchain/metadata", blockchainMetadataHandler(gateway), map[string][]string{
@@ -577,13 +687,25 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/transaction", transactionHandler(gateway), map[string][]string{
 		http.MethodGet: {EndpointsRead},
 	})
-	webHandlerV2("/transaction", transactionHandlerV2(gateway), map[string][]string{
+	webHandlerV2("/transaction", transactionHandlerV2(gateway, c.addressResolver), map[string][]string{
 		// http.MethodGet:  []string{EndpointsRead},
 		http.MethodPost: {EndpointsTransaction},
 	})
 	webHandlerV2("/transaction/verify", verifyTxnHandler(gateway), map[string][]string{
 		http.MethodPost: {EndpointsRead},
 	})
+	webHandlerV2("/transaction/decode", decodeTxnHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsRead},
+	})
+	webHandlerV2("/transaction/estimate", estimateTransactionHandler(gateway, c.addressResolver), map[string][]string{
+		http.MethodPost: {EndpointsRead},
+	})
+	webHandlerV2("/transaction/export", transactionExportHandler(gateway, c.addressResolver), map[string][]string{
+		http.MethodPost: {EndpointsTransaction},
+	})
+	webHandlerV2("/transaction/import", transactionImportHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsTransaction, EndpointsWallet},
+	})
 	webHandlerV1("/transactions", transactionsHandler(gateway), map[string][]string{
 		http.MethodGet:  {EndpointsRead},
 		http.MethodPost: {EndpointsRead},
@@ -597,6 +719,22 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/resendUnconfirmedTxns", resendUnconfirmedTxnsHandler(gateway), map[string][]string{
 		http.MethodPost: {EndpointsTransaction, EndpointsWallet},
 	})
+	webHandlerV2("/transaction/status", transactionStatusHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
+	webHandlerV2("/transaction/rebroadcast", rebroadcastTransactionHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsTransaction, EndpointsWallet},
+	})
+	webHandlerV2("/mempool", mempoolHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
+	webHandlerV2("/mempool/evictions", mempoolEvictionsHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
+	webHandlerV2("/graphql", graphqlHandler(gateway), map[string][]string{
+		http.MethodGet:  {EndpointsGraphQL},
+		http.MethodPost: {EndpointsGraphQL},
+	})
 	webHandlerV1("/rawtx", rawTxnHandler(gateway), map[string][]string{
 		http.MethodGet: {EndpointsRead},
 	})
@@ -610,6 +748,9 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		http.MethodGet:  {EndpointsRead},
 		http.MethodPost: {EndpointsRead},
 	})
+	webHandlerV2("/balance", balanceHandlerV2(gateway), map[string][]string{
+		http.MethodPost: {EndpointsRead},
+	})
 	webHandlerV1("/uxout", uxOutHandler(gateway), map[string][]string{
 		http.MethodGet: {EndpointsRead},
 	})
@@ -621,6 +762,12 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV2("/address/verify", http.HandlerFunc(addressVerifyHandler), map[string][]string{
 		http.MethodPost: {EndpointsRead},
 	})
+	webHandlerV2("/address/message/verify", http.HandlerFunc(addressVerifyMessageHandler), map[string][]string{
+		http.MethodPost: {EndpointsRead},
+	})
+	webHandlerV2("/address/balance-at", addressBalanceAtHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
 
 	// Explorer endpoints
 	webHandlerV1("/coinSupply", coinSupplyHandler(gateway), map[string][]string{
@@ -633,6 +780,17 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		http.MethodGet: {EndpointsRead},
 	})
 
+	// Websocket event stream
+	_, walletAPIEnabled := c.enabledAPISets[EndpointsWallet]
+	streamHandlerV2("/ws", webSocketHandler(gateway, walletAPIEnabled, c.quit), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
+
+	// Server-sent events stream
+	streamHandlerV2("/events", eventsHandler(gateway, walletAPIEnabled, c.quit), map[string][]string{
+		http.MethodGet: {EndpointsRead},
+	})
+
 	// Storage endpoint
 	webHandlerV2("/data", storageHandler(gateway), map[string][]string{
 		http.MethodGet:    {EndpointsStorage},
@@ -640,9 +798,75 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		http.MethodDelete: {EndpointsStorage},
 	})
 
+	// Webhook endpoints
+	webHandlerV2("/webhooks", webhooksHandler(gateway), map[string][]string{
+		http.MethodGet:    {EndpointsWebhook},
+		http.MethodPost:   {EndpointsWebhook},
+		http.MethodDelete: {EndpointsWebhook},
+	})
+	webHandlerV2("/webhooks/deliveries", webhookDeliveriesHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsWebhook},
+	})
+	webHandlerV2("/watch", watchListHandler(gateway), map[string][]string{
+		http.MethodGet:    {EndpointsWebhook},
+		http.MethodPost:   {EndpointsWebhook},
+		http.MethodDelete: {EndpointsWebhook},
+	})
+	webHandlerV2("/watch/transactions", watchTransactionsHandler(gateway), map[string][]string{
+		http.MethodGet: {EndpointsWebhook},
+	})
+
+	// Admin endpoints
+	webHandlerV2("/admin/db/verify", adminDBVerifyHandler(gateway), map[string][]string{
+		http.MethodGet:  {EndpointsAdmin},
+		http.MethodPost: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/chain/verify", adminChainVerifyHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/log/rotate", adminLogRotateHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/log/level", adminLogLevelHandler(gateway), map[string][]string{
+		http.MethodGet:  {EndpointsAdmin},
+		http.MethodPost: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/peers/reconnect", adminPeersReconnectHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/peers/ban", adminPeersBanHandler(gateway), map[string][]string{
+		http.MethodGet:    {EndpointsAdmin},
+		http.MethodPost:   {EndpointsAdmin},
+		http.MethodDelete: {EndpointsAdmin},
+	})
+	webHandlerV2("/admin/shutdown", adminShutdownHandler(gateway), map[string][]string{
+		http.MethodPost: {EndpointsAdmin},
+	})
+
+	// OpenAPI spec of the endpoints registered above, always available like the version
+	spec := &OpenAPI{}
+	webHandlerV2("/spec", specHandler(spec), nil)
+	*spec = *newOpenAPISpec(c, routes)
+
+	for _, t := range c.requestTimeouts {
+		if t.Endpoint != RequestTimeoutDefault && !hasRoute(routes, t.Endpoint) {
+			logger.Warningf("Request timeout of unknown endpoint %s is ignored", t.Endpoint)
+		}
+	}
+
 	return mux
 }
 
+// hasRoute returns true if the endpoint is one of the routes
+func hasRoute(routes []apiRoute, endpoint string) bool {
+	for _, r := range routes {
+		if r.endpoint == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
 // newIndexHandler returns a http.Handler for index.html, where index.html is in appLoc
 func newIndexHandler(appLoc string, enableGUI bool) http.Handler {
 	// Serves the main page
@@ -22,6 +22,9 @@ var allAPISetsEnabled = map[string]struct{}{
 	EndpointsInsecureWalletSeed: struct{}{},
 	EndpointsNetCtrl:            struct{}{},
 	EndpointsStorage:            struct{}{},
+	EndpointsWebhook:            struct{}{},
+	EndpointsAdmin:              struct{}{},
+	EndpointsGraphQL:            struct{}{},
 }
 
 func defaultMuxConfig() muxConfig {
@@ -154,21 +157,85 @@ var endpointsMethods = map[string][]string{
 		http.MethodGet,
 	},
 
+	"/api/v2/health": []string{
+		http.MethodGet,
+	},
 	"/api/v2/transaction/verify": []string{
 		http.MethodPost,
 	},
+	"/api/v2/transaction/decode": []string{
+		http.MethodPost,
+	},
+	"/api/v2/transaction/estimate": []string{
+		http.MethodPost,
+	},
+	"/api/v2/transaction/export": []string{
+		http.MethodPost,
+	},
+	"/api/v2/transaction/import": []string{
+		http.MethodPost,
+	},
+	"/api/v2/transaction/status": []string{
+		http.MethodGet,
+	},
+	"/api/v2/transaction/rebroadcast": []string{
+		http.MethodPost,
+	},
+	"/api/v2/mempool": []string{
+		http.MethodGet,
+	},
+	"/api/v2/mempool/evictions": []string{
+		http.MethodGet,
+	},
+	"/api/v2/graphql": []string{
+		http.MethodGet,
+		http.MethodPost,
+	},
+	"/api/v2/balance": []string{
+		http.MethodPost,
+	},
 	"/api/v2/address/verify": []string{
 		http.MethodPost,
 	},
+	"/api/v2/address/message/verify": []string{
+		http.MethodPost,
+	},
+	"/api/v2/address/balance-at": []string{
+		http.MethodGet,
+	},
 	"/api/v2/wallet/recover": []string{
 		http.MethodPost,
 	},
+	"/api/v2/wallet/unlock": []string{
+		http.MethodPost,
+	},
+	"/api/v2/wallet/lock": []string{
+		http.MethodPost,
+	},
+	"/api/v2/wallet/labels": []string{
+		http.MethodGet,
+	},
+	"/api/v2/wallet/labels/import": []string{
+		http.MethodPost,
+	},
 	"/api/v2/wallet/seed/verify": []string{
 		http.MethodPost,
 	},
+	"/api/v2/wallet/seed-passphrase/verify": []string{
+		http.MethodPost,
+	},
+	"/api/v2/wallet/seed/match": []string{
+		http.MethodPost,
+	},
+	"/api/v2/wallet/message/sign": []string{
+		http.MethodPost,
+	},
 	"/api/v2/wallet/transaction/sign": []string{
 		http.MethodPost,
 	},
+	"/api/v2/wallets/transaction": []string{
+		http.MethodPost,
+	},
 	"/api/v2/transaction": []string{
 		http.MethodPost,
 	},
@@ -178,6 +245,52 @@ var endpointsMethods = map[string][]string{
 		http.MethodPost,
 		http.MethodDelete,
 	},
+	"/api/v2/webhooks": []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodDelete,
+	},
+	"/api/v2/webhooks/deliveries": []string{
+		http.MethodGet,
+	},
+	"/api/v2/watch": []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodDelete,
+	},
+	"/api/v2/watch/transactions": []string{
+		http.MethodGet,
+	},
+
+	"/api/v2/admin/db/verify": []string{
+		http.MethodGet,
+		http.MethodPost,
+	},
+	"/api/v2/admin/chain/verify": []string{
+		http.MethodPost,
+	},
+	"/api/v2/admin/log/rotate": []string{
+		http.MethodPost,
+	},
+	"/api/v2/admin/log/level": []string{
+		http.MethodGet,
+		http.MethodPost,
+	},
+	"/api/v2/admin/peers/reconnect": []string{
+		http.MethodPost,
+	},
+	"/api/v2/admin/peers/ban": []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodDelete,
+	},
+	"/api/v2/admin/shutdown": []string{
+		http.MethodPost,
+	},
+
+	"/api/v2/spec": []string{
+		http.MethodGet,
+	},
 }
 
 func allEndpoints() []string {
@@ -310,7 +423,7 @@ func TestAPISetDisabled(t *testing.T) {
 		handler.ServeHTTP(rr, req)
 
 		switch endpoint {
-		case "/api/v1/csrf", "/api/v1/version": // always enabled
+		case "/api/v1/csrf", "/api/v1/version", "/api/v2/spec": // always enabled
 			require.Equal(t, http.StatusOK, rr.Code)
 		default:
 			require.Equal(t, http.StatusForbidden, rr.Code)
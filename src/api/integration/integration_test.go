@@ -358,7 +358,7 @@ func TestVerifyAddress(t *testing.T) {
 			name:    "invalid address",
 			addr:    "7apQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
 			errCode: http.StatusUnprocessableEntity,
-			errMsg:  "Invalid checksum",
+			errMsg:  "Invalid checksum, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?",
 		},
 
 		{
@@ -2471,7 +2471,7 @@ func TestStableTransactions(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 		{
@@ -2572,7 +2572,7 @@ func TestStableConfirmedTransactions(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 		{
@@ -2649,7 +2649,7 @@ func TestStableUnconfirmedTransactions(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 	}
@@ -2759,7 +2759,7 @@ func TestStableTransactionsVerbose(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 		{
@@ -2859,7 +2859,7 @@ func TestStableConfirmedTransactionsVerbose(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 		{
@@ -2936,7 +2936,7 @@ func TestStableUnconfirmedTransactionsVerbose(t *testing.T) {
 			err: api.ClientError{
 				Status:     "400 Bad Request",
 				StatusCode: http.StatusBadRequest,
-				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum",
+				Message:    "400 Bad Request - parse parameter: 'addrs' failed: address \"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKk\" is invalid: Invalid checksum, a character may be mistyped, did you mean 2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt?",
 			},
 		},
 	}
@@ -3317,6 +3317,16 @@ func TestStableRichlist(t *testing.T) {
 
 	expected = api.Richlist{}
 	checkGoldenFile(t, "richlist-150-include-distribution.golden", TestData{*richlist, &expected})
+
+	richlist, err = c.Richlist(&api.RichlistParams{
+		N:       8,
+		Page:    2,
+		Exclude: []string{"2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt"},
+	})
+	require.NoError(t, err)
+
+	expected = api.Richlist{}
+	checkGoldenFile(t, "richlist-8-page-2-exclude.golden", TestData{*richlist, &expected})
 }
 
 func TestLiveRichlist(t *testing.T) {
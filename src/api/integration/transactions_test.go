@@ -206,8 +206,8 @@ func TestLiveInjectTransactionEnableNetworking(t *testing.T) {
 			require.NoError(t, err)
 
 			for _, e := range es {
-				addr := cipher.MustAddressFromSecKey(e.Secret)
-				keyMap[addr.String()] = e.Secret
+				addr := cipher.MustAddressFromSecKey(e.Secret.SecKey())
+				keyMap[addr.String()] = e.Secret.SecKey()
 			}
 
 			// Get seckeys in wallet of input addresses
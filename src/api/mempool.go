@@ -0,0 +1,216 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/util/timeutil"
+)
+
+// MempoolTransaction is a transaction of the unconfirmed pool returned by GET /api/v2/mempool
+type MempoolTransaction struct {
+	Txid   string `json:"txid"`
+	Length uint32 `json:"length"`
+	// ReceivedAt is the unix time the transaction was last received
+	ReceivedAt int64 `json:"received_at"`
+	// CheckedAt is the unix time the transaction was last checked against the blockchain
+	CheckedAt int64 `json:"checked_at"`
+	// AnnouncedAt is the unix time the transaction was last announced, as recorded in the database
+	AnnouncedAt int64 `json:"announced_at,omitempty"`
+	// Age is the number of seconds since the transaction was received
+	Age uint64 `json:"age"`
+	// Peers is the number of peers the transaction was sent or announced to since the node started
+	Peers int `json:"peers"`
+	// Announces is the number of times the transaction was sent or announced to a peer since the node started
+	Announces  int   `json:"announces"`
+	LastSentAt int64 `json:"last_sent_at,omitempty"`
+	// Coins and Hours are the totals of the outputs
+	Coins string `json:"coins"`
+	Hours uint64 `json:"hours"`
+	// Fee is the number of coin hours burned, the input hours minus the output hours
+	Fee     uint64 `json:"fee"`
+	Inputs  int    `json:"inputs"`
+	Outputs int    `json:"outputs"`
+	// Valid is false if the transaction is not valid against the blockchain, Violation is why
+	Valid     bool   `json:"valid"`
+	Violation string `json:"violation,omitempty"`
+}
+
+// NewMempoolTransaction creates a MempoolTransaction, with its age at now
+func NewMempoolTransaction(txn daemon.MempoolTransaction, now time.Time) (*MempoolTransaction, error) {
+	var coins, hours uint64
+	for _, o := range txn.Transaction.Out {
+		var err error
+		coins, err = mathutil.AddUint64(coins, o.Coins)
+		if err != nil {
+			return nil, err
+		}
+		hours, err = mathutil.AddUint64(hours, o.Hours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var inputHours uint64
+	for _, in := range txn.Inputs {
+		var err error
+		inputHours, err = mathutil.AddUint64(inputHours, in.CalculatedHours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var fee uint64
+	if inputHours > hours {
+		fee = inputHours - hours
+	}
+
+	coinsStr, err := droplet.ToString(coins)
+	if err != nil {
+		return nil, err
+	}
+
+	unixTime := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+
+	received := timeutil.NanoToTime(txn.Received)
+	var age uint64
+	if now.After(received) {
+		age = uint64(now.Sub(received) / time.Second)
+	}
+
+	return &MempoolTransaction{
+		Txid:        txn.Transaction.Hash().Hex(),
+		Length:      txn.Transaction.Length,
+		ReceivedAt:  unixTime(received),
+		CheckedAt:   unixTime(timeutil.NanoToTime(txn.Checked)),
+		AnnouncedAt: unixTime(timeutil.NanoToTime(txn.Announced)),
+		Age:         age,
+		Peers:       txn.Peers,
+		Announces:   txn.Announces,
+		LastSentAt:  unixTime(txn.LastSentAt),
+		Coins:       coinsStr,
+		Hours:       hours,
+		Fee:         fee,
+		Inputs:      len(txn.Transaction.In),
+		Outputs:     len(txn.Transaction.Out),
+		Valid:       txn.IsValid == 1,
+		Violation:   txn.Violation,
+	}, nil
+}
+
+// MempoolResponse is returned by GET /api/v2/mempool
+type MempoolResponse struct {
+	// Count is the number of transactions, Valid and Invalid the number of valid and invalid transactions
+	Count   int `json:"count"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	// Transactions are ordered by received time, the oldest first
+	Transactions []MempoolTransaction `json:"transactions"`
+}
+
+// mempoolHandler returns the transactions of the unconfirmed pool, with their age,
+// propagation, fee and verification status, to debug the transactions that are not confirmed
+// Method: GET
+// URI: /api/v2/mempool
+// Args:
+//	addrs: comma-separated list of addresses [optional, returns the transactions with an input or output of one of the addresses]
+func mempoolHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		addrs, err := parseAddressesFromStr(r.FormValue("addrs"))
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("parse parameter: 'addrs' failed: %v", err))
+			return
+		}
+
+		txns, err := gateway.GetMempoolTransactions(addrs)
+		if err != nil {
+			writeError500Response(w, fmt.Sprintf("gateway.GetMempoolTransactions failed: %v", err))
+			return
+		}
+
+		now := time.Now()
+		resp := MempoolResponse{
+			Transactions: make([]MempoolTransaction, 0, len(txns)),
+		}
+		for _, txn := range txns {
+			mTxn, err := NewMempoolTransaction(txn, now)
+			if err != nil {
+				writeError500Response(w, fmt.Sprintf("NewMempoolTransaction failed: %v", err))
+				return
+			}
+
+			resp.Transactions = append(resp.Transactions, *mTxn)
+			if mTxn.Valid {
+				resp.Valid++
+			} else {
+				resp.Invalid++
+			}
+		}
+		resp.Count = len(resp.Transactions)
+
+		sort.SliceStable(resp.Transactions, func(i, j int) bool {
+			return resp.Transactions[i].ReceivedAt < resp.Transactions[j].ReceivedAt
+		})
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: resp,
+		})
+	}
+}
+
+// MempoolEviction is a transaction evicted from the unconfirmed pool, returned by GET /api/v2/mempool/evictions
+type MempoolEviction struct {
+	Txid      string `json:"txid"`
+	Reason    string `json:"reason"`
+	EvictedAt int64  `json:"evicted_at"`
+}
+
+// MempoolEvictionsResponse is returned by GET /api/v2/mempool/evictions
+type MempoolEvictionsResponse struct {
+	// Evictions are ordered by eviction time, the most recent first
+	Evictions []MempoolEviction `json:"evictions"`
+}
+
+// mempoolEvictionsHandler returns the transactions evicted from the unconfirmed pool since the node started
+// Method: GET
+// URI: /api/v2/mempool/evictions
+func mempoolEvictionsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		evictions := gateway.GetMempoolEvictions()
+
+		resp := MempoolEvictionsResponse{
+			Evictions: make([]MempoolEviction, len(evictions)),
+		}
+		for i, e := range evictions {
+			resp.Evictions[i] = MempoolEviction{
+				Txid:      e.Txid.Hex(),
+				Reason:    e.Reason,
+				EvictedAt: e.EvictedAt.Unix(),
+			}
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: resp,
+		})
+	}
+}
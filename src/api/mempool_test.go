@@ -0,0 +1,222 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func makeMempoolTransaction(t *testing.T, received time.Time, valid bool) daemon.MempoolTransaction {
+	txn := coin.Transaction{}
+	require.NoError(t, txn.PushInput(testutil.RandSHA256(t)))
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 1e6, 50))
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 2e6, 20))
+	require.NoError(t, txn.UpdateHeader())
+
+	mTxn := daemon.MempoolTransaction{
+		UnconfirmedTransaction: visor.UnconfirmedTransaction{
+			Transaction: txn,
+			Received:    received.UnixNano(),
+			Checked:     received.Add(time.Minute).UnixNano(),
+			Announced:   time.Time{}.UnixNano(),
+		},
+		Inputs: []visor.TransactionInput{
+			{
+				CalculatedHours: 100,
+			},
+		},
+		Peers:      2,
+		Announces:  3,
+		LastSentAt: received.Add(time.Second),
+	}
+
+	if valid {
+		mTxn.IsValid = 1
+	} else {
+		mTxn.Violation = "Transaction violates hard constraint: Transaction has duplicate inputs"
+	}
+
+	return mTxn
+}
+
+func TestNewMempoolTransaction(t *testing.T) {
+	received := time.Unix(1539849621, 0)
+	txn := makeMempoolTransaction(t, received, true)
+
+	mTxn, err := NewMempoolTransaction(txn, received.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, &MempoolTransaction{
+		Txid:       txn.Transaction.Hash().Hex(),
+		Length:     txn.Transaction.Length,
+		ReceivedAt: received.Unix(),
+		CheckedAt:  received.Add(time.Minute).Unix(),
+		Age:        3600,
+		Peers:      2,
+		Announces:  3,
+		LastSentAt: received.Add(time.Second).Unix(),
+		Coins:      "3.000000",
+		Hours:      70,
+		Fee:        30,
+		Inputs:     1,
+		Outputs:    2,
+		Valid:      true,
+	}, mTxn)
+
+	txn = makeMempoolTransaction(t, received, false)
+	mTxn, err = NewMempoolTransaction(txn, received.Add(-time.Second))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), mTxn.Age)
+	require.False(t, mTxn.Valid)
+	require.Equal(t, txn.Violation, mTxn.Violation)
+}
+
+func TestMempoolHandler(t *testing.T) {
+	now := time.Now()
+	older := makeMempoolTransaction(t, now.Add(-time.Hour), false)
+	newer := makeMempoolTransaction(t, now.Add(-time.Minute), true)
+	addr := testutil.MakeAddress()
+
+	tt := []struct {
+		name   string
+		method string
+		query  string
+		addrs  []cipher.Address
+		txns   []daemon.MempoolTransaction
+		err    error
+		status int
+		errMsg string
+		txids  []string
+	}{
+		{
+			name:   "405",
+			method: http.MethodDelete,
+			status: http.StatusMethodNotAllowed,
+			errMsg: "Method Not Allowed",
+		},
+		{
+			name:   "400 - invalid addrs",
+			method: http.MethodGet,
+			query:  "?addrs=foo",
+			status: http.StatusBadRequest,
+			errMsg: "parse parameter: 'addrs' failed: address \"foo\" is invalid: Invalid address length",
+		},
+		{
+			name:   "500 - gateway error",
+			method: http.MethodGet,
+			addrs:  []cipher.Address{},
+			err:    errors.New("failure"),
+			status: http.StatusInternalServerError,
+			errMsg: "gateway.GetMempoolTransactions failed: failure",
+		},
+		{
+			name:   "200 - empty",
+			method: http.MethodGet,
+			addrs:  []cipher.Address{},
+			status: http.StatusOK,
+			txids:  []string{},
+		},
+		{
+			name:   "200 - oldest first",
+			method: http.MethodGet,
+			query:  "?addrs=" + addr.String(),
+			addrs:  []cipher.Address{addr},
+			txns:   []daemon.MempoolTransaction{newer, older},
+			status: http.StatusOK,
+			txids:  []string{older.Transaction.Hash().Hex(), newer.Transaction.Hash().Hex()},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.addrs != nil {
+				gateway.On("GetMempoolTransactions", tc.addrs).Return(tc.txns, tc.err)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/mempool"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			if tc.status != http.StatusOK {
+				expected, err := json.MarshalIndent(NewHTTPErrorResponse(tc.status, tc.errMsg), "", "    ")
+				require.NoError(t, err)
+				require.Equal(t, string(expected), rr.Body.String())
+				return
+			}
+
+			var resp struct {
+				Data MempoolResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, len(tc.txids), resp.Data.Count)
+			txids := make([]string, len(resp.Data.Transactions))
+			for i, txn := range resp.Data.Transactions {
+				txids[i] = txn.Txid
+			}
+			require.Equal(t, tc.txids, txids)
+
+			if len(tc.txns) != 0 {
+				require.Equal(t, 1, resp.Data.Valid)
+				require.Equal(t, 1, resp.Data.Invalid)
+				require.True(t, resp.Data.Transactions[0].Age >= 3600)
+				require.Equal(t, older.Violation, resp.Data.Transactions[0].Violation)
+			}
+
+			gateway.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMempoolEvictionsHandler(t *testing.T) {
+	txid := testutil.RandSHA256(t)
+	evictedAt := time.Unix(1539849621, 0)
+
+	gateway := &MockGatewayer{}
+	gateway.On("GetMempoolEvictions").Return([]daemon.MempoolEviction{
+		{
+			Txid:      txid,
+			Reason:    daemon.EvictionReasonInvalid,
+			EvictedAt: evictedAt,
+		},
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v2/mempool/evictions", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(defaultMuxConfig(), gateway)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	expected, err := json.MarshalIndent(HTTPResponse{
+		Data: MempoolEvictionsResponse{
+			Evictions: []MempoolEviction{
+				{
+					Txid:      txid.Hex(),
+					Reason:    daemon.EvictionReasonInvalid,
+					EvictedAt: evictedAt.Unix(),
+				},
+			},
+		},
+	}, "", "    ")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), rr.Body.String())
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"crypto/subtle"
 	"fmt"
 	"net/http"
@@ -57,6 +58,82 @@ func isContentTypeJSON(contentType string) bool {
 	return contentType == ContentTypeJSON || strings.HasPrefix(contentType, ContentTypeJSON+";")
 }
 
+// ETagHandler sets a weak ETag, a hash of the response body, on the 200 OK responses to GET requests.
+// If the ETag matches the If-None-Match header of the request, it responds 304 Not Modified without a body instead.
+// The response is buffered to hash it. The ETag does not depend on the Content-Encoding of the response,
+// so the handler must wrap the handlers that write the response body, inside of the compression handler.
+func ETagHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedResponseWriter{
+			ResponseWriter: w,
+		}
+		handler.ServeHTTP(bw, r)
+
+		if bw.status == 0 {
+			bw.status = http.StatusOK
+		}
+
+		if bw.status == http.StatusOK && w.Header().Get("ETag") == "" {
+			h := cipher.SumSHA256(bw.body.Bytes())
+			etag := fmt.Sprintf(`W/"%s"`, h.Hex()[:32])
+			w.Header().Set("ETag", etag)
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.WriteHeader(bw.status)
+		w.Write(bw.body.Bytes()) //nolint:errcheck
+	})
+}
+
+// bufferedResponseWriter records the status and body written by a handler, the headers are written
+// to the wrapped http.ResponseWriter
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// etagMatches returns true if an If-None-Match header matches the etag, with the weak comparison of RFC 7232
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HostCheck checks that the request's Host header is 127.0.0.1:$port or localhost:$port
 // if the HTTP interface host is also a localhost address.
 // This prevents DNS rebinding attacks, where an attacker uses a DNS rebinding service
@@ -130,6 +207,12 @@ func originRefererCheck(apiVersion, host string, hostWhitelist []string, handler
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Cross-origin requests allowed by the CORS rules are accepted
+		if corsAllowed(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
 		origin := r.Header.Get("Origin")
 		referer := r.Header.Get("Referer")
 		toCheck := origin
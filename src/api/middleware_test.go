@@ -1,7 +1,10 @@
 package api
 
 import (
+	"compress/flate"
+	"compress/gzip"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -282,3 +285,136 @@ func TestIsContentTypeJSON(t *testing.T) {
 	require.False(t, isContentTypeJSON("application/x-www-form-urlencoded"))
 	require.False(t, isContentTypeJSON(ContentTypeForm))
 }
+
+func TestETagHandler(t *testing.T) {
+	gateway := &MockGatewayer{}
+	handler := newServerMux(defaultMuxConfig(), gateway)
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		require.NoError(t, err)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get(nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `W/"`), etag)
+	body := rr.Body.String()
+	require.NotEmpty(t, body)
+
+	// The ETag is stable
+	rr = get(nil)
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	require.Equal(t, body, rr.Body.String())
+
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{
+			name:        "matching etag",
+			ifNoneMatch: etag,
+			status:      http.StatusNotModified,
+		},
+		{
+			name:        "matching strong etag",
+			ifNoneMatch: strings.TrimPrefix(etag, "W/"),
+			status:      http.StatusNotModified,
+		},
+		{
+			name:        "matching etag in list",
+			ifNoneMatch: `W/"foo", ` + etag,
+			status:      http.StatusNotModified,
+		},
+		{
+			name:        "wildcard",
+			ifNoneMatch: "*",
+			status:      http.StatusNotModified,
+		},
+		{
+			name:        "different etag",
+			ifNoneMatch: `W/"foo"`,
+			status:      http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, encoding := range []string{"", "gzip"} {
+				rr := get(map[string]string{
+					"If-None-Match":   tc.ifNoneMatch,
+					"Accept-Encoding": encoding,
+				})
+				require.Equal(t, tc.status, rr.Code)
+				require.Equal(t, etag, rr.Header().Get("ETag"))
+
+				if tc.status == http.StatusNotModified {
+					require.Empty(t, rr.Body.Bytes())
+					require.Empty(t, rr.Header().Get("Content-Encoding"))
+				} else {
+					require.NotEmpty(t, rr.Body.Bytes())
+				}
+			}
+		})
+	}
+
+	// Errors and requests other than GET do not have an ETag
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/version", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	require.Empty(t, rr.Header().Get("ETag"))
+}
+
+func TestCompression(t *testing.T) {
+	gateway := &MockGatewayer{}
+	handler := newServerMux(defaultMuxConfig(), gateway)
+
+	get := func(acceptEncoding string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/version", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+		return rr
+	}
+
+	rr := get("")
+	require.Empty(t, rr.Header().Get("Content-Encoding"))
+	body := rr.Body.String()
+	contentType := rr.Header().Get("Content-Type")
+	etag := rr.Header().Get("ETag")
+
+	rr = get("gzip, deflate")
+	require.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	require.Equal(t, contentType, rr.Header().Get("Content-Type"))
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	gz, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, body, string(b))
+
+	rr = get("gzip;q=0.5, deflate")
+	require.Equal(t, "deflate", rr.Header().Get("Content-Encoding"))
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	b, err = ioutil.ReadAll(flate.NewReader(rr.Body))
+	require.NoError(t, err)
+	require.Equal(t, body, string(b))
+
+	rr = get("identity")
+	require.Empty(t, rr.Header().Get("Content-Encoding"))
+	require.Equal(t, body, rr.Body.String())
+}
@@ -3,9 +3,13 @@
 package api
 
 import (
+	admin "github.com/skycoin/skycoin/src/admin"
+
 	cipher "github.com/skycoin/skycoin/src/cipher"
 	coin "github.com/skycoin/skycoin/src/coin"
 
+	context "context"
+
 	daemon "github.com/skycoin/skycoin/src/daemon"
 
 	historydb "github.com/skycoin/skycoin/src/visor/historydb"
@@ -14,6 +18,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	notify "github.com/skycoin/skycoin/src/notify"
+
 	time "time"
 
 	transaction "github.com/skycoin/skycoin/src/transaction"
@@ -42,6 +48,52 @@ func (_m *MockGatewayer) AddStorageValue(storageType kvstorage.Type, key string,
 	return r0
 }
 
+// AddWatchedAddresses provides a mock function with given fields: addrs
+func (_m *MockGatewayer) AddWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error) {
+	ret := _m.Called(addrs)
+
+	var r0 []cipher.Address
+	if rf, ok := ret.Get(0).(func([]cipher.Address) []cipher.Address); ok {
+		r0 = rf(addrs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cipher.Address)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]cipher.Address) error); ok {
+		r1 = rf(addrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddWebhook provides a mock function with given fields: w
+func (_m *MockGatewayer) AddWebhook(w notify.Webhook) (*notify.Webhook, error) {
+	ret := _m.Called(w)
+
+	var r0 *notify.Webhook
+	if rf, ok := ret.Get(0).(func(notify.Webhook) *notify.Webhook); ok {
+		r0 = rf(w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*notify.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(notify.Webhook) error); ok {
+		r1 = rf(w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AddressCount provides a mock function with given fields:
 func (_m *MockGatewayer) AddressCount() (uint64, error) {
 	ret := _m.Called()
@@ -63,6 +115,27 @@ func (_m *MockGatewayer) AddressCount() (uint64, error) {
 	return r0, r1
 }
 
+// BanPeer provides a mock function with given fields: ip
+func (_m *MockGatewayer) BanPeer(ip string) (int, error) {
+	ret := _m.Called(ip)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTransaction provides a mock function with given fields: p, wp
 func (_m *MockGatewayer) CreateTransaction(p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(p, wp)
@@ -192,6 +265,75 @@ func (_m *MockGatewayer) EncryptWallet(wltID string, password []byte) (wallet.Wa
 	return r0, r1
 }
 
+// GetAddressBalanceAtSeq provides a mock function with given fields: addr, seq
+func (_m *MockGatewayer) GetAddressBalanceAtSeq(addr cipher.Address, seq uint64) (*visor.HistoricalBalance, error) {
+	ret := _m.Called(addr, seq)
+
+	var r0 *visor.HistoricalBalance
+	if rf, ok := ret.Get(0).(func(cipher.Address, uint64) *visor.HistoricalBalance); ok {
+		r0 = rf(addr, seq)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.HistoricalBalance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.Address, uint64) error); ok {
+		r1 = rf(addr, seq)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAddressBalanceAtTime provides a mock function with given fields: addr, t
+func (_m *MockGatewayer) GetAddressBalanceAtTime(addr cipher.Address, t uint64) (*visor.HistoricalBalance, error) {
+	ret := _m.Called(addr, t)
+
+	var r0 *visor.HistoricalBalance
+	if rf, ok := ret.Get(0).(func(cipher.Address, uint64) *visor.HistoricalBalance); ok {
+		r0 = rf(addr, t)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.HistoricalBalance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.Address, uint64) error); ok {
+		r1 = rf(addr, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAddressLabels provides a mock function with given fields: wltID
+func (_m *MockGatewayer) GetAddressLabels(wltID string) ([]wallet.AddressLabel, error) {
+	ret := _m.Called(wltID)
+
+	var r0 []wallet.AddressLabel
+	if rf, ok := ret.Get(0).(func(string) []wallet.AddressLabel); ok {
+		r0 = rf(wltID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wallet.AddressLabel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(wltID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllStorageValues provides a mock function with given fields: storageType
 func (_m *MockGatewayer) GetAllStorageValues(storageType kvstorage.Type) (map[string]string, error) {
 	ret := _m.Called(storageType)
@@ -293,6 +435,22 @@ func (_m *MockGatewayer) GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet
 	return r0, r1
 }
 
+// GetBannedPeers provides a mock function with given fields:
+func (_m *MockGatewayer) GetBannedPeers() []daemon.BannedPeer {
+	ret := _m.Called()
+
+	var r0 []daemon.BannedPeer
+	if rf, ok := ret.Get(0).(func() []daemon.BannedPeer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]daemon.BannedPeer)
+		}
+	}
+
+	return r0
+}
+
 // GetBlockchainMetadata provides a mock function with given fields:
 func (_m *MockGatewayer) GetBlockchainMetadata() (*visor.BlockchainMetadata, error) {
 	ret := _m.Called()
@@ -355,13 +513,13 @@ func (_m *MockGatewayer) GetBlocks(seqs []uint64) ([]coin.SignedBlock, error) {
 	return r0, r1
 }
 
-// GetBlocksInRange provides a mock function with given fields: start, end
-func (_m *MockGatewayer) GetBlocksInRange(start uint64, end uint64) ([]coin.SignedBlock, error) {
-	ret := _m.Called(start, end)
+// GetBlocksInRange provides a mock function with given fields: ctx, start, end
+func (_m *MockGatewayer) GetBlocksInRange(ctx context.Context, start uint64, end uint64) ([]coin.SignedBlock, error) {
+	ret := _m.Called(ctx, start, end)
 
 	var r0 []coin.SignedBlock
-	if rf, ok := ret.Get(0).(func(uint64, uint64) []coin.SignedBlock); ok {
-		r0 = rf(start, end)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) []coin.SignedBlock); ok {
+		r0 = rf(ctx, start, end)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]coin.SignedBlock)
@@ -369,8 +527,8 @@ func (_m *MockGatewayer) GetBlocksInRange(start uint64, end uint64) ([]coin.Sign
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
-		r1 = rf(start, end)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, start, end)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -378,13 +536,13 @@ func (_m *MockGatewayer) GetBlocksInRange(start uint64, end uint64) ([]coin.Sign
 	return r0, r1
 }
 
-// GetBlocksInRangeVerbose provides a mock function with given fields: start, end
-func (_m *MockGatewayer) GetBlocksInRangeVerbose(start uint64, end uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error) {
-	ret := _m.Called(start, end)
+// GetBlocksInRangeVerbose provides a mock function with given fields: ctx, start, end
+func (_m *MockGatewayer) GetBlocksInRangeVerbose(ctx context.Context, start uint64, end uint64) ([]coin.SignedBlock, [][][]visor.TransactionInput, error) {
+	ret := _m.Called(ctx, start, end)
 
 	var r0 []coin.SignedBlock
-	if rf, ok := ret.Get(0).(func(uint64, uint64) []coin.SignedBlock); ok {
-		r0 = rf(start, end)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) []coin.SignedBlock); ok {
+		r0 = rf(ctx, start, end)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]coin.SignedBlock)
@@ -392,8 +550,8 @@ func (_m *MockGatewayer) GetBlocksInRangeVerbose(start uint64, end uint64) ([]co
 	}
 
 	var r1 [][][]visor.TransactionInput
-	if rf, ok := ret.Get(1).(func(uint64, uint64) [][][]visor.TransactionInput); ok {
-		r1 = rf(start, end)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) [][][]visor.TransactionInput); ok {
+		r1 = rf(ctx, start, end)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([][][]visor.TransactionInput)
@@ -401,8 +559,8 @@ func (_m *MockGatewayer) GetBlocksInRangeVerbose(start uint64, end uint64) ([]co
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(uint64, uint64) error); ok {
-		r2 = rf(start, end)
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, uint64) error); ok {
+		r2 = rf(ctx, start, end)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -488,6 +646,50 @@ func (_m *MockGatewayer) GetConnections(f func(daemon.Connection) bool) ([]daemo
 	return r0, r1
 }
 
+// GetDBVerification provides a mock function with given fields:
+func (_m *MockGatewayer) GetDBVerification() (*admin.DBVerification, error) {
+	ret := _m.Called()
+
+	var r0 *admin.DBVerification
+	if rf, ok := ret.Get(0).(func() *admin.DBVerification); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*admin.DBVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDBVerifiedAt provides a mock function with given fields:
+func (_m *MockGatewayer) GetDBVerifiedAt() (time.Time, error) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDefaultConnections provides a mock function with given fields:
 func (_m *MockGatewayer) GetDefaultConnections() []string {
 	ret := _m.Called()
@@ -575,22 +777,82 @@ func (_m *MockGatewayer) GetLastBlocksVerbose(num uint64) ([]coin.SignedBlock, [
 	return r0, r1, r2
 }
 
-// GetRichlist provides a mock function with given fields: includeDistribution
-func (_m *MockGatewayer) GetRichlist(includeDistribution bool) (visor.Richlist, error) {
-	ret := _m.Called(includeDistribution)
+// GetLogLevel provides a mock function with given fields:
+func (_m *MockGatewayer) GetLogLevel() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMempoolEvictions provides a mock function with given fields:
+func (_m *MockGatewayer) GetMempoolEvictions() []daemon.MempoolEviction {
+	ret := _m.Called()
+
+	var r0 []daemon.MempoolEviction
+	if rf, ok := ret.Get(0).(func() []daemon.MempoolEviction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]daemon.MempoolEviction)
+		}
+	}
+
+	return r0
+}
+
+// GetMempoolTransactions provides a mock function with given fields: addrs
+func (_m *MockGatewayer) GetMempoolTransactions(addrs []cipher.Address) ([]daemon.MempoolTransaction, error) {
+	ret := _m.Called(addrs)
+
+	var r0 []daemon.MempoolTransaction
+	if rf, ok := ret.Get(0).(func([]cipher.Address) []daemon.MempoolTransaction); ok {
+		r0 = rf(addrs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]daemon.MempoolTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]cipher.Address) error); ok {
+		r1 = rf(addrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRichlist provides a mock function with given fields: p
+func (_m *MockGatewayer) GetRichlist(p visor.RichlistParams) (*visor.RichlistPage, error) {
+	ret := _m.Called(p)
 
-	var r0 visor.Richlist
-	if rf, ok := ret.Get(0).(func(bool) visor.Richlist); ok {
-		r0 = rf(includeDistribution)
+	var r0 *visor.RichlistPage
+	if rf, ok := ret.Get(0).(func(visor.RichlistParams) *visor.RichlistPage); ok {
+		r0 = rf(p)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(visor.Richlist)
+			r0 = ret.Get(0).(*visor.RichlistPage)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bool) error); ok {
-		r1 = rf(includeDistribution)
+	if rf, ok := ret.Get(1).(func(visor.RichlistParams) error); ok {
+		r1 = rf(p)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -782,6 +1044,29 @@ func (_m *MockGatewayer) GetTransaction(txid cipher.SHA256) (*visor.Transaction,
 	return r0, r1
 }
 
+// GetTransactionStatus provides a mock function with given fields: txid
+func (_m *MockGatewayer) GetTransactionStatus(txid cipher.SHA256) (*daemon.TransactionStatus, error) {
+	ret := _m.Called(txid)
+
+	var r0 *daemon.TransactionStatus
+	if rf, ok := ret.Get(0).(func(cipher.SHA256) *daemon.TransactionStatus); ok {
+		r0 = rf(txid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*daemon.TransactionStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.SHA256) error); ok {
+		r1 = rf(txid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionWithInputs provides a mock function with given fields: txid
 func (_m *MockGatewayer) GetTransactionWithInputs(txid cipher.SHA256) (*visor.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(txid)
@@ -814,29 +1099,29 @@ func (_m *MockGatewayer) GetTransactionWithInputs(txid cipher.SHA256) (*visor.Tr
 	return r0, r1, r2
 }
 
-// GetTransactions provides a mock function with given fields: flts, order, page
-func (_m *MockGatewayer) GetTransactions(flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, uint64, error) {
-	ret := _m.Called(flts, order, page)
+// GetTransactions provides a mock function with given fields: ctx, flts, order, page
+func (_m *MockGatewayer) GetTransactions(ctx context.Context, flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, visor.TxnPage, error) {
+	ret := _m.Called(ctx, flts, order, page)
 
 	var r0 []visor.Transaction
-	if rf, ok := ret.Get(0).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) []visor.Transaction); ok {
-		r0 = rf(flts, order, page)
+	if rf, ok := ret.Get(0).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) []visor.Transaction); ok {
+		r0 = rf(ctx, flts, order, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]visor.Transaction)
 		}
 	}
 
-	var r1 uint64
-	if rf, ok := ret.Get(1).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) uint64); ok {
-		r1 = rf(flts, order, page)
+	var r1 visor.TxnPage
+	if rf, ok := ret.Get(1).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) visor.TxnPage); ok {
+		r1 = rf(ctx, flts, order, page)
 	} else {
-		r1 = ret.Get(1).(uint64)
+		r1 = ret.Get(1).(visor.TxnPage)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) error); ok {
-		r2 = rf(flts, order, page)
+	if rf, ok := ret.Get(2).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) error); ok {
+		r2 = rf(ctx, flts, order, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -844,13 +1129,13 @@ func (_m *MockGatewayer) GetTransactions(flts []visor.TxFilter, order visor.Sort
 	return r0, r1, r2
 }
 
-// GetTransactionsWithInputs provides a mock function with given fields: flts, order, page
-func (_m *MockGatewayer) GetTransactionsWithInputs(flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, [][]visor.TransactionInput, uint64, error) {
-	ret := _m.Called(flts, order, page)
+// GetTransactionsWithInputs provides a mock function with given fields: ctx, flts, order, page
+func (_m *MockGatewayer) GetTransactionsWithInputs(ctx context.Context, flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) ([]visor.Transaction, [][]visor.TransactionInput, visor.TxnPage, error) {
+	ret := _m.Called(ctx, flts, order, page)
 
 	var r0 []visor.Transaction
-	if rf, ok := ret.Get(0).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) []visor.Transaction); ok {
-		r0 = rf(flts, order, page)
+	if rf, ok := ret.Get(0).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) []visor.Transaction); ok {
+		r0 = rf(ctx, flts, order, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]visor.Transaction)
@@ -858,24 +1143,24 @@ func (_m *MockGatewayer) GetTransactionsWithInputs(flts []visor.TxFilter, order
 	}
 
 	var r1 [][]visor.TransactionInput
-	if rf, ok := ret.Get(1).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) [][]visor.TransactionInput); ok {
-		r1 = rf(flts, order, page)
+	if rf, ok := ret.Get(1).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) [][]visor.TransactionInput); ok {
+		r1 = rf(ctx, flts, order, page)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([][]visor.TransactionInput)
 		}
 	}
 
-	var r2 uint64
-	if rf, ok := ret.Get(2).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) uint64); ok {
-		r2 = rf(flts, order, page)
+	var r2 visor.TxnPage
+	if rf, ok := ret.Get(2).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) visor.TxnPage); ok {
+		r2 = rf(ctx, flts, order, page)
 	} else {
-		r2 = ret.Get(2).(uint64)
+		r2 = ret.Get(2).(visor.TxnPage)
 	}
 
 	var r3 error
-	if rf, ok := ret.Get(3).(func([]visor.TxFilter, visor.SortOrder, *visor.PageIndex) error); ok {
-		r3 = rf(flts, order, page)
+	if rf, ok := ret.Get(3).(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) error); ok {
+		r3 = rf(ctx, flts, order, page)
 	} else {
 		r3 = ret.Error(3)
 	}
@@ -899,13 +1184,13 @@ func (_m *MockGatewayer) GetTrustConnections() []string {
 	return r0
 }
 
-// GetUnspentOutputsSummary provides a mock function with given fields: filters
-func (_m *MockGatewayer) GetUnspentOutputsSummary(filters []visor.OutputsFilter) (*visor.UnspentOutputsSummary, error) {
-	ret := _m.Called(filters)
+// GetUnspentOutputsSummary provides a mock function with given fields: ctx, filters
+func (_m *MockGatewayer) GetUnspentOutputsSummary(ctx context.Context, filters []visor.OutputsFilter) (*visor.UnspentOutputsSummary, error) {
+	ret := _m.Called(ctx, filters)
 
 	var r0 *visor.UnspentOutputsSummary
-	if rf, ok := ret.Get(0).(func([]visor.OutputsFilter) *visor.UnspentOutputsSummary); ok {
-		r0 = rf(filters)
+	if rf, ok := ret.Get(0).(func(context.Context, []visor.OutputsFilter) *visor.UnspentOutputsSummary); ok {
+		r0 = rf(ctx, filters)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*visor.UnspentOutputsSummary)
@@ -913,8 +1198,8 @@ func (_m *MockGatewayer) GetUnspentOutputsSummary(filters []visor.OutputsFilter)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]visor.OutputsFilter) error); ok {
-		r1 = rf(filters)
+	if rf, ok := ret.Get(1).(func(context.Context, []visor.OutputsFilter) error); ok {
+		r1 = rf(ctx, filters)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -975,20 +1260,20 @@ func (_m *MockGatewayer) GetWallet(wltID string) (wallet.Wallet, error) {
 	return r0, r1
 }
 
-// GetWalletBalance provides a mock function with given fields: wltID
-func (_m *MockGatewayer) GetWalletBalance(wltID string) (wallet.BalancePair, wallet.AddressBalances, error) {
-	ret := _m.Called(wltID)
+// GetWalletBalance provides a mock function with given fields: ctx, wltID
+func (_m *MockGatewayer) GetWalletBalance(ctx context.Context, wltID string) (wallet.BalancePair, wallet.AddressBalances, error) {
+	ret := _m.Called(ctx, wltID)
 
 	var r0 wallet.BalancePair
-	if rf, ok := ret.Get(0).(func(string) wallet.BalancePair); ok {
-		r0 = rf(wltID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) wallet.BalancePair); ok {
+		r0 = rf(ctx, wltID)
 	} else {
 		r0 = ret.Get(0).(wallet.BalancePair)
 	}
 
 	var r1 wallet.AddressBalances
-	if rf, ok := ret.Get(1).(func(string) wallet.AddressBalances); ok {
-		r1 = rf(wltID)
+	if rf, ok := ret.Get(1).(func(context.Context, string) wallet.AddressBalances); ok {
+		r1 = rf(ctx, wltID)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(wallet.AddressBalances)
@@ -996,8 +1281,8 @@ func (_m *MockGatewayer) GetWalletBalance(wltID string) (wallet.BalancePair, wal
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(string) error); ok {
-		r2 = rf(wltID)
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, wltID)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -1111,49 +1396,118 @@ func (_m *MockGatewayer) GetWallets() (wallet.Wallets, error) {
 	return r0, r1
 }
 
-// HeadBkSeq provides a mock function with given fields:
-func (_m *MockGatewayer) HeadBkSeq() (uint64, bool, error) {
+// GetWatchedAddresses provides a mock function with given fields:
+func (_m *MockGatewayer) GetWatchedAddresses() ([]cipher.Address, error) {
 	ret := _m.Called()
 
-	var r0 uint64
-	if rf, ok := ret.Get(0).(func() uint64); ok {
+	var r0 []cipher.Address
+	if rf, ok := ret.Get(0).(func() []cipher.Address); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(uint64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cipher.Address)
+		}
 	}
 
-	var r1 bool
-	if rf, ok := ret.Get(1).(func() bool); ok {
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
 		r1 = rf()
 	} else {
-		r1 = ret.Get(1).(bool)
-	}
-
-	var r2 error
-	if rf, ok := ret.Get(2).(func() error); ok {
-		r2 = rf()
-	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// InjectBroadcastTransaction provides a mock function with given fields: txn
-func (_m *MockGatewayer) InjectBroadcastTransaction(txn coin.Transaction) error {
-	ret := _m.Called(txn)
+// GetWebhookDeliveries provides a mock function with given fields: id
+func (_m *MockGatewayer) GetWebhookDeliveries(id string) ([]notify.Delivery, error) {
+	ret := _m.Called(id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(coin.Transaction) error); ok {
-		r0 = rf(txn)
+	var r0 []notify.Delivery
+	if rf, ok := ret.Get(0).(func(string) []notify.Delivery); ok {
+		r0 = rf(id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]notify.Delivery)
+		}
 	}
 
-	return r0
-}
-
-// InjectTransaction provides a mock function with given fields: txn
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields:
+func (_m *MockGatewayer) GetWebhooks() ([]notify.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []notify.Webhook
+	if rf, ok := ret.Get(0).(func() []notify.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]notify.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HeadBkSeq provides a mock function with given fields:
+func (_m *MockGatewayer) HeadBkSeq() (uint64, bool, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// InjectBroadcastTransaction provides a mock function with given fields: txn
+func (_m *MockGatewayer) InjectBroadcastTransaction(txn coin.Transaction) error {
+	ret := _m.Called(txn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(coin.Transaction) error); ok {
+		r0 = rf(txn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InjectTransaction provides a mock function with given fields: txn
 func (_m *MockGatewayer) InjectTransaction(txn coin.Transaction) error {
 	ret := _m.Called(txn)
 
@@ -1167,6 +1521,34 @@ func (_m *MockGatewayer) InjectTransaction(txn coin.Transaction) error {
 	return r0
 }
 
+// IsWatchedAddress provides a mock function with given fields: addr
+func (_m *MockGatewayer) IsWatchedAddress(addr cipher.Address) bool {
+	ret := _m.Called(addr)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(cipher.Address) bool); ok {
+		r0 = rf(addr)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// LockWallet provides a mock function with given fields: wltID
+func (_m *MockGatewayer) LockWallet(wltID string) error {
+	ret := _m.Called(wltID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(wltID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewAddresses provides a mock function with given fields: wltID, password, n, options
 func (_m *MockGatewayer) NewAddresses(wltID string, password []byte, n uint64, options ...wallet.Option) ([]cipher.Address, error) {
 	_va := make([]interface{}, len(options))
@@ -1197,6 +1579,48 @@ func (_m *MockGatewayer) NewAddresses(wltID string, password []byte, n uint64, o
 	return r0, r1
 }
 
+// RebroadcastTransaction provides a mock function with given fields: txid
+func (_m *MockGatewayer) RebroadcastTransaction(txid cipher.SHA256) (int, error) {
+	ret := _m.Called(txid)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(cipher.SHA256) int); ok {
+		r0 = rf(txid)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.SHA256) error); ok {
+		r1 = rf(txid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconnectPeers provides a mock function with given fields:
+func (_m *MockGatewayer) ReconnectPeers() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RecoverWallet provides a mock function with given fields: wltID, seed, seedPassphrase, password
 func (_m *MockGatewayer) RecoverWallet(wltID string, seed string, seedPassphrase string, password []byte) (wallet.Wallet, error) {
 	ret := _m.Called(wltID, seed, seedPassphrase, password)
@@ -1234,6 +1658,57 @@ func (_m *MockGatewayer) RemoveStorageValue(storageType kvstorage.Type, key stri
 	return r0
 }
 
+// RemoveWatchedAddresses provides a mock function with given fields: addrs
+func (_m *MockGatewayer) RemoveWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error) {
+	ret := _m.Called(addrs)
+
+	var r0 []cipher.Address
+	if rf, ok := ret.Get(0).(func([]cipher.Address) []cipher.Address); ok {
+		r0 = rf(addrs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cipher.Address)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]cipher.Address) error); ok {
+		r1 = rf(addrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWebhook provides a mock function with given fields: id
+func (_m *MockGatewayer) RemoveWebhook(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestShutdown provides a mock function with given fields:
+func (_m *MockGatewayer) RequestShutdown() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResendUnconfirmedTxns provides a mock function with given fields:
 func (_m *MockGatewayer) ResendUnconfirmedTxns() ([]cipher.SHA256, error) {
 	ret := _m.Called()
@@ -1257,6 +1732,27 @@ func (_m *MockGatewayer) ResendUnconfirmedTxns() ([]cipher.SHA256, error) {
 	return r0, r1
 }
 
+// RotateLogFile provides a mock function with given fields:
+func (_m *MockGatewayer) RotateLogFile() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScanAddresses provides a mock function with given fields: wltID, password, n, tf
 func (_m *MockGatewayer) ScanAddresses(wltID string, password []byte, n uint64, tf wallet.TransactionsFinder) ([]cipher.Address, error) {
 	ret := _m.Called(wltID, password, n, tf)
@@ -1303,6 +1799,64 @@ func (_m *MockGatewayer) ScanWalletAddresses(wltID string, password []byte, num
 	return r0, r1
 }
 
+// SetAddressLabels provides a mock function with given fields: wltID, labels
+func (_m *MockGatewayer) SetAddressLabels(wltID string, labels []wallet.AddressLabel) ([]cipher.Addresser, error) {
+	ret := _m.Called(wltID, labels)
+
+	var r0 []cipher.Addresser
+	if rf, ok := ret.Get(0).(func(string, []wallet.AddressLabel) []cipher.Addresser); ok {
+		r0 = rf(wltID, labels)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]cipher.Addresser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []wallet.AddressLabel) error); ok {
+		r1 = rf(wltID, labels)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLogLevel provides a mock function with given fields: level
+func (_m *MockGatewayer) SetLogLevel(level string) error {
+	ret := _m.Called(level)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SignMessage provides a mock function with given fields: wltID, password, addr, msg
+func (_m *MockGatewayer) SignMessage(wltID string, password []byte, addr cipher.Address, msg []byte) (cipher.Sig, error) {
+	ret := _m.Called(wltID, password, addr, msg)
+
+	var r0 cipher.Sig
+	if rf, ok := ret.Get(0).(func(string, []byte, cipher.Address, []byte) cipher.Sig); ok {
+		r0 = rf(wltID, password, addr, msg)
+	} else {
+		r0 = ret.Get(0).(cipher.Sig)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, cipher.Address, []byte) error); ok {
+		r1 = rf(wltID, password, addr, msg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartedAt provides a mock function with given fields:
 func (_m *MockGatewayer) StartedAt() time.Time {
 	ret := _m.Called()
@@ -1317,6 +1871,31 @@ func (_m *MockGatewayer) StartedAt() time.Time {
 	return r0
 }
 
+// SubscribeBlockchain provides a mock function with given fields: bufferSize
+func (_m *MockGatewayer) SubscribeBlockchain(bufferSize int) (<-chan visor.Event, func()) {
+	ret := _m.Called(bufferSize)
+
+	var r0 <-chan visor.Event
+	if rf, ok := ret.Get(0).(func(int) <-chan visor.Event); ok {
+		r0 = rf(bufferSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan visor.Event)
+		}
+	}
+
+	var r1 func()
+	if rf, ok := ret.Get(1).(func(int) func()); ok {
+		r1 = rf(bufferSize)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
 // TransactionsFinder provides a mock function with given fields:
 func (_m *MockGatewayer) TransactionsFinder() wallet.TransactionsFinder {
 	ret := _m.Called()
@@ -1333,6 +1912,20 @@ func (_m *MockGatewayer) TransactionsFinder() wallet.TransactionsFinder {
 	return r0
 }
 
+// UnbanPeer provides a mock function with given fields: ip
+func (_m *MockGatewayer) UnbanPeer(ip string) error {
+	ret := _m.Called(ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnloadWallet provides a mock function with given fields: wltID
 func (_m *MockGatewayer) UnloadWallet(wltID string) error {
 	ret := _m.Called(wltID)
@@ -1347,6 +1940,27 @@ func (_m *MockGatewayer) UnloadWallet(wltID string) error {
 	return r0
 }
 
+// UnlockWallet provides a mock function with given fields: wltID, password, ttl
+func (_m *MockGatewayer) UnlockWallet(wltID string, password []byte, ttl time.Duration) (time.Time, error) {
+	ret := _m.Called(wltID, password, ttl)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(string, []byte, time.Duration) time.Time); ok {
+		r0 = rf(wltID, password, ttl)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, time.Duration) error); ok {
+		r1 = rf(wltID, password, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateWalletLabel provides a mock function with given fields: wltID, label
 func (_m *MockGatewayer) UpdateWalletLabel(wltID string, label string) error {
 	ret := _m.Called(wltID, label)
@@ -1361,6 +1975,66 @@ func (_m *MockGatewayer) UpdateWalletLabel(wltID string, label string) error {
 	return r0
 }
 
+// VerifyChain provides a mock function with given fields: c
+func (_m *MockGatewayer) VerifyChain(c admin.Checkpoint) (*admin.ChainVerification, error) {
+	ret := _m.Called(c)
+
+	var r0 *admin.ChainVerification
+	if rf, ok := ret.Get(0).(func(admin.Checkpoint) *admin.ChainVerification); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*admin.ChainVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(admin.Checkpoint) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyDB provides a mock function with given fields:
+func (_m *MockGatewayer) VerifyDB() (*admin.DBVerification, error) {
+	ret := _m.Called()
+
+	var r0 *admin.DBVerification
+	if rf, ok := ret.Get(0).(func() *admin.DBVerification); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*admin.DBVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifySeedPassphrase provides a mock function with given fields: wltID, password, seedPassphrase
+func (_m *MockGatewayer) VerifySeedPassphrase(wltID string, password []byte, seedPassphrase string) error {
+	ret := _m.Called(wltID, password, seedPassphrase)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []byte, string) error); ok {
+		r0 = rf(wltID, password, seedPassphrase)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // VerifyTxnVerbose provides a mock function with given fields: txn, signed
 func (_m *MockGatewayer) VerifyTxnVerbose(txn *coin.Transaction, signed visor.TxnSignedFlag) ([]visor.TransactionInput, bool, error) {
 	ret := _m.Called(txn, signed)
@@ -1391,6 +2065,27 @@ func (_m *MockGatewayer) VerifyTxnVerbose(txn *coin.Transaction, signed visor.Tx
 	return r0, r1, r2
 }
 
+// VerifyWalletSeed provides a mock function with given fields: wltID, seed, seedPassphrase
+func (_m *MockGatewayer) VerifyWalletSeed(wltID string, seed string, seedPassphrase string) (bool, error) {
+	ret := _m.Called(wltID, seed, seedPassphrase)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(wltID, seed, seedPassphrase)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(wltID, seed, seedPassphrase)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VisorConfig provides a mock function with given fields:
 func (_m *MockGatewayer) VisorConfig() visor.Config {
 	ret := _m.Called()
@@ -1521,3 +2216,35 @@ func (_m *MockGatewayer) WalletSignTransaction(wltID string, password []byte, tx
 
 	return r0, r1, r2
 }
+
+// WalletsCreateTransactionSigned provides a mock function with given fields: spends, p, ignoreUnconfirmed
+func (_m *MockGatewayer) WalletsCreateTransactionSigned(spends []visor.WalletSpend, p transaction.Params, ignoreUnconfirmed bool) (*coin.Transaction, []visor.TransactionInput, error) {
+	ret := _m.Called(spends, p, ignoreUnconfirmed)
+
+	var r0 *coin.Transaction
+	if rf, ok := ret.Get(0).(func([]visor.WalletSpend, transaction.Params, bool) *coin.Transaction); ok {
+		r0 = rf(spends, p, ignoreUnconfirmed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coin.Transaction)
+		}
+	}
+
+	var r1 []visor.TransactionInput
+	if rf, ok := ret.Get(1).(func([]visor.WalletSpend, transaction.Params, bool) []visor.TransactionInput); ok {
+		r1 = rf(spends, p, ignoreUnconfirmed)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]visor.TransactionInput)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func([]visor.WalletSpend, transaction.Params, bool) error); ok {
+		r2 = rf(spends, p, ignoreUnconfirmed)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
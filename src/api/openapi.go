@@ -0,0 +1,451 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	wh "github.com/skycoin/skycoin/src/util/http"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification of the document served at /api/v2/spec
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPI is an OpenAPI 3 document, limited to the fields used to describe this API
+type OpenAPI struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents          `json:"components"`
+	Security   []map[string][]string      `json:"security,omitempty"`
+}
+
+// OpenAPIInfo is the info object of an OpenAPI document
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIPathItem maps the lowercase methods of a path to their operations
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation describes an endpoint method
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	// APISets are the API sets that enable the endpoint method, see EndpointsRead etc.
+	APISets []string `json:"x-api-sets,omitempty"`
+}
+
+// OpenAPIParameter is a query parameter of an operation
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody is the request body of an operation
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an operation
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a request or response content type
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIComponents holds the schemas of the named types and the security schemes
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+// OpenAPISecurityScheme is an HTTP authentication scheme
+type OpenAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// OpenAPISchema is the schema of a JSON value
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+}
+
+// apiRoute is an endpoint registered in the server mux
+type apiRoute struct {
+	apiVersion    string
+	endpoint      string
+	methodAPISets map[string][]string
+}
+
+// endpointDoc documents the parameters and responses of an endpoint method, see endpointDocs
+type endpointDoc struct {
+	summary string
+	// params are query parameters of GET and DELETE requests and form fields of v1 POST requests
+	params []paramDoc
+	// body is a value of the JSON request body type
+	body interface{}
+	// response is a value of the response type, or a oneOf of the response types
+	response interface{}
+	// raw is set if the v2 response is not wrapped in an HTTPResponse
+	raw bool
+}
+
+// paramDoc documents a query parameter or form field
+type paramDoc struct {
+	name        string
+	typ         string
+	description string
+	required    bool
+}
+
+// oneOf documents the alternative types of a response, e.g. of verbose requests
+type oneOf []interface{}
+
+// lookupEndpointDoc returns the doc of an endpoint method, documented by method and path or by path only
+func lookupEndpointDoc(method, endpoint string) (endpointDoc, bool) {
+	if d, ok := endpointDocs[method+" "+endpoint]; ok {
+		return d, true
+	}
+	d, ok := endpointDocs[endpoint]
+	return d, ok
+}
+
+// routeMethods returns the methods of a route, sorted.
+// Routes without API sets are always enabled and only document GET.
+func routeMethods(r apiRoute) []string {
+	if r.methodAPISets == nil {
+		return []string{http.MethodGet}
+	}
+
+	methods := make([]string, 0, len(r.methodAPISets))
+	for m := range r.methodAPISets {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// newOpenAPISpec builds the OpenAPI document of the registered routes
+func newOpenAPISpec(c muxConfig, routes []apiRoute) *OpenAPI {
+	b := &schemaBuilder{
+		schemas: make(map[string]*OpenAPISchema),
+	}
+
+	spec := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   "Skycoin node REST API",
+			Version: c.health.BuildInfo.Version,
+		},
+		Paths: make(map[string]OpenAPIPathItem, len(routes)),
+		Components: OpenAPIComponents{
+			Schemas: b.schemas,
+			SecuritySchemes: map[string]OpenAPISecurityScheme{
+				"basicAuth": {
+					Type:   "http",
+					Scheme: "basic",
+				},
+				"bearerAuth": {
+					Type:   "http",
+					Scheme: "bearer",
+				},
+			},
+		},
+	}
+
+	if c.username != "" || c.password != "" || len(c.tokens) != 0 {
+		spec.Security = []map[string][]string{
+			{"basicAuth": {}},
+			{"bearerAuth": {}},
+		}
+	}
+
+	errorSchema := b.schemaOf(reflect.TypeOf(HTTPResponse{}))
+
+	for _, r := range routes {
+		item := make(OpenAPIPathItem)
+
+		for _, method := range routeMethods(r) {
+			doc, _ := lookupEndpointDoc(method, r.endpoint)
+
+			op := &OpenAPIOperation{
+				OperationID: operationID(method, r.endpoint),
+				Summary:     doc.summary,
+				APISets:     r.methodAPISets[method],
+				Tags:        []string{r.apiVersion},
+				Responses:   make(map[string]OpenAPIResponse),
+			}
+
+			if method == http.MethodPost && r.apiVersion == apiVersion1 && doc.body == nil && len(doc.params) != 0 {
+				form := &OpenAPISchema{
+					Type:       "object",
+					Properties: make(map[string]*OpenAPISchema, len(doc.params)),
+				}
+				for _, p := range doc.params {
+					form.Properties[p.name] = paramSchema(p)
+				}
+				op.RequestBody = &OpenAPIRequestBody{
+					Content: map[string]OpenAPIMediaType{
+						ContentTypeForm: {Schema: form},
+					},
+				}
+			} else {
+				for _, p := range doc.params {
+					op.Parameters = append(op.Parameters, OpenAPIParameter{
+						Name:        p.name,
+						In:          "query",
+						Description: p.description,
+						Required:    p.required,
+						Schema:      paramSchema(p),
+					})
+				}
+			}
+
+			if doc.body != nil {
+				op.RequestBody = &OpenAPIRequestBody{
+					Required: true,
+					Content: map[string]OpenAPIMediaType{
+						ContentTypeJSON: {Schema: b.schemaOf(reflect.TypeOf(doc.body))},
+					},
+				}
+			}
+
+			var respSchema *OpenAPISchema
+			if types, ok := doc.response.(oneOf); ok {
+				respSchema = &OpenAPISchema{}
+				for _, t := range types {
+					respSchema.OneOf = append(respSchema.OneOf, b.schemaOf(reflect.TypeOf(t)))
+				}
+			} else {
+				respSchema = b.schemaOf(reflect.TypeOf(doc.response))
+			}
+
+			switch {
+			case r.apiVersion == apiVersion1, doc.raw:
+				op.Responses["200"] = OpenAPIResponse{
+					Description: "OK",
+					Content: map[string]OpenAPIMediaType{
+						ContentTypeJSON: {Schema: respSchema},
+					},
+				}
+				if r.apiVersion == apiVersion1 {
+					op.Responses["default"] = OpenAPIResponse{
+						Description: "Error, as text in the format \"<code> <status> - <message>\"",
+					}
+				} else {
+					op.Responses["default"] = OpenAPIResponse{
+						Description: "Error",
+						Content: map[string]OpenAPIMediaType{
+							ContentTypeJSON: {Schema: errorSchema},
+						},
+					}
+				}
+			default:
+				op.Responses["200"] = OpenAPIResponse{
+					Description: "OK",
+					Content: map[string]OpenAPIMediaType{
+						ContentTypeJSON: {Schema: &OpenAPISchema{
+							Type: "object",
+							Properties: map[string]*OpenAPISchema{
+								"data": respSchema,
+							},
+						}},
+					},
+				}
+				op.Responses["default"] = OpenAPIResponse{
+					Description: "Error",
+					Content: map[string]OpenAPIMediaType{
+						ContentTypeJSON: {Schema: errorSchema},
+					},
+				}
+			}
+
+			item[strings.ToLower(method)] = op
+		}
+
+		spec.Paths[r.endpoint] = item
+	}
+
+	return spec
+}
+
+// operationID returns an identifier of an endpoint method, e.g. getApiV1WalletBalance
+func operationID(method, endpoint string) string {
+	words := strings.FieldsFunc(endpoint, func(r rune) bool {
+		return r == '/' || r == '_' || r == '-'
+	})
+
+	id := strings.ToLower(method)
+	for _, w := range words {
+		id += strings.ToUpper(w[:1]) + w[1:]
+	}
+	return id
+}
+
+func paramSchema(p paramDoc) *OpenAPISchema {
+	typ := p.typ
+	if typ == "" {
+		typ = "string"
+	}
+	return &OpenAPISchema{
+		Type:        typ,
+		Description: p.description,
+	}
+}
+
+// schemaBuilder builds the schemas of Go types from their JSON encoding.
+// Named struct types are added to schemas and referenced.
+type schemaBuilder struct {
+	schemas map[string]*OpenAPISchema
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+func (b *schemaBuilder) schemaOf(t reflect.Type) *OpenAPISchema {
+	if t == nil {
+		// Any value
+		return &OpenAPISchema{}
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType), reflect.PtrTo(t).Implements(jsonMarshalerType),
+		t.Implements(textMarshalerType), reflect.PtrTo(t).Implements(textMarshalerType):
+		// The custom JSON types of this API are encoded as strings, see util/http
+		return &OpenAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := b.schemas[name]; !ok {
+			// Add a placeholder first, for recursive types
+			b.schemas[name] = &OpenAPISchema{}
+			*b.schemas[name] = *b.structSchema(t)
+		}
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema),
+	}
+	b.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of a struct to the properties of s, including the fields of embedded structs
+func (b *schemaBuilder) addFields(s *OpenAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(s, ft)
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if strings.Contains(tag, ",string") {
+			s.Properties[name] = &OpenAPISchema{Type: "string"}
+			continue
+		}
+
+		s.Properties[name] = b.schemaOf(f.Type)
+	}
+}
+
+// specHandler returns the OpenAPI document of the API
+// URI: /api/v2/spec
+// Method: GET
+func specHandler(spec *OpenAPI) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wh.SendJSONOr500(logger, w, spec)
+	}
+}
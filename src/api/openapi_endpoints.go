@@ -0,0 +1,667 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/notify"
+	"github.com/skycoin/skycoin/src/readable"
+)
+
+// Common parameters of the endpoint docs
+var (
+	verboseParam = paramDoc{
+		name:        "verbose",
+		typ:         "boolean",
+		description: "include the inputs of the transactions",
+	}
+	transactionsFormatParam = paramDoc{
+		name:        "format",
+		description: "json or csv, defaults to csv if the Accept header is text/csv. The csv has a row for each address whose balance is changed by a transaction",
+	}
+	walletIDParam = paramDoc{
+		name:        "id",
+		description: "wallet id",
+		required:    true,
+	}
+	walletPasswordParam = paramDoc{
+		name:        "password",
+		description: "wallet password, required if the wallet is encrypted",
+	}
+	transactionsParams = []paramDoc{
+		{
+			name:        "addrs",
+			description: "comma-separated list of addresses, returns the transactions of the addresses",
+		},
+		{
+			name:        "confirmed",
+			typ:         "boolean",
+			description: "returns only the confirmed or unconfirmed transactions if set",
+		},
+		verboseParam,
+		{
+			name:        "page",
+			typ:         "integer",
+			description: "page number",
+		},
+		{
+			name:        "limit",
+			typ:         "integer",
+			description: "number of transactions per page, defaults to 10, must be <= 100",
+		},
+		{
+			name:        "cursor",
+			description: "next_cursor of the previous page, replaces page",
+		},
+		{
+			name:        "sort",
+			description: "sort the transactions by block seq, asc or desc",
+		},
+	}
+	storageTypeParam = paramDoc{
+		name:        "type",
+		description: "storage type, txid or client",
+		required:    true,
+	}
+	webhookIDParam = paramDoc{
+		name:        "id",
+		description: "webhook id",
+		required:    true,
+	}
+)
+
+// endpointDocs documents the parameters and responses of the endpoints registered in newServerMux.
+// The keys are the endpoint paths, or "<method> <path>" if the methods of a path differ.
+// TestOpenAPISpecEndpointDocs checks that the docs match the registered endpoints.
+var endpointDocs = map[string]endpointDoc{
+	"/api/v1/csrf": {
+		summary:  "Returns a CSRF token for POST requests, in the X-CSRF-Token header",
+		response: map[string]string{},
+	},
+	"/api/v1/version": {
+		summary:  "Returns the version of the node",
+		response: readable.BuildInfo{},
+	},
+	"/api/v1/health": {
+		summary:  "Returns the health of the node",
+		response: HealthResponse{},
+	},
+	"/api/v2/health": {
+		summary: "Returns the health of the node and its dependencies, with a 503 status if the node is not ready",
+		params: []paramDoc{
+			{name: "max_blocks_behind", typ: "integer", description: "the number of blocks the node can be behind its peers and be ready, defaults to 0"},
+		},
+		response: HealthV2Response{},
+	},
+
+	// Wallet endpoints
+	"/api/v1/wallet": {
+		summary:  "Returns a wallet",
+		params:   []paramDoc{walletIDParam},
+		response: WalletResponse{},
+	},
+	"/api/v1/wallet/create": {
+		summary: "Creates a wallet",
+		params: []paramDoc{
+			{name: "type", description: "wallet type, deterministic, bip44, xpub or collection-watch", required: true},
+			{name: "label", description: "wallet label", required: true},
+			{name: "seed", description: "wallet seed, required for deterministic and bip44 wallets"},
+			{name: "seed-passphrase", description: "seed passphrase of bip44 wallets"},
+			{name: "seed-language", description: "bip39 wordlist language of the seed, detected if not set"},
+			{name: "seed-entropy", description: "entropy derivation description returned by /api/v1/wallet/newSeed"},
+			{name: "try-seed-passphrase", typ: "boolean", description: "creates the wallet without the seed passphrase if only those addresses have transactions"},
+			{name: "bip44-coin", typ: "integer", description: "bip44 coin type, defaults to 8000"},
+			{name: "xpub", description: "xpub key, required for xpub wallets"},
+			{name: "xpub-account", typ: "boolean", description: "the xpub is a bip44 account key"},
+			{name: "addresses", description: "comma-separated addresses to watch, required for collection-watch wallets"},
+			{name: "scan", typ: "integer", description: "number of addresses to scan ahead for balances"},
+			{name: "encrypt", typ: "boolean", description: "encrypt the wallet"},
+			{name: "password", description: "password to encrypt the wallet, required if encrypt is set"},
+		},
+		response: WalletResponse{},
+	},
+	"/api/v1/wallet/newAddress": {
+		summary: "Generates new addresses in a wallet",
+		params: []paramDoc{
+			walletIDParam,
+			{name: "num", typ: "integer", description: "number of addresses to generate, defaults to 1"},
+			walletPasswordParam,
+		},
+		response: struct {
+			Addresses []string `json:"addresses"`
+		}{},
+	},
+	"/api/v1/wallet/scan": {
+		summary: "Scans ahead the addresses of a wallet for balances",
+		params: []paramDoc{
+			walletIDParam,
+			{name: "num", typ: "integer", description: "number of addresses to scan ahead, defaults to 20"},
+			walletPasswordParam,
+		},
+		response: struct {
+			Addresses []string `json:"addresses"`
+		}{},
+	},
+	"/api/v1/wallet/balance": {
+		summary:  "Returns the confirmed and predicted balance of a wallet and its addresses",
+		params:   []paramDoc{walletIDParam},
+		response: BalanceResponse{},
+	},
+	"/api/v1/wallet/transaction": {
+		summary:  "Creates a transaction spending the outputs of a wallet",
+		body:     WalletCreateTransactionRequest{},
+		response: CreateTransactionResponse{},
+	},
+	"/api/v2/wallet/transaction/sign": {
+		summary:  "Signs the inputs of a transaction with the keys of a wallet",
+		body:     WalletSignTransactionRequest{},
+		response: CreateTransactionResponse{},
+	},
+	"/api/v1/wallet/transactions": {
+		summary:  "Returns the unconfirmed transactions of a wallet",
+		params:   []paramDoc{walletIDParam, verboseParam},
+		response: oneOf{UnconfirmedTxnsResponse{}, UnconfirmedTxnsVerboseResponse{}},
+	},
+	"/api/v1/wallet/update": {
+		summary: "Changes the label of a wallet",
+		params: []paramDoc{
+			walletIDParam,
+			{name: "label", description: "new label", required: true},
+		},
+		response: "",
+	},
+	"/api/v1/wallets": {
+		summary:  "Returns the loaded wallets",
+		response: []WalletResponse{},
+	},
+	"/api/v1/wallets/folderName": {
+		summary:  "Returns the wallet directory",
+		response: WalletFolder{},
+	},
+	"/api/v2/wallets/transaction": {
+		summary:  "Creates a transaction spending the outputs of several wallets, signed by each wallet",
+		body:     WalletsCreateTransactionRequest{},
+		response: CreateTransactionResponse{},
+	},
+	"/api/v1/wallet/newSeed": {
+		summary: "Generates a bip39 seed",
+		params: []paramDoc{
+			{name: "entropy", typ: "integer", description: "entropy bit size, 128 or 256, defaults to 128"},
+			{name: "language", description: "bip39 wordlist language, defaults to english"},
+			{name: "user-entropy", description: "user entropy mixed with the random entropy, e.g. dice rolls"},
+			{name: "user-entropy-type", description: "format of user-entropy, dice or hex, defaults to dice"},
+		},
+		response: struct {
+			Seed               string `json:"seed"`
+			EntropyDescription string `json:"entropy_description,omitempty"`
+		}{},
+	},
+	"/api/v1/wallet/seed": {
+		summary:  "Returns the seed of an encrypted wallet",
+		params:   []paramDoc{walletIDParam, walletPasswordParam},
+		response: WalletSeedResponse{},
+	},
+	"/api/v2/wallet/seed/verify": {
+		summary:  "Verifies that a seed is a valid bip39 mnemonic",
+		body:     VerifySeedRequest{},
+		response: struct{}{},
+	},
+	"/api/v2/wallet/seed-passphrase/verify": {
+		summary:  "Verifies the seed passphrase of a bip44 wallet",
+		body:     VerifySeedPassphraseRequest{},
+		response: struct{}{},
+	},
+	"/api/v2/wallet/seed/match": {
+		summary:  "Returns whether a seed and seed passphrase are the ones of a wallet, to validate a backup of the seed",
+		body:     WalletSeedMatchRequest{},
+		response: WalletSeedMatchResponse{},
+	},
+	"/api/v2/wallet/message/sign": {
+		summary:  "Signs a message with the secret key of a wallet address",
+		body:     WalletSignMessageRequest{},
+		response: WalletSignMessageResponse{},
+	},
+	"/api/v1/wallet/unload": {
+		summary: "Unloads a wallet",
+		params:  []paramDoc{walletIDParam},
+	},
+	"/api/v1/wallet/encrypt": {
+		summary:  "Encrypts a wallet",
+		params:   []paramDoc{walletIDParam, walletPasswordParam},
+		response: WalletResponse{},
+	},
+	"/api/v1/wallet/decrypt": {
+		summary:  "Decrypts a wallet",
+		params:   []paramDoc{walletIDParam, walletPasswordParam},
+		response: WalletResponse{},
+	},
+	"/api/v2/wallet/recover": {
+		summary:  "Recovers an encrypted wallet with its seed",
+		body:     WalletRecoverRequest{},
+		response: WalletResponse{},
+	},
+	"/api/v2/wallet/unlock": {
+		summary:  "Starts an unlock session of an encrypted wallet, which signs transactions without the password until the session expires",
+		body:     WalletUnlockRequest{},
+		response: WalletUnlockResponse{},
+	},
+	"/api/v2/wallet/lock": {
+		summary: "Ends the unlock session of a wallet",
+		body:    WalletLockRequest{},
+	},
+	"/api/v2/wallet/labels": {
+		summary:  "Returns the labels, notes and tags of the addresses of a wallet",
+		params:   []paramDoc{walletIDParam},
+		response: WalletLabelsResponse{},
+	},
+	"/api/v2/wallet/labels/import": {
+		summary:  "Sets the labels, notes and tags of the addresses of a wallet",
+		body:     WalletLabelsImportRequest{},
+		response: WalletLabelsImportResponse{},
+	},
+
+	// Blockchain endpoints
+	"/api/v1/blockchain/metadata": {
+		summary:  "Returns the blockchain metadata",
+		response: readable.BlockchainMetadata{},
+	},
+	"/api/v1/blockchain/progress": {
+		summary:  "Returns the blockchain sync progress",
+		response: readable.BlockchainProgress{},
+	},
+	"/api/v1/block": {
+		summary: "Returns a block by hash or seq",
+		params: []paramDoc{
+			{name: "hash", description: "block hash, only one of hash or seq is allowed"},
+			{name: "seq", typ: "integer", description: "block seq, only one of hash or seq is allowed"},
+			verboseParam,
+		},
+		response: oneOf{readable.Block{}, readable.BlockVerbose{}},
+	},
+	"/api/v1/blocks": {
+		summary: "Returns blocks in a seq range or by seqs",
+		params: []paramDoc{
+			{name: "start", typ: "integer", description: "first block seq of the range"},
+			{name: "end", typ: "integer", description: "last block seq of the range"},
+			{name: "seqs", description: "comma-separated list of block seqs, not allowed with start and end"},
+			verboseParam,
+		},
+		response: oneOf{readable.Blocks{}, readable.BlocksVerbose{}},
+	},
+	"/api/v1/last_blocks": {
+		summary: "Returns the last N blocks",
+		params: []paramDoc{
+			{name: "num", typ: "integer", description: "number of blocks", required: true},
+			verboseParam,
+		},
+		response: oneOf{readable.Blocks{}, readable.BlocksVerbose{}},
+	},
+
+	// Network endpoints
+	"/api/v1/network/connection": {
+		summary: "Returns a connection",
+		params: []paramDoc{
+			{name: "addr", description: "ip:port address of the connection", required: true},
+		},
+		response: readable.Connection{},
+	},
+	"/api/v1/network/connections": {
+		summary: "Returns the connections",
+		params: []paramDoc{
+			{name: "states", description: "comma-separated list of connection states, pending, connected or introduced. Defaults to connected,introduced"},
+			{name: "direction", description: "outgoing or incoming, both if not set"},
+		},
+		response: Connections{},
+	},
+	"/api/v1/network/defaultConnections": {
+		summary:  "Returns the default bootstrap peers",
+		response: []string{},
+	},
+	"/api/v1/network/connections/trust": {
+		summary:  "Returns the trusted peers",
+		response: []string{},
+	},
+	"/api/v1/network/connections/exchange": {
+		summary:  "Returns the peers found through peer exchange",
+		response: []string{},
+	},
+	"/api/v1/network/connection/disconnect": {
+		summary: "Disconnects a connection",
+		params: []paramDoc{
+			{name: "id", typ: "integer", description: "id of the connection", required: true},
+		},
+		response: struct{}{},
+	},
+
+	// Transaction endpoints
+	"/api/v1/pendingTxs": {
+		summary:  "Returns the unconfirmed transactions",
+		params:   []paramDoc{verboseParam, transactionsFormatParam},
+		response: oneOf{[]readable.UnconfirmedTransactions{}, []readable.UnconfirmedTransactionVerbose{}},
+	},
+	"/api/v1/transaction": {
+		summary: "Returns a transaction",
+		params: []paramDoc{
+			{name: "txid", description: "transaction hash", required: true},
+			verboseParam,
+			{name: "encoded", typ: "boolean", description: "return the encoded transaction"},
+		},
+		response: oneOf{readable.TransactionWithStatus{}, readable.TransactionWithStatusVerbose{}, TransactionEncodedResponse{}},
+	},
+	"/api/v2/transaction": {
+		summary:  "Creates an unsigned transaction from addresses or unspent outputs",
+		body:     CreateTransactionRequest{},
+		response: CreateTransactionResponse{},
+	},
+	"/api/v2/transaction/verify": {
+		summary:  "Decodes and verifies an encoded transaction",
+		body:     VerifyTransactionRequest{},
+		response: VerifyTransactionResponse{},
+	},
+	"/api/v2/transaction/decode": {
+		summary:  "Decodes an encoded transaction, annotated with its inputs, totals, required fee and policy violations",
+		body:     DecodeTransactionRequest{},
+		response: DecodeTransactionResponse{},
+	},
+	"/api/v2/transaction/estimate": {
+		summary:  "Estimates the fee, change and validity of a transaction from a wallet, addresses or unspent outputs, without creating it",
+		body:     EstimateTransactionRequest{},
+		response: EstimateTransactionResponse{},
+	},
+	"/api/v2/transaction/export": {
+		summary:  "Creates an unsigned transaction from addresses or unspent outputs, with its inputs and fee fixed, to sign offline",
+		body:     CreateTransactionRequest{},
+		response: UnsignedTransactionExport{},
+	},
+	"/api/v2/transaction/import": {
+		summary:  "Verifies a transaction signed offline against the inner hash of the exported transaction and injects it",
+		body:     ImportTransactionRequest{},
+		response: ImportTransactionResponse{},
+	},
+	"/api/v2/transaction/status": {
+		summary: "Returns the status of a transaction: unknown, pending, confirmed or rejected, and the number of peers it was sent to",
+		params: []paramDoc{
+			{name: "txid", description: "transaction hash", required: true},
+		},
+		response: TransactionStatusResponse{},
+	},
+	"/api/v2/transaction/rebroadcast": {
+		summary:  "Broadcasts a transaction of the unconfirmed pool to all peers",
+		body:     RebroadcastTransactionRequest{},
+		response: RebroadcastTransactionResponse{},
+	},
+	"/api/v2/mempool": {
+		summary: "Returns the transactions of the unconfirmed pool with their age, propagation, fee and verification status",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses, returns the transactions with an input or output of one of them"},
+		},
+		response: MempoolResponse{},
+	},
+	"/api/v2/mempool/evictions": {
+		summary:  "Returns the transactions evicted from the unconfirmed pool since the node started",
+		response: MempoolEvictionsResponse{},
+	},
+	"GET /api/v2/graphql": {
+		summary: "Executes a GraphQL query over blocks, transactions, outputs and addresses. The response is the standard GraphQL response, not wrapped in data",
+		params: []paramDoc{
+			{name: "query", description: "GraphQL query", required: true},
+			{name: "operationName", description: "name of the operation to execute, if the query has several operations"},
+			{name: "variables", description: "JSON object of the variables of the query"},
+		},
+		response: GraphQLResponse{},
+	},
+	"POST /api/v2/graphql": {
+		summary:  "Executes a GraphQL query over blocks, transactions, outputs and addresses. The response is the standard GraphQL response, not wrapped in data",
+		body:     GraphQLRequest{},
+		response: GraphQLResponse{},
+	},
+	"/api/v1/transactions": {
+		summary:  "Returns transactions, paginated if page, limit or cursor is set. The page info is in the X-Total-Count, X-Total-Pages and X-Next-Cursor headers",
+		params:   append(transactionsParams[:len(transactionsParams):len(transactionsParams)], transactionsFormatParam),
+		response: oneOf{[]readable.TransactionWithStatus{}, []readable.TransactionWithStatusVerbose{}},
+	},
+	"/api/v2/transactions": {
+		summary:  "Returns a page of transactions",
+		params:   transactionsParams,
+		response: oneOf{TransactionsWithStatusV2{}, TransactionsWithStatusVerboseV2{}},
+	},
+	"/api/v1/injectTransaction": {
+		summary:  "Broadcasts an encoded transaction, returns its hash",
+		body:     InjectTransactionRequest{},
+		response: "",
+	},
+	"/api/v1/resendUnconfirmedTxns": {
+		summary:  "Broadcasts the unconfirmed transactions",
+		response: ResendResult{},
+	},
+	"/api/v1/rawtx": {
+		summary: "Returns the hex-encoded serialization of a transaction",
+		params: []paramDoc{
+			{name: "txid", description: "transaction hash", required: true},
+		},
+		response: "",
+	},
+
+	// Unspent output endpoints
+	"/api/v1/outputs": {
+		summary: "Returns the unspent outputs of addresses or by hashes, all unspent outputs if neither is set",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses"},
+			{name: "hashes", description: "comma-separated list of unspent output hashes"},
+		},
+		response: readable.UnspentOutputsSummary{},
+	},
+	"/api/v1/balance": {
+		summary: "Returns the confirmed and predicted balance of addresses",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses", required: true},
+		},
+		response: BalanceResponse{},
+	},
+	"/api/v2/balance": {
+		summary:  "Returns the confirmed and predicted balance of up to 10000 addresses",
+		body:     BalanceRequest{},
+		response: BalanceResponse{},
+	},
+	"/api/v1/uxout": {
+		summary: "Returns an unspent or spent output",
+		params: []paramDoc{
+			{name: "uxid", description: "output hash", required: true},
+		},
+		response: readable.SpentOutput{},
+	},
+	"/api/v1/address_uxouts": {
+		summary: "Returns the spent outputs of an address",
+		params: []paramDoc{
+			{name: "address", required: true},
+		},
+		response: []readable.SpentOutput{},
+	},
+
+	// Address endpoints
+	"/api/v2/address/verify": {
+		summary:  "Verifies an address",
+		body:     VerifyAddressRequest{},
+		response: VerifyAddressResponse{},
+	},
+	"/api/v2/address/message/verify": {
+		summary:  "Verifies that a message was signed by the secret key of an address",
+		body:     VerifyMessageRequest{},
+		response: struct{}{},
+	},
+	"/api/v2/address/balance-at": {
+		summary: "Returns the balance of an address after the block at a height or time was executed",
+		params: []paramDoc{
+			{name: "address", description: "address", required: true},
+			{name: "height", typ: "integer", description: "the seq of the block, required if timestamp is not set"},
+			{name: "timestamp", typ: "integer", description: "unix time, the block is the last block created at or before the time, required if height is not set"},
+		},
+		response: AddressBalanceAtResponse{},
+	},
+
+	// Explorer endpoints
+	"/api/v1/coinSupply": {
+		summary:  "Returns the coin supply",
+		response: CoinSupply{},
+	},
+	"/api/v1/richlist": {
+		summary: "Returns the addresses with the most coins",
+		params: []paramDoc{
+			{name: "n", typ: "integer", description: "number of addresses per page, defaults to 20, all if 0"},
+			{name: "page", typ: "integer", description: "page number, starting from 1, requires n"},
+			{name: "include-distribution", typ: "boolean", description: "include the distribution addresses"},
+			{name: "exclude", description: "comma-separated list of addresses to leave out of the richlist"},
+		},
+		response: Richlist{},
+	},
+	"/api/v1/addresscount": {
+		summary:  "Returns the number of addresses with coins",
+		response: map[string]uint64{},
+	},
+
+	// Streaming endpoints
+	"/api/v2/ws": {
+		summary: "Upgrades to a websocket streaming the blockchain events as WSEvent messages",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses to subscribe to"},
+			{name: "wallets", description: "comma-separated list of wallet ids to subscribe to"},
+		},
+		response: WSEvent{},
+		raw:      true,
+	},
+	"/api/v2/events": {
+		summary: "Streams the blockchain events of /api/v2/ws as server-sent events, resumed with the Last-Event-ID header",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses to subscribe to"},
+			{name: "wallets", description: "comma-separated list of wallet ids to subscribe to"},
+			{name: "watch", typ: "boolean", description: "subscribe to the addresses of the watch list"},
+			{name: "last_event_id", typ: "integer", description: "the id of the last event received, replaces the Last-Event-ID header"},
+		},
+		response: WSEvent{},
+		raw:      true,
+	},
+
+	// Storage endpoints
+	http.MethodGet + " /api/v2/data": {
+		summary: "Returns a value of a storage, or all its values if key is not set",
+		params: []paramDoc{
+			storageTypeParam,
+			{name: "key", description: "key of the value"},
+		},
+		response: oneOf{"", map[string]string{}},
+	},
+	http.MethodPost + " /api/v2/data": {
+		summary: "Sets a value of a storage",
+		body:    StorageRequest{},
+	},
+	http.MethodDelete + " /api/v2/data": {
+		summary: "Removes a value of a storage",
+		params: []paramDoc{
+			storageTypeParam,
+			{name: "key", description: "key of the value", required: true},
+		},
+	},
+
+	// Webhook endpoints
+	http.MethodGet + " /api/v2/webhooks": {
+		summary:  "Returns the registered webhooks, without their secrets",
+		response: []notify.Webhook{},
+	},
+	http.MethodPost + " /api/v2/webhooks": {
+		summary:  "Registers a webhook, the response includes the secret that signs its payloads",
+		body:     WebhookRequest{},
+		response: notify.Webhook{},
+	},
+	http.MethodDelete + " /api/v2/webhooks": {
+		summary: "Removes a webhook",
+		params: []paramDoc{
+			webhookIDParam,
+		},
+	},
+	"/api/v2/webhooks/deliveries": {
+		summary: "Returns the recent deliveries of a webhook, newest first",
+		params: []paramDoc{
+			webhookIDParam,
+		},
+		response: []notify.Delivery{},
+	},
+	http.MethodGet + " /api/v2/watch": {
+		summary:  "Returns the addresses of the watch list",
+		response: WatchListResponse{},
+	},
+	http.MethodPost + " /api/v2/watch": {
+		summary:  "Adds addresses to the watch list, returns the watched addresses",
+		body:     WatchListRequest{},
+		response: WatchListResponse{},
+	},
+	http.MethodDelete + " /api/v2/watch": {
+		summary: "Removes addresses from the watch list, returns the watched addresses",
+		params: []paramDoc{
+			{name: "addrs", description: "comma-separated list of addresses", required: true},
+		},
+		response: WatchListResponse{},
+	},
+	"/api/v2/watch/transactions": {
+		summary:  "Returns a page of the transactions of the watched addresses",
+		params:   transactionsParams[1:],
+		response: oneOf{TransactionsWithStatusV2{}, TransactionsWithStatusVerboseV2{}},
+	},
+
+	// Admin endpoints
+	http.MethodGet + " /api/v2/admin/db/verify": {
+		summary:  "Returns the state of the last DB verification",
+		response: DBVerificationResponse{},
+	},
+	http.MethodPost + " /api/v2/admin/db/verify": {
+		summary:  "Starts a verification of the block signatures and the history of the DB in the background",
+		response: DBVerificationResponse{},
+	},
+	"/api/v2/admin/chain/verify": {
+		summary:  "Verifies the block headers of the DB from the genesis block up to a trusted checkpoint",
+		body:     ChainVerifyRequest{},
+		response: ChainVerificationResponse{},
+	},
+	"/api/v2/admin/log/rotate": {
+		summary:  "Closes the log file and continues logging to a new one",
+		response: LogRotateResponse{},
+	},
+	http.MethodGet + " /api/v2/admin/log/level": {
+		summary:  "Returns the log level of the node",
+		response: LogLevelResponse{},
+	},
+	http.MethodPost + " /api/v2/admin/log/level": {
+		summary:  "Sets the log level of the node until it is restarted",
+		body:     LogLevelRequest{},
+		response: LogLevelResponse{},
+	},
+	"/api/v2/admin/peers/reconnect": {
+		summary:  "Disconnects all outgoing connections, which are replaced by new outgoing connections",
+		response: PeersReconnectResponse{},
+	},
+	http.MethodGet + " /api/v2/admin/peers/ban": {
+		summary:  "Returns the IP addresses banned by the node operator",
+		response: []BannedPeer{},
+	},
+	http.MethodPost + " /api/v2/admin/peers/ban": {
+		summary:  "Bans an IP address until the node is restarted and disconnects its connections",
+		body:     PeerBanRequest{},
+		response: PeerBanResponse{},
+	},
+	http.MethodDelete + " /api/v2/admin/peers/ban": {
+		summary: "Lifts the ban of an IP address",
+		params: []paramDoc{
+			{
+				name:        "ip",
+				description: "banned IP address",
+				required:    true,
+			},
+		},
+	},
+	"/api/v2/admin/shutdown": {
+		summary: "Shuts down the node cleanly, after the response is sent",
+	},
+
+	"/api/v2/spec": {
+		summary: "Returns this OpenAPI document",
+		raw:     true,
+	},
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getOpenAPISpec(t *testing.T, cfg muxConfig) OpenAPI {
+	req, err := http.NewRequest(http.MethodGet, "/api/v2/spec", nil)
+	require.NoError(t, err)
+	if cfg.username != "" || cfg.password != "" {
+		req.SetBasicAuth(cfg.username, cfg.password)
+	}
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(cfg, &MockGatewayer{})
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var spec OpenAPI
+	err = json.NewDecoder(rr.Body).Decode(&spec)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestOpenAPISpecEndpointDocs(t *testing.T) {
+	spec := getOpenAPISpec(t, defaultMuxConfig())
+
+	// Every registered endpoint method is documented
+	for p, item := range spec.Paths {
+		require.NotEmpty(t, item, p)
+		for method, op := range item {
+			_, ok := lookupEndpointDoc(strings.ToUpper(method), p)
+			require.True(t, ok, "%s %s is not documented in endpointDocs", method, p)
+			require.NotEmpty(t, op.Summary, "%s %s", method, p)
+		}
+	}
+
+	// Every documented endpoint is registered
+	for k := range endpointDocs {
+		method := ""
+		p := k
+		if pts := strings.SplitN(k, " ", 2); len(pts) == 2 {
+			method = strings.ToLower(pts[0])
+			p = pts[1]
+		}
+
+		item, ok := spec.Paths[p]
+		require.True(t, ok, "%s is documented but not registered", k)
+		if method != "" {
+			_, ok := item[method]
+			require.True(t, ok, "%s is documented but not registered", k)
+		}
+	}
+
+	// The endpoints of the http tests are in the spec
+	for _, e := range allEndpoints() {
+		_, ok := spec.Paths[e]
+		require.True(t, ok, e)
+	}
+}
+
+func TestOpenAPISpecHandler(t *testing.T) {
+	cfg := defaultMuxConfig()
+	cfg.health.BuildInfo.Version = "0.27.0"
+	spec := getOpenAPISpec(t, cfg)
+
+	require.Equal(t, OpenAPIVersion, spec.OpenAPI)
+	require.Equal(t, "0.27.0", spec.Info.Version)
+	require.Empty(t, spec.Security)
+
+	// Query parameters and v1 response
+	op := spec.Paths["/api/v1/wallet/balance"]["get"]
+	require.NotNil(t, op)
+	require.Equal(t, "getApiV1WalletBalance", op.OperationID)
+	require.Equal(t, []string{EndpointsWallet}, op.APISets)
+	require.Equal(t, []OpenAPIParameter{
+		{
+			Name:        "id",
+			In:          "query",
+			Description: "wallet id",
+			Required:    true,
+			Schema: &OpenAPISchema{
+				Type:        "string",
+				Description: "wallet id",
+			},
+		},
+	}, op.Parameters)
+	require.Equal(t, "#/components/schemas/api.BalanceResponse", op.Responses["200"].Content[ContentTypeJSON].Schema.Ref)
+
+	// Embedded structs are flattened, custom JSON types are strings
+	balance := spec.Components.Schemas["api.BalanceResponse"]
+	require.NotNil(t, balance)
+	require.Equal(t, "object", balance.Type)
+	require.Equal(t, "#/components/schemas/readable.Balance", balance.Properties["confirmed"].Ref)
+	require.Equal(t, "object", balance.Properties["addresses"].Type)
+	require.Equal(t, "string", spec.Components.Schemas["api.Receiver"].Properties["coins"].Type)
+
+	// v1 form fields
+	op = spec.Paths["/api/v1/wallet/newAddress"]["post"]
+	require.NotNil(t, op)
+	require.Empty(t, op.Parameters)
+	form := op.RequestBody.Content[ContentTypeForm].Schema
+	require.Equal(t, "integer", form.Properties["num"].Type)
+
+	// v2 JSON body and wrapped response
+	op = spec.Paths["/api/v2/address/verify"]["post"]
+	require.NotNil(t, op)
+	require.Equal(t, "#/components/schemas/api.VerifyAddressRequest", op.RequestBody.Content[ContentTypeJSON].Schema.Ref)
+	resp := op.Responses["200"].Content[ContentTypeJSON].Schema
+	require.Equal(t, "#/components/schemas/api.VerifyAddressResponse", resp.Properties["data"].Ref)
+	require.Equal(t, "#/components/schemas/api.HTTPResponse", op.Responses["default"].Content[ContentTypeJSON].Schema.Ref)
+
+	// Alternative responses
+	op = spec.Paths["/api/v1/block"]["get"]
+	require.NotNil(t, op)
+	require.Len(t, op.Responses["200"].Content[ContentTypeJSON].Schema.OneOf, 2)
+
+	// Methods of a path
+	require.Len(t, spec.Paths["/api/v2/data"], 3)
+
+	// Auth
+	cfg.username = "foo"
+	spec = getOpenAPISpec(t, cfg)
+	require.Len(t, spec.Security, 2)
+}
+
+func TestOpenAPISpecHandlerMethod(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "/api/v2/spec", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", ContentTypeJSON)
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(defaultMuxConfig(), &MockGatewayer{})
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -59,8 +59,12 @@ func outputsHandler(gateway Gatewayer) http.HandlerFunc {
 			}
 		}
 
-		summary, err := gateway.GetUnspentOutputsSummary(filters)
+		summary, err := gateway.GetUnspentOutputsSummary(r.Context(), filters)
 		if err != nil {
+			if writeContextError(w, apiVersion1, err) {
+				return
+			}
+
 			err = fmt.Errorf("gateway.GetUnspentOutputsSummary failed: %v", err)
 			wh.Error500(w, err.Error())
 			return
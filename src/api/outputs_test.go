@@ -114,7 +114,7 @@ func TestGetOutputsHandler(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			gateway := &MockGatewayer{}
 			endpoint := "/api/v1/outputs"
-			gateway.On("GetUnspentOutputsSummary", mock.Anything).Return(tc.getUnspentOutputsResponse, tc.getUnspentOutputsError)
+			gateway.On("GetUnspentOutputsSummary", mock.Anything, mock.Anything).Return(tc.getUnspentOutputsResponse, tc.getUnspentOutputsError)
 
 			v := url.Values{}
 			if tc.httpBody != nil {
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// RateLimitRead limits the endpoints that are not expensive or wallet endpoints
+	RateLimitRead = "read"
+	// RateLimitExpensive limits the endpoints that scan the blockchain, the unspent outputs
+	// or the unconfirmed pool, and the endpoints that stream events
+	RateLimitExpensive = "expensive"
+	// RateLimitWallet limits the endpoints of the WALLET and INSECURE_SEED API sets
+	RateLimitWallet = "wallet"
+
+	// rateLimitSweepInterval is the interval at which the idle buckets are removed
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimitClasses are the classes of endpoints that can be rate limited
+var rateLimitClasses = []string{RateLimitRead, RateLimitExpensive, RateLimitWallet}
+
+// rateLimitExpensiveEndpoints are the endpoints of the RateLimitExpensive class
+var rateLimitExpensiveEndpoints = map[string]struct{}{
+	"/api/v1/blocks":                {},
+	"/api/v1/last_blocks":           {},
+	"/api/v1/pendingTxs":            {},
+	"/api/v2/mempool":               {},
+	"/api/v1/transactions":          {},
+	"/api/v2/transactions":          {},
+	"/api/v2/transaction":           {},
+	"/api/v2/transaction/estimate":  {},
+	"/api/v2/transaction/export":    {},
+	"/api/v1/resendUnconfirmedTxns": {},
+	"/api/v1/outputs":               {},
+	"/api/v1/balance":               {},
+	"/api/v2/balance":               {},
+	"/api/v1/address_uxouts":        {},
+	"/api/v2/address/balance-at":    {},
+	"/api/v1/coinSupply":            {},
+	"/api/v1/richlist":              {},
+	"/api/v1/addresscount":          {},
+	"/api/v2/watch/transactions":    {},
+	"/api/v2/graphql":               {},
+	"/api/v2/admin/db/verify":       {},
+	"/api/v2/admin/chain/verify":    {},
+	"/api/v2/ws":                    {},
+	"/api/v2/events":                {},
+}
+
+// RateLimit limits the requests of each client to a class of endpoints with a token bucket
+type RateLimit struct {
+	// Class is the class of endpoints, RateLimitRead, RateLimitExpensive or RateLimitWallet
+	Class string
+	// Rate is the number of requests per second that refill the bucket
+	Rate float64
+	// Burst is the size of the bucket, the number of requests that can be made at once
+	Burst int
+}
+
+// ParseRateLimits parses a comma-separated list of rate limits, in the format
+// <class>=<requests per second>[:<burst>], e.g. "read=10:20,expensive=0.5:2".
+// The burst defaults to the rate, rounded up. The classes without a rate limit are not limited.
+func ParseRateLimits(s string) ([]RateLimit, error) {
+	var limits []RateLimit
+	seen := make(map[string]struct{})
+
+	for _, l := range splitCommaString(s) {
+		pts := strings.SplitN(l, "=", 2)
+		if len(pts) != 2 || pts[0] == "" || pts[1] == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, must be <class>=<requests per second>[:<burst>]", l)
+		}
+
+		class := strings.ToLower(strings.TrimSpace(pts[0]))
+		if !containsString(rateLimitClasses, class) {
+			return nil, fmt.Errorf("invalid rate limit class %q, must be %s", class, strings.Join(rateLimitClasses, ", "))
+		}
+
+		if _, ok := seen[class]; ok {
+			return nil, fmt.Errorf("duplicate rate limit class %q", class)
+		}
+		seen[class] = struct{}{}
+
+		rateAndBurst := strings.SplitN(pts[1], ":", 2)
+
+		rate, err := strconv.ParseFloat(rateAndBurst[0], 64)
+		if err != nil || rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return nil, fmt.Errorf("invalid rate %q of rate limit %q, must be a positive number", rateAndBurst[0], class)
+		}
+
+		burst := int(math.Ceil(rate))
+		if len(rateAndBurst) == 2 {
+			burst, err = strconv.Atoi(rateAndBurst[1])
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst %q of rate limit %q, must be a positive integer", rateAndBurst[1], class)
+			}
+		}
+
+		limits = append(limits, RateLimit{
+			Class: class,
+			Rate:  rate,
+			Burst: burst,
+		})
+	}
+
+	return limits, nil
+}
+
+// rateLimitClass returns the rate limit class of the endpoint method
+func rateLimitClass(endpoint, method string, methodAPISets map[string][]string) string {
+	for _, k := range methodAPISets[method] {
+		if k == EndpointsWallet || k == EndpointsInsecureWalletSeed {
+			return RateLimitWallet
+		}
+	}
+
+	if _, ok := rateLimitExpensiveEndpoints[endpoint]; ok {
+		return RateLimitExpensive
+	}
+
+	return RateLimitRead
+}
+
+// tokenBucket is the bucket of a client
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket for each client
+type rateLimiter struct {
+	sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(l RateLimit) *rateLimiter {
+	return &rateLimiter{
+		rate:    l.Rate,
+		burst:   float64(l.Burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the bucket of the client.
+// If the bucket is empty, returns false and the time until a token is available.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{
+			tokens: l.burst,
+			last:   now,
+		}
+		l.buckets[client] = b
+	} else {
+		b.tokens = l.refill(b, now)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// refill returns the tokens of the bucket at now
+func (l *rateLimiter) refill(b *tokenBucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(l.burst, b.tokens+elapsed*l.rate)
+}
+
+// sweep removes the buckets that are full, they are recreated full on the next request of their client
+func (l *rateLimiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitClient returns the client of a request, the bearer token of the request if it is one of the tokens,
+// or the IP address of the remote host. The requests with an invalid token or basic auth credentials are
+// limited by their IP address, since they are limited before they are authenticated.
+// The proxy headers such as X-Forwarded-For are not used, since any client can set them.
+func rateLimitClient(r *http.Request, tokens map[cipher.SHA256]map[string]struct{}) string {
+	if hash, ok := bearerTokenHash(r); ok {
+		if _, ok := tokens[hash]; ok {
+			return "token:" + hash.Hex()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// newRateLimiters creates the rate limiters of the classes of the rate limits
+func newRateLimiters(limits []RateLimit) map[string]*rateLimiter {
+	limiters := make(map[string]*rateLimiter, len(limits))
+	for _, l := range limits {
+		limiters[l.Class] = newRateLimiter(l)
+	}
+	return limiters
+}
+
+// rateLimitHandler rejects the requests of the clients that exceed the rate limit of the class of the endpoint method
+// with 429 Too Many Requests and a Retry-After header. It wraps the authentication handler,
+// so that the failed authentication attempts are limited too.
+func rateLimitHandler(apiVersion, endpoint string, limiters map[string]*rateLimiter, methodAPISets map[string][]string, tokens []APIToken, handler http.Handler) http.Handler {
+	if len(limiters) == 0 {
+		return handler
+	}
+
+	tokenScopesMap := tokenScopesByHash(tokens)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter, ok := limiters[rateLimitClass(endpoint, r.Method, methodAPISets)]
+		if !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if ok, wait := limiter.allow(rateLimitClient(r, tokenScopesMap)); !ok {
+			retryAfter := int64(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+			writeError(w, apiVersion, http.StatusTooManyRequests, "")
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
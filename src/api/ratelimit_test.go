@@ -0,0 +1,383 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	tt := []struct {
+		name   string
+		s      string
+		limits []RateLimit
+		err    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "rate limits",
+			s:    "read=10:20, EXPENSIVE=0.5, wallet=2:1",
+			limits: []RateLimit{
+				{
+					Class: RateLimitRead,
+					Rate:  10,
+					Burst: 20,
+				},
+				{
+					Class: RateLimitExpensive,
+					Rate:  0.5,
+					Burst: 1,
+				},
+				{
+					Class: RateLimitWallet,
+					Rate:  2,
+					Burst: 1,
+				},
+			},
+		},
+		{
+			name: "missing rate",
+			s:    "read",
+			err:  "invalid rate limit \"read\", must be <class>=<requests per second>[:<burst>]",
+		},
+		{
+			name: "invalid class",
+			s:    "spend=1",
+			err:  "invalid rate limit class \"spend\", must be read, expensive, wallet",
+		},
+		{
+			name: "duplicate class",
+			s:    "read=1,read=2",
+			err:  "duplicate rate limit class \"read\"",
+		},
+		{
+			name: "invalid rate",
+			s:    "read=foo",
+			err:  "invalid rate \"foo\" of rate limit \"read\", must be a positive number",
+		},
+		{
+			name: "zero rate",
+			s:    "read=0",
+			err:  "invalid rate \"0\" of rate limit \"read\", must be a positive number",
+		},
+		{
+			name: "invalid burst",
+			s:    "read=1:0",
+			err:  "invalid burst \"0\" of rate limit \"read\", must be a positive integer",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			limits, err := ParseRateLimits(tc.s)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.err, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.limits, limits)
+		})
+	}
+}
+
+func TestRateLimitClass(t *testing.T) {
+	readSets := map[string][]string{
+		http.MethodGet: []string{EndpointsRead},
+	}
+	walletSets := map[string][]string{
+		http.MethodGet:  []string{EndpointsWallet},
+		http.MethodPost: []string{EndpointsWallet},
+	}
+
+	require.Equal(t, RateLimitRead, rateLimitClass("/api/v1/block", http.MethodGet, readSets))
+	require.Equal(t, RateLimitRead, rateLimitClass("/api/v1/version", http.MethodGet, nil))
+	require.Equal(t, RateLimitExpensive, rateLimitClass("/api/v1/richlist", http.MethodGet, readSets))
+	require.Equal(t, RateLimitExpensive, rateLimitClass("/api/v2/mempool", http.MethodGet, readSets))
+	require.Equal(t, RateLimitRead, rateLimitClass("/api/v2/mempool/evictions", http.MethodGet, readSets))
+	require.Equal(t, RateLimitExpensive, rateLimitClass("/api/v2/transaction/estimate", http.MethodPost, readSets))
+	require.Equal(t, RateLimitExpensive, rateLimitClass("/api/v2/graphql", http.MethodPost, map[string][]string{
+		http.MethodPost: []string{EndpointsGraphQL},
+	}))
+	require.Equal(t, RateLimitExpensive, rateLimitClass("/api/v2/admin/chain/verify", http.MethodPost, map[string][]string{
+		http.MethodPost: []string{EndpointsAdmin},
+	}))
+	require.Equal(t, RateLimitWallet, rateLimitClass("/api/v1/wallet/transaction", http.MethodPost, walletSets))
+	require.Equal(t, RateLimitWallet, rateLimitClass("/api/v2/wallet/seed", http.MethodPost, map[string][]string{
+		http.MethodPost: []string{EndpointsInsecureWalletSeed},
+	}))
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1e9, 0)
+	l := newRateLimiter(RateLimit{
+		Class: RateLimitRead,
+		Rate:  2,
+		Burst: 3,
+	})
+	l.now = func() time.Time {
+		return now
+	}
+
+	// The burst is available at once
+	for i := 0; i < 3; i++ {
+		ok, _ := l.allow("a")
+		require.True(t, ok)
+	}
+
+	ok, wait := l.allow("a")
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	// The clients have their own buckets
+	ok, _ = l.allow("b")
+	require.True(t, ok)
+
+	// The bucket is refilled at the rate
+	now = now.Add(250 * time.Millisecond)
+	ok, wait = l.allow("a")
+	require.False(t, ok)
+	require.Equal(t, 250*time.Millisecond, wait)
+
+	now = now.Add(250 * time.Millisecond)
+	ok, _ = l.allow("a")
+	require.True(t, ok)
+
+	// The full buckets are removed by the sweep
+	require.Len(t, l.buckets, 2)
+	now = now.Add(rateLimitSweepInterval)
+	ok, _ = l.allow("b")
+	require.True(t, ok)
+	require.Len(t, l.buckets, 1)
+
+	// The bucket is not refilled above the burst
+	for i := 0; i < 2; i++ {
+		ok, _ = l.allow("b")
+		require.True(t, ok)
+	}
+	ok, _ = l.allow("b")
+	require.False(t, ok)
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	tokens := []APIToken{
+		{
+			Token:  "root",
+			Scopes: []string{ScopeAdmin},
+		},
+	}
+
+	type request struct {
+		endpoint   string
+		remoteAddr string
+		token      string
+		status     int
+		retryAfter string
+	}
+
+	tt := []struct {
+		name     string
+		tokens   []APIToken
+		requests []request
+	}{
+		{
+			name: "per IP",
+			requests: []request{
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusOK,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "1.2.3.4:1001",
+					status:     http.StatusOK,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					// The read endpoints share the bucket
+					endpoint:   "/api/v1/version",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "5.6.7.8:1000",
+					status:     http.StatusOK,
+				},
+				{
+					// The wallet endpoints have their own bucket
+					endpoint:   "/api/v1/wallet/balance?id=foo.wlt",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusOK,
+				},
+				{
+					endpoint:   "/api/v1/wallet/balance?id=foo.wlt",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					// The endpoints of a class without a rate limit are not limited
+					endpoint:   "/api/v1/addresscount",
+					remoteAddr: "1.2.3.4:1000",
+					status:     http.StatusOK,
+				},
+			},
+		},
+		{
+			name:   "per token",
+			tokens: tokens,
+			requests: []request{
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "1.2.3.4:1000",
+					token:      "root",
+					status:     http.StatusOK,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "5.6.7.8:1000",
+					token:      "root",
+					status:     http.StatusOK,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					token:      "root",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					// The invalid tokens are limited by IP
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					token:      "foo",
+					status:     http.StatusUnauthorized,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					token:      "bar",
+					status:     http.StatusUnauthorized,
+				},
+				{
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					token:      "foo",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					// The requests without a token are limited by IP too
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+				{
+					// The valid token has its own bucket
+					endpoint:   "/api/v1/blockchain/metadata",
+					remoteAddr: "9.9.9.9:1000",
+					token:      "root",
+					status:     http.StatusTooManyRequests,
+					retryAfter: "100",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetBlockchainMetadata").Return(&visor.BlockchainMetadata{}, nil)
+			gateway.On("GetWalletBalance", mock.Anything, "foo.wlt").Return(wallet.BalancePair{}, wallet.AddressBalances{}, nil)
+			gateway.On("AddressCount").Return(uint64(1), nil)
+
+			cfg := defaultMuxConfig()
+			cfg.tokens = tc.tokens
+			cfg.rateLimits = []RateLimit{
+				{
+					Class: RateLimitRead,
+					Rate:  0.01,
+					Burst: 2,
+				},
+				{
+					Class: RateLimitWallet,
+					Rate:  0.01,
+					Burst: 1,
+				},
+			}
+			handler := newServerMux(cfg, gateway)
+
+			for i, r := range tc.requests {
+				req, err := http.NewRequest(http.MethodGet, r.endpoint, nil)
+				require.NoError(t, err)
+				req.RemoteAddr = r.remoteAddr
+				if r.token != "" {
+					req.Header.Set("Authorization", "Bearer "+r.token)
+				}
+
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				require.Equal(t, r.status, rr.Code, "request %d: %s", i, rr.Body.String())
+				require.Equal(t, r.retryAfter, rr.Header().Get("Retry-After"), "request %d", i)
+			}
+		})
+	}
+}
+
+func TestRateLimitHandlerV2Error(t *testing.T) {
+	cfg := defaultMuxConfig()
+	cfg.rateLimits = []RateLimit{
+		{
+			Class: RateLimitExpensive,
+			Rate:  0.5,
+			Burst: 1,
+		},
+	}
+	handler := newServerMux(cfg, &MockGatewayer{})
+
+	for i := 0; i < 2; i++ {
+		// The stream is rejected before it is opened, the invalid query checks that the rate limit applies first
+		req, err := http.NewRequest(http.MethodGet, "/api/v2/events?watch=foo", nil)
+		require.NoError(t, err)
+		req.RemoteAddr = "1.2.3.4:1000"
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if i == 0 {
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			continue
+		}
+
+		require.Equal(t, http.StatusTooManyRequests, rr.Code)
+		require.Equal(t, "2", rr.Header().Get("Retry-After"))
+
+		var resp ReceivedHTTPResponse
+		err = json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, http.StatusTooManyRequests, resp.Error.Code)
+		require.Equal(t, "Too Many Requests", resp.Error.Message)
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// AddressResolverDNS is the -address-resolver value of the DNS TXT record resolver
+	AddressResolverDNS = "dns"
+
+	// DNSAddressRecordPrefix is the prefix of the DNS TXT records of the addresses resolved by DNSAddressResolver
+	DNSAddressRecordPrefix = "skycoin="
+
+	httpAddressResolverTimeout = 10 * time.Second
+)
+
+// ErrAddressNameNotFound is returned by an AddressResolver if no address is registered for the name
+var ErrAddressNameNotFound = errors.New("no address found for name")
+
+// AddressResolver resolves the human-readable names of the receivers of the send endpoints to addresses
+type AddressResolver interface {
+	// ResolveAddress returns the address registered for name, or ErrAddressNameNotFound
+	ResolveAddress(ctx context.Context, name string) (cipher.Address, error)
+}
+
+// NewAddressResolver creates the AddressResolver of a -address-resolver value, which is
+// "dns" for the DNS TXT record resolver or the http(s) URL of an external name service.
+// Returns nil if s is empty.
+func NewAddressResolver(s string) (AddressResolver, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return nil, nil
+	case s == AddressResolverDNS:
+		return NewDNSAddressResolver(), nil
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return NewHTTPAddressResolver(s)
+	default:
+		return nil, fmt.Errorf("invalid address resolver %q, must be %s or an http(s) URL", s, AddressResolverDNS)
+	}
+}
+
+// DNSAddressResolver resolves a domain name to the address of its "skycoin=<address>" TXT record
+type DNSAddressResolver struct {
+	resolver *net.Resolver
+}
+
+// NewDNSAddressResolver creates a DNSAddressResolver using the system DNS resolver
+func NewDNSAddressResolver() *DNSAddressResolver {
+	return &DNSAddressResolver{
+		resolver: net.DefaultResolver,
+	}
+}
+
+// ResolveAddress looks up the TXT records of name
+func (r *DNSAddressResolver) ResolveAddress(ctx context.Context, name string) (cipher.Address, error) {
+	records, err := r.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return cipher.Address{}, ErrAddressNameNotFound
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return cipher.Address{}, ctxErr
+		}
+		return cipher.Address{}, err
+	}
+
+	return addressFromTXTRecords(name, records)
+}
+
+// addressFromTXTRecords returns the address of the "skycoin=<address>" records of name.
+// The records of a name must not have different addresses.
+func addressFromTXTRecords(name string, records []string) (cipher.Address, error) {
+	var addr cipher.Address
+	for _, rec := range records {
+		if !strings.HasPrefix(rec, DNSAddressRecordPrefix) {
+			continue
+		}
+
+		a, err := cipher.DecodeBase58Address(strings.TrimSpace(strings.TrimPrefix(rec, DNSAddressRecordPrefix)))
+		if err != nil {
+			return cipher.Address{}, fmt.Errorf("invalid address in TXT record of %q: %v", name, err)
+		}
+
+		if !addr.Null() && a != addr {
+			return cipher.Address{}, fmt.Errorf("TXT records of %q have different addresses", name)
+		}
+		addr = a
+	}
+
+	if addr.Null() {
+		return cipher.Address{}, ErrAddressNameNotFound
+	}
+
+	return addr, nil
+}
+
+// HTTPAddressResolver resolves names with an external name service.
+// The service is requested with GET <url>?name=<name> and responds with {"address": "<address>"},
+// or 404 Not Found if no address is registered for the name.
+type HTTPAddressResolver struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// httpAddressResolverResponse is the response of the name service of an HTTPAddressResolver
+type httpAddressResolverResponse struct {
+	Address string `json:"address"`
+}
+
+// NewHTTPAddressResolver creates an HTTPAddressResolver of the name service at rawurl
+func NewHTTPAddressResolver(rawurl string) (*HTTPAddressResolver, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address resolver URL: %v", err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid address resolver URL %q", rawurl)
+	}
+
+	return &HTTPAddressResolver{
+		url: u,
+		client: &http.Client{
+			Timeout: httpAddressResolverTimeout,
+		},
+	}, nil
+}
+
+// ResolveAddress requests the address of name from the name service
+func (r *HTTPAddressResolver) ResolveAddress(ctx context.Context, name string) (cipher.Address, error) {
+	u := *r.url
+	q := u.Query()
+	q.Set("name", name)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return cipher.Address{}, err
+	}
+	req.Header.Set("Accept", ContentTypeJSON)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return cipher.Address{}, ctxErr
+		}
+		return cipher.Address{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return cipher.Address{}, ErrAddressNameNotFound
+	default:
+		return cipher.Address{}, fmt.Errorf("name service responded with %s", resp.Status)
+	}
+
+	var rr httpAddressResolverResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&rr); err != nil {
+		return cipher.Address{}, fmt.Errorf("invalid name service response: %v", err)
+	}
+
+	addr, err := cipher.DecodeBase58Address(rr.Address)
+	if err != nil {
+		return cipher.Address{}, fmt.Errorf("invalid address in name service response: %v", err)
+	}
+
+	return addr, nil
+}
+
+// ResolvedName is a receiver name resolved to an address, returned with the created transaction
+// for the user to confirm the address before signing or broadcasting it
+type ResolvedName struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// resolveReceivers resolves the names of the receivers to their addresses.
+// A receiver must have either an address or a name.
+func resolveReceivers(ctx context.Context, resolver AddressResolver, to []receiver) ([]ResolvedName, error) {
+	var resolved []ResolvedName
+	for i := range to {
+		name := strings.TrimSpace(to[i].Name)
+		if name == "" {
+			continue
+		}
+
+		if !to[i].Address.Null() {
+			return nil, resolveError{fmt.Errorf("to[%d] must not have both address and name", i)}
+		}
+
+		if resolver == nil {
+			return nil, resolveError{fmt.Errorf("to[%d].name can not be resolved, no address resolver is configured", i)}
+		}
+
+		addr, err := resolver.ResolveAddress(ctx, name)
+		if err != nil {
+			if err == ErrAddressNameNotFound {
+				return nil, resolveError{fmt.Errorf("to[%d].name %q: %v", i, name, err)}
+			}
+			return nil, err
+		}
+
+		to[i].Address.Address = addr
+		resolved = append(resolved, ResolvedName{
+			Name:    name,
+			Address: addr.String(),
+		})
+	}
+
+	return resolved, nil
+}
+
+// resolveError is an error of the receiver names of a request, as opposed to a failure of the resolver
+type resolveError struct {
+	error
+}
+
+// writeResolveError writes the error of resolveReceivers, 400 Bad Request for an invalid or unknown name
+// and 503 Service Unavailable if the address resolver failed
+func writeResolveError(w http.ResponseWriter, apiVersion string, err error) {
+	if writeContextError(w, apiVersion, err) {
+		return
+	}
+
+	if _, ok := err.(resolveError); ok {
+		writeError(w, apiVersion, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeError(w, apiVersion, http.StatusServiceUnavailable, fmt.Sprintf("address resolver failed: %v", err))
+}
@@ -0,0 +1,277 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+// fakeAddressResolver resolves the names of its map
+type fakeAddressResolver struct {
+	addresses map[string]cipher.Address
+	err       error
+}
+
+func (r fakeAddressResolver) ResolveAddress(ctx context.Context, name string) (cipher.Address, error) {
+	if r.err != nil {
+		return cipher.Address{}, r.err
+	}
+
+	addr, ok := r.addresses[name]
+	if !ok {
+		return cipher.Address{}, ErrAddressNameNotFound
+	}
+	return addr, nil
+}
+
+func TestNewAddressResolver(t *testing.T) {
+	r, err := NewAddressResolver("")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	r, err = NewAddressResolver("dns")
+	require.NoError(t, err)
+	require.IsType(t, &DNSAddressResolver{}, r)
+
+	r, err = NewAddressResolver("https://names.example.com/resolve")
+	require.NoError(t, err)
+	require.IsType(t, &HTTPAddressResolver{}, r)
+
+	_, err = NewAddressResolver("ens")
+	require.Error(t, err)
+	require.Equal(t, "invalid address resolver \"ens\", must be dns or an http(s) URL", err.Error())
+
+	_, err = NewAddressResolver("http://")
+	require.Error(t, err)
+	require.Equal(t, "invalid address resolver URL \"http://\"", err.Error())
+}
+
+func TestAddressFromTXTRecords(t *testing.T) {
+	addr := testutil.MakeAddress()
+	other := testutil.MakeAddress()
+
+	tt := []struct {
+		name    string
+		records []string
+		addr    cipher.Address
+		err     string
+	}{
+		{
+			name:    "no records",
+			records: nil,
+			err:     ErrAddressNameNotFound.Error(),
+		},
+		{
+			name:    "no address records",
+			records: []string{"v=spf1 -all"},
+			err:     ErrAddressNameNotFound.Error(),
+		},
+		{
+			name:    "address record",
+			records: []string{"v=spf1 -all", "skycoin=" + addr.String()},
+			addr:    addr,
+		},
+		{
+			name:    "duplicate address records",
+			records: []string{"skycoin=" + addr.String(), "skycoin= " + addr.String()},
+			addr:    addr,
+		},
+		{
+			name:    "different addresses",
+			records: []string{"skycoin=" + addr.String(), "skycoin=" + other.String()},
+			err:     "TXT records of \"alice.example.com\" have different addresses",
+		},
+		{
+			name:    "invalid address",
+			records: []string{"skycoin=foo"},
+			err:     "invalid address in TXT record of \"alice.example.com\": Invalid address length",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := addressFromTXTRecords("alice.example.com", tc.records)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.err, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.addr, a)
+		})
+	}
+}
+
+func TestHTTPAddressResolver(t *testing.T) {
+	addr := testutil.MakeAddress()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("name") {
+		case "alice":
+			require.NoError(t, json.NewEncoder(w).Encode(httpAddressResolverResponse{
+				Address: addr.String(),
+			}))
+		case "bob":
+			require.NoError(t, json.NewEncoder(w).Encode(httpAddressResolverResponse{
+				Address: "foo",
+			}))
+		case "carol":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r, err := NewHTTPAddressResolver(server.URL + "/resolve?coin=skycoin")
+	require.NoError(t, err)
+
+	a, err := r.ResolveAddress(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, addr, a)
+
+	_, err = r.ResolveAddress(context.Background(), "bob")
+	require.Error(t, err)
+	require.Equal(t, "invalid address in name service response: Invalid address length", err.Error())
+
+	_, err = r.ResolveAddress(context.Background(), "carol")
+	require.Error(t, err)
+	require.Equal(t, "name service responded with 500 Internal Server Error", err.Error())
+
+	_, err = r.ResolveAddress(context.Background(), "dave")
+	require.Equal(t, ErrAddressNameNotFound, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.ResolveAddress(ctx, "alice")
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestTransactionHandlerV2ResolveNames(t *testing.T) {
+	signed := prepareTxnAndInputs(t)
+	addr := testutil.MakeAddress()
+
+	body := func(to rawReceiver) string {
+		b, err := json.Marshal(rawCreateTxnRequest{
+			HoursSelection: rawHoursSelection{
+				Type: transaction.HoursSelectionTypeManual,
+			},
+			To:     []rawReceiver{to},
+			UxOuts: []string{signed.txn.In[0].Hex()},
+		})
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	txnResp, err := NewCreateTransactionResponse(&signed.txn, signed.inputs)
+	require.NoError(t, err)
+	txnResp.ResolvedNames = []ResolvedName{
+		{
+			Name:    "alice",
+			Address: addr.String(),
+		},
+	}
+
+	tt := []struct {
+		name         string
+		body         string
+		resolver     AddressResolver
+		status       int
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "400 - no resolver",
+			body:         body(rawReceiver{Name: "alice", Coins: "1", Hours: "1"}),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "to[0].name can not be resolved, no address resolver is configured"),
+		},
+		{
+			name: "400 - address and name",
+			body: body(rawReceiver{Address: addr.String(), Name: "alice", Coins: "1", Hours: "1"}),
+			resolver: fakeAddressResolver{
+				addresses: map[string]cipher.Address{"alice": addr},
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "to[0] must not have both address and name"),
+		},
+		{
+			name: "400 - name not found",
+			body: body(rawReceiver{Name: "bob", Coins: "1", Hours: "1"}),
+			resolver: fakeAddressResolver{
+				addresses: map[string]cipher.Address{"alice": addr},
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "to[0].name \"bob\": no address found for name"),
+		},
+		{
+			name: "503 - resolver failed",
+			body: body(rawReceiver{Name: "alice", Coins: "1", Hours: "1"}),
+			resolver: fakeAddressResolver{
+				err: errors.New("connection refused"),
+			},
+			status:       http.StatusServiceUnavailable,
+			httpResponse: NewHTTPErrorResponse(http.StatusServiceUnavailable, "address resolver failed: connection refused"),
+		},
+		{
+			name: "504 - resolver timeout",
+			body: body(rawReceiver{Name: "alice", Coins: "1", Hours: "1"}),
+			resolver: fakeAddressResolver{
+				err: context.DeadlineExceeded,
+			},
+			status:       http.StatusGatewayTimeout,
+			httpResponse: NewHTTPErrorResponse(http.StatusGatewayTimeout, "Request timeout exceeded"),
+		},
+		{
+			name: "200",
+			body: body(rawReceiver{Name: "alice", Coins: "1", Hours: "1"}),
+			resolver: fakeAddressResolver{
+				addresses: map[string]cipher.Address{"alice": addr},
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: txnResp,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("CreateTransaction", mock.Anything, mock.Anything).Return(&signed.txn, signed.inputs, nil)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v2/transaction", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			cfg := defaultMuxConfig()
+			cfg.addressResolver = tc.resolver
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			expected, err := json.MarshalIndent(tc.httpResponse, "", "    ")
+			require.NoError(t, err)
+			require.Equal(t, string(expected), rr.Body.String())
+
+			if tc.status == http.StatusOK {
+				params := gateway.Calls[0].Arguments.Get(0).(transaction.Params)
+				require.Equal(t, addr, params.To[0].Address)
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ import (
 type CreateTransactionResponse struct {
 	Transaction        CreatedTransaction `json:"transaction"`
 	EncodedTransaction string             `json:"encoded_transaction"`
+	// ResolvedNames are the addresses of the receivers sent to by name, to confirm before signing or broadcasting
+	ResolvedNames []ResolvedName `json:"resolved_names,omitempty"`
 }
 
 // NewCreateTransactionResponse creates a CreateTransactionResponse
@@ -268,6 +270,8 @@ type createTransactionRequest struct {
 	To                []receiver     `json:"to"`
 	UxOuts            []wh.SHA256    `json:"unspents,omitempty"`
 	Addresses         []wh.Address   `json:"addresses,omitempty"`
+	ExcludeUxOuts     []wh.SHA256    `json:"exclude_unspents,omitempty"`
+	AvoidAddressReuse bool           `json:"avoid_address_reuse"`
 }
 
 // hoursSelection defines options for hours distribution
@@ -280,8 +284,10 @@ type hoursSelection struct {
 // receiver specifies a spend destination
 type receiver struct {
 	Address wh.Address `json:"address"`
-	Coins   wh.Coins   `json:"coins"`
-	Hours   *wh.Hours  `json:"hours,omitempty"`
+	// Name is resolved to the address by the AddressResolver of the node if address is not set
+	Name  string    `json:"name,omitempty"`
+	Coins wh.Coins  `json:"coins"`
+	Hours *wh.Hours `json:"hours,omitempty"`
 }
 
 // Validate validates createTransactionRequest data
@@ -367,6 +373,19 @@ func (r createTransactionRequest) Validate() error {
 		uxouts[o.SHA256] = struct{}{}
 	}
 
+	if len(r.UxOuts) != 0 && len(r.ExcludeUxOuts) != 0 {
+		return errors.New("unspents and exclude_unspents cannot be combined")
+	}
+
+	excludeUxOuts := make(map[cipher.SHA256]struct{}, len(r.ExcludeUxOuts))
+	for _, o := range r.ExcludeUxOuts {
+		if _, ok := excludeUxOuts[o.SHA256]; ok {
+			return errors.New("exclude_unspents contains duplicate values")
+		}
+
+		excludeUxOuts[o.SHA256] = struct{}{}
+	}
+
 	if len(r.To) == 0 {
 		return errors.New("to is empty")
 	}
@@ -450,6 +469,8 @@ func (r createTransactionRequest) VisorParams() visor.CreateTransactionParams {
 		IgnoreUnconfirmed: r.IgnoreUnconfirmed,
 		Addresses:         r.addresses(),
 		UxOuts:            r.uxOuts(),
+		ExcludeUxOuts:     r.excludeUxOuts(),
+		AvoidAddressReuse: r.AvoidAddressReuse,
 	}
 }
 
@@ -475,11 +496,22 @@ func (r createTransactionRequest) uxOuts() []cipher.SHA256 {
 	return uxouts
 }
 
+func (r createTransactionRequest) excludeUxOuts() []cipher.SHA256 {
+	if len(r.ExcludeUxOuts) == 0 {
+		return nil
+	}
+	uxouts := make([]cipher.SHA256, len(r.ExcludeUxOuts))
+	for i, o := range r.ExcludeUxOuts {
+		uxouts[i] = o.SHA256
+	}
+	return uxouts
+}
+
 // transactionHandlerV2 creates a transaction from provided outputs and parameters
 // Method: POST
 // URI: /api/v2/transaction
 // Args: JSON body
-func transactionHandlerV2(gateway Gatewayer) http.HandlerFunc {
+func transactionHandlerV2(gateway Gatewayer, resolver AddressResolver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
@@ -487,33 +519,168 @@ func transactionHandlerV2(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		var req createTransactionRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		txn, inputs, resolved, ok := createTransactionV2(w, r, gateway, resolver)
+		if !ok {
+			return
+		}
+
+		txnResp, err := NewCreateTransactionResponse(txn, inputs)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("NewCreateTransactionResponse failed: %v", err))
 			writeHTTPResponse(w, resp)
 			return
 		}
+		txnResp.ResolvedNames = resolved
 
-		if err := req.Validate(); err != nil {
+		writeHTTPResponse(w, HTTPResponse{
+			Data: txnResp,
+		})
+	}
+}
+
+// createTransactionV2 creates the unsigned transaction of the createTransactionRequest body of the request,
+// which spends the outputs of addresses or unspents without a wallet, and returns the resolved receiver names.
+// Writes the error response and returns false if the transaction can not be created.
+func createTransactionV2(w http.ResponseWriter, r *http.Request, gateway Gatewayer, resolver AddressResolver) (*coin.Transaction, []visor.TransactionInput, []ResolvedName, bool) {
+	var req createTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return nil, nil, nil, false
+	}
+
+	resolved, err := resolveReceivers(r.Context(), resolver, req.To)
+	if err != nil {
+		writeResolveError(w, apiVersion2, err)
+		return nil, nil, nil, false
+	}
+
+	if err := req.Validate(); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return nil, nil, nil, false
+	}
+
+	// Check that addresses or unspents are not empty
+	// This is not checked in Validate() because POST /api/v1/wallet/transaction
+	// allows both to be empty
+	if len(req.Addresses) == 0 && len(req.UxOuts) == 0 {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "one of addresses or unspents must not be empty")
+		writeHTTPResponse(w, resp)
+		return nil, nil, nil, false
+	}
+
+	txn, inputs, err := gateway.CreateTransaction(req.TransactionParams(), req.VisorParams())
+	if err != nil {
+		var resp HTTPResponse
+		switch err.(type) {
+		case blockdb.ErrUnspentNotExist, transaction.Error, visor.UserError, wallet.Error:
+			resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		default:
+			switch err {
+			case fee.ErrTxnNoFee, fee.ErrTxnInsufficientCoinHours:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+		}
+		writeHTTPResponse(w, resp)
+		return nil, nil, nil, false
+	}
+
+	return txn, inputs, resolved, true
+}
+
+// estimateTransactionRequest is sent to POST /api/v2/transaction/estimate
+type estimateTransactionRequest struct {
+	WalletID string `json:"wallet_id,omitempty"`
+	createTransactionRequest
+}
+
+// EstimateTransactionResponse is returned by POST /api/v2/transaction/estimate
+type EstimateTransactionResponse struct {
+	// Fee is the number of coin hours that would be burned
+	Fee    string `json:"fee"`
+	Length uint32 `json:"length"`
+
+	In     []CreatedTransactionInput  `json:"inputs"`
+	Out    []CreatedTransactionOutput `json:"outputs"`
+	Change *CreatedTransactionOutput  `json:"change,omitempty"`
+
+	ResolvedNames []ResolvedName `json:"resolved_names,omitempty"`
+
+	// Valid is true if the transaction meets the soft and hard constraints,
+	// i.e. it would be accepted and relayed by the network once signed
+	Valid     bool   `json:"valid"`
+	Violation string `json:"violation,omitempty"`
+}
+
+// estimateTransactionHandler estimates the fee and change of a transaction, without creating or signing it.
+// The unspent outputs are chosen from the addresses or unspents, or from the wallet if wallet_id is set.
+// Method: POST
+// URI: /api/v2/transaction/estimate
+// Args: JSON body
+func estimateTransactionHandler(gateway Gatewayer, resolver AddressResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req estimateTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
 			writeHTTPResponse(w, resp)
 			return
 		}
 
-		// Check that addresses or unspents are not empty
-		// This is not checked in Validate() because POST /api/v1/wallet/transaction
-		// allows both to be empty
-		if len(req.Addresses) == 0 && len(req.UxOuts) == 0 {
-			resp := NewHTTPErrorResponse(http.StatusBadRequest, "one of addresses or unspents must not be empty")
+		resolved, err := resolveReceivers(r.Context(), resolver, req.To)
+		if err != nil {
+			writeResolveError(w, apiVersion2, err)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
 			writeHTTPResponse(w, resp)
 			return
 		}
 
-		txn, inputs, err := gateway.CreateTransaction(req.TransactionParams(), req.VisorParams())
+		var txn *coin.Transaction
+		var inputs []visor.TransactionInput
+		if req.WalletID != "" {
+			if !tokenAllows(r, http.MethodGet, []string{EndpointsWallet}) {
+				resp := NewHTTPErrorResponse(http.StatusForbidden, "Token scope does not allow wallet_id")
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			txn, inputs, err = gateway.WalletCreateTransaction(req.WalletID, req.TransactionParams(), req.VisorParams())
+		} else {
+			if len(req.Addresses) == 0 && len(req.UxOuts) == 0 {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "one of wallet_id, addresses or unspents must not be empty")
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			txn, inputs, err = gateway.CreateTransaction(req.TransactionParams(), req.VisorParams())
+		}
 		if err != nil {
 			var resp HTTPResponse
 			switch err.(type) {
-			case blockdb.ErrUnspentNotExist, transaction.Error, visor.UserError, wallet.Error:
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			case wallet.SpendLimitError:
+				resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+			case blockdb.ErrUnspentNotExist, transaction.Error, visor.UserError:
 				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
 			default:
 				switch err {
@@ -527,19 +694,59 @@ func transactionHandlerV2(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		txnResp, err := NewCreateTransactionResponse(txn, inputs)
+		estimate, err := newEstimateTransactionResponse(txn, inputs, len(req.To))
 		if err != nil {
-			resp := NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("NewCreateTransactionResponse failed: %v", err))
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("newEstimateTransactionResponse failed: %v", err))
 			writeHTTPResponse(w, resp)
 			return
 		}
+		estimate.ResolvedNames = resolved
+
+		// Check the transaction against the constraints applied to the transactions
+		// received from the network. A violation is not an error of the estimate.
+		if _, _, err := gateway.VerifyTxnVerbose(txn, visor.TxnUnsigned); err != nil {
+			switch err.(type) {
+			case visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				estimate.Valid = false
+				estimate.Violation = err.Error()
+			default:
+				resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
 
 		writeHTTPResponse(w, HTTPResponse{
-			Data: txnResp,
+			Data: estimate,
 		})
 	}
 }
 
+// newEstimateTransactionResponse creates an EstimateTransactionResponse of a created transaction.
+// The transaction has a change output if it has more outputs than the nReceivers requested.
+func newEstimateTransactionResponse(txn *coin.Transaction, inputs []visor.TransactionInput, nReceivers int) (*EstimateTransactionResponse, error) {
+	cTxn, err := NewCreatedTransaction(txn, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	var change *CreatedTransactionOutput
+	if len(cTxn.Out) > nReceivers {
+		change = &cTxn.Out[len(cTxn.Out)-1]
+	}
+
+	return &EstimateTransactionResponse{
+		Fee:    cTxn.Fee,
+		Length: cTxn.Length,
+		In:     cTxn.In,
+		Out:    cTxn.Out,
+		Change: change,
+		Valid:  true,
+	}, nil
+}
+
 // walletCreateTransactionRequest is sent to POST /api/v1/wallet/transaction
 type walletCreateTransactionRequest struct {
 	Unsigned bool   `json:"unsigned"`
@@ -565,7 +772,7 @@ func (r walletCreateTransactionRequest) Validate() error {
 // Method: POST
 // URI: /api/v1/wallet/transaction
 // Args: JSON body
-func walletCreateTransactionHandler(gateway Gatewayer) http.HandlerFunc {
+func walletCreateTransactionHandler(gateway Gatewayer, resolver AddressResolver) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			wh.Error405(w)
@@ -585,6 +792,13 @@ func walletCreateTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
+		resolved, err := resolveReceivers(r.Context(), resolver, req.To)
+		if err != nil {
+			logger.WithError(err).Error("Resolve receiver names failed")
+			writeResolveError(w, apiVersion1, err)
+			return
+		}
+
 		if err := req.Validate(); err != nil {
 			logger.WithError(err).Error("Invalid create transaction request")
 			wh.Error400(w, err.Error())
@@ -609,6 +823,8 @@ func walletCreateTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 				default:
 					wh.Error400(w, err.Error())
 				}
+			case wallet.SpendLimitError:
+				wh.Error403(w, err.Error())
 			case blockdb.ErrUnspentNotExist,
 				transaction.Error,
 				visor.UserError:
@@ -631,6 +847,7 @@ func walletCreateTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 			wh.Error500(w, err.Error())
 			return
 		}
+		txnResp.ResolvedNames = resolved
 
 		wh.SendJSONOr500(logger, w, txnResp)
 	}
@@ -722,6 +939,8 @@ func walletSignTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 				default:
 					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
 				}
+			case wallet.SpendLimitError:
+				resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
 			case visor.ErrTxnViolatesSoftConstraint,
 				visor.ErrTxnViolatesHardConstraint,
 				visor.ErrTxnViolatesUserConstraint,
@@ -746,3 +965,158 @@ func walletSignTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 		})
 	}
 }
+
+// walletsCreateTransactionRequest is sent to POST /api/v2/wallets/transaction
+type walletsCreateTransactionRequest struct {
+	Wallets []walletSpendRequest `json:"wallets"`
+	createTransactionRequest
+}
+
+// walletSpendRequest is a wallet spending its outputs in a transaction created by POST /api/v2/wallets/transaction
+type walletSpendRequest struct {
+	ID        string       `json:"id"`
+	Password  string       `json:"password"`
+	Addresses []wh.Address `json:"addresses,omitempty"`
+}
+
+// Validate validates walletsCreateTransactionRequest data
+func (r walletsCreateTransactionRequest) Validate() error {
+	if len(r.Wallets) == 0 {
+		return errors.New("wallets is empty")
+	}
+
+	if len(r.UxOuts) != 0 || len(r.Addresses) != 0 {
+		return errors.New("unspents and addresses cannot be used, set the addresses of each wallet instead")
+	}
+
+	if len(r.ExcludeUxOuts) != 0 || r.AvoidAddressReuse {
+		return errors.New("exclude_unspents and avoid_address_reuse cannot be used when spending from several wallets")
+	}
+
+	ids := make(map[string]struct{}, len(r.Wallets))
+	for i, w := range r.Wallets {
+		if w.ID == "" {
+			return fmt.Errorf("wallets[%d].id is required", i)
+		}
+
+		if _, ok := ids[w.ID]; ok {
+			return errors.New("wallets contains duplicate values")
+		}
+		ids[w.ID] = struct{}{}
+
+		addressMap := make(map[cipher.Address]struct{}, len(w.Addresses))
+		for j, a := range w.Addresses {
+			if a.Null() {
+				return fmt.Errorf("wallets[%d].addresses[%d] is empty", i, j)
+			}
+
+			if _, ok := addressMap[a.Address]; ok {
+				return fmt.Errorf("wallets[%d].addresses contains duplicate values", i)
+			}
+			addressMap[a.Address] = struct{}{}
+		}
+	}
+
+	return r.createTransactionRequest.Validate()
+}
+
+// WalletSpends converts the wallets of walletsCreateTransactionRequest to []visor.WalletSpend
+func (r walletsCreateTransactionRequest) WalletSpends() []visor.WalletSpend {
+	spends := make([]visor.WalletSpend, len(r.Wallets))
+	for i, w := range r.Wallets {
+		var addrs []cipher.Address
+		if len(w.Addresses) != 0 {
+			addrs = make([]cipher.Address, len(w.Addresses))
+			for j, a := range w.Addresses {
+				addrs[j] = a.Address
+			}
+		}
+
+		spends[i] = visor.WalletSpend{
+			WalletID:  w.ID,
+			Password:  []byte(w.Password),
+			Addresses: addrs,
+		}
+	}
+	return spends
+}
+
+// walletsCreateTransactionHandler creates a transaction spending the outputs of several wallets,
+// each wallet signs the inputs of its addresses
+// Method: POST
+// URI: /api/v2/wallets/transaction
+// Args: JSON body
+func walletsCreateTransactionHandler(gateway Gatewayer, resolver AddressResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req walletsCreateTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		resolved, err := resolveReceivers(r.Context(), resolver, req.To)
+		if err != nil {
+			writeResolveError(w, apiVersion2, err)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txn, inputs, err := gateway.WalletsCreateTransactionSigned(req.WalletSpends(), req.TransactionParams(), req.IgnoreUnconfirmed)
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			case wallet.SpendLimitError:
+				resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+			case blockdb.ErrUnspentNotExist,
+				transaction.Error,
+				visor.UserError,
+				visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				switch err {
+				case fee.ErrTxnNoFee, fee.ErrTxnInsufficientCoinHours:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				}
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txnResp, err := NewCreateTransactionResponse(txn, inputs)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("NewCreateTransactionResponse failed: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+		txnResp.ResolvedNames = resolved
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: txnResp,
+		})
+	}
+}
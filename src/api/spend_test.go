@@ -30,18 +30,26 @@ type rawHoursSelection struct {
 }
 
 type rawReceiver struct {
-	Address string `json:"address"`
+	Address string `json:"address,omitempty"`
+	Name    string `json:"name,omitempty"`
 	Coins   string `json:"coins"`
 	Hours   string `json:"hours,omitempty"`
 }
 
 type rawCreateTxnRequest struct {
-	UxOuts         []string          `json:"unspents,omitempty"`
-	Addresses      []string          `json:"addresses,omitempty"`
-	HoursSelection rawHoursSelection `json:"hours_selection"`
-	ChangeAddress  string            `json:"change_address,omitempty"`
-	To             []rawReceiver     `json:"to"`
-	Password       string            `json:"password"`
+	UxOuts            []string          `json:"unspents,omitempty"`
+	Addresses         []string          `json:"addresses,omitempty"`
+	ExcludeUxOuts     []string          `json:"exclude_unspents,omitempty"`
+	AvoidAddressReuse bool              `json:"avoid_address_reuse,omitempty"`
+	HoursSelection    rawHoursSelection `json:"hours_selection"`
+	ChangeAddress     string            `json:"change_address,omitempty"`
+	To                []rawReceiver     `json:"to"`
+	Password          string            `json:"password"`
+}
+
+type rawEstimateTxnRequest struct {
+	WalletID string `json:"wallet_id,omitempty"`
+	rawCreateTxnRequest
 }
 
 func TestCreateTransaction(t *testing.T) {
@@ -869,6 +877,260 @@ func TestCreateTransaction(t *testing.T) {
 	}
 }
 
+func TestEstimateTransaction(t *testing.T) {
+	changeAddress := testutil.MakeAddress()
+	destinationAddress := testutil.MakeAddress()
+
+	txn := &coin.Transaction{
+		Length:    100,
+		Type:      0,
+		InnerHash: testutil.RandSHA256(t),
+		In:        []cipher.SHA256{testutil.RandSHA256(t)},
+		Out: []coin.TransactionOutput{
+			{
+				Address: destinationAddress,
+				Coins:   1e6,
+				Hours:   10,
+			},
+			{
+				Address: changeAddress,
+				Coins:   1e6,
+				Hours:   90,
+			},
+		},
+	}
+
+	inputs := []visor.TransactionInput{
+		{
+			UxOut: coin.UxOut{
+				Head: coin.UxHead{
+					Time:  uint64(time.Now().UTC().Unix()),
+					BkSeq: 9999,
+				},
+				Body: coin.UxBody{
+					SrcTransaction: testutil.RandSHA256(t),
+					Address:        testutil.MakeAddress(),
+					Coins:          2e6,
+					Hours:          100,
+				},
+			},
+			CalculatedHours: 200,
+		},
+	}
+
+	createdTxn, err := NewCreatedTransaction(txn, inputs)
+	require.NoError(t, err)
+
+	estimateResponse := EstimateTransactionResponse{
+		Fee:    "100",
+		Length: 100,
+		In:     createdTxn.In,
+		Out:    createdTxn.Out,
+		Change: &createdTxn.Out[1],
+		Valid:  true,
+	}
+
+	invalidEstimateResponse := estimateResponse
+	invalidEstimateResponse.Valid = false
+	invalidEstimateResponse.Violation = "Transaction violates soft constraint: Transaction coinhour fee minimum not met"
+
+	validBody := rawEstimateTxnRequest{
+		rawCreateTxnRequest: rawCreateTxnRequest{
+			HoursSelection: rawHoursSelection{
+				Type: transaction.HoursSelectionTypeManual,
+			},
+			To: []rawReceiver{
+				{
+					Address: destinationAddress.String(),
+					Coins:   "1",
+					Hours:   "10",
+				},
+			},
+			ChangeAddress: changeAddress.String(),
+			Addresses:     []string{testutil.MakeAddress().String()},
+		},
+	}
+
+	walletBody := validBody
+	walletBody.WalletID = "foo.wlt"
+	walletBody.Addresses = nil
+
+	noSourceBody := validBody
+	noSourceBody.Addresses = nil
+
+	tt := []struct {
+		name    string
+		method  string
+		status  int
+		body    rawEstimateTxnRequest
+		rawBody string
+
+		gatewayCreateTransactionResult *coin.Transaction
+		gatewayCreateTransactionInputs []visor.TransactionInput
+		gatewayCreateTransactionErr    error
+		gatewayVerifyTxnVerboseErr     error
+
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+
+		{
+			name:         "400 - invalid json",
+			method:       http.MethodPost,
+			rawBody:      "{ca",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid character 'c' looking for beginning of object key string"),
+		},
+
+		{
+			name:         "400 - no wallet_id, addresses or unspents",
+			method:       http.MethodPost,
+			body:         noSourceBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "one of wallet_id, addresses or unspents must not be empty"),
+		},
+
+		{
+			name:                        "400 - insufficient balance",
+			method:                      http.MethodPost,
+			body:                        validBody,
+			status:                      http.StatusBadRequest,
+			gatewayCreateTransactionErr: transaction.ErrInsufficientBalance,
+			httpResponse:                NewHTTPErrorResponse(http.StatusBadRequest, "balance is not sufficient"),
+		},
+
+		{
+			name:                        "404 - wallet not found",
+			method:                      http.MethodPost,
+			body:                        walletBody,
+			status:                      http.StatusNotFound,
+			gatewayCreateTransactionErr: wallet.ErrWalletNotExist,
+			httpResponse:                NewHTTPErrorResponse(http.StatusNotFound, "wallet doesn't exist"),
+		},
+
+		{
+			name:                        "403 - wallet API disabled",
+			method:                      http.MethodPost,
+			body:                        walletBody,
+			status:                      http.StatusForbidden,
+			gatewayCreateTransactionErr: wallet.ErrWalletAPIDisabled,
+			httpResponse:                NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+
+		{
+			name:                           "500 - verify failed",
+			method:                         http.MethodPost,
+			body:                           validBody,
+			status:                         http.StatusInternalServerError,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			gatewayVerifyTxnVerboseErr:     errors.New("foo"),
+			httpResponse:                   NewHTTPErrorResponse(http.StatusInternalServerError, "foo"),
+		},
+
+		{
+			name:                           "200 - addresses",
+			method:                         http.MethodPost,
+			body:                           validBody,
+			status:                         http.StatusOK,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			httpResponse: HTTPResponse{
+				Data: estimateResponse,
+			},
+		},
+
+		{
+			name:                           "200 - wallet",
+			method:                         http.MethodPost,
+			body:                           walletBody,
+			status:                         http.StatusOK,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			httpResponse: HTTPResponse{
+				Data: estimateResponse,
+			},
+		},
+
+		{
+			name:                           "200 - violates soft constraint",
+			method:                         http.MethodPost,
+			body:                           validBody,
+			status:                         http.StatusOK,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			gatewayVerifyTxnVerboseErr:     visor.NewErrTxnViolatesSoftConstraint(fee.ErrTxnInsufficientFee),
+			httpResponse: HTTPResponse{
+				Data: invalidEstimateResponse,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+
+			serializedBody, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			var body estimateTransactionRequest
+			err = json.Unmarshal(serializedBody, &body)
+			require.NoError(t, err)
+
+			if body.WalletID != "" {
+				gateway.On("WalletCreateTransaction", body.WalletID, body.TransactionParams(), body.VisorParams()).Return(tc.gatewayCreateTransactionResult, tc.gatewayCreateTransactionInputs, tc.gatewayCreateTransactionErr)
+			} else {
+				gateway.On("CreateTransaction", body.TransactionParams(), body.VisorParams()).Return(tc.gatewayCreateTransactionResult, tc.gatewayCreateTransactionInputs, tc.gatewayCreateTransactionErr)
+			}
+
+			if tc.gatewayCreateTransactionResult != nil {
+				gateway.On("VerifyTxnVerbose", tc.gatewayCreateTransactionResult, visor.TxnUnsigned).Return(tc.gatewayCreateTransactionInputs, false, tc.gatewayVerifyTxnVerboseErr)
+			}
+
+			endpoint := "/api/v2/transaction/estimate"
+
+			bodyText := []byte(tc.rawBody)
+			if len(bodyText) == 0 {
+				bodyText = serializedBody
+			}
+
+			req, err := http.NewRequest(tc.method, endpoint, bytes.NewBuffer(bodyText))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v` (%v)", status, tc.status, rr.Body)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var msg EstimateTransactionResponse
+				err := json.Unmarshal(rsp.Data, &msg)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.httpResponse.Data.(EstimateTransactionResponse), msg)
+			}
+		})
+	}
+}
+
 func TestWalletCreateTransaction(t *testing.T) {
 	type rawWalletCreateTxnRequest struct {
 		rawCreateTxnRequest
@@ -1566,6 +1828,110 @@ func TestWalletCreateTransaction(t *testing.T) {
 			err:    "400 Bad Request - addresses contains duplicate values",
 		},
 
+		{
+			name:   "400 - both uxouts and exclude uxouts specified",
+			method: http.MethodPost,
+			body: rawWalletCreateTxnRequest{
+				rawCreateTxnRequest: rawCreateTxnRequest{
+					HoursSelection: rawHoursSelection{
+						Type:        transaction.HoursSelectionTypeAuto,
+						Mode:        transaction.HoursSelectionModeShare,
+						ShareFactor: newStrPtr("0.5"),
+					},
+					ChangeAddress: changeAddress.String(),
+					To: []rawReceiver{
+						{
+							Address: destinationAddress.String(),
+							Coins:   "1.2",
+						},
+					},
+					UxOuts:        []string{walletInput.Hex()},
+					ExcludeUxOuts: []string{testutil.RandSHA256(t).Hex()},
+				},
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - unspents and exclude_unspents cannot be combined",
+		},
+
+		{
+			name:   "400 - duplicate exclude uxouts",
+			method: http.MethodPost,
+			body: rawWalletCreateTxnRequest{
+				rawCreateTxnRequest: rawCreateTxnRequest{
+					HoursSelection: rawHoursSelection{
+						Type:        transaction.HoursSelectionTypeAuto,
+						Mode:        transaction.HoursSelectionModeShare,
+						ShareFactor: newStrPtr("0.5"),
+					},
+					ChangeAddress: changeAddress.String(),
+					To: []rawReceiver{
+						{
+							Address: destinationAddress.String(),
+							Coins:   "1.2",
+						},
+					},
+					ExcludeUxOuts: []string{walletInput.Hex(), walletInput.Hex()},
+				},
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - exclude_unspents contains duplicate values",
+		},
+
+		{
+			name:   "400 - change address reused",
+			method: http.MethodPost,
+			body: rawWalletCreateTxnRequest{
+				rawCreateTxnRequest: rawCreateTxnRequest{
+					HoursSelection: rawHoursSelection{
+						Type:        transaction.HoursSelectionTypeAuto,
+						Mode:        transaction.HoursSelectionModeShare,
+						ShareFactor: newStrPtr("0.5"),
+					},
+					ChangeAddress: changeAddress.String(),
+					To: []rawReceiver{
+						{
+							Address: destinationAddress.String(),
+							Coins:   "1.2",
+						},
+					},
+					AvoidAddressReuse: true,
+				},
+				WalletID: "foo.wlt",
+			},
+			status:                      http.StatusBadRequest,
+			gatewayCreateTransactionErr: visor.ErrChangeAddressReused,
+			err:                         "400 Bad Request - ChangeAddress has already been used, choose an unused change address to avoid address reuse",
+		},
+
+		{
+			name:   "200 - exclude uxouts and avoid address reuse",
+			method: http.MethodPost,
+			body: rawWalletCreateTxnRequest{
+				rawCreateTxnRequest: rawCreateTxnRequest{
+					HoursSelection: rawHoursSelection{
+						Type:        transaction.HoursSelectionTypeAuto,
+						Mode:        transaction.HoursSelectionModeShare,
+						ShareFactor: newStrPtr("0.5"),
+					},
+					To: []rawReceiver{
+						{
+							Address: destinationAddress.String(),
+							Coins:   "100",
+						},
+					},
+					ExcludeUxOuts:     []string{walletInput.Hex()},
+					AvoidAddressReuse: true,
+				},
+				WalletID: "foo.wlt",
+			},
+			status:                         http.StatusOK,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			createTransactionResponse:      createTxnResponse,
+		},
+
 		{
 			name:   "200 - auto type split even",
 			method: http.MethodPost,
@@ -1724,6 +2090,15 @@ func TestWalletCreateTransaction(t *testing.T) {
 			gatewayCreateTransactionErr: wallet.ErrWalletAPIDisabled,
 			err:                         "403 Forbidden",
 		},
+
+		{
+			name:                        "403 - spend limit exceeded",
+			method:                      http.MethodPost,
+			body:                        validBody,
+			status:                      http.StatusForbidden,
+			gatewayCreateTransactionErr: wallet.SpendLimitError{Limit: 1e6, Spent: 5e5, Amount: 1e6},
+			err:                         "403 Forbidden - transaction sends 1.000000 coins, exceeding the daily spend limit of 1.000000 coins (0.500000 coins already sent today)",
+		},
 	}
 
 	cases := make([]testCase, len(baseCases)*2)
@@ -2059,6 +2434,15 @@ func TestWalletSignTransaction(t *testing.T) {
 			httpResponse:              NewHTTPErrorResponse(http.StatusForbidden, "wallet api is disabled"),
 		},
 
+		{
+			name:                      "403 - spend limit exceeded",
+			method:                    http.MethodPost,
+			body:                      validBody,
+			status:                    http.StatusForbidden,
+			gatewaySignTransactionErr: wallet.SpendLimitError{Limit: 1e6, Amount: 2e6},
+			httpResponse:              NewHTTPErrorResponse(http.StatusForbidden, "transaction sends 2.000000 coins, exceeding the daily spend limit of 1.000000 coins (0.000000 coins already sent today)"),
+		},
+
 		{
 			name:                         "200 - no password",
 			method:                       http.MethodPost,
@@ -2191,3 +2575,325 @@ func TestWalletSignTransaction(t *testing.T) {
 		})
 	}
 }
+
+type rawWalletSpend struct {
+	ID        string   `json:"id"`
+	Password  string   `json:"password,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type rawWalletsCreateTxnRequest struct {
+	Wallets []rawWalletSpend `json:"wallets"`
+	rawCreateTxnRequest
+}
+
+func TestWalletsCreateTransaction(t *testing.T) {
+	destinationAddress := testutil.MakeAddress()
+	fooAddress := testutil.MakeAddress()
+
+	txn := &coin.Transaction{
+		Length:    100,
+		Type:      0,
+		InnerHash: testutil.RandSHA256(t),
+		Sigs:      []cipher.Sig{testutil.RandSig(t), testutil.RandSig(t)},
+		In:        []cipher.SHA256{testutil.RandSHA256(t), testutil.RandSHA256(t)},
+		Out: []coin.TransactionOutput{
+			{
+				Address: destinationAddress,
+				Coins:   3e6,
+				Hours:   10,
+			},
+		},
+	}
+
+	newInput := func(addr cipher.Address) visor.TransactionInput {
+		return visor.TransactionInput{
+			UxOut: coin.UxOut{
+				Head: coin.UxHead{
+					Time:  uint64(time.Now().UTC().Unix()),
+					BkSeq: 9999,
+				},
+				Body: coin.UxBody{
+					SrcTransaction: testutil.RandSHA256(t),
+					Address:        addr,
+					Coins:          15e5,
+					Hours:          100,
+				},
+			},
+			CalculatedHours: 100,
+		}
+	}
+
+	inputs := []visor.TransactionInput{
+		newInput(fooAddress),
+		newInput(testutil.MakeAddress()),
+	}
+
+	txnResponse, err := NewCreateTransactionResponse(txn, inputs)
+	require.NoError(t, err)
+
+	createTxnRequest := rawCreateTxnRequest{
+		HoursSelection: rawHoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []rawReceiver{
+			{
+				Address: destinationAddress.String(),
+				Coins:   "3",
+				Hours:   "10",
+			},
+		},
+	}
+
+	validBody := rawWalletsCreateTxnRequest{
+		Wallets: []rawWalletSpend{
+			{
+				ID:        "foo.wlt",
+				Addresses: []string{fooAddress.String()},
+			},
+			{
+				ID:       "bar.wlt",
+				Password: "pwd",
+			},
+		},
+		rawCreateTxnRequest: createTxnRequest,
+	}
+
+	noWalletsBody := rawWalletsCreateTxnRequest{
+		rawCreateTxnRequest: createTxnRequest,
+	}
+
+	missingIDBody := rawWalletsCreateTxnRequest{
+		Wallets: []rawWalletSpend{
+			{
+				ID: "foo.wlt",
+			},
+			{
+				Password: "pwd",
+			},
+		},
+		rawCreateTxnRequest: createTxnRequest,
+	}
+
+	duplicateWalletsBody := rawWalletsCreateTxnRequest{
+		Wallets: []rawWalletSpend{
+			{
+				ID: "foo.wlt",
+			},
+			{
+				ID: "foo.wlt",
+			},
+		},
+		rawCreateTxnRequest: createTxnRequest,
+	}
+
+	duplicateAddressesBody := rawWalletsCreateTxnRequest{
+		Wallets: []rawWalletSpend{
+			{
+				ID:        "foo.wlt",
+				Addresses: []string{fooAddress.String(), fooAddress.String()},
+			},
+		},
+		rawCreateTxnRequest: createTxnRequest,
+	}
+
+	addressesBody := validBody
+	addressesBody.Addresses = []string{fooAddress.String()}
+
+	avoidAddressReuseBody := validBody
+	avoidAddressReuseBody.AvoidAddressReuse = true
+
+	tt := []struct {
+		name    string
+		method  string
+		status  int
+		body    rawWalletsCreateTxnRequest
+		rawBody string
+
+		gatewayWalletsCreateTransactionResult *coin.Transaction
+		gatewayWalletsCreateTransactionInputs []visor.TransactionInput
+		gatewayWalletsCreateTransactionErr    error
+
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+
+		{
+			name:         "400 - invalid json",
+			method:       http.MethodPost,
+			rawBody:      "{ca",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid character 'c' looking for beginning of object key string"),
+		},
+
+		{
+			name:         "400 - no wallets",
+			method:       http.MethodPost,
+			body:         noWalletsBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "wallets is empty"),
+		},
+
+		{
+			name:         "400 - missing wallet id",
+			method:       http.MethodPost,
+			body:         missingIDBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "wallets[1].id is required"),
+		},
+
+		{
+			name:         "400 - duplicate wallets",
+			method:       http.MethodPost,
+			body:         duplicateWalletsBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "wallets contains duplicate values"),
+		},
+
+		{
+			name:         "400 - duplicate wallet addresses",
+			method:       http.MethodPost,
+			body:         duplicateAddressesBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "wallets[0].addresses contains duplicate values"),
+		},
+
+		{
+			name:         "400 - addresses",
+			method:       http.MethodPost,
+			body:         addressesBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "unspents and addresses cannot be used, set the addresses of each wallet instead"),
+		},
+
+		{
+			name:         "400 - avoid address reuse",
+			method:       http.MethodPost,
+			body:         avoidAddressReuseBody,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "exclude_unspents and avoid_address_reuse cannot be used when spending from several wallets"),
+		},
+
+		{
+			name:                               "400 - insufficient balance",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusBadRequest,
+			gatewayWalletsCreateTransactionErr: transaction.ErrInsufficientBalance,
+			httpResponse:                       NewHTTPErrorResponse(http.StatusBadRequest, "balance is not sufficient"),
+		},
+
+		{
+			name:                               "400 - missing password",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusBadRequest,
+			gatewayWalletsCreateTransactionErr: wallet.ErrMissingPassword,
+			httpResponse:                       NewHTTPErrorResponse(http.StatusBadRequest, "missing password"),
+		},
+
+		{
+			name:                               "403 - spend limit",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusForbidden,
+			gatewayWalletsCreateTransactionErr: wallet.SpendLimitError{Limit: 1e6, Amount: 3e6},
+			httpResponse:                       NewHTTPErrorResponse(http.StatusForbidden, wallet.SpendLimitError{Limit: 1e6, Amount: 3e6}.Error()),
+		},
+
+		{
+			name:                               "403 - wallet API disabled",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusForbidden,
+			gatewayWalletsCreateTransactionErr: wallet.ErrWalletAPIDisabled,
+			httpResponse:                       NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+
+		{
+			name:                               "404 - wallet not found",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusNotFound,
+			gatewayWalletsCreateTransactionErr: wallet.ErrWalletNotExist,
+			httpResponse:                       NewHTTPErrorResponse(http.StatusNotFound, "wallet doesn't exist"),
+		},
+
+		{
+			name:                               "500 - other error",
+			method:                             http.MethodPost,
+			body:                               validBody,
+			status:                             http.StatusInternalServerError,
+			gatewayWalletsCreateTransactionErr: errors.New("foo"),
+			httpResponse:                       NewHTTPErrorResponse(http.StatusInternalServerError, "foo"),
+		},
+
+		{
+			name:                                  "200",
+			method:                                http.MethodPost,
+			body:                                  validBody,
+			status:                                http.StatusOK,
+			gatewayWalletsCreateTransactionResult: txn,
+			gatewayWalletsCreateTransactionInputs: inputs,
+			httpResponse: HTTPResponse{
+				Data: *txnResponse,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+
+			serializedBody, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			var body walletsCreateTransactionRequest
+			err = json.Unmarshal(serializedBody, &body)
+			require.NoError(t, err)
+
+			gateway.On("WalletsCreateTransactionSigned", body.WalletSpends(), body.TransactionParams(), body.IgnoreUnconfirmed).Return(tc.gatewayWalletsCreateTransactionResult, tc.gatewayWalletsCreateTransactionInputs, tc.gatewayWalletsCreateTransactionErr)
+
+			endpoint := "/api/v2/wallets/transaction"
+
+			bodyText := []byte(tc.rawBody)
+			if len(bodyText) == 0 {
+				bodyText = serializedBody
+			}
+
+			req, err := http.NewRequest(tc.method, endpoint, bytes.NewBuffer(bodyText))
+			require.NoError(t, err)
+			req.Header.Add("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v` (%v)", status, tc.status, rr.Body)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var msg CreateTransactionResponse
+				err := json.Unmarshal(rsp.Data, &msg)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.httpResponse.Data.(CreateTransactionResponse), msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestTimeoutDefault is the endpoint of the request timeout of the endpoints without their own request timeout
+const RequestTimeoutDefault = "default"
+
+// RequestTimeout limits the time spent by the node on the requests to an endpoint.
+// The gateway calls of a request that exceeds its timeout are aborted and the request fails with 504 Gateway Timeout.
+type RequestTimeout struct {
+	// Endpoint is the path of the endpoint, e.g. /api/v1/transactions, or RequestTimeoutDefault
+	Endpoint string
+	Timeout  time.Duration
+}
+
+// ParseRequestTimeouts parses a comma-separated list of request timeouts, in the format
+// <endpoint>=<duration>, e.g. "default=30s,/api/v1/transactions=2m".
+// The endpoint "default" applies to the endpoints without their own timeout.
+// The requests are not limited if there is no timeout for their endpoint and no default timeout.
+func ParseRequestTimeouts(s string) ([]RequestTimeout, error) {
+	var timeouts []RequestTimeout
+	seen := make(map[string]struct{})
+
+	for _, t := range splitCommaString(s) {
+		pts := strings.SplitN(t, "=", 2)
+		if len(pts) != 2 || pts[0] == "" || pts[1] == "" {
+			return nil, fmt.Errorf("invalid request timeout %q, must be <endpoint>=<duration>", t)
+		}
+
+		endpoint := strings.TrimSpace(pts[0])
+		if endpoint != RequestTimeoutDefault && !strings.HasPrefix(endpoint, "/api/") {
+			return nil, fmt.Errorf("invalid request timeout endpoint %q, must be %s or an endpoint path starting with /api/", endpoint, RequestTimeoutDefault)
+		}
+
+		if _, ok := seen[endpoint]; ok {
+			return nil, fmt.Errorf("duplicate request timeout endpoint %q", endpoint)
+		}
+		seen[endpoint] = struct{}{}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(pts[1]))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid duration %q of request timeout %q, must be a positive duration", pts[1], endpoint)
+		}
+
+		timeouts = append(timeouts, RequestTimeout{
+			Endpoint: endpoint,
+			Timeout:  timeout,
+		})
+	}
+
+	return timeouts, nil
+}
+
+// requestTimeouts returns the timeouts of the request timeouts by endpoint
+func requestTimeouts(timeouts []RequestTimeout) map[string]time.Duration {
+	m := make(map[string]time.Duration, len(timeouts))
+	for _, t := range timeouts {
+		m[t.Endpoint] = t.Timeout
+	}
+	return m
+}
+
+// requestTimeoutHandler sets the timeout of the endpoint, or the default timeout, as the deadline of the request context
+func requestTimeoutHandler(endpoint string, timeouts map[string]time.Duration, handler http.Handler) http.Handler {
+	timeout, ok := timeouts[endpoint]
+	if !ok {
+		timeout, ok = timeouts[RequestTimeoutDefault]
+	}
+	if !ok {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// writeContextError writes the error of a gateway call aborted by the end of the request context,
+// 504 Gateway Timeout if the request timeout was exceeded and 503 Service Unavailable if the client
+// disconnected. Returns false if err was not caused by the request context.
+func writeContextError(w http.ResponseWriter, apiVersion string, err error) bool {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, apiVersion, http.StatusGatewayTimeout, "Request timeout exceeded")
+	case errors.Is(err, context.Canceled):
+		writeError(w, apiVersion, http.StatusServiceUnavailable, "Request canceled")
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func TestParseRequestTimeouts(t *testing.T) {
+	tt := []struct {
+		name     string
+		s        string
+		timeouts []RequestTimeout
+		err      string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "request timeouts",
+			s:    "default=30s, /api/v1/transactions=2m",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: RequestTimeoutDefault,
+					Timeout:  30 * time.Second,
+				},
+				{
+					Endpoint: "/api/v1/transactions",
+					Timeout:  2 * time.Minute,
+				},
+			},
+		},
+		{
+			name: "missing duration",
+			s:    "default",
+			err:  "invalid request timeout \"default\", must be <endpoint>=<duration>",
+		},
+		{
+			name: "invalid endpoint",
+			s:    "transactions=1s",
+			err:  "invalid request timeout endpoint \"transactions\", must be default or an endpoint path starting with /api/",
+		},
+		{
+			name: "duplicate endpoint",
+			s:    "default=1s,default=2s",
+			err:  "duplicate request timeout endpoint \"default\"",
+		},
+		{
+			name: "invalid duration",
+			s:    "default=10",
+			err:  "invalid duration \"10\" of request timeout \"default\", must be a positive duration",
+		},
+		{
+			name: "zero duration",
+			s:    "/api/v1/outputs=0s",
+			err:  "invalid duration \"0s\" of request timeout \"/api/v1/outputs\", must be a positive duration",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			timeouts, err := ParseRequestTimeouts(tc.s)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.err, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.timeouts, timeouts)
+		})
+	}
+}
+
+// waitDeadline waits for the end of the context if it has a deadline
+func waitDeadline(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("no deadline")
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRequestTimeoutHandler(t *testing.T) {
+	tt := []struct {
+		name     string
+		endpoint string
+		timeouts []RequestTimeout
+		cancel   bool
+		status   int
+		body     string
+	}{
+		{
+			name:     "no timeout",
+			endpoint: "/api/v1/outputs",
+			status:   http.StatusInternalServerError,
+			body:     "500 Internal Server Error - gateway.GetUnspentOutputsSummary failed: no deadline\n",
+		},
+		{
+			name:     "default timeout",
+			endpoint: "/api/v1/outputs",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: RequestTimeoutDefault,
+					Timeout:  10 * time.Millisecond,
+				},
+			},
+			status: http.StatusGatewayTimeout,
+			body:   "504 Gateway Timeout - Request timeout exceeded\n",
+		},
+		{
+			name:     "endpoint timeout",
+			endpoint: "/api/v1/outputs",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: "/api/v1/outputs",
+					Timeout:  10 * time.Millisecond,
+				},
+			},
+			status: http.StatusGatewayTimeout,
+			body:   "504 Gateway Timeout - Request timeout exceeded\n",
+		},
+		{
+			name:     "timeout of other endpoint",
+			endpoint: "/api/v1/outputs",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: "/api/v1/transactions",
+					Timeout:  10 * time.Millisecond,
+				},
+			},
+			status: http.StatusInternalServerError,
+			body:   "500 Internal Server Error - gateway.GetUnspentOutputsSummary failed: no deadline\n",
+		},
+		{
+			name:     "client disconnected",
+			endpoint: "/api/v1/outputs",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: RequestTimeoutDefault,
+					Timeout:  time.Hour,
+				},
+			},
+			cancel: true,
+			status: http.StatusServiceUnavailable,
+			body:   "503 Service Unavailable - Request canceled\n",
+		},
+		{
+			name:     "v2 timeout",
+			endpoint: "/api/v2/transactions",
+			timeouts: []RequestTimeout{
+				{
+					Endpoint: RequestTimeoutDefault,
+					Timeout:  10 * time.Millisecond,
+				},
+			},
+			status: http.StatusGatewayTimeout,
+			body:   "{\n    \"error\": {\n        \"message\": \"Request timeout exceeded\",\n        \"code\": 504\n    }\n}",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetUnspentOutputsSummary", mock.Anything, mock.Anything).Return(nil, func(ctx context.Context, filters []visor.OutputsFilter) error {
+				return waitDeadline(ctx)
+			})
+			gateway.On("GetTransactions", mock.Anything, mock.Anything, visor.AscOrder, mock.Anything).Return(nil, visor.TxnPage{}, func(ctx context.Context, flts []visor.TxFilter, order visor.SortOrder, page *visor.PageIndex) error {
+				return waitDeadline(ctx)
+			})
+
+			cfg := defaultMuxConfig()
+			cfg.requestTimeouts = tc.timeouts
+			handler := newServerMux(cfg, gateway)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			req, err := http.NewRequest(http.MethodGet, tc.endpoint, nil)
+			require.NoError(t, err)
+			req = req.WithContext(ctx)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+			require.Equal(t, tc.body, rr.Body.String())
+		})
+	}
+}
+
+func TestWriteContextError(t *testing.T) {
+	rr := httptest.NewRecorder()
+	require.False(t, writeContextError(rr, apiVersion2, errors.New("failure")))
+	require.Equal(t, 0, rr.Body.Len())
+
+	rr = httptest.NewRecorder()
+	require.True(t, writeContextError(rr, apiVersion2, context.DeadlineExceeded))
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+
+	var resp HTTPResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Equal(t, NewHTTPErrorResponse(http.StatusGatewayTimeout, "Request timeout exceeded"), resp)
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// ScopeRead tokens can access the READ and STATUS API sets
+	ScopeRead = "read"
+	// ScopeWallet tokens can query the WALLET API set with GET requests.
+	// They can not create, sign or inject transactions, nor change the wallets.
+	ScopeWallet = "wallet"
+	// ScopeAdmin tokens can access all API sets
+	ScopeAdmin = "admin"
+)
+
+// APIToken is a bearer token of the API and the scopes it can access
+type APIToken struct {
+	Token  string
+	Scopes []string
+}
+
+// ParseAPITokens parses a comma-separated list of tokens with their scopes,
+// in the format <token>:<scope>[+<scope>...], e.g. "foo:read+wallet,bar:admin"
+func ParseAPITokens(s string) ([]APIToken, error) {
+	var tokens []APIToken
+	seen := make(map[string]struct{})
+
+	for _, t := range splitCommaString(s) {
+		pts := strings.SplitN(t, ":", 2)
+		if len(pts) != 2 || pts[0] == "" || pts[1] == "" {
+			return nil, fmt.Errorf("invalid API token %q, must be <token>:<scope>[+<scope>...]", t)
+		}
+
+		if _, ok := seen[pts[0]]; ok {
+			return nil, fmt.Errorf("duplicate API token %q", pts[0])
+		}
+		seen[pts[0]] = struct{}{}
+
+		scopes := strings.Split(pts[1], "+")
+		for _, scope := range scopes {
+			switch scope {
+			case ScopeRead, ScopeWallet, ScopeAdmin:
+			default:
+				return nil, fmt.Errorf("invalid scope %q of API token %q, must be %s, %s or %s", scope, pts[0], ScopeRead, ScopeWallet, ScopeAdmin)
+			}
+		}
+
+		tokens = append(tokens, APIToken{
+			Token:  pts[0],
+			Scopes: scopes,
+		})
+	}
+
+	return tokens, nil
+}
+
+type tokenScopesKey struct{}
+
+// tokenScopes returns the scopes of the bearer token of the request.
+// ok is false if the request was not authenticated by a token.
+func tokenScopes(r *http.Request) (scopes map[string]struct{}, ok bool) {
+	scopes, ok = r.Context().Value(tokenScopesKey{}).(map[string]struct{})
+	return
+}
+
+// tokenScopesByHash returns the scopes of the tokens by the hash of the token.
+// Tokens are looked up by their hash, so that the lookup time does not depend on the token.
+func tokenScopesByHash(tokens []APIToken) map[cipher.SHA256]map[string]struct{} {
+	m := make(map[cipher.SHA256]map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		scopes := make(map[string]struct{}, len(t.Scopes))
+		for _, s := range t.Scopes {
+			scopes[s] = struct{}{}
+		}
+		m[cipher.SumSHA256([]byte(t.Token))] = scopes
+	}
+	return m
+}
+
+// bearerTokenHash returns the hash of the "Authorization: Bearer <token>" header of the request.
+// ok is false if the request has no bearer token.
+func bearerTokenHash(r *http.Request) (hash cipher.SHA256, ok bool) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return cipher.SHA256{}, false
+	}
+	return cipher.SumSHA256([]byte(strings.TrimPrefix(auth, "Bearer "))), true
+}
+
+// scopesAllow returns true if the scopes can access the endpoint method of one of the API sets
+func scopesAllow(scopes map[string]struct{}, method string, apiSets []string) bool {
+	if _, ok := scopes[ScopeAdmin]; ok {
+		return true
+	}
+
+	_, read := scopes[ScopeRead]
+	_, wlt := scopes[ScopeWallet]
+
+	for _, k := range apiSets {
+		switch k {
+		case EndpointsRead, EndpointsStatus:
+			if read {
+				return true
+			}
+		case EndpointsWallet:
+			if wlt && method == http.MethodGet {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tokenAllows returns true if the request was not authenticated by a token,
+// or if the scopes of its token can access the endpoint method of one of the API sets
+func tokenAllows(r *http.Request, method string, apiSets []string) bool {
+	scopes, ok := tokenScopes(r)
+	return !ok || scopesAllow(scopes, method, apiSets)
+}
+
+// tokenAuth authenticates the requests with an "Authorization: Bearer <token>" header
+// and passes the scopes of the token to f in the request context.
+// The other requests are passed to basicAuth. If tokens are configured without
+// a username and password, requests without a token are rejected.
+func tokenAuth(apiVersion string, tokens []APIToken, username, password, realm string, f http.Handler) http.HandlerFunc {
+	basic := basicAuth(apiVersion, username, password, realm, f)
+	if len(tokens) == 0 {
+		return basic
+	}
+
+	tokenScopesMap := tokenScopesByHash(tokens)
+
+	basicAuthEnabled := username != "" || password != ""
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		hash, ok := bearerTokenHash(r)
+		if !ok {
+			if !basicAuthEnabled {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", realm))
+				writeError(w, apiVersion, http.StatusUnauthorized, "")
+				return
+			}
+
+			basic.ServeHTTP(w, r)
+			return
+		}
+
+		scopes, ok := tokenScopesMap[hash]
+		if !ok {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q, error=\"invalid_token\"", realm))
+			writeError(w, apiVersion, http.StatusUnauthorized, "")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), tokenScopesKey{}, scopes)
+		f.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestParseAPITokens(t *testing.T) {
+	tt := []struct {
+		name   string
+		s      string
+		tokens []APIToken
+		err    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "tokens",
+			s:    "foo:read+wallet, bar:admin",
+			tokens: []APIToken{
+				{
+					Token:  "foo",
+					Scopes: []string{ScopeRead, ScopeWallet},
+				},
+				{
+					Token:  "bar",
+					Scopes: []string{ScopeAdmin},
+				},
+			},
+		},
+		{
+			name: "missing scopes",
+			s:    "foo",
+			err:  "invalid API token \"foo\", must be <token>:<scope>[+<scope>...]",
+		},
+		{
+			name: "empty token",
+			s:    ":read",
+			err:  "invalid API token \":read\", must be <token>:<scope>[+<scope>...]",
+		},
+		{
+			name: "invalid scope",
+			s:    "foo:read+spend",
+			err:  "invalid scope \"spend\" of API token \"foo\", must be read, wallet or admin",
+		},
+		{
+			name: "duplicate token",
+			s:    "foo:read,foo:admin",
+			err:  "duplicate API token \"foo\"",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := ParseAPITokens(tc.s)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.err, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.tokens, tokens)
+		})
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	tokens := []APIToken{
+		{
+			Token:  "reader",
+			Scopes: []string{ScopeRead},
+		},
+		{
+			Token:  "dashboard",
+			Scopes: []string{ScopeRead, ScopeWallet},
+		},
+		{
+			Token:  "root",
+			Scopes: []string{ScopeAdmin},
+		},
+	}
+
+	tt := []struct {
+		name     string
+		method   string
+		endpoint string
+		token    string
+		username string
+		password string
+		status   int
+	}{
+		{
+			name:     "401 missing token",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/blockchain/metadata",
+			status:   http.StatusUnauthorized,
+		},
+		{
+			name:     "401 invalid token",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/blockchain/metadata",
+			token:    "foo",
+			status:   http.StatusUnauthorized,
+		},
+		{
+			name:     "200 basic auth without token",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/blockchain/metadata",
+			username: "foo",
+			password: "bar",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "200 read scope",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/blockchain/metadata",
+			token:    "reader",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "200 version without API set",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/version",
+			token:    "reader",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "403 read scope wallet endpoint",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/wallet/balance?id=foo.wlt",
+			token:    "reader",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "200 wallet scope wallet endpoint",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/wallet/balance?id=foo.wlt",
+			token:    "dashboard",
+			status:   http.StatusOK,
+		},
+		{
+			name:     "403 wallet scope create transaction",
+			method:   http.MethodPost,
+			endpoint: "/api/v1/wallet/transaction",
+			token:    "dashboard",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "403 wallet scope inject transaction",
+			method:   http.MethodPost,
+			endpoint: "/api/v1/injectTransaction",
+			token:    "dashboard",
+			status:   http.StatusForbidden,
+		},
+		{
+			name:     "200 admin scope wallet endpoint",
+			method:   http.MethodGet,
+			endpoint: "/api/v1/wallet/balance?id=foo.wlt",
+			token:    "root",
+			status:   http.StatusOK,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetBlockchainMetadata").Return(&visor.BlockchainMetadata{}, nil)
+			gateway.On("GetWalletBalance", mock.Anything, "foo.wlt").Return(wallet.BalancePair{}, wallet.AddressBalances{}, nil)
+
+			req, err := http.NewRequest(tc.method, tc.endpoint, nil)
+			require.NoError(t, err)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			if tc.username != "" {
+				req.SetBasicAuth(tc.username, tc.password)
+			}
+
+			cfg := defaultMuxConfig()
+			cfg.tokens = tokens
+			cfg.username = tc.username
+			cfg.password = tc.password
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+		})
+	}
+}
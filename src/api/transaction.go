@@ -9,13 +9,18 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/fee"
 	wh "github.com/skycoin/skycoin/src/util/http"
 	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/util/timeutil"
 	"github.com/skycoin/skycoin/src/visor"
 )
 
@@ -24,6 +29,7 @@ import (
 // URI: /api/v1/pendingTxs
 // Args:
 //	verbose: [bool] include verbose transaction input data
+//	format: json or csv [optional, defaults to csv if the Accept header is text/csv, json otherwise]
 func pendingTxnsHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -37,7 +43,32 @@ func pendingTxnsHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		if verbose {
+		format, err := parseTransactionsFormat(r)
+		if err != nil {
+			wh.Error400(w, err.Error())
+			return
+		}
+
+		switch {
+		case format == transactionsFormatCSV:
+			utxns, inputs, err := gateway.GetAllUnconfirmedTransactionsVerbose()
+			if err != nil {
+				wh.Error500(w, err.Error())
+				return
+			}
+
+			txns := make([]visor.Transaction, len(utxns))
+			for i, utxn := range utxns {
+				txns[i] = visor.Transaction{
+					Transaction: utxn.Transaction,
+					Time:        uint64(timeutil.NanoToTime(utxn.Received).Unix()),
+				}
+			}
+
+			sortTransactionsByTime(txns, inputs)
+
+			sendTransactionsCSV(w, "pending_transactions.csv", txns, inputs, nil)
+		case verbose:
 			txns, inputs, err := gateway.GetAllUnconfirmedTransactionsVerbose()
 			if err != nil {
 				wh.Error500(w, err.Error())
@@ -51,7 +82,7 @@ func pendingTxnsHandler(gateway Gatewayer) http.HandlerFunc {
 			}
 
 			wh.SendJSONOr500(logger, w, vb)
-		} else {
+		default:
 			txns, err := gateway.GetAllUnconfirmedTransactions()
 			if err != nil {
 				wh.Error500(w, err.Error())
@@ -256,6 +287,13 @@ func NewTransactionsWithStatusVerbose(txns []visor.Transaction, inputs [][]visor
 //     addrs: Comma separated addresses [optional, returns all transactions if no address provided]
 //     confirmed: Whether the transactions should be confirmed [optional, must be 0 or 1; if not provided, returns all]
 //	   verbose: [bool] include verbose transaction input data
+//     page: Page number [optional, returns all transactions if none of page, limit or cursor is provided]
+//     limit: the number of transactions per page [optional, default to 10, must be <= 100]
+//     cursor: the next_cursor of the previous page, replaces page [optional]
+//     sort: Sort the transactions by block seq. [optional, must be desc or asc]; if not provided,
+//     return the transactions sorted by time.
+//     format: json or csv [optional, defaults to csv if the Accept header is text/csv, json otherwise]
+// The page info is returned in the X-Total-Count, X-Total-Pages and X-Next-Cursor headers of paginated requests.
 func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodPost {
@@ -291,10 +329,48 @@ func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 			flts = append(flts, visor.NewConfirmedTxFilter(confirmed))
 		}
 
-		if verbose {
-			txns, inputs, _, err := gateway.GetTransactionsWithInputs(flts, visor.AscOrder, nil)
+		order, err := parseSortOrderFromStr(r.FormValue("sort"))
+		if err != nil {
+			wh.Error400(w, fmt.Sprintf("invalid 'sort' value: %v", err))
+			return
+		}
+
+		pageIndex, _, err := parseTransactionsPageIndex(r)
+		if err != nil {
+			wh.Error400(w, err.Error())
+			return
+		}
+
+		format, err := parseTransactionsFormat(r)
+		if err != nil {
+			wh.Error400(w, err.Error())
+			return
+		}
+
+		// Without pagination or sort order, the transactions are sorted by time as they always were
+		sortByTime := pageIndex == nil && r.FormValue("sort") == ""
+
+		switch {
+		case format == transactionsFormatCSV:
+			txns, inputs, txnPage, err := gateway.GetTransactionsWithInputs(r.Context(), flts, order, pageIndex)
 			if err != nil {
-				wh.Error500(w, err.Error())
+				writeTransactionsError(w, err)
+				return
+			}
+
+			if sortByTime {
+				sortTransactionsByTime(txns, inputs)
+			}
+
+			if pageIndex != nil {
+				setTransactionsPageHeaders(w, txnPage)
+			}
+
+			sendTransactionsCSV(w, "transactions.csv", txns, inputs, addrs)
+		case verbose:
+			txns, inputs, txnPage, err := gateway.GetTransactionsWithInputs(r.Context(), flts, order, pageIndex)
+			if err != nil {
+				writeTransactionsError(w, err)
 				return
 			}
 
@@ -304,13 +380,19 @@ func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 				return
 			}
 
-			rTxns.Sort()
+			if sortByTime {
+				rTxns.Sort()
+			}
+
+			if pageIndex != nil {
+				setTransactionsPageHeaders(w, txnPage)
+			}
 
 			wh.SendJSONOr500(logger, w, rTxns.Transactions)
-		} else {
-			txns, _, err := gateway.GetTransactions(flts, visor.AscOrder, nil)
+		default:
+			txns, txnPage, err := gateway.GetTransactions(r.Context(), flts, order, pageIndex)
 			if err != nil {
-				wh.Error500(w, err.Error())
+				writeTransactionsError(w, err)
 				return
 			}
 
@@ -320,13 +402,111 @@ func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 				return
 			}
 
-			rTxns.Sort()
+			if sortByTime {
+				rTxns.Sort()
+			}
+
+			if pageIndex != nil {
+				setTransactionsPageHeaders(w, txnPage)
+			}
 
 			wh.SendJSONOr500(logger, w, rTxns.Transactions)
 		}
 	}
 }
 
+// parseTransactionsPageIndex parses the page, limit and cursor parameters of the transactions endpoints.
+// Returns a nil page if none of them is provided, and the current page number, 0 for cursor pages.
+func parseTransactionsPageIndex(r *http.Request) (*visor.PageIndex, uint64, error) {
+	pageStr := r.FormValue("page")
+	pageSizeStr := r.FormValue("limit")
+	cursorStr := r.FormValue("cursor")
+
+	if pageStr == "" && pageSizeStr == "" && cursorStr == "" {
+		return nil, 0, nil
+	}
+
+	var pageSize = visor.DefaultTxnPageSize
+	if pageSizeStr != "" {
+		var err error
+		pageSize, err = strconv.ParseUint(pageSizeStr, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid 'limit' value: %v", err)
+		}
+	}
+
+	if cursorStr != "" {
+		if pageStr != "" {
+			return nil, 0, errors.New("'page' and 'cursor' can not be combined")
+		}
+
+		cursor, err := cipher.SHA256FromHex(cursorStr)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid 'cursor' value: %v", err)
+		}
+
+		pageIndex, err := visor.NewCursorPageIndex(pageSize, cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return pageIndex, 0, nil
+	}
+
+	var currentPage = uint64(1)
+	if pageStr != "" {
+		var err error
+		currentPage, err = strconv.ParseUint(pageStr, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid 'page' value: %v", err)
+		}
+	}
+
+	pageIndex, err := visor.NewPageIndex(pageSize, currentPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pageIndex, currentPage, nil
+}
+
+// setTransactionsPageHeaders writes the page info of a paginated /api/v1/transactions request to the headers
+func setTransactionsPageHeaders(w http.ResponseWriter, p visor.TxnPage) {
+	w.Header().Set("X-Total-Count", strconv.FormatUint(p.Total, 10))
+	w.Header().Set("X-Total-Pages", strconv.FormatUint(p.TotalPages, 10))
+	if p.NextCursor != nil {
+		w.Header().Set("X-Next-Cursor", p.NextCursor.Hex())
+	}
+}
+
+// writeTransactionsError writes the error of a GetTransactions call of /api/v1/transactions
+func writeTransactionsError(w http.ResponseWriter, err error) {
+	if writeContextError(w, apiVersion1, err) {
+		return
+	}
+
+	switch err {
+	case visor.ErrTxnCursorNotFound:
+		wh.Error400(w, err.Error())
+	default:
+		wh.Error500(w, err.Error())
+	}
+}
+
+// newPageInfo creates the page info of a paginated transactions query
+func newPageInfo(p visor.TxnPage, pageSize, currentPage uint64) readable.PageInfo {
+	info := readable.PageInfo{
+		TotalPages:  p.TotalPages,
+		PageSize:    pageSize,
+		CurrentPage: currentPage,
+		Total:       p.Total,
+	}
+	if p.NextCursor != nil {
+		info.NextCursor = p.NextCursor.Hex()
+	}
+	return info
+}
+
 // Returns transactions that match the filters.
 // Method: GET, POST
 // URI: /api/v2/transactions
@@ -336,6 +516,7 @@ func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 //	   verbose: [bool] include verbose transaction input data
 //     page: Page number
 //     limit: the number of transactions per page [optional, default to 10, must be <= 100]
+//     cursor: the next_cursor of the previous page, replaces page [optional]
 //     sort: Sort the transactions by block seq. [optional, must be desc or asc]; if not provided, return
 //     in asc order.
 func transactionsHandlerV2(gateway Gatewayer) http.HandlerFunc {
@@ -364,104 +545,103 @@ func transactionsHandlerV2(gateway Gatewayer) http.HandlerFunc {
 			flts = append(flts, visor.NewAddrsFilter(addrs))
 		}
 
-		// Gets the 'confirmed' parameter value
-		confirmedStr := r.FormValue("confirmed")
-		if confirmedStr != "" {
-			confirmed, err := strconv.ParseBool(confirmedStr)
-			if err != nil {
-				writeError400Response(w, fmt.Sprintf("invalid 'confirmed' value: %v", err))
-				return
-			}
+		writeTransactionsPageV2(w, r, gateway, flts, verbose)
+	}
+}
 
-			flts = append(flts, visor.NewConfirmedTxFilter(confirmed))
+// writeTransactionsPageV2 writes a page of the transactions that match the filters,
+// with the confirmed, sort, page, limit and cursor parameters of /api/v2/transactions
+func writeTransactionsPageV2(w http.ResponseWriter, r *http.Request, gateway Gatewayer, flts []visor.TxFilter, verbose bool) {
+	// Gets the 'confirmed' parameter value
+	confirmedStr := r.FormValue("confirmed")
+	if confirmedStr != "" {
+		confirmed, err := strconv.ParseBool(confirmedStr)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid 'confirmed' value: %v", err))
+			return
 		}
 
-		order, err := parseSortOrderFromStr(r.FormValue("sort"))
+		flts = append(flts, visor.NewConfirmedTxFilter(confirmed))
+	}
+
+	order, err := parseSortOrderFromStr(r.FormValue("sort"))
+	if err != nil {
+		writeError400Response(w, fmt.Sprintf("invalid 'sort' value: %v", err))
+		return
+	}
+
+	pageIndex, currentPage, err := parseTransactionsPageIndex(r)
+	if err != nil {
+		writeError400Response(w, err.Error())
+		return
+	}
+
+	if pageIndex == nil {
+		currentPage = 1
+		pageIndex, err = visor.NewPageIndex(visor.DefaultTxnPageSize, currentPage)
 		if err != nil {
-			writeError400Response(w, fmt.Sprintf("invalid 'sort' value: %v", err))
+			writeError500Response(w, err.Error())
 			return
 		}
+	}
 
-		var pageSize = visor.DefaultTxnPageSize
-		pageSizeStr := r.FormValue("limit")
-		if pageSizeStr != "" {
-			var err error
-			pageSize, err = strconv.ParseUint(pageSizeStr, 10, 64)
-			if err != nil {
-				writeError400Response(w, fmt.Sprintf("invalid 'limit' value: %v", err))
-				return
-			}
+	var resp HTTPResponse
+	if verbose {
+		txns, inputs, txnPage, err := gateway.GetTransactionsWithInputs(r.Context(), flts, order, pageIndex)
+		if err != nil {
+			writeTransactionsErrorV2(w, err)
+			return
 		}
 
-		var pageIndex *visor.PageIndex
-		var currentPage = uint64(1)
-		pageStr := r.FormValue("page")
-		if pageStr != "" {
-			currentPage, err = strconv.ParseUint(pageStr, 10, 64)
-			if err != nil {
-				writeError400Response(w, fmt.Sprintf("invalid 'page' value: %v", err))
-				return
-			}
+		rTxns, err := NewTransactionsWithStatusVerbose(txns, inputs)
+		if err != nil {
+			writeError500Response(w, err.Error())
+			return
 		}
 
-		pageIndex, err = visor.NewPageIndex(pageSize, currentPage)
+		resp.Data = struct {
+			PageInfo readable.PageInfo                       `json:"page_info"`
+			Txns     []readable.TransactionWithStatusVerbose `json:"txns"`
+		}{
+			PageInfo: newPageInfo(txnPage, pageIndex.Size(), currentPage),
+			Txns:     rTxns.Transactions,
+		}
+		writeHTTPResponse(w, resp)
+	} else {
+		txns, txnPage, err := gateway.GetTransactions(r.Context(), flts, order, pageIndex)
 		if err != nil {
-			writeError400Response(w, err.Error())
+			writeTransactionsErrorV2(w, err)
 			return
 		}
 
-		var resp HTTPResponse
-		if verbose {
-			txns, inputs, pages, err := gateway.GetTransactionsWithInputs(flts, order, pageIndex)
-			if err != nil {
-				writeError500Response(w, err.Error())
-				return
-			}
-
-			rTxns, err := NewTransactionsWithStatusVerbose(txns, inputs)
-			if err != nil {
-				writeError500Response(w, err.Error())
-				return
-			}
+		rTxns, err := NewTransactionsWithStatus(txns)
+		if err != nil {
+			writeError500Response(w, err.Error())
+			return
+		}
 
-			resp.Data = struct {
-				PageInfo readable.PageInfo                       `json:"page_info"`
-				Txns     []readable.TransactionWithStatusVerbose `json:"txns"`
-			}{
-				PageInfo: readable.PageInfo{
-					TotalPages:  pages,
-					PageSize:    pageSize,
-					CurrentPage: currentPage,
-				},
-				Txns: rTxns.Transactions,
-			}
-			writeHTTPResponse(w, resp)
-		} else {
-			txns, pages, err := gateway.GetTransactions(flts, order, pageIndex)
-			if err != nil {
-				writeError500Response(w, err.Error())
-				return
-			}
+		resp.Data = struct {
+			PageInfo readable.PageInfo                `json:"page_info"`
+			Txns     []readable.TransactionWithStatus `json:"txns"`
+		}{
+			PageInfo: newPageInfo(txnPage, pageIndex.Size(), currentPage),
+			Txns:     rTxns.Transactions,
+		}
+		writeHTTPResponse(w, resp)
+	}
+}
 
-			rTxns, err := NewTransactionsWithStatus(txns)
-			if err != nil {
-				writeError500Response(w, err.Error())
-				return
-			}
+// writeTransactionsErrorV2 writes the error of a GetTransactions call of /api/v2/transactions
+func writeTransactionsErrorV2(w http.ResponseWriter, err error) {
+	if writeContextError(w, apiVersion2, err) {
+		return
+	}
 
-			resp.Data = struct {
-				PageInfo readable.PageInfo                `json:"page_info"`
-				Txns     []readable.TransactionWithStatus `json:"txns"`
-			}{
-				PageInfo: readable.PageInfo{
-					TotalPages:  pages,
-					PageSize:    pageSize,
-					CurrentPage: currentPage,
-				},
-				Txns: rTxns.Transactions,
-			}
-			writeHTTPResponse(w, resp)
-		}
+	switch err {
+	case visor.ErrTxnCursorNotFound:
+		writeError400Response(w, err.Error())
+	default:
+		writeError500Response(w, err.Error())
 	}
 }
 
@@ -726,6 +906,170 @@ func verifyTxnHandler(gateway Gatewayer) http.HandlerFunc {
 	}
 }
 
+// DecodeTransactionRequest represents the data struct of the request for /api/v2/transaction/decode
+type DecodeTransactionRequest struct {
+	EncodedTransaction string `json:"encoded_transaction"`
+}
+
+// DecodeTransactionResponse the response data struct for /api/v2/transaction/decode
+type DecodeTransactionResponse struct {
+	Unsigned  bool `json:"unsigned"`
+	Confirmed bool `json:"confirmed"`
+	// InputsFound is false if the inputs are not in the unspent pool nor in the historical archive of unspents.
+	// The inputs then only have their uxid, and the input totals and fees are not known.
+	InputsFound bool `json:"inputs_found"`
+
+	InputCoins  string `json:"input_coins,omitempty"`
+	InputHours  string `json:"input_hours,omitempty"`
+	OutputCoins string `json:"output_coins"`
+	OutputHours string `json:"output_hours"`
+	// RequiredFee is the minimum fee of the transaction for the burn factor of the network
+	RequiredFee string `json:"required_fee,omitempty"`
+
+	// Violations are the reasons why the transaction would be rejected by the network
+	Violations  []string           `json:"violations,omitempty"`
+	Transaction CreatedTransaction `json:"transaction"`
+}
+
+// Decode an encoded transaction and annotate it with its inputs, totals, fees and policy violations.
+// Unlike /api/v2/transaction/verify, a transaction that can be parsed but would be rejected by the network
+// is not an error, the reasons are listed in "violations".
+// Method: POST
+// URI: /api/v2/transaction/decode
+func decodeTxnHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req DecodeTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.EncodedTransaction == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "encoded_transaction is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txn, err := decodeTxn(req.EncodedTransaction)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("decode transaction failed: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		// Transactions missing signatures are decoded before they are signed,
+		// verify them as unsigned so that the missing signatures are not reported
+		unsigned := !txn.IsFullySigned()
+		signed := visor.TxnSigned
+		if unsigned {
+			signed = visor.TxnUnsigned
+		}
+
+		var violations []string
+		inputs, isTxnConfirmed, err := gateway.VerifyTxnVerbose(txn, signed)
+		if err != nil {
+			switch err.(type) {
+			case visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				violations = append(violations, err.Error())
+			default:
+				resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		if isTxnConfirmed {
+			violations = append(violations, "transaction has been spent")
+		}
+
+		if len(inputs) != len(txn.In) {
+			inputs = nil
+		}
+		verboseTxn, err := newCreatedTransactionFuzzy(txn, inputs)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		decodeResp := DecodeTransactionResponse{
+			Unsigned:    unsigned,
+			Confirmed:   isTxnConfirmed,
+			InputsFound: inputs != nil,
+			Violations:  violations,
+			Transaction: *verboseTxn,
+		}
+
+		if err := decodeResp.setTotals(txn, inputs); err != nil {
+			decodeResp.Violations = append(decodeResp.Violations, err.Error())
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: decodeResp,
+		})
+	}
+}
+
+// setTotals sets the coins and hours totals of the transaction outputs and inputs,
+// and the required fee if the inputs are known
+func (r *DecodeTransactionResponse) setTotals(txn *coin.Transaction, inputs []visor.TransactionInput) error {
+	var outputCoins, outputHours uint64
+	for _, o := range txn.Out {
+		var err error
+		outputCoins, err = mathutil.AddUint64(outputCoins, o.Coins)
+		if err != nil {
+			return errors.New("output coins overflow")
+		}
+		outputHours, err = mathutil.AddUint64(outputHours, o.Hours)
+		if err != nil {
+			return errors.New("output hours overflow")
+		}
+	}
+
+	coins, err := droplet.ToString(outputCoins)
+	if err != nil {
+		return err
+	}
+	r.OutputCoins = coins
+	r.OutputHours = fmt.Sprint(outputHours)
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	var inputCoins, inputHours uint64
+	for _, i := range inputs {
+		var err error
+		inputCoins, err = mathutil.AddUint64(inputCoins, i.UxOut.Body.Coins)
+		if err != nil {
+			return errors.New("input coins overflow")
+		}
+		inputHours, err = mathutil.AddUint64(inputHours, i.CalculatedHours)
+		if err != nil {
+			return errors.New("input hours overflow")
+		}
+	}
+
+	coins, err = droplet.ToString(inputCoins)
+	if err != nil {
+		return err
+	}
+	r.InputCoins = coins
+	r.InputHours = fmt.Sprint(inputHours)
+	r.RequiredFee = fmt.Sprint(fee.RequiredFee(inputHours, params.UserVerifyTxn.BurnFactor))
+
+	return nil
+}
+
 func decodeTxn(encodedTxn string) (*coin.Transaction, error) {
 	var txn coin.Transaction
 	b, err := hex.DecodeString(encodedTxn)
@@ -821,3 +1165,152 @@ func newCreatedTransactionFuzzy(txn *coin.Transaction, inputs []visor.Transactio
 		Out:  out,
 	}, nil
 }
+
+// TransactionStatusResponse is the response data struct for /api/v2/transaction/status
+type TransactionStatusResponse struct {
+	Txid string `json:"txid"`
+	// Status is "unknown", "pending", "confirmed" or "rejected"
+	Status string `json:"status"`
+	// ReceivedAt is the unix time the transaction was received by the unconfirmed pool
+	ReceivedAt int64 `json:"received_at,omitempty"`
+	// Peers is the number of peers the transaction was sent or announced to since the node started
+	Peers      int   `json:"peers"`
+	LastSentAt int64 `json:"last_sent_at,omitempty"`
+	// BlockSeq and Confirmations are set if the transaction is confirmed
+	BlockSeq      *uint64 `json:"block_seq,omitempty"`
+	Confirmations uint64  `json:"confirmations,omitempty"`
+	// Reason is set if the transaction is rejected
+	Reason     string `json:"reason,omitempty"`
+	RejectedAt int64  `json:"rejected_at,omitempty"`
+}
+
+// NewTransactionStatusResponse creates a TransactionStatusResponse from daemon.TransactionStatus
+func NewTransactionStatusResponse(s daemon.TransactionStatus) TransactionStatusResponse {
+	unixTime := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+
+	resp := TransactionStatusResponse{
+		Txid:       s.Txid.Hex(),
+		Status:     s.Status,
+		ReceivedAt: unixTime(s.ReceivedAt),
+		Peers:      s.Peers,
+		LastSentAt: unixTime(s.LastSentAt),
+		Reason:     s.Reason,
+		RejectedAt: unixTime(s.RejectedAt),
+	}
+
+	if s.Status == daemon.TxnStatusConfirmed {
+		seq := s.BlockSeq
+		resp.BlockSeq = &seq
+		resp.Confirmations = s.Confirmations
+	}
+
+	return resp
+}
+
+// transactionStatusHandler returns the lifecycle status of a transaction
+// Method: GET
+// URI: /api/v2/transaction/status
+// Args:
+//	txid: transaction ID hash [required]
+func transactionStatusHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		txidStr := r.FormValue("txid")
+		if txidStr == "" {
+			writeError400Response(w, "txid is required")
+			return
+		}
+
+		txid, err := cipher.SHA256FromHex(txidStr)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid txid: %v", err))
+			return
+		}
+
+		status, err := gateway.GetTransactionStatus(txid)
+		if err != nil {
+			writeError500Response(w, fmt.Sprintf("gateway.GetTransactionStatus failed: %v", err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewTransactionStatusResponse(*status),
+		})
+	}
+}
+
+// RebroadcastTransactionRequest is the request data struct for /api/v2/transaction/rebroadcast
+type RebroadcastTransactionRequest struct {
+	Txid string `json:"txid"`
+}
+
+// RebroadcastTransactionResponse is the response data struct for /api/v2/transaction/rebroadcast
+type RebroadcastTransactionResponse struct {
+	Txid string `json:"txid"`
+	// Peers is the number of peers the transaction was sent to
+	Peers int `json:"peers"`
+}
+
+// rebroadcastTransactionHandler broadcasts a transaction of the unconfirmed pool to all peers
+// Method: POST
+// URI: /api/v2/transaction/rebroadcast
+// Args:
+//	JSON body, see RebroadcastTransactionRequest
+func rebroadcastTransactionHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		var req RebroadcastTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError400Response(w, err.Error())
+			return
+		}
+
+		if req.Txid == "" {
+			writeError400Response(w, "txid is required")
+			return
+		}
+
+		txid, err := cipher.SHA256FromHex(req.Txid)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid txid: %v", err))
+			return
+		}
+
+		peers, err := gateway.RebroadcastTransaction(txid)
+		if err != nil {
+			var resp HTTPResponse
+			switch {
+			case err == daemon.ErrTxnNotPending:
+				resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+			case err == daemon.ErrTxnNotValid:
+				resp = NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error())
+			case daemon.IsBroadcastFailure(err):
+				resp = NewHTTPErrorResponse(http.StatusServiceUnavailable, err.Error())
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, fmt.Sprintf("gateway.RebroadcastTransaction failed: %v", err))
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: RebroadcastTransactionResponse{
+				Txid:  txid.Hex(),
+				Peers: peers,
+			},
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// UnsignedTransactionExportVersion is the version of the format of UnsignedTransactionExport
+const UnsignedTransactionExportVersion = 1
+
+// UnsignedTransactionExport is an unsigned transaction exported by POST /api/v2/transaction/export
+// to be signed offline. The inputs, outputs and fee of the transaction are fixed by its inner hash,
+// signing it only adds the signatures.
+type UnsignedTransactionExport struct {
+	Version int `json:"version"`
+	// Transaction describes the inputs, outputs and fee of the transaction, to review before signing it
+	Transaction CreatedTransaction `json:"transaction"`
+	// EncodedTransaction is the hex-encoded serialized unsigned transaction
+	EncodedTransaction string `json:"encoded_transaction"`
+	// ResolvedNames are the addresses of the receivers sent to by name
+	ResolvedNames []ResolvedName `json:"resolved_names,omitempty"`
+}
+
+// NewUnsignedTransactionExport creates an UnsignedTransactionExport
+func NewUnsignedTransactionExport(txn *coin.Transaction, inputs []visor.TransactionInput) (*UnsignedTransactionExport, error) {
+	r, err := NewCreateTransactionResponse(txn, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnsignedTransactionExport{
+		Version:            UnsignedTransactionExportVersion,
+		Transaction:        r.Transaction,
+		EncodedTransaction: r.EncodedTransaction,
+	}, nil
+}
+
+// Creates an unsigned transaction to sign offline, with its inputs and fee fixed.
+// The transaction is signed by the wallet of an offline (air-gapped) node with POST /api/v2/wallet/transaction/sign,
+// then imported with POST /api/v2/transaction/import to be injected.
+// Method: POST
+// URI: /api/v2/transaction/export
+// Args: JSON body, the same as POST /api/v2/transaction
+func transactionExportHandler(gateway Gatewayer, resolver AddressResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		txn, inputs, resolved, ok := createTransactionV2(w, r, gateway, resolver)
+		if !ok {
+			return
+		}
+
+		export, err := NewUnsignedTransactionExport(txn, inputs)
+		if err != nil {
+			writeError500Response(w, fmt.Sprintf("NewUnsignedTransactionExport failed: %v", err))
+			return
+		}
+		export.ResolvedNames = resolved
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: export,
+		})
+	}
+}
+
+// ImportTransactionRequest is sent to POST /api/v2/transaction/import
+type ImportTransactionRequest struct {
+	// EncodedTransaction is the hex-encoded serialized signed transaction
+	EncodedTransaction string `json:"encoded_transaction"`
+	// InnerHash is the inner hash of the exported transaction, which the signed transaction must match
+	InnerHash   string `json:"inner_hash"`
+	NoBroadcast bool   `json:"no_broadcast,omitempty"`
+}
+
+// ImportTransactionResponse is returned by POST /api/v2/transaction/import
+type ImportTransactionResponse struct {
+	Txid        string             `json:"txid"`
+	Transaction CreatedTransaction `json:"transaction"`
+}
+
+// Verifies a transaction signed offline and injects it.
+// The transaction must be fully signed and have the inputs and outputs of the exported transaction.
+// Method: POST
+// URI: /api/v2/transaction/import
+// Args: JSON body, see ImportTransactionRequest
+func transactionImportHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		var req ImportTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError400Response(w, err.Error())
+			return
+		}
+
+		if req.EncodedTransaction == "" {
+			writeError400Response(w, "encoded_transaction is required")
+			return
+		}
+
+		if req.InnerHash == "" {
+			writeError400Response(w, "inner_hash is required")
+			return
+		}
+
+		innerHash, err := cipher.SHA256FromHex(req.InnerHash)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("invalid inner_hash: %v", err))
+			return
+		}
+
+		txn, err := decodeTxn(req.EncodedTransaction)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("decode transaction failed: %v", err))
+			return
+		}
+
+		if txn.InnerHash != innerHash || txn.HashInner() != innerHash {
+			writeError400Response(w, "transaction does not match the inner hash of the exported transaction")
+			return
+		}
+
+		if !txn.IsFullySigned() {
+			writeError400Response(w, "transaction is not fully signed")
+			return
+		}
+
+		inputs, isTxnConfirmed, err := gateway.VerifyTxnVerbose(txn, visor.TxnSigned)
+		if err != nil {
+			switch err.(type) {
+			case visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error()))
+			default:
+				writeError500Response(w, err.Error())
+			}
+			return
+		}
+
+		if isTxnConfirmed {
+			writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusUnprocessableEntity, "transaction has been spent"))
+			return
+		}
+
+		if req.NoBroadcast {
+			err = gateway.InjectTransaction(*txn)
+		} else {
+			err = gateway.InjectBroadcastTransaction(*txn)
+		}
+		if err != nil {
+			switch err.(type) {
+			case visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error()))
+			default:
+				if daemon.IsBroadcastFailure(err) {
+					writeHTTPResponse(w, NewHTTPErrorResponse(http.StatusServiceUnavailable, err.Error()))
+				} else {
+					writeError500Response(w, err.Error())
+				}
+			}
+			return
+		}
+
+		cTxn, err := NewCreatedTransaction(txn, inputs)
+		if err != nil {
+			writeError500Response(w, fmt.Sprintf("NewCreatedTransaction failed: %v", err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: ImportTransactionResponse{
+				Txid:        txn.Hash().Hex(),
+				Transaction: *cTxn,
+			},
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func TestTransactionExportHandler(t *testing.T) {
+	signed := prepareTxnAndInputs(t)
+	unsigned := signed.txn
+	unsigned.Sigs = make([]cipher.Sig, len(unsigned.Sigs))
+	require.NoError(t, unsigned.UpdateHeader())
+
+	export, err := NewUnsignedTransactionExport(&unsigned, signed.inputs)
+	require.NoError(t, err)
+	require.Equal(t, UnsignedTransactionExportVersion, export.Version)
+	require.Equal(t, unsigned.InnerHash.Hex(), export.Transaction.InnerHash)
+
+	body, err := json.Marshal(rawCreateTxnRequest{
+		HoursSelection: rawHoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []rawReceiver{
+			{
+				Address: testutil.MakeAddress().String(),
+				Coins:   "1",
+				Hours:   "50",
+			},
+		},
+		ChangeAddress: testutil.MakeAddress().String(),
+		UxOuts:        []string{signed.txn.In[0].Hex()},
+	})
+	require.NoError(t, err)
+
+	tt := []struct {
+		name         string
+		method       string
+		body         string
+		createErr    error
+		status       int
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - EOF",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "400 - no addresses or unspents",
+			method:       http.MethodPost,
+			body:         strings.Replace(string(body), `"unspents"`, `"foo"`, 1),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "one of addresses or unspents must not be empty"),
+		},
+		{
+			name:         "400 - insufficient coin hours",
+			method:       http.MethodPost,
+			body:         string(body),
+			createErr:    fee.ErrTxnInsufficientCoinHours,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, fee.ErrTxnInsufficientCoinHours.Error()),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			body:   string(body),
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: export,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.createErr != nil {
+				gateway.On("CreateTransaction", mock.Anything, mock.Anything).Return(nil, nil, tc.createErr)
+			} else {
+				gateway.On("CreateTransaction", mock.Anything, mock.Anything).Return(&unsigned, signed.inputs, nil)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/transaction/export", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			expected, err := json.MarshalIndent(tc.httpResponse, "", "    ")
+			require.NoError(t, err)
+			require.Equal(t, string(expected), rr.Body.String())
+		})
+	}
+}
+
+func TestTransactionImportHandler(t *testing.T) {
+	signed := prepareTxnAndInputs(t)
+	unsigned := signed.txn
+	unsigned.Sigs = make([]cipher.Sig, len(unsigned.Sigs))
+	require.NoError(t, unsigned.UpdateHeader())
+
+	other := prepareTxnAndInputs(t)
+
+	cTxn, err := NewCreatedTransaction(&signed.txn, signed.inputs)
+	require.NoError(t, err)
+
+	importBody := func(encodedTxn, innerHash string, noBroadcast bool) string {
+		b, err := json.Marshal(ImportTransactionRequest{
+			EncodedTransaction: encodedTxn,
+			InnerHash:          innerHash,
+			NoBroadcast:        noBroadcast,
+		})
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	validBody := importBody(signed.txn.MustSerializeHex(), unsigned.InnerHash.Hex(), false)
+	violation := visor.NewErrTxnViolatesHardConstraint(visor.ErrTxnViolatesUserConstraint{})
+
+	tt := []struct {
+		name         string
+		method       string
+		body         string
+		gateway      func(*MockGatewayer)
+		status       int
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - missing encoded_transaction",
+			method:       http.MethodPost,
+			body:         importBody("", unsigned.InnerHash.Hex(), false),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "encoded_transaction is required"),
+		},
+		{
+			name:         "400 - missing inner_hash",
+			method:       http.MethodPost,
+			body:         importBody(signed.txn.MustSerializeHex(), "", false),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "inner_hash is required"),
+		},
+		{
+			name:         "400 - invalid inner_hash",
+			method:       http.MethodPost,
+			body:         importBody(signed.txn.MustSerializeHex(), "abcd", false),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid inner_hash: Invalid hex length"),
+		},
+		{
+			name:         "400 - other transaction",
+			method:       http.MethodPost,
+			body:         importBody(other.txn.MustSerializeHex(), unsigned.InnerHash.Hex(), false),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "transaction does not match the inner hash of the exported transaction"),
+		},
+		{
+			name:         "400 - not signed",
+			method:       http.MethodPost,
+			body:         importBody(unsigned.MustSerializeHex(), unsigned.InnerHash.Hex(), false),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "transaction is not fully signed"),
+		},
+		{
+			name:   "422 - invalid transaction",
+			method: http.MethodPost,
+			body:   validBody,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyTxnVerbose", &signed.txn, visor.TxnSigned).Return(nil, false, violation)
+			},
+			status:       http.StatusUnprocessableEntity,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnprocessableEntity, violation.Error()),
+		},
+		{
+			name:   "422 - confirmed",
+			method: http.MethodPost,
+			body:   validBody,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyTxnVerbose", &signed.txn, visor.TxnSigned).Return(signed.inputs, true, nil)
+			},
+			status:       http.StatusUnprocessableEntity,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnprocessableEntity, "transaction has been spent"),
+		},
+		{
+			name:   "503 - networking disabled",
+			method: http.MethodPost,
+			body:   validBody,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyTxnVerbose", &signed.txn, visor.TxnSigned).Return(signed.inputs, false, nil)
+				g.On("InjectBroadcastTransaction", signed.txn).Return(daemon.ErrNetworkingDisabled)
+			},
+			status:       http.StatusServiceUnavailable,
+			httpResponse: NewHTTPErrorResponse(http.StatusServiceUnavailable, daemon.ErrNetworkingDisabled.Error()),
+		},
+		{
+			name:   "200",
+			method: http.MethodPost,
+			body:   validBody,
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyTxnVerbose", &signed.txn, visor.TxnSigned).Return(signed.inputs, false, nil)
+				g.On("InjectBroadcastTransaction", signed.txn).Return(nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: ImportTransactionResponse{
+					Txid:        signed.txn.Hash().Hex(),
+					Transaction: *cTxn,
+				},
+			},
+		},
+		{
+			name:   "200 - no broadcast",
+			method: http.MethodPost,
+			body:   importBody(signed.txn.MustSerializeHex(), unsigned.InnerHash.Hex(), true),
+			gateway: func(g *MockGatewayer) {
+				g.On("VerifyTxnVerbose", &signed.txn, visor.TxnSigned).Return(signed.inputs, false, nil)
+				g.On("InjectTransaction", signed.txn).Return(nil)
+			},
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: ImportTransactionResponse{
+					Txid:        signed.txn.Hash().Hex(),
+					Transaction: *cTxn,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.gateway != nil {
+				tc.gateway(gateway)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/transaction/import", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			expected, err := json.MarshalIndent(tc.httpResponse, "", "    ")
+			require.NoError(t, err)
+			require.Equal(t, string(expected), rr.Body.String())
+
+			gateway.AssertExpectations(t)
+		})
+	}
+}
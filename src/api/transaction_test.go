@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math"
@@ -23,8 +24,11 @@ import (
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
 	"github.com/skycoin/skycoin/src/daemon/gnet"
+	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/readable"
 	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/fee"
 	"github.com/skycoin/skycoin/src/visor"
 )
 
@@ -1292,9 +1296,9 @@ func TestGetTransactions(t *testing.T) {
 				return true
 			})
 			var pageIndex *visor.PageIndex
-			gateway.On("GetTransactions", matchFunc, visor.AscOrder, pageIndex).Return(tc.getTransactionsResponse, uint64(0), tc.getTransactionsError)
-			gateway.On("GetTransactionsWithInputs", matchFunc, visor.AscOrder, pageIndex).Return(tc.getTransactionsVerboseResponse.Transactions,
-				tc.getTransactionsVerboseResponse.Inputs, uint64(0), tc.getTransactionsVerboseError)
+			gateway.On("GetTransactions", mock.Anything, matchFunc, visor.AscOrder, pageIndex).Return(tc.getTransactionsResponse, visor.TxnPage{}, tc.getTransactionsError)
+			gateway.On("GetTransactionsWithInputs", mock.Anything, matchFunc, visor.AscOrder, pageIndex).Return(tc.getTransactionsVerboseResponse.Transactions,
+				tc.getTransactionsVerboseResponse.Inputs, visor.TxnPage{}, tc.getTransactionsVerboseError)
 
 			v := url.Values{}
 			if tc.httpBody != nil {
@@ -1358,6 +1362,125 @@ func TestGetTransactions(t *testing.T) {
 	}
 }
 
+func TestGetTransactionsPaginated(t *testing.T) {
+	txns := []visor.Transaction{
+		{
+			Transaction: makeTransaction(t),
+			Status:      visor.TransactionStatus{Confirmed: true, BlockSeq: 101, Height: 2},
+			Time:        200,
+		},
+		{
+			Transaction: makeTransaction(t),
+			Status:      visor.TransactionStatus{Confirmed: true, BlockSeq: 100, Height: 3},
+			Time:        100,
+		},
+	}
+	next := txns[1].Transaction.Hash()
+
+	tt := []struct {
+		name             string
+		query            string
+		order            visor.SortOrder
+		pageIndex        func() *visor.PageIndex
+		txnPage          visor.TxnPage
+		err              error
+		status           int
+		errMsg           string
+		totalCountHeader string
+		totalPagesHeader string
+		nextCursorHeader string
+	}{
+		{
+			name:  "200 page limit sort=desc",
+			query: "?page=1&limit=2&sort=desc",
+			order: visor.DescOrder,
+			pageIndex: func() *visor.PageIndex {
+				p, err := visor.NewPageIndex(2, 1)
+				require.NoError(t, err)
+				return p
+			},
+			txnPage:          visor.TxnPage{TotalPages: 3, Total: 5, NextCursor: &next},
+			status:           http.StatusOK,
+			totalCountHeader: "5",
+			totalPagesHeader: "3",
+			nextCursorHeader: next.Hex(),
+		},
+		{
+			name:  "200 cursor last page",
+			query: "?cursor=" + next.Hex(),
+			order: visor.AscOrder,
+			pageIndex: func() *visor.PageIndex {
+				p, err := visor.NewCursorPageIndex(visor.DefaultTxnPageSize, next)
+				require.NoError(t, err)
+				return p
+			},
+			txnPage:          visor.TxnPage{TotalPages: 1, Total: 2},
+			status:           http.StatusOK,
+			totalCountHeader: "2",
+			totalPagesHeader: "1",
+		},
+		{
+			name:  "400 cursor not found",
+			query: "?cursor=" + next.Hex(),
+			order: visor.AscOrder,
+			pageIndex: func() *visor.PageIndex {
+				p, err := visor.NewCursorPageIndex(visor.DefaultTxnPageSize, next)
+				require.NoError(t, err)
+				return p
+			},
+			err:    visor.ErrTxnCursorNotFound,
+			status: http.StatusBadRequest,
+			errMsg: "400 Bad Request - cursor transaction not found",
+		},
+		{
+			name:   "400 invalid sort",
+			query:  "?sort=foo",
+			status: http.StatusBadRequest,
+			errMsg: "400 Bad Request - invalid 'sort' value: Unknown sort order",
+		},
+		{
+			name:   "400 limit too large",
+			query:  "?limit=101",
+			status: http.StatusBadRequest,
+			errMsg: "400 Bad Request - transaction page size must be not greater than 100",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.pageIndex != nil {
+				gateway.On("GetTransactions", mock.Anything, mock.Anything, tc.order, tc.pageIndex()).Return(txns, tc.txnPage, tc.err)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/transactions"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code)
+			if rr.Code != http.StatusOK {
+				require.Equal(t, tc.errMsg, strings.TrimSpace(rr.Body.String()))
+				return
+			}
+
+			require.Equal(t, tc.totalCountHeader, rr.Header().Get("X-Total-Count"))
+			require.Equal(t, tc.totalPagesHeader, rr.Header().Get("X-Total-Pages"))
+			require.Equal(t, tc.nextCursorHeader, rr.Header().Get("X-Next-Cursor"))
+
+			// Paginated transactions keep the order of the gateway, they are not sorted by time
+			var msg []readable.TransactionWithStatus
+			err = json.Unmarshal(rr.Body.Bytes(), &msg)
+			require.NoError(t, err)
+			require.Len(t, msg, 2)
+			require.Equal(t, txns[0].Transaction.Hash().Hex(), msg[0].Transaction.Hash)
+			require.Equal(t, txns[1].Transaction.Hash().Hex(), msg[1].Transaction.Hash)
+		})
+	}
+}
+
 func TestTransactionsHandlerV2(t *testing.T) {
 	var addrs []cipher.Address
 
@@ -1399,7 +1522,8 @@ func TestTransactionsHandlerV2(t *testing.T) {
 		verbose                      bool
 		gatewayGetTransactions       []visor.Transaction
 		gatewayGetTransactionsInputs [][]visor.TransactionInput
-		gatewayTotalPage             uint64
+		gatewayTxnPage               visor.TxnPage
+		gatewayErr                   error
 		expectStatusCode             int
 		expectErrMsg                 string
 		expectPageInfo               readable.PageInfo
@@ -1409,7 +1533,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			name:                   "GET no args",
 			method:                 "GET",
 			gatewayGetTransactions: txns,
-			gatewayTotalPage:       uint64(1),
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 1},
 			expectStatusCode:       200,
 			expectPageInfo:         readable.PageInfo{TotalPages: 1, CurrentPage: 1, PageSize: 10},
 			expectTxns:             expectTxns(t, txns, nil),
@@ -1419,7 +1543,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			method:                 "GET",
 			args:                   []string{"addrs=" + addrs[0].String()},
 			gatewayGetTransactions: txns[:5],
-			gatewayTotalPage:       uint64(1),
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 1},
 			expectStatusCode:       200,
 			expectPageInfo:         readable.PageInfo{TotalPages: 1, CurrentPage: 1, PageSize: 10},
 			expectTxns:             expectTxns(t, txns[:5], nil),
@@ -1429,7 +1553,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			method:                 "GET",
 			args:                   []string{"addrs=" + addrs[0].String() + "," + addrs[1].String()},
 			gatewayGetTransactions: txns[:11],
-			gatewayTotalPage:       uint64(2),
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 2},
 			expectStatusCode:       200,
 			expectPageInfo:         readable.PageInfo{TotalPages: 2, CurrentPage: 1, PageSize: 10},
 			expectTxns:             expectTxns(t, txns[:11], nil),
@@ -1439,7 +1563,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			method:                 "GET",
 			args:                   []string{"addrs=" + addrs[0].String(), "limit=1"},
 			gatewayGetTransactions: txns[:1],
-			gatewayTotalPage:       uint64(10),
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 10},
 			expectStatusCode:       200,
 			expectPageInfo:         readable.PageInfo{TotalPages: 10, CurrentPage: 1, PageSize: 1},
 			expectTxns:             expectTxns(t, txns[:1], nil),
@@ -1449,7 +1573,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			method:                 "GET",
 			args:                   []string{"addrs=" + addrs[0].String(), "limit=2"},
 			gatewayGetTransactions: txns[:2],
-			gatewayTotalPage:       uint64(5),
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 5},
 			expectStatusCode:       200,
 			expectPageInfo:         readable.PageInfo{TotalPages: 5, CurrentPage: 1, PageSize: 2},
 			expectTxns:             expectTxns(t, txns[:2], nil),
@@ -1461,11 +1585,65 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			verbose:                      true,
 			gatewayGetTransactions:       txns[:2],
 			gatewayGetTransactionsInputs: txnsInputs[:2],
-			gatewayTotalPage:             uint64(5),
+			gatewayTxnPage:               visor.TxnPage{TotalPages: 5},
 			expectStatusCode:             200,
 			expectPageInfo:               readable.PageInfo{TotalPages: 5, CurrentPage: 1, PageSize: 2},
 			expectTxns:                   expectTxns(t, txns[:2], txnsInputs[:2]),
 		},
+		{
+			name:                   "GET with addr limit=2 total and next cursor",
+			method:                 "GET",
+			args:                   []string{"addrs=" + addrs[0].String(), "limit=2"},
+			gatewayGetTransactions: txns[:2],
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 5, Total: 10, NextCursor: &txns[1].Transaction.InnerHash},
+			expectStatusCode:       200,
+			expectPageInfo: readable.PageInfo{
+				TotalPages:  5,
+				CurrentPage: 1,
+				PageSize:    2,
+				Total:       10,
+				NextCursor:  txns[1].Transaction.InnerHash.Hex(),
+			},
+			expectTxns: expectTxns(t, txns[:2], nil),
+		},
+		{
+			name:                   "GET with addr limit=2 cursor",
+			method:                 "GET",
+			args:                   []string{"addrs=" + addrs[0].String(), "limit=2", "cursor=" + txns[1].Transaction.InnerHash.Hex()},
+			gatewayGetTransactions: txns[2:4],
+			gatewayTxnPage:         visor.TxnPage{TotalPages: 5, Total: 10, NextCursor: &txns[3].Transaction.InnerHash},
+			expectStatusCode:       200,
+			expectPageInfo: readable.PageInfo{
+				TotalPages:  5,
+				CurrentPage: 0,
+				PageSize:    2,
+				Total:       10,
+				NextCursor:  txns[3].Transaction.InnerHash.Hex(),
+			},
+			expectTxns: expectTxns(t, txns[2:4], nil),
+		},
+		{
+			name:             "GET with cursor err=cursor not found",
+			method:           "GET",
+			args:             []string{"cursor=" + txns[1].Transaction.InnerHash.Hex()},
+			gatewayErr:       visor.ErrTxnCursorNotFound,
+			expectStatusCode: 400,
+			expectErrMsg:     "cursor transaction not found",
+		},
+		{
+			name:             "GET with page and cursor",
+			method:           "GET",
+			args:             []string{"page=2", "cursor=" + txns[1].Transaction.InnerHash.Hex()},
+			expectStatusCode: 400,
+			expectErrMsg:     "'page' and 'cursor' can not be combined",
+		},
+		{
+			name:             "GET with invalid cursor",
+			method:           "GET",
+			args:             []string{"cursor=foo"},
+			expectStatusCode: 400,
+			expectErrMsg:     "invalid 'cursor' value: encoding/hex: invalid byte: U+006F 'o'",
+		},
 		{
 			name:             "GET with addr limit=2 err=invalid page number",
 			method:           "GET",
@@ -1525,6 +1703,7 @@ func TestTransactionsHandlerV2(t *testing.T) {
 			flts := []visor.TxFilter{}
 			var page = uint64(1)
 			var pageSize = visor.DefaultTxnPageSize
+			var cursor *cipher.SHA256
 			for _, arg := range tc.args {
 				kv := strings.Split(arg, "=")
 				if len(kv) < 2 {
@@ -1547,12 +1726,20 @@ func TestTransactionsHandlerV2(t *testing.T) {
 					page, _ = strconv.ParseUint(kv[1], 10, 64) // nolint:errcheck
 				case "limit":
 					pageSize, _ = strconv.ParseUint(kv[1], 10, 64) // nolint:errcheck
+				case "cursor":
+					h, err := cipher.SHA256FromHex(kv[1])
+					if err == nil {
+						cursor = &h
+					}
 				}
 			}
 			pi, _ := visor.NewPageIndex(pageSize, page) // nolint:errcheck
+			if cursor != nil {
+				pi, _ = visor.NewCursorPageIndex(pageSize, *cursor) // nolint:errcheck
+			}
 
-			gateway.On("GetTransactions", flts, visor.AscOrder, pi).Return(tc.gatewayGetTransactions, tc.gatewayTotalPage, nil)
-			gateway.On("GetTransactionsWithInputs", flts, visor.AscOrder, pi).Return(tc.gatewayGetTransactions, tc.gatewayGetTransactionsInputs, tc.gatewayTotalPage, nil)
+			gateway.On("GetTransactions", mock.Anything, flts, visor.AscOrder, pi).Return(tc.gatewayGetTransactions, tc.gatewayTxnPage, tc.gatewayErr)
+			gateway.On("GetTransactionsWithInputs", mock.Anything, flts, visor.AscOrder, pi).Return(tc.gatewayGetTransactions, tc.gatewayGetTransactionsInputs, tc.gatewayTxnPage, tc.gatewayErr)
 
 			srv := newServerMux(cfg, gateway)
 			srv.ServeHTTP(rec, req)
@@ -1912,3 +2099,501 @@ func TestVerifyTransaction(t *testing.T) {
 		})
 	}
 }
+
+func newDecodeTxnResponse(t *testing.T, txn *coin.Transaction, inputs []visor.TransactionInput, isTxnConfirmed, isUnsigned bool, violations []string) DecodeTransactionResponse {
+	ctxn, err := newCreatedTransactionFuzzy(txn, inputs)
+	require.NoError(t, err)
+
+	outputHours, err := txn.OutputHours()
+	require.NoError(t, err)
+
+	var outputCoins uint64
+	for _, o := range txn.Out {
+		outputCoins += o.Coins
+	}
+
+	coins, err := droplet.ToString(outputCoins)
+	require.NoError(t, err)
+
+	rsp := DecodeTransactionResponse{
+		Unsigned:    isUnsigned,
+		Confirmed:   isTxnConfirmed,
+		InputsFound: len(inputs) != 0,
+		OutputCoins: coins,
+		OutputHours: fmt.Sprint(outputHours),
+		Violations:  violations,
+		Transaction: *ctxn,
+	}
+
+	if len(inputs) != 0 {
+		var inputCoins, inputHours uint64
+		for _, i := range inputs {
+			inputCoins += i.UxOut.Body.Coins
+			inputHours += i.CalculatedHours
+		}
+
+		rsp.InputCoins, err = droplet.ToString(inputCoins)
+		require.NoError(t, err)
+		rsp.InputHours = fmt.Sprint(inputHours)
+		rsp.RequiredFee = fmt.Sprint(fee.RequiredFee(inputHours, params.UserVerifyTxn.BurnFactor))
+	}
+
+	return rsp
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	txnAndInputs := prepareTxnAndInputs(t)
+	validTxnBodyJSON, err := json.Marshal(DecodeTransactionRequest{
+		EncodedTransaction: txnAndInputs.txn.MustSerializeHex(),
+	})
+	require.NoError(t, err)
+
+	unsignedTxnAndInputs := prepareTxnAndInputs(t)
+	unsignedTxnAndInputs.txn.Sigs = make([]cipher.Sig, len(unsignedTxnAndInputs.txn.Sigs))
+	err = unsignedTxnAndInputs.txn.UpdateHeader()
+	require.NoError(t, err)
+	unsignedTxnBodyJSON, err := json.Marshal(DecodeTransactionRequest{
+		EncodedTransaction: unsignedTxnAndInputs.txn.MustSerializeHex(),
+	})
+	require.NoError(t, err)
+
+	type verifyTxnVerboseResult struct {
+		Uxouts         []visor.TransactionInput
+		IsTxnConfirmed bool
+		Err            error
+	}
+
+	tt := []struct {
+		name                          string
+		method                        string
+		status                        int
+		httpBody                      string
+		gatewayVerifyTxnVerboseArg    coin.Transaction
+		gatewayVerifyTxnVerboseSigned visor.TxnSignedFlag
+		gatewayVerifyTxnVerboseResult verifyTxnVerboseResult
+		httpResponse                  HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - encoded_transaction is required",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     `{"wrongKey":"wrongValue"}`,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "encoded_transaction is required"),
+		},
+		{
+			name:         "400 - encoding/hex: odd length hex string",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     `{"encoded_transaction":"aab"}`,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "decode transaction failed: encoding/hex: odd length hex string"),
+		},
+		{
+			name:                          "500 - internal server error",
+			method:                        http.MethodPost,
+			status:                        http.StatusInternalServerError,
+			httpBody:                      string(validTxnBodyJSON),
+			gatewayVerifyTxnVerboseArg:    txnAndInputs.txn,
+			gatewayVerifyTxnVerboseSigned: visor.TxnSigned,
+			gatewayVerifyTxnVerboseResult: verifyTxnVerboseResult{
+				Err: errors.New("verify transaction failed"),
+			},
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "verify transaction failed"),
+		},
+		{
+			name:                          "200 - inputs not found",
+			method:                        http.MethodPost,
+			status:                        http.StatusOK,
+			httpBody:                      string(validTxnBodyJSON),
+			gatewayVerifyTxnVerboseArg:    txnAndInputs.txn,
+			gatewayVerifyTxnVerboseSigned: visor.TxnSigned,
+			gatewayVerifyTxnVerboseResult: verifyTxnVerboseResult{
+				Err: visor.NewErrTxnViolatesHardConstraint(errors.New("transaction input of foo does not exist in either unspent pool or historydb")),
+			},
+			httpResponse: HTTPResponse{
+				Data: newDecodeTxnResponse(t, &txnAndInputs.txn, nil, false, false, []string{
+					"Transaction violates hard constraint: transaction input of foo does not exist in either unspent pool or historydb",
+				}),
+			},
+		},
+		{
+			name:                          "200 - violates soft constraint and confirmed",
+			method:                        http.MethodPost,
+			status:                        http.StatusOK,
+			httpBody:                      string(validTxnBodyJSON),
+			gatewayVerifyTxnVerboseArg:    txnAndInputs.txn,
+			gatewayVerifyTxnVerboseSigned: visor.TxnSigned,
+			gatewayVerifyTxnVerboseResult: verifyTxnVerboseResult{
+				Uxouts:         txnAndInputs.inputs,
+				IsTxnConfirmed: true,
+				Err:            visor.NewErrTxnViolatesSoftConstraint(fee.ErrTxnInsufficientFee),
+			},
+			httpResponse: HTTPResponse{
+				Data: newDecodeTxnResponse(t, &txnAndInputs.txn, txnAndInputs.inputs, true, false, []string{
+					"Transaction violates soft constraint: Transaction coinhour fee minimum not met",
+					"transaction has been spent",
+				}),
+			},
+		},
+		{
+			name:                          "200 - unsigned",
+			method:                        http.MethodPost,
+			status:                        http.StatusOK,
+			httpBody:                      string(unsignedTxnBodyJSON),
+			gatewayVerifyTxnVerboseArg:    unsignedTxnAndInputs.txn,
+			gatewayVerifyTxnVerboseSigned: visor.TxnUnsigned,
+			gatewayVerifyTxnVerboseResult: verifyTxnVerboseResult{
+				Uxouts: unsignedTxnAndInputs.inputs,
+			},
+			httpResponse: HTTPResponse{
+				Data: newDecodeTxnResponse(t, &unsignedTxnAndInputs.txn, unsignedTxnAndInputs.inputs, false, true, nil),
+			},
+		},
+		{
+			name:                          "200",
+			method:                        http.MethodPost,
+			status:                        http.StatusOK,
+			httpBody:                      string(validTxnBodyJSON),
+			gatewayVerifyTxnVerboseArg:    txnAndInputs.txn,
+			gatewayVerifyTxnVerboseSigned: visor.TxnSigned,
+			gatewayVerifyTxnVerboseResult: verifyTxnVerboseResult{
+				Uxouts: txnAndInputs.inputs,
+			},
+			httpResponse: HTTPResponse{
+				Data: newDecodeTxnResponse(t, &txnAndInputs.txn, txnAndInputs.inputs, false, false, nil),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := "/api/v2/transaction/decode"
+			gateway := &MockGatewayer{}
+			gateway.On("VerifyTxnVerbose", &tc.gatewayVerifyTxnVerboseArg, tc.gatewayVerifyTxnVerboseSigned).Return(tc.gatewayVerifyTxnVerboseResult.Uxouts,
+				tc.gatewayVerifyTxnVerboseResult.IsTxnConfirmed, tc.gatewayVerifyTxnVerboseResult.Err)
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var txnRsp DecodeTransactionResponse
+				err := json.Unmarshal(rsp.Data, &txnRsp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.httpResponse.Data.(DecodeTransactionResponse), txnRsp)
+			}
+		})
+	}
+}
+
+func TestTransactionStatus(t *testing.T) {
+	newUint64Ptr := func(n uint64) *uint64 {
+		return &n
+	}
+
+	txid := testutil.RandSHA256(t)
+	receivedAt := time.Unix(1539849621, 0)
+	sentAt := time.Unix(1539849625, 0)
+
+	cases := []struct {
+		name          string
+		method        string
+		query         string
+		gatewayStatus *daemon.TransactionStatus
+		gatewayErr    error
+		status        int
+		err           string
+		statusRsp     *TransactionStatusResponse
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			query:  "?txid=" + txid.Hex(),
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - missing txid",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "txid is required",
+		},
+		{
+			name:   "400 - invalid txid",
+			method: http.MethodGet,
+			query:  "?txid=abc",
+			status: http.StatusBadRequest,
+			err:    "invalid txid: encoding/hex: odd length hex string",
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodGet,
+			query:      "?txid=" + txid.Hex(),
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "gateway.GetTransactionStatus failed: failed",
+		},
+		{
+			name:   "200 - unknown",
+			method: http.MethodGet,
+			query:  "?txid=" + txid.Hex(),
+			gatewayStatus: &daemon.TransactionStatus{
+				Txid:   txid,
+				Status: daemon.TxnStatusUnknown,
+			},
+			status: http.StatusOK,
+			statusRsp: &TransactionStatusResponse{
+				Txid:   txid.Hex(),
+				Status: daemon.TxnStatusUnknown,
+			},
+		},
+		{
+			name:   "200 - pending",
+			method: http.MethodGet,
+			query:  "?txid=" + txid.Hex(),
+			gatewayStatus: &daemon.TransactionStatus{
+				Txid:       txid,
+				Status:     daemon.TxnStatusPending,
+				ReceivedAt: receivedAt,
+				Peers:      3,
+				LastSentAt: sentAt,
+			},
+			status: http.StatusOK,
+			statusRsp: &TransactionStatusResponse{
+				Txid:       txid.Hex(),
+				Status:     daemon.TxnStatusPending,
+				ReceivedAt: receivedAt.Unix(),
+				Peers:      3,
+				LastSentAt: sentAt.Unix(),
+			},
+		},
+		{
+			name:   "200 - confirmed in the genesis block",
+			method: http.MethodGet,
+			query:  "?txid=" + txid.Hex(),
+			gatewayStatus: &daemon.TransactionStatus{
+				Txid:          txid,
+				Status:        daemon.TxnStatusConfirmed,
+				BlockSeq:      0,
+				Confirmations: 10,
+			},
+			status: http.StatusOK,
+			statusRsp: &TransactionStatusResponse{
+				Txid:          txid.Hex(),
+				Status:        daemon.TxnStatusConfirmed,
+				BlockSeq:      newUint64Ptr(0),
+				Confirmations: 10,
+			},
+		},
+		{
+			name:   "200 - rejected",
+			method: http.MethodGet,
+			query:  "?txid=" + txid.Hex(),
+			gatewayStatus: &daemon.TransactionStatus{
+				Txid:       txid,
+				Status:     daemon.TxnStatusRejected,
+				Reason:     "Transaction violates hard constraint: Insufficient coins",
+				RejectedAt: receivedAt,
+			},
+			status: http.StatusOK,
+			statusRsp: &TransactionStatusResponse{
+				Txid:       txid.Hex(),
+				Status:     daemon.TxnStatusRejected,
+				Reason:     "Transaction violates hard constraint: Insufficient coins",
+				RejectedAt: receivedAt.Unix(),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetTransactionStatus", txid).Return(tc.gatewayStatus, tc.gatewayErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/transaction/status"+tc.query, nil)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			if tc.status != http.StatusOK {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+
+			var statusRsp TransactionStatusResponse
+			err = json.Unmarshal(rsp.Data, &statusRsp)
+			require.NoError(t, err)
+			require.Equal(t, *tc.statusRsp, statusRsp)
+		})
+	}
+}
+
+func TestRebroadcastTransaction(t *testing.T) {
+	txid := testutil.RandSHA256(t)
+
+	cases := []struct {
+		name           string
+		method         string
+		body           string
+		gatewayPeers   int
+		gatewayErr     error
+		status         int
+		err            string
+		rebroadcastRsp *RebroadcastTransactionResponse
+	}{
+		{
+			name:   "405",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - invalid json",
+			method: http.MethodPost,
+			body:   "{",
+			status: http.StatusBadRequest,
+			err:    "unexpected EOF",
+		},
+		{
+			name:   "400 - missing txid",
+			method: http.MethodPost,
+			body:   "{}",
+			status: http.StatusBadRequest,
+			err:    "txid is required",
+		},
+		{
+			name:   "400 - invalid txid",
+			method: http.MethodPost,
+			body:   `{"txid": "abc"}`,
+			status: http.StatusBadRequest,
+			err:    "invalid txid: encoding/hex: odd length hex string",
+		},
+		{
+			name:       "404 - not pending",
+			method:     http.MethodPost,
+			body:       fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayErr: daemon.ErrTxnNotPending,
+			status:     http.StatusNotFound,
+			err:        "transaction is not in the unconfirmed pool",
+		},
+		{
+			name:       "422 - not valid",
+			method:     http.MethodPost,
+			body:       fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayErr: daemon.ErrTxnNotValid,
+			status:     http.StatusUnprocessableEntity,
+			err:        "transaction is not valid against the blockchain",
+		},
+		{
+			name:       "503 - broadcast failure",
+			method:     http.MethodPost,
+			body:       fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayErr: gnet.ErrPoolEmpty,
+			status:     http.StatusServiceUnavailable,
+			err:        gnet.ErrPoolEmpty.Error(),
+		},
+		{
+			name:       "503 - networking disabled",
+			method:     http.MethodPost,
+			body:       fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayErr: daemon.ErrNetworkingDisabled,
+			status:     http.StatusServiceUnavailable,
+			err:        daemon.ErrNetworkingDisabled.Error(),
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodPost,
+			body:       fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "gateway.RebroadcastTransaction failed: failed",
+		},
+		{
+			name:         "200",
+			method:       http.MethodPost,
+			body:         fmt.Sprintf(`{"txid": "%s"}`, txid.Hex()),
+			gatewayPeers: 4,
+			status:       http.StatusOK,
+			rebroadcastRsp: &RebroadcastTransactionResponse{
+				Txid:  txid.Hex(),
+				Peers: 4,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("RebroadcastTransaction", txid).Return(tc.gatewayPeers, tc.gatewayErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/transaction/rebroadcast", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			if tc.status != http.StatusOK {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+
+			var rebroadcastRsp RebroadcastTransactionResponse
+			err = json.Unmarshal(rsp.Data, &rebroadcastRsp)
+			require.NoError(t, err)
+			require.Equal(t, *tc.rebroadcastRsp, rebroadcastRsp)
+		})
+	}
+}
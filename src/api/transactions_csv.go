@@ -0,0 +1,239 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	transactionsFormatJSON = "json"
+	transactionsFormatCSV  = "csv"
+)
+
+// transactionsCSVHeader is the header row of the CSV transaction history.
+// Each row is the change of the balance of an address in a transaction.
+var transactionsCSVHeader = []string{
+	"time",
+	"txid",
+	"status",
+	"block_seq",
+	"address",
+	"sent_coins",
+	"received_coins",
+	"net_coins",
+	"sent_hours",
+	"received_hours",
+	"fee_hours",
+}
+
+// parseTransactionsFormat returns the format of the transaction history, from the format parameter,
+// or the Accept header if it is not set. Defaults to JSON.
+func parseTransactionsFormat(r *http.Request) (string, error) {
+	switch format := r.FormValue("format"); format {
+	case transactionsFormatJSON, transactionsFormatCSV:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("invalid 'format' value: %q", format)
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ContentTypeCSV {
+			return transactionsFormatCSV, nil
+		}
+	}
+
+	return transactionsFormatJSON, nil
+}
+
+// transactionCSVRow is the change of the balance of an address in a transaction
+type transactionCSVRow struct {
+	sentCoins     uint64
+	receivedCoins uint64
+	sentHours     uint64
+	receivedHours uint64
+}
+
+// newTransactionsCSV writes the transaction history as CSV, with a row for each address
+// whose balance is changed by a transaction. If addrs is not empty, only the rows of these addresses are written.
+// The coins are formatted as decimal strings, and the time as RFC3339 in UTC.
+func newTransactionsCSV(txns []visor.Transaction, inputs [][]visor.TransactionInput, addrs []cipher.Address) ([]byte, error) {
+	if len(txns) != len(inputs) {
+		return nil, fmt.Errorf("newTransactionsCSV: len(txns) != len(inputs)")
+	}
+
+	include := make(map[cipher.Address]struct{}, len(addrs))
+	for _, a := range addrs {
+		include[a] = struct{}{}
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+
+	if err := cw.Write(transactionsCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for i, txn := range txns {
+		if len(inputs[i]) != len(txn.Transaction.In) {
+			return nil, fmt.Errorf("newTransactionsCSV: len(inputs[%d]) != len(txns[%d].Transaction.In)", i, i)
+		}
+
+		rows := make(map[cipher.Address]*transactionCSVRow)
+		getRow := func(a cipher.Address) *transactionCSVRow {
+			r, ok := rows[a]
+			if !ok {
+				r = &transactionCSVRow{}
+				rows[a] = r
+			}
+			return r
+		}
+
+		var inputHours, outputHours uint64
+		for _, in := range inputs[i] {
+			r := getRow(in.UxOut.Body.Address)
+			r.sentCoins += in.UxOut.Body.Coins
+			r.sentHours += in.CalculatedHours
+			inputHours += in.CalculatedHours
+		}
+
+		for _, o := range txn.Transaction.Out {
+			r := getRow(o.Address)
+			r.receivedCoins += o.Coins
+			r.receivedHours += o.Hours
+			outputHours += o.Hours
+		}
+
+		var fee uint64
+		if inputHours > outputHours {
+			fee = inputHours - outputHours
+		}
+
+		status := "pending"
+		var blockSeq string
+		if txn.Status.Confirmed {
+			status = "confirmed"
+			blockSeq = strconv.FormatUint(txn.Status.BlockSeq, 10)
+		}
+
+		// Writes the rows in address order so that the output is deterministic
+		rowAddrs := make([]cipher.Address, 0, len(rows))
+		for a := range rows {
+			if _, ok := include[a]; len(include) > 0 && !ok {
+				continue
+			}
+			rowAddrs = append(rowAddrs, a)
+		}
+		sort.Slice(rowAddrs, func(i, j int) bool {
+			return rowAddrs[i].String() < rowAddrs[j].String()
+		})
+
+		for _, a := range rowAddrs {
+			r := rows[a]
+
+			sentCoins, err := droplet.ToString(r.sentCoins)
+			if err != nil {
+				return nil, err
+			}
+			receivedCoins, err := droplet.ToString(r.receivedCoins)
+			if err != nil {
+				return nil, err
+			}
+
+			var netCoins string
+			if r.receivedCoins >= r.sentCoins {
+				netCoins, err = droplet.ToString(r.receivedCoins - r.sentCoins)
+			} else {
+				netCoins, err = droplet.ToString(r.sentCoins - r.receivedCoins)
+				netCoins = "-" + netCoins
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if err := cw.Write([]string{
+				time.Unix(int64(txn.Time), 0).UTC().Format(time.RFC3339),
+				txn.Transaction.Hash().Hex(),
+				status,
+				blockSeq,
+				a.String(),
+				sentCoins,
+				receivedCoins,
+				netCoins,
+				strconv.FormatUint(r.sentHours, 10),
+				strconv.FormatUint(r.receivedHours, 10),
+				strconv.FormatUint(fee, 10),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sortTransactionsByTime sorts the transactions and their inputs by time, then by hash
+func sortTransactionsByTime(txns []visor.Transaction, inputs [][]visor.TransactionInput) {
+	sort.Sort(transactionsByTime{
+		txns:   txns,
+		inputs: inputs,
+	})
+}
+
+type transactionsByTime struct {
+	txns   []visor.Transaction
+	inputs [][]visor.TransactionInput
+}
+
+func (t transactionsByTime) Len() int {
+	return len(t.txns)
+}
+
+func (t transactionsByTime) Less(i, j int) bool {
+	a := t.txns[i]
+	b := t.txns[j]
+
+	if a.Time == b.Time {
+		return strings.Compare(a.Transaction.Hash().Hex(), b.Transaction.Hash().Hex()) < 0
+	}
+
+	return a.Time < b.Time
+}
+
+func (t transactionsByTime) Swap(i, j int) {
+	t.txns[i], t.txns[j] = t.txns[j], t.txns[i]
+	t.inputs[i], t.inputs[j] = t.inputs[j], t.inputs[i]
+}
+
+// sendTransactionsCSV writes the transaction history as CSV, as an attachment named filename
+func sendTransactionsCSV(w http.ResponseWriter, filename string, txns []visor.Transaction, inputs [][]visor.TransactionInput, addrs []cipher.Address) {
+	b, err := newTransactionsCSV(txns, inputs, addrs)
+	if err != nil {
+		wh.Error500(w, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeCSV+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(b); err != nil {
+		logger.WithError(err).Error("http Write failed")
+	}
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// makeCSVTransaction makes a transaction from addr to a destination address and back to addr as change,
+// with the input held by addr
+func makeCSVTransaction(t *testing.T, addr, dst cipher.Address, coins, change uint64) (coin.Transaction, []visor.TransactionInput) {
+	txn := coin.Transaction{
+		In: []cipher.SHA256{testutil.RandSHA256(t)},
+		Out: []coin.TransactionOutput{
+			{
+				Address: dst,
+				Coins:   coins,
+				Hours:   5,
+			},
+			{
+				Address: addr,
+				Coins:   change,
+				Hours:   3,
+			},
+		},
+	}
+
+	inputs := []visor.TransactionInput{
+		{
+			UxOut: coin.UxOut{
+				Body: coin.UxBody{
+					Address: addr,
+					Coins:   coins + change,
+					Hours:   10,
+				},
+			},
+			CalculatedHours: 20,
+		},
+	}
+
+	return txn, inputs
+}
+
+func TestNewTransactionsCSV(t *testing.T) {
+	addrA := cipher.MustDecodeBase58Address("2RxP5N26GhDqHrP6SK45ZzEMSmSpeUeWxsS")
+	addrB := cipher.MustDecodeBase58Address("222uMeCeL1PbkJGZJDgAz5sib2uisv9hYUm")
+
+	txn1, inputs1 := makeCSVTransaction(t, addrA, addrB, 1.5e6, 8.5e6)
+	txn2, inputs2 := makeCSVTransaction(t, addrB, addrA, 1e6, 0.5e6)
+
+	txns := []visor.Transaction{
+		{
+			Transaction: txn1,
+			Status: visor.TransactionStatus{
+				Confirmed: true,
+				BlockSeq:  10,
+			},
+			Time: 1494275231,
+		},
+		{
+			Transaction: txn2,
+			Time:        1494275300,
+		},
+	}
+	inputs := [][]visor.TransactionInput{inputs1, inputs2}
+
+	header := "time,txid,status,block_seq,address,sent_coins,received_coins,net_coins,sent_hours,received_hours,fee_hours\n"
+
+	b, err := newTransactionsCSV(txns, inputs, nil)
+	require.NoError(t, err)
+	require.Equal(t, header+
+		fmt.Sprintf("2017-05-08T20:27:11Z,%s,confirmed,10,%s,0.000000,1.500000,1.500000,0,5,12\n", txn1.Hash().Hex(), addrB)+
+		fmt.Sprintf("2017-05-08T20:27:11Z,%s,confirmed,10,%s,10.000000,8.500000,-1.500000,20,3,12\n", txn1.Hash().Hex(), addrA)+
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,1.500000,0.500000,-1.000000,20,3,12\n", txn2.Hash().Hex(), addrB)+
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,0.000000,1.000000,1.000000,0,5,12\n", txn2.Hash().Hex(), addrA),
+		string(b))
+
+	// Only the rows of the filtered addresses are written
+	b, err = newTransactionsCSV(txns, inputs, []cipher.Address{addrA})
+	require.NoError(t, err)
+	require.Equal(t, header+
+		fmt.Sprintf("2017-05-08T20:27:11Z,%s,confirmed,10,%s,10.000000,8.500000,-1.500000,20,3,12\n", txn1.Hash().Hex(), addrA)+
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,0.000000,1.000000,1.000000,0,5,12\n", txn2.Hash().Hex(), addrA),
+		string(b))
+
+	_, err = newTransactionsCSV(txns, inputs[:1], nil)
+	require.Error(t, err)
+}
+
+func TestTransactionsCSVHandlers(t *testing.T) {
+	addrA := cipher.MustDecodeBase58Address("2RxP5N26GhDqHrP6SK45ZzEMSmSpeUeWxsS")
+	addrB := cipher.MustDecodeBase58Address("222uMeCeL1PbkJGZJDgAz5sib2uisv9hYUm")
+
+	txn1, inputs1 := makeCSVTransaction(t, addrA, addrB, 1.5e6, 8.5e6)
+	txn2, inputs2 := makeCSVTransaction(t, addrB, addrA, 1e6, 0.5e6)
+
+	// The transactions are sorted by time
+	txns := []visor.Transaction{
+		{
+			Transaction: txn2,
+			Time:        1494275300,
+		},
+		{
+			Transaction: txn1,
+			Status: visor.TransactionStatus{
+				Confirmed: true,
+				BlockSeq:  10,
+			},
+			Time: 1494275231,
+		},
+	}
+	inputs := [][]visor.TransactionInput{inputs2, inputs1}
+
+	unconfirmed := []visor.UnconfirmedTransaction{
+		{
+			Transaction: txn2,
+			Received:    time.Unix(1494275300, 0).UnixNano(),
+		},
+	}
+
+	header := "time,txid,status,block_seq,address,sent_coins,received_coins,net_coins,sent_hours,received_hours,fee_hours\n"
+	transactionsCSV := header +
+		fmt.Sprintf("2017-05-08T20:27:11Z,%s,confirmed,10,%s,10.000000,8.500000,-1.500000,20,3,12\n", txn1.Hash().Hex(), addrA) +
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,0.000000,1.000000,1.000000,0,5,12\n", txn2.Hash().Hex(), addrA)
+	pendingCSV := header +
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,1.500000,0.500000,-1.000000,20,3,12\n", txn2.Hash().Hex(), addrB) +
+		fmt.Sprintf("2017-05-08T20:28:20Z,%s,pending,,%s,0.000000,1.000000,1.000000,0,5,12\n", txn2.Hash().Hex(), addrA)
+
+	cases := []struct {
+		name   string
+		url    string
+		accept string
+		status int
+		body   string
+	}{
+		{
+			name:   "transactions format=csv",
+			url:    "/api/v1/transactions?addrs=" + addrA.String() + "&format=csv",
+			status: http.StatusOK,
+			body:   transactionsCSV,
+		},
+		{
+			name:   "transactions accept text/csv",
+			url:    "/api/v1/transactions?addrs=" + addrA.String(),
+			accept: "text/csv; charset=utf-8, application/json;q=0.5",
+			status: http.StatusOK,
+			body:   transactionsCSV,
+		},
+		{
+			name:   "transactions invalid format",
+			url:    "/api/v1/transactions?addrs=" + addrA.String() + "&format=xml",
+			status: http.StatusBadRequest,
+			body:   "400 Bad Request - invalid 'format' value: \"xml\"\n",
+		},
+		{
+			name:   "pending format=csv",
+			url:    "/api/v1/pendingTxs?format=csv",
+			status: http.StatusOK,
+			body:   pendingCSV,
+		},
+		{
+			name:   "pending accept text/csv",
+			url:    "/api/v1/pendingTxs",
+			accept: "text/csv",
+			status: http.StatusOK,
+			body:   pendingCSV,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetTransactionsWithInputs", mock.Anything, mock.Anything, visor.AscOrder, (*visor.PageIndex)(nil)).Return(func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) []visor.Transaction {
+				// The handler sorts the transactions in place
+				return append([]visor.Transaction{}, txns...)
+			}, func(context.Context, []visor.TxFilter, visor.SortOrder, *visor.PageIndex) [][]visor.TransactionInput {
+				return append([][]visor.TransactionInput{}, inputs...)
+			}, visor.TxnPage{}, nil)
+			gateway.On("GetAllUnconfirmedTransactionsVerbose").Return(unconfirmed, [][]visor.TransactionInput{inputs2}, nil)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+			require.Equal(t, tc.body, rr.Body.String())
+
+			if tc.status == http.StatusOK {
+				require.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
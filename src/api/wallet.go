@@ -9,7 +9,9 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"sync"
 
+	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/readable"
@@ -51,6 +53,9 @@ func NewWalletResponse(w wallet.Wallet) (*WalletResponse, error) {
 	wr.Meta.CryptoType = w.CryptoType()
 	wr.Meta.Encrypted = w.IsEncrypted()
 	wr.Meta.Timestamp = w.Timestamp()
+	if t := w.UnlockedUntil(); !t.IsZero() {
+		wr.Meta.UnlockedUntil = t.Unix()
+	}
 
 	var options []wallet.Option
 	switch w.Type() {
@@ -63,8 +68,13 @@ func NewWalletResponse(w wallet.Wallet) (*WalletResponse, error) {
 
 		// get entries on both external and change chains
 		options = append(options, wallet.OptionExternal(), wallet.OptionChange())
-	case wallet.WalletTypeXPub:
+	case wallet.WalletTypeXPub, wallet.WalletTypeHardware:
 		wr.Meta.XPub = w.XPub()
+		wr.Meta.XPubAccount = w.IsXPubAccount()
+		if w.Type() == wallet.WalletTypeHardware {
+			wr.Meta.Device = w.Device()
+			wr.Meta.DerivationPath = w.DerivationPath()
+		}
 	}
 
 	entries, err := w.GetEntries(options...)
@@ -76,7 +86,14 @@ func NewWalletResponse(w wallet.Wallet) (*WalletResponse, error) {
 	for i, e := range entries {
 		wr.Entries[i] = readable.WalletEntry{
 			Address: e.Address.String(),
-			Public:  e.Public.Hex(),
+			Label:   e.Label,
+			Note:    e.Note,
+			Tags:    e.Tags,
+		}
+
+		// Entries of watch-only wallets may have no public key
+		if !e.Public.Null() {
+			wr.Entries[i].Public = e.Public.Hex()
 		}
 
 		switch w.Type() {
@@ -89,9 +106,13 @@ func NewWalletResponse(w wallet.Wallet) (*WalletResponse, error) {
 			wr.Entries[i].ChildNumber = &childNumber
 			change := e.Change
 			wr.Entries[i].Change = &change
-		case wallet.WalletTypeXPub:
+		case wallet.WalletTypeXPub, wallet.WalletTypeHardware:
 			childNumber := e.ChildNumber
 			wr.Entries[i].ChildNumber = &childNumber
+			if w.IsXPubAccount() {
+				change := e.Change
+				wr.Entries[i].Change = &change
+			}
 		}
 	}
 
@@ -116,8 +137,12 @@ func walletBalanceHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		walletBalance, addressBalances, err := gateway.GetWalletBalance(wltID)
+		walletBalance, addressBalances, err := gateway.GetWalletBalance(r.Context(), wltID)
 		if err != nil {
+			if writeContextError(w, apiVersion1, err) {
+				return
+			}
+
 			logger.Errorf("Get wallet balance failed: %v", err)
 			switch err {
 			case wallet.ErrWalletNotExist:
@@ -169,35 +194,181 @@ func balanceHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		// create map of address to balance
-		addressBalances := make(readable.AddressBalances, len(addrs))
-		for idx, addr := range addrs {
-			addressBalances[addr.String()] = readable.NewBalancePair(bals[idx])
+		resp, err := newBalanceResponse(addrs, bals)
+		if err != nil {
+			wh.Error500(w, err.Error())
+			return
 		}
 
-		var balance wallet.BalancePair
-		for _, bal := range bals {
-			var err error
-			balance.Confirmed, err = balance.Confirmed.Add(bal.Confirmed)
+		wh.SendJSONOr500(logger, w, resp)
+	}
+}
+
+// newBalanceResponse creates a BalanceResponse from the balances of the addresses
+func newBalanceResponse(addrs []cipher.Address, bals []wallet.BalancePair) (*BalanceResponse, error) {
+	// create map of address to balance
+	addressBalances := make(readable.AddressBalances, len(addrs))
+	for idx, addr := range addrs {
+		addressBalances[addr.String()] = readable.NewBalancePair(bals[idx])
+	}
+
+	var balance wallet.BalancePair
+	for _, bal := range bals {
+		var err error
+		balance.Confirmed, err = balance.Confirmed.Add(bal.Confirmed)
+		if err != nil {
+			return nil, err
+		}
+
+		balance.Predicted, err = balance.Predicted.Add(bal.Predicted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &BalanceResponse{
+		BalancePair: readable.NewBalancePair(balance),
+		Addresses:   addressBalances,
+	}, nil
+}
+
+const (
+	// MaxBalanceAddresses is the maximum number of addresses of a POST /api/v2/balance request
+	MaxBalanceAddresses = 10000
+	// balanceChunkSize is the number of addresses looked up together by POST /api/v2/balance
+	balanceChunkSize = 500
+	// balanceWorkers is the number of chunks looked up in parallel by POST /api/v2/balance
+	balanceWorkers = 4
+)
+
+// BalanceRequest is the request data for POST /api/v2/balance
+type BalanceRequest struct {
+	Addrs []string `json:"addrs"`
+}
+
+// Returns the balance of many addresses, both confirmed and predicted.
+// The addresses are looked up in parallel chunks, so that large requests do not
+// hold a single database transaction for long.
+// Since each chunk is read separately, a block executed during the request
+// may be counted in the balances of some chunks only.
+// URI: /api/v2/balance
+// Method: POST
+// Content-Type: application/json
+// Body: {"addrs": ["<address>", ...]}
+func balanceHandlerV2(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError405Response(w)
+			return
+		}
+
+		var req BalanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError400Response(w, err.Error())
+			return
+		}
+
+		if len(req.Addrs) == 0 {
+			writeError400Response(w, "addrs is required")
+			return
+		}
+
+		if len(req.Addrs) > MaxBalanceAddresses {
+			writeError400Response(w, fmt.Sprintf("too many addrs, the maximum is %d", MaxBalanceAddresses))
+			return
+		}
+
+		addrs := make([]cipher.Address, 0, len(req.Addrs))
+		seen := make(map[cipher.Address]struct{}, len(req.Addrs))
+		for _, s := range req.Addrs {
+			a, err := cipher.DecodeBase58Address(s)
 			if err != nil {
-				wh.Error500(w, err.Error())
+				writeError400Response(w, fmt.Sprintf("address %q is invalid: %v", s, err))
 				return
 			}
 
-			balance.Predicted, err = balance.Predicted.Add(bal.Predicted)
-			if err != nil {
-				wh.Error500(w, err.Error())
-				return
+			if _, ok := seen[a]; ok {
+				continue
 			}
+			seen[a] = struct{}{}
+			addrs = append(addrs, a)
 		}
 
-		wh.SendJSONOr500(logger, w, BalanceResponse{
-			BalancePair: readable.NewBalancePair(balance),
-			Addresses:   addressBalances,
+		bals, err := getBalanceOfAddressesParallel(gateway, addrs)
+		if err != nil {
+			err = fmt.Errorf("gateway.GetBalanceOfAddresses failed: %v", err)
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		balances, err := newBalanceResponse(addrs, bals)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: balances,
 		})
 	}
 }
 
+// getBalanceOfAddressesParallel returns the balances of the addresses, in the same order,
+// looking up chunks of balanceChunkSize addresses with balanceWorkers goroutines
+func getBalanceOfAddressesParallel(gateway Gatewayer, addrs []cipher.Address) ([]wallet.BalancePair, error) {
+	bals := make([]wallet.BalancePair, len(addrs))
+
+	starts := make(chan int, (len(addrs)+balanceChunkSize-1)/balanceChunkSize)
+	for i := 0; i < len(addrs); i += balanceChunkSize {
+		starts <- i
+	}
+	close(starts)
+
+	workers := balanceWorkers
+	if workers > len(starts) {
+		workers = len(starts)
+	}
+
+	errC := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + balanceChunkSize
+				if end > len(addrs) {
+					end = len(addrs)
+				}
+
+				chunk, err := gateway.GetBalanceOfAddresses(addrs[start:end])
+				if err != nil {
+					errC <- err
+					return
+				}
+
+				if len(chunk) != end-start {
+					errC <- fmt.Errorf("got %d balances for %d addresses", len(chunk), end-start)
+					return
+				}
+
+				copy(bals[start:end], chunk)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errC)
+
+	if err := <-errC; err != nil {
+		return nil, err
+	}
+
+	return bals, nil
+}
+
 // Loads wallet from seed, will scan ahead N address and
 // load addresses till the last one that have coins.
 // URI: /api/v1/wallet/create
@@ -205,9 +376,14 @@ func balanceHandler(gateway Gatewayer) http.HandlerFunc {
 // Args:
 //     seed: wallet seed [required]
 //     seed-passphrase: wallet seed passphrase [optional, bip44 type wallet only]
-//     type: wallet type [required, one of "deterministic", "bip44" or "xpub"]
+//     seed-language: bip39 wordlist language of the seed, e.g. japanese [optional, detected if not set, bip44 type wallet only]
+//     seed-entropy: entropy derivation description returned by /api/v1/wallet/newSeed with user-entropy [optional, bip44 and deterministic type wallets only]
+//     try-seed-passphrase: bool value, whether to create the wallet without the seed passphrase if only those addresses have transactions [optional, bip44 type wallet only]
+//     type: wallet type [required, one of "deterministic", "bip44", "xpub" or "collection-watch"]
 //     bip44-coin: BIP44 coin type [optional, defaults to 8000 (skycoin's coin type), only valid if type is "bip44"]
 //     xpub: xpub key [required for xpub wallets]
+//     xpub-account: bool value, whether the xpub is a bip44 account key, whose external and change chains are scanned [optional, xpub type wallet only]
+//     addresses: comma separated addresses to watch [required for collection-watch wallets]
 //     label: wallet label [required]
 //     scan: the number of addresses to scan ahead for balances [optional, must be > 0]
 //     encrypt: bool value, whether encrypt the wallet [optional]
@@ -234,6 +410,18 @@ func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 			}
 		}
 
+		var addresses []string
+		if addrsStr := r.FormValue("addresses"); addrsStr != "" {
+			if walletType != wallet.WalletTypeCollectionWatch {
+				wh.Error400(w, "addresses is only valid for collection-watch type wallets")
+				return
+			}
+			addresses = splitCommaString(addrsStr)
+		} else if walletType == wallet.WalletTypeCollectionWatch {
+			wh.Error400(w, "missing addresses")
+			return
+		}
+
 		label := r.FormValue("label")
 		if label == "" {
 			wh.Error400(w, "missing label")
@@ -300,17 +488,62 @@ func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 			bip44Coin = &c
 		}
 
+		var seedLanguage bip39.Language
+		if v := r.FormValue("seed-language"); v != "" {
+			if walletType != wallet.WalletTypeBip44 {
+				wh.Error400(w, "seed-language is only valid for bip44 type wallets")
+				return
+			}
+
+			var err error
+			seedLanguage, err = bip39.ParseLanguage(v)
+			if err != nil {
+				wh.Error400(w, "invalid seed-language value")
+				return
+			}
+		}
+
+		var trySeedPassphrase bool
+		if v := r.FormValue("try-seed-passphrase"); v != "" {
+			var err error
+			trySeedPassphrase, err = strconv.ParseBool(v)
+			if err != nil {
+				wh.Error400(w, fmt.Sprintf("invalid try-seed-passphrase value: %v", err))
+				return
+			}
+		}
+
+		var xpubAccount bool
+		if v := r.FormValue("xpub-account"); v != "" {
+			if walletType != wallet.WalletTypeXPub {
+				wh.Error400(w, "xpub-account is only valid for xpub type wallets")
+				return
+			}
+
+			var err error
+			xpubAccount, err = strconv.ParseBool(v)
+			if err != nil {
+				wh.Error400(w, fmt.Sprintf("invalid xpub-account value: %v", err))
+				return
+			}
+		}
+
 		wlt, err := gateway.CreateWallet("", wallet.Options{
-			Seed:           seed,
-			Label:          label,
-			Encrypt:        encrypt,
-			Password:       []byte(password),
-			ScanN:          scanN,
-			Type:           walletType,
-			SeedPassphrase: r.FormValue("seed-passphrase"),
-			Bip44Coin:      bip44Coin,
-			XPub:           r.FormValue("xpub"),
-			TF:             gateway.TransactionsFinder(),
+			Seed:              seed,
+			Label:             label,
+			Encrypt:           encrypt,
+			Password:          []byte(password),
+			ScanN:             scanN,
+			Type:              walletType,
+			SeedPassphrase:    r.FormValue("seed-passphrase"),
+			SeedLanguage:      seedLanguage,
+			SeedEntropy:       r.FormValue("seed-entropy"),
+			TrySeedPassphrase: trySeedPassphrase,
+			Bip44Coin:         bip44Coin,
+			XPub:              r.FormValue("xpub"),
+			XPubAccount:       xpubAccount,
+			Addresses:         addresses,
+			TF:                gateway.TransactionsFinder(),
 		})
 		if err != nil {
 			switch err.(type) {
@@ -699,6 +932,11 @@ func walletFolderHandler(s Walleter) http.HandlerFunc {
 // Method: GET
 // Args:
 //     entropy: entropy bitsize [optional, default value of 128 will be used if not set]
+//     language: bip39 wordlist language, e.g. japanese [optional, default value of english will be used if not set]
+//     user-entropy: user entropy mixed with the random entropy, e.g. dice rolls [optional]
+//     user-entropy-type: format of user-entropy, "dice" or "hex" [optional, default value of dice will be used if not set]
+// The response has the entropy derivation description if user-entropy is set,
+// which can be passed as seed-entropy to /api/v1/wallet/create to record it in the wallet.
 func newSeedHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -723,24 +961,52 @@ func newSeedHandler() http.HandlerFunc {
 			return
 		}
 
-		entropy, err := bip39.NewEntropy(entropyBits)
-		if err != nil {
-			err = fmt.Errorf("bip39.NewEntropy failed: %v", err)
-			wh.Error500(w, err.Error())
-			return
+		var entropy []byte
+		var entropyDescription string
+		if userEntropy := r.FormValue("user-entropy"); userEntropy != "" {
+			userEntropyType := bip39.UserEntropyDice
+			if v := r.FormValue("user-entropy-type"); v != "" {
+				userEntropyType = bip39.UserEntropyFormat(v)
+			}
+
+			var desc bip39.EntropyDescription
+			entropy, desc, err = bip39.NewEntropyWithUserEntropy(entropyBits, userEntropyType, userEntropy)
+			if err != nil {
+				wh.Error400(w, fmt.Sprintf("invalid user-entropy: %v", err))
+				return
+			}
+			entropyDescription = desc.String()
+		} else {
+			entropy, err = bip39.NewEntropy(entropyBits)
+			if err != nil {
+				err = fmt.Errorf("bip39.NewEntropy failed: %v", err)
+				wh.Error500(w, err.Error())
+				return
+			}
+		}
+
+		language := bip39.English
+		if v := r.FormValue("language"); v != "" {
+			language, err = bip39.ParseLanguage(v)
+			if err != nil {
+				wh.Error400(w, "invalid language")
+				return
+			}
 		}
 
-		mnemonic, err := bip39.NewMnemonic(entropy)
+		mnemonic, err := bip39.NewMnemonicInLanguage(entropy, language)
 		if err != nil {
-			err = fmt.Errorf("bip39.NewDefaultMnemonic failed: %v", err)
+			err = fmt.Errorf("bip39.NewMnemonicInLanguage failed: %v", err)
 			wh.Error500(w, err.Error())
 			return
 		}
 
 		var rlt = struct {
-			Seed string `json:"seed"`
+			Seed               string `json:"seed"`
+			EntropyDescription string `json:"entropy_description,omitempty"`
 		}{
 			mnemonic,
+			entropyDescription,
 		}
 		wh.SendJSONOr500(logger, w, rlt)
 	}
@@ -839,115 +1105,362 @@ func walletVerifySeedHandler(w http.ResponseWriter, r *http.Request) {
 	writeHTTPResponse(w, HTTPResponse{Data: struct{}{}})
 }
 
-// Unloads wallet from the wallet service
-// URI: /api/v1/wallet/unload
+// VerifySeedPassphraseRequest is the request data for POST /api/v2/wallet/seed-passphrase/verify
+type VerifySeedPassphraseRequest struct {
+	ID             string `json:"id"`
+	SeedPassphrase string `json:"seed_passphrase"`
+	Password       string `json:"password"`
+}
+
+// walletVerifySeedPassphraseHandler verifies the seed passphrase of a bip44 wallet
+// against the accounts of the wallet, e.g. before signing with the wallet
 // Method: POST
+// URI: /api/v2/wallet/seed-passphrase/verify
 // Args:
-//     id: wallet id
-func walletUnloadHandler(gateway Gatewayer) http.HandlerFunc {
+//  id: wallet id
+//  seed_passphrase: seed passphrase to verify
+//  password: [optional] wallet password, required if the wallet is encrypted
+func walletVerifySeedPassphraseHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			wh.Error405(w)
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		id := r.FormValue("id")
-		if id == "" {
-			wh.Error400(w, "missing wallet id")
+		var req VerifySeedPassphraseRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		if err := gateway.UnloadWallet(id); err != nil {
-			switch err {
-			case wallet.ErrWalletAPIDisabled:
-				wh.Error403(w, "")
-			default:
-				wh.Error500(w, err.Error())
-			}
-		}
-	}
-}
-
-// Encrypts wallet
-// URI: /api/v1/wallet/encrypt
-// Method: POST
-// Args:
-//     id: wallet id
-//     password: wallet password
-func walletEncryptHandler(gateway Gatewayer) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			wh.Error405(w)
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		id := r.FormValue("id")
-		if id == "" {
-			wh.Error400(w, "missing wallet id")
-			return
+		var password []byte
+		if req.Password != "" {
+			password = []byte(req.Password)
 		}
 
-		password := r.FormValue("password")
 		defer func() {
-			password = ""
+			req.SeedPassphrase = ""
+			req.Password = ""
+			password = nil
 		}()
 
-		wlt, err := gateway.EncryptWallet(id, []byte(password))
-		if err != nil {
-			switch err {
-			case wallet.ErrWalletEncrypted,
-				wallet.ErrMissingPassword,
-				wallet.ErrInvalidPassword:
-				wh.Error400(w, err.Error())
-			case wallet.ErrWalletAPIDisabled:
-				wh.Error403(w, "")
-			case wallet.ErrWalletNotExist:
-				wh.Error404(w, "")
+		if err := gateway.VerifySeedPassphrase(req.ID, password, req.SeedPassphrase); err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				case wallet.ErrSeedPassphraseWrong:
+					resp = NewHTTPErrorResponse(http.StatusUnprocessableEntity, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
 			default:
-				wh.Error500(w, err.Error())
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
 			}
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		// Make sure the sensitive data are wiped
-		rlt, err := NewWalletResponse(wlt)
-		if err != nil {
-			wh.Error500(w, err.Error())
-			return
-		}
-		wh.SendJSONOr500(logger, w, rlt)
+		writeHTTPResponse(w, HTTPResponse{Data: struct{}{}})
 	}
 }
 
-// Decrypts wallet
-// URI: /api/v1/wallet/decrypt
+// WalletSeedMatchRequest is the request data for POST /api/v2/wallet/seed/match
+type WalletSeedMatchRequest struct {
+	ID             string `json:"id"`
+	Seed           string `json:"seed"`
+	SeedPassphrase string `json:"seed_passphrase,omitempty"`
+}
+
+// WalletSeedMatchResponse is returned by POST /api/v2/wallet/seed/match
+type WalletSeedMatchResponse struct {
+	Match bool `json:"match"`
+}
+
+// walletSeedMatchHandler checks whether a seed and seed passphrase are the ones of a wallet,
+// by comparing the addresses they derive with the wallet's, to validate a backup of the seed.
+// The wallet does not need to be decrypted and the seed of the wallet is never returned.
 // Method: POST
+// URI: /api/v2/wallet/seed/match
 // Args:
-//     id: wallet id
-//     password: wallet password
-func walletDecryptHandler(gateway Gatewayer) http.HandlerFunc {
+//  id: wallet id
+//  seed: seed to verify
+//  seed_passphrase: [optional] seed passphrase to verify, bip44 wallets only
+func walletSeedMatchHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			wh.Error405(w)
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		id := r.FormValue("id")
-		if id == "" {
-			wh.Error400(w, "missing wallet id")
+		var req WalletSeedMatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
 			return
 		}
 
-		password := r.FormValue("password")
 		defer func() {
-			password = ""
+			req.Seed = ""
+			req.SeedPassphrase = ""
 		}()
 
-		wlt, err := gateway.DecryptWallet(id, []byte(password))
-		if err != nil {
-			switch err {
-			case wallet.ErrMissingPassword,
-				wallet.ErrWalletNotEncrypted,
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Seed == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "seed is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		match, err := gateway.VerifyWalletSeed(req.ID, req.Seed, req.SeedPassphrase)
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: WalletSeedMatchResponse{
+				Match: match,
+			},
+		})
+	}
+}
+
+// WalletSignMessageRequest is the request data for POST /api/v2/wallet/message/sign
+type WalletSignMessageRequest struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Message  string `json:"message"`
+	Password string `json:"password"`
+}
+
+// WalletSignMessageResponse is returned by POST /api/v2/wallet/message/sign
+type WalletSignMessageResponse struct {
+	Signature string `json:"signature"`
+}
+
+// walletSignMessageHandler signs a message with the secret key of a wallet address,
+// proving the ownership of the address. The signature is verified with POST /api/v2/address/message/verify
+// Method: POST
+// URI: /api/v2/wallet/message/sign
+// Args:
+//  id: wallet id
+//  address: address of the wallet that signs the message
+//  message: message to sign
+//  password: [optional] wallet password, required if the wallet is encrypted and not unlocked
+func walletSignMessageHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletSignMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Address == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "address is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		addr, err := cipher.DecodeBase58Address(req.Address)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid address: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var password []byte
+		if req.Password != "" {
+			password = []byte(req.Password)
+		}
+
+		defer func() {
+			req.Password = ""
+			password = nil
+		}()
+
+		sig, err := gateway.SignMessage(req.ID, password, addr, []byte(req.Message))
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: WalletSignMessageResponse{
+				Signature: sig.Hex(),
+			},
+		})
+	}
+}
+
+// Unloads wallet from the wallet service
+// URI: /api/v1/wallet/unload
+// Method: POST
+// Args:
+//     id: wallet id
+func walletUnloadHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			wh.Error405(w)
+			return
+		}
+
+		id := r.FormValue("id")
+		if id == "" {
+			wh.Error400(w, "missing wallet id")
+			return
+		}
+
+		if err := gateway.UnloadWallet(id); err != nil {
+			switch err {
+			case wallet.ErrWalletAPIDisabled:
+				wh.Error403(w, "")
+			default:
+				wh.Error500(w, err.Error())
+			}
+		}
+	}
+}
+
+// Encrypts wallet
+// URI: /api/v1/wallet/encrypt
+// Method: POST
+// Args:
+//     id: wallet id
+//     password: wallet password
+func walletEncryptHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			wh.Error405(w)
+			return
+		}
+
+		id := r.FormValue("id")
+		if id == "" {
+			wh.Error400(w, "missing wallet id")
+			return
+		}
+
+		password := r.FormValue("password")
+		defer func() {
+			password = ""
+		}()
+
+		wlt, err := gateway.EncryptWallet(id, []byte(password))
+		if err != nil {
+			switch err {
+			case wallet.ErrWalletEncrypted,
+				wallet.ErrMissingPassword,
+				wallet.ErrInvalidPassword:
+				wh.Error400(w, err.Error())
+			case wallet.ErrWalletAPIDisabled:
+				wh.Error403(w, "")
+			case wallet.ErrWalletNotExist:
+				wh.Error404(w, "")
+			default:
+				wh.Error500(w, err.Error())
+			}
+			return
+		}
+
+		// Make sure the sensitive data are wiped
+		rlt, err := NewWalletResponse(wlt)
+		if err != nil {
+			wh.Error500(w, err.Error())
+			return
+		}
+		wh.SendJSONOr500(logger, w, rlt)
+	}
+}
+
+// Decrypts wallet
+// URI: /api/v1/wallet/decrypt
+// Method: POST
+// Args:
+//     id: wallet id
+//     password: wallet password
+func walletDecryptHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			wh.Error405(w)
+			return
+		}
+
+		id := r.FormValue("id")
+		if id == "" {
+			wh.Error400(w, "missing wallet id")
+			return
+		}
+
+		password := r.FormValue("password")
+		defer func() {
+			password = ""
+		}()
+
+		wlt, err := gateway.DecryptWallet(id, []byte(password))
+		if err != nil {
+			switch err {
+			case wallet.ErrMissingPassword,
+				wallet.ErrWalletNotEncrypted,
 				wallet.ErrInvalidPassword:
 				wh.Error400(w, err.Error())
 			case wallet.ErrWalletAPIDisabled:
@@ -1059,3 +1572,299 @@ func walletRecoverHandler(gateway Gatewayer) http.HandlerFunc {
 		})
 	}
 }
+
+// WalletUnlockRequest is the request data for POST /api/v2/wallet/unlock
+type WalletUnlockRequest struct {
+	ID       string      `json:"id"`
+	Password string      `json:"password"`
+	TTL      wh.Duration `json:"ttl"`
+}
+
+// WalletUnlockResponse is the response data for POST /api/v2/wallet/unlock
+type WalletUnlockResponse struct {
+	// UnlockedUntil is the expiry of the unlock session, in unix seconds
+	UnlockedUntil int64 `json:"unlocked_until"`
+}
+
+// walletUnlockHandler starts an unlock session of an encrypted wallet, within the session
+// the wallet signs transactions without the password until the ttl elapses.
+// Unlocking a wallet in session renews the session.
+// Method: POST
+// URI: /api/v2/wallet/unlock
+// Args:
+//  id: wallet id
+//  password: wallet password
+//  ttl: duration of the session, e.g. "1h30m"
+func walletUnlockHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletUnlockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		password := []byte(req.Password)
+		defer func() {
+			req.Password = ""
+			password = nil
+		}()
+
+		expires, err := gateway.UnlockWallet(req.ID, password, req.TTL.Duration)
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: WalletUnlockResponse{
+				UnlockedUntil: expires.Unix(),
+			},
+		})
+	}
+}
+
+// WalletLockRequest is the request data for POST /api/v2/wallet/lock
+type WalletLockRequest struct {
+	ID string `json:"id"`
+}
+
+// walletLockHandler ends the unlock session of a wallet, it's a no-op if the wallet is not in an unlock session
+// Method: POST
+// URI: /api/v2/wallet/lock
+// Args:
+//  id: wallet id
+func walletLockHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletLockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if err := gateway.LockWallet(req.ID); err != nil {
+			var resp HTTPResponse
+			switch err {
+			case wallet.ErrWalletNotExist:
+				resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+			case wallet.ErrWalletAPIDisabled:
+				resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{Data: struct{}{}})
+	}
+}
+
+// WalletAddressLabel is the label, note and tags of an address of a wallet
+type WalletAddressLabel struct {
+	Address string            `json:"address"`
+	Label   string            `json:"label,omitempty"`
+	Note    string            `json:"note,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// WalletLabelsResponse is the response data for GET /api/v2/wallet/labels
+type WalletLabelsResponse struct {
+	Labels []WalletAddressLabel `json:"labels"`
+}
+
+// walletLabelsHandler returns the label, note and tags of the addresses of a wallet that have any,
+// on all accounts of bip44 wallets
+// Method: GET
+// URI: /api/v2/wallet/labels
+// Args:
+//  id: wallet id [required]
+func walletLabelsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wltID := r.FormValue("id")
+		if wltID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		labels, err := gateway.GetAddressLabels(wltID)
+		if err != nil {
+			writeHTTPResponse(w, walletLabelsErrorResponse(err))
+			return
+		}
+
+		rlt := WalletLabelsResponse{
+			Labels: make([]WalletAddressLabel, len(labels)),
+		}
+		for i, l := range labels {
+			rlt.Labels[i] = WalletAddressLabel{
+				Address: l.Address.String(),
+				Label:   l.Label,
+				Note:    l.Note,
+				Tags:    l.Tags,
+			}
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: rlt,
+		})
+	}
+}
+
+// WalletLabelsImportRequest is the request data for POST /api/v2/wallet/labels/import
+type WalletLabelsImportRequest struct {
+	ID     string               `json:"id"`
+	Labels []WalletAddressLabel `json:"labels"`
+}
+
+// WalletLabelsImportResponse is the response data for POST /api/v2/wallet/labels/import
+type WalletLabelsImportResponse struct {
+	// Imported is the number of addresses whose labels were set
+	Imported int `json:"imported"`
+	// NotFound are the addresses that the wallet does not have
+	NotFound []string `json:"not_found"`
+}
+
+// walletLabelsImportHandler sets the label, note and tags of the addresses of a wallet,
+// replacing their metadata. The addresses that the wallet does not have are skipped and returned,
+// e.g. the addresses that are not generated yet by a wallet recreated from its seed.
+// Method: POST
+// URI: /api/v2/wallet/labels/import
+// Args:
+//  id: wallet id
+//  labels: the address, label, note and tags of each address
+func walletLabelsImportHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletLabelsImportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		labels := make([]wallet.AddressLabel, len(req.Labels))
+		for i, l := range req.Labels {
+			addr, err := decodeAddresser(l.Address)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid address %q: %v", l.Address, err))
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			labels[i] = wallet.AddressLabel{
+				Address: addr,
+				EntryMeta: wallet.EntryMeta{
+					Label: l.Label,
+					Note:  l.Note,
+					Tags:  l.Tags,
+				},
+			}
+		}
+
+		notFound, err := gateway.SetAddressLabels(req.ID, labels)
+		if err != nil {
+			writeHTTPResponse(w, walletLabelsErrorResponse(err))
+			return
+		}
+
+		rlt := WalletLabelsImportResponse{
+			Imported: len(labels) - len(notFound),
+			NotFound: make([]string, len(notFound)),
+		}
+		for i, a := range notFound {
+			rlt.NotFound[i] = a.String()
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: rlt,
+		})
+	}
+}
+
+func walletLabelsErrorResponse(err error) HTTPResponse {
+	switch err.(type) {
+	case wallet.Error:
+		switch err {
+		case wallet.ErrWalletNotExist:
+			return NewHTTPErrorResponse(http.StatusNotFound, "")
+		case wallet.ErrWalletAPIDisabled:
+			return NewHTTPErrorResponse(http.StatusForbidden, "")
+		default:
+			return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		}
+	default:
+		return NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// decodeAddresser decodes a skycoin or bitcoin address, the addresses of the wallets of both coins
+func decodeAddresser(s string) (cipher.Addresser, error) {
+	addr, err := cipher.DecodeBase58Address(s)
+	if err == nil {
+		return addr, nil
+	}
+
+	if btcAddr, btcErr := cipher.DecodeBase58BitcoinAddress(s); btcErr == nil {
+		return btcAddr, nil
+	}
+
+	return nil, err
+}
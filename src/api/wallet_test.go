@@ -11,9 +11,11 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"encoding/json"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/skycoin/skycoin/src/cipher"
@@ -21,6 +23,7 @@ import (
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/readable"
 	"github.com/skycoin/skycoin/src/testutil"
+	wh "github.com/skycoin/skycoin/src/util/http"
 	"github.com/skycoin/skycoin/src/visor"
 	"github.com/skycoin/skycoin/src/wallet"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
@@ -212,6 +215,192 @@ func TestGetBalanceHandler(t *testing.T) {
 	}
 }
 
+func TestBalanceHandlerV2(t *testing.T) {
+	validAddr := "2eZYSbzBKJ7QCL4kd5LSqV478rJQGb4UNkf"
+	address, err := cipher.DecodeBase58Address(validAddr)
+	require.NoError(t, err)
+
+	// Enough addresses for several parallel chunks
+	manyAddrs := make([]cipher.Address, balanceChunkSize*2+10)
+	manyAddrStrs := make([]string, len(manyAddrs))
+	manyBals := make([]wallet.BalancePair, len(manyAddrs))
+	for i := range manyAddrs {
+		manyAddrs[i] = testutil.MakeAddress()
+		manyAddrStrs[i] = manyAddrs[i].String()
+		manyBals[i] = wallet.BalancePair{
+			Confirmed: wallet.Balance{Coins: 1e6, Hours: 1},
+			Predicted: wallet.Balance{Coins: 2e6, Hours: 2},
+		}
+	}
+
+	tooManyAddrs := make([]string, MaxBalanceAddresses+1)
+	for i := range tooManyAddrs {
+		tooManyAddrs[i] = validAddr
+	}
+
+	tt := []struct {
+		name                      string
+		method                    string
+		status                    int
+		httpBody                  string
+		getBalanceOfAddrsArg      [][]cipher.Address
+		getBalanceOfAddrsResponse [][]wallet.BalancePair
+		getBalanceOfAddrsError    error
+		httpResponse              HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - EOF",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "400 - no addresses",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "{}",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "addrs is required"),
+		},
+		{
+			name:         "400 - too many addresses",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, BalanceRequest{Addrs: tooManyAddrs}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "too many addrs, the maximum is 10000"),
+		},
+		{
+			name:         "400 - invalid address",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, BalanceRequest{Addrs: []string{validAddr, "invalidAddr"}}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address \"invalidAddr\" is invalid: Invalid base58 character"),
+		},
+		{
+			name:                   "500 - GetBalanceOfAddrsError",
+			method:                 http.MethodPost,
+			status:                 http.StatusInternalServerError,
+			httpBody:               toJSON(t, BalanceRequest{Addrs: []string{validAddr}}),
+			getBalanceOfAddrsArg:   [][]cipher.Address{{address}},
+			getBalanceOfAddrsError: errors.New("GetBalanceOfAddrsError"),
+			httpResponse:           NewHTTPErrorResponse(http.StatusInternalServerError, "gateway.GetBalanceOfAddresses failed: GetBalanceOfAddrsError"),
+		},
+		{
+			name:                 "200 - duplicate addresses",
+			method:               http.MethodPost,
+			status:               http.StatusOK,
+			httpBody:             toJSON(t, BalanceRequest{Addrs: []string{validAddr, validAddr}}),
+			getBalanceOfAddrsArg: [][]cipher.Address{{address}},
+			getBalanceOfAddrsResponse: [][]wallet.BalancePair{
+				{
+					{
+						Confirmed: wallet.Balance{Coins: 1e6, Hours: 1},
+						Predicted: wallet.Balance{Coins: 2e6, Hours: 2},
+					},
+				},
+			},
+			httpResponse: HTTPResponse{
+				Data: BalanceResponse{
+					BalancePair: readable.BalancePair{
+						Confirmed: readable.Balance{Coins: 1e6, Hours: 1},
+						Predicted: readable.Balance{Coins: 2e6, Hours: 2},
+					},
+					Addresses: readable.AddressBalances{
+						validAddr: readable.BalancePair{
+							Confirmed: readable.Balance{Coins: 1e6, Hours: 1},
+							Predicted: readable.Balance{Coins: 2e6, Hours: 2},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "200 - parallel chunks",
+			method:   http.MethodPost,
+			status:   http.StatusOK,
+			httpBody: toJSON(t, BalanceRequest{Addrs: manyAddrStrs}),
+			getBalanceOfAddrsArg: [][]cipher.Address{
+				manyAddrs[:balanceChunkSize],
+				manyAddrs[balanceChunkSize : balanceChunkSize*2],
+				manyAddrs[balanceChunkSize*2:],
+			},
+			getBalanceOfAddrsResponse: [][]wallet.BalancePair{
+				manyBals[:balanceChunkSize],
+				manyBals[balanceChunkSize : balanceChunkSize*2],
+				manyBals[balanceChunkSize*2:],
+			},
+			httpResponse: HTTPResponse{
+				Data: func() BalanceResponse {
+					addrBals := make(readable.AddressBalances, len(manyAddrs))
+					for _, a := range manyAddrStrs {
+						addrBals[a] = readable.BalancePair{
+							Confirmed: readable.Balance{Coins: 1e6, Hours: 1},
+							Predicted: readable.Balance{Coins: 2e6, Hours: 2},
+						}
+					}
+
+					n := uint64(len(manyAddrs))
+					return BalanceResponse{
+						BalancePair: readable.BalancePair{
+							Confirmed: readable.Balance{Coins: 1e6 * n, Hours: n},
+							Predicted: readable.Balance{Coins: 2e6 * n, Hours: 2 * n},
+						},
+						Addresses: addrBals,
+					}
+				}(),
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			for i, addrs := range tc.getBalanceOfAddrsArg {
+				var bals []wallet.BalancePair
+				if tc.getBalanceOfAddrsResponse != nil {
+					bals = tc.getBalanceOfAddrsResponse[i]
+				}
+				gateway.On("GetBalanceOfAddresses", addrs).Return(bals, tc.getBalanceOfAddrsError)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/balance", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var msg BalanceResponse
+				err := json.Unmarshal(rsp.Data, &msg)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, msg)
+			}
+
+			gateway.AssertExpectations(t)
+		})
+	}
+}
+
 func TestWalletGet(t *testing.T) {
 	_, resEntries := makeEntries([]byte("seed"), 5)
 	type httpBody struct {
@@ -454,7 +643,7 @@ func TestWalletBalanceHandler(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			gateway := &MockGatewayer{}
-			gateway.On("GetWalletBalance", tc.walletID).Return(tc.gatewayGetWalletBalanceResult.BalancePair,
+			gateway.On("GetWalletBalance", mock.Anything, tc.walletID).Return(tc.gatewayGetWalletBalanceResult.BalancePair,
 				tc.gatewayGetWalletBalanceResult.Addresses, tc.gatewayBalanceErr)
 
 			endpoint := "/api/v1/wallet/balance"
@@ -882,6 +1071,8 @@ func TestWalletCreateHandler(t *testing.T) {
 		SeedPassphrase string
 		Bip44Coin      string
 		XPub           string
+		XPubAccount    string
+		Addresses      string
 	}
 	tt := []struct {
 		name                      string
@@ -1000,6 +1191,34 @@ func TestWalletCreateHandler(t *testing.T) {
 			err:     "400 Bad Request - bip44-coin is only valid for bip44 type wallets",
 			wltName: "foo",
 		},
+		{
+			name:   "400 - invalid xpub-account",
+			method: http.MethodPost,
+			body: &httpBody{
+				Type:        wallet.WalletTypeXPub,
+				XPub:        "xpub",
+				Label:       "bar",
+				ScanN:       "1",
+				XPubAccount: "foo",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - invalid xpub-account value: strconv.ParseBool: parsing \"foo\": invalid syntax",
+			wltName: "foo",
+		},
+		{
+			name:   "400 - xpub-account does not match type",
+			method: http.MethodPost,
+			body: &httpBody{
+				Type:        wallet.WalletTypeDeterministic,
+				Seed:        bip39.MustNewDefaultMnemonic(),
+				Label:       "bar",
+				ScanN:       "1",
+				XPubAccount: "true",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - xpub-account is only valid for xpub type wallets",
+			wltName: "foo",
+		},
 		{
 			name:   "400 - seed in use",
 			method: http.MethodPost,
@@ -1196,6 +1415,28 @@ func TestWalletCreateHandler(t *testing.T) {
 				Entries: responseEntries[:],
 			},
 		},
+		{
+			name:   "400 - addresses with deterministic wallet",
+			method: http.MethodPost,
+			body: &httpBody{
+				Type:      wallet.WalletTypeDeterministic,
+				Seed:      "foo",
+				Label:     "bar",
+				Addresses: "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - addresses is only valid for collection-watch type wallets",
+		},
+		{
+			name:   "400 - collection-watch wallet missing addresses",
+			method: http.MethodPost,
+			body: &httpBody{
+				Type:  wallet.WalletTypeCollectionWatch,
+				Label: "bar",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - missing addresses",
+		},
 		// CSRF Tests
 		{
 			name:   "200 - OK - CSRF disabled",
@@ -1333,6 +1574,14 @@ func TestWalletCreateHandler(t *testing.T) {
 				if tc.body.XPub != "" {
 					v.Add("xpub", tc.body.XPub)
 				}
+
+				if tc.body.XPubAccount != "" {
+					v.Add("xpub-account", tc.body.XPubAccount)
+				}
+
+				if tc.body.Addresses != "" {
+					v.Add("addresses", tc.body.Addresses)
+				}
 			}
 
 			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(v.Encode()))
@@ -1372,16 +1621,20 @@ func TestWalletCreateHandler(t *testing.T) {
 
 func TestWalletNewSeed(t *testing.T) {
 	type httpBody struct {
-		Entropy string
+		Entropy         string
+		Language        string
+		UserEntropy     string
+		UserEntropyType string
 	}
 	tt := []struct {
-		name      string
-		method    string
-		body      *httpBody
-		status    int
-		err       string
-		entropy   string
-		resultLen int
+		name               string
+		method             string
+		body               *httpBody
+		status             int
+		err                string
+		entropy            string
+		resultLen          int
+		entropyDescription bool
 	}{
 		{
 			name:   "405",
@@ -1409,6 +1662,15 @@ func TestWalletNewSeed(t *testing.T) {
 			err:     "400 Bad Request - entropy length must be 128 or 256",
 			entropy: "200",
 		},
+		{
+			name:   "400 - invalid language",
+			method: http.MethodGet,
+			body: &httpBody{
+				Language: "klingon",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid language",
+		},
 		{
 			name:      "200 - OK with no entropy",
 			method:    http.MethodGet,
@@ -1437,6 +1699,56 @@ func TestWalletNewSeed(t *testing.T) {
 			entropy:   "256",
 			resultLen: 24,
 		},
+		{
+			name:   "400 - invalid dice rolls",
+			method: http.MethodGet,
+			body: &httpBody{
+				UserEntropy: "1234567",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid user-entropy: Dice rolls must only contain the digits 1 to 6",
+		},
+		{
+			name:   "400 - invalid user entropy type",
+			method: http.MethodGet,
+			body: &httpBody{
+				UserEntropy:     "1234",
+				UserEntropyType: "coins",
+			},
+			status: http.StatusBadRequest,
+			err:    "400 Bad Request - invalid user-entropy: Unknown user entropy format, must be dice or hex",
+		},
+		{
+			name:   "200 - OK | dice rolls",
+			method: http.MethodGet,
+			body: &httpBody{
+				UserEntropy: "1234 5612 3456",
+			},
+			status:             http.StatusOK,
+			resultLen:          12,
+			entropyDescription: true,
+		},
+		{
+			name:   "200 - OK | hex user entropy",
+			method: http.MethodGet,
+			body: &httpBody{
+				Entropy:         "256",
+				UserEntropy:     "deadbeef",
+				UserEntropyType: "hex",
+			},
+			status:             http.StatusOK,
+			resultLen:          24,
+			entropyDescription: true,
+		},
+		{
+			name:   "200 - OK | japanese seed",
+			method: http.MethodGet,
+			body: &httpBody{
+				Language: "japanese",
+			},
+			status:    http.StatusOK,
+			resultLen: 12,
+		},
 	}
 
 	// Loop over each test case
@@ -1452,6 +1764,15 @@ func TestWalletNewSeed(t *testing.T) {
 				if tc.body.Entropy != "" {
 					v.Add("entropy", tc.body.Entropy)
 				}
+				if tc.body.Language != "" {
+					v.Add("language", tc.body.Language)
+				}
+				if tc.body.UserEntropy != "" {
+					v.Add("user-entropy", tc.body.UserEntropy)
+				}
+				if tc.body.UserEntropyType != "" {
+					v.Add("user-entropy-type", tc.body.UserEntropyType)
+				}
 			}
 			if len(v) > 0 {
 				endpoint += "?" + v.Encode()
@@ -1481,12 +1802,22 @@ func TestWalletNewSeed(t *testing.T) {
 					strings.TrimSpace(rr.Body.String()), status, tc.err)
 			} else {
 				var msg struct {
-					Seed string `json:"seed"`
+					Seed               string `json:"seed"`
+					EntropyDescription string `json:"entropy_description"`
 				}
 				err = json.Unmarshal(rr.Body.Bytes(), &msg)
 				require.NoError(t, err)
 				// check that expected length is equal to response length
 				require.Equal(t, tc.resultLen, len(strings.Fields(msg.Seed)), tc.name)
+				require.NoError(t, bip39.ValidateMnemonic(msg.Seed))
+
+				if tc.entropyDescription {
+					desc, err := bip39.ParseEntropyDescription(msg.EntropyDescription)
+					require.NoError(t, err)
+					require.NoError(t, desc.VerifyUserEntropy(tc.body.UserEntropy))
+				} else {
+					require.Empty(t, msg.EntropyDescription)
+				}
 			}
 		})
 	}
@@ -2956,7 +3287,7 @@ func makeEntries(seed []byte, n int) ([]wallet.Entry, []readable.WalletEntry) {
 		entries = append(entries, wallet.Entry{
 			Address: cipher.AddressFromPubKey(pubkey),
 			Public:  pubkey,
-			Secret:  seckey,
+			Secret:  wallet.NewSecretKey(seckey),
 		})
 		responseEntries = append(responseEntries, readable.WalletEntry{
 			Address: entries[i].Address.String(),
@@ -3236,3 +3567,991 @@ func TestWalletRecover(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletVerifySeedPassphrase(t *testing.T) {
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		contentType  string
+		req          *VerifySeedPassphraseRequest
+		httpBody     string
+		httpResponse HTTPResponse
+		gatewayErr   error
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, VerifySeedPassphraseRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "empty json body",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:   "id missing",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &VerifySeedPassphraseRequest{
+				SeedPassphrase: "foo",
+			},
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:   "seed passphrase wrong",
+			method: http.MethodPost,
+			status: http.StatusUnprocessableEntity,
+			req: &VerifySeedPassphraseRequest{
+				ID:             "foo",
+				SeedPassphrase: "foo",
+			},
+			gatewayErr:   wallet.ErrSeedPassphraseWrong,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnprocessableEntity, wallet.ErrSeedPassphraseWrong.Error()),
+		},
+		{
+			name:   "invalid password",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &VerifySeedPassphraseRequest{
+				ID:             "foo",
+				SeedPassphrase: "foo",
+				Password:       "pwd",
+			},
+			gatewayErr:   wallet.ErrInvalidPassword,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrInvalidPassword.Error()),
+		},
+		{
+			name:   "wallet does not exist",
+			method: http.MethodPost,
+			status: http.StatusNotFound,
+			req: &VerifySeedPassphraseRequest{
+				ID: "foo",
+			},
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:   "wallet api disabled",
+			method: http.MethodPost,
+			status: http.StatusForbidden,
+			req: &VerifySeedPassphraseRequest{
+				ID: "foo",
+			},
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "wallet other error",
+			method: http.MethodPost,
+			status: http.StatusInternalServerError,
+			req: &VerifySeedPassphraseRequest{
+				ID: "foo",
+			},
+			gatewayErr:   errors.New("wallet error"),
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "wallet error"),
+		},
+		{
+			name:   "ok",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &VerifySeedPassphraseRequest{
+				ID:             "foo",
+				SeedPassphrase: "foo",
+				Password:       "pwd",
+			},
+			httpResponse: HTTPResponse{
+				Data: struct{}{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				var password []byte
+				if tc.req.Password != "" {
+					password = []byte(tc.req.Password)
+				}
+				gateway.On("VerifySeedPassphrase", tc.req.ID, password, tc.req.SeedPassphrase).Return(tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			endpoint := "/api/v2/wallet/seed-passphrase/verify"
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = ContentTypeJSON
+			}
+			req.Header.Set("Content-Type", contentType)
+
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+			}
+		})
+	}
+}
+
+func TestWalletSeedMatch(t *testing.T) {
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		req          *WalletSeedMatchRequest
+		httpBody     string
+		httpResponse HTTPResponse
+		match        bool
+		gatewayErr   error
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, WalletSeedMatchRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "empty json body",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "id missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, WalletSeedMatchRequest{Seed: "foo bar baz"}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:         "seed missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, WalletSeedMatchRequest{ID: "foo"}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "seed is required"),
+		},
+		{
+			name:   "wallet not generated from a seed",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletSeedMatchRequest{
+				ID:   "foo",
+				Seed: "foo bar baz",
+			},
+			gatewayErr:   wallet.ErrWalletSeedVerify,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrWalletSeedVerify.Error()),
+		},
+		{
+			name:   "wallet does not exist",
+			method: http.MethodPost,
+			status: http.StatusNotFound,
+			req: &WalletSeedMatchRequest{
+				ID:   "foo",
+				Seed: "foo bar baz",
+			},
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, ""),
+		},
+		{
+			name:   "wallet api disabled",
+			method: http.MethodPost,
+			status: http.StatusForbidden,
+			req: &WalletSeedMatchRequest{
+				ID:   "foo",
+				Seed: "foo bar baz",
+			},
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "wallet other error",
+			method: http.MethodPost,
+			status: http.StatusInternalServerError,
+			req: &WalletSeedMatchRequest{
+				ID:   "foo",
+				Seed: "foo bar baz",
+			},
+			gatewayErr:   errors.New("wallet error"),
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "wallet error"),
+		},
+		{
+			name:   "no match",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &WalletSeedMatchRequest{
+				ID:   "foo",
+				Seed: "foo bar baz",
+			},
+			httpResponse: HTTPResponse{
+				Data: WalletSeedMatchResponse{},
+			},
+		},
+		{
+			name:   "match",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &WalletSeedMatchRequest{
+				ID:             "foo",
+				Seed:           "foo bar baz",
+				SeedPassphrase: "passphrase",
+			},
+			match: true,
+			httpResponse: HTTPResponse{
+				Data: WalletSeedMatchResponse{
+					Match: true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				gateway.On("VerifyWalletSeed", tc.req.ID, tc.req.Seed, tc.req.SeedPassphrase).Return(tc.match, tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/wallet/seed/match", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+
+			expected, err := json.MarshalIndent(tc.httpResponse, "", "    ")
+			require.NoError(t, err)
+			require.Equal(t, string(expected), rr.Body.String())
+
+			// The seed is never returned
+			if tc.req != nil {
+				require.NotContains(t, rr.Body.String(), tc.req.Seed)
+			}
+		})
+	}
+}
+
+func TestWalletSignMessage(t *testing.T) {
+	addr := testutil.MakeAddress()
+	sig := cipher.MustSignMessage(cipher.MustNewSecKey(testutil.RandBytes(t, 32)), []byte("foo"))
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		req          *WalletSignMessageRequest
+		httpBody     string
+		httpResponse HTTPResponse
+		gatewayErr   error
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, WalletSignMessageRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "empty json body",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "id missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, WalletSignMessageRequest{Address: addr.String()}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:         "address missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, WalletSignMessageRequest{ID: "foo"}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address is required"),
+		},
+		{
+			name:   "invalid address",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, WalletSignMessageRequest{
+				ID:      "foo",
+				Address: "7apQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid address: Invalid checksum, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?"),
+		},
+		{
+			name:   "address not in wallet",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletSignMessageRequest{
+				ID:      "foo",
+				Address: addr.String(),
+				Message: "foo",
+			},
+			gatewayErr:   wallet.ErrUnknownAddress,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrUnknownAddress.Error()),
+		},
+		{
+			name:   "wallet does not exist",
+			method: http.MethodPost,
+			status: http.StatusNotFound,
+			req: &WalletSignMessageRequest{
+				ID:      "foo",
+				Address: addr.String(),
+			},
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:   "wallet api disabled",
+			method: http.MethodPost,
+			status: http.StatusForbidden,
+			req: &WalletSignMessageRequest{
+				ID:      "foo",
+				Address: addr.String(),
+			},
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "wallet other error",
+			method: http.MethodPost,
+			status: http.StatusInternalServerError,
+			req: &WalletSignMessageRequest{
+				ID:      "foo",
+				Address: addr.String(),
+			},
+			gatewayErr:   errors.New("wallet error"),
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "wallet error"),
+		},
+		{
+			name:   "ok",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &WalletSignMessageRequest{
+				ID:       "foo",
+				Address:  addr.String(),
+				Message:  "foo",
+				Password: "pwd",
+			},
+			httpResponse: HTTPResponse{
+				Data: WalletSignMessageResponse{
+					Signature: sig.Hex(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				var password []byte
+				if tc.req.Password != "" {
+					password = []byte(tc.req.Password)
+				}
+				gateway.On("SignMessage", tc.req.ID, password, addr, []byte(tc.req.Message)).Return(sig, tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			endpoint := "/api/v2/wallet/message/sign"
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var msgRsp WalletSignMessageResponse
+				err := json.Unmarshal(rsp.Data, &msgRsp)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data.(WalletSignMessageResponse), msgRsp)
+			}
+		})
+	}
+}
+
+func TestWalletUnlock(t *testing.T) {
+	expires := time.Unix(1571112910, 0)
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		req          *WalletUnlockRequest
+		httpBody     string
+		httpResponse HTTPResponse
+		expires      time.Time
+		gatewayErr   error
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, WalletUnlockRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "empty json body",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "invalid ttl format",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     `{"id":"foo","password":"pwd","ttl":"foo"}`,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, `time: invalid duration "foo"`),
+		},
+		{
+			name:   "id missing",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletUnlockRequest{
+				Password: "pwd",
+				TTL:      wh.FromDuration(time.Hour),
+			},
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:   "invalid ttl",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletUnlockRequest{
+				ID:       "foo",
+				Password: "pwd",
+			},
+			gatewayErr:   wallet.ErrInvalidUnlockTTL,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrInvalidUnlockTTL.Error()),
+		},
+		{
+			name:   "invalid password",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletUnlockRequest{
+				ID:       "foo",
+				Password: "pwd",
+				TTL:      wh.FromDuration(time.Hour),
+			},
+			gatewayErr:   wallet.ErrInvalidPassword,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrInvalidPassword.Error()),
+		},
+		{
+			name:   "wallet not encrypted",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			req: &WalletUnlockRequest{
+				ID:  "foo",
+				TTL: wh.FromDuration(time.Hour),
+			},
+			gatewayErr:   wallet.ErrWalletNotEncrypted,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrWalletNotEncrypted.Error()),
+		},
+		{
+			name:   "wallet does not exist",
+			method: http.MethodPost,
+			status: http.StatusNotFound,
+			req: &WalletUnlockRequest{
+				ID:       "foo",
+				Password: "pwd",
+				TTL:      wh.FromDuration(time.Hour),
+			},
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:   "wallet api disabled",
+			method: http.MethodPost,
+			status: http.StatusForbidden,
+			req: &WalletUnlockRequest{
+				ID:       "foo",
+				Password: "pwd",
+				TTL:      wh.FromDuration(time.Hour),
+			},
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "ok",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &WalletUnlockRequest{
+				ID:       "foo",
+				Password: "pwd",
+				TTL:      wh.FromDuration(time.Hour),
+			},
+			expires: expires,
+			httpResponse: HTTPResponse{
+				Data: WalletUnlockResponse{
+					UnlockedUntil: 1571112910,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				gateway.On("UnlockWallet", tc.req.ID, []byte(tc.req.Password), tc.req.TTL.Duration).Return(tc.expires, tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/wallet/unlock", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var unlockRsp WalletUnlockResponse
+				err := json.Unmarshal(rsp.Data, &unlockRsp)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data.(WalletUnlockResponse), unlockRsp)
+			}
+		})
+	}
+}
+
+func TestWalletLock(t *testing.T) {
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		req          *WalletLockRequest
+		httpBody     string
+		httpResponse HTTPResponse
+		gatewayErr   error
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, WalletLockRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "id missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			req:          &WalletLockRequest{},
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:   "wallet does not exist",
+			method: http.MethodPost,
+			status: http.StatusNotFound,
+			req: &WalletLockRequest{
+				ID: "foo",
+			},
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:   "wallet api disabled",
+			method: http.MethodPost,
+			status: http.StatusForbidden,
+			req: &WalletLockRequest{
+				ID: "foo",
+			},
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "ok",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			req: &WalletLockRequest{
+				ID: "foo",
+			},
+			httpResponse: HTTPResponse{
+				Data: struct{}{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				gateway.On("LockWallet", tc.req.ID).Return(tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/wallet/lock", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+			}
+		})
+	}
+}
+
+func TestWalletLabels(t *testing.T) {
+	addr := testutil.MakeAddress()
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		id           string
+		labels       []wallet.AddressLabel
+		gatewayErr   error
+		httpResponse HTTPResponse
+		rsp          WalletLabelsResponse
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodPost,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "id missing",
+			method:       http.MethodGet,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:         "wallet does not exist",
+			method:       http.MethodGet,
+			status:       http.StatusNotFound,
+			id:           "foo",
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:         "wallet api disabled",
+			method:       http.MethodGet,
+			status:       http.StatusForbidden,
+			id:           "foo",
+			gatewayErr:   wallet.ErrWalletAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:   "ok",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			id:     "foo",
+			labels: []wallet.AddressLabel{
+				{
+					Address: addr,
+					EntryMeta: wallet.EntryMeta{
+						Label: "savings",
+						Note:  "for the holidays",
+						Tags:  map[string]string{"category": "savings"},
+					},
+				},
+			},
+			rsp: WalletLabelsResponse{
+				Labels: []WalletAddressLabel{
+					{
+						Address: addr.String(),
+						Label:   "savings",
+						Note:    "for the holidays",
+						Tags:    map[string]string{"category": "savings"},
+					},
+				},
+			},
+		},
+		{
+			name:   "ok no labels",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			id:     "foo",
+			rsp: WalletLabelsResponse{
+				Labels: []WalletAddressLabel{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetAddressLabels", tc.id).Return(tc.labels, tc.gatewayErr)
+
+			v := url.Values{}
+			if tc.id != "" {
+				v.Add("id", tc.id)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/wallet/labels?"+v.Encode(), nil)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data != nil {
+				var labels WalletLabelsResponse
+				require.NoError(t, json.Unmarshal(rsp.Data, &labels))
+				require.Equal(t, tc.rsp, labels)
+			}
+		})
+	}
+}
+
+func TestWalletLabelsImport(t *testing.T) {
+	addr := testutil.MakeAddress()
+	unknown := testutil.MakeAddress()
+	btcAddr := cipher.BitcoinAddressFromPubKey(testutil.MakePubKey())
+
+	labels := []wallet.AddressLabel{
+		{
+			Address: addr,
+			EntryMeta: wallet.EntryMeta{
+				Label: "savings",
+				Tags:  map[string]string{"category": "savings"},
+			},
+		},
+		{
+			Address: btcAddr,
+			EntryMeta: wallet.EntryMeta{
+				Note: "bitcoin",
+			},
+		},
+		{
+			Address: unknown,
+			EntryMeta: wallet.EntryMeta{
+				Label: "unknown",
+			},
+		},
+	}
+
+	req := &WalletLabelsImportRequest{
+		ID: "foo",
+		Labels: []WalletAddressLabel{
+			{
+				Address: addr.String(),
+				Label:   "savings",
+				Tags:    map[string]string{"category": "savings"},
+			},
+			{
+				Address: btcAddr.String(),
+				Note:    "bitcoin",
+			},
+			{
+				Address: unknown.String(),
+				Label:   "unknown",
+			},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		req          *WalletLabelsImportRequest
+		httpBody     string
+		notFound     []cipher.Addresser
+		gatewayErr   error
+		httpResponse HTTPResponse
+		rsp          WalletLabelsImportResponse
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpBody:     toJSON(t, WalletLabelsImportRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, "Method Not Allowed"),
+		},
+		{
+			name:         "id missing",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     toJSON(t, WalletLabelsImportRequest{}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:   "invalid address",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, WalletLabelsImportRequest{
+				ID: "foo",
+				Labels: []WalletAddressLabel{
+					{
+						Address: "foo",
+					},
+				},
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, `invalid address "foo": Invalid address length`),
+		},
+		{
+			name:         "wallet does not exist",
+			method:       http.MethodPost,
+			status:       http.StatusNotFound,
+			req:          req,
+			gatewayErr:   wallet.ErrWalletNotExist,
+			httpResponse: NewHTTPErrorResponse(http.StatusNotFound, "Not Found"),
+		},
+		{
+			name:         "wallet read only",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			req:          req,
+			gatewayErr:   wallet.ErrWalletReadOnly,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrWalletReadOnly.Error()),
+		},
+		{
+			name:     "ok",
+			method:   http.MethodPost,
+			status:   http.StatusOK,
+			req:      req,
+			notFound: []cipher.Addresser{unknown},
+			rsp: WalletLabelsImportResponse{
+				Imported: 2,
+				NotFound: []string{unknown.String()},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.req != nil {
+				gateway.On("SetAddressLabels", tc.req.ID, labels).Return(tc.notFound, tc.gatewayErr)
+			}
+
+			if tc.httpBody == "" && tc.req != nil {
+				tc.httpBody = toJSON(t, tc.req)
+			}
+
+			req, err := http.NewRequest(tc.method, "/api/v2/wallet/labels/import", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data != nil {
+				var r WalletLabelsImportResponse
+				require.NoError(t, json.Unmarshal(rsp.Data, &r))
+				require.Equal(t, tc.rsp, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// WatchListRequest is the request data for POST /api/v2/watch
+type WatchListRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// WatchListResponse is the response data of /api/v2/watch
+type WatchListResponse struct {
+	Addresses []string `json:"addresses"`
+}
+
+func newWatchListResponse(addrs []cipher.Address) WatchListResponse {
+	ss := make([]string, len(addrs))
+	for i, a := range addrs {
+		ss[i] = a.String()
+	}
+
+	return WatchListResponse{
+		Addresses: ss,
+	}
+}
+
+// Dispatches /watch endpoint.
+// Method: GET, POST, DELETE
+// URI: /api/v2/watch
+func watchListHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getWatchListHandler(w, gateway)
+		case http.MethodPost:
+			addWatchedAddressesHandler(w, r, gateway)
+		case http.MethodDelete:
+			removeWatchedAddressesHandler(w, r, gateway)
+		default:
+			writeError405Response(w)
+		}
+	}
+}
+
+// Returns the watched addresses
+func getWatchListHandler(w http.ResponseWriter, gateway Gatewayer) {
+	addrs, err := gateway.GetWatchedAddresses()
+	if err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: newWatchListResponse(addrs),
+	})
+}
+
+// Adds addresses to the watch list, returns the watched addresses
+// Body: WatchListRequest
+func addWatchedAddressesHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	var req WatchListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError400Response(w, err.Error())
+		return
+	}
+
+	if len(req.Addresses) == 0 {
+		writeError400Response(w, "addresses is required")
+		return
+	}
+
+	addrs := make([]cipher.Address, len(req.Addresses))
+	for i, a := range req.Addresses {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			writeError400Response(w, fmt.Sprintf("address %q is invalid: %v", a, err))
+			return
+		}
+		addrs[i] = addr
+	}
+
+	watched, err := gateway.AddWatchedAddresses(addrs)
+	if err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: newWatchListResponse(watched),
+	})
+}
+
+// Removes addresses from the watch list, returns the watched addresses
+// Args:
+//     addrs: comma-separated list of addresses [required]
+func removeWatchedAddressesHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	addrs, err := parseAddressesFromStr(r.FormValue("addrs"))
+	if err != nil {
+		writeError400Response(w, fmt.Sprintf("parse parameter: 'addrs' failed: %v", err))
+		return
+	}
+
+	if len(addrs) == 0 {
+		writeError400Response(w, "addrs is required")
+		return
+	}
+
+	watched, err := gateway.RemoveWatchedAddresses(addrs)
+	if err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: newWatchListResponse(watched),
+	})
+}
+
+// Returns the transactions of the watched addresses, with the pagination of /api/v2/transactions
+// Method: GET
+// URI: /api/v2/watch/transactions
+// Args:
+//     confirmed: Whether the transactions should be confirmed [optional, must be 0 or 1; if not provided, returns all]
+//     verbose: [bool] include verbose transaction input data
+//     page: Page number
+//     limit: the number of transactions per page [optional, default to 10, must be <= 100]
+//     cursor: the next_cursor of the previous page, replaces page [optional]
+//     sort: Sort the transactions by block seq. [optional, must be desc or asc]
+func watchTransactionsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		verbose, err := parseBoolFlag(r.FormValue("verbose"))
+		if err != nil {
+			writeError400Response(w, "invalid value for verbose")
+			return
+		}
+
+		addrs, err := gateway.GetWatchedAddresses()
+		if err != nil {
+			writeHTTPResponse(w, webhookErrorResponse(err))
+			return
+		}
+
+		// An addresses filter without addresses matches all transactions
+		if len(addrs) == 0 {
+			writeHTTPResponse(w, HTTPResponse{
+				Data: TransactionsWithStatusV2{
+					PageInfo: readable.PageInfo{
+						PageSize:    visor.DefaultTxnPageSize,
+						CurrentPage: 1,
+					},
+					Txns: []readable.TransactionWithStatus{},
+				},
+			})
+			return
+		}
+
+		writeTransactionsPageV2(w, r, gateway, []visor.TxFilter{visor.NewAddrsFilter(addrs)}, verbose)
+	}
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/notify"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func TestWatchListHandler(t *testing.T) {
+	a1 := makeAddress()
+	a2 := makeAddress()
+
+	watchedResponse := HTTPResponse{
+		Data: WatchListResponse{
+			Addresses: []string{a1.String(), a2.String()},
+		},
+	}
+
+	tt := []struct {
+		name     string
+		method   string
+		query    string
+		httpBody string
+		status   int
+
+		getWatched    []cipher.Address
+		getWatchedErr error
+		addArg        []cipher.Address
+		removeArg     []cipher.Address
+		updated       []cipher.Address
+		updateErr     error
+
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodPut,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:          "GET 403",
+			method:        http.MethodGet,
+			status:        http.StatusForbidden,
+			getWatchedErr: notify.ErrWebhookAPIDisabled,
+			httpResponse:  NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:         "GET 200",
+			method:       http.MethodGet,
+			status:       http.StatusOK,
+			getWatched:   []cipher.Address{a1, a2},
+			httpResponse: watchedResponse,
+		},
+		{
+			name:         "POST 400 invalid json",
+			method:       http.MethodPost,
+			httpBody:     "{",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "unexpected EOF"),
+		},
+		{
+			name:         "POST 400 no addresses",
+			method:       http.MethodPost,
+			httpBody:     `{"addresses":[]}`,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "addresses is required"),
+		},
+		{
+			name:         "POST 400 invalid address",
+			method:       http.MethodPost,
+			httpBody:     `{"addresses":["foo"]}`,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address \"foo\" is invalid: Invalid address length"),
+		},
+		{
+			name:         "POST 500",
+			method:       http.MethodPost,
+			httpBody:     `{"addresses":["` + a2.String() + `"]}`,
+			status:       http.StatusInternalServerError,
+			addArg:       []cipher.Address{a2},
+			updateErr:    errors.New("failed to save watch list file"),
+			httpResponse: NewHTTPErrorResponse(http.StatusInternalServerError, "failed to save watch list file"),
+		},
+		{
+			name:         "POST 200",
+			method:       http.MethodPost,
+			httpBody:     `{"addresses":["` + a2.String() + `"]}`,
+			status:       http.StatusOK,
+			addArg:       []cipher.Address{a2},
+			updated:      []cipher.Address{a1, a2},
+			httpResponse: watchedResponse,
+		},
+		{
+			name:         "DELETE 400 no addrs",
+			method:       http.MethodDelete,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "addrs is required"),
+		},
+		{
+			name:         "DELETE 400 invalid addrs",
+			method:       http.MethodDelete,
+			query:        "?addrs=foo",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "parse parameter: 'addrs' failed: address \"foo\" is invalid: Invalid address length"),
+		},
+		{
+			name:         "DELETE 403",
+			method:       http.MethodDelete,
+			query:        "?addrs=" + a1.String(),
+			status:       http.StatusForbidden,
+			removeArg:    []cipher.Address{a1},
+			updateErr:    notify.ErrWebhookAPIDisabled,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:      "DELETE 200",
+			method:    http.MethodDelete,
+			query:     "?addrs=" + a1.String(),
+			status:    http.StatusOK,
+			removeArg: []cipher.Address{a1},
+			updated:   []cipher.Address{},
+			httpResponse: HTTPResponse{
+				Data: WatchListResponse{
+					Addresses: []string{},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetWatchedAddresses").Return(tc.getWatched, tc.getWatchedErr)
+			gateway.On("AddWatchedAddresses", tc.addArg).Return(tc.updated, tc.updateErr)
+			gateway.On("RemoveWatchedAddresses", tc.removeArg).Return(tc.updated, tc.updateErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/watch"+tc.query, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var r WatchListResponse
+				err := json.Unmarshal(rsp.Data, &r)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, r)
+			}
+		})
+	}
+}
+
+func TestWatchTransactionsHandler(t *testing.T) {
+	addr := makeAddress()
+	txn := prepareTxnAndInputs(t).txn
+
+	txns := []visor.Transaction{
+		{
+			Transaction: txn,
+			Status:      visor.NewConfirmedTransactionStatus(10, 1),
+			Time:        1e9,
+		},
+	}
+	rTxns, err := NewTransactionsWithStatus(txns)
+	require.NoError(t, err)
+
+	pageIndex, err := visor.NewPageIndex(visor.DefaultTxnPageSize, 1)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name   string
+		method string
+		query  string
+		status int
+
+		getWatched    []cipher.Address
+		getWatchedErr error
+		getTxnsErr    error
+
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodDelete,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:          "403",
+			method:        http.MethodGet,
+			status:        http.StatusForbidden,
+			getWatchedErr: notify.ErrWebhookAPIDisabled,
+			httpResponse:  NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:         "400 invalid verbose",
+			method:       http.MethodGet,
+			query:        "?verbose=foo",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid value for verbose"),
+		},
+		{
+			name:         "400 invalid sort",
+			method:       http.MethodGet,
+			query:        "?sort=foo",
+			status:       http.StatusBadRequest,
+			getWatched:   []cipher.Address{addr},
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid 'sort' value: Unknown sort order"),
+		},
+		{
+			name:   "200 no watched addresses",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			httpResponse: HTTPResponse{
+				Data: TransactionsWithStatusV2{
+					PageInfo: readable.PageInfo{
+						PageSize:    visor.DefaultTxnPageSize,
+						CurrentPage: 1,
+					},
+					Txns: []readable.TransactionWithStatus{},
+				},
+			},
+		},
+		{
+			name:       "200",
+			method:     http.MethodGet,
+			status:     http.StatusOK,
+			getWatched: []cipher.Address{addr},
+			httpResponse: HTTPResponse{
+				Data: TransactionsWithStatusV2{
+					PageInfo: readable.PageInfo{
+						TotalPages:  1,
+						PageSize:    visor.DefaultTxnPageSize,
+						CurrentPage: 1,
+						Total:       1,
+					},
+					Txns: rTxns.Transactions,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetWatchedAddresses").Return(tc.getWatched, tc.getWatchedErr)
+			gateway.On("GetTransactions", mock.Anything, []visor.TxFilter{visor.NewAddrsFilter(tc.getWatched)}, visor.AscOrder, pageIndex).Return(txns, visor.TxnPage{
+				TotalPages: 1,
+				Total:      1,
+			}, tc.getTxnsErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/watch/transactions"+tc.query, nil)
+			require.NoError(t, err)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v` %s", status, tc.status, rr.Body.String())
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var r TransactionsWithStatusV2
+				err := json.Unmarshal(rsp.Data, &r)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/notify"
+)
+
+// WebhookRequest is the request data for POST /api/v2/webhooks
+type WebhookRequest struct {
+	URL           string   `json:"url"`
+	Addresses     []string `json:"addresses"`
+	Confirmations uint64   `json:"confirmations"`
+	Blocks        bool     `json:"blocks"`
+	WatchList     bool     `json:"watch_list"`
+}
+
+// Dispatches /webhooks endpoint.
+// Method: GET, POST, DELETE
+// URI: /api/v2/webhooks
+func webhooksHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getWebhooksHandler(w, gateway)
+		case http.MethodPost:
+			addWebhookHandler(w, r, gateway)
+		case http.MethodDelete:
+			removeWebhookHandler(w, r, gateway)
+		default:
+			writeError405Response(w)
+		}
+	}
+}
+
+// webhookErrorResponse maps a notify error to an HTTP error response
+func webhookErrorResponse(err error) HTTPResponse {
+	switch err {
+	case notify.ErrWebhookAPIDisabled:
+		return NewHTTPErrorResponse(http.StatusForbidden, "")
+	case notify.ErrWebhookNotFound:
+		return NewHTTPErrorResponse(http.StatusNotFound, "")
+	}
+
+	if _, ok := err.(notify.Error); ok {
+		return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	return NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+}
+
+// Returns the registered webhooks, without their secrets
+func getWebhooksHandler(w http.ResponseWriter, gateway Gatewayer) {
+	webhooks, err := gateway.GetWebhooks()
+	if err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: webhooks,
+	})
+}
+
+// Registers a webhook. The response includes the secret of the webhook
+// that signs its payloads, which is not returned again.
+// Body: WebhookRequest
+func addWebhookHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError400Response(w, err.Error())
+		return
+	}
+
+	if req.URL == "" {
+		writeError400Response(w, "url is required")
+		return
+	}
+
+	webhook, err := gateway.AddWebhook(notify.Webhook{
+		URL:           req.URL,
+		Addresses:     req.Addresses,
+		Confirmations: req.Confirmations,
+		Blocks:        req.Blocks,
+		WatchList:     req.WatchList,
+	})
+	if err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: webhook,
+	})
+}
+
+// Removes a webhook
+// Args:
+//     id: webhook ID [required]
+func removeWebhookHandler(w http.ResponseWriter, r *http.Request, gateway Gatewayer) {
+	id := r.FormValue("id")
+	if id == "" {
+		writeError400Response(w, "id is required")
+		return
+	}
+
+	if err := gateway.RemoveWebhook(id); err != nil {
+		writeHTTPResponse(w, webhookErrorResponse(err))
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{})
+}
+
+// Returns the recent deliveries of a webhook, newest first
+// Method: GET
+// URI: /api/v2/webhooks/deliveries
+// Args:
+//     id: webhook ID [required]
+func webhookDeliveriesHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError405Response(w)
+			return
+		}
+
+		id := r.FormValue("id")
+		if id == "" {
+			writeError400Response(w, "id is required")
+			return
+		}
+
+		deliveries, err := gateway.GetWebhookDeliveries(id)
+		if err != nil {
+			writeHTTPResponse(w, webhookErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: deliveries,
+		})
+	}
+}
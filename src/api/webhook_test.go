@@ -0,0 +1,405 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/notify"
+)
+
+func TestGetWebhooksHandler(t *testing.T) {
+	webhooks := []notify.Webhook{
+		{
+			ID:            "foo",
+			URL:           "https://example.com/hook",
+			Secret:        "secret",
+			Addresses:     []string{"2HTnQe3ZupkG6k8S81brNC3JycGV2Em71F2"},
+			Confirmations: 6,
+			CreatedAt:     1571109000,
+		},
+	}
+
+	tt := []struct {
+		name           string
+		method         string
+		status         int
+		getWebhooks    []notify.Webhook
+		getWebhooksErr error
+		httpResponse   HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodPut,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:           "403",
+			method:         http.MethodGet,
+			status:         http.StatusForbidden,
+			getWebhooksErr: notify.ErrWebhookAPIDisabled,
+			httpResponse:   NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name:           "500",
+			method:         http.MethodGet,
+			status:         http.StatusInternalServerError,
+			getWebhooksErr: errors.New("failure"),
+			httpResponse:   NewHTTPErrorResponse(http.StatusInternalServerError, "failure"),
+		},
+		{
+			name:        "200",
+			method:      http.MethodGet,
+			status:      http.StatusOK,
+			getWebhooks: webhooks,
+			httpResponse: HTTPResponse{
+				Data: []notify.Webhook{
+					{
+						ID:            "foo",
+						URL:           "https://example.com/hook",
+						Addresses:     []string{"2HTnQe3ZupkG6k8S81brNC3JycGV2Em71F2"},
+						Confirmations: 6,
+						CreatedAt:     1571109000,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetWebhooks").Return(tc.getWebhooks, tc.getWebhooksErr)
+
+			req, err := http.NewRequest(tc.method, "/api/v2/webhooks", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var ws []notify.Webhook
+				err := json.Unmarshal(rsp.Data, &ws)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, ws)
+			}
+		})
+	}
+}
+
+func TestAddWebhookHandler(t *testing.T) {
+	webhook := notify.Webhook{
+		URL:       "https://example.com/hook",
+		Addresses: []string{"2HTnQe3ZupkG6k8S81brNC3JycGV2Em71F2"},
+	}
+
+	created := webhook
+	created.ID = "foo"
+	created.Secret = "secret"
+	created.Confirmations = 1
+
+	tt := []struct {
+		name          string
+		contentType   string
+		httpBody      string
+		status        int
+		addWebhookArg notify.Webhook
+		addWebhook    *notify.Webhook
+		addWebhookErr error
+		httpResponse  HTTPResponse
+		csrfDisabled  bool
+	}{
+		{
+			name:         "415",
+			contentType:  ContentTypeForm,
+			status:       http.StatusUnsupportedMediaType,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnsupportedMediaType, ""),
+		},
+		{
+			name:         "400 - EOF",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+		{
+			name:         "400 - missing url",
+			httpBody:     toJSON(t, WebhookRequest{Blocks: true}),
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "url is required"),
+		},
+		{
+			name: "400 - no events",
+			httpBody: toJSON(t, WebhookRequest{
+				URL: "https://example.com/hook",
+			}),
+			status: http.StatusBadRequest,
+			addWebhookArg: notify.Webhook{
+				URL: "https://example.com/hook",
+			},
+			addWebhookErr: notify.ErrWebhookNoEvents,
+			httpResponse:  NewHTTPErrorResponse(http.StatusBadRequest, "Webhook must watch addresses, blocks or the watch list"),
+		},
+		{
+			name: "403",
+			httpBody: toJSON(t, WebhookRequest{
+				URL:       webhook.URL,
+				Addresses: webhook.Addresses,
+			}),
+			status:        http.StatusForbidden,
+			addWebhookArg: webhook,
+			addWebhookErr: notify.ErrWebhookAPIDisabled,
+			httpResponse:  NewHTTPErrorResponse(http.StatusForbidden, ""),
+		},
+		{
+			name: "403 - csrf disabled",
+			httpBody: toJSON(t, WebhookRequest{
+				URL:       webhook.URL,
+				Addresses: webhook.Addresses,
+			}),
+			status:       http.StatusForbidden,
+			httpResponse: NewHTTPErrorResponse(http.StatusForbidden, "invalid CSRF token"),
+			csrfDisabled: true,
+		},
+		{
+			name: "200",
+			httpBody: toJSON(t, WebhookRequest{
+				URL:       webhook.URL,
+				Addresses: webhook.Addresses,
+			}),
+			status:        http.StatusOK,
+			addWebhookArg: webhook,
+			addWebhook:    &created,
+			httpResponse: HTTPResponse{
+				Data: created,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("AddWebhook", tc.addWebhookArg).Return(tc.addWebhook, tc.addWebhookErr)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/v2/webhooks", strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = ContentTypeJSON
+			}
+			req.Header.Set("Content-Type", contentType)
+
+			if tc.csrfDisabled {
+				setCSRFParameters(t, tokenInvalid, req)
+			} else {
+				setCSRFParameters(t, tokenValid, req)
+			}
+
+			rr := httptest.NewRecorder()
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var w notify.Webhook
+				err := json.Unmarshal(rsp.Data, &w)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, w)
+			}
+		})
+	}
+}
+
+func TestRemoveWebhookHandler(t *testing.T) {
+	tt := []struct {
+		name             string
+		id               string
+		status           int
+		removeWebhookErr error
+		httpResponse     HTTPResponse
+	}{
+		{
+			name:         "400 - missing id",
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:             "404",
+			id:               "foo",
+			status:           http.StatusNotFound,
+			removeWebhookErr: notify.ErrWebhookNotFound,
+			httpResponse:     NewHTTPErrorResponse(http.StatusNotFound, ""),
+		},
+		{
+			name:         "200",
+			id:           "foo",
+			status:       http.StatusOK,
+			httpResponse: HTTPResponse{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("RemoveWebhook", tc.id).Return(tc.removeWebhookErr)
+
+			endpoint := "/api/v2/webhooks"
+			if tc.id != "" {
+				endpoint += "?id=" + tc.id
+			}
+
+			req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+			require.NoError(t, err)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			cfg := defaultMuxConfig()
+			cfg.disableCSRF = false
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+			require.Nil(t, rsp.Data)
+		})
+	}
+}
+
+func TestWebhookDeliveriesHandler(t *testing.T) {
+	deliveries := []notify.Delivery{
+		{
+			ID:         "bar",
+			WebhookID:  "foo",
+			Type:       notify.EventBlock,
+			Status:     notify.DeliveryFailed,
+			Attempts:   5,
+			StatusCode: http.StatusServiceUnavailable,
+			Error:      "webhook responded with status 503",
+			Payload: notify.Payload{
+				ID:        "bar",
+				Type:      notify.EventBlock,
+				WebhookID: "foo",
+			},
+		},
+	}
+
+	tt := []struct {
+		name          string
+		method        string
+		id            string
+		status        int
+		deliveries    []notify.Delivery
+		deliveriesErr error
+		httpResponse  HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodPost,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+		{
+			name:         "400 - missing id",
+			method:       http.MethodGet,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "id is required"),
+		},
+		{
+			name:          "404",
+			method:        http.MethodGet,
+			id:            "foo",
+			status:        http.StatusNotFound,
+			deliveriesErr: notify.ErrWebhookNotFound,
+			httpResponse:  NewHTTPErrorResponse(http.StatusNotFound, ""),
+		},
+		{
+			name:       "200",
+			method:     http.MethodGet,
+			id:         "foo",
+			status:     http.StatusOK,
+			deliveries: deliveries,
+			httpResponse: HTTPResponse{
+				Data: deliveries,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetWebhookDeliveries", tc.id).Return(tc.deliveries, tc.deliveriesErr)
+
+			endpoint := "/api/v2/webhooks/deliveries"
+			if tc.id != "" {
+				endpoint += "?id=" + tc.id
+			}
+
+			req, err := http.NewRequest(tc.method, endpoint, nil)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", ContentTypeJSON)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.Unmarshal(rr.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var ds []notify.Delivery
+				err := json.Unmarshal(rsp.Data, &ds)
+				require.NoError(t, err)
+				require.Equal(t, tc.httpResponse.Data, ds)
+			}
+		})
+	}
+}
@@ -0,0 +1,521 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/websocket"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// Types of the messages streamed by /api/v2/ws
+const (
+	// WSEventBlock a block was added to the blockchain
+	WSEventBlock = "block"
+	// WSEventTransaction a transaction was confirmed in a block
+	WSEventTransaction = "transaction"
+	// WSEventUnconfirmedTransaction a transaction was added to the unconfirmed pool
+	WSEventUnconfirmedTransaction = "unconfirmed_transaction"
+	// WSEventAddressBalance the balance of a subscribed address changed
+	WSEventAddressBalance = "address_balance"
+	// WSEventWalletBalance the balance of a subscribed wallet changed
+	WSEventWalletBalance = "wallet_balance"
+	// WSEventSubscribed the subscriptions changed, sent in response to a subscription request
+	WSEventSubscribed = "subscribed"
+	// WSEventError a subscription request failed
+	WSEventError = "error"
+)
+
+// Types of the subscription requests sent to /api/v2/ws
+const (
+	// WSRequestSubscribe adds addresses and wallets to the subscriptions
+	WSRequestSubscribe = "subscribe"
+	// WSRequestUnsubscribe removes addresses and wallets from the subscriptions
+	WSRequestUnsubscribe = "unsubscribe"
+)
+
+const (
+	// wsPingInterval is the interval of the pings that keep the connection alive
+	wsPingInterval = 30 * time.Second
+	// wsWriteTimeout is the time allowed to write a message
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WSEvent is a message streamed by /api/v2/ws
+type WSEvent struct {
+	Type        string                                 `json:"type"`
+	Block       *readable.Block                        `json:"block,omitempty"`
+	Transaction *readable.TransactionWithStatusVerbose `json:"transaction,omitempty"`
+	Address     string                                 `json:"address,omitempty"`
+	WalletID    string                                 `json:"wallet_id,omitempty"`
+	Balance     *readable.BalancePair                  `json:"balance,omitempty"`
+	Addresses   []string                               `json:"addresses,omitempty"`
+	Wallets     []string                               `json:"wallets,omitempty"`
+	Watch       bool                                   `json:"watch,omitempty"`
+	Error       string                                 `json:"error,omitempty"`
+}
+
+// WSRequest is a subscription request sent to /api/v2/ws
+type WSRequest struct {
+	Type      string   `json:"type"`
+	Addresses []string `json:"addresses"`
+	Wallets   []string `json:"wallets"`
+	// Watch subscribes to or unsubscribes from the addresses of the watch list of the node
+	Watch bool `json:"watch"`
+}
+
+// wsSession streams the events of a websocket connection or of an /api/v2/events stream, filtered by its subscriptions
+type wsSession struct {
+	// ctx is the context of the request of the session, done when the client disconnects
+	ctx              context.Context
+	gateway          Gatewayer
+	conn             *websocket.Conn
+	walletAPIEnabled bool
+	// send writes an event to the client
+	send func(WSEvent) error
+
+	addrs   map[cipher.Address]struct{}
+	wallets map[string]struct{}
+	// watch subscribes to the addresses of the watch list of the node
+	watch bool
+
+	// addrBalances and walletBalances are the last balances sent, balance events are only sent when they change
+	addrBalances   map[cipher.Address]wallet.BalancePair
+	walletBalances map[string]wallet.BalancePair
+}
+
+// Streams blockchain events over a websocket
+// URI: /api/v2/ws
+// Method: GET
+// Args:
+//	addrs: comma-separated list of addresses to subscribe to [optional]
+//	wallets: comma-separated list of wallet IDs to subscribe to [optional]
+//	watch: [bool] subscribe to the addresses of the watch list of the node [optional]
+// Without address subscriptions, all transactions are streamed. With address subscriptions,
+// only the transactions with an input or output of a subscribed address are streamed,
+// along with the balance changes of the addresses. The watched addresses are subscribed
+// while they are in the watch list. Wallet subscriptions stream the balance
+// changes of the wallets, and require the wallet API to be enabled.
+// The subscriptions can be changed by sending WSRequest messages.
+func webSocketHandler(gateway Gatewayer, walletAPIEnabled bool, quit <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		s, resp := newWSSession(r, gateway, walletAPIEnabled)
+		if s == nil {
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+		defer conn.Close() //nolint:errcheck
+
+		events, unsubscribe := gateway.SubscribeBlockchain(0)
+		defer unsubscribe()
+
+		s.conn = conn
+		s.send = s.writeWS
+		if err := s.run(events, quit); err != nil {
+			logger.WithError(err).WithField("remoteAddr", conn.RemoteAddr()).Debug("Websocket connection closed")
+		}
+	}
+}
+
+// newWSSession creates a wsSession subscribed to the addrs, wallets and watch parameters of the request.
+// Returns nil and the error response if the parameters are invalid.
+func newWSSession(r *http.Request, gateway Gatewayer, walletAPIEnabled bool) (*wsSession, HTTPResponse) {
+	s := &wsSession{
+		ctx:              r.Context(),
+		gateway:          gateway,
+		walletAPIEnabled: walletAPIEnabled && tokenAllows(r, http.MethodGet, []string{EndpointsWallet}),
+		addrs:            make(map[cipher.Address]struct{}),
+		wallets:          make(map[string]struct{}),
+		addrBalances:     make(map[cipher.Address]wallet.BalancePair),
+		walletBalances:   make(map[string]wallet.BalancePair),
+	}
+
+	watch, err := parseBoolFlag(r.FormValue("watch"))
+	if err != nil {
+		return nil, NewHTTPErrorResponse(http.StatusBadRequest, "invalid value for watch")
+	}
+
+	if err := s.subscribe(splitCommaString(r.FormValue("addrs")), splitCommaString(r.FormValue("wallets")), watch); err != nil {
+		return nil, wsErrorResponse(err)
+	}
+
+	return s, HTTPResponse{}
+}
+
+// wsErrorResponse maps a subscription error to an HTTP error response
+func wsErrorResponse(err error) HTTPResponse {
+	switch err {
+	case wallet.ErrWalletNotExist:
+		return NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+	case wallet.ErrWalletAPIDisabled:
+		return NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+	}
+
+	if _, ok := err.(wallet.Error); ok {
+		return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+	}
+	if _, ok := err.(wsRequestError); ok {
+		return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	return NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+}
+
+// wsRequestError is an invalid subscription request
+type wsRequestError struct {
+	error
+}
+
+// subscribe adds the addresses and wallets to the subscriptions, all of them are validated before any is added.
+// If watch is true, the watch list of the node is subscribed to.
+func (s *wsSession) subscribe(addrsStr, wallets []string, watch bool) error {
+	addrs := make([]cipher.Address, len(addrsStr))
+	for i, a := range addrsStr {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			return wsRequestError{fmt.Errorf("address %q is invalid: %v", a, err)}
+		}
+		addrs[i] = addr
+	}
+
+	if len(wallets) != 0 && !s.walletAPIEnabled {
+		return wallet.ErrWalletAPIDisabled
+	}
+
+	for _, id := range wallets {
+		if _, err := s.gateway.GetWallet(id); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range addrs {
+		s.addrs[a] = struct{}{}
+	}
+	for _, id := range wallets {
+		s.wallets[id] = struct{}{}
+	}
+	if watch {
+		s.watch = true
+	}
+
+	return nil
+}
+
+// unsubscribe removes the addresses and wallets from the subscriptions.
+// If watch is true, the watch list of the node is unsubscribed from.
+func (s *wsSession) unsubscribe(addrsStr, wallets []string, watch bool) error {
+	addrs := make([]cipher.Address, len(addrsStr))
+	for i, a := range addrsStr {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			return wsRequestError{fmt.Errorf("address %q is invalid: %v", a, err)}
+		}
+		addrs[i] = addr
+	}
+
+	for _, a := range addrs {
+		delete(s.addrs, a)
+		delete(s.addrBalances, a)
+	}
+	for _, id := range wallets {
+		delete(s.wallets, id)
+		delete(s.walletBalances, id)
+	}
+	if watch {
+		s.watch = false
+		for a := range s.addrBalances {
+			if _, ok := s.addrs[a]; !ok {
+				delete(s.addrBalances, a)
+			}
+		}
+	}
+
+	return nil
+}
+
+// run streams the events until the connection fails, the client closes it or quit is closed
+func (s *wsSession) run(events <-chan visor.Event, quit <-chan struct{}) error {
+	requests := make(chan WSRequest)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(requests)
+		for {
+			_, data, err := s.conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			var req WSRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				req = WSRequest{}
+			}
+
+			select {
+			case requests <- req:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := s.handleEvent(e); err != nil {
+				return err
+			}
+		case req, ok := <-requests:
+			if !ok {
+				return <-readErr
+			}
+			if err := s.handleRequest(req); err != nil {
+				return err
+			}
+		case <-ping.C:
+			if err := s.conn.SetWriteDeadline(time.Now().Add(wsPingInterval)); err != nil {
+				return err
+			}
+			if err := s.conn.Ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *wsSession) write(e WSEvent) error {
+	return s.send(e)
+}
+
+func (s *wsSession) writeWS(e WSEvent) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(e)
+}
+
+func (s *wsSession) handleRequest(req WSRequest) error {
+	var err error
+	switch req.Type {
+	case WSRequestSubscribe:
+		err = s.subscribe(req.Addresses, req.Wallets, req.Watch)
+	case WSRequestUnsubscribe:
+		err = s.unsubscribe(req.Addresses, req.Wallets, req.Watch)
+	default:
+		err = wsRequestError{fmt.Errorf("invalid request type %q, must be %s or %s", req.Type, WSRequestSubscribe, WSRequestUnsubscribe)}
+	}
+
+	if err != nil {
+		return s.write(WSEvent{
+			Type:  WSEventError,
+			Error: err.Error(),
+		})
+	}
+
+	e := WSEvent{
+		Type:  WSEventSubscribed,
+		Watch: s.watch,
+	}
+	for a := range s.addrs {
+		e.Addresses = append(e.Addresses, a.String())
+	}
+	for id := range s.wallets {
+		e.Wallets = append(e.Wallets, id)
+	}
+	sort.Strings(e.Addresses)
+	sort.Strings(e.Wallets)
+
+	return s.write(e)
+}
+
+func (s *wsSession) handleEvent(e visor.Event) error {
+	touched := make(map[cipher.Address]struct{})
+
+	switch e.Type {
+	case visor.EventBlockExecuted:
+		b, err := readable.NewBlock(e.Block.Block)
+		if err != nil {
+			return err
+		}
+
+		if err := s.write(WSEvent{
+			Type:  WSEventBlock,
+			Block: b,
+		}); err != nil {
+			return err
+		}
+
+		for _, txn := range e.Block.Block.Body.Transactions {
+			if err := s.writeTransaction(WSEventTransaction, txn, touched); err != nil {
+				return err
+			}
+		}
+
+	case visor.EventUnconfirmedTxnInjected:
+		if err := s.writeTransaction(WSEventUnconfirmedTransaction, *e.Transaction, touched); err != nil {
+			return err
+		}
+
+	default:
+		return nil
+	}
+
+	if err := s.writeAddressBalances(touched); err != nil {
+		return err
+	}
+
+	return s.writeWalletBalances()
+}
+
+// writeTransaction writes the transaction event if the transaction has an input or output of a subscribed address,
+// or if there are no address subscriptions. The subscribed addresses of the transaction are added to touched.
+func (s *wsSession) writeTransaction(eventType string, txn coin.Transaction, touched map[cipher.Address]struct{}) error {
+	vtxn, inputs, err := s.gateway.GetTransactionWithInputs(txn.Hash())
+	if err != nil {
+		return err
+	}
+	if vtxn == nil {
+		// The transaction was removed from the unconfirmed pool in the meantime
+		return nil
+	}
+
+	match := len(s.addrs) == 0 && !s.watch
+	for _, in := range inputs {
+		if s.isSubscribed(in.UxOut.Body.Address) {
+			touched[in.UxOut.Body.Address] = struct{}{}
+			match = true
+		}
+	}
+	for _, o := range txn.Out {
+		if s.isSubscribed(o.Address) {
+			touched[o.Address] = struct{}{}
+			match = true
+		}
+	}
+
+	if !match {
+		return nil
+	}
+
+	rtxn, err := readable.NewTransactionWithStatusVerbose(vtxn, inputs)
+	if err != nil {
+		return err
+	}
+
+	return s.write(WSEvent{
+		Type:        eventType,
+		Transaction: rtxn,
+	})
+}
+
+// isSubscribed returns true if the address is subscribed, or is watched while the watch list is subscribed
+func (s *wsSession) isSubscribed(addr cipher.Address) bool {
+	if _, ok := s.addrs[addr]; ok {
+		return true
+	}
+	return s.watch && s.gateway.IsWatchedAddress(addr)
+}
+
+// writeAddressBalances writes the balances of the addresses that changed since they were last written
+func (s *wsSession) writeAddressBalances(touched map[cipher.Address]struct{}) error {
+	if len(touched) == 0 {
+		return nil
+	}
+
+	addrs := make([]cipher.Address, 0, len(touched))
+	for a := range touched {
+		addrs = append(addrs, a)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+
+	balances, err := s.gateway.GetBalanceOfAddresses(addrs)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range addrs {
+		if last, ok := s.addrBalances[a]; ok && last == balances[i] {
+			continue
+		}
+		s.addrBalances[a] = balances[i]
+
+		b := readable.NewBalancePair(balances[i])
+		if err := s.write(WSEvent{
+			Type:    WSEventAddressBalance,
+			Address: a.String(),
+			Balance: &b,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeWalletBalances writes the balances of the subscribed wallets that changed since they were last written
+func (s *wsSession) writeWalletBalances() error {
+	ids := make([]string, 0, len(s.wallets))
+	for id := range s.wallets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		balance, _, err := s.gateway.GetWalletBalance(s.ctx, id)
+		if err != nil {
+			// The wallet may have been unloaded
+			logger.WithError(err).WithField("walletID", id).Warning("Websocket GetWalletBalance failed")
+			continue
+		}
+
+		if last, ok := s.walletBalances[id]; ok && last == balance {
+			continue
+		}
+		s.walletBalances[id] = balance
+
+		b := readable.NewBalancePair(balance)
+		if err := s.write(WSEvent{
+			Type:     WSEventWalletBalance,
+			WalletID: id,
+			Balance:  &b,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/websocket"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWebSocketHandlerErrors(t *testing.T) {
+	addr := makeAddress().String()
+
+	tt := []struct {
+		name             string
+		query            string
+		walletAPIEnabled bool
+		getWalletErr     error
+		status           int
+		err              string
+	}{
+		{
+			name:   "400 invalid address",
+			query:  "?addrs=foo",
+			status: http.StatusBadRequest,
+			err:    "address \"foo\" is invalid: Invalid address length",
+		},
+		{
+			name:   "403 wallet API disabled",
+			query:  "?wallets=foo.wlt",
+			status: http.StatusForbidden,
+			err:    "wallet api is disabled",
+		},
+		{
+			name:             "404 wallet not found",
+			query:            "?wallets=foo.wlt",
+			walletAPIEnabled: true,
+			getWalletErr:     wallet.ErrWalletNotExist,
+			status:           http.StatusNotFound,
+			err:              "wallet doesn't exist",
+		},
+		{
+			name:   "400 not a websocket request",
+			query:  "?addrs=" + addr,
+			status: http.StatusBadRequest,
+			err:    websocket.ErrNotWebSocket.Error(),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetWallet", "foo.wlt").Return(nil, tc.getWalletErr)
+
+			cfg := defaultMuxConfig()
+			if !tc.walletAPIEnabled {
+				cfg.enabledAPISets = map[string]struct{}{
+					EndpointsRead: struct{}{},
+				}
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v2/ws"+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(cfg, gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code)
+
+			var resp ReceivedHTTPResponse
+			err = json.NewDecoder(rr.Body).Decode(&resp)
+			require.NoError(t, err)
+			require.NotNil(t, resp.Error)
+			require.Equal(t, tc.err, resp.Error.Message)
+		})
+	}
+}
+
+func readWSEvent(t *testing.T, conn *websocket.Conn) WSEvent {
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var e WSEvent
+	err = json.Unmarshal(data, &e)
+	require.NoError(t, err)
+	return e
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	subscribed := prepareTxnAndInputs(t)
+	other := prepareTxnAndInputs(t)
+	addr := subscribed.txn.Out[0].Address
+
+	gateway := &MockGatewayer{}
+
+	events := make(chan visor.Event, 8)
+	unsubscribed := make(chan struct{})
+	gateway.On("SubscribeBlockchain", 0).Return((<-chan visor.Event)(events), func() {
+		close(unsubscribed)
+	})
+
+	for _, x := range []transactionAndInputs{subscribed, other} {
+		gateway.On("GetTransactionWithInputs", x.txn.Hash()).Return(&visor.Transaction{
+			Transaction: x.txn,
+		}, x.inputs, nil)
+	}
+
+	balance := wallet.BalancePair{
+		Confirmed: wallet.Balance{Coins: 1e6, Hours: 50},
+		Predicted: wallet.Balance{Coins: 2e6, Hours: 100},
+	}
+	gateway.On("GetBalanceOfAddresses", []cipher.Address{addr}).Return([]wallet.BalancePair{balance}, nil)
+	gateway.On("GetWallet", "foo.wlt").Return(nil, nil)
+	gateway.On("GetWalletBalance", mock.Anything, "foo.wlt").Return(balance, wallet.AddressBalances{}, nil)
+
+	cfg := defaultMuxConfig()
+	cfg.disableHeaderCheck = true
+	server := httptest.NewServer(newServerMux(cfg, gateway))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v2/ws?addrs=" + addr.String()
+	conn, err := websocket.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	// Only the transaction of the subscribed address is streamed, with the balance of the address
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &other.txn,
+	}
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &subscribed.txn,
+	}
+
+	e := readWSEvent(t, conn)
+	require.Equal(t, WSEventUnconfirmedTransaction, e.Type)
+	require.NotNil(t, e.Transaction)
+	require.Equal(t, subscribed.txn.Hash().Hex(), e.Transaction.Transaction.Hash)
+
+	rb := readable.NewBalancePair(balance)
+	require.Equal(t, WSEvent{
+		Type:    WSEventAddressBalance,
+		Address: addr.String(),
+		Balance: &rb,
+	}, readWSEvent(t, conn))
+
+	// Subscribe to a wallet, its balance is sent on the next event.
+	// The balance of the address did not change and is not sent again.
+	err = conn.WriteJSON(WSRequest{
+		Type:    WSRequestSubscribe,
+		Wallets: []string{"foo.wlt"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, WSEvent{
+		Type:      WSEventSubscribed,
+		Addresses: []string{addr.String()},
+		Wallets:   []string{"foo.wlt"},
+	}, readWSEvent(t, conn))
+
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &subscribed.txn,
+	}
+
+	e = readWSEvent(t, conn)
+	require.Equal(t, WSEventUnconfirmedTransaction, e.Type)
+	require.Equal(t, WSEvent{
+		Type:     WSEventWalletBalance,
+		WalletID: "foo.wlt",
+		Balance:  &rb,
+	}, readWSEvent(t, conn))
+
+	// Invalid requests are answered with an error event
+	err = conn.WriteJSON(WSRequest{
+		Type: "foo",
+	})
+	require.NoError(t, err)
+	require.Equal(t, WSEvent{
+		Type:  WSEventError,
+		Error: "invalid request type \"foo\", must be subscribe or unsubscribe",
+	}, readWSEvent(t, conn))
+
+	err = conn.WriteJSON(WSRequest{
+		Type:      WSRequestUnsubscribe,
+		Addresses: []string{addr.String()},
+		Wallets:   []string{"foo.wlt"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, WSEvent{
+		Type: WSEventSubscribed,
+	}, readWSEvent(t, conn))
+
+	// The subscription to the blockchain events ends with the connection
+	err = conn.Close()
+	require.NoError(t, err)
+	<-unsubscribed
+}
+
+func TestWebSocketHandlerWatchList(t *testing.T) {
+	watched := prepareTxnAndInputs(t)
+	other := prepareTxnAndInputs(t)
+	addr := watched.txn.Out[0].Address
+
+	gateway := &MockGatewayer{}
+
+	events := make(chan visor.Event, 8)
+	gateway.On("SubscribeBlockchain", 0).Return((<-chan visor.Event)(events), func() {})
+
+	for _, x := range []transactionAndInputs{watched, other} {
+		gateway.On("GetTransactionWithInputs", x.txn.Hash()).Return(&visor.Transaction{
+			Transaction: x.txn,
+		}, x.inputs, nil)
+	}
+
+	gateway.On("IsWatchedAddress", mock.Anything).Return(func(a cipher.Address) bool {
+		return a == addr
+	})
+
+	balance := wallet.BalancePair{
+		Confirmed: wallet.Balance{Coins: 1e6, Hours: 50},
+		Predicted: wallet.Balance{Coins: 2e6, Hours: 100},
+	}
+	gateway.On("GetBalanceOfAddresses", []cipher.Address{addr}).Return([]wallet.BalancePair{balance}, nil)
+
+	cfg := defaultMuxConfig()
+	cfg.disableHeaderCheck = true
+	server := httptest.NewServer(newServerMux(cfg, gateway))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v2/ws?watch=1"
+	conn, err := websocket.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	// Only the transaction of the watched address is streamed, with the balance of the address
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &other.txn,
+	}
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &watched.txn,
+	}
+
+	e := readWSEvent(t, conn)
+	require.Equal(t, WSEventUnconfirmedTransaction, e.Type)
+	require.NotNil(t, e.Transaction)
+	require.Equal(t, watched.txn.Hash().Hex(), e.Transaction.Transaction.Hash)
+
+	rb := readable.NewBalancePair(balance)
+	require.Equal(t, WSEvent{
+		Type:    WSEventAddressBalance,
+		Address: addr.String(),
+		Balance: &rb,
+	}, readWSEvent(t, conn))
+
+	err = conn.WriteJSON(WSRequest{
+		Type:  WSRequestUnsubscribe,
+		Watch: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, WSEvent{
+		Type: WSEventSubscribed,
+	}, readWSEvent(t, conn))
+
+	err = conn.WriteJSON(WSRequest{
+		Type:  WSRequestSubscribe,
+		Watch: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, WSEvent{
+		Type:  WSEventSubscribed,
+		Watch: true,
+	}, readWSEvent(t, conn))
+}
@@ -5,13 +5,14 @@ import (
 	"log"
 
 	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
 )
 
 var (
 	// ErrAddressInvalidLength Unexpected size of address bytes buffer
 	ErrAddressInvalidLength = errors.New("Invalid address length")
 	// ErrAddressInvalidChecksum Computed checksum did not match expected value
-	ErrAddressInvalidChecksum = errors.New("Invalid checksum")
+	ErrAddressInvalidChecksum = base58check.ErrInvalidChecksum
 	// ErrAddressInvalidVersion Unsupported address version value
 	ErrAddressInvalidVersion = errors.New("Address version invalid")
 	// ErrAddressInvalidPubKey Public key invalid for address
@@ -88,13 +89,28 @@ func MustAddressFromSecKey(secKey SecKey) Address {
 	return AddressFromPubKey(MustPubKeyFromSecKey(secKey))
 }
 
-// DecodeBase58Address creates an Address from its base58 encoding
+// DecodeBase58Address creates an Address from its base58 encoding.
+// If the address has an invalid character or checksum, and a single character substitution
+// would make it valid, the error is a base58check.TypoError that suggests the corrected address.
 func DecodeBase58Address(addr string) (Address, error) {
 	b, err := base58.Decode(addr)
-	if err != nil {
+	if err == nil {
+		var a Address
+		a, err = AddressFromBytes(b)
+		if err == nil {
+			return a, nil
+		}
+	}
+
+	switch err {
+	case base58.ErrInvalidChar, ErrAddressInvalidChecksum:
+		return Address{}, base58check.Hint(addr, err, func(b []byte) bool {
+			_, err := AddressFromBytes(b)
+			return err == nil
+		})
+	default:
 		return Address{}, err
 	}
-	return AddressFromBytes(b)
 }
 
 // MustDecodeBase58Address creates an Address from its base58 encoding, panics on error
@@ -176,8 +192,5 @@ func (addr Address) String() string {
 // Checksum returns Address Checksum which is the first 4 bytes of sha256(key+version)
 func (addr Address) Checksum() Checksum {
 	r1 := append(addr.Key[:], []byte{addr.Version}...)
-	r2 := SumSHA256(r1[:])
-	c := Checksum{}
-	copy(c[:], r2[:len(c)])
-	return c
+	return base58check.SHA256Checksum(r1)
 }
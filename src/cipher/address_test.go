@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
 )
 
 func TestMustDecodeBase58Address(t *testing.T) {
@@ -101,6 +102,33 @@ func TestDecodeBase58Address(t *testing.T) {
 	require.Equal(t, ErrAddressInvalidChecksum, err)
 }
 
+func TestDecodeBase58AddressTypo(t *testing.T) {
+	p, _ := GenerateKeyPair()
+	as := AddressFromPubKey(p).String()
+
+	// Replace a character in the middle of the address
+	i := len(as) / 2
+	c := byte('2')
+	if as[i] == c {
+		c = '3'
+	}
+	typo := as[:i] + string(c) + as[i+1:]
+
+	_, err := DecodeBase58Address(typo)
+	typoErr, ok := err.(base58check.TypoError)
+	require.True(t, ok)
+	require.Equal(t, ErrAddressInvalidChecksum, typoErr.Err)
+	require.Equal(t, []string{as}, typoErr.Corrections)
+
+	// Invalid base58 character
+	typo = as[:i] + "0" + as[i+1:]
+	_, err = DecodeBase58Address(typo)
+	typoErr, ok = err.(base58check.TypoError)
+	require.True(t, ok)
+	require.Equal(t, base58.ErrInvalidChar, typoErr.Err)
+	require.Equal(t, []string{as}, typoErr.Corrections)
+}
+
 func TestAddressFromBytes(t *testing.T) {
 	p, _ := GenerateKeyPair()
 	a := AddressFromPubKey(p)
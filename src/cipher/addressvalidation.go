@@ -0,0 +1,130 @@
+package cipher
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/bech32"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
+)
+
+// AddressInvalidReason is the reason why a string is not a valid skycoin address
+type AddressInvalidReason string
+
+const (
+	// AddressInvalidCharacter the address has a character that is not in the base58 alphabet, or is empty
+	AddressInvalidCharacter AddressInvalidReason = "invalid_character"
+	// AddressInvalidLength the address does not decode to 25 bytes
+	AddressInvalidLength AddressInvalidReason = "invalid_length"
+	// AddressInvalidChecksum the address checksum does not match
+	AddressInvalidChecksum AddressInvalidReason = "invalid_checksum"
+	// AddressInvalidVersion the address checksum matches, but the version byte is not a skycoin address version
+	AddressInvalidVersion AddressInvalidReason = "invalid_version"
+	// AddressWrongCoin the address is a valid address of another coin, e.g. a bitcoin address
+	AddressWrongCoin AddressInvalidReason = "wrong_coin"
+)
+
+// AddressValidation is the detailed result of validating a skycoin address with ValidateAddress
+type AddressValidation struct {
+	Address string
+	Valid   bool
+	// Reason is why the address is invalid, empty if it is valid
+	Reason AddressInvalidReason
+	// Version is the version byte of a valid address, or of an address with an invalid version
+	Version byte
+	// Coin is the coin of the address if it is the address of another coin, e.g. "bitcoin"
+	Coin string
+	// Suggestions are the valid addresses that differ in one character from a mistyped address
+	Suggestions []string
+	// Err is the error of DecodeBase58Address
+	Err error
+}
+
+// ValidateAddress validates a base58 skycoin address and reports why an invalid address is invalid,
+// e.g. so users pasting a bitcoin address into a skycoin send get an actionable message.
+func ValidateAddress(addr string) AddressValidation {
+	v := AddressValidation{
+		Address: addr,
+	}
+
+	a, err := DecodeBase58Address(addr)
+	if err == nil {
+		v.Valid = true
+		v.Version = a.Version
+		return v
+	}
+	v.Err = err
+
+	if coin := otherCoinAddress(addr); coin != "" {
+		v.Reason = AddressWrongCoin
+		v.Coin = coin
+		return v
+	}
+
+	if te, ok := err.(base58check.TypoError); ok {
+		v.Suggestions = te.Corrections
+		err = te.Err
+	}
+
+	switch err {
+	case ErrAddressInvalidLength:
+		v.Reason = AddressInvalidLength
+	case ErrAddressInvalidChecksum:
+		v.Reason = AddressInvalidChecksum
+	case ErrAddressInvalidVersion:
+		v.Reason = AddressInvalidVersion
+		if b, err := base58.Decode(addr); err == nil {
+			v.Version = b[20]
+		}
+	default:
+		v.Reason = AddressInvalidCharacter
+	}
+
+	return v
+}
+
+// Message returns a message for users, explaining why the address is invalid
+func (v AddressValidation) Message() string {
+	switch v.Reason {
+	case "":
+		return "Valid address"
+	case AddressWrongCoin:
+		return fmt.Sprintf("This is a %s address, not a Skycoin address", v.Coin)
+	case AddressInvalidVersion:
+		return fmt.Sprintf("Address version %d is invalid, this is not a Skycoin address", v.Version)
+	case AddressInvalidLength:
+		return "Invalid address length, the address may be incomplete or have extra characters"
+	default:
+		return v.Err.Error()
+	}
+}
+
+// otherCoinAddress returns the coin of addr if it is a valid address of another coin
+func otherCoinAddress(addr string) string {
+	if b, err := base58.Decode(addr); err == nil && len(b) == 25 {
+		switch b[0] {
+		// P2PKH and P2SH, mainnet and testnet
+		case 0x00, 0x05, 0x6f, 0xc4:
+			if c := base58check.DoubleSHA256Checksum(b[:21]); bytes.Equal(c[:], b[21:]) {
+				return "bitcoin"
+			}
+		}
+	}
+
+	lower := strings.ToLower(addr)
+	for _, hrp := range []string{BitcoinBech32HRP, "tb"} {
+		if strings.HasPrefix(lower, hrp+"1") {
+			if _, _, err := bech32.DecodeSegwitAddress(hrp, addr); err == nil {
+				return "bitcoin"
+			}
+		}
+	}
+
+	if _, err := DecodeHexEthereumAddress(addr); err == nil {
+		return "ethereum"
+	}
+
+	return ""
+}
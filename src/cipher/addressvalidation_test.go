@@ -0,0 +1,113 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAddress(t *testing.T) {
+	versionAddr := Address{
+		Version: 1,
+		Key:     MustDecodeBase58Address("7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD").Key,
+	}.String()
+
+	cases := []struct {
+		name        string
+		addr        string
+		reason      AddressInvalidReason
+		version     byte
+		coin        string
+		suggestions []string
+		message     string
+	}{
+		{
+			name:    "valid",
+			addr:    "7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
+			message: "Valid address",
+		},
+		{
+			name:        "mistyped character",
+			addr:        "7apQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD",
+			reason:      AddressInvalidChecksum,
+			suggestions: []string{"7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD"},
+			message:     "Invalid checksum, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?",
+		},
+		{
+			name:    "invalid checksum",
+			addr:    "7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBDP",
+			reason:  AddressInvalidChecksum,
+			message: "Invalid checksum",
+		},
+		{
+			name:    "invalid length",
+			addr:    "7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8f",
+			reason:  AddressInvalidLength,
+			message: "Invalid address length, the address may be incomplete or have extra characters",
+		},
+		{
+			name:        "invalid character",
+			addr:        "7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBP0",
+			reason:      AddressInvalidCharacter,
+			suggestions: []string{"7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD"},
+			message:     "Invalid base58 character, a character may be mistyped, did you mean 7cpQ7t3PZZXvjTst8G7Uvs7XH4LeM8fBPD?",
+		},
+		{
+			name:    "empty",
+			addr:    "",
+			reason:  AddressInvalidCharacter,
+			message: "Invalid base58 string",
+		},
+		{
+			name:    "invalid version",
+			addr:    versionAddr,
+			reason:  AddressInvalidVersion,
+			version: 1,
+			message: "Address version 1 is invalid, this is not a Skycoin address",
+		},
+		{
+			name:    "bitcoin",
+			addr:    "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			reason:  AddressWrongCoin,
+			coin:    "bitcoin",
+			message: "This is a bitcoin address, not a Skycoin address",
+		},
+		{
+			name:    "bitcoin p2sh",
+			addr:    "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+			reason:  AddressWrongCoin,
+			coin:    "bitcoin",
+			message: "This is a bitcoin address, not a Skycoin address",
+		},
+		{
+			name:    "bitcoin bech32",
+			addr:    "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			reason:  AddressWrongCoin,
+			coin:    "bitcoin",
+			message: "This is a bitcoin address, not a Skycoin address",
+		},
+		{
+			name:    "ethereum",
+			addr:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			reason:  AddressWrongCoin,
+			coin:    "ethereum",
+			message: "This is a ethereum address, not a Skycoin address",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := ValidateAddress(tc.addr)
+			require.Equal(t, tc.addr, v.Address)
+			require.Equal(t, tc.reason == "", v.Valid)
+			require.Equal(t, tc.reason, v.Reason)
+			require.Equal(t, tc.version, v.Version)
+			require.Equal(t, tc.coin, v.Coin)
+			require.Equal(t, tc.suggestions, v.Suggestions)
+			require.Equal(t, tc.message, v.Message())
+
+			_, err := DecodeBase58Address(tc.addr)
+			require.Equal(t, err, v.Err)
+		})
+	}
+}
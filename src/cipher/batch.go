@@ -0,0 +1,93 @@
+package cipher
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrSignatureBatchLengthMismatch The number of pubkeys, signatures and hashes of a batch do not match
+	ErrSignatureBatchLengthMismatch = errors.New("Signature batch pubkeys, signatures and hashes lengths do not match")
+)
+
+// minBatchPerWorker is the smallest number of signatures verified by one worker goroutine.
+// Smaller batches are verified on the calling goroutine.
+const minBatchPerWorker = 16
+
+// BatchVerifyError is returned by VerifySignaturesBatch when a signature of the batch is invalid
+type BatchVerifyError struct {
+	// Index of the first invalid signature in the batch
+	Index int
+	// Err is the error returned by VerifyPubKeySignedHash for the signature
+	Err error
+}
+
+func (e BatchVerifyError) Error() string {
+	return fmt.Sprintf("signature %d of batch is invalid: %v", e.Index, e.Err)
+}
+
+// VerifySignaturesBatch verifies that each hash was signed by the PubKey at the same index
+// with the Sig at the same index, as VerifyPubKeySignedHash does.
+// ECDSA signatures have no algebraic batch verification, so the signatures are split
+// among worker goroutines, one per CPU, and verified in parallel.
+// If any signature is invalid, a BatchVerifyError for the lowest invalid index is returned.
+func VerifySignaturesBatch(pubkeys []PubKey, sigs []Sig, hashes []SHA256) error {
+	if len(pubkeys) != len(sigs) || len(pubkeys) != len(hashes) {
+		return ErrSignatureBatchLengthMismatch
+	}
+
+	n := len(sigs)
+	workers := runtime.NumCPU()
+	if max := n / minBatchPerWorker; max < workers {
+		workers = max
+	}
+
+	if workers <= 1 {
+		return verifySignaturesRange(pubkeys, sigs, hashes, 0, n)
+	}
+
+	errs := make([]error, workers)
+	chunk := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		start := i * chunk
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		if start >= end {
+			break
+		}
+
+		wg.Add(1)
+		go func(i, start, end int) {
+			defer wg.Done()
+			errs[i] = verifySignaturesRange(pubkeys, sigs, hashes, start, end)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	// The chunks are in index order, so the first error is for the lowest invalid index
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func verifySignaturesRange(pubkeys []PubKey, sigs []Sig, hashes []SHA256, start, end int) error {
+	for i := start; i < end; i++ {
+		if err := VerifyPubKeySignedHash(pubkeys[i], sigs[i], hashes[i]); err != nil {
+			return BatchVerifyError{
+				Index: i,
+				Err:   err,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignaturesBatch(t *testing.T) {
+	// Large enough to be split among several workers
+	n := minBatchPerWorker * 5
+	pubkeys := make([]PubKey, n)
+	sigs := make([]Sig, n)
+	hashes := make([]SHA256, n)
+	for i := 0; i < n; i++ {
+		pk, sk := GenerateKeyPair()
+		pubkeys[i] = pk
+		hashes[i] = SumSHA256(randBytes(t, 32))
+		sigs[i] = MustSignHash(hashes[i], sk)
+	}
+
+	require.NoError(t, VerifySignaturesBatch(pubkeys, sigs, hashes))
+	require.NoError(t, VerifySignaturesBatch(pubkeys[:1], sigs[:1], hashes[:1]))
+	require.NoError(t, VerifySignaturesBatch(nil, nil, nil))
+
+	require.Equal(t, ErrSignatureBatchLengthMismatch, VerifySignaturesBatch(pubkeys[:1], sigs, hashes))
+	require.Equal(t, ErrSignatureBatchLengthMismatch, VerifySignaturesBatch(pubkeys, sigs, hashes[:1]))
+
+	// The lowest invalid index is reported
+	badHashes := append([]SHA256{}, hashes...)
+	badHashes[n-1], badHashes[n-2] = badHashes[n-2], badHashes[n-1]
+	badHashes[20], badHashes[21] = badHashes[21], badHashes[20]
+	err := VerifySignaturesBatch(pubkeys, sigs, badHashes)
+	require.Equal(t, BatchVerifyError{
+		Index: 20,
+		Err:   ErrPubKeyRecoverMismatch,
+	}, err)
+
+	for _, i := range []int{0, n / 2, n - 1} {
+		badPubKeys := append([]PubKey{}, pubkeys...)
+		badPubKeys[i] = pubkeys[(i+1)%n]
+		err := VerifySignaturesBatch(badPubKeys, sigs, hashes)
+		require.Equal(t, BatchVerifyError{
+			Index: i,
+			Err:   ErrPubKeyRecoverMismatch,
+		}, err)
+	}
+
+	badSigs := append([]Sig{}, sigs...)
+	badSigs[3] = Sig{}
+	err = VerifySignaturesBatch(pubkeys, badSigs, hashes)
+	require.Equal(t, BatchVerifyError{
+		Index: 3,
+		Err:   ErrInvalidSigPubKeyRecovery,
+	}, err)
+}
@@ -0,0 +1,243 @@
+/*
+Package bech32 implements the bech32 encoding of BIP173 and the segwit address format built on it.
+
+https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+
+Only witness version 0 addresses are supported, the addresses of later witness versions
+use the bech32m checksum of BIP350.
+*/
+package bech32
+
+import (
+	"errors"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// maxLength is the maximum length of a bech32 string
+const maxLength = 90
+
+var (
+	// ErrInvalidLength the bech32 string is too short or too long
+	ErrInvalidLength = errors.New("Invalid bech32 string length")
+	// ErrInvalidChar the bech32 string has a character out of the printable US-ASCII range or not in the charset
+	ErrInvalidChar = errors.New("Invalid bech32 character")
+	// ErrMixedCase the bech32 string has both lower and upper case characters
+	ErrMixedCase = errors.New("Mixed case bech32 string")
+	// ErrInvalidSeparator the bech32 string has no separator, or the human readable part is empty
+	ErrInvalidSeparator = errors.New("Invalid bech32 separator position")
+	// ErrInvalidChecksum the bech32 checksum does not match
+	ErrInvalidChecksum = errors.New("Invalid bech32 checksum")
+	// ErrInvalidPadding the data has non-zero or excess padding bits
+	ErrInvalidPadding = errors.New("Invalid bech32 padding")
+	// ErrInvalidHRP the human readable part of the segwit address does not match
+	ErrInvalidHRP = errors.New("Invalid segwit address human readable part")
+	// ErrInvalidWitnessVersion the witness version of the segwit address is not supported
+	ErrInvalidWitnessVersion = errors.New("Unsupported segwit witness version")
+	// ErrInvalidWitnessProgram the witness program of the segwit address has an invalid length
+	ErrInvalidWitnessProgram = errors.New("Invalid segwit witness program length")
+)
+
+func polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	b := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]>>5)
+	}
+	b = append(b, 0)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]&31)
+	}
+	return b
+}
+
+// Checksum returns the 6 checksum values of the 5 bit data values with the lower case human readable part
+func Checksum(hrp string, data []byte) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := polymod(values) ^ 1
+	c := make([]byte, 6)
+	for i := range c {
+		c[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return c
+}
+
+func verifyChecksum(hrp string, data []byte) bool {
+	return polymod(append(hrpExpand(hrp), data...)) == 1
+}
+
+// Encode encodes the 5 bit data values with the human readable part as a lower case bech32 string
+func Encode(hrp string, data []byte) (string, error) {
+	if len(hrp)+len(data)+7 > maxLength {
+		return "", ErrInvalidLength
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", ErrInvalidChar
+		}
+	}
+	if hrp == "" {
+		return "", ErrInvalidSeparator
+	}
+
+	hrp = strings.ToLower(hrp)
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + 6)
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	values := append(append(make([]byte, 0, len(data)+6), data...), Checksum(hrp, data)...)
+	for _, d := range values {
+		if d > 31 {
+			return "", ErrInvalidChar
+		}
+		sb.WriteByte(charset[d])
+	}
+	return sb.String(), nil
+}
+
+// Decode decodes a bech32 string into its lower case human readable part and its 5 bit data values,
+// without the checksum
+func Decode(s string) (string, []byte, error) {
+	if len(s) < 8 || len(s) > maxLength {
+		return "", nil, ErrInvalidLength
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < 33 || s[i] > 126 {
+			return "", nil, ErrInvalidChar
+		}
+	}
+
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, ErrMixedCase
+	}
+	s = lower
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, ErrInvalidSeparator
+	}
+
+	hrp := s[:pos]
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		d := strings.IndexByte(charset, s[i])
+		if d == -1 {
+			return "", nil, ErrInvalidChar
+		}
+		data = append(data, byte(d))
+	}
+
+	if !verifyChecksum(hrp, data) {
+		return "", nil, ErrInvalidChecksum
+	}
+
+	return hrp, data[:len(data)-6], nil
+}
+
+// ConvertBits regroups the data values of fromBits bits into values of toBits bits.
+// If pad is false, the leftover bits must be zero padding of less than fromBits bits.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, ErrInvalidChar
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return out, nil
+}
+
+// EncodeSegwitAddress encodes the witness program as a segwit address with the human readable part,
+// e.g. "bc" for bitcoin mainnet
+func EncodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := verifyWitness(version, program); err != nil {
+		return "", err
+	}
+
+	data, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(hrp, append([]byte{version}, data...))
+}
+
+// DecodeSegwitAddress decodes the segwit address with the human readable part into its witness version and program
+func DecodeSegwitAddress(hrp, addr string) (byte, []byte, error) {
+	h, data, err := Decode(addr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if h != strings.ToLower(hrp) {
+		return 0, nil, ErrInvalidHRP
+	}
+
+	if len(data) == 0 {
+		return 0, nil, ErrInvalidWitnessProgram
+	}
+
+	if data[0] != 0 {
+		return 0, nil, ErrInvalidWitnessVersion
+	}
+
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := verifyWitness(data[0], program); err != nil {
+		return 0, nil, err
+	}
+
+	return data[0], program, nil
+}
+
+func verifyWitness(version byte, program []byte) error {
+	if version != 0 {
+		return ErrInvalidWitnessVersion
+	}
+
+	// Version 0 programs are the 20 byte P2WPKH pubkey hash or the 32 byte P2WSH script hash
+	if len(program) != 20 && len(program) != 32 {
+		return ErrInvalidWitnessProgram
+	}
+
+	return nil
+}
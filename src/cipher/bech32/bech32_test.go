@@ -0,0 +1,125 @@
+package bech32
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	// BIP173 test vectors
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+
+	for _, s := range valid {
+		t.Run(s, func(t *testing.T) {
+			hrp, data, err := Decode(s)
+			require.NoError(t, err)
+
+			e, err := Encode(hrp, data)
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(s), e)
+		})
+	}
+
+	invalid := []struct {
+		s   string
+		err error
+	}{
+		{"\x201nwldj5", ErrInvalidChar},
+		{"\x7f1axkwrx", ErrInvalidChar},
+		{"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", ErrInvalidLength},
+		{"pzry9x0s0muk", ErrInvalidSeparator},
+		{"1pzry9x0s0muk", ErrInvalidSeparator},
+		{"x1b4n0q5v", ErrInvalidChar},
+		{"li1dgmt3", ErrInvalidSeparator},
+		{"de1lg7wt\xff", ErrInvalidChar},
+		{"A1G7SGD8", ErrInvalidChecksum},
+		{"10a06t8", ErrInvalidLength},
+		{"1qzzfhee", ErrInvalidSeparator},
+		{"a12Uel5l", ErrMixedCase},
+	}
+
+	for _, tc := range invalid {
+		t.Run(tc.s, func(t *testing.T) {
+			_, _, err := Decode(tc.s)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestSegwitAddress(t *testing.T) {
+	// BIP173 witness version 0 test vectors
+	cases := []struct {
+		hrp    string
+		addr   string
+		script string
+	}{
+		{
+			hrp:    "bc",
+			addr:   "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			script: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			hrp:    "tb",
+			addr:   "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			script: "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+		},
+		{
+			hrp:    "tb",
+			addr:   "tb1qqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesrxh6hy",
+			script: "0020000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.addr, func(t *testing.T) {
+			version, program, err := DecodeSegwitAddress(tc.hrp, tc.addr)
+			require.NoError(t, err)
+			require.Equal(t, byte(0), version)
+
+			script, err := hex.DecodeString(tc.script)
+			require.NoError(t, err)
+			require.Equal(t, script[2:], program)
+
+			addr, err := EncodeSegwitAddress(tc.hrp, version, program)
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(tc.addr), addr)
+		})
+	}
+
+	invalid := []struct {
+		hrp  string
+		addr string
+		err  error
+	}{
+		{"bc", "tc1qw508d6qejxtdg4y5r3zarvary0c5xw7kg3g4ty", ErrInvalidHRP},
+		{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", ErrInvalidChecksum},
+		{"bc", "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", ErrInvalidWitnessProgram},
+		{"tb", "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7", ErrMixedCase},
+		{"bc", "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du", ErrInvalidWitnessVersion},
+		{"tb", "tb1pw508d6qejxtdg4y5r3zarqfsj6c3", ErrInvalidWitnessVersion},
+		{"bc", "bc1gmk9yu", ErrInvalidWitnessProgram},
+	}
+
+	for _, tc := range invalid {
+		t.Run(tc.addr, func(t *testing.T) {
+			_, _, err := DecodeSegwitAddress(tc.hrp, tc.addr)
+			require.Equal(t, tc.err, err)
+		})
+	}
+
+	_, err := EncodeSegwitAddress("bc", 1, make([]byte, 20))
+	require.Equal(t, ErrInvalidWitnessVersion, err)
+	_, err = EncodeSegwitAddress("bc", 0, make([]byte, 21))
+	require.Equal(t, ErrInvalidWitnessProgram, err)
+}
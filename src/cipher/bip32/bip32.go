@@ -126,6 +126,9 @@ var (
 
 	// ErrMaxDepthReached maximum allowed depth (255) reached for child key
 	ErrMaxDepthReached = NewError(errors.New("Maximum child depth reached"))
+
+	// ErrDeriveFromNonMasterKey is returned when deriving a full path starting with m/ from a key that is not a master key
+	ErrDeriveFromNonMasterKey = NewError(errors.New("Full path must be derived from a master key"))
 )
 
 // key represents a bip32 extended key
@@ -231,11 +234,47 @@ func NewPrivateKeyFromPath(seed []byte, p string) (*PrivateKey, error) {
 		return nil, err
 	}
 
-	if len(path.Elements) > 1 {
-		return k.DeriveSubpath(path.Elements[1:])
+	return DeriveFromPath(k, path)
+}
+
+// DeriveFromPath derives the private key at a full bip32 path from the master key,
+// e.g. the path parsed from m/44'/8000'/0'/0/1. Returns a copy of the master key for the path m.
+// This method can return an ImpossibleChild error.
+func DeriveFromPath(master *PrivateKey, path *Path) (*PrivateKey, error) {
+	if master.Depth != 0 {
+		return nil, ErrDeriveFromNonMasterKey
 	}
 
-	return k, nil
+	if len(path.Elements) == 0 || !path.Elements[0].Master {
+		return nil, ErrPathNoMaster
+	}
+
+	if len(path.Elements) == 1 {
+		k := master.Clone()
+		return &k, nil
+	}
+
+	return master.DeriveSubpath(path.Elements[1:])
+}
+
+// DerivePublicFromPath derives the public key at a full bip32 path from the master public key,
+// with public parent key to public child key derivation. The path can't have hardened nodes.
+// This method can return an ImpossibleChild error.
+func DerivePublicFromPath(master *PublicKey, path *Path) (*PublicKey, error) {
+	if master.Depth != 0 {
+		return nil, ErrDeriveFromNonMasterKey
+	}
+
+	if len(path.Elements) == 0 || !path.Elements[0].Master {
+		return nil, ErrPathNoMaster
+	}
+
+	if len(path.Elements) == 1 {
+		k := master.Clone()
+		return &k, nil
+	}
+
+	return master.DeriveSubpath(path.Elements[1:])
 }
 
 // DeriveSubpath derives a PrivateKey at at bip32 subpath, e.g. `0'/1'/0`.
@@ -269,6 +308,31 @@ func (k *PrivateKey) newPrivateChildKeyFromPathNode(n PathNode) (*PrivateKey, er
 	return k.NewPrivateChildKey(n.ChildNumber)
 }
 
+// DeriveSubpath derives a PublicKey at a bip32 subpath, e.g. `0/1`.
+// Hardened nodes can't be derived from a public key.
+// The nodes argument must not be empty.
+// This method can return an ImpossibleChild error.
+func (k *PublicKey) DeriveSubpath(nodes []PathNode) (*PublicKey, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("Path nodes array empty when deriving a bip32 subpath")
+	}
+
+	ck := k
+	for _, n := range nodes {
+		if n.Master {
+			return nil, errors.New("PathNode is Master at a non-zero depth")
+		}
+
+		var err error
+		ck, err = ck.NewPublicChildKey(n.ChildNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ck, nil
+}
+
 // PublicKey returns the public version of key or return a copy
 // The 'Neuter' function from the bip32 spec, N((k, c) -> (K, c).
 // https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#private-parent-key--public-child-key
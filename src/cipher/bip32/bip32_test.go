@@ -315,6 +315,8 @@ func testVectorKeyPairs(t *testing.T, vector testMasterKey) {
 	require.NoError(t, err)
 	require.Equal(t, privKey2, privKey3)
 
+	masterKey := privKey
+
 	// Iterate over the entire child chain and test the given keys
 	for _, testChildKey := range vector.children {
 		t.Run(testChildKey.path, func(t *testing.T) {
@@ -333,6 +335,26 @@ func testVectorKeyPairs(t *testing.T, vector testMasterKey) {
 
 			require.Equal(t, xx, privKey)
 
+			// Derive the same key from the master key
+			path, err := ParsePath(testChildKey.path)
+			require.NoError(t, err)
+			privKey2, err := DeriveFromPath(masterKey, path)
+			require.NoError(t, err)
+			require.Equal(t, privKey, privKey2)
+
+			// Derive the same public key from the master public key, if the path has no hardened nodes
+			hardened := false
+			for _, n := range path.Elements {
+				hardened = hardened || n.Hardened()
+			}
+			pubKey2, err := DerivePublicFromPath(masterKey.PublicKey(), path)
+			if hardened {
+				require.Equal(t, ErrHardenedChildPublicKey, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, pubKey, pubKey2)
+			}
+
 			// Assert correctness
 			require.Equal(t, testChildKey.privKey, privKey.String())
 			require.Equal(t, testChildKey.pubKey, pubKey.String())
@@ -359,6 +381,159 @@ func testVectorKeyPairs(t *testing.T, vector testMasterKey) {
 	}
 }
 
+func TestBip32TestVector5(t *testing.T) {
+	// vector5 test cases from:
+	// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-5
+	tests := []struct {
+		name    string
+		private bool
+		err     error
+		base58  string
+	}{
+		{
+			name:   "pubkey version / prvkey mismatch",
+			err:    ErrInvalidPublicKey,
+			base58: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm",
+		},
+		{
+			name:    "prvkey version / pubkey mismatch",
+			private: true,
+			err:     ErrInvalidPrivateKey,
+			base58:  "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH",
+		},
+		{
+			name:   "invalid pubkey prefix 04",
+			err:    ErrInvalidPublicKey,
+			base58: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn",
+		},
+		{
+			name:    "invalid prvkey prefix 04",
+			private: true,
+			err:     ErrInvalidPrivateKey,
+			base58:  "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ",
+		},
+		{
+			name:   "invalid pubkey prefix 01",
+			err:    ErrInvalidPublicKey,
+			base58: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4",
+		},
+		{
+			name:    "invalid prvkey prefix 01",
+			private: true,
+			err:     ErrInvalidPrivateKey,
+			base58:  "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J",
+		},
+		{
+			name:    "zero depth with non-zero parent fingerprint (private)",
+			private: true,
+			err:     ErrInvalidFingerprint,
+			base58:  "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv",
+		},
+		{
+			name:   "zero depth with non-zero parent fingerprint (public)",
+			err:    ErrInvalidFingerprint,
+			base58: "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+		},
+		{
+			name:    "zero depth with non-zero index (private)",
+			private: true,
+			err:     ErrInvalidChildNumber,
+			base58:  "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN",
+		},
+		{
+			name:   "zero depth with non-zero index (public)",
+			err:    ErrInvalidChildNumber,
+			base58: "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8",
+		},
+		{
+			name:    "unknown extended key version (private)",
+			private: true,
+			err:     ErrInvalidKeyVersion,
+			base58:  "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4",
+		},
+		{
+			name:   "unknown extended key version (public)",
+			err:    ErrInvalidKeyVersion,
+			base58: "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9",
+		},
+		{
+			name:    "private key 0 not in 1..n-1",
+			private: true,
+			err:     ErrInvalidPrivateKey,
+			base58:  "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx",
+		},
+		{
+			name:    "private key n not in 1..n-1",
+			private: true,
+			err:     ErrInvalidPrivateKey,
+			base58:  "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G",
+		},
+		{
+			name:   "invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007",
+			err:    ErrInvalidPublicKey,
+			base58: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY",
+		},
+		{
+			name:    "invalid checksum",
+			private: true,
+			err:     ErrInvalidChecksum,
+			base58:  "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			if tc.private {
+				_, err = DeserializeEncodedPrivateKey(tc.base58)
+			} else {
+				_, err = DeserializeEncodedPublicKey(tc.base58)
+			}
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestDeriveFromPathErrors(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	// The path m returns a copy of the master key
+	p, err := ParsePath("m")
+	require.NoError(t, err)
+	k, err := DeriveFromPath(master, p)
+	require.NoError(t, err)
+	require.Equal(t, master, k)
+	require.False(t, master == k)
+
+	pk, err := DerivePublicFromPath(master.PublicKey(), p)
+	require.NoError(t, err)
+	require.Equal(t, master.PublicKey(), pk)
+
+	// A full path can't be derived from a child key
+	p, err = ParsePath("m/0'/1")
+	require.NoError(t, err)
+	child, err := DeriveFromPath(master, p)
+	require.NoError(t, err)
+
+	_, err = DeriveFromPath(child, p)
+	require.Equal(t, ErrDeriveFromNonMasterKey, err)
+	_, err = DerivePublicFromPath(child.PublicKey(), p)
+	require.Equal(t, ErrDeriveFromNonMasterKey, err)
+
+	// A hardened path can't be derived from a public key
+	_, err = DerivePublicFromPath(master.PublicKey(), p)
+	require.Equal(t, ErrHardenedChildPublicKey, err)
+
+	// A path must start with the master node
+	_, err = DeriveFromPath(master, &Path{})
+	require.Equal(t, ErrPathNoMaster, err)
+	_, err = DeriveFromPath(master, &Path{Elements: []PathNode{{ChildNumber: 1}}})
+	require.Equal(t, ErrPathNoMaster, err)
+}
+
 func TestParentPublicChildDerivation(t *testing.T) {
 	// Generated using https://iancoleman.github.io/bip39/
 	// Root key:
@@ -1138,6 +1313,31 @@ func TestParsePath(t *testing.T) {
 			err:  ErrPathChildMaster,
 		},
 
+		{
+			path: "m/44h/8000H/0'/0/1",
+			p: &Path{
+				Elements: []PathNode{
+					{Master: true},
+					{ChildNumber: FirstHardenedChild + 44},
+					{ChildNumber: FirstHardenedChild + 8000},
+					{ChildNumber: FirstHardenedChild},
+					{ChildNumber: 0},
+					{ChildNumber: 1},
+				},
+			},
+			hardenedDepths: []int{1, 2, 3},
+		},
+
+		{
+			path: "m/1hh",
+			err:  ErrPathNodeNotNumber,
+		},
+
+		{
+			path: "m/1'h",
+			err:  ErrPathNodeNotNumber,
+		},
+
 		{
 			path: "m/1'/1/4294967296", // maxuint32+1
 			err:  ErrPathNodeNotNumber,
@@ -1170,10 +1370,25 @@ func TestParsePath(t *testing.T) {
 				_, ok := hardenedDepthsMap[i]
 				require.Equal(t, ok, n.Hardened())
 			}
+
+			// The formatted path parses back to the same path
+			p2, err := ParsePath(p.String())
+			require.NoError(t, err)
+			require.Equal(t, p, p2)
 		})
 	}
 }
 
+func TestPathString(t *testing.T) {
+	p, err := ParsePath("m/44h/8000H/0'/0/1")
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/8000'/0'/0/1", p.String())
+
+	p, err = ParsePath("m")
+	require.NoError(t, err)
+	require.Equal(t, "m", p.String())
+}
+
 func TestMaxChildDepthError(t *testing.T) {
 	key, err := NewMasterKey(make([]byte, 32))
 	require.NoError(t, err)
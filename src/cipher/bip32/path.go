@@ -23,6 +23,27 @@ func (p PathNode) Hardened() bool {
 	return p.ChildNumber >= FirstHardenedChild
 }
 
+// String returns the path node as formatted in a path, e.g. m, 0 or 44'
+func (p PathNode) String() string {
+	if p.Master {
+		return "m"
+	}
+	if p.Hardened() {
+		return strconv.FormatUint(uint64(p.ChildNumber-FirstHardenedChild), 10) + "'"
+	}
+	return strconv.FormatUint(uint64(p.ChildNumber), 10)
+}
+
+// String returns the path formatted as parsed by ParsePath, e.g. m/44'/8000'/0'/0/1.
+// Hardened nodes are formatted with an apostrophe.
+func (p Path) String() string {
+	nodes := make([]string, len(p.Elements))
+	for i, n := range p.Elements {
+		nodes[i] = n.String()
+	}
+	return strings.Join(nodes, "/")
+}
+
 var (
 	// ErrPathNoMaster HD wallet path does not start with m
 	ErrPathNoMaster = errors.New("Path must start with m")
@@ -35,6 +56,7 @@ var (
 )
 
 // ParsePath parses a bip32 HD wallet path. The path must start with m/.
+// Hardened nodes have an apostrophe ' or h appended, e.g. m/44'/8000'/0'/0/1 or m/44h/8000h/0h/0/1.
 func ParsePath(p string) (*Path, error) {
 	pts := strings.Split(p, "/")
 
@@ -70,14 +92,14 @@ func ParsePath(p string) (*Path, error) {
 }
 
 func parseNode(x string) (PathNode, error) {
-	// Hardened nodes have an apostrophe ' appended
+	// Hardened nodes have an apostrophe ' or h appended
 	hardened := false
-	if strings.HasSuffix(x, "'") {
+	if strings.HasSuffix(x, "'") || strings.HasSuffix(x, "h") || strings.HasSuffix(x, "H") {
 		hardened = true
 		x = x[:len(x)-1]
 	}
 
-	// Node element (minus a single trailing apostrophe) must be a valid uint32 number
+	// Node element (minus a single trailing hardened marker) must be a valid uint32 number
 	n, err := strconv.ParseUint(x, 10, 32)
 	if err != nil {
 		return PathNode{}, ErrPathNodeNotNumber
@@ -0,0 +1,202 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
+	"github.com/skycoin/skycoin/src/cipher/scrypt"
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+)
+
+const (
+	// bip38Len is the length of a BIP38 encrypted key, prefix + flag + address hash + encrypted key
+	bip38Len = 2 + 1 + 4 + 32
+	// bip38StringLen is the length of the base58 encoding of a BIP38 encrypted key and its checksum
+	bip38StringLen = 58
+
+	// bip38FlagNoECMultiply is the flag byte of keys encrypted without EC multiplication
+	bip38FlagNoECMultiply = 0xc0
+	// bip38FlagCompressed is set in the flag byte if the address is of the compressed public key
+	bip38FlagCompressed = 0x20
+
+	bip38ScryptN = 16384
+	bip38ScryptR = 8
+	bip38ScryptP = 8
+)
+
+var (
+	// bip38Prefix is the prefix of keys encrypted without EC multiplication, their encoding starts with "6P"
+	bip38Prefix = []byte{0x01, 0x42}
+	// bip38ECMultiplyPrefix is the prefix of keys encrypted with EC multiplication, their encoding also starts with "6P"
+	bip38ECMultiplyPrefix = []byte{0x01, 0x43}
+)
+
+var (
+	// ErrBIP38Invalid the string is not a BIP38 encrypted key
+	ErrBIP38Invalid = errors.New("BIP38 key: Invalid format")
+	// ErrBIP38InvalidChecksum the checksum of the BIP38 encrypted key failed
+	ErrBIP38InvalidChecksum = errors.New("BIP38 key: Checksum fail")
+	// ErrBIP38ECMultiply the BIP38 key was encrypted with EC multiplication, which is not supported
+	ErrBIP38ECMultiply = errors.New("BIP38 key: EC multiplied keys are not supported")
+	// ErrBIP38EmptyPassphrase the passphrase is empty
+	ErrBIP38EmptyPassphrase = errors.New("BIP38 key: Empty passphrase")
+	// ErrBIP38WrongPassphrase the decrypted key does not match the address hash of the BIP38 key
+	ErrBIP38WrongPassphrase = errors.New("BIP38 key: Wrong passphrase")
+)
+
+// IsBIP38Key returns true if input has the form of a BIP38 encrypted key:
+// 58 base58 characters starting with "6P". It does not check the checksum.
+func IsBIP38Key(input string) bool {
+	if len(input) != bip38StringLen || input[:2] != "6P" {
+		return false
+	}
+
+	b, err := base58.Decode(input)
+	return err == nil && len(b) == bip38Len+base58check.ChecksumLen
+}
+
+// bip38AddressHash returns the first 4 bytes of sha256(sha256(addr)), the salt of the key derivation
+func bip38AddressHash(addr string) []byte {
+	h := DoubleSHA256([]byte(addr))
+	return h[:4]
+}
+
+// bip38DeriveKey derives the 64 byte key that encrypts the secret key, from the NFC normalized passphrase
+func bip38DeriveKey(passphrase, addrHash []byte) ([]byte, error) {
+	return scrypt.Key(norm.NFC.Bytes(passphrase), addrHash, bip38ScryptN, bip38ScryptR, bip38ScryptP, 64)
+}
+
+// BIP38Encrypt encrypts a secret key with a passphrase in the BIP38 format without EC multiplication,
+// for paper wallets that do not expose the raw secret key. The key is encrypted with AES-256
+// and a scrypt derived key, salted with the hash of the skycoin address of the key, which is
+// used to check the passphrase when decrypting.
+func BIP38Encrypt(secKey SecKey, passphrase []byte) (string, error) {
+	if len(passphrase) == 0 {
+		return "", ErrBIP38EmptyPassphrase
+	}
+
+	addr, err := AddressFromSecKey(secKey)
+	if err != nil {
+		return "", err
+	}
+
+	addrHash := bip38AddressHash(addr.String())
+	derived, err := bip38DeriveKey(passphrase, addrHash)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return "", err
+	}
+
+	encrypted := make([]byte, 32)
+	for i := range encrypted {
+		encrypted[i] = secKey[i] ^ derived[i]
+	}
+	block.Encrypt(encrypted[:16], encrypted[:16])
+	block.Encrypt(encrypted[16:], encrypted[16:])
+
+	b := make([]byte, 0, bip38Len)
+	b = append(b, bip38Prefix...)
+	b = append(b, bip38FlagNoECMultiply|bip38FlagCompressed)
+	b = append(b, addrHash...)
+	b = append(b, encrypted...)
+
+	return base58check.Encode(b, base58check.DoubleSHA256Checksum), nil
+}
+
+// BIP38Decrypt decrypts a BIP38 encrypted key without EC multiplication with the passphrase.
+// Keys encrypted by BIP38Encrypt and bitcoin paper wallet keys are accepted: the address hash
+// is checked against the skycoin address and the bitcoin address of the decrypted key.
+func BIP38Decrypt(input string, passphrase []byte) (SecKey, error) {
+	if len(passphrase) == 0 {
+		return SecKey{}, ErrBIP38EmptyPassphrase
+	}
+
+	if !IsBIP38Key(input) {
+		return SecKey{}, ErrBIP38Invalid
+	}
+
+	b, err := base58.Decode(input)
+	if err != nil {
+		return SecKey{}, ErrBIP38Invalid
+	}
+
+	if err := base58check.Verify(b, base58check.DoubleSHA256Checksum); err != nil {
+		return SecKey{}, ErrBIP38InvalidChecksum
+	}
+
+	switch {
+	case bytes.Equal(b[:2], bip38Prefix):
+	case bytes.Equal(b[:2], bip38ECMultiplyPrefix):
+		return SecKey{}, ErrBIP38ECMultiply
+	default:
+		return SecKey{}, ErrBIP38Invalid
+	}
+
+	flag := b[2]
+	if flag&^bip38FlagCompressed != bip38FlagNoECMultiply {
+		return SecKey{}, ErrBIP38Invalid
+	}
+	compressed := flag&bip38FlagCompressed != 0
+
+	addrHash := b[3:7]
+	encrypted := make([]byte, 32)
+	copy(encrypted, b[7:bip38Len])
+
+	derived, err := bip38DeriveKey(passphrase, addrHash)
+	if err != nil {
+		return SecKey{}, err
+	}
+
+	block, err := aes.NewCipher(derived[32:])
+	if err != nil {
+		return SecKey{}, err
+	}
+
+	block.Decrypt(encrypted[:16], encrypted[:16])
+	block.Decrypt(encrypted[16:], encrypted[16:])
+	for i := range encrypted {
+		encrypted[i] ^= derived[i]
+	}
+
+	secKey, err := NewSecKey(encrypted)
+	if err != nil {
+		return SecKey{}, ErrBIP38WrongPassphrase
+	}
+
+	pk, err := PubKeyFromSecKey(secKey)
+	if err != nil {
+		return SecKey{}, ErrBIP38WrongPassphrase
+	}
+
+	var addrs []string
+	if compressed {
+		addrs = []string{
+			AddressFromPubKey(pk).String(),
+			BitcoinAddressFromPubKey(pk).String(),
+		}
+	} else {
+		h := SumSHA256(secp256k1.UncompressPubkey(pk[:]))
+		addrs = []string{
+			BitcoinAddress{
+				Key: HashRipemd160(h[:]),
+			}.String(),
+		}
+	}
+
+	for _, addr := range addrs {
+		if bytes.Equal(bip38AddressHash(addr), addrHash) {
+			return secKey, nil
+		}
+	}
+
+	return SecKey{}, ErrBIP38WrongPassphrase
+}
@@ -0,0 +1,129 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBIP38Decrypt(t *testing.T) {
+	// Test vectors from BIP38, encrypted without EC multiplication
+	cases := []struct {
+		name       string
+		key        string
+		passphrase string
+		secKey     string
+	}{
+		{
+			name:       "uncompressed",
+			key:        "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGg",
+			passphrase: "TestingOneTwoThree",
+			secKey:     "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		{
+			name:       "uncompressed 2",
+			key:        "6PRNFFkZc2NZ6dJqFfhRoFNMR9Lnyj7dYGrzdgXXVMXcxoKTePPX1dWByq",
+			passphrase: "Satoshi",
+			secKey:     "09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		},
+		{
+			name:       "uncompressed unicode passphrase",
+			key:        "6PRW5o9FLp4gJDDVqJQKJFTpMvdsSGJxMYHtHaQBF3ooa8mwD69bapcDQn",
+			passphrase: "\u03d2\u0301\u0000\U00010400\U0001F4A9",
+			secKey:     "64eeab5f9be2a01a8365a579511eb3373c87c40da6d2a25f05bda68fe077b66e",
+		},
+		{
+			name:       "compressed",
+			key:        "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			passphrase: "TestingOneTwoThree",
+			secKey:     "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+		},
+		{
+			name:       "compressed 2",
+			key:        "6PYLtMnXvfG3oJde97zRyLYFZCYizPU5T3LwgdYJz1fRhh16bU7u6PPmY7",
+			passphrase: "Satoshi",
+			secKey:     "09c2686880095b1a4c249ee3ac4eea8a014f11e6f986d0b5025ac1f39afbd9ae",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.True(t, IsBIP38Key(tc.key))
+
+			sk, err := BIP38Decrypt(tc.key, []byte(tc.passphrase))
+			require.NoError(t, err)
+			require.Equal(t, tc.secKey, sk.Hex())
+		})
+	}
+}
+
+func TestBIP38EncryptDecrypt(t *testing.T) {
+	_, sk := GenerateKeyPair()
+	passphrase := []byte("paper wallet")
+
+	key, err := BIP38Encrypt(sk, passphrase)
+	require.NoError(t, err)
+	require.True(t, IsBIP38Key(key))
+	require.Equal(t, "6PY", key[:3])
+
+	// The encryption is deterministic, salted with the address hash
+	key2, err := BIP38Encrypt(sk, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, key, key2)
+
+	sk2, err := BIP38Decrypt(key, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, sk, sk2)
+
+	_, err = BIP38Decrypt(key, []byte("wrong passphrase"))
+	require.Equal(t, ErrBIP38WrongPassphrase, err)
+
+	_, err = BIP38Encrypt(sk, nil)
+	require.Equal(t, ErrBIP38EmptyPassphrase, err)
+	_, err = BIP38Decrypt(key, nil)
+	require.Equal(t, ErrBIP38EmptyPassphrase, err)
+}
+
+func TestBIP38DecryptInvalid(t *testing.T) {
+	passphrase := []byte("TestingOneTwoThree")
+
+	cases := []struct {
+		name string
+		key  string
+		err  error
+	}{
+		{
+			name: "empty",
+			key:  "",
+			err:  ErrBIP38Invalid,
+		},
+		{
+			name: "hex secret key",
+			key:  "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5",
+			err:  ErrBIP38Invalid,
+		},
+		{
+			name: "invalid base58",
+			key:  "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2Zo0g",
+			err:  ErrBIP38Invalid,
+		},
+		{
+			name: "invalid checksum",
+			key:  "6PRVWUbkzzsbcVac2qwfssoUJAN1Xhrg6bNk8J7Nzm5H7kxEbn2Nh2ZoGh",
+			err:  ErrBIP38InvalidChecksum,
+		},
+		{
+			// BIP38 test vector "No compression, EC multiply, no lot/sequence numbers"
+			name: "ec multiply",
+			key:  "6PfQu77ygVyJLZjfvMLyhLMQbYnu5uguoJJ4kMCLqWwPEdfpwANVS76gTX",
+			err:  ErrBIP38ECMultiply,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := BIP38Decrypt(tc.key, passphrase)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
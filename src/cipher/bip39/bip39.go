@@ -15,8 +15,11 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/text/unicode/norm"
+
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/bip39/wordlists"
+	"github.com/skycoin/skycoin/src/cipher/secret"
 
 	"github.com/skycoin/skycoin/src/cipher/pbkdf2"
 )
@@ -148,9 +151,10 @@ func NewEntropy(bitSize int) ([]byte, error) {
 
 // EntropyFromMnemonic takes a mnemonic generated by this library,
 // and returns the input entropy used to generate the given mnemonic.
+// The language of the mnemonic is detected with DetectLanguage.
 // An error is returned if the given mnemonic is invalid.
 func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
-	words, err := splitMnemonicWords(mnemonic)
+	words, _, wl, err := splitMnemonicWords(mnemonic)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +162,7 @@ func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
 	// Decode the words into a big.Int.
 	b := big.NewInt(0)
 	for _, v := range words {
-		index, found := wordMap[v]
+		index, found := wl.index[v]
 		if !found {
 			// This should have been caught by splitMnemonicWords()
 			panic(fmt.Sprintf("word %q not found in reverse map", v))
@@ -197,18 +201,40 @@ func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
 	return entropy, nil
 }
 
-// NewMnemonic will return a string consisting of the mnemonic words for
+// NewMnemonic will return a string consisting of the english mnemonic words for
 // the given entropy.
 // If the provide entropy is invalid, an error will be returned.
 func NewMnemonic(entropy []byte) (string, error) {
+	return NewMnemonicInLanguage(entropy, English)
+}
+
+// NewDefaultMnemonicInLanguage returns a generated mnemonic in the language using entropy with bitSize 128
+func NewDefaultMnemonicInLanguage(l Language) (string, error) {
+	entropy, err := NewEntropy(DefaultMnemonicEntropyBitSize)
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicInLanguage(entropy, l)
+}
+
+// NewMnemonicInLanguage will return a string consisting of the mnemonic words
+// of the language for the given entropy. The words of japanese mnemonics are
+// separated by ideographic spaces, other mnemonics are separated by ASCII spaces.
+// If the provide entropy is invalid, an error will be returned.
+func NewMnemonicInLanguage(entropy []byte, l Language) (string, error) {
+	wl, err := getLanguageWordList(l)
+	if err != nil {
+		return "", err
+	}
+
 	// Compute some lengths for convenience.
 	entropyBitLength := len(entropy) * 8
 	checksumBitLength := entropyBitLength / 32
 	sentenceLength := (entropyBitLength + checksumBitLength) / 11
 
 	// Validate that the requested size is supported.
-	err := validateEntropyBitSize(entropyBitLength)
-	if err != nil {
+	if err := validateEntropyBitSize(entropyBitLength); err != nil {
 		return "", err
 	}
 
@@ -238,20 +264,64 @@ func NewMnemonic(entropy []byte) (string, error) {
 		wordBytes := padByteSlice(word.Bytes(), 2)
 
 		// Convert bytes to an index and add that word to the list.
-		words[i] = wordList[binary.BigEndian.Uint16(wordBytes)]
+		words[i] = wl.words[binary.BigEndian.Uint16(wordBytes)]
 	}
 
-	return strings.Join(words, " "), nil
+	return strings.Join(words, l.separator()), nil
 }
 
 // NewSeed creates a hashed seed output given the mnemonic string and a password.
+// The mnemonic is NFKD normalized and its language is detected with DetectLanguage.
 // An error is returned if the mnemonic is not valid.
 func NewSeed(mnemonic string, password string) ([]byte, error) {
-	if err := ValidateMnemonic(mnemonic); err != nil {
+	words, l, _, err := splitMnemonicWords(mnemonic)
+	if err != nil {
 		return nil, err
 	}
 
-	return newSeed(mnemonic, password), nil
+	if !isChecksumValid(words, languageWordLists[l].index) {
+		return nil, ErrChecksumIncorrect
+	}
+
+	// The password of english mnemonics is not normalized, the seeds of the
+	// existing wallets with non-ASCII passwords would change otherwise
+	if l != English {
+		password = norm.NFKD.String(password)
+	}
+
+	return newSeed(strings.Join(words, " "), password), nil
+}
+
+// NewSeedFromBytes creates a hashed seed output given the mnemonic and password bytes,
+// like NewSeed. It's used for the mnemonics held in secret.Buffers: the mnemonic is not
+// copied to strings, and the normalized copies of the mnemonic and password are wiped.
+func NewSeedFromBytes(mnemonic, password []byte) ([]byte, error) {
+	words, nm, err := splitMnemonicBytes(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeMnemonic(words, nm)
+
+	l, wl, err := detectLanguage(words)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isChecksumValid(words, wl.index) {
+		return nil, ErrChecksumIncorrect
+	}
+
+	// The password of english mnemonics is not normalized, see NewSeed
+	var salt []byte
+	if l == English {
+		salt = append([]byte("mnemonic"), password...)
+	} else {
+		salt = norm.NFKD.Append([]byte("mnemonic"), password...)
+	}
+	defer secret.Wipe(salt)
+
+	// The normalized mnemonic is the words joined with single spaces
+	return pbkdf2.Key(nm, salt, 2048, 64, sha512.New), nil
 }
 
 // newSeed creates a hashed seed output given a provided string and password.
@@ -267,14 +337,63 @@ func newSeed(mnemonic, password string) []byte {
 // - Mnemonic string has leading or trailing whitespace
 // - Any word is not present in the wordlist
 // - The mnemonic checksum is incorrect
-// Note: this only works on ascii wordlists. Japanese, Chinese etc are not supported yet.
+// The language of the mnemonic is detected with DetectLanguage.
 func ValidateMnemonic(mnemonic string) error {
-	words, err := splitMnemonicWords(mnemonic)
+	words, l, _, err := splitMnemonicWords(mnemonic)
 	if err != nil {
 		return err
 	}
 
-	if !isMnemonicChecksumValid(words) {
+	if !isChecksumValid(words, languageWordLists[l].index) {
+		return ErrChecksumIncorrect
+	}
+
+	return nil
+}
+
+// ValidateMnemonicInLanguage returns an error if a mnemonic is invalid or
+// if its words are not in the wordlist of the language
+func ValidateMnemonicInLanguage(mnemonic string, l Language) error {
+	wl, err := getLanguageWordList(l)
+	if err != nil {
+		return err
+	}
+
+	words, err := splitMnemonic(mnemonic)
+	if err != nil {
+		return err
+	}
+
+	if !wl.hasWords(words) {
+		return ErrUnknownWord
+	}
+
+	if !isChecksumValid(words, wl.index) {
+		return ErrChecksumIncorrect
+	}
+
+	return nil
+}
+
+// ValidateMnemonicBytesInLanguage is ValidateMnemonicInLanguage for a mnemonic held
+// in a byte slice, the mnemonic is not copied to strings
+func ValidateMnemonicBytesInLanguage(mnemonic []byte, l Language) error {
+	wl, err := getLanguageWordList(l)
+	if err != nil {
+		return err
+	}
+
+	words, nm, err := splitMnemonicBytes(mnemonic)
+	if err != nil {
+		return err
+	}
+	defer wipeMnemonic(words, nm)
+
+	if !wl.hasWords(words) {
+		return ErrUnknownWord
+	}
+
+	if !isChecksumValid(words, wl.index) {
 		return ErrChecksumIncorrect
 	}
 
@@ -283,15 +402,33 @@ func ValidateMnemonic(mnemonic string) error {
 
 // splitMnemonicWords attempts to verify that the provided mnemonic is valid.
 // Validity is determined by both the number of words being appropriate,
-// and that all the words in the mnemonic are present in the word list.
-func splitMnemonicWords(mnemonic string) ([]string, error) {
+// and that all the words in the mnemonic are present in the word list
+// of one language. The words are NFKD normalized.
+func splitMnemonicWords(mnemonic string) ([]string, Language, *languageWordList, error) {
+	words, err := splitMnemonic(mnemonic)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	l, wl, err := detectLanguage(words)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return words, l, wl, nil
+}
+
+// splitMnemonic NFKD normalizes the mnemonic and splits it into words,
+// checking the whitespace and the number of words.
+// The ideographic spaces of japanese mnemonics are normalized to ASCII spaces.
+func splitMnemonic(mnemonic string) ([]string, error) {
 	// Make sure no leading/trailing whitespace
 	if mnemonic != strings.TrimSpace(mnemonic) {
 		return nil, ErrSurroundingWhitespace
 	}
 
 	// Create a list of all the words in the mnemonic sentence
-	words := strings.Split(mnemonic, " ")
+	words := strings.Split(norm.NFKD.String(mnemonic), " ")
 
 	// Detect duplicate whitespace
 	for _, w := range words {
@@ -307,18 +444,64 @@ func splitMnemonicWords(mnemonic string) ([]string, error) {
 		return nil, ErrInvalidNumberOfWords
 	}
 
-	// Check if all words belong in the wordlist
-	for _, word := range words {
-		if _, ok := wordMap[word]; !ok {
-			return nil, ErrUnknownWord
+	return words, nil
+}
+
+// splitMnemonicBytes is splitMnemonic for a mnemonic held in a byte slice, it also returns
+// the normalized mnemonic. The words are the normalized words of the wordlists, so that
+// the mnemonic is not copied to strings, ErrUnknownWord is returned if a word is in no wordlist.
+// The caller wipes the words and the normalized mnemonic with wipeMnemonic.
+func splitMnemonicBytes(mnemonic []byte) ([]string, []byte, error) {
+	// Make sure no leading/trailing whitespace
+	if len(bytes.TrimSpace(mnemonic)) != len(mnemonic) {
+		return nil, nil, ErrSurroundingWhitespace
+	}
+
+	nm := norm.NFKD.Append(nil, mnemonic...)
+	fields := bytes.Split(nm, []byte(" "))
+
+	// Detect duplicate whitespace
+	for _, f := range fields {
+		if len(f) == 0 {
+			secret.Wipe(nm)
+			return nil, nil, ErrInvalidSeparator
+		}
+	}
+
+	// The number of words should be 12, 15, 18, 21 or 24
+	if n := len(fields); n%3 != 0 || n < 12 || n > 24 {
+		secret.Wipe(nm)
+		return nil, nil, ErrInvalidNumberOfWords
+	}
+
+	words := make([]string, len(fields))
+	for i, f := range fields {
+		w, ok := normalizedWord(f)
+		if !ok {
+			wipeMnemonic(words, nm)
+			return nil, nil, ErrUnknownWord
 		}
+		words[i] = w
 	}
 
-	return words, nil
+	return words, nm, nil
+}
+
+// wipeMnemonic clears the words and wipes the normalized mnemonic returned by splitMnemonicBytes
+func wipeMnemonic(words []string, nm []byte) {
+	for i := range words {
+		words[i] = ""
+	}
+	secret.Wipe(nm)
 }
 
-// isMnemonicChecksumValid validates the checksum value of a mnemonic
+// isMnemonicChecksumValid validates the checksum value of an english mnemonic
 func isMnemonicChecksumValid(words []string) bool {
+	return isChecksumValid(words, wordMap)
+}
+
+// isChecksumValid validates the checksum value of a mnemonic, index maps the words to their index
+func isChecksumValid(words []string, index map[string]int) bool {
 	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
 		panic("invalid number of words") // caller should validate words before passing to this function
 	}
@@ -334,9 +517,9 @@ func isMnemonicChecksumValid(words []string) bool {
 	checksummedEntropy := big.NewInt(0)
 	modulo := big.NewInt(2048)
 	for _, v := range words {
-		index := big.NewInt(int64(wordMap[v]))
+		idx := big.NewInt(int64(index[v]))
 		checksummedEntropy.Mul(checksummedEntropy, modulo)
-		checksummedEntropy.Add(checksummedEntropy, index)
+		checksummedEntropy.Add(checksummedEntropy, idx)
 	}
 
 	// Calculate the unchecksummed entropy so we can validate that the checksum is
@@ -51,6 +51,21 @@ func TestNewMnemonic(t *testing.T) {
 	}
 }
 
+func TestNewSeedFromBytes(t *testing.T) {
+	for _, vector := range testVectors() {
+		mnemonic := []byte(vector.mnemonic)
+		seed, err := NewSeedFromBytes(mnemonic, []byte("TREZOR"))
+		require.NoError(t, err)
+		require.Equal(t, vector.seed, hex.EncodeToString(seed))
+		require.Equal(t, vector.mnemonic, string(mnemonic))
+	}
+
+	for _, vector := range badMnemonicSentences() {
+		_, err := NewSeedFromBytes([]byte(vector.mnemonic), []byte("TREZOR"))
+		require.Equal(t, vector.err, err, vector.mnemonic)
+	}
+}
+
 func TestNewMnemonicInvalidEntropy(t *testing.T) {
 	_, err := NewMnemonic([]byte{})
 	require.Error(t, err)
@@ -0,0 +1,173 @@
+package bip39
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/skycoin/skycoin/src/cipher/bip39/wordlists"
+)
+
+// Language is the language of a BIP39 wordlist
+type Language string
+
+const (
+	// English is the english wordlist, used by default
+	English Language = "english"
+	// ChineseSimplified is the simplified chinese wordlist
+	ChineseSimplified Language = "chinese_simplified"
+	// ChineseTraditional is the traditional chinese wordlist
+	ChineseTraditional Language = "chinese_traditional"
+	// French is the french wordlist
+	French Language = "french"
+	// Italian is the italian wordlist
+	Italian Language = "italian"
+	// Japanese is the japanese wordlist
+	Japanese Language = "japanese"
+	// Korean is the korean wordlist
+	Korean Language = "korean"
+	// Spanish is the spanish wordlist
+	Spanish Language = "spanish"
+)
+
+// ErrUnknownLanguage is returned if the language has no wordlist
+var ErrUnknownLanguage = errors.New("Unknown mnemonic language")
+
+// japaneseSeparator is the ideographic space that separates the words of japanese mnemonics
+const japaneseSeparator = "　"
+
+// languageWordList holds a wordlist and its reverse lookup map
+type languageWordList struct {
+	words []string
+	// normalized are the NFKD normalized words
+	normalized []string
+	// index maps the NFKD normalized words to their index
+	index map[string]int
+}
+
+func newLanguageWordList(words []string) *languageWordList {
+	l := &languageWordList{
+		words:      words,
+		normalized: make([]string, len(words)),
+		index:      make(map[string]int, len(words)),
+	}
+	for i, w := range words {
+		l.normalized[i] = norm.NFKD.String(w)
+		l.index[l.normalized[i]] = i
+	}
+	return l
+}
+
+// languages are the supported languages, in the order a mnemonic language is detected
+var languages = []Language{
+	English,
+	Spanish,
+	French,
+	Italian,
+	Japanese,
+	Korean,
+	ChineseSimplified,
+	ChineseTraditional,
+}
+
+var languageWordLists = map[Language]*languageWordList{
+	English:            newLanguageWordList(wordlists.English),
+	ChineseSimplified:  newLanguageWordList(wordlists.ChineseSimplified),
+	ChineseTraditional: newLanguageWordList(wordlists.ChineseTraditional),
+	French:             newLanguageWordList(wordlists.French),
+	Italian:            newLanguageWordList(wordlists.Italian),
+	Japanese:           newLanguageWordList(wordlists.Japanese),
+	Korean:             newLanguageWordList(wordlists.Korean),
+	Spanish:            newLanguageWordList(wordlists.Spanish),
+}
+
+// Languages returns the supported languages
+func Languages() []Language {
+	ls := make([]Language, len(languages))
+	copy(ls, languages)
+	return ls
+}
+
+// ParseLanguage parses a language name, e.g. "japanese"
+func ParseLanguage(s string) (Language, error) {
+	l := Language(strings.ToLower(s))
+	if _, ok := languageWordLists[l]; !ok {
+		return "", ErrUnknownLanguage
+	}
+	return l, nil
+}
+
+func getLanguageWordList(l Language) (*languageWordList, error) {
+	wl, ok := languageWordLists[l]
+	if !ok {
+		return nil, ErrUnknownLanguage
+	}
+	return wl, nil
+}
+
+// DetectLanguage returns the language of the mnemonic's wordlist.
+// Some words are in multiple wordlists, if the words of the mnemonic are all in
+// multiple wordlists, the first of them whose checksum is valid is returned.
+func DetectLanguage(mnemonic string) (Language, error) {
+	words, err := splitMnemonic(mnemonic)
+	if err != nil {
+		return "", err
+	}
+
+	l, _, err := detectLanguage(words)
+	return l, err
+}
+
+// detectLanguage returns the language of the words, which must be NFKD normalized
+func detectLanguage(words []string) (Language, *languageWordList, error) {
+	var candidate Language
+	for _, l := range languages {
+		wl := languageWordLists[l]
+		if !wl.hasWords(words) {
+			continue
+		}
+
+		if isChecksumValid(words, wl.index) {
+			return l, wl, nil
+		}
+
+		if candidate == "" {
+			candidate = l
+		}
+	}
+
+	if candidate == "" {
+		return "", nil, ErrUnknownWord
+	}
+
+	return candidate, languageWordLists[candidate], nil
+}
+
+func (l *languageWordList) hasWords(words []string) bool {
+	for _, w := range words {
+		if _, ok := l.index[w]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizedWord returns the NFKD normalized word of a wordlist that equals w
+func normalizedWord(w []byte) (string, bool) {
+	for _, l := range languages {
+		wl := languageWordLists[l]
+		if i, ok := wl.index[string(w)]; ok {
+			return wl.normalized[i], true
+		}
+	}
+	return "", false
+}
+
+// separator returns the separator of the mnemonic words
+func (l Language) separator() string {
+	if l == Japanese {
+		return japaneseSeparator
+	}
+	return " "
+}
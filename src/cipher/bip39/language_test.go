@@ -0,0 +1,133 @@
+package bip39
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestJapaneseVector(t *testing.T) {
+	// Test vector of https://github.com/bip32JP/bip32JP.github.io/blob/master/test_JP_BIP39.json
+	mnemonic := strings.Repeat("あいこくしん　", 11) + "あおぞら"
+	password := "㍍ガバヴァぱばぐゞちぢ十人十色"
+	seed := "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55"
+
+	m, err := NewMnemonicInLanguage(make([]byte, 16), Japanese)
+	require.NoError(t, err)
+	require.Equal(t, norm.NFKD.String(mnemonic), norm.NFKD.String(m))
+
+	l, err := DetectLanguage(mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, Japanese, l)
+
+	s, err := NewSeed(mnemonic, password)
+	require.NoError(t, err)
+	require.Equal(t, seed, hex.EncodeToString(s))
+
+	// The words can also be separated by ASCII spaces
+	s, err = NewSeed(strings.Replace(mnemonic, "　", " ", -1), password)
+	require.NoError(t, err)
+	require.Equal(t, seed, hex.EncodeToString(s))
+
+	s, err = NewSeedFromBytes([]byte(mnemonic), []byte(password))
+	require.NoError(t, err)
+	require.Equal(t, seed, hex.EncodeToString(s))
+	require.NoError(t, ValidateMnemonicBytesInLanguage([]byte(mnemonic), Japanese))
+
+	entropy, err := EntropyFromMnemonic(mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 16), entropy)
+}
+
+func TestLanguagesRoundTrip(t *testing.T) {
+	for _, l := range Languages() {
+		t.Run(string(l), func(t *testing.T) {
+			for i := 0; i < 64; i++ {
+				entropy, err := NewEntropy(256)
+				require.NoError(t, err)
+
+				mnemonic, err := NewMnemonicInLanguage(entropy, l)
+				require.NoError(t, err)
+				require.NoError(t, ValidateMnemonic(mnemonic))
+				require.NoError(t, ValidateMnemonicInLanguage(mnemonic, l))
+
+				entropy2, err := EntropyFromMnemonic(mnemonic)
+				require.NoError(t, err)
+				require.Equal(t, entropy, entropy2)
+
+				d, err := DetectLanguage(mnemonic)
+				require.NoError(t, err)
+				// The simplified and traditional chinese wordlists share words,
+				// a mnemonic of shared words with a valid checksum is ambiguous
+				if l != ChineseTraditional {
+					require.Equal(t, l, d)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tt := []struct {
+		name     string
+		mnemonic string
+		language Language
+		err      error
+	}{
+		{
+			name:     "english",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			language: English,
+		},
+		{
+			name:     "english invalid checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			language: English,
+		},
+		{
+			name:     "spanish",
+			mnemonic: "ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco ábaco abierto",
+			language: Spanish,
+		},
+		{
+			name:     "unknown word",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon ábaco",
+			err:      ErrUnknownWord,
+		},
+		{
+			name:     "invalid number of words",
+			mnemonic: "abandon abandon abandon",
+			err:      ErrInvalidNumberOfWords,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := DetectLanguage(tc.mnemonic)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.language, l)
+		})
+	}
+}
+
+func TestValidateMnemonicInLanguage(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	require.NoError(t, ValidateMnemonicInLanguage(mnemonic, English))
+	require.Equal(t, ErrUnknownWord, ValidateMnemonicInLanguage(mnemonic, Spanish))
+	require.Equal(t, ErrUnknownLanguage, ValidateMnemonicInLanguage(mnemonic, Language("klingon")))
+
+	require.NoError(t, ValidateMnemonicBytesInLanguage([]byte(mnemonic), English))
+	require.Equal(t, ErrUnknownWord, ValidateMnemonicBytesInLanguage([]byte(mnemonic), Spanish))
+	require.Equal(t, ErrUnknownWord, ValidateMnemonicBytesInLanguage([]byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon klingon"), English))
+	require.Equal(t, ErrChecksumIncorrect, ValidateMnemonicBytesInLanguage([]byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"), English))
+
+	l, err := ParseLanguage("Japanese")
+	require.NoError(t, err)
+	require.Equal(t, Japanese, l)
+
+	_, err = ParseLanguage("klingon")
+	require.Equal(t, ErrUnknownLanguage, err)
+}
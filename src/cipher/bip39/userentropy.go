@@ -0,0 +1,199 @@
+package bip39
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// UserEntropyFormat is the format of the user supplied entropy mixed into new entropy
+type UserEntropyFormat string
+
+const (
+	// UserEntropyDice six sided dice rolls, digits 1 to 6, whitespace is ignored
+	UserEntropyDice UserEntropyFormat = "dice"
+	// UserEntropyHex hex encoded bytes, whitespace is ignored
+	UserEntropyHex UserEntropyFormat = "hex"
+)
+
+// entropyMixScheme names the derivation of the mixed entropy:
+// sha256(entropyMixTag || 32 bytes of crypto/rand || user entropy), truncated to the bit size
+const (
+	entropyMixScheme = "sha256-rand256-user-v1"
+	entropyMixTag    = "skycoin-entropy-mix-v1"
+)
+
+var (
+	// ErrUnknownUserEntropyFormat is returned when the user entropy format is not dice or hex
+	ErrUnknownUserEntropyFormat = errors.New("Unknown user entropy format, must be dice or hex")
+	// ErrEmptyUserEntropy is returned when the user entropy is empty
+	ErrEmptyUserEntropy = errors.New("User entropy is empty")
+	// ErrInvalidDiceRolls is returned when dice rolls contain characters other than 1 to 6
+	ErrInvalidDiceRolls = errors.New("Dice rolls must only contain the digits 1 to 6")
+	// ErrInvalidHexEntropy is returned when hex user entropy is not valid hex
+	ErrInvalidHexEntropy = errors.New("User entropy is not valid hex")
+	// ErrInvalidEntropyDescription is returned when parsing an invalid entropy description
+	ErrInvalidEntropyDescription = errors.New("Invalid entropy description")
+	// ErrUserEntropyMismatch is returned when the user entropy is not the one of the entropy description
+	ErrUserEntropyMismatch = errors.New("User entropy does not match the entropy description")
+)
+
+// EntropyDescription describes how mixed entropy was derived, without revealing it.
+// It records the bit size, the format and estimated strength of the user entropy,
+// and a sha256 commitment to the user entropy, so that users can verify their own
+// dice rolls or hex string were mixed in with VerifyUserEntropy.
+// The commitment does not weaken the entropy: without the crypto/rand bytes, which are
+// not recorded, knowing the user entropy does not reveal the mixed entropy.
+type EntropyDescription struct {
+	BitSize    int
+	Format     UserEntropyFormat
+	UserBits   int
+	UserSHA256 cipher.SHA256
+	Scheme     string
+}
+
+// String encodes the description, e.g.
+// "scheme=sha256-rand256-user-v1 bits=128 user=dice user_bits=129 user_sha256=..."
+func (d EntropyDescription) String() string {
+	return fmt.Sprintf("scheme=%s bits=%d user=%s user_bits=%d user_sha256=%s",
+		d.Scheme, d.BitSize, d.Format, d.UserBits, d.UserSHA256.Hex())
+}
+
+// ParseEntropyDescription parses an entropy description encoded by EntropyDescription.String
+func ParseEntropyDescription(s string) (EntropyDescription, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	values := make(map[string]string, len(fields))
+	for _, f := range fields {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return EntropyDescription{}, ErrInvalidEntropyDescription
+		}
+		values[kv[0]] = kv[1]
+	}
+
+	var d EntropyDescription
+	var err error
+
+	d.Scheme = values["scheme"]
+	if d.Scheme != entropyMixScheme {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	d.BitSize, err = strconv.Atoi(values["bits"])
+	if err != nil || validateEntropyBitSize(d.BitSize) != nil {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	d.Format = UserEntropyFormat(values["user"])
+	if d.Format != UserEntropyDice && d.Format != UserEntropyHex {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	d.UserBits, err = strconv.Atoi(values["user_bits"])
+	if err != nil || d.UserBits < 0 {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	d.UserSHA256, err = cipher.SHA256FromHex(values["user_sha256"])
+	if err != nil {
+		return EntropyDescription{}, ErrInvalidEntropyDescription
+	}
+
+	return d, nil
+}
+
+// VerifyUserEntropy checks that userEntropy is the user entropy the description was made with
+func (d EntropyDescription) VerifyUserEntropy(userEntropy string) error {
+	b, _, err := ParseUserEntropy(d.Format, userEntropy)
+	if err != nil {
+		return err
+	}
+
+	if cipher.SumSHA256(b) != d.UserSHA256 {
+		return ErrUserEntropyMismatch
+	}
+
+	return nil
+}
+
+// ParseUserEntropy normalizes the user entropy of the format to bytes, and estimates its strength in bits.
+// Dice rolls are normalized to the string of their digits, each roll adds log2(6) bits.
+func ParseUserEntropy(format UserEntropyFormat, s string) ([]byte, int, error) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	switch format {
+	case UserEntropyDice, UserEntropyHex:
+	default:
+		return nil, 0, ErrUnknownUserEntropyFormat
+	}
+
+	if s == "" {
+		return nil, 0, ErrEmptyUserEntropy
+	}
+
+	switch format {
+	case UserEntropyDice:
+		for _, c := range s {
+			if c < '1' || c > '6' {
+				return nil, 0, ErrInvalidDiceRolls
+			}
+		}
+		return []byte(s), int(float64(len(s)) * math.Log2(6)), nil
+	default:
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, 0, ErrInvalidHexEntropy
+		}
+		return b, len(b) * 8, nil
+	}
+}
+
+// NewEntropyWithUserEntropy creates entropy of bitSize from crypto/rand mixed with user supplied entropy,
+// such as dice rolls, for users who do not trust the host random number generator alone.
+// The entropy is as strong as the stronger of the two sources.
+// The returned description records the derivation and can be stored alongside the seed.
+func NewEntropyWithUserEntropy(bitSize int, format UserEntropyFormat, userEntropy string) ([]byte, EntropyDescription, error) {
+	if err := validateEntropyBitSize(bitSize); err != nil {
+		return nil, EntropyDescription{}, err
+	}
+
+	user, userBits, err := ParseUserEntropy(format, userEntropy)
+	if err != nil {
+		return nil, EntropyDescription{}, err
+	}
+
+	entropy := mixEntropy(cipher.RandByte(32), user, bitSize)
+
+	return entropy, EntropyDescription{
+		BitSize:    bitSize,
+		Format:     format,
+		UserBits:   userBits,
+		UserSHA256: cipher.SumSHA256(user),
+		Scheme:     entropyMixScheme,
+	}, nil
+}
+
+// mixEntropy returns sha256(entropyMixTag || host || user), truncated to bitSize
+func mixEntropy(host, user []byte, bitSize int) []byte {
+	h := sha256.New()
+	h.Write([]byte(entropyMixTag)) //nolint:errcheck
+	h.Write(host)                  //nolint:errcheck
+	h.Write(user)                  //nolint:errcheck
+	return h.Sum(nil)[:bitSize/8]
+}
@@ -0,0 +1,144 @@
+package bip39
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestParseUserEntropy(t *testing.T) {
+	cases := []struct {
+		name   string
+		format UserEntropyFormat
+		s      string
+		b      []byte
+		bits   int
+		err    error
+	}{
+		{
+			name:   "dice",
+			format: UserEntropyDice,
+			s:      "1 2 3\n456 6",
+			b:      []byte("1234566"),
+			bits:   18,
+		},
+		{
+			name:   "dice invalid roll",
+			format: UserEntropyDice,
+			s:      "1234567",
+			err:    ErrInvalidDiceRolls,
+		},
+		{
+			name:   "hex",
+			format: UserEntropyHex,
+			s:      "00ff 10",
+			b:      []byte{0x00, 0xff, 0x10},
+			bits:   24,
+		},
+		{
+			name:   "hex odd length",
+			format: UserEntropyHex,
+			s:      "abc",
+			err:    ErrInvalidHexEntropy,
+		},
+		{
+			name:   "empty",
+			format: UserEntropyDice,
+			s:      " \t",
+			err:    ErrEmptyUserEntropy,
+		},
+		{
+			name:   "unknown format",
+			format: "coins",
+			s:      "0101",
+			err:    ErrUnknownUserEntropyFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, bits, err := ParseUserEntropy(tc.format, tc.s)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tc.b, b)
+			require.Equal(t, tc.bits, bits)
+		})
+	}
+}
+
+func TestNewEntropyWithUserEntropy(t *testing.T) {
+	dice := "314152653562643323626243314456123456543211112355526134561123"
+
+	e1, d1, err := NewEntropyWithUserEntropy(128, UserEntropyDice, dice)
+	require.NoError(t, err)
+	require.Len(t, e1, 16)
+	require.Equal(t, 128, d1.BitSize)
+	require.Equal(t, UserEntropyDice, d1.Format)
+	require.Equal(t, 155, d1.UserBits)
+	require.NoError(t, d1.VerifyUserEntropy(dice))
+	require.Equal(t, ErrUserEntropyMismatch, d1.VerifyUserEntropy(dice+"1"))
+
+	// The host randomness is always mixed in
+	e2, d2, err := NewEntropyWithUserEntropy(128, UserEntropyDice, dice)
+	require.NoError(t, err)
+	require.NotEqual(t, e1, e2)
+	require.Equal(t, d1, d2)
+
+	_, err = NewMnemonic(e1)
+	require.NoError(t, err)
+
+	e3, _, err := NewEntropyWithUserEntropy(256, UserEntropyHex, "deadbeef")
+	require.NoError(t, err)
+	require.Len(t, e3, 32)
+
+	_, _, err = NewEntropyWithUserEntropy(100, UserEntropyHex, "deadbeef")
+	require.Equal(t, ErrInvalidEntropyLength, err)
+	_, _, err = NewEntropyWithUserEntropy(128, UserEntropyHex, "")
+	require.Equal(t, ErrEmptyUserEntropy, err)
+}
+
+func TestMixEntropy(t *testing.T) {
+	host := make([]byte, 32)
+	user := []byte("123456")
+
+	e := mixEntropy(host, user, 256)
+	h := cipher.SumSHA256(append(append([]byte(entropyMixTag), host...), user...))
+	require.Equal(t, h[:], e)
+	require.Equal(t, h[:16], mixEntropy(host, user, 128))
+
+	// Both sources change the entropy
+	require.NotEqual(t, e, mixEntropy(host, []byte("123455"), 256))
+	host[0] = 1
+	require.NotEqual(t, e, mixEntropy(host, user, 256))
+}
+
+func TestEntropyDescription(t *testing.T) {
+	_, d, err := NewEntropyWithUserEntropy(256, UserEntropyHex, "0123456789abcdef")
+	require.NoError(t, err)
+
+	s := d.String()
+	require.Equal(t, "scheme=sha256-rand256-user-v1 bits=256 user=hex user_bits=64 user_sha256="+
+		cipher.SumSHA256([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}).Hex(), s)
+
+	d2, err := ParseEntropyDescription(s)
+	require.NoError(t, err)
+	require.Equal(t, d, d2)
+	require.NoError(t, d2.VerifyUserEntropy("01234567 89ABCDEF"))
+
+	for _, s := range []string{
+		"",
+		"scheme=sha256-rand256-user-v2 bits=256 user=hex user_bits=64 user_sha256=" + d.UserSHA256.Hex(),
+		"scheme=sha256-rand256-user-v1 bits=100 user=hex user_bits=64 user_sha256=" + d.UserSHA256.Hex(),
+		"scheme=sha256-rand256-user-v1 bits=256 user=coins user_bits=64 user_sha256=" + d.UserSHA256.Hex(),
+		"scheme=sha256-rand256-user-v1 bits=256 user=hex user_bits=-1 user_sha256=" + d.UserSHA256.Hex(),
+		"scheme=sha256-rand256-user-v1 bits=256 user=hex user_bits=64 user_sha256=00",
+		"scheme=sha256-rand256-user-v1 bits=256 user=hex user_bits=64 " + d.UserSHA256.Hex(),
+	} {
+		_, err := ParseEntropyDescription(s)
+		require.Equal(t, ErrInvalidEntropyDescription, err, s)
+	}
+}
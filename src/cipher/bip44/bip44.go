@@ -5,7 +5,6 @@ package bip44
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/skycoin/skycoin/src/cipher/bip32"
 )
@@ -28,6 +27,8 @@ const (
 	CoinTypeBitcoin CoinType = 0
 	// CoinTypeBitcoinTestnet is the coin_type for Skycoin
 	CoinTypeBitcoinTestnet CoinType = 1
+	// CoinTypeEthereum is the coin_type for Ethereum
+	CoinTypeEthereum CoinType = 60
 	// CoinTypeSkycoin is the coin_type for Skycoin
 	CoinTypeSkycoin CoinType = 8000
 
@@ -48,15 +49,7 @@ func NewCoin(seed []byte, coinType CoinType) (*Coin, error) {
 		return nil, ErrInvalidCoinType
 	}
 
-	path := fmt.Sprintf("m/44'/%d'", coinType)
-	pk, err := bip32.NewPrivateKeyFromPath(seed, path)
-	if err != nil {
-		return nil, err
-	}
-
-	return &Coin{
-		pk,
-	}, nil
+	return DefaultPathTemplate(coinType).NewCoin(seed)
 }
 
 // Account creates a bip32 node at the `account'` level of the bip44 path.
@@ -74,3 +74,98 @@ func TestNewCoin(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "02681b301293fdf0292cd679b37d60b92a71b389fd994b2b57c8daf99532bfb4a5", hex.EncodeToString(change1.Key))
 }
+
+func TestParsePathTemplate(t *testing.T) {
+	tt := []struct {
+		name     string
+		template string
+		prefix   string
+		err      error
+	}{
+		{
+			name:     "bip44 skycoin",
+			template: "m/44'/8000'/n'/change/index",
+			prefix:   "m/44'/8000'",
+		},
+		{
+			name:     "non hardened fixed node",
+			template: "m/49'/0'/7/n'/change/index",
+			prefix:   "m/49'/0'/7",
+		},
+		{
+			name:     "accounts of the master node",
+			template: "m/n'/change/index",
+			prefix:   "m",
+		},
+		{
+			name:     "missing index node",
+			template: "m/44'/8000'/n'/change",
+			err:      ErrInvalidPathTemplate,
+		},
+		{
+			name:     "account node not hardened",
+			template: "m/44'/8000'/n/change/index",
+			err:      ErrInvalidPathTemplate,
+		},
+		{
+			name:     "placeholder in fixed nodes",
+			template: "m/44'/n'/n'/change/index",
+			err:      ErrPathTemplatePlaceholder,
+		},
+		{
+			name:     "no master node",
+			template: "44'/8000'/n'/change/index",
+			err:      bip32.ErrPathNoMaster,
+		},
+		{
+			name:     "invalid fixed node",
+			template: "m/44'/foo'/n'/change/index",
+			err:      bip32.ErrPathNodeNotNumber,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			pt, err := ParsePathTemplate(tc.template)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.prefix, pt.Prefix)
+			require.Equal(t, tc.template, pt.String())
+		})
+	}
+}
+
+func TestPathTemplateNewCoin(t *testing.T) {
+	seed := mustDefaultSeed(t)
+
+	// The default path template derives the same nodes as NewCoin
+	pt := DefaultPathTemplate(CoinTypeBitcoin)
+	require.Equal(t, "m/44'/0'/n'/change/index", pt.String())
+
+	c, err := pt.NewCoin(seed)
+	require.NoError(t, err)
+	account, err := c.Account(0)
+	require.NoError(t, err)
+	require.Equal(t, "xprv9yKAFQtFghZSe4mfdpdqFm1WWmGeQbYMB4MSGUB85zbKGQgSxty4duZb8k6hNoHVd2UR7Y3QhWU3rS9wox9ewgVG7gDLyYTL4yzEuqUCjvF", account.String())
+
+	// The nodes of a custom path template match the bip32 path
+	pt2, err := ParsePathTemplate("m/49'/8000'/3/n'/change/index")
+	require.NoError(t, err)
+
+	c, err = pt2.NewCoin(seed)
+	require.NoError(t, err)
+	account, err = c.Account(2)
+	require.NoError(t, err)
+	change, err := account.Change()
+	require.NoError(t, err)
+	k, err := change.NewPrivateChildKey(5)
+	require.NoError(t, err)
+
+	require.Equal(t, "m/49'/8000'/3/2'/1/5", pt2.Path(2, ChangeChainIndex, 5))
+	k2, err := bip32.NewPrivateKeyFromPath(seed, pt2.Path(2, ChangeChainIndex, 5))
+	require.NoError(t, err)
+	require.Equal(t, k2.String(), k.String())
+}
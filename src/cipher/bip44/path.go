@@ -0,0 +1,93 @@
+package bip44
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+const (
+	// PathTemplateAccount is the placeholder of the hardened account node in a path template
+	PathTemplateAccount = "n'"
+	// PathTemplateChange is the placeholder of the chain node in a path template
+	PathTemplateChange = "change"
+	// PathTemplateIndex is the placeholder of the address index node in a path template
+	PathTemplateIndex = "index"
+)
+
+var (
+	// ErrInvalidPathTemplate is returned if a path template does not end with the account, change and index nodes
+	ErrInvalidPathTemplate = fmt.Errorf("Path template must end with /%s/%s/%s", PathTemplateAccount, PathTemplateChange, PathTemplateIndex)
+
+	// ErrPathTemplatePlaceholder is returned if a placeholder appears in the fixed nodes of a path template
+	ErrPathTemplatePlaceholder = errors.New("Path template placeholders can only be used in the last three nodes")
+)
+
+// PathTemplate is a bip32 derivation path template with the bip44 account structure,
+// m / <fixed nodes> / n' / change / index, e.g. m/44'/8000'/n'/change/index.
+// The fixed nodes replace the `purpose' / coin_type'` part of the bip44 path.
+type PathTemplate struct {
+	// Prefix is the path of the fixed nodes, the parent of the account nodes
+	Prefix string
+}
+
+// DefaultPathTemplate returns the bip44 path template of the coin type
+func DefaultPathTemplate(coinType CoinType) PathTemplate {
+	return PathTemplate{
+		Prefix: fmt.Sprintf("m/44'/%d'", coinType),
+	}
+}
+
+// ParsePathTemplate parses a path template, e.g. m/44'/8000'/n'/change/index
+func ParsePathTemplate(s string) (*PathTemplate, error) {
+	pts := strings.Split(s, "/")
+	if len(pts) < 4 {
+		return nil, ErrInvalidPathTemplate
+	}
+
+	n := len(pts)
+	if pts[n-3] != PathTemplateAccount || pts[n-2] != PathTemplateChange || pts[n-1] != PathTemplateIndex {
+		return nil, ErrInvalidPathTemplate
+	}
+
+	prefix := strings.Join(pts[:n-3], "/")
+	for _, p := range pts[:n-3] {
+		switch strings.TrimSuffix(p, "'") {
+		case "n", PathTemplateChange, PathTemplateIndex:
+			return nil, ErrPathTemplatePlaceholder
+		}
+	}
+
+	if _, err := bip32.ParsePath(prefix); err != nil {
+		return nil, err
+	}
+
+	return &PathTemplate{
+		Prefix: prefix,
+	}, nil
+}
+
+// String returns the path template string
+func (t PathTemplate) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", t.Prefix, PathTemplateAccount, PathTemplateChange, PathTemplateIndex)
+}
+
+// Path returns the bip32 path of the address of the account, chain and index
+func (t PathTemplate) Path(account, chain, index uint32) string {
+	return fmt.Sprintf("%s/%d'/%d/%d", t.Prefix, account, chain, index)
+}
+
+// NewCoin creates the bip32 node of the fixed nodes of the path template,
+// the accounts are derived from it with Coin.Account
+func (t PathTemplate) NewCoin(seed []byte) (*Coin, error) {
+	pk, err := bip32.NewPrivateKeyFromPath(seed, t.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coin{
+		pk,
+	}, nil
+}
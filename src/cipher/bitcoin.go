@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
 )
 
 var (
@@ -17,6 +18,8 @@ var (
 	ErrBitcoinWIFInvalidSuffix = errors.New("Bitcoin WIF: Invalid 33rd byte")
 	// ErrBitcoinWIFInvalidChecksum Invalid Checksum in Bitcoin WIF address
 	ErrBitcoinWIFInvalidChecksum = errors.New("Bitcoin WIF: Checksum fail")
+	// ErrBitcoinWIFInvalidLength Unexpected length of Bitcoin Wallet Import Format data
+	ErrBitcoinWIFInvalidLength = errors.New("Bitcoin WIF: Invalid length")
 )
 
 // BitcoinAddress is a bitcoin address
@@ -53,13 +56,27 @@ func MustBitcoinAddressFromSecKey(secKey SecKey) BitcoinAddress {
 	return BitcoinAddressFromPubKey(MustPubKeyFromSecKey(secKey))
 }
 
-// DecodeBase58BitcoinAddress creates a BitcoinAddress from its base58 encoding
+// DecodeBase58BitcoinAddress creates a BitcoinAddress from its base58 encoding.
+// Like DecodeBase58Address, a probable typo is reported with a base58check.TypoError.
 func DecodeBase58BitcoinAddress(addr string) (BitcoinAddress, error) {
 	b, err := base58.Decode(addr)
-	if err != nil {
+	if err == nil {
+		var a BitcoinAddress
+		a, err = BitcoinAddressFromBytes(b)
+		if err == nil {
+			return a, nil
+		}
+	}
+
+	switch err {
+	case base58.ErrInvalidChar, ErrAddressInvalidChecksum:
+		return BitcoinAddress{}, base58check.Hint(addr, err, func(b []byte) bool {
+			_, err := BitcoinAddressFromBytes(b)
+			return err == nil
+		})
+	default:
 		return BitcoinAddress{}, err
 	}
-	return BitcoinAddressFromBytes(b)
 }
 
 // MustDecodeBase58BitcoinAddress creates a BitcoinAddress from its base58 encoding, panics on error
@@ -140,10 +157,7 @@ func (addr BitcoinAddress) String() string {
 // Checksum returns a bitcoin address Checksum which is the first 4 bytes of sha256(sha256(version+key))
 func (addr BitcoinAddress) Checksum() Checksum {
 	r1 := append([]byte{addr.Version}, addr.Key[:]...)
-	r2 := DoubleSHA256(r1[:])
-	c := Checksum{}
-	copy(c[:], r2[:len(c)])
-	return c
+	return base58check.DoubleSHA256Checksum(r1)
 }
 
 // BitcoinWalletImportFormatFromSeckey exports seckey in wallet import format
@@ -185,6 +199,47 @@ func SecKeyFromBitcoinWalletImportFormat(input string) (SecKey, error) {
 	return NewSecKey(b[1:33])
 }
 
+// SecKeyFromBitcoinWIF extracts a seckey from the bitcoin wallet import format, for a compressed
+// or an uncompressed public key. SecKeyFromBitcoinWalletImportFormat only accepts compressed keys.
+// Returns true if the key is for a compressed public key.
+func SecKeyFromBitcoinWIF(input string) (SecKey, bool, error) {
+	b, err := base58.Decode(input)
+	if err != nil {
+		return SecKey{}, false, err
+	}
+
+	// 1+32+4 uncompressed, 1+32+1+4 compressed
+	var compressed bool
+	switch len(b) {
+	case 37:
+	case 38:
+		compressed = true
+	default:
+		return SecKey{}, false, ErrBitcoinWIFInvalidLength
+	}
+
+	if b[0] != 0x80 {
+		return SecKey{}, false, ErrBitcoinWIFInvalidFirstByte
+	}
+
+	if compressed && b[1+32] != 0x01 {
+		return SecKey{}, false, ErrBitcoinWIFInvalidSuffix
+	}
+
+	if err := base58check.Verify(b, base58check.DoubleSHA256Checksum); err != nil {
+		return SecKey{}, false, base58check.Hint(input, ErrBitcoinWIFInvalidChecksum, func(b []byte) bool {
+			return (len(b) == 37 || len(b) == 38) && b[0] == 0x80 && base58check.Verify(b, base58check.DoubleSHA256Checksum) == nil
+		})
+	}
+
+	seckey, err := NewSecKey(b[1:33])
+	if err != nil {
+		return SecKey{}, false, err
+	}
+
+	return seckey, compressed, nil
+}
+
 // MustSecKeyFromBitcoinWalletImportFormat extracts a seckey from the bitcoin wallet import format, panics on error
 func MustSecKeyFromBitcoinWalletImportFormat(input string) SecKey {
 	seckey, err := SecKeyFromBitcoinWalletImportFormat(input)
@@ -0,0 +1,131 @@
+package cipher
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/skycoin/skycoin/src/cipher/bech32"
+)
+
+// BitcoinBech32HRP is the human readable part of bitcoin mainnet bech32 addresses
+const BitcoinBech32HRP = "bc"
+
+// BitcoinBech32Address is a bitcoin native segwit pay-to-witness-pubkey-hash (P2WPKH) address,
+// which is encoded with bech32 (BIP173) as a witness version 0 program
+type BitcoinBech32Address struct {
+	Key Ripemd160 // 20 byte pubkey hash
+}
+
+// BitcoinBech32AddressFromPubKey creates a mainnet BitcoinBech32Address from PubKey as ripemd160(sha256(pubkey)))
+func BitcoinBech32AddressFromPubKey(pubKey PubKey) BitcoinBech32Address {
+	return BitcoinBech32Address{
+		Key: BitcoinPubKeyRipemd160(pubKey),
+	}
+}
+
+// BitcoinBech32AddressFromSecKey generates a BitcoinBech32Address from SecKey
+func BitcoinBech32AddressFromSecKey(secKey SecKey) (BitcoinBech32Address, error) {
+	p, err := PubKeyFromSecKey(secKey)
+	if err != nil {
+		return BitcoinBech32Address{}, err
+	}
+	return BitcoinBech32AddressFromPubKey(p), nil
+}
+
+// MustBitcoinBech32AddressFromSecKey generates a BitcoinBech32Address from SecKey, panics on error
+func MustBitcoinBech32AddressFromSecKey(secKey SecKey) BitcoinBech32Address {
+	return BitcoinBech32AddressFromPubKey(MustPubKeyFromSecKey(secKey))
+}
+
+// DecodeBech32BitcoinAddress creates a BitcoinBech32Address from its bech32 encoding.
+// Only mainnet P2WPKH addresses are accepted.
+func DecodeBech32BitcoinAddress(addr string) (BitcoinBech32Address, error) {
+	version, program, err := bech32.DecodeSegwitAddress(BitcoinBech32HRP, addr)
+	if err != nil {
+		return BitcoinBech32Address{}, err
+	}
+
+	if version != 0 {
+		return BitcoinBech32Address{}, ErrAddressInvalidVersion
+	}
+
+	// 32 byte programs are P2WSH script hashes, which are not pubkey addresses
+	if len(program) != 20 {
+		return BitcoinBech32Address{}, ErrAddressInvalidLength
+	}
+
+	a := BitcoinBech32Address{}
+	copy(a.Key[:], program)
+	return a, nil
+}
+
+// MustDecodeBech32BitcoinAddress creates a BitcoinBech32Address from its bech32 encoding, panics on error
+func MustDecodeBech32BitcoinAddress(addr string) BitcoinBech32Address {
+	a, err := DecodeBech32BitcoinAddress(addr)
+	if err != nil {
+		log.Panicf("Invalid bitcoin bech32 address %s: %v", addr, err)
+	}
+	return a
+}
+
+// BitcoinBech32AddressFromBytes converts the witness output script []byte to a BitcoinBech32Address
+func BitcoinBech32AddressFromBytes(b []byte) (BitcoinBech32Address, error) {
+	if len(b) != 20+2 {
+		return BitcoinBech32Address{}, ErrAddressInvalidLength
+	}
+
+	if !bytes.Equal(b[:2], []byte{0x00, 0x14}) {
+		return BitcoinBech32Address{}, ErrAddressInvalidVersion
+	}
+
+	a := BitcoinBech32Address{}
+	copy(a.Key[:], b[2:])
+	return a, nil
+}
+
+// Null returns true if the address is null (0x0000....)
+func (addr BitcoinBech32Address) Null() bool {
+	return addr == BitcoinBech32Address{}
+}
+
+// Bytes returns the witness output script of the address, the witness version 0 opcode,
+// the push of 20 bytes and the pubkey hash
+func (addr BitcoinBech32Address) Bytes() []byte {
+	b := make([]byte, 20+2)
+	b[0] = 0x00
+	b[1] = 0x14
+	copy(b[2:], addr.Key[:])
+	return b
+}
+
+// Verify checks that the bitcoin bech32 address appears valid for the public key
+func (addr BitcoinBech32Address) Verify(key PubKey) error {
+	if addr.Key != BitcoinPubKeyRipemd160(key) {
+		return ErrAddressInvalidPubKey
+	}
+	return nil
+}
+
+// String returns the lower case bech32 encoding of the address, e.g. bc1q...
+func (addr BitcoinBech32Address) String() string {
+	s, err := bech32.EncodeSegwitAddress(BitcoinBech32HRP, 0, addr.Key[:])
+	if err != nil {
+		log.Panic(err)
+	}
+	return s
+}
+
+// Checksum returns the 30 bit bech32 checksum of the address, big endian in the 4 bytes
+func (addr BitcoinBech32Address) Checksum() Checksum {
+	data, err := bech32.ConvertBits(addr.Key[:], 8, 5, true)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	var v uint32
+	for _, d := range bech32.Checksum(BitcoinBech32HRP, append([]byte{0}, data...)) {
+		v = v<<5 | uint32(d)
+	}
+
+	return Checksum{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
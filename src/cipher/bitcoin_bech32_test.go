@@ -0,0 +1,130 @@
+package cipher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher/bech32"
+)
+
+func TestBitcoinBech32Address(t *testing.T) {
+	cases := []struct {
+		seckey string
+		legacy string
+		addr   string
+	}{
+		{
+			seckey: "0000000000000000000000000000000000000000000000000000000000000001",
+			legacy: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+			addr:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			seckey: "1111111111111111111111111111111111111111111111111111111111111111",
+			legacy: "1Q1pE5vPGEEMqRcVRMbtBK842Y6Pzo6nK9",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.legacy, func(t *testing.T) {
+			seckey := MustSecKeyFromHex(tc.seckey)
+			pubkey := MustPubKeyFromSecKey(seckey)
+
+			addr := BitcoinBech32AddressFromPubKey(pubkey)
+			require.NoError(t, addr.Verify(pubkey))
+			require.False(t, addr.Null())
+
+			// The bech32 address has the pubkey hash of the legacy address
+			require.Equal(t, MustDecodeBase58BitcoinAddress(tc.legacy).Key, addr.Key)
+			if tc.addr != "" {
+				require.Equal(t, tc.addr, addr.String())
+			}
+
+			secAddr, err := BitcoinBech32AddressFromSecKey(seckey)
+			require.NoError(t, err)
+			require.Equal(t, addr, secAddr)
+			require.Equal(t, addr, MustBitcoinBech32AddressFromSecKey(seckey))
+
+			decoded, err := DecodeBech32BitcoinAddress(addr.String())
+			require.NoError(t, err)
+			require.Equal(t, addr, decoded)
+
+			decoded, err = DecodeBech32BitcoinAddress(strings.ToUpper(addr.String()))
+			require.NoError(t, err)
+			require.Equal(t, addr, decoded)
+
+			b := addr.Bytes()
+			require.Len(t, b, 22)
+			fromBytes, err := BitcoinBech32AddressFromBytes(b)
+			require.NoError(t, err)
+			require.Equal(t, addr, fromBytes)
+
+			// The checksum has the 5 bit values of the last 6 characters
+			s := addr.String()
+			var v uint32
+			for _, c := range s[len(s)-6:] {
+				v = v<<5 | uint32(strings.IndexRune("qpzry9x8gf2tvdw0s3jn54khce6mua7l", c))
+			}
+			require.Equal(t, Checksum{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}, addr.Checksum())
+
+			p, _ := GenerateKeyPair()
+			require.Equal(t, ErrAddressInvalidPubKey, addr.Verify(p))
+		})
+	}
+}
+
+func TestDecodeBech32BitcoinAddress(t *testing.T) {
+	cases := []struct {
+		name string
+		addr string
+		err  error
+	}{
+		{
+			name: "testnet",
+			addr: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			err:  bech32.ErrInvalidHRP,
+		},
+		{
+			name: "invalid checksum",
+			addr: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+			err:  bech32.ErrInvalidChecksum,
+		},
+		{
+			name: "p2wsh",
+			addr: "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3",
+			err:  ErrAddressInvalidLength,
+		},
+		{
+			name: "base58",
+			addr: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+			err:  bech32.ErrMixedCase,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeBech32BitcoinAddress(tc.addr)
+			require.Equal(t, tc.err, err)
+
+			require.Panics(t, func() {
+				MustDecodeBech32BitcoinAddress(tc.addr)
+			})
+		})
+	}
+}
+
+func TestBitcoinBech32AddressFromBytes(t *testing.T) {
+	p, _ := GenerateKeyPair()
+	addr := BitcoinBech32AddressFromPubKey(p)
+
+	_, err := BitcoinBech32AddressFromBytes(addr.Bytes()[:21])
+	require.Equal(t, ErrAddressInvalidLength, err)
+
+	b := addr.Bytes()
+	b[0] = 0x51
+	_, err = BitcoinBech32AddressFromBytes(b)
+	require.Equal(t, ErrAddressInvalidVersion, err)
+
+	require.True(t, BitcoinBech32Address{}.Null())
+}
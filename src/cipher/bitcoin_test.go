@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/encoder/base58check"
 )
 
 func TestBitcoinAddress(t *testing.T) {
@@ -331,6 +332,106 @@ func TestBitcoinWIFFailures(t *testing.T) {
 	require.Equal(t, errors.New("Bitcoin WIF: Checksum fail"), err)
 }
 
+func TestSecKeyFromBitcoinWIF(t *testing.T) {
+	seckey := MustSecKeyFromHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+
+	cases := []struct {
+		name       string
+		wif        string
+		compressed bool
+		err        error
+	}{
+		{
+			name: "uncompressed",
+			wif:  "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		},
+		{
+			name:       "compressed",
+			wif:        "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617",
+			compressed: true,
+		},
+		{
+			name: "invalid base58",
+			wif:  "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyT0",
+			err:  base58.ErrInvalidChar,
+		},
+		{
+			name: "invalid length",
+			wif:  string(base58.Encode(randBytes(t, 36))),
+			err:  ErrBitcoinWIFInvalidLength,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sk, compressed, err := SecKeyFromBitcoinWIF(tc.wif)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, seckey, sk)
+			require.Equal(t, tc.compressed, compressed)
+		})
+	}
+
+	// A mistyped character is reported with the correction
+	_, _, err := SecKeyFromBitcoinWIF("5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTK")
+	typoErr, ok := err.(base58check.TypoError)
+	require.True(t, ok)
+	require.Equal(t, ErrBitcoinWIFInvalidChecksum, typoErr.Err)
+	require.Equal(t, []string{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"}, typoErr.Corrections)
+}
+
+func TestSecKeyFromMiniPrivateKey(t *testing.T) {
+	cases := []struct {
+		name   string
+		key    string
+		seckey string
+		err    error
+	}{
+		{
+			name:   "30 characters",
+			key:    "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy",
+			seckey: "4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab",
+		},
+		{
+			name: "typo",
+			key:  "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRz",
+			err:  ErrMiniPrivateKeyInvalidChecksum,
+		},
+		{
+			name: "no S prefix",
+			key:  "T6c56bnXQiBjk9mqSYE7ykVQ7NzrRy",
+			err:  ErrMiniPrivateKeyInvalid,
+		},
+		{
+			name: "invalid length",
+			key:  "S6c56bnXQiBjk9mqSYE7ykVQ7NzrR",
+			err:  ErrMiniPrivateKeyInvalid,
+		},
+		{
+			name: "invalid base58",
+			key:  "S6c56bnXQiBjk9mqSYE7ykVQ7NzrR0",
+			err:  ErrMiniPrivateKeyInvalid,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sk, err := SecKeyFromMiniPrivateKey(tc.key)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.seckey, sk.Hex())
+		})
+	}
+}
+
 func TestMustBitcoinWIFFailures(t *testing.T) {
 	a := " asdio"
 	require.Panics(t, func() {
@@ -0,0 +1,44 @@
+package cipher
+
+import (
+	"errors"
+)
+
+// Compact signatures put the recovery id in a header byte before r and s, as the signed messages of bitcoin:
+// header = compactSigHeaderBase + recovery id + compactSigCompressed, skycoin public keys are always compressed
+const (
+	compactSigHeaderBase = 27
+	compactSigCompressed = 4
+)
+
+var (
+	// ErrInvalidCompactSig the compact signature has an invalid length or header byte
+	ErrInvalidCompactSig = errors.New("Invalid compact signature")
+)
+
+// Compact returns the 65 byte compact encoding of the signature, header || r || s,
+// where the header byte records the recovery id of a compressed public key
+func (s Sig) Compact() []byte {
+	b := make([]byte, len(s))
+	b[0] = compactSigHeaderBase + compactSigCompressed + s.RecoveryID()
+	copy(b[1:], s[:64])
+	return b
+}
+
+// SigFromCompact decodes a compact signature made by Sig.Compact.
+// Only headers of compressed public keys are accepted.
+func SigFromCompact(b []byte) (Sig, error) {
+	var s Sig
+	if len(b) != len(s) {
+		return Sig{}, ErrInvalidCompactSig
+	}
+
+	header := b[0]
+	if header < compactSigHeaderBase+compactSigCompressed || header > compactSigHeaderBase+compactSigCompressed+3 {
+		return Sig{}, ErrInvalidCompactSig
+	}
+
+	copy(s[:64], b[1:])
+	s[64] = header - compactSigHeaderBase - compactSigCompressed
+	return s, nil
+}
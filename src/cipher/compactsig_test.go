@@ -0,0 +1,36 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigCompact(t *testing.T) {
+	_, s := GenerateKeyPair()
+	for i := 0; i < 16; i++ {
+		sig := MustSignHash(SumSHA256(RandByte(32)), s)
+
+		b := sig.Compact()
+		require.Len(t, b, 65)
+		require.Equal(t, byte(31)+sig.RecoveryID(), b[0])
+		require.Equal(t, sig[:64], b[1:])
+
+		sig2, err := SigFromCompact(b)
+		require.NoError(t, err)
+		require.Equal(t, sig, sig2)
+	}
+
+	sig := MustSignHash(SumSHA256([]byte("compact")), s)
+	b := sig.Compact()
+
+	_, err := SigFromCompact(b[:64])
+	require.Equal(t, ErrInvalidCompactSig, err)
+
+	// Headers of uncompressed public keys are rejected
+	for _, h := range []byte{0, 27, 30, 35, 255} {
+		b[0] = h
+		_, err = SigFromCompact(b)
+		require.Equal(t, ErrInvalidCompactSig, err)
+	}
+}
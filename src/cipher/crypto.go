@@ -175,6 +175,35 @@ func MustPubKeyFromSig(sig Sig, hash SHA256) PubKey {
 	return pk
 }
 
+// PubKeyFromSigAndHash recovers the public key of the signer of hash with the recovery id of the signature,
+// and verifies that the signature is well formed and valid for the recovered public key.
+// Unlike PubKeyFromSig, malleable signatures and recovery ids larger than 3 are rejected.
+func PubKeyFromSigAndHash(sig Sig, hash SHA256) (PubKey, error) {
+	if secp256k1.VerifySignatureValidity(sig[:]) != 1 {
+		return PubKey{}, ErrInvalidSigValidity
+	}
+
+	pk, err := PubKeyFromSig(sig, hash)
+	if err != nil {
+		return PubKey{}, err
+	}
+
+	if secp256k1.VerifySignature(hash[:], sig[:], pk[:]) != 1 {
+		return PubKey{}, ErrInvalidHashForSig
+	}
+
+	return pk, nil
+}
+
+// MustPubKeyFromSigAndHash recovers the public key of the signer of hash, panics on error
+func MustPubKeyFromSigAndHash(sig Sig, hash SHA256) PubKey {
+	pk, err := PubKeyFromSigAndHash(sig, hash)
+	if err != nil {
+		log.Panic(err)
+	}
+	return pk
+}
+
 // Verify attempts to determine if pubkey is valid. Returns nil on success
 func (pk PubKey) Verify() error {
 	if secp256k1.VerifyPubkey(pk[:]) != 1 {
@@ -359,7 +388,14 @@ func (s Sig) Hex() string {
 	return hex.EncodeToString(s[:])
 }
 
-// SignHash sign hash
+// RecoveryID returns the recovery id of the signature, which selects the signer's public key
+// among the public keys the r and s values of the signature are valid for
+func (s Sig) RecoveryID() byte {
+	return s[64]
+}
+
+// SignHash sign hash. The signature is deterministic, its nonce is generated
+// from the hash and secret key as specified by RFC6979, see RFC6979Nonce
 func SignHash(hash SHA256, sec SecKey) (Sig, error) {
 	if secp256k1.VerifySeckey(sec[:]) != 1 {
 		// Can't use sec.Verify() because that calls SignHash again, with DebugLevel2 set
@@ -371,13 +407,15 @@ func SignHash(hash SHA256, sec SecKey) (Sig, error) {
 		return Sig{}, ErrNullSignHash
 	}
 
-	s := secp256k1.Sign(hash[:], sec[:])
+	s, attempt := secp256k1.SignRFC6979(hash[:], sec[:])
 
 	sig, err := NewSig(s)
 	if err != nil {
 		return Sig{}, err
 	}
 
+	recordNonce(sig, hash, sec, attempt)
+
 	if DebugLevel2 || DebugLevel1 {
 		// Guard against coin loss;
 		// if the generated signature is somehow invalid, coins would be lost,
@@ -550,22 +588,55 @@ func DeterministicKeyPairIterator(seed []byte) ([]byte, PubKey, SecKey, error) {
 		return nil, PubKey{}, SecKey{}, ErrEmptySeed
 	}
 
-	hash, public, secret := secp256k1.DeterministicKeyPairIterator(seed)
+	hash := secp256k1.Secp256k1Hash(seed)
+	pubKey, secKey, err := DeterministicKeyPairFromSeeds(seed, hash)
+	if err != nil {
+		return nil, PubKey{}, SecKey{}, err
+	}
+
+	return hash, pubKey, secKey, nil
+}
+
+// DeterministicKeyPairSeeds returns the sequence of n+1 SHA256 values that
+// DeterministicKeyPairIterator is fed with to generate n keys, starting with the initial seed.
+// The last value is the new seed. The key pair i is DeterministicKeyPairFromSeeds(seeds[i], seeds[i+1]),
+// so that the key pairs can be generated concurrently.
+func DeterministicKeyPairSeeds(seed []byte, n int) ([][]byte, error) {
+	if len(seed) == 0 {
+		return nil, ErrEmptySeed
+	}
+
+	seeds := make([][]byte, n+1)
+	seeds[0] = seed
+	for i := 0; i < n; i++ {
+		seeds[i+1] = secp256k1.Secp256k1Hash(seeds[i])
+	}
+	return seeds, nil
+}
+
+// DeterministicKeyPairFromSeeds returns the public key and private key that DeterministicKeyPairIterator
+// returns for seed, next is the SHA256 value that DeterministicKeyPairIterator returns for seed
+func DeterministicKeyPairFromSeeds(seed, next []byte) (PubKey, SecKey, error) {
+	if len(seed) == 0 {
+		return PubKey{}, SecKey{}, ErrEmptySeed
+	}
+
+	public, secret := secp256k1.DeterministicKeyPairFromSeeds(seed, next)
 
 	secKey := MustNewSecKey(secret)
 	pubKey := MustNewPubKey(public)
 
 	if DebugLevel1 {
 		if err := CheckSecKey(secKey); err != nil {
-			log.Panicf("DebugLevel1, DeterministicKeyPairIterator, CheckSecKey failed: %v", err)
+			log.Panicf("DebugLevel1, DeterministicKeyPairFromSeeds, CheckSecKey failed: %v", err)
 		}
 
 		if MustPubKeyFromSecKey(secKey) != pubKey {
-			log.Panic("DebugLevel1, DeterministicKeyPairIterator, public key does not match private key")
+			log.Panic("DebugLevel1, DeterministicKeyPairFromSeeds, public key does not match private key")
 		}
 	}
 
-	return hash, pubKey, secKey, nil
+	return pubKey, secKey, nil
 }
 
 // MustDeterministicKeyPairIterator takes SHA256 value, returns a new
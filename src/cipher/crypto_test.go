@@ -751,3 +751,48 @@ func TestHighSPointSigInvalid(t *testing.T) {
 	err = VerifySignatureRecoverPubKey(s2, h)
 	require.NoError(t, err)
 }
+
+func TestPubKeyFromSigAndHash(t *testing.T) {
+	p, s := GenerateKeyPair()
+	h := SumSHA256(randBytes(t, 256))
+	sig := MustSignHash(h, s)
+	require.True(t, sig.RecoveryID() < 4)
+
+	p2, err := PubKeyFromSigAndHash(sig, h)
+	require.NoError(t, err)
+	require.Equal(t, p, p2)
+	require.Equal(t, p, MustPubKeyFromSigAndHash(sig, h))
+
+	// Another hash recovers another public key, which is not the signer
+	p3, err := PubKeyFromSigAndHash(sig, SumSHA256(h[:]))
+	if err == nil {
+		require.NotEqual(t, p, p3)
+	}
+
+	// An invalid recovery id
+	badSig := sig
+	badSig[64] = 4
+	_, err = PubKeyFromSigAndHash(badSig, h)
+	require.Equal(t, ErrInvalidSigValidity, err)
+
+	// Another recovery id recovers another public key, or none
+	badSig[64] = sig.RecoveryID() ^ 1
+	p4, err := PubKeyFromSigAndHash(badSig, h)
+	if err == nil {
+		require.NotEqual(t, p, p4)
+	}
+
+	_, err = PubKeyFromSigAndHash(Sig{}, h)
+	require.Equal(t, ErrInvalidSigPubKeyRecovery, err)
+	require.Panics(t, func() {
+		MustPubKeyFromSigAndHash(Sig{}, h)
+	})
+
+	// A malleable high S signature is rejected, though PubKeyFromSig recovers a public key from it
+	h = MustSHA256FromHex("DD72CBF2203C1A55A411EEC4404AF2AFB2FE942C434B23EFE46E9F04DA8433CA")
+	highS := MustSigFromHex("8c20a668be1b5a910205de46095023fe4823a3757f4417114168925f28193bffadf317cc256cec28d90d5b2b7e1ce6a45cd5f3b10880ab5f99c389c66177d39a01")
+	_, err = PubKeyFromSig(highS, h)
+	require.NoError(t, err)
+	_, err = PubKeyFromSigAndHash(highS, h)
+	require.Equal(t, ErrInvalidSigValidity, err)
+}
@@ -0,0 +1,104 @@
+package cipher
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"errors"
+	"log"
+)
+
+// ECIES ciphertext layout: ephemeral pubkey || nonce || AES-256-GCM sealed plaintext
+const (
+	eciesNonceSize = 12
+	eciesTagSize   = 16
+	// ECIESOverhead is the number of bytes the ECIES ciphertext is longer than the plaintext
+	ECIESOverhead = len(PubKey{}) + eciesNonceSize + eciesTagSize
+)
+
+var (
+	// ErrInvalidLengthECIESCiphertext ECIES ciphertext is too short
+	ErrInvalidLengthECIESCiphertext = errors.New("Invalid ECIES ciphertext length")
+	// ErrECIESDecrypt ECIES ciphertext authentication failed, it was not encrypted to the pubkey of the seckey or was modified
+	ErrECIESDecrypt = errors.New("ECIES decryption failed")
+)
+
+// ECIESEncrypt encrypts the plaintext to the pubkey with ECIES over secp256k1 and AES-256-GCM.
+// A new ephemeral key pair is generated for each message, the AES key is derived from the ECDH
+// shared secret of the ephemeral seckey and the pubkey. Only the seckey of pubkey can decrypt it with ECIESDecrypt.
+func ECIESEncrypt(pubkey PubKey, plaintext []byte) ([]byte, error) {
+	ephPub, ephSec := GenerateKeyPair()
+
+	key, err := eciesKey(pubkey, ephSec, ephPub)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newECIESAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := RandByte(eciesNonceSize)
+
+	out := make([]byte, 0, len(plaintext)+ECIESOverhead)
+	out = append(out, ephPub[:]...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, ephPub[:]), nil
+}
+
+// MustECIESEncrypt calls ECIESEncrypt and panics on error
+func MustECIESEncrypt(pubkey PubKey, plaintext []byte) []byte {
+	b, err := ECIESEncrypt(pubkey, plaintext)
+	if err != nil {
+		log.Panic(err)
+	}
+	return b
+}
+
+// ECIESDecrypt decrypts the ciphertext encrypted to the pubkey of seckey by ECIESEncrypt
+func ECIESDecrypt(seckey SecKey, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < ECIESOverhead {
+		return nil, ErrInvalidLengthECIESCiphertext
+	}
+
+	ephPub, err := NewPubKey(ciphertext[:len(PubKey{})])
+	if err != nil {
+		return nil, ErrECIESDecrypt
+	}
+
+	key, err := eciesKey(ephPub, seckey, ephPub)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newECIESAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := ciphertext[len(PubKey{}) : len(PubKey{})+eciesNonceSize]
+	plaintext, err := aead.Open(nil, nonce, ciphertext[len(PubKey{})+eciesNonceSize:], ephPub[:])
+	if err != nil {
+		return nil, ErrECIESDecrypt
+	}
+	return plaintext, nil
+}
+
+// eciesKey derives the AES key from the ECDH shared secret of pub and sec, bound to the ephemeral pubkey
+func eciesKey(pub PubKey, sec SecKey, ephPub PubKey) ([]byte, error) {
+	shared, err := ECDH(pub, sec)
+	if err != nil {
+		return nil, err
+	}
+
+	key := SumSHA256(append(shared, ephPub[:]...))
+	return key[:], nil
+}
+
+func newECIESAEAD(key []byte) (gocipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return gocipher.NewGCM(block)
+}
@@ -0,0 +1,54 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestECIES(t *testing.T) {
+	p, s := GenerateKeyPair()
+
+	for _, n := range []int{0, 1, 32, 1000} {
+		plaintext := randBytes(t, n)
+		ciphertext, err := ECIESEncrypt(p, plaintext)
+		require.NoError(t, err)
+		require.Len(t, ciphertext, n+ECIESOverhead)
+
+		decrypted, err := ECIESDecrypt(s, ciphertext)
+		require.NoError(t, err)
+		require.Equal(t, len(plaintext), len(decrypted))
+		if n > 0 {
+			require.Equal(t, plaintext, decrypted)
+		}
+
+		// The ciphertexts use a new ephemeral key
+		ciphertext2 := MustECIESEncrypt(p, plaintext)
+		require.NotEqual(t, ciphertext, ciphertext2)
+	}
+
+	ciphertext := MustECIESEncrypt(p, []byte("secret message"))
+
+	// Another seckey can't decrypt
+	_, s2 := GenerateKeyPair()
+	_, err := ECIESDecrypt(s2, ciphertext)
+	require.Equal(t, ErrECIESDecrypt, err)
+
+	// Modified ciphertext fails authentication
+	for _, i := range []int{0, 1, len(PubKey{}), len(ciphertext) - 1} {
+		c := append([]byte{}, ciphertext...)
+		c[i] ^= 1
+		_, err := ECIESDecrypt(s, c)
+		require.Error(t, err)
+	}
+
+	_, err = ECIESDecrypt(s, ciphertext[:ECIESOverhead-1])
+	require.Equal(t, ErrInvalidLengthECIESCiphertext, err)
+
+	_, err = ECIESEncrypt(PubKey{}, []byte("secret message"))
+	require.Equal(t, ErrECHDInvalidPubKey, err)
+	require.Panics(t, func() { MustECIESEncrypt(PubKey{}, nil) })
+
+	_, err = ECIESDecrypt(SecKey{}, ciphertext)
+	require.Equal(t, ErrECHDInvalidSecKey, err)
+}
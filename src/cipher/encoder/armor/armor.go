@@ -0,0 +1,263 @@
+/*
+Package armor implements an ASCII armored encoding of public keys, signatures and xpub keys,
+similar to the OpenPGP ASCII armor of RFC4880, so that they can be pasted in emails and tickets.
+
+An armored block looks like:
+
+	-----BEGIN SKYCOIN PUBLIC KEY-----
+	Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY
+	=c8ec
+	-----END SKYCOIN PUBLIC KEY-----
+
+The data is base64 encoded in lines of at most 64 characters, followed by the base64 encoded
+CRC-24 checksum of the data. Parsing is strict: whitespace is only allowed around the block.
+*/
+package armor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+// Block types
+const (
+	// TypePubKey is the block type of a cipher.PubKey
+	TypePubKey = "SKYCOIN PUBLIC KEY"
+	// TypeSig is the block type of a cipher.Sig
+	TypeSig = "SKYCOIN SIGNATURE"
+	// TypeXPub is the block type of a bip32 xpub key
+	TypeXPub = "SKYCOIN XPUB"
+)
+
+const (
+	beginPrefix = "-----BEGIN "
+	endPrefix   = "-----END "
+	lineSuffix  = "-----"
+
+	// lineLen is the maximum length of a base64 data line
+	lineLen = 64
+
+	crc24Init = 0xb704ce
+	crc24Poly = 0x1864cfb
+)
+
+var (
+	// ErrMissingBegin the armored block does not start with a BEGIN line
+	ErrMissingBegin = errors.New("armor: missing BEGIN line")
+	// ErrMissingEnd the armored block does not end with an END line matching the BEGIN line
+	ErrMissingEnd = errors.New("armor: missing END line")
+	// ErrInvalidType the block type is not valid
+	ErrInvalidType = errors.New("armor: invalid block type")
+	// ErrMissingChecksum the armored block has no checksum line
+	ErrMissingChecksum = errors.New("armor: missing checksum line")
+	// ErrInvalidLine a data line is not valid base64 or has an invalid length
+	ErrInvalidLine = errors.New("armor: invalid data line")
+	// ErrInvalidChecksum the checksum line is not valid
+	ErrInvalidChecksum = errors.New("armor: invalid checksum line")
+	// ErrChecksumMismatch the checksum does not match the data
+	ErrChecksumMismatch = errors.New("armor: checksum mismatch")
+	// ErrEmptyData the armored block has no data
+	ErrEmptyData = errors.New("armor: no data")
+)
+
+// TypeMismatchError is returned when decoding a block of a different type than expected
+type TypeMismatchError struct {
+	Expected string
+	Type     string
+}
+
+func (e TypeMismatchError) Error() string {
+	return fmt.Sprintf("armor: expected block type %q, got %q", e.Expected, e.Type)
+}
+
+// crc24 computes the CRC-24 checksum of data, as specified by RFC4880 section 6.1
+func crc24(data []byte) uint32 {
+	crc := uint32(crc24Init)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= crc24Poly
+			}
+		}
+	}
+	return crc & 0xffffff
+}
+
+func encodeChecksum(data []byte) string {
+	crc := crc24(data)
+	return base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)})
+}
+
+func validType(blockType string) bool {
+	if blockType == "" || strings.TrimSpace(blockType) != blockType {
+		return false
+	}
+	for _, c := range blockType {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode encodes data as an armored block of blockType.
+// blockType must be made of uppercase letters, digits and spaces.
+func Encode(blockType string, data []byte) (string, error) {
+	if !validType(blockType) {
+		return "", ErrInvalidType
+	}
+	if len(data) == 0 {
+		return "", ErrEmptyData
+	}
+
+	var b strings.Builder
+	b.WriteString(beginPrefix + blockType + lineSuffix + "\n")
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > lineLen {
+		b.WriteString(encoded[:lineLen] + "\n")
+		encoded = encoded[lineLen:]
+	}
+	b.WriteString(encoded + "\n")
+
+	b.WriteString("=" + encodeChecksum(data) + "\n")
+	b.WriteString(endPrefix + blockType + lineSuffix + "\n")
+
+	return b.String(), nil
+}
+
+// Decode decodes an armored block, returning its type and data.
+// Whitespace around the block is ignored. Lines may end with "\n" or "\r\n".
+func Decode(s string) (string, []byte, error) {
+	s = strings.TrimSpace(s)
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	lines := strings.Split(s, "\n")
+
+	begin := lines[0]
+	if !strings.HasPrefix(begin, beginPrefix) || !strings.HasSuffix(begin, lineSuffix) ||
+		len(begin) < len(beginPrefix)+len(lineSuffix) {
+		return "", nil, ErrMissingBegin
+	}
+	blockType := begin[len(beginPrefix) : len(begin)-len(lineSuffix)]
+	if !validType(blockType) {
+		return "", nil, ErrInvalidType
+	}
+
+	if len(lines) < 2 || lines[len(lines)-1] != endPrefix+blockType+lineSuffix {
+		return "", nil, ErrMissingEnd
+	}
+	body := lines[1 : len(lines)-1]
+
+	if len(body) < 2 || !strings.HasPrefix(body[len(body)-1], "=") {
+		return "", nil, ErrMissingChecksum
+	}
+	checksumLine := body[len(body)-1][1:]
+	dataLines := body[:len(body)-1]
+
+	// All data lines but the last are full, no line is empty
+	for i, l := range dataLines {
+		if len(l) == 0 || len(l) > lineLen || (i < len(dataLines)-1 && len(l) != lineLen) {
+			return "", nil, ErrInvalidLine
+		}
+	}
+
+	data, err := base64.StdEncoding.Strict().DecodeString(strings.Join(dataLines, ""))
+	if err != nil {
+		return "", nil, ErrInvalidLine
+	}
+	if len(data) == 0 {
+		return "", nil, ErrEmptyData
+	}
+
+	checksum, err := base64.StdEncoding.Strict().DecodeString(checksumLine)
+	if err != nil || len(checksum) != 3 {
+		return "", nil, ErrInvalidChecksum
+	}
+
+	crc := crc24(data)
+	if !bytes.Equal(checksum, []byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}) {
+		return "", nil, ErrChecksumMismatch
+	}
+
+	return blockType, data, nil
+}
+
+// decodeType decodes an armored block that must be of blockType
+func decodeType(s, blockType string) ([]byte, error) {
+	t, data, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if t != blockType {
+		return nil, TypeMismatchError{
+			Expected: blockType,
+			Type:     t,
+		}
+	}
+
+	return data, nil
+}
+
+// EncodePubKey encodes a public key as an armored block
+func EncodePubKey(pk cipher.PubKey) string {
+	s, err := Encode(TypePubKey, pk[:])
+	if err != nil {
+		log.Panic(err)
+	}
+	return s
+}
+
+// DecodePubKey decodes an armored public key block
+func DecodePubKey(s string) (cipher.PubKey, error) {
+	data, err := decodeType(s, TypePubKey)
+	if err != nil {
+		return cipher.PubKey{}, err
+	}
+	return cipher.NewPubKey(data)
+}
+
+// EncodeSig encodes a signature as an armored block
+func EncodeSig(sig cipher.Sig) string {
+	s, err := Encode(TypeSig, sig[:])
+	if err != nil {
+		log.Panic(err)
+	}
+	return s
+}
+
+// DecodeSig decodes an armored signature block
+func DecodeSig(s string) (cipher.Sig, error) {
+	data, err := decodeType(s, TypeSig)
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+	return cipher.NewSig(data)
+}
+
+// EncodeXPub encodes a bip32 public key as an armored block of its serialization
+func EncodeXPub(xpub *bip32.PublicKey) string {
+	s, err := Encode(TypeXPub, xpub.Serialize())
+	if err != nil {
+		log.Panic(err)
+	}
+	return s
+}
+
+// DecodeXPub decodes an armored bip32 public key block
+func DecodeXPub(s string) (*bip32.PublicKey, error) {
+	data, err := decodeType(s, TypeXPub)
+	if err != nil {
+		return nil, err
+	}
+	return bip32.DeserializePublicKey(data)
+}
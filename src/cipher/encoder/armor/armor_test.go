@@ -0,0 +1,206 @@
+package armor
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+func TestCRC24(t *testing.T) {
+	// CRC-24/OpenPGP check value
+	require.Equal(t, uint32(0x21cf02), crc24([]byte("123456789")))
+	require.Equal(t, uint32(crc24Init), crc24(nil))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 47, 48, 49, 96, 200} {
+		data := make([]byte, n)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		s, err := Encode("TEST DATA", data)
+		require.NoError(t, err)
+
+		for _, l := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+			require.True(t, len(l) <= lineLen)
+		}
+
+		blockType, data2, err := Decode(s)
+		require.NoError(t, err)
+		require.Equal(t, "TEST DATA", blockType)
+		require.Equal(t, data, data2)
+
+		// Whitespace around the block and CRLF line endings are accepted
+		blockType, data2, err = Decode("\n  " + strings.Replace(s, "\n", "\r\n", -1) + "\t\n")
+		require.NoError(t, err)
+		require.Equal(t, "TEST DATA", blockType)
+		require.Equal(t, data, data2)
+	}
+
+	_, err := Encode("test", []byte{1})
+	require.Equal(t, ErrInvalidType, err)
+	_, err = Encode("TEST-DATA", []byte{1})
+	require.Equal(t, ErrInvalidType, err)
+	_, err = Encode(" TEST", []byte{1})
+	require.Equal(t, ErrInvalidType, err)
+	_, err = Encode("TEST", nil)
+	require.Equal(t, ErrEmptyData, err)
+}
+
+func TestDecodeStrict(t *testing.T) {
+	pk := cipher.MustPubKeyFromSecKey(cipher.MustSecKeyFromHex("0000000000000000000000000000000000000000000000000000000000000001"))
+	valid := EncodePubKey(pk)
+	require.Equal(t, `-----BEGIN SKYCOIN PUBLIC KEY-----
+Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY
+=c8ec
+-----END SKYCOIN PUBLIC KEY-----
+`, valid)
+
+	data := make([]byte, 100)
+	long, err := Encode(TypePubKey, data)
+	require.NoError(t, err)
+	longLines := strings.Split(long, "\n")
+
+	cases := []struct {
+		name string
+		s    string
+		err  error
+	}{
+		{
+			name: "empty",
+			s:    "",
+			err:  ErrMissingBegin,
+		},
+		{
+			name: "text before block",
+			s:    "key:\n" + valid,
+			err:  ErrMissingBegin,
+		},
+		{
+			name: "lowercase type",
+			s:    strings.Replace(valid, "BEGIN SKYCOIN", "BEGIN skycoin", 1),
+			err:  ErrInvalidType,
+		},
+		{
+			name: "mismatched end",
+			s:    strings.Replace(valid, "END SKYCOIN PUBLIC KEY", "END SKYCOIN SIGNATURE", 1),
+			err:  ErrMissingEnd,
+		},
+		{
+			name: "text after block",
+			s:    valid + "thanks\n",
+			err:  ErrMissingEnd,
+		},
+		{
+			name: "missing checksum",
+			s:    strings.Replace(valid, "=c8ec\n", "", 1),
+			err:  ErrMissingChecksum,
+		},
+		{
+			name: "invalid checksum",
+			s:    strings.Replace(valid, "=c8ec", "=c8e", 1),
+			err:  ErrInvalidChecksum,
+		},
+		{
+			name: "checksum mismatch",
+			s:    strings.Replace(valid, "=c8ec", "=c8ed", 1),
+			err:  ErrChecksumMismatch,
+		},
+		{
+			name: "modified data",
+			s:    strings.Replace(valid, "Anm+", "Anm/", 1),
+			err:  ErrChecksumMismatch,
+		},
+		{
+			name: "invalid base64",
+			s:    strings.Replace(valid, "Anm+", "Anm*", 1),
+			err:  ErrInvalidLine,
+		},
+		{
+			name: "whitespace in data",
+			s:    strings.Replace(valid, "Anm+", "Anm +", 1),
+			err:  ErrInvalidLine,
+		},
+		{
+			name: "blank line",
+			s:    strings.Replace(valid, "-----\nAnm+", "-----\n\nAnm+", 1),
+			err:  ErrInvalidLine,
+		},
+		{
+			name: "short line before last",
+			s:    strings.Join(append([]string{longLines[0], longLines[1][:60], longLines[1][60:]}, longLines[2:]...), "\n"),
+			err:  ErrInvalidLine,
+		},
+		{
+			name: "long line",
+			s:    strings.Join(append([]string{longLines[0], longLines[1] + longLines[2]}, longLines[3:]...), "\n"),
+			err:  ErrInvalidLine,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := Decode(tc.s)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestPubKey(t *testing.T) {
+	pk, sk := cipher.GenerateKeyPair()
+
+	pk2, err := DecodePubKey(EncodePubKey(pk))
+	require.NoError(t, err)
+	require.Equal(t, pk, pk2)
+
+	sig := cipher.MustSignHash(cipher.SumSHA256([]byte("armor")), sk)
+	_, err = DecodePubKey(EncodeSig(sig))
+	require.Equal(t, TypeMismatchError{
+		Expected: TypePubKey,
+		Type:     TypeSig,
+	}, err)
+
+	// A block with the pubkey type but data of the wrong length
+	s, err := Encode(TypePubKey, sig[:])
+	require.NoError(t, err)
+	_, err = DecodePubKey(s)
+	require.Equal(t, cipher.ErrInvalidLengthPubKey, err)
+}
+
+func TestSig(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	sig := cipher.MustSignHash(cipher.SumSHA256([]byte("armor")), sk)
+
+	sig2, err := DecodeSig(EncodeSig(sig))
+	require.NoError(t, err)
+	require.Equal(t, sig, sig2)
+
+	s, err := Encode(TypeSig, sig[:64])
+	require.NoError(t, err)
+	_, err = DecodeSig(s)
+	require.Equal(t, cipher.ErrInvalidLengthSig, err)
+}
+
+func TestXPub(t *testing.T) {
+	master, err := bip32.NewMasterKey([]byte("armor test seed armor test seed!"))
+	require.NoError(t, err)
+	xpub := master.PublicKey()
+
+	s := EncodeXPub(xpub)
+	require.True(t, strings.HasPrefix(s, "-----BEGIN SKYCOIN XPUB-----\n"))
+
+	xpub2, err := DecodeXPub(s)
+	require.NoError(t, err)
+	require.Equal(t, xpub.String(), xpub2.String())
+
+	// xprv keys are not accepted
+	s, err = Encode(TypeXPub, master.Serialize())
+	require.NoError(t, err)
+	_, err = DecodeXPub(s)
+	require.Equal(t, bip32.ErrInvalidPublicKeyVersion, err)
+}
@@ -0,0 +1,74 @@
+package armorfuzz
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher/encoder/armor"
+)
+
+// To use the fuzzer:
+// Follow the install instructions from https://github.com/dvyukov/go-fuzz
+// Then, from the repo root,
+// $ go-fuzz-build github.com/skycoin/skycoin/src/cipher/encoder/armor/internal
+// This creates a file armorfuzz-fuzz.zip
+// Then,
+// $ go-fuzz -bin=armorfuzz-fuzz.zip -workdir=src/cipher/encoder/armor/internal
+// New corpus and crash objects will be put in src/cipher/encoder/armor/internal
+
+// Fuzz is the entrypoint for go-fuzz
+func Fuzz(b []byte) int {
+	encodeDecode(b)
+
+	if decodeEncode(string(b)) {
+		return 1
+	}
+
+	return 0
+}
+
+// decodeEncode decodes s and, if it is valid, checks that it is the canonical encoding of its data,
+// except for the whitespace around the block and CRLF line endings. The typed decoders must not panic.
+func decodeEncode(s string) bool {
+	armor.DecodePubKey(s) //nolint:errcheck
+	armor.DecodeSig(s)    //nolint:errcheck
+	armor.DecodeXPub(s)   //nolint:errcheck
+
+	blockType, data, err := armor.Decode(s)
+	if err != nil {
+		if blockType != "" || data != nil {
+			panic("blockType or data is set on error")
+		}
+		return false
+	}
+
+	s2, err := armor.Encode(blockType, data)
+	if err != nil {
+		panic("decoded block can't be encoded")
+	}
+
+	normalized := strings.Replace(strings.TrimSpace(s), "\r\n", "\n", -1) + "\n"
+	if s2 != normalized {
+		panic("decoded block is not canonical")
+	}
+
+	return true
+}
+
+func encodeDecode(b []byte) {
+	s, err := armor.Encode("FUZZ", b)
+	if err != nil {
+		if len(b) != 0 {
+			panic("encode failed")
+		}
+		return
+	}
+
+	blockType, data, err := armor.Decode(s)
+	if err != nil {
+		panic("decode of encoded data failed")
+	}
+	if blockType != "FUZZ" || !bytes.Equal(data, b) {
+		panic("decoded data is not equal")
+	}
+}
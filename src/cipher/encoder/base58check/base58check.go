@@ -0,0 +1,160 @@
+/*
+Package base58check implements base58 encoding with a 4 byte checksum appended to the data,
+as used by skycoin and bitcoin addresses and bitcoin wallet import format keys.
+
+When a string fails to decode, Corrections finds the strings that differ from it by one character
+and decode successfully, so that callers can report a probable typo.
+*/
+package base58check
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher/base58"
+)
+
+var (
+	// ErrInvalidChecksum the checksum does not match the data
+	ErrInvalidChecksum = errors.New("Invalid checksum")
+	// ErrTooShort the decoded data is shorter than the checksum
+	ErrTooShort = errors.New("base58check data is too short")
+)
+
+// ChecksumLen is the length of the checksum appended to the data
+const ChecksumLen = 4
+
+// alphabet is the bitcoin base58 alphabet, used by the base58 package
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// maxCorrections is the maximum number of corrections that TypoError reports
+const maxCorrections = 3
+
+// ChecksumFunc computes the checksum of data
+type ChecksumFunc func(data []byte) [ChecksumLen]byte
+
+// SHA256Checksum is the first 4 bytes of sha256(data), used by skycoin addresses
+func SHA256Checksum(data []byte) [ChecksumLen]byte {
+	h := sha256.Sum256(data)
+	var c [ChecksumLen]byte
+	copy(c[:], h[:ChecksumLen])
+	return c
+}
+
+// DoubleSHA256Checksum is the first 4 bytes of sha256(sha256(data)), used by bitcoin addresses and keys
+func DoubleSHA256Checksum(data []byte) [ChecksumLen]byte {
+	h := sha256.Sum256(data)
+	h = sha256.Sum256(h[:])
+	var c [ChecksumLen]byte
+	copy(c[:], h[:ChecksumLen])
+	return c
+}
+
+// Encode encodes data with its checksum appended in base58
+func Encode(data []byte, checksum ChecksumFunc) string {
+	c := checksum(data)
+	b := make([]byte, 0, len(data)+ChecksumLen)
+	b = append(b, data...)
+	b = append(b, c[:]...)
+	return base58.Encode(b)
+}
+
+// Decode decodes a base58 string and verifies the checksum at its end, returning the data without the checksum.
+// If the string is invalid but a single character substitution would make it valid,
+// the error is a TypoError with the corrected strings.
+func Decode(s string, checksum ChecksumFunc) ([]byte, error) {
+	data, err := decode(s, checksum)
+	if err != nil {
+		return nil, Hint(s, err, func(b []byte) bool {
+			return Verify(b, checksum) == nil
+		})
+	}
+	return data, nil
+}
+
+func decode(s string, checksum ChecksumFunc) ([]byte, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Verify(b, checksum); err != nil {
+		return nil, err
+	}
+
+	return b[:len(b)-ChecksumLen], nil
+}
+
+// Verify verifies the checksum at the end of b
+func Verify(b []byte, checksum ChecksumFunc) error {
+	if len(b) < ChecksumLen {
+		return ErrTooShort
+	}
+
+	n := len(b) - ChecksumLen
+	var c [ChecksumLen]byte
+	copy(c[:], b[n:])
+	if c != checksum(b[:n]) {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// Corrections returns the strings that differ from s in one character and whose base58 decoding is accepted by valid.
+// valid should verify the checksum of the decoded bytes, and any other constraints on them, e.g. length or version.
+func Corrections(s string, valid func(b []byte) bool) []string {
+	var corrections []string
+	buf := []byte(s)
+	for i := range buf {
+		orig := buf[i]
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == orig {
+				continue
+			}
+
+			buf[i] = alphabet[j]
+			if b, err := base58.Decode(string(buf)); err == nil && valid(b) {
+				corrections = append(corrections, string(buf))
+			}
+		}
+		buf[i] = orig
+	}
+
+	return corrections
+}
+
+// Hint returns a TypoError wrapping err if s has Corrections, otherwise it returns err
+func Hint(s string, err error, valid func(b []byte) bool) error {
+	corrections := Corrections(s, valid)
+	if len(corrections) == 0 {
+		return err
+	}
+
+	return TypoError{
+		Err:         err,
+		Corrections: corrections,
+	}
+}
+
+// TypoError is a decoding error for a string that is probably mistyped.
+// Corrections are the valid strings that differ from the string in one character.
+type TypoError struct {
+	Err         error
+	Corrections []string
+}
+
+func (e TypoError) Error() string {
+	corrections := e.Corrections
+	if len(corrections) > maxCorrections {
+		corrections = corrections[:maxCorrections]
+	}
+	return fmt.Sprintf("%v, a character may be mistyped, did you mean %s?", e.Err, strings.Join(corrections, " or "))
+}
+
+// Unwrap returns the decoding error
+func (e TypoError) Unwrap() error {
+	return e.Err
+}
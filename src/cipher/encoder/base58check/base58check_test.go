@@ -0,0 +1,102 @@
+package base58check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher/base58"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// Skycoin address: ripemd160 + version
+	data := []byte{
+		0x7b, 0x1e, 0x08, 0xe4, 0x06, 0x5d, 0x5c, 0xfa, 0x4d, 0x58,
+		0xaa, 0x6e, 0x2e, 0x3e, 0x9b, 0x36, 0x2f, 0xa6, 0x4c, 0x34, 0x00,
+	}
+
+	s := Encode(data, SHA256Checksum)
+	b, err := Decode(s, SHA256Checksum)
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+
+	// The checksums are different
+	_, err = Decode(s, DoubleSHA256Checksum)
+	require.Equal(t, ErrInvalidChecksum, err)
+
+	s = Encode(data, DoubleSHA256Checksum)
+	b, err = Decode(s, DoubleSHA256Checksum)
+	require.NoError(t, err)
+	require.Equal(t, data, b)
+
+	// Empty data only has a checksum
+	s = Encode(nil, SHA256Checksum)
+	b, err = Decode(s, SHA256Checksum)
+	require.NoError(t, err)
+	require.Empty(t, b)
+
+	_, err = Decode(base58.Encode([]byte{1, 2, 3}), SHA256Checksum)
+	require.Equal(t, ErrTooShort, err)
+
+	_, err = Decode("", SHA256Checksum)
+	require.Equal(t, base58.ErrInvalidString, err)
+}
+
+func TestDecodeTypo(t *testing.T) {
+	valid := "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"
+	_, err := Decode(valid, SHA256Checksum)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		s    string
+		err  error
+	}{
+		{
+			name: "substituted character",
+			s:    "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qw",
+			err:  ErrInvalidChecksum,
+		},
+		{
+			name: "capitalized character",
+			s:    "2GgFvqoyk9RjwVzJ8tqfcXVXB4orBwoc9qv",
+			err:  ErrInvalidChecksum,
+		},
+		{
+			name: "invalid character",
+			s:    "2GgFvqoyk9RjwVzj8tqfcXVXB4orBw0c9qv",
+			err:  base58.ErrInvalidChar,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.s, SHA256Checksum)
+			require.Error(t, err)
+
+			typoErr, ok := err.(TypoError)
+			require.True(t, ok)
+			require.Equal(t, tc.err, typoErr.Err)
+			require.True(t, errors.Is(err, tc.err))
+			require.Contains(t, typoErr.Corrections, valid)
+			require.Contains(t, err.Error(), valid)
+		})
+	}
+
+	// Two typos can not be corrected
+	_, err = Decode("2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9rw", SHA256Checksum)
+	require.Equal(t, ErrInvalidChecksum, err)
+}
+
+func TestCorrections(t *testing.T) {
+	valid := "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"
+	require.Empty(t, Corrections(valid, func(b []byte) bool {
+		return false
+	}))
+
+	corrections := Corrections("2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qw", func(b []byte) bool {
+		return Verify(b, SHA256Checksum) == nil
+	})
+	require.Equal(t, []string{valid}, corrections)
+}
@@ -0,0 +1,200 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/argon2"
+	"github.com/skycoin/skycoin/src/cipher/chacha20poly1305"
+)
+
+const (
+	argon2idChacha20MetaLengthSize = 2  // meta data length field size in bytes
+	argon2idChacha20SaltSize       = 32 // salt bytes number
+)
+
+// Default argon2id paramenters, as recommended by RFC 9106 for memory constrained environments
+const (
+	// Argon2idTime: argon2id number of passes over the memory.
+	Argon2idTime = 3
+	// Argon2idMemory: argon2id memory size in KiB, 64 MiB.
+	Argon2idMemory = 64 * 1024
+	// Argon2idThreads: argon2id degree of parallelism.
+	Argon2idThreads = 4
+	// Argon2idKeyLen: The length of returned byte slice that can be used as cryptographic key.
+	Argon2idKeyLen = 32
+)
+
+// Maximum argon2id parameters. The parameters are read from the encrypted data when decrypting,
+// so they are bounded to keep a crafted ciphertext from using unbounded memory or CPU time.
+const (
+	// Argon2idMaxTime: maximum argon2id number of passes over the memory.
+	Argon2idMaxTime = 64
+	// Argon2idMaxMemory: maximum argon2id memory size in KiB, 4 GiB.
+	Argon2idMaxMemory = 4 * 1024 * 1024
+	// Argon2idMaxThreads: maximum argon2id degree of parallelism.
+	Argon2idMaxThreads = 64
+)
+
+// DefaultArgon2idChacha20poly1305 default Argon2idChacha20poly1305 encryptor
+var DefaultArgon2idChacha20poly1305 = Argon2idChacha20poly1305{
+	Time:    Argon2idTime,
+	Memory:  Argon2idMemory,
+	Threads: Argon2idThreads,
+	KeyLen:  Argon2idKeyLen,
+}
+
+// Argon2idChacha20poly1305 provides methods for encryption/decryption with argon2id and chacha20poly1305
+type Argon2idChacha20poly1305 struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+}
+
+type argon2idMeta struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	KeyLen  uint32 `json:"keyLen"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+}
+
+func (m argon2idMeta) validate() error {
+	if m.Time == 0 {
+		return errors.New("argon2id time must be > 0")
+	}
+	if m.Time > Argon2idMaxTime {
+		return fmt.Errorf("argon2id time must be <= %d", Argon2idMaxTime)
+	}
+	if m.Threads == 0 {
+		return errors.New("argon2id threads must be > 0")
+	}
+	if m.Threads > Argon2idMaxThreads {
+		return fmt.Errorf("argon2id threads must be <= %d", Argon2idMaxThreads)
+	}
+	if m.Memory < 8*uint32(m.Threads) {
+		return errors.New("argon2id memory must be at least 8*threads KiB")
+	}
+	if m.Memory > Argon2idMaxMemory {
+		return fmt.Errorf("argon2id memory must be <= %d KiB", Argon2idMaxMemory)
+	}
+	if m.KeyLen != chacha20poly1305.KeySize {
+		return errors.New("argon2id key length must be the chacha20poly1305 key size")
+	}
+	return nil
+}
+
+// Encrypt encrypts data with password,
+// 1. Argon2id derives the key from password
+// 2. Chacha20poly1305 generates AEAD from the derived key
+// 3. Puts argon2id paramenters, salt and nonce into metadata, json serialize it and get the serialized metadata length
+// 4. AEAD.Seal encrypts the data, and use [length][metadata] as additional data
+// 5. Final format: base64([[length][metadata]][ciphertext]), length is 2 bytes.
+func (a Argon2idChacha20poly1305) Encrypt(data, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("missing password")
+	}
+
+	// Prepare metadata
+	m := argon2idMeta{
+		Time:    a.Time,
+		Memory:  a.Memory,
+		Threads: a.Threads,
+		KeyLen:  a.KeyLen,
+		Salt:    cipher.RandByte(argon2idChacha20SaltSize),
+		Nonce:   cipher.RandByte(chacha20poly1305.NonceSize),
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	// Argon2id derives key from password
+	dk := argon2.IDKey(password, m.Salt, m.Time, m.Memory, m.Threads, m.KeyLen)
+
+	// json serialize the metadata
+	ms, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ms) > math.MaxUint16 {
+		return nil, errors.New("metadata length beyond the math.MaxUint16")
+	}
+
+	length := make([]byte, argon2idChacha20MetaLengthSize)
+	binary.LittleEndian.PutUint16(length, uint16(len(ms)))
+
+	// Additional data for AEAD
+	ad := append(length, ms...)
+	aead, err := chacha20poly1305.New(dk)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := aead.Seal(nil, m.Nonce, data, ad)
+
+	// Base64 encode the [[length][metadata]][ciphertext]
+	rawData := append(ad, ciphertext...)
+	enc := base64.StdEncoding
+	buf := make([]byte, enc.EncodedLen(len(rawData)))
+	enc.Encode(buf, rawData)
+	return buf, nil
+}
+
+// Decrypt decrypts the data with password
+// 1. Base64 decodes the data
+// 2. Reads the first [metaLengthSize] bytes data to get the metadata length, and reads out the metadata.
+// 3. Argon2id derives key from password and paramenters in metadata
+// 4. Chacha20poly1305 geneates AEAD
+// 5. AEAD decrypts ciphertext with nonce in metadata and [length][metadata] as additional data.
+// The argon2id paramenters of a are not used, the data is decrypted with the parameters it was encrypted with.
+func (a Argon2idChacha20poly1305) Decrypt(data, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("missing password")
+	}
+
+	enc := base64.StdEncoding
+	encData := make([]byte, enc.DecodedLen(len(data)))
+	n, err := enc.Decode(encData, data)
+	if err != nil {
+		return nil, err
+	}
+	encData = encData[:n]
+
+	if len(encData) < argon2idChacha20MetaLengthSize {
+		return nil, errors.New("invalid data length")
+	}
+
+	metaEnd := argon2idChacha20MetaLengthSize + int(binary.LittleEndian.Uint16(encData[:argon2idChacha20MetaLengthSize]))
+	if metaEnd > len(encData) {
+		return nil, errors.New("invalid metadata length")
+	}
+
+	var m argon2idMeta
+	if err := json.Unmarshal(encData[argon2idChacha20MetaLengthSize:metaEnd], &m); err != nil {
+		return nil, err
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	ad := encData[:metaEnd]
+	// Argon2id derives key
+	dk := argon2.IDKey(password, m.Salt, m.Time, m.Memory, m.Threads, m.KeyLen)
+
+	// Geneates AEAD
+	aead, err := chacha20poly1305.New(dk)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, m.Nonce, encData[metaEnd:], ad)
+}
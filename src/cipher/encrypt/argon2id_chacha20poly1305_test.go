@@ -0,0 +1,139 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testArgon2idEncData = "fwB7InRpbWUiOjEsIm1lbW9yeSI6NjQsInRocmVhZHMiOjEsImtleUxlbiI6MzIsInNhbHQiOiJuTEw5NVJSa1lJMitVK2VGYzVkaGhteXZnNEVoM1NwWHVORGVBemF1M0RjPSIsIm5vbmNlIjoieExPakRlSTA3d0xZeHBrTCJ99ro+XSIdzQ8ieywOGutzr+lpiuj96gehGg=="
+
+func TestArgon2idChacha20poly1305Encrypt(t *testing.T) {
+	tt := []struct {
+		time    uint32
+		memory  uint32
+		threads uint8
+		keyLen  uint32
+		err     error
+	}{
+		{time: 1, memory: 64, threads: 1, keyLen: 32},
+		{time: 3, memory: 1024, threads: 4, keyLen: 32},
+		{time: 0, memory: 64, threads: 1, keyLen: 32, err: errors.New("argon2id time must be > 0")},
+		{time: 1, memory: 64, threads: 0, keyLen: 32, err: errors.New("argon2id threads must be > 0")},
+		{time: 1, memory: 16, threads: 4, keyLen: 32, err: errors.New("argon2id memory must be at least 8*threads KiB")},
+		{time: 1, memory: 64, threads: 1, keyLen: 16, err: errors.New("argon2id key length must be the chacha20poly1305 key size")},
+		{time: 65, memory: 64, threads: 1, keyLen: 32, err: errors.New("argon2id time must be <= 64")},
+		{time: 1, memory: 1024, threads: 65, keyLen: 32, err: errors.New("argon2id threads must be <= 64")},
+		{time: 1, memory: 4*1024*1024 + 1, threads: 1, keyLen: 32, err: errors.New("argon2id memory must be <= 4194304 KiB")},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("time=%v memory=%v threads=%v keyLen=%v", tc.time, tc.memory, tc.threads, tc.keyLen)
+		t.Run(name, func(t *testing.T) {
+			crypto := Argon2idChacha20poly1305{Time: tc.time, Memory: tc.memory, Threads: tc.threads, KeyLen: tc.keyLen}
+			encData, err := crypto.Encrypt([]byte("plaintext"), []byte("password"))
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			data, err := base64.StdEncoding.DecodeString(string(encData))
+			require.NoError(t, err)
+			// Checks the prefix
+			ml := binary.LittleEndian.Uint16(data[:argon2idChacha20MetaLengthSize])
+			require.True(t, int(argon2idChacha20MetaLengthSize+ml) <= len(data))
+			var m argon2idMeta
+			require.NoError(t, json.Unmarshal(data[argon2idChacha20MetaLengthSize:argon2idChacha20MetaLengthSize+ml], &m))
+			require.Equal(t, tc.time, m.Time)
+			require.Equal(t, tc.memory, m.Memory)
+			require.Equal(t, tc.threads, m.Threads)
+			require.Equal(t, tc.keyLen, m.KeyLen)
+
+			// Decrypts with the parameters in the metadata, not the ones of the decryptor
+			plaintext, err := DefaultArgon2idChacha20poly1305.Decrypt(encData, []byte("password"))
+			require.NoError(t, err)
+			require.Equal(t, []byte("plaintext"), plaintext)
+		})
+	}
+}
+
+func TestArgon2idChacha20poly1305Decrypt(t *testing.T) {
+	tt := []struct {
+		name    string
+		data    []byte
+		encData []byte
+		decPwd  []byte
+		err     error
+	}{
+		{
+			name:    "ok",
+			data:    []byte("plaintext"),
+			encData: []byte(testArgon2idEncData),
+			decPwd:  []byte("pwd"),
+		},
+		{
+			name:    "invalid password",
+			encData: []byte(testArgon2idEncData),
+			decPwd:  []byte("wrong password"),
+			err:     errors.New("chacha20poly1305: message authentication failed"),
+		},
+		{
+			name:    "missing password",
+			encData: []byte(testArgon2idEncData),
+			err:     errors.New("missing password"),
+		},
+		{
+			name:    "invalid metadata length",
+			encData: []byte(base64.StdEncoding.EncodeToString([]byte{0xff, 0xff, 0x00})),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("invalid metadata length"),
+		},
+		{
+			name:    "time too high",
+			encData: testArgon2idMetaData(t, argon2idMeta{Time: 1 << 20, Memory: 64, Threads: 1, KeyLen: 32}),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("argon2id time must be <= 64"),
+		},
+		{
+			name:    "memory too high",
+			encData: testArgon2idMetaData(t, argon2idMeta{Time: 1, Memory: 0xffffffff, Threads: 1, KeyLen: 32}),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("argon2id memory must be <= 4194304 KiB"),
+		},
+		{
+			name:    "threads too high",
+			encData: testArgon2idMetaData(t, argon2idMeta{Time: 1, Memory: 1 << 20, Threads: 255, KeyLen: 32}),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("argon2id threads must be <= 64"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			crypto := Argon2idChacha20poly1305{}
+			data, err := crypto.Decrypt(tc.encData, tc.decPwd)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
+
+// testArgon2idMetaData returns the base64 encoded [length][metadata] of m, without ciphertext
+func testArgon2idMetaData(t *testing.T, m argon2idMeta) []byte {
+	ms, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	data := make([]byte, argon2idChacha20MetaLengthSize)
+	binary.LittleEndian.PutUint16(data, uint16(len(ms)))
+	data = append(data, ms...)
+	return []byte(base64.StdEncoding.EncodeToString(data))
+}
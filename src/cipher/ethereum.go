@@ -0,0 +1,157 @@
+package cipher
+
+import (
+	"encoding/hex"
+	"log"
+	"strings"
+
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+	"github.com/skycoin/skycoin/src/cipher/sha3"
+)
+
+// EthereumAddress is an ethereum style address, the last 20 bytes of the keccak256
+// hash of the uncompressed public key
+type EthereumAddress [20]byte
+
+// Keccak256 returns the legacy keccak256 hash of data, as used by ethereum
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data) //nolint:errcheck
+	return h.Sum(nil)
+}
+
+// EthereumAddressFromPubKey creates an EthereumAddress from PubKey as the last 20 bytes of
+// keccak256(uncompressed pubkey), without the 0x04 prefix. Returns a null address if the pubkey is invalid.
+func EthereumAddressFromPubKey(pubKey PubKey) EthereumAddress {
+	var addr EthereumAddress
+	if secp256k1.VerifyPubkey(pubKey[:]) != 1 {
+		return addr
+	}
+
+	uncompressed := secp256k1.UncompressPubkey(pubKey[:])
+	h := Keccak256(uncompressed[1:])
+	copy(addr[:], h[12:])
+	return addr
+}
+
+// EthereumAddressFromSecKey generates an EthereumAddress from SecKey
+func EthereumAddressFromSecKey(secKey SecKey) (EthereumAddress, error) {
+	p, err := PubKeyFromSecKey(secKey)
+	if err != nil {
+		return EthereumAddress{}, err
+	}
+	return EthereumAddressFromPubKey(p), nil
+}
+
+// MustEthereumAddressFromSecKey generates an EthereumAddress from SecKey, panics on error
+func MustEthereumAddressFromSecKey(secKey SecKey) EthereumAddress {
+	return EthereumAddressFromPubKey(MustPubKeyFromSecKey(secKey))
+}
+
+// DecodeHexEthereumAddress creates an EthereumAddress from its hex encoding, the 0x prefix is optional.
+// Mixed case addresses must have a valid EIP-55 checksum.
+func DecodeHexEthereumAddress(addr string) (EthereumAddress, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	if len(s) != 40 {
+		return EthereumAddress{}, ErrAddressInvalidLength
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return EthereumAddress{}, err
+	}
+
+	a, err := EthereumAddressFromBytes(b)
+	if err != nil {
+		return EthereumAddress{}, err
+	}
+
+	// All lower or all upper case addresses carry no checksum
+	if s == strings.ToLower(s) || s == strings.ToUpper(s) {
+		return a, nil
+	}
+
+	if a.String()[2:] != s {
+		return EthereumAddress{}, ErrAddressInvalidChecksum
+	}
+
+	return a, nil
+}
+
+// MustDecodeHexEthereumAddress creates an EthereumAddress from its hex encoding, panics on error
+func MustDecodeHexEthereumAddress(addr string) EthereumAddress {
+	a, err := DecodeHexEthereumAddress(addr)
+	if err != nil {
+		log.Panicf("Invalid ethereum address %s: %v", addr, err)
+	}
+	return a
+}
+
+// EthereumAddressFromBytes converts []byte to an EthereumAddress
+func EthereumAddressFromBytes(b []byte) (EthereumAddress, error) {
+	var a EthereumAddress
+	if len(b) != len(a) {
+		return EthereumAddress{}, ErrAddressInvalidLength
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// MustEthereumAddressFromBytes converts []byte to an EthereumAddress, panics on error
+func MustEthereumAddressFromBytes(b []byte) EthereumAddress {
+	a, err := EthereumAddressFromBytes(b)
+	if err != nil {
+		log.Panic(err)
+	}
+	return a
+}
+
+// Null returns true if the address is null (0x0000....)
+func (addr EthereumAddress) Null() bool {
+	return addr == EthereumAddress{}
+}
+
+// Bytes returns the ethereum address as byte slice
+func (addr EthereumAddress) Bytes() []byte {
+	b := make([]byte, len(addr))
+	copy(b, addr[:])
+	return b
+}
+
+// Verify checks that the ethereum address appears valid for the public key
+func (addr EthereumAddress) Verify(key PubKey) error {
+	if addr.Null() || addr != EthereumAddressFromPubKey(key) {
+		return ErrAddressInvalidPubKey
+	}
+	return nil
+}
+
+// String returns the 0x prefixed hex encoding of the address with the EIP-55 mixed case checksum
+func (addr EthereumAddress) String() string {
+	s := []byte(hex.EncodeToString(addr[:]))
+	h := Keccak256(s)
+	for i, c := range s {
+		if c < 'a' {
+			continue
+		}
+
+		// The letter is upper cased if the corresponding nibble of the hash is >= 8
+		nibble := h[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			s[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(s)
+}
+
+// Checksum returns the first 4 bytes of the keccak256 hash of the lower case hex address,
+// which determines the EIP-55 checksum casing
+func (addr EthereumAddress) Checksum() Checksum {
+	h := Keccak256([]byte(hex.EncodeToString(addr[:])))
+	c := Checksum{}
+	copy(c[:], h[:len(c)])
+	return c
+}
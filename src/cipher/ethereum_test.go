@@ -0,0 +1,93 @@
+package cipher
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthereumAddress(t *testing.T) {
+	cases := []struct {
+		seckey string
+		addr   string
+	}{
+		{
+			seckey: "0000000000000000000000000000000000000000000000000000000000000001",
+			addr:   "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{
+			seckey: "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+			addr:   "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.addr, func(t *testing.T) {
+			seckey := MustSecKeyFromHex(tc.seckey)
+			pubkey := MustPubKeyFromSecKey(seckey)
+
+			addr := EthereumAddressFromPubKey(pubkey)
+			require.Equal(t, tc.addr, addr.String())
+			require.NoError(t, addr.Verify(pubkey))
+
+			secAddr, err := EthereumAddressFromSecKey(seckey)
+			require.NoError(t, err)
+			require.Equal(t, addr, secAddr)
+			require.Equal(t, addr, MustEthereumAddressFromSecKey(seckey))
+
+			p, _ := GenerateKeyPair()
+			require.Equal(t, ErrAddressInvalidPubKey, addr.Verify(p))
+		})
+	}
+
+	require.True(t, EthereumAddressFromPubKey(PubKey{}).Null())
+	require.Equal(t, ErrAddressInvalidPubKey, EthereumAddress{}.Verify(PubKey{}))
+}
+
+func TestDecodeHexEthereumAddress(t *testing.T) {
+	cases := []struct {
+		name string
+		addr string
+		err  error
+	}{
+		// EIP-55 test vectors
+		{name: "checksum all caps", addr: "0x52908400098527886E0F7030069857D2E4169EE7"},
+		{name: "checksum all lower", addr: "0xde709f2102306220921060314715629080e2fb77"},
+		{name: "checksum mixed 1", addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "checksum mixed 2", addr: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{name: "checksum mixed 3", addr: "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{name: "checksum mixed 4", addr: "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"},
+		{name: "no prefix", addr: "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{name: "bad checksum", addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", err: ErrAddressInvalidChecksum},
+		{name: "short", addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", err: ErrAddressInvalidLength},
+		{name: "empty", addr: "", err: ErrAddressInvalidLength},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := DecodeHexEthereumAddress(tc.addr)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				require.Panics(t, func() {
+					MustDecodeHexEthereumAddress(tc.addr)
+				})
+				return
+			}
+
+			require.Equal(t, a, MustDecodeHexEthereumAddress(tc.addr))
+			require.Equal(t, a, MustEthereumAddressFromBytes(a.Bytes()))
+			require.Equal(t, "0x"+tc.addr[len(tc.addr)-40:], a.String())
+		})
+	}
+
+	_, err := DecodeHexEthereumAddress("0xzzAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.Error(t, err)
+
+	_, err = EthereumAddressFromBytes(make([]byte, 21))
+	require.Equal(t, ErrAddressInvalidLength, err)
+}
+
+func TestKeccak256(t *testing.T) {
+	require.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(Keccak256(nil)))
+}
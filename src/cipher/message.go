@@ -0,0 +1,64 @@
+package cipher
+
+import (
+	"encoding/base64"
+	"log"
+)
+
+// SignedMessagePrefix is prepended to the messages before they are hashed and signed,
+// so that a signed message can never be a valid signature of a transaction or another raw hash
+const SignedMessagePrefix = "Skycoin Signed Message:\n"
+
+// HashMessage returns the hash of the message that is signed by SignMessage
+func HashMessage(msg []byte) SHA256 {
+	b := make([]byte, 0, len(SignedMessagePrefix)+len(msg))
+	b = append(b, SignedMessagePrefix...)
+	b = append(b, msg...)
+	return SumSHA256(b)
+}
+
+// SignMessage signs the message with the SignedMessagePrefix domain separation,
+// the signature proves the ownership of the address of the seckey
+func SignMessage(seckey SecKey, msg []byte) (Sig, error) {
+	return SignHash(HashMessage(msg), seckey)
+}
+
+// MustSignMessage signs the message, panics on error
+func MustSignMessage(seckey SecKey, msg []byte) Sig {
+	sig, err := SignMessage(seckey, msg)
+	if err != nil {
+		log.Panic(err)
+	}
+	return sig
+}
+
+// VerifyMessage verifies that the message was signed by SignMessage with the seckey of the address
+func VerifyMessage(addr Address, sig Sig, msg []byte) error {
+	return VerifyAddressSignedHash(addr, sig, HashMessage(msg))
+}
+
+// SignMessageCompact signs the message like SignMessage and returns the base64 encoded compact signature,
+// the signer's public key and address can be recovered from it with PubKeyFromCompactMessageSig
+func SignMessageCompact(seckey SecKey, msg []byte) (string, error) {
+	sig, err := SignMessage(seckey, msg)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig.Compact()), nil
+}
+
+// PubKeyFromCompactMessageSig recovers the public key of the signer of the message
+// from a base64 encoded compact signature made by SignMessageCompact
+func PubKeyFromCompactMessageSig(compactSig string, msg []byte) (PubKey, error) {
+	b, err := base64.StdEncoding.DecodeString(compactSig)
+	if err != nil {
+		return PubKey{}, ErrInvalidCompactSig
+	}
+
+	sig, err := SigFromCompact(b)
+	if err != nil {
+		return PubKey{}, err
+	}
+
+	return PubKeyFromSigAndHash(sig, HashMessage(msg))
+}
@@ -0,0 +1,69 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignMessage(t *testing.T) {
+	p, s := GenerateKeyPair()
+	addr := AddressFromPubKey(p)
+	msg := []byte("I own this address")
+
+	sig, err := SignMessage(s, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyMessage(addr, sig, msg))
+
+	// The signature is not valid for a modified message
+	require.Equal(t, ErrInvalidAddressForSig, VerifyMessage(addr, sig, []byte("I own this address!")))
+
+	// The signature is not valid for another address
+	p2, _ := GenerateKeyPair()
+	require.Equal(t, ErrInvalidAddressForSig, VerifyMessage(AddressFromPubKey(p2), sig, msg))
+
+	// The message signature is not a signature of the raw hash of the message
+	require.Error(t, VerifyAddressSignedHash(addr, sig, SumSHA256(msg)))
+	rawSig := MustSignHash(SumSHA256(msg), s)
+	require.Error(t, VerifyMessage(addr, rawSig, msg))
+
+	// Empty messages can be signed
+	sig = MustSignMessage(s, nil)
+	require.NoError(t, VerifyMessage(addr, sig, []byte{}))
+
+	_, err = SignMessage(SecKey{}, msg)
+	require.Equal(t, ErrInvalidSecKey, err)
+	require.Panics(t, func() { MustSignMessage(SecKey{}, msg) })
+}
+
+func TestHashMessage(t *testing.T) {
+	require.Equal(t, SumSHA256([]byte("Skycoin Signed Message:\nhello")), HashMessage([]byte("hello")))
+	require.NotEqual(t, HashMessage([]byte("hello")), HashMessage([]byte("hello ")))
+}
+
+func TestSignMessageCompact(t *testing.T) {
+	p, s := GenerateKeyPair()
+	msg := []byte("I own this address")
+
+	sig, err := SignMessageCompact(s, msg)
+	require.NoError(t, err)
+	require.Len(t, sig, 88)
+
+	p2, err := PubKeyFromCompactMessageSig(sig, msg)
+	require.NoError(t, err)
+	require.Equal(t, p, p2)
+
+	// Another message recovers another public key, or none
+	p3, err := PubKeyFromCompactMessageSig(sig, []byte("I own this address!"))
+	if err == nil {
+		require.NotEqual(t, p, p3)
+	}
+
+	_, err = PubKeyFromCompactMessageSig("not base64!", msg)
+	require.Equal(t, ErrInvalidCompactSig, err)
+	_, err = PubKeyFromCompactMessageSig(sig[:40], msg)
+	require.Equal(t, ErrInvalidCompactSig, err)
+
+	_, err = SignMessageCompact(SecKey{}, msg)
+	require.Equal(t, ErrInvalidSecKey, err)
+}
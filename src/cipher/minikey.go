@@ -0,0 +1,48 @@
+package cipher
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher/base58"
+)
+
+var (
+	// ErrMiniPrivateKeyInvalid the string is not in the mini private key format
+	ErrMiniPrivateKeyInvalid = errors.New("Mini private key: Invalid format")
+	// ErrMiniPrivateKeyInvalidChecksum the typo check of the mini private key failed
+	ErrMiniPrivateKeyInvalidChecksum = errors.New("Mini private key: Checksum fail")
+)
+
+// IsMiniPrivateKey returns true if input has the form of a mini private key:
+// 22, 26 or 30 base58 characters starting with 'S'. It does not check the typo check byte.
+func IsMiniPrivateKey(input string) bool {
+	switch len(input) {
+	case 22, 26, 30:
+	default:
+		return false
+	}
+
+	if input[0] != 'S' {
+		return false
+	}
+
+	_, err := base58.Decode(input)
+	return err == nil
+}
+
+// SecKeyFromMiniPrivateKey extracts a seckey from the mini private key format used by Casascius coins
+// and some paper wallets. The seckey is sha256(input). The key is valid only if sha256(input + "?") starts with a zero byte.
+func SecKeyFromMiniPrivateKey(input string) (SecKey, error) {
+	if !IsMiniPrivateKey(input) {
+		return SecKey{}, ErrMiniPrivateKeyInvalid
+	}
+
+	check := sha256.Sum256([]byte(input + "?"))
+	if check[0] != 0x00 {
+		return SecKey{}, ErrMiniPrivateKeyInvalidChecksum
+	}
+
+	seckey := sha256.Sum256([]byte(input))
+	return NewSecKey(seckey[:])
+}
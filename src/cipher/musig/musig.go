@@ -0,0 +1,602 @@
+/*
+Package musig implements MuSig2 multi-signatures for secp256k1, as specified by BIP327.
+
+N signers aggregate their public keys into a single aggregate key, then sign a hash
+in two rounds: each signer publishes a public nonce, and once every public nonce is known,
+each signer publishes a partial signature. The partial signatures are aggregated into a
+single BIP340 Schnorr signature, which verifies against the aggregate key with
+cipher.VerifySchnorrSignedHash like a signature made by a single key.
+
+MuSig2 is N-of-N: every signer of the aggregate key must sign. M-of-N threshold signing, such as
+2-of-3, uses a key tree: ThresholdKeys aggregates the keys of every subset of M signers, any M signers
+sign with the aggregate key of their subset, and VerifyThreshold verifies the signature against
+every subset key. The signatures are not verified by consensus, the blockchain only verifies
+ECDSA signatures, so coins can't be spent with an aggregate signature.
+
+Usage:
+
+	aggKey, err := musig.AggregatePubKeys(musig.SortPubKeys(pubKeys))
+
+	// Round 1, each signer
+	secNonce, pubNonce, err := musig.NewNonce(seckey, aggKey, hash)
+
+	// Round 2, once all public nonces are collected
+	aggNonce, err := musig.AggregateNonces(pubNonces)
+	session, err := musig.NewSession(aggKey, aggNonce, hash)
+	psig, err := session.Sign(secNonce, seckey)
+
+	// Any party, once all partial signatures are collected
+	sig, err := session.AggregatePartialSigs(psigs)
+
+	// M-of-N, the M signers sign with the aggregate key of their subset
+	keys, err := musig.ThresholdKeys(pubKeys, m)
+	aggKey, err := musig.SubsetKey(keys, signerPubKeys)
+	...
+	aggKey, err = musig.VerifyThreshold(keys, sig, hash)
+
+A SecNonce must never be used to sign twice, doing so leaks the secret key.
+Sign erases the SecNonce after use.
+*/
+package musig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+	secp256k1go "github.com/skycoin/skycoin/src/cipher/secp256k1-go/secp256k1-go2"
+)
+
+// BIP327 tagged hash tags
+const (
+	tagKeyAggList        = "KeyAgg list"
+	tagKeyAggCoefficient = "KeyAgg coefficient"
+	tagAux               = "MuSig/aux"
+	tagNonce             = "MuSig/nonce"
+	tagNonceCoef         = "MuSig/noncecoef"
+	tagChallenge         = "BIP0340/challenge"
+)
+
+var (
+	// ErrNoPubKeys no public keys to aggregate
+	ErrNoPubKeys = errors.New("MuSig: no public keys to aggregate")
+	// ErrInvalidAggregateKey the aggregate key is the point at infinity
+	ErrInvalidAggregateKey = errors.New("MuSig: aggregate key is the point at infinity")
+	// ErrNotSigner the public key is not one of the keys of the aggregate key
+	ErrNotSigner = errors.New("MuSig: public key is not a signer of the aggregate key")
+	// ErrInvalidPubNonce the public nonce is not two valid compressed points
+	ErrInvalidPubNonce = errors.New("MuSig: invalid public nonce")
+	// ErrInvalidAggNonce the aggregate nonce is not two valid compressed points or infinity
+	ErrInvalidAggNonce = errors.New("MuSig: invalid aggregate nonce")
+	// ErrNoPubNonces no public nonces to aggregate
+	ErrNoPubNonces = errors.New("MuSig: no public nonces to aggregate")
+	// ErrSecNonceUsed the secret nonce was already used to sign, or was not generated by NewNonce
+	ErrSecNonceUsed = errors.New("MuSig: secret nonce already used")
+	// ErrSecNonceMismatch the secret nonce was generated for a different secret key
+	ErrSecNonceMismatch = errors.New("MuSig: secret nonce does not belong to the secret key")
+	// ErrInvalidPartialSig the partial signature is out of range or was not made by the signer
+	ErrInvalidPartialSig = errors.New("MuSig: invalid partial signature")
+	// ErrNoPartialSigs no partial signatures to aggregate
+	ErrNoPartialSigs = errors.New("MuSig: no partial signatures to aggregate")
+)
+
+// PubNonce is the public nonce of a signer: two compressed points
+type PubNonce [66]byte
+
+// AggNonce is the aggregate of the public nonces of all signers: two compressed points,
+// a point at infinity is encoded as 33 zero bytes
+type AggNonce [66]byte
+
+// PartialSig is the partial signature of a signer
+type PartialSig [32]byte
+
+// SecNonce is the secret nonce of a signer, it must only be used to sign once
+type SecNonce struct {
+	k1, k2 secp256k1go.Number
+	pubKey cipher.PubKey
+}
+
+// erase zeroes the nonce scalars, so that it can not be used again
+func (n *SecNonce) erase() {
+	n.k1.SetInt64(0)
+	n.k2.SetInt64(0)
+}
+
+// taggedHash returns sha256(sha256(tag) || sha256(tag) || data...), as defined by BIP340
+func taggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:]) //nolint:errcheck
+	h.Write(t[:]) //nolint:errcheck
+	for _, d := range data {
+		h.Write(d) //nolint:errcheck
+	}
+	return h.Sum(nil)
+}
+
+// scalarFromHash returns int(h) mod n
+func scalarFromHash(h []byte) secp256k1go.Number {
+	var k secp256k1go.Number
+	k.SetBytes(h)
+	k.Mod(&k.Int, &secp256k1go.TheCurve.Order.Int)
+	return k
+}
+
+// modN sets k to k mod n
+func modN(k *secp256k1go.Number) {
+	k.Mod(&k.Int, &secp256k1go.TheCurve.Order.Int)
+}
+
+// negateN sets k to n - k mod n
+func negateN(k *secp256k1go.Number) {
+	k.Sub(&secp256k1go.TheCurve.Order.Int, &k.Int)
+	modN(k)
+}
+
+func scalarBytes(k secp256k1go.Number) []byte {
+	return secp256k1go.LeftPadBytes(k.Bytes(), 32)
+}
+
+// parsePoint parses a compressed point
+func parsePoint(b []byte) (secp256k1go.XY, bool) {
+	var p secp256k1go.XY
+	if len(b) != 33 || secp256k1.VerifyPubkey(b) != 1 {
+		return p, false
+	}
+	if err := p.ParsePubkey(b); err != nil {
+		return p, false
+	}
+	p.X.Normalize()
+	p.Y.Normalize()
+	return p, true
+}
+
+// affine converts the point to affine coordinates with normalized fields
+func affine(p secp256k1go.XYZ) secp256k1go.XY {
+	if p.Infinity {
+		return secp256k1go.XY{Infinity: true}
+	}
+	var a secp256k1go.XY
+	a.SetXYZ(&p)
+	a.X.Normalize()
+	a.Y.Normalize()
+	return a
+}
+
+// jacobian converts the affine point to jacobian coordinates
+func jacobian(p secp256k1go.XY) secp256k1go.XYZ {
+	if p.Infinity {
+		return secp256k1go.XYZ{Infinity: true}
+	}
+	var r secp256k1go.XYZ
+	r.SetXY(&p)
+	return r
+}
+
+// mulPoint returns k*p
+func mulPoint(p secp256k1go.XY, k secp256k1go.Number) secp256k1go.XYZ {
+	if p.Infinity || k.Sign() == 0 {
+		return secp256k1go.XYZ{Infinity: true}
+	}
+	pj := jacobian(p)
+	var r secp256k1go.XYZ
+	var zero secp256k1go.Number
+	pj.ECmult(&r, &k, &zero)
+	return r
+}
+
+// mulGen returns k*G
+func mulGen(k secp256k1go.Number) secp256k1go.XYZ {
+	if k.Sign() == 0 {
+		return secp256k1go.XYZ{Infinity: true}
+	}
+	return secp256k1go.ECmultGen(k)
+}
+
+// addPoints returns a+b
+func addPoints(a, b secp256k1go.XYZ) secp256k1go.XYZ {
+	var r secp256k1go.XYZ
+	a.Add(&r, &b)
+	return r
+}
+
+func xBytes(p secp256k1go.XY) []byte {
+	b := make([]byte, 32)
+	p.X.GetB32(b)
+	return b
+}
+
+func hasEvenY(p secp256k1go.XY) bool {
+	return !p.Y.IsOdd()
+}
+
+// SortPubKeys returns a copy of the public keys sorted lexicographically,
+// so that the aggregate key does not depend on the order the signers provided their keys in
+func SortPubKeys(pubKeys []cipher.PubKey) []cipher.PubKey {
+	pks := make([]cipher.PubKey, len(pubKeys))
+	copy(pks, pubKeys)
+	sort.Slice(pks, func(i, j int) bool {
+		return bytes.Compare(pks[i][:], pks[j][:]) < 0
+	})
+	return pks
+}
+
+// AggregateKey is the aggregate of the public keys of the signers
+type AggregateKey struct {
+	q         secp256k1go.XY
+	pubKeys   []cipher.PubKey
+	listHash  []byte
+	secondKey cipher.PubKey
+}
+
+// AggregatePubKeys aggregates the public keys of the signers, as the BIP327 KeyAgg algorithm.
+// The aggregate key depends on the order of pubKeys, use SortPubKeys to make it order independent.
+func AggregatePubKeys(pubKeys []cipher.PubKey) (*AggregateKey, error) {
+	if len(pubKeys) == 0 {
+		return nil, ErrNoPubKeys
+	}
+
+	k := &AggregateKey{
+		pubKeys: make([]cipher.PubKey, len(pubKeys)),
+	}
+	copy(k.pubKeys, pubKeys)
+
+	list := make([]byte, 0, len(pubKeys)*len(cipher.PubKey{}))
+	for _, pk := range pubKeys {
+		list = append(list, pk[:]...)
+	}
+	k.listHash = taggedHash(tagKeyAggList, list)
+
+	// The second distinct key has a coefficient of 1, as an optimization
+	for _, pk := range pubKeys[1:] {
+		if pk != pubKeys[0] {
+			k.secondKey = pk
+			break
+		}
+	}
+
+	q := secp256k1go.XYZ{Infinity: true}
+	for _, pk := range pubKeys {
+		p, ok := parsePoint(pk[:])
+		if !ok {
+			return nil, cipher.ErrInvalidPubKey
+		}
+		q = addPoints(q, mulPoint(p, k.coefficient(pk)))
+	}
+
+	k.q = affine(q)
+	if k.q.Infinity {
+		return nil, ErrInvalidAggregateKey
+	}
+
+	return k, nil
+}
+
+// coefficient returns the KeyAgg coefficient of the public key
+func (k *AggregateKey) coefficient(pk cipher.PubKey) secp256k1go.Number {
+	if pk == k.secondKey {
+		var one secp256k1go.Number
+		one.SetInt64(1)
+		return one
+	}
+	return scalarFromHash(taggedHash(tagKeyAggCoefficient, k.listHash, pk[:]))
+}
+
+// hasSigner returns true if pk is one of the public keys of the aggregate key
+func (k *AggregateKey) hasSigner(pk cipher.PubKey) bool {
+	for _, p := range k.pubKeys {
+		if p == pk {
+			return true
+		}
+	}
+	return false
+}
+
+// PubKeys returns a copy of the public keys of the signers, in aggregation order
+func (k *AggregateKey) PubKeys() []cipher.PubKey {
+	pks := make([]cipher.PubKey, len(k.pubKeys))
+	copy(pks, k.pubKeys)
+	return pks
+}
+
+// SchnorrPubKey returns the x-only BIP340 public key of the aggregate key,
+// which verifies the aggregated signatures
+func (k *AggregateKey) SchnorrPubKey() cipher.SchnorrPubKey {
+	return cipher.MustNewSchnorrPubKey(xBytes(k.q))
+}
+
+// PubKey returns the compressed public key of the aggregate key
+func (k *AggregateKey) PubKey() cipher.PubKey {
+	return cipher.MustNewPubKey(k.q.Bytes())
+}
+
+// NewNonce generates the secret and public nonces of the signer of sec for signing hash with aggKey,
+// as the BIP327 NonceGen algorithm. The public nonce is sent to the other signers,
+// the secret nonce is kept until the partial signature is made with Session.Sign.
+func NewNonce(sec cipher.SecKey, aggKey *AggregateKey, hash cipher.SHA256) (*SecNonce, PubNonce, error) {
+	return nonceGen(cipher.RandByte(32), sec, aggKey, hash)
+}
+
+// nonceGen generates the nonces from the 32 bytes of randomness rnd
+func nonceGen(rnd []byte, sec cipher.SecKey, aggKey *AggregateKey, hash cipher.SHA256) (*SecNonce, PubNonce, error) {
+	pk, err := cipher.PubKeyFromSecKey(sec)
+	if err != nil {
+		return nil, PubNonce{}, err
+	}
+
+	if !aggKey.hasSigner(pk) {
+		return nil, PubNonce{}, ErrNotSigner
+	}
+
+	// The secret key is mixed into the randomness, to protect against a weak random number generator
+	aux := taggedHash(tagAux, rnd)
+	rand := make([]byte, 32)
+	for i := range rand {
+		rand[i] = sec[i] ^ aux[i]
+	}
+
+	aggPK := xBytes(aggKey.q)
+	var msgLen [8]byte
+	binary.BigEndian.PutUint64(msgLen[:], uint64(len(hash)))
+	var extraLen [4]byte
+
+	nonce := func(i byte) secp256k1go.Number {
+		return scalarFromHash(taggedHash(tagNonce,
+			rand,
+			[]byte{byte(len(pk))}, pk[:],
+			[]byte{byte(len(aggPK))}, aggPK,
+			[]byte{1}, msgLen[:], hash[:],
+			extraLen[:],
+			[]byte{i},
+		))
+	}
+
+	secNonce := &SecNonce{
+		k1:     nonce(0),
+		k2:     nonce(1),
+		pubKey: pk,
+	}
+	if secNonce.k1.Sign() == 0 || secNonce.k2.Sign() == 0 {
+		// Negligible probability
+		return nil, PubNonce{}, errors.New("MuSig: generated nonce is zero")
+	}
+
+	var pubNonce PubNonce
+	copy(pubNonce[:33], affine(mulGen(secNonce.k1)).Bytes())
+	copy(pubNonce[33:], affine(mulGen(secNonce.k2)).Bytes())
+
+	return secNonce, pubNonce, nil
+}
+
+// points parses the two points of the public nonce
+func (n PubNonce) points() (secp256k1go.XY, secp256k1go.XY, bool) {
+	r1, ok := parsePoint(n[:33])
+	if !ok {
+		return r1, r1, false
+	}
+	r2, ok := parsePoint(n[33:])
+	return r1, r2, ok
+}
+
+// AggregateNonces aggregates the public nonces of all signers
+func AggregateNonces(pubNonces []PubNonce) (AggNonce, error) {
+	if len(pubNonces) == 0 {
+		return AggNonce{}, ErrNoPubNonces
+	}
+
+	r1 := secp256k1go.XYZ{Infinity: true}
+	r2 := secp256k1go.XYZ{Infinity: true}
+	for _, n := range pubNonces {
+		p1, p2, ok := n.points()
+		if !ok {
+			return AggNonce{}, ErrInvalidPubNonce
+		}
+		r1 = addPoints(r1, jacobian(p1))
+		r2 = addPoints(r2, jacobian(p2))
+	}
+
+	var aggNonce AggNonce
+	if r := affine(r1); !r.Infinity {
+		copy(aggNonce[:33], r.Bytes())
+	}
+	if r := affine(r2); !r.Infinity {
+		copy(aggNonce[33:], r.Bytes())
+	}
+
+	return aggNonce, nil
+}
+
+// parseAggNoncePoint parses a point of the aggregate nonce, which may be infinity
+func parseAggNoncePoint(b []byte) (secp256k1go.XY, bool) {
+	if bytes.Equal(b, make([]byte, 33)) {
+		return secp256k1go.XY{Infinity: true}, true
+	}
+	return parsePoint(b)
+}
+
+// Session holds the values shared by the signers to sign a hash with an aggregate key and aggregate nonce
+type Session struct {
+	aggKey *AggregateKey
+	hash   cipher.SHA256
+	b      secp256k1go.Number
+	e      secp256k1go.Number
+	r      secp256k1go.XY
+}
+
+// NewSession creates the signing session of hash with the aggregate key and the aggregate nonce
+func NewSession(aggKey *AggregateKey, aggNonce AggNonce, hash cipher.SHA256) (*Session, error) {
+	r1, ok := parseAggNoncePoint(aggNonce[:33])
+	if !ok {
+		return nil, ErrInvalidAggNonce
+	}
+	r2, ok := parseAggNoncePoint(aggNonce[33:])
+	if !ok {
+		return nil, ErrInvalidAggNonce
+	}
+
+	qx := xBytes(aggKey.q)
+	b := scalarFromHash(taggedHash(tagNonceCoef, aggNonce[:], qx, hash[:]))
+
+	r := affine(addPoints(jacobian(r1), mulPoint(r2, b)))
+	if r.Infinity {
+		r = secp256k1go.TheCurve.G
+		r.X.Normalize()
+		r.Y.Normalize()
+	}
+
+	e := scalarFromHash(taggedHash(tagChallenge, xBytes(r), qx, hash[:]))
+
+	return &Session{
+		aggKey: aggKey,
+		hash:   hash,
+		b:      b,
+		e:      e,
+		r:      r,
+	}, nil
+}
+
+// keyParity returns g, 1 if the aggregate key has an even y coordinate, n-1 otherwise
+func (s *Session) keyParity() secp256k1go.Number {
+	var g secp256k1go.Number
+	g.SetInt64(1)
+	if !hasEvenY(s.aggKey.q) {
+		negateN(&g)
+	}
+	return g
+}
+
+// Sign makes the partial signature of the signer of sec with its secret nonce.
+// The secret nonce is erased and can not be used again.
+func (s *Session) Sign(secNonce *SecNonce, sec cipher.SecKey) (PartialSig, error) {
+	if secNonce == nil || secNonce.k1.Sign() == 0 || secNonce.k2.Sign() == 0 {
+		return PartialSig{}, ErrSecNonceUsed
+	}
+
+	k1 := secNonce.k1
+	k2 := secNonce.k2
+	pubKey := secNonce.pubKey
+	secNonce.erase()
+
+	if secp256k1.VerifySeckey(sec[:]) != 1 {
+		return PartialSig{}, cipher.ErrInvalidSecKey
+	}
+
+	pk, err := cipher.PubKeyFromSecKey(sec)
+	if err != nil {
+		return PartialSig{}, err
+	}
+	if pk != pubKey {
+		return PartialSig{}, ErrSecNonceMismatch
+	}
+	if !s.aggKey.hasSigner(pk) {
+		return PartialSig{}, ErrNotSigner
+	}
+
+	var pubNonce PubNonce
+	copy(pubNonce[:33], affine(mulGen(k1)).Bytes())
+	copy(pubNonce[33:], affine(mulGen(k2)).Bytes())
+
+	if !hasEvenY(s.r) {
+		negateN(&k1)
+		negateN(&k2)
+	}
+
+	// d = g * d' mod n
+	d := scalarFromHash(sec[:])
+	g := s.keyParity()
+	d.Mul(&d.Int, &g.Int)
+
+	// s = k1 + b*k2 + e*a*d mod n
+	a := s.aggKey.coefficient(pk)
+	var v secp256k1go.Number
+	v.Mul(&s.e.Int, &a.Int)
+	v.Mul(&v.Int, &d.Int)
+	k2.Mul(&k2.Int, &s.b.Int)
+	v.Add(&v.Int, &k2.Int)
+	v.Add(&v.Int, &k1.Int)
+	modN(&v)
+
+	var psig PartialSig
+	copy(psig[:], scalarBytes(v))
+
+	// Guard against fault attacks leaking the seckey through an invalid signature
+	if err := s.VerifyPartialSig(psig, pubNonce, pk); err != nil {
+		return PartialSig{}, errors.New("MuSig: made an invalid partial signature")
+	}
+
+	return psig, nil
+}
+
+// VerifyPartialSig verifies the partial signature of the signer of pk, made with the secret nonce of pubNonce
+func (s *Session) VerifyPartialSig(psig PartialSig, pubNonce PubNonce, pk cipher.PubKey) error {
+	if !s.aggKey.hasSigner(pk) {
+		return ErrNotSigner
+	}
+
+	var v secp256k1go.Number
+	v.SetBytes(psig[:])
+	if v.Sign() == 0 || v.Cmp(&secp256k1go.TheCurve.Order.Int) >= 0 {
+		return ErrInvalidPartialSig
+	}
+
+	r1, r2, ok := pubNonce.points()
+	if !ok {
+		return ErrInvalidPubNonce
+	}
+
+	p, ok := parsePoint(pk[:])
+	if !ok {
+		return cipher.ErrInvalidPubKey
+	}
+
+	// Re = R1 + b*R2, negated if the final nonce has an odd y coordinate
+	re := affine(addPoints(jacobian(r1), mulPoint(r2, s.b)))
+	if !re.Infinity && !hasEvenY(s.r) {
+		re.Neg(&re)
+		re.Y.Normalize()
+	}
+
+	// s*G == Re + e*a*g*P
+	a := s.aggKey.coefficient(pk)
+	g := s.keyParity()
+	var k secp256k1go.Number
+	k.Mul(&s.e.Int, &a.Int)
+	k.Mul(&k.Int, &g.Int)
+	modN(&k)
+
+	lhs := affine(mulGen(v))
+	rhs := affine(addPoints(jacobian(re), mulPoint(p, k)))
+	if rhs.Infinity || !bytes.Equal(lhs.Bytes(), rhs.Bytes()) {
+		return ErrInvalidPartialSig
+	}
+
+	return nil
+}
+
+// AggregatePartialSigs aggregates the partial signatures of all signers into
+// a BIP340 Schnorr signature, verified by the SchnorrPubKey of the aggregate key
+func (s *Session) AggregatePartialSigs(psigs []PartialSig) (cipher.SchnorrSig, error) {
+	if len(psigs) == 0 {
+		return cipher.SchnorrSig{}, ErrNoPartialSigs
+	}
+
+	var sum secp256k1go.Number
+	for _, psig := range psigs {
+		var v secp256k1go.Number
+		v.SetBytes(psig[:])
+		if v.Cmp(&secp256k1go.TheCurve.Order.Int) >= 0 {
+			return cipher.SchnorrSig{}, ErrInvalidPartialSig
+		}
+		sum.Add(&sum.Int, &v.Int)
+	}
+	modN(&sum)
+
+	var sig cipher.SchnorrSig
+	copy(sig[:32], xBytes(s.r))
+	copy(sig[32:], scalarBytes(sum))
+	return sig, nil
+}
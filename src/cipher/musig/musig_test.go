@@ -0,0 +1,236 @@
+package musig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestAggregatePubKeysBIP327(t *testing.T) {
+	// Test vectors from BIP327 key_agg_vectors.json
+	pks := []cipher.PubKey{
+		cipher.MustPubKeyFromHex("02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9"),
+		cipher.MustPubKeyFromHex("03dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659"),
+		cipher.MustPubKeyFromHex("023590a94e768f8e1815c2f24b4d80a8e3149316c3518ce7b7ad338368d038ca66"),
+	}
+
+	cases := []struct {
+		indexes  []int
+		expected string
+	}{
+		{
+			indexes:  []int{0, 1, 2},
+			expected: "90539eede565f5d054f32cc0c220126889ed1e5d193baf15aef344fe59d4610c",
+		},
+		{
+			indexes:  []int{2, 1, 0},
+			expected: "6204de8b083426dc6eaf9502d27024d53fc826bf7d2012148a0575435df54b2b",
+		},
+		{
+			indexes:  []int{0, 0, 0},
+			expected: "b436e3bad62b8cd409969a224731c193d051162d8c5ae8b109306127da3aa935",
+		},
+		{
+			indexes:  []int{0, 0, 1, 1},
+			expected: "69bc22bfa5d106306e48a20679de1d7389386124d07571d0d872686028c26a3e",
+		},
+	}
+
+	for _, tc := range cases {
+		keys := make([]cipher.PubKey, len(tc.indexes))
+		for i, j := range tc.indexes {
+			keys[i] = pks[j]
+		}
+
+		aggKey, err := AggregatePubKeys(keys)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, aggKey.SchnorrPubKey().Hex())
+		require.Equal(t, keys, aggKey.PubKeys())
+	}
+
+	_, err := AggregatePubKeys(nil)
+	require.Equal(t, ErrNoPubKeys, err)
+
+	_, err = AggregatePubKeys([]cipher.PubKey{{}})
+	require.Equal(t, cipher.ErrInvalidPubKey, err)
+}
+
+func TestSortPubKeys(t *testing.T) {
+	pks := make([]cipher.PubKey, 5)
+	for i := range pks {
+		pks[i], _ = cipher.GenerateKeyPair()
+	}
+
+	sorted := SortPubKeys(pks)
+	reversed := make([]cipher.PubKey, len(pks))
+	for i, pk := range pks {
+		reversed[len(pks)-1-i] = pk
+	}
+	require.Equal(t, sorted, SortPubKeys(reversed))
+
+	k1, err := AggregatePubKeys(sorted)
+	require.NoError(t, err)
+	k2, err := AggregatePubKeys(SortPubKeys(reversed))
+	require.NoError(t, err)
+	require.Equal(t, k1.SchnorrPubKey(), k2.SchnorrPubKey())
+}
+
+// sign runs the two rounds of MuSig2 signing with every secret key
+func sign(t *testing.T, secKeys []cipher.SecKey, hash cipher.SHA256) (*AggregateKey, cipher.SchnorrSig) {
+	pks := make([]cipher.PubKey, len(secKeys))
+	for i, sk := range secKeys {
+		pks[i] = cipher.MustPubKeyFromSecKey(sk)
+	}
+
+	aggKey, err := AggregatePubKeys(SortPubKeys(pks))
+	require.NoError(t, err)
+
+	secNonces := make([]*SecNonce, len(secKeys))
+	pubNonces := make([]PubNonce, len(secKeys))
+	for i, sk := range secKeys {
+		secNonces[i], pubNonces[i], err = NewNonce(sk, aggKey, hash)
+		require.NoError(t, err)
+	}
+
+	aggNonce, err := AggregateNonces(pubNonces)
+	require.NoError(t, err)
+
+	session, err := NewSession(aggKey, aggNonce, hash)
+	require.NoError(t, err)
+
+	psigs := make([]PartialSig, len(secKeys))
+	for i, sk := range secKeys {
+		psigs[i], err = session.Sign(secNonces[i], sk)
+		require.NoError(t, err)
+		require.NoError(t, session.VerifyPartialSig(psigs[i], pubNonces[i], pks[i]))
+	}
+
+	sig, err := session.AggregatePartialSigs(psigs)
+	require.NoError(t, err)
+
+	return aggKey, sig
+}
+
+func TestSign(t *testing.T) {
+	for n := 1; n <= 5; n++ {
+		// Repeat to cover both parities of the aggregate key and the final nonce
+		for i := 0; i < 8; i++ {
+			secKeys := make([]cipher.SecKey, n)
+			for j := range secKeys {
+				_, secKeys[j] = cipher.GenerateKeyPair()
+			}
+
+			hash := cipher.SumSHA256(cipher.RandByte(32))
+			aggKey, sig := sign(t, secKeys, hash)
+			require.NoError(t, cipher.VerifySchnorrSignedHash(aggKey.SchnorrPubKey(), sig, hash))
+
+			require.Equal(t, cipher.ErrInvalidSigForMessage,
+				cipher.VerifySchnorrSignedHash(aggKey.SchnorrPubKey(), sig, cipher.SumSHA256(hash[:])))
+		}
+	}
+
+	// The same signer twice
+	_, sk := cipher.GenerateKeyPair()
+	hash := cipher.SumSHA256([]byte("musig"))
+	aggKey, sig := sign(t, []cipher.SecKey{sk, sk}, hash)
+	require.NoError(t, cipher.VerifySchnorrSignedHash(aggKey.SchnorrPubKey(), sig, hash))
+}
+
+func TestSignErrors(t *testing.T) {
+	pk1, sk1 := cipher.GenerateKeyPair()
+	pk2, sk2 := cipher.GenerateKeyPair()
+	_, sk3 := cipher.GenerateKeyPair()
+	hash := cipher.SumSHA256([]byte("musig"))
+
+	aggKey, err := AggregatePubKeys(SortPubKeys([]cipher.PubKey{pk1, pk2}))
+	require.NoError(t, err)
+
+	_, _, err = NewNonce(sk3, aggKey, hash)
+	require.Equal(t, ErrNotSigner, err)
+
+	secNonce1, pubNonce1, err := NewNonce(sk1, aggKey, hash)
+	require.NoError(t, err)
+	secNonce2, pubNonce2, err := NewNonce(sk2, aggKey, hash)
+	require.NoError(t, err)
+
+	_, err = AggregateNonces(nil)
+	require.Equal(t, ErrNoPubNonces, err)
+	_, err = AggregateNonces([]PubNonce{pubNonce1, {}})
+	require.Equal(t, ErrInvalidPubNonce, err)
+
+	aggNonce, err := AggregateNonces([]PubNonce{pubNonce1, pubNonce2})
+	require.NoError(t, err)
+
+	badAggNonce := aggNonce
+	badAggNonce[0] = 0x04
+	_, err = NewSession(aggKey, badAggNonce, hash)
+	require.Equal(t, ErrInvalidAggNonce, err)
+
+	session, err := NewSession(aggKey, aggNonce, hash)
+	require.NoError(t, err)
+
+	// The secret nonce of another signer
+	_, err = session.Sign(secNonce2, sk1)
+	require.Equal(t, ErrSecNonceMismatch, err)
+	// The secret nonce was erased by the failed attempt
+	_, err = session.Sign(secNonce2, sk2)
+	require.Equal(t, ErrSecNonceUsed, err)
+	_, err = session.Sign(nil, sk2)
+	require.Equal(t, ErrSecNonceUsed, err)
+
+	psig1, err := session.Sign(secNonce1, sk1)
+	require.NoError(t, err)
+
+	// A secret nonce can not be used twice
+	_, err = session.Sign(secNonce1, sk1)
+	require.Equal(t, ErrSecNonceUsed, err)
+
+	require.NoError(t, session.VerifyPartialSig(psig1, pubNonce1, pk1))
+	require.Equal(t, ErrInvalidPartialSig, session.VerifyPartialSig(psig1, pubNonce2, pk2))
+	require.Equal(t, ErrInvalidPartialSig, session.VerifyPartialSig(psig1, pubNonce2, pk1))
+	require.Equal(t, ErrInvalidPartialSig, session.VerifyPartialSig(PartialSig{}, pubNonce1, pk1))
+	require.Equal(t, ErrNotSigner, session.VerifyPartialSig(psig1, pubNonce1, cipher.MustPubKeyFromSecKey(sk3)))
+
+	_, err = session.AggregatePartialSigs(nil)
+	require.Equal(t, ErrNoPartialSigs, err)
+
+	var overflow PartialSig
+	for i := range overflow {
+		overflow[i] = 0xff
+	}
+	_, err = session.AggregatePartialSigs([]PartialSig{psig1, overflow})
+	require.Equal(t, ErrInvalidPartialSig, err)
+
+	// A signature missing the partial signature of a signer is invalid
+	sig, err := session.AggregatePartialSigs([]PartialSig{psig1})
+	require.NoError(t, err)
+	require.Equal(t, cipher.ErrInvalidSigForMessage, cipher.VerifySchnorrSignedHash(aggKey.SchnorrPubKey(), sig, hash))
+}
+
+func TestNonceGenDeterministic(t *testing.T) {
+	pk1, sk1 := cipher.GenerateKeyPair()
+	pk2, _ := cipher.GenerateKeyPair()
+	hash := cipher.SumSHA256([]byte("musig"))
+
+	aggKey, err := AggregatePubKeys([]cipher.PubKey{pk1, pk2})
+	require.NoError(t, err)
+
+	rnd := make([]byte, 32)
+	_, n1, err := nonceGen(rnd, sk1, aggKey, hash)
+	require.NoError(t, err)
+	_, n2, err := nonceGen(rnd, sk1, aggKey, hash)
+	require.NoError(t, err)
+	require.Equal(t, n1, n2)
+
+	// Different randomness, message or aggregate key give different nonces
+	rnd[0] = 1
+	_, n3, err := nonceGen(rnd, sk1, aggKey, hash)
+	require.NoError(t, err)
+	require.NotEqual(t, n1, n3)
+
+	_, n4, err := nonceGen(make([]byte, 32), sk1, aggKey, cipher.SumSHA256(hash[:]))
+	require.NoError(t, err)
+	require.NotEqual(t, n1, n4)
+}
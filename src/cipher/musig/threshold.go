@@ -0,0 +1,138 @@
+package musig
+
+import (
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// MaxThresholdKeys is the maximum number of signer subsets ThresholdKeys aggregates,
+// e.g. a 2-of-3 has 3 subsets and a 8-of-16 has 12870
+const MaxThresholdKeys = 1024
+
+var (
+	// ErrInvalidThreshold the threshold is 0 or larger than the number of public keys
+	ErrInvalidThreshold = errors.New("MuSig: threshold must be between 1 and the number of public keys")
+	// ErrTooManyThresholdKeys the number of signer subsets is larger than MaxThresholdKeys
+	ErrTooManyThresholdKeys = errors.New("MuSig: too many signer subsets for the threshold")
+	// ErrDuplicatePubKey the same public key is provided twice
+	ErrDuplicatePubKey = errors.New("MuSig: duplicate public key")
+	// ErrNoThresholdKey no aggregate key of the signers, or no aggregate key verifies the signature
+	ErrNoThresholdKey = errors.New("MuSig: no aggregate key of the signers")
+)
+
+// ThresholdKeys returns the aggregate keys of every subset of m of the public keys, so that any m
+// of the N signers can sign with the aggregate key of their subset: a M-of-N threshold key tree made
+// of N-of-N MuSig2 keys, e.g. the 3 keys of the pairs of a 2-of-3. Each subset is aggregated sorted,
+// as AggregatePubKeys(SortPubKeys(subset)), and the subsets are ordered by the indexes of their
+// keys in the sorted public keys, so that the keys do not depend on the order of pubKeys.
+//
+// A signature made by a subset verifies against the aggregate key of that subset only,
+// VerifyThreshold checks it against every subset.
+func ThresholdKeys(pubKeys []cipher.PubKey, m int) ([]*AggregateKey, error) {
+	n := len(pubKeys)
+	if n == 0 {
+		return nil, ErrNoPubKeys
+	}
+
+	if m <= 0 || m > n {
+		return nil, ErrInvalidThreshold
+	}
+
+	if binomial(n, m) > MaxThresholdKeys {
+		return nil, ErrTooManyThresholdKeys
+	}
+
+	pks := SortPubKeys(pubKeys)
+	for i := 1; i < n; i++ {
+		if pks[i] == pks[i-1] {
+			return nil, ErrDuplicatePubKey
+		}
+	}
+
+	// Enumerates the subsets of indexes in lexicographic order
+	idx := make([]int, m)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	var keys []*AggregateKey
+	subset := make([]cipher.PubKey, m)
+	for {
+		for i, j := range idx {
+			subset[i] = pks[j]
+		}
+
+		k, err := AggregatePubKeys(subset)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+
+		// Advances the rightmost index that can be incremented
+		i := m - 1
+		for i >= 0 && idx[i] == n-m+i {
+			i--
+		}
+		if i < 0 {
+			return keys, nil
+		}
+
+		idx[i]++
+		for j := i + 1; j < m; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// SubsetKey returns the aggregate key of keys whose signers are the signers, in any order
+func SubsetKey(keys []*AggregateKey, signers []cipher.PubKey) (*AggregateKey, error) {
+	sorted := SortPubKeys(signers)
+	for _, k := range keys {
+		if len(k.pubKeys) != len(sorted) {
+			continue
+		}
+
+		match := true
+		for i, pk := range k.pubKeys {
+			if pk != sorted[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return k, nil
+		}
+	}
+
+	return nil, ErrNoThresholdKey
+}
+
+// VerifyThreshold verifies that sig is a signature of hash made by the signers of one of the aggregate
+// keys, e.g. of the ThresholdKeys of a M-of-N. Returns the aggregate key that verifies the signature.
+func VerifyThreshold(keys []*AggregateKey, sig cipher.SchnorrSig, hash cipher.SHA256) (*AggregateKey, error) {
+	for _, k := range keys {
+		if cipher.VerifySchnorrSignedHash(k.SchnorrPubKey(), sig, hash) == nil {
+			return k, nil
+		}
+	}
+
+	return nil, ErrNoThresholdKey
+}
+
+// binomial returns the number of subsets of k of n elements, capped above MaxThresholdKeys
+func binomial(n, k int) int {
+	if k > n-k {
+		k = n - k
+	}
+
+	c := 1
+	for i := 1; i <= k; i++ {
+		c = c * (n - k + i) / i
+		if c > MaxThresholdKeys {
+			return MaxThresholdKeys + 1
+		}
+	}
+	return c
+}
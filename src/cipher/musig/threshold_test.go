@@ -0,0 +1,127 @@
+package musig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestThresholdKeys(t *testing.T) {
+	secKeys := make([]cipher.SecKey, 4)
+	pks := make([]cipher.PubKey, len(secKeys))
+	for i := range secKeys {
+		pks[i], secKeys[i] = cipher.GenerateKeyPair()
+	}
+	sorted := SortPubKeys(pks)
+
+	cases := []struct {
+		m       int
+		subsets [][]int
+	}{
+		{
+			m:       1,
+			subsets: [][]int{{0}, {1}, {2}, {3}},
+		},
+		{
+			m:       2,
+			subsets: [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}},
+		},
+		{
+			m:       3,
+			subsets: [][]int{{0, 1, 2}, {0, 1, 3}, {0, 2, 3}, {1, 2, 3}},
+		},
+		{
+			m:       4,
+			subsets: [][]int{{0, 1, 2, 3}},
+		},
+	}
+
+	for _, tc := range cases {
+		keys, err := ThresholdKeys(pks, tc.m)
+		require.NoError(t, err)
+		require.Len(t, keys, len(tc.subsets))
+
+		for i, s := range tc.subsets {
+			subset := make([]cipher.PubKey, len(s))
+			for j, k := range s {
+				subset[j] = sorted[k]
+			}
+			require.Equal(t, subset, keys[i].PubKeys())
+
+			aggKey, err := AggregatePubKeys(subset)
+			require.NoError(t, err)
+			require.Equal(t, aggKey.SchnorrPubKey(), keys[i].SchnorrPubKey())
+		}
+	}
+
+	// The keys do not depend on the order of the public keys
+	keys, err := ThresholdKeys(pks, 2)
+	require.NoError(t, err)
+	keys2, err := ThresholdKeys([]cipher.PubKey{pks[3], pks[1], pks[0], pks[2]}, 2)
+	require.NoError(t, err)
+	require.Equal(t, keys, keys2)
+
+	_, err = ThresholdKeys(nil, 1)
+	require.Equal(t, ErrNoPubKeys, err)
+	_, err = ThresholdKeys(pks, 0)
+	require.Equal(t, ErrInvalidThreshold, err)
+	_, err = ThresholdKeys(pks, 5)
+	require.Equal(t, ErrInvalidThreshold, err)
+	_, err = ThresholdKeys([]cipher.PubKey{pks[0], pks[1], pks[0]}, 2)
+	require.Equal(t, ErrDuplicatePubKey, err)
+
+	// C(16, 8) = 12870 subsets
+	many := make([]cipher.PubKey, 16)
+	for i := range many {
+		many[i], _ = cipher.GenerateKeyPair()
+	}
+	_, err = ThresholdKeys(many, 8)
+	require.Equal(t, ErrTooManyThresholdKeys, err)
+	keys, err = ThresholdKeys(many, 15)
+	require.NoError(t, err)
+	require.Len(t, keys, 16)
+}
+
+func TestThresholdSign(t *testing.T) {
+	secKeys := make([]cipher.SecKey, 3)
+	pks := make([]cipher.PubKey, len(secKeys))
+	for i := range secKeys {
+		pks[i], secKeys[i] = cipher.GenerateKeyPair()
+	}
+
+	// 2-of-3
+	keys, err := ThresholdKeys(pks, 2)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	hash := cipher.SumSHA256([]byte("threshold"))
+	for _, signers := range [][]int{{0, 1}, {0, 2}, {2, 1}} {
+		sks := []cipher.SecKey{secKeys[signers[0]], secKeys[signers[1]]}
+		aggKey, sig := sign(t, sks, hash)
+
+		k, err := SubsetKey(keys, []cipher.PubKey{pks[signers[0]], pks[signers[1]]})
+		require.NoError(t, err)
+		require.Equal(t, aggKey.SchnorrPubKey(), k.SchnorrPubKey())
+
+		k, err = VerifyThreshold(keys, sig, hash)
+		require.NoError(t, err)
+		require.Equal(t, aggKey.SchnorrPubKey(), k.SchnorrPubKey())
+
+		_, err = VerifyThreshold(keys, sig, cipher.SumSHA256(hash[:]))
+		require.Equal(t, ErrNoThresholdKey, err)
+	}
+
+	// A single signer or a non signer has no key
+	_, err = SubsetKey(keys, pks[:1])
+	require.Equal(t, ErrNoThresholdKey, err)
+	other, otherSk := cipher.GenerateKeyPair()
+	_, err = SubsetKey(keys, []cipher.PubKey{pks[0], other})
+	require.Equal(t, ErrNoThresholdKey, err)
+
+	// A signature of a subset that is not in the keys does not verify
+	_, sig := sign(t, []cipher.SecKey{secKeys[0], otherSk}, hash)
+	_, err = VerifyThreshold(keys, sig, hash)
+	require.Equal(t, ErrNoThresholdKey, err)
+}
@@ -0,0 +1,89 @@
+package cipher
+
+import (
+	"errors"
+	"sync"
+
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+)
+
+var (
+	// ErrSigNotDeterministic the signature is not the RFC6979 deterministic signature of the hash
+	ErrSigNotDeterministic = errors.New("Signature does not match the deterministic signature for the hash and secret key")
+)
+
+// NonceRecord holds the inputs and output of the RFC6979 nonce derivation for a signature made by SignHash
+type NonceRecord struct {
+	Sig    Sig
+	Hash   SHA256
+	PubKey PubKey
+	// Attempt is the number of nonce candidates that were rejected before Nonce
+	Attempt int
+	// Nonce is the nonce k used for the signature.
+	// Anyone who knows the nonce of a signature can compute the secret key from the signature.
+	Nonce []byte
+}
+
+var (
+	nonceRecorder     func(NonceRecord)
+	nonceRecorderLock sync.RWMutex
+)
+
+// SetNonceRecorder enables the nonce debug mode. Every signature made by SignHash
+// is passed to f with its RFC6979 nonce derivation inputs, for auditing.
+// The records reveal the secret keys, so this must only be used with test keys.
+// Pass nil to disable the debug mode.
+func SetNonceRecorder(f func(NonceRecord)) {
+	nonceRecorderLock.Lock()
+	defer nonceRecorderLock.Unlock()
+	nonceRecorder = f
+}
+
+func recordNonce(sig Sig, hash SHA256, sec SecKey, attempt int) {
+	nonceRecorderLock.RLock()
+	f := nonceRecorder
+	nonceRecorderLock.RUnlock()
+
+	if f == nil {
+		return
+	}
+
+	f(NonceRecord{
+		Sig:     sig,
+		Hash:    hash,
+		PubKey:  MustPubKeyFromSecKey(sec),
+		Attempt: attempt,
+		Nonce:   secp256k1.NonceRFC6979(hash[:], sec[:], attempt),
+	})
+}
+
+// RFC6979Nonce returns the nonce k that SignHash uses to sign hash with sec.
+// The nonce is generated deterministically from the hash and secret key, as specified by RFC6979 with HMAC-SHA256.
+// In the unlikely case that a nonce candidate produces an invalid signature, SignHash uses the next candidate,
+// which NonceRecord.Attempt reports.
+func RFC6979Nonce(hash SHA256, sec SecKey) ([]byte, error) {
+	if secp256k1.VerifySeckey(sec[:]) != 1 {
+		return nil, ErrInvalidSecKey
+	}
+
+	if hash.Null() {
+		return nil, ErrNullSignHash
+	}
+
+	return secp256k1.NonceRFC6979(hash[:], sec[:], 0), nil
+}
+
+// VerifyDeterministicSignedHash verifies that sig is the deterministic RFC6979 signature
+// of hash by sec, i.e. the signature that SignHash returns
+func VerifyDeterministicSignedHash(sig Sig, hash SHA256, sec SecKey) error {
+	expected, err := SignHash(hash, sec)
+	if err != nil {
+		return err
+	}
+
+	if expected != sig {
+		return ErrSigNotDeterministic
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cipher
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRFC6979Nonce(t *testing.T) {
+	// secp256k1 RFC6979 test vector: seckey 1, message "Satoshi Nakamoto"
+	sec := MustSecKeyFromHex("0000000000000000000000000000000000000000000000000000000000000001")
+	hash := SumSHA256([]byte("Satoshi Nakamoto"))
+
+	nonce, err := RFC6979Nonce(hash, sec)
+	require.NoError(t, err)
+	require.Equal(t, "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15", hex.EncodeToString(nonce))
+
+	sig := MustSignHash(hash, sec)
+	require.Equal(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5", hex.EncodeToString(sig[:64]))
+	require.Equal(t, sig, MustSignHash(hash, sec))
+
+	_, err = RFC6979Nonce(hash, SecKey{})
+	require.Equal(t, ErrInvalidSecKey, err)
+	_, err = RFC6979Nonce(SHA256{}, sec)
+	require.Equal(t, ErrNullSignHash, err)
+}
+
+func TestVerifyDeterministicSignedHash(t *testing.T) {
+	_, sec := GenerateKeyPair()
+	hash := SumSHA256(randBytes(t, 128))
+	sig := MustSignHash(hash, sec)
+
+	require.NoError(t, VerifyDeterministicSignedHash(sig, hash, sec))
+
+	_, sec2 := GenerateKeyPair()
+	require.Equal(t, ErrSigNotDeterministic, VerifyDeterministicSignedHash(sig, hash, sec2))
+	require.Equal(t, ErrSigNotDeterministic, VerifyDeterministicSignedHash(sig, SumSHA256(hash[:]), sec))
+	require.Equal(t, ErrInvalidSecKey, VerifyDeterministicSignedHash(sig, hash, SecKey{}))
+}
+
+func TestSetNonceRecorder(t *testing.T) {
+	// GenerateKeyPair makes test signatures, so generate the key before recording
+	pub, sec := GenerateKeyPair()
+	hash := SumSHA256(randBytes(t, 128))
+
+	var records []NonceRecord
+	SetNonceRecorder(func(r NonceRecord) {
+		records = append(records, r)
+	})
+	defer SetNonceRecorder(nil)
+
+	sig := MustSignHash(hash, sec)
+
+	require.Len(t, records, 1)
+	nonce, err := RFC6979Nonce(hash, sec)
+	require.NoError(t, err)
+	require.Equal(t, NonceRecord{
+		Sig:     sig,
+		Hash:    hash,
+		PubKey:  pub,
+		Attempt: 0,
+		Nonce:   nonce,
+	}, records[0])
+
+	SetNonceRecorder(nil)
+	MustSignHash(hash, sec)
+	require.Len(t, records, 1)
+}
@@ -0,0 +1,252 @@
+package cipher
+
+import (
+	"encoding/hex"
+	"errors"
+	"log"
+
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+)
+
+var (
+	// ErrInvalidLengthSchnorrPubKey Invalid Schnorr public key length
+	ErrInvalidLengthSchnorrPubKey = errors.New("Invalid Schnorr public key length")
+	// ErrInvalidLengthSchnorrSig Invalid Schnorr signature length
+	ErrInvalidLengthSchnorrSig = errors.New("Invalid Schnorr signature length")
+	// ErrInvalidSchnorrSig Invalid Schnorr signature
+	ErrInvalidSchnorrSig = errors.New("Invalid Schnorr signature")
+	// ErrSchnorrBatchLengthMismatch The number of pubkeys, signatures and hashes of a batch do not match
+	ErrSchnorrBatchLengthMismatch = errors.New("Schnorr batch pubkeys, signatures and hashes lengths do not match")
+)
+
+// SchnorrPubKey x-only BIP340 public key, the x coordinate of the public key point with an even y coordinate
+type SchnorrPubKey [32]byte
+
+// NewSchnorrPubKey converts []byte to a SchnorrPubKey
+func NewSchnorrPubKey(b []byte) (SchnorrPubKey, error) {
+	p := SchnorrPubKey{}
+	if len(b) != len(p) {
+		return SchnorrPubKey{}, ErrInvalidLengthSchnorrPubKey
+	}
+	copy(p[:], b[:])
+
+	if err := p.Verify(); err != nil {
+		return SchnorrPubKey{}, err
+	}
+
+	return p, nil
+}
+
+// MustNewSchnorrPubKey converts []byte to a SchnorrPubKey, panics on error
+func MustNewSchnorrPubKey(b []byte) SchnorrPubKey {
+	p, err := NewSchnorrPubKey(b)
+	if err != nil {
+		log.Panic(err)
+	}
+	return p
+}
+
+// SchnorrPubKeyFromHex generates SchnorrPubKey from hex string
+func SchnorrPubKeyFromHex(s string) (SchnorrPubKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return SchnorrPubKey{}, ErrInvalidPubKey
+	}
+	return NewSchnorrPubKey(b)
+}
+
+// MustSchnorrPubKeyFromHex decodes a hex encoded SchnorrPubKey, panics on error
+func MustSchnorrPubKeyFromHex(s string) SchnorrPubKey {
+	p, err := SchnorrPubKeyFromHex(s)
+	if err != nil {
+		log.Panic(err)
+	}
+	return p
+}
+
+// SchnorrPubKeyFromSecKey recovers the SchnorrPubKey from a secret key
+func SchnorrPubKeyFromSecKey(seckey SecKey) (SchnorrPubKey, error) {
+	if seckey == (SecKey{}) {
+		return SchnorrPubKey{}, ErrPubKeyFromNullSecKey
+	}
+
+	if secp256k1.VerifySeckey(seckey[:]) != 1 {
+		return SchnorrPubKey{}, ErrInvalidSecKey
+	}
+
+	b := secp256k1.SchnorrPubkeyFromSeckey(seckey[:])
+	if b == nil {
+		return SchnorrPubKey{}, ErrPubKeyFromBadSecKey
+	}
+
+	return NewSchnorrPubKey(b)
+}
+
+// MustSchnorrPubKeyFromSecKey recovers the SchnorrPubKey from a secret key, panics on error
+func MustSchnorrPubKeyFromSecKey(seckey SecKey) SchnorrPubKey {
+	p, err := SchnorrPubKeyFromSecKey(seckey)
+	if err != nil {
+		log.Panic(err)
+	}
+	return p
+}
+
+// SchnorrPubKeyFromPubKey returns the SchnorrPubKey of the compressed public key,
+// which is its x coordinate. Both public keys of a secret key and its negation have the same SchnorrPubKey.
+func SchnorrPubKeyFromPubKey(pubkey PubKey) (SchnorrPubKey, error) {
+	if err := pubkey.Verify(); err != nil {
+		return SchnorrPubKey{}, err
+	}
+	return NewSchnorrPubKey(pubkey[1:])
+}
+
+// Verify attempts to determine if the SchnorrPubKey is the x coordinate of a point on the curve
+func (p SchnorrPubKey) Verify() error {
+	if secp256k1.SchnorrVerifyPubkey(p[:]) != 1 {
+		return ErrInvalidPubKey
+	}
+	return nil
+}
+
+// Hex returns a hex encoded SchnorrPubKey string
+func (p SchnorrPubKey) Hex() string {
+	return hex.EncodeToString(p[:])
+}
+
+// Null returns true if SchnorrPubKey is the null SchnorrPubKey
+func (p SchnorrPubKey) Null() bool {
+	return p == SchnorrPubKey{}
+}
+
+// SchnorrSig 64 byte BIP340 Schnorr signature
+type SchnorrSig [64]byte
+
+// NewSchnorrSig converts []byte to a SchnorrSig
+func NewSchnorrSig(b []byte) (SchnorrSig, error) {
+	s := SchnorrSig{}
+	if len(b) != len(s) {
+		return SchnorrSig{}, ErrInvalidLengthSchnorrSig
+	}
+	copy(s[:], b[:])
+	return s, nil
+}
+
+// MustNewSchnorrSig converts []byte to a SchnorrSig. Panics is []byte is not the exact size
+func MustNewSchnorrSig(b []byte) SchnorrSig {
+	s, err := NewSchnorrSig(b)
+	if err != nil {
+		log.Panic(err)
+	}
+	return s
+}
+
+// SchnorrSigFromHex converts a hex string to a Schnorr signature
+func SchnorrSigFromHex(s string) (SchnorrSig, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return SchnorrSig{}, ErrInvalidSchnorrSig
+	}
+	return NewSchnorrSig(b)
+}
+
+// MustSchnorrSigFromHex converts a hex string to a Schnorr signature, panics on error
+func MustSchnorrSigFromHex(s string) SchnorrSig {
+	sig, err := SchnorrSigFromHex(s)
+	if err != nil {
+		log.Panic(err)
+	}
+	return sig
+}
+
+func (s SchnorrSig) String() string {
+	return s.Hex()
+}
+
+// Null returns true if the SchnorrSig is a null SchnorrSig
+func (s SchnorrSig) Null() bool {
+	return s == SchnorrSig{}
+}
+
+// Hex converts the Schnorr signature to hex string
+func (s SchnorrSig) Hex() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SignHashSchnorr signs the hash with a BIP340 Schnorr signature
+func SignHashSchnorr(hash SHA256, sec SecKey) (SchnorrSig, error) {
+	if secp256k1.VerifySeckey(sec[:]) != 1 {
+		return SchnorrSig{}, ErrInvalidSecKey
+	}
+
+	// Null hashes can't be signed
+	if hash.Null() {
+		return SchnorrSig{}, ErrNullSignHash
+	}
+
+	sig, err := NewSchnorrSig(secp256k1.SchnorrSign(hash[:], sec[:]))
+	if err != nil {
+		return SchnorrSig{}, err
+	}
+
+	if DebugLevel2 || DebugLevel1 {
+		// Guard against coin loss;
+		// if the generated signature is somehow invalid, coins would be lost,
+		// make sure that the signature is valid
+		pubkey, err := SchnorrPubKeyFromSecKey(sec)
+		if err != nil {
+			log.Panic("SignHashSchnorr error: pubkey from seckey failure")
+		}
+		if VerifySchnorrSignedHash(pubkey, sig, hash) != nil {
+			log.Panic("SignHashSchnorr error: secp256k1.SchnorrSign returned invalid signature")
+		}
+	}
+
+	return sig, nil
+}
+
+// MustSignHashSchnorr signs the hash with a BIP340 Schnorr signature, panics on error
+func MustSignHashSchnorr(hash SHA256, sec SecKey) SchnorrSig {
+	sig, err := SignHashSchnorr(hash, sec)
+	if err != nil {
+		log.Panic(err)
+	}
+	return sig
+}
+
+// VerifySchnorrSignedHash verifies that hash was signed by SchnorrPubKey with a Schnorr signature
+func VerifySchnorrSignedHash(pubkey SchnorrPubKey, sig SchnorrSig, hash SHA256) error {
+	if err := pubkey.Verify(); err != nil {
+		return ErrInvalidSigInvalidPubKey
+	}
+	if secp256k1.SchnorrVerifySignature(hash[:], sig[:], pubkey[:]) != 1 {
+		return ErrInvalidSigForMessage
+	}
+	return nil
+}
+
+// VerifySchnorrSignedHashes verifies that each hash was signed by the SchnorrPubKey at the same index
+// with the Schnorr signature at the same index. The signatures are verified at once with batch verification,
+// which is faster than verifying them one by one, but does not tell which signature is invalid.
+func VerifySchnorrSignedHashes(pubkeys []SchnorrPubKey, sigs []SchnorrSig, hashes []SHA256) error {
+	if len(pubkeys) != len(sigs) || len(pubkeys) != len(hashes) {
+		return ErrSchnorrBatchLengthMismatch
+	}
+
+	msgs := make([][]byte, len(hashes))
+	rawSigs := make([][]byte, len(sigs))
+	rawPubKeys := make([][]byte, len(pubkeys))
+	for i := range hashes {
+		if err := pubkeys[i].Verify(); err != nil {
+			return ErrInvalidSigInvalidPubKey
+		}
+
+		msgs[i] = hashes[i][:]
+		rawSigs[i] = sigs[i][:]
+		rawPubKeys[i] = pubkeys[i][:]
+	}
+
+	if secp256k1.SchnorrBatchVerifySignatures(msgs, rawSigs, rawPubKeys) != 1 {
+		return ErrInvalidSigForMessage
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package cipher
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchnorrPubKey(t *testing.T) {
+	_, err := NewSchnorrPubKey(randBytes(t, 31))
+	require.Equal(t, ErrInvalidLengthSchnorrPubKey, err)
+	_, err = NewSchnorrPubKey(randBytes(t, 33))
+	require.Equal(t, ErrInvalidLengthSchnorrPubKey, err)
+
+	// Not on the curve
+	_, err = NewSchnorrPubKey(mustDecodeHex(t, "EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34"))
+	require.Equal(t, ErrInvalidPubKey, err)
+
+	// Exceeds the field size
+	_, err = NewSchnorrPubKey(mustDecodeHex(t, "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30"))
+	require.Equal(t, ErrInvalidPubKey, err)
+
+	p, err := NewSchnorrPubKey(mustDecodeHex(t, "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659"))
+	require.NoError(t, err)
+	require.Equal(t, "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659", p.Hex())
+
+	p2, err := SchnorrPubKeyFromHex(p.Hex())
+	require.NoError(t, err)
+	require.Equal(t, p, p2)
+
+	_, err = SchnorrPubKeyFromHex("xx")
+	require.Equal(t, ErrInvalidPubKey, err)
+
+	require.Panics(t, func() { MustNewSchnorrPubKey(randBytes(t, 31)) })
+	require.Panics(t, func() { MustSchnorrPubKeyFromHex("xx") })
+}
+
+func TestSchnorrPubKeyFromSecKey(t *testing.T) {
+	// BIP340 test vector 1
+	sk := MustSecKeyFromHex("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef")
+	p, err := SchnorrPubKeyFromSecKey(sk)
+	require.NoError(t, err)
+	require.Equal(t, MustSchnorrPubKeyFromHex("dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659"), p)
+
+	_, err = SchnorrPubKeyFromSecKey(SecKey{})
+	require.Equal(t, ErrPubKeyFromNullSecKey, err)
+	require.Panics(t, func() { MustSchnorrPubKeyFromSecKey(SecKey{}) })
+
+	for i := 0; i < 10; i++ {
+		pk, sk := GenerateKeyPair()
+		p, err := SchnorrPubKeyFromSecKey(sk)
+		require.NoError(t, err)
+
+		p2, err := SchnorrPubKeyFromPubKey(pk)
+		require.NoError(t, err)
+		require.Equal(t, p, p2)
+	}
+}
+
+func TestNewSchnorrSig(t *testing.T) {
+	_, err := NewSchnorrSig(randBytes(t, 65))
+	require.Equal(t, ErrInvalidLengthSchnorrSig, err)
+	_, err = NewSchnorrSig(randBytes(t, 63))
+	require.Equal(t, ErrInvalidLengthSchnorrSig, err)
+
+	b := randBytes(t, 64)
+	s, err := NewSchnorrSig(b)
+	require.NoError(t, err)
+	require.Equal(t, b, s[:])
+	require.False(t, s.Null())
+	require.True(t, SchnorrSig{}.Null())
+
+	s2, err := SchnorrSigFromHex(s.Hex())
+	require.NoError(t, err)
+	require.Equal(t, s, s2)
+	require.Equal(t, s.Hex(), s.String())
+
+	_, err = SchnorrSigFromHex("xx")
+	require.Equal(t, ErrInvalidSchnorrSig, err)
+
+	require.Panics(t, func() { MustNewSchnorrSig(randBytes(t, 63)) })
+	require.Panics(t, func() { MustSchnorrSigFromHex("xx") })
+}
+
+func TestSignHashSchnorr(t *testing.T) {
+	_, sk := GenerateKeyPair()
+	h := SumSHA256(randBytes(t, 256))
+
+	_, err := SignHashSchnorr(SHA256{}, sk)
+	require.Equal(t, ErrNullSignHash, err)
+
+	_, err = SignHashSchnorr(h, SecKey{})
+	require.Equal(t, ErrInvalidSecKey, err)
+	require.Panics(t, func() { MustSignHashSchnorr(h, SecKey{}) })
+
+	p := MustSchnorrPubKeyFromSecKey(sk)
+	sig, err := SignHashSchnorr(h, sk)
+	require.NoError(t, err)
+	require.NoError(t, VerifySchnorrSignedHash(p, sig, h))
+
+	// The signatures use fresh auxiliary randomness
+	sig2 := MustSignHashSchnorr(h, sk)
+	require.NotEqual(t, sig, sig2)
+	require.NoError(t, VerifySchnorrSignedHash(p, sig2, h))
+}
+
+func TestVerifySchnorrSignedHash(t *testing.T) {
+	// BIP340 test vector 2
+	p := MustSchnorrPubKeyFromHex("dd308afec5777e13121fa72b9cc1b7cc0139715309b086c960e18fd969774eb8")
+	h := MustSHA256FromHex("7e2d58d8b3bcdf1abadec7829054f90dda9805aab56c77333024b9d0a508b75c")
+	sig := MustSchnorrSigFromHex("5831aaeed7b44bb74e5eab94ba9d4294c49bcf2a60728d8b4c200f50dd313c1bab745879a5ad954a72c45a91c3a51d3c7adea98d82f8481e0e1e03674a6f3fb7")
+	require.NoError(t, VerifySchnorrSignedHash(p, sig, h))
+
+	h2 := h
+	h2[0] ^= 1
+	require.Equal(t, ErrInvalidSigForMessage, VerifySchnorrSignedHash(p, sig, h2))
+
+	sig2 := sig
+	sig2[63] ^= 1
+	require.Equal(t, ErrInvalidSigForMessage, VerifySchnorrSignedHash(p, sig2, h))
+
+	require.Equal(t, ErrInvalidSigInvalidPubKey, VerifySchnorrSignedHash(SchnorrPubKey{}, sig, h))
+
+	// A Schnorr signature is not valid for another pubkey
+	_, sk := GenerateKeyPair()
+	require.Equal(t, ErrInvalidSigForMessage, VerifySchnorrSignedHash(MustSchnorrPubKeyFromSecKey(sk), sig, h))
+}
+
+func TestVerifySchnorrSignedHashes(t *testing.T) {
+	n := 10
+	pubkeys := make([]SchnorrPubKey, n)
+	sigs := make([]SchnorrSig, n)
+	hashes := make([]SHA256, n)
+	for i := 0; i < n; i++ {
+		_, sk := GenerateKeyPair()
+		pubkeys[i] = MustSchnorrPubKeyFromSecKey(sk)
+		hashes[i] = SumSHA256(randBytes(t, 32))
+		sigs[i] = MustSignHashSchnorr(hashes[i], sk)
+	}
+
+	require.NoError(t, VerifySchnorrSignedHashes(pubkeys, sigs, hashes))
+	require.NoError(t, VerifySchnorrSignedHashes(pubkeys[:1], sigs[:1], hashes[:1]))
+	require.NoError(t, VerifySchnorrSignedHashes(nil, nil, nil))
+
+	require.Equal(t, ErrSchnorrBatchLengthMismatch, VerifySchnorrSignedHashes(pubkeys[:1], sigs, hashes))
+	require.Equal(t, ErrSchnorrBatchLengthMismatch, VerifySchnorrSignedHashes(pubkeys, sigs, hashes[:1]))
+
+	// One invalid signature invalidates the batch
+	for i := 0; i < n; i++ {
+		badSigs := append([]SchnorrSig{}, sigs...)
+		badSigs[i][40] ^= 1
+		require.Equal(t, ErrInvalidSigForMessage, VerifySchnorrSignedHashes(pubkeys, badSigs, hashes))
+	}
+
+	badHashes := append([]SHA256{}, hashes...)
+	badHashes[n-1], badHashes[0] = badHashes[0], badHashes[n-1]
+	require.Equal(t, ErrInvalidSigForMessage, VerifySchnorrSignedHashes(pubkeys, sigs, badHashes))
+
+	badPubKeys := append([]SchnorrPubKey{}, pubkeys...)
+	badPubKeys[3] = SchnorrPubKey{}
+	require.Equal(t, ErrInvalidSigInvalidPubKey, VerifySchnorrSignedHashes(badPubKeys, sigs, hashes))
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
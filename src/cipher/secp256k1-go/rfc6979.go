@@ -0,0 +1,102 @@
+package secp256k1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"log"
+
+	secp "github.com/skycoin/skycoin/src/cipher/secp256k1-go/secp256k1-go2"
+)
+
+// rfc6979 generates the candidate nonces of RFC6979 section 3.2, with HMAC-SHA256,
+// for a 32 byte secret key and a 32 byte message hash
+type rfc6979 struct {
+	k []byte
+	v []byte
+	// started is true once the first candidate has been generated
+	started bool
+}
+
+func newRFC6979(msg, seckey []byte) *rfc6979 {
+	// bits2octets(h1): the hash reduced modulo the curve order
+	var h secp.Number
+	h.SetBytes(msg)
+	if h.Cmp(&secp.TheCurve.Order.Int) >= 0 {
+		h.Sub(&h.Int, &secp.TheCurve.Order.Int)
+	}
+	hb := numberBytes32(h)
+
+	g := &rfc6979{
+		k: make([]byte, 32),
+		v: make([]byte, 32),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+
+	g.k = g.mac(g.v, []byte{0x00}, seckey, hb)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, seckey, hb)
+	g.v = g.mac(g.v)
+
+	return g
+}
+
+func (g *rfc6979) mac(data ...[]byte) []byte {
+	m := hmac.New(sha256.New, g.k)
+	for _, d := range data {
+		m.Write(d) //nolint:errcheck
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next candidate nonce k, with 0 < k < n, where n is the order of the curve
+func (g *rfc6979) next() secp.Number {
+	for {
+		if g.started {
+			g.k = g.mac(g.v, []byte{0x00})
+			g.v = g.mac(g.v)
+		}
+		g.started = true
+
+		g.v = g.mac(g.v)
+
+		var k secp.Number
+		k.SetBytes(g.v)
+		if k.Sign() != 0 && k.Cmp(&secp.TheCurve.Order.Int) < 0 {
+			return k
+		}
+	}
+}
+
+// NonceRFC6979 returns the deterministic nonce that Sign uses to sign msg with seckey,
+// generated as specified by RFC6979 with HMAC-SHA256.
+// attempt is the number of candidates that were rejected before it, usually 0.
+// Sign rejects a candidate if the signature made with it has a zero S value.
+func NonceRFC6979(msg, seckey []byte, attempt int) []byte {
+	if len(seckey) != 32 {
+		log.Panic("NonceRFC6979, Invalid seckey length")
+	}
+	if len(msg) != 32 {
+		log.Panic("NonceRFC6979, message must be 32 bytes")
+	}
+	if attempt < 0 {
+		log.Panic("NonceRFC6979, attempt must not be negative")
+	}
+
+	g := newRFC6979(msg, seckey)
+	k := g.next()
+	for i := 0; i < attempt; i++ {
+		k = g.next()
+	}
+
+	return numberBytes32(k)
+}
+
+// numberBytes32 returns n as 32 big-endian bytes
+func numberBytes32(n secp.Number) []byte {
+	b := make([]byte, 32)
+	nb := n.Bytes()
+	copy(b[32-len(nb):], nb)
+	return b
+}
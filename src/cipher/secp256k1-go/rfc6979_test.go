@@ -0,0 +1,77 @@
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors for secp256k1 with SHA256 message hashes, shared by several RFC6979 implementations
+var rfc6979Cases = []struct {
+	seckey string
+	msg    string
+	nonce  string
+	sig    string
+}{
+	{
+		seckey: "0000000000000000000000000000000000000000000000000000000000000001",
+		msg:    "Satoshi Nakamoto",
+		nonce:  "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+		sig:    "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+	},
+	{
+		seckey: "0000000000000000000000000000000000000000000000000000000000000001",
+		msg:    "All those moments will be lost in time, like tears in rain. Time to die...",
+		nonce:  "38aa22d72376b4dbc472e06c3ba403ee0a394da63fc58d88686c611aba98d6b3",
+		sig:    "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+	},
+	{
+		seckey: "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+		msg:    "Satoshi Nakamoto",
+		nonce:  "33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90",
+	},
+	{
+		seckey: "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181",
+		msg:    "Alan Turing",
+		nonce:  "525a82b70e67874398067543fd84c83d30c175fdc45fdeee082fe13b1d7cfdf1",
+		sig:    "7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+	},
+}
+
+func TestNonceRFC6979(t *testing.T) {
+	for _, tc := range rfc6979Cases {
+		t.Run(tc.msg, func(t *testing.T) {
+			seckey, err := hex.DecodeString(tc.seckey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := sha256.Sum256([]byte(tc.msg))
+
+			nonce := NonceRFC6979(msg[:], seckey, 0)
+			if hex.EncodeToString(nonce) != tc.nonce {
+				t.Fatalf("nonce %x != %s", nonce, tc.nonce)
+			}
+
+			// The next candidates are different
+			if bytes.Equal(nonce, NonceRFC6979(msg[:], seckey, 1)) {
+				t.Fatal("nonce candidates should not repeat")
+			}
+
+			sig, attempt := SignRFC6979(msg[:], seckey)
+			if attempt != 0 {
+				t.Fatalf("attempt %d != 0", attempt)
+			}
+			if tc.sig != "" && hex.EncodeToString(sig[:64]) != tc.sig {
+				t.Fatalf("signature %x != %s", sig[:64], tc.sig)
+			}
+
+			if !bytes.Equal(sig, Sign(msg[:], seckey)) {
+				t.Fatal("signatures should be deterministic")
+			}
+			if VerifySignature(msg[:], sig, PubkeyFromSeckey(seckey)) != 1 {
+				t.Fatal("signature should be valid")
+			}
+		})
+	}
+}
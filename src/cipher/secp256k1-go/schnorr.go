@@ -0,0 +1,84 @@
+package secp256k1
+
+import (
+	"log"
+
+	secp "github.com/skycoin/skycoin/src/cipher/secp256k1-go/secp256k1-go2"
+)
+
+// SchnorrPubkeyFromSeckey returns the 32 byte x-only BIP340 public key of the seckey
+func SchnorrPubkeyFromSeckey(seckey []byte) []byte {
+	if len(seckey) != 32 {
+		log.Panic("SchnorrPubkeyFromSeckey, invalid seckey length")
+	}
+	return secp.SchnorrPubkey(seckey)
+}
+
+// SchnorrVerifyPubkey verifies that the 32 byte x-only pubkey is the x coordinate of a point on the curve
+// Returns 1 on success
+func SchnorrVerifyPubkey(pubkey []byte) int {
+	if len(pubkey) != 32 {
+		return -2
+	}
+	if secp.SchnorrPubkeyIsValid(pubkey) {
+		return 1
+	}
+	return 0
+}
+
+// SchnorrSign signs the 32 byte hash with a BIP340 Schnorr signature, using fresh auxiliary randomness.
+// Returns a 64 byte signature
+func SchnorrSign(msg []byte, seckey []byte) []byte {
+	if len(seckey) != 32 {
+		log.Panic("SchnorrSign, Invalid seckey length")
+	}
+	if secp.SeckeyIsValid(seckey) != 1 {
+		log.Panic("Attempting to sign with invalid seckey")
+	}
+	if len(msg) != 32 {
+		log.Panic("SchnorrSign, message must be 32 bytes")
+	}
+
+	sig := secp.SchnorrSign(msg, seckey, RandByte(32))
+	if sig == nil {
+		log.Panic("Secp25k1-go, SchnorrSign, signature operation failed")
+	}
+	return sig
+}
+
+// SchnorrVerifySignature verifies the BIP340 Schnorr signature of the hash for the x-only pubkey
+// Returns 1 on success
+func SchnorrVerifySignature(msg []byte, sig []byte, pubkey []byte) int {
+	if len(sig) != 64 {
+		log.Panic("SchnorrVerifySignature, invalid signature length")
+	}
+	if len(pubkey) != 32 {
+		log.Panic("SchnorrVerifySignature, invalid pubkey length")
+	}
+
+	if secp.SchnorrVerify(msg, pubkey, sig) {
+		return 1
+	}
+	return 0
+}
+
+// SchnorrBatchVerifySignatures verifies the BIP340 Schnorr signatures of the hashes for the x-only pubkeys at once
+// Returns 1 if all signatures are valid
+func SchnorrBatchVerifySignatures(msgs, sigs, pubkeys [][]byte) int {
+	if len(msgs) != len(sigs) || len(msgs) != len(pubkeys) {
+		log.Panic("SchnorrBatchVerifySignatures, mismatched input lengths")
+	}
+	for i := range sigs {
+		if len(sigs[i]) != 64 {
+			log.Panic("SchnorrBatchVerifySignatures, invalid signature length")
+		}
+		if len(pubkeys[i]) != 32 {
+			log.Panic("SchnorrBatchVerifySignatures, invalid pubkey length")
+		}
+	}
+
+	if secp.SchnorrBatchVerify(msgs, pubkeys, sigs) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,17 @@
+// +build !secp256k1ct
+
+package secp256k1go
+
+// ConstantTime is true if the package is built with the secp256k1ct build tag,
+// which computes the secret key operations of signing in constant time
+const ConstantTime = false
+
+// secretMultGen r = a*G, for a secret scalar a
+func secretMultGen(a Number) XYZ {
+	return ECmultGen(a)
+}
+
+// secretModInv sets num = a^-1 mod m, for a secret a
+func (num *Number) secretModInv(a, m *Number) {
+	num.modInv(a, m)
+}
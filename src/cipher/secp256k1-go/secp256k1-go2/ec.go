@@ -146,7 +146,7 @@ func GeneratePublicKey(k []byte) []byte {
 		log.Panic("only call for valid seckey, check that seckey is valid first")
 		return nil
 	}
-	r := ECmultGen(n)
+	r := secretMultGen(n)
 	pk.SetXYZ(&r)
 	if !pk.IsValid() {
 		log.Panic("public key derived from secret key is unexpectedly valid")
@@ -0,0 +1,79 @@
+package secp256k1go
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"log"
+)
+
+// ecmultGenConstTime r = a*G, in constant time.
+// Like ECmultGen it adds one precomputed point per 4 bit window of the scalar,
+// but each point is selected by scanning the entire window of the table with masked copies,
+// so the memory access pattern does not depend on the scalar.
+// The table points are offset so that the additions never hit the point at infinity,
+// except with negligible probability.
+func ecmultGenConstTime(a Number) XYZ {
+	// The scalar is read from its fixed size big-endian bytes, not from its big.Int words
+	b := LeftPadBytes(a.Bytes(), 32)
+
+	var r XYZ
+	var p XY
+	for j := 0; j < 64; j++ {
+		// Window j is the j-th nibble counted from the least significant one
+		nibble := int(b[31-j/2])
+		if j%2 == 1 {
+			nibble >>= 4
+		}
+		nibble &= 0xF
+
+		for i := 0; i < 16; i++ {
+			p.cmov(&prec[j][i], subtle.ConstantTimeEq(int32(i), int32(nibble)))
+		}
+
+		if j == 0 {
+			r.SetXY(&p)
+		} else {
+			r.AddXY(&r, &p)
+		}
+	}
+	r.AddXY(&r, &fin)
+
+	for i := range b {
+		b[i] = 0
+	}
+	return r
+}
+
+// modInvBlinded sets num = a^-1 mod m.
+// The inverse is computed of a*k for a random k and multiplied by k afterwards,
+// so the timing of the variable time inversion does not depend on a.
+func (num *Number) modInvBlinded(a, m *Number) {
+	var k Number
+	for k.Sign() == 0 || k.Cmp(&m.Int) >= 0 {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			log.Panic(err)
+		}
+		k.SetBytes(b)
+	}
+
+	var ak Number
+	ak.modMul(a, &k, m)
+	num.modInv(&ak, m)
+	num.modMul(num, &k, m)
+}
+
+// cmov sets the coordinates of xy to those of a if flag is 1 and leaves them unchanged if flag is 0, in constant time.
+// The Infinity flag is not copied, a must not be the point at infinity.
+func (xy *XY) cmov(a *XY, flag int) {
+	xy.X.cmov(&a.X, flag)
+	xy.Y.cmov(&a.Y, flag)
+}
+
+// cmov sets a to b if flag is 1 and leaves it unchanged if flag is 0, in constant time
+func (a *Field) cmov(b *Field, flag int) {
+	mask := uint32(-flag)
+	for i := range a.n {
+		a.n[i] = (a.n[i] &^ mask) | (b.n[i] & mask)
+	}
+}
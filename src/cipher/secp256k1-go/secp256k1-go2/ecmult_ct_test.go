@@ -0,0 +1,69 @@
+package secp256k1go
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestECmultGenConstTime(t *testing.T) {
+	scalars := []string{
+		"1",
+		"2",
+		"F",
+		"10",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364140", // n-1
+		"8000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	var ns []Number
+	for _, s := range scalars {
+		var n Number
+		n.SetHex(s)
+		ns = append(ns, n)
+	}
+
+	for i := 0; i < 64; i++ {
+		b := make([]byte, 32)
+		_, err := rand.Read(b)
+		require.NoError(t, err)
+		var n Number
+		n.SetBytes(b)
+		n.mod(&TheCurve.Order)
+		ns = append(ns, n)
+	}
+
+	for _, n := range ns {
+		var p, q XY
+		a := ECmultGen(n)
+		b := ecmultGenConstTime(n)
+		p.SetXYZ(&a)
+		q.SetXYZ(&b)
+		p.X.Normalize()
+		p.Y.Normalize()
+		q.X.Normalize()
+		q.Y.Normalize()
+		require.Equal(t, p.Bytes(), q.Bytes(), n.String())
+	}
+}
+
+func TestModInvBlinded(t *testing.T) {
+	for i := 0; i < 64; i++ {
+		b := make([]byte, 32)
+		_, err := rand.Read(b)
+		require.NoError(t, err)
+
+		var a Number
+		a.SetBytes(b)
+		a.mod(&TheCurve.Order)
+		if a.Sign() == 0 {
+			continue
+		}
+
+		var x, y Number
+		x.modInv(&a, &TheCurve.Order)
+		y.modInvBlinded(&a, &TheCurve.Order)
+		require.Equal(t, 0, x.Cmp(&y.Int))
+	}
+}
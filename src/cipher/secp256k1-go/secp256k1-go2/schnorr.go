@@ -0,0 +1,366 @@
+package secp256k1go
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// BIP340 tagged hash tags
+const (
+	schnorrTagAux       = "BIP0340/aux"
+	schnorrTagNonce     = "BIP0340/nonce"
+	schnorrTagChallenge = "BIP0340/challenge"
+	schnorrTagBatch     = "BIP0340/batch"
+)
+
+// taggedHash returns sha256(sha256(tag) || sha256(tag) || data...), as defined by BIP340
+func taggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:]) //nolint:errcheck
+	h.Write(t[:]) //nolint:errcheck
+	for _, d := range data {
+		h.Write(d) //nolint:errcheck
+	}
+	return h.Sum(nil)
+}
+
+// fieldBytes returns the 32 byte big endian encoding of the normalized field element
+func fieldBytes(f *Field) []byte {
+	b := make([]byte, 32)
+	v := *f
+	v.Normalize()
+	v.GetB32(b)
+	return b
+}
+
+// liftX returns the point with the x coordinate and an even y coordinate,
+// returns false if x is not the x coordinate of a point on the curve
+func liftX(x []byte) (XY, bool) {
+	var xn Number
+	xn.SetBytes(x)
+	if xn.Cmp(&TheCurve.p.Int) >= 0 {
+		return XY{}, false
+	}
+
+	var X, x2, x3, c, y, y2 Field
+	X.SetB32(x)
+	X.Sqr(&x2)
+	X.Mul(&x3, &x2)
+	c.SetInt(7)
+	c.SetAdd(&x3)
+	c.Sqrt(&y)
+
+	// Sqrt does not fail for non quadratic residues, check the result
+	y.Sqr(&y2)
+	y2.Normalize()
+	c.Normalize()
+	if !y2.Equals(&c) {
+		return XY{}, false
+	}
+
+	y.Normalize()
+	if y.IsOdd() {
+		y.Negate(&y, 1)
+		y.Normalize()
+	}
+
+	var p XY
+	p.SetXY(&X, &y)
+	return p, true
+}
+
+// SchnorrPubkeyIsValid returns true if the 32 byte x-only pubkey is the x coordinate of a point on the curve
+func SchnorrPubkeyIsValid(pubkey []byte) bool {
+	if len(pubkey) != 32 {
+		return false
+	}
+	_, ok := liftX(pubkey)
+	return ok
+}
+
+// schnorrChallenge returns int(hash_BIP0340/challenge(rx || px || msg)) mod n
+func schnorrChallenge(rx, px, msg []byte) Number {
+	var e Number
+	e.SetBytes(taggedHash(schnorrTagChallenge, rx, px, msg))
+	e.mod(&TheCurve.Order)
+	return e
+}
+
+// baseMultiplyXY returns k*G as an affine point with normalized coordinates, for a secret k
+func baseMultiplyXY(k Number) XY {
+	r := secretMultGen(k)
+	var p XY
+	p.SetXYZ(&r)
+	p.X.Normalize()
+	p.Y.Normalize()
+	return p
+}
+
+// SchnorrPubkey returns the 32 byte x-only public key of the seckey, as defined by BIP340.
+// Returns nil if the seckey is invalid.
+func SchnorrPubkey(seckey []byte) []byte {
+	if len(seckey) != 32 {
+		return nil
+	}
+
+	var d Number
+	d.SetBytes(seckey)
+	if d.Sign() == 0 || d.Cmp(&TheCurve.Order.Int) >= 0 {
+		return nil
+	}
+
+	p := baseMultiplyXY(d)
+	return fieldBytes(&p.X)
+}
+
+// SchnorrSign signs the message with the seckey, as defined by BIP340.
+// auxRand is 32 bytes of fresh randomness mixed into the nonce.
+// Returns the 64 byte signature, or nil if the seckey is invalid.
+func SchnorrSign(msg, seckey, auxRand []byte) []byte {
+	if len(seckey) != 32 || len(auxRand) != 32 {
+		return nil
+	}
+
+	var d Number
+	d.SetBytes(seckey)
+	if d.Sign() == 0 || d.Cmp(&TheCurve.Order.Int) >= 0 {
+		return nil
+	}
+
+	p := baseMultiplyXY(d)
+	if p.Y.IsOdd() {
+		d.Sub(&TheCurve.Order.Int, &d.Int)
+	}
+	px := fieldBytes(&p.X)
+
+	t := LeftPadBytes(d.Bytes(), 32)
+	aux := taggedHash(schnorrTagAux, auxRand)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+
+	var k Number
+	k.SetBytes(taggedHash(schnorrTagNonce, t, px, msg))
+	k.mod(&TheCurve.Order)
+	for i := range t {
+		t[i] = 0
+	}
+	if k.Sign() == 0 {
+		return nil
+	}
+
+	r := baseMultiplyXY(k)
+	if r.Y.IsOdd() {
+		k.Sub(&TheCurve.Order.Int, &k.Int)
+	}
+	rx := fieldBytes(&r.X)
+
+	e := schnorrChallenge(rx, px, msg)
+	var s Number
+	s.modMul(&e, &d, &TheCurve.Order)
+	s.Add(&s.Int, &k.Int)
+	s.mod(&TheCurve.Order)
+
+	sig := make([]byte, 64)
+	copy(sig[:32], rx)
+	copy(sig[32:], LeftPadBytes(s.Bytes(), 32))
+
+	// Guard against fault attacks leaking the seckey through an invalid signature
+	if !SchnorrVerify(msg, px, sig) {
+		return nil
+	}
+
+	return sig
+}
+
+// parseSchnorrSig parses the signature into its r value and s scalar, returns false if they are out of range
+func parseSchnorrSig(sig []byte) (Number, Number, bool) {
+	var r, s Number
+	if len(sig) != 64 {
+		return r, s, false
+	}
+
+	r.SetBytes(sig[:32])
+	if r.Cmp(&TheCurve.p.Int) >= 0 {
+		return r, s, false
+	}
+
+	s.SetBytes(sig[32:])
+	if s.Cmp(&TheCurve.Order.Int) >= 0 {
+		return r, s, false
+	}
+
+	return r, s, true
+}
+
+// SchnorrVerify verifies the BIP340 signature of the message for the 32 byte x-only pubkey
+func SchnorrVerify(msg, pubkey, sig []byte) bool {
+	if len(pubkey) != 32 {
+		return false
+	}
+
+	p, ok := liftX(pubkey)
+	if !ok {
+		return false
+	}
+
+	_, s, ok := parseSchnorrSig(sig)
+	if !ok {
+		return false
+	}
+
+	// R = s*G - e*P
+	e := schnorrChallenge(sig[:32], pubkey, msg)
+	if e.Sign() != 0 {
+		e.Sub(&TheCurve.Order.Int, &e.Int)
+	}
+
+	var pj, rj XYZ
+	pj.SetXY(&p)
+	pj.ECmult(&rj, &e, &s)
+	if rj.IsInfinity() {
+		return false
+	}
+
+	var r XY
+	r.SetXYZ(&rj)
+	r.X.Normalize()
+	r.Y.Normalize()
+	if r.Y.IsOdd() {
+		return false
+	}
+
+	return bytes.Equal(fieldBytes(&r.X), sig[:32])
+}
+
+// SchnorrBatchVerify verifies the BIP340 signatures of the messages for the x-only pubkeys at once,
+// which is faster than verifying each signature. Returns false if any of the signatures is invalid,
+// without telling which one.
+//
+// The signatures are combined with coefficients derived from the hash of all inputs,
+// so that invalid signatures can not be crafted to cancel each other out.
+func SchnorrBatchVerify(msgs, pubkeys, sigs [][]byte) bool {
+	if len(msgs) != len(pubkeys) || len(msgs) != len(sigs) {
+		return false
+	}
+
+	if len(sigs) == 1 {
+		return SchnorrVerify(msgs[0], pubkeys[0], sigs[0])
+	}
+
+	seedHash := sha256.New()
+	for i := range sigs {
+		seedHash.Write(pubkeys[i]) //nolint:errcheck
+		seedHash.Write(sigs[i])    //nolint:errcheck
+		seedHash.Write(msgs[i])    //nolint:errcheck
+	}
+	seed := seedHash.Sum(nil)
+
+	// Checks that (sum a_i*s_i)*G - sum(a_i*R_i) - sum(a_i*e_i*P_i) is the point at infinity,
+	// with a_0 = 1 and random a_i for i > 0
+	points := make([]XY, 0, 2*len(sigs))
+	scalars := make([]Number, 0, 2*len(sigs))
+	var sg Number
+	for i := range sigs {
+		if len(pubkeys[i]) != 32 {
+			return false
+		}
+
+		p, ok := liftX(pubkeys[i])
+		if !ok {
+			return false
+		}
+
+		_, s, ok := parseSchnorrSig(sigs[i])
+		if !ok {
+			return false
+		}
+
+		r, ok := liftX(sigs[i][:32])
+		if !ok {
+			return false
+		}
+
+		var a Number
+		if i == 0 {
+			a.SetInt64(1)
+		} else {
+			var idx [4]byte
+			binary.BigEndian.PutUint32(idx[:], uint32(i))
+			a.SetBytes(taggedHash(schnorrTagBatch, seed, idx[:]))
+			a.mod(&TheCurve.Order)
+		}
+
+		e := schnorrChallenge(sigs[i][:32], pubkeys[i], msgs[i])
+
+		var as, ae Number
+		as.modMul(&a, &s, &TheCurve.Order)
+		sg.Add(&sg.Int, &as.Int)
+		sg.mod(&TheCurve.Order)
+
+		ae.modMul(&a, &e, &TheCurve.Order)
+		negate(&a)
+		negate(&ae)
+
+		points = append(points, r, p)
+		scalars = append(scalars, a, ae)
+	}
+
+	var rj XYZ
+	ecmultMulti(&rj, points, scalars)
+	if sg.Sign() != 0 {
+		g := ECmultGen(sg)
+		rj.Add(&rj, &g)
+	}
+
+	return rj.IsInfinity()
+}
+
+// negate sets the scalar to -a mod n
+func negate(a *Number) {
+	if a.Sign() != 0 {
+		a.Sub(&TheCurve.Order.Int, &a.Int)
+	}
+}
+
+// ecmultMulti computes r = sum(scalars[i]*points[i]) with interleaved wNAF multiplication,
+// all points share the same doublings
+func ecmultMulti(r *XYZ, points []XY, scalars []Number) {
+	wnafs := make([][]int, len(points))
+	bits := make([]int, len(points))
+	pres := make([][]XYZ, len(points))
+	maxBits := 0
+	for i := range points {
+		wnafs[i] = make([]int, 257)
+		bits[i] = ecmultWnaf(wnafs[i], &scalars[i], winA)
+		if bits[i] > maxBits {
+			maxBits = bits[i]
+		}
+
+		var pj XYZ
+		pj.SetXY(&points[i])
+		pres[i] = pj.precomp(winA)
+	}
+
+	r.Infinity = true
+	var tmpj XYZ
+	for b := maxBits - 1; b >= 0; b-- {
+		r.Double(r)
+
+		for i := range points {
+			if b >= bits[i] {
+				continue
+			}
+
+			n := wnafs[i][b]
+			if n > 0 {
+				r.Add(r, &pres[i][(n-1)/2])
+			} else if n != 0 {
+				pres[i][(-n-1)/2].Neg(&tmpj)
+				r.Add(r, &tmpj)
+			}
+		}
+	}
+}
@@ -175,7 +175,7 @@ func (sig *Signature) Sign(seckey, message, nonce *Number, recid *int) int {
 	var b [32]byte
 
 	// r = nonce*G
-	rp := ECmultGen(*nonce)
+	rp := secretMultGen(*nonce)
 	r.SetXYZ(&rp)
 	r.X.Normalize()
 	r.Y.Normalize()
@@ -203,7 +203,7 @@ func (sig *Signature) Sign(seckey, message, nonce *Number, recid *int) int {
 	n.modMul(&sig.R, seckey, &TheCurve.Order)
 	n.Add(&n.Int, &message.Int)
 	n.mod(&TheCurve.Order)
-	sig.S.modInv(nonce, &TheCurve.Order)
+	sig.S.secretModInv(nonce, &TheCurve.Order)
 	sig.S.modMul(&sig.S, &n, &TheCurve.Order)
 
 	if sig.S.Sign() == 0 {
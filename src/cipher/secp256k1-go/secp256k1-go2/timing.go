@@ -0,0 +1,118 @@
+// +build secp256k1ct
+
+package secp256k1go
+
+import (
+	"crypto/rand"
+	"log"
+	"math"
+	"time"
+)
+
+// TimingThreshold is the absolute Welch's t statistic above which
+// the timings of two input classes are considered distinguishable, as used by dudect
+const TimingThreshold = 4.5
+
+// TimingResult is the result of a timing test of two input classes
+type TimingResult struct {
+	// Samples is the number of measurements of each class
+	Samples int
+	// MeanA and MeanB are the mean durations of the classes, in nanoseconds
+	MeanA float64
+	MeanB float64
+	// T is Welch's t statistic of the durations of the classes
+	T float64
+}
+
+// Leaks returns true if the timings of the classes are distinguishable
+func (r TimingResult) Leaks() bool {
+	return math.Abs(r.T) > TimingThreshold
+}
+
+// TimingTest measures a and b samples times each, interleaved, and compares their durations with Welch's t-test.
+// a and b should run the same operation on two classes of inputs, e.g. fixed and random secret keys.
+// The result is only meaningful with many samples on an otherwise idle host.
+func TimingTest(a, b func(), samples int) TimingResult {
+	if samples < 2 {
+		log.Panic("TimingTest needs at least 2 samples")
+	}
+
+	da := make([]float64, samples)
+	db := make([]float64, samples)
+	for i := 0; i < samples; i++ {
+		// Alternate the order of the classes so that warmup effects are not attributed to one class
+		if i%2 == 0 {
+			da[i] = measure(a)
+			db[i] = measure(b)
+		} else {
+			db[i] = measure(b)
+			da[i] = measure(a)
+		}
+	}
+
+	ma, va := meanVariance(da)
+	mb, vb := meanVariance(db)
+	n := float64(samples)
+
+	var t float64
+	if se := math.Sqrt(va/n + vb/n); se > 0 {
+		t = (ma - mb) / se
+	}
+
+	return TimingResult{
+		Samples: samples,
+		MeanA:   ma,
+		MeanB:   mb,
+		T:       t,
+	}
+}
+
+// SignTimingTest compares the duration of signing with a fixed low weight secret key and nonce
+// against signing with random secret keys and nonces
+func SignTimingTest(samples int) TimingResult {
+	var msg Number
+	msg.SetBytes(randBytes(32))
+
+	var fixed Number
+	fixed.SetInt64(1)
+
+	var sig Signature
+	return TimingTest(func() {
+		sig.Sign(&fixed, &msg, &fixed, nil)
+	}, func() {
+		var seckey, nonce Number
+		seckey.SetBytes(randBytes(32))
+		seckey.mod(&TheCurve.Order)
+		nonce.SetBytes(randBytes(32))
+		nonce.mod(&TheCurve.Order)
+		sig.Sign(&seckey, &msg, &nonce, nil)
+	}, samples)
+}
+
+func measure(f func()) float64 {
+	start := time.Now()
+	f()
+	return float64(time.Since(start).Nanoseconds())
+}
+
+func meanVariance(xs []float64) (float64, float64) {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, ss / float64(len(xs)-1)
+}
+
+func randBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Panic(err)
+	}
+	return b
+}
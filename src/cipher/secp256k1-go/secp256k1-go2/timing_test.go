@@ -0,0 +1,34 @@
+// +build secp256k1ct
+
+package secp256k1go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstantTime(t *testing.T) {
+	require.True(t, ConstantTime)
+}
+
+func TestTimingTest(t *testing.T) {
+	// Distinguishable classes
+	r := TimingTest(func() {}, func() {
+		time.Sleep(time.Millisecond)
+	}, 20)
+	require.Equal(t, 20, r.Samples)
+	require.True(t, r.MeanB > r.MeanA)
+	require.True(t, r.Leaks())
+}
+
+func TestSignTimingTest(t *testing.T) {
+	// The timings are noisy on shared test hosts, so the test only checks that the helper runs.
+	// Run with more samples on an idle host to audit the constant time mode.
+	r := SignTimingTest(50)
+	require.Equal(t, 50, r.Samples)
+	require.True(t, r.MeanA > 0)
+	require.True(t, r.MeanB > 0)
+	t.Logf("sign timing: fixed=%.0fns random=%.0fns t=%.2f", r.MeanA, r.MeanB, r.T)
+}
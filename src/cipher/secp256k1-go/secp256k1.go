@@ -14,6 +14,11 @@ import (
 // DebugPrint enable debug print statements
 var DebugPrint = false
 
+// ConstantTime is true if the package is built with the secp256k1ct build tag.
+// In this mode signing and public key generation multiply the secret scalars by the generator
+// in constant time, and the signing nonce is inverted with blinding.
+const ConstantTime = secp.ConstantTime
+
 // intenal, may fail
 // may return nil
 func pubkeyFromSeckey(seckey []byte) []byte {
@@ -214,30 +219,32 @@ func GenerateDeterministicKeyPair(seed []byte) ([]byte, []byte) {
 // If private key is disclosed, should not be able to compute future or past keys in sequence
 func DeterministicKeyPairIterator(seedIn []byte) ([]byte, []byte, []byte) {
 	seed1 := Secp256k1Hash(seedIn) // make it difficult to derive future seckeys from previous seckeys
-	seed2 := SumSHA256(append(seedIn, seed1...))
-	pubkey, seckey := deterministicKeyPairIteratorStep(seed2) // this is our seckey
+	pubkey, seckey := DeterministicKeyPairFromSeeds(seedIn, seed1)
 	return seed1, pubkey, seckey
 }
 
-func newRandomNonceNumber() secp.Number {
-	nonce := RandByte(32)
-	var n secp.Number
-	n.SetBytes(nonce)
-	return n
+// DeterministicKeyPairFromSeeds generates the keypair that DeterministicKeyPairIterator
+// returns for seedIn, next is Secp256k1Hash(seedIn), the SHA256 value returned with the keypair.
+// The sequence of SHA256 values can be computed first, so that the keypairs are generated concurrently.
+// Returns PubKey, SecKey as bytes
+func DeterministicKeyPairFromSeeds(seedIn, next []byte) ([]byte, []byte) {
+	seed2 := make([]byte, 0, len(seedIn)+len(next))
+	seed2 = append(seed2, seedIn...)
+	seed2 = SumSHA256(append(seed2, next...))
+	return deterministicKeyPairIteratorStep(seed2) // this is our seckey
 }
 
-// newSigningNonce creates a nonce for signing. This is the `k` parameter in
-// ECDSA signing. `k` must be 0 < k < n, where `n` is the order of the curve
-func newSigningNonce() secp.Number {
-	nonce := newRandomNonceNumber()
-	for nonce.Sign() == 0 || nonce.Cmp(&secp.TheCurve.Order.Int) >= 0 {
-		nonce = newRandomNonceNumber()
-	}
-	return nonce
+// Sign sign hash, returns a compact recoverable signature.
+// The nonce is generated deterministically from msg and seckey as specified by RFC6979,
+// so signing the same msg with the same seckey always returns the same signature.
+func Sign(msg []byte, seckey []byte) []byte {
+	sig, _ := SignRFC6979(msg, seckey)
+	return sig
 }
 
-// Sign sign hash, returns a compact recoverable signature
-func Sign(msg []byte, seckey []byte) []byte {
+// SignRFC6979 is Sign, and also returns the number of RFC6979 nonce candidates that were rejected
+// before the nonce used for the signature. NonceRFC6979(msg, seckey, attempt) returns that nonce.
+func SignRFC6979(msg []byte, seckey []byte) ([]byte, int) {
 	if len(seckey) != 32 {
 		log.Panic("Sign, Invalid seckey length")
 	}
@@ -251,7 +258,6 @@ func Sign(msg []byte, seckey []byte) []byte {
 		log.Panic("Sign, message must be 32 bytes")
 	}
 
-	nonce := newSigningNonce()
 	sig := make([]byte, 65)
 	var recid int // recovery byte, used to recover pubkey from sig
 
@@ -267,10 +273,15 @@ func Sign(msg []byte, seckey []byte) []byte {
 		log.Panic("Sign: message is 0")
 	}
 
-	ret := cSig.Sign(&seckey1, &msg1, &nonce, &recid)
-
-	if ret != 1 {
-		log.Panic("Secp25k1-go, Sign, signature operation failed")
+	// The signature fails if its S value is 0, then the next nonce candidate is used
+	nonces := newRFC6979(msg, seckey)
+	attempt := 0
+	for {
+		nonce := nonces.next()
+		if cSig.Sign(&seckey1, &msg1, &nonce, &recid) == 1 {
+			break
+		}
+		attempt++
 	}
 
 	sigBytes := cSig.Bytes()
@@ -286,7 +297,7 @@ func Sign(msg []byte, seckey []byte) []byte {
 		log.Panic("invalid recovery id")
 	}
 
-	return sig
+	return sig, attempt
 }
 
 // VerifySeckey verifies a secret key
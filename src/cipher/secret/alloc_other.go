@@ -0,0 +1,8 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package secret
+
+// Memory locking is not supported on this platform, the memory is only wiped
+func alloc(n int) ([]byte, bool, func([]byte)) {
+	return make([]byte, n), false, nil
+}
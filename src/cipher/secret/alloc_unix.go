@@ -0,0 +1,22 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package secret
+
+import "syscall"
+
+// alloc maps n bytes of anonymous memory and locks it, the memory is allocated
+// on the Go heap and not locked if it can not be mapped
+func alloc(n int) ([]byte, bool, func([]byte)) {
+	data, err := syscall.Mmap(-1, 0, n, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return make([]byte, n), false, nil
+	}
+
+	locked := syscall.Mlock(data) == nil
+	return data, locked, func(b []byte) {
+		if locked {
+			syscall.Munlock(b) //nolint:errcheck
+		}
+		syscall.Munmap(b) //nolint:errcheck
+	}
+}
@@ -0,0 +1,27 @@
+// +build windows
+
+package secret
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32          = syscall.NewLazyDLL("kernel32.dll")
+	procVirtualLock   = kernel32.NewProc("VirtualLock")
+	procVirtualUnlock = kernel32.NewProc("VirtualUnlock")
+)
+
+// alloc allocates n bytes and locks them in the working set of the process
+func alloc(n int) ([]byte, bool, func([]byte)) {
+	data := make([]byte, n)
+	r, _, _ := procVirtualLock.Call(uintptr(unsafe.Pointer(&data[0])), uintptr(n)) //nolint:errcheck
+	if r == 0 {
+		return data, false, nil
+	}
+
+	return data, true, func(b []byte) {
+		procVirtualUnlock.Call(uintptr(unsafe.Pointer(&b[0])), uintptr(len(b))) //nolint:errcheck
+	}
+}
@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"os"
+	"sync"
+)
+
+// maxPoolSlotSize is the size of the largest pool slots, larger Buffers have their own pages
+const maxPoolSlotSize = 1024
+
+// poolSlotSizes are the sizes of the slots of the pools, in increasing order.
+// The pools share locked pages between the small Buffers, so that a wallet with
+// thousands of secret keys does not need a locked page for each key.
+var poolSlotSizes = [...]int{64, 256, maxPoolSlotSize}
+
+var pools [len(poolSlotSizes)]pool
+
+// pool holds the free slots of a slot size
+type pool struct {
+	sync.Mutex
+	free []poolSlot
+}
+
+type poolSlot struct {
+	data   []byte
+	locked bool
+}
+
+// poolIndex returns the index of the pool of the smallest slots that hold n bytes
+func poolIndex(n int) int {
+	for i, size := range poolSlotSizes {
+		if n <= size {
+			return i
+		}
+	}
+	panic("secret: buffer is too large for the pools")
+}
+
+// allocSlot returns a zeroed slot that holds n bytes, and whether it is locked.
+// The pool pages are allocated with alloc when the pool is empty, and are never freed.
+func allocSlot(n int) ([]byte, bool) {
+	i := poolIndex(n)
+	size := poolSlotSizes[i]
+
+	p := &pools[i]
+	p.Lock()
+	defer p.Unlock()
+
+	if len(p.free) == 0 {
+		pageSize := os.Getpagesize()
+		if pageSize < size {
+			pageSize = size
+		}
+
+		page, locked, _ := alloc(pageSize)
+		for off := 0; off+size <= len(page); off += size {
+			p.free = append(p.free, poolSlot{
+				data:   page[off : off+size : off+size],
+				locked: locked,
+			})
+		}
+	}
+
+	s := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+	return s.data, s.locked
+}
+
+// freeSlot wipes the slot and returns it to its pool
+func freeSlot(slot []byte, locked bool) {
+	Wipe(slot)
+
+	p := &pools[poolIndex(len(slot))]
+	p.Lock()
+	defer p.Unlock()
+
+	p.free = append(p.free, poolSlot{
+		data:   slot,
+		locked: locked,
+	})
+}
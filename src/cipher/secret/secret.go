@@ -0,0 +1,104 @@
+/*
+Package secret provides memory buffers for secret data, such as seeds and private keys.
+
+The memory of a Buffer is allocated outside of the Go heap and locked where the
+platform supports it, so that it's not copied by the garbage collector nor swapped
+to disk, and it's wiped when the Buffer is destroyed.
+
+The secrets of an unlocked wallet are held in Buffers: the seed, last seed and seed
+passphrase (wallet.Seeds) and the secret keys of the entries (wallet.SecretKey). They are
+wiped when the wallet is locked or erased. Buffers also hold the decrypted secrets while
+they are serialized, the data re-encrypted by crypto.ReEncrypt and the bip39 seed while a
+bip44 account is derived, the mnemonic is read from its Buffer by bip39.NewSeedFromBytes.
+
+The secrets are only copied to strings on the Go heap, which can't be wiped, at the
+boundaries of the wallet: when they are decoded from or encoded to the wallet file or the
+encrypted secrets, set from the wallet options, or exported by the seed API. A secret
+key is copied to a cipher.SecKey while it signs or its public key is derived.
+
+The small Buffers, such as the secret keys, share locked pages from a pool, so that
+a wallet with many addresses doesn't exhaust the locked memory limit of the process.
+*/
+package secret
+
+import (
+	"runtime"
+)
+
+// Buffer holds secret data in guarded memory
+type Buffer struct {
+	data   []byte
+	locked bool
+	free   func([]byte)
+	// slot is the pool slot holding the data of a small Buffer, nil if the Buffer has its own pages
+	slot []byte
+}
+
+// New allocates a zeroed Buffer of n bytes.
+// The small Buffers, such as secret keys and seeds, share the pages of a pool.
+func New(n int) *Buffer {
+	b := &Buffer{}
+	switch {
+	case n <= 0:
+		b.data = []byte{}
+	case n <= maxPoolSlotSize:
+		b.slot, b.locked = allocSlot(n)
+		b.data = b.slot[:n:n]
+	default:
+		b.data, b.locked, b.free = alloc(n)
+	}
+
+	// Wipes the buffer if it's not destroyed
+	runtime.SetFinalizer(b, (*Buffer).Destroy)
+	return b
+}
+
+// FromBytes copies p into a new Buffer and wipes p
+func FromBytes(p []byte) *Buffer {
+	b := New(len(p))
+	copy(b.data, p)
+	Wipe(p)
+	return b
+}
+
+// Bytes returns the secret data, which is only valid until the Buffer is destroyed
+func (b *Buffer) Bytes() []byte {
+	return b.data
+}
+
+// Len returns the length of the secret data
+func (b *Buffer) Len() int {
+	return len(b.data)
+}
+
+// Locked returns whether the memory of the Buffer is locked. Locking is not supported
+// on all platforms, and fails if the locked memory limit of the process is reached.
+func (b *Buffer) Locked() bool {
+	return b.locked
+}
+
+// Destroy wipes and frees the Buffer, destroying a destroyed Buffer is a no-op
+func (b *Buffer) Destroy() {
+	if b.data == nil {
+		return
+	}
+
+	Wipe(b.data)
+	if b.free != nil {
+		b.free(b.data)
+	}
+	if b.slot != nil {
+		freeSlot(b.slot, b.locked)
+		b.slot = nil
+	}
+	b.data = nil
+	b.locked = false
+	runtime.SetFinalizer(b, nil)
+}
+
+// Wipe zeroes p
+func Wipe(p []byte) {
+	for i := range p {
+		p[i] = 0
+	}
+}
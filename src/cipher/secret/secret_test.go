@@ -0,0 +1,95 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuffer(t *testing.T) {
+	tt := []struct {
+		name string
+		n    int
+	}{
+		{
+			name: "empty",
+			n:    0,
+		},
+		{
+			name: "32 bytes",
+			n:    32,
+		},
+		{
+			name: "multiple pages",
+			n:    3*4096 + 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p := make([]byte, tc.n)
+			for i := range p {
+				p[i] = byte(i%255) + 1
+			}
+			v := append([]byte{}, p...)
+
+			b := FromBytes(p)
+			require.Equal(t, tc.n, b.Len())
+			require.Equal(t, v, b.Bytes())
+			// The source is wiped
+			require.Equal(t, make([]byte, tc.n), p)
+
+			// The data is wiped before the memory is freed
+			var freed []byte
+			free := b.free
+			b.free = func(d []byte) {
+				freed = append([]byte{}, d...)
+				if free != nil {
+					free(d)
+				}
+			}
+			b.Destroy()
+			if tc.n > 0 {
+				require.Equal(t, make([]byte, tc.n), freed)
+			}
+
+			require.Nil(t, b.Bytes())
+			require.Equal(t, 0, b.Len())
+			require.False(t, b.Locked())
+
+			// Destroying twice is a no-op
+			b.Destroy()
+		})
+	}
+}
+
+func TestBufferPool(t *testing.T) {
+	for _, n := range []int{1, 32, 64, 65, 300, maxPoolSlotSize} {
+		b := New(n)
+		require.Equal(t, n, b.Len())
+		require.Equal(t, n, cap(b.Bytes()))
+		require.Equal(t, make([]byte, n), b.Bytes())
+		require.NotNil(t, b.slot)
+		require.Equal(t, poolSlotSizes[poolIndex(n)], len(b.slot))
+
+		for i := range b.Bytes() {
+			b.Bytes()[i] = 0xff
+		}
+
+		// The slot is wiped when it is returned to the pool, and reused
+		slot := b.slot
+		b.Destroy()
+		require.Nil(t, b.slot)
+		require.Equal(t, make([]byte, len(slot)), slot)
+
+		b2 := New(n)
+		require.Equal(t, &slot[0], &b2.slot[0])
+		require.Equal(t, make([]byte, n), b2.Bytes())
+		b2.Destroy()
+	}
+
+	// The larger buffers have their own pages
+	b := New(maxPoolSlotSize + 1)
+	require.Nil(t, b.slot)
+	b.Destroy()
+}
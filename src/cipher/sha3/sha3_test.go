@@ -0,0 +1,32 @@
+package sha3
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyKeccak256(t *testing.T) {
+	cases := []struct {
+		in  string
+		out string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+	}
+
+	for _, tc := range cases {
+		h := NewLegacyKeccak256()
+		_, err := h.Write([]byte(tc.in))
+		require.NoError(t, err)
+		require.Equal(t, tc.out, hex.EncodeToString(h.Sum(nil)))
+	}
+}
+
+func TestSum256(t *testing.T) {
+	// SHA3-256 of the empty string, FIPS 202
+	d := Sum256(nil)
+	require.Equal(t, "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a", hex.EncodeToString(d[:]))
+}
@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+)
+
+var (
+	// ErrInvalidECPoint is returned when the CKA_EC_POINT of a key is not a secp256k1 point
+	ErrInvalidECPoint = errors.New("signer: invalid PKCS#11 EC point")
+	// ErrInvalidTokenSignature is returned when the token returns a signature that is not of the key and hash
+	ErrInvalidTokenSignature = errors.New("signer: PKCS#11 token returned an invalid signature")
+)
+
+var (
+	// secp256k1 group order, and half of it for the low S normalization
+	curveOrder     = mustBigFromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	curveHalfOrder = new(big.Int).Rsh(curveOrder, 1)
+)
+
+// PKCS11Token is the subset of a logged in PKCS#11 session used to sign with secp256k1 keys held by
+// a hardware security module. The keys are EC key pairs on the secp256k1 curve, found by their CKA_LABEL.
+// It is implemented by the caller with a PKCS#11 binding of the HSM vendor library, e.g. github.com/miekg/pkcs11,
+// this package doesn't load a PKCS#11 module. The private keys never leave the HSM.
+type PKCS11Token interface {
+	// ECPoint returns the CKA_EC_POINT attribute of the public key object of the label,
+	// the DER encoded octet string of the point
+	ECPoint(label string) ([]byte, error)
+	// SignECDSA signs the hash with the CKM_ECDSA mechanism and the private key object of the label,
+	// returning the 64 bytes r || s signature
+	SignECDSA(label string, hash []byte) ([]byte, error)
+}
+
+// PKCS11Signer signs with a secp256k1 key held by a PKCS#11 token
+type PKCS11Signer struct {
+	token  PKCS11Token
+	label  string
+	pubKey cipher.PubKey
+}
+
+// NewPKCS11Signer creates a signer of the key of the label on the token
+func NewPKCS11Signer(token PKCS11Token, label string) (*PKCS11Signer, error) {
+	p, err := token.ECPoint(label)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := ParseECPoint(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PKCS11Signer{
+		token:  token,
+		label:  label,
+		pubKey: pk,
+	}, nil
+}
+
+// Label returns the CKA_LABEL of the key
+func (s *PKCS11Signer) Label() string {
+	return s.label
+}
+
+// PubKey returns the public key of the token key
+func (s *PKCS11Signer) PubKey() cipher.PubKey {
+	return s.pubKey
+}
+
+// SignHash signs the hash on the token. The r || s signature of the token is normalized to a low S value,
+// which is required by cipher.Sig, and the recovery id is found by recovering the public key.
+func (s *PKCS11Signer) SignHash(hash cipher.SHA256) (cipher.Sig, error) {
+	rs, err := s.token.SignECDSA(s.label, hash[:])
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+
+	return sigFromRS(rs, s.pubKey, hash)
+}
+
+// sigFromRS converts a r || s ECDSA signature of pk to a cipher.Sig
+func sigFromRS(rs []byte, pk cipher.PubKey, hash cipher.SHA256) (cipher.Sig, error) {
+	if len(rs) != 64 {
+		return cipher.Sig{}, ErrInvalidTokenSignature
+	}
+
+	var sig cipher.Sig
+	copy(sig[:64], rs)
+
+	sv := new(big.Int).SetBytes(rs[32:])
+	if sv.Sign() == 0 || sv.Cmp(curveOrder) >= 0 {
+		return cipher.Sig{}, ErrInvalidTokenSignature
+	}
+	if sv.Cmp(curveHalfOrder) > 0 {
+		sv.Sub(curveOrder, sv)
+		copy(sig[32:64], scalarBytes(sv))
+	}
+
+	for recid := byte(0); recid < 4; recid++ {
+		sig[64] = recid
+		if rpk, err := cipher.PubKeyFromSig(sig, hash); err == nil && rpk == pk {
+			if err := cipher.VerifyPubKeySignedHash(pk, sig, hash); err != nil {
+				return cipher.Sig{}, ErrInvalidTokenSignature
+			}
+			return sig, nil
+		}
+	}
+
+	return cipher.Sig{}, ErrInvalidTokenSignature
+}
+
+// ParseECPoint parses the CKA_EC_POINT attribute of a secp256k1 key, the DER encoded octet string of
+// the uncompressed or compressed point. The raw point, as returned by some tokens, is also accepted.
+func ParseECPoint(p []byte) (cipher.PubKey, error) {
+	// DER octet string of a 33 or 65 bytes point
+	if len(p) > 2 && p[0] == 0x04 && int(p[1]) == len(p)-2 && (len(p) == 35 || len(p) == 67) {
+		p = p[2:]
+	}
+
+	switch len(p) {
+	case 33:
+		pk, err := cipher.NewPubKey(p)
+		if err != nil {
+			return cipher.PubKey{}, ErrInvalidECPoint
+		}
+		return pk, nil
+	case 65:
+		if p[0] != 0x04 {
+			return cipher.PubKey{}, ErrInvalidECPoint
+		}
+
+		compressed := make([]byte, 33)
+		compressed[0] = 0x02 | (p[64] & 1)
+		copy(compressed[1:], p[1:33])
+
+		pk, err := cipher.NewPubKey(compressed)
+		if err != nil {
+			return cipher.PubKey{}, ErrInvalidECPoint
+		}
+
+		// The Y coordinate must be the one of the point
+		if !bytes.Equal(secp256k1.UncompressPubkey(pk[:]), p) {
+			return cipher.PubKey{}, ErrInvalidECPoint
+		}
+		return pk, nil
+	default:
+		return cipher.PubKey{}, ErrInvalidECPoint
+	}
+}
+
+// scalarBytes returns the 32 bytes big endian encoding of n
+func scalarBytes(n *big.Int) []byte {
+	b := n.Bytes()
+	out := make([]byte, 32)
+	copy(out[32-len(b):], b)
+	return out
+}
+
+func mustBigFromHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex number " + s)
+	}
+	return n
+}
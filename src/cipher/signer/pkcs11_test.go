@@ -0,0 +1,141 @@
+package signer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	secp256k1 "github.com/skycoin/skycoin/src/cipher/secp256k1-go"
+)
+
+// fakeToken is a PKCS#11 token holding secret keys by label
+type fakeToken struct {
+	keys       map[string]cipher.SecKey
+	compressed bool
+	highS      bool
+}
+
+func (t *fakeToken) ECPoint(label string) ([]byte, error) {
+	sk, ok := t.keys[label]
+	if !ok {
+		return nil, errors.New("CKR_OBJECT_HANDLE_INVALID")
+	}
+
+	p := cipher.MustPubKeyFromSecKey(sk)
+	point := p[:]
+	if !t.compressed {
+		point = secp256k1.UncompressPubkey(p[:])
+	}
+	return append([]byte{0x04, byte(len(point))}, point...), nil
+}
+
+func (t *fakeToken) SignECDSA(label string, hash []byte) ([]byte, error) {
+	sk, ok := t.keys[label]
+	if !ok {
+		return nil, errors.New("CKR_KEY_HANDLE_INVALID")
+	}
+
+	sig := cipher.MustSignHash(cipher.MustSHA256FromBytes(hash), sk)
+	rs := sig[:64]
+
+	// HSMs do not normalize S
+	if t.highS {
+		s := new(big.Int).SetBytes(rs[32:])
+		s.Sub(curveOrder, s)
+		copy(rs[32:], scalarBytes(s))
+	}
+
+	return rs, nil
+}
+
+func TestPKCS11Signer(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+
+	for _, compressed := range []bool{true, false} {
+		for _, highS := range []bool{true, false} {
+			token := &fakeToken{
+				keys:       map[string]cipher.SecKey{"hot": sk},
+				compressed: compressed,
+				highS:      highS,
+			}
+
+			s, err := NewPKCS11Signer(token, "hot")
+			require.NoError(t, err)
+			require.Equal(t, "hot", s.Label())
+			require.Equal(t, cipher.MustPubKeyFromSecKey(sk), s.PubKey())
+
+			for i := 0; i < 8; i++ {
+				hash := cipher.SumSHA256(cipher.RandByte(32))
+				sig, err := s.SignHash(hash)
+				require.NoError(t, err)
+				require.NoError(t, cipher.VerifyPubKeySignedHash(s.PubKey(), sig, hash))
+			}
+		}
+	}
+
+	_, err := NewPKCS11Signer(&fakeToken{}, "missing")
+	require.Equal(t, errors.New("CKR_OBJECT_HANDLE_INVALID"), err)
+}
+
+func TestSigFromRS(t *testing.T) {
+	pk, sk := cipher.GenerateKeyPair()
+	hash := cipher.SumSHA256([]byte("pkcs11"))
+	sig := cipher.MustSignHash(hash, sk)
+
+	sig2, err := sigFromRS(sig[:64], pk, hash)
+	require.NoError(t, err)
+	require.Equal(t, sig, sig2)
+
+	_, err = sigFromRS(sig[:63], pk, hash)
+	require.Equal(t, ErrInvalidTokenSignature, err)
+
+	// A signature of another key
+	pk2, _ := cipher.GenerateKeyPair()
+	_, err = sigFromRS(sig[:64], pk2, hash)
+	require.Equal(t, ErrInvalidTokenSignature, err)
+
+	// S out of range
+	rs := make([]byte, 64)
+	copy(rs, sig[:32])
+	_, err = sigFromRS(rs, pk, hash)
+	require.Equal(t, ErrInvalidTokenSignature, err)
+	copy(rs[32:], scalarBytes(curveOrder))
+	_, err = sigFromRS(rs, pk, hash)
+	require.Equal(t, ErrInvalidTokenSignature, err)
+}
+
+func TestParseECPoint(t *testing.T) {
+	pk, _ := cipher.GenerateKeyPair()
+	uncompressed := secp256k1.UncompressPubkey(pk[:])
+
+	for _, p := range [][]byte{
+		pk[:],
+		uncompressed,
+		append([]byte{0x04, 33}, pk[:]...),
+		append([]byte{0x04, 65}, uncompressed...),
+	} {
+		pk2, err := ParseECPoint(p)
+		require.NoError(t, err)
+		require.Equal(t, pk, pk2)
+	}
+
+	// Y is not the coordinate of the point
+	bad := append([]byte{}, uncompressed...)
+	bad[64] ^= 1
+	_, err := ParseECPoint(bad)
+	require.Equal(t, ErrInvalidECPoint, err)
+
+	bad = append([]byte{}, uncompressed...)
+	bad[0] = 0x06
+	_, err = ParseECPoint(bad)
+	require.Equal(t, ErrInvalidECPoint, err)
+
+	_, err = ParseECPoint(pk[:32])
+	require.Equal(t, ErrInvalidECPoint, err)
+
+	_, err = ParseECPoint(make([]byte, 33))
+	require.Equal(t, ErrInvalidECPoint, err)
+}
@@ -0,0 +1,121 @@
+/*
+Package signer abstracts the secp256k1 signing keys, so that transactions can be signed by keys
+that are not held in memory, e.g. by a hardware security module through PKCS#11.
+
+The package is a library hook for the programs that embed the wallet package. It does not load
+a PKCS#11 module, the program provides a PKCS11Token of its PKCS#11 binding and registers the
+keyring with wallet.RegisterKeyring. The skycoin node has no PKCS#11 option.
+*/
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+var (
+	// ErrKeyNotFound is returned when the keyring has no signer of the address
+	ErrKeyNotFound = errors.New("signer: no key of the address in the keyring")
+)
+
+// Signer signs hashes with a secp256k1 key
+type Signer interface {
+	// PubKey returns the public key of the signing key
+	PubKey() cipher.PubKey
+	// SignHash signs the hash, the signature must be a valid cipher.Sig of PubKey
+	SignHash(hash cipher.SHA256) (cipher.Sig, error)
+}
+
+// SecKeySigner signs with a secret key held in memory
+type SecKeySigner struct {
+	pubKey cipher.PubKey
+	secKey cipher.SecKey
+}
+
+// NewSecKeySigner creates a signer of the secret key
+func NewSecKeySigner(secKey cipher.SecKey) (*SecKeySigner, error) {
+	pk, err := cipher.PubKeyFromSecKey(secKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecKeySigner{
+		pubKey: pk,
+		secKey: secKey,
+	}, nil
+}
+
+// PubKey returns the public key of the secret key
+func (s *SecKeySigner) PubKey() cipher.PubKey {
+	return s.pubKey
+}
+
+// SignHash signs the hash with the secret key
+func (s *SecKeySigner) SignHash(hash cipher.SHA256) (cipher.Sig, error) {
+	return cipher.SignHash(hash, s.secKey)
+}
+
+// Keyring is a set of signers looked up by the address of their public key. It is safe for concurrent use.
+type Keyring struct {
+	l       sync.RWMutex
+	signers map[cipher.Address]Signer
+}
+
+// NewKeyring creates a keyring of the signers
+func NewKeyring(signers ...Signer) *Keyring {
+	k := &Keyring{
+		signers: make(map[cipher.Address]Signer, len(signers)),
+	}
+	for _, s := range signers {
+		k.signers[cipher.AddressFromPubKey(s.PubKey())] = s
+	}
+	return k
+}
+
+// Add adds a signer to the keyring, replacing the signer of the same key
+func (k *Keyring) Add(s Signer) {
+	k.l.Lock()
+	defer k.l.Unlock()
+	k.signers[cipher.AddressFromPubKey(s.PubKey())] = s
+}
+
+// Get returns the signer of the address
+func (k *Keyring) Get(addr cipher.Address) (Signer, bool) {
+	k.l.RLock()
+	defer k.l.RUnlock()
+	s, ok := k.signers[addr]
+	return s, ok
+}
+
+// Addresses returns the addresses of the keys of the keyring
+func (k *Keyring) Addresses() []cipher.Address {
+	k.l.RLock()
+	defer k.l.RUnlock()
+	addrs := make([]cipher.Address, 0, len(k.signers))
+	for a := range k.signers {
+		addrs = append(addrs, a)
+	}
+	return addrs
+}
+
+// SignHash signs the hash with the key of the address, and verifies the signature
+func (k *Keyring) SignHash(addr cipher.Address, hash cipher.SHA256) (cipher.Sig, error) {
+	s, ok := k.Get(addr)
+	if !ok {
+		return cipher.Sig{}, ErrKeyNotFound
+	}
+
+	sig, err := s.SignHash(hash)
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+
+	if err := cipher.VerifyAddressSignedHash(addr, sig, hash); err != nil {
+		return cipher.Sig{}, fmt.Errorf("signer: invalid signature of %s: %v", addr, err)
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,55 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// badSigner returns the signatures of another key
+type badSigner struct {
+	*SecKeySigner
+	other cipher.SecKey
+}
+
+func (s badSigner) SignHash(hash cipher.SHA256) (cipher.Sig, error) {
+	return cipher.SignHash(hash, s.other)
+}
+
+func TestKeyring(t *testing.T) {
+	_, sk1 := cipher.GenerateKeyPair()
+	_, sk2 := cipher.GenerateKeyPair()
+	_, sk3 := cipher.GenerateKeyPair()
+
+	s1, err := NewSecKeySigner(sk1)
+	require.NoError(t, err)
+	s2, err := NewSecKeySigner(sk2)
+	require.NoError(t, err)
+
+	_, err = NewSecKeySigner(cipher.SecKey{})
+	require.Equal(t, cipher.ErrPubKeyFromNullSecKey, err)
+
+	k := NewKeyring(s1)
+	require.Equal(t, []cipher.Address{cipher.AddressFromPubKey(s1.PubKey())}, k.Addresses())
+	k.Add(s2)
+	require.Len(t, k.Addresses(), 2)
+
+	hash := cipher.SumSHA256([]byte("keyring"))
+	addr2 := cipher.MustAddressFromSecKey(sk2)
+	sig, err := k.SignHash(addr2, hash)
+	require.NoError(t, err)
+	require.NoError(t, cipher.VerifyAddressSignedHash(addr2, sig, hash))
+
+	_, err = k.SignHash(cipher.MustAddressFromSecKey(sk3), hash)
+	require.Equal(t, ErrKeyNotFound, err)
+
+	// Signatures of the wrong key are rejected
+	k.Add(badSigner{
+		SecKeySigner: s1,
+		other:        sk3,
+	})
+	_, err = k.SignHash(cipher.MustAddressFromSecKey(sk1), hash)
+	require.Error(t, err)
+}
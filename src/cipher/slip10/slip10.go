@@ -0,0 +1,185 @@
+/*
+Package slip10 implements the ed25519 key derivation of the SLIP-0010 spec
+https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+
+The ed25519 curve only supports hardened child key derivation, so there are no extended public keys.
+Paths are parsed with the bip32 path parser, e.g. m/44'/501'/0'.
+*/
+package slip10
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"log"
+
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+const (
+	// FirstHardenedChild is the index of the first hardened child key, all derived keys must be hardened
+	FirstHardenedChild = bip32.FirstHardenedChild
+
+	// masterKey is the HMAC key of the ed25519 master key generation
+	masterKey = "ed25519 seed"
+)
+
+var (
+	// ErrInvalidSeedLength seed length must be in the range [16, 64]
+	ErrInvalidSeedLength = errors.New("Seed length must be between 128 and 512 bits")
+	// ErrNonHardenedChild ed25519 child keys can only be derived with hardened child numbers
+	ErrNonHardenedChild = errors.New("ed25519 child keys must be hardened")
+	// ErrMaxDepthReached maximum allowed depth (255) reached for child key
+	ErrMaxDepthReached = errors.New("Maximum child depth reached")
+	// ErrDeriveFromNonMasterKey is returned when deriving a full path starting with m/ from a key that is not a master key
+	ErrDeriveFromNonMasterKey = errors.New("Full path must be derived from a master key")
+)
+
+// PrivateKey is a SLIP-0010 ed25519 private key node
+type PrivateKey struct {
+	// Key is the 32 byte ed25519 private key seed
+	Key []byte
+	// ChainCode is the 32 byte chain code
+	ChainCode []byte
+	// Depth is the depth of the key in the path, 0 for the master key
+	Depth byte
+	// ChildNumber is the child number of the key, 0 for the master key
+	ChildNumber uint32
+}
+
+// NewMasterKey creates a new master key from the seed.
+// The seed must be between 128 and 512 bits.
+func NewMasterKey(seed []byte) (*PrivateKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeedLength
+	}
+
+	// Every 256 bit value is a valid ed25519 private key seed, so the master key can't be invalid
+	key, chainCode := hmacSplit([]byte(masterKey), seed)
+
+	return &PrivateKey{
+		Key:       key,
+		ChainCode: chainCode,
+	}, nil
+}
+
+// NewPrivateKeyFromPath returns the private key at a given path.
+// The path must be a full path starting with m/, with hardened nodes only.
+func NewPrivateKeyFromPath(seed []byte, p string) (*PrivateKey, error) {
+	path, err := bip32.ParsePath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeriveFromPath(k, path)
+}
+
+// DeriveFromPath derives the private key at a full path from the master key.
+// Returns a copy of the master key for the path m.
+func DeriveFromPath(master *PrivateKey, path *bip32.Path) (*PrivateKey, error) {
+	if master.Depth != 0 {
+		return nil, ErrDeriveFromNonMasterKey
+	}
+
+	if len(path.Elements) == 0 || !path.Elements[0].Master {
+		return nil, bip32.ErrPathNoMaster
+	}
+
+	if len(path.Elements) == 1 {
+		k := master.Clone()
+		return &k, nil
+	}
+
+	return master.DeriveSubpath(path.Elements[1:])
+}
+
+// DeriveSubpath derives a PrivateKey at a subpath, e.g. `0'/1'`.
+// The nodes argument must not be empty.
+func (k *PrivateKey) DeriveSubpath(nodes []bip32.PathNode) (*PrivateKey, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("Path nodes array empty when deriving a slip10 subpath")
+	}
+
+	ck := k
+	for _, n := range nodes {
+		if n.Master {
+			return nil, errors.New("PathNode is Master at a non-zero depth")
+		}
+
+		var err error
+		ck, err = ck.NewChildKey(n.ChildNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ck, nil
+}
+
+// NewChildKey derives the hardened child key at childIdx.
+// Returns ErrNonHardenedChild if childIdx is not a hardened child number.
+func (k *PrivateKey) NewChildKey(childIdx uint32) (*PrivateKey, error) {
+	if childIdx < FirstHardenedChild {
+		return nil, ErrNonHardenedChild
+	}
+
+	if k.Depth == 0xFF {
+		return nil, ErrMaxDepthReached
+	}
+
+	// I = HMAC-SHA512(Key = c_par, Data = 0x00 || ser256(k_par) || ser32(i))
+	data := make([]byte, 37)
+	copy(data[1:33], k.Key)
+	binary.BigEndian.PutUint32(data[33:], childIdx)
+
+	key, chainCode := hmacSplit(k.ChainCode, data)
+
+	return &PrivateKey{
+		Key:         key,
+		ChainCode:   chainCode,
+		Depth:       k.Depth + 1,
+		ChildNumber: childIdx,
+	}, nil
+}
+
+// PublicKey returns the 33 byte SLIP-0010 public key, which is the ed25519 public key prefixed with 0x00
+func (k *PrivateKey) PublicKey() []byte {
+	return append([]byte{0x00}, k.Ed25519PublicKey()...)
+}
+
+// Ed25519PrivateKey returns the ed25519 private key of the key
+func (k *PrivateKey) Ed25519PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.Key)
+}
+
+// Ed25519PublicKey returns the ed25519 public key of the key
+func (k *PrivateKey) Ed25519PublicKey() ed25519.PublicKey {
+	return k.Ed25519PrivateKey().Public().(ed25519.PublicKey)
+}
+
+// Clone returns a copy of the key
+func (k PrivateKey) Clone() PrivateKey {
+	return PrivateKey{
+		Key:         append([]byte{}, k.Key...),
+		ChainCode:   append([]byte{}, k.ChainCode...),
+		Depth:       k.Depth,
+		ChildNumber: k.ChildNumber,
+	}
+}
+
+// hmacSplit returns the left and right 32 bytes of HMAC-SHA512(key, data)
+func hmacSplit(key, data []byte) ([]byte, []byte) {
+	h := hmac.New(sha512.New, key)
+	if _, err := h.Write(data); err != nil {
+		log.Panic(err)
+	}
+	i := h.Sum(nil)
+	return i[:32], i[32:]
+}
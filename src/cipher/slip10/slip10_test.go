@@ -0,0 +1,169 @@
+package slip10
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+type testKey struct {
+	path      string
+	chainCode string
+	privKey   string
+	pubKey    string
+}
+
+func TestSlip10TestVectors(t *testing.T) {
+	// ed25519 test vectors from:
+	// https://github.com/satoshilabs/slips/blob/master/slip-0010.md#test-vector-1-for-ed25519
+	cases := []struct {
+		seed string
+		keys []testKey
+	}{
+		{
+			seed: "000102030405060708090a0b0c0d0e0f",
+			keys: []testKey{
+				{
+					path:      "m",
+					chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+					privKey:   "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+					pubKey:    "00a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+				},
+				{
+					path:      "m/0H",
+					chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+					privKey:   "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+					pubKey:    "008c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+				},
+				{
+					path:      "m/0H/1H",
+					chainCode: "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+					privKey:   "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+					pubKey:    "001932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+				},
+				{
+					path:      "m/0H/1H/2H",
+					chainCode: "2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c",
+					privKey:   "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9",
+					pubKey:    "00ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1",
+				},
+				{
+					path:      "m/0H/1H/2H/2H",
+					chainCode: "8f6d87f93d750e0efccda017d662a1b31a266e4a6f5993b15f5c1f07f74dd5cc",
+					privKey:   "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662",
+					pubKey:    "008abae2d66361c879b900d204ad2cc4984fa2aa344dd7ddc46007329ac76c429c",
+				},
+				{
+					path:      "m/0H/1H/2H/2H/1000000000H",
+					chainCode: "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230",
+					privKey:   "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+					pubKey:    "003c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a",
+				},
+			},
+		},
+		{
+			seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			keys: []testKey{
+				{
+					path:      "m",
+					chainCode: "ef70a74db9c3a5af931b5fe73ed8e1a53464133654fd55e7a66f8570b8e33c3b",
+					privKey:   "171cb88b1b3c1db25add599712e36245d75bc65a1a5c9e18d76f9f2b1eab4012",
+					pubKey:    "008fe9693f8fa62a4305a140b9764c5ee01e455963744fe18204b4fb948249308a",
+				},
+				{
+					path:      "m/0H",
+					chainCode: "0b78a3226f915c082bf118f83618a618ab6dec793752624cbeb622acb562862d",
+					privKey:   "1559eb2bbec5790b0c65d8693e4d0875b1747f4970ae8b650486ed7470845635",
+					pubKey:    "0086fab68dcb57aa196c77c5f264f215a112c22a912c10d123b0d03c3c28ef1037",
+				},
+				{
+					path:      "m/0H/2147483647H",
+					chainCode: "138f0b2551bcafeca6ff2aa88ba8ed0ed8de070841f0c4ef0165df8181eaad7f",
+					privKey:   "ea4f5bfe8694d8bb74b7b59404632fd5968b774ed545e810de9c32a4fb4192f4",
+					pubKey:    "005ba3b9ac6e90e83effcd25ac4e58a1365a9e35a3d3ae5eb07b9e4d90bcf7506d",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		seed, err := hex.DecodeString(tc.seed)
+		require.NoError(t, err)
+
+		for _, tk := range tc.keys {
+			t.Run(tk.path, func(t *testing.T) {
+				k, err := NewPrivateKeyFromPath(seed, tk.path)
+				require.NoError(t, err)
+
+				require.Equal(t, tk.chainCode, hex.EncodeToString(k.ChainCode))
+				require.Equal(t, tk.privKey, hex.EncodeToString(k.Key))
+				require.Equal(t, tk.pubKey, hex.EncodeToString(k.PublicKey()))
+
+				p, err := bip32.ParsePath(tk.path)
+				require.NoError(t, err)
+				require.Equal(t, byte(len(p.Elements)-1), k.Depth)
+				if k.Depth > 0 {
+					require.Equal(t, p.Elements[len(p.Elements)-1].ChildNumber, k.ChildNumber)
+				}
+
+				// The ed25519 keys sign and verify
+				msg := []byte("slip10")
+				sig := ed25519.Sign(k.Ed25519PrivateKey(), msg)
+				require.True(t, ed25519.Verify(k.Ed25519PublicKey(), msg, sig))
+			})
+		}
+	}
+}
+
+func TestNewMasterKey(t *testing.T) {
+	_, err := NewMasterKey(make([]byte, 15))
+	require.Equal(t, ErrInvalidSeedLength, err)
+
+	_, err = NewMasterKey(make([]byte, 65))
+	require.Equal(t, ErrInvalidSeedLength, err)
+
+	_, err = NewMasterKey(make([]byte, 16))
+	require.NoError(t, err)
+
+	_, err = NewMasterKey(make([]byte, 64))
+	require.NoError(t, err)
+}
+
+func TestDeriveErrors(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	_, err = master.NewChildKey(0)
+	require.Equal(t, ErrNonHardenedChild, err)
+
+	_, err = NewPrivateKeyFromPath(seed, "m/0'/1")
+	require.Equal(t, ErrNonHardenedChild, err)
+
+	_, err = NewPrivateKeyFromPath(seed, "0'/1'")
+	require.Equal(t, bip32.ErrPathNoMaster, err)
+
+	// The path m returns a copy of the master key
+	p, err := bip32.ParsePath("m")
+	require.NoError(t, err)
+	k, err := DeriveFromPath(master, p)
+	require.NoError(t, err)
+	require.Equal(t, master, k)
+	k.Key[0]++
+	require.NotEqual(t, master.Key, k.Key)
+
+	// A full path can't be derived from a child key
+	child, err := master.NewChildKey(FirstHardenedChild)
+	require.NoError(t, err)
+	_, err = DeriveFromPath(child, p)
+	require.Equal(t, ErrDeriveFromNonMasterKey, err)
+
+	child.Depth = 0xFF
+	_, err = child.NewChildKey(FirstHardenedChild)
+	require.Equal(t, ErrMaxDepthReached, err)
+}
@@ -0,0 +1,63 @@
+package slip39
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/skycoin/skycoin/src/cipher/pbkdf2"
+)
+
+const (
+	// baseIterationCount is the minimum number of PBKDF2 iterations of the encryption
+	baseIterationCount = 10000
+	// roundCount is the number of rounds of the Feistel cipher
+	roundCount = 4
+)
+
+func salt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+
+	s := make([]byte, len(customizationString)+2)
+	copy(s, customizationString)
+	binary.BigEndian.PutUint16(s[len(customizationString):], identifier)
+	return s
+}
+
+func roundFunction(i byte, passphrase []byte, e byte, salt, r []byte) []byte {
+	password := append([]byte{i}, passphrase...)
+	iterations := (baseIterationCount << e) / roundCount
+	return pbkdf2.Key(password, append(append([]byte{}, salt...), r...), iterations, len(r), sha256.New)
+}
+
+func xorBytes(a, b []byte) []byte {
+	v := make([]byte, len(a))
+	for i := range a {
+		v[i] = a[i] ^ b[i]
+	}
+	return v
+}
+
+// feistel runs the 4 rounds Feistel network with PBKDF2-SHA256 round function,
+// in the given order of the round indices
+func feistel(data, passphrase []byte, e byte, identifier uint16, extendable bool, rounds []byte) []byte {
+	half := len(data) / 2
+	l := append([]byte{}, data[:half]...)
+	r := append([]byte{}, data[half:]...)
+	s := salt(identifier, extendable)
+	for _, i := range rounds {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, e, s, r))
+	}
+	return append(r, l...)
+}
+
+// encrypt encrypts the master secret with the passphrase
+func encrypt(masterSecret, passphrase []byte, e byte, identifier uint16, extendable bool) []byte {
+	return feistel(masterSecret, passphrase, e, identifier, extendable, []byte{0, 1, 2, 3})
+}
+
+// decrypt decrypts the encrypted master secret with the passphrase
+func decrypt(encryptedMasterSecret, passphrase []byte, e byte, identifier uint16, extendable bool) []byte {
+	return feistel(encryptedMasterSecret, passphrase, e, identifier, extendable, []byte{3, 2, 1, 0})
+}
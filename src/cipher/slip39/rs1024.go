@@ -0,0 +1,69 @@
+package slip39
+
+// customizationString is mixed into the checksum and the encryption salt of non extendable shares
+const customizationString = "shamir"
+
+// customizationStringExtendable is mixed into the checksum of extendable shares
+const customizationStringExtendable = "shamir_extendable"
+
+// checksumWords is the number of words of the RS1024 checksum
+const checksumWords = 3
+
+var rs1024Gen = [10]uint32{
+	0xE0E040,
+	0x1C1C080,
+	0x3838100,
+	0x7070200,
+	0xE0E0009,
+	0x1C0C2412,
+	0x38086C24,
+	0x3090FC48,
+	0x21B1F890,
+	0x3F3F120,
+}
+
+// rs1024Polymod computes the Reed-Solomon code over GF(1024) of the 10 bit values
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+		for i := uint(0); i < 10; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= rs1024Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func customization(extendable bool) []int {
+	s := customizationString
+	if extendable {
+		s = customizationStringExtendable
+	}
+
+	values := make([]int, len(s))
+	for i := 0; i < len(s); i++ {
+		values[i] = int(s[i])
+	}
+	return values
+}
+
+// rs1024CreateChecksum returns the checksum words of the data words
+func rs1024CreateChecksum(data []int, extendable bool) []int {
+	values := append(customization(extendable), data...)
+	values = append(values, make([]int, checksumWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+
+	checksum := make([]int, checksumWords)
+	for i := range checksum {
+		checksum[i] = int(polymod>>(10*uint(checksumWords-1-i))) & 1023
+	}
+	return checksum
+}
+
+// rs1024VerifyChecksum returns whether the data words end with a valid checksum
+func rs1024VerifyChecksum(data []int, extendable bool) bool {
+	return rs1024Polymod(append(customization(extendable), data...)) == 1
+}
@@ -0,0 +1,175 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// maxShareCount is the maximum number of shares that can be created from a secret
+	maxShareCount = 16
+	// digestLength is the length of the digest of the shared secret in bytes
+	digestLength = 4
+	// digestIndex is the x coordinate of the digest share
+	digestIndex = 254
+	// secretIndex is the x coordinate of the shared secret
+	secretIndex = 255
+)
+
+var (
+	expTable [255]byte
+	logTable [256]byte
+)
+
+func init() {
+	// Precompute the exponent and log tables of GF(256), using the
+	// Rijndael polynomial x^8 + x^4 + x^3 + x + 1 and the generator x + 1
+	poly := 1
+	for i := 0; i < 255; i++ {
+		expTable[i] = byte(poly)
+		logTable[poly] = byte(i)
+
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11B
+		}
+	}
+}
+
+// rawShare is a point of the polynomial, x is the share index and value the evaluations
+// of the polynomials of all secret bytes
+type rawShare struct {
+	x     byte
+	value []byte
+}
+
+// interpolate evaluates the Lagrange interpolation polynomial of the shares at x
+func interpolate(shares []rawShare, x byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares to interpolate")
+	}
+
+	n := len(shares[0].value)
+	seen := make(map[byte]struct{}, len(shares))
+	for _, s := range shares {
+		if _, ok := seen[s.x]; ok {
+			return nil, errors.New("share indices must be unique")
+		}
+		seen[s.x] = struct{}{}
+
+		if len(s.value) != n {
+			return nil, errors.New("all share values must have the same length")
+		}
+	}
+
+	for _, s := range shares {
+		if s.x == x {
+			return append([]byte{}, s.value...), nil
+		}
+	}
+
+	// log of the product of (x_i - x) for all shares, in GF(256) subtraction is xor.
+	// The log of 0 is never used, log(x_i ^ x_i) is logTable[0] == 0.
+	logProd := 0
+	for _, s := range shares {
+		logProd += int(logTable[s.x^x])
+	}
+
+	result := make([]byte, n)
+	for _, s := range shares {
+		logBasis := logProd - int(logTable[s.x^x])
+		for _, o := range shares {
+			logBasis -= int(logTable[s.x^o.x])
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+
+		for i, b := range s.value {
+			if b != 0 {
+				result[i] ^= expTable[(int(logTable[b])+logBasis)%255]
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func createDigest(randomData, sharedSecret []byte) []byte {
+	h := hmac.New(sha256.New, randomData)
+	h.Write(sharedSecret) //nolint:errcheck
+	return h.Sum(nil)[:digestLength]
+}
+
+// splitSecret splits the secret into shareCount shares, any threshold of them can recover the secret
+func splitSecret(threshold, shareCount int, secret []byte) ([]rawShare, error) {
+	if threshold < 1 {
+		return nil, errors.New("the requested threshold must be a positive integer")
+	}
+
+	if threshold > shareCount {
+		return nil, errors.New("the requested threshold must not exceed the number of shares")
+	}
+
+	if shareCount > maxShareCount {
+		return nil, fmt.Errorf("the requested number of shares must not exceed %d", maxShareCount)
+	}
+
+	// If the threshold is 1, then the digest of the shared secret is not used
+	if threshold == 1 {
+		shares := make([]rawShare, shareCount)
+		for i := range shares {
+			shares[i] = rawShare{x: byte(i), value: append([]byte{}, secret...)}
+		}
+		return shares, nil
+	}
+
+	randomShareCount := threshold - 2
+	shares := make([]rawShare, 0, shareCount)
+	for i := 0; i < randomShareCount; i++ {
+		shares = append(shares, rawShare{x: byte(i), value: cipher.RandByte(len(secret))})
+	}
+
+	randomPart := cipher.RandByte(len(secret) - digestLength)
+	digest := createDigest(randomPart, secret)
+
+	baseShares := append([]rawShare{}, shares...)
+	baseShares = append(baseShares,
+		rawShare{x: digestIndex, value: append(digest, randomPart...)},
+		rawShare{x: secretIndex, value: secret})
+
+	for i := randomShareCount; i < shareCount; i++ {
+		v, err := interpolate(baseShares, byte(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: byte(i), value: v})
+	}
+
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from threshold shares and verifies its digest
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return append([]byte{}, shares[0].value...), nil
+	}
+
+	sharedSecret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digestShare, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(digestShare[:digestLength], createDigest(digestShare[digestLength:], sharedSecret)) {
+		return nil, ErrInvalidDigest
+	}
+
+	return sharedSecret, nil
+}
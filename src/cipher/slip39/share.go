@@ -0,0 +1,156 @@
+package slip39
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// radixBits is the number of bits of a mnemonic word
+	radixBits = 10
+	// radix is the number of words of the wordlist
+	radix = 1 << radixBits
+	// idLengthBits is the length of the random identifier in bits
+	idLengthBits = 15
+	// iterationExpLengthBits is the length of the iteration exponent in bits
+	iterationExpLengthBits = 4
+	// idExpLengthWords is the number of words of the identifier, extendable flag and iteration exponent
+	idExpLengthWords = 2
+	// groupParamsLengthWords is the number of words of the group and member parameters
+	groupParamsLengthWords = 2
+	// metadataLengthWords is the number of words of the share header and checksum
+	metadataLengthWords = idExpLengthWords + groupParamsLengthWords + checksumWords
+	// minStrengthBits is the minimum allowed entropy of the master secret
+	minStrengthBits = 128
+	// minMnemonicLengthWords is the minimum number of words of a mnemonic
+	minMnemonicLengthWords = metadataLengthWords + (minStrengthBits+radixBits-1)/radixBits
+)
+
+// Share is a SLIP-0039 share decoded from a mnemonic
+type Share struct {
+	Identifier        uint16
+	Extendable        bool
+	IterationExponent byte
+	GroupIndex        byte
+	GroupThreshold    byte
+	GroupCount        byte
+	MemberIndex       byte
+	MemberThreshold   byte
+	Value             []byte
+}
+
+// Mnemonic encodes the share as a mnemonic
+func (s Share) Mnemonic() string {
+	words := s.words()
+	ws := make([]string, len(words))
+	for i, w := range words {
+		ws[i] = wordList[w]
+	}
+	return strings.Join(ws, " ")
+}
+
+func (s Share) words() []int {
+	ext := 0
+	if s.Extendable {
+		ext = 1
+	}
+
+	idExp := int(s.Identifier)<<(iterationExpLengthBits+1) | ext<<iterationExpLengthBits | int(s.IterationExponent)
+	groupParams := int(s.GroupIndex)<<16 |
+		int(s.GroupThreshold-1)<<12 |
+		int(s.GroupCount-1)<<8 |
+		int(s.MemberIndex)<<4 |
+		int(s.MemberThreshold-1)
+
+	valueWordCount := (len(s.Value)*8 + radixBits - 1) / radixBits
+
+	data := make([]int, 0, idExpLengthWords+groupParamsLengthWords+valueWordCount+checksumWords)
+	data = append(data, intToWords(big.NewInt(int64(idExp)), idExpLengthWords)...)
+	data = append(data, intToWords(big.NewInt(int64(groupParams)), groupParamsLengthWords)...)
+	data = append(data, intToWords(new(big.Int).SetBytes(s.Value), valueWordCount)...)
+	return append(data, rs1024CreateChecksum(data, s.Extendable)...)
+}
+
+// DecodeMnemonic decodes a mnemonic to a share, verifying its checksum
+func DecodeMnemonic(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	data := make([]int, len(words))
+	for i, w := range words {
+		idx, ok := wordIndex[w]
+		if !ok {
+			return nil, errUnknownWord(w)
+		}
+		data[i] = idx
+	}
+
+	if len(data) < minMnemonicLengthWords {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	paddingLen := (radixBits * (len(data) - metadataLengthWords)) % 16
+	if paddingLen > 8 {
+		return nil, ErrInvalidMnemonicLength
+	}
+
+	idExp := wordsToInt(data[:idExpLengthWords]).Int64()
+	extendable := (idExp>>iterationExpLengthBits)&1 == 1
+	if !rs1024VerifyChecksum(data, extendable) {
+		return nil, ErrInvalidChecksum
+	}
+
+	groupParams := wordsToInt(data[idExpLengthWords : idExpLengthWords+groupParamsLengthWords]).Int64()
+	s := &Share{
+		Identifier:        uint16(idExp >> (iterationExpLengthBits + 1)),
+		Extendable:        extendable,
+		IterationExponent: byte(idExp & (1<<iterationExpLengthBits - 1)),
+		GroupIndex:        byte(groupParams >> 16 & 0xF),
+		GroupThreshold:    byte(groupParams>>12&0xF) + 1,
+		GroupCount:        byte(groupParams>>8&0xF) + 1,
+		MemberIndex:       byte(groupParams >> 4 & 0xF),
+		MemberThreshold:   byte(groupParams&0xF) + 1,
+	}
+
+	if s.GroupCount < s.GroupThreshold || s.GroupIndex >= s.GroupCount {
+		return nil, ErrInvalidGroupThreshold
+	}
+
+	valueData := data[idExpLengthWords+groupParamsLengthWords : len(data)-checksumWords]
+	valueByteCount := (radixBits*len(valueData) - paddingLen) / 8
+	value := wordsToInt(valueData)
+	if value.BitLen() > valueByteCount*8 {
+		return nil, ErrInvalidPadding
+	}
+
+	b := value.Bytes()
+	s.Value = make([]byte, valueByteCount)
+	copy(s.Value[valueByteCount-len(b):], b)
+
+	return s, nil
+}
+
+func errUnknownWord(w string) error {
+	return fmt.Errorf("%v: %q", ErrUnknownWord, w)
+}
+
+// intToWords converts the integer to n radix-1024 digits, most significant first
+func intToWords(v *big.Int, n int) []int {
+	words := make([]int, n)
+	mask := big.NewInt(radix - 1)
+	x := new(big.Int).Set(v)
+	for i := n - 1; i >= 0; i-- {
+		words[i] = int(new(big.Int).And(x, mask).Int64())
+		x.Rsh(x, radixBits)
+	}
+	return words
+}
+
+// wordsToInt converts the radix-1024 digits, most significant first, to an integer
+func wordsToInt(words []int) *big.Int {
+	v := new(big.Int)
+	for _, w := range words {
+		v.Lsh(v, radixBits)
+		v.Or(v, big.NewInt(int64(w)))
+	}
+	return v
+}
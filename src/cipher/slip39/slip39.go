@@ -0,0 +1,262 @@
+// Package slip39 is the Golang implementation of the SLIP-0039 spec,
+// Shamir's Secret-Sharing for Mnemonic Codes.
+//
+// The official SLIP-0039 spec can be found at
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// DefaultIterationExponent is the default iteration exponent, the passphrase encryption
+// uses 10000 * 2^e PBKDF2 iterations
+const DefaultIterationExponent = 1
+
+var (
+	// ErrInvalidMasterSecretLength is returned when the master secret is shorter than 128 bits or has an odd length
+	ErrInvalidMasterSecretLength = errors.New("Master secret must be at least 128 bits and its length in bytes must be even")
+
+	// ErrInvalidPassphrase is returned if the passphrase contains characters other than printable ASCII
+	ErrInvalidPassphrase = errors.New("Passphrase must contain only printable ASCII characters")
+
+	// ErrInvalidGroupThreshold is returned if the group threshold is zero or exceeds the number of groups
+	ErrInvalidGroupThreshold = errors.New("Group threshold must be between 1 and the number of groups")
+
+	// ErrInvalidMemberThreshold is returned if a group would have multiple shares with member threshold 1
+	ErrInvalidMemberThreshold = errors.New("Creating multiple member shares with member threshold 1 is not allowed")
+
+	// ErrInvalidIterationExponent is returned if the iteration exponent does not fit in 4 bits
+	ErrInvalidIterationExponent = errors.New("Iteration exponent must be less than 16")
+
+	// ErrUnknownWord is returned if a mnemonic contains an unrecognized word
+	ErrUnknownWord = errors.New("Mnemonic contains an unrecognized word")
+
+	// ErrInvalidMnemonicLength is returned if a mnemonic has an invalid number of words
+	ErrInvalidMnemonicLength = errors.New("Invalid mnemonic length")
+
+	// ErrInvalidChecksum is returned if the checksum of a mnemonic is incorrect
+	ErrInvalidChecksum = errors.New("Invalid mnemonic checksum")
+
+	// ErrInvalidPadding is returned if the padding bits of a mnemonic are not zero
+	ErrInvalidPadding = errors.New("Invalid mnemonic padding")
+
+	// ErrInvalidDigest is returned if the digest of the recovered secret does not match, e.g. the shares
+	// are from different secrets
+	ErrInvalidDigest = errors.New("Invalid digest of the shared secret")
+
+	// ErrNoMnemonics is returned if no mnemonics are provided to recover the master secret
+	ErrNoMnemonics = errors.New("The list of mnemonics is empty")
+
+	// ErrMismatchedShares is returned if the mnemonics belong to different share sets
+	ErrMismatchedShares = errors.New("All mnemonics must belong to the same share set")
+
+	// ErrInsufficientShares is returned if the mnemonics do not reach the group and member thresholds
+	ErrInsufficientShares = errors.New("Insufficient number of mnemonics to recover the master secret")
+)
+
+// Group is the member threshold and member count of a group of shares
+type Group struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// GenerateMnemonics splits the master secret into mnemonic shares of the groups,
+// the master secret can be recovered from groupThreshold groups, with at least the
+// member threshold of shares of each group.
+// The master secret is encrypted with the passphrase, which may be empty.
+func GenerateMnemonics(groupThreshold int, groups []Group, masterSecret, passphrase []byte, iterationExponent byte) ([][]string, error) {
+	if len(masterSecret)*8 < minStrengthBits || len(masterSecret)%2 != 0 {
+		return nil, ErrInvalidMasterSecretLength
+	}
+
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, ErrInvalidGroupThreshold
+	}
+
+	if iterationExponent >= 1<<iterationExpLengthBits {
+		return nil, ErrInvalidIterationExponent
+	}
+
+	for _, g := range groups {
+		if g.MemberThreshold == 1 && g.MemberCount > 1 {
+			return nil, ErrInvalidMemberThreshold
+		}
+	}
+
+	identifier := binary.BigEndian.Uint16(cipher.RandByte(2)) & (1<<idLengthBits - 1)
+	ems := encrypt(masterSecret, passphrase, iterationExponent, identifier, false)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), ems)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(groups))
+	for i, g := range groups {
+		memberShares, err := splitSecret(g.MemberThreshold, g.MemberCount, groupShares[i].value)
+		if err != nil {
+			return nil, fmt.Errorf("group %d: %v", i, err)
+		}
+
+		mnemonics[i] = make([]string, len(memberShares))
+		for j, ms := range memberShares {
+			mnemonics[i][j] = Share{
+				Identifier:        identifier,
+				IterationExponent: iterationExponent,
+				GroupIndex:        groupShares[i].x,
+				GroupThreshold:    byte(groupThreshold),
+				GroupCount:        byte(len(groups)),
+				MemberIndex:       ms.x,
+				MemberThreshold:   byte(g.MemberThreshold),
+				Value:             ms.value,
+			}.Mnemonic()
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the master secret from the mnemonic shares,
+// decrypting it with the passphrase the shares were generated with
+func CombineMnemonics(mnemonics []string, passphrase []byte) ([]byte, error) {
+	shares, err := decodeMnemonics(mnemonics)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	ems, err := recoverEncryptedMasterSecret(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	s := shares[0]
+	return decrypt(ems, passphrase, s.IterationExponent, s.Identifier, s.Extendable), nil
+}
+
+// Identifier returns the identifier of the share set of the mnemonics
+func Identifier(mnemonics []string) (uint16, error) {
+	shares, err := decodeMnemonics(mnemonics)
+	if err != nil {
+		return 0, err
+	}
+
+	return shares[0].Identifier, nil
+}
+
+func decodeMnemonics(mnemonics []string) ([]*Share, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrNoMnemonics
+	}
+
+	shares := make([]*Share, len(mnemonics))
+	for i, m := range mnemonics {
+		s, err := DecodeMnemonic(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			f := shares[0]
+			if s.Identifier != f.Identifier ||
+				s.Extendable != f.Extendable ||
+				s.IterationExponent != f.IterationExponent ||
+				s.GroupThreshold != f.GroupThreshold ||
+				s.GroupCount != f.GroupCount ||
+				len(s.Value) != len(f.Value) {
+				return nil, ErrMismatchedShares
+			}
+		}
+
+		shares[i] = s
+	}
+
+	return shares, nil
+}
+
+func recoverEncryptedMasterSecret(shares []*Share) ([]byte, error) {
+	// Collect the member shares of each group, in the order the groups appear
+	var groupIndices []byte
+	groups := make(map[byte][]*Share)
+	for _, s := range shares {
+		g, ok := groups[s.GroupIndex]
+		if !ok {
+			groupIndices = append(groupIndices, s.GroupIndex)
+		}
+
+		duplicate := false
+		for _, o := range g {
+			if o.MemberIndex == s.MemberIndex {
+				if !bytes.Equal(o.Value, s.Value) {
+					return nil, fmt.Errorf("%v: group %d member %d", ErrMismatchedShares, s.GroupIndex, s.MemberIndex)
+				}
+				duplicate = true
+				break
+			}
+		}
+
+		if duplicate {
+			continue
+		}
+
+		if len(g) > 0 && g[0].MemberThreshold != s.MemberThreshold {
+			return nil, ErrMismatchedShares
+		}
+
+		groups[s.GroupIndex] = append(g, s)
+	}
+
+	groupThreshold := int(shares[0].GroupThreshold)
+	groupShares := make([]rawShare, 0, groupThreshold)
+	for _, gi := range groupIndices {
+		if len(groupShares) == groupThreshold {
+			break
+		}
+
+		g := groups[gi]
+		memberThreshold := int(g[0].MemberThreshold)
+		if len(g) < memberThreshold {
+			continue
+		}
+
+		memberShares := make([]rawShare, memberThreshold)
+		for i, s := range g[:memberThreshold] {
+			memberShares[i] = rawShare{x: s.MemberIndex, value: s.Value}
+		}
+
+		v, err := recoverSecret(memberThreshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+
+		groupShares = append(groupShares, rawShare{x: gi, value: v})
+	}
+
+	if len(groupShares) < groupThreshold {
+		return nil, ErrInsufficientShares
+	}
+
+	return recoverSecret(groupThreshold, groupShares)
+}
+
+func validatePassphrase(passphrase []byte) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return ErrInvalidPassphrase
+		}
+	}
+	return nil
+}
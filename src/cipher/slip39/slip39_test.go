@@ -0,0 +1,236 @@
+package slip39
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from https://github.com/trezor/python-shamir-mnemonic/blob/master/vectors.json,
+// all encrypted with the passphrase "TREZOR"
+func TestCombineMnemonicsVectors(t *testing.T) {
+	tt := []struct {
+		name         string
+		mnemonics    []string
+		masterSecret string
+		err          error
+	}{
+		{
+			name: "valid mnemonic without sharing (128 bits)",
+			mnemonics: []string{
+				"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard",
+			},
+			masterSecret: "bb54aac4b89dc868ba37d9cc21b2cece",
+		},
+		{
+			name: "mnemonic with invalid checksum (128 bits)",
+			mnemonics: []string{
+				"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney",
+			},
+			err: ErrInvalidChecksum,
+		},
+		{
+			name: "basic sharing 2-of-3 (128 bits)",
+			mnemonics: []string{
+				"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+				"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+			},
+			masterSecret: "b43ceb7e57a0ea8766221624d01b0864",
+		},
+		{
+			name: "basic sharing 2-of-3, one share (128 bits)",
+			mnemonics: []string{
+				"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			},
+			err: ErrInsufficientShares,
+		},
+		{
+			name: "mnemonics with groups, sufficient shares (128 bits)",
+			mnemonics: []string{
+				"eraser senior decision roster beard treat identify grumpy salt index fake aviation theater cubic bike cause research dragon emphasis counter",
+				"eraser senior ceramic snake clay various huge numb argue hesitate auction category timber browser greatest hanger petition script leaf pickup",
+				"eraser senior ceramic shaft dynamic become junior wrist silver peasant force math alto coal amazing segment yelp velvet image paces",
+				"eraser senior ceramic round column hawk trust auction smug shame alive greatest sheriff living perfect corner chest sled fumes adequate",
+				"eraser senior decision smug corner ruin rescue cubic angel tackle skin skunk program roster trash rumor slush angel flea amazing",
+			},
+			masterSecret: "7c3397a292a5941682d7a4ae2d898d11",
+		},
+		{
+			name: "mnemonics with groups, insufficient member shares (128 bits)",
+			mnemonics: []string{
+				"eraser senior decision roster beard treat identify grumpy salt index fake aviation theater cubic bike cause research dragon emphasis counter",
+				"eraser senior ceramic snake clay various huge numb argue hesitate auction category timber browser greatest hanger petition script leaf pickup",
+				"eraser senior ceramic shaft dynamic become junior wrist silver peasant force math alto coal amazing segment yelp velvet image paces",
+				"eraser senior decision smug corner ruin rescue cubic angel tackle skin skunk program roster trash rumor slush angel flea amazing",
+			},
+			err: ErrInsufficientShares,
+		},
+		{
+			name: "valid mnemonic without sharing (256 bits)",
+			mnemonics: []string{
+				"theory painting academic academic armed sweater year military elder discuss acne wildlife boring employer fused large satoshi bundle carbon diagnose anatomy hamster leaves tracks paces beyond phantom capital marvel lips brave detect luck",
+			},
+			masterSecret: "989baf9dcaad5b10ca33dfd8cc75e42477025dce88ae83e75a230086a0e00e92",
+		},
+		{
+			name: "mnemonics from different share sets",
+			mnemonics: []string{
+				"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+				"eraser senior decision roster beard treat identify grumpy salt index fake aviation theater cubic bike cause research dragon emphasis counter",
+			},
+			err: ErrMismatchedShares,
+		},
+		{
+			name:      "unknown word",
+			mnemonics: []string{"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision foo"},
+			err:       errUnknownWord("foo"),
+		},
+		{
+			name:      "mnemonic too short",
+			mnemonics: []string{"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal"},
+			err:       ErrInvalidMnemonicLength,
+		},
+		{
+			name: "no mnemonics",
+			err:  ErrNoMnemonics,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ms, err := CombineMnemonics(tc.mnemonics, []byte("TREZOR"))
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.masterSecret, hex.EncodeToString(ms))
+		})
+	}
+}
+
+func TestGenerateMnemonics(t *testing.T) {
+	masterSecret, err := hex.DecodeString("7c3397a292a5941682d7a4ae2d898d11b43ceb7e57a0ea8766221624d01b0864")
+	require.NoError(t, err)
+
+	tt := []struct {
+		name           string
+		groupThreshold int
+		groups         []Group
+		masterSecret   []byte
+		passphrase     []byte
+		combine        func(ms [][]string) []string
+		err            error
+	}{
+		{
+			name:           "single share",
+			groupThreshold: 1,
+			groups:         []Group{{1, 1}},
+			masterSecret:   masterSecret,
+			combine: func(ms [][]string) []string {
+				return ms[0]
+			},
+		},
+		{
+			name:           "3 of 5 shares",
+			groupThreshold: 1,
+			groups:         []Group{{3, 5}},
+			masterSecret:   masterSecret[:16],
+			passphrase:     []byte("TREZOR"),
+			combine: func(ms [][]string) []string {
+				return []string{ms[0][4], ms[0][0], ms[0][2]}
+			},
+		},
+		{
+			name:           "2 of 3 groups",
+			groupThreshold: 2,
+			groups:         []Group{{1, 1}, {2, 3}, {3, 5}},
+			masterSecret:   masterSecret,
+			combine: func(ms [][]string) []string {
+				return []string{ms[2][1], ms[1][2], ms[2][3], ms[1][0], ms[2][4]}
+			},
+		},
+		{
+			name:           "master secret too short",
+			groupThreshold: 1,
+			groups:         []Group{{1, 1}},
+			masterSecret:   masterSecret[:14],
+			err:            ErrInvalidMasterSecretLength,
+		},
+		{
+			name:           "master secret odd length",
+			groupThreshold: 1,
+			groups:         []Group{{1, 1}},
+			masterSecret:   masterSecret[:17],
+			err:            ErrInvalidMasterSecretLength,
+		},
+		{
+			name:           "group threshold exceeds groups",
+			groupThreshold: 2,
+			groups:         []Group{{1, 1}},
+			masterSecret:   masterSecret,
+			err:            ErrInvalidGroupThreshold,
+		},
+		{
+			name:           "multiple shares with member threshold 1",
+			groupThreshold: 1,
+			groups:         []Group{{1, 2}},
+			masterSecret:   masterSecret,
+			err:            ErrInvalidMemberThreshold,
+		},
+		{
+			name:           "invalid passphrase",
+			groupThreshold: 1,
+			groups:         []Group{{1, 1}},
+			masterSecret:   masterSecret,
+			passphrase:     []byte("pass\nphrase"),
+			err:            ErrInvalidPassphrase,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ms, err := GenerateMnemonics(tc.groupThreshold, tc.groups, tc.masterSecret, tc.passphrase, 0)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Len(t, ms, len(tc.groups))
+			for i, g := range tc.groups {
+				require.Len(t, ms[i], g.MemberCount)
+			}
+
+			s, err := CombineMnemonics(tc.combine(ms), tc.passphrase)
+			require.NoError(t, err)
+			require.Equal(t, tc.masterSecret, s)
+
+			// Using a different passphrase yields a different master secret
+			s, err = CombineMnemonics(tc.combine(ms), []byte("wrong"))
+			require.NoError(t, err)
+			require.NotEqual(t, tc.masterSecret, s)
+		})
+	}
+}
+
+func TestMnemonicEncodeDecode(t *testing.T) {
+	s := Share{
+		Identifier:        0x7fff,
+		IterationExponent: 15,
+		GroupIndex:        3,
+		GroupThreshold:    2,
+		GroupCount:        4,
+		MemberIndex:       5,
+		MemberThreshold:   3,
+		Value:             []byte("0123456789abcdefghij"),
+	}
+
+	s2, err := DecodeMnemonic(s.Mnemonic())
+	require.NoError(t, err)
+	require.Equal(t, s, *s2)
+
+	id, err := Identifier([]string{s.Mnemonic()})
+	require.NoError(t, err)
+	require.Equal(t, s.Identifier, id)
+}
@@ -0,0 +1,119 @@
+package cipher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// base58Alphabet is the alphabet of base58 encoded addresses
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	// maxVanityPrefixLen is the length of the shortest base58 encoded address
+	maxVanityPrefixLen = 34
+)
+
+var (
+	// ErrEmptyVanityPrefix is returned when searching a vanity address with an empty prefix
+	ErrEmptyVanityPrefix = errors.New("Vanity address prefix is empty")
+	// ErrVanityPrefixTooLong is returned when the vanity prefix is longer than an address
+	ErrVanityPrefixTooLong = fmt.Errorf("Vanity address prefix is longer than %d characters", maxVanityPrefixLen)
+)
+
+// vanityProgressInterval is the interval of the progress callback of SearchVanityAddress
+var vanityProgressInterval = time.Second
+
+// VanityProgressFunc is called periodically by SearchVanityAddress with the number of keys tried
+type VanityProgressFunc func(attempts uint64)
+
+// VanityAttempts returns the expected number of keys tried to find an address with the prefix
+func VanityAttempts(prefix string) float64 {
+	return math.Pow(58, float64(len(prefix)))
+}
+
+func validateVanityPrefix(prefix string) error {
+	if prefix == "" {
+		return ErrEmptyVanityPrefix
+	}
+
+	if len(prefix) > maxVanityPrefixLen {
+		return ErrVanityPrefixTooLong
+	}
+
+	for _, c := range prefix {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return fmt.Errorf("Vanity address prefix has the invalid base58 character %q", c)
+		}
+	}
+
+	return nil
+}
+
+// SearchVanityAddress generates random keys with workers goroutines until the address of a key starts with prefix,
+// and returns the address and its secret key. If workers is not positive, a worker is started per CPU.
+// The search stops with the context error when ctx is done, e.g. to cancel it or set a timeout.
+// If progress is not nil, it is called every second with the number of keys tried.
+// Each character of the prefix multiplies the expected search time by 58, see VanityAttempts.
+func SearchVanityAddress(ctx context.Context, prefix string, workers int, progress VanityProgressFunc) (Address, SecKey, error) {
+	if err := validateVanityPrefix(prefix); err != nil {
+		return Address{}, SecKey{}, err
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var attempts uint64
+	found := make(chan SecKey, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				pk, sk := GenerateKeyPair()
+				atomic.AddUint64(&attempts, 1)
+
+				if strings.HasPrefix(AddressFromPubKey(pk).String(), prefix) {
+					found <- sk
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(vanityProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sk := <-found:
+			cancel()
+			wg.Wait()
+			return MustAddressFromSecKey(sk), sk, nil
+		case <-ctx.Done():
+			wg.Wait()
+			return Address{}, SecKey{}, ctx.Err()
+		case <-ticker.C:
+			if progress != nil {
+				progress(atomic.LoadUint64(&attempts))
+			}
+		}
+	}
+}
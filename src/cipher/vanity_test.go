@@ -0,0 +1,68 @@
+package cipher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchVanityAddress(t *testing.T) {
+	for _, prefix := range []string{"2", "a", "Z"} {
+		addr, sk, err := SearchVanityAddress(context.Background(), prefix, 2, nil)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(addr.String(), prefix))
+		require.Equal(t, MustAddressFromSecKey(sk), addr)
+	}
+}
+
+func TestSearchVanityAddressInvalidPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	_, _, err := SearchVanityAddress(ctx, "", 1, nil)
+	require.Equal(t, ErrEmptyVanityPrefix, err)
+
+	_, _, err = SearchVanityAddress(ctx, "sky0", 1, nil)
+	require.Equal(t, errors.New(`Vanity address prefix has the invalid base58 character '0'`), err)
+
+	_, _, err = SearchVanityAddress(ctx, "skyl", 1, nil)
+	require.Equal(t, errors.New(`Vanity address prefix has the invalid base58 character 'l'`), err)
+
+	_, _, err = SearchVanityAddress(ctx, strings.Repeat("a", 35), 1, nil)
+	require.Equal(t, ErrVanityPrefixTooLong, err)
+}
+
+func TestSearchVanityAddressCancel(t *testing.T) {
+	interval := vanityProgressInterval
+	vanityProgressInterval = 10 * time.Millisecond
+	defer func() {
+		vanityProgressInterval = interval
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var reports []uint64
+	_, _, err := SearchVanityAddress(ctx, "zzzzzzzzzz", 0, func(attempts uint64) {
+		reports = append(reports, attempts)
+	})
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	require.NotEmpty(t, reports)
+	for i := 1; i < len(reports); i++ {
+		require.True(t, reports[i] >= reports[i-1])
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, _, err = SearchVanityAddress(ctx, "zzzzzzzzzz", 1, nil)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestVanityAttempts(t *testing.T) {
+	require.Equal(t, float64(58), VanityAttempts("a"))
+	require.Equal(t, float64(58*58*58), VanityAttempts("sky"))
+}
@@ -46,8 +46,7 @@ func addPrivateKeyCmd() *cobra.Command {
 
 			switch err.(type) {
 			case nil:
-				fmt.Println("success")
-				return nil
+				return printSuccess(c, "success")
 			case WalletLoadError:
 				printHelp(c)
 				return err
@@ -79,7 +78,7 @@ func AddPrivateKey(wlt *collection.Wallet, key string) error {
 	entry := wallet.Entry{
 		Address: addr,
 		Public:  pk,
-		Secret:  sk,
+		Secret:  wallet.NewSecretKey(sk),
 	}
 
 	return wlt.AddEntry(entry)
@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+const (
+	addressBookFilename = "cli_addressbook.json"
+	// addressBookVersion is the version of the address book file format
+	addressBookVersion = 1
+)
+
+// addressBookCryptoType is the crypto type of the created address books,
+// the address books are loaded with the crypto type recorded in the file
+var addressBookCryptoType = crypto.DefaultCryptoType
+
+var (
+	// ErrAddressBookPassword is returned if the address book can't be decrypted with the password
+	ErrAddressBookPassword = errors.New("invalid address book password")
+	// ErrAddressBookVersion is returned when loading an address book of an unsupported version
+	ErrAddressBookVersion = errors.New("unsupported address book version")
+)
+
+// Contact is a named address of the address book
+type Contact struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// AddressBook is the list of contacts, sorted by name
+type AddressBook struct {
+	Contacts []Contact `json:"contacts"`
+}
+
+// Add adds a contact. The name can't be an address, so that names and addresses are never ambiguous.
+func (b *AddressBook) Add(name, addr string) error {
+	if name == "" || strings.TrimSpace(name) != name {
+		return fmt.Errorf("invalid contact name %q", name)
+	}
+	if _, err := cipher.DecodeBase58Address(name); err == nil {
+		return fmt.Errorf("invalid contact name %s, it is an address", name)
+	}
+	if _, err := cipher.DecodeBase58Address(addr); err != nil {
+		return fmt.Errorf("invalid address %s: %v", addr, err)
+	}
+
+	if _, ok := b.find(name); ok {
+		return fmt.Errorf("contact %q already exists", name)
+	}
+
+	b.Contacts = append(b.Contacts, Contact{
+		Name:    name,
+		Address: addr,
+	})
+	sort.Slice(b.Contacts, func(i, j int) bool {
+		return b.Contacts[i].Name < b.Contacts[j].Name
+	})
+
+	return nil
+}
+
+// Remove removes the contact with the name
+func (b *AddressBook) Remove(name string) error {
+	i, ok := b.find(name)
+	if !ok {
+		return fmt.Errorf("contact %q not found", name)
+	}
+
+	b.Contacts = append(b.Contacts[:i], b.Contacts[i+1:]...)
+	return nil
+}
+
+// Resolve returns the address of the contact with the name
+func (b *AddressBook) Resolve(name string) (string, error) {
+	i, ok := b.find(name)
+	if !ok {
+		return "", fmt.Errorf("contact %q not found", name)
+	}
+
+	return b.Contacts[i].Address, nil
+}
+
+func (b *AddressBook) find(name string) (int, bool) {
+	for i, c := range b.Contacts {
+		if c.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// addressBookFile is the saved address book, encrypted with the address book password
+type addressBookFile struct {
+	Version    int               `json:"version"`
+	CryptoType crypto.CryptoType `json:"crypto_type"`
+	Data       []byte            `json:"data"`
+}
+
+func addressBookPath() string {
+	return filepath.Join(cliConfig.DataDir, addressBookFilename)
+}
+
+// loadAddressBook loads and decrypts the address book, an address book that does not exist is empty
+func loadAddressBook(path string, password []byte) (*AddressBook, error) {
+	var f addressBookFile
+	if err := file.LoadJSON(path, &f); err != nil {
+		if os.IsNotExist(err) {
+			return &AddressBook{}, nil
+		}
+		return nil, fmt.Errorf("load address book failed: %v", err)
+	}
+
+	if f.Version != addressBookVersion {
+		return nil, ErrAddressBookVersion
+	}
+
+	cryptor, err := crypto.GetCrypto(f.CryptoType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := cryptor.Decrypt(f.Data, password)
+	if err != nil {
+		return nil, ErrAddressBookPassword
+	}
+
+	var b AddressBook
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid address book: %v", err)
+	}
+	return &b, nil
+}
+
+// saveAddressBook encrypts the address book with the password and saves it
+func saveAddressBook(path string, b *AddressBook, password []byte) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	cryptor, err := crypto.GetCrypto(addressBookCryptoType)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := cryptor.Encrypt(data, password)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return file.SaveJSON(path, addressBookFile{
+		Version:    addressBookVersion,
+		CryptoType: addressBookCryptoType,
+		Data:       encrypted,
+	}, 0600)
+}
+
+// addressBookPassword returns the address book password of the ADDRESS_BOOK_PASSWORD environment variable,
+// if it is not set the password is read from the terminal
+func addressBookPassword() ([]byte, error) {
+	if p := os.Getenv("ADDRESS_BOOK_PASSWORD"); p != "" {
+		return []byte(p), nil
+	}
+
+	fmt.Fprint(os.Stdout, "enter address book password:")
+	p, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, "")
+
+	if len(p) == 0 {
+		return nil, errors.New("missing address book password")
+	}
+	return p, nil
+}
+
+// contactResolver resolves the contact names used in place of addresses.
+// The address book is loaded the first time a name is resolved.
+type contactResolver struct {
+	path     string
+	password func() ([]byte, error)
+	book     *AddressBook
+}
+
+func newContactResolver() *contactResolver {
+	return &contactResolver{
+		path:     addressBookPath(),
+		password: addressBookPassword,
+	}
+}
+
+// resolve returns the address of the contact named s. An address, or any value if there is no address book,
+// is returned unchanged to be validated by the caller.
+func (r *contactResolver) resolve(s string) (string, error) {
+	if _, err := cipher.DecodeBase58Address(s); err == nil {
+		return s, nil
+	}
+
+	if r.book == nil {
+		exists, err := file.Exists(r.path)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return s, nil
+		}
+
+		password, err := r.password()
+		if err != nil {
+			return "", err
+		}
+
+		r.book, err = loadAddressBook(r.path, password)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	addr, err := r.book.Resolve(s)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither an address nor a contact of the address book", s)
+	}
+	return addr, nil
+}
+
+func addressBookCmd() *cobra.Command {
+	addressBookCmd := &cobra.Command{
+		Short: "Manage the contacts of the address book",
+		Use:   "addressbook",
+		Long: `Manage the contacts of the address book. A contact is a name for an address,
+    the contact names can be used in place of the recipient addresses of the send commands.
+
+    The address book is saved encrypted in $DATA_DIR/cli_addressbook.json, the password
+    is read from the ADDRESS_BOOK_PASSWORD environment variable or prompted.
+    The password of a new address book is the password used to add its first contact.`,
+		Args: cobra.NoArgs,
+	}
+
+	addressBookCmd.AddCommand(
+		addressBookAddCmd(),
+		addressBookListCmd(),
+		addressBookRemoveCmd(),
+		addressBookResolveCmd(),
+	)
+
+	return addressBookCmd
+}
+
+func addressBookAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(2),
+		Short:        "Add a contact to the address book",
+		Use:          "add [name] [address]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := updateAddressBook(func(b *AddressBook) error {
+				return b.Add(args[0], args[1])
+			}); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+func addressBookRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Short:        "Remove a contact from the address book",
+		Use:          "remove [name]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := updateAddressBook(func(b *AddressBook) error {
+				return b.Remove(args[0])
+			}); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+func addressBookListCmd() *cobra.Command {
+	addressBookListCmd := &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "List the contacts of the address book",
+		Use:          "list",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			b, err := openAddressBook()
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(b)
+			}
+
+			return printContacts(os.Stdout, b.Contacts)
+		},
+	}
+
+	addressBookListCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return addressBookListCmd
+}
+
+func addressBookResolveCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Short:        "Print the address of a contact",
+		Use:          "resolve [name]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			b, err := openAddressBook()
+			if err != nil {
+				return err
+			}
+
+			addr, err := b.Resolve(args[0])
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(Contact{
+					Name:    args[0],
+					Address: addr,
+				})
+			}
+
+			fmt.Println(addr)
+			return nil
+		},
+	}
+}
+
+// openAddressBook loads the address book, the password is not read if there is no address book
+func openAddressBook() (*AddressBook, error) {
+	path := addressBookPath()
+	exists, err := file.Exists(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return &AddressBook{}, nil
+	}
+
+	password, err := addressBookPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadAddressBook(path, password)
+}
+
+// updateAddressBook loads the address book, updates it with update and saves it
+func updateAddressBook(update func(b *AddressBook) error) error {
+	password, err := addressBookPassword()
+	if err != nil {
+		return err
+	}
+
+	path := addressBookPath()
+	b, err := loadAddressBook(path, password)
+	if err != nil {
+		return err
+	}
+
+	if err := update(b); err != nil {
+		return err
+	}
+
+	return saveAddressBook(path, b, password)
+}
+
+func printContacts(w io.Writer, contacts []Contact) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range contacts {
+		fmt.Fprintf(tw, "%s\t%s\n", c.Name, c.Address)
+	}
+	return tw.Flush()
+}
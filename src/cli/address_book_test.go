@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+func init() {
+	// Speed up the tests
+	addressBookCryptoType = crypto.CryptoTypeScryptChacha20poly1305Insecure
+}
+
+// newTestContactResolver returns a contactResolver of a temporary address book with the contacts
+func newTestContactResolver(t *testing.T, contacts ...Contact) (*contactResolver, func()) {
+	dir, err := ioutil.TempDir("", "cli-addressbook")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, addressBookFilename)
+	require.NoError(t, saveAddressBook(path, &AddressBook{Contacts: contacts}, []byte("pwd")))
+
+	r := &contactResolver{
+		path: path,
+		password: func() ([]byte, error) {
+			return []byte("pwd"), nil
+		},
+	}
+
+	return r, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAddressBook(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+
+	var b AddressBook
+	require.NoError(t, b.Add("carol", addr1))
+	require.NoError(t, b.Add("alice", addr2))
+	require.NoError(t, b.Add("bob", addr1))
+
+	require.EqualError(t, b.Add("alice", addr1), `contact "alice" already exists`)
+	require.EqualError(t, b.Add("", addr1), `invalid contact name ""`)
+	require.EqualError(t, b.Add(" dave", addr1), `invalid contact name " dave"`)
+	require.EqualError(t, b.Add(addr2, addr1), "invalid contact name "+addr2+", it is an address")
+	require.EqualError(t, b.Add("dave", "foo"), "invalid address foo: Invalid address length")
+
+	require.Equal(t, []Contact{
+		{Name: "alice", Address: addr2},
+		{Name: "bob", Address: addr1},
+		{Name: "carol", Address: addr1},
+	}, b.Contacts)
+
+	addr, err := b.Resolve("alice")
+	require.NoError(t, err)
+	require.Equal(t, addr2, addr)
+
+	require.NoError(t, b.Remove("bob"))
+	require.EqualError(t, b.Remove("bob"), `contact "bob" not found`)
+	_, err = b.Resolve("bob")
+	require.EqualError(t, err, `contact "bob" not found`)
+
+	var out bytes.Buffer
+	require.NoError(t, printContacts(&out, b.Contacts))
+	require.Equal(t, "alice  "+addr2+"\ncarol  "+addr1+"\n", out.String())
+}
+
+func TestLoadSaveAddressBook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-addressbook")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data", addressBookFilename)
+
+	// An address book that does not exist is empty
+	b, err := loadAddressBook(path, []byte("pwd"))
+	require.NoError(t, err)
+	require.Empty(t, b.Contacts)
+
+	addr := testutil.MakeAddress().String()
+	require.NoError(t, b.Add("alice", addr))
+	require.NoError(t, saveAddressBook(path, b, []byte("pwd")))
+
+	// The contacts are encrypted
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "alice")
+	require.NotContains(t, string(data), addr)
+
+	loaded, err := loadAddressBook(path, []byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, b, loaded)
+
+	_, err = loadAddressBook(path, []byte("wrong"))
+	require.Equal(t, ErrAddressBookPassword, err)
+}
+
+func TestContactResolver(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+
+	r, cleanup := newTestContactResolver(t, Contact{Name: "alice", Address: addr1})
+	defer cleanup()
+
+	var prompts int
+	r.password = func() ([]byte, error) {
+		prompts++
+		return []byte("pwd"), nil
+	}
+
+	// Addresses are not resolved, the address book is not loaded
+	addr, err := r.resolve(addr2)
+	require.NoError(t, err)
+	require.Equal(t, addr2, addr)
+	require.Equal(t, 0, prompts)
+
+	addr, err = r.resolve("alice")
+	require.NoError(t, err)
+	require.Equal(t, addr1, addr)
+
+	_, err = r.resolve("bob")
+	require.EqualError(t, err, "bob is neither an address nor a contact of the address book")
+
+	// The address book is loaded once
+	require.Equal(t, 1, prompts)
+
+	// Without an address book the values are not resolved
+	r = &contactResolver{
+		path: filepath.Join(filepath.Dir(r.path), "missing.json"),
+		password: func() ([]byte, error) {
+			return nil, errors.New("no password")
+		},
+	}
+	addr, err = r.resolve("alice")
+	require.NoError(t, err)
+	require.Equal(t, "alice", addr)
+}
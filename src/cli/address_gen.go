@@ -42,6 +42,11 @@ func addressGenCmd() *cobra.Command {
 				return err
 			}
 
+			addressEncoding, err := c.Flags().GetString("address-encoding")
+			if err != nil {
+				return err
+			}
+
 			encrypt, err := c.Flags().GetBool("encrypt")
 			if err != nil {
 				return err
@@ -52,6 +57,18 @@ func addressGenCmd() *cobra.Command {
 				return nil
 			}
 
+			// The wallet mode is the only JSON output
+			if jsonOutput, _ := c.Flags().GetBool("json"); jsonOutput {
+				if c.Flags().Changed("mode") {
+					switch strings.ToLower(mode) {
+					case "json", "wallet":
+					default:
+						return ValidationError{errors.New("--json requires -mode to be wallet")}
+					}
+				}
+				mode = "wallet"
+			}
+
 			label, err := c.Flags().GetString("label")
 			if err != nil {
 				return nil
@@ -83,12 +100,13 @@ func addressGenCmd() *cobra.Command {
 			}
 
 			w, err := wallet.NewWallet(wallet.NewWalletFilename(), label, seed, wallet.Options{
-				Coin:       coinType,
-				Encrypt:    encrypt,
-				Password:   password,
-				CryptoType: crypto.DefaultCryptoType,
-				GenerateN:  uint64(numAddresses),
-				Type:       wallet.WalletTypeDeterministic,
+				Coin:            coinType,
+				AddressEncoding: wallet.AddressEncoding(addressEncoding),
+				Encrypt:         encrypt,
+				Password:        password,
+				CryptoType:      crypto.DefaultCryptoType,
+				GenerateN:       uint64(numAddresses),
+				Type:            wallet.WalletTypeDeterministic,
 			})
 			if err != nil {
 				return err
@@ -125,13 +143,9 @@ func addressGenCmd() *cobra.Command {
 					return err
 				}
 
+				d := wallet.ResolveSecKeyDecoder(coinType)
 				for _, e := range es {
-					switch coinType {
-					case wallet.CoinTypeSkycoin:
-						fmt.Println(e.Secret.Hex())
-					case wallet.CoinTypeBitcoin:
-						fmt.Println(cipher.BitcoinWalletImportFormatFromSeckey(e.Secret))
-					}
+					fmt.Println(d.SecKeyToHex(e.Secret.SecKey()))
 				}
 			default:
 				return errors.New("invalid mode")
@@ -142,7 +156,8 @@ func addressGenCmd() *cobra.Command {
 	}
 
 	addressGenCmd.Flags().IntP("num", "n", 1, "Number of addresses to generate")
-	addressGenCmd.Flags().StringP("coin", "c", "skycoin", "Coin type. Must be skycoin or bitcoin. If bitcoin, secret keys are in Wallet Import Format instead of hex.")
+	addressGenCmd.Flags().StringP("coin", "c", "skycoin", "Coin type. Must be skycoin, bitcoin or ethereum. If bitcoin, secret keys are in Wallet Import Format instead of hex.")
+	addressGenCmd.Flags().String("address-encoding", "", "Address encoding. Bitcoin addresses can be base58 (legacy P2PKH, the default) or bech32 (native segwit P2WPKH).")
 	addressGenCmd.Flags().StringP("label", "l", "", "Wallet label to use when printing or writing a wallet file")
 	addressGenCmd.Flags().Bool("hex", false, "Use hex(sha256sum(rand(1024))) (CSPRNG-generated) as the seed if not seed is not provided")
 	addressGenCmd.Flags().StringP("seed", "s", "", "Seed for deterministic key generation. Will use bip39 as the seed if not provided.")
@@ -324,7 +339,11 @@ func fiberAddressGenCmd() *cobra.Command {
 				return err
 			}
 
-			return seedsF.Sync()
+			if err := seedsF.Sync(); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
 		},
 	}
 
@@ -13,7 +13,7 @@ func broadcastTxCmd() *cobra.Command {
 		Args:                  cobra.ExactArgs(1),
 		DisableFlagsInUseLine: true,
 		SilenceUsage:          true,
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
 			rawtx := args[0]
 
 			txid, err := apiClient.InjectEncodedTransaction(rawtx)
@@ -21,9 +21,26 @@ func broadcastTxCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println(txid)
-			return nil
+			return printTxid(c, txid)
 		},
 	}
+}
+
+// printTxid prints the id of a transaction sent to the network, as {"txid": txid} if the command was run with --json
+func printTxid(c *cobra.Command, txid string) error {
+	jsonOutput, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			Txid string `json:"txid"`
+		}{
+			Txid: txid,
+		})
+	}
 
+	fmt.Println(txid)
+	return nil
 }
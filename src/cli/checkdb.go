@@ -42,7 +42,7 @@ func checkDBCmd() *cobra.Command {
 	}
 }
 
-func checkDB(_ *cobra.Command, args []string) error {
+func checkDB(c *cobra.Command, args []string) error {
 	// get db path
 	dbPath := ""
 	if len(args) > 0 {
@@ -82,8 +82,7 @@ func checkDB(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("checkdb failed: %v", err)
 	}
 
-	fmt.Println("check db success")
-	return nil
+	return printSuccess(c, "check db success")
 }
 
 func checkDBEncodingCmd() *cobra.Command {
@@ -99,7 +98,7 @@ func checkDBEncodingCmd() *cobra.Command {
 	}
 }
 
-func checkDBDecoding(_ *cobra.Command, args []string) error {
+func checkDBDecoding(c *cobra.Command, args []string) error {
 	// get db path
 	dbPath := ""
 	if len(args) > 0 {
@@ -135,7 +134,6 @@ func checkDBDecoding(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("checkDBDecoding failed: %v", err)
 	}
 
-	fmt.Println("check db decoding success")
-	return nil
+	return printSuccess(c, "check db decoding success")
 
 }
@@ -44,7 +44,9 @@ var (
     RPC_USER: Username for RPC API, if enabled in the RPC.
     RPC_PASS: Password for RPC API, if enabled in the RPC.
     COIN: Name of the coin. Default "%s"
-    DATA_DIR: Directory where everything is stored. Default "%s"`, defaultRPCAddress, defaultCoin, defaultDataDir)
+    DATA_DIR: Directory where everything is stored. Default "%s"
+    ADDRESS_BOOK_PASSWORD: Password of the address book, prompted if not set.
+    PROFILES_PASSWORD: Password of the encrypted profiles file, prompted if not set.`, defaultRPCAddress, defaultCoin, defaultDataDir)
 
 	helpTemplate = fmt.Sprintf(`USAGE:{{if .Runnable}}
   {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
@@ -94,6 +96,12 @@ type Config struct {
 	RPCAddress  string `json:"rpc_address"`
 	RPCUsername string `json:"-"`
 	RPCPassword string `json:"-"`
+	// Profile is the name of the profile selected with --profile
+	Profile string `json:"profile,omitempty"`
+	// Wallet is the default wallet of the commands with a [wallet] argument, set by the profile
+	Wallet string `json:"wallet,omitempty"`
+	// ProfilesPath is the path of the profiles file, in the data directory of the environment
+	ProfilesPath string `json:"-"`
 }
 
 // LoadConfig loads config from environment, prior to parsing CLI flags
@@ -133,11 +141,12 @@ func LoadConfig() (Config, error) {
 	}
 
 	return Config{
-		DataDir:     dataDir,
-		Coin:        coin,
-		RPCAddress:  rpcAddr,
-		RPCUsername: rpcUser,
-		RPCPassword: rpcPass,
+		DataDir:      dataDir,
+		Coin:         coin,
+		RPCAddress:   rpcAddr,
+		RPCUsername:  rpcUser,
+		RPCPassword:  rpcPass,
+		ProfilesPath: filepath.Join(dataDir, profilesFilename),
 	}, nil
 }
 
@@ -165,11 +174,47 @@ func resolveDBPath(cfg Config, db string) (string, error) {
 
 // NewCLI creates a cli instance
 func NewCLI(cfg Config) (*cobra.Command, error) {
+	// The profile is selected before parsing the flags, to configure the commands
+	profile, args, err := profileFromArgs(os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if profile != "" {
+		cfg, err = loadProfileConfig(cfg, profile, profilesPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	setConfig(cfg)
+
+	skyCLI := newRootCmd(cfg)
+	skyCLI.AddCommand(shellCmd(), completionCmd())
+	skyCLI.PersistentFlags().String("profile", "", "Name of the configuration profile to use, see the profile command")
+	setValidationErrors(skyCLI)
+	// The errors are printed by PrintError
+	skyCLI.SilenceErrors = true
+
+	if profile != "" {
+		skyCLI.SetArgs(withDefaultWallet(skyCLI, args, cfg.Wallet))
+	}
+
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+
+	return skyCLI, nil
+}
+
+// setConfig sets the configuration and the api client used by the commands
+func setConfig(cfg Config) {
 	apiClient = api.NewClient(cfg.RPCAddress)
 	apiClient.SetAuth(cfg.RPCUsername, cfg.RPCPassword)
 
 	cliConfig = cfg
+}
 
+// newRootCmd creates the root command with all the commands, except for the shell
+func newRootCmd(cfg Config) *cobra.Command {
 	skyCLI := &cobra.Command{
 		Short: fmt.Sprintf("The %s command line interface", cfg.Coin),
 		Use:   fmt.Sprintf("%s-cli", cfg.Coin),
@@ -181,13 +226,18 @@ func NewCLI(cfg Config) (*cobra.Command, error) {
 		addressGenCmd(),
 		fiberAddressGenCmd(),
 		addressOutputsCmd(),
+		addressBookCmd(),
 		blocksCmd(),
 		broadcastTxCmd(),
+		broadcastSignedCmd(),
 		checkDBCmd(),
 		checkDBEncodingCmd(),
+		consolidateCmd(),
 		createRawTxnCmd(),
 		createRawTxnV2Cmd(),
+		createUnsignedTxnCmd(),
 		signTxnCmd(),
+		signTxnOfflineCmd(),
 		decodeRawTxnCmd(),
 		encodeJSONTxnCmd(),
 		decryptWalletCmd(),
@@ -195,24 +245,44 @@ func NewCLI(cfg Config) (*cobra.Command, error) {
 		lastBlocksCmd(),
 		listAddressesCmd(),
 		listWalletsCmd(),
+		multisigCreateCmd(),
+		multisigProposeCmd(),
+		multisigSignCmd(),
+		multisigCombineCmd(),
+		nodeCmd(),
+		scheduleCmd(),
 		sendCmd(),
+		sendFromCSVCmd(),
+		sendManyCmd(),
 		showConfigCmd(),
+		paperWalletCmd(),
 		showSeedCmd(),
+		signMessageCmd(),
+		sweepCmd(),
 		statusCmd(),
 		transactionCmd(),
 		verifyTransactionCmd(),
+		vanityAddressCmd(),
 		verifyAddressCmd(),
+		verifyChainCmd(),
+		verifyMessageCmd(),
 		versionCmd(),
 		walletCreateCmd(),
+		walletCreateWatchCmd(),
 		walletAddAddressesCmd(),
 		walletScanAddressesCmd(),
+		walletAuditCmd(),
 		walletKeyExportCmd(),
+		walletLabelsExportCmd(),
+		walletLabelsImportCmd(),
 		walletBalanceCmd(),
 		walletHisCmd(),
+		historyCmd(),
 		walletOutputsCmd(),
 		richlistCmd(),
 		addressTransactionsCmd(),
 		pendingTransactionsCmd(),
+		profileCmd(),
 		addresscountCmd(),
 		distributeGenesisCmd(),
 	}
@@ -221,12 +291,13 @@ func NewCLI(cfg Config) (*cobra.Command, error) {
 	skyCLI.SuggestionsMinimumDistance = 1
 	skyCLI.SilenceUsage = true
 	skyCLI.AddCommand(commands...)
+	skyCLI.PersistentFlags().BoolP("json", "j", false, "Returns the results in JSON format.")
+	setWalletCompletions(skyCLI)
 
 	skyCLI.SetHelpTemplate(helpTemplate)
 	skyCLI.SetUsageTemplate(helpTemplate)
-	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
-	return skyCLI, nil
+	return skyCLI
 }
 
 func printHelp(c *cobra.Command) {
@@ -252,6 +323,28 @@ func printJSON(obj interface{}) error {
 	return nil
 }
 
+// printSuccess prints the text of a command without a result, if it is not empty.
+// {"success": true} is printed instead if the command was run with --json.
+func printSuccess(c *cobra.Command, text string) error {
+	jsonOutput, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			Success bool `json:"success"`
+		}{
+			Success: true,
+		})
+	}
+
+	if text != "" {
+		fmt.Println(text)
+	}
+	return nil
+}
+
 // readPasswordFromTerminal promotes user to enter password and read it.
 func readPasswordFromTerminal() ([]byte, error) {
 	// Promotes to enter the wallet password
@@ -24,9 +24,9 @@ func Example() {
 		os.Exit(1)
 	}
 
-	if err := cli.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if c, err := cli.ExecuteC(); err != nil {
+		PrintError(c, err)
+		os.Exit(ExitCode(err))
 	}
 }
 
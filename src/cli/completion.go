@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+)
+
+// completionShells are the shells of the completion command
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// walletsClient is the API client used to complete the wallet names and addresses
+type walletsClient interface {
+	Wallets() ([]api.WalletResponse, error)
+}
+
+func completionCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Generate the shell completion script",
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Long: fmt.Sprintf(`Generate the completion script of the commands for bash, zsh, fish or powershell.
+
+    The commands and flags are completed, and the wallet names and addresses are completed
+    from the wallets of the node.
+
+    To load the completions in the current bash session:
+      $ source <(%[1]s-cli completion bash)
+
+    To load the completions for every new session:
+      bash:       $ %[1]s-cli completion bash > /etc/bash_completion.d/%[1]s-cli
+      zsh:        $ %[1]s-cli completion zsh > "${fpath[1]}/_%[1]s-cli"
+      fish:       $ %[1]s-cli completion fish > ~/.config/fish/completions/%[1]s-cli.fish
+      powershell: PS> %[1]s-cli completion powershell >> $PROFILE`, cliConfig.Coin),
+		Args:                  cobra.ExactValidArgs(1),
+		ValidArgs:             completionShells,
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(c *cobra.Command, args []string) error {
+			root := c.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(os.Stdout)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return root.GenPowerShellCompletion(os.Stdout)
+			}
+		},
+	}
+}
+
+// setWalletCompletions completes the wallet names of the commands with a [wallet] argument,
+// and the addresses of the wallet of the commands with a [wallet] [address] argument
+func setWalletCompletions(root *cobra.Command) {
+	for _, c := range root.Commands() {
+		if c.ValidArgsFunction != nil {
+			continue
+		}
+
+		args := strings.Fields(c.Use)[1:]
+		if len(args) == 0 || !strings.HasPrefix(args[0], "[wallet") {
+			continue
+		}
+
+		completeAddresses := len(args) > 1 && args[1] == "[address]"
+		c.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch {
+			case len(args) == 0:
+				return completeWalletNames(apiClient, toComplete), cobra.ShellCompDirectiveNoFileComp
+			case len(args) == 1 && completeAddresses:
+				return completeWalletAddresses(apiClient, args[0], toComplete), cobra.ShellCompDirectiveNoFileComp
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+	}
+}
+
+// completeWalletNames returns the names of the wallets of the node starting with toComplete.
+// Nothing is completed if the node is unavailable.
+func completeWalletNames(c walletsClient, toComplete string) []string {
+	wlts, err := c.Wallets()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, w := range wlts {
+		if strings.HasPrefix(w.Meta.Filename, toComplete) {
+			names = append(names, w.Meta.Filename)
+		}
+	}
+	return names
+}
+
+// completeWalletAddresses returns the addresses of the wallet starting with toComplete.
+// Nothing is completed if the node is unavailable.
+func completeWalletAddresses(c walletsClient, wltID, toComplete string) []string {
+	wlts, err := c.Wallets()
+	if err != nil {
+		return nil
+	}
+
+	var addrs []string
+	for _, w := range wlts {
+		if w.Meta.Filename != wltID {
+			continue
+		}
+
+		for _, e := range w.Entries {
+			if strings.HasPrefix(e.Address, toComplete) {
+				addrs = append(addrs, e.Address)
+			}
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/readable"
+)
+
+type fakeWalletsClient struct {
+	wallets []api.WalletResponse
+	err     error
+}
+
+func (c fakeWalletsClient) Wallets() ([]api.WalletResponse, error) {
+	return c.wallets, c.err
+}
+
+func TestCompleteWallets(t *testing.T) {
+	var foo, bar api.WalletResponse
+	foo.Meta.Filename = "foo.wlt"
+	foo.Entries = []readable.WalletEntry{
+		{Address: "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"},
+		{Address: "2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH"},
+	}
+	bar.Meta.Filename = "bar.wlt"
+	bar.Entries = []readable.WalletEntry{
+		{Address: "2mEgmYt6NZHA1erYqbAeXmGPD5gqLZ9toFv"},
+	}
+
+	c := fakeWalletsClient{wallets: []api.WalletResponse{foo, bar}}
+
+	require.Equal(t, []string{"foo.wlt", "bar.wlt"}, completeWalletNames(c, ""))
+	require.Equal(t, []string{"bar.wlt"}, completeWalletNames(c, "b"))
+	require.Empty(t, completeWalletNames(c, "x"))
+
+	require.Equal(t, []string{"2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv", "2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH"}, completeWalletAddresses(c, "foo.wlt", ""))
+	require.Equal(t, []string{"2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH"}, completeWalletAddresses(c, "foo.wlt", "2U"))
+	require.Empty(t, completeWalletAddresses(c, "bar.wlt", "2U"))
+	require.Empty(t, completeWalletAddresses(c, "baz.wlt", ""))
+
+	// The node is unavailable
+	c.err = errors.New("connection refused")
+	require.Empty(t, completeWalletNames(c, ""))
+	require.Empty(t, completeWalletAddresses(c, "foo.wlt", ""))
+}
+
+func TestSetWalletCompletions(t *testing.T) {
+	root := newRootCmd(Config{Coin: "skycoin"})
+
+	for _, name := range []string{"listAddresses", "send", "history", "signMessage", "walletAudit"} {
+		c, _, err := root.Find([]string{name})
+		require.NoError(t, err)
+		require.NotNil(t, c.ValidArgsFunction, name)
+	}
+
+	for _, name := range []string{"version", "status", "verifyAddress"} {
+		c, _, err := root.Find([]string{name})
+		require.NoError(t, err)
+		require.Nil(t, c.ValidArgsFunction, name)
+	}
+}
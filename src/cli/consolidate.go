@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// transactionInputSize is the encoded size of a signed transaction input: the output hash (32 bytes) and the signature (65 bytes)
+	transactionInputSize = 97
+	// transactionOverheadSize is the encoded size of a transaction without inputs and outputs:
+	// the length (4 bytes), type (1 byte), inner hash (32 bytes) and the lengths of the signatures, inputs and outputs (4 bytes each)
+	transactionOverheadSize = 49
+)
+
+// ConsolidationSummary is printed by the consolidate command before sending the consolidation transaction
+type ConsolidationSummary struct {
+	Txid   string `json:"txid"`
+	To     string `json:"to"`
+	Inputs int    `json:"inputs"`
+	Coins  string `json:"coins"`
+	// InputHours are the coin hours of the consolidated outputs, BurnedHours the fee and OutputHours the hours sent to the address
+	InputHours  uint64 `json:"input_hours"`
+	BurnedHours string `json:"burned_hours"`
+	OutputHours string `json:"output_hours"`
+	Size        uint32 `json:"size"`
+	// Remaining is the number of outputs below the threshold that are not consolidated by the transaction
+	Remaining int `json:"remaining"`
+	// Sent is true if the transaction was sent to the network
+	Sent bool `json:"sent"`
+}
+
+// consolidateClient is the API client used by the consolidate command
+type consolidateClient interface {
+	GetOutputser
+	paymentsClient
+}
+
+func consolidateCmd() *cobra.Command {
+	consolidateCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Consolidate the small outputs of a wallet in a single output",
+		Use:   "consolidate [wallet]",
+		Long: fmt.Sprintf(`Consolidate the spendable outputs of a wallet with fewer coins than the --threshold
+    in a single output of the --to address, to keep the wallet from accumulating many small outputs.
+    The --to address defaults to the first address of the wallet.
+
+    The smallest outputs are consolidated first, at most --max-inputs outputs in a transaction.
+    The default maximum is %d outputs, which fit in the maximum transaction size.
+    Run the command again to consolidate the remaining outputs.
+
+    The summary of the transaction, with the coin hours burned as fee, is shown and the
+    transaction is sent after confirmation. All the remaining coin hours are sent to the --to address.
+    With --dry-run the details of the transaction are printed instead and it is neither signed nor sent.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, defaultConsolidateMaxInputs()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			yes, err := c.Flags().GetBool("yes")
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput && !yes && !dryRun {
+				return errors.New("--json requires --yes or --dry-run")
+			}
+
+			thresholdStr, err := c.Flags().GetString("threshold")
+			if err != nil {
+				return err
+			}
+			threshold, err := droplet.FromString(thresholdStr)
+			if err != nil {
+				return fmt.Errorf("invalid --threshold: %v", err)
+			}
+			if threshold == 0 {
+				return errors.New("--threshold must be positive")
+			}
+
+			maxInputs, err := c.Flags().GetInt("max-inputs")
+			if err != nil {
+				return err
+			}
+			if maxInputs < 2 {
+				return errors.New("--max-inputs must be at least 2")
+			}
+
+			to, err := c.Flags().GetString("to")
+			if err != nil {
+				return err
+			}
+			if to != "" {
+				if _, err := cipher.DecodeBase58Address(to); err != nil {
+					return fmt.Errorf("invalid --to address: %v", err)
+				}
+			}
+
+			wlt, err := apiClient.Wallet(args[0])
+			if err != nil {
+				return err
+			}
+
+			var addrs []string
+			for _, e := range wlt.Entries {
+				addrs = append(addrs, e.Address)
+			}
+			if len(addrs) == 0 {
+				return errors.New("Wallet is empty")
+			}
+
+			if to == "" {
+				to = addrs[0]
+			}
+
+			req := api.WalletCreateTransactionRequest{
+				Unsigned: dryRun,
+				WalletID: wlt.Meta.Filename,
+			}
+
+			if wlt.Meta.Encrypted && !dryRun {
+				p, err := getPassword(c)
+				if err != nil {
+					return err
+				}
+				req.Password = string(p)
+			}
+
+			summary, txn, err := createConsolidationTransaction(apiClient, req, addrs, to, threshold, maxInputs)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				return printTransactionPreviews(os.Stdout, jsonOutput, newTransactionPreview(txn.Transaction, 1))
+			}
+
+			if !yes {
+				printConsolidationSummary(os.Stdout, summary)
+
+				ok, err := confirmPayments(os.Stdin, os.Stdout, 1)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("No transaction was sent")
+					return nil
+				}
+			}
+
+			if _, err := apiClient.InjectEncodedTransaction(txn.EncodedTransaction); err != nil {
+				return err
+			}
+			summary.Sent = true
+
+			if jsonOutput {
+				return printJSON(summary)
+			}
+
+			fmt.Printf("txid:%s\n", summary.Txid)
+			return nil
+		},
+	}
+
+	consolidateCmd.Flags().String("threshold", "1", "Consolidate the outputs with fewer coins than the threshold")
+	consolidateCmd.Flags().String("to", "", "Address to send the consolidated coins to, defaults to the first address of the wallet")
+	consolidateCmd.Flags().Int("max-inputs", defaultConsolidateMaxInputs(), "Maximum number of outputs to consolidate in the transaction")
+	consolidateCmd.Flags().StringP("password", "p", "", "Wallet password")
+	consolidateCmd.Flags().BoolP("yes", "y", false, "Send the transaction without confirmation")
+	consolidateCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format, requires --yes or --dry-run")
+	addDryRunFlag(consolidateCmd)
+
+	return consolidateCmd
+}
+
+// defaultConsolidateMaxInputs returns the number of inputs of a transaction with a single output that fit in the maximum transaction size
+func defaultConsolidateMaxInputs() int {
+	return int(params.UserVerifyTxn.MaxTransactionSize-transactionOverheadSize-transactionOutputSize) / transactionInputSize
+}
+
+// selectConsolidationOutputs returns the outputs with fewer coins than the threshold, smallest first,
+// and the number of these outputs not selected because of maxInputs
+func selectConsolidationOutputs(outs readable.UnspentOutputs, threshold uint64, maxInputs int) (readable.UnspentOutputs, int, error) {
+	type output struct {
+		readable.UnspentOutput
+		coins uint64
+	}
+
+	var small []output
+	for _, o := range outs {
+		coins, err := droplet.FromString(o.Coins)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if coins < threshold {
+			small = append(small, output{
+				UnspentOutput: o,
+				coins:         coins,
+			})
+		}
+	}
+
+	sort.SliceStable(small, func(i, j int) bool {
+		if small[i].coins == small[j].coins {
+			return small[i].Hash < small[j].Hash
+		}
+		return small[i].coins < small[j].coins
+	})
+
+	n := len(small)
+	if n > maxInputs {
+		n = maxInputs
+	}
+
+	selected := make(readable.UnspentOutputs, n)
+	for i := range selected {
+		selected[i] = small[i].UnspentOutput
+	}
+
+	return selected, len(small) - n, nil
+}
+
+// createConsolidationTransaction creates the transaction sending the spendable outputs of the addresses with fewer coins
+// than the threshold to the address to. A transaction that exceeds the maximum transaction size is retried with half of the outputs.
+func createConsolidationTransaction(c consolidateClient, req api.WalletCreateTransactionRequest, addrs []string, to string, threshold uint64, maxInputs int) (*ConsolidationSummary, *api.CreateTransactionResponse, error) {
+	outs, err := c.OutputsForAddresses(addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		selected, remaining, err := selectConsolidationOutputs(outs.SpendableOutputs(), threshold, maxInputs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(selected) < 2 {
+			return nil, nil, fmt.Errorf("No outputs to consolidate, the wallet has %d spendable output(s) with fewer coins than the threshold", len(selected))
+		}
+
+		summary := &ConsolidationSummary{
+			To:        to,
+			Inputs:    len(selected),
+			Remaining: remaining,
+		}
+
+		var coins uint64
+		uxOuts := make([]string, len(selected))
+		for i, o := range selected {
+			uxOuts[i] = o.Hash
+
+			oc, err := droplet.FromString(o.Coins)
+			if err != nil {
+				return nil, nil, err
+			}
+			coins, err = mathutil.AddUint64(coins, oc)
+			if err != nil {
+				return nil, nil, err
+			}
+			summary.InputHours, err = mathutil.AddUint64(summary.InputHours, o.CalculatedHours)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		summary.Coins, err = droplet.ToString(coins)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		req.CreateTransactionRequest = api.CreateTransactionRequest{
+			HoursSelection: api.HoursSelection{
+				Type:        transaction.HoursSelectionTypeAuto,
+				Mode:        transaction.HoursSelectionModeShare,
+				ShareFactor: "1",
+			},
+			UxOuts: uxOuts,
+			To: []api.Receiver{
+				{
+					Address: to,
+					Coins:   summary.Coins,
+				},
+			},
+		}
+
+		rsp, err := c.WalletCreateTransaction(req)
+		if err != nil {
+			if strings.Contains(err.Error(), visor.ErrTxnExceedsMaxBlockSize.Error()) {
+				maxInputs = len(selected) / 2
+				continue
+			}
+			return nil, nil, err
+		}
+
+		summary.Txid = rsp.Transaction.TxID
+		summary.BurnedHours = rsp.Transaction.Fee
+		summary.Size = rsp.Transaction.Length
+		summary.OutputHours = "0"
+		if len(rsp.Transaction.Out) != 0 {
+			summary.OutputHours = rsp.Transaction.Out[0].Hours
+		}
+
+		return summary, rsp, nil
+	}
+}
+
+func printConsolidationSummary(w io.Writer, summary *ConsolidationSummary) {
+	fmt.Fprintf(w, "Transaction:  %s\n", summary.Txid)
+	fmt.Fprintf(w, "Outputs:      %d\n", summary.Inputs)
+	fmt.Fprintf(w, "Coins:        %s\n", summary.Coins)
+	fmt.Fprintf(w, "To:           %s\n", summary.To)
+	fmt.Fprintf(w, "Hours:        %d\n", summary.InputHours)
+	fmt.Fprintf(w, "Hours burned: %s\n", summary.BurnedHours)
+	fmt.Fprintf(w, "Hours sent:   %s\n", summary.OutputHours)
+	fmt.Fprintf(w, "Size:         %d\n", summary.Size)
+	if summary.Remaining != 0 {
+		fmt.Fprintf(w, "%d output(s) below the threshold remain, run the command again to consolidate them\n", summary.Remaining)
+	}
+}
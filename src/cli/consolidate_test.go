@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+type fakeConsolidateClient struct {
+	outs *readable.UnspentOutputsSummary
+	// maxInputs is the number of inputs above which a transaction exceeds the maximum size
+	maxInputs int
+	requests  []api.WalletCreateTransactionRequest
+}
+
+func (c *fakeConsolidateClient) OutputsForAddresses(addrs []string) (*readable.UnspentOutputsSummary, error) {
+	return c.outs, nil
+}
+
+func (c *fakeConsolidateClient) WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error) {
+	c.requests = append(c.requests, req)
+
+	if len(req.UxOuts) > c.maxInputs {
+		return nil, errors.New("400 Bad Request - Transaction size bigger than max block size")
+	}
+
+	return &api.CreateTransactionResponse{
+		Transaction: api.CreatedTransaction{
+			TxID:   fmt.Sprintf("txid%d", len(c.requests)),
+			Length: uint32(len(req.UxOuts) * 100),
+			Fee:    "15",
+			Out: []api.CreatedTransactionOutput{
+				{Address: req.To[0].Address, Coins: req.To[0].Coins, Hours: "14"},
+			},
+		},
+		EncodedTransaction: "txn",
+	}, nil
+}
+
+func (c *fakeConsolidateClient) InjectEncodedTransaction(rawTxn string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func TestCreateConsolidationTransaction(t *testing.T) {
+	to := "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"
+
+	c := &fakeConsolidateClient{
+		outs: &readable.UnspentOutputsSummary{
+			HeadOutputs: readable.UnspentOutputs{
+				{Hash: "e", Coins: "0.5", CalculatedHours: 2},
+				{Hash: "d", Coins: "100", CalculatedHours: 1000},
+				{Hash: "c", Coins: "0.1", CalculatedHours: 5},
+				{Hash: "b", Coins: "0.2", CalculatedHours: 10},
+				{Hash: "a", Coins: "0.1", CalculatedHours: 7},
+				{Hash: "f", Coins: "0.3", CalculatedHours: 1},
+			},
+			// f is spent by an unconfirmed transaction
+			OutgoingOutputs: readable.UnspentOutputs{
+				{Hash: "f", Coins: "0.3", CalculatedHours: 1},
+			},
+		},
+		maxInputs: 3,
+	}
+
+	req := api.WalletCreateTransactionRequest{
+		WalletID: "foo.wlt",
+		Password: "pwd",
+	}
+
+	summary, txn, err := createConsolidationTransaction(c, req, []string{to}, to, 1e6, 10)
+	require.NoError(t, err)
+	require.Equal(t, "txid2", txn.Transaction.TxID)
+
+	// The 4 outputs below the threshold exceed the maximum size, the 2 smallest are consolidated
+	require.Len(t, c.requests, 2)
+	require.Equal(t, []string{"a", "c", "b", "e"}, c.requests[0].UxOuts)
+	require.Equal(t, api.WalletCreateTransactionRequest{
+		WalletID: "foo.wlt",
+		Password: "pwd",
+		CreateTransactionRequest: api.CreateTransactionRequest{
+			HoursSelection: api.HoursSelection{
+				Type:        transaction.HoursSelectionTypeAuto,
+				Mode:        transaction.HoursSelectionModeShare,
+				ShareFactor: "1",
+			},
+			UxOuts: []string{"a", "c"},
+			To: []api.Receiver{
+				{Address: to, Coins: "0.200000"},
+			},
+		},
+	}, c.requests[1])
+
+	require.Equal(t, &ConsolidationSummary{
+		Txid:        "txid2",
+		To:          to,
+		Inputs:      2,
+		Coins:       "0.200000",
+		InputHours:  12,
+		BurnedHours: "15",
+		OutputHours: "14",
+		Size:        200,
+		Remaining:   2,
+	}, summary)
+
+	var out bytes.Buffer
+	printConsolidationSummary(&out, summary)
+	require.Equal(t, `Transaction:  txid2
+Outputs:      2
+Coins:        0.200000
+To:           `+to+`
+Hours:        12
+Hours burned: 15
+Hours sent:   14
+Size:         200
+2 output(s) below the threshold remain, run the command again to consolidate them
+`, out.String())
+
+	// Nothing to consolidate
+	c.requests = nil
+	_, _, err = createConsolidationTransaction(c, req, []string{to}, to, 1e5, 10)
+	require.EqualError(t, err, "No outputs to consolidate, the wallet has 0 spendable output(s) with fewer coins than the threshold")
+	require.Empty(t, c.requests)
+}
+
+func TestDefaultConsolidateMaxInputs(t *testing.T) {
+	require.Equal(t, 336, defaultConsolidateMaxInputs())
+}
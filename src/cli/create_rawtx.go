@@ -48,6 +48,10 @@ func createRawTxnCmd() *cobra.Command {
     Note: The [amount] argument is the coins you will spend, with decimal formatting, e.g. 1, 1.001 or 1.000000.
 
     The [to address] and [amount] arguments can be replaced with the --many/-m or the --csv option.
+    The [to address] can be the name of a contact of the address book, also in the --many/-m option.
+
+    With the --dry-run option the transaction is created but not signed,
+    its inputs, outputs, change, hours burned and size are printed.
 
     Use caution when using the "-p" command. If you have command history enabled
     your wallet encryption password can be recovered from the history log. If you
@@ -61,6 +65,19 @@ func createRawTxnCmd() *cobra.Command {
 				return err
 			}
 
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				preview, err := previewRawTxnCmdHandler(c, args)
+				if err != nil {
+					return err
+				}
+				return printTransactionPreviews(os.Stdout, jsonOutput, preview)
+			}
+
 			txn, err := createRawTxnCmdHandler(c, args)
 			switch err.(type) {
 			case nil:
@@ -98,6 +115,7 @@ example: -m '[{"addr":"$addr1", "coins": "10.2"}, {"addr":"$addr2", "coins": "20
 	createRawTxnCmd.Flags().StringP("password", "p", "", "Wallet password")
 	createRawTxnCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
 	createRawTxnCmd.Flags().String("csv", "", "CSV file containing addresses and amounts to send")
+	addDryRunFlag(createRawTxnCmd)
 
 	return createRawTxnCmd
 }
@@ -111,6 +129,10 @@ func createRawTxnV2Cmd() *cobra.Command {
     Note: The [amount] argument is the coins you will spend, with decimal formatting, e.g. 1, 1.001 or 1.000000.
 
     The [to address] and [amount] arguments can be replaced with the --csv option.,
+    The [to address] can be the name of a contact of the address book.
+
+    With the --dry-run option the transaction is created but not signed,
+    its inputs, outputs, change, hours burned and size are printed.
 
     Use caution when using the "-p" command. If you have command history enabled
     your wallet encryption password can be recovered from the history log. If you
@@ -124,7 +146,12 @@ func createRawTxnV2Cmd() *cobra.Command {
 				return err
 			}
 
-			req, err := makeWalletCreateTxnRequest(c, args)
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			req, err := makeWalletCreateTxnRequest(c, args, dryRun)
 			if err != nil {
 				return err
 			}
@@ -134,6 +161,10 @@ func createRawTxnV2Cmd() *cobra.Command {
 				return err
 			}
 
+			if dryRun {
+				return printTransactionPreviews(os.Stdout, jsonOutput, newTransactionPreview(rsp.Transaction, len(req.To)))
+			}
+
 			if jsonOutput {
 				return printJSON(rsp)
 			}
@@ -151,6 +182,7 @@ func createRawTxnV2Cmd() *cobra.Command {
 	createRawTxnCmd.Flags().StringP("password", "p", "", "Wallet password")
 	createRawTxnCmd.Flags().BoolP("unsign", "", false, "Do not sign the transaction")
 	createRawTxnCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+	addDryRunFlag(createRawTxnCmd)
 
 	createRawTxnCmd.Flags().BoolP("ignore-unconfirmed", "", false, "Ignore unconfirmed transactions")
 	createRawTxnCmd.Flags().StringP("hours-selection-type", "", transaction.HoursSelectionTypeAuto, "Hours selection type")
@@ -160,14 +192,21 @@ func createRawTxnV2Cmd() *cobra.Command {
 	return createRawTxnCmd
 }
 
-func makeWalletCreateTxnRequest(c *cobra.Command, args []string) (*api.WalletCreateTransactionRequest, error) {
+// makeWalletCreateTxnRequest makes the request of the createRawTransactionV2 command, the transaction is unsigned if dryRun is true
+func makeWalletCreateTxnRequest(c *cobra.Command, args []string, dryRun bool) (*api.WalletCreateTransactionRequest, error) {
 	unsign, err := c.Flags().GetBool("unsign")
 	if err != nil {
 		return nil, err
 	}
 
-	walletFile := args[0]
-	w, err := apiClient.Wallet(args[0])
+	return makeWalletCreateTxnRequestTo(c, args[0], unsign || dryRun, func() ([]api.Receiver, error) {
+		return getToAddressesV2(c, args[1:])
+	})
+}
+
+// makeWalletCreateTxnRequestTo makes the request to create a transaction from the wallet, sending to the receivers of getTo
+func makeWalletCreateTxnRequestTo(c *cobra.Command, walletFile string, unsign bool, getTo func() ([]api.Receiver, error)) (*api.WalletCreateTransactionRequest, error) {
+	w, err := apiClient.Wallet(walletFile)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +225,12 @@ func makeWalletCreateTxnRequest(c *cobra.Command, args []string) (*api.WalletCre
 		}
 	}
 
-	ctr, err := makeCreateTransactionRequest(c, args, addrs)
+	to, err := getTo()
+	if err != nil {
+		return nil, err
+	}
+
+	ctr, err := makeCreateTransactionRequest(c, to, addrs)
 	if err != nil {
 		return nil, err
 	}
@@ -222,7 +266,7 @@ func getPassword(c *cobra.Command) ([]byte, error) {
 	return NewPasswordReader([]byte(p)).Password()
 }
 
-func makeCreateTransactionRequest(c *cobra.Command, args []string, fromAddrs []string) (*api.CreateTransactionRequest, error) {
+func makeCreateTransactionRequest(c *cobra.Command, to []api.Receiver, fromAddrs []string) (*api.CreateTransactionRequest, error) {
 	hoursSelection, err := getHoursSelection(c)
 	if err != nil {
 		return nil, err
@@ -242,11 +286,6 @@ func makeCreateTransactionRequest(c *cobra.Command, args []string, fromAddrs []s
 		changeAddr = &ca
 	}
 
-	to, err := getToAddressesV2(c, args[1:])
-	if err != nil {
-		return nil, err
-	}
-
 	return &api.CreateTransactionRequest{
 		IgnoreUnconfirmed: iu,
 		HoursSelection:    *hoursSelection,
@@ -274,7 +313,10 @@ func getToAddressesV2(c *cobra.Command, args []string) ([]api.Receiver, error) {
 		return nil, fmt.Errorf("requires at least 2 arg(s), only received %d", len(args))
 	}
 
-	toAddr := args[0]
+	toAddr, err := newContactResolver().resolve(args[0])
+	if err != nil {
+		return nil, err
+	}
 	if _, err := cipher.DecodeBase58Address(toAddr); err != nil {
 		return nil, err
 	}
@@ -390,7 +432,19 @@ func getToAddresses(c *cobra.Command, args []string) ([]SendAmount, error) {
 	}
 
 	if many != "" {
-		return parseSendAmountsFromJSON(many)
+		sendAmts, err := parseSendAmountsFromJSON(many)
+		if err != nil {
+			return nil, err
+		}
+
+		contacts := newContactResolver()
+		for i := range sendAmts {
+			sendAmts[i].Addr, err = contacts.resolve(sendAmts[i].Addr)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return sendAmts, nil
 	} else if csvFile != "" {
 		fields, err := openCSV(csvFile)
 		if err != nil {
@@ -403,7 +457,10 @@ func getToAddresses(c *cobra.Command, args []string) ([]SendAmount, error) {
 		return nil, fmt.Errorf("requires at least 2 arg(s), only received %d", len(args))
 	}
 
-	toAddr := args[0]
+	toAddr, err := newContactResolver().resolve(args[0])
+	if err != nil {
+		return nil, err
+	}
 
 	if _, err := cipher.DecodeBase58Address(toAddr); err != nil {
 		return nil, err
@@ -639,97 +696,88 @@ func validateSendAmounts(toAddrs []SendAmount) error {
 
 // CreateRawTxnFromWallet creates a transaction from any address or combination of addresses in a wallet
 func CreateRawTxnFromWallet(c GetOutputser, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader, distParams params.Distribution) (*coin.Transaction, error) {
-	// check change address
-	cAddr, err := cipher.DecodeBase58Address(chgAddr)
-	if err != nil {
-		return nil, ErrAddress
-	}
-
-	// check if the change address is in wallet.
-	wlt, err := wallet.Load(walletFile)
+	wlt, inAddrs, err := loadRawTxnWallet(walletFile, "", chgAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := wlt.GetEntry(cAddr); err != nil {
-		if err == wallet.ErrEntryNotFound {
-			return nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
-		}
+	password, err := readRawTxnPassword(wlt, pr)
+	if err != nil {
 		return nil, err
 	}
 
-	switch pr.(type) {
-	case nil:
-		if wlt.IsEncrypted() {
-			return nil, wallet.ErrWalletEncrypted
-		}
-	case PasswordFromBytes:
-		p, err := pr.Password()
-		if err != nil {
-			return nil, err
-		}
-
-		if !wlt.IsEncrypted() && len(p) != 0 {
-			return nil, wallet.ErrWalletNotEncrypted
-		}
-	}
-
-	var password []byte
-	if wlt.IsEncrypted() {
-		var err error
-		password, err = pr.Password()
-		if err != nil {
-			return nil, err
-		}
-	}
+	return CreateRawTxn(c, wlt, inAddrs, chgAddr, toAddrs, password, distParams)
+}
 
-	// get all address in the wallet
-	totalAddrs, err := wlt.GetAddresses()
+// CreateRawTxnFromAddress creates a transaction from a specific address in a wallet
+func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader, distParams params.Distribution) (*coin.Transaction, error) {
+	wlt, inAddrs, err := loadRawTxnWallet(walletFile, addr, chgAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	addrStrArray := make([]string, len(totalAddrs))
-	for i, a := range totalAddrs {
-		addrStrArray[i] = a.String()
+	password, err := readRawTxnPassword(wlt, pr)
+	if err != nil {
+		return nil, err
 	}
 
-	return CreateRawTxn(c, wlt, addrStrArray, chgAddr, toAddrs, password, distParams)
+	return CreateRawTxn(c, wlt, inAddrs, chgAddr, toAddrs, password, distParams)
 }
 
-// CreateRawTxnFromAddress creates a transaction from a specific address in a wallet
-func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader, distParams params.Distribution) (*coin.Transaction, error) {
-	// check if the address is in the default wallet.
+// loadRawTxnWallet loads the wallet and returns the addresses to spend from, the address addr
+// or all the addresses of the wallet if addr is empty. The change address must be in the wallet.
+func loadRawTxnWallet(walletFile, addr, chgAddr string) (wallet.Wallet, []string, error) {
 	wlt, err := wallet.Load(walletFile)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	srcAddr, err := cipher.DecodeBase58Address(addr)
-	if err != nil {
-		return nil, ErrAddress
-	}
+	var inAddrs []string
+	if addr != "" {
+		srcAddr, err := cipher.DecodeBase58Address(addr)
+		if err != nil {
+			return nil, nil, ErrAddress
+		}
 
-	if _, err := wlt.GetEntry(srcAddr); err != nil {
-		if err == wallet.ErrEntryNotFound {
-			return nil, fmt.Errorf("%v address is not in wallet", addr)
+		if _, err := wlt.GetEntry(srcAddr); err != nil {
+			if err == wallet.ErrEntryNotFound {
+				return nil, nil, fmt.Errorf("%v address is not in wallet", addr)
+			}
+			return nil, nil, err
+		}
+
+		inAddrs = []string{addr}
+	} else {
+		// get all address in the wallet
+		totalAddrs, err := wlt.GetAddresses()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		inAddrs = make([]string, len(totalAddrs))
+		for i, a := range totalAddrs {
+			inAddrs[i] = a.String()
 		}
-		return nil, err
 	}
 
-	// validate change address
+	// check if the change address is in wallet.
 	cAddr, err := cipher.DecodeBase58Address(chgAddr)
 	if err != nil {
-		return nil, ErrAddress
+		return nil, nil, ErrAddress
 	}
 
 	if _, err := wlt.GetEntry(cAddr); err != nil {
 		if err == wallet.ErrEntryNotFound {
-			return nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
+			return nil, nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
+	return wlt, inAddrs, nil
+}
+
+// readRawTxnPassword reads the password of an encrypted wallet
+func readRawTxnPassword(wlt wallet.Wallet, pr PasswordReader) ([]byte, error) {
 	switch pr.(type) {
 	case nil:
 		if wlt.IsEncrypted() {
@@ -746,16 +794,11 @@ func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, t
 		}
 	}
 
-	var password []byte
-	if wlt.IsEncrypted() {
-		var err error
-		password, err = pr.Password()
-		if err != nil {
-			return nil, err
-		}
+	if !wlt.IsEncrypted() {
+		return nil, nil
 	}
 
-	return CreateRawTxn(c, wlt, []string{addr}, chgAddr, toAddrs, password, distParams)
+	return pr.Password()
 }
 
 // GetOutputser implements unspent output querying
@@ -775,61 +818,80 @@ func CreateRawTxn(c GetOutputser, wlt wallet.Wallet, inAddrs []string, chgAddr s
 		return nil, err
 	}
 
-	inUxs, err := outputs.SpendableOutputs().ToUxArray()
+	txn, err := createRawTxn(outputs, wlt, chgAddr, toAddrs, password)
 	if err != nil {
 		return nil, err
 	}
 
-	txn, err := createRawTxn(outputs, wlt, chgAddr, toAddrs, password)
-	if err != nil {
+	if err := verifyRawTxn(outputs, txn, visor.TxnSigned, distParams); err != nil {
 		return nil, err
 	}
 
-	// filter out unspents which are not used in transaction
-	var inUxsFiltered coin.UxArray
-	for _, h := range txn.In {
-		for _, u := range inUxs {
-			if h == u.Hash() {
-				inUxsFiltered = append(inUxsFiltered, u)
-			}
-		}
+	return txn, nil
+}
+
+// PreviewRawTxn creates the transaction that CreateRawTxn creates from a set of addresses, without signing it.
+// The wallet keys are not needed, the inputs of the transaction are returned with their coins and hours.
+func PreviewRawTxn(c GetOutputser, inAddrs []string, chgAddr string, toAddrs []SendAmount, distParams params.Distribution) (*api.CreatedTransaction, error) {
+	if err := validateSendAmounts(toAddrs); err != nil {
+		return nil, err
 	}
 
-	head, err := outputs.Head.ToCoinBlockHeader()
+	outputs, err := c.OutputsForAddresses(inAddrs)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := visor.VerifySingleTxnSoftConstraints(*txn, head.Time, inUxsFiltered, distParams, params.UserVerifyTxn); err != nil {
+	spendOutputs, txOuts, err := makeRawTxnOutputs(outputs, chgAddr, toAddrs)
+	if err != nil {
 		return nil, err
 	}
-	if err := visor.VerifySingleTxnHardConstraints(*txn, head, inUxsFiltered, visor.TxnSigned); err != nil {
+
+	txn, err := NewTransaction(spendOutputs, nil, txOuts)
+	if err != nil {
 		return nil, err
 	}
-	if err := visor.VerifySingleTxnUserConstraints(*txn); err != nil {
+
+	if err := verifyRawTxn(outputs, txn, visor.TxnUnsigned, distParams); err != nil {
 		return nil, err
 	}
 
-	return txn, nil
+	return api.NewCreatedTransaction(txn, visor.NewTransactionInputsFromUxBalance(spendOutputs))
 }
 
-func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt wallet.Wallet, chgAddr string, toAddrs []SendAmount, password []byte) (*coin.Transaction, error) {
-	// Calculate total required coins
-	var totalCoins uint64
-	for _, arg := range toAddrs {
-		var err error
-		totalCoins, err = mathutil.AddUint64(totalCoins, arg.Coins)
-		if err != nil {
-			return nil, err
+// verifyRawTxn verifies the transaction spending the unspent outputs against the soft, hard and user constraints
+func verifyRawTxn(outputs *readable.UnspentOutputsSummary, txn *coin.Transaction, signed visor.TxnSignedFlag, distParams params.Distribution) error {
+	inUxs, err := outputs.SpendableOutputs().ToUxArray()
+	if err != nil {
+		return err
+	}
+
+	// filter out unspents which are not used in transaction
+	var inUxsFiltered coin.UxArray
+	for _, h := range txn.In {
+		for _, u := range inUxs {
+			if h == u.Hash() {
+				inUxsFiltered = append(inUxsFiltered, u)
+			}
 		}
 	}
 
-	spendOutputs, err := chooseSpends(uxouts, totalCoins)
+	head, err := outputs.Head.ToCoinBlockHeader()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	txOuts, err := makeChangeOut(spendOutputs, chgAddr, toAddrs)
+	if err := visor.VerifySingleTxnSoftConstraints(*txn, head.Time, inUxsFiltered, distParams, params.UserVerifyTxn); err != nil {
+		return err
+	}
+	if err := visor.VerifySingleTxnHardConstraints(*txn, head, inUxsFiltered, signed); err != nil {
+		return err
+	}
+	return visor.VerifySingleTxnUserConstraints(*txn)
+}
+
+func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt wallet.Wallet, chgAddr string, toAddrs []SendAmount, password []byte) (*coin.Transaction, error) {
+	spendOutputs, txOuts, err := makeRawTxnOutputs(uxouts, chgAddr, toAddrs)
 	if err != nil {
 		return nil, err
 	}
@@ -865,6 +927,31 @@ func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt wallet.Wallet, chg
 	return makeTxn()
 }
 
+// makeRawTxnOutputs chooses the unspent outputs to spend and makes the outputs to the addresses, with the change output
+func makeRawTxnOutputs(uxouts *readable.UnspentOutputsSummary, chgAddr string, toAddrs []SendAmount) ([]transaction.UxBalance, []coin.TransactionOutput, error) {
+	// Calculate total required coins
+	var totalCoins uint64
+	for _, arg := range toAddrs {
+		var err error
+		totalCoins, err = mathutil.AddUint64(totalCoins, arg.Coins)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	spendOutputs, err := chooseSpends(uxouts, totalCoins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txOuts, err := makeChangeOut(spendOutputs, chgAddr, toAddrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spendOutputs, txOuts, nil
+}
+
 func chooseSpends(uxouts *readable.UnspentOutputsSummary, coins uint64) ([]transaction.UxBalance, error) {
 	// Convert spendable unspent outputs to []transaction.UxBalance
 	spendableOutputs, err := readable.OutputsToUxBalances(uxouts.SpendableOutputs())
@@ -981,12 +1068,13 @@ func getKeys(wlt wallet.Wallet, outs []transaction.UxBalance) ([]cipher.SecKey,
 			}
 			return nil, err
 		}
-		keys[i] = entry.Secret
+		keys[i] = entry.Secret.SecKey()
 	}
 	return keys, nil
 }
 
-// NewTransaction creates a transaction. The transaction should be validated against hard and soft constraints before transmission.
+// NewTransaction creates a transaction, the inputs are signed with the keys. If keys is nil the transaction is unsigned.
+// The transaction should be validated against hard and soft constraints before transmission.
 func NewTransaction(utxos []transaction.UxBalance, keys []cipher.SecKey, outs []coin.TransactionOutput) (*coin.Transaction, error) {
 	txn := coin.Transaction{}
 	for _, u := range utxos {
@@ -1001,7 +1089,11 @@ func NewTransaction(utxos []transaction.UxBalance, keys []cipher.SecKey, outs []
 		}
 	}
 
-	txn.SignInputs(keys)
+	if keys == nil {
+		txn.Sigs = make([]cipher.Sig, len(txn.In))
+	} else {
+		txn.SignInputs(keys)
+	}
 
 	err := txn.UpdateHeader()
 	if err != nil {
@@ -35,7 +35,7 @@ func distributeGenesisCmd() *cobra.Command {
 func distributeGenesisHandler(c *cobra.Command, args []string) error {
 	sk, err := cipher.SecKeyFromHex(args[0])
 	if err != nil {
-		return errors.New("invalid genesis secret key")
+		return ValidationError{errors.New("invalid genesis secret key")}
 	}
 
 	// Obtain the genesis uxid from the node
@@ -72,7 +72,7 @@ func distributeGenesisHandler(c *cobra.Command, args []string) error {
 		}
 	}
 
-	return nil
+	return printTxid(c, txn.Hash().Hex())
 }
 
 func getGenesisUxID() (string, error) {
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/params"
+)
+
+// TransactionPreview is a transaction created by the --dry-run option of the send commands, it is neither signed nor broadcast
+type TransactionPreview struct {
+	Inputs  []api.CreatedTransactionInput  `json:"inputs"`
+	Outputs []api.CreatedTransactionOutput `json:"outputs"`
+	// Change is the change output, nil if the transaction has no change
+	Change      *api.CreatedTransactionOutput `json:"change"`
+	HoursBurned string                        `json:"hours_burned"`
+	// Size is the size in bytes of the signed transaction
+	Size uint32 `json:"size"`
+}
+
+// newTransactionPreview returns the preview of a created transaction, sending to nTo recipients.
+// The outputs of the recipients come first, followed by the change output.
+func newTransactionPreview(txn api.CreatedTransaction, nTo int) *TransactionPreview {
+	p := &TransactionPreview{
+		Inputs:      txn.In,
+		Outputs:     txn.Out,
+		HoursBurned: txn.Fee,
+		Size:        txn.Length,
+	}
+
+	if len(txn.Out) > nTo {
+		p.Outputs = txn.Out[:nTo]
+		p.Change = &txn.Out[nTo]
+	}
+
+	return p
+}
+
+func addDryRunFlag(c *cobra.Command) {
+	c.Flags().Bool("dry-run", false, `Print the inputs, outputs, change, hours burned and size of the transaction,
+without signing or sending it. The wallet password is not needed.`)
+}
+
+// printTransactionPreviews prints the previews, in JSON if jsonOutput is true
+func printTransactionPreviews(w io.Writer, jsonOutput bool, previews ...*TransactionPreview) error {
+	if jsonOutput {
+		if len(previews) == 1 {
+			return printJSON(previews[0])
+		}
+		return printJSON(previews)
+	}
+
+	for i, p := range previews {
+		if len(previews) > 1 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "Transaction %d:\n", i+1)
+		}
+		printTransactionPreview(w, p)
+	}
+	return nil
+}
+
+func printTransactionPreview(w io.Writer, p *TransactionPreview) {
+	fmt.Fprintf(w, "Inputs:       %d\n", len(p.Inputs))
+	for i, in := range p.Inputs {
+		fmt.Fprintf(w, "  %d. %s coins:%s hours:%s uxid:%s\n", i+1, in.Address, in.Coins, in.CalculatedHours, in.UxID)
+	}
+	fmt.Fprintf(w, "Outputs:      %d\n", len(p.Outputs))
+	for i, o := range p.Outputs {
+		fmt.Fprintf(w, "  %d. %s coins:%s hours:%s\n", i+1, o.Address, o.Coins, o.Hours)
+	}
+	if p.Change != nil {
+		fmt.Fprintf(w, "Change:       %s coins:%s hours:%s\n", p.Change.Address, p.Change.Coins, p.Change.Hours)
+	} else {
+		fmt.Fprintln(w, "Change:       none")
+	}
+	fmt.Fprintf(w, "Hours burned: %s\n", p.HoursBurned)
+	fmt.Fprintf(w, "Size:         %d bytes\n", p.Size)
+}
+
+// previewRawTxnCmdHandler creates the transaction of the send and createRawTransaction commands without signing it
+func previewRawTxnCmdHandler(c *cobra.Command, args []string) (*TransactionPreview, error) {
+	parsedArgs, err := parseCreateRawTxnArgs(c, args)
+	if err != nil {
+		return nil, err
+	}
+
+	_, inAddrs, err := loadRawTxnWallet(parsedArgs.WalletID, parsedArgs.Address, parsedArgs.ChangeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	txn, err := PreviewRawTxn(apiClient, inAddrs, parsedArgs.ChangeAddress, parsedArgs.SendAmounts, params.MainNetDistribution)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTransactionPreview(*txn, len(parsedArgs.SendAmounts)), nil
+}
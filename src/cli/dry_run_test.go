@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+type fakeOutputsClient struct {
+	outs *readable.UnspentOutputsSummary
+}
+
+func (c fakeOutputsClient) OutputsForAddresses(addrs []string) (*readable.UnspentOutputsSummary, error) {
+	return c.outs, nil
+}
+
+func TestPreviewRawTxn(t *testing.T) {
+	from := testutil.MakeAddress()
+	to := testutil.MakeAddress().String()
+
+	head := coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				BkSeq: 10,
+				Time:  1e9,
+			},
+		},
+	}
+
+	var uxs []visor.UnspentOutput
+	for _, coins := range []uint64{5e6, 20e6} {
+		ux, err := visor.NewUnspentOutput(coin.UxOut{
+			Head: coin.UxHead{
+				Time:  head.Head.Time - 3600,
+				BkSeq: 5,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        from,
+				Coins:          coins,
+				Hours:          100,
+			},
+		}, head.Head.Time)
+		require.NoError(t, err)
+		uxs = append(uxs, ux)
+	}
+
+	outs, err := readable.NewUnspentOutputsSummary(&visor.UnspentOutputsSummary{
+		HeadBlock: &head,
+		Confirmed: uxs,
+	})
+	require.NoError(t, err)
+
+	c := fakeOutputsClient{outs: outs}
+
+	// The output of 20 coins and 120 hours is spent, 10 coins are sent back as change
+	txn, err := PreviewRawTxn(c, []string{from.String()}, from.String(), []SendAmount{
+		{Addr: to, Coins: 10e6},
+	}, params.MainNetDistribution)
+	require.NoError(t, err)
+
+	require.Len(t, txn.In, 1)
+	require.Equal(t, uxs[1].Hash().Hex(), txn.In[0].UxID)
+	require.Equal(t, "120", txn.In[0].CalculatedHours)
+
+	// The transaction is not signed, its length is the length of the signed transaction
+	require.Equal(t, []string{cipher.Sig{}.Hex()}, txn.Sigs)
+	ct, err := txn.ToTransaction()
+	require.NoError(t, err)
+	size, err := ct.Size()
+	require.NoError(t, err)
+	require.Equal(t, size, txn.Length)
+
+	preview := newTransactionPreview(*txn, 1)
+	require.Equal(t, []api.CreatedTransactionOutput{txn.Out[0]}, preview.Outputs)
+	require.Equal(t, &txn.Out[1], preview.Change)
+	require.Equal(t, to, preview.Outputs[0].Address)
+	require.Equal(t, "10.000000", preview.Outputs[0].Coins)
+	require.Equal(t, from.String(), preview.Change.Address)
+	require.Equal(t, "10.000000", preview.Change.Coins)
+	require.Equal(t, "12", preview.HoursBurned)
+
+	var out bytes.Buffer
+	require.NoError(t, printTransactionPreviews(&out, false, preview))
+	require.Equal(t, `Inputs:       1
+  1. `+from.String()+` coins:20.000000 hours:120 uxid:`+txn.In[0].UxID+`
+Outputs:      1
+  1. `+to+` coins:10.000000 hours:10
+Change:       `+from.String()+` coins:10.000000 hours:98
+Hours burned: 12
+Size:         `+fmt.Sprint(txn.Length)+` bytes
+`, out.String())
+
+	// Not enough coins
+	_, err = PreviewRawTxn(c, []string{from.String()}, from.String(), []SendAmount{
+		{Addr: to, Coins: 30e6},
+	}, params.MainNetDistribution)
+	require.Error(t, err)
+}
+
+func TestNewTransactionPreview(t *testing.T) {
+	txn := api.CreatedTransaction{
+		Length: 300,
+		Fee:    "20",
+		In: []api.CreatedTransactionInput{
+			{UxID: "a", Address: "addr1", Coins: "5.000000", CalculatedHours: "40"},
+			{UxID: "b", Address: "addr1", Coins: "1.000000", CalculatedHours: "0"},
+		},
+		Out: []api.CreatedTransactionOutput{
+			{Address: "addr2", Coins: "3.000000", Hours: "10"},
+			{Address: "addr3", Coins: "3.000000", Hours: "10"},
+		},
+	}
+
+	// Without change
+	preview := newTransactionPreview(txn, 2)
+	require.Nil(t, preview.Change)
+	require.Len(t, preview.Outputs, 2)
+
+	var out bytes.Buffer
+	require.NoError(t, printTransactionPreviews(&out, false, preview, preview))
+	require.Equal(t, `Transaction 1:
+Inputs:       2
+  1. addr1 coins:5.000000 hours:40 uxid:a
+  2. addr1 coins:1.000000 hours:0 uxid:b
+Outputs:      2
+  1. addr2 coins:3.000000 hours:10
+  2. addr3 coins:3.000000 hours:10
+Change:       none
+Hours burned: 20
+Size:         300 bytes
+
+Transaction 2:
+Inputs:       2
+  1. addr1 coins:5.000000 hours:40 uxid:a
+  2. addr1 coins:1.000000 hours:0 uxid:b
+Outputs:      2
+  1. addr2 coins:3.000000 hours:10
+  2. addr3 coins:3.000000 hours:10
+Change:       none
+Hours burned: 20
+Size:         300 bytes
+`, out.String())
+
+	// With change
+	preview = newTransactionPreview(txn, 1)
+	require.Equal(t, txn.Out[:1], preview.Outputs)
+	require.Equal(t, &txn.Out[1], preview.Change)
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+// Exit codes of the cli, for the classes of errors of the commands
+const (
+	// ExitCodeError is the exit code of the errors without a more specific class
+	ExitCodeError = 1
+	// ExitCodeValidation is the exit code of invalid arguments, flags and values
+	ExitCodeValidation = 2
+	// ExitCodeConnection is the exit code of the errors connecting to the node
+	ExitCodeConnection = 3
+	// ExitCodeInsufficientFunds is the exit code of spends exceeding the coins or coin hours of the wallet or addresses
+	ExitCodeInsufficientFunds = 4
+	// ExitCodeNode is the exit code of the errors returned by the node
+	ExitCodeNode = 5
+)
+
+// Error classes of the ErrorResponse, by exit code
+var errorClasses = map[int]string{
+	ExitCodeError:             "error",
+	ExitCodeValidation:        "validation",
+	ExitCodeConnection:        "connection",
+	ExitCodeInsufficientFunds: "insufficient_funds",
+	ExitCodeNode:              "node",
+}
+
+// ValidationError is returned for invalid arguments, flags and values
+type ValidationError struct {
+	error
+}
+
+// Unwrap returns the invalid value error
+func (e ValidationError) Unwrap() error {
+	return e.error
+}
+
+// printedError is the error of a command that printed its JSON result before failing.
+// The exit code is the code of the error, but no ErrorResponse is printed after the result.
+type printedError struct {
+	error
+}
+
+func (e printedError) Unwrap() error {
+	return e.error
+}
+
+// ErrorResponse is printed for the error of a command run with --json
+type ErrorResponse struct {
+	Error ErrorResponseDetail `json:"error"`
+}
+
+// ErrorResponseDetail is the error of an ErrorResponse
+type ErrorResponseDetail struct {
+	// Code is the exit code of the cli
+	Code int `json:"code"`
+	// Class is the class of the error: error, validation, connection, insufficient_funds or node
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// NewErrorResponse creates the ErrorResponse of err
+func NewErrorResponse(err error) ErrorResponse {
+	code := ExitCode(err)
+	return ErrorResponse{
+		Error: ErrorResponseDetail{
+			Code:    code,
+			Class:   errorClasses[code],
+			Message: err.Error(),
+		},
+	}
+}
+
+// ExitCode returns the exit code of the class of err, 0 if err is nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var clientErr api.ClientError
+	isClientErr := errors.As(err, &clientErr)
+
+	switch {
+	case isInsufficientFunds(err):
+		return ExitCodeInsufficientFunds
+	case errors.As(err, &ValidationError{}),
+		errors.Is(err, ErrAddress),
+		errors.Is(err, ErrWalletName),
+		strings.HasPrefix(err.Error(), "unknown command"):
+		return ExitCodeValidation
+	case isClientErr && clientErr.StatusCode == http.StatusBadRequest:
+		return ExitCodeValidation
+	case isClientErr:
+		return ExitCodeNode
+	case isConnectionError(err):
+		return ExitCodeConnection
+	default:
+		return ExitCodeError
+	}
+}
+
+func isInsufficientFunds(err error) bool {
+	for _, e := range []error{
+		transaction.ErrInsufficientBalance,
+		transaction.ErrInsufficientHours,
+		ErrTemporaryInsufficientBalance,
+	} {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+
+	// The errors of the node only have a message
+	var clientErr api.ClientError
+	if errors.As(err, &clientErr) {
+		return strings.Contains(clientErr.Message, transaction.ErrInsufficientBalance.Error()) ||
+			strings.Contains(clientErr.Message, transaction.ErrInsufficientHours.Error())
+	}
+
+	return false
+}
+
+func isConnectionError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// setValidationErrors makes the argument and flag errors of the commands ValidationErrors
+func setValidationErrors(root *cobra.Command) {
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return ValidationError{err}
+	})
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				if err := args(c, a); err != nil {
+					return ValidationError{err}
+				}
+				return nil
+			}
+		}
+
+		for _, sc := range c.Commands() {
+			walk(sc)
+		}
+	}
+	walk(root)
+}
+
+// PrintError prints the error of the command c. The error is printed to stdout as an ErrorResponse
+// if c was run with --json, and to stderr otherwise.
+func PrintError(c *cobra.Command, err error) {
+	if jsonOutput, _ := c.Flags().GetBool("json"); jsonOutput {
+		if errors.As(err, &printedError{}) {
+			return
+		}
+		if printJSON(NewErrorResponse(err)) == nil {
+			return
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", err)
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+func TestExitCode(t *testing.T) {
+	tt := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name: "error",
+			err:  errors.New("foo"),
+			code: ExitCodeError,
+		},
+		{
+			name: "validation error",
+			err:  ValidationError{errors.New("invalid txid")},
+			code: ExitCodeValidation,
+		},
+		{
+			name: "wrapped validation error",
+			err:  fmt.Errorf("send: %w", ValidationError{errors.New("invalid amount")}),
+			code: ExitCodeValidation,
+		},
+		{
+			name: "invalid address",
+			err:  ErrAddress,
+			code: ExitCodeValidation,
+		},
+		{
+			name: "unknown command",
+			err:  errors.New(`unknown command "foo" for "skycoin-cli"`),
+			code: ExitCodeValidation,
+		},
+		{
+			name: "insufficient balance",
+			err:  transaction.ErrInsufficientBalance,
+			code: ExitCodeInsufficientFunds,
+		},
+		{
+			name: "temporary insufficient balance",
+			err:  ErrTemporaryInsufficientBalance,
+			code: ExitCodeInsufficientFunds,
+		},
+		{
+			name: "node insufficient hours",
+			err:  api.NewClientError("400 Bad Request", http.StatusBadRequest, "400 Bad Request - hours are not sufficient"),
+			code: ExitCodeInsufficientFunds,
+		},
+		{
+			name: "node bad request",
+			err:  api.NewClientError("400 Bad Request", http.StatusBadRequest, "400 Bad Request - invalid password"),
+			code: ExitCodeValidation,
+		},
+		{
+			name: "node error",
+			err:  api.NewClientError("404 Not Found", http.StatusNotFound, "404 Not Found"),
+			code: ExitCodeNode,
+		},
+		{
+			name: "connection error",
+			err: &url.Error{
+				Op:  "Get",
+				URL: "http://127.0.0.1:6420/api/v1/health",
+				Err: errors.New("dial tcp 127.0.0.1:6420: connect: connection refused"),
+			},
+			code: ExitCodeConnection,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.code, ExitCode(tc.err))
+		})
+	}
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	r := NewErrorResponse(api.NewClientError("400 Bad Request", http.StatusBadRequest, "400 Bad Request - invalid password\n"))
+	d, err := formatJSON(r)
+	require.NoError(t, err)
+	require.Equal(t, `{
+    "error": {
+        "code": 2,
+        "class": "validation",
+        "message": "400 Bad Request - invalid password"
+    }
+}`, string(d))
+}
+
+func TestSetValidationErrors(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	cmd := &cobra.Command{
+		Use:  "cmd",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return nil
+		},
+	}
+	cmd.Flags().Int("num", 1, "")
+	root.AddCommand(cmd)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	setValidationErrors(root)
+
+	root.SetArgs([]string{"cmd"})
+	err := root.Execute()
+	require.EqualError(t, err, "accepts 1 arg(s), received 0")
+	require.Equal(t, ExitCodeValidation, ExitCode(err))
+
+	root.SetArgs([]string{"cmd", "foo", "--num", "bar"})
+	err = root.Execute()
+	require.Error(t, err)
+	require.Equal(t, ExitCodeValidation, ExitCode(err))
+
+	root.SetArgs([]string{"cmd", "foo"})
+	require.NoError(t, root.Execute())
+}
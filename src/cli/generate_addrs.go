@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -30,13 +31,18 @@ func walletAddAddressesCmd() *cobra.Command {
     Use caution when using the "-p" command. If you have command
     history enabled your wallet encryption password can be recovered from the
     history log. If you do not include the "-p" option you will be prompted to
-    enter your password after you enter your command.`,
+    enter your password after you enter your command.
+
+    With --qr or --qr-png, the new addresses are rendered as QR codes instead,
+    e.g. to receive a payment to a new address. The QR codes encode a payment URI
+    if --amount, --hours, --label or --message are set.`,
 		RunE: generateAddrs,
 	}
 
 	walletAddAddressesCmd.Flags().Uint64P("num", "n", 1, "Number of addresses to generate")
 	walletAddAddressesCmd.Flags().StringP("password", "p", "", "wallet password")
 	walletAddAddressesCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format")
+	addQRFlags(walletAddAddressesCmd)
 
 	return walletAddAddressesCmd
 }
@@ -57,6 +63,11 @@ func generateAddrs(c *cobra.Command, args []string) error {
 		return err
 	}
 
+	qr, err := getQROptions(c)
+	if err != nil {
+		return err
+	}
+
 	wltID := args[0]
 
 	// get the wallet to check if it is encrypted
@@ -79,6 +90,10 @@ func generateAddrs(c *cobra.Command, args []string) error {
 		return err
 	}
 
+	if qr.enabled() {
+		return writeQRCodes(os.Stdout, qr, cliConfig.Coin, addrs)
+	}
+
 	if jsonFmt {
 		s, err := FormatAddressesAsJSON(addrs)
 		if err != nil {
@@ -160,6 +160,9 @@ func generateWalletHandler(c *cobra.Command, args []string) error {
 			return fmt.Errorf("%q type wallets do not use seeds", walletType)
 		}
 
+	case wallet.WalletTypeCollectionWatch:
+		return fmt.Errorf("%q type wallets are created with walletCreateWatch", walletType)
+
 	default:
 		return fmt.Errorf("unhandled wallet type %q", walletType)
 	}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+)
+
+// Directions of the transactions of the history command
+const (
+	// HistoryDirectionReceived is a transaction that spends no output of the addresses
+	HistoryDirectionReceived = "received"
+	// HistoryDirectionSent is a transaction that spends outputs of the addresses and sends coins to other addresses
+	HistoryDirectionSent = "sent"
+	// HistoryDirectionInternal is a transaction that spends outputs of the addresses and sends coins to the addresses only
+	HistoryDirectionInternal = "internal"
+)
+
+// HistoryEntry is a confirmed transaction of the addresses of the history command.
+// Amount and Hours are the changes of the coins and coin hours of the addresses, negative if spent.
+// Balance is the coins of the addresses after the transaction.
+type HistoryEntry struct {
+	Txid           string    `json:"txid"`
+	Timestamp      time.Time `json:"timestamp"`
+	BlockHeight    uint64    `json:"block_height"`
+	Direction      string    `json:"direction"`
+	Counterparties []string  `json:"counterparties"`
+	Amount         string    `json:"amount"`
+	Hours          string    `json:"hours"`
+	Balance        string    `json:"balance"`
+}
+
+var historyCSVHeader = []string{"txid", "timestamp", "block_height", "direction", "counterparties", "amount", "hours", "balance"}
+
+// historyClient is the API client used by the history command
+type historyClient interface {
+	Wallet(id string) (*api.WalletResponse, error)
+	ConfirmedTransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error)
+}
+
+func historyCmd() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Short: "Export the confirmed transactions of a wallet or an address with a running balance",
+		Use:   "history [wallet|address]",
+		Long: `Export the confirmed transactions of a wallet or an address, oldest first, in CSV or JSON format.
+
+    Each transaction has its direction (received, sent or internal), the counterparty addresses,
+    the change of the coins and coin hours of the addresses, the block height, the timestamp
+    and the balance of the addresses after the transaction.
+
+    The counterparties are the senders of received transactions and the receivers of sent transactions.
+    The coin hours spent include the coin hours accumulated by the spent outputs, and the fee.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			format, err := c.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "csv", "json":
+			default:
+				return fmt.Errorf("invalid --format %q, must be csv or json", format)
+			}
+
+			entries, err := getHistory(apiClient, args[0])
+			if err != nil {
+				return err
+			}
+
+			if format == "csv" {
+				return writeHistoryCSV(os.Stdout, entries)
+			}
+
+			return printJSON(entries)
+		},
+	}
+
+	historyCmd.Flags().StringP("format", "f", "json", "Output format, csv or json")
+
+	return historyCmd
+}
+
+// getHistory returns the history of the address, or of the addresses of the wallet
+func getHistory(c historyClient, walletOrAddress string) ([]HistoryEntry, error) {
+	var addrs []string
+	if _, err := cipher.DecodeBase58Address(walletOrAddress); err == nil {
+		addrs = []string{walletOrAddress}
+	} else {
+		wlt, err := c.Wallet(walletOrAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range wlt.Entries {
+			addrs = append(addrs, e.Address)
+		}
+
+		if len(addrs) == 0 {
+			return nil, errors.New("Wallet is empty")
+		}
+	}
+
+	txns, err := c.ConfirmedTransactionsVerbose(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeHistory(addrs, txns)
+}
+
+// makeHistory makes the history entries of the transactions of the addresses, sorted by block height
+func makeHistory(addrs []string, txns []readable.TransactionWithStatusVerbose) ([]HistoryEntry, error) {
+	own := make(map[string]struct{}, len(addrs))
+	for _, a := range addrs {
+		own[a] = struct{}{}
+	}
+
+	txns = append([]readable.TransactionWithStatusVerbose(nil), txns...)
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].Status.BlockSeq < txns[j].Status.BlockSeq
+	})
+
+	entries := make([]HistoryEntry, 0, len(txns))
+	var balance uint64
+	for _, txn := range txns {
+		var inCoins, inHours, outCoins, outHours uint64
+		var spends, sendsToOthers bool
+		senders := make(map[string]struct{})
+		receivers := make(map[string]struct{})
+
+		for _, in := range txn.Transaction.In {
+			if _, ok := own[in.Address]; !ok {
+				senders[in.Address] = struct{}{}
+				continue
+			}
+
+			spends = true
+			coins, err := droplet.FromString(in.Coins)
+			if err != nil {
+				return nil, err
+			}
+			if inCoins, err = mathutil.AddUint64(inCoins, coins); err != nil {
+				return nil, err
+			}
+			if inHours, err = mathutil.AddUint64(inHours, in.CalculatedHours); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, out := range txn.Transaction.Out {
+			if _, ok := own[out.Address]; !ok {
+				sendsToOthers = true
+				receivers[out.Address] = struct{}{}
+				continue
+			}
+
+			coins, err := droplet.FromString(out.Coins)
+			if err != nil {
+				return nil, err
+			}
+			if outCoins, err = mathutil.AddUint64(outCoins, coins); err != nil {
+				return nil, err
+			}
+			if outHours, err = mathutil.AddUint64(outHours, out.Hours); err != nil {
+				return nil, err
+			}
+		}
+
+		e := HistoryEntry{
+			Txid:        txn.Transaction.Hash,
+			Timestamp:   time.Unix(int64(txn.Time), 0).UTC(),
+			BlockHeight: txn.Status.BlockSeq,
+		}
+
+		switch {
+		case !spends:
+			e.Direction = HistoryDirectionReceived
+			e.Counterparties = sortedKeys(senders)
+		case sendsToOthers:
+			e.Direction = HistoryDirectionSent
+			e.Counterparties = sortedKeys(receivers)
+		default:
+			e.Direction = HistoryDirectionInternal
+			e.Counterparties = []string{}
+		}
+
+		var err error
+		e.Amount, err = signedDroplets(outCoins, inCoins)
+		if err != nil {
+			return nil, err
+		}
+		e.Hours = signedDifference(outHours, inHours)
+
+		balance, err = mathutil.AddUint64(balance, outCoins)
+		if err != nil {
+			return nil, err
+		}
+		if balance < inCoins {
+			return nil, fmt.Errorf("transaction %s spends more coins than the balance of the addresses", e.Txid)
+		}
+		balance -= inCoins
+
+		e.Balance, err = droplet.ToString(balance)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func writeHistoryCSV(w io.Writer, entries []HistoryEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(historyCSVHeader); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := cw.Write([]string{
+			e.Txid,
+			e.Timestamp.Format(time.RFC3339),
+			strconv.FormatUint(e.BlockHeight, 10),
+			e.Direction,
+			strings.Join(e.Counterparties, " "),
+			e.Amount,
+			e.Hours,
+			e.Balance,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// signedDroplets formats a - b as coins, with a "-" sign if negative
+func signedDroplets(a, b uint64) (string, error) {
+	if a >= b {
+		return droplet.ToString(a - b)
+	}
+
+	s, err := droplet.ToString(b - a)
+	if err != nil {
+		return "", err
+	}
+	return "-" + s, nil
+}
+
+// signedDifference formats a - b, with a "-" sign if negative
+func signedDifference(a, b uint64) string {
+	if a >= b {
+		return strconv.FormatUint(a-b, 10)
+	}
+	return "-" + strconv.FormatUint(b-a, 10)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+type fakeHistoryClient struct {
+	wallet *api.WalletResponse
+	txns   []readable.TransactionWithStatusVerbose
+	addrs  []string
+}
+
+func (c *fakeHistoryClient) Wallet(id string) (*api.WalletResponse, error) {
+	if c.wallet == nil {
+		return nil, errors.New("404 Not Found")
+	}
+	return c.wallet, nil
+}
+
+func (c *fakeHistoryClient) ConfirmedTransactionsVerbose(addrs []string) ([]readable.TransactionWithStatusVerbose, error) {
+	c.addrs = addrs
+	return c.txns, nil
+}
+
+func makeHistoryTxn(txid string, seq, t uint64, in []readable.TransactionInput, out []readable.TransactionOutput) readable.TransactionWithStatusVerbose {
+	var txn readable.TransactionWithStatusVerbose
+	txn.Status = readable.TransactionStatus{
+		Confirmed: true,
+		BlockSeq:  seq,
+	}
+	txn.Time = t
+	txn.Transaction.Hash = txid
+	txn.Transaction.In = in
+	txn.Transaction.Out = out
+	return txn
+}
+
+func TestGetHistory(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+	other1 := testutil.MakeAddress().String()
+	other2 := testutil.MakeAddress().String()
+
+	// Returned out of order
+	txns := []readable.TransactionWithStatusVerbose{
+		makeHistoryTxn("sent", 12, 1539849900, []readable.TransactionInput{
+			{Address: addr1, Coins: "10", CalculatedHours: 30},
+			{Address: addr2, Coins: "2.5", CalculatedHours: 20},
+		}, []readable.TransactionOutput{
+			{Address: other2, Coins: "4", Hours: 10},
+			{Address: other1, Coins: "1", Hours: 10},
+			{Address: addr2, Coins: "7.5", Hours: 5},
+		}),
+		makeHistoryTxn("received", 10, 1539849621, []readable.TransactionInput{
+			{Address: other1, Coins: "20", CalculatedHours: 100},
+		}, []readable.TransactionOutput{
+			{Address: addr1, Coins: "10", Hours: 20},
+			{Address: other1, Coins: "10", Hours: 20},
+		}),
+		makeHistoryTxn("internal", 13, 1539850000, []readable.TransactionInput{
+			{Address: addr2, Coins: "7.5", CalculatedHours: 8},
+		}, []readable.TransactionOutput{
+			{Address: addr1, Coins: "7.5", Hours: 4},
+		}),
+		makeHistoryTxn("received2", 11, 1539849700, []readable.TransactionInput{
+			{Address: other2, Coins: "2.5", CalculatedHours: 10},
+		}, []readable.TransactionOutput{
+			{Address: addr2, Coins: "2.5", Hours: 5},
+		}),
+	}
+
+	c := &fakeHistoryClient{
+		wallet: &api.WalletResponse{
+			Entries: []readable.WalletEntry{
+				{Address: addr1},
+				{Address: addr2},
+			},
+		},
+		txns: txns,
+	}
+
+	entries, err := getHistory(c, "foo.wlt")
+	require.NoError(t, err)
+	require.Equal(t, []string{addr1, addr2}, c.addrs)
+
+	sortedOthers := []string{other1, other2}
+	if other2 < other1 {
+		sortedOthers = []string{other2, other1}
+	}
+
+	require.Equal(t, []HistoryEntry{
+		{
+			Txid:           "received",
+			Timestamp:      time.Unix(1539849621, 0).UTC(),
+			BlockHeight:    10,
+			Direction:      HistoryDirectionReceived,
+			Counterparties: []string{other1},
+			Amount:         "10.000000",
+			Hours:          "20",
+			Balance:        "10.000000",
+		},
+		{
+			Txid:           "received2",
+			Timestamp:      time.Unix(1539849700, 0).UTC(),
+			BlockHeight:    11,
+			Direction:      HistoryDirectionReceived,
+			Counterparties: []string{other2},
+			Amount:         "2.500000",
+			Hours:          "5",
+			Balance:        "12.500000",
+		},
+		{
+			Txid:           "sent",
+			Timestamp:      time.Unix(1539849900, 0).UTC(),
+			BlockHeight:    12,
+			Direction:      HistoryDirectionSent,
+			Counterparties: sortedOthers,
+			Amount:         "-5.000000",
+			Hours:          "-45",
+			Balance:        "7.500000",
+		},
+		{
+			Txid:           "internal",
+			Timestamp:      time.Unix(1539850000, 0).UTC(),
+			BlockHeight:    13,
+			Direction:      HistoryDirectionInternal,
+			Counterparties: []string{},
+			Amount:         "0.000000",
+			Hours:          "-4",
+			Balance:        "7.500000",
+		},
+	}, entries)
+
+	var out bytes.Buffer
+	require.NoError(t, writeHistoryCSV(&out, entries[:3]))
+	require.Equal(t, `txid,timestamp,block_height,direction,counterparties,amount,hours,balance
+received,2018-10-18T08:00:21Z,10,received,`+other1+`,10.000000,20,10.000000
+received2,2018-10-18T08:01:40Z,11,received,`+other2+`,2.500000,5,12.500000
+sent,2018-10-18T08:05:00Z,12,sent,`+sortedOthers[0]+" "+sortedOthers[1]+`,-5.000000,-45,7.500000
+`, out.String())
+
+	// The history of an address
+	c.txns = txns[1:2]
+	entries, err = getHistory(c, addr1)
+	require.NoError(t, err)
+	require.Equal(t, []string{addr1}, c.addrs)
+	require.Len(t, entries, 1)
+	require.Equal(t, "10.000000", entries[0].Balance)
+
+	// A wallet without addresses
+	c.wallet.Entries = nil
+	_, err = getHistory(c, "foo.wlt")
+	require.EqualError(t, err, "Wallet is empty")
+
+	// The history of the addresses is incomplete
+	_, err = makeHistory([]string{addr1}, txns[:1])
+	require.EqualError(t, err, "transaction sent spends more coins than the balance of the addresses")
+}
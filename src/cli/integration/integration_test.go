@@ -547,7 +547,7 @@ func TestWalletAddAddresses(t *testing.T) {
 			args := append([]string{"walletAddAddresses", id}, tc.args...)
 			output, err := execCommandCombinedOutput(args...)
 			if err != nil {
-				require.EqualError(t, err, "exit status 1")
+				require.EqualError(t, err, "exit status 2")
 				return
 			}
 
@@ -599,13 +599,13 @@ func TestVerifyAddress(t *testing.T) {
 		{
 			"invalid skycoin address",
 			"2KG9eRXUhx6hrDZvNGB99DKahtrPDQ1W9vn",
-			errors.New("exit status 1"),
+			errors.New("exit status 2"),
 			"Error: Invalid checksum",
 		},
 		{
 			"invalid bitcoin address",
 			"1Dcb9gpaZpBKmjqjCsiBsP3sBW1md2kEM2",
-			errors.New("exit status 1"),
+			errors.New("exit status 2"),
 			"Error: Invalid checksum",
 		},
 	}
@@ -870,7 +870,7 @@ func TestAddressGen(t *testing.T) {
 					address := cipher.BitcoinAddressFromPubKey(pk)
 					require.Equal(t, address.String(), entries[i].Address.String())
 					require.Equal(t, pk.Hex(), entries[i].Public.Hex())
-					require.Equal(t, key, entries[i].Secret)
+					require.Equal(t, key, entries[i].Secret.SecKey())
 				}
 			},
 		},
@@ -898,7 +898,7 @@ func TestAddressGen(t *testing.T) {
 					address := cipher.BitcoinAddressFromPubKey(pk)
 					require.Equal(t, address.String(), entries[i].Address.String())
 					require.Equal(t, pk.Hex(), entries[i].Public.Hex())
-					require.Equal(t, key, entries[i].Secret)
+					require.Equal(t, key, entries[i].Secret.SecKey())
 				}
 			},
 		},
@@ -1254,7 +1254,7 @@ func TestFiberAddressGen(t *testing.T) {
 				testutil.RequireFileNotExists(t, seedsFilename)
 				require.Equal(t, "Error: this command does not take any positional arguments\n", string(v))
 			},
-			err: errors.New("exit status 1"),
+			err: errors.New("exit status 2"),
 		},
 	}
 
@@ -1555,7 +1555,7 @@ func TestStableAddressOutputs(t *testing.T) {
 		{
 			name:   "addressOutputs two address one invalid",
 			args:   []string{"addressOutputs", "2kvLEyXwAYvHfJuFCkjnYNRTUfHPyWgVwKt", "badaddress"},
-			err:    errors.New("exit status 1"),
+			err:    errors.New("exit status 2"),
 			errMsg: "Error: invalid address: badaddress, err: Invalid address length\n",
 		},
 	}
@@ -1737,21 +1737,21 @@ func TestStableTransaction(t *testing.T) {
 		{
 			name:       "invalid txid",
 			args:       []string{"abcd"},
-			err:        errors.New("exit status 1"),
+			err:        errors.New("exit status 2"),
 			errMsg:     "Error: invalid txid\n",
 			goldenFile: "",
 		},
 		{
 			name:       "not exist",
 			args:       []string{"540582ee4128b733f810f149e908d984a5f403ad2865108e6c1c5423aeefc759"},
-			err:        errors.New("exit status 1"),
+			err:        errors.New("exit status 5"),
 			errMsg:     "Error: 404 Not Found\n",
 			goldenFile: "",
 		},
 		{
 			name:       "empty txid",
 			args:       []string{""},
-			err:        errors.New("exit status 1"),
+			err:        errors.New("exit status 2"),
 			errMsg:     "Error: txid is empty\n",
 			goldenFile: "",
 		},
@@ -3168,7 +3168,7 @@ func TestStableWalletCreate(t *testing.T) {
 				entries, err := w.GetEntries()
 				require.NoError(t, err)
 				for _, e := range entries {
-					require.Equal(t, cipher.SecKey{}, e.Secret)
+					require.True(t, e.Secret.Null())
 				}
 			},
 		},
@@ -3184,7 +3184,7 @@ func TestStableWalletCreate(t *testing.T) {
 			args := append([]string{"walletCreate", "test-stable-wallet-create"}, tc.args...)
 			output, err := execCommandCombinedOutput(args...)
 			if err != nil {
-				require.EqualError(t, err, "exit status 1")
+				require.EqualError(t, err, "exit status 2")
 				require.Equal(t, tc.errMsg, string(output))
 				return
 			}
@@ -3243,7 +3243,7 @@ func checkWalletEntriesAndLastSeedDeterministic(t *testing.T, w wallet.Wallet) {
 	newSeed, seckeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte(seed), len(entries))
 	require.Len(t, seckeys, len(entries))
 	for i, sk := range seckeys {
-		require.Equal(t, entries[i].Secret, sk)
+		require.Equal(t, entries[i].Secret.SecKey(), sk)
 		pk := cipher.MustPubKeyFromSecKey(sk)
 		require.Equal(t, entries[i].Public, pk)
 	}
@@ -3413,6 +3413,7 @@ func TestEncryptWallet(t *testing.T) {
 		setup       func(t *testing.T) string
 		errMsg      []byte
 		errWithHelp bool
+		exitCode    int
 		checkWallet func(t *testing.T, w wallet.Wallet)
 	}{
 		{
@@ -3433,7 +3434,7 @@ func TestEncryptWallet(t *testing.T) {
 				entries, err := w.GetEntries()
 				require.NoError(t, err)
 				for _, e := range entries {
-					require.Equal(t, cipher.SecKey{}, e.Secret)
+					require.True(t, e.Secret.Null())
 				}
 			},
 		},
@@ -3445,7 +3446,8 @@ func TestEncryptWallet(t *testing.T) {
 				wlt := createTempWallet(t, "test-encrypt-wallet", seed, encryptOption(true), passwordOption([]byte("pwd")))
 				return wlt.Meta.Filename
 			},
-			errMsg: []byte("Error: wallet is encrypted\n"),
+			errMsg:   []byte("Error: wallet is encrypted\n"),
+			exitCode: 1,
 		},
 		{
 			name: "wallet doesn't exist",
@@ -3455,6 +3457,7 @@ func TestEncryptWallet(t *testing.T) {
 			},
 			errWithHelp: true,
 			errMsg:      []byte("400 Bad Request - wallet doesn't exist"),
+			exitCode:    2,
 		},
 	}
 
@@ -3465,7 +3468,7 @@ func TestEncryptWallet(t *testing.T) {
 			args := append([]string{"encryptWallet", walletID}, tc.args[:]...)
 			output, err := execCommandCombinedOutput(args...)
 			if err != nil {
-				require.EqualError(t, err, "exit status 1")
+				require.EqualError(t, err, fmt.Sprintf("exit status %d", tc.exitCode))
 				if tc.errWithHelp {
 					require.True(t, bytes.Contains(output, tc.errMsg), fmt.Sprintf("expect: %s, get: %s", tc.errMsg, string(output)))
 				} else {
@@ -3497,6 +3500,7 @@ func TestDecryptWallet(t *testing.T) {
 		setup       func(t *testing.T) string
 		errMsg      []byte
 		errWithHelp bool
+		exitCode    int
 		checkWallet func(t *testing.T, w wallet.Wallet)
 	}{
 		{
@@ -3528,7 +3532,8 @@ func TestDecryptWallet(t *testing.T) {
 				wlt := createTempWallet(t, "test-decrypt-wallet", seed)
 				return wlt.Meta.Filename
 			},
-			errMsg: []byte("Error: wallet is not encrypted\n"),
+			errMsg:   []byte("Error: wallet is not encrypted\n"),
+			exitCode: 1,
 		},
 		{
 			name: "invalid password",
@@ -3538,7 +3543,8 @@ func TestDecryptWallet(t *testing.T) {
 				wlt := createTempWallet(t, "test-decrypt-wallet", seed, encryptOption(true), passwordOption([]byte("pwd")))
 				return wlt.Meta.Filename
 			},
-			errMsg: []byte("Error: 400 Bad Request - invalid password\n"),
+			errMsg:   []byte("Error: 400 Bad Request - invalid password\n"),
+			exitCode: 2,
 		},
 		{
 			name: "wallet doesn't exist",
@@ -3548,6 +3554,7 @@ func TestDecryptWallet(t *testing.T) {
 			},
 			errWithHelp: true,
 			errMsg:      []byte("400 Bad Request - wallet doesn't exist"),
+			exitCode:    2,
 		},
 	}
 
@@ -3558,7 +3565,7 @@ func TestDecryptWallet(t *testing.T) {
 			args := append([]string{"decryptWallet", walletID}, tc.args...)
 			output, err := execCommandCombinedOutput(args...)
 			if err != nil {
-				require.EqualError(t, err, "exit status 1")
+				require.EqualError(t, err, fmt.Sprintf("exit status %d", tc.exitCode))
 				if tc.errWithHelp {
 					require.True(t, bytes.Contains(output, tc.errMsg), fmt.Sprintf("expect: %s, get: %s", tc.errMsg, string(output)))
 				} else {
@@ -3614,7 +3621,7 @@ func TestWalletShowSeed(t *testing.T) {
 			name:     "unencrypted wallet with -j option",
 			walletID: unencryptedWlt.Meta.Filename,
 			args:     []string{"-j"},
-			errMsg:   []byte("Error: 400 Bad Request - wallet is not encrypted\n"),
+			errMsg:   []byte("{\n    \"error\": {\n        \"code\": 2,\n        \"class\": \"validation\",\n        \"message\": \"400 Bad Request - wallet is not encrypted\"\n    }\n}\n"),
 		},
 		{
 			name:         "encrypted wallet",
@@ -3649,7 +3656,7 @@ func TestWalletShowSeed(t *testing.T) {
 			args := append([]string{"showSeed", tc.walletID}, tc.args...)
 			output, err := execCommandCombinedOutput(args...)
 			if err != nil {
-				require.EqualError(t, err, "exit status 1")
+				require.EqualError(t, err, "exit status 2")
 				if tc.errWithHelp {
 					require.True(t, bytes.Contains(output, tc.errMsg),
 						fmt.Sprintf("expect: %s, get: %v", tc.errMsg, string(output)))
@@ -2,27 +2,44 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
 
 func listAddressesCmd() *cobra.Command {
-	return &cobra.Command{
-		Short:                 "Lists all addresses in a given wallet",
-		Use:                   "listAddresses [wallet]",
-		Args:                  cobra.ExactArgs(1),
-		DisableFlagsInUseLine: true,
-		SilenceUsage:          true,
-		RunE:                  listAddresses,
+	listAddressesCmd := &cobra.Command{
+		Short: "Lists all addresses in a given wallet",
+		Use:   "listAddresses [wallet]",
+		Long: `Lists all addresses in a given wallet.
+
+    With --qr or --qr-png, the addresses are rendered as QR codes instead.
+    The QR codes encode a payment URI if --amount, --hours, --label or --message are set.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         listAddresses,
 	}
+
+	addQRFlags(listAddressesCmd)
+
+	return listAddressesCmd
 }
 
-func listAddresses(_ *cobra.Command, args []string) error {
+func listAddresses(c *cobra.Command, args []string) error {
+	qr, err := getQROptions(c)
+	if err != nil {
+		return err
+	}
+
 	addrs, err := getWalletAddresses(args[0])
 	if err != nil {
 		return err
 	}
 
+	if qr.enabled() {
+		return writeQRCodes(os.Stdout, qr, cliConfig.Coin, addrs)
+	}
+
 	s, err := FormatAddressesAsJSON(addrs)
 	if err != nil {
 		return err
@@ -0,0 +1,495 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/multisig"
+)
+
+func multisigCreateCmd() *cobra.Command {
+	multisigCreateCmd := &cobra.Command{
+		Short: "Create a multisig wallet file from the public keys of the cosigners",
+		Use:   "multisigCreate [wallet file]",
+		Long: `Create a M-of-N multisig wallet file from the public keys of the N cosigners, M is the --threshold.
+    The wallet holds no secret keys. Every cosigner creating the wallet with the same public keys
+    and threshold gets the same wallet, in any order of the public keys.
+
+    The wallet has no receive addresses. The blockchain has no multisig output type,
+    coins sent to a multisig address could never be spent.
+
+    Spending from the wallet is coordinated with partially-signed transaction files:
+    a cosigner creates the file with multisigPropose, the cosigners sign it with multisigSign,
+    and the signed files are merged with multisigCombine.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			pubKeys, err := c.Flags().GetStringSlice("pubkeys")
+			if err != nil {
+				return err
+			}
+			if len(pubKeys) == 0 {
+				return errors.New("missing --pubkeys")
+			}
+
+			threshold, err := c.Flags().GetUint64("threshold")
+			if err != nil {
+				return err
+			}
+
+			label, err := c.Flags().GetString("label")
+			if err != nil {
+				return err
+			}
+
+			w, err := createMultisigWallet(args[0], label, threshold, pubKeys)
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					Wallet    string `json:"wallet"`
+					Threshold uint64 `json:"threshold"`
+					Cosigners int    `json:"cosigners"`
+				}{
+					Wallet:    args[0],
+					Threshold: w.Threshold(),
+					Cosigners: len(w.CosignerPubKeys()),
+				})
+			}
+
+			fmt.Printf("%d-of-%d multisig wallet written to %s\n", w.Threshold(), len(w.CosignerPubKeys()), args[0])
+			return nil
+		},
+	}
+
+	multisigCreateCmd.Flags().StringSlice("pubkeys", nil, "Comma separated public keys of the cosigners")
+	multisigCreateCmd.Flags().Uint64P("threshold", "m", 0, "Number of cosigner signatures required to spend")
+	multisigCreateCmd.Flags().StringP("label", "l", "", "Label used to identify your wallet")
+
+	return multisigCreateCmd
+}
+
+func multisigProposeCmd() *cobra.Command {
+	multisigProposeCmd := &cobra.Command{
+		Short: "Create a partially-signed transaction file from an unsigned raw transaction",
+		Use:   "multisigPropose [wallet file] [raw transaction]",
+		Long: `Create a partially-signed transaction file of an unsigned raw transaction for the cosigners
+    of a multisig wallet file, and write it to the --out file, to be signed by the cosigners with multisigSign.
+    The raw transaction is the hex encoded unsigned transaction, e.g. created with createRawTransactionV2 --unsign.
+
+    The multisig wallet has no addresses, the inputs of the transaction are not checked against the wallet.
+    The cosigners review the transaction printed by multisigSign before signing it.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			out, err := c.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				return errors.New("missing --out transaction file")
+			}
+
+			w, err := loadMultisigWallet(args[0])
+			if err != nil {
+				return err
+			}
+
+			p, err := proposeMultisigTransaction(w, args[1])
+			if err != nil {
+				return err
+			}
+
+			if err := savePartialTransaction(out, p); err != nil {
+				return err
+			}
+
+			return printPartialTransaction(c, out, p, fmt.Sprintf("Partially-signed transaction written to %s", out))
+		},
+	}
+
+	multisigProposeCmd.Flags().String("out", "", "File to write the partially-signed transaction to")
+
+	return multisigProposeCmd
+}
+
+func multisigSignCmd() *cobra.Command {
+	multisigSignCmd := &cobra.Command{
+		Short: "Sign a partially-signed transaction file as a cosigner",
+		Use:   "multisigSign [transaction file]",
+		Long: `Add the signatures of a cosigner to a partially-signed transaction file created by multisigPropose.
+    The keys of the cosigner are the keys of the --wallet file whose public keys are cosigner public keys.
+    No node is needed, the command can be run on an air-gapped machine holding the wallet.
+    The signed transaction is written back to the transaction file, or to the --out file.
+
+    Review the outputs of the transaction printed by the command before passing the file on.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			walletFile, err := c.Flags().GetString("wallet")
+			if err != nil {
+				return err
+			}
+			if walletFile == "" {
+				return errors.New("missing --wallet file")
+			}
+
+			out, err := c.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				out = args[0]
+			}
+
+			p, err := loadPartialTransaction(args[0])
+			if err != nil {
+				return err
+			}
+
+			w, err := wallet.Load(walletFile)
+			if err != nil {
+				return WalletLoadError{err}
+			}
+
+			var password []byte
+			if w.IsEncrypted() {
+				password, err = getPassword(c)
+				if err != nil {
+					return err
+				}
+			}
+
+			n, err := signPartialTransaction(p, w, password)
+			if err != nil {
+				return err
+			}
+
+			if err := savePartialTransaction(out, p); err != nil {
+				return err
+			}
+
+			return printPartialTransaction(c, out, p, fmt.Sprintf("%d signature(s) added, partially-signed transaction written to %s", n, out))
+		},
+	}
+
+	multisigSignCmd.Flags().String("wallet", "", "Wallet file holding the keys of the cosigner")
+	multisigSignCmd.Flags().String("out", "", "File to write the signed transaction to, defaults to the transaction file")
+	multisigSignCmd.Flags().StringP("password", "p", "", "Wallet password")
+
+	return multisigSignCmd
+}
+
+func multisigCombineCmd() *cobra.Command {
+	multisigCombineCmd := &cobra.Command{
+		Short: "Merge the signatures of partially-signed transaction files",
+		Use:   "multisigCombine [transaction file] [transaction file]...",
+		Long: `Merge the signatures of partially-signed transaction files signed by different cosigners
+    and write the result to the --out file. The files must be partially-signed versions of the same transaction.
+    The transaction is complete when every input has the signatures of the threshold number of cosigners.
+
+    A complete transaction can't be broadcast, the blockchain does not accept multisig transactions yet.`,
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			out, err := c.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				return errors.New("missing --out transaction file")
+			}
+
+			p, err := combinePartialTransactionFiles(args)
+			if err != nil {
+				return err
+			}
+
+			if err := savePartialTransaction(out, p); err != nil {
+				return err
+			}
+
+			return printPartialTransaction(c, out, p, fmt.Sprintf("Combined partially-signed transaction written to %s", out))
+		},
+	}
+
+	multisigCombineCmd.Flags().String("out", "", "File to write the combined transaction to")
+
+	return multisigCombineCmd
+}
+
+// createMultisigWallet creates a multisig wallet file. An existing file is not overwritten.
+func createMultisigWallet(filename, label string, threshold uint64, pubKeys []string) (*multisig.Wallet, error) {
+	exists, err := file.Exists(filename)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("wallet file %s already exists", filename)
+	}
+
+	pks := make([]cipher.PubKey, len(pubKeys))
+	for i, s := range pubKeys {
+		pks[i], err = cipher.PubKeyFromHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %v", s, err)
+		}
+	}
+
+	w, err := multisig.NewWallet(filepath.Base(filename), label, threshold, pks)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wallet.Save(w, dir); err != nil {
+		return nil, WalletSaveError{err}
+	}
+
+	return w, nil
+}
+
+func loadMultisigWallet(filename string) (*multisig.Wallet, error) {
+	w, err := wallet.Load(filename)
+	if err != nil {
+		return nil, WalletLoadError{err}
+	}
+
+	mw, ok := w.(*multisig.Wallet)
+	if !ok {
+		return nil, fmt.Errorf("%s is a %q wallet, not a multisig wallet", filename, w.Type())
+	}
+
+	return mw, nil
+}
+
+// proposeMultisigTransaction creates the partially-signed transaction of the hex encoded unsigned transaction rawTxn
+func proposeMultisigTransaction(w *multisig.Wallet, rawTxn string) (*multisig.PartiallySignedTransaction, error) {
+	txn, err := coin.DeserializeTransactionHex(rawTxn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %v", err)
+	}
+
+	if !txn.IsFullyUnsigned() {
+		return nil, errors.New("raw transaction must be unsigned")
+	}
+
+	return w.NewPartiallySignedTransaction(txn)
+}
+
+// signPartialTransaction signs the partially-signed transaction with the keys of the wallet that belong to cosigners.
+// The password is required if the wallet is encrypted. Returns the number of signatures added.
+func signPartialTransaction(p *multisig.PartiallySignedTransaction, w wallet.Wallet, password []byte) (int, error) {
+	var n int
+	var cosigner bool
+	sign := func(w wallet.Wallet) error {
+		entries, err := w.GetEntries()
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			if e.Secret.Null() {
+				continue
+			}
+
+			k, err := p.Sign(e.Secret.SecKey())
+			switch err {
+			case nil:
+				cosigner = true
+				n += k
+			case multisig.ErrNotCosigner:
+			default:
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if w.IsEncrypted() {
+		err = wallet.GuardView(w, password, sign)
+	} else {
+		err = sign(w)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if !cosigner {
+		return 0, multisig.ErrNotCosigner
+	}
+
+	return n, nil
+}
+
+// combinePartialTransactionFiles merges the signatures of the partially-signed transaction files
+func combinePartialTransactionFiles(filenames []string) (*multisig.PartiallySignedTransaction, error) {
+	p, err := loadPartialTransaction(filenames[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fn := range filenames[1:] {
+		other, err := loadPartialTransaction(fn)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.Combine(other); err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+	}
+
+	return p, nil
+}
+
+func loadPartialTransaction(filename string) (*multisig.PartiallySignedTransaction, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := multisig.DeserializePartiallySignedTransaction(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid partially-signed transaction file %s: %v", filename, err)
+	}
+
+	return p, nil
+}
+
+func savePartialTransaction(filename string, p *multisig.PartiallySignedTransaction) error {
+	b, err := p.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return file.SaveBinary(filename, b, 0600)
+}
+
+// PartialTransactionStatus is the --json output of the multisig transaction commands
+type PartialTransactionStatus struct {
+	File      string                           `json:"file"`
+	InnerHash string                           `json:"inner_hash"`
+	Threshold uint64                           `json:"threshold"`
+	Outputs   []PartialTransactionStatusOutput `json:"outputs"`
+	Inputs    []PartialTransactionStatusInput  `json:"inputs"`
+	Complete  bool                             `json:"complete"`
+}
+
+// PartialTransactionStatusOutput is an output of a PartialTransactionStatus
+type PartialTransactionStatusOutput struct {
+	Address string `json:"address"`
+	Coins   string `json:"coins"`
+	Hours   uint64 `json:"hours"`
+}
+
+// PartialTransactionStatusInput is an input of a PartialTransactionStatus
+type PartialTransactionStatusInput struct {
+	Hash       string `json:"hash"`
+	Signatures int    `json:"signatures"`
+}
+
+func newPartialTransactionStatus(filename string, p *multisig.PartiallySignedTransaction) (*PartialTransactionStatus, error) {
+	s := &PartialTransactionStatus{
+		File:      filename,
+		InnerHash: p.Transaction.InnerHash.Hex(),
+		Threshold: p.Threshold,
+		Outputs:   make([]PartialTransactionStatusOutput, len(p.Transaction.Out)),
+		Inputs:    make([]PartialTransactionStatusInput, len(p.Inputs)),
+		Complete:  p.IsComplete(),
+	}
+
+	for i, o := range p.Transaction.Out {
+		coins, err := droplet.ToString(o.Coins)
+		if err != nil {
+			return nil, err
+		}
+		s.Outputs[i] = PartialTransactionStatusOutput{
+			Address: o.Address.String(),
+			Coins:   coins,
+			Hours:   o.Hours,
+		}
+	}
+
+	for i, h := range p.Transaction.In {
+		s.Inputs[i] = PartialTransactionStatusInput{
+			Hash:       h.Hex(),
+			Signatures: p.SignatureCount(i),
+		}
+	}
+
+	return s, nil
+}
+
+// printPartialTransaction prints the status of the transaction written to filename,
+// as a PartialTransactionStatus with --json or after text otherwise
+func printPartialTransaction(c *cobra.Command, filename string, p *multisig.PartiallySignedTransaction, text string) error {
+	jsonOutput, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		s, err := newPartialTransactionStatus(filename, p)
+		if err != nil {
+			return err
+		}
+		return printJSON(s)
+	}
+
+	fmt.Println(text)
+	return printPartialTransactionStatus(os.Stdout, p)
+}
+
+// printPartialTransactionStatus prints the outputs of the transaction and the signatures collected for each input
+func printPartialTransactionStatus(w io.Writer, p *multisig.PartiallySignedTransaction) error {
+	fmt.Fprintf(w, "Inner hash:   %s\n", p.Transaction.InnerHash.Hex())
+	fmt.Fprintf(w, "Outputs:      %d\n", len(p.Transaction.Out))
+	for i, o := range p.Transaction.Out {
+		coins, err := droplet.ToString(o.Coins)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  %d. %s coins:%s hours:%d\n", i+1, o.Address, coins, o.Hours)
+	}
+
+	fmt.Fprintf(w, "Inputs:       %d\n", len(p.Inputs))
+	for i, h := range p.Transaction.In {
+		fmt.Fprintf(w, "  %d. %s signatures:%d/%d\n", i+1, h.Hex(), p.SignatureCount(i), p.Threshold)
+	}
+
+	if p.IsComplete() {
+		fmt.Fprintln(w, "Complete:     yes, but it can't be broadcast, the blockchain does not accept multisig transactions yet")
+	} else {
+		fmt.Fprintln(w, "Complete:     no")
+	}
+	return nil
+}
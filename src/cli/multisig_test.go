@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/deterministic"
+	"github.com/skycoin/skycoin/src/wallet/multisig"
+)
+
+func TestMultisigCoordination(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-multisig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The wallets of the 3 cosigners
+	var cosigners []wallet.Wallet
+	var pubKeys []string
+	for i := 0; i < 3; i++ {
+		w, err := deterministic.NewWallet(fmt.Sprintf("cosigner%d.wlt", i), "cosigner", fmt.Sprintf("cosignerseed%d", i), wallet.OptionGenerateN(1))
+		require.NoError(t, err)
+		e, err := w.GetEntryAt(0)
+		require.NoError(t, err)
+		cosigners = append(cosigners, w)
+		pubKeys = append(pubKeys, e.Public.Hex())
+	}
+
+	walletFile := filepath.Join(dir, "multisig.wlt")
+	_, err = createMultisigWallet(walletFile, "multisig", 4, pubKeys)
+	require.Equal(t, multisig.ErrInvalidThreshold, err)
+
+	_, err = createMultisigWallet(walletFile, "multisig", 2, []string{"foo"})
+	require.Error(t, err)
+
+	_, err = createMultisigWallet(walletFile, "multisig", 2, pubKeys)
+	require.NoError(t, err)
+
+	_, err = createMultisigWallet(walletFile, "multisig", 2, pubKeys)
+	require.EqualError(t, err, fmt.Sprintf("wallet file %s already exists", walletFile))
+
+	// The wallet has no addresses
+	w, err := loadMultisigWallet(walletFile)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), w.Threshold())
+	n, err := w.EntriesLen()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// An unsigned transaction spending two outputs
+	to := testutil.MakeAddress()
+	var txn coin.Transaction
+	for i := 0; i < 2; i++ {
+		require.NoError(t, txn.PushInput(testutil.RandSHA256(t)))
+	}
+	require.NoError(t, txn.PushOutput(to, 15e6, 50))
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 5e6, 50))
+	require.NoError(t, txn.UpdateHeader())
+	rawTxn, err := txn.SerializeHex()
+	require.NoError(t, err)
+
+	_, err = proposeMultisigTransaction(w, "foo")
+	require.Error(t, err)
+
+	signedTxn := txn
+	signedTxn.Sigs = []cipher.Sig{testutil.RandSig(t), {}}
+	signedRawTxn, err := signedTxn.SerializeHex()
+	require.NoError(t, err)
+	_, err = proposeMultisigTransaction(w, signedRawTxn)
+	require.EqualError(t, err, "raw transaction must be unsigned")
+
+	p, err := proposeMultisigTransaction(w, rawTxn)
+	require.NoError(t, err)
+	require.Len(t, p.Inputs, 2)
+	require.Equal(t, txn.Hash(), p.Transaction.Hash())
+	require.False(t, p.IsComplete())
+
+	proposed := filepath.Join(dir, "proposed.json")
+	require.NoError(t, savePartialTransaction(proposed, p))
+
+	// A wallet that is not a cosigner can't sign
+	other, err := deterministic.NewWallet("other.wlt", "other", "otherseed", wallet.OptionGenerateN(1))
+	require.NoError(t, err)
+	_, err = signPartialTransaction(p, other, nil)
+	require.Equal(t, multisig.ErrNotCosigner, err)
+
+	// The first and third cosigners sign copies of the proposed transaction
+	var signed []string
+	for _, i := range []int{0, 2} {
+		p, err := loadPartialTransaction(proposed)
+		require.NoError(t, err)
+
+		n, err := signPartialTransaction(p, cosigners[i], nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		require.False(t, p.IsComplete())
+
+		fn := filepath.Join(dir, fmt.Sprintf("signed%d.json", i))
+		require.NoError(t, savePartialTransaction(fn, p))
+		signed = append(signed, fn)
+	}
+
+	p, err = combinePartialTransactionFiles(signed)
+	require.NoError(t, err)
+	require.True(t, p.IsComplete())
+	require.NoError(t, p.Verify())
+
+	var out bytes.Buffer
+	require.NoError(t, printPartialTransactionStatus(&out, p))
+	require.Equal(t, fmt.Sprintf(`Inner hash:   %s
+Outputs:      2
+  1. %s coins:15.000000 hours:%d
+  2. %s coins:5.000000 hours:%d
+Inputs:       2
+  1. %s signatures:2/2
+  2. %s signatures:2/2
+Complete:     yes, but it can't be broadcast, the blockchain does not accept multisig transactions yet
+`, p.Transaction.InnerHash.Hex(), to, p.Transaction.Out[0].Hours, txn.Out[1].Address, p.Transaction.Out[1].Hours, txn.In[0].Hex(), txn.In[1].Hex()), out.String())
+
+	// Combining with another transaction fails
+	txn.Out[0].Coins = 1e6
+	require.NoError(t, txn.UpdateHeader())
+	rawTxn, err = txn.SerializeHex()
+	require.NoError(t, err)
+	p2, err := proposeMultisigTransaction(w, rawTxn)
+	require.NoError(t, err)
+	otherFile := filepath.Join(dir, "other.json")
+	require.NoError(t, savePartialTransaction(otherFile, p2))
+	_, err = combinePartialTransactionFiles([]string{proposed, otherFile})
+	require.Error(t, err)
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/readable"
+)
+
+func nodeCmd() *cobra.Command {
+	nodeCmd := &cobra.Command{
+		Short: "Manage the node",
+		Use:   "node",
+		Long: `Manage the node the cli is connected to, at RPC_ADDR.
+
+    The peers, ban, unban and loglevel commands use the admin API, which is only
+    enabled by the -enable-admin-api option of the node. The admin API requires
+    the username and password of the node, read from RPC_USER and RPC_PASS.`,
+		Args: cobra.NoArgs,
+	}
+
+	nodeCmd.AddCommand(
+		nodeStatusCmd(),
+		nodePeersCmd(),
+		nodeBanCmd(),
+		nodeUnbanCmd(),
+		nodeLogLevelCmd(),
+	)
+
+	return nodeCmd
+}
+
+func nodeStatusCmd() *cobra.Command {
+	nodeStatusCmd := &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "Show the version, blockchain head, connections and uptime of the node",
+		Use:          "status",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			status, err := apiClient.Health()
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(status)
+			}
+
+			printNodeStatus(os.Stdout, status)
+			return nil
+		},
+	}
+
+	nodeStatusCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return nodeStatusCmd
+}
+
+func nodePeersCmd() *cobra.Command {
+	nodePeersCmd := &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "List the connections of the node, or the banned peers",
+		Use:          "peers",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			banned, err := c.Flags().GetBool("banned")
+			if err != nil {
+				return err
+			}
+
+			if banned {
+				peers, err := apiClient.BannedPeers()
+				if err != nil {
+					return err
+				}
+
+				if jsonOutput {
+					return printJSON(peers)
+				}
+
+				return printBannedPeers(os.Stdout, peers)
+			}
+
+			conns, err := apiClient.NetworkConnections(nil)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(conns)
+			}
+
+			return printPeers(os.Stdout, conns.Connections)
+		},
+	}
+
+	nodePeersCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+	nodePeersCmd.Flags().Bool("banned", false, "List the banned peers instead of the connections")
+
+	return nodePeersCmd
+}
+
+func nodeBanCmd() *cobra.Command {
+	nodeBanCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Ban an IP address and disconnect its connections",
+		Use:   "ban [ip]",
+		Long: `Ban an IP address until the node is restarted. The connections to the IP address
+    are disconnected and new connections with it are refused.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			r, err := apiClient.BanPeer(args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(r)
+			}
+
+			fmt.Printf("Banned %s, %d connections disconnected\n", r.IP, r.Disconnected)
+			return nil
+		},
+	}
+
+	nodeBanCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return nodeBanCmd
+}
+
+func nodeUnbanCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Short:        "Lift the ban of an IP address",
+		Use:          "unban [ip]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := apiClient.UnbanPeer(args[0]); err != nil {
+				return err
+			}
+
+			return printSuccess(c, fmt.Sprintf("Unbanned %s", args[0]))
+		},
+	}
+}
+
+func nodeLogLevelCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Show or set the log level of the node",
+		Use:   "loglevel [level]",
+		Long: `Show the log level of the node, or set it to [level] until the node is restarted.
+    The level is one of debug, info, warn, error, fatal or panic.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			var level string
+			var err error
+			if len(args) == 0 {
+				level, err = apiClient.LogLevel()
+			} else {
+				level, err = apiClient.SetLogLevel(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					Level string `json:"level"`
+				}{
+					Level: level,
+				})
+			}
+
+			fmt.Println(level)
+			return nil
+		},
+	}
+}
+
+func printNodeStatus(w io.Writer, s *api.HealthResponse) {
+	head := s.BlockchainMetadata.Head
+
+	fmt.Fprintf(w, "Coin:         %s\n", s.CoinName)
+	fmt.Fprintf(w, "Version:      %s\n", s.Version.Version)
+	fmt.Fprintf(w, "User agent:   %s\n", s.DaemonUserAgent)
+	fmt.Fprintf(w, "Head block:   %d %s\n", head.BkSeq, time.Unix(int64(head.Time), 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Last block:   %s ago\n", s.BlockchainMetadata.TimeSinceLastBlock.Duration)
+	fmt.Fprintf(w, "Unconfirmed:  %d\n", s.BlockchainMetadata.Unconfirmed)
+	fmt.Fprintf(w, "Connections:  %d (%d outgoing, %d incoming)\n", s.OpenConnections, s.OutgoingConnections, s.IncomingConnections)
+	fmt.Fprintf(w, "Uptime:       %s\n", s.Uptime.Duration)
+}
+
+func printPeers(w io.Writer, conns []readable.Connection) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tDIRECTION\tSTATE\tHEIGHT\tUSER AGENT")
+	for _, c := range conns {
+		direction := "incoming"
+		if c.Outgoing {
+			direction = "outgoing"
+		}
+
+		userAgent := "-"
+		if !c.UserAgent.Empty() {
+			if ua, err := c.UserAgent.Build(); err == nil {
+				userAgent = ua
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", c.Addr, direction, c.State, c.Height, userAgent)
+	}
+	return tw.Flush()
+}
+
+func printBannedPeers(w io.Writer, peers []api.BannedPeer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "IP\tBANNED AT")
+	for _, p := range peers {
+		fmt.Fprintf(tw, "%s\t%s\n", p.IP, time.Unix(p.BannedAt, 0).UTC().Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/readable"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/util/useragent"
+)
+
+func TestPrintNodeStatus(t *testing.T) {
+	var s api.HealthResponse
+	s.CoinName = "skycoin"
+	s.Version.Version = "0.27.0"
+	s.DaemonUserAgent = "skycoin:0.27.0"
+	s.BlockchainMetadata.Head.BkSeq = 100
+	s.BlockchainMetadata.Head.Time = 1571109310
+	s.BlockchainMetadata.Unconfirmed = 3
+	s.BlockchainMetadata.TimeSinceLastBlock = wh.FromDuration(90 * time.Second)
+	s.OpenConnections = 5
+	s.OutgoingConnections = 4
+	s.IncomingConnections = 1
+	s.Uptime = wh.FromDuration(2 * time.Hour)
+
+	var out bytes.Buffer
+	printNodeStatus(&out, &s)
+	require.Equal(t, `Coin:         skycoin
+Version:      0.27.0
+User agent:   skycoin:0.27.0
+Head block:   100 2019-10-15T03:15:10Z
+Last block:   1m30s ago
+Unconfirmed:  3
+Connections:  5 (4 outgoing, 1 incoming)
+Uptime:       2h0m0s
+`, out.String())
+}
+
+func TestPrintPeers(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printPeers(&out, []readable.Connection{
+		{
+			Addr:     "10.0.0.1:6000",
+			Outgoing: true,
+			State:    daemon.ConnectionStateIntroduced,
+			Height:   100,
+			UserAgent: useragent.Data{
+				Coin:    "skycoin",
+				Version: "0.27.0",
+			},
+		},
+		{
+			Addr:  "10.0.0.2:41234",
+			State: daemon.ConnectionStateConnected,
+		},
+	}))
+	require.Equal(t, `ADDRESS         DIRECTION  STATE       HEIGHT  USER AGENT
+10.0.0.1:6000   outgoing   introduced  100     skycoin:0.27.0
+10.0.0.2:41234  incoming   connected   0       -
+`, out.String())
+}
+
+func TestPrintBannedPeers(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printBannedPeers(&out, []api.BannedPeer{
+		{
+			IP:       "10.0.0.1",
+			BannedAt: 1571109310,
+		},
+		{
+			IP:       "2001:db8::1",
+			BannedAt: 1571109370,
+		},
+	}))
+	require.Equal(t, `IP           BANNED AT
+10.0.0.1     2019-10-15T03:15:10Z
+2001:db8::1  2019-10-15T03:16:10Z
+`, out.String())
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// ErrTransactionNotFullySigned is returned when broadcasting a transaction file that is not fully signed
+var ErrTransactionNotFullySigned = errors.New("transaction is not fully signed")
+
+func createUnsignedTxnCmd() *cobra.Command {
+	createUnsignedTxnCmd := &cobra.Command{
+		Short: "Create an unsigned transaction file to sign on an offline machine",
+		Use:   "createUnsignedTransaction [wallet] [to address] [amount]",
+		Long: `Create an unsigned transaction from a wallet of the node and write it to the --out file.
+    The file has the transaction and the outputs it spends, so that it can be signed on an
+    air-gapped machine with signTransactionOffline, and broadcast with broadcastSigned.
+    The wallet of the node can be a watch-only wallet, e.g. an xpub wallet.
+
+    Note: The [amount] argument is the coins you will spend, with decimal formatting, e.g. 1, 1.001 or 1.000000.
+
+    The [to address] and [amount] arguments can be replaced with the --csv option.
+    The [to address] can be the name of a contact of the address book.`,
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			out, err := c.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				return errors.New("missing --out transaction file")
+			}
+
+			req, err := makeWalletCreateTxnRequestTo(c, args[0], true, func() ([]api.Receiver, error) {
+				return getToAddressesV2(c, args[1:])
+			})
+			if err != nil {
+				return err
+			}
+
+			rsp, err := apiClient.WalletCreateTransaction(*req)
+			if err != nil {
+				return err
+			}
+
+			if err := file.SaveJSON(out, rsp, 0600); err != nil {
+				return err
+			}
+
+			return printTransactionFile(c, "Unsigned", rsp, out)
+		},
+	}
+
+	createUnsignedTxnCmd.Flags().String("out", "", "File to write the unsigned transaction to")
+	createUnsignedTxnCmd.Flags().StringP("from-address", "a", "", "From address in wallet")
+	createUnsignedTxnCmd.Flags().StringP("change-address", "c", "", `Specify the change address.
+Defaults to one of the spending addresses (deterministic wallets) or to a new change address (bip44 wallets).`)
+	createUnsignedTxnCmd.Flags().String("csv", "", "CSV file containing addresses and amounts to send")
+
+	createUnsignedTxnCmd.Flags().Bool("ignore-unconfirmed", false, "Ignore unconfirmed transactions")
+	createUnsignedTxnCmd.Flags().String("hours-selection-type", transaction.HoursSelectionTypeAuto, "Hours selection type")
+	createUnsignedTxnCmd.Flags().String("hours-selection-mode", transaction.HoursSelectionModeShare, "Hours selection mode")
+	createUnsignedTxnCmd.Flags().String("hours-selection-share-factor", "0.5", "Hour selection share factor")
+
+	return createUnsignedTxnCmd
+}
+
+func signTxnOfflineCmd() *cobra.Command {
+	signTxnOfflineCmd := &cobra.Command{
+		Short: "Sign a transaction file with a wallet file, without a node",
+		Use:   "signTransactionOffline [transaction file]",
+		Long: `Sign a transaction file created by createUnsignedTransaction with the --wallet file.
+    No node is needed, the command is meant to be run on an air-gapped machine holding the wallet.
+    The signed transaction is written back to the transaction file, or to the --out file.
+
+    All unsigned inputs are signed, unless the inputs to sign are set with --index.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			walletFile, err := c.Flags().GetString("wallet")
+			if err != nil {
+				return err
+			}
+			if walletFile == "" {
+				return errors.New("missing --wallet file")
+			}
+
+			out, err := c.Flags().GetString("out")
+			if err != nil {
+				return err
+			}
+			if out == "" {
+				out = args[0]
+			}
+
+			signIndexes, err := c.Flags().GetIntSlice("index")
+			if err != nil {
+				return err
+			}
+
+			w, err := wallet.Load(walletFile)
+			if err != nil {
+				return WalletLoadError{err}
+			}
+
+			var password []byte
+			if w.IsEncrypted() {
+				password, err = getPassword(c)
+				if err != nil {
+					return err
+				}
+			}
+
+			rsp, err := signOfflineTransactionFile(args[0], w, password, signIndexes)
+			if err != nil {
+				return err
+			}
+
+			if err := file.SaveJSON(out, rsp, 0600); err != nil {
+				return err
+			}
+
+			return printTransactionFile(c, "Signed", rsp, out)
+		},
+	}
+
+	signTxnOfflineCmd.Flags().String("wallet", "", "Wallet file to sign with")
+	signTxnOfflineCmd.Flags().String("out", "", "File to write the signed transaction to, defaults to the transaction file")
+	signTxnOfflineCmd.Flags().IntSlice("index", nil, "Indexes of the transaction inputs to sign, all unsigned inputs if not set")
+	signTxnOfflineCmd.Flags().StringP("password", "p", "", "Wallet password")
+
+	return signTxnOfflineCmd
+}
+
+func broadcastSignedCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Broadcast a signed transaction file to the network",
+		Use:   "broadcastSigned [transaction file]",
+		Long: `Broadcast a transaction file signed by signTransactionOffline to the network.
+    The transaction must be fully signed.`,
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(c *cobra.Command, args []string) error {
+			rsp, txn, _, err := loadOfflineTransaction(args[0])
+			if err != nil {
+				return err
+			}
+
+			if !txn.IsFullySigned() {
+				return ErrTransactionNotFullySigned
+			}
+
+			txid, err := apiClient.InjectEncodedTransaction(rsp.EncodedTransaction)
+			if err != nil {
+				return err
+			}
+
+			return printTxid(c, txid)
+		},
+	}
+}
+
+// signOfflineTransactionFile signs the transaction of the file with the wallet, without a node.
+// The password is required if the wallet is encrypted.
+func signOfflineTransactionFile(filename string, w wallet.Wallet, password []byte, signIndexes []int) (*api.CreateTransactionResponse, error) {
+	_, txn, inputs, err := loadOfflineTransaction(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	uxOuts := make([]coin.UxOut, len(inputs))
+	for i, in := range inputs {
+		uxOuts[i] = in.UxOut
+	}
+
+	var signedTxn *coin.Transaction
+	sign := func(w wallet.Wallet) error {
+		var err error
+		signedTxn, err = wallet.SignTransaction(w, txn, signIndexes, uxOuts)
+		return err
+	}
+
+	if w.IsEncrypted() {
+		err = wallet.GuardView(w, password, sign)
+	} else {
+		err = sign(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return api.NewCreateTransactionResponse(signedTxn, inputs)
+}
+
+// loadOfflineTransaction reads a transaction file written by createUnsignedTransaction or signTransactionOffline.
+// The transaction and its inputs in the file must match the encoded transaction.
+func loadOfflineTransaction(filename string) (*api.CreateTransactionResponse, *coin.Transaction, []visor.TransactionInput, error) {
+	var rsp api.CreateTransactionResponse
+	if err := file.LoadJSON(filename, &rsp); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid transaction file: %v", err)
+	}
+
+	txn, err := coin.DeserializeTransactionHex(rsp.EncodedTransaction)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid encoded transaction: %v", err)
+	}
+
+	if txn.Hash().Hex() != rsp.Transaction.TxID || txn.InnerHash.Hex() != rsp.Transaction.InnerHash {
+		return nil, nil, nil, errors.New("transaction does not match the encoded transaction")
+	}
+
+	if len(rsp.Transaction.In) != len(txn.In) {
+		return nil, nil, nil, errors.New("transaction inputs do not match the encoded transaction")
+	}
+
+	inputs := make([]visor.TransactionInput, len(txn.In))
+	for i, in := range rsp.Transaction.In {
+		inputs[i], err = offlineTransactionInput(in)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid transaction input %d: %v", i, err)
+		}
+
+		if inputs[i].UxOut.Hash() != txn.In[i] {
+			return nil, nil, nil, fmt.Errorf("transaction input %d does not match the encoded transaction", i)
+		}
+	}
+
+	return &rsp, &txn, inputs, nil
+}
+
+func offlineTransactionInput(in api.CreatedTransactionInput) (visor.TransactionInput, error) {
+	addr, err := cipher.DecodeBase58Address(in.Address)
+	if err != nil {
+		return visor.TransactionInput{}, err
+	}
+
+	srcTx, err := cipher.SHA256FromHex(in.TxID)
+	if err != nil {
+		return visor.TransactionInput{}, err
+	}
+
+	coins, err := droplet.FromString(in.Coins)
+	if err != nil {
+		return visor.TransactionInput{}, err
+	}
+
+	hours, err := strconv.ParseUint(in.Hours, 10, 64)
+	if err != nil {
+		return visor.TransactionInput{}, err
+	}
+
+	calculatedHours, err := strconv.ParseUint(in.CalculatedHours, 10, 64)
+	if err != nil {
+		return visor.TransactionInput{}, err
+	}
+
+	return visor.TransactionInput{
+		UxOut: coin.UxOut{
+			Head: coin.UxHead{
+				Time:  in.Time,
+				BkSeq: in.Block,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: srcTx,
+				Address:        addr,
+				Coins:          coins,
+				Hours:          hours,
+			},
+		},
+		CalculatedHours: calculatedHours,
+	}, nil
+}
+
+// printTransactionFile prints the transaction written to the file out, with the transaction id and file
+// as JSON if the command was run with --json
+func printTransactionFile(c *cobra.Command, state string, rsp *api.CreateTransactionResponse, out string) error {
+	jsonOutput, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			Txid string `json:"txid"`
+			File string `json:"file"`
+		}{
+			Txid: rsp.Transaction.TxID,
+			File: out,
+		})
+	}
+
+	fmt.Printf("%s transaction %s written to %s\n", state, rsp.Transaction.TxID, out)
+	return nil
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+	"github.com/skycoin/skycoin/src/wallet/deterministic"
+)
+
+// makeOfflineTransaction writes the unsigned transaction file of a transaction spending an output of each address of w
+func makeOfflineTransaction(t *testing.T, w wallet.Wallet, filename string) *api.CreateTransactionResponse {
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+
+	var inputs []visor.TransactionInput
+	txn := &coin.Transaction{}
+	for i, a := range addrs {
+		ux := coin.UxOut{
+			Head: coin.UxHead{
+				Time:  1539849621,
+				BkSeq: uint64(i + 1),
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        a.(cipher.Address),
+				Coins:          1e6,
+				Hours:          100,
+			},
+		}
+		inputs = append(inputs, visor.TransactionInput{
+			UxOut:           ux,
+			CalculatedHours: 110,
+		})
+		require.NoError(t, txn.PushInput(ux.Hash()))
+	}
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), uint64(len(addrs))*1e6, 50))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+
+	rsp, err := api.NewCreateTransactionResponse(txn, inputs)
+	require.NoError(t, err)
+	require.NoError(t, file.SaveJSON(filename, rsp, 0600))
+
+	return rsp
+}
+
+func TestSignOfflineTransactionFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-offline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "tx.json")
+
+	password := []byte("pwd")
+	w, err := deterministic.NewWallet("test.wlt", "test", "testseed123",
+		wallet.OptionGenerateN(2),
+		wallet.OptionEncrypt(true),
+		wallet.OptionPassword(password),
+		wallet.OptionCryptoType(crypto.CryptoTypeScryptChacha20poly1305Insecure))
+	require.NoError(t, err)
+
+	unsigned := makeOfflineTransaction(t, w, filename)
+
+	_, err = signOfflineTransactionFile(filename, w, nil, nil)
+	require.Equal(t, wallet.ErrMissingPassword, err)
+
+	_, err = signOfflineTransactionFile(filename, w, []byte("foo"), nil)
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	// Sign the second input only
+	rsp, err := signOfflineTransactionFile(filename, w, password, []int{1})
+	require.NoError(t, err)
+	require.Equal(t, unsigned.Transaction.In, rsp.Transaction.In)
+	require.Equal(t, unsigned.Transaction.Fee, rsp.Transaction.Fee)
+	require.NotEqual(t, unsigned.Transaction.TxID, rsp.Transaction.TxID)
+	require.NoError(t, file.SaveJSON(filename, rsp, 0600))
+
+	_, txn, _, err := loadOfflineTransaction(filename)
+	require.NoError(t, err)
+	require.False(t, txn.IsFullySigned())
+
+	rsp, err = signOfflineTransactionFile(filename, w, password, nil)
+	require.NoError(t, err)
+	require.NoError(t, file.SaveJSON(filename, rsp, 0600))
+
+	_, txn, _, err = loadOfflineTransaction(filename)
+	require.NoError(t, err)
+	require.True(t, txn.IsFullySigned())
+	require.NoError(t, txn.Verify())
+
+	// A wallet without the addresses of the inputs can't sign
+	w2, err := deterministic.NewWallet("test2.wlt", "test", "anotherseed123", wallet.OptionGenerateN(2))
+	require.NoError(t, err)
+	makeOfflineTransaction(t, w, filename)
+	_, err = signOfflineTransactionFile(filename, w2, nil, nil)
+	require.Error(t, err)
+}
+
+func TestLoadOfflineTransaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-offline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "tx.json")
+
+	w, err := deterministic.NewWallet("test.wlt", "test", "testseed123", wallet.OptionGenerateN(2))
+	require.NoError(t, err)
+
+	tt := []struct {
+		name   string
+		modify func(rsp *api.CreateTransactionResponse)
+		err    string
+	}{
+		{
+			name:   "valid",
+			modify: func(rsp *api.CreateTransactionResponse) {},
+		},
+		{
+			name: "invalid encoded transaction",
+			modify: func(rsp *api.CreateTransactionResponse) {
+				rsp.EncodedTransaction = "foo"
+			},
+			err: "invalid encoded transaction: encoding/hex: invalid byte: U+006F 'o'",
+		},
+		{
+			name: "txid mismatch",
+			modify: func(rsp *api.CreateTransactionResponse) {
+				rsp.Transaction.TxID = testutil.RandSHA256(t).Hex()
+			},
+			err: "transaction does not match the encoded transaction",
+		},
+		{
+			name: "missing input",
+			modify: func(rsp *api.CreateTransactionResponse) {
+				rsp.Transaction.In = rsp.Transaction.In[:1]
+			},
+			err: "transaction inputs do not match the encoded transaction",
+		},
+		{
+			name: "invalid input",
+			modify: func(rsp *api.CreateTransactionResponse) {
+				rsp.Transaction.In[1].Coins = "foo"
+			},
+			err: "invalid transaction input 1: can't convert foo to decimal",
+		},
+		{
+			name: "input mismatch",
+			modify: func(rsp *api.CreateTransactionResponse) {
+				rsp.Transaction.In[0].Address = rsp.Transaction.In[1].Address
+			},
+			err: "transaction input 0 does not match the encoded transaction",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rsp := makeOfflineTransaction(t, w, filename)
+			tc.modify(rsp)
+			require.NoError(t, file.SaveJSON(filename, rsp, 0600))
+
+			_, txn, inputs, err := loadOfflineTransaction(filename)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, rsp.EncodedTransaction, txn.MustSerializeHex())
+			require.Len(t, inputs, 2)
+			require.Equal(t, uint64(110), inputs[0].CalculatedHours)
+		})
+	}
+
+	_, _, _, err = loadOfflineTransaction(filepath.Join(dir, "foo.json"))
+	require.Error(t, err)
+}
@@ -61,7 +61,7 @@ func getAddressOutputsCmd(_ *cobra.Command, args []string) error {
 	for i := 0; i < len(args); i++ {
 		addrs[i] = args[i]
 		if _, err = cipher.DecodeBase58Address(addrs[i]); err != nil {
-			return fmt.Errorf("invalid address: %v, err: %v", addrs[i], err)
+			return ValidationError{fmt.Errorf("invalid address: %v, err: %v", addrs[i], err)}
 		}
 	}
 
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"syscall"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// ErrPaperWalletUnencryptedFile is returned when writing a paper wallet with unencrypted secret keys to a file
+var ErrPaperWalletUnencryptedFile = errors.New("the secret keys are not encrypted, use --bip38 to write the paper wallet to a file")
+
+// PaperWalletKey is a key pair of a paper wallet
+type PaperWalletKey struct {
+	Address string `json:"address"`
+	// Secret is the hex encoded secret key, or the BIP38 encrypted secret key if Encrypted is true
+	Secret    string `json:"secret"`
+	Encrypted bool   `json:"encrypted"`
+}
+
+// paperWalletTemplate is the printable page of the paper wallet, one card per key pair
+var paperWalletTemplate = template.Must(template.New("paperWallet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 1cm; }
+.card { display: flex; justify-content: space-between; border: 1px dashed #000; padding: 0.5cm; margin-bottom: 1cm; page-break-inside: avoid; }
+.side { width: 48%; text-align: center; }
+.side img { width: 5cm; height: 5cm; }
+.key { font-family: monospace; font-size: 9pt; word-break: break-all; }
+.note { font-size: 8pt; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Keys}}<div class="card">
+<div class="side">
+<h2>Address</h2>
+<img src="{{.AddressQR}}" alt="address QR code">
+<p class="key">{{.Address}}</p>
+<p class="note">Share to receive coins</p>
+</div>
+<div class="side">
+<h2>{{if .Encrypted}}BIP38 encrypted secret key{{else}}Secret key{{end}}</h2>
+<img src="{{.SecretQR}}" alt="secret key QR code">
+<p class="key">{{.Secret}}</p>
+<p class="note">{{if .Encrypted}}Keep secret, the passphrase is required to spend{{else}}Keep secret, anyone with the secret key can spend{{end}}</p>
+</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+func paperWalletCmd() *cobra.Command {
+	paperWalletCmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Short: "Generate a paper wallet",
+		Use:   "paperWallet",
+		Long: `Generate random key pairs and render their addresses and secret keys as QR codes
+    in an HTML page to print. Open the page in a browser to print it or to save it as PDF.
+
+    The secret keys are encrypted with a passphrase in the BIP38 format if "--bip38" is set,
+    the passphrase is read from the terminal if "--passphrase" is not given.
+
+    The page is written to stdout, the QR codes are embedded in the page. The unencrypted
+    secret keys are never written to disk: "-o" requires "--bip38".
+    With --json the key pairs are written in JSON format instead of the page.
+
+    Use caution when using the "--passphrase" command. If you have command history enabled
+    your BIP38 passphrase can be recovered from the history log.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			num, err := c.Flags().GetInt("num")
+			if err != nil {
+				return err
+			}
+
+			bip38, err := c.Flags().GetBool("bip38")
+			if err != nil {
+				return err
+			}
+
+			passphrase, err := c.Flags().GetString("passphrase")
+			if err != nil {
+				return err
+			}
+
+			output, err := c.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+
+			title, err := c.Flags().GetString("title")
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			if num < 1 {
+				return errors.New("num must be > 0")
+			}
+			if passphrase != "" && !bip38 {
+				return errors.New("--passphrase requires --bip38")
+			}
+			if output != "" && !bip38 {
+				return ErrPaperWalletUnencryptedFile
+			}
+
+			var p []byte
+			if bip38 {
+				if passphrase != "" {
+					p = []byte(passphrase)
+				} else if p, err = readBIP38Passphrase(); err != nil {
+					return err
+				}
+			}
+
+			keys, err := newPaperWalletKeys(num, p)
+			if err != nil {
+				return err
+			}
+
+			if title == "" {
+				title = fmt.Sprintf("%s paper wallet", cliConfig.Coin)
+			}
+
+			render := func(w io.Writer) error {
+				return renderPaperWallet(w, title, keys)
+			}
+			if jsonOutput {
+				render = func(w io.Writer) error {
+					return writePaperWalletJSON(w, keys)
+				}
+			}
+
+			if output == "" {
+				return render(os.Stdout)
+			}
+
+			f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			if err := render(f); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		},
+	}
+
+	paperWalletCmd.Flags().IntP("num", "n", 1, "Number of key pairs to generate")
+	paperWalletCmd.Flags().Bool("bip38", false, "Encrypt the secret keys with a passphrase in the BIP38 format")
+	paperWalletCmd.Flags().String("passphrase", "", "BIP38 passphrase")
+	paperWalletCmd.Flags().StringP("output", "o", "", "Write the page to a file, requires --bip38")
+	paperWalletCmd.Flags().String("title", "", `Title of the page, defaults to "<coin> paper wallet"`)
+
+	return paperWalletCmd
+}
+
+// readBIP38Passphrase reads the BIP38 passphrase from the terminal, twice to avoid printing keys
+// encrypted with a mistyped passphrase
+func readBIP38Passphrase() ([]byte, error) {
+	fmt.Fprint(os.Stderr, "enter BIP38 passphrase:")
+	p, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "")
+
+	if len(p) == 0 {
+		return nil, cipher.ErrBIP38EmptyPassphrase
+	}
+
+	fmt.Fprint(os.Stderr, "confirm BIP38 passphrase:")
+	confirm, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "")
+
+	if string(p) != string(confirm) {
+		return nil, errors.New("the passphrases do not match")
+	}
+	return p, nil
+}
+
+// newPaperWalletKeys generates n key pairs, the secret keys are BIP38 encrypted with the passphrase if it is not empty
+func newPaperWalletKeys(n int, passphrase []byte) ([]PaperWalletKey, error) {
+	keys := make([]PaperWalletKey, n)
+	for i := range keys {
+		pk, sk := cipher.GenerateKeyPair()
+		keys[i].Address = cipher.AddressFromPubKey(pk).String()
+
+		if len(passphrase) == 0 {
+			keys[i].Secret = sk.Hex()
+			continue
+		}
+
+		encrypted, err := cipher.BIP38Encrypt(sk, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		keys[i].Secret = encrypted
+		keys[i].Encrypted = true
+	}
+
+	return keys, nil
+}
+
+// renderPaperWallet writes the page of the paper wallet, with the QR codes embedded as PNG data URIs
+// writePaperWalletJSON writes the key pairs of the paper wallet in JSON format
+func writePaperWalletJSON(w io.Writer, keys []PaperWalletKey) error {
+	d, err := formatJSON(struct {
+		Keys []PaperWalletKey `json:"keys"`
+	}{
+		Keys: keys,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(d))
+	return err
+}
+
+func renderPaperWallet(w io.Writer, title string, keys []PaperWalletKey) error {
+	type card struct {
+		PaperWalletKey
+		AddressQR template.URL
+		SecretQR  template.URL
+	}
+
+	cards := make([]card, len(keys))
+	for i, k := range keys {
+		cards[i].PaperWalletKey = k
+
+		var err error
+		if cards[i].AddressQR, err = qrDataURI(k.Address); err != nil {
+			return err
+		}
+		if cards[i].SecretQR, err = qrDataURI(k.Secret); err != nil {
+			return err
+		}
+	}
+
+	return paperWalletTemplate.Execute(w, struct {
+		Title string
+		Keys  []card
+	}{
+		Title: title,
+		Keys:  cards,
+	})
+}
+
+// qrDataURI returns the QR code PNG image of the content as a data URI
+func qrDataURI(content string) (template.URL, error) {
+	png, err := qrcode.Encode(content, qrcode.Medium, qrPNGSize)
+	if err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)), nil //nolint:gosec
+}
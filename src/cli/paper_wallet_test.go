@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestNewPaperWalletKeys(t *testing.T) {
+	keys, err := newPaperWalletKeys(2, nil)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.NotEqual(t, keys[0].Address, keys[1].Address)
+
+	for _, k := range keys {
+		require.False(t, k.Encrypted)
+		sk, err := cipher.SecKeyFromHex(k.Secret)
+		require.NoError(t, err)
+		require.Equal(t, k.Address, cipher.MustAddressFromSecKey(sk).String())
+	}
+
+	keys, err = newPaperWalletKeys(1, []byte("pass"))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.True(t, keys[0].Encrypted)
+	require.True(t, cipher.IsBIP38Key(keys[0].Secret))
+
+	sk, err := cipher.BIP38Decrypt(keys[0].Secret, []byte("pass"))
+	require.NoError(t, err)
+	require.Equal(t, keys[0].Address, cipher.MustAddressFromSecKey(sk).String())
+}
+
+func TestRenderPaperWallet(t *testing.T) {
+	keys, err := newPaperWalletKeys(2, nil)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, renderPaperWallet(&out, "skycoin <paper> wallet", keys))
+
+	page := out.String()
+	require.Contains(t, page, "<title>skycoin &lt;paper&gt; wallet</title>")
+	require.Equal(t, 4, strings.Count(page, `src="data:image/png;base64,`))
+	require.Equal(t, 2, strings.Count(page, "<h2>Secret key</h2>"))
+	for _, k := range keys {
+		require.Contains(t, page, k.Address)
+		require.Contains(t, page, k.Secret)
+	}
+}
@@ -0,0 +1,514 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+const (
+	profilesFilename = "cli_profiles.json"
+	// profilesVersion is the version of the profiles file format
+	profilesVersion = 1
+)
+
+// profilesCryptoType is the crypto type of the encrypted profiles files,
+// the profiles are decrypted with the crypto type recorded in the file
+var profilesCryptoType = crypto.DefaultCryptoType
+
+var (
+	// ErrProfilesPassword is returned if the profiles can't be decrypted with the password
+	ErrProfilesPassword = errors.New("invalid profiles password")
+	// ErrProfilesVersion is returned when loading a profiles file of an unsupported version
+	ErrProfilesVersion = errors.New("unsupported profiles file version")
+)
+
+// Profile is a named configuration of the cli, selected with the --profile flag.
+// The empty fields keep the configuration of the environment variables.
+type Profile struct {
+	Name        string `json:"name"`
+	RPCAddress  string `json:"rpc_address,omitempty"`
+	RPCUsername string `json:"rpc_username,omitempty"`
+	RPCPassword string `json:"rpc_password,omitempty"`
+	Coin        string `json:"coin,omitempty"`
+	DataDir     string `json:"data_directory,omitempty"`
+	// Wallet is the default wallet of the commands with a [wallet] argument
+	Wallet string `json:"wallet,omitempty"`
+}
+
+func (p Profile) validate() error {
+	if p.Name == "" || strings.TrimSpace(p.Name) != p.Name {
+		return fmt.Errorf("invalid profile name %q", p.Name)
+	}
+
+	if p.RPCAddress != "" {
+		u, err := url.Parse(p.RPCAddress)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("the rpc address of the profile must be in scheme://host format")
+		}
+	}
+
+	if p.Wallet != "" && !strings.HasSuffix(p.Wallet, walletExt) {
+		return ErrWalletName
+	}
+
+	return nil
+}
+
+// apply returns cfg with the configuration of the profile
+func (p Profile) apply(cfg Config) Config {
+	cfg.Profile = p.Name
+	if p.RPCAddress != "" {
+		cfg.RPCAddress = p.RPCAddress
+	}
+	if p.RPCUsername != "" {
+		cfg.RPCUsername = p.RPCUsername
+	}
+	if p.RPCPassword != "" {
+		cfg.RPCPassword = p.RPCPassword
+	}
+	if p.Coin != "" {
+		cfg.Coin = p.Coin
+	}
+	if p.DataDir != "" {
+		cfg.DataDir = p.DataDir
+	}
+	if p.Wallet != "" {
+		cfg.Wallet = p.Wallet
+	}
+	return cfg
+}
+
+// Profiles is the list of profiles, sorted by name
+type Profiles struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// Set adds the profile, or replaces the profile with the same name
+func (ps *Profiles) Set(p Profile) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	if i, ok := ps.find(p.Name); ok {
+		ps.Profiles[i] = p
+		return nil
+	}
+
+	ps.Profiles = append(ps.Profiles, p)
+	sort.Slice(ps.Profiles, func(i, j int) bool {
+		return ps.Profiles[i].Name < ps.Profiles[j].Name
+	})
+
+	return nil
+}
+
+// Get returns the profile with the name
+func (ps *Profiles) Get(name string) (Profile, error) {
+	i, ok := ps.find(name)
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found", name)
+	}
+
+	return ps.Profiles[i], nil
+}
+
+// Remove removes the profile with the name
+func (ps *Profiles) Remove(name string) error {
+	i, ok := ps.find(name)
+	if !ok {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	ps.Profiles = append(ps.Profiles[:i], ps.Profiles[i+1:]...)
+	return nil
+}
+
+func (ps *Profiles) find(name string) (int, bool) {
+	for i, p := range ps.Profiles {
+		if p.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// profilesFile is the saved profiles. The profiles of an encrypted file are encrypted in Data.
+type profilesFile struct {
+	Version    int               `json:"version"`
+	Encrypted  bool              `json:"encrypted"`
+	CryptoType crypto.CryptoType `json:"crypto_type,omitempty"`
+	Profiles   []Profile         `json:"profiles,omitempty"`
+	Data       []byte            `json:"data,omitempty"`
+}
+
+// loadProfiles loads the profiles, the profiles of a file that does not exist are empty.
+// The password of an encrypted file is read with password. Returns the password, nil if the file is not encrypted.
+func loadProfiles(path string, password func() ([]byte, error)) (*Profiles, []byte, error) {
+	var f profilesFile
+	if err := file.LoadJSON(path, &f); err != nil {
+		if os.IsNotExist(err) {
+			return &Profiles{}, nil, nil
+		}
+		return nil, nil, fmt.Errorf("load profiles failed: %v", err)
+	}
+
+	if f.Version != profilesVersion {
+		return nil, nil, ErrProfilesVersion
+	}
+
+	if !f.Encrypted {
+		return &Profiles{Profiles: f.Profiles}, nil, nil
+	}
+
+	cryptor, err := crypto.GetCrypto(f.CryptoType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := password()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := cryptor.Decrypt(f.Data, p)
+	if err != nil {
+		return nil, nil, ErrProfilesPassword
+	}
+
+	var ps Profiles
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return nil, nil, fmt.Errorf("invalid profiles: %v", err)
+	}
+	return &ps, p, nil
+}
+
+// saveProfiles saves the profiles, encrypted with the password if it is not nil
+func saveProfiles(path string, ps *Profiles, password []byte) error {
+	f := profilesFile{
+		Version: profilesVersion,
+	}
+
+	if password == nil {
+		f.Profiles = ps.Profiles
+	} else {
+		data, err := json.Marshal(ps)
+		if err != nil {
+			return err
+		}
+
+		cryptor, err := crypto.GetCrypto(profilesCryptoType)
+		if err != nil {
+			return err
+		}
+
+		f.Data, err = cryptor.Encrypt(data, password)
+		if err != nil {
+			return err
+		}
+		f.Encrypted = true
+		f.CryptoType = profilesCryptoType
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return file.SaveJSON(path, f, 0600)
+}
+
+// profilesPassword returns the profiles password of the PROFILES_PASSWORD environment variable,
+// if it is not set the password is read from the terminal
+func profilesPassword() ([]byte, error) {
+	if p := os.Getenv("PROFILES_PASSWORD"); p != "" {
+		return []byte(p), nil
+	}
+
+	fmt.Fprint(os.Stdout, "enter profiles password:")
+	p, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, "")
+
+	if len(p) == 0 {
+		return nil, errors.New("missing profiles password")
+	}
+	return p, nil
+}
+
+// loadProfileConfig returns cfg with the configuration of the profile of the profiles file of cfg
+func loadProfileConfig(cfg Config, name string, password func() ([]byte, error)) (Config, error) {
+	ps, _, err := loadProfiles(cfg.ProfilesPath, password)
+	if err != nil {
+		return Config{}, err
+	}
+
+	p, err := ps.Get(name)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return p.apply(cfg), nil
+}
+
+// profileFromArgs returns the value of the --profile flag of the command line arguments,
+// and the arguments without the flag
+func profileFromArgs(args []string) (string, []string, error) {
+	var profile string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			return profile, append(rest, args[i:]...), nil
+		case a == "--profile":
+			if i+1 == len(args) {
+				return "", nil, errors.New("flag needs an argument: --profile")
+			}
+			i++
+			profile = args[i]
+		case strings.HasPrefix(a, "--profile="):
+			profile = strings.TrimPrefix(a, "--profile=")
+		default:
+			rest = append(rest, a)
+		}
+	}
+
+	return profile, rest, nil
+}
+
+func profileCmd() *cobra.Command {
+	profileCmd := &cobra.Command{
+		Short: "Manage the configuration profiles",
+		Use:   "profile",
+		Long: `Manage the configuration profiles. A profile is a named configuration with the node address,
+    the RPC username and password, the coin, the data directory and the default wallet of the commands.
+    The profile is selected with the --profile flag of the commands, the values it does not set are
+    read from the environment variables.
+
+    The profiles are saved in $DATA_DIR/cli_profiles.json, where DATA_DIR is the data directory
+    of the environment. The profiles file can be encrypted with the "profile encrypt" command,
+    the password is read from the PROFILES_PASSWORD environment variable or prompted.`,
+		Args: cobra.NoArgs,
+	}
+
+	profileCmd.AddCommand(
+		profileSetCmd(),
+		profileListCmd(),
+		profileRemoveCmd(),
+		profileEncryptCmd(),
+		profileDecryptCmd(),
+	)
+
+	return profileCmd
+}
+
+func profileSetCmd() *cobra.Command {
+	profileSetCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Create a profile or update its values",
+		Use:   "set [name]",
+		Long: `Create the profile [name], or update the values of the flags of an existing profile.
+    A value is removed from the profile by setting it to "".
+
+    Use caution when using the "--rpc-pass" option. If you have command history enabled
+    the password can be recovered from the history log. Encrypt the profiles file to
+    keep the password of the profile secret.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			err := updateProfiles(func(ps *Profiles) error {
+				p, err := ps.Get(args[0])
+				if err != nil {
+					p = Profile{Name: args[0]}
+				}
+
+				for flag, v := range map[string]*string{
+					"rpc-addr": &p.RPCAddress,
+					"rpc-user": &p.RPCUsername,
+					"rpc-pass": &p.RPCPassword,
+					"coin":     &p.Coin,
+					"data-dir": &p.DataDir,
+					"wallet":   &p.Wallet,
+				} {
+					if !c.Flags().Changed(flag) {
+						continue
+					}
+
+					*v, err = c.Flags().GetString(flag)
+					if err != nil {
+						return err
+					}
+				}
+
+				return ps.Set(p)
+			})
+			if err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+
+	profileSetCmd.Flags().String("rpc-addr", "", "Address of the RPC node, in scheme://host format")
+	profileSetCmd.Flags().String("rpc-user", "", "Username for the RPC API")
+	profileSetCmd.Flags().String("rpc-pass", "", "Password for the RPC API")
+	profileSetCmd.Flags().String("coin", "", "Name of the coin")
+	profileSetCmd.Flags().String("data-dir", "", "Directory where everything is stored")
+	profileSetCmd.Flags().String("wallet", "", "Default wallet of the commands with a [wallet] argument")
+
+	return profileSetCmd
+}
+
+func profileListCmd() *cobra.Command {
+	profileListCmd := &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "List the profiles, without their RPC passwords",
+		Use:          "list",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			ps, _, err := loadProfiles(cliConfig.ProfilesPath, profilesPassword)
+			if err != nil {
+				return err
+			}
+
+			for i := range ps.Profiles {
+				ps.Profiles[i].RPCPassword = ""
+			}
+
+			if jsonOutput {
+				return printJSON(ps)
+			}
+
+			return printProfiles(os.Stdout, ps.Profiles)
+		},
+	}
+
+	profileListCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return profileListCmd
+}
+
+func profileRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Short:        "Remove a profile",
+		Use:          "remove [name]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := updateProfiles(func(ps *Profiles) error {
+				return ps.Remove(args[0])
+			}); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+func profileEncryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "Encrypt the profiles file",
+		Use:          "encrypt",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			path := cliConfig.ProfilesPath
+			ps, password, err := loadProfiles(path, profilesPassword)
+			if err != nil {
+				return err
+			}
+			if password != nil {
+				return errors.New("the profiles file is already encrypted")
+			}
+
+			password, err = profilesPassword()
+			if err != nil {
+				return err
+			}
+
+			if err := saveProfiles(path, ps, password); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+func profileDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "Decrypt the profiles file",
+		Use:          "decrypt",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			path := cliConfig.ProfilesPath
+			ps, password, err := loadProfiles(path, profilesPassword)
+			if err != nil {
+				return err
+			}
+			if password == nil {
+				return errors.New("the profiles file is not encrypted")
+			}
+
+			if err := saveProfiles(path, ps, nil); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+// updateProfiles loads the profiles, updates them with update and saves them, encrypted if they were
+func updateProfiles(update func(ps *Profiles) error) error {
+	path := cliConfig.ProfilesPath
+	ps, password, err := loadProfiles(path, profilesPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := update(ps); err != nil {
+		return err
+	}
+
+	return saveProfiles(path, ps, password)
+}
+
+func printProfiles(w io.Writer, profiles []Profile) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tNODE\tCOIN\tDATA DIR\tWALLET")
+	for _, p := range profiles {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.Name, orDash(p.RPCAddress), orDash(p.Coin), orDash(p.DataDir), orDash(p.Wallet))
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+func init() {
+	// Speed up the tests
+	profilesCryptoType = crypto.CryptoTypeScryptChacha20poly1305Insecure
+}
+
+func TestProfiles(t *testing.T) {
+	var ps Profiles
+	require.NoError(t, ps.Set(Profile{Name: "testnet", RPCAddress: "http://127.0.0.1:6421", Wallet: "test.wlt"}))
+	require.NoError(t, ps.Set(Profile{Name: "fiber", Coin: "mycoin", DataDir: "/data/mycoin"}))
+	require.NoError(t, ps.Set(Profile{Name: "testnet", RPCAddress: "http://127.0.0.1:6422"}))
+
+	require.EqualError(t, ps.Set(Profile{Name: ""}), `invalid profile name ""`)
+	require.EqualError(t, ps.Set(Profile{Name: "a ", Coin: "foo"}), `invalid profile name "a "`)
+	require.EqualError(t, ps.Set(Profile{Name: "a", RPCAddress: "127.0.0.1:6420"}), "the rpc address of the profile must be in scheme://host format")
+	require.Equal(t, ErrWalletName, ps.Set(Profile{Name: "a", Wallet: "foo"}))
+
+	require.Equal(t, []Profile{
+		{Name: "fiber", Coin: "mycoin", DataDir: "/data/mycoin"},
+		{Name: "testnet", RPCAddress: "http://127.0.0.1:6422"},
+	}, ps.Profiles)
+
+	p, err := ps.Get("fiber")
+	require.NoError(t, err)
+	require.Equal(t, "mycoin", p.Coin)
+
+	require.NoError(t, ps.Remove("fiber"))
+	require.EqualError(t, ps.Remove("fiber"), `profile "fiber" not found`)
+	_, err = ps.Get("fiber")
+	require.EqualError(t, err, `profile "fiber" not found`)
+
+	var out bytes.Buffer
+	require.NoError(t, printProfiles(&out, []Profile{
+		{Name: "fiber", Coin: "mycoin", DataDir: "/data/mycoin", Wallet: "fiber.wlt"},
+		{Name: "testnet", RPCAddress: "http://127.0.0.1:6422"},
+	}))
+	require.Equal(t, `NAME     NODE                   COIN    DATA DIR      WALLET
+fiber    -                      mycoin  /data/mycoin  fiber.wlt
+testnet  http://127.0.0.1:6422  -       -             -
+`, out.String())
+}
+
+func TestLoadSaveProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data", profilesFilename)
+
+	noPassword := func() ([]byte, error) {
+		return nil, errors.New("the password must not be read")
+	}
+	password := func(p string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			return []byte(p), nil
+		}
+	}
+
+	// Profiles that do not exist are empty
+	ps, pwd, err := loadProfiles(path, noPassword)
+	require.NoError(t, err)
+	require.Nil(t, pwd)
+	require.Empty(t, ps.Profiles)
+
+	require.NoError(t, ps.Set(Profile{Name: "testnet", RPCAddress: "http://127.0.0.1:6421", RPCPassword: "secret"}))
+
+	// The profiles are not encrypted without a password
+	require.NoError(t, saveProfiles(path, ps, nil))
+	loaded, pwd, err := loadProfiles(path, noPassword)
+	require.NoError(t, err)
+	require.Nil(t, pwd)
+	require.Equal(t, ps, loaded)
+
+	// The profiles are encrypted with a password
+	require.NoError(t, saveProfiles(path, ps, []byte("pwd")))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "testnet")
+	require.NotContains(t, string(data), "secret")
+
+	loaded, pwd, err = loadProfiles(path, password("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("pwd"), pwd)
+	require.Equal(t, ps, loaded)
+
+	_, _, err = loadProfiles(path, password("wrong"))
+	require.Equal(t, ErrProfilesPassword, err)
+}
+
+func TestLoadProfileConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := Config{
+		DataDir:      dir,
+		Coin:         "skycoin",
+		RPCAddress:   "http://127.0.0.1:6420",
+		RPCUsername:  "user",
+		ProfilesPath: filepath.Join(dir, profilesFilename),
+	}
+
+	require.NoError(t, saveProfiles(cfg.ProfilesPath, &Profiles{
+		Profiles: []Profile{
+			{
+				Name:        "fiber",
+				RPCAddress:  "http://10.0.0.1:6420",
+				RPCPassword: "pass",
+				Coin:        "mycoin",
+				DataDir:     "/data/mycoin",
+				Wallet:      "fiber.wlt",
+			},
+		},
+	}, []byte("pwd")))
+
+	password := func() ([]byte, error) {
+		return []byte("pwd"), nil
+	}
+
+	pcfg, err := loadProfileConfig(cfg, "fiber", password)
+	require.NoError(t, err)
+	require.Equal(t, Config{
+		DataDir:      "/data/mycoin",
+		Coin:         "mycoin",
+		RPCAddress:   "http://10.0.0.1:6420",
+		RPCUsername:  "user",
+		RPCPassword:  "pass",
+		Profile:      "fiber",
+		Wallet:       "fiber.wlt",
+		ProfilesPath: cfg.ProfilesPath,
+	}, pcfg)
+
+	_, err = loadProfileConfig(cfg, "foo", password)
+	require.EqualError(t, err, `profile "foo" not found`)
+}
+
+func TestProfileFromArgs(t *testing.T) {
+	tt := []struct {
+		name    string
+		args    []string
+		profile string
+		rest    []string
+		err     string
+	}{
+		{
+			name: "no profile",
+			args: []string{"status"},
+			rest: []string{"status"},
+		},
+		{
+			name:    "profile flag",
+			args:    []string{"walletBalance", "--profile", "fiber", "-j"},
+			profile: "fiber",
+			rest:    []string{"walletBalance", "-j"},
+		},
+		{
+			name:    "profile flag with value",
+			args:    []string{"--profile=fiber", "status"},
+			profile: "fiber",
+			rest:    []string{"status"},
+		},
+		{
+			name: "profile after --",
+			args: []string{"send", "--", "--profile", "fiber"},
+			rest: []string{"send", "--", "--profile", "fiber"},
+		},
+		{
+			name: "missing value",
+			args: []string{"status", "--profile"},
+			err:  "flag needs an argument: --profile",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, rest, err := profileFromArgs(tc.args)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.profile, profile)
+			require.Equal(t, tc.rest, rest)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/droplet"
+)
+
+// qrPNGSize is the width and height in pixels of the QR code PNG images
+const qrPNGSize = 256
+
+// PaymentRequest is a payment request to an address, encoded as a payment URI,
+// e.g. skycoin:2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv?amount=1.5&hours=10&label=shop
+type PaymentRequest struct {
+	Address string
+	Amount  string
+	Hours   uint64
+	Label   string
+	Message string
+}
+
+// URI returns the payment URI of the request, with the coin name as scheme
+func (p PaymentRequest) URI(coin string) string {
+	v := url.Values{}
+	if p.Amount != "" {
+		v.Set("amount", p.Amount)
+	}
+	if p.Hours != 0 {
+		v.Set("hours", strconv.FormatUint(p.Hours, 10))
+	}
+	if p.Label != "" {
+		v.Set("label", p.Label)
+	}
+	if p.Message != "" {
+		v.Set("message", p.Message)
+	}
+
+	uri := fmt.Sprintf("%s:%s", coin, p.Address)
+	if len(v) != 0 {
+		// url.Values encodes spaces as "+", payment URIs use "%20"
+		uri += "?" + strings.Replace(v.Encode(), "+", "%20", -1)
+	}
+	return uri
+}
+
+// isPayment returns true if the request has any payment details
+func (p PaymentRequest) isPayment() bool {
+	return p.Amount != "" || p.Hours != 0 || p.Label != "" || p.Message != ""
+}
+
+// addQRFlags adds the flags to render the addresses of the command as QR codes
+func addQRFlags(c *cobra.Command) {
+	c.Flags().Bool("qr", false, "Render the addresses as QR codes in the terminal")
+	c.Flags().String("qr-png", "", `Write the QR codes to PNG files, e.g. "qr.png".
+With multiple addresses, the address is added to the file names, e.g. "qr-<address>.png"`)
+	c.Flags().String("amount", "", "Amount of coins of the payment request encoded in the QR codes")
+	c.Flags().Uint64("hours", 0, "Coin hours of the payment request encoded in the QR codes")
+	c.Flags().String("label", "", "Label of the payment request encoded in the QR codes")
+	c.Flags().String("message", "", "Message of the payment request encoded in the QR codes")
+}
+
+// qrOptions are the options of the QR code flags
+type qrOptions struct {
+	terminal bool
+	png      string
+	payment  PaymentRequest
+}
+
+func (o qrOptions) enabled() bool {
+	return o.terminal || o.png != ""
+}
+
+func getQROptions(c *cobra.Command) (qrOptions, error) {
+	var o qrOptions
+	var err error
+
+	if o.terminal, err = c.Flags().GetBool("qr"); err != nil {
+		return o, err
+	}
+	if o.png, err = c.Flags().GetString("qr-png"); err != nil {
+		return o, err
+	}
+	if o.payment.Amount, err = c.Flags().GetString("amount"); err != nil {
+		return o, err
+	}
+	if o.payment.Hours, err = c.Flags().GetUint64("hours"); err != nil {
+		return o, err
+	}
+	if o.payment.Label, err = c.Flags().GetString("label"); err != nil {
+		return o, err
+	}
+	if o.payment.Message, err = c.Flags().GetString("message"); err != nil {
+		return o, err
+	}
+
+	if jsonOutput, _ := c.Flags().GetBool("json"); jsonOutput && o.enabled() {
+		return o, errors.New("--qr and --qr-png can't be used with --json")
+	}
+
+	if o.payment.isPayment() && !o.enabled() {
+		return o, errors.New("--amount, --hours, --label and --message require --qr or --qr-png")
+	}
+
+	if o.payment.Amount != "" {
+		if _, err := droplet.FromString(o.payment.Amount); err != nil {
+			return o, fmt.Errorf("invalid --amount: %v", err)
+		}
+	}
+
+	return o, nil
+}
+
+// qrContent returns the content of the QR code of the address, the address or the payment URI of the address
+func (o qrOptions) qrContent(coin, addr string) (string, error) {
+	if _, err := cipher.DecodeBase58Address(addr); err != nil {
+		return "", err
+	}
+
+	if !o.payment.isPayment() {
+		return addr, nil
+	}
+
+	p := o.payment
+	p.Address = addr
+	return p.URI(coin), nil
+}
+
+// writeQRCodes renders the QR codes of the addresses in the terminal, and writes them to PNG files
+func writeQRCodes(w io.Writer, o qrOptions, coin string, addrs []string) error {
+	for _, addr := range addrs {
+		content, err := o.qrContent(coin, addr)
+		if err != nil {
+			return err
+		}
+
+		qr, err := qrcode.New(content, qrcode.Medium)
+		if err != nil {
+			return err
+		}
+
+		if o.terminal {
+			fmt.Fprintln(w, content)
+			fmt.Fprint(w, qr.ToSmallString(false))
+		}
+
+		if o.png != "" {
+			filename := qrPNGFilename(o.png, addr, len(addrs) > 1)
+			if err := qr.WriteFile(qrPNGSize, filename); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "QR code of %s written to %s\n", addr, filename)
+		}
+	}
+
+	return nil
+}
+
+// qrPNGFilename returns the PNG file name of the address, the address is added to the name if multiple is true
+func qrPNGFilename(name, addr string, multiple bool) string {
+	if !multiple {
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(name, ext), addr, ext)
+}
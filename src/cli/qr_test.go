@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestPaymentRequestURI(t *testing.T) {
+	addr := testutil.MakeAddress().String()
+
+	tt := []struct {
+		name string
+		p    PaymentRequest
+		uri  string
+	}{
+		{
+			name: "address only",
+			p:    PaymentRequest{Address: addr},
+			uri:  "skycoin:" + addr,
+		},
+		{
+			name: "amount",
+			p:    PaymentRequest{Address: addr, Amount: "1.5"},
+			uri:  "skycoin:" + addr + "?amount=1.5",
+		},
+		{
+			name: "all fields",
+			p: PaymentRequest{
+				Address: addr,
+				Amount:  "10",
+				Hours:   20,
+				Label:   "Coffee shop",
+				Message: "Order #12&3",
+			},
+			uri: "skycoin:" + addr + "?amount=10&hours=20&label=Coffee%20shop&message=Order%20%2312%263",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.uri, tc.p.URI("skycoin"))
+		})
+	}
+}
+
+func TestQROptions(t *testing.T) {
+	tt := []struct {
+		name string
+		args []string
+		opts qrOptions
+		err  string
+	}{
+		{
+			name: "no flags",
+		},
+		{
+			name: "terminal payment",
+			args: []string{"--qr", "--amount", "1.5", "--label", "shop"},
+			opts: qrOptions{
+				terminal: true,
+				payment:  PaymentRequest{Amount: "1.5", Label: "shop"},
+			},
+		},
+		{
+			name: "png",
+			args: []string{"--qr-png", "qr.png", "--hours", "5"},
+			opts: qrOptions{
+				png:     "qr.png",
+				payment: PaymentRequest{Hours: 5},
+			},
+		},
+		{
+			name: "payment without qr",
+			args: []string{"--amount", "1"},
+			err:  "--amount, --hours, --label and --message require --qr or --qr-png",
+		},
+		{
+			name: "invalid amount",
+			args: []string{"--qr", "--amount", "foo"},
+			err:  "invalid --amount: can't convert foo to decimal",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := listAddressesCmd()
+			require.NoError(t, c.ParseFlags(tc.args))
+
+			opts, err := getQROptions(c)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.opts, opts)
+		})
+	}
+}
+
+func TestWriteQRCodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-qr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+
+	// Terminal QR codes of the payment URIs
+	var out bytes.Buffer
+	opts := qrOptions{
+		terminal: true,
+		payment:  PaymentRequest{Amount: "2"},
+	}
+	require.NoError(t, writeQRCodes(&out, opts, "skycoin", []string{addr1, addr2}))
+	s := out.String()
+	require.True(t, strings.HasPrefix(s, "skycoin:"+addr1+"?amount=2\n"))
+	require.Contains(t, s, "\nskycoin:"+addr2+"?amount=2\n")
+	require.Contains(t, s, "█")
+
+	// PNG QR codes of the addresses
+	out.Reset()
+	opts = qrOptions{png: filepath.Join(dir, "qr.png")}
+	require.NoError(t, writeQRCodes(&out, opts, "skycoin", []string{addr1, addr2}))
+	for _, a := range []string{addr1, addr2} {
+		filename := filepath.Join(dir, "qr-"+a+".png")
+		require.Contains(t, out.String(), "QR code of "+a+" written to "+filename+"\n")
+
+		d, err := ioutil.ReadFile(filename)
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(d, []byte("\x89PNG")))
+	}
+
+	// A single address keeps the file name
+	out.Reset()
+	require.NoError(t, writeQRCodes(&out, opts, "skycoin", []string{addr1}))
+	_, err = os.Stat(opts.png)
+	require.NoError(t, err)
+
+	// Invalid address
+	require.Error(t, writeQRCodes(&out, opts, "skycoin", []string{"foo"}))
+}
@@ -0,0 +1,787 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/secret"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/file"
+	wh "github.com/skycoin/skycoin/src/util/http"
+)
+
+const (
+	schedulesFilename = "cli_schedules.json"
+	// schedulesVersion is the version of the schedules file format
+	schedulesVersion = 1
+	// minScheduleInterval is the shortest interval of the recurring payments
+	minScheduleInterval = time.Minute
+)
+
+var (
+	// ErrSchedulesVersion is returned when loading a schedules file of an unsupported version
+	ErrSchedulesVersion = errors.New("unsupported schedules file version")
+	// ErrSchedulePayment is returned by "schedule run --once" if a payment failed
+	ErrSchedulePayment = errors.New("some scheduled payments failed")
+)
+
+// Schedule is a recurring payment from a wallet to an address
+type Schedule struct {
+	Name     string      `json:"name"`
+	Wallet   string      `json:"wallet"`
+	To       string      `json:"to"`
+	Coins    string      `json:"coins"`
+	Interval wh.Duration `json:"interval"`
+	// NextRun is the time of the next payment
+	NextRun time.Time `json:"next_run"`
+	// LastRun, LastTxid and LastError are the time, the transaction id and the error of the last payment
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	LastTxid  string     `json:"last_txid,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+func (s Schedule) validate() error {
+	if s.Name == "" || strings.TrimSpace(s.Name) != s.Name {
+		return fmt.Errorf("invalid schedule name %q", s.Name)
+	}
+
+	if !strings.HasSuffix(s.Wallet, walletExt) {
+		return ErrWalletName
+	}
+
+	if _, err := cipher.DecodeBase58Address(s.To); err != nil {
+		return fmt.Errorf("invalid address %s: %v", s.To, err)
+	}
+
+	coins, err := droplet.FromString(s.Coins)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %v", err)
+	}
+	if coins == 0 {
+		return errors.New("invalid amount: must be greater than 0")
+	}
+
+	if s.Interval.Duration < minScheduleInterval {
+		return fmt.Errorf("invalid interval %s, must be at least %s", s.Interval, minScheduleInterval)
+	}
+
+	return nil
+}
+
+// next returns the first run of the schedule after t. The runs missed while
+// the scheduler was not running are skipped, they are not paid on catching up.
+func (s Schedule) next(t time.Time) time.Time {
+	next := s.NextRun
+	if next.After(t) {
+		return next
+	}
+
+	n := t.Sub(next)/s.Interval.Duration + 1
+	return next.Add(n * s.Interval.Duration)
+}
+
+// Schedules is the list of schedules, sorted by name
+type Schedules struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+// Add adds a schedule
+func (ss *Schedules) Add(s Schedule) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	if _, ok := ss.find(s.Name); ok {
+		return fmt.Errorf("schedule %q already exists", s.Name)
+	}
+
+	ss.Schedules = append(ss.Schedules, s)
+	sort.Slice(ss.Schedules, func(i, j int) bool {
+		return ss.Schedules[i].Name < ss.Schedules[j].Name
+	})
+
+	return nil
+}
+
+// Remove removes the schedule with the name
+func (ss *Schedules) Remove(name string) error {
+	i, ok := ss.find(name)
+	if !ok {
+		return fmt.Errorf("schedule %q not found", name)
+	}
+
+	ss.Schedules = append(ss.Schedules[:i], ss.Schedules[i+1:]...)
+	return nil
+}
+
+// Wallets returns the wallets of the schedules, sorted by name
+func (ss *Schedules) Wallets() []string {
+	var wallets []string
+	seen := make(map[string]struct{})
+	for _, s := range ss.Schedules {
+		if _, ok := seen[s.Wallet]; !ok {
+			seen[s.Wallet] = struct{}{}
+			wallets = append(wallets, s.Wallet)
+		}
+	}
+
+	sort.Strings(wallets)
+	return wallets
+}
+
+func (ss *Schedules) find(name string) (int, bool) {
+	for i, s := range ss.Schedules {
+		if s.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// schedulesFile is the saved schedules
+type schedulesFile struct {
+	Version   int        `json:"version"`
+	Schedules []Schedule `json:"schedules"`
+}
+
+func schedulesPath() string {
+	return filepath.Join(cliConfig.DataDir, schedulesFilename)
+}
+
+// loadSchedules loads the schedules, the schedules of a file that does not exist are empty
+func loadSchedules(path string) (*Schedules, error) {
+	var f schedulesFile
+	if err := file.LoadJSON(path, &f); err != nil {
+		if os.IsNotExist(err) {
+			return &Schedules{}, nil
+		}
+		return nil, fmt.Errorf("load schedules failed: %v", err)
+	}
+
+	if f.Version != schedulesVersion {
+		return nil, ErrSchedulesVersion
+	}
+
+	return &Schedules{Schedules: f.Schedules}, nil
+}
+
+// saveSchedules saves the schedules
+func saveSchedules(path string, ss *Schedules) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return file.SaveJSON(path, schedulesFile{
+		Version:   schedulesVersion,
+		Schedules: ss.Schedules,
+	}, 0600)
+}
+
+// updateSchedules loads the schedules, updates them with update and saves them
+func updateSchedules(path string, update func(ss *Schedules) error) error {
+	ss, err := loadSchedules(path)
+	if err != nil {
+		return err
+	}
+
+	if err := update(ss); err != nil {
+		return err
+	}
+
+	return saveSchedules(path, ss)
+}
+
+// SchedulePayment is a payment made by "schedule run"
+type SchedulePayment struct {
+	Schedule string    `json:"schedule"`
+	Time     time.Time `json:"time"`
+	Wallet   string    `json:"wallet"`
+	To       string    `json:"to"`
+	Coins    string    `json:"coins"`
+	Txid     string    `json:"txid,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// scheduleClient is the API client used by the schedule run command
+type scheduleClient interface {
+	WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error)
+	InjectEncodedTransaction(rawTxn string) (string, error)
+}
+
+// scheduleRunner pays the due schedules of a schedules file
+type scheduleRunner struct {
+	client scheduleClient
+	path   string
+	// passwords are the passwords of the encrypted wallets, by wallet.
+	// The wallets without a password sign in the unlock session of the node.
+	passwords map[string]*secret.Buffer
+}
+
+// runDue pays the schedules due at t. A schedule is updated before its transaction is broadcast,
+// so that a payment is not made twice if the scheduler stops before recording it.
+// The schedule of a failed payment is retried on the next run.
+func (r *scheduleRunner) runDue(t time.Time) ([]SchedulePayment, error) {
+	ss, err := loadSchedules(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []SchedulePayment
+	for _, s := range ss.Schedules {
+		if s.NextRun.After(t) {
+			continue
+		}
+
+		p, err := r.pay(s, t)
+		if err != nil {
+			return payments, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, nil
+}
+
+func (r *scheduleRunner) pay(s Schedule, t time.Time) (SchedulePayment, error) {
+	p := SchedulePayment{
+		Schedule: s.Name,
+		Time:     t,
+		Wallet:   s.Wallet,
+		To:       s.To,
+		Coins:    s.Coins,
+	}
+
+	// The schedules file may have been edited
+	if err := s.validate(); err != nil {
+		p.Error = err.Error()
+		return p, r.update(s.Name, func(s *Schedule) {
+			s.LastError = p.Error
+		})
+	}
+
+	var password string
+	if b, ok := r.passwords[s.Wallet]; ok {
+		password = string(b.Bytes())
+	}
+
+	txn, err := r.client.WalletCreateTransaction(api.WalletCreateTransactionRequest{
+		WalletID: s.Wallet,
+		Password: password,
+		CreateTransactionRequest: api.CreateTransactionRequest{
+			HoursSelection: api.HoursSelection{
+				Type:        transaction.HoursSelectionTypeAuto,
+				Mode:        transaction.HoursSelectionModeShare,
+				ShareFactor: "0.5",
+			},
+			To: []api.Receiver{
+				{
+					Address: s.To,
+					Coins:   s.Coins,
+				},
+			},
+		},
+	})
+	if err != nil {
+		p.Error = err.Error()
+		return p, r.update(s.Name, func(s *Schedule) {
+			s.LastError = p.Error
+		})
+	}
+
+	p.Txid = txn.Transaction.TxID
+	if err := r.update(s.Name, func(s *Schedule) {
+		s.NextRun = s.next(t)
+		s.LastRun = &t
+		s.LastTxid = p.Txid
+		s.LastError = ""
+	}); err != nil {
+		return p, err
+	}
+
+	if _, err := r.client.InjectEncodedTransaction(txn.EncodedTransaction); err != nil {
+		p.Txid = ""
+		p.Error = err.Error()
+		// The schedule is restored to retry the payment
+		return p, r.update(s.Name, func(u *Schedule) {
+			u.NextRun = s.NextRun
+			u.LastRun = s.LastRun
+			u.LastTxid = s.LastTxid
+			u.LastError = p.Error
+		})
+	}
+
+	return p, nil
+}
+
+// update updates the schedule with the name, the schedules file is reloaded to keep
+// the schedules added or removed while running. A removed schedule is not updated.
+func (r *scheduleRunner) update(name string, f func(s *Schedule)) error {
+	return updateSchedules(r.path, func(ss *Schedules) error {
+		if i, ok := ss.find(name); ok {
+			f(&ss.Schedules[i])
+		}
+		return nil
+	})
+}
+
+// destroy wipes the passwords of the wallets
+func (r *scheduleRunner) destroy() {
+	for _, b := range r.passwords {
+		b.Destroy()
+	}
+}
+
+func scheduleCmd() *cobra.Command {
+	scheduleCmd := &cobra.Command{
+		Short: "Manage recurring payments",
+		Use:   "schedule",
+		Long: `Manage recurring payments. A schedule is a payment of an amount of coins from a wallet
+    to an address, repeated at an interval. The payments are made by "schedule run", which runs
+    until it is interrupted and sends the payments that are due.
+
+    The schedules are saved in $DATA_DIR/cli_schedules.json, with the time, the transaction id
+    and the error of the last payment of each schedule.`,
+		Args: cobra.NoArgs,
+	}
+
+	scheduleCmd.AddCommand(
+		scheduleAddCmd(),
+		scheduleListCmd(),
+		scheduleRemoveCmd(),
+		scheduleUnlockCmd(),
+		scheduleLockCmd(),
+		scheduleRunCmd(),
+	)
+
+	return scheduleCmd
+}
+
+func scheduleAddCmd() *cobra.Command {
+	scheduleAddCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Add a recurring payment",
+		Use:   "add [name]",
+		Long: `Add the recurring payment [name] of --coins coins from --wallet to --to, every --every.
+    The recipient can be the name of a contact of the address book, it is resolved when adding the schedule.
+    The first payment is made at --start, or when "schedule run" runs if --start is not set.
+
+    The interval is a duration such as "24h" or "168h", of at least 1m.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			wlt, err := c.Flags().GetString("wallet")
+			if err != nil {
+				return err
+			}
+			if wlt == "" {
+				wlt = cliConfig.Wallet
+			}
+
+			to, err := c.Flags().GetString("to")
+			if err != nil {
+				return err
+			}
+
+			coins, err := c.Flags().GetString("coins")
+			if err != nil {
+				return err
+			}
+
+			every, err := c.Flags().GetDuration("every")
+			if err != nil {
+				return err
+			}
+
+			start, err := c.Flags().GetString("start")
+			if err != nil {
+				return err
+			}
+
+			s := Schedule{
+				Name:     args[0],
+				Wallet:   wlt,
+				Coins:    coins,
+				Interval: wh.FromDuration(every),
+				NextRun:  time.Now().UTC().Truncate(time.Second),
+			}
+
+			if start != "" {
+				s.NextRun, err = time.Parse(time.RFC3339, start)
+				if err != nil {
+					return ValidationError{fmt.Errorf("invalid --start: %v", err)}
+				}
+			}
+
+			s.To, err = newContactResolver().resolve(to)
+			if err != nil {
+				return err
+			}
+
+			if err := updateSchedules(schedulesPath(), func(ss *Schedules) error {
+				if err := ss.Add(s); err != nil {
+					return ValidationError{err}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+
+	scheduleAddCmd.Flags().StringP("wallet", "w", "", "Wallet of the payments, defaults to the wallet of the profile")
+	scheduleAddCmd.Flags().String("to", "", "Recipient address or contact name")
+	scheduleAddCmd.Flags().String("coins", "", "Coins of each payment")
+	scheduleAddCmd.Flags().Duration("every", 0, "Interval of the payments")
+	scheduleAddCmd.Flags().String("start", "", "Time of the first payment, in RFC3339 format")
+
+	return scheduleAddCmd
+}
+
+func scheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "List the recurring payments",
+		Use:          "list",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			ss, err := loadSchedules(schedulesPath())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(ss)
+			}
+
+			return printSchedules(os.Stdout, ss.Schedules)
+		},
+	}
+}
+
+func scheduleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Short:        "Remove a recurring payment",
+		Use:          "remove [name]",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := updateSchedules(schedulesPath(), func(ss *Schedules) error {
+				return ss.Remove(args[0])
+			}); err != nil {
+				return err
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+// WalletUnlockSession is the unlock session of a wallet started by "schedule unlock"
+type WalletUnlockSession struct {
+	Wallet        string    `json:"wallet"`
+	UnlockedUntil time.Time `json:"unlocked_until"`
+}
+
+func scheduleUnlockCmd() *cobra.Command {
+	scheduleUnlockCmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Short: "Unlock the encrypted wallets of the recurring payments in the node",
+		Use:   "unlock",
+		Long: `Start an unlock session of the encrypted wallets of the schedules in the node, which signs
+    the transactions of the wallets without the password until the session expires after --ttl.
+    The password of each wallet is prompted.
+
+    The unlock session pre-authorizes the payments of "schedule run --unlock-session",
+    which then runs without the passwords. Note that within the session any client of the
+    wallet API of the node can spend from the wallets without the password.
+    The sessions are ended by "schedule lock" or by restarting the node.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			ttl, err := c.Flags().GetDuration("ttl")
+			if err != nil {
+				return err
+			}
+			if ttl <= 0 {
+				return ValidationError{errors.New("--ttl must be positive")}
+			}
+
+			wallets, err := scheduleWallets()
+			if err != nil {
+				return err
+			}
+
+			var sessions []WalletUnlockSession
+			for _, id := range wallets {
+				w, err := apiClient.Wallet(id)
+				if err != nil {
+					return err
+				}
+				if !w.Meta.Encrypted {
+					continue
+				}
+
+				password, err := readWalletPassword(id)
+				if err != nil {
+					return err
+				}
+
+				expires, err := apiClient.UnlockWallet(id, string(password), ttl)
+				secret.Wipe(password)
+				if err != nil {
+					return err
+				}
+
+				sessions = append(sessions, WalletUnlockSession{
+					Wallet:        id,
+					UnlockedUntil: expires.UTC(),
+				})
+			}
+
+			if jsonOutput {
+				return printJSON(sessions)
+			}
+
+			for _, s := range sessions {
+				fmt.Printf("%s unlocked until %s\n", s.Wallet, s.UnlockedUntil.Format(time.RFC3339))
+			}
+			return nil
+		},
+	}
+
+	scheduleUnlockCmd.Flags().Duration("ttl", 24*time.Hour, "Duration of the unlock sessions")
+
+	return scheduleUnlockCmd
+}
+
+func scheduleLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:         cobra.NoArgs,
+		Short:        "End the unlock sessions of the wallets of the recurring payments",
+		Use:          "lock",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			wallets, err := scheduleWallets()
+			if err != nil {
+				return err
+			}
+
+			for _, id := range wallets {
+				if err := apiClient.LockWallet(id); err != nil {
+					return err
+				}
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
+
+func scheduleRunCmd() *cobra.Command {
+	scheduleRunCmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Short: "Send the recurring payments when they are due",
+		Use:   "run",
+		Long: `Send the recurring payments when they are due, checking the schedules every --poll
+    until interrupted. With --once the due payments are sent and the command exits,
+    e.g. to run it from cron.
+
+    The password of each encrypted wallet is prompted when the command starts and kept in memory.
+    With --unlock-session the passwords are not prompted, the transactions are signed in the
+    unlock sessions of the wallets in the node, started with "schedule unlock".
+
+    A payment is made at most once: the schedule is updated before the transaction is broadcast.
+    A failed payment is retried at the next check. The runs missed while the command
+    was not running are skipped.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			once, err := c.Flags().GetBool("once")
+			if err != nil {
+				return err
+			}
+
+			unlockSession, err := c.Flags().GetBool("unlock-session")
+			if err != nil {
+				return err
+			}
+
+			poll, err := c.Flags().GetDuration("poll")
+			if err != nil {
+				return err
+			}
+			if poll <= 0 {
+				return ValidationError{errors.New("--poll must be positive")}
+			}
+
+			wallets, err := scheduleWallets()
+			if err != nil {
+				return err
+			}
+
+			r := &scheduleRunner{
+				client:    apiClient,
+				path:      schedulesPath(),
+				passwords: make(map[string]*secret.Buffer),
+			}
+			defer r.destroy()
+
+			for _, id := range wallets {
+				w, err := apiClient.Wallet(id)
+				if err != nil {
+					return err
+				}
+				if !w.Meta.Encrypted {
+					continue
+				}
+
+				if unlockSession {
+					if time.Unix(w.Meta.UnlockedUntil, 0).Before(time.Now()) {
+						return fmt.Errorf(`wallet %s is not unlocked, start an unlock session with "schedule unlock"`, id)
+					}
+					continue
+				}
+
+				password, err := readWalletPassword(id)
+				if err != nil {
+					return err
+				}
+				r.passwords[id] = secret.FromBytes(password)
+			}
+
+			quit := make(chan os.Signal, 1)
+			signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(quit)
+
+			tick := time.NewTicker(poll)
+			defer tick.Stop()
+
+			for {
+				payments, err := r.runDue(time.Now().UTC().Truncate(time.Second))
+				if perr := printSchedulePayments(os.Stdout, jsonOutput, payments); perr != nil {
+					return perr
+				}
+				if err != nil {
+					return err
+				}
+
+				if once {
+					for _, p := range payments {
+						if p.Error != "" {
+							return ErrSchedulePayment
+						}
+					}
+					return nil
+				}
+
+				select {
+				case <-quit:
+					return nil
+				case <-tick.C:
+				}
+			}
+		},
+	}
+
+	scheduleRunCmd.Flags().Bool("once", false, "Send the due payments and exit")
+	scheduleRunCmd.Flags().Bool("unlock-session", false, "Sign in the unlock sessions of the wallets instead of prompting the passwords")
+	scheduleRunCmd.Flags().Duration("poll", time.Minute, "Interval of the checks of the due payments")
+
+	return scheduleRunCmd
+}
+
+// scheduleWallets returns the wallets of the schedules
+func scheduleWallets() ([]string, error) {
+	ss, err := loadSchedules(schedulesPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ss.Schedules) == 0 {
+		return nil, errors.New(`no schedules, add a schedule with "schedule add"`)
+	}
+
+	return ss.Wallets(), nil
+}
+
+// readWalletPassword reads the password of the wallet from the terminal
+func readWalletPassword(wlt string) ([]byte, error) {
+	fmt.Fprintf(os.Stdout, "enter password of %s:", wlt)
+	p, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, "")
+
+	if len(p) == 0 {
+		return nil, fmt.Errorf("missing password of %s", wlt)
+	}
+	return p, nil
+}
+
+func printSchedules(w io.Writer, schedules []Schedule) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tWALLET\tTO\tCOINS\tEVERY\tNEXT PAYMENT\tLAST PAYMENT")
+	for _, s := range schedules {
+		last := "-"
+		switch {
+		case s.LastError != "":
+			last = "error: " + s.LastError
+		case s.LastTxid != "":
+			last = s.LastTxid
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.Wallet, s.To, s.Coins, s.Interval, s.NextRun.UTC().Format(time.RFC3339), last)
+	}
+	return tw.Flush()
+}
+
+func printSchedulePayments(w io.Writer, jsonOutput bool, payments []SchedulePayment) error {
+	for _, p := range payments {
+		if jsonOutput {
+			d, err := formatJSON(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(d))
+			continue
+		}
+
+		t := p.Time.UTC().Format(time.RFC3339)
+		if p.Error != "" {
+			fmt.Fprintf(w, "%s %s: %s coins from %s to %s failed: %s\n", t, p.Schedule, p.Coins, p.Wallet, p.To, p.Error)
+		} else {
+			fmt.Fprintf(w, "%s %s: %s coins from %s to %s, txid:%s\n", t, p.Schedule, p.Coins, p.Wallet, p.To, p.Txid)
+		}
+	}
+	return nil
+}
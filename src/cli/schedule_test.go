@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher/secret"
+	"github.com/skycoin/skycoin/src/transaction"
+	wh "github.com/skycoin/skycoin/src/util/http"
+)
+
+const (
+	scheduleAddr1 = "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"
+	scheduleAddr2 = "2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH"
+)
+
+func TestSchedules(t *testing.T) {
+	start := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)
+	rent := Schedule{
+		Name:     "rent",
+		Wallet:   "foo.wlt",
+		To:       scheduleAddr1,
+		Coins:    "10",
+		Interval: wh.FromDuration(720 * time.Hour),
+		NextRun:  start,
+	}
+	tip := Schedule{
+		Name:     "tip",
+		Wallet:   "bar.wlt",
+		To:       scheduleAddr2,
+		Coins:    "0.5",
+		Interval: wh.FromDuration(time.Hour),
+		NextRun:  start,
+	}
+
+	var ss Schedules
+	require.NoError(t, ss.Add(tip))
+	require.NoError(t, ss.Add(rent))
+	require.Equal(t, []Schedule{rent, tip}, ss.Schedules)
+	require.Equal(t, []string{"bar.wlt", "foo.wlt"}, ss.Wallets())
+
+	require.EqualError(t, ss.Add(rent), `schedule "rent" already exists`)
+
+	invalid := func(f func(s *Schedule)) Schedule {
+		s := rent
+		s.Name = "invalid"
+		f(&s)
+		return s
+	}
+	require.EqualError(t, ss.Add(invalid(func(s *Schedule) { s.Name = "" })), `invalid schedule name ""`)
+	require.Equal(t, ErrWalletName, ss.Add(invalid(func(s *Schedule) { s.Wallet = "foo" })))
+	require.EqualError(t, ss.Add(invalid(func(s *Schedule) { s.To = "foo" })), "invalid address foo: Invalid address length")
+	require.EqualError(t, ss.Add(invalid(func(s *Schedule) { s.Coins = "foo" })), "invalid amount: can't convert foo to decimal")
+	require.EqualError(t, ss.Add(invalid(func(s *Schedule) { s.Coins = "0" })), "invalid amount: must be greater than 0")
+	require.EqualError(t, ss.Add(invalid(func(s *Schedule) { s.Interval = wh.FromDuration(time.Second) })), "invalid interval 1s, must be at least 1m0s")
+
+	require.NoError(t, ss.Remove("tip"))
+	require.EqualError(t, ss.Remove("tip"), `schedule "tip" not found`)
+	require.Equal(t, []Schedule{rent}, ss.Schedules)
+
+	// The missed runs are skipped
+	require.Equal(t, start.Add(time.Hour), tip.next(start))
+	require.Equal(t, start.Add(time.Hour), tip.next(start.Add(time.Minute)))
+	require.Equal(t, start.Add(4*time.Hour), tip.next(start.Add(3*time.Hour)))
+	require.Equal(t, start, tip.next(start.Add(-time.Minute)))
+
+	txid := "ca7f3f1e9c4e8b4f1f6d86b2ed1b0f0c3d1b3e1e4f67b1a8e42a5f0d6e1a2b3c"
+	tip.LastTxid = txid
+	rent.LastError = "wallet is encrypted"
+
+	var out bytes.Buffer
+	require.NoError(t, printSchedules(&out, []Schedule{rent, tip}))
+	require.Equal(t, `NAME  WALLET   TO                                   COINS  EVERY     NEXT PAYMENT          LAST PAYMENT
+rent  foo.wlt  2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv  10     720h0m0s  2019-10-15T00:00:00Z  error: wallet is encrypted
+tip   bar.wlt  2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH  0.5    1h0m0s    2019-10-15T00:00:00Z  `+txid+`
+`, out.String())
+}
+
+func TestLoadSaveSchedules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-schedules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data", schedulesFilename)
+
+	// Schedules that do not exist are empty
+	ss, err := loadSchedules(path)
+	require.NoError(t, err)
+	require.Empty(t, ss.Schedules)
+
+	require.NoError(t, ss.Add(Schedule{
+		Name:     "rent",
+		Wallet:   "foo.wlt",
+		To:       scheduleAddr1,
+		Coins:    "10",
+		Interval: wh.FromDuration(720 * time.Hour),
+		NextRun:  time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC),
+	}))
+	require.NoError(t, saveSchedules(path, ss))
+
+	loaded, err := loadSchedules(path)
+	require.NoError(t, err)
+	require.Equal(t, ss, loaded)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"version":2}`), 0600))
+	_, err = loadSchedules(path)
+	require.Equal(t, ErrSchedulesVersion, err)
+}
+
+type fakeScheduleClient struct {
+	createReqs []api.WalletCreateTransactionRequest
+	injected   []string
+	createErr  error
+	injectErr  error
+}
+
+func (c *fakeScheduleClient) WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error) {
+	c.createReqs = append(c.createReqs, req)
+	if c.createErr != nil {
+		return nil, c.createErr
+	}
+
+	return &api.CreateTransactionResponse{
+		Transaction: api.CreatedTransaction{
+			TxID: "txid-" + req.WalletID,
+		},
+		EncodedTransaction: "encoded-" + req.WalletID,
+	}, nil
+}
+
+func (c *fakeScheduleClient) InjectEncodedTransaction(rawTxn string) (string, error) {
+	if c.injectErr != nil {
+		return "", c.injectErr
+	}
+
+	c.injected = append(c.injected, rawTxn)
+	return "", nil
+}
+
+func TestScheduleRunner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-schedules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, schedulesFilename)
+
+	start := time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)
+	rent := Schedule{
+		Name:     "rent",
+		Wallet:   "foo.wlt",
+		To:       scheduleAddr1,
+		Coins:    "10",
+		Interval: wh.FromDuration(720 * time.Hour),
+		NextRun:  start,
+	}
+	tip := Schedule{
+		Name:     "tip",
+		Wallet:   "bar.wlt",
+		To:       scheduleAddr2,
+		Coins:    "0.5",
+		Interval: wh.FromDuration(time.Hour),
+		NextRun:  start.Add(30 * time.Minute),
+	}
+
+	require.NoError(t, saveSchedules(path, &Schedules{
+		Schedules: []Schedule{rent, tip},
+	}))
+
+	c := &fakeScheduleClient{}
+	r := &scheduleRunner{
+		client: c,
+		path:   path,
+		passwords: map[string]*secret.Buffer{
+			"foo.wlt": secret.FromBytes([]byte("pwd")),
+		},
+	}
+	defer r.destroy()
+
+	// Only rent is due
+	payments, err := r.runDue(start)
+	require.NoError(t, err)
+	require.Equal(t, []SchedulePayment{
+		{
+			Schedule: "rent",
+			Time:     start,
+			Wallet:   "foo.wlt",
+			To:       scheduleAddr1,
+			Coins:    "10",
+			Txid:     "txid-foo.wlt",
+		},
+	}, payments)
+	require.Equal(t, []api.WalletCreateTransactionRequest{
+		{
+			WalletID: "foo.wlt",
+			Password: "pwd",
+			CreateTransactionRequest: api.CreateTransactionRequest{
+				HoursSelection: api.HoursSelection{
+					Type:        transaction.HoursSelectionTypeAuto,
+					Mode:        transaction.HoursSelectionModeShare,
+					ShareFactor: "0.5",
+				},
+				To: []api.Receiver{
+					{
+						Address: scheduleAddr1,
+						Coins:   "10",
+					},
+				},
+			},
+		},
+	}, c.createReqs)
+	require.Equal(t, []string{"encoded-foo.wlt"}, c.injected)
+
+	ss, err := loadSchedules(path)
+	require.NoError(t, err)
+	require.Equal(t, start.Add(720*time.Hour), ss.Schedules[0].NextRun)
+	require.Equal(t, start, *ss.Schedules[0].LastRun)
+	require.Equal(t, "txid-foo.wlt", ss.Schedules[0].LastTxid)
+	require.Equal(t, tip, ss.Schedules[1])
+
+	// Nothing is due
+	c.createReqs = nil
+	payments, err = r.runDue(start.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, payments)
+	require.Empty(t, c.createReqs)
+
+	// A failed broadcast restores the schedule to retry the payment,
+	// tip signs in the unlock session of bar.wlt without a password
+	c.injectErr = errors.New("inject failed")
+	t1 := start.Add(time.Hour)
+	payments, err = r.runDue(t1)
+	require.NoError(t, err)
+	require.Equal(t, []SchedulePayment{
+		{
+			Schedule: "tip",
+			Time:     t1,
+			Wallet:   "bar.wlt",
+			To:       scheduleAddr2,
+			Coins:    "0.5",
+			Error:    "inject failed",
+		},
+	}, payments)
+	require.Equal(t, "", c.createReqs[0].Password)
+
+	ss, err = loadSchedules(path)
+	require.NoError(t, err)
+	failed := tip
+	failed.LastError = "inject failed"
+	require.Equal(t, failed, ss.Schedules[1])
+
+	// A failed transaction keeps the schedule due
+	c.injectErr = nil
+	c.createErr = errors.New("insufficient balance")
+	payments, err = r.runDue(t1)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	require.Equal(t, "insufficient balance", payments[0].Error)
+
+	ss, err = loadSchedules(path)
+	require.NoError(t, err)
+	failed.LastError = "insufficient balance"
+	require.Equal(t, failed, ss.Schedules[1])
+
+	// The retried payment succeeds, the runs missed are skipped
+	c.createErr = nil
+	t2 := start.Add(3*time.Hour + time.Minute)
+	payments, err = r.runDue(t2)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	require.Equal(t, "txid-bar.wlt", payments[0].Txid)
+
+	ss, err = loadSchedules(path)
+	require.NoError(t, err)
+	require.Equal(t, start.Add(3*time.Hour+30*time.Minute), ss.Schedules[1].NextRun)
+	require.Equal(t, "", ss.Schedules[1].LastError)
+
+	var out bytes.Buffer
+	require.NoError(t, printSchedulePayments(&out, false, []SchedulePayment{
+		payments[0],
+		{
+			Schedule: "rent",
+			Time:     t2,
+			Wallet:   "foo.wlt",
+			To:       scheduleAddr1,
+			Coins:    "10",
+			Error:    "insufficient balance",
+		},
+	}))
+	require.Equal(t, `2019-10-15T03:01:00Z tip: 0.5 coins from bar.wlt to 2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH, txid:txid-bar.wlt
+2019-10-15T03:01:00Z rent: 10 coins from foo.wlt to 2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv failed: insufficient balance
+`, out.String())
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -16,31 +17,49 @@ func sendCmd() *cobra.Command {
     Note: the [amount] argument is the coins you will spend, 1 coins = 1e6 droplets.
 
     The [to address] and [amount] arguments can be replaced with the --many/-m option.
+    The [to address] can be the name of a contact of the address book, also in the --many/-m option.
 
     If you are sending from a wallet without specifying an address,
     the transaction will use one or more of the addresses within the wallet.
 
+    With the --dry-run option the transaction is created but neither signed nor sent,
+    its inputs, outputs, change, hours burned and size are printed.
+
     Use caution when using the “-p” command. If you have command history enabled
     your wallet encryption password can be recovered from the history log.
     If you do not include the “-p” option you will be prompted to enter your password
     after you enter your command.`,
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
-			rawTxn, err := createRawTxnCmdHandler(c, args)
+			dryRun, err := c.Flags().GetBool("dry-run")
 			if err != nil {
-				printHelp(c)
 				return err
 			}
 
-			txid, err := apiClient.InjectTransaction(rawTxn)
+			jsonOutput, err := c.Flags().GetBool("json")
 			if err != nil {
 				return err
 			}
 
-			jsonOutput, err := c.Flags().GetBool("json")
+			if dryRun {
+				preview, err := previewRawTxnCmdHandler(c, args)
+				if err != nil {
+					return err
+				}
+				return printTransactionPreviews(os.Stdout, jsonOutput, preview)
+			}
+
+			rawTxn, err := createRawTxnCmdHandler(c, args)
+			if err != nil {
+				printHelp(c)
+				return err
+			}
+
+			txid, err := apiClient.InjectTransaction(rawTxn)
 			if err != nil {
 				return err
 			}
+
 			if jsonOutput {
 				return printJSON(struct {
 					Txid string `json:"txid"`
@@ -62,6 +81,7 @@ example: -m '[{"addr":"$addr1", "coins": "10.2"}, {"addr":"$addr2", "coins": "20
 	sendCmd.Flags().StringP("password", "p", "", "Wallet password")
 	sendCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
 	sendCmd.Flags().String("csv", "", "CSV file containing addresses and amounts to send")
+	addDryRunFlag(sendCmd)
 
 	return sendCmd
 }
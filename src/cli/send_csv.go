@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// transactionOutputSize is the encoded size of a transaction output: the address (21 bytes), coins and hours (8 bytes each)
+const transactionOutputSize = 37
+
+// PaymentsSummary is printed by the sendFromCSV command before sending the transactions
+type PaymentsSummary struct {
+	Payments     int                  `json:"payments"`
+	Coins        string               `json:"coins"`
+	Hours        uint64               `json:"hours,omitempty"`
+	Transactions []PaymentTransaction `json:"transactions"`
+}
+
+// PaymentTransaction is a transaction created by the sendFromCSV command
+type PaymentTransaction struct {
+	Txid     string `json:"txid"`
+	Payments int    `json:"payments"`
+	Inputs   int    `json:"inputs"`
+	Size     uint32 `json:"size"`
+	Fee      string `json:"fee"`
+	// Sent is true if the transaction was sent to the network
+	Sent bool `json:"sent"`
+}
+
+// paymentsClient is the API client used by the sendFromCSV command
+type paymentsClient interface {
+	WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error)
+	InjectEncodedTransaction(rawTxn string) (string, error)
+}
+
+func sendFromCSVCmd() *cobra.Command {
+	sendFromCSVCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		Short: "Send skycoin from a wallet to the addresses of a CSV file",
+		Use:   "sendFromCSV [wallet] [csv file]",
+		Long: fmt.Sprintf(`Send skycoin from a wallet to the addresses of a CSV file, e.g. for payrolls or airdrops.
+
+    Each row of the CSV file is address,amount[,hours], a first row starting with "address" is skipped as a header.
+    The amount is the coins to send, with decimal formatting, e.g. 1, 1.001 or 1.000000.
+    The hours must be set in all rows or none, if none the hours are distributed by the share factor.
+
+    All rows are validated before any transaction is created. The payments are split in transactions
+    of at most --batch-size payments, and smaller transactions if a transaction exceeds the maximum size.
+    The summary of the transactions is shown and the transactions are sent after confirmation.
+    With --dry-run the details of the transactions are printed instead and they are neither signed nor sent.
+    The transactions do not spend the outputs of each other, the wallet must have enough confirmed coins for all of them.
+
+    The default batch size is %d payments, half of the maximum transaction size.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, defaultPaymentsBatchSize()),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			yes, err := c.Flags().GetBool("yes")
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput && !yes && !dryRun {
+				return errors.New("--json requires --yes or --dry-run")
+			}
+
+			batchSize, err := c.Flags().GetInt("batch-size")
+			if err != nil {
+				return err
+			}
+			if batchSize <= 0 {
+				return errors.New("--batch-size must be positive")
+			}
+
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			payments, err := parsePaymentsCSV(f)
+			if err != nil {
+				return err
+			}
+
+			req, err := makeWalletCreateTxnRequestTo(c, args[0], dryRun, func() ([]api.Receiver, error) {
+				return payments, nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if payments[0].Hours != "" {
+				req.HoursSelection = api.HoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				}
+			}
+
+			summary, txns, err := createPaymentTransactions(apiClient, *req, payments, batchSize)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				previews := make([]*TransactionPreview, len(txns))
+				for i, txn := range txns {
+					previews[i] = newTransactionPreview(txn.Transaction, summary.Transactions[i].Payments)
+				}
+				return printTransactionPreviews(os.Stdout, jsonOutput, previews...)
+			}
+
+			if !yes {
+				printPaymentsSummary(os.Stdout, summary)
+
+				ok, err := confirmPayments(os.Stdin, os.Stdout, len(txns))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("No transaction was sent")
+					return nil
+				}
+			}
+
+			sendErr := sendPaymentTransactions(apiClient, summary, txns)
+
+			if jsonOutput {
+				if err := printJSON(summary); err != nil {
+					return err
+				}
+			} else {
+				for _, t := range summary.Transactions {
+					if t.Sent {
+						fmt.Printf("txid:%s\n", t.Txid)
+					}
+				}
+			}
+
+			return sendErr
+		},
+	}
+
+	sendFromCSVCmd.Flags().StringP("from-address", "a", "", "From address in wallet")
+	sendFromCSVCmd.Flags().StringP("change-address", "c", "", `Specify the change address.
+Defaults to one of the spending addresses (deterministic wallets) or to a new change address (bip44 wallets).`)
+	sendFromCSVCmd.Flags().StringP("password", "p", "", "Wallet password")
+	sendFromCSVCmd.Flags().Int("batch-size", defaultPaymentsBatchSize(), "Maximum number of payments of a transaction")
+	sendFromCSVCmd.Flags().BoolP("yes", "y", false, "Send the transactions without confirmation")
+	sendFromCSVCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format, requires --yes or --dry-run")
+	addDryRunFlag(sendFromCSVCmd)
+
+	sendFromCSVCmd.Flags().Bool("ignore-unconfirmed", false, "Ignore unconfirmed transactions")
+	sendFromCSVCmd.Flags().String("hours-selection-type", transaction.HoursSelectionTypeAuto, "Hours selection type, manual if the CSV file has hours")
+	sendFromCSVCmd.Flags().String("hours-selection-mode", transaction.HoursSelectionModeShare, "Hours selection mode")
+	sendFromCSVCmd.Flags().String("hours-selection-share-factor", "0.5", "Hour selection share factor")
+
+	return sendFromCSVCmd
+}
+
+// defaultPaymentsBatchSize returns the number of payments that take half of the maximum transaction size,
+// leaving the other half to the inputs and the change
+func defaultPaymentsBatchSize() int {
+	return int(params.UserVerifyTxn.MaxTransactionSize/2) / transactionOutputSize
+}
+
+// parsePaymentsCSV reads the address,amount[,hours] rows of a CSV file. All rows are validated,
+// the errors of all invalid rows are returned.
+func parsePaymentsCSV(r io.Reader) ([]api.Receiver, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) != 0 && strings.EqualFold(strings.TrimSpace(rows[0][0]), "address") {
+		rows = rows[1:]
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("No payments in the CSV file")
+	}
+
+	type payment struct {
+		addr  string
+		coins uint64
+		hours string
+	}
+
+	var payments []api.Receiver
+	var errs []string
+	withHours := -1
+	rowsByPayment := make(map[payment]int, len(rows))
+
+	for i, row := range rows {
+		if len(row) != 2 && len(row) != 3 {
+			errs = append(errs, fmt.Sprintf("[row %d] Must have 2 or 3 fields, address,amount[,hours], has %d", i, len(row)))
+			continue
+		}
+
+		addr := strings.TrimSpace(row[0])
+		if _, err := cipher.DecodeBase58Address(addr); err != nil {
+			errs = append(errs, fmt.Sprintf("[row %d] Invalid address %s: %v", i, addr, err))
+			continue
+		}
+
+		amount := strings.TrimSpace(row[1])
+		coins, err := droplet.FromString(amount)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("[row %d] Invalid amount %s: %v", i, amount, err))
+			continue
+		}
+		if coins == 0 {
+			errs = append(errs, fmt.Sprintf("[row %d] Amount must not be zero", i))
+			continue
+		}
+		if coins%params.UserVerifyTxn.MaxDropletDivisor() != 0 {
+			errs = append(errs, fmt.Sprintf("[row %d] Amount %s has too many decimal places", i, amount))
+			continue
+		}
+
+		var hours string
+		if len(row) == 3 {
+			hours = strings.TrimSpace(row[2])
+			if _, err := strconv.ParseUint(hours, 10, 64); err != nil {
+				errs = append(errs, fmt.Sprintf("[row %d] Invalid hours %s: %v", i, hours, err))
+				continue
+			}
+		}
+
+		rowHasHours := 0
+		if len(row) == 3 {
+			rowHasHours = 1
+		}
+		if withHours == -1 {
+			withHours = rowHasHours
+		} else if withHours != rowHasHours {
+			errs = append(errs, fmt.Sprintf("[row %d] Hours must be set in all rows or none", i))
+			continue
+		}
+
+		// A transaction can't have duplicate outputs
+		p := payment{
+			addr:  addr,
+			coins: coins,
+			hours: hours,
+		}
+		if j, ok := rowsByPayment[p]; ok {
+			errs = append(errs, fmt.Sprintf("[row %d] Duplicate of row %d", i, j))
+			continue
+		}
+		rowsByPayment[p] = i
+
+		payments = append(payments, api.Receiver{
+			Address: addr,
+			Coins:   amount,
+			Hours:   hours,
+		})
+	}
+
+	if len(errs) != 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+
+	return payments, nil
+}
+
+// createPaymentTransactions creates the transactions sending the payments, of at most batchSize payments.
+// A transaction that exceeds the maximum transaction size is split in half, and so are the next batches.
+// The transactions do not spend the same outputs.
+func createPaymentTransactions(c paymentsClient, req api.WalletCreateTransactionRequest, payments []api.Receiver, batchSize int) (*PaymentsSummary, []*api.CreateTransactionResponse, error) {
+	summary := &PaymentsSummary{
+		Payments: len(payments),
+	}
+
+	var coins uint64
+	for _, p := range payments {
+		c, err := droplet.FromString(p.Coins)
+		if err != nil {
+			return nil, nil, err
+		}
+		coins, err = mathutil.AddUint64(coins, c)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if p.Hours != "" {
+			h, err := strconv.ParseUint(p.Hours, 10, 64)
+			if err != nil {
+				return nil, nil, err
+			}
+			summary.Hours, err = mathutil.AddUint64(summary.Hours, h)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var err error
+	summary.Coins, err = droplet.ToString(coins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var txns []*api.CreateTransactionResponse
+	var spent []string
+	rest := payments
+
+	for len(rest) != 0 {
+		n := batchSize
+		if n > len(rest) {
+			n = len(rest)
+		}
+
+		var rsp *api.CreateTransactionResponse
+		for {
+			batchReq := req
+			batchReq.To = rest[:n]
+			batchReq.ExcludeUxOuts = spent
+
+			var err error
+			rsp, err = c.WalletCreateTransaction(batchReq)
+			if err == nil {
+				break
+			}
+
+			// The next batches are not bigger than a batch that fits
+			if n > 1 && strings.Contains(err.Error(), visor.ErrTxnExceedsMaxBlockSize.Error()) {
+				n /= 2
+				batchSize = n
+				continue
+			}
+
+			return nil, nil, fmt.Errorf("Create transaction %d failed: %v", len(txns)+1, err)
+		}
+
+		for _, in := range rsp.Transaction.In {
+			spent = append(spent, in.UxID)
+		}
+
+		txns = append(txns, rsp)
+		summary.Transactions = append(summary.Transactions, PaymentTransaction{
+			Txid:     rsp.Transaction.TxID,
+			Payments: n,
+			Inputs:   len(rsp.Transaction.In),
+			Size:     rsp.Transaction.Length,
+			Fee:      rsp.Transaction.Fee,
+		})
+
+		rest = rest[n:]
+	}
+
+	return summary, txns, nil
+}
+
+// sendPaymentTransactions sends the transactions in order, it stops at the first transaction that fails
+func sendPaymentTransactions(c paymentsClient, summary *PaymentsSummary, txns []*api.CreateTransactionResponse) error {
+	for i, txn := range txns {
+		if _, err := c.InjectEncodedTransaction(txn.EncodedTransaction); err != nil {
+			return fmt.Errorf("Send transaction %d failed, %d of %d transactions were sent: %v", i+1, i, len(txns), err)
+		}
+		summary.Transactions[i].Sent = true
+	}
+	return nil
+}
+
+func printPaymentsSummary(w io.Writer, summary *PaymentsSummary) {
+	fmt.Fprintf(w, "Payments:     %d\n", summary.Payments)
+	fmt.Fprintf(w, "Coins:        %s\n", summary.Coins)
+	if summary.Hours != 0 {
+		fmt.Fprintf(w, "Hours:        %d\n", summary.Hours)
+	}
+	fmt.Fprintf(w, "Transactions: %d\n", len(summary.Transactions))
+	for i, t := range summary.Transactions {
+		fmt.Fprintf(w, "  %d. %s payments:%d inputs:%d size:%d fee:%s\n", i+1, t.Txid, t.Payments, t.Inputs, t.Size, t.Fee)
+	}
+}
+
+// confirmPayments asks to confirm sending the transactions, only "y" or "yes" confirms
+func confirmPayments(r io.Reader, w io.Writer, n int) (bool, error) {
+	fmt.Fprintf(w, "Send %d transaction(s)? [y/N]: ", n)
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestParsePaymentsCSV(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+
+	tt := []struct {
+		name     string
+		csv      string
+		payments []api.Receiver
+		err      string
+	}{
+		{
+			name: "no hours",
+			csv:  fmt.Sprintf("%s,1\n%s, 0.5\n", addr1, addr2),
+			payments: []api.Receiver{
+				{Address: addr1, Coins: "1"},
+				{Address: addr2, Coins: "0.5"},
+			},
+		},
+		{
+			name: "header and hours",
+			csv:  fmt.Sprintf("address,amount,hours\n%s,1,10\n%s,1,10\n", addr1, addr2),
+			payments: []api.Receiver{
+				{Address: addr1, Coins: "1", Hours: "10"},
+				{Address: addr2, Coins: "1", Hours: "10"},
+			},
+		},
+		{
+			name: "empty",
+			csv:  "address,amount\n",
+			err:  "No payments in the CSV file",
+		},
+		{
+			name: "invalid rows",
+			csv: strings.Join([]string{
+				addr1,
+				"foo,1",
+				fmt.Sprintf("%s,0", addr1),
+				fmt.Sprintf("%s,0.0001", addr1),
+				fmt.Sprintf("%s,1,x", addr1),
+				fmt.Sprintf("%s,1", addr1),
+				fmt.Sprintf("%s,2,1", addr1),
+				fmt.Sprintf("%s,1", addr1),
+			}, "\n"),
+			err: strings.Join([]string{
+				"[row 0] Must have 2 or 3 fields, address,amount[,hours], has 1",
+				"[row 1] Invalid address foo: Invalid address length",
+				"[row 2] Amount must not be zero",
+				"[row 3] Amount 0.0001 has too many decimal places",
+				`[row 4] Invalid hours x: strconv.ParseUint: parsing "x": invalid syntax`,
+				"[row 6] Hours must be set in all rows or none",
+				"[row 7] Duplicate of row 5",
+			}, "\n"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			payments, err := parsePaymentsCSV(strings.NewReader(tc.csv))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.payments, payments)
+		})
+	}
+}
+
+type fakePaymentsClient struct {
+	// maxOutputs is the number of outputs above which a transaction exceeds the maximum size
+	maxOutputs int
+	requests   []api.WalletCreateTransactionRequest
+	injectErr  error
+	injected   []string
+}
+
+func (c *fakePaymentsClient) WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error) {
+	c.requests = append(c.requests, req)
+
+	if len(req.To) > c.maxOutputs {
+		return nil, errors.New("400 Bad Request - Transaction size bigger than max block size")
+	}
+
+	n := len(c.requests)
+	return &api.CreateTransactionResponse{
+		Transaction: api.CreatedTransaction{
+			TxID:   fmt.Sprintf("txid%d", n),
+			Length: uint32(len(req.To)),
+			Fee:    "1",
+			In: []api.CreatedTransactionInput{
+				{UxID: fmt.Sprintf("uxid%d", n)},
+			},
+		},
+		EncodedTransaction: fmt.Sprintf("txn%d", n),
+	}, nil
+}
+
+func (c *fakePaymentsClient) InjectEncodedTransaction(rawTxn string) (string, error) {
+	if c.injectErr != nil && len(c.injected) == 1 {
+		return "", c.injectErr
+	}
+	c.injected = append(c.injected, rawTxn)
+	return rawTxn, nil
+}
+
+func TestCreatePaymentTransactions(t *testing.T) {
+	var payments []api.Receiver
+	for i := 0; i < 5; i++ {
+		payments = append(payments, api.Receiver{
+			Address: testutil.MakeAddress().String(),
+			Coins:   "1.5",
+			Hours:   "2",
+		})
+	}
+
+	c := &fakePaymentsClient{maxOutputs: 2}
+	summary, txns, err := createPaymentTransactions(c, api.WalletCreateTransactionRequest{}, payments, 4)
+	require.NoError(t, err)
+	require.Len(t, txns, 3)
+
+	// The first batch of 4 payments is too big, it is split in half and so are the next batches
+	var sizes []int
+	for _, r := range c.requests {
+		sizes = append(sizes, len(r.To))
+	}
+	require.Equal(t, []int{4, 2, 2, 1}, sizes)
+
+	require.Empty(t, c.requests[1].ExcludeUxOuts)
+	require.Equal(t, []string{"uxid2"}, c.requests[2].ExcludeUxOuts)
+	require.Equal(t, []string{"uxid2", "uxid3"}, c.requests[3].ExcludeUxOuts)
+
+	require.Equal(t, &PaymentsSummary{
+		Payments: 5,
+		Coins:    "7.500000",
+		Hours:    10,
+		Transactions: []PaymentTransaction{
+			{Txid: "txid2", Payments: 2, Inputs: 1, Size: 2, Fee: "1"},
+			{Txid: "txid3", Payments: 2, Inputs: 1, Size: 2, Fee: "1"},
+			{Txid: "txid4", Payments: 1, Inputs: 1, Size: 1, Fee: "1"},
+		},
+	}, summary)
+
+	var out bytes.Buffer
+	printPaymentsSummary(&out, summary)
+	require.Equal(t, `Payments:     5
+Coins:        7.500000
+Hours:        10
+Transactions: 3
+  1. txid2 payments:2 inputs:1 size:2 fee:1
+  2. txid3 payments:2 inputs:1 size:2 fee:1
+  3. txid4 payments:1 inputs:1 size:1 fee:1
+`, out.String())
+
+	// The transactions are sent up to the first failure
+	c.injectErr = errors.New("500 Internal Server Error")
+	err = sendPaymentTransactions(c, summary, txns)
+	require.EqualError(t, err, "Send transaction 2 failed, 1 of 3 transactions were sent: 500 Internal Server Error")
+	require.Equal(t, []string{"txn2"}, c.injected)
+	require.True(t, summary.Transactions[0].Sent)
+	require.False(t, summary.Transactions[1].Sent)
+	require.False(t, summary.Transactions[2].Sent)
+
+	// Other errors are returned
+	c = &fakePaymentsClient{maxOutputs: 0}
+	_, _, err = createPaymentTransactions(c, api.WalletCreateTransactionRequest{}, payments[:1], 3)
+	require.EqualError(t, err, "Create transaction 1 failed: 400 Bad Request - Transaction size bigger than max block size")
+}
+
+func TestConfirmPayments(t *testing.T) {
+	tt := []struct {
+		answer string
+		ok     bool
+	}{
+		{"y\n", true},
+		{" YES \n", true},
+		{"yes", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"yess\n", false},
+	}
+
+	for _, tc := range tt {
+		var out bytes.Buffer
+		ok, err := confirmPayments(strings.NewReader(tc.answer), &out, 2)
+		require.NoError(t, err)
+		require.Equal(t, tc.ok, ok, "%q", tc.answer)
+		require.Equal(t, "Send 2 transaction(s)? [y/N]: ", out.String())
+	}
+}
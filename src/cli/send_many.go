@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+)
+
+// sendManyClient is the API client used by the sendMany command
+type sendManyClient interface {
+	WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error)
+	VerifyTransaction(req api.VerifyTransactionRequest) (*api.VerifyTransactionResponse, error)
+	WalletSignTransaction(req api.WalletSignTransactionRequest) (*api.CreateTransactionResponse, error)
+}
+
+func sendManyCmd() *cobra.Command {
+	sendManyCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Send skycoin from a wallet to many recipients of a JSON spec file",
+		Use:   "sendMany [wallet]",
+		Long: `Send skycoin from a wallet to the recipients of a JSON spec file, with the hours distribution,
+    the change address and optionally the addresses or the outputs to spend. The spec has the fields
+    of the /api/v1/wallet/transaction request:
+
+    {
+        "hours_selection": {"type": "auto", "mode": "share", "share_factor": "0.5"},
+        "change_address": "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv",
+        "to": [
+            {"address": "2UrEV3Vyu5RJABZNukKRq25ggrrg96RUwdH", "coins": "10.5"},
+            {"address": "2mEgmYt6NZHA1erYqbAeXmGPD5gqLZ9toFv", "coins": "2"}
+        ],
+        "addresses": ["2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"],
+        "unspents": ["8c9a7fdd0f2ad1bbe1f1c2d0c4c6bdc1b39f4aa2a3e34b3ccf1e8b7e3ba6c2a5"],
+        "ignore_unconfirmed": false
+    }
+
+    The hours_selection type is required, "manual" if the recipients have hours, else "auto".
+    The recipient addresses can be replaced with the names of contacts of the address book.
+    The addresses and unspents are optional and can't be used together.
+
+    The spec is validated, then an unsigned transaction is created and verified by the node.
+    The summary of the transaction is shown and the transaction is signed and sent after confirmation.
+    With --dry-run the details of the transaction are printed instead and it is neither signed nor sent.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			yes, err := c.Flags().GetBool("yes")
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput && !yes && !dryRun {
+				return errors.New("--json requires --yes or --dry-run")
+			}
+
+			specFile, err := c.Flags().GetString("spec")
+			if err != nil {
+				return err
+			}
+			if specFile == "" {
+				return errors.New("missing --spec file")
+			}
+
+			f, err := os.Open(specFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			spec, err := parseSendManySpec(f, newContactResolver())
+			if err != nil {
+				return err
+			}
+
+			wlt, err := apiClient.Wallet(args[0])
+			if err != nil {
+				return err
+			}
+
+			var password string
+			if wlt.Meta.Encrypted && !dryRun {
+				p, err := getPassword(c)
+				if err != nil {
+					return err
+				}
+				password = string(p)
+			}
+
+			txn, err := createSendManyTransaction(apiClient, wlt.Meta.Filename, *spec)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				return printTransactionPreviews(os.Stdout, jsonOutput, newTransactionPreview(txn.Transaction, len(spec.To)))
+			}
+
+			if !yes {
+				printSendManySummary(os.Stdout, txn)
+
+				ok, err := confirmPayments(os.Stdin, os.Stdout, 1)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("No transaction was sent")
+					return nil
+				}
+			}
+
+			signed, err := apiClient.WalletSignTransaction(api.WalletSignTransactionRequest{
+				WalletID:           wlt.Meta.Filename,
+				Password:           password,
+				EncodedTransaction: txn.EncodedTransaction,
+			})
+			if err != nil {
+				return err
+			}
+
+			txid, err := apiClient.InjectEncodedTransaction(signed.EncodedTransaction)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					Txid string `json:"txid"`
+				}{
+					Txid: txid,
+				})
+			}
+
+			fmt.Printf("txid:%s\n", txid)
+			return nil
+		},
+	}
+
+	sendManyCmd.Flags().String("spec", "", "JSON spec file of the transaction")
+	sendManyCmd.Flags().StringP("password", "p", "", "Wallet password")
+	sendManyCmd.Flags().BoolP("yes", "y", false, "Send the transaction without confirmation")
+	sendManyCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format, requires --yes or --dry-run")
+	addDryRunFlag(sendManyCmd)
+
+	return sendManyCmd
+}
+
+// parseSendManySpec reads and validates a sendMany spec, the contact names of the recipients are resolved with contacts.
+// All the fields are validated, the errors of all invalid fields are returned.
+func parseSendManySpec(r io.Reader, contacts *contactResolver) (*api.CreateTransactionRequest, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var spec api.CreateTransactionRequest
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+
+	var errs []string
+
+	switch spec.HoursSelection.Type {
+	case transaction.HoursSelectionTypeManual:
+	case transaction.HoursSelectionTypeAuto:
+		if spec.HoursSelection.Mode == "" {
+			errs = append(errs, "hours_selection.mode is required for auto hours selection")
+		}
+	case "":
+		errs = append(errs, "hours_selection.type is required")
+	default:
+		errs = append(errs, fmt.Sprintf("invalid hours_selection.type %q", spec.HoursSelection.Type))
+	}
+
+	if spec.ChangeAddress != nil {
+		if _, err := cipher.DecodeBase58Address(*spec.ChangeAddress); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid change_address %s: %v", *spec.ChangeAddress, err))
+		}
+	}
+
+	if len(spec.Addresses) != 0 && len(spec.UxOuts) != 0 {
+		errs = append(errs, "addresses and unspents can't be used together")
+	}
+
+	for i, a := range spec.Addresses {
+		if _, err := cipher.DecodeBase58Address(a); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid addresses[%d] %s: %v", i, a, err))
+		}
+	}
+
+	for i, h := range spec.UxOuts {
+		if _, err := cipher.SHA256FromHex(h); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid unspents[%d] %s: %v", i, h, err))
+		}
+	}
+
+	if len(spec.To) == 0 {
+		errs = append(errs, "to is required")
+	}
+
+	for i, to := range spec.To {
+		if to.Address != "" {
+			addr, err := contacts.resolve(to.Address)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("invalid to[%d] address: %v", i, err))
+				continue
+			}
+			spec.To[i].Address = addr
+			to.Address = addr
+		}
+
+		switch {
+		case to.Address != "" && to.Name != "":
+			errs = append(errs, fmt.Sprintf("to[%d] can't have both an address and a name", i))
+		case to.Address != "":
+			if _, err := cipher.DecodeBase58Address(to.Address); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid to[%d] address %s: %v", i, to.Address, err))
+			}
+		case to.Name == "":
+			errs = append(errs, fmt.Sprintf("to[%d] address is required", i))
+		}
+
+		coins, err := droplet.FromString(to.Coins)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("invalid to[%d] coins %s: %v", i, to.Coins, err))
+		case coins == 0:
+			errs = append(errs, fmt.Sprintf("to[%d] coins must not be zero", i))
+		case coins%params.UserVerifyTxn.MaxDropletDivisor() != 0:
+			errs = append(errs, fmt.Sprintf("to[%d] coins %s has too many decimal places", i, to.Coins))
+		}
+
+		switch spec.HoursSelection.Type {
+		case transaction.HoursSelectionTypeManual:
+			if _, err := strconv.ParseUint(to.Hours, 10, 64); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid to[%d] hours %q: %v", i, to.Hours, err))
+			}
+		case transaction.HoursSelectionTypeAuto:
+			if to.Hours != "" {
+				errs = append(errs, fmt.Sprintf("to[%d] hours must not be set for auto hours selection", i))
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+
+	return &spec, nil
+}
+
+// createSendManyTransaction creates the unsigned transaction of the spec and verifies it with the node
+func createSendManyTransaction(c sendManyClient, walletID string, spec api.CreateTransactionRequest) (*api.CreateTransactionResponse, error) {
+	txn, err := c.WalletCreateTransaction(api.WalletCreateTransactionRequest{
+		Unsigned:                 true,
+		WalletID:                 walletID,
+		CreateTransactionRequest: spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.VerifyTransaction(api.VerifyTransactionRequest{
+		Unsigned:           true,
+		EncodedTransaction: txn.EncodedTransaction,
+	}); err != nil {
+		return nil, fmt.Errorf("transaction verification failed: %v", err)
+	}
+
+	return txn, nil
+}
+
+func printSendManySummary(w io.Writer, txn *api.CreateTransactionResponse) {
+	t := txn.Transaction
+	fmt.Fprintf(w, "Inputs:  %d\n", len(t.In))
+	fmt.Fprintf(w, "Outputs: %d\n", len(t.Out))
+	for i, o := range t.Out {
+		fmt.Fprintf(w, "  %d. %s coins:%s hours:%s\n", i+1, o.Address, o.Coins, o.Hours)
+	}
+	fmt.Fprintf(w, "Fee:     %s\n", t.Fee)
+	fmt.Fprintf(w, "Size:    %d\n", t.Length)
+}
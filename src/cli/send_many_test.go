@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+func TestParseSendManySpec(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+	uxid := testutil.RandSHA256(t).Hex()
+
+	contacts, cleanup := newTestContactResolver(t, Contact{Name: "alice", Address: addr2})
+	defer cleanup()
+
+	tt := []struct {
+		name string
+		spec string
+		req  *api.CreateTransactionRequest
+		err  string
+	}{
+		{
+			name: "auto hours with change address and unspents",
+			spec: `{
+				"hours_selection": {"type": "auto", "mode": "share", "share_factor": "0.5"},
+				"change_address": "` + addr1 + `",
+				"to": [
+					{"address": "` + addr1 + `", "coins": "10.5"},
+					{"address": "alice", "coins": "2"}
+				],
+				"unspents": ["` + uxid + `"]
+			}`,
+			req: &api.CreateTransactionRequest{
+				HoursSelection: api.HoursSelection{
+					Type:        transaction.HoursSelectionTypeAuto,
+					Mode:        transaction.HoursSelectionModeShare,
+					ShareFactor: "0.5",
+				},
+				ChangeAddress: &addr1,
+				To: []api.Receiver{
+					{Address: addr1, Coins: "10.5"},
+					{Address: addr2, Coins: "2"},
+				},
+				UxOuts: []string{uxid},
+			},
+		},
+		{
+			name: "manual hours with addresses",
+			spec: `{
+				"hours_selection": {"type": "manual"},
+				"to": [{"address": "` + addr2 + `", "coins": "1", "hours": "5"}],
+				"addresses": ["` + addr1 + `"],
+				"ignore_unconfirmed": true
+			}`,
+			req: &api.CreateTransactionRequest{
+				IgnoreUnconfirmed: true,
+				HoursSelection: api.HoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				},
+				To: []api.Receiver{
+					{Address: addr2, Coins: "1", Hours: "5"},
+				},
+				Addresses: []string{addr1},
+			},
+		},
+		{
+			name: "unknown field",
+			spec: `{"hours_selection": {"type": "manual"}, "receivers": []}`,
+			err:  `invalid spec: json: unknown field "receivers"`,
+		},
+		{
+			name: "missing fields",
+			spec: `{}`,
+			err: strings.Join([]string{
+				"hours_selection.type is required",
+				"to is required",
+			}, "\n"),
+		},
+		{
+			name: "invalid fields",
+			spec: `{
+				"hours_selection": {"type": "auto"},
+				"change_address": "foo",
+				"to": [
+					{"coins": "1"},
+					{"address": "foo", "coins": "1"},
+					{"address": "` + addr1 + `", "coins": "0"},
+					{"address": "` + addr1 + `", "name": "bar", "coins": "0.0001"},
+					{"address": "` + addr2 + `", "coins": "x", "hours": "5"}
+				],
+				"addresses": ["foo"],
+				"unspents": ["bar"]
+			}`,
+			err: strings.Join([]string{
+				"hours_selection.mode is required for auto hours selection",
+				"invalid change_address foo: Invalid address length",
+				"addresses and unspents can't be used together",
+				"invalid addresses[0] foo: Invalid address length",
+				"invalid unspents[0] bar: encoding/hex: invalid byte: U+0072 'r'",
+				"to[0] address is required",
+				"invalid to[1] address: foo is neither an address nor a contact of the address book",
+				"to[2] coins must not be zero",
+				"to[3] can't have both an address and a name",
+				"to[3] coins 0.0001 has too many decimal places",
+				"invalid to[4] coins x: can't convert x to decimal",
+				"to[4] hours must not be set for auto hours selection",
+			}, "\n"),
+		},
+		{
+			name: "manual hours without hours",
+			spec: `{"hours_selection": {"type": "manual"}, "to": [{"address": "` + addr1 + `", "coins": "1"}]}`,
+			err:  `invalid to[0] hours "": strconv.ParseUint: parsing "": invalid syntax`,
+		},
+		{
+			name: "invalid hours selection type",
+			spec: `{"hours_selection": {"type": "foo"}, "to": [{"address": "` + addr1 + `", "coins": "1"}]}`,
+			err:  `invalid hours_selection.type "foo"`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := parseSendManySpec(strings.NewReader(tc.spec), contacts)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.req, req)
+		})
+	}
+}
+
+type fakeSendManyClient struct {
+	createReq api.WalletCreateTransactionRequest
+	verifyReq api.VerifyTransactionRequest
+	verifyErr error
+}
+
+func (c *fakeSendManyClient) WalletCreateTransaction(req api.WalletCreateTransactionRequest) (*api.CreateTransactionResponse, error) {
+	c.createReq = req
+	return &api.CreateTransactionResponse{
+		Transaction: api.CreatedTransaction{
+			Length: 220,
+			Fee:    "12",
+			In:     []api.CreatedTransactionInput{{}},
+			Out: []api.CreatedTransactionOutput{
+				{Address: "addr1", Coins: "10.500000", Hours: "6"},
+				{Address: "addr2", Coins: "0.500000", Hours: "6"},
+			},
+		},
+		EncodedTransaction: "unsigned",
+	}, nil
+}
+
+func (c *fakeSendManyClient) VerifyTransaction(req api.VerifyTransactionRequest) (*api.VerifyTransactionResponse, error) {
+	c.verifyReq = req
+	if c.verifyErr != nil {
+		return nil, c.verifyErr
+	}
+	return &api.VerifyTransactionResponse{Unsigned: true}, nil
+}
+
+func (c *fakeSendManyClient) WalletSignTransaction(req api.WalletSignTransactionRequest) (*api.CreateTransactionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestCreateSendManyTransaction(t *testing.T) {
+	spec := api.CreateTransactionRequest{
+		HoursSelection: api.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []api.Receiver{
+			{Address: "addr1", Coins: "10.5", Hours: "6"},
+		},
+	}
+
+	c := &fakeSendManyClient{}
+	txn, err := createSendManyTransaction(c, "foo.wlt", spec)
+	require.NoError(t, err)
+	require.Equal(t, api.WalletCreateTransactionRequest{
+		Unsigned:                 true,
+		WalletID:                 "foo.wlt",
+		CreateTransactionRequest: spec,
+	}, c.createReq)
+	require.Equal(t, api.VerifyTransactionRequest{
+		Unsigned:           true,
+		EncodedTransaction: "unsigned",
+	}, c.verifyReq)
+
+	var out bytes.Buffer
+	printSendManySummary(&out, txn)
+	require.Equal(t, `Inputs:  1
+Outputs: 2
+  1. addr1 coins:10.500000 hours:6
+  2. addr2 coins:0.500000 hours:6
+Fee:     12
+Size:    220
+`, out.String())
+
+	c.verifyErr = errors.New("422 Unprocessable Entity - Transaction violates hard constraint")
+	_, err = createSendManyTransaction(c, "foo.wlt", spec)
+	require.EqualError(t, err, "transaction verification failed: 422 Unprocessable Entity - Transaction violates hard constraint")
+}
@@ -0,0 +1,473 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/util/file"
+)
+
+const (
+	shellSettingsFilename = "cli_shell.json"
+	shellHistoryFilename  = "cli_shell_history"
+)
+
+var (
+	// ErrShellNodeAddress is returned if the node address of the shell settings is invalid
+	ErrShellNodeAddress = errors.New("node must be in scheme://host format")
+
+	// shellBuiltins are the commands handled by the shell rather than the cli
+	shellBuiltins = []string{"exit", "quit", "set", "settings", "unset"}
+	// shellSettingNames are the names of the settings of the set and unset commands
+	shellSettingNames = []string{"node", "wallet"}
+	// shellSecretFlags are the flags whose values are secrets, the lines using them are not saved in the history
+	shellSecretFlags = []string{"p", "password", "s", "seed", "genesis-seckey"}
+	// shellSecretCommands are the commands with secret arguments, their lines are not saved in the history
+	shellSecretCommands = []string{"addPrivateKey", "distributeGenesis"}
+)
+
+// ShellSettings are the session settings of the interactive shell, saved in the data directory
+type ShellSettings struct {
+	// Node is the address of the RPC node, overriding RPC_ADDR
+	Node string `json:"node,omitempty"`
+	// Wallet is the default wallet of the commands with a [wallet] argument
+	Wallet string `json:"wallet,omitempty"`
+}
+
+// loadShellSettings loads the shell settings, the settings are empty if the file does not exist
+func loadShellSettings(path string) (ShellSettings, error) {
+	var s ShellSettings
+	if err := file.LoadJSON(path, &s); err != nil {
+		if os.IsNotExist(err) {
+			return ShellSettings{}, nil
+		}
+		return ShellSettings{}, fmt.Errorf("load shell settings failed: %v", err)
+	}
+	return s, nil
+}
+
+func shellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive shell",
+		Long: fmt.Sprintf(`Start an interactive shell to run the cli commands without the %s-cli prefix.
+
+    The shell has a command history and completes the commands, wallet names and wallet addresses with the tab key.
+    The history and the settings are saved in $DATA_DIR and kept between sessions,
+    the lines with a password, seed or secret key are not saved in the history.
+
+    Shell commands:
+      set node [url]       Use the RPC node at the url instead of RPC_ADDR
+      set wallet [wallet]  Use the wallet when a command with a [wallet] argument is run without a wallet
+      unset node|wallet    Remove a setting
+      settings             Show the settings
+      exit, quit           Exit the shell (or Ctrl-D)`, cliConfig.Coin),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		Args:                  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runShell(cliConfig)
+		},
+	}
+}
+
+func runShell(cfg Config) error {
+	sh, err := newShell(cfg, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	line := liner.NewLiner()
+	defer line.Close()
+
+	line.SetCtrlCAborts(true)
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetWordCompleter(sh.complete)
+
+	historyPath := filepath.Join(cfg.DataDir, shellHistoryFilename)
+	if f, err := os.Open(historyPath); err == nil {
+		_, err := line.ReadHistory(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("read shell history failed: %v", err)
+		}
+	}
+
+	defer func() {
+		if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+			fmt.Fprintf(sh.out, "Save shell history failed: %v\n", err)
+			return
+		}
+
+		f, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			fmt.Fprintf(sh.out, "Save shell history failed: %v\n", err)
+			return
+		}
+		defer f.Close()
+
+		if _, err := line.WriteHistory(f); err != nil {
+			fmt.Fprintf(sh.out, "Save shell history failed: %v\n", err)
+		}
+	}()
+
+	for {
+		input, err := line.Prompt(sh.prompt())
+		switch err {
+		case nil:
+		case liner.ErrPromptAborted:
+			continue
+		case io.EOF:
+			fmt.Fprintln(sh.out)
+			return nil
+		default:
+			return err
+		}
+
+		exit, saveHistory, err := sh.exec(input)
+		if saveHistory {
+			line.AppendHistory(strings.TrimSpace(input))
+		}
+		if err != nil {
+			fmt.Fprintf(sh.out, "Error: %v\n", err)
+		}
+		if exit {
+			return nil
+		}
+	}
+}
+
+// shell runs the lines of the interactive shell
+type shell struct {
+	// cfg is the configuration without the shell settings
+	cfg          Config
+	settings     ShellSettings
+	settingsPath string
+	out          io.Writer
+	// commands are the names of the cli commands
+	commands []string
+	// wallets and addresses are completed from, they are loaded from the node when needed
+	wallets   []string
+	addresses []string
+	loaded    bool
+}
+
+func newShell(cfg Config, out io.Writer) (*shell, error) {
+	settingsPath := filepath.Join(cfg.DataDir, shellSettingsFilename)
+	settings, err := loadShellSettings(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []string
+	for _, c := range newRootCmd(cfg).Commands() {
+		commands = append(commands, c.Name())
+	}
+	commands = append(commands, "help")
+	commands = append(commands, shellBuiltins...)
+	sort.Strings(commands)
+
+	sh := &shell{
+		cfg:          cfg,
+		settings:     settings,
+		settingsPath: settingsPath,
+		out:          out,
+		commands:     commands,
+	}
+	sh.apply()
+
+	return sh, nil
+}
+
+// apply sets the configuration of the commands from the shell settings
+func (sh *shell) apply() {
+	cfg := sh.cfg
+	if sh.settings.Node != "" {
+		cfg.RPCAddress = sh.settings.Node
+	}
+	setConfig(cfg)
+	sh.loaded = false
+}
+
+func (sh *shell) prompt() string {
+	if sh.settings.Wallet != "" {
+		return fmt.Sprintf("%s [%s]> ", sh.cfg.Coin, sh.settings.Wallet)
+	}
+	return fmt.Sprintf("%s> ", sh.cfg.Coin)
+}
+
+// exec runs a line of the shell. Returns whether the shell exits and whether the line can be saved in the history.
+func (sh *shell) exec(input string) (bool, bool, error) {
+	args, err := splitShellLine(input)
+	if err != nil {
+		return false, true, err
+	}
+
+	if len(args) == 0 {
+		return false, false, nil
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true, false, nil
+	case "settings":
+		return false, true, sh.printSettings()
+	case "set":
+		if len(args) != 3 {
+			return false, true, errors.New("usage: set node|wallet [value]")
+		}
+		return false, true, sh.set(args[1], args[2])
+	case "unset":
+		if len(args) != 2 {
+			return false, true, errors.New("usage: unset node|wallet")
+		}
+		return false, true, sh.set(args[1], "")
+	}
+
+	saveHistory := !shellLineHasSecrets(args)
+
+	root := newRootCmd(cliConfig)
+	root.SilenceErrors = true
+	root.SetArgs(sh.withDefaultWallet(root, args))
+
+	// The commands can create wallets and addresses, reload them for the completions
+	sh.loaded = false
+
+	return false, saveHistory, root.Execute()
+}
+
+func (sh *shell) printSettings() error {
+	return printJSON(struct {
+		ShellSettings
+		RPCAddress string `json:"rpc_address"`
+	}{
+		ShellSettings: sh.settings,
+		RPCAddress:    cliConfig.RPCAddress,
+	})
+}
+
+// set sets the setting to value and saves the settings, an empty value removes the setting
+func (sh *shell) set(name, value string) error {
+	settings := sh.settings
+	switch name {
+	case "node":
+		if value != "" {
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return ErrShellNodeAddress
+			}
+		}
+		settings.Node = value
+	case "wallet":
+		if value != "" && !strings.HasSuffix(value, walletExt) {
+			return ErrWalletName
+		}
+		settings.Wallet = value
+	default:
+		return fmt.Errorf("unknown setting %q, must be one of %s", name, strings.Join(shellSettingNames, ", "))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sh.settingsPath), 0700); err != nil {
+		return err
+	}
+	if err := file.SaveJSON(sh.settingsPath, settings, 0600); err != nil {
+		return fmt.Errorf("save shell settings failed: %v", err)
+	}
+
+	sh.settings = settings
+	sh.apply()
+	return nil
+}
+
+// withDefaultWallet adds the default wallet to the arguments of a command with a [wallet] argument,
+// if none of the arguments is a wallet. The wallet of the shell settings is used before the wallet of the profile.
+func (sh *shell) withDefaultWallet(root *cobra.Command, args []string) []string {
+	wlt := sh.settings.Wallet
+	if wlt == "" {
+		wlt = sh.cfg.Wallet
+	}
+	return withDefaultWallet(root, args, wlt)
+}
+
+// withDefaultWallet adds wlt to the arguments of a command with a [wallet] argument,
+// if wlt is set and none of the arguments is a wallet
+func withDefaultWallet(root *cobra.Command, args []string, wlt string) []string {
+	if wlt == "" {
+		return args
+	}
+
+	c, _, err := root.Find(args)
+	if err != nil || c == root {
+		return args
+	}
+
+	if !strings.HasPrefix(c.Use, c.Name()+" [wallet]") {
+		return args
+	}
+
+	for _, a := range args[1:] {
+		if strings.HasSuffix(a, walletExt) {
+			return args
+		}
+	}
+
+	withWallet := make([]string, 0, len(args)+1)
+	withWallet = append(withWallet, args[0], wlt)
+	return append(withWallet, args[1:]...)
+}
+
+// complete implements liner.WordCompleter
+func (sh *shell) complete(line string, pos int) (string, []string, string) {
+	if !sh.loaded {
+		sh.loadWallets()
+	}
+	return completeShellWord(line, pos, sh.commands, sh.wallets, sh.addresses)
+}
+
+// loadWallets loads the wallet names and addresses to complete, they are not completed if the node is unavailable
+func (sh *shell) loadWallets() {
+	sh.wallets = nil
+	sh.addresses = nil
+	sh.loaded = true
+
+	wlts, err := apiClient.Wallets()
+	if err != nil {
+		return
+	}
+
+	for _, w := range wlts {
+		sh.wallets = append(sh.wallets, w.Meta.Filename)
+		for _, e := range w.Entries {
+			sh.addresses = append(sh.addresses, e.Address)
+		}
+	}
+}
+
+// completeShellWord completes the word of line at pos, with the commands for the first word,
+// the setting names and wallets for the set and unset commands, and the wallets and addresses for the arguments
+func completeShellWord(line string, pos int, commands, wallets, addresses []string) (string, []string, string) {
+	if pos > len(line) {
+		pos = len(line)
+	}
+	head, tail := line[:pos], line[pos:]
+
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+	previous := strings.Fields(head[:start])
+
+	var candidates []string
+	switch {
+	case len(previous) == 0:
+		candidates = commands
+	case previous[0] == "set" || previous[0] == "unset":
+		switch {
+		case len(previous) == 1:
+			candidates = shellSettingNames
+		case len(previous) == 2 && previous[0] == "set" && previous[1] == "wallet":
+			candidates = wallets
+		}
+	case previous[0] == "help" && len(previous) == 1:
+		candidates = commands
+	default:
+		candidates = append(append([]string{}, wallets...), addresses...)
+	}
+
+	var completions []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			completions = append(completions, c)
+		}
+	}
+
+	return head[:start], completions, tail
+}
+
+// splitShellLine splits a line of the shell into arguments, separated by whitespace.
+// Single or double quotes group an argument with whitespace and a backslash escapes the next character.
+func splitShellLine(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("unfinished escape at the end of the line")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// shellLineHasSecrets returns true if the arguments of a line may contain a secret
+func shellLineHasSecrets(args []string) bool {
+	for _, c := range shellSecretCommands {
+		if args[0] == c {
+			return true
+		}
+	}
+
+	for _, a := range args[1:] {
+		if !strings.HasPrefix(a, "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(a, "-")
+		if i := strings.Index(name, "="); i != -1 {
+			name = name[:i]
+		}
+
+		for _, f := range shellSecretFlags {
+			// Short flags can be combined with their value, e.g. -pfoo
+			if name == f || (len(f) == 1 && !strings.HasPrefix(a, "--") && strings.HasPrefix(name, f)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitShellLine(t *testing.T) {
+	tt := []struct {
+		name string
+		line string
+		args []string
+		err  string
+	}{
+		{
+			name: "empty",
+			line: "  \t ",
+		},
+		{
+			name: "whitespace",
+			line: " send  foo.wlt\t2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv 1 ",
+			args: []string{"send", "foo.wlt", "2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv", "1"},
+		},
+		{
+			name: "quotes",
+			line: `walletCreate "my label" -p 'foo "bar"' ""`,
+			args: []string{"walletCreate", "my label", "-p", `foo "bar"`, ""},
+		},
+		{
+			name: "escapes",
+			line: `signMessage foo.wlt a\ b "c\"d" 'e\f'`,
+			args: []string{"signMessage", "foo.wlt", "a b", `c"d`, `e\f`},
+		},
+		{
+			name: "unterminated quote",
+			line: `walletCreate "my label`,
+			err:  `unterminated " quote`,
+		},
+		{
+			name: "unfinished escape",
+			line: `walletCreate foo\`,
+			err:  "unfinished escape at the end of the line",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := splitShellLine(tc.line)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestShellLineHasSecrets(t *testing.T) {
+	tt := []struct {
+		args    []string
+		secrets bool
+	}{
+		{[]string{"walletBalance", "foo.wlt"}, false},
+		{[]string{"send", "foo.wlt", "addr", "1", "-p", "pass"}, true},
+		{[]string{"send", "foo.wlt", "addr", "1", "-ppass"}, true},
+		{[]string{"send", "foo.wlt", "addr", "1", "--password=pass"}, true},
+		{[]string{"walletCreate", "--seed", "foo bar"}, true},
+		{[]string{"walletCreate", "--type", "bip44"}, false},
+		{[]string{"addPrivateKey", "foo.wlt", "key"}, true},
+		{[]string{"distributeGenesis", "key"}, true},
+	}
+
+	for _, tc := range tt {
+		require.Equal(t, tc.secrets, shellLineHasSecrets(tc.args), "%v", tc.args)
+	}
+}
+
+func TestCompleteShellWord(t *testing.T) {
+	commands := []string{"send", "set", "walletBalance", "walletCreate"}
+	wallets := []string{"foo.wlt", "bar.wlt"}
+	addresses := []string{"2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv", "2AzuN3aqF53vUC2yHqdfMKnw4i8eRrwye71"}
+
+	tt := []struct {
+		name        string
+		line        string
+		pos         int
+		head        string
+		completions []string
+		tail        string
+	}{
+		{
+			name:        "command",
+			line:        "wal",
+			pos:         3,
+			completions: []string{"walletBalance", "walletCreate"},
+		},
+		{
+			name:        "setting",
+			line:        "set w",
+			pos:         5,
+			head:        "set ",
+			completions: []string{"wallet"},
+		},
+		{
+			name:        "set wallet",
+			line:        "set wallet f",
+			pos:         12,
+			head:        "set wallet ",
+			completions: []string{"foo.wlt"},
+		},
+		{
+			name:        "wallet argument",
+			line:        "walletBalance b",
+			pos:         15,
+			head:        "walletBalance ",
+			completions: []string{"bar.wlt"},
+		},
+		{
+			name:        "address argument",
+			line:        "send foo.wlt 2G 1",
+			pos:         15,
+			head:        "send foo.wlt ",
+			completions: []string{"2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"},
+			tail:        " 1",
+		},
+		{
+			name: "no match",
+			line: "send x",
+			pos:  6,
+			head: "send ",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			head, completions, tail := completeShellWord(tc.line, tc.pos, commands, wallets, addresses)
+			require.Equal(t, tc.head, head)
+			require.Equal(t, tc.completions, completions)
+			require.Equal(t, tc.tail, tail)
+		})
+	}
+}
+
+func TestShellSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-shell")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := Config{
+		DataDir:    filepath.Join(dir, "data"),
+		Coin:       "skycoin",
+		RPCAddress: "http://127.0.0.1:6420",
+	}
+
+	var out bytes.Buffer
+	sh, err := newShell(cfg, &out)
+	require.NoError(t, err)
+	require.Equal(t, "skycoin> ", sh.prompt())
+	require.Equal(t, cfg.RPCAddress, cliConfig.RPCAddress)
+
+	_, _, err = sh.exec("set node 127.0.0.1:6421")
+	require.Equal(t, ErrShellNodeAddress, err)
+	_, _, err = sh.exec("set wallet foo")
+	require.Equal(t, ErrWalletName, err)
+	_, _, err = sh.exec("set foo bar")
+	require.EqualError(t, err, `unknown setting "foo", must be one of node, wallet`)
+
+	_, _, err = sh.exec("set node http://127.0.0.1:6421")
+	require.NoError(t, err)
+	_, _, err = sh.exec("set wallet foo.wlt")
+	require.NoError(t, err)
+	require.Equal(t, "skycoin [foo.wlt]> ", sh.prompt())
+	require.Equal(t, "http://127.0.0.1:6421", cliConfig.RPCAddress)
+
+	// The settings are kept by the next session
+	sh, err = newShell(cfg, &out)
+	require.NoError(t, err)
+	require.Equal(t, ShellSettings{
+		Node:   "http://127.0.0.1:6421",
+		Wallet: "foo.wlt",
+	}, sh.settings)
+	require.Equal(t, "http://127.0.0.1:6421", cliConfig.RPCAddress)
+
+	// The default wallet is added to the commands with a [wallet] argument without a wallet
+	root := newRootCmd(cliConfig)
+	require.Equal(t, []string{"walletBalance", "foo.wlt"}, sh.withDefaultWallet(root, []string{"walletBalance"}))
+	require.Equal(t, []string{"send", "foo.wlt", "addr", "1"}, sh.withDefaultWallet(root, []string{"send", "addr", "1"}))
+	require.Equal(t, []string{"send", "bar.wlt", "addr", "1"}, sh.withDefaultWallet(root, []string{"send", "bar.wlt", "addr", "1"}))
+	require.Equal(t, []string{"status"}, sh.withDefaultWallet(root, []string{"status"}))
+	require.Equal(t, []string{"foo"}, sh.withDefaultWallet(root, []string{"foo"}))
+
+	_, _, err = sh.exec("unset node")
+	require.NoError(t, err)
+	require.Equal(t, cfg.RPCAddress, cliConfig.RPCAddress)
+
+	// The wallet of the profile is the default wallet if the wallet is not set
+	sh.cfg.Wallet = "profile.wlt"
+	require.Equal(t, []string{"walletBalance", "foo.wlt"}, sh.withDefaultWallet(root, []string{"walletBalance"}))
+	_, _, err = sh.exec("unset wallet")
+	require.NoError(t, err)
+	require.Equal(t, []string{"walletBalance", "profile.wlt"}, sh.withDefaultWallet(root, []string{"walletBalance"}))
+
+	exit, saveHistory, err := sh.exec("quit")
+	require.NoError(t, err)
+	require.True(t, exit)
+	require.False(t, saveHistory)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func signMessageCmd() *cobra.Command {
+	signMessageCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(3),
+		Use:   "signMessage [wallet] [address] [message]",
+		Short: "Sign a message with a wallet address",
+		Long: `Sign a message with the secret key of a wallet address, proving the
+    ownership of the address. The signature is verified with verifyMessage.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			id := args[0]
+			if _, err := cipher.DecodeBase58Address(args[1]); err != nil {
+				return fmt.Errorf("invalid address: %v", err)
+			}
+
+			wlt, err := apiClient.Wallet(id)
+			if err != nil {
+				return err
+			}
+
+			var password []byte
+			if wlt.Meta.Encrypted {
+				pr := NewPasswordReader([]byte(c.Flag("password").Value.String()))
+				password, err = pr.Password()
+				if err != nil {
+					return err
+				}
+			}
+
+			rsp, err := apiClient.SignMessage(api.WalletSignMessageRequest{
+				ID:       id,
+				Address:  args[1],
+				Message:  args[2],
+				Password: string(password),
+			})
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					Signature string `json:"signature"`
+				}{
+					Signature: rsp.Signature,
+				})
+			}
+
+			fmt.Println(rsp.Signature)
+			return nil
+		},
+	}
+
+	signMessageCmd.Flags().StringP("password", "p", "", "Wallet password")
+
+	return signMessageCmd
+}
+
+func verifyMessageCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Verify a message signed by a skycoin address",
+		Long: `Verify that a message was signed with the secret key of a skycoin address,
+    e.g. by signMessage. The signature is verified locally, without a node.`,
+		Use:                   "verifyMessage [skycoin address] [signature] [message]",
+		Args:                  cobra.ExactArgs(3),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(c *cobra.Command, args []string) error {
+			addr, err := cipher.DecodeBase58Address(args[0])
+			if err != nil {
+				return ValidationError{fmt.Errorf("invalid address: %v", err)}
+			}
+
+			sig, err := cipher.SigFromHex(args[1])
+			if err != nil {
+				return ValidationError{fmt.Errorf("invalid signature: %v", err)}
+			}
+
+			if err := cipher.VerifyMessage(addr, sig, []byte(args[2])); err != nil {
+				return ValidationError{err}
+			}
+
+			return printSuccess(c, "")
+		},
+	}
+}
@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// SweepSummary is printed by the sweep command before sending the sweep transaction
+type SweepSummary struct {
+	Txid   string `json:"txid"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Inputs int    `json:"inputs"`
+	Coins  string `json:"coins"`
+	// InputHours are the coin hours of the swept outputs, BurnedHours the fee and OutputHours the hours sent to the address
+	InputHours  uint64 `json:"input_hours"`
+	BurnedHours string `json:"burned_hours"`
+	OutputHours string `json:"output_hours"`
+	Size        uint32 `json:"size"`
+	// Remaining is the number of spendable outputs that are not swept by the transaction
+	Remaining int `json:"remaining"`
+	// Sent is true if the transaction was sent to the network
+	Sent bool `json:"sent"`
+}
+
+func sweepCmd() *cobra.Command {
+	sweepCmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Short: "Send all the coins of a private key to an address",
+		Use:   "sweep",
+		Long: `Send all the spendable coins and coin hours of the address of a private key to the --to address,
+    in a single transaction. The private key is not added to a wallet, the transaction is signed
+    by the cli and sent to the node.
+
+    The private key can be hex encoded, in the bitcoin wallet import format (WIF), a mini private key
+    or a BIP38 encrypted key, such as the keys of the paperWallet command. If --privkey is not set
+    the private key is prompted. The passphrase of a BIP38 key is prompted if --passphrase is not set.
+    The --to address can be the name of a contact of the address book.
+
+    The spendable outputs with the most coins are swept first, as many as fit in the maximum
+    transaction size. Run the command again to sweep the remaining outputs.
+
+    The summary of the transaction, with the coin hours burned as fee, is shown and the
+    transaction is sent after confirmation.
+    With --dry-run the details of the transaction are printed instead and it is neither signed nor sent.
+
+    Use caution when using the "--privkey" and "--passphrase" options. If you have command history
+    enabled the private key can be recovered from the history log.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			yes, err := c.Flags().GetBool("yes")
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := c.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput && !yes && !dryRun {
+				return errors.New("--json requires --yes or --dry-run")
+			}
+
+			to, err := c.Flags().GetString("to")
+			if err != nil {
+				return err
+			}
+			if to == "" {
+				return errors.New("missing --to address")
+			}
+			to, err = newContactResolver().resolve(to)
+			if err != nil {
+				return err
+			}
+			if _, err := cipher.DecodeBase58Address(to); err != nil {
+				return fmt.Errorf("invalid --to address: %v", err)
+			}
+
+			key, err := c.Flags().GetString("privkey")
+			if err != nil {
+				return err
+			}
+			if key == "" {
+				k, err := readSweepSecret("enter private key:")
+				if err != nil {
+					return err
+				}
+				key = string(k)
+			}
+
+			passphrase, err := c.Flags().GetString("passphrase")
+			if err != nil {
+				return err
+			}
+			if passphrase == "" && cipher.IsBIP38Key(strings.TrimSpace(key)) {
+				p, err := readSweepSecret("enter BIP38 passphrase:")
+				if err != nil {
+					return err
+				}
+				passphrase = string(p)
+			}
+
+			sk, _, err := wallet.ParseSecKeyWithPassphrase(key, []byte(passphrase))
+			if err != nil {
+				return fmt.Errorf("invalid private key: %v", err)
+			}
+
+			txn, created, remaining, err := createSweepTransaction(apiClient, sk, to, defaultConsolidateMaxInputs(), !dryRun, params.MainNetDistribution)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				return printTransactionPreviews(os.Stdout, jsonOutput, newTransactionPreview(*created, 1))
+			}
+
+			summary := newSweepSummary(cipher.MustAddressFromSecKey(sk).String(), to, created, remaining)
+
+			if !yes {
+				printSweepSummary(os.Stdout, summary)
+
+				ok, err := confirmPayments(os.Stdin, os.Stdout, 1)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("No transaction was sent")
+					return nil
+				}
+			}
+
+			if _, err := apiClient.InjectTransaction(txn); err != nil {
+				return err
+			}
+			summary.Sent = true
+
+			if jsonOutput {
+				return printJSON(summary)
+			}
+
+			fmt.Printf("txid:%s\n", summary.Txid)
+			return nil
+		},
+	}
+
+	sweepCmd.Flags().String("privkey", "", "Private key to sweep, prompted if not set")
+	sweepCmd.Flags().String("passphrase", "", "Passphrase of a BIP38 encrypted private key, prompted if not set")
+	sweepCmd.Flags().String("to", "", "Address to send the coins to")
+	sweepCmd.Flags().BoolP("yes", "y", false, "Send the transaction without confirmation")
+	sweepCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format, requires --yes or --dry-run")
+	addDryRunFlag(sweepCmd)
+
+	return sweepCmd
+}
+
+func readSweepSecret(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	s, err := terminal.ReadPassword(int(syscall.Stdin)) //nolint:unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "")
+
+	return s, nil
+}
+
+// createSweepTransaction creates the transaction sending the spendable outputs of the address of sk to the address to,
+// at most maxInputs outputs with the most coins. The transaction is signed with sk if sign is true.
+// Returns the number of spendable outputs that are not spent by the transaction.
+func createSweepTransaction(c GetOutputser, sk cipher.SecKey, to string, maxInputs int, sign bool, distParams params.Distribution) (*coin.Transaction, *api.CreatedTransaction, int, error) {
+	from, err := cipher.AddressFromSecKey(sk)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if from.String() == to {
+		return nil, nil, 0, errors.New("--to is the address of the private key")
+	}
+
+	outputs, err := c.OutputsForAddresses([]string{from.String()})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	uxs, err := readable.OutputsToUxBalances(outputs.SpendableOutputs())
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if len(uxs) == 0 {
+		if len(outputs.ExpectedOutputs()) != 0 {
+			return nil, nil, 0, ErrTemporaryInsufficientBalance
+		}
+		return nil, nil, 0, fmt.Errorf("No spendable outputs to sweep, the address %s has no coins", from)
+	}
+
+	sort.SliceStable(uxs, func(i, j int) bool {
+		if uxs[i].Coins == uxs[j].Coins {
+			return uxs[i].Hash.Hex() < uxs[j].Hash.Hex()
+		}
+		return uxs[i].Coins > uxs[j].Coins
+	})
+
+	n := len(uxs)
+	if n > maxInputs {
+		n = maxInputs
+	}
+	spends := uxs[:n]
+
+	var coins, hours uint64
+	for _, u := range spends {
+		coins, err = mathutil.AddUint64(coins, u.Coins)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		hours, err = mathutil.AddUint64(hours, u.Hours)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if hours == 0 {
+		return nil, nil, 0, fee.ErrTxnNoFee
+	}
+
+	_, addrHours, totalOutHours := transaction.DistributeSpendHours(hours, 1, false)
+	if err := fee.VerifyTransactionFeeForHours(totalOutHours, hours-totalOutHours, params.UserVerifyTxn.BurnFactor); err != nil {
+		return nil, nil, 0, err
+	}
+
+	outs := []coin.TransactionOutput{
+		mustMakeUtxoOutput(to, coins, addrHours[0]),
+	}
+
+	var keys []cipher.SecKey
+	signed := visor.TxnUnsigned
+	if sign {
+		keys = make([]cipher.SecKey, len(spends))
+		for i := range keys {
+			keys[i] = sk
+		}
+		signed = visor.TxnSigned
+	}
+
+	txn, err := NewTransaction(spends, keys, outs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if err := verifyRawTxn(outputs, txn, signed, distParams); err != nil {
+		return nil, nil, 0, err
+	}
+
+	created, err := api.NewCreatedTransaction(txn, visor.NewTransactionInputsFromUxBalance(spends))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return txn, created, len(uxs) - n, nil
+}
+
+func newSweepSummary(from, to string, txn *api.CreatedTransaction, remaining int) *SweepSummary {
+	s := &SweepSummary{
+		Txid:        txn.TxID,
+		From:        from,
+		To:          to,
+		Inputs:      len(txn.In),
+		Coins:       txn.Out[0].Coins,
+		BurnedHours: txn.Fee,
+		OutputHours: txn.Out[0].Hours,
+		Size:        txn.Length,
+		Remaining:   remaining,
+	}
+
+	for _, in := range txn.In {
+		if h, err := strconv.ParseUint(in.CalculatedHours, 10, 64); err == nil {
+			s.InputHours += h
+		}
+	}
+
+	return s
+}
+
+func printSweepSummary(w io.Writer, summary *SweepSummary) {
+	fmt.Fprintf(w, "Transaction:  %s\n", summary.Txid)
+	fmt.Fprintf(w, "From:         %s\n", summary.From)
+	fmt.Fprintf(w, "Outputs:      %d\n", summary.Inputs)
+	fmt.Fprintf(w, "Coins:        %s\n", summary.Coins)
+	fmt.Fprintf(w, "To:           %s\n", summary.To)
+	fmt.Fprintf(w, "Hours:        %d\n", summary.InputHours)
+	fmt.Fprintf(w, "Hours burned: %s\n", summary.BurnedHours)
+	fmt.Fprintf(w, "Hours sent:   %s\n", summary.OutputHours)
+	fmt.Fprintf(w, "Size:         %d\n", summary.Size)
+	if summary.Remaining != 0 {
+		fmt.Fprintf(w, "%d output(s) remain, run the command again to sweep them\n", summary.Remaining)
+	}
+}
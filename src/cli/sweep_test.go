@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func TestCreateSweepTransaction(t *testing.T) {
+	pk, sk := cipher.GenerateKeyPair()
+	from := cipher.AddressFromPubKey(pk)
+	to := testutil.MakeAddress().String()
+
+	head := coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				BkSeq: 10,
+				Time:  1e9,
+			},
+		},
+	}
+
+	var uxs []visor.UnspentOutput
+	for _, coins := range []uint64{1e6, 20e6, 5e6} {
+		ux, err := visor.NewUnspentOutput(coin.UxOut{
+			Head: coin.UxHead{
+				Time:  head.Head.Time - 3600,
+				BkSeq: 5,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        from,
+				Coins:          coins,
+				Hours:          100,
+			},
+		}, head.Head.Time)
+		require.NoError(t, err)
+		uxs = append(uxs, ux)
+	}
+
+	outs, err := readable.NewUnspentOutputsSummary(&visor.UnspentOutputsSummary{
+		HeadBlock: &head,
+		Confirmed: uxs,
+	})
+	require.NoError(t, err)
+
+	c := fakeOutputsClient{outs: outs}
+
+	// The 2 outputs with the most coins are swept
+	txn, created, remaining, err := createSweepTransaction(c, sk, to, 2, true, params.MainNetDistribution)
+	require.NoError(t, err)
+	require.Equal(t, 1, remaining)
+	require.Equal(t, []cipher.SHA256{uxs[1].Hash(), uxs[2].Hash()}, txn.In)
+	require.NoError(t, txn.Verify())
+
+	require.Len(t, created.Out, 1)
+	require.Equal(t, to, created.Out[0].Address)
+	require.Equal(t, "25.000000", created.Out[0].Coins)
+	require.Equal(t, "202", created.Out[0].Hours)
+	require.Equal(t, "23", created.Fee)
+
+	summary := newSweepSummary(from.String(), to, created, remaining)
+	require.Equal(t, &SweepSummary{
+		Txid:        txn.Hash().Hex(),
+		From:        from.String(),
+		To:          to,
+		Inputs:      2,
+		Coins:       "25.000000",
+		InputHours:  225,
+		BurnedHours: "23",
+		OutputHours: "202",
+		Size:        created.Length,
+		Remaining:   1,
+	}, summary)
+
+	var out bytes.Buffer
+	printSweepSummary(&out, summary)
+	require.Contains(t, out.String(), "Coins:        25.000000\n")
+	require.Contains(t, out.String(), "1 output(s) remain, run the command again to sweep them\n")
+
+	// All the outputs are swept, the transaction is not signed
+	txn, created, remaining, err = createSweepTransaction(c, sk, to, 10, false, params.MainNetDistribution)
+	require.NoError(t, err)
+	require.Equal(t, 0, remaining)
+	require.Len(t, txn.In, 3)
+	require.Equal(t, []cipher.Sig{{}, {}, {}}, txn.Sigs)
+	require.Equal(t, "26.000000", created.Out[0].Coins)
+
+	// Sweeping to the address of the key
+	_, _, _, err = createSweepTransaction(c, sk, from.String(), 10, true, params.MainNetDistribution)
+	require.EqualError(t, err, "--to is the address of the private key")
+
+	// No outputs
+	_, sk2 := cipher.GenerateKeyPair()
+	empty, err := readable.NewUnspentOutputsSummary(&visor.UnspentOutputsSummary{
+		HeadBlock: &head,
+	})
+	require.NoError(t, err)
+	_, _, _, err = createSweepTransaction(fakeOutputsClient{outs: empty}, sk2, to, 10, true, params.MainNetDistribution)
+	require.Error(t, err)
+}
@@ -33,13 +33,13 @@ func transactionCmd() *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			txid := args[0]
 			if txid == "" {
-				return errors.New("txid is empty")
+				return ValidationError{errors.New("txid is empty")}
 			}
 
 			// validate the txid
 			_, err := cipher.SHA256FromHex(txid)
 			if err != nil {
-				return errors.New("invalid txid")
+				return ValidationError{errors.New("invalid txid")}
 			}
 
 			txn, err := apiClient.Transaction(txid)
@@ -64,7 +64,7 @@ func decodeRawTxnCmd() *cobra.Command {
 		RunE: func(_ *cobra.Command, args []string) error {
 			txn, err := coin.DeserializeTransactionHex(args[0])
 			if err != nil {
-				return fmt.Errorf("invalid raw transaction: %v", err)
+				return ValidationError{fmt.Errorf("invalid raw transaction: %v", err)}
 			}
 
 			// Assume the transaction is not malformed and if it has no inputs
@@ -283,7 +283,7 @@ func verifyTransactionCmd() *cobra.Command {
 		DisableFlagsInUseLine: true,
 		SilenceUsage:          true,
 		Args:                  cobra.MaximumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
 			encodedTxn := args[0]
 			if encodedTxn == "" {
 				return errors.New("transaction is empty")
@@ -296,9 +296,7 @@ func verifyTransactionCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println("transaction is spendable")
-
-			return nil
+			return printSuccess(c, "transaction is spendable")
 		},
 	}
 }
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// VanityAddressResult is the output of the vanityAddress command
+type VanityAddressResult struct {
+	Address string `json:"address"`
+	Secret  string `json:"secret,omitempty"`
+	Wallet  string `json:"wallet,omitempty"`
+}
+
+func vanityAddressCmd() *cobra.Command {
+	vanityAddressCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "vanityAddress [prefix]",
+		Short: "Generate an address starting with a prefix",
+		Long: `Generate random keys on all CPUs until an address starts with the prefix,
+    e.g. "2Sky". The prefix is case sensitive and must only contain base58 characters.
+    Each character of the prefix multiplies the expected search time by 58.
+    The progress is printed to stderr, press Ctrl-C to cancel the search.
+
+    If a "collection" type wallet file is given with "-w", the found key is added
+    to the wallet and its secret key is not printed.
+
+    Use caution when using the "-p" command. If you have command
+    history enabled your wallet encryption password can be recovered from the
+    history log. If you do not include the "-p" option you will be prompted to
+    enter your password after you enter your command.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			prefix := args[0]
+
+			workers, err := c.Flags().GetInt("workers")
+			if err != nil {
+				return err
+			}
+
+			walletFile, err := c.Flags().GetString("wallet-file")
+			if err != nil {
+				return err
+			}
+
+			password, err := c.Flags().GetString("password")
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			quit := make(chan os.Signal, 1)
+			signal.Notify(quit, os.Interrupt)
+			defer signal.Stop(quit)
+			go func() {
+				select {
+				case <-quit:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			expected := cipher.VanityAttempts(prefix)
+			addr, sk, err := cipher.SearchVanityAddress(ctx, prefix, workers, func(attempts uint64) {
+				fmt.Fprintf(os.Stderr, "\r%d keys tried, %.0f expected", attempts, expected)
+			})
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return err
+			}
+
+			res := VanityAddressResult{
+				Address: addr.String(),
+			}
+
+			if walletFile == "" {
+				res.Secret = sk.Hex()
+				return printJSON(res)
+			}
+
+			if err := AddPrivateKeyToFile(walletFile, sk.Hex(), NewPasswordReader([]byte(password))); err != nil {
+				return err
+			}
+
+			res.Wallet = walletFile
+			return printJSON(res)
+		},
+	}
+
+	vanityAddressCmd.Flags().IntP("workers", "n", 0, "Number of search goroutines, defaults to the number of CPUs")
+	vanityAddressCmd.Flags().StringP("wallet-file", "w", "", "Collection wallet file to add the found key to")
+	vanityAddressCmd.Flags().StringP("password", "p", "", "Wallet password")
+
+	return vanityAddressCmd
+}
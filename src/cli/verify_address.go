@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 
 	"github.com/skycoin/skycoin/src/cipher"
@@ -8,14 +10,19 @@ import (
 
 func verifyAddressCmd() *cobra.Command {
 	return &cobra.Command{
-		Short:                 "Verify a skycoin address",
+		Short: "Verify a skycoin address",
+		Long: `Verify a skycoin address. An invalid address is reported with the reason,
+    e.g. a mistyped character, a wrong length or the address of another coin such as bitcoin.`,
 		Use:                   "verifyAddress [skycoin address]",
 		Args:                  cobra.ExactArgs(1),
 		DisableFlagsInUseLine: true,
 		SilenceUsage:          true,
-		RunE: func(_ *cobra.Command, args []string) error {
-			_, err := cipher.DecodeBase58Address(args[0])
-			return err
+		RunE: func(c *cobra.Command, args []string) error {
+			v := cipher.ValidateAddress(args[0])
+			if !v.Valid {
+				return ValidationError{errors.New(v.Message())}
+			}
+			return printSuccess(c, "")
 		},
 	}
 }
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// ErrChainMismatch is returned by verifyChain when the blockchain of the node does not match the checkpoint
+var ErrChainMismatch = errors.New("the blockchain of the node does not match the checkpoint")
+
+func verifyChainCmd() *cobra.Command {
+	verifyChainCmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Short: "Verify the blockchain of the node up to a trusted checkpoint",
+		Use:   "verifyChain",
+		Long: `Ask the node to verify the block headers of its database, from the genesis block
+    up to a trusted checkpoint in <hash>@<height> format, e.g. the hash of a block at a height
+    published by the coin developers or read from another node.
+
+    The headers must form a chain ending with the hash of the checkpoint: every header links
+    to the hash of the previous header and matches the hash of the transactions of its block.
+    The blocks that do not match are reported, the verification stops after 100 mismatches.
+
+    The command uses the admin API, which is only enabled by the -enable-admin-api option
+    of the node, with the username and password of the node read from RPC_USER and RPC_PASS.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			checkpoint, err := c.Flags().GetString("checkpoint")
+			if err != nil {
+				return err
+			}
+
+			seq, hash, err := parseCheckpoint(checkpoint)
+			if err != nil {
+				return err
+			}
+
+			r, err := apiClient.VerifyChain(seq, hash.Hex())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := printJSON(r); err != nil {
+					return err
+				}
+				if !r.Valid {
+					return printedError{ErrChainMismatch}
+				}
+				return nil
+			}
+
+			printChainVerification(os.Stdout, r)
+			if !r.Valid {
+				return ErrChainMismatch
+			}
+			return nil
+		},
+	}
+
+	verifyChainCmd.Flags().String("checkpoint", "", "Trusted block hash and height, in <hash>@<height> format")
+
+	return verifyChainCmd
+}
+
+// parseCheckpoint parses a checkpoint in <hash>@<height> format
+func parseCheckpoint(s string) (uint64, cipher.SHA256, error) {
+	if s == "" {
+		return 0, cipher.SHA256{}, ValidationError{errors.New("missing --checkpoint")}
+	}
+
+	fields := strings.Split(s, "@")
+	if len(fields) != 2 {
+		return 0, cipher.SHA256{}, ValidationError{fmt.Errorf("invalid checkpoint %q, must be in <hash>@<height> format", s)}
+	}
+
+	hash, err := cipher.SHA256FromHex(fields[0])
+	if err != nil {
+		return 0, cipher.SHA256{}, ValidationError{fmt.Errorf("invalid checkpoint hash: %v", err)}
+	}
+
+	seq, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, cipher.SHA256{}, ValidationError{fmt.Errorf("invalid checkpoint height: %v", err)}
+	}
+
+	return seq, hash, nil
+}
+
+func printChainVerification(w io.Writer, r *api.ChainVerificationResponse) {
+	fmt.Fprintf(w, "Checkpoint:   %s@%d\n", r.Checkpoint.Hash, r.Checkpoint.Seq)
+	fmt.Fprintf(w, "Head block:   %d\n", r.HeadSeq)
+	fmt.Fprintf(w, "Verified:     %d blocks\n", r.Verified)
+
+	if r.Valid {
+		fmt.Fprintln(w, "Result:       the blockchain matches the checkpoint")
+		return
+	}
+
+	fmt.Fprintf(w, "Result:       %d mismatches\n", len(r.Mismatches))
+	for _, m := range r.Mismatches {
+		fmt.Fprintf(w, "  %d: %s\n", m.Seq, m.Error)
+	}
+}
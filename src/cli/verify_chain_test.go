@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestParseCheckpoint(t *testing.T) {
+	hash := testutil.RandSHA256(t)
+
+	seq, h, err := parseCheckpoint(hash.Hex() + "@1200")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1200), seq)
+	require.Equal(t, hash, h)
+
+	for s, errMsg := range map[string]string{
+		"":                   "missing --checkpoint",
+		hash.Hex():           `invalid checkpoint "` + hash.Hex() + `", must be in <hash>@<height> format`,
+		hash.Hex() + "@1@2":  `invalid checkpoint "` + hash.Hex() + `@1@2", must be in <hash>@<height> format`,
+		"1200@" + hash.Hex(): "invalid checkpoint hash: Invalid hex length",
+		hash.Hex() + "@foo":  `invalid checkpoint height: strconv.ParseUint: parsing "foo": invalid syntax`,
+		hash.Hex() + "@-1":   `invalid checkpoint height: strconv.ParseUint: parsing "-1": invalid syntax`,
+	} {
+		_, _, err := parseCheckpoint(s)
+		require.EqualError(t, err, errMsg, s)
+		require.Equal(t, ExitCodeValidation, ExitCode(err))
+	}
+}
+
+func TestPrintChainVerification(t *testing.T) {
+	r := &api.ChainVerificationResponse{
+		Checkpoint: api.ChainCheckpoint{
+			Seq:  100,
+			Hash: "6ae9e18eaa15617e8e6288cb046ff98cb45066e5d85f3789ca9b49645a3e8c46",
+		},
+		HeadSeq:  120,
+		Verified: 101,
+		Valid:    true,
+	}
+
+	var out bytes.Buffer
+	printChainVerification(&out, r)
+	require.Equal(t, `Checkpoint:   6ae9e18eaa15617e8e6288cb046ff98cb45066e5d85f3789ca9b49645a3e8c46@100
+Head block:   120
+Verified:     101 blocks
+Result:       the blockchain matches the checkpoint
+`, out.String())
+
+	r.Valid = false
+	r.Mismatches = []api.ChainMismatch{
+		{
+			Seq:   100,
+			Error: "block hash 12ab does not match the checkpoint hash 6ae9",
+		},
+	}
+
+	out.Reset()
+	printChainVerification(&out, r)
+	require.Equal(t, `Checkpoint:   6ae9e18eaa15617e8e6288cb046ff98cb45066e5d85f3789ca9b49645a3e8c46@100
+Head block:   120
+Verified:     101 blocks
+Result:       1 mismatches
+  100: block hash 12ab does not match the checkpoint hash 6ae9
+`, out.String())
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// ErrWalletCorrupted is returned by walletAudit if the wallet has integrity issues
+var ErrWalletCorrupted = errors.New("the wallet is corrupted")
+
+func walletAuditCmd() *cobra.Command {
+	walletAuditCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Short: "Check the integrity of a wallet file",
+		Use:   "walletAudit [wallet]",
+		Long: `Check the integrity of a wallet file, to find a corrupted wallet before spending from it.
+
+    The keys of every entry must match its address and the addresses must be unique.
+    The addresses of the deterministic, bip44 and xpub wallets are derived again from the seed
+    or the xpub key and compared with the addresses of the wallet. The accounts of a bip44 wallet
+    must be derived from the seed and the seed passphrase, i.e. match the accountsHash of the wallet.
+
+    The command fails if the wallet can't be loaded or has any issue.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			w, err := wallet.Load(args[0])
+			if err != nil {
+				return WalletLoadError{err}
+			}
+			if w == nil {
+				return WalletLoadError{errors.New("unsupported wallet type")}
+			}
+
+			var report *wallet.AuditReport
+			audit := func(w wallet.Wallet) error {
+				var err error
+				report, err = wallet.Audit(w)
+				return err
+			}
+
+			if w.IsEncrypted() {
+				password, err := getPassword(c)
+				if err != nil {
+					return err
+				}
+				err = wallet.GuardView(w, password, audit)
+			} else {
+				err = audit(w)
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				if err := printJSON(report); err != nil {
+					return err
+				}
+			} else {
+				printAuditReport(os.Stdout, report)
+			}
+
+			if len(report.Issues) != 0 {
+				return ErrWalletCorrupted
+			}
+			return nil
+		},
+	}
+
+	walletAuditCmd.Flags().StringP("password", "p", "", "Wallet password")
+	walletAuditCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return walletAuditCmd
+}
+
+func printAuditReport(w io.Writer, report *wallet.AuditReport) {
+	derived := "no"
+	if report.Derived {
+		derived = "yes"
+	}
+
+	fmt.Fprintf(w, "Type:    %s\n", report.Type)
+	fmt.Fprintf(w, "Entries: %d\n", report.Entries)
+	fmt.Fprintf(w, "Derived: %s\n", derived)
+
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(w, "No issues found")
+		return
+	}
+
+	fmt.Fprintf(w, "%d issue(s) found:\n", len(report.Issues))
+	for _, issue := range report.Issues {
+		fmt.Fprintf(w, "  - %s\n", issue)
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestPrintAuditReport(t *testing.T) {
+	var out bytes.Buffer
+	printAuditReport(&out, &wallet.AuditReport{
+		Type:    wallet.WalletTypeBip44,
+		Entries: 6,
+		Derived: true,
+		Issues:  []string{},
+	})
+	require.Equal(t, `Type:    bip44
+Entries: 6
+Derived: yes
+No issues found
+`, out.String())
+
+	out.Reset()
+	printAuditReport(&out, &wallet.AuditReport{
+		Type:    wallet.WalletTypeCollection,
+		Entries: 2,
+		Issues: []string{
+			"address entry 1 foo: Invalid address for pubkey",
+			"address entry 1 foo: duplicate of address entry 0",
+		},
+	})
+	require.Equal(t, `Type:    collection
+Entries: 2
+Derived: no
+2 issue(s) found:
+  - address entry 1 foo: Invalid address for pubkey
+  - address entry 1 foo: duplicate of address entry 0
+`, out.String())
+}
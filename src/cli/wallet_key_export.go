@@ -110,7 +110,7 @@ func walletKeyExportHandler(c *cobra.Command, args []string) error {
 	}
 
 	if len(nodes) == 1 {
-		return printKey(keyType, acct.PrivateKey)
+		return printKey(c, keyType, acct.PrivateKey)
 	}
 
 	change, err := acct.NewPrivateChildKey(nodes[1])
@@ -119,7 +119,7 @@ func walletKeyExportHandler(c *cobra.Command, args []string) error {
 	}
 
 	if len(nodes) == 2 {
-		return printKey(keyType, change)
+		return printKey(c, keyType, change)
 	}
 
 	child, err := change.NewPrivateChildKey(nodes[2])
@@ -128,7 +128,7 @@ func walletKeyExportHandler(c *cobra.Command, args []string) error {
 	}
 
 	if len(nodes) == 3 {
-		return printKey(keyType, child)
+		return printKey(c, keyType, child)
 	}
 
 	return nil
@@ -144,24 +144,41 @@ func validateKeyType(kt string) error {
 	return nil
 }
 
-func printKey(kt string, k *bip32.PrivateKey) error {
+func printKey(c *cobra.Command, kt string, k *bip32.PrivateKey) error {
 	if err := validateKeyType(kt); err != nil {
 		return err
 	}
 
+	var key string
 	switch kt {
 	case "xpub":
-		fmt.Println(k.PublicKey().String())
+		key = k.PublicKey().String()
 	case "xprv":
-		fmt.Println(k.String())
+		key = k.String()
 	case "pub":
-		fmt.Println(cipher.MustNewPubKey(k.PublicKey().Key).Hex())
+		key = cipher.MustNewPubKey(k.PublicKey().Key).Hex()
 	case "prv":
-		fmt.Println(cipher.MustNewSecKey(k.Key).Hex())
+		key = cipher.MustNewSecKey(k.Key).Hex()
 	default:
 		panic("unhandled key type")
 	}
 
+	jsonOutput, err := c.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			Type string `json:"type"`
+			Key  string `json:"key"`
+		}{
+			Type: kt,
+			Key:  key,
+		})
+	}
+
+	fmt.Println(key)
 	return nil
 }
 
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// labelsHeader is the header row of the labels CSV files
+var labelsHeader = []string{"address", "label", "note", "tags"}
+
+// LabelsExportResult is printed by walletLabelsExport with --json when the labels are written to a file
+type LabelsExportResult struct {
+	Exported int    `json:"exported"`
+	File     string `json:"file"`
+}
+
+func walletLabelsExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Export the address labels of a wallet to a CSV file",
+		Use:   "walletLabelsExport [wallet] [csv file]",
+		Long: `Export the labels, notes and tags of the addresses of a wallet to a CSV file,
+    or print them if no file is given. The labels of all accounts of bip44 wallets are exported.
+
+    Each row of the CSV file is address,label,note,tags, after an address,label,note,tags header row.
+    The tags are a JSON object, e.g. {"category":"savings"}, empty if the address has no tags.
+    Only the addresses with a label, note or tags are exported.
+
+    The labels are kept in the wallet file, they are lost if the wallet is recreated from its seed.
+    Import the exported file with walletLabelsImport to restore them.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			labels, err := apiClient.WalletLabels(args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				if jsonOutput {
+					return printJSON(labels)
+				}
+				return writeLabelsCSV(os.Stdout, labels)
+			}
+
+			f, err := os.OpenFile(args[1], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+
+			if err := writeLabelsCSV(f, labels); err != nil {
+				f.Close()
+				return err
+			}
+
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(LabelsExportResult{
+					Exported: len(labels),
+					File:     args[1],
+				})
+			}
+
+			fmt.Printf("Exported the labels of %d addresses to %s\n", len(labels), args[1])
+			return nil
+		},
+	}
+}
+
+func walletLabelsImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		Short: "Import the address labels of a wallet from a CSV file",
+		Use:   "walletLabelsImport [wallet] [csv file]",
+		Long: `Import the labels, notes and tags of the addresses of a wallet from a CSV file
+    written by walletLabelsExport, e.g. to restore them after recreating the wallet from its seed.
+
+    Each row of the CSV file is address,label[,note[,tags]], a first row starting with "address" is skipped as a header.
+    The tags are a JSON object, e.g. {"category":"savings"}. All rows are validated before any label is imported.
+    The label, note and tags of each address of the file replace the ones of the address in the wallet.
+
+    The addresses that the wallet does not have are skipped and listed. The addresses of a wallet
+    recreated from its seed are generated again with walletAddAddresses or walletScanAddresses,
+    import the file again after generating them.`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			labels, err := parseLabelsCSV(f)
+			if err != nil {
+				return err
+			}
+
+			rsp, err := apiClient.ImportWalletLabels(args[0], labels)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(rsp)
+			}
+
+			printLabelsImport(os.Stdout, rsp)
+			return nil
+		},
+	}
+}
+
+// writeLabelsCSV writes the labels as CSV rows with a header row
+func writeLabelsCSV(w io.Writer, labels []api.WalletAddressLabel) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(labelsHeader); err != nil {
+		return err
+	}
+
+	for _, l := range labels {
+		var tags string
+		if len(l.Tags) != 0 {
+			// The keys of the map are sorted by json.Marshal
+			b, err := json.Marshal(l.Tags)
+			if err != nil {
+				return err
+			}
+			tags = string(b)
+		}
+
+		if err := cw.Write([]string{l.Address, l.Label, l.Note, tags}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// parseLabelsCSV reads the address,label[,note[,tags]] rows of a CSV file. All rows are validated,
+// the errors of all invalid rows are returned.
+func parseLabelsCSV(r io.Reader) ([]api.WalletAddressLabel, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, ValidationError{err}
+	}
+
+	if len(rows) != 0 && strings.EqualFold(strings.TrimSpace(rows[0][0]), "address") {
+		rows = rows[1:]
+	}
+
+	if len(rows) == 0 {
+		return nil, ValidationError{errors.New("No labels in the CSV file")}
+	}
+
+	var labels []api.WalletAddressLabel
+	var errs []string
+	rowsByAddress := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		if len(row) < 2 || len(row) > 4 {
+			errs = append(errs, fmt.Sprintf("[row %d] Must have 2 to 4 fields, address,label[,note[,tags]], has %d", i, len(row)))
+			continue
+		}
+
+		addr := strings.TrimSpace(row[0])
+		if err := validateLabelAddress(addr); err != nil {
+			errs = append(errs, fmt.Sprintf("[row %d] Invalid address %s: %v", i, addr, err))
+			continue
+		}
+
+		if j, ok := rowsByAddress[addr]; ok {
+			errs = append(errs, fmt.Sprintf("[row %d] Duplicate address %s of row %d", i, addr, j))
+			continue
+		}
+
+		l := api.WalletAddressLabel{
+			Address: addr,
+			Label:   row[1],
+		}
+
+		if len(row) > 2 {
+			l.Note = row[2]
+		}
+
+		if len(row) > 3 && strings.TrimSpace(row[3]) != "" {
+			if err := json.Unmarshal([]byte(row[3]), &l.Tags); err != nil {
+				errs = append(errs, fmt.Sprintf("[row %d] Invalid tags %s, must be a JSON object of strings: %v", i, row[3], err))
+				continue
+			}
+		}
+
+		rowsByAddress[addr] = i
+		labels = append(labels, l)
+	}
+
+	if len(errs) != 0 {
+		return nil, ValidationError{errors.New(strings.Join(errs, "\n"))}
+	}
+
+	return labels, nil
+}
+
+// validateLabelAddress validates the address of a label, which is a skycoin or a bitcoin address
+// as the labels of the wallets of both coins are exported
+func validateLabelAddress(addr string) error {
+	_, err := cipher.DecodeBase58Address(addr)
+	if err == nil {
+		return nil
+	}
+
+	if _, btcErr := cipher.DecodeBase58BitcoinAddress(addr); btcErr == nil {
+		return nil
+	}
+
+	return err
+}
+
+func printLabelsImport(w io.Writer, rsp *api.WalletLabelsImportResponse) {
+	fmt.Fprintf(w, "Imported the labels of %d addresses\n", rsp.Imported)
+
+	if len(rsp.NotFound) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Skipped %d addresses that are not in the wallet, generate them with walletAddAddresses or walletScanAddresses and import the file again:\n", len(rsp.NotFound))
+	for _, a := range rsp.NotFound {
+		fmt.Fprintf(w, "  %s\n", a)
+	}
+}
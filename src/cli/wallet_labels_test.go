@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestWriteParseLabelsCSV(t *testing.T) {
+	addr := testutil.MakeAddress().String()
+	btcAddr := cipher.BitcoinAddressFromPubKey(testutil.MakePubKey()).String()
+
+	labels := []api.WalletAddressLabel{
+		{
+			Address: addr,
+			Label:   "savings, 2020",
+			Note:    "for the holidays",
+			Tags:    map[string]string{"category": "savings", "owner": "alice"},
+		},
+		{
+			Address: btcAddr,
+			Label:   "bitcoin",
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeLabelsCSV(&out, labels))
+	require.Equal(t, "address,label,note,tags\n"+
+		addr+`,"savings, 2020",for the holidays,"{""category"":""savings"",""owner"":""alice""}"`+"\n"+
+		btcAddr+",bitcoin,,\n", out.String())
+
+	parsed, err := parseLabelsCSV(&out)
+	require.NoError(t, err)
+	require.Equal(t, labels, parsed)
+
+	// The header, note and tags are optional
+	parsed, err = parseLabelsCSV(strings.NewReader(addr + ",savings\n"))
+	require.NoError(t, err)
+	require.Equal(t, []api.WalletAddressLabel{
+		{
+			Address: addr,
+			Label:   "savings",
+		},
+	}, parsed)
+
+	// An empty label removes the label
+	parsed, err = parseLabelsCSV(strings.NewReader(addr + ",\n"))
+	require.NoError(t, err)
+	require.Equal(t, []api.WalletAddressLabel{
+		{
+			Address: addr,
+		},
+	}, parsed)
+}
+
+func TestParseLabelsCSVErrors(t *testing.T) {
+	addr := testutil.MakeAddress().String()
+
+	for _, tc := range []struct {
+		name string
+		csv  string
+		err  string
+	}{
+		{
+			name: "empty",
+			csv:  "address,label,note,tags\n",
+			err:  "No labels in the CSV file",
+		},
+		{
+			name: "invalid rows",
+			csv: "address,label,note,tags\n" +
+				addr + "\n" +
+				"foo,bar\n" +
+				addr + ",savings,,[1]\n" +
+				addr + ",savings\n" +
+				addr + ",savings again\n" +
+				addr + ",a,b,c,d\n",
+			err: "[row 0] Must have 2 to 4 fields, address,label[,note[,tags]], has 1\n" +
+				"[row 1] Invalid address foo: Invalid address length\n" +
+				"[row 2] Invalid tags [1], must be a JSON object of strings: json: cannot unmarshal array into Go value of type map[string]string\n" +
+				"[row 4] Duplicate address " + addr + " of row 3\n" +
+				"[row 5] Must have 2 to 4 fields, address,label[,note[,tags]], has 5",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseLabelsCSV(strings.NewReader(tc.csv))
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, ExitCodeValidation, ExitCode(err))
+		})
+	}
+}
+
+func TestPrintLabelsImport(t *testing.T) {
+	var out bytes.Buffer
+	printLabelsImport(&out, &api.WalletLabelsImportResponse{
+		Imported: 2,
+	})
+	require.Equal(t, "Imported the labels of 2 addresses\n", out.String())
+
+	out.Reset()
+	printLabelsImport(&out, &api.WalletLabelsImportResponse{
+		Imported: 1,
+		NotFound: []string{"2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv"},
+	})
+	require.Equal(t, `Imported the labels of 1 addresses
+Skipped 1 addresses that are not in the wallet, generate them with walletAddAddresses or walletScanAddresses and import the file again:
+  2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv
+`, out.String())
+}
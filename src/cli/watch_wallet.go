@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func walletCreateWatchCmd() *cobra.Command {
+	walletCreateWatchCmd := &cobra.Command{
+		Args:  cobra.ExactArgs(1),
+		Use:   "walletCreateWatch [label]",
+		Short: "Create a watch-only wallet from an xpub key or a list of addresses",
+		Long: `Create a watch-only wallet, which has no secret keys, to monitor the balance
+    and the transactions of addresses with walletBalance, walletHistory and history.
+
+    With --xpub, an "xpub" wallet is created, its addresses are generated from the xpub key.
+    With --addresses, a "collection-watch" wallet is created with the addresses of the file.
+    The file has an address per line, or addresses separated by commas or spaces.
+    Empty lines and lines starting with "#" are skipped.
+
+    The wallet can't sign transactions, use createUnsignedTransaction to create
+    transactions spending its outputs and sign them offline.
+
+    All results are returned in JSON format.`,
+		SilenceUsage: true,
+		RunE:         walletCreateWatchHandler,
+	}
+
+	walletCreateWatchCmd.Flags().String("xpub", "", "xpub key of the addresses to watch")
+	walletCreateWatchCmd.Flags().Bool("xpub-account", false, "The xpub key is a bip44 account key, whose external and change addresses are watched")
+	walletCreateWatchCmd.Flags().String("addresses", "", "File with the addresses to watch")
+	walletCreateWatchCmd.Flags().Uint64P("num", "n", 1, "Number of addresses to generate (xpub wallets only)")
+	walletCreateWatchCmd.Flags().Uint64("scan", 1, "Number of addresses to scan ahead for balances (xpub wallets only)")
+
+	return walletCreateWatchCmd
+}
+
+func walletCreateWatchHandler(c *cobra.Command, args []string) error {
+	opts, num, err := makeWatchWalletOptions(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	wlt, err := apiClient.CreateWallet(*opts)
+	if err != nil {
+		return err
+	}
+
+	id := wlt.Meta.Filename
+
+	if opts.Type == wallet.WalletTypeXPub {
+		// Change addresses of xpub account wallets are not counted
+		var addrN uint64
+		for _, e := range wlt.Entries {
+			if e.Change == nil || *e.Change == 0 {
+				addrN++
+			}
+		}
+
+		if num > addrN {
+			if _, err := apiClient.NewWalletAddress(id, int(num-addrN), ""); err != nil {
+				return err
+			}
+
+			wlt, err = apiClient.Wallet(id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return printJSON(wlt)
+}
+
+// makeWatchWalletOptions makes the options to create a watch-only wallet with the flags of the walletCreateWatch command,
+// and returns the number of addresses to generate
+func makeWatchWalletOptions(c *cobra.Command, label string) (*api.CreateWalletOptions, uint64, error) {
+	xpub, err := c.Flags().GetString("xpub")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	xpubAccount, err := c.Flags().GetBool("xpub-account")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	addrsFile, err := c.Flags().GetString("addresses")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	num, err := c.Flags().GetUint64("num")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	scan, err := c.Flags().GetUint64("scan")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case xpub != "" && addrsFile != "":
+		return nil, 0, errors.New("--xpub and --addresses can't be used together")
+
+	case xpub != "":
+		if num == 0 {
+			return nil, 0, errors.New("-n must > 0")
+		}
+		if scan == 0 {
+			return nil, 0, errors.New("scan must be > 0")
+		}
+
+		// set scan number as 1 when generate num is greater than scan number to avoid
+		// unnecessary addresses scanning for API.
+		if num >= scan {
+			scan = 1
+		}
+
+		return &api.CreateWalletOptions{
+			Type:        wallet.WalletTypeXPub,
+			Label:       label,
+			XPub:        xpub,
+			XPubAccount: xpubAccount,
+			ScanN:       scan,
+		}, num, nil
+
+	case addrsFile != "":
+		if xpubAccount {
+			return nil, 0, errors.New("--xpub-account requires --xpub")
+		}
+		if c.Flags().Changed("num") || c.Flags().Changed("scan") {
+			return nil, 0, fmt.Errorf("%q type wallets do not support address generation", wallet.WalletTypeCollectionWatch)
+		}
+
+		f, err := os.Open(addrsFile)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer f.Close()
+
+		addrs, err := readWatchAddresses(f)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid addresses file %s: %v", addrsFile, err)
+		}
+
+		return &api.CreateWalletOptions{
+			Type:      wallet.WalletTypeCollectionWatch,
+			Label:     label,
+			Addresses: addrs,
+		}, 0, nil
+
+	default:
+		return nil, 0, errors.New("missing --xpub or --addresses")
+	}
+}
+
+// readWatchAddresses reads the addresses of a walletCreateWatch addresses file, which has an address per line,
+// or addresses separated by commas or spaces. Empty lines and lines starting with "#" are skipped.
+func readWatchAddresses(r io.Reader) ([]string, error) {
+	var addrs []string
+	seen := make(map[string]int)
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+
+		for _, a := range fields {
+			if _, err := cipher.DecodeBase58Address(a); err != nil {
+				return nil, fmt.Errorf("line %d: invalid address %q: %v", n, a, err)
+			}
+
+			if m, ok := seen[a]; ok {
+				return nil, fmt.Errorf("line %d: duplicate address %s of line %d", n, a, m)
+			}
+			seen[a] = n
+
+			addrs = append(addrs, a)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(addrs) == 0 {
+		return nil, errors.New("no addresses")
+	}
+
+	return addrs, nil
+}
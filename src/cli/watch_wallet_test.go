@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestReadWatchAddresses(t *testing.T) {
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+	addr3 := testutil.MakeAddress().String()
+
+	tt := []struct {
+		name  string
+		in    string
+		addrs []string
+		err   string
+	}{
+		{
+			name:  "one per line",
+			in:    addr1 + "\n" + addr2 + "\n",
+			addrs: []string{addr1, addr2},
+		},
+		{
+			name:  "separated by commas and spaces, with comments",
+			in:    "# deposit addresses\n\n" + addr1 + ", " + addr2 + "\n\t" + addr3 + "  \n",
+			addrs: []string{addr1, addr2, addr3},
+		},
+		{
+			name: "invalid address",
+			in:   addr1 + "\nfoo\n",
+			err:  `line 2: invalid address "foo": Invalid address length`,
+		},
+		{
+			name: "duplicate address",
+			in:   addr1 + "\n# comment\n" + addr2 + "," + addr1,
+			err:  "line 3: duplicate address " + addr1 + " of line 1",
+		},
+		{
+			name: "no addresses",
+			in:   "# empty\n\n",
+			err:  "no addresses",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			addrs, err := readWatchAddresses(strings.NewReader(tc.in))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.addrs, addrs)
+		})
+	}
+}
+
+func TestMakeWatchWalletOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr1 := testutil.MakeAddress().String()
+	addr2 := testutil.MakeAddress().String()
+	addrsFile := filepath.Join(dir, "addrs.txt")
+	require.NoError(t, ioutil.WriteFile(addrsFile, []byte(addr1+"\n"+addr2+"\n"), 0600))
+
+	badFile := filepath.Join(dir, "bad.txt")
+	require.NoError(t, ioutil.WriteFile(badFile, []byte("foo\n"), 0600))
+
+	xpub := "xpub6CkxdS1d4vNqqcnf9xPgqR5e2jE2PZKmKSw93QQMjHE1hRk22nU4zns85EDRgmLWYXYtu62XexwqaET33XA28c26NbXCAUJh1xmqq6B3S2v"
+
+	tt := []struct {
+		name string
+		args []string
+		opts *api.CreateWalletOptions
+		num  uint64
+		err  string
+	}{
+		{
+			name: "xpub",
+			args: []string{"--xpub", xpub},
+			opts: &api.CreateWalletOptions{
+				Type:  wallet.WalletTypeXPub,
+				Label: "foo",
+				XPub:  xpub,
+				ScanN: 1,
+			},
+			num: 1,
+		},
+		{
+			name: "xpub account",
+			args: []string{"--xpub", xpub, "--xpub-account", "-n", "5", "--scan", "20"},
+			opts: &api.CreateWalletOptions{
+				Type:        wallet.WalletTypeXPub,
+				Label:       "foo",
+				XPub:        xpub,
+				XPubAccount: true,
+				ScanN:       20,
+			},
+			num: 5,
+		},
+		{
+			name: "addresses",
+			args: []string{"--addresses", addrsFile},
+			opts: &api.CreateWalletOptions{
+				Type:      wallet.WalletTypeCollectionWatch,
+				Label:     "foo",
+				Addresses: []string{addr1, addr2},
+			},
+		},
+		{
+			name: "missing xpub and addresses",
+			err:  "missing --xpub or --addresses",
+		},
+		{
+			name: "xpub and addresses",
+			args: []string{"--xpub", xpub, "--addresses", addrsFile},
+			err:  "--xpub and --addresses can't be used together",
+		},
+		{
+			name: "xpub zero num",
+			args: []string{"--xpub", xpub, "-n", "0"},
+			err:  "-n must > 0",
+		},
+		{
+			name: "addresses with num",
+			args: []string{"--addresses", addrsFile, "-n", "2"},
+			err:  `"collection-watch" type wallets do not support address generation`,
+		},
+		{
+			name: "addresses with xpub account",
+			args: []string{"--addresses", addrsFile, "--xpub-account"},
+			err:  "--xpub-account requires --xpub",
+		},
+		{
+			name: "invalid addresses file",
+			args: []string{"--addresses", badFile},
+			err:  "invalid addresses file " + badFile + `: line 1: invalid address "foo": Invalid address length`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := walletCreateWatchCmd()
+			require.NoError(t, c.ParseFlags(tc.args))
+
+			opts, num, err := makeWatchWalletOptions(c, "foo")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.opts, opts)
+			require.Equal(t, tc.num, num)
+		})
+	}
+}
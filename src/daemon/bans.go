@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/util/iputil"
+)
+
+var (
+	// ErrInvalidBanIP is returned when banning or unbanning an invalid IP address
+	ErrInvalidBanIP = errors.New("invalid IP address")
+	// ErrPeerNotBanned is returned when unbanning an IP address that is not banned
+	ErrPeerNotBanned = errors.New("IP address is not banned")
+)
+
+// BannedPeer is an IP address banned by the node operator
+type BannedPeer struct {
+	IP       string
+	BannedAt time.Time
+}
+
+// banList is the set of IP addresses banned by the node operator.
+// The bans are not persisted, they are lifted when the node restarts.
+type banList struct {
+	sync.RWMutex
+	ips map[string]time.Time
+}
+
+func newBanList() *banList {
+	return &banList{
+		ips: make(map[string]time.Time),
+	}
+}
+
+// normalizeBanIP returns the canonical form of an IP address, for the comparison of IPv6 addresses
+func normalizeBanIP(ip string) (string, error) {
+	p := net.ParseIP(ip)
+	if p == nil {
+		return "", ErrInvalidBanIP
+	}
+	return p.String(), nil
+}
+
+func (b *banList) add(ip string) {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, ok := b.ips[ip]; !ok {
+		b.ips[ip] = time.Now().UTC()
+	}
+}
+
+func (b *banList) remove(ip string) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, ok := b.ips[ip]; !ok {
+		return false
+	}
+	delete(b.ips, ip)
+	return true
+}
+
+// isBanned returns true if the IP of an ip:port address is banned
+func (b *banList) isBanned(addr string) bool {
+	ip, _, err := iputil.SplitAddr(addr)
+	if err != nil {
+		return false
+	}
+
+	ip, err = normalizeBanIP(ip)
+	if err != nil {
+		return false
+	}
+
+	b.RLock()
+	defer b.RUnlock()
+
+	_, ok := b.ips[ip]
+	return ok
+}
+
+func (b *banList) all() []BannedPeer {
+	b.RLock()
+	defer b.RUnlock()
+
+	peers := make([]BannedPeer, 0, len(b.ips))
+	for ip, t := range b.ips {
+		peers = append(peers, BannedPeer{
+			IP:       ip,
+			BannedAt: t,
+		})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].IP < peers[j].IP
+	})
+
+	return peers
+}
+
+// BanPeer bans an IP address until the node is restarted. The connections to the IP address are
+// disconnected, and new connections to or from it are refused. Returns the number of connections disconnected.
+func (dm *Daemon) BanPeer(ip string) (int, error) {
+	ip, err := normalizeBanIP(ip)
+	if err != nil {
+		return 0, err
+	}
+
+	dm.bans.add(ip)
+
+	var n int
+	for _, c := range dm.connections.all() {
+		if !dm.bans.isBanned(c.Addr) {
+			continue
+		}
+
+		if err := dm.Disconnect(c.Addr, ErrDisconnectIsBlacklisted); err != nil {
+			logger.WithError(err).WithField("addr", c.Addr).Warning("BanPeer Disconnect failed")
+			continue
+		}
+		n++
+	}
+
+	logger.WithField("ip", ip).Infof("Banned peer, disconnecting %d connections", n)
+
+	return n, nil
+}
+
+// UnbanPeer lifts the ban of an IP address
+func (dm *Daemon) UnbanPeer(ip string) error {
+	ip, err := normalizeBanIP(ip)
+	if err != nil {
+		return err
+	}
+
+	if !dm.bans.remove(ip) {
+		return ErrPeerNotBanned
+	}
+
+	logger.WithField("ip", ip).Info("Unbanned peer")
+
+	return nil
+}
+
+// GetBannedPeers returns the banned IP addresses, sorted by IP
+func (dm *Daemon) GetBannedPeers() []BannedPeer {
+	return dm.bans.all()
+}
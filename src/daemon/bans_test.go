@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanPeer(t *testing.T) {
+	dm := &Daemon{
+		connections: NewConnections(),
+		bans:        newBanList(),
+	}
+
+	_, err := dm.BanPeer("1.2.3")
+	require.Equal(t, ErrInvalidBanIP, err)
+
+	n, err := dm.BanPeer("2001:db8:0:0:0:0:0:1")
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = dm.BanPeer("10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// Banning again keeps the time of the first ban
+	bannedAt := dm.GetBannedPeers()[0].BannedAt
+	_, err = dm.BanPeer("10.0.0.1")
+	require.NoError(t, err)
+
+	peers := dm.GetBannedPeers()
+	require.Len(t, peers, 2)
+	require.Equal(t, "10.0.0.1", peers[0].IP)
+	require.Equal(t, bannedAt, peers[0].BannedAt)
+	require.Equal(t, "2001:db8::1", peers[1].IP)
+
+	require.True(t, dm.bans.isBanned("10.0.0.1:6000"))
+	require.True(t, dm.bans.isBanned("[2001:db8::1]:6000"))
+	require.False(t, dm.bans.isBanned("10.0.0.2:6000"))
+	require.False(t, dm.bans.isBanned("10.0.0.1"))
+
+	require.Equal(t, ErrInvalidBanIP, dm.UnbanPeer("foo"))
+	require.Equal(t, ErrPeerNotBanned, dm.UnbanPeer("10.0.0.2"))
+	require.NoError(t, dm.UnbanPeer("2001:db8::1"))
+
+	peers = dm.GetBannedPeers()
+	require.Len(t, peers, 1)
+	require.Equal(t, "10.0.0.1", peers[0].IP)
+	require.False(t, dm.bans.isBanned("[2001:db8::1]:6000"))
+}
@@ -93,6 +93,16 @@ func (cfg *Config) preprocess() (Config, error) {
 	}
 	config.Pool.port = config.Daemon.Port
 	config.Pool.address = config.Daemon.Address
+	config.Pool.socks5Proxy = config.Daemon.SOCKS5Proxy
+
+	if config.Daemon.TorOnly {
+		if config.Daemon.SOCKS5Proxy == "" {
+			return Config{}, errors.New("TorOnly requires a SOCKS5Proxy to connect to the onion peers through")
+		}
+		// Peer lists are IPv4 peers that cannot be connected to
+		config.Pex.OnionOnly = true
+		config.Pex.DownloadPeerList = false
+	}
 
 	if config.Daemon.DisableNetworking {
 		logger.Info("Networking is disabled")
@@ -188,6 +198,10 @@ type DaemonConfig struct { //nolint:golint
 	DisableIncomingConnections bool
 	// Run on localhost and only connect to localhost peers
 	LocalhostOnly bool
+	// Address of a SOCKS5 proxy to make the outgoing connections through, e.g. a Tor client
+	SOCKS5Proxy string
+	// Only connect to the onion peers, through the SOCKS5Proxy
+	TorOnly bool
 	// Log ping and pong messages
 	LogPings bool
 	// How often to request blocks from peers
@@ -243,6 +257,8 @@ func NewDaemonConfig() DaemonConfig {
 		DisableOutgoingConnections:   false,
 		DisableIncomingConnections:   false,
 		LocalhostOnly:                false,
+		SOCKS5Proxy:                  "",
+		TorOnly:                      false,
 		LogPings:                     true,
 		BlocksRequestRate:            time.Second * 60,
 		BlocksAnnounceRate:           time.Second * 60,
@@ -273,7 +289,7 @@ type daemoner interface {
 	recordPeerHeight(addr string, gnetID, height uint64)
 	getSignedBlocksSince(seq, count uint64) ([]coin.SignedBlock, error)
 	headBkSeq() (uint64, bool, error)
-	executeSignedBlock(b coin.SignedBlock) error
+	executeSignedBlocks(blocks []coin.SignedBlock) (int, error)
 	filterKnownUnconfirmed(txns []cipher.SHA256) ([]cipher.SHA256, error)
 	getKnownUnconfirmed(txns []cipher.SHA256) (coin.Transactions, error)
 	requestBlocksFromAddr(addr string) error
@@ -298,8 +314,12 @@ type Daemon struct {
 
 	// Cache of announced transactions that are flushed to the database periodically
 	announcedTxns *announcedTxnsCache
+	// Tracks the peers the transactions were sent to and the rejected user transactions
+	txnTracker *txnTracker
 	// Cache of connection metadata
 	connections *Connections
+	// IP addresses banned by the node operator
+	bans *banList
 	// connect, disconnect, message, error events channel
 	events chan interface{}
 	// quit channel
@@ -330,7 +350,9 @@ func New(config Config, v *visor.Visor) (*Daemon, error) {
 		visor:    v,
 
 		announcedTxns: newAnnouncedTxnsCache(),
+		txnTracker:    newTxnTracker(),
 		connections:   NewConnections(),
+		bans:          newBanList(),
 		events:        make(chan interface{}, config.Pool.EventChannelSize),
 		quit:          make(chan struct{}),
 		done:          make(chan struct{}),
@@ -702,6 +724,7 @@ func (dm *Daemon) startUnconfirmedTxnsProcess(wg *sync.WaitGroup) {
 			}
 			if len(removedTxns) > 0 {
 				logger.Infof("Remove %d txns from pool that began violating hard constraints", len(removedTxns))
+				dm.txnTracker.addEvicted(removedTxns, EvictionReasonInvalid)
 			}
 		}
 	}
@@ -725,6 +748,18 @@ func (dm *Daemon) connectToPeer(p pex.Peer) error {
 		return errors.New("Not localhost")
 	}
 
+	if pex.IsOnionAddress(p.Addr) {
+		if dm.config.SOCKS5Proxy == "" {
+			return errors.New("Onion peer requires a SOCKS5 proxy")
+		}
+	} else if dm.config.TorOnly {
+		return errors.New("Not an onion peer")
+	}
+
+	if dm.bans.isBanned(p.Addr) {
+		return errors.New("Peer is banned")
+	}
+
 	if c := dm.connections.get(p.Addr); c != nil {
 		return errors.New("Already connected to this peer")
 	}
@@ -936,6 +971,14 @@ func (dm *Daemon) onConnectEvent(e ConnectEvent) {
 		logger.Critical().WithFields(fields).Warning("Connection.Outgoing does not match ConnectEvent.Solicited state")
 	}
 
+	if dm.bans.isBanned(e.Addr) {
+		logger.WithFields(fields).Info("Peer is banned, disconnecting")
+		if err := dm.Disconnect(e.Addr, ErrDisconnectIsBlacklisted); err != nil {
+			logger.WithError(err).WithFields(fields).Error("Disconnect")
+		}
+		return
+	}
+
 	if dm.ipCountMaxed(e.Addr) {
 		logger.WithFields(fields).Info("Max connections for this IP address reached, disconnecting")
 		if err := dm.Disconnect(e.Addr, ErrDisconnectIPLimitReached); err != nil {
@@ -1072,6 +1115,7 @@ func (dm *Daemon) handleMessageSendResult(r gnet.SendResult) {
 
 	if m, ok := r.Message.(SendingTxnsMessage); ok {
 		dm.announcedTxns.add(m.GetFiltered())
+		dm.txnTracker.addSent(m.GetFiltered(), r.Addr)
 	}
 
 	if m, ok := r.Message.(*DisconnectMessage); ok {
@@ -1529,9 +1573,9 @@ func (dm *Daemon) headBkSeq() (uint64, bool, error) {
 	return dm.visor.HeadBkSeq()
 }
 
-// executeSignedBlock executes the signed block
-func (dm *Daemon) executeSignedBlock(b coin.SignedBlock) error {
-	return dm.visor.ExecuteSignedBlock(b)
+// executeSignedBlocks executes the signed blocks in sequence, returning the number of blocks executed
+func (dm *Daemon) executeSignedBlocks(blocks []coin.SignedBlock) (int, error) {
+	return dm.visor.ExecuteSignedBlocks(blocks)
 }
 
 // filterKnownUnconfirmed returns unconfirmed txn hashes with known ones removed
@@ -1677,6 +1721,31 @@ func (dm *Daemon) DisconnectByGnetID(gnetID uint64) error {
 	return dm.Disconnect(c.Addr, ErrDisconnectRequestedByOperator)
 }
 
+// ReconnectPeers disconnects all outgoing connections, which are then replaced
+// by new outgoing connections. Returns the number of connections disconnected.
+func (dm *Daemon) ReconnectPeers() (int, error) {
+	if dm.config.DisableNetworking {
+		return 0, ErrNetworkingDisabled
+	}
+
+	var n int
+	for _, c := range dm.connections.all() {
+		if !c.Outgoing {
+			continue
+		}
+
+		if err := dm.Disconnect(c.Addr, ErrDisconnectRequestedByOperator); err != nil {
+			logger.WithError(err).WithField("addr", c.Addr).Warning("ReconnectPeers Disconnect failed")
+			continue
+		}
+		n++
+	}
+
+	logger.Infof("Reconnecting %d outgoing peers", n)
+
+	return n, nil
+}
+
 // GetTrustConnections returns all trusted connections
 func (dm *Daemon) GetTrustConnections() []string {
 	return dm.pex.AllTrusted().ToAddrs()
@@ -1756,10 +1825,15 @@ func (dm *Daemon) GetBlockchainProgress(headSeq uint64) *BlockchainProgress {
 // For transactions received over the network, use daemon.injectTransaction and check the result to
 // decide on repropagation.
 func (dm *Daemon) InjectBroadcastTransaction(txn coin.Transaction) error {
-	return dm.visor.WithUpdateTx("daemon.InjectBroadcastTransaction", func(tx *dbutil.Tx) error {
-		_, head, inputs, err := dm.visor.InjectUserTransactionTx(tx, txn)
+	var known bool
+	if err := dm.visor.WithUpdateTx("daemon.InjectBroadcastTransaction", func(tx *dbutil.Tx) error {
+		var head *coin.SignedBlock
+		var inputs coin.UxArray
+		var err error
+		known, head, inputs, err = dm.visor.InjectUserTransactionTx(tx, txn)
 		if err != nil {
 			logger.WithError(err).Error("InjectUserTransactionTx failed")
+			dm.recordRejectedTransaction(txn, err)
 			return err
 		}
 
@@ -1769,7 +1843,15 @@ func (dm *Daemon) InjectBroadcastTransaction(txn coin.Transaction) error {
 		}
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	if !known {
+		dm.visor.PublishUnconfirmedTxnInjected(txn)
+	}
+
+	return nil
 }
 
 // InjectTransaction injects transaction to the unconfirmed pool but does not broadcast it.
@@ -1779,5 +1861,8 @@ func (dm *Daemon) InjectBroadcastTransaction(txn coin.Transaction) error {
 // decide on repropagation.
 func (dm *Daemon) InjectTransaction(txn coin.Transaction) error {
 	_, _, _, err := dm.visor.InjectUserTransaction(txn)
+	if err != nil {
+		dm.recordRejectedTransaction(txn, err)
+	}
 	return err
 }
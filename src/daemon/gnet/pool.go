@@ -113,6 +113,9 @@ type Config struct {
 	// Timeout is the timeout for dialing new connections.  Use a
 	// timeout of 0 to ignore timeout.
 	DialTimeout time.Duration
+	// Address of a SOCKS5 proxy, e.g. a Tor client, to make the outgoing connections through.
+	// The connections are made directly if empty.
+	SOCKS5Proxy string
 	// Timeout for reading from a connection. Set to 0 to default to the
 	// system's timeout
 	ReadTimeout time.Duration
@@ -827,7 +830,7 @@ func (pool *ConnectionPool) Connect(address string) error {
 	}
 
 	logger.WithField("addr", address).Debugf("Making TCP connection")
-	conn, err := net.DialTimeout("tcp", address, pool.Config.DialTimeout)
+	conn, err := pool.dial(address)
 	if err != nil {
 		return err
 	}
@@ -845,6 +848,14 @@ func (pool *ConnectionPool) Connect(address string) error {
 	return nil
 }
 
+// dial makes a TCP connection to an address, through the SOCKS5 proxy if configured
+func (pool *ConnectionPool) dial(address string) (net.Conn, error) {
+	if pool.Config.SOCKS5Proxy != "" {
+		return dialSOCKS5(pool.Config.SOCKS5Proxy, address, pool.Config.DialTimeout)
+	}
+	return net.DialTimeout("tcp", address, pool.Config.DialTimeout)
+}
+
 // Disconnect removes a connection from the pool by address and invokes DisconnectCallback
 func (pool *ConnectionPool) Disconnect(addr string, r DisconnectReason) error {
 	return pool.strand("Disconnect", func() error {
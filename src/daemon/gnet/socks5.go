@@ -0,0 +1,198 @@
+package gnet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 protocol constants, see RFC 1928
+const (
+	socks5Version        = 0x05
+	socks5AuthNone       = 0x00
+	socks5AuthNoneFound  = 0xff
+	socks5CmdConnect     = 0x01
+	socks5AddrTypeIPv4   = 0x01
+	socks5AddrTypeDomain = 0x03
+	socks5AddrTypeIPv6   = 0x04
+	socks5ReplySucceeded = 0x00
+)
+
+var (
+	// ErrSOCKS5AuthRequired is returned when the SOCKS5 proxy requires an authentication
+	ErrSOCKS5AuthRequired = errors.New("SOCKS5 proxy requires an authentication method that is not supported")
+	// ErrSOCKS5InvalidResponse is returned when the SOCKS5 proxy sends an invalid response
+	ErrSOCKS5InvalidResponse = errors.New("Invalid SOCKS5 proxy response")
+)
+
+// socks5Replies are the messages of the failure replies of a SOCKS5 proxy
+var socks5Replies = map[byte]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// proxyAddr is the remote address of a connection made through a proxy
+type proxyAddr string
+
+// Network implements net.Addr
+func (a proxyAddr) Network() string {
+	return "tcp"
+}
+
+// String implements net.Addr
+func (a proxyAddr) String() string {
+	return string(a)
+}
+
+// proxyConn is a connection made through a proxy. Its remote address is the address
+// the connection was made to instead of the address of the proxy, the connections
+// of the pool are keyed by their remote address.
+type proxyConn struct {
+	net.Conn
+	addr proxyAddr
+}
+
+// RemoteAddr implements net.Conn
+func (c *proxyConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// dialSOCKS5 connects to address through the SOCKS5 proxy at proxy, without authentication.
+// Domain names, such as the .onion addresses of Tor, are resolved by the proxy.
+// The timeout applies to the connection to the proxy and to the SOCKS5 handshake, a timeout of 0 is ignored.
+func dialSOCKS5(proxy, address string, timeout time.Duration) (net.Conn, error) {
+	req, err := socks5ConnectRequest(address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", proxy, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := socks5Handshake(conn, req, timeout); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SOCKS5 proxy %s: %v", proxy, err)
+	}
+
+	return &proxyConn{
+		Conn: conn,
+		addr: proxyAddr(address),
+	}, nil
+}
+
+// socks5ConnectRequest creates the CONNECT request of a host:port address
+func socks5ConnectRequest(address string) ([]byte, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid port %q", portStr)
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(req, socks5AddrTypeIPv4)
+			req = append(req, ip4...)
+		} else {
+			req = append(req, socks5AddrTypeIPv6)
+			req = append(req, ip.To16()...)
+		}
+	} else {
+		if len(host) == 0 || len(host) > 255 {
+			return nil, fmt.Errorf("Invalid host %q", host)
+		}
+		req = append(req, socks5AddrTypeDomain, byte(len(host)))
+		req = append(req, host...)
+	}
+
+	return append(req, byte(port>>8), byte(port)), nil
+}
+
+// socks5Handshake negotiates no authentication with the proxy and sends the CONNECT request
+func socks5Handshake(conn net.Conn, req []byte, timeout time.Duration) error {
+	if timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := conn.Write([]byte{socks5Version, 1, socks5AuthNone}); err != nil {
+		return err
+	}
+
+	b := make([]byte, 2)
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return err
+	}
+	if b[0] != socks5Version {
+		return ErrSOCKS5InvalidResponse
+	}
+	switch b[1] {
+	case socks5AuthNone:
+	case socks5AuthNoneFound:
+		return ErrSOCKS5AuthRequired
+	default:
+		return ErrSOCKS5InvalidResponse
+	}
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// The reply is VER, REP, RSV, ATYP, BND.ADDR and BND.PORT
+	b = make([]byte, 4)
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return err
+	}
+	if b[0] != socks5Version {
+		return ErrSOCKS5InvalidResponse
+	}
+	if b[1] != socks5ReplySucceeded {
+		if msg, ok := socks5Replies[b[1]]; ok {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("unknown SOCKS5 reply %d", b[1])
+	}
+
+	var n int
+	switch b[3] {
+	case socks5AddrTypeIPv4:
+		n = net.IPv4len
+	case socks5AddrTypeIPv6:
+		n = net.IPv6len
+	case socks5AddrTypeDomain:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return err
+		}
+		n = int(l[0])
+	default:
+		return ErrSOCKS5InvalidResponse
+	}
+
+	// The bound address and port are not used
+	if _, err := io.ReadFull(conn, make([]byte, n+2)); err != nil {
+		return err
+	}
+
+	if timeout > 0 {
+		return conn.SetDeadline(time.Time{})
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package gnet
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testOnionAddr = "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion:6000"
+
+// runSOCKS5Proxy runs a SOCKS5 proxy that accepts a connection, replies to the method selection with method,
+// reads the CONNECT request and replies with reply. After a successful reply it echoes the data it reads.
+// The CONNECT request is sent to requests.
+func runSOCKS5Proxy(t *testing.T, method, reply byte, requests chan<- []byte) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		defer l.Close()
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		greeting := make([]byte, 3)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return
+		}
+
+		if _, err := conn.Write([]byte{socks5Version, method}); err != nil || method != socks5AuthNone {
+			return
+		}
+
+		// The request header and the length of the domain name
+		req := make([]byte, 5)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		host := make([]byte, int(req[4])+2)
+		if _, err := io.ReadFull(conn, host); err != nil {
+			return
+		}
+		requests <- append(req, host...)
+
+		if _, err := conn.Write([]byte{socks5Version, reply, 0x00, socks5AddrTypeIPv4, 127, 0, 0, 1, 0x17, 0x70}); err != nil {
+			return
+		}
+
+		if reply == socks5ReplySucceeded {
+			io.Copy(conn, conn) //nolint:errcheck
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func TestDialSOCKS5(t *testing.T) {
+	requests := make(chan []byte, 1)
+	proxy := runSOCKS5Proxy(t, socks5AuthNone, socks5ReplySucceeded, requests)
+
+	conn, err := dialSOCKS5(proxy, testOnionAddr, time.Second)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The onion address is resolved by the proxy
+	host := "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion"
+	expected := append([]byte{socks5Version, socks5CmdConnect, 0x00, socks5AddrTypeDomain, byte(len(host))}, host...)
+	expected = append(expected, 0x17, 0x70)
+	require.Equal(t, expected, <-requests)
+
+	// The remote address is the address the connection was made to
+	require.Equal(t, testOnionAddr, conn.RemoteAddr().String())
+	require.Equal(t, "tcp", conn.RemoteAddr().Network())
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	b := make([]byte, 4)
+	_, err = io.ReadFull(conn, b)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(b))
+}
+
+func TestDialSOCKS5Errors(t *testing.T) {
+	proxy := runSOCKS5Proxy(t, socks5AuthNoneFound, socks5ReplySucceeded, nil)
+	_, err := dialSOCKS5(proxy, testOnionAddr, time.Second)
+	require.EqualError(t, err, "SOCKS5 proxy "+proxy+": "+ErrSOCKS5AuthRequired.Error())
+
+	requests := make(chan []byte, 1)
+	proxy = runSOCKS5Proxy(t, socks5AuthNone, 0x04, requests)
+	_, err = dialSOCKS5(proxy, testOnionAddr, time.Second)
+	require.EqualError(t, err, "SOCKS5 proxy "+proxy+": host unreachable")
+
+	_, err = dialSOCKS5(proxy, "foo", time.Second)
+	require.EqualError(t, err, "address foo: missing port in address")
+}
+
+func TestSOCKS5ConnectRequest(t *testing.T) {
+	cases := []struct {
+		addr string
+		req  []byte
+		err  string
+	}{
+		{
+			addr: "1.2.3.4:6000",
+			req:  []byte{socks5Version, socks5CmdConnect, 0x00, socks5AddrTypeIPv4, 1, 2, 3, 4, 0x17, 0x70},
+		},
+		{
+			addr: "[::1]:6000",
+			req:  []byte{socks5Version, socks5CmdConnect, 0x00, socks5AddrTypeIPv6, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0x17, 0x70},
+		},
+		{
+			addr: "foo.onion:1",
+			req:  []byte{socks5Version, socks5CmdConnect, 0x00, socks5AddrTypeDomain, 9, 'f', 'o', 'o', '.', 'o', 'n', 'i', 'o', 'n', 0x00, 0x01},
+		},
+		{
+			addr: ":6000",
+			err:  `Invalid host ""`,
+		},
+		{
+			addr: "foo.onion:70000",
+			err:  `Invalid port "70000"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.addr, func(t *testing.T) {
+			req, err := socks5ConnectRequest(tc.addr)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.req, req)
+		})
+	}
+}
+
+func TestConnectSOCKS5Proxy(t *testing.T) {
+	requests := make(chan []byte, 1)
+	cfg := newTestConfig()
+	cfg.SOCKS5Proxy = runSOCKS5Proxy(t, socks5AuthNone, socks5ReplySucceeded, requests)
+
+	p, err := NewConnectionPool(cfg, nil)
+	require.NoError(t, err)
+
+	q := make(chan struct{})
+	go func() {
+		defer close(q)
+		err := p.Run()
+		require.NoError(t, err)
+	}()
+	wait()
+
+	err = p.Connect(testOnionAddr)
+	require.NoError(t, err)
+	<-requests
+	wait()
+
+	// The connection is keyed by the onion address, not by the address of the proxy
+	c, err := p.GetConnection(testOnionAddr)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	require.True(t, c.Solicited)
+	require.Equal(t, testOnionAddr, c.Addr())
+
+	p.Shutdown()
+	<-q
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// maxTrackedEvictions is the maximum number of evictions from the unconfirmed pool that are tracked.
+	// When it is reached, the oldest eviction is forgotten.
+	maxTrackedEvictions = 1000
+
+	// EvictionReasonInvalid the transaction was evicted because it began violating hard constraints,
+	// e.g. its inputs were spent by another transaction
+	EvictionReasonInvalid = "transaction violates hard constraints"
+)
+
+// MempoolTransaction is a transaction of the unconfirmed pool, with the details of its propagation
+type MempoolTransaction struct {
+	visor.UnconfirmedTransaction
+	Inputs []visor.TransactionInput
+	// Peers is the number of peers the transaction was sent or announced to since the node started
+	Peers int
+	// Announces is the number of times the transaction was sent or announced to a peer since the node started
+	Announces int
+	// LastSentAt is the last time the transaction was sent or announced to a peer
+	LastSentAt time.Time
+	// Violation is why the transaction is not valid against the blockchain, if it is not valid
+	Violation string
+}
+
+// MempoolEviction is a transaction evicted from the unconfirmed pool
+type MempoolEviction struct {
+	Txid      cipher.SHA256
+	Reason    string
+	EvictedAt time.Time
+}
+
+// addEvicted records that the transactions were evicted from the unconfirmed pool
+func (t *txnTracker) addEvicted(txids []cipher.SHA256, reason string) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := t.now()
+	for _, txid := range txids {
+		t.evictions = append(t.evictions, MempoolEviction{
+			Txid:      txid,
+			Reason:    reason,
+			EvictedAt: now,
+		})
+	}
+
+	if n := len(t.evictions) - maxTrackedEvictions; n > 0 {
+		t.evictions = append(t.evictions[:0:0], t.evictions[n:]...)
+	}
+}
+
+// getEvictions returns the evictions from the unconfirmed pool, the most recent first
+func (t *txnTracker) getEvictions() []MempoolEviction {
+	t.Lock()
+	defer t.Unlock()
+
+	evictions := make([]MempoolEviction, len(t.evictions))
+	for i, e := range t.evictions {
+		evictions[len(evictions)-1-i] = e
+	}
+	return evictions
+}
+
+// getSends returns the number of peers a transaction was sent or announced to,
+// the number of times it was sent or announced and the last time it was sent
+func (t *txnTracker) getSends(txid cipher.SHA256) (int, int, time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	s, ok := t.sends[txid]
+	if !ok {
+		return 0, 0, time.Time{}
+	}
+	return len(s.peers), s.count, s.last
+}
+
+// GetMempoolTransactions returns the transactions of the unconfirmed pool with the details of their propagation.
+// If addrs is not empty, only the transactions with an input or output of one of addrs are returned.
+func (dm *Daemon) GetMempoolTransactions(addrs []cipher.Address) ([]MempoolTransaction, error) {
+	txns, inputs, err := dm.visor.GetAllUnconfirmedTransactionsVerbose()
+	if err != nil {
+		return nil, err
+	}
+
+	addrMap := make(map[cipher.Address]struct{}, len(addrs))
+	for _, a := range addrs {
+		addrMap[a] = struct{}{}
+	}
+
+	hasAddress := func(txn visor.UnconfirmedTransaction, inputs []visor.TransactionInput) bool {
+		for _, in := range inputs {
+			if _, ok := addrMap[in.UxOut.Body.Address]; ok {
+				return true
+			}
+		}
+		for _, o := range txn.Transaction.Out {
+			if _, ok := addrMap[o.Address]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	mempoolTxns := make([]MempoolTransaction, 0, len(txns))
+	for i, txn := range txns {
+		if len(addrMap) != 0 && !hasAddress(txn, inputs[i]) {
+			continue
+		}
+
+		peers, announces, lastSent := dm.txnTracker.getSends(txn.Transaction.Hash())
+
+		mTxn := MempoolTransaction{
+			UnconfirmedTransaction: txn,
+			Inputs:                 inputs[i],
+			Peers:                  peers,
+			Announces:              announces,
+			LastSentAt:             lastSent,
+		}
+
+		if txn.IsValid != 1 {
+			mTxn.Violation = ErrTxnNotValid.Error()
+			if _, _, err := dm.visor.VerifyTxnVerbose(&txn.Transaction, visor.TxnSigned); err != nil {
+				mTxn.Violation = err.Error()
+			}
+		}
+
+		mempoolTxns = append(mempoolTxns, mTxn)
+	}
+
+	return mempoolTxns, nil
+}
+
+// GetMempoolEvictions returns the transactions evicted from the unconfirmed pool since the node started, the most recent first
+func (dm *Daemon) GetMempoolEvictions() []MempoolEviction {
+	return dm.txnTracker.getEvictions()
+}
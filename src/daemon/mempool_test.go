@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestTxnTrackerSends(t *testing.T) {
+	tracker := newTxnTracker()
+
+	now := time.Unix(1539849621, 0)
+	tracker.now = func() time.Time {
+		return now
+	}
+
+	txid := testutil.RandSHA256(t)
+
+	peers, announces, last := tracker.getSends(txid)
+	require.Equal(t, 0, peers)
+	require.Equal(t, 0, announces)
+	require.True(t, last.IsZero())
+
+	// Sending to the same peer again counts as another announce, but not as another peer
+	tracker.addSent([]cipher.SHA256{txid}, "1.2.3.4:6000")
+	tracker.addSent([]cipher.SHA256{txid}, "1.2.3.4:6000")
+	tracker.addSent([]cipher.SHA256{txid}, "5.6.7.8:6000")
+
+	peers, announces, last = tracker.getSends(txid)
+	require.Equal(t, 2, peers)
+	require.Equal(t, 3, announces)
+	require.Equal(t, now, last)
+}
+
+func TestTxnTrackerEvictions(t *testing.T) {
+	tracker := newTxnTracker()
+
+	now := time.Unix(1539849621, 0)
+	tracker.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	require.Empty(t, tracker.getEvictions())
+
+	txids := make([]cipher.SHA256, maxTrackedEvictions+1)
+	for i := range txids {
+		txids[i] = testutil.RandSHA256(t)
+	}
+
+	tracker.addEvicted(txids[:1], EvictionReasonInvalid)
+	tracker.addEvicted(txids[1:], EvictionReasonInvalid)
+
+	// The oldest eviction is forgotten, the most recent is first
+	evictions := tracker.getEvictions()
+	require.Len(t, evictions, maxTrackedEvictions)
+	require.Equal(t, txids[maxTrackedEvictions], evictions[0].Txid)
+	require.Equal(t, txids[1], evictions[maxTrackedEvictions-1].Txid)
+	require.Equal(t, EvictionReasonInvalid, evictions[0].Reason)
+	require.Equal(t, now, evictions[0].EvictedAt)
+}
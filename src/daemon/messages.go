@@ -884,7 +884,6 @@ func (m *GiveBlocksMessage) process(d daemoner) {
 	// These DB queries are not performed in a transaction for performance reasons.
 	// It is not necessary that the blocks be executed together in a single transaction.
 
-	processed := 0
 	maxSeq, ok, err := d.headBkSeq()
 	if err != nil {
 		logger.WithError(err).Error("d.headBkSeq failed")
@@ -895,27 +894,27 @@ func (m *GiveBlocksMessage) process(d daemoner) {
 		return
 	}
 
+	// To minimize waste when receiving multiple responses from peers
+	// we only stop executing blocks if a block itself is invalid.
+	// E.g. if we request 20 blocks since 0 from 2 peers, and one peer
+	// replies with 15 and the other 20, if we did not do this check and
+	// the reply with 15 was received first, we would toss the one with 20
+	// even though we could process it at the time.
+	blocks := make([]coin.SignedBlock, 0, len(m.Blocks))
 	for _, b := range m.Blocks {
-		// To minimize waste when receiving multiple responses from peers
-		// we only break out of the loop if the block itself is invalid.
-		// E.g. if we request 20 blocks since 0 from 2 peers, and one peer
-		// replies with 15 and the other 20, if we did not do this check and
-		// the reply with 15 was received first, we would toss the one with 20
-		// even though we could process it at the time.
-		if b.Seq() <= maxSeq {
-			continue
+		if b.Seq() > maxSeq {
+			blocks = append(blocks, b)
 		}
+	}
 
-		err := d.executeSignedBlock(b)
-		if err == nil {
-			logger.Critical().WithField("seq", b.Block.Head.BkSeq).Info("Added new block")
-			processed++
-		} else {
-			logger.Critical().WithError(err).WithField("seq", b.Block.Head.BkSeq).Error("Failed to execute received block")
-			// Blocks must be received in order, so if one fails its assumed
-			// the rest are failing
-			break
-		}
+	// The block signatures are verified together, then the blocks are executed in order.
+	// Blocks must be received in order, so if one fails its assumed the rest are failing.
+	processed, err := d.executeSignedBlocks(blocks)
+	for _, b := range blocks[:processed] {
+		logger.Critical().WithField("seq", b.Block.Head.BkSeq).Info("Added new block")
+	}
+	if err != nil {
+		logger.Critical().WithError(err).WithField("seq", blocks[processed].Block.Head.BkSeq).Error("Failed to execute received block")
 	}
 	if processed == 0 {
 		return
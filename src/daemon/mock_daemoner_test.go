@@ -130,18 +130,25 @@ func (_m *mockDaemoner) disconnectNow(addr string, r gnet.DisconnectReason) erro
 	return r0
 }
 
-// executeSignedBlock provides a mock function with given fields: b
-func (_m *mockDaemoner) executeSignedBlock(b coin.SignedBlock) error {
-	ret := _m.Called(b)
+// executeSignedBlocks provides a mock function with given fields: blocks
+func (_m *mockDaemoner) executeSignedBlocks(blocks []coin.SignedBlock) (int, error) {
+	ret := _m.Called(blocks)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(coin.SignedBlock) error); ok {
-		r0 = rf(b)
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]coin.SignedBlock) int); ok {
+		r0 = rf(blocks)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]coin.SignedBlock) error); ok {
+		r1 = rf(blocks)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // filterKnownUnconfirmed provides a mock function with given fields: txns
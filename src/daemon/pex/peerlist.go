@@ -172,8 +172,15 @@ func canTry(p Peer) bool {
 	return p.CanTry()
 }
 
-// isExchangeable filters exchangeable peers
-var isExchangeable = []Filter{hasIncomingPort}
+func isOnion(p Peer) bool {
+	return IsOnionAddress(p.Addr)
+}
+
+// isExchangeable filters exchangeable peers. Onion peers are not exchanged,
+// the peers of the GivePeersMessage are IPv4 addresses.
+var isExchangeable = []Filter{hasIncomingPort, func(p Peer) bool {
+	return !isOnion(p)
+}}
 
 // removePeer removes peer
 func (pl *peerlist) removePeer(addr string) {
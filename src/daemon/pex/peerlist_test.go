@@ -23,6 +23,8 @@ var testPeers = []string{
 
 var wrongPortPeer = "112.32.32.14:1"
 
+var testOnionPeer = "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion:6000"
+
 /* Peer tests */
 
 func TestNewPeer(t *testing.T) {
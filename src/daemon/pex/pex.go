@@ -63,8 +63,19 @@ var (
 	rnum = rand.New(rand.NewSource(time.Now().Unix()))
 	// For removing inadvertent whitespace from addresses
 	whitespaceFilter = regexp.MustCompile(`\s`)
+	// Host of a Tor v3 onion service, the base32 encoding of its public key, checksum and version
+	onionHostRegexp = regexp.MustCompile(`^[a-z2-7]{56}\.onion$`)
 )
 
+// IsOnionAddress returns true if the host of a host:port address is a Tor v3 onion service
+func IsOnionAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	return onionHostRegexp.MatchString(host)
+}
+
 // validateAddress returns a sanitized address if valid, otherwise an error
 func validateAddress(ipPort string, allowLocalhost bool) (string, error) {
 	ipPort = whitespaceFilter.ReplaceAllString(ipPort, "")
@@ -73,15 +84,18 @@ func validateAddress(ipPort string, allowLocalhost bool) (string, error) {
 		return "", ErrInvalidAddress
 	}
 
-	ip := net.ParseIP(pts[0])
-	if ip == nil {
-		return "", ErrInvalidAddress
-	} else if ip.IsLoopback() {
-		if !allowLocalhost {
-			return "", ErrNoLocalhost
+	// The onion services of Tor are reached through a proxy, not by IP
+	if !onionHostRegexp.MatchString(pts[0]) {
+		ip := net.ParseIP(pts[0])
+		if ip == nil {
+			return "", ErrInvalidAddress
+		} else if ip.IsLoopback() {
+			if !allowLocalhost {
+				return "", ErrNoLocalhost
+			}
+		} else if !ip.IsGlobalUnicast() {
+			return "", ErrNotExternalIP
 		}
-	} else if !ip.IsGlobalUnicast() {
-		return "", ErrNotExternalIP
 	}
 
 	port, err := strconv.ParseUint(pts[1], 10, 16)
@@ -188,6 +202,8 @@ type Config struct {
 	CustomPeersFile string
 	// Default "trusted" connections
 	DefaultConnections []string
+	// Only the onion peers are returned by Trusted and Random, for nodes connecting through Tor
+	OnionOnly bool
 }
 
 // NewConfig creates default pex config.
@@ -581,16 +597,24 @@ func (px *Pex) AllTrusted() Peers {
 func (px *Pex) Trusted() Peers {
 	px.RLock()
 	defer px.RUnlock()
-	return px.peerlist.getCanTryPeers([]Filter{isTrusted})
+	return px.peerlist.getCanTryPeers(px.connectableFilters(isTrusted))
 }
 
 // Random returns N random untrusted peers
 func (px *Pex) Random(n int) Peers {
 	px.RLock()
 	defer px.RUnlock()
-	return px.peerlist.random(n, []Filter{func(p Peer) bool {
+	return px.peerlist.random(n, px.connectableFilters(func(p Peer) bool {
 		return !p.Trusted
-	}})
+	}))
+}
+
+// connectableFilters appends the onion filter to flts if only onion peers can be connected to
+func (px *Pex) connectableFilters(flts ...Filter) []Filter {
+	if px.Config.OnionOnly {
+		flts = append(flts, isOnion)
+	}
+	return flts
 }
 
 // RandomExchangeable returns N random exchangeable peers
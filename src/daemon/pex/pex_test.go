@@ -140,6 +140,20 @@ func TestValidateAddress(t *testing.T) {
 			allowLocalhost: false,
 			cleanAddr:      "11.22.33.44:8080",
 		},
+		{
+			addr:           testOnionPeer,
+			allowLocalhost: false,
+		},
+		{
+			addr:           "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion:80",
+			allowLocalhost: false,
+			err:            ErrPortTooLow,
+		},
+		{
+			addr:           "facebookcorewwwi.onion:6000",
+			allowLocalhost: false,
+			err:            ErrInvalidAddress,
+		},
 	}
 
 	for _, tc := range cases {
@@ -700,6 +714,39 @@ func TestPexTrusted(t *testing.T) {
 	}
 }
 
+func TestIsOnionAddress(t *testing.T) {
+	require.True(t, IsOnionAddress(testOnionPeer))
+	require.False(t, IsOnionAddress("vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion"))
+	require.False(t, IsOnionAddress("VWW6YBAL4BD7SZMGNCYRUUCPGFKQAHZDDI37KTCEO3AH7NGMCOPNPYYD.onion:6000"))
+	require.False(t, IsOnionAddress("facebookcorewwwi.onion:6000"))
+	require.False(t, IsOnionAddress(testPeers[0]))
+}
+
+func TestPexOnionOnly(t *testing.T) {
+	pex := &Pex{
+		peerlist: newPeerlist(),
+	}
+
+	pex.peerlist.setPeers([]Peer{
+		Peer{Addr: testPeers[0], Trusted: true, HasIncomingPort: true},
+		Peer{Addr: testPeers[1], HasIncomingPort: true},
+		Peer{Addr: testOnionPeer, Trusted: true, HasIncomingPort: true},
+	})
+
+	require.Len(t, pex.Trusted(), 2)
+	require.Len(t, pex.Random(0), 1)
+
+	// Onion peers are not exchanged
+	require.Len(t, pex.RandomExchangeable(0), 2)
+
+	pex.Config.OnionOnly = true
+
+	peers := pex.Trusted()
+	require.Len(t, peers, 1)
+	require.Equal(t, testOnionPeer, peers[0].Addr)
+	require.Empty(t, pex.Random(0))
+}
+
 func TestPexRandomExchangeable(t *testing.T) {
 	tt := []struct {
 		name        string
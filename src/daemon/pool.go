@@ -37,8 +37,9 @@ type PoolConfig struct {
 	// Maximum length of outgoing messages in bytes
 	MaxOutgoingMessageLength int
 	// These should be assigned by the controlling daemon
-	address string
-	port    int
+	address     string
+	port        int
+	socks5Proxy string
 }
 
 // NewPoolConfig creates pool config
@@ -72,6 +73,7 @@ type Pool struct {
 func NewPool(cfg PoolConfig, d *Daemon) (*Pool, error) {
 	gnetCfg := gnet.NewConfig()
 	gnetCfg.DialTimeout = cfg.DialTimeout
+	gnetCfg.SOCKS5Proxy = cfg.socks5Proxy
 	gnetCfg.Port = uint16(cfg.port)
 	gnetCfg.Address = cfg.address
 	gnetCfg.ConnectCallback = d.onGnetConnect
@@ -0,0 +1,268 @@
+package daemon
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/util/timeutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// TxnStatusUnknown the transaction is not known by the node
+	TxnStatusUnknown = "unknown"
+	// TxnStatusPending the transaction is in the unconfirmed pool
+	TxnStatusPending = "pending"
+	// TxnStatusConfirmed the transaction is in a block
+	TxnStatusConfirmed = "confirmed"
+	// TxnStatusRejected the transaction was rejected by the node when it was injected,
+	// or is in the unconfirmed pool but is not valid against the blockchain
+	TxnStatusRejected = "rejected"
+
+	// maxTrackedTxns is the maximum number of transactions whose broadcasts, or rejections, are tracked.
+	// When it is reached, the transaction tracked the longest ago is forgotten.
+	maxTrackedTxns = 10000
+)
+
+var (
+	// ErrTxnNotPending is returned when rebroadcasting a transaction that is not in the unconfirmed pool
+	ErrTxnNotPending = errors.New("transaction is not in the unconfirmed pool")
+	// ErrTxnNotValid is returned when rebroadcasting a transaction of the unconfirmed pool that is not valid
+	ErrTxnNotValid = errors.New("transaction is not valid against the blockchain")
+)
+
+// TransactionStatus is the lifecycle status of a transaction
+type TransactionStatus struct {
+	Txid cipher.SHA256
+	// Status is TxnStatusUnknown, TxnStatusPending, TxnStatusConfirmed or TxnStatusRejected
+	Status string
+	// ReceivedAt is the time the transaction was last received by the unconfirmed pool
+	ReceivedAt time.Time
+	// Peers is the number of peers the transaction was sent or announced to since the node started
+	Peers int
+	// LastSentAt is the last time the transaction was sent or announced to a peer
+	LastSentAt time.Time
+	// BlockSeq is the seq of the block of a confirmed transaction
+	BlockSeq uint64
+	// Confirmations is the number of blocks from the head to the block of a confirmed transaction, at least 1
+	Confirmations uint64
+	// Reason is why a rejected transaction was rejected
+	Reason string
+	// RejectedAt is the time a rejected transaction was rejected, zero if it is rejected by the unconfirmed pool
+	RejectedAt time.Time
+}
+
+// txnSends records the peers that a transaction was sent or announced to
+type txnSends struct {
+	peers map[string]struct{}
+	// count is the number of times the transaction was sent or announced, to the same peer or not
+	count int
+	last  time.Time
+}
+
+// txnRejection records why a transaction was rejected when it was injected
+type txnRejection struct {
+	reason string
+	at     time.Time
+}
+
+// txnTracker tracks the peers that the transactions were sent or announced to,
+// the transactions that were rejected when they were injected and the transactions
+// evicted from the unconfirmed pool
+type txnTracker struct {
+	sync.Mutex
+	sends      map[cipher.SHA256]*txnSends
+	rejections map[cipher.SHA256]txnRejection
+	// evictions are ordered by eviction time, the oldest first
+	evictions []MempoolEviction
+	now       func() time.Time
+}
+
+func newTxnTracker() *txnTracker {
+	return &txnTracker{
+		sends:      make(map[cipher.SHA256]*txnSends),
+		rejections: make(map[cipher.SHA256]txnRejection),
+		now:        time.Now,
+	}
+}
+
+// addSent records that the transactions were sent or announced to a peer
+func (t *txnTracker) addSent(txids []cipher.SHA256, addr string) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := t.now()
+	for _, txid := range txids {
+		s, ok := t.sends[txid]
+		if !ok {
+			if len(t.sends) >= maxTrackedTxns {
+				t.evictOldestSends()
+			}
+
+			s = &txnSends{
+				peers: make(map[string]struct{}),
+			}
+			t.sends[txid] = s
+		}
+
+		s.peers[addr] = struct{}{}
+		s.count++
+		s.last = now
+
+		// The transaction was accepted since
+		delete(t.rejections, txid)
+	}
+}
+
+// addRejected records that a transaction was rejected when it was injected
+func (t *txnTracker) addRejected(txid cipher.SHA256, reason string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.rejections[txid]; !ok && len(t.rejections) >= maxTrackedTxns {
+		t.evictOldestRejection()
+	}
+
+	t.rejections[txid] = txnRejection{
+		reason: reason,
+		at:     t.now(),
+	}
+}
+
+// get returns the number of peers a transaction was sent or announced to, the last time it was sent,
+// and its rejection if it was rejected
+func (t *txnTracker) get(txid cipher.SHA256) (int, time.Time, *txnRejection) {
+	t.Lock()
+	defer t.Unlock()
+
+	var peers int
+	var last time.Time
+	if s, ok := t.sends[txid]; ok {
+		peers = len(s.peers)
+		last = s.last
+	}
+
+	var rejection *txnRejection
+	if r, ok := t.rejections[txid]; ok {
+		rejection = &r
+	}
+
+	return peers, last, rejection
+}
+
+func (t *txnTracker) evictOldestSends() {
+	var oldest cipher.SHA256
+	var oldestTime time.Time
+	for txid, s := range t.sends {
+		if oldestTime.IsZero() || s.last.Before(oldestTime) {
+			oldest = txid
+			oldestTime = s.last
+		}
+	}
+	delete(t.sends, oldest)
+}
+
+func (t *txnTracker) evictOldestRejection() {
+	var oldest cipher.SHA256
+	var oldestTime time.Time
+	for txid, r := range t.rejections {
+		if oldestTime.IsZero() || r.at.Before(oldestTime) {
+			oldest = txid
+			oldestTime = r.at
+		}
+	}
+	delete(t.rejections, oldest)
+}
+
+// GetTransactionStatus returns the lifecycle status of a transaction.
+// The peers a transaction was sent to and the rejections are tracked since the node started.
+func (dm *Daemon) GetTransactionStatus(txid cipher.SHA256) (*TransactionStatus, error) {
+	peers, lastSent, rejection := dm.txnTracker.get(txid)
+
+	status := &TransactionStatus{
+		Txid:       txid,
+		Status:     TxnStatusUnknown,
+		Peers:      peers,
+		LastSentAt: lastSent,
+	}
+
+	utxn, err := dm.visor.GetUnconfirmedTxn(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	if utxn != nil {
+		status.ReceivedAt = timeutil.NanoToTime(utxn.Received)
+
+		if utxn.IsValid == 1 {
+			status.Status = TxnStatusPending
+			return status, nil
+		}
+
+		status.Status = TxnStatusRejected
+		status.Reason = ErrTxnNotValid.Error()
+		if _, _, err := dm.visor.VerifyTxnVerbose(&utxn.Transaction, visor.TxnSigned); err != nil {
+			status.Reason = err.Error()
+		}
+		return status, nil
+	}
+
+	txn, err := dm.visor.GetTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	if txn != nil && txn.Status.Confirmed {
+		status.Status = TxnStatusConfirmed
+		status.BlockSeq = txn.Status.BlockSeq
+		status.Confirmations = txn.Status.Height
+		return status, nil
+	}
+
+	if rejection != nil {
+		status.Status = TxnStatusRejected
+		status.Reason = rejection.reason
+		status.RejectedAt = rejection.at
+	}
+
+	return status, nil
+}
+
+// RebroadcastTransaction broadcasts a valid transaction of the unconfirmed pool to all peers.
+// Returns the number of peers the transaction is sent to.
+func (dm *Daemon) RebroadcastTransaction(txid cipher.SHA256) (int, error) {
+	if dm.config.DisableNetworking {
+		return 0, ErrNetworkingDisabled
+	}
+
+	utxn, err := dm.visor.GetUnconfirmedTxn(txid)
+	if err != nil {
+		return 0, err
+	} else if utxn == nil {
+		return 0, ErrTxnNotPending
+	} else if utxn.IsValid != 1 {
+		return 0, ErrTxnNotValid
+	}
+
+	logger.WithField("txid", txid.Hex()).Debug("Rebroadcast transaction")
+
+	ids, err := dm.BroadcastTransaction(utxn.Transaction)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+// recordRejectedTransaction records a user transaction that was rejected for violating a constraint
+func (dm *Daemon) recordRejectedTransaction(txn coin.Transaction, err error) {
+	switch err.(type) {
+	case visor.ErrTxnViolatesHardConstraint,
+		visor.ErrTxnViolatesSoftConstraint,
+		visor.ErrTxnViolatesUserConstraint:
+		dm.txnTracker.addRejected(txn.Hash(), err.Error())
+	}
+}
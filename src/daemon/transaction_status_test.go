@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestTxnTracker(t *testing.T) {
+	tracker := newTxnTracker()
+
+	now := time.Unix(1539849621, 0)
+	tracker.now = func() time.Time {
+		return now
+	}
+
+	txid1 := testutil.RandSHA256(t)
+	txid2 := testutil.RandSHA256(t)
+
+	peers, last, rejection := tracker.get(txid1)
+	require.Equal(t, 0, peers)
+	require.True(t, last.IsZero())
+	require.Nil(t, rejection)
+
+	tracker.addSent([]cipher.SHA256{txid1, txid2}, "1.2.3.4:6000")
+
+	// Sending to the same peer again does not count it twice
+	now = now.Add(time.Second)
+	tracker.addSent([]cipher.SHA256{txid1}, "1.2.3.4:6000")
+	tracker.addSent([]cipher.SHA256{txid1}, "5.6.7.8:6000")
+
+	peers, last, rejection = tracker.get(txid1)
+	require.Equal(t, 2, peers)
+	require.Equal(t, now, last)
+	require.Nil(t, rejection)
+
+	peers, last, _ = tracker.get(txid2)
+	require.Equal(t, 1, peers)
+	require.Equal(t, now.Add(-time.Second), last)
+
+	txid3 := testutil.RandSHA256(t)
+	tracker.addRejected(txid3, "Transaction violates hard constraint: Insufficient coins")
+
+	peers, _, rejection = tracker.get(txid3)
+	require.Equal(t, 0, peers)
+	require.Equal(t, &txnRejection{
+		reason: "Transaction violates hard constraint: Insufficient coins",
+		at:     now,
+	}, rejection)
+
+	// A rejected transaction that is sent afterwards is no longer rejected
+	tracker.addSent([]cipher.SHA256{txid3}, "1.2.3.4:6000")
+	peers, _, rejection = tracker.get(txid3)
+	require.Equal(t, 1, peers)
+	require.Nil(t, rejection)
+}
+
+func TestTxnTrackerEviction(t *testing.T) {
+	tracker := newTxnTracker()
+
+	now := time.Unix(1539849621, 0)
+	tracker.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	txids := make([]cipher.SHA256, maxTrackedTxns+1)
+	for i := range txids {
+		txids[i] = testutil.RandSHA256(t)
+		tracker.addSent(txids[i:i+1], "1.2.3.4:6000")
+		tracker.addRejected(txids[i], "rejected")
+	}
+
+	require.Len(t, tracker.sends, maxTrackedTxns)
+	require.Len(t, tracker.rejections, maxTrackedTxns)
+
+	// The transaction tracked the longest ago is forgotten
+	peers, _, rejection := tracker.get(txids[0])
+	require.Equal(t, 0, peers)
+	require.Nil(t, rejection)
+
+	peers, _, rejection = tracker.get(txids[maxTrackedTxns])
+	require.Equal(t, 1, peers)
+	require.NotNil(t, rejection)
+}
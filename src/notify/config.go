@@ -0,0 +1,44 @@
+package notify
+
+import "time"
+
+// Config is a configuration for the webhook dispatcher
+type Config struct {
+	// WebhooksFile is the file where the registered webhooks are saved
+	WebhooksFile string
+	// WatchListFile is the file where the watched addresses of the node are saved
+	WatchListFile string
+	// EnableWebhookAPI enables the registration of webhooks and watched addresses
+	EnableWebhookAPI bool
+	// AllowPrivateWebhooks allows the webhook URLs of loopback, link-local and private network addresses
+	AllowPrivateWebhooks bool
+	// MaxAttempts is the number of times a delivery is attempted before it fails
+	MaxAttempts int
+	// RetryInterval is the wait before the first retry of a delivery, doubled after each attempt
+	RetryInterval time.Duration
+	// MaxRetryInterval caps the wait between the retries of a delivery
+	MaxRetryInterval time.Duration
+	// Timeout is the timeout of the POST request of a delivery attempt
+	Timeout time.Duration
+	// Workers is the number of deliveries sent in parallel
+	Workers int
+	// QueueSize is the number of deliveries that can wait to be sent
+	QueueSize int
+	// MaxDeliveryLogs is the number of recent deliveries kept for each webhook
+	MaxDeliveryLogs int
+}
+
+// NewConfig creates a default config
+func NewConfig() Config {
+	return Config{
+		WebhooksFile:     "./webhooks.json",
+		WatchListFile:    "./watchlist.json",
+		MaxAttempts:      5,
+		RetryInterval:    5 * time.Second,
+		MaxRetryInterval: 5 * time.Minute,
+		Timeout:          10 * time.Second,
+		Workers:          4,
+		QueueSize:        1000,
+		MaxDeliveryLogs:  100,
+	}
+}
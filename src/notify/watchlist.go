@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/file"
+)
+
+// The watch list holds the addresses watched by the node without a wallet,
+// for example the deposit addresses of a merchant. Their transactions are sent
+// to the webhooks registered with WatchList, and can be streamed over the websocket API.
+
+// AddWatchedAddresses adds addresses to the watch list and returns the watched addresses.
+// Addresses that are already watched are ignored.
+func (d *Dispatcher) AddWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	var added []cipher.Address
+	for _, a := range addrs {
+		if _, ok := d.watched[a]; ok {
+			continue
+		}
+		d.watched[a] = struct{}{}
+		added = append(added, a)
+	}
+
+	if len(added) == 0 {
+		return d.sortedWatchedAddresses(), nil
+	}
+
+	if err := d.saveWatchList(); err != nil {
+		for _, a := range added {
+			delete(d.watched, a)
+		}
+		return nil, err
+	}
+
+	return d.sortedWatchedAddresses(), nil
+}
+
+// RemoveWatchedAddresses removes addresses from the watch list and returns the watched addresses.
+// Addresses that are not watched are ignored.
+func (d *Dispatcher) RemoveWatchedAddresses(addrs []cipher.Address) ([]cipher.Address, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	var removed []cipher.Address
+	for _, a := range addrs {
+		if _, ok := d.watched[a]; !ok {
+			continue
+		}
+		delete(d.watched, a)
+		removed = append(removed, a)
+	}
+
+	if len(removed) == 0 {
+		return d.sortedWatchedAddresses(), nil
+	}
+
+	if err := d.saveWatchList(); err != nil {
+		for _, a := range removed {
+			d.watched[a] = struct{}{}
+		}
+		return nil, err
+	}
+
+	return d.sortedWatchedAddresses(), nil
+}
+
+// GetWatchedAddresses returns the addresses of the watch list
+func (d *Dispatcher) GetWatchedAddresses() ([]cipher.Address, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	return d.sortedWatchedAddresses(), nil
+}
+
+// IsWatchedAddress returns true if the address is in the watch list.
+// It returns false if the webhook API is disabled.
+func (d *Dispatcher) IsWatchedAddress(addr cipher.Address) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	_, ok := d.watched[addr]
+	return ok
+}
+
+// sortedWatchedAddresses returns the watched addresses, sorted. Must be called under lock.
+func (d *Dispatcher) sortedWatchedAddresses() []cipher.Address {
+	addrs := make([]cipher.Address, 0, len(d.watched))
+	for a := range d.watched {
+		addrs = append(addrs, a)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+
+	return addrs
+}
+
+// saveWatchList writes the watched addresses to the watch list file. Must be called under lock.
+func (d *Dispatcher) saveWatchList() error {
+	addrs := d.sortedWatchedAddresses()
+	ss := make([]string, len(addrs))
+	for i, a := range addrs {
+		ss[i] = a.String()
+	}
+
+	if err := file.SaveJSON(d.config.WatchListFile, ss, 0600); err != nil {
+		return fmt.Errorf("failed to save watch list file %s: %v", d.config.WatchListFile, err)
+	}
+
+	return nil
+}
+
+// loadWatchList loads the watched addresses of Config.WatchListFile, if it exists
+func (d *Dispatcher) loadWatchList() error {
+	exists, err := file.Exists(d.config.WatchListFile)
+	if err != nil {
+		return fmt.Errorf("NewDispatcher file.Exists failed: %v", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	var addrs []string
+	if err := file.LoadJSON(d.config.WatchListFile, &addrs); err != nil {
+		return fmt.Errorf("failed to load watch list file %s: %v", d.config.WatchListFile, err)
+	}
+
+	for _, a := range addrs {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			return fmt.Errorf("invalid address %q in %s: %v", a, d.config.WatchListFile, err)
+		}
+		d.watched[addr] = struct{}{}
+	}
+
+	logger.Infof("Loaded %d watched addresses", len(d.watched))
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func sortAddresses(addrs []cipher.Address) []cipher.Address {
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].String() < addrs[j].String()
+	})
+	return addrs
+}
+
+func TestWatchList(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+
+	a1 := testutil.MakeAddress()
+	a2 := testutil.MakeAddress()
+	a3 := testutil.MakeAddress()
+
+	addrs, err := d.GetWatchedAddresses()
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+
+	addrs, err = d.AddWatchedAddresses([]cipher.Address{a1, a2, a1})
+	require.NoError(t, err)
+	require.Equal(t, sortAddresses([]cipher.Address{a1, a2}), addrs)
+	require.True(t, d.IsWatchedAddress(a1))
+	require.False(t, d.IsWatchedAddress(a3))
+
+	// Adding watched addresses again is a no-op
+	addrs, err = d.AddWatchedAddresses([]cipher.Address{a2, a3})
+	require.NoError(t, err)
+	require.Equal(t, sortAddresses([]cipher.Address{a1, a2, a3}), addrs)
+
+	// Removing addresses that are not watched is a no-op
+	addrs, err = d.RemoveWatchedAddresses([]cipher.Address{a2, testutil.MakeAddress()})
+	require.NoError(t, err)
+	require.Equal(t, sortAddresses([]cipher.Address{a1, a3}), addrs)
+	require.False(t, d.IsWatchedAddress(a2))
+
+	// The watch list is reloaded from the file
+	d2, err := NewDispatcher(d.config)
+	require.NoError(t, err)
+	addrs, err = d2.GetWatchedAddresses()
+	require.NoError(t, err)
+	require.Equal(t, sortAddresses([]cipher.Address{a1, a3}), addrs)
+}
+
+func TestWatchListAPIDisabled(t *testing.T) {
+	d, err := NewDispatcher(NewConfig())
+	require.NoError(t, err)
+
+	addr := testutil.MakeAddress()
+
+	_, err = d.AddWatchedAddresses([]cipher.Address{addr})
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	_, err = d.RemoveWatchedAddresses([]cipher.Address{addr})
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	_, err = d.GetWatchedAddresses()
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	require.False(t, d.IsWatchedAddress(addr))
+}
+
+func TestDispatcherWatchList(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+
+	srv := &webhookServer{
+		t:        t,
+		received: make(chan struct{}, 10),
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	_, err := d.AddWebhook(Webhook{
+		URL: ts.URL,
+	})
+	require.Equal(t, ErrWebhookNoEvents, err)
+
+	w, err := d.AddWebhook(Webhook{
+		URL:       ts.URL,
+		WatchList: true,
+	})
+	require.NoError(t, err)
+	srv.secret = w.Secret
+
+	addr := testutil.MakeAddress()
+	makeTxn := func() coin.Transaction {
+		return coin.Transaction{
+			In: []cipher.SHA256{testutil.RandSHA256(t)},
+			Out: []coin.TransactionOutput{
+				{
+					Address: addr,
+					Coins:   1e6,
+					Hours:   1,
+				},
+			},
+		}
+	}
+
+	// The events are handled before the dispatcher runs, so that the
+	// watch list changes are not racing with them
+
+	// The address is not watched yet
+	d.handleEvent(makeBlockEvent(10, makeTxn()))
+
+	_, err = d.AddWatchedAddresses([]cipher.Address{addr})
+	require.NoError(t, err)
+
+	txn := makeTxn()
+	d.handleEvent(makeBlockEvent(11, txn))
+
+	events := make(chan visor.Event)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, d.Run(events))
+	}()
+	defer func() {
+		d.Shutdown()
+		<-done
+	}()
+
+	payloads := srv.wait(t, 1)
+	require.Len(t, payloads, 1)
+	require.Equal(t, EventFundsReceived, payloads[0].Type)
+	require.Equal(t, txn.Hash().Hex(), payloads[0].Transaction)
+	require.Equal(t, uint64(11), payloads[0].BlockSeq)
+	require.Equal(t, addr.String(), payloads[0].Outputs[0].Address)
+
+	deliveries := waitDeliveries(t, d, w.ID, 1)
+	require.Equal(t, DeliveryDelivered, deliveries[0].Status)
+}
@@ -0,0 +1,757 @@
+/*
+Package notify sends the blockchain events of watched addresses to webhooks
+*/
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/util/iputil"
+	"github.com/skycoin/skycoin/src/util/logging"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// Types of the payloads sent to the webhooks
+const (
+	// EventBlock a block was added to the blockchain
+	EventBlock = "block"
+	// EventFundsReceived a transaction with outputs to watched addresses was confirmed in a block
+	EventFundsReceived = "funds_received"
+	// EventConfirmations a transaction with outputs to watched addresses reached the confirmations of the webhook
+	EventConfirmations = "confirmations"
+)
+
+// Statuses of a delivery
+const (
+	// DeliveryPending the delivery is queued or waiting for a retry
+	DeliveryPending = "pending"
+	// DeliveryDelivered the webhook URL responded with a 2xx status
+	DeliveryDelivered = "delivered"
+	// DeliveryFailed all the attempts of the delivery failed
+	DeliveryFailed = "failed"
+)
+
+// Headers of the requests sent to the webhooks
+const (
+	// SignatureHeader is the HMAC-SHA256 of the request body keyed with the webhook secret, as "sha256=<hex>"
+	SignatureHeader = "X-Skycoin-Signature"
+	// EventHeader is the type of the payload
+	EventHeader = "X-Skycoin-Event"
+	// DeliveryHeader is the ID of the delivery, which is the same for all of its attempts
+	DeliveryHeader = "X-Skycoin-Delivery"
+)
+
+var (
+	// ErrWebhookAPIDisabled is returned while trying to register webhooks while
+	// the EnableWebhookAPI option is false
+	ErrWebhookAPIDisabled = NewError(errors.New("Webhook API is disabled"))
+	// ErrWebhookNotFound is returned if no webhook with the ID is registered
+	ErrWebhookNotFound = NewError(errors.New("Webhook not found"))
+	// ErrWebhookNoEvents is returned when registering a webhook that watches no addresses nor blocks
+	ErrWebhookNoEvents = NewError(errors.New("Webhook must watch addresses, blocks or the watch list"))
+
+	// errPrivateAddress is returned when dialing a webhook address that is private,
+	// unless the AllowPrivateWebhooks option is true
+	errPrivateAddress = errors.New("webhook address is a loopback, link-local or private network address")
+
+	logger = logging.MustGetLogger("notify")
+)
+
+// Webhook is a URL that is sent the transactions of watched addresses and the new blocks
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Secret is the key of the HMAC-SHA256 signature of the payloads
+	Secret string `json:"secret,omitempty"`
+	// Addresses are the watched addresses
+	Addresses []string `json:"addresses"`
+	// Confirmations is the number of confirmations of a transaction to the watched addresses
+	// at which an EventConfirmations payload is sent, if greater than 1
+	Confirmations uint64 `json:"confirmations"`
+	// Blocks enables an EventBlock payload for every new block
+	Blocks bool `json:"blocks"`
+	// WatchList adds the addresses of the watch list of the node to the watched addresses
+	WatchList bool  `json:"watch_list"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	// ID is the delivery ID
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	WebhookID string `json:"webhook_id"`
+	Time      int64  `json:"time"`
+	// Block is the header of the block of an EventBlock payload
+	Block *readable.BlockHeader `json:"block,omitempty"`
+	// Transaction, BlockSeq, Confirmations and Outputs describe the transaction
+	// of EventFundsReceived and EventConfirmations payloads
+	Transaction   string          `json:"transaction,omitempty"`
+	BlockSeq      uint64          `json:"block_seq,omitempty"`
+	Confirmations uint64          `json:"confirmations,omitempty"`
+	Outputs       []PayloadOutput `json:"outputs,omitempty"`
+}
+
+// PayloadOutput is an output of a transaction to a watched address
+type PayloadOutput struct {
+	Hash    string `json:"hash"`
+	Address string `json:"address"`
+	Coins   string `json:"coins"`
+	Hours   uint64 `json:"hours"`
+}
+
+// Delivery is the log of a payload sent to a webhook
+type Delivery struct {
+	ID         string  `json:"id"`
+	WebhookID  string  `json:"webhook_id"`
+	Type       string  `json:"type"`
+	Status     string  `json:"status"`
+	Attempts   int     `json:"attempts"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	CreatedAt  int64   `json:"created_at"`
+	UpdatedAt  int64   `json:"updated_at"`
+	Payload    Payload `json:"payload"`
+}
+
+type webhook struct {
+	Webhook
+	addrs map[cipher.Address]struct{}
+}
+
+func newWebhook(w Webhook) (*webhook, error) {
+	addrs := make(map[cipher.Address]struct{}, len(w.Addresses))
+	for _, a := range w.Addresses {
+		addr, err := cipher.DecodeBase58Address(a)
+		if err != nil {
+			return nil, NewError(fmt.Errorf("address %q is invalid: %v", a, err))
+		}
+		addrs[addr] = struct{}{}
+	}
+
+	return &webhook{
+		Webhook: w,
+		addrs:   addrs,
+	}, nil
+}
+
+// pendingConfirmation is an EventFundsReceived payload waiting for the confirmations of its webhook
+type pendingConfirmation struct {
+	payload Payload
+	seq     uint64
+}
+
+// Dispatcher POSTs signed JSON payloads to the registered webhooks when the watched
+// addresses receive funds, when their transactions reach the confirmations of the
+// webhook, and for every new block. Failed deliveries are retried with an exponential backoff.
+// The webhooks are saved to Config.WebhooksFile and the watch list of the node to Config.WatchListFile.
+// The delivery logs and the transactions waiting for confirmations are kept in memory only.
+type Dispatcher struct {
+	config Config
+	client *http.Client
+	queue  chan *Delivery
+	quit   chan struct{}
+	wg     sync.WaitGroup
+
+	sync.Mutex
+	webhooks   map[string]*webhook
+	watched    map[cipher.Address]struct{}
+	pending    []pendingConfirmation
+	deliveries map[string][]*Delivery
+}
+
+// NewDispatcher creates a Dispatcher and loads the webhooks of Config.WebhooksFile
+// and the watched addresses of Config.WatchListFile
+func NewDispatcher(c Config) (*Dispatcher, error) {
+	d := &Dispatcher{
+		config:     c,
+		client:     newWebhookClient(c),
+		queue:      make(chan *Delivery, c.QueueSize),
+		quit:       make(chan struct{}),
+		webhooks:   make(map[string]*webhook),
+		watched:    make(map[cipher.Address]struct{}),
+		deliveries: make(map[string][]*Delivery),
+	}
+
+	if !c.EnableWebhookAPI {
+		logger.Info("Webhook API is disabled")
+		return d, nil
+	}
+
+	if err := d.loadWebhooks(); err != nil {
+		return nil, err
+	}
+
+	if err := d.loadWatchList(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// newWebhookClient creates the client of the deliveries. Unless Config.AllowPrivateWebhooks is true,
+// the client refuses to connect to private addresses, which are checked after the host of the
+// URL or of a redirect is resolved, so that a public host name can't resolve to a private address.
+// Proxies are not used, since the address of the proxy would be checked instead.
+func newWebhookClient(c Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: c.Timeout,
+	}
+	if !c.AllowPrivateWebhooks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || iputil.IsPrivate(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: c.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: c.Timeout,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// checkWebhookHost returns an error if the host is a private address or resolves to one.
+// A host that does not resolve is accepted, the deliveries check the address they connect to.
+func (d *Dispatcher) checkWebhookHost(rawURL, host string) error {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+		defer cancel()
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			logger.WithError(err).WithField("host", host).Debug("Webhook host lookup failed")
+			return nil
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if iputil.IsPrivate(ip) {
+			return NewError(fmt.Errorf("webhook url %q is a loopback, link-local or private network address", rawURL))
+		}
+	}
+
+	return nil
+}
+
+// loadWebhooks loads the webhooks of Config.WebhooksFile, if it exists
+func (d *Dispatcher) loadWebhooks() error {
+	exists, err := file.Exists(d.config.WebhooksFile)
+	if err != nil {
+		return fmt.Errorf("NewDispatcher file.Exists failed: %v", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	var webhooks []Webhook
+	if err := file.LoadJSON(d.config.WebhooksFile, &webhooks); err != nil {
+		return fmt.Errorf("failed to load webhooks file %s: %v", d.config.WebhooksFile, err)
+	}
+
+	for _, w := range webhooks {
+		wh, err := newWebhook(w)
+		if err != nil {
+			return fmt.Errorf("invalid webhook %s in %s: %v", w.ID, d.config.WebhooksFile, err)
+		}
+		d.webhooks[w.ID] = wh
+	}
+
+	logger.Infof("Loaded %d webhooks", len(d.webhooks))
+
+	return nil
+}
+
+// Run dispatches the blockchain events until Shutdown is called
+func (d *Dispatcher) Run(events <-chan visor.Event) error {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.deliver()
+		}()
+	}
+
+	defer d.wg.Wait()
+
+	for {
+		select {
+		case <-d.quit:
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			d.handleEvent(e)
+		}
+	}
+}
+
+// Shutdown stops the Dispatcher. Pending deliveries are not sent.
+func (d *Dispatcher) Shutdown() {
+	close(d.quit)
+}
+
+// AddWebhook registers a webhook. The ID and secret of the webhook are generated.
+// If Confirmations is 0, it is set to 1. Unless Config.AllowPrivateWebhooks is true,
+// the URL must not be a private address nor a host that resolves to one.
+func (d *Dispatcher) AddWebhook(w Webhook) (*Webhook, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, NewError(fmt.Errorf("webhook url %q is invalid, must be an absolute http or https url", w.URL))
+	}
+
+	if !d.config.AllowPrivateWebhooks {
+		if err := d.checkWebhookHost(w.URL, u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(w.Addresses) == 0 && !w.Blocks && !w.WatchList {
+		return nil, ErrWebhookNoEvents
+	}
+
+	if w.Confirmations == 0 {
+		w.Confirmations = 1
+	}
+
+	w.ID = hex.EncodeToString(cipher.RandByte(16))
+	w.Secret = hex.EncodeToString(cipher.RandByte(32))
+	w.CreatedAt = time.Now().UTC().Unix()
+
+	wh, err := newWebhook(w)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduplicate the addresses
+	wh.Addresses = wh.Addresses[:0:0]
+	for a := range wh.addrs {
+		wh.Addresses = append(wh.Addresses, a.String())
+	}
+	sort.Strings(wh.Addresses)
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.webhooks[wh.ID] = wh
+	if err := d.save(); err != nil {
+		delete(d.webhooks, wh.ID)
+		return nil, err
+	}
+
+	w = wh.Webhook
+	return &w, nil
+}
+
+// RemoveWebhook removes a webhook and its delivery logs
+func (d *Dispatcher) RemoveWebhook(id string) error {
+	if !d.config.EnableWebhookAPI {
+		return ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	wh, ok := d.webhooks[id]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+
+	delete(d.webhooks, id)
+	if err := d.save(); err != nil {
+		d.webhooks[id] = wh
+		return err
+	}
+
+	delete(d.deliveries, id)
+
+	return nil
+}
+
+// GetWebhooks returns the registered webhooks, ordered by creation time
+func (d *Dispatcher) GetWebhooks() ([]Webhook, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	webhooks := d.sortedWebhooks()
+	ws := make([]Webhook, len(webhooks))
+	for i, wh := range webhooks {
+		ws[i] = wh.Webhook
+	}
+
+	return ws, nil
+}
+
+// GetWebhookDeliveries returns the recent deliveries of a webhook, newest first
+func (d *Dispatcher) GetWebhookDeliveries(id string) ([]Delivery, error) {
+	if !d.config.EnableWebhookAPI {
+		return nil, ErrWebhookAPIDisabled
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	if _, ok := d.webhooks[id]; !ok {
+		return nil, ErrWebhookNotFound
+	}
+
+	logs := d.deliveries[id]
+	deliveries := make([]Delivery, len(logs))
+	for i, dl := range logs {
+		deliveries[len(logs)-1-i] = *dl
+	}
+
+	return deliveries, nil
+}
+
+// sortedWebhooks returns the webhooks ordered by creation time. Must be called under lock.
+func (d *Dispatcher) sortedWebhooks() []*webhook {
+	webhooks := make([]*webhook, 0, len(d.webhooks))
+	for _, wh := range d.webhooks {
+		webhooks = append(webhooks, wh)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		if webhooks[i].CreatedAt != webhooks[j].CreatedAt {
+			return webhooks[i].CreatedAt < webhooks[j].CreatedAt
+		}
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return webhooks
+}
+
+// save writes the webhooks to the webhooks file. Must be called under lock.
+func (d *Dispatcher) save() error {
+	webhooks := d.sortedWebhooks()
+	ws := make([]Webhook, len(webhooks))
+	for i, wh := range webhooks {
+		ws[i] = wh.Webhook
+	}
+
+	if err := file.SaveJSON(d.config.WebhooksFile, ws, 0600); err != nil {
+		return fmt.Errorf("failed to save webhooks file %s: %v", d.config.WebhooksFile, err)
+	}
+
+	return nil
+}
+
+// handleEvent creates the deliveries of a blockchain event and queues them
+func (d *Dispatcher) handleEvent(e visor.Event) {
+	if e.Type != visor.EventBlockExecuted {
+		return
+	}
+
+	b := e.Block.Block
+	var payloads []Payload
+
+	d.Lock()
+
+	for _, wh := range d.sortedWebhooks() {
+		if wh.Blocks {
+			h := readable.NewBlockHeader(b.Head)
+			payloads = append(payloads, Payload{
+				Type:      EventBlock,
+				WebhookID: wh.ID,
+				Block:     &h,
+			})
+		}
+
+		if len(wh.addrs) == 0 && !wh.WatchList {
+			continue
+		}
+
+		for _, txn := range b.Body.Transactions {
+			outputs, err := d.watchedOutputs(wh, b.Head, txn)
+			if err != nil {
+				logger.WithError(err).WithField("txid", txn.Hash().Hex()).Error("watchedOutputs failed")
+				continue
+			}
+			if len(outputs) == 0 {
+				continue
+			}
+
+			p := Payload{
+				Type:          EventFundsReceived,
+				WebhookID:     wh.ID,
+				Transaction:   txn.Hash().Hex(),
+				BlockSeq:      b.Head.BkSeq,
+				Confirmations: 1,
+				Outputs:       outputs,
+			}
+			payloads = append(payloads, p)
+
+			if wh.Confirmations > 1 {
+				d.pending = append(d.pending, pendingConfirmation{
+					payload: p,
+					seq:     b.Head.BkSeq + wh.Confirmations - 1,
+				})
+			}
+		}
+	}
+
+	pending := d.pending[:0]
+	for _, pc := range d.pending {
+		if _, ok := d.webhooks[pc.payload.WebhookID]; !ok {
+			continue
+		}
+
+		if b.Head.BkSeq < pc.seq {
+			pending = append(pending, pc)
+			continue
+		}
+
+		p := pc.payload
+		p.Type = EventConfirmations
+		p.Confirmations = b.Head.BkSeq - p.BlockSeq + 1
+		payloads = append(payloads, p)
+	}
+	d.pending = pending
+
+	now := time.Now().UTC().Unix()
+	deliveries := make([]*Delivery, len(payloads))
+	for i, p := range payloads {
+		p.ID = hex.EncodeToString(cipher.RandByte(16))
+		p.Time = now
+
+		dl := &Delivery{
+			ID:        p.ID,
+			WebhookID: p.WebhookID,
+			Type:      p.Type,
+			Status:    DeliveryPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Payload:   p,
+		}
+		d.logDelivery(dl)
+		deliveries[i] = dl
+	}
+
+	d.Unlock()
+
+	for _, dl := range deliveries {
+		select {
+		case d.queue <- dl:
+		default:
+			logger.WithField("webhookID", dl.WebhookID).Warning("Webhook delivery queue is full, dropping delivery")
+			d.updateDelivery(dl, DeliveryFailed, 0, errors.New("delivery queue is full"))
+		}
+	}
+}
+
+// watchedOutputs returns the outputs of the transaction to the watched addresses of the webhook,
+// including the watch list of the node if the webhook watches it. Must be called under lock.
+func (d *Dispatcher) watchedOutputs(wh *webhook, bh coin.BlockHeader, txn coin.Transaction) ([]PayloadOutput, error) {
+	var outputs []PayloadOutput
+	for i, o := range txn.Out {
+		_, ok := wh.addrs[o.Address]
+		if !ok && wh.WatchList {
+			_, ok = d.watched[o.Address]
+		}
+		if !ok {
+			continue
+		}
+
+		ux, err := coin.CreateUnspent(bh, txn, i)
+		if err != nil {
+			return nil, err
+		}
+
+		coins, err := droplet.ToString(o.Coins)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, PayloadOutput{
+			Hash:    ux.Hash().Hex(),
+			Address: o.Address.String(),
+			Coins:   coins,
+			Hours:   o.Hours,
+		})
+	}
+
+	return outputs, nil
+}
+
+// logDelivery adds the delivery to the logs of its webhook. Must be called under lock.
+func (d *Dispatcher) logDelivery(dl *Delivery) {
+	logs := append(d.deliveries[dl.WebhookID], dl)
+	if len(logs) > d.config.MaxDeliveryLogs {
+		logs = logs[len(logs)-d.config.MaxDeliveryLogs:]
+	}
+	d.deliveries[dl.WebhookID] = logs
+}
+
+func (d *Dispatcher) updateDelivery(dl *Delivery, status string, statusCode int, err error) {
+	d.Lock()
+	defer d.Unlock()
+
+	dl.Status = status
+	dl.StatusCode = statusCode
+	dl.Error = ""
+	if err != nil {
+		dl.Error = err.Error()
+	}
+	dl.UpdatedAt = time.Now().UTC().Unix()
+}
+
+// deliver sends the queued deliveries until Shutdown is called
+func (d *Dispatcher) deliver() {
+	for {
+		select {
+		case <-d.quit:
+			return
+		case dl := <-d.queue:
+			d.attempt(dl)
+		}
+	}
+}
+
+// attempt sends a delivery and schedules a retry if it fails
+func (d *Dispatcher) attempt(dl *Delivery) {
+	d.Lock()
+	wh, ok := d.webhooks[dl.WebhookID]
+	var u, secret string
+	if ok {
+		u = wh.URL
+		secret = wh.Secret
+	}
+	dl.Attempts++
+	attempts := dl.Attempts
+	d.Unlock()
+
+	if !ok {
+		d.updateDelivery(dl, DeliveryFailed, 0, ErrWebhookNotFound)
+		return
+	}
+
+	statusCode, err := d.post(u, secret, dl.Payload)
+	if err == nil {
+		d.updateDelivery(dl, DeliveryDelivered, statusCode, nil)
+		return
+	}
+
+	logger.WithError(err).WithFields(logrus.Fields{
+		"webhookID":  dl.WebhookID,
+		"deliveryID": dl.ID,
+		"attempts":   attempts,
+	}).Warning("Webhook delivery failed")
+
+	if attempts >= d.config.MaxAttempts {
+		d.updateDelivery(dl, DeliveryFailed, statusCode, err)
+		return
+	}
+
+	d.updateDelivery(dl, DeliveryPending, statusCode, err)
+
+	time.AfterFunc(d.retryInterval(attempts), func() {
+		select {
+		case d.queue <- dl:
+		case <-d.quit:
+		}
+	})
+}
+
+// retryInterval returns the wait before the retry of a delivery that failed the given number of attempts
+func (d *Dispatcher) retryInterval(attempts int) time.Duration {
+	interval := d.config.RetryInterval
+	for i := 1; i < attempts && interval < d.config.MaxRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > d.config.MaxRetryInterval {
+		interval = d.config.MaxRetryInterval
+	}
+
+	return interval
+}
+
+// post sends the signed payload to the webhook URL
+func (d *Dispatcher) post(u, secret string, p Payload) (int, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, p.Type)
+	req.Header.Set(DeliveryHeader, p.ID)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		logger.WithError(err).Debug("Failed to read webhook response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the SignatureHeader value of a request body, "sha256=" followed by
+// the hex HMAC-SHA256 of the body keyed with the webhook secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) //nolint:errcheck
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
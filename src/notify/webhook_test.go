@@ -0,0 +1,510 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func newTestDispatcher(t *testing.T) (*Dispatcher, func()) {
+	dir, err := ioutil.TempDir("", "notify")
+	require.NoError(t, err)
+	teardown := func() {
+		os.RemoveAll(dir) //nolint:errcheck
+	}
+
+	c := NewConfig()
+	c.WebhooksFile = filepath.Join(dir, "webhooks.json")
+	c.WatchListFile = filepath.Join(dir, "watchlist.json")
+	c.EnableWebhookAPI = true
+	// The deliveries are sent to httptest servers on the loopback address
+	c.AllowPrivateWebhooks = true
+	c.RetryInterval = 10 * time.Millisecond
+	c.MaxRetryInterval = 20 * time.Millisecond
+
+	d, err := NewDispatcher(c)
+	require.NoError(t, err)
+
+	return d, teardown
+}
+
+func TestAddWebhook(t *testing.T) {
+	addr := testutil.MakeAddress()
+
+	tt := []struct {
+		name    string
+		webhook Webhook
+		err     error
+	}{
+		{
+			name: "invalid url",
+			webhook: Webhook{
+				URL:    "foo",
+				Blocks: true,
+			},
+			err: NewError(errors.New("webhook url \"foo\" is invalid, must be an absolute http or https url")),
+		},
+		{
+			name: "invalid scheme",
+			webhook: Webhook{
+				URL:    "ftp://example.com",
+				Blocks: true,
+			},
+			err: NewError(errors.New("webhook url \"ftp://example.com\" is invalid, must be an absolute http or https url")),
+		},
+		{
+			name: "no events",
+			webhook: Webhook{
+				URL: "http://example.com",
+			},
+			err: ErrWebhookNoEvents,
+		},
+		{
+			name: "invalid address",
+			webhook: Webhook{
+				URL:       "http://example.com",
+				Addresses: []string{"foo"},
+			},
+			err: NewError(errors.New("address \"foo\" is invalid: Invalid address length")),
+		},
+		{
+			name: "ok",
+			webhook: Webhook{
+				URL:       "https://example.com/hook",
+				Addresses: []string{addr.String(), addr.String()},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d, teardown := newTestDispatcher(t)
+			defer teardown()
+
+			w, err := d.AddWebhook(tc.webhook)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, w.ID, 32)
+			require.Len(t, w.Secret, 64)
+			require.Equal(t, []string{addr.String()}, w.Addresses)
+			require.Equal(t, uint64(1), w.Confirmations)
+			require.NotZero(t, w.CreatedAt)
+
+			ws, err := d.GetWebhooks()
+			require.NoError(t, err)
+			require.Equal(t, []Webhook{*w}, ws)
+
+			// The webhooks are reloaded from the file
+			d2, err := NewDispatcher(d.config)
+			require.NoError(t, err)
+			ws, err = d2.GetWebhooks()
+			require.NoError(t, err)
+			require.Equal(t, []Webhook{*w}, ws)
+
+			err = d2.RemoveWebhook(w.ID)
+			require.NoError(t, err)
+			err = d2.RemoveWebhook(w.ID)
+			require.Equal(t, ErrWebhookNotFound, err)
+
+			d3, err := NewDispatcher(d.config)
+			require.NoError(t, err)
+			ws, err = d3.GetWebhooks()
+			require.NoError(t, err)
+			require.Empty(t, ws)
+		})
+	}
+}
+
+func TestPrivateWebhooks(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+
+	c := d.config
+	c.AllowPrivateWebhooks = false
+	d, err := NewDispatcher(c)
+	require.NoError(t, err)
+
+	for _, u := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fd00::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://localhost:8080/hook",
+	} {
+		_, err := d.AddWebhook(Webhook{
+			URL:    u,
+			Blocks: true,
+		})
+		require.Equal(t, NewError(fmt.Errorf("webhook url %q is a loopback, link-local or private network address", u)), err)
+	}
+
+	w, err := d.AddWebhook(Webhook{
+		URL:    "https://8.8.8.8/hook",
+		Blocks: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://8.8.8.8/hook", w.URL)
+
+	// The deliveries do not connect to private addresses, e.g. a host that resolved
+	// to a public address when the webhook was registered
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	_, err = d.client.Post(ts.URL, "application/json", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errPrivateAddress.Error())
+	require.Equal(t, 0, requests)
+
+	// The private addresses are allowed with AllowPrivateWebhooks
+	c.AllowPrivateWebhooks = true
+	d, err = NewDispatcher(c)
+	require.NoError(t, err)
+
+	_, err = d.AddWebhook(Webhook{
+		URL:    ts.URL,
+		Blocks: true,
+	})
+	require.NoError(t, err)
+
+	resp, err := d.client.Post(ts.URL, "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, requests)
+}
+
+func TestWebhookAPIDisabled(t *testing.T) {
+	d, err := NewDispatcher(NewConfig())
+	require.NoError(t, err)
+
+	_, err = d.AddWebhook(Webhook{
+		URL:    "http://example.com",
+		Blocks: true,
+	})
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	_, err = d.GetWebhooks()
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	_, err = d.GetWebhookDeliveries("foo")
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+
+	err = d.RemoveWebhook("foo")
+	require.Equal(t, ErrWebhookAPIDisabled, err)
+}
+
+// webhookServer records the payloads it receives, after failing the first `fail` requests
+type webhookServer struct {
+	sync.Mutex
+	t        *testing.T
+	secret   string
+	fail     int
+	payloads []Payload
+	received chan struct{}
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(s.t, err)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.fail > 0 {
+		s.fail--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	require.Equal(s.t, Sign(s.secret, body), r.Header.Get(SignatureHeader))
+
+	var p Payload
+	require.NoError(s.t, json.Unmarshal(body, &p))
+	require.Equal(s.t, p.Type, r.Header.Get(EventHeader))
+	require.Equal(s.t, p.ID, r.Header.Get(DeliveryHeader))
+
+	s.payloads = append(s.payloads, p)
+	s.received <- struct{}{}
+}
+
+func (s *webhookServer) wait(t *testing.T, n int) []Payload {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for payload %d", i)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	return s.payloads
+}
+
+// waitDeliveries waits until the webhook has n deliveries that are no longer pending
+func waitDeliveries(t *testing.T, d *Dispatcher, id string, n int) []Delivery {
+	var deliveries []Delivery
+	for i := 0; i < 250; i++ {
+		var err error
+		deliveries, err = d.GetWebhookDeliveries(id)
+		require.NoError(t, err)
+
+		done := len(deliveries) == n
+		for _, dl := range deliveries {
+			if dl.Status == DeliveryPending {
+				done = false
+			}
+		}
+		if done {
+			return deliveries
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	require.Len(t, deliveries, n)
+	t.Fatal("timeout waiting for the deliveries")
+	return nil
+}
+
+func makeBlockEvent(seq uint64, txns ...coin.Transaction) visor.Event {
+	return visor.Event{
+		Type: visor.EventBlockExecuted,
+		Block: &coin.SignedBlock{
+			Block: coin.Block{
+				Head: coin.BlockHeader{
+					BkSeq: seq,
+					Time:  uint64(1e9 + seq),
+				},
+				Body: coin.BlockBody{
+					Transactions: txns,
+				},
+			},
+		},
+	}
+}
+
+func TestDispatcherDeliveries(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+
+	srv := &webhookServer{
+		t:        t,
+		fail:     1,
+		received: make(chan struct{}, 10),
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	addr := testutil.MakeAddress()
+	w, err := d.AddWebhook(Webhook{
+		URL:           ts.URL,
+		Addresses:     []string{addr.String()},
+		Confirmations: 3,
+	})
+	require.NoError(t, err)
+	srv.secret = w.Secret
+
+	events := make(chan visor.Event, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, d.Run(events))
+	}()
+	defer func() {
+		d.Shutdown()
+		<-done
+	}()
+
+	txn := coin.Transaction{
+		In: []cipher.SHA256{testutil.RandSHA256(t)},
+		Out: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   1e6,
+				Hours:   1,
+			},
+			{
+				Address: addr,
+				Coins:   2e6,
+				Hours:   2,
+			},
+		},
+	}
+
+	events <- makeBlockEvent(10, txn)
+
+	// The first attempt fails and is retried
+	payloads := srv.wait(t, 1)
+	require.Len(t, payloads, 1)
+	p := payloads[0]
+	require.Equal(t, EventFundsReceived, p.Type)
+	require.Equal(t, w.ID, p.WebhookID)
+	require.Equal(t, txn.Hash().Hex(), p.Transaction)
+	require.Equal(t, uint64(10), p.BlockSeq)
+	require.Equal(t, uint64(1), p.Confirmations)
+
+	ux, err := coin.CreateUnspent(coin.BlockHeader{BkSeq: 10, Time: 1e9 + 10}, txn, 1)
+	require.NoError(t, err)
+	require.Equal(t, []PayloadOutput{
+		{
+			Hash:    ux.Hash().Hex(),
+			Address: addr.String(),
+			Coins:   "2.000000",
+			Hours:   2,
+		},
+	}, p.Outputs)
+
+	events <- makeBlockEvent(11)
+	events <- makeBlockEvent(12)
+
+	payloads = srv.wait(t, 1)
+	require.Len(t, payloads, 2)
+	p2 := payloads[1]
+	require.Equal(t, EventConfirmations, p2.Type)
+	require.Equal(t, uint64(3), p2.Confirmations)
+	require.Equal(t, p.Transaction, p2.Transaction)
+	require.Equal(t, p.Outputs, p2.Outputs)
+	require.NotEqual(t, p.ID, p2.ID)
+
+	// The deliveries are updated after the response is received
+	deliveries := waitDeliveries(t, d, w.ID, 2)
+	require.Equal(t, p2.ID, deliveries[0].ID)
+	require.Equal(t, DeliveryDelivered, deliveries[0].Status)
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, p.ID, deliveries[1].ID)
+	require.Equal(t, DeliveryDelivered, deliveries[1].Status)
+	require.Equal(t, 2, deliveries[1].Attempts)
+	require.Equal(t, http.StatusOK, deliveries[1].StatusCode)
+	require.Empty(t, deliveries[1].Error)
+
+	_, err = d.GetWebhookDeliveries("foo")
+	require.Equal(t, ErrWebhookNotFound, err)
+}
+
+func TestDispatcherBlocks(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+
+	srv := &webhookServer{
+		t:        t,
+		received: make(chan struct{}, 10),
+	}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	w, err := d.AddWebhook(Webhook{
+		URL:    ts.URL,
+		Blocks: true,
+	})
+	require.NoError(t, err)
+	srv.secret = w.Secret
+
+	events := make(chan visor.Event, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, d.Run(events))
+	}()
+	defer func() {
+		d.Shutdown()
+		<-done
+	}()
+
+	events <- makeBlockEvent(5)
+	events <- visor.Event{
+		Type:        visor.EventUnconfirmedTxnInjected,
+		Transaction: &coin.Transaction{},
+	}
+
+	payloads := srv.wait(t, 1)
+	require.Len(t, payloads, 1)
+	require.Equal(t, EventBlock, payloads[0].Type)
+	require.NotNil(t, payloads[0].Block)
+	require.Equal(t, uint64(5), payloads[0].Block.BkSeq)
+}
+
+func TestDispatcherFailedDelivery(t *testing.T) {
+	d, teardown := newTestDispatcher(t)
+	defer teardown()
+	d.config.MaxAttempts = 3
+
+	var mx sync.Mutex
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	w, err := d.AddWebhook(Webhook{
+		URL:    ts.URL,
+		Blocks: true,
+	})
+	require.NoError(t, err)
+
+	events := make(chan visor.Event, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, d.Run(events))
+	}()
+	defer func() {
+		d.Shutdown()
+		<-done
+	}()
+
+	events <- makeBlockEvent(1)
+
+	deliveries := waitDeliveries(t, d, w.ID, 1)
+	require.Equal(t, DeliveryFailed, deliveries[0].Status)
+	require.Equal(t, 3, deliveries[0].Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	require.Equal(t, "webhook responded with status 503", deliveries[0].Error)
+
+	mx.Lock()
+	defer mx.Unlock()
+	require.Equal(t, 3, attempts)
+}
+
+func TestRetryInterval(t *testing.T) {
+	c := NewConfig()
+	c.RetryInterval = time.Second
+	c.MaxRetryInterval = 5 * time.Second
+	d := &Dispatcher{
+		config: c,
+	}
+
+	require.Equal(t, time.Second, d.retryInterval(1))
+	require.Equal(t, 2*time.Second, d.retryInterval(2))
+	require.Equal(t, 4*time.Second, d.retryInterval(3))
+	require.Equal(t, 5*time.Second, d.retryInterval(4))
+	require.Equal(t, 5*time.Second, d.retryInterval(100))
+}
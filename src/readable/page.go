@@ -5,4 +5,6 @@ type PageInfo struct {
 	TotalPages  uint64 `json:"total_pages"`
 	PageSize    uint64 `json:"page_size"`
 	CurrentPage uint64 `json:"current_page"`
+	Total       uint64 `json:"total"`
+	NextCursor  string `json:"next_cursor,omitempty"`
 }
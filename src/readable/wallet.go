@@ -48,10 +48,13 @@ func NewAddressBalances(wab wallet.AddressBalances) AddressBalances {
 
 // WalletEntry the wallet entry struct
 type WalletEntry struct {
-	Address     string  `json:"address"`
-	Public      string  `json:"public_key"`
-	ChildNumber *uint32 `json:"child_number,omitempty"` // For bip32/44
-	Change      *uint32 `json:"change,omitempty"`       // For bip44
+	Address     string            `json:"address"`
+	Public      string            `json:"public_key"`
+	ChildNumber *uint32           `json:"child_number,omitempty"` // For bip32/44
+	Change      *uint32           `json:"change,omitempty"`       // For bip44
+	Label       string            `json:"label,omitempty"`
+	Note        string            `json:"note,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
 }
 
 // WalletMeta the wallet meta struct
@@ -66,4 +69,11 @@ type WalletMeta struct {
 	Encrypted  bool              `json:"encrypted"`
 	Bip44Coin  *bip44.CoinType   `json:"bip44_coin,omitempty"` // For bip44
 	XPub       string            `json:"xpub,omitempty"`       // For xpub
+	// XPubAccount is whether the xpub is a bip44 account key with external and change chains
+	XPubAccount bool `json:"xpub_account,omitempty"` // For xpub
+	// Device and DerivationPath are the device type and the bip32 path of the xpub of hardware wallets
+	Device         string `json:"device,omitempty"`          // For hardware
+	DerivationPath string `json:"derivation_path,omitempty"` // For hardware
+	// UnlockedUntil is the expiry in unix seconds of the unlock session of an encrypted wallet
+	UnlockedUntil int64 `json:"unlocked_until,omitempty"`
 }
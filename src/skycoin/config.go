@@ -68,14 +68,24 @@ type NodeConfig struct {
 	DisabledAPISets string
 	// Enable all of API sets. Applies before disabling individual sets
 	EnableAllAPISets bool
+	// Enable the admin API, which manages the node at runtime. It requires the web interface auth.
+	EnableAdminAPI bool
 
 	enabledAPISets map[string]struct{}
 	// Comma separate list of hostnames to accept in the Host header, used to bypass the Host header check which only applies to localhost addresses
 	HostWhitelist string
 	hostWhitelist []string
+	// CORS rules allowing browser origins to access API sets with methods, in the format
+	// <origin>=<API set>[+<API set>...][:<method>[+<method>...]], separated by commas
+	CORSRules string
+	corsRules []api.CORSRule
 
 	// Only run on localhost and only connect to others on localhost
 	LocalhostOnly bool
+	// Address of a SOCKS5 proxy, e.g. a Tor client, to make the outgoing peer connections through
+	SOCKS5Proxy string
+	// Only connect to onion peers through the SOCKS5 proxy
+	TorOnly bool
 	// Which address to serve on. Leave blank to automatically assign to a
 	// public interface
 	Address string
@@ -116,6 +126,24 @@ type NodeConfig struct {
 	WebInterfacePassword string
 	// Allow web interface auth without HTTPS
 	WebInterfacePlaintextAuth bool
+	// Bearer tokens of the web interface and their scopes, in the format <token>:<scope>[+<scope>...], separated by commas
+	APITokens string
+	apiTokens []api.APIToken
+	// Rate limits of the web interface requests of each client, in the format
+	// <class>=<requests per second>[:<burst>], separated by commas
+	RateLimits string
+	rateLimits []api.RateLimit
+	// Timeouts of the web interface requests, in the format <endpoint>=<duration>, separated by commas.
+	// The endpoint "default" applies to the endpoints without their own timeout.
+	RequestTimeouts string
+	requestTimeouts []api.RequestTimeout
+	// Resolver of the receiver names of the send endpoints, "dns" for DNS TXT records
+	// or the http(s) URL of a name service. Names are not accepted if empty.
+	AddressResolver string
+	addressResolver api.AddressResolver
+	// gRPC interface address, the gRPC interface is disabled if empty.
+	// It uses the API sets and the username and password of the web interface.
+	GRPCAddr string
 
 	// Launch System Default Browser after client startup
 	LaunchBrowser bool
@@ -172,6 +200,15 @@ type NodeConfig struct {
 	KVStorageDirectory  string
 	EnabledStorageTypes []kvstorage.Type
 
+	// Webhooks file
+	// Default to ${DataDirectory}/webhooks.json
+	WebhooksFile string
+	// Watch list file
+	// Default to ${DataDirectory}/watchlist.json
+	WatchListFile string
+	// Allow the webhooks of loopback, link-local and private network addresses
+	WebhookAllowPrivate bool
+
 	// Disable the hardcoded default peers
 	DisableDefaultPeers bool
 	// Load custom peers from disk
@@ -242,6 +279,10 @@ func NewNodeConfig(mode string, node fiber.NodeConfig) NodeConfig {
 		DisableCSP: false,
 		// Only run on localhost and only connect to others on localhost
 		LocalhostOnly: false,
+		// Address of a SOCKS5 proxy to make the outgoing peer connections through
+		SOCKS5Proxy: "",
+		// Only connect to onion peers through the SOCKS5 proxy
+		TorOnly: false,
 		// Which address to serve on. Leave blank to automatically assign to a
 		// public interface
 		Address: "",
@@ -415,6 +456,17 @@ func (c *Config) postProcess() error {
 	} else {
 		c.Node.KVStorageDirectory = replaceHome(c.Node.KVStorageDirectory, home)
 	}
+	if c.Node.WebhooksFile == "" {
+		c.Node.WebhooksFile = filepath.Join(c.Node.DataDirectory, "webhooks.json")
+	} else {
+		c.Node.WebhooksFile = replaceHome(c.Node.WebhooksFile, home)
+	}
+	if c.Node.WatchListFile == "" {
+		c.Node.WatchListFile = filepath.Join(c.Node.DataDirectory, "watchlist.json")
+	} else {
+		c.Node.WatchListFile = replaceHome(c.Node.WatchListFile, home)
+	}
+
 	if len(c.Node.EnabledStorageTypes) == 0 {
 		c.Node.EnabledStorageTypes = []kvstorage.Type{
 			kvstorage.TypeGeneral,
@@ -467,11 +519,40 @@ func (c *Config) postProcess() error {
 		c.Node.hostWhitelist = strings.Split(c.Node.HostWhitelist, ",")
 	}
 
-	httpAuthEnabled := c.Node.WebInterfaceUsername != "" || c.Node.WebInterfacePassword != ""
+	c.Node.corsRules, err = api.ParseCORSRules(c.Node.CORSRules)
+	if err != nil {
+		return fmt.Errorf("Invalid -cors-rules: %v", err)
+	}
+
+	c.Node.apiTokens, err = api.ParseAPITokens(c.Node.APITokens)
+	if err != nil {
+		return fmt.Errorf("Invalid -api-tokens: %v", err)
+	}
+
+	c.Node.rateLimits, err = api.ParseRateLimits(c.Node.RateLimits)
+	if err != nil {
+		return fmt.Errorf("Invalid -rate-limits: %v", err)
+	}
+
+	c.Node.requestTimeouts, err = api.ParseRequestTimeouts(c.Node.RequestTimeouts)
+	if err != nil {
+		return fmt.Errorf("Invalid -request-timeouts: %v", err)
+	}
+
+	c.Node.addressResolver, err = api.NewAddressResolver(c.Node.AddressResolver)
+	if err != nil {
+		return fmt.Errorf("Invalid -address-resolver: %v", err)
+	}
+
+	httpAuthEnabled := c.Node.WebInterfaceUsername != "" || c.Node.WebInterfacePassword != "" || len(c.Node.apiTokens) != 0
 	if httpAuthEnabled && !c.Node.WebInterfaceHTTPS && !c.Node.WebInterfacePlaintextAuth {
 		return errors.New("Web interface auth enabled but HTTPS is not enabled. Use -web-interface-plaintext-auth=true if this is desired")
 	}
 
+	if c.Node.EnableAdminAPI && !adminAuthEnabled(c.Node) {
+		return errors.New("-enable-admin-api requires -web-interface-username and -web-interface-password, or -api-tokens with the admin scope")
+	}
+
 	if c.Node.MaxConnections < c.Node.MaxOutgoingConnections+c.Node.MaxIncomingConnections {
 		return errors.New("-max-connections must be >= -max-outgoing-connections + -max-incoming-connections")
 	}
@@ -593,6 +674,8 @@ func buildAPISets(c NodeConfig) (map[string]struct{}, error) {
 		api.EndpointsTransaction,
 		api.EndpointsNetCtrl,
 		api.EndpointsStorage,
+		api.EndpointsWebhook,
+		api.EndpointsGraphQL,
 		// Do not include insecure or deprecated API sets, they must always
 		// be explicitly enabled through -enable-api-sets
 	}
@@ -613,9 +696,32 @@ func buildAPISets(c NodeConfig) (map[string]struct{}, error) {
 		delete(apiSets, k)
 	}
 
+	// The admin API set is only enabled by -enable-admin-api
+	if c.EnableAdminAPI {
+		apiSets[api.EndpointsAdmin] = struct{}{}
+	}
+
 	return apiSets, nil
 }
 
+// adminAuthEnabled returns true if the admin API can only be accessed with a username and password,
+// or with a token with the admin scope
+func adminAuthEnabled(c NodeConfig) bool {
+	if c.WebInterfaceUsername != "" && c.WebInterfacePassword != "" {
+		return true
+	}
+
+	for _, t := range c.apiTokens {
+		for _, s := range t.Scopes {
+			if s == api.ScopeAdmin {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func validateAPISets(opt string, apiSets []string) error {
 	for _, k := range apiSets {
 		k = strings.ToUpper(strings.TrimSpace(k))
@@ -626,7 +732,9 @@ func validateAPISets(opt string, apiSets []string) error {
 			api.EndpointsWallet,
 			api.EndpointsInsecureWalletSeed,
 			api.EndpointsNetCtrl,
-			api.EndpointsStorage:
+			api.EndpointsStorage,
+			api.EndpointsWebhook,
+			api.EndpointsGraphQL:
 		case "":
 			continue
 		default:
@@ -659,6 +767,7 @@ func (c *NodeConfig) RegisterFlags() {
 	flag.StringVar(&c.WebInterfaceKey, "web-interface-key", c.WebInterfaceKey, "skycoind.key file for web interface HTTPS. If not provided, will autogenerate or use skycoind.key in --data-dir")
 	flag.BoolVar(&c.WebInterfaceHTTPS, "web-interface-https", c.WebInterfaceHTTPS, "enable HTTPS for web interface")
 	flag.StringVar(&c.HostWhitelist, "host-whitelist", c.HostWhitelist, "Hostnames to whitelist in the Host header check. Only applies when the web interface is bound to localhost.")
+	flag.StringVar(&c.CORSRules, "cors-rules", c.CORSRules, "CORS rules allowing browser origins to access the web interface, in the format <origin>=<API set>[+<API set>...][:<method>[+<method>...]], separated by commas. Methods default to GET")
 
 	allAPISets := []string{
 		api.EndpointsRead,
@@ -668,14 +777,22 @@ func (c *NodeConfig) RegisterFlags() {
 		api.EndpointsNetCtrl,
 		api.EndpointsInsecureWalletSeed,
 		api.EndpointsStorage,
+		api.EndpointsWebhook,
+		api.EndpointsGraphQL,
 	}
 	flag.StringVar(&c.EnabledAPISets, "enable-api-sets", c.EnabledAPISets, fmt.Sprintf("enable API set. Options are %s. Multiple values should be separated by comma", strings.Join(allAPISets, ", ")))
 	flag.StringVar(&c.DisabledAPISets, "disable-api-sets", c.DisabledAPISets, fmt.Sprintf("disable API set. Options are %s. Multiple values should be separated by comma", strings.Join(allAPISets, ", ")))
 	flag.BoolVar(&c.EnableAllAPISets, "enable-all-api-sets", c.EnableAllAPISets, "enable all API sets, except for deprecated or insecure sets. This option is applied before -disable-api-sets.")
+	flag.BoolVar(&c.EnableAdminAPI, "enable-admin-api", c.EnableAdminAPI, fmt.Sprintf("enable the %s API set, which manages the node at runtime. Requires -web-interface-username and -web-interface-password, or -api-tokens with the %s scope", api.EndpointsAdmin, api.ScopeAdmin))
 
 	flag.StringVar(&c.WebInterfaceUsername, "web-interface-username", c.WebInterfaceUsername, "username for the web interface")
 	flag.StringVar(&c.WebInterfacePassword, "web-interface-password", c.WebInterfacePassword, "password for the web interface")
 	flag.BoolVar(&c.WebInterfacePlaintextAuth, "web-interface-plaintext-auth", c.WebInterfacePlaintextAuth, "allow web interface auth without https")
+	flag.StringVar(&c.APITokens, "api-tokens", c.APITokens, fmt.Sprintf("bearer tokens of the web interface with their scopes, in the format <token>:<scope>[+<scope>...], separated by commas. Scopes are %s, %s and %s", api.ScopeRead, api.ScopeWallet, api.ScopeAdmin))
+	flag.StringVar(&c.RateLimits, "rate-limits", c.RateLimits, fmt.Sprintf("rate limits of the web interface requests of each IP address or bearer token, in the format <class>=<requests per second>[:<burst>], separated by commas. Classes are %s, %s and %s. The classes without a rate limit are not limited", api.RateLimitRead, api.RateLimitExpensive, api.RateLimitWallet))
+	flag.StringVar(&c.RequestTimeouts, "request-timeouts", c.RequestTimeouts, fmt.Sprintf("timeouts of the web interface requests, in the format <endpoint>=<duration>, separated by commas, e.g. %s=30s,/api/v1/transactions=2m. The endpoint %s applies to the endpoints without their own timeout. The requests are not limited by default", api.RequestTimeoutDefault, api.RequestTimeoutDefault))
+	flag.StringVar(&c.AddressResolver, "address-resolver", c.AddressResolver, fmt.Sprintf("resolver of the receiver names of the send endpoints, %s to resolve a domain name with its %q TXT record or the http(s) URL of a name service requested with ?name=<name>. Names are not accepted by default", api.AddressResolverDNS, api.DNSAddressRecordPrefix+"<address>"))
+	flag.StringVar(&c.GRPCAddr, "grpc-addr", c.GRPCAddr, "addr to serve the gRPC interface on, e.g. 127.0.0.1:6440. The gRPC interface is disabled if empty")
 
 	flag.BoolVar(&c.LaunchBrowser, "launch-browser", c.LaunchBrowser, "launch system default webbrowser at client startup")
 	flag.StringVar(&c.DataDirectory, "data-dir", c.DataDirectory, "directory to store app data (defaults to ~/.skycoin)")
@@ -717,6 +834,9 @@ func (c *NodeConfig) RegisterFlags() {
 
 	flag.StringVar(&c.WalletDirectory, "wallet-dir", c.WalletDirectory, "location of the wallet files. Defaults to ~/.skycoin/wallet/")
 	flag.StringVar(&c.KVStorageDirectory, "storage-dir", c.KVStorageDirectory, "location of the storage data files. Defaults to ~/.skycoin/data/")
+	flag.StringVar(&c.WebhooksFile, "webhooks-file", c.WebhooksFile, "location of the webhooks file. Defaults to ~/.skycoin/webhooks.json")
+	flag.StringVar(&c.WatchListFile, "watch-list-file", c.WatchListFile, "location of the watch list file. Defaults to ~/.skycoin/watchlist.json")
+	flag.BoolVar(&c.WebhookAllowPrivate, "webhook-allow-private", c.WebhookAllowPrivate, "Allow webhook URLs of loopback, link-local and private network addresses")
 	flag.IntVar(&c.MaxConnections, "max-connections", c.MaxConnections, "Maximum number of total connections allowed")
 	flag.IntVar(&c.MaxOutgoingConnections, "max-outgoing-connections", c.MaxOutgoingConnections, "Maximum number of outgoing connections allowed")
 	flag.IntVar(&c.MaxIncomingConnections, "max-incoming-connections", c.MaxIncomingConnections, "Maximum number of incoming connections allowd")
@@ -726,6 +846,8 @@ func (c *NodeConfig) RegisterFlags() {
 	flag.IntVar(&c.MaxOutgoingMessageLength, "max-out-msg-len", c.MaxOutgoingMessageLength, "Maximum length of outgoing wire messages")
 	flag.IntVar(&c.MaxIncomingMessageLength, "max-in-msg-len", c.MaxIncomingMessageLength, "Maximum length of incoming wire messages")
 	flag.BoolVar(&c.LocalhostOnly, "localhost-only", c.LocalhostOnly, "Run on localhost and only connect to localhost peers")
+	flag.StringVar(&c.SOCKS5Proxy, "socks5-proxy", c.SOCKS5Proxy, "Address of a SOCKS5 proxy, e.g. a Tor client at 127.0.0.1:9050, to make the outgoing peer connections through")
+	flag.BoolVar(&c.TorOnly, "tor-only", c.TorOnly, "Only connect to .onion peers through the -socks5-proxy, the peer list is not downloaded")
 	flag.StringVar(&c.WalletCryptoType, "wallet-crypto-type", c.WalletCryptoType, "wallet crypto type. Can be sha256-xor or scrypt-chacha20poly1305")
 	flag.BoolVar(&c.Version, "version", false, "show node version")
 }
@@ -19,11 +19,13 @@ import (
 	"github.com/blang/semver"
 	"github.com/toqueteos/webbrowser"
 
+	"github.com/skycoin/skycoin/src/admin"
 	"github.com/skycoin/skycoin/src/api"
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
 	"github.com/skycoin/skycoin/src/kvstorage"
+	"github.com/skycoin/skycoin/src/notify"
 	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/readable"
 	"github.com/skycoin/skycoin/src/util/apputil"
@@ -57,8 +59,11 @@ func (c *Coin) Run() error {
 	var v *visor.Visor
 	var d *daemon.Daemon
 	var s *kvstorage.Manager
+	var n *notify.Dispatcher
+	var adm *admin.Admin
 	var gw *api.Gateway
 	var webInterface *api.Server
+	var grpcInterface *api.GRPCServer
 	var retErr error
 	errC := make(chan error, 10)
 
@@ -82,10 +87,10 @@ func (c *Coin) Run() error {
 		logging.DisableColors()
 	}
 
-	var logFile *os.File
+	var lf *logFile
 	if c.config.Node.LogToFile {
 		var err error
-		logFile, err = c.initLogFile()
+		lf, err = c.initLogFile()
 		if err != nil {
 			c.logger.Error(err)
 			return err
@@ -146,6 +151,8 @@ func (c *Coin) Run() error {
 	dconf := c.ConfigureDaemon()
 	vconf := c.ConfigureVisor()
 	sconf := c.ConfigureStorage()
+	nconf := c.ConfigureNotify()
+	aconf := c.ConfigureAdmin()
 
 	// Open the database
 	c.logger.Infof("Opening database %s", c.config.Node.DBPath)
@@ -165,8 +172,8 @@ func (c *Coin) Run() error {
 
 		c.logger.Info("Goodbye")
 
-		if logFile != nil {
-			if err := logFile.Close(); err != nil {
+		if lf != nil {
+			if err := lf.Close(); err != nil {
 				fmt.Println("Failed to close log file")
 			}
 		}
@@ -201,6 +208,13 @@ func (c *Coin) Run() error {
 		return err
 	}
 
+	defer func() {
+		c.logger.Info("Unlocking wallet directory")
+		if err := w.UnlockWalletDir(); err != nil {
+			c.logger.WithError(err).Error("Failed to unlock wallet directory")
+		}
+	}()
+
 	c.logger.Info("visor.New")
 	v, err = visor.New(vconf, db, w)
 	if err != nil {
@@ -222,8 +236,23 @@ func (c *Coin) Run() error {
 		return err
 	}
 
+	c.logger.Info("notify.NewDispatcher")
+	n, err = notify.NewDispatcher(nconf)
+	if err != nil {
+		c.logger.WithError(err).Error("notify.NewDispatcher failed")
+		return err
+	}
+
+	c.logger.Info("admin.NewAdmin")
+	// A nil *logFile must not be wrapped in the admin.LogRotator interface
+	var lr admin.LogRotator
+	if lf != nil {
+		lr = lf
+	}
+	adm = admin.NewAdmin(aconf, db, lr)
+
 	c.logger.Info("api.NewGateway")
-	gw = api.NewGateway(d, v, w, s)
+	gw = api.NewGateway(d, v, w, s, n, adm)
 
 	if c.config.Node.WebInterface {
 		webInterface, err = c.createGUI(gw, host)
@@ -236,12 +265,28 @@ func (c *Coin) Run() error {
 		c.logger.Critical().Infof("Full address: %s", fullAddress)
 	}
 
+	if c.config.Node.GRPCAddr != "" {
+		grpcInterface, err = api.CreateGRPC(c.config.Node.GRPCAddr, api.GRPCConfig{
+			EnabledAPISets: c.config.Node.enabledAPISets,
+			Username:       c.config.Node.WebInterfaceUsername,
+			Password:       c.config.Node.WebInterfacePassword,
+		}, gw)
+		if err != nil {
+			c.logger.WithError(err).Error("api.CreateGRPC failed")
+			return err
+		}
+	}
+
 	c.logger.Info("visor.Init")
 	if err := v.Init(); err != nil {
 		c.logger.WithError(err).Error("visor.Init failed")
 		return err
 	}
 
+	// Subscribe before the daemon can execute blocks, so that no block is missed
+	events, unsubscribeEvents := v.SubscribeBlockchain(0)
+	defer unsubscribeEvents()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -253,6 +298,17 @@ func (c *Coin) Run() error {
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		c.logger.Info("notify.Run")
+		if err := n.Run(events); err != nil {
+			c.logger.WithError(err).Error("notify.Run failed")
+			errC <- err
+		}
+	}()
+
 	if c.config.Node.WebInterface {
 		cancelLaunchBrowser := make(chan struct{})
 
@@ -285,8 +341,22 @@ func (c *Coin) Run() error {
 		}
 	}
 
+	if grpcInterface != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c.logger.Info("grpcInterface.Serve")
+			if err := grpcInterface.Serve(); err != nil {
+				c.logger.WithError(err).Error("grpcInterface.Serve failed")
+				errC <- err
+			}
+		}()
+	}
+
 	select {
 	case <-quit:
+	case <-adm.ShutdownRequested():
 	case retErr = <-errC:
 		c.logger.WithError(err).Error("Received error from errC (something prior has failed)")
 	}
@@ -298,9 +368,20 @@ func (c *Coin) Run() error {
 		webInterface.Shutdown()
 	}
 
+	if grpcInterface != nil {
+		c.logger.Info("Closing gRPC interface")
+		grpcInterface.Shutdown()
+	}
+
 	c.logger.Info("Closing daemon")
 	d.Shutdown()
 
+	c.logger.Info("Closing webhook dispatcher")
+	n.Shutdown()
+
+	c.logger.Info("Closing admin")
+	adm.Shutdown()
+
 	c.logger.Info("Waiting for goroutines to finish")
 	wg.Wait()
 
@@ -315,29 +396,84 @@ func NewCoin(config Config, logger *logging.Logger) *Coin {
 	}
 }
 
-func (c *Coin) initLogFile() (*os.File, error) {
+func (c *Coin) initLogFile() (*logFile, error) {
 	logDir := filepath.Join(c.config.Node.DataDirectory, "logs")
 	if err := createDirIfNotExist(logDir); err != nil {
 		c.logger.WithError(err).Errorf("createDirIfNotExist(%s) failed", logDir)
 		return nil, fmt.Errorf("createDirIfNotExist(%s) failed: %v", logDir, err)
 	}
 
-	// open log file
-	tf := "2006-01-02-030405"
-	logfile := filepath.Join(logDir, fmt.Sprintf("%s-v%s.log", time.Now().Format(tf), c.config.Build.Version))
+	lf := &logFile{
+		dir:     logDir,
+		version: c.config.Build.Version,
+	}
 
-	f, err := os.OpenFile(logfile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	f, err := lf.open()
 	if err != nil {
-		c.logger.WithError(err).Errorf("os.OpenFile(%s) failed", logfile)
+		c.logger.WithError(err).Error("Open log file failed")
 		return nil, err
 	}
 
-	hook := logging.NewWriteHook(f)
-	logging.AddHook(hook)
+	lf.f = f
+	lf.hook = logging.NewWriteHook(f)
+	logging.AddHook(lf.hook)
+
+	return lf, nil
+}
+
+// logFile is the log file of the node, which can be rotated by the admin API
+type logFile struct {
+	sync.Mutex
+	dir     string
+	version string
+	f       *os.File
+	hook    *logging.WriteHook
+}
+
+// open opens a new log file named after the current time
+func (lf *logFile) open() (*os.File, error) {
+	tf := "2006-01-02-030405"
+	path := filepath.Join(lf.dir, fmt.Sprintf("%s-v%s.log", time.Now().Format(tf), lf.version))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile(%s) failed: %v", path, err)
+	}
 
 	return f, nil
 }
 
+// RotateLogFile closes the log file and continues logging to a new one.
+// Returns the path of the new log file.
+func (lf *logFile) RotateLogFile() (string, error) {
+	lf.Lock()
+	defer lf.Unlock()
+
+	f, err := lf.open()
+	if err != nil {
+		return "", err
+	}
+
+	lf.hook.SetWriter(f)
+
+	old := lf.f
+	lf.f = f
+
+	if err := old.Close(); err != nil {
+		return "", fmt.Errorf("Failed to close log file %s: %v", old.Name(), err)
+	}
+
+	return f.Name(), nil
+}
+
+// Close closes the log file
+func (lf *logFile) Close() error {
+	lf.Lock()
+	defer lf.Unlock()
+
+	return lf.f.Close()
+}
+
 // ConfigureVisor sets the visor config values
 func (c *Coin) ConfigureVisor() visor.Config {
 	vc := visor.NewConfig()
@@ -395,6 +531,28 @@ func (c *Coin) ConfigureStorage() kvstorage.Config {
 	return sc
 }
 
+// ConfigureNotify sets the webhook dispatcher config values
+func (c *Coin) ConfigureNotify() notify.Config {
+	nc := notify.NewConfig()
+
+	nc.WebhooksFile = c.config.Node.WebhooksFile
+	nc.WatchListFile = c.config.Node.WatchListFile
+	nc.AllowPrivateWebhooks = c.config.Node.WebhookAllowPrivate
+	_, nc.EnableWebhookAPI = c.config.Node.enabledAPISets[api.EndpointsWebhook]
+
+	return nc
+}
+
+// ConfigureAdmin sets the admin config values
+func (c *Coin) ConfigureAdmin() admin.Config {
+	ac := admin.NewConfig()
+
+	_, ac.EnableAdminAPI = c.config.Node.enabledAPISets[api.EndpointsAdmin]
+	ac.BlockchainPubkey = c.config.Node.blockchainPubkey
+
+	return ac
+}
+
 // ConfigureDaemon sets the daemon config values
 func (c *Coin) ConfigureDaemon() daemon.Config {
 	dc := daemon.NewConfig()
@@ -427,6 +585,8 @@ func (c *Coin) ConfigureDaemon() daemon.Config {
 	dc.Daemon.Port = c.config.Node.Port
 	dc.Daemon.Address = c.config.Node.Address
 	dc.Daemon.LocalhostOnly = c.config.Node.LocalhostOnly
+	dc.Daemon.SOCKS5Proxy = c.config.Node.SOCKS5Proxy
+	dc.Daemon.TorOnly = c.config.Node.TorOnly
 	dc.Daemon.MaxConnections = c.config.Node.MaxConnections
 	dc.Daemon.MaxOutgoingConnections = c.config.Node.MaxOutgoingConnections
 	dc.Daemon.DataDirectory = c.config.Node.DataDirectory
@@ -456,6 +616,7 @@ func (c *Coin) createGUI(gw *api.Gateway, host string) (*api.Server, error) {
 		IdleTimeout:        c.config.Node.HTTPIdleTimeout,
 		EnabledAPISets:     c.config.Node.enabledAPISets,
 		HostWhitelist:      c.config.Node.hostWhitelist,
+		CORSRules:          c.config.Node.corsRules,
 		Health: api.HealthConfig{
 			BuildInfo: readable.BuildInfo{
 				Version: c.config.Build.Version,
@@ -466,8 +627,12 @@ func (c *Coin) createGUI(gw *api.Gateway, host string) (*api.Server, error) {
 			DaemonUserAgent: c.config.Node.userAgent,
 			BlockPublisher:  c.config.Node.RunBlockPublisher,
 		},
-		Username: c.config.Node.WebInterfaceUsername,
-		Password: c.config.Node.WebInterfacePassword,
+		Username:        c.config.Node.WebInterfaceUsername,
+		Password:        c.config.Node.WebInterfacePassword,
+		Tokens:          c.config.Node.apiTokens,
+		RateLimits:      c.config.Node.rateLimits,
+		RequestTimeouts: c.config.Node.requestTimeouts,
+		AddressResolver: c.config.Node.addressResolver,
 	}
 
 	var s *api.Server
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -151,23 +150,61 @@ func SaveJSONSafe(filename string, thing interface{}, mode os.FileMode) error {
 	return f.Sync()
 }
 
-// SaveBinary persists data into given file in binary,
-// backup the data to `tmp` wallet file and then write data
-// to target wallet file. Remove the tmp file in the end of
-// this function. In this way, the wallet data would not be lost
+// SaveBinary persists data into given file in binary atomically.
+// The data is written to a `tmp` file which is synced to disk and then
+// renamed to the target file. In this way, the file either has the old
+// or the new data, even if the process crashes in the middle of the save.
 func SaveBinary(filename string, data []byte, mode os.FileMode) error {
 	// Write the new file to a temporary
 	dataHash := cipher.SumSHA256(data)
 	tmpname := filename + ".tmp." + dataHash.Hex()[:8]
-	if err := ioutil.WriteFile(tmpname, data, mode); err != nil {
+	if err := writeFileSync(tmpname, data, mode); err != nil {
+		os.Remove(tmpname) //nolint:errcheck
 		return err
 	}
 
-	if err := ioutil.WriteFile(filename, data, mode); err != nil {
+	if err := os.Rename(tmpname, filename); err != nil {
+		os.Remove(tmpname) //nolint:errcheck
 		return err
 	}
 
-	return os.Remove(tmpname)
+	return syncDir(filepath.Dir(filename))
+}
+
+// writeFileSync writes data to the file and syncs it to disk
+func writeFileSync(filename string, data []byte, mode os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+
+	return f.Close()
+}
+
+// syncDir syncs the directory so that a rename in it is persisted
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// Directories can not be opened for syncing on windows
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
 }
 
 //TODO: require file named after application and then hashcode, in static directory
@@ -265,3 +265,45 @@ func TestIsWritable(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, IsWritable(fn))
 }
+
+func TestSaveBinaryReplacesFile(t *testing.T) {
+	fn := "test.bin"
+	defer cleanup(t, fn)
+
+	b := []byte("foo")
+	require.NoError(t, ioutil.WriteFile(fn, b, 0644))
+	fi, err := os.Stat(fn)
+	require.NoError(t, err)
+
+	// The new data is written to a new file which replaces the old file,
+	// so a reader of the old file never sees partially written data
+	b2 := []byte("barbaz")
+	require.NoError(t, SaveBinary(fn, b2, 0644))
+	requireFileContentsBinary(t, fn, b2)
+	testutil.RequireFileNotExists(t, fn+".tmp."+cipher.SumSHA256(b2).Hex()[:8])
+
+	fi2, err := os.Stat(fn)
+	require.NoError(t, err)
+	require.False(t, os.SameFile(fi, fi2))
+}
+
+func TestTryLock(t *testing.T) {
+	fn := "test.lock"
+	defer cleanup(t, fn)
+
+	l, err := TryLock(fn)
+	require.NoError(t, err)
+	requireIsRegularFile(t, fn)
+
+	// The file can not be locked again while the lock is held
+	_, err = TryLock(fn)
+	require.Equal(t, ErrLocked, err)
+
+	require.NoError(t, l.Unlock())
+	// Unlocking twice is a no-op
+	require.NoError(t, l.Unlock())
+
+	l2, err := TryLock(fn)
+	require.NoError(t, err)
+	require.NoError(t, l2.Unlock())
+}
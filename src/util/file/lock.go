@@ -0,0 +1,46 @@
+package file
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is returned when the lock file is locked by another process
+var ErrLocked = errors.New("file is locked by another process")
+
+// Lock is an advisory exclusive lock of a file, taken with flock on unix
+// systems and LockFileEx on windows. The lock is released when the process
+// exits, so a crashed process does not leave a stale lock behind.
+type Lock struct {
+	f *os.File
+}
+
+// TryLock takes the exclusive lock of the file, the file is created if it does not exist.
+// Returns ErrLocked without blocking if the file is locked by another process.
+func TryLock(filename string) (*Lock, error) {
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return &Lock{f: f}, nil
+}
+
+// Unlock releases the lock, the lock file is kept
+func (l *Lock) Unlock() error {
+	if l.f == nil {
+		return nil
+	}
+
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
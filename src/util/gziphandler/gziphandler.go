@@ -3,14 +3,29 @@ package gziphandler
 // https://gist.github.com/CJEnright/bc2d8b8dc0c1389a9feeddb110f822d7
 
 import (
+	"compress/flate"
 	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 )
 
+const (
+	// EncodingGzip is the gzip content coding
+	EncodingGzip = "gzip"
+	// EncodingDeflate is the deflate content coding
+	EncodingDeflate = "deflate"
+)
+
+// compressWriter is implemented by *gzip.Writer and *flate.Writer
+type compressWriter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+}
+
 var gzPool = sync.Pool{
 	New: func() interface{} {
 		w := gzip.NewWriter(ioutil.Discard)
@@ -18,36 +33,167 @@ var gzPool = sync.Pool{
 	},
 }
 
-type gzipResponseWriter struct {
-	io.Writer
+var flatePool = sync.Pool{
+	New: func() interface{} {
+		w, err := flate.NewWriter(ioutil.Discard, flate.DefaultCompression)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	},
+}
+
+func poolFor(encoding string) *sync.Pool {
+	switch encoding {
+	case EncodingGzip:
+		return &gzPool
+	case EncodingDeflate:
+		return &flatePool
+	default:
+		panic("unsupported encoding " + encoding)
+	}
+}
+
+// compressResponseWriter compresses the response body, unless the status does not allow a body
+// or the handler already encoded it. The compression starts when the header is written.
+type compressResponseWriter struct {
 	http.ResponseWriter
+	encoding    string
+	writer      compressWriter
+	wroteHeader bool
 }
 
-func (w *gzipResponseWriter) WriteHeader(status int) {
-	w.Header().Del("Content-Length")
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if bodyAllowed(status) && w.Header().Get("Content-Encoding") == "" {
+		w.Header().Set("Content-Encoding", w.encoding)
+		w.Header().Del("Content-Length")
+
+		w.writer = poolFor(w.encoding).Get().(compressWriter)
+		w.writer.Reset(w.ResponseWriter)
+	}
+
 	w.ResponseWriter.WriteHeader(status)
 }
 
-func (w *gzipResponseWriter) Write(b []byte) (int, error) {
-	return w.Writer.Write(b)
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		// Sniff the content type of the uncompressed body, like net/http would
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.writer == nil {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.writer.Write(b)
+}
+
+// close flushes the compressed body and returns the compressor to its pool
+func (w *compressResponseWriter) close() {
+	if w.writer == nil {
+		return
+	}
+
+	w.writer.Close()
+	poolFor(w.encoding).Put(w.writer)
+	w.writer = nil
 }
 
-// New creates a gzip compression HTTP middleware
+// bodyAllowed returns true if a response with the status can have a body
+func bodyAllowed(status int) bool {
+	switch {
+	case status < http.StatusOK:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	default:
+		return true
+	}
+}
+
+// New creates a compression HTTP middleware. The response is compressed with gzip or deflate,
+// whichever is preferred by the Accept-Encoding header of the request. gzip is preferred if both are accepted equally.
 func New(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := NegotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		w.Header().Set("Content-Encoding", "gzip")
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+		}
+		defer cw.close()
 
-		gz := gzPool.Get().(*gzip.Writer)
-		defer gzPool.Put(gz)
+		next.ServeHTTP(cw, r)
+	})
+}
 
-		gz.Reset(w)
-		defer gz.Close()
+// NegotiateEncoding returns the content coding of the response, EncodingGzip or EncodingDeflate,
+// according to the quality values of an Accept-Encoding header.
+// Returns an empty string if neither is acceptable and the response should not be compressed.
+func NegotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	wildcard := -1.0
 
-		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, Writer: gz}, r)
-	})
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil {
+				// Ignore a coding with a malformed quality value
+				q = 0
+			} else {
+				q = v
+			}
+		}
+
+		if coding == "*" {
+			wildcard = q
+		} else {
+			qualities[coding] = q
+		}
+	}
+
+	quality := func(coding string) float64 {
+		if q, ok := qualities[coding]; ok {
+			return q
+		}
+		return wildcard
+	}
+
+	gzipQ := quality(EncodingGzip)
+	deflateQ := quality(EncodingDeflate)
+
+	switch {
+	case gzipQ <= 0 && deflateQ <= 0:
+		return ""
+	case gzipQ >= deflateQ:
+		return EncodingGzip
+	default:
+		return EncodingDeflate
+	}
 }
@@ -0,0 +1,58 @@
+package gziphandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"br", ""},
+		{"gzip", EncodingGzip},
+		{"GZIP", EncodingGzip},
+		{"deflate", EncodingDeflate},
+		{"gzip, deflate, br", EncodingGzip},
+		{"deflate, gzip", EncodingGzip},
+		{"gzip;q=0.5, deflate", EncodingDeflate},
+		{"gzip; q=0.8, deflate;q=0.9", EncodingDeflate},
+		{"gzip;q=0, deflate;q=0", ""},
+		{"gzip;q=0", ""},
+		{"gzip;q=foo, deflate", EncodingDeflate},
+		{"*", EncodingGzip},
+		{"*;q=0", ""},
+		{"gzip;q=0, *", EncodingDeflate},
+		{"deflate;q=0.5, *;q=0.1", EncodingDeflate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.acceptEncoding, func(t *testing.T) {
+			require.Equal(t, tc.encoding, NegotiateEncoding(tc.acceptEncoding))
+		})
+	}
+}
+
+func TestNewNoBody(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusNotModified} {
+		handler := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, status, rr.Code)
+		require.Empty(t, rr.Header().Get("Content-Encoding"))
+		require.Empty(t, rr.Body.Bytes())
+	}
+}
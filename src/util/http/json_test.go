@@ -96,7 +96,7 @@ func TestAddressUnmarshalJSON(t *testing.T) {
 		{
 			name: "invalid base58 address",
 			addr: "2blYafFtdkCRNcCyuDvsATV66GvBR9xfvjy",
-			err:  "invalid address: Invalid base58 character",
+			err:  "invalid address: Invalid base58 character, a character may be mistyped, did you mean 2bfYafFtdkCRNcCyuDvsATV66GvBR9xfvjy?",
 		},
 		{
 			name: "valid address",
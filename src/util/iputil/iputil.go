@@ -10,6 +10,10 @@ import (
 )
 
 var (
+	// privateNets are the private network ranges of IPv4 (RFC 1918), carrier-grade NAT (RFC 6598)
+	// and IPv6 unique local addresses (RFC 4193)
+	privateNets = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
 	// ErrMissingIP IP missing from ip:port string
 	ErrMissingIP = errors.New("IP missing from ip:port address")
 	// ErrInvalidPort port invalid in ip:port string
@@ -44,6 +48,36 @@ func IsLocalhost(addr string) bool {
 	return net.ParseIP(addr).IsLoopback() || addr == "localhost"
 }
 
+// IsPrivate returns true if ip is a loopback, link-local, private, carrier-grade NAT,
+// multicast or unspecified address, which can't be reached from the internet.
+// Works for both ipv4 and ipv6 addresses.
+func IsPrivate(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
 // SplitAddr splits an ip:port string to ip, port.
 // Works for both ipv4 and ipv6 addresses.
 // If the IP is not specified, returns an error.
@@ -58,6 +58,41 @@ func TestIsLocalhost(t *testing.T) {
 	}
 }
 
+func TestIsPrivate(t *testing.T) {
+	testData := []struct {
+		ip       string
+		expected bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"100.64.0.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"224.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"172.32.0.1", false},
+		{"8.8.8.8", false},
+		{"85.56.12.34", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.ip, func(t *testing.T) {
+			ip := net.ParseIP(tc.ip)
+			require.NotNil(t, ip)
+			require.Equal(t, tc.expected, IsPrivate(ip))
+		})
+	}
+}
+
 func TestSplitAddr(t *testing.T) {
 	testData := []struct {
 		input string
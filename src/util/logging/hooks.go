@@ -2,12 +2,14 @@ package logging
 
 import (
 	"io"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
 
 // WriteHook is a logrus.Hook that logs to an io.Writer
 type WriteHook struct {
+	sync.Mutex
 	w         io.Writer
 	formatter logrus.Formatter
 }
@@ -39,6 +41,18 @@ func (f *WriteHook) Fire(e *logrus.Entry) error {
 		return err
 	}
 
+	f.Lock()
+	defer f.Unlock()
+
 	_, err = f.w.Write(b)
 	return err
 }
+
+// SetWriter replaces the io.Writer of the WriteHook, e.g. to rotate a log file.
+// The entries are written to the new io.Writer once SetWriter returns.
+func (f *WriteHook) SetWriter(w io.Writer) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.w = w
+}
@@ -61,9 +61,10 @@ func (logger *MasterLogger) AddHook(hook logrus.Hook) {
 	logger.Hooks.Add(hook)
 }
 
-// SetLevel sets the log level for the logger and its module loggers
+// SetLevel sets the log level for the logger and its module loggers.
+// It is safe to call while logging.
 func (logger *MasterLogger) SetLevel(level logrus.Level) {
-	logger.Level = level
+	logger.Logger.SetLevel(level)
 }
 
 // EnableColors enables colored logging
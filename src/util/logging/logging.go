@@ -68,6 +68,11 @@ func SetLevel(level logrus.Level) {
 	log.SetLevel(level)
 }
 
+// GetLevel returns the logger's minimum log level
+func GetLevel() logrus.Level {
+	return log.GetLevel()
+}
+
 // SetOutputTo sets the logger's output to an io.Writer
 func SetOutputTo(w io.Writer) {
 	log.Out = w
@@ -0,0 +1,465 @@
+/*
+Package websocket implements the subset of the RFC6455 websocket protocol used by the API:
+the opening handshake, unfragmented writes, fragmented reads and the ping, pong and close
+control frames. Extensions and subprotocols are not supported.
+*/
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message types, the opcodes of the data frames
+const (
+	// TextMessage is a UTF-8 encoded text message
+	TextMessage = 1
+	// BinaryMessage is a binary message
+	BinaryMessage = 2
+)
+
+const (
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+
+	// acceptGUID is appended to the key of the opening handshake to compute the accept header
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// closeNormal is the status code of a normal closure
+	closeNormal = 1000
+
+	// DefaultMaxMessageSize is the default maximum size of a message read from the connection
+	DefaultMaxMessageSize = 64 * 1024
+
+	// closeTimeout is the time allowed to write the close frame
+	closeTimeout = time.Second
+)
+
+var (
+	// ErrNotWebSocket the request is not a websocket opening handshake
+	ErrNotWebSocket = errors.New("websocket: not a websocket handshake request")
+	// ErrBadVersion the websocket version of the request is not 13
+	ErrBadVersion = errors.New("websocket: unsupported websocket version, must be 13")
+	// ErrBadKey the Sec-WebSocket-Key header of the request is invalid
+	ErrBadKey = errors.New("websocket: invalid Sec-WebSocket-Key header")
+	// ErrHijackUnsupported the http.ResponseWriter does not implement http.Hijacker
+	ErrHijackUnsupported = errors.New("websocket: response writer does not support hijacking")
+	// ErrBadHandshake the server response to the opening handshake is invalid
+	ErrBadHandshake = errors.New("websocket: bad handshake response")
+	// ErrProtocol the peer violated the websocket protocol
+	ErrProtocol = errors.New("websocket: protocol error")
+	// ErrMessageTooLarge a message read from the connection is larger than the maximum message size
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+	// ErrClosed the connection was closed by the peer or by Close
+	ErrClosed = errors.New("websocket: connection closed")
+)
+
+// Conn is a websocket connection. ReadMessage must not be called concurrently,
+// the write methods can be called concurrently with each other and with ReadMessage.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	// MaxMessageSize is the maximum size of a message read from the connection
+	MaxMessageSize int
+
+	wl     sync.Mutex
+	closed bool
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{
+		conn:           conn,
+		br:             br,
+		client:         client,
+		MaxMessageSize: DefaultMaxMessageSize,
+	}
+}
+
+// acceptKey computes the Sec-WebSocket-Accept header of the key of the opening handshake
+func acceptKey(key string) string {
+	h := sha1.New()                   //nolint:gosec
+	h.Write([]byte(key + acceptGUID)) //nolint:errcheck
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken returns true if the comma separated header contains the token, ignoring case
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsWebSocketUpgrade returns true if the request asks to upgrade the connection to a websocket
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return headerContainsToken(r.Header, "Connection", "upgrade") && headerContainsToken(r.Header, "Upgrade", "websocket")
+}
+
+// Upgrade completes the opening handshake of a websocket request and returns the websocket connection.
+// If the request is not a valid handshake, an error is returned and nothing is written to w,
+// so that the caller can respond with an error.
+// The origin of the request is not checked, the caller must check it to prevent cross-site websocket hijacking.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet || !IsWebSocketUpgrade(r) {
+		return nil, ErrNotWebSocket
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, ErrBadVersion
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+		return nil, ErrBadKey
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, ErrHijackUnsupported
+	}
+
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// Clear the deadlines set by the http server
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	rsp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(rsp)); err != nil {
+		conn.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return newConn(conn, rw.Reader, false), nil
+}
+
+// Dial opens a websocket connection to a ws:// or wss:// URL, sending the header with the opening handshake
+func Dial(urlStr string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws":
+		conn, err = net.Dial("tcp", hostPort(u, "80"))
+	case "wss":
+		conn, err = tls.Dial("tcp", hostPort(u, "443"), &tls.Config{
+			ServerName: u.Hostname(),
+		})
+	default:
+		return nil, fmt.Errorf("websocket: unsupported URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := handshake(conn, u, header)
+	if err != nil {
+		conn.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+func handshake(conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	var k [16]byte
+	if _, err := io.ReadFull(rand.Reader, k[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(k[:])
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	rsp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("%v: status %s", ErrBadHandshake, rsp.Status)
+	}
+
+	if !headerContainsToken(rsp.Header, "Upgrade", "websocket") ||
+		!headerContainsToken(rsp.Header, "Connection", "upgrade") ||
+		rsp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, ErrBadHandshake
+	}
+
+	return newConn(conn, br, true), nil
+}
+
+// ReadMessage reads the next text or binary message, returning its type and data.
+// Pings are answered with pongs and pongs are ignored. If the peer closes the connection,
+// the close frame is answered and ErrClosed is returned.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var messageType int
+	data := []byte{}
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := []byte{}
+			if len(payload) >= 2 {
+				code = payload[:2]
+			}
+			c.writeFrame(opClose, code) //nolint:errcheck
+			c.conn.Close()              //nolint:errcheck
+			return 0, nil, ErrClosed
+		case opContinuation:
+			if messageType == 0 {
+				return 0, nil, ErrProtocol
+			}
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, ErrProtocol
+			}
+			messageType = int(opcode)
+		default:
+			return 0, nil, ErrProtocol
+		}
+
+		if len(data)+len(payload) > c.MaxMessageSize {
+			return 0, nil, ErrMessageTooLarge
+		}
+		data = append(data, payload...)
+
+		if fin {
+			return messageType, data, nil
+		}
+	}
+}
+
+// readFrame reads a frame, unmasking its payload
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(c.br, h[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := h[0]&0x80 != 0
+	opcode := h[0] & 0x0f
+	if h[0]&0x70 != 0 {
+		// Reserved bits are only used by extensions
+		return false, 0, nil, ErrProtocol
+	}
+
+	// Clients must mask their frames, servers must not
+	masked := h[1]&0x80 != 0
+	if masked == c.client {
+		return false, 0, nil, ErrProtocol
+	}
+
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+
+	// Control frames are not fragmented and have a payload of at most 125 bytes
+	if opcode >= opClose && (!fin || n > 125) {
+		return false, 0, nil, ErrProtocol
+	}
+
+	if n > uint64(c.MaxMessageSize) {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes an unfragmented frame, masking it if the connection is a client connection
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.wl.Lock()
+	defer c.wl.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	b := make([]byte, 0, 14+len(payload))
+	b = append(b, 0x80|opcode)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		b = append(b, maskBit|byte(n))
+	case n <= 0xffff:
+		b = append(b, maskBit|126, byte(n>>8), byte(n))
+	default:
+		var l [8]byte
+		binary.BigEndian.PutUint64(l[:], uint64(n))
+		b = append(b, maskBit|127)
+		b = append(b, l[:]...)
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := io.ReadFull(rand.Reader, mask[:]); err != nil {
+			return err
+		}
+		b = append(b, mask[:]...)
+		for i, p := range payload {
+			b = append(b, p^mask[i%4])
+		}
+	} else {
+		b = append(b, payload...)
+	}
+
+	if opcode == opClose {
+		c.closed = true
+	}
+
+	_, err := c.conn.Write(b)
+	return err
+}
+
+// WriteMessage writes a text or binary message
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case TextMessage, BinaryMessage:
+	default:
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return c.writeFrame(byte(messageType), data)
+}
+
+// WriteJSON writes the JSON encoding of v as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(TextMessage, b)
+}
+
+// Ping writes a ping frame, the peer answers with a pong which is ignored by ReadMessage
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// SetReadDeadline sets the deadline of the reads from the connection
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline of the writes to the connection
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// RemoteAddr returns the address of the peer
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// Close sends a close frame with a normal closure status and closes the connection
+func (c *Conn) Close() error {
+	c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))               //nolint:errcheck
+	c.writeFrame(opClose, []byte{closeNormal >> 8, closeNormal & 0xff}) //nolint:errcheck
+	return c.conn.Close()
+}
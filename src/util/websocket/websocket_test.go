@@ -0,0 +1,165 @@
+package websocket
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// Example of RFC6455 section 1.3
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+// echoServer echoes the messages it reads until the connection is closed
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer c.Close() //nolint:errcheck
+
+		for {
+			messageType, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func wsURL(s *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func TestEcho(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	c, err := Dial(wsURL(s), nil)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	// Payload lengths of the 7 bit, 16 bit and 64 bit encodings
+	for _, n := range []int{0, 1, 125, 126, 0xffff, 0x10000} {
+		data := bytes.Repeat([]byte{'a'}, n)
+		require.NoError(t, c.WriteMessage(BinaryMessage, data))
+
+		messageType, echo, err := c.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, BinaryMessage, messageType)
+		require.Equal(t, data, echo)
+	}
+
+	// Pongs are ignored
+	require.NoError(t, c.Ping())
+
+	require.NoError(t, c.WriteJSON(map[string]string{"type": "test"}))
+	messageType, echo, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, TextMessage, messageType)
+	require.Equal(t, `{"type":"test"}`, string(echo))
+
+	require.Error(t, c.WriteMessage(opPing, nil))
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	c, err := Dial(wsURL(s), nil)
+	require.NoError(t, err)
+	defer c.Close() //nolint:errcheck
+
+	c.MaxMessageSize = 10
+	require.NoError(t, c.WriteMessage(TextMessage, []byte("0123456789a")))
+	_, _, err = c.ReadMessage()
+	require.Equal(t, ErrMessageTooLarge, err)
+}
+
+func TestClose(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	c, err := Dial(wsURL(s), nil)
+	require.NoError(t, err)
+
+	// The server answers the close frame and stops
+	require.NoError(t, c.writeFrame(opClose, []byte{closeNormal >> 8, closeNormal & 0xff}))
+	_, _, err = c.ReadMessage()
+	require.Equal(t, ErrClosed, err)
+
+	require.Equal(t, ErrClosed, c.WriteMessage(TextMessage, []byte("closed")))
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	s := echoServer(t)
+	defer s.Close()
+
+	cases := []struct {
+		name    string
+		header  map[string]string
+		errBody error
+	}{
+		{
+			name:    "not an upgrade",
+			header:  map[string]string{},
+			errBody: ErrNotWebSocket,
+		},
+		{
+			name: "bad version",
+			header: map[string]string{
+				"Connection":            "keep-alive, Upgrade",
+				"Upgrade":               "websocket",
+				"Sec-WebSocket-Version": "8",
+				"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+			},
+			errBody: ErrBadVersion,
+		},
+		{
+			name: "bad key",
+			header: map[string]string{
+				"Connection":            "Upgrade",
+				"Upgrade":               "WebSocket",
+				"Sec-WebSocket-Version": "13",
+				"Sec-WebSocket-Key":     "dGhlIHNhbXBsZQ==",
+			},
+			errBody: ErrBadKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+			require.NoError(t, err)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+
+			rsp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer rsp.Body.Close() //nolint:errcheck
+
+			require.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+			var body bytes.Buffer
+			_, err = body.ReadFrom(rsp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.errBody.Error()+"\n", body.String())
+		})
+	}
+
+	// The server of a plain http endpoint rejects the handshake
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+	_, err := Dial(wsURL(plain), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package visor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+var (
+	// ErrBalanceBlockNotFound is returned by the historical balance queries if there is no block
+	// at the seq, or no block created at or before the time
+	ErrBalanceBlockNotFound = errors.New("block not found")
+)
+
+// HistoricalBalance is the balance of an address after a block was executed
+type HistoricalBalance struct {
+	Address   cipher.Address
+	BlockSeq  uint64
+	BlockTime uint64
+	// Balance has the coin hours of the unspent outputs of the address at the time of the block
+	Balance wallet.Balance
+	// Outputs is the number of unspent outputs of the address
+	Outputs int
+}
+
+// GetAddressBalanceAtSeq returns the balance of an address after the block seq was executed
+func (vs *Visor) GetAddressBalanceAtSeq(addr cipher.Address, seq uint64) (*HistoricalBalance, error) {
+	var balance *HistoricalBalance
+	if err := vs.db.View("GetAddressBalanceAtSeq", func(tx *dbutil.Tx) error {
+		b, err := vs.blockchain.GetSignedBlockBySeq(tx, seq)
+		if err != nil {
+			return err
+		} else if b == nil {
+			return ErrBalanceBlockNotFound
+		}
+
+		balance, err = vs.getAddressBalanceAt(tx, addr, b)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// GetAddressBalanceAtTime returns the balance of an address after the last block created
+// at or before the time was executed
+func (vs *Visor) GetAddressBalanceAtTime(addr cipher.Address, t uint64) (*HistoricalBalance, error) {
+	var balance *HistoricalBalance
+	if err := vs.db.View("GetAddressBalanceAtTime", func(tx *dbutil.Tx) error {
+		b, err := vs.getLastBlockAtTime(tx, t)
+		if err != nil {
+			return err
+		} else if b == nil {
+			return ErrBalanceBlockNotFound
+		}
+
+		balance, err = vs.getAddressBalanceAt(tx, addr, b)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// getLastBlockAtTime returns the last block created at or before the time, nil if the time
+// precedes the genesis block. The block times increase with the block seq.
+func (vs *Visor) getLastBlockAtTime(tx *dbutil.Tx, t uint64) (*coin.SignedBlock, error) {
+	headSeq, ok, err := vs.blockchain.HeadSeq(tx)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	// Finds the first block created after the time
+	var searchErr error
+	n := sort.Search(int(headSeq)+1, func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+
+		b, err := vs.blockchain.GetSignedBlockBySeq(tx, uint64(i))
+		if err != nil {
+			searchErr = err
+			return true
+		} else if b == nil {
+			searchErr = fmt.Errorf("block %d not found", i)
+			return true
+		}
+
+		return b.Time() > t
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	if n == 0 {
+		return nil, nil
+	}
+
+	return vs.blockchain.GetSignedBlockBySeq(tx, uint64(n-1))
+}
+
+// getAddressBalanceAt returns the balance of an address after the block was executed
+func (vs *Visor) getAddressBalanceAt(tx *dbutil.Tx, addr cipher.Address, b *coin.SignedBlock) (*HistoricalBalance, error) {
+	parsedSeq, ok, err := vs.history.ParsedBlockSeq(tx)
+	if err != nil {
+		return nil, err
+	} else if !ok || parsedSeq < b.Seq() {
+		return nil, fmt.Errorf("block %d is not parsed by the history db", b.Seq())
+	}
+
+	outs, err := vs.history.GetUnspentOutputsOfAddressAt(tx, addr, b.Seq())
+	if err != nil {
+		return nil, err
+	}
+
+	var balance wallet.Balance
+	for _, o := range outs {
+		ob, err := wallet.NewBalanceFromUxOut(b.Time(), &o.Out)
+		if err != nil {
+			return nil, err
+		}
+
+		balance, err = balance.Add(ob)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &HistoricalBalance{
+		Address:   addr,
+		BlockSeq:  b.Seq(),
+		BlockTime: b.Time(),
+		Balance:   balance,
+		Outputs:   len(outs),
+	}, nil
+}
@@ -0,0 +1,223 @@
+package visor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/visor/historydb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestGetAddressBalanceAt(t *testing.T) {
+	newUint64 := func(n uint64) *uint64 {
+		return &n
+	}
+
+	addr := testutil.MakeAddress()
+
+	// The blocks are created an hour apart
+	var blocks []coin.SignedBlock
+	for i := uint64(0); i < 5; i++ {
+		blocks = append(blocks, coin.SignedBlock{
+			Block: coin.Block{
+				Head: coin.BlockHeader{
+					BkSeq: i,
+					Time:  1000 + i*3600,
+				},
+			},
+		})
+	}
+
+	out1 := historydb.UxOut{
+		Out: coin.UxOut{
+			Head: coin.UxHead{
+				Time:  1000,
+				BkSeq: 0,
+			},
+			Body: coin.UxBody{
+				Address: addr,
+				Coins:   2e6,
+				Hours:   10,
+			},
+		},
+	}
+	out2 := historydb.UxOut{
+		Out: coin.UxOut{
+			Head: coin.UxHead{
+				Time:  1000 + 2*3600,
+				BkSeq: 2,
+			},
+			Body: coin.UxBody{
+				Address: addr,
+				Coins:   1.5e6,
+			},
+		},
+	}
+
+	outputs := map[uint64][]historydb.UxOut{
+		0: {out1},
+		1: {out1},
+		2: {out1, out2},
+		3: {out2},
+		4: {out2},
+	}
+
+	cases := []struct {
+		name      string
+		seq       *uint64
+		time      *uint64
+		parsedSeq uint64
+		balance   *HistoricalBalance
+		err       error
+	}{
+		{
+			name:      "seq 0",
+			seq:       newUint64(0),
+			parsedSeq: 4,
+			balance: &HistoricalBalance{
+				Address:   addr,
+				BlockSeq:  0,
+				BlockTime: 1000,
+				Balance: wallet.Balance{
+					Coins: 2e6,
+					Hours: 10,
+				},
+				Outputs: 1,
+			},
+		},
+		{
+			name:      "seq 2",
+			seq:       newUint64(2),
+			parsedSeq: 4,
+			balance: &HistoricalBalance{
+				Address:   addr,
+				BlockSeq:  2,
+				BlockTime: 1000 + 2*3600,
+				Balance: wallet.Balance{
+					Coins: 3.5e6,
+					Hours: 14,
+				},
+				Outputs: 2,
+			},
+		},
+		{
+			name:      "seq after the head",
+			seq:       newUint64(5),
+			parsedSeq: 4,
+			err:       ErrBalanceBlockNotFound,
+		},
+		{
+			name:      "seq not parsed",
+			seq:       newUint64(4),
+			parsedSeq: 3,
+			err:       errors.New("block 4 is not parsed by the history db"),
+		},
+		{
+			name:      "time before the genesis block",
+			time:      newUint64(999),
+			parsedSeq: 4,
+			err:       ErrBalanceBlockNotFound,
+		},
+		{
+			name:      "time of a block",
+			time:      newUint64(1000 + 3600),
+			parsedSeq: 4,
+			balance: &HistoricalBalance{
+				Address:   addr,
+				BlockSeq:  1,
+				BlockTime: 1000 + 3600,
+				Balance: wallet.Balance{
+					Coins: 2e6,
+					Hours: 12,
+				},
+				Outputs: 1,
+			},
+		},
+		{
+			name:      "time between blocks",
+			time:      newUint64(1000 + 4*3600 - 1),
+			parsedSeq: 4,
+			balance: &HistoricalBalance{
+				Address:   addr,
+				BlockSeq:  3,
+				BlockTime: 1000 + 3*3600,
+				Balance: wallet.Balance{
+					Coins: 1.5e6,
+					Hours: 1,
+				},
+				Outputs: 1,
+			},
+		},
+		{
+			name:      "time after the head",
+			time:      newUint64(1e9),
+			parsedSeq: 4,
+			balance: &HistoricalBalance{
+				Address:   addr,
+				BlockSeq:  4,
+				BlockTime: 1000 + 4*3600,
+				Balance: wallet.Balance{
+					Coins: 1.5e6,
+					Hours: 3,
+				},
+				Outputs: 1,
+			},
+		},
+	}
+
+	matchDBTx := mock.MatchedBy(func(tx *dbutil.Tx) bool {
+		return true
+	})
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, shutdown := prepareDB(t)
+			defer shutdown()
+
+			bc := &MockBlockchainer{}
+			his := &MockHistoryer{}
+
+			bc.On("HeadSeq", matchDBTx).Return(uint64(len(blocks)-1), true, nil)
+			bc.On("GetSignedBlockBySeq", matchDBTx, mock.Anything).Return(func(_ *dbutil.Tx, seq uint64) *coin.SignedBlock {
+				if seq >= uint64(len(blocks)) {
+					return nil
+				}
+				return &blocks[seq]
+			}, nil)
+
+			his.On("ParsedBlockSeq", matchDBTx).Return(tc.parsedSeq, true, nil)
+			his.On("GetUnspentOutputsOfAddressAt", matchDBTx, addr, mock.Anything).Return(func(_ *dbutil.Tx, _ cipher.Address, seq uint64) []historydb.UxOut {
+				return outputs[seq]
+			}, nil)
+
+			v := &Visor{
+				db:         db,
+				blockchain: bc,
+				history:    his,
+			}
+
+			var balance *HistoricalBalance
+			var err error
+			if tc.seq != nil {
+				balance, err = v.GetAddressBalanceAtSeq(addr, *tc.seq)
+			} else {
+				balance, err = v.GetAddressBalanceAtTime(addr, *tc.time)
+			}
+
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.balance, balance)
+		})
+	}
+}
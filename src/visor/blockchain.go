@@ -483,6 +483,10 @@ func (bc Blockchain) GetBlocksInRange(tx *dbutil.Tx, start, end uint64) ([]coin.
 
 	var blocks []coin.SignedBlock
 	for i := start; i <= end; i++ {
+		if err := tx.Err(); err != nil {
+			return nil, err
+		}
+
 		b, err := bc.store.GetSignedBlockBySeq(tx, i)
 		if err != nil {
 			logger.WithError(err).Error("bc.store.GetSignedBlockBySeq failed")
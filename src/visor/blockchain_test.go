@@ -594,7 +594,7 @@ func TestProcessTransactions(t *testing.T) {
 					UxIndex: 0,
 					Keys:    []cipher.SecKey{genSecret},
 					ToAddr:  toAddrs[0],
-					Coins:   10e6,
+					Coins:   20e6,
 				},
 			},
 			errors.New("Cannot spend output twice in the same block"),
@@ -67,6 +67,8 @@ func CreateBuckets(tx *dbutil.Tx) error {
 		UnspentPoolBkt,
 		UnspentPoolAddrIndexBkt,
 		UnspentMetaBkt,
+		UnspentPoolAddrCoinsBkt,
+		UnspentPoolRichlistBkt,
 	})
 }
 
@@ -100,6 +102,7 @@ type UnspentPooler interface {
 	GetUnspentHashesOfAddrs(*dbutil.Tx, []cipher.Address) (AddressHashes, error)
 	ProcessBlock(*dbutil.Tx, *coin.SignedBlock) error
 	AddressCount(*dbutil.Tx) (uint64, error)
+	ForEachRichlistEntry(*dbutil.Tx, func(cipher.Address, uint64) error) error
 }
 
 // ChainMeta blockchain metadata
@@ -267,6 +267,10 @@ func (fup *fakeUnspentPool) AddressCount(tx *dbutil.Tx) (uint64, error) {
 	return uint64(len(addrs)), nil
 }
 
+func (fup *fakeUnspentPool) ForEachRichlistEntry(tx *dbutil.Tx, f func(cipher.Address, uint64) error) error {
+	return nil
+}
+
 type fakeChainMeta struct {
 	headSeq   uint64
 	didSetSeq bool
@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/util/mathutil"
 	"github.com/skycoin/skycoin/src/visor/dbutil"
 )
 
 var (
 	xorhashKey         = []byte("xorhash")
 	addrIndexHeightKey = []byte("addr_index_height")
+	richlistHeightKey  = []byte("richlist_index_height")
 
 	// UnspentPoolBkt holds unspent outputs, indexed by unspent output hash
 	UnspentPoolBkt = []byte("unspent_pool")
@@ -20,6 +23,13 @@ var (
 	UnspentPoolAddrIndexBkt = []byte("unspent_pool_addr_index")
 	// UnspentMetaBkt holds unspent output metadata
 	UnspentMetaBkt = []byte("unspent_meta")
+	// UnspentPoolAddrCoinsBkt maps addresses to the coins of their unspent outputs
+	UnspentPoolAddrCoinsBkt = []byte("unspent_pool_addr_coins")
+	// UnspentPoolRichlistBkt indexes the addresses with unspent outputs by their coins, in descending order
+	UnspentPoolRichlistBkt = []byte("unspent_pool_richlist")
+
+	// ErrStopIteration can be returned by the callback of ForEachRichlistEntry to stop the iteration
+	ErrStopIteration = errors.New("stop iteration")
 )
 
 // ErrUnspentNotExist is returned if an unspent is not found in the pool
@@ -91,6 +101,21 @@ func (m *unspentMeta) setAddrIndexHeight(tx *dbutil.Tx, height uint64) error {
 	return dbutil.PutBucketValue(tx, UnspentMetaBkt, addrIndexHeightKey, dbutil.Itob(height))
 }
 
+func (m *unspentMeta) getRichlistHeight(tx *dbutil.Tx) (uint64, bool, error) {
+	v, err := dbutil.GetBucketValue(tx, UnspentMetaBkt, richlistHeightKey)
+	if err != nil {
+		return 0, false, err
+	} else if v == nil {
+		return 0, false, nil
+	}
+
+	return dbutil.Btoi(v), true, nil
+}
+
+func (m *unspentMeta) setRichlistHeight(tx *dbutil.Tx, height uint64) error {
+	return dbutil.PutBucketValue(tx, UnspentMetaBkt, richlistHeightKey, dbutil.Itob(height))
+}
+
 type pool struct{}
 
 func (pl pool) get(tx *dbutil.Tx, hash cipher.SHA256) (*coin.UxOut, error) {
@@ -250,19 +275,98 @@ func (p poolAddrIndex) adjust(tx *dbutil.Tx, addr cipher.Address, addHashes, rmH
 	return p.put(tx, addr, newHashes)
 }
 
+// richlistKey is the key of an address in the richlist index.
+// The inverted coins sort the addresses by descending coins, then by ascending address bytes.
+func richlistKey(addr cipher.Address, coins uint64) []byte {
+	return append(dbutil.Itob(^coins), addr.Bytes()...)
+}
+
+// parseRichlistKey returns the address and coins of a richlist index key
+func parseRichlistKey(k []byte) (cipher.Address, uint64, error) {
+	if len(k) < 8 {
+		return cipher.Address{}, 0, errors.New("invalid richlist index key length")
+	}
+
+	addr, err := cipher.AddressFromBytes(k[8:])
+	if err != nil {
+		return cipher.Address{}, 0, err
+	}
+
+	return addr, ^dbutil.Btoi(k[:8]), nil
+}
+
+// poolRichlistIndex keeps the coins of each address with unspent outputs,
+// and the addresses sorted by their coins
+type poolRichlistIndex struct{}
+
+func (p poolRichlistIndex) get(tx *dbutil.Tx, addr cipher.Address) (uint64, error) {
+	v, err := dbutil.GetBucketValueNoCopy(tx, UnspentPoolAddrCoinsBkt, addr.Bytes())
+	if err != nil {
+		return 0, err
+	} else if v == nil {
+		return 0, nil
+	}
+
+	return dbutil.Btoi(v), nil
+}
+
+// set changes the coins of an address, an address without coins is removed from the index
+func (p poolRichlistIndex) set(tx *dbutil.Tx, addr cipher.Address, prevCoins, coins uint64) error {
+	if prevCoins == coins {
+		return nil
+	}
+
+	if prevCoins != 0 {
+		if err := dbutil.Delete(tx, UnspentPoolRichlistBkt, richlistKey(addr, prevCoins)); err != nil {
+			return err
+		}
+	}
+
+	if coins == 0 {
+		return dbutil.Delete(tx, UnspentPoolAddrCoinsBkt, addr.Bytes())
+	}
+
+	if err := dbutil.PutBucketValue(tx, UnspentPoolRichlistBkt, richlistKey(addr, coins), nil); err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, UnspentPoolAddrCoinsBkt, addr.Bytes(), dbutil.Itob(coins))
+}
+
+// adjust adds and removes coins from the coins of an address
+func (p poolRichlistIndex) adjust(tx *dbutil.Tx, addr cipher.Address, addCoins, rmCoins uint64) error {
+	prevCoins, err := p.get(tx, addr)
+	if err != nil {
+		return err
+	}
+
+	coins, err := mathutil.AddUint64(prevCoins, addCoins)
+	if err != nil {
+		return err
+	}
+
+	if rmCoins > coins {
+		return fmt.Errorf("poolRichlistIndex.adjust: removing %d coins from the %d coins of address %s", rmCoins, coins, addr.String())
+	}
+
+	return p.set(tx, addr, prevCoins, coins-rmCoins)
+}
+
 // Unspents unspent outputs pool
 type Unspents struct {
-	pool          *pool
-	poolAddrIndex *poolAddrIndex
-	meta          *unspentMeta
+	pool              *pool
+	poolAddrIndex     *poolAddrIndex
+	poolRichlistIndex *poolRichlistIndex
+	meta              *unspentMeta
 }
 
 // NewUnspentPool creates new unspent pool instance
 func NewUnspentPool() *Unspents {
 	return &Unspents{
-		pool:          &pool{},
-		poolAddrIndex: &poolAddrIndex{},
-		meta:          &unspentMeta{},
+		pool:              &pool{},
+		poolAddrIndex:     &poolAddrIndex{},
+		poolRichlistIndex: &poolRichlistIndex{},
+		meta:              &unspentMeta{},
 	}
 }
 
@@ -270,6 +374,15 @@ func NewUnspentPool() *Unspents {
 func (up *Unspents) MaybeBuildIndexes(tx *dbutil.Tx, headSeq uint64) error {
 	logger.Info("Unspents.MaybeBuildIndexes")
 
+	if err := up.maybeBuildAddrIndex(tx, headSeq); err != nil {
+		return err
+	}
+
+	return up.maybeBuildRichlistIndex(tx, headSeq)
+}
+
+// maybeBuildAddrIndex builds the address index if its height does not match the head block
+func (up *Unspents) maybeBuildAddrIndex(tx *dbutil.Tx, headSeq uint64) error {
 	// Compare the addrIndexHeight to the head block,
 	// if not equal, rebuild the address index
 	addrIndexHeight, ok, err := up.meta.getAddrIndexHeight(tx)
@@ -290,6 +403,80 @@ func (up *Unspents) MaybeBuildIndexes(tx *dbutil.Tx, headSeq uint64) error {
 	return up.buildAddrIndex(tx)
 }
 
+// maybeBuildRichlistIndex builds the richlist index if its height does not match the head block
+func (up *Unspents) maybeBuildRichlistIndex(tx *dbutil.Tx, headSeq uint64) error {
+	richlistHeight, ok, err := up.meta.getRichlistHeight(tx)
+	if err != nil {
+		return err
+	}
+
+	if ok && richlistHeight == headSeq {
+		return nil
+	}
+
+	logger.Infof("Rebuilding unspent_pool_richlist (richlistHeightExists=%v, richlistHeight=%d, headSeq=%d)", ok, richlistHeight, headSeq)
+
+	return up.buildRichlistIndex(tx, headSeq)
+}
+
+func (up *Unspents) buildRichlistIndex(tx *dbutil.Tx, headSeq uint64) error {
+	logger.Info("Building unspent richlist index")
+
+	// The buckets do not exist in databases created before the richlist index
+	buckets := [][]byte{UnspentPoolAddrCoinsBkt, UnspentPoolRichlistBkt}
+	if err := dbutil.CreateBuckets(tx, buckets); err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		if err := dbutil.Reset(tx, b); err != nil {
+			return err
+		}
+	}
+
+	addrCoins, err := up.getAddressCoins(tx)
+	if err != nil {
+		return err
+	}
+
+	for addr, coins := range addrCoins {
+		if err := up.poolRichlistIndex.set(tx, addr, 0, coins); err != nil {
+			return err
+		}
+	}
+
+	if err := up.meta.setRichlistHeight(tx, headSeq); err != nil {
+		return err
+	}
+
+	logger.Infof("Indexed the coins of %d addresses", len(addrCoins))
+
+	return nil
+}
+
+// getAddressCoins sums the coins of the unspent outputs of each address
+func (up *Unspents) getAddressCoins(tx *dbutil.Tx) (map[cipher.Address]uint64, error) {
+	addrCoins := make(map[cipher.Address]uint64)
+
+	if err := dbutil.ForEach(tx, UnspentPoolBkt, func(_, v []byte) error {
+		var ux coin.UxOut
+		if err := decodeUxOutExact(v, &ux); err != nil {
+			return err
+		}
+
+		coins, err := mathutil.AddUint64(addrCoins[ux.Body.Address], ux.Body.Coins)
+		if err != nil {
+			return err
+		}
+		addrCoins[ux.Body.Address] = coins
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return addrCoins, nil
+}
+
 func (up *Unspents) buildAddrIndex(tx *dbutil.Tx) error {
 	logger.Info("Building unspent address index")
 
@@ -427,6 +614,35 @@ func (up *Unspents) ProcessBlock(tx *dbutil.Tx, b *coin.SignedBlock) error {
 		}
 	}
 
+	// Update the richlist index
+	addrCoinsDelta := make(map[cipher.Address][2]uint64)
+	for _, ux := range uxs {
+		d := addrCoinsDelta[ux.Body.Address]
+		d[1], err = mathutil.AddUint64(d[1], ux.Body.Coins)
+		if err != nil {
+			return err
+		}
+		addrCoinsDelta[ux.Body.Address] = d
+	}
+	for _, ux := range txnUxs {
+		d := addrCoinsDelta[ux.Body.Address]
+		d[0], err = mathutil.AddUint64(d[0], ux.Body.Coins)
+		if err != nil {
+			return err
+		}
+		addrCoinsDelta[ux.Body.Address] = d
+	}
+
+	for addr, d := range addrCoinsDelta {
+		if err := up.poolRichlistIndex.adjust(tx, addr, d[0], d[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := up.meta.setRichlistHeight(tx, b.Block.Head.BkSeq); err != nil {
+		return err
+	}
+
 	// Check that the addrIndexHeight is incremental
 	addrIndexHeight, ok, err := up.meta.getAddrIndexHeight(tx)
 	if err != nil {
@@ -508,6 +724,10 @@ func (up *Unspents) GetUnspentsOfAddrs(tx *dbutil.Tx, addrs []cipher.Address) (c
 	addrUxs := make(coin.AddressUxOuts, len(addrs))
 
 	for _, addr := range addrs {
+		if err := tx.Err(); err != nil {
+			return nil, err
+		}
+
 		hashes, err := up.poolAddrIndex.get(tx, addr)
 		if err != nil {
 			return nil, err
@@ -535,6 +755,64 @@ func (up *Unspents) GetUxHash(tx *dbutil.Tx) (cipher.SHA256, error) {
 	return up.meta.getXorHash(tx)
 }
 
+// ForEachRichlistEntry calls f with the addresses with unspent outputs and their coins,
+// sorted by descending coins then by ascending address bytes.
+// The iteration stops without error if f returns ErrStopIteration.
+func (up *Unspents) ForEachRichlistEntry(tx *dbutil.Tx, f func(cipher.Address, uint64) error) error {
+	if !dbutil.Exists(tx, UnspentPoolRichlistBkt) {
+		// The index is not built in databases opened read-only before the richlist index
+		return up.forEachRichlistEntryUnindexed(tx, f)
+	}
+
+	err := dbutil.ForEach(tx, UnspentPoolRichlistBkt, func(k, _ []byte) error {
+		addr, coins, err := parseRichlistKey(k)
+		if err != nil {
+			return err
+		}
+
+		return f(addr, coins)
+	})
+
+	if err == ErrStopIteration {
+		return nil
+	}
+	return err
+}
+
+// forEachRichlistEntryUnindexed is ForEachRichlistEntry for databases without the richlist index,
+// the coins of the addresses are summed from the unspent pool
+func (up *Unspents) forEachRichlistEntryUnindexed(tx *dbutil.Tx, f func(cipher.Address, uint64) error) error {
+	addrCoins, err := up.getAddressCoins(tx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, 0, len(addrCoins))
+	for addr, coins := range addrCoins {
+		keys = append(keys, richlistKey(addr, coins))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	for _, k := range keys {
+		addr, coins, err := parseRichlistKey(k)
+		if err != nil {
+			return err
+		}
+
+		if err := f(addr, coins); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // AddressCount returns the total number of addresses with unspents
 func (up *Unspents) AddressCount(tx *dbutil.Tx) (uint64, error) {
 	return dbutil.Len(tx, UnspentPoolAddrIndexBkt)
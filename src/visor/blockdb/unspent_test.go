@@ -68,8 +68,47 @@ func addUxOut(db *dbutil.DB, up *Unspents, ux coin.UxOut) error {
 			return err
 		}
 
-		return up.poolAddrIndex.adjust(tx, ux.Body.Address, []cipher.SHA256{ux.Hash()}, nil)
+		if err := up.poolAddrIndex.adjust(tx, ux.Body.Address, []cipher.SHA256{ux.Hash()}, nil); err != nil {
+			return err
+		}
+
+		return up.poolRichlistIndex.adjust(tx, ux.Body.Address, ux.Body.Coins, 0)
+	})
+}
+
+// requireRichlistIndex checks that the richlist index matches the coins of the unspent outputs
+func requireRichlistIndex(t *testing.T, tx *dbutil.Tx, up *Unspents) {
+	expectedCoins, err := up.getAddressCoins(tx)
+	require.NoError(t, err)
+
+	length, err := dbutil.Len(tx, UnspentPoolRichlistBkt)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(expectedCoins)), length)
+
+	length, err = dbutil.Len(tx, UnspentPoolAddrCoinsBkt)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(expectedCoins)), length)
+
+	var prevCoins uint64
+	var prevAddr *cipher.Address
+	err = up.ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+		require.Equal(t, expectedCoins[addr], coins)
+
+		indexedCoins, err := up.poolRichlistIndex.get(tx, addr)
+		require.NoError(t, err)
+		require.Equal(t, coins, indexedCoins)
+
+		if prevAddr != nil {
+			require.True(t, coins < prevCoins || (coins == prevCoins && bytes.Compare(prevAddr.Bytes(), addr.Bytes()) < 0))
+		}
+		prevCoins = coins
+		prevAddr = &addr
+
+		delete(expectedCoins, addr)
+		return nil
 	})
+	require.NoError(t, err)
+	require.Empty(t, expectedCoins)
 }
 
 func TestUnspentPoolGet(t *testing.T) {
@@ -628,6 +667,14 @@ func TestUnspentProcessBlock(t *testing.T) {
 				})
 				require.NoError(t, err)
 
+				// the richlist index should be updated
+				requireRichlistIndex(t, tx, up)
+
+				richlistHeight, ok, err := up.meta.getRichlistHeight(tx)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, uint64(1), richlistHeight)
+
 				return nil
 			})
 			require.NoError(t, err)
@@ -1042,6 +1089,14 @@ func testUnspentMaybeBuildIndexes(t *testing.T, headIndex uint64, setupDB func(*
 
 		require.Empty(t, addrHashes)
 
+		// Check the richlist index
+		requireRichlistIndex(t, tx, u)
+
+		richlistHeight, ok, err := u.meta.getRichlistHeight(tx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, headIndex, richlistHeight)
+
 		return nil
 	})
 	require.NoError(t, err)
@@ -1118,6 +1173,70 @@ func setupNoUnspentAddrIndexDB(t *testing.T) (*dbutil.DB, func()) {
 	}
 }
 
+func TestUnspentForEachRichlistEntry(t *testing.T) {
+	db, shutdown := setupNoUnspentAddrIndexDB(t)
+	defer shutdown()
+
+	u := NewUnspentPool()
+
+	// Collect the richlist of a database without the richlist index, it is summed from the unspent pool
+	type entry struct {
+		addr  cipher.Address
+		coins uint64
+	}
+	var unindexed []entry
+	err := db.View("", func(tx *dbutil.Tx) error {
+		return u.ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+			unindexed = append(unindexed, entry{addr, coins})
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Len(t, unindexed, 155)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(UnspentPoolAddrIndexBkt); err != nil {
+			return err
+		}
+
+		return u.MaybeBuildIndexes(tx, 180)
+	})
+	require.NoError(t, err)
+
+	// The indexed richlist matches the unindexed richlist
+	var indexed []entry
+	err = db.View("", func(tx *dbutil.Tx) error {
+		return u.ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+			indexed = append(indexed, entry{addr, coins})
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, unindexed, indexed)
+
+	// The iteration stops when ErrStopIteration is returned
+	var n int
+	err = db.View("", func(tx *dbutil.Tx) error {
+		return u.ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+			n++
+			if n == 3 {
+				return ErrStopIteration
+			}
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	// Other errors are returned
+	err = db.View("", func(tx *dbutil.Tx) error {
+		return u.ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+			return errors.New("failed")
+		})
+	})
+	require.Equal(t, errors.New("failed"), err)
+}
+
 func TestAddressHashesFlatten(t *testing.T) {
 	addrHashes := make(AddressHashes)
 
@@ -86,10 +86,19 @@ func CheckDatabase(db *dbutil.DB, pubkey cipher.PubKey, quit chan struct{}) erro
 		lock.Lock()
 		err = historyVerifyErr
 		lock.Unlock()
-		return err
+		if err != nil {
+			return err
+		}
 	default:
 		return err
 	}
+
+	// Record the check, its age is reported by the health API
+	if !db.IsReadOnly() {
+		return SetDBVerifiedAt(db, time.Now())
+	}
+
+	return nil
 }
 
 // backup the corrypted db first, then rebuild the history DB.
@@ -4,6 +4,7 @@ Package dbutil provides boltdb utility methods
 package dbutil
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
@@ -31,6 +32,17 @@ var (
 // Tx wraps a Tx
 type Tx struct {
 	*bolt.Tx
+	// ctx is the context of a ViewContext transaction
+	ctx context.Context
+}
+
+// Err returns the error of the context of a ViewContext transaction once it is canceled or its deadline is exceeded.
+// The long iterations of a read transaction check it to abort early.
+func (tx *Tx) Err() error {
+	if tx.ctx == nil {
+		return nil
+	}
+	return tx.ctx.Err()
 }
 
 // String is implemented to prevent a panic when mocking methods with *Tx arguments.
@@ -75,6 +87,20 @@ func WrapDB(db *bolt.DB) *DB {
 
 // View wraps *bolt.DB.View to add logging
 func (db *DB) View(name string, f func(*Tx) error) error {
+	return db.view(context.Background(), name, f)
+}
+
+// ViewContext is the same as View, but the transaction is aborted with the error of ctx
+// when ctx is canceled or its deadline is exceeded, at the next check of Tx.Err
+func (db *DB) ViewContext(ctx context.Context, name string, f func(*Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return db.view(ctx, name, f)
+}
+
+func (db *DB) view(ctx context.Context, name string, f func(*Tx) error) error {
 	db.shutdownLock.RLock()
 	defer db.shutdownLock.RUnlock()
 
@@ -89,7 +115,10 @@ func (db *DB) View(name string, f func(*Tx) error) error {
 	t0 := time.Now()
 
 	err := db.DB.View(func(tx *bolt.Tx) error {
-		return f(&Tx{tx})
+		return f(&Tx{
+			Tx:  tx,
+			ctx: ctx,
+		})
 	})
 
 	t1 := time.Now()
@@ -117,7 +146,10 @@ func (db *DB) Update(name string, f func(*Tx) error) error {
 	t0 := time.Now()
 
 	err := db.DB.Update(func(tx *bolt.Tx) error {
-		return f(&Tx{tx})
+		return f(&Tx{
+			Tx:  tx,
+			ctx: context.Background(),
+		})
 	})
 
 	t1 := time.Now()
@@ -287,14 +319,25 @@ func NextSequence(tx *Tx, bktName []byte) (uint64, error) {
 	return bkt.NextSequence()
 }
 
-// ForEach calls ForEach on the bucket
+// ForEach calls ForEach on the bucket.
+// The iteration is aborted with the error of Tx.Err.
 func ForEach(tx *Tx, bktName []byte, f func(k, v []byte) error) error {
 	bkt := tx.Bucket(bktName)
 	if bkt == nil {
 		return NewErrBucketNotExist(bktName)
 	}
 
-	return bkt.ForEach(f)
+	// The context of View and Update transactions is never done
+	if tx.ctx == nil || tx.ctx.Done() == nil {
+		return bkt.ForEach(f)
+	}
+
+	return bkt.ForEach(func(k, v []byte) error {
+		if err := tx.Err(); err != nil {
+			return err
+		}
+		return f(k, v)
+	})
 }
 
 // Delete deletes from a bucket
@@ -0,0 +1,102 @@
+package visor
+
+import (
+	"sync"
+
+	"github.com/skycoin/skycoin/src/coin"
+)
+
+// EventType is the type of a blockchain event
+type EventType string
+
+const (
+	// EventBlockExecuted is emitted when a block is added to the blockchain
+	EventBlockExecuted EventType = "blockExecuted"
+	// EventUnconfirmedTxnInjected is emitted when a new transaction is added to the unconfirmed pool
+	EventUnconfirmedTxnInjected EventType = "unconfirmedTxnInjected"
+)
+
+// DefaultEventBufferSize is the channel buffer size of a subscription if none is specified
+const DefaultEventBufferSize = 64
+
+// Event is a blockchain event emitted by the visor, after the database transaction that caused it is committed
+type Event struct {
+	Type EventType
+	// Block is the block of an EventBlockExecuted event
+	Block *coin.SignedBlock
+	// Transaction is the transaction of an EventUnconfirmedTxnInjected event
+	Transaction *coin.Transaction
+}
+
+// eventBus delivers the blockchain events to the subscribers.
+// Events are dropped for subscribers whose channel buffer is full, so that
+// a slow subscriber can not block the visor.
+// Publishing to a nil eventBus is a no-op.
+type eventBus struct {
+	sync.Mutex
+	subs   map[uint64]chan Event
+	nextID uint64
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subs: make(map[uint64]chan Event),
+	}
+}
+
+func (b *eventBus) subscribe(bufferSize int) (<-chan Event, func()) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	c := make(chan Event, bufferSize)
+	b.subs[id] = c
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			delete(b.subs, id)
+			close(c)
+		})
+	}
+
+	return c, unsubscribe
+}
+
+func (b *eventBus) publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	for _, c := range b.subs {
+		select {
+		case c <- e:
+		default:
+			logger.WithField("eventType", e.Type).Warning("Blockchain event subscriber is full, dropping event")
+		}
+	}
+}
+
+func (b *eventBus) publishBlock(block coin.SignedBlock) {
+	b.publish(Event{
+		Type:  EventBlockExecuted,
+		Block: &block,
+	})
+}
+
+func (b *eventBus) publishUnconfirmedTxn(txn coin.Transaction) {
+	b.publish(Event{
+		Type:        EventUnconfirmedTxnInjected,
+		Transaction: &txn,
+	})
+}
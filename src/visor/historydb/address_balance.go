@@ -0,0 +1,81 @@
+package historydb
+
+import (
+	"bytes"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+// AddressBalancesBkt maps an address and the seq of a block to the outputs of the address
+// created and spent in the block
+var AddressBalancesBkt = []byte("address_balances")
+
+// addressBalanceChange is the change of the outputs of an address in a block
+type addressBalanceChange struct {
+	Created []cipher.SHA256
+	Spent   []cipher.SHA256
+}
+
+// bucket for storing the history of the outputs of addresses,
+// key as address followed by the block seq, value as addressBalanceChange.
+// The keys of an address are sorted by block seq.
+type addressBalances struct{}
+
+// addressBalanceKey returns the key of an address and a block seq
+func addressBalanceKey(addr cipher.Address, seq uint64) []byte {
+	return append(addr.Bytes(), dbutil.Itob(seq)...)
+}
+
+// put stores the change of the outputs of an address in a block
+func (ab *addressBalances) put(tx *dbutil.Tx, addr cipher.Address, seq uint64, c addressBalanceChange) error {
+	return dbutil.PutBucketValue(tx, AddressBalancesBkt, addressBalanceKey(addr, seq), encoder.Serialize(c))
+}
+
+// unspentAt returns the hashes of the unspent outputs of an address after the block seq was executed,
+// sorted by the seq of the block that created them
+func (ab *addressBalances) unspentAt(tx *dbutil.Tx, addr cipher.Address, seq uint64) ([]cipher.SHA256, error) {
+	bkt := tx.Bucket(AddressBalancesBkt)
+	if bkt == nil {
+		return nil, dbutil.NewErrBucketNotExist(AddressBalancesBkt)
+	}
+
+	prefix := addr.Bytes()
+	last := addressBalanceKey(addr, seq)
+
+	var created []cipher.SHA256
+	spent := make(map[cipher.SHA256]struct{})
+
+	c := bkt.Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix) && bytes.Compare(k, last) <= 0; k, v = c.Next() {
+		var change addressBalanceChange
+		if err := encoder.DeserializeRawExact(v, &change); err != nil {
+			return nil, err
+		}
+
+		created = append(created, change.Created...)
+		for _, h := range change.Spent {
+			spent[h] = struct{}{}
+		}
+	}
+
+	var unspent []cipher.SHA256
+	for _, h := range created {
+		if _, ok := spent[h]; !ok {
+			unspent = append(unspent, h)
+		}
+	}
+
+	return unspent, nil
+}
+
+// isEmpty checks if the addressBalances bucket is empty
+func (ab *addressBalances) isEmpty(tx *dbutil.Tx) (bool, error) {
+	return dbutil.IsEmpty(tx, AddressBalancesBkt)
+}
+
+// reset resets the bucket
+func (ab *addressBalances) reset(tx *dbutil.Tx) error {
+	return dbutil.Reset(tx, AddressBalancesBkt)
+}
@@ -0,0 +1,185 @@
+package historydb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+func TestGetUnspentOutputsOfAddressAt(t *testing.T) {
+	db, teardown := prepareDB(t)
+	defer teardown()
+
+	bc := newBlockchain()
+	gb := bc.CreateGenesisBlock(genAddress, genCoins, genTime)
+	hisDB := New()
+
+	err := db.Update("", func(tx *dbutil.Tx) error {
+		return hisDB.ParseBlock(tx, gb)
+	})
+	require.NoError(t, err)
+
+	addrA := "2RxP5N26GhDqHrP6SK45ZzEMSmSpeUeWxsS"
+	addrB := "222uMeCeL1PbkJGZJDgAz5sib2uisv9hYUm"
+	secKeyB := "62f4d675d991c41a2819d908a4fcf4ba44ff0c31564039e80508c9d68197f90c"
+
+	// genesis ==> A, B
+	// B ==> A, B
+	tds := []testData{
+		{
+			PreBlockHash: gb.HashHeader(),
+			Vin: txIn{
+				SigKey:   genSecret.Hex(),
+				Addr:     genAddress.String(),
+				TxID:     gb.Body.Transactions[0].Hash(),
+				BlockSeq: 0,
+			},
+			Vouts: []txOut{
+				{
+					ToAddr: addrA,
+					Coins:  10e6,
+					Hours:  100,
+				},
+				{
+					ToAddr: addrB,
+					Coins:  genCoins - 10e6,
+					Hours:  400,
+				},
+			},
+		},
+		{
+			Vin: txIn{
+				Addr:     addrB,
+				SigKey:   secKeyB,
+				BlockSeq: 1,
+			},
+			Vouts: []txOut{
+				{
+					ToAddr: addrA,
+					Coins:  10e6,
+					Hours:  100,
+				},
+				{
+					ToAddr: addrB,
+					Coins:  genCoins - 20e6,
+					Hours:  100,
+				},
+			},
+		},
+	}
+
+	var blocks []coin.Block
+	for i, td := range tds {
+		b, txn, err := addBlock(bc, td, incTime*(uint64(i)+1))
+		require.NoError(t, err)
+		blocks = append(blocks, *b)
+
+		if i+1 < len(tds) {
+			tds[i+1].Vin.TxID = txn.Hash()
+			tds[i+1].PreBlockHash = b.HashHeader()
+		}
+
+		err = db.Update("", func(tx *dbutil.Tx) error {
+			return hisDB.ParseBlock(tx, *b)
+		})
+		require.NoError(t, err)
+	}
+
+	outputHash := func(b coin.Block, i int) cipher.SHA256 {
+		return coin.CreateUnspents(b.Head, b.Body.Transactions[0])[i].Hash()
+	}
+	genesisOutput := outputHash(gb, 0)
+
+	cases := []struct {
+		addr    cipher.Address
+		seq     uint64
+		outputs []cipher.SHA256
+	}{
+		{
+			addr:    genAddress,
+			seq:     0,
+			outputs: []cipher.SHA256{genesisOutput},
+		},
+		{
+			addr: genAddress,
+			seq:  1,
+		},
+		{
+			addr: cipher.MustDecodeBase58Address(addrA),
+			seq:  0,
+		},
+		{
+			addr:    cipher.MustDecodeBase58Address(addrA),
+			seq:     1,
+			outputs: []cipher.SHA256{outputHash(blocks[0], 0)},
+		},
+		{
+			addr:    cipher.MustDecodeBase58Address(addrA),
+			seq:     2,
+			outputs: []cipher.SHA256{outputHash(blocks[0], 0), outputHash(blocks[1], 0)},
+		},
+		{
+			addr:    cipher.MustDecodeBase58Address(addrB),
+			seq:     1,
+			outputs: []cipher.SHA256{outputHash(blocks[0], 1)},
+		},
+		{
+			addr:    cipher.MustDecodeBase58Address(addrB),
+			seq:     2,
+			outputs: []cipher.SHA256{outputHash(blocks[1], 1)},
+		},
+		{
+			// A seq after the head returns the current outputs
+			addr:    cipher.MustDecodeBase58Address(addrB),
+			seq:     100,
+			outputs: []cipher.SHA256{outputHash(blocks[1], 1)},
+		},
+	}
+
+	checkOutputs := func(t *testing.T) {
+		for _, tc := range cases {
+			err := db.View("", func(tx *dbutil.Tx) error {
+				outs, err := hisDB.GetUnspentOutputsOfAddressAt(tx, tc.addr, tc.seq)
+				require.NoError(t, err)
+
+				var hashes []cipher.SHA256
+				for _, o := range outs {
+					hashes = append(hashes, o.Hash())
+				}
+				require.Equal(t, tc.outputs, hashes, "%s at %d", tc.addr, tc.seq)
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("parsed blocks", checkOutputs)
+
+	// The index is built from the outputs bucket for the databases created before it was added
+	t.Run("built index", func(t *testing.T) {
+		err := db.Update("", func(tx *dbutil.Tx) error {
+			if err := hisDB.addrBalances.reset(tx); err != nil {
+				return err
+			}
+			return hisDB.MaybeBuildIndexes(tx)
+		})
+		require.NoError(t, err)
+
+		checkOutputs(t)
+	})
+
+	// The outputs of the address are filtered if the index does not exist in a read-only database
+	t.Run("no index", func(t *testing.T) {
+		err := db.Update("", func(tx *dbutil.Tx) error {
+			return tx.DeleteBucket(AddressBalancesBkt)
+		})
+		require.NoError(t, err)
+
+		checkOutputs(t)
+	})
+}
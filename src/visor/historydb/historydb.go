@@ -6,9 +6,11 @@ package historydb
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/util/logging"
 	"github.com/skycoin/skycoin/src/visor/dbutil"
@@ -21,6 +23,7 @@ func CreateBuckets(tx *dbutil.Tx) error {
 	return dbutil.CreateBuckets(tx, [][]byte{
 		AddressTxnsBkt,
 		AddressUxBkt,
+		AddressBalancesBkt,
 		HistoryMetaBkt,
 		UxOutsBkt,
 		TransactionsBkt,
@@ -29,21 +32,23 @@ func CreateBuckets(tx *dbutil.Tx) error {
 
 // HistoryDB provides APIs for blockchain explorer
 type HistoryDB struct {
-	outputs  *uxOuts       // outputs bucket
-	txns     *transactions // transactions bucket
-	addrUx   *addressUx    // bucket which stores all UxOuts that address received
-	addrTxns *addressTxns  // address related transaction bucket
-	meta     *historyMeta  // stores history meta info
+	outputs      *uxOuts          // outputs bucket
+	txns         *transactions    // transactions bucket
+	addrUx       *addressUx       // bucket which stores all UxOuts that address received
+	addrTxns     *addressTxns     // address related transaction bucket
+	addrBalances *addressBalances // outputs created and spent by the addresses in each block
+	meta         *historyMeta     // stores history meta info
 }
 
 // New create HistoryDB instance
 func New() *HistoryDB {
 	return &HistoryDB{
-		outputs:  &uxOuts{},
-		txns:     &transactions{},
-		addrUx:   &addressUx{},
-		addrTxns: &addressTxns{},
-		meta:     &historyMeta{},
+		outputs:      &uxOuts{},
+		txns:         &transactions{},
+		addrUx:       &addressUx{},
+		addrTxns:     &addressTxns{},
+		addrBalances: &addressBalances{},
+		meta:         &historyMeta{},
 	}
 }
 
@@ -97,6 +102,10 @@ func (hd *HistoryDB) Erase(tx *dbutil.Tx) error {
 		return err
 	}
 
+	if err := hd.addrBalances.reset(tx); err != nil {
+		return err
+	}
+
 	if err := hd.outputs.reset(tx); err != nil {
 		return err
 	}
@@ -125,6 +134,19 @@ func (hd *HistoryDB) GetUxOuts(tx *dbutil.Tx, uxIDs []cipher.SHA256) ([]UxOut, e
 
 // ParseBlock builds indexes out of the block data
 func (hd *HistoryDB) ParseBlock(tx *dbutil.Tx, b coin.Block) error {
+	// The outputs created and spent by each address in the block
+	var addrs []cipher.Address
+	changes := make(map[cipher.Address]*addressBalanceChange)
+	addressChange := func(addr cipher.Address) *addressBalanceChange {
+		c, ok := changes[addr]
+		if !ok {
+			c = &addressBalanceChange{}
+			changes[addr] = c
+			addrs = append(addrs, addr)
+		}
+		return c
+	}
+
 	for _, t := range b.Body.Transactions {
 		txn := Transaction{
 			Txn:      t,
@@ -158,6 +180,9 @@ func (hd *HistoryDB) ParseBlock(tx *dbutil.Tx, b coin.Block) error {
 			if err := hd.addrTxns.add(tx, o.Out.Body.Address, spentTxnID); err != nil {
 				return err
 			}
+
+			c := addressChange(o.Out.Body.Address)
+			c.Spent = append(c.Spent, in)
 		}
 
 		// handle the tx out
@@ -176,6 +201,15 @@ func (hd *HistoryDB) ParseBlock(tx *dbutil.Tx, b coin.Block) error {
 			if err := hd.addrTxns.add(tx, ux.Body.Address, spentTxnID); err != nil {
 				return err
 			}
+
+			c := addressChange(ux.Body.Address)
+			c.Created = append(c.Created, ux.Hash())
+		}
+	}
+
+	for _, addr := range addrs {
+		if err := hd.addrBalances.put(tx, addr, b.Seq(), *changes[addr]); err != nil {
+			return err
 		}
 	}
 
@@ -224,6 +258,92 @@ func (hd HistoryDB) AddressSeen(tx *dbutil.Tx, addr cipher.Address) (bool, error
 	return hd.addrTxns.contains(tx, addr)
 }
 
+// GetUnspentOutputsOfAddressAt returns the outputs of an address that were unspent
+// after the block seq was executed
+func (hd HistoryDB) GetUnspentOutputsOfAddressAt(tx *dbutil.Tx, addr cipher.Address, seq uint64) ([]UxOut, error) {
+	// The database opened read-only may have been created before the address balances index
+	if !dbutil.Exists(tx, AddressBalancesBkt) {
+		outs, err := hd.GetOutputsForAddress(tx, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		var unspent []UxOut
+		for _, o := range outs {
+			if o.Out.Head.BkSeq <= seq && (o.SpentBlockSeq == 0 || o.SpentBlockSeq > seq) {
+				unspent = append(unspent, o)
+			}
+		}
+		return unspent, nil
+	}
+
+	hashes, err := hd.addrBalances.unspentAt(tx, addr, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd.outputs.getArray(tx, hashes)
+}
+
+// MaybeBuildIndexes builds the address balances index from the outputs bucket,
+// if the database was created before the index was added
+func (hd *HistoryDB) MaybeBuildIndexes(tx *dbutil.Tx) error {
+	empty, err := hd.addrBalances.isEmpty(tx)
+	if err != nil {
+		return err
+	} else if !empty {
+		return nil
+	}
+
+	logger.Info("Building the address balances index")
+
+	changes := make(map[string]*addressBalanceChange)
+	addressChange := func(addr cipher.Address, seq uint64) *addressBalanceChange {
+		k := string(addressBalanceKey(addr, seq))
+		c, ok := changes[k]
+		if !ok {
+			c = &addressBalanceChange{}
+			changes[k] = c
+		}
+		return c
+	}
+
+	if err := dbutil.ForEach(tx, UxOutsBkt, func(_, v []byte) error {
+		var o UxOut
+		if err := decodeUxOutExact(v, &o); err != nil {
+			return err
+		}
+
+		hash := o.Hash()
+		c := addressChange(o.Out.Body.Address, o.Out.Head.BkSeq)
+		c.Created = append(c.Created, hash)
+
+		if o.SpentBlockSeq != 0 {
+			c := addressChange(o.Out.Body.Address, o.SpentBlockSeq)
+			c.Spent = append(c.Spent, hash)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Puts the keys in order, bolt fills the pages of the bucket sequentially
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := dbutil.PutBucketValue(tx, AddressBalancesBkt, []byte(k), encoder.Serialize(*changes[k])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ForEachTxn traverses the transactions bucket
 func (hd HistoryDB) ForEachTxn(tx *dbutil.Tx, f func(cipher.SHA256, *Transaction) error) error {
 	return hd.txns.forEach(tx, f)
@@ -2,6 +2,7 @@ package visor
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 
@@ -13,6 +14,8 @@ var (
 	MetaBkt = []byte("db_meta")
 
 	versionKey = []byte("version")
+
+	verifiedAtKey = []byte("verified_at")
 )
 
 // GetDBVersion returns the saved DB version
@@ -69,3 +72,40 @@ func SetDBVersion(db *dbutil.DB, version semver.Version) error {
 		return dbutil.PutBucketValue(tx, MetaBkt, versionKey, []byte(version.String()))
 	})
 }
+
+// GetDBVerifiedAt returns the time of the last successful CheckDatabase of the DB.
+// Returns a zero time if the DB has not been checked.
+func GetDBVerifiedAt(db *dbutil.DB) (time.Time, error) {
+	var t time.Time
+	if err := db.View("GetDBVerifiedAt", func(tx *dbutil.Tx) error {
+		v, err := dbutil.GetBucketValue(tx, MetaBkt, verifiedAtKey)
+		if err != nil {
+			switch err.(type) {
+			case dbutil.ErrBucketNotExist:
+				return nil
+			default:
+				return err
+			}
+		} else if v == nil {
+			return nil
+		}
+
+		t = time.Unix(int64(dbutil.Btoi(v)), 0)
+		return nil
+	}); err != nil {
+		return time.Time{}, err
+	}
+
+	return t, nil
+}
+
+// SetDBVerifiedAt sets the time of the last successful CheckDatabase of the DB
+func SetDBVerifiedAt(db *dbutil.DB, t time.Time) error {
+	return db.Update("SetDBVerifiedAt", func(tx *dbutil.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(MetaBkt); err != nil {
+			return err
+		}
+
+		return dbutil.PutBucketValue(tx, MetaBkt, verifiedAtKey, dbutil.Itob(uint64(t.Unix())))
+	})
+}
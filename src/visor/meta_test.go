@@ -2,6 +2,7 @@ package visor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/stretchr/testify/require"
@@ -43,3 +44,21 @@ func TestGetSetDBVersion(t *testing.T) {
 	err = SetDBVersion(db, x)
 	testutil.RequireError(t, err, "SetDBVersion cannot regress version from 0.26.0 to 0.25.0")
 }
+
+func TestGetSetDBVerifiedAt(t *testing.T) {
+	db, shutdown := testutil.PrepareDB(t)
+	defer shutdown()
+
+	// Not verified yet
+	v, err := GetDBVerifiedAt(db)
+	require.NoError(t, err)
+	require.True(t, v.IsZero())
+
+	x := time.Unix(1571109310, 0)
+	err = SetDBVerifiedAt(db, x)
+	require.NoError(t, err)
+
+	v, err = GetDBVerifiedAt(db)
+	require.NoError(t, err)
+	require.True(t, x.Equal(v))
+}
@@ -136,6 +136,29 @@ func (_m *MockHistoryer) GetTransactionHashesForAddresses(tx *dbutil.Tx, address
 	return r0, r1
 }
 
+// GetUnspentOutputsOfAddressAt provides a mock function with given fields: tx, address, seq
+func (_m *MockHistoryer) GetUnspentOutputsOfAddressAt(tx *dbutil.Tx, address cipher.Address, seq uint64) ([]historydb.UxOut, error) {
+	ret := _m.Called(tx, address, seq)
+
+	var r0 []historydb.UxOut
+	if rf, ok := ret.Get(0).(func(*dbutil.Tx, cipher.Address, uint64) []historydb.UxOut); ok {
+		r0 = rf(tx, address, seq)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]historydb.UxOut)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dbutil.Tx, cipher.Address, uint64) error); ok {
+		r1 = rf(tx, address, seq)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUxOuts provides a mock function with given fields: tx, uxids
 func (_m *MockHistoryer) GetUxOuts(tx *dbutil.Tx, uxids []cipher.SHA256) ([]historydb.UxOut, error) {
 	ret := _m.Called(tx, uxids)
@@ -59,6 +59,20 @@ func (_m *MockUnspentPooler) Contains(_a0 *dbutil.Tx, _a1 cipher.SHA256) (bool,
 	return r0, r1
 }
 
+// ForEachRichlistEntry provides a mock function with given fields: _a0, _a1
+func (_m *MockUnspentPooler) ForEachRichlistEntry(_a0 *dbutil.Tx, _a1 func(cipher.Address, uint64) error) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dbutil.Tx, func(cipher.Address, uint64) error) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: _a0, _a1
 func (_m *MockUnspentPooler) Get(_a0 *dbutil.Tx, _a1 cipher.SHA256) (*coin.UxOut, error) {
 	ret := _m.Called(_a0, _a1)
@@ -17,6 +17,25 @@ type RichlistBalance struct {
 // Richlist contains RichlistBalances
 type Richlist []RichlistBalance
 
+// RichlistParams are the parameters of a richlist query
+type RichlistParams struct {
+	// IncludeDistribution includes the distribution addresses in the richlist
+	IncludeDistribution bool
+	// Exclude are addresses left out of the richlist
+	Exclude []cipher.Address
+	// Offset is the number of richlist entries skipped
+	Offset uint64
+	// Limit is the maximum number of richlist entries returned, all entries if 0
+	Limit uint64
+}
+
+// RichlistPage is a page of the richlist
+type RichlistPage struct {
+	Richlist Richlist
+	// Total is the number of entries of the richlist, without the excluded addresses
+	Total uint64
+}
+
 // NewRichlist create Richlist via unspent outputs map
 func NewRichlist(allAccounts map[cipher.Address]uint64, lockedAddrs map[cipher.Address]struct{}) (Richlist, error) {
 	richlist := make(Richlist, 0, len(allAccounts))
@@ -1,11 +1,18 @@
 package visor
 
 import (
+	"bytes"
+	"sort"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
 )
 
 func getLockedMap(distributionAddresses [4]cipher.Address) map[cipher.Address]struct{} {
@@ -122,3 +129,198 @@ func TestRichlist(t *testing.T) {
 		})
 	}
 }
+
+func TestVisorGetRichlist(t *testing.T) {
+	otherAddresses := [4]cipher.Address{
+		cipher.MustDecodeBase58Address("2cmpPv9PJfKFStekrKZXBnAfLKE6cB7qMrS"),
+		cipher.MustDecodeBase58Address("jhLw4EXNn2E7zVjrmi8fGsATZfRnAXfqRj"),
+		cipher.MustDecodeBase58Address("R7zjFhmW3KqGz6r92VFpJTpRWCzaXSokYb"),
+		cipher.MustDecodeBase58Address("JFQRvKXBoTt6D8aiFVrGquemzbrQDGKTAR"),
+	}
+
+	distributionAddresses := [4]cipher.Address{
+		cipher.MustDecodeBase58Address("DniB7KqDRNx8CjM6vruaKwbQPgWj1GSj5t"),
+		cipher.MustDecodeBase58Address("FbJuRez3RKpYsTSYTVyAQt146vzcFNkqpU"),
+		cipher.MustDecodeBase58Address("2mxNdCnUd7vF1uSpRhSDMEhZHKAyL9r1Uys"),
+		cipher.MustDecodeBase58Address("uBcaMg2vGpy45K7NVsRGmuNXQdaB8kgHfM"),
+	}
+
+	// The first distribution address is unlocked
+	dist := params.Distribution{
+		InitialUnlockedCount: 1,
+	}
+	for _, a := range distributionAddresses {
+		dist.Addresses = append(dist.Addresses, a.String())
+	}
+
+	accounts := getAllAccounts(distributionAddresses, otherAddresses)
+	lockedAddrs := getLockedMap(distributionAddresses)
+	delete(lockedAddrs, distributionAddresses[0])
+
+	allRichlist, err := NewRichlist(accounts, lockedAddrs)
+	require.NoError(t, err)
+
+	// The entries of the richlist index, sorted by coins then by address bytes
+	type entry struct {
+		addr  cipher.Address
+		coins uint64
+	}
+	var entries []entry
+	for addr, coins := range accounts {
+		entries = append(entries, entry{addr, coins})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].coins == entries[j].coins {
+			return bytes.Compare(entries[i].addr.Bytes(), entries[j].addr.Bytes()) < 0
+		}
+		return entries[i].coins > entries[j].coins
+	})
+
+	distributionMap := getLockedMap(distributionAddresses)
+
+	cases := []struct {
+		name    string
+		params  RichlistParams
+		exclude map[cipher.Address]struct{}
+		start   int
+		end     int
+		// maxCalls is the maximum number of index entries iterated
+		maxCalls int
+	}{
+		{
+			name: "include distribution",
+			params: RichlistParams{
+				IncludeDistribution: true,
+			},
+			exclude:  map[cipher.Address]struct{}{},
+			start:    0,
+			end:      8,
+			maxCalls: 8,
+		},
+		{
+			name:     "without distribution",
+			exclude:  distributionMap,
+			start:    0,
+			end:      4,
+			maxCalls: 8,
+		},
+		{
+			name: "limit",
+			params: RichlistParams{
+				IncludeDistribution: true,
+				Limit:               3,
+			},
+			exclude:  map[cipher.Address]struct{}{},
+			start:    0,
+			end:      3,
+			maxCalls: 4,
+		},
+		{
+			name: "offset and limit within entries with the same coins",
+			params: RichlistParams{
+				IncludeDistribution: true,
+				Offset:              4,
+				Limit:               2,
+			},
+			exclude:  map[cipher.Address]struct{}{},
+			start:    4,
+			end:      6,
+			maxCalls: 8,
+		},
+		{
+			name: "offset past the end",
+			params: RichlistParams{
+				IncludeDistribution: true,
+				Offset:              10,
+				Limit:               2,
+			},
+			exclude:  map[cipher.Address]struct{}{},
+			start:    0,
+			end:      0,
+			maxCalls: 8,
+		},
+		{
+			name: "exclude",
+			params: RichlistParams{
+				IncludeDistribution: true,
+				Exclude: []cipher.Address{
+					otherAddresses[2],
+					otherAddresses[0],
+					otherAddresses[0],
+					testutil.MakeAddress(),
+				},
+				Limit: 2,
+			},
+			exclude: map[cipher.Address]struct{}{
+				otherAddresses[2]: {},
+				otherAddresses[0]: {},
+			},
+			start:    0,
+			end:      2,
+			maxCalls: 5,
+		},
+	}
+
+	matchDBTx := mock.MatchedBy(func(tx *dbutil.Tx) bool {
+		return true
+	})
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, shutdown := testutil.PrepareDB(t)
+			defer shutdown()
+
+			bc := &MockBlockchainer{}
+			unspent := &MockUnspentPooler{}
+
+			bc.On("Unspent").Return(unspent)
+			unspent.On("AddressCount", matchDBTx).Return(uint64(len(accounts)), nil)
+			unspent.On("GetUnspentHashesOfAddrs", matchDBTx, mock.Anything).Return(func(_ *dbutil.Tx, addrs []cipher.Address) blockdb.AddressHashes {
+				addrHashes := make(blockdb.AddressHashes, len(addrs))
+				for _, a := range addrs {
+					if _, ok := accounts[a]; ok {
+						addrHashes[a] = []cipher.SHA256{testutil.RandSHA256(t)}
+					} else {
+						addrHashes[a] = nil
+					}
+				}
+				return addrHashes
+			}, nil)
+
+			var calls int
+			unspent.On("ForEachRichlistEntry", matchDBTx, mock.Anything).Return(func(_ *dbutil.Tx, f func(cipher.Address, uint64) error) error {
+				for _, e := range entries {
+					calls++
+					if err := f(e.addr, e.coins); err != nil {
+						if err == blockdb.ErrStopIteration {
+							return nil
+						}
+						return err
+					}
+				}
+				return nil
+			})
+
+			v := &Visor{
+				Config: Config{
+					Distribution: dist,
+				},
+				blockchain: bc,
+				db:         db,
+			}
+
+			page, err := v.GetRichlist(tc.params)
+			require.NoError(t, err)
+
+			filtered := allRichlist.FilterAddresses(tc.exclude)
+			require.Equal(t, uint64(len(filtered)), page.Total)
+
+			expected := Richlist{}
+			if tc.end != 0 {
+				expected = filtered[tc.start:tc.end]
+			}
+			require.Equal(t, expected, page.Richlist)
+			require.True(t, calls <= tc.maxCalls, "%d > %d", calls, tc.maxCalls)
+		})
+	}
+}
@@ -40,12 +40,16 @@ var (
 	ErrZeroPageNum = errors.New("page number must be greater than 0")
 	// ErrMaxTxnPageSize will be returned when page size is greater than MaxTxnPageSize
 	ErrMaxTxnPageSize = fmt.Errorf("transaction page size must be not greater than %d", MaxTxnPageSize)
+	// ErrTxnCursorNotFound will be returned when the cursor transaction does not match the filters,
+	// or is no longer in the unconfirmed pool
+	ErrTxnCursorNotFound = errors.New("cursor transaction not found")
 )
 
 // PageIndex represents
 type PageIndex struct {
-	size uint64 // Page size
-	n    uint64 // Page number, start from 1
+	size   uint64         // Page size
+	n      uint64         // Page number, start from 1
+	cursor *cipher.SHA256 // Hash of the last transaction of the previous page, replaces the page number
 }
 
 // NewPageIndex creates a page
@@ -65,6 +69,21 @@ func NewPageIndex(size uint64, pageN uint64) (*PageIndex, error) {
 	return &PageIndex{size: size, n: pageN}, nil
 }
 
+// NewCursorPageIndex creates a page of the transactions that follow the cursor transaction in the sort order.
+// The cursor is the NextCursor of the previous page. Unlike page numbers, cursors are stable
+// when new transactions are added before the page in the sort order.
+func NewCursorPageIndex(size uint64, cursor cipher.SHA256) (*PageIndex, error) {
+	if size == 0 {
+		return nil, ErrZeroPageSize
+	}
+
+	if size > MaxTxnPageSize {
+		return nil, ErrMaxTxnPageSize
+	}
+
+	return &PageIndex{size: size, cursor: &cursor}, nil
+}
+
 // Cal calculate the slice indexes
 func (p PageIndex) Cal(n uint64) (start uint64, end uint64, totalPages uint64, err error) {
 	if p.size == 0 {
@@ -98,11 +117,42 @@ func (p PageIndex) Size() uint64 {
 	return p.size
 }
 
-// PageNum returns the page num
+// PageNum returns the page num, 0 for cursor pages
 func (p PageIndex) PageNum() uint64 {
 	return p.n
 }
 
+// Cursor returns the cursor of the page, nil if the page is selected by its number
+func (p PageIndex) Cursor() *cipher.SHA256 {
+	return p.cursor
+}
+
+// calAfter calculates the slice indexes of the page that follows the cursor at index i
+func (p PageIndex) calAfter(i, n uint64) (start uint64, end uint64, totalPages uint64) {
+	totalPages = n / p.size
+	if n%p.size != 0 {
+		totalPages++
+	}
+
+	start = i + 1
+	end = start + p.size
+	if end > n {
+		end = n
+	}
+
+	return
+}
+
+// TxnPage describes a page of transactions returned by a paginated query
+type TxnPage struct {
+	// TotalPages is the number of pages of the query
+	TotalPages uint64
+	// Total is the number of transactions that match the filters, on all pages
+	Total uint64
+	// NextCursor is the cursor of the next page, nil if this is the last page
+	NextCursor *cipher.SHA256
+}
+
 type txnHashConfirm struct {
 	hash        cipher.SHA256
 	seq         uint64
@@ -189,6 +239,10 @@ func (s txnHashesContainer) Filter(tx *dbutil.Tx, flts []TxFilter, getTxn txnGet
 	newTxnsHashes := newTxnHashesContainer()
 
 	for _, item := range s.items {
+		if err := tx.Err(); err != nil {
+			return nil, err
+		}
+
 		txn, err := getTxn(tx, item)
 		if err != nil {
 			return nil, err
@@ -228,26 +282,68 @@ func (s *txnHashesContainer) Update(f txnContainerUpdateFunc) {
 	}
 }
 
-func (s txnHashesContainer) Pagination(page *PageIndex) (*txnHashesContainer, uint64, error) {
+func (s txnHashesContainer) indexOf(hash cipher.SHA256) (uint64, bool) {
+	if _, ok := s.m[hash]; !ok {
+		return 0, false
+	}
+
+	for i, item := range s.items {
+		if item.hash == hash {
+			return uint64(i), true
+		}
+	}
+
+	return 0, false
+}
+
+func (s txnHashesContainer) Pagination(page *PageIndex) (*txnHashesContainer, TxnPage, error) {
 	if page == nil {
 		// Returns all transactions if page index is nil; total page is 1.
-		return &s, 1, nil
+		return &s, TxnPage{
+			TotalPages: 1,
+			Total:      s.Len(),
+		}, nil
 	}
-	start, end, total, err := page.Cal(s.Len())
-	if err != nil {
-		return nil, 0, err
+
+	var start, end, totalPages uint64
+	if cursor := page.Cursor(); cursor != nil {
+		i, ok := s.indexOf(*cursor)
+		if !ok {
+			return nil, TxnPage{}, ErrTxnCursorNotFound
+		}
+		start, end, totalPages = page.calAfter(i, s.Len())
+	} else {
+		var err error
+		start, end, totalPages, err = page.Cal(s.Len())
+		if err != nil {
+			return nil, TxnPage{}, err
+		}
 	}
 
 	newTxnHashes := newTxnHashesContainer()
 	for _, item := range s.items[start:end] {
 		newTxnHashes.AddItem(item)
 	}
-	return newTxnHashes, total, nil
+
+	p := TxnPage{
+		TotalPages: totalPages,
+		Total:      s.Len(),
+	}
+	if end > start && end < s.Len() {
+		next := s.items[end-1].hash
+		p.NextCursor = &next
+	}
+
+	return newTxnHashes, p, nil
 }
 
 func (s txnHashesContainer) ToTransactions(tx *dbutil.Tx, f txnGetFunc) ([]Transaction, error) {
 	var txns []Transaction
 	for _, item := range s.items {
+		if err := tx.Err(); err != nil {
+			return nil, err
+		}
+
 		txn, err := f(tx, item)
 		if err != nil {
 			return nil, err
@@ -263,7 +359,7 @@ type transactionModel struct {
 	blockchain  Blockchainer
 }
 
-func (tm transactionModel) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error) {
+func (tm transactionModel) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error) {
 	var otherFlts []TxFilter
 	var txnGetter transactionsGetter = newAllTxnsGetter(tm)
 	for _, f := range flts {
@@ -283,19 +379,19 @@ func (tm transactionModel) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order
 }
 
 type transactionsGetter interface {
-	GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error)
+	GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error)
 }
 
 type confirmedTxnsGetter struct {
 	transactionModel
 }
 
-func (ct confirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error) {
+func (ct confirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error) {
 	addrs, otherFlts := getAddrsFromFlts(flts)
 
 	txnsHashesCon, err := ct.getTxnsHashes(tx, addrs)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	getTxn := func(tx *dbutil.Tx, item txnHashConfirm) (*Transaction, error) {
@@ -305,26 +401,26 @@ func (ct confirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, or
 	// Apply remaining filters
 	txnsHashesCon, err = txnsHashesCon.Filter(tx, otherFlts, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	// Sort the transaction hashes
 	if err := txnsHashesCon.Sort(order); err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	var totalPages uint64
-	txnsHashesCon, totalPages, err = txnsHashesCon.Pagination(page)
+	var txnPage TxnPage
+	txnsHashesCon, txnPage, err = txnsHashesCon.Pagination(page)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	txns, err := txnsHashesCon.ToTransactions(tx, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	return txns, totalPages, nil
+	return txns, txnPage, nil
 }
 
 func (ct confirmedTxnsGetter) getTransaction(tx *dbutil.Tx, hash cipher.SHA256) (*Transaction, error) {
@@ -401,6 +497,10 @@ func (ct confirmedTxnsGetter) getTxnsHashes(tx *dbutil.Tx, addrs []cipher.Addres
 	}
 
 	for _, hash := range hashes {
+		if err := tx.Err(); err != nil {
+			return nil, err
+		}
+
 		hisTxn, err := ct.history.GetTransaction(tx, hash)
 		if err != nil {
 			return nil, err
@@ -416,12 +516,12 @@ type unconfirmedTxnsGetter struct {
 	transactionModel
 }
 
-func (uct unconfirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error) {
+func (uct unconfirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error) {
 	addrs, otherFlts := getAddrsFromFlts(flts)
 
 	txnHashesCon, err := uct.getTxnsHashes(tx, addrs)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	getTxn := func(tx *dbutil.Tx, item txnHashConfirm) (*Transaction, error) {
@@ -430,25 +530,25 @@ func (uct unconfirmedTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter,
 
 	txnHashesCon, err = txnHashesCon.Filter(tx, otherFlts, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	if err := txnHashesCon.Sort(order); err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	var totalPage uint64
-	txnHashesCon, totalPage, err = txnHashesCon.Pagination(page)
+	var txnPage TxnPage
+	txnHashesCon, txnPage, err = txnHashesCon.Pagination(page)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	txns, err := txnHashesCon.ToTransactions(tx, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	return txns, totalPage, nil
+	return txns, txnPage, nil
 }
 
 func (uct unconfirmedTxnsGetter) getTransaction(tx *dbutil.Tx, hash cipher.SHA256) (*Transaction, error) {
@@ -504,11 +604,11 @@ func newAllTxnsGetter(tm transactionModel) *fullTxnsGetter {
 	}
 }
 
-func (ft fullTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error) {
+func (ft fullTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error) {
 	addrs, otherFlts := getAddrsFromFlts(flts)
 	txnsHashesCon, err := ft.getTxnsHashes(tx, addrs)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	getTxn := func(tx *dbutil.Tx, item txnHashConfirm) (*Transaction, error) {
@@ -520,25 +620,25 @@ func (ft fullTxnsGetter) GetTransactions(tx *dbutil.Tx, flts []TxFilter, order S
 
 	txnsHashesCon, err = txnsHashesCon.Filter(tx, otherFlts, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	if err := txnsHashesCon.Sort(order); err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	var totalPages uint64
-	txnsHashesCon, totalPages, err = txnsHashesCon.Pagination(page)
+	var txnPage TxnPage
+	txnsHashesCon, txnPage, err = txnsHashesCon.Pagination(page)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
 	txns, err := txnsHashesCon.ToTransactions(tx, getTxn)
 	if err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	return txns, totalPages, nil
+	return txns, txnPage, nil
 }
 
 func (ft fullTxnsGetter) getTxnsHashes(tx *dbutil.Tx, addrs []cipher.Address) (*txnHashesContainer, error) {
@@ -1,7 +1,14 @@
 package visor
 
 import (
+	"context"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
 )
 
 func TestPage_Cal(t *testing.T) {
@@ -214,3 +221,104 @@ func TestPage_Cal(t *testing.T) {
 		})
 	}
 }
+
+func TestTxnHashesContainerPagination(t *testing.T) {
+	c := newTxnHashesContainer()
+	hashes := make([]cipher.SHA256, 25)
+	for i := range hashes {
+		hashes[i] = testutil.RandSHA256(t)
+		c.Add(hashes[i], true, uint64(i))
+	}
+
+	// All transactions without a page
+	all, p, err := c.Pagination(nil)
+	require.NoError(t, err)
+	require.Equal(t, c.Len(), all.Len())
+	require.Equal(t, TxnPage{TotalPages: 1, Total: 25}, p)
+
+	page, err := NewPageIndex(10, 1)
+	require.NoError(t, err)
+	first, p, err := c.Pagination(page)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), first.Len())
+	require.Equal(t, uint64(3), p.TotalPages)
+	require.Equal(t, uint64(25), p.Total)
+	require.NotNil(t, p.NextCursor)
+	require.Equal(t, hashes[9], *p.NextCursor)
+
+	// Follow the cursors to the last page, a transaction added before the cursor does not shift the pages
+	c.Add(testutil.RandSHA256(t), true, 0)
+	require.NoError(t, c.Sort(AscOrder))
+
+	page, err = NewCursorPageIndex(10, *p.NextCursor)
+	require.NoError(t, err)
+	second, p, err := c.Pagination(page)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), second.Len())
+	require.Equal(t, hashes[10], second.items[0].hash)
+	require.Equal(t, uint64(26), p.Total)
+	require.Equal(t, uint64(3), p.TotalPages)
+	require.Equal(t, hashes[19], *p.NextCursor)
+
+	page, err = NewCursorPageIndex(10, *p.NextCursor)
+	require.NoError(t, err)
+	last, p, err := c.Pagination(page)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), last.Len())
+	require.Equal(t, hashes[20], last.items[0].hash)
+	require.Nil(t, p.NextCursor)
+
+	// The last page of page numbers has no next cursor either
+	page, err = NewPageIndex(13, 2)
+	require.NoError(t, err)
+	_, p, err = c.Pagination(page)
+	require.NoError(t, err)
+	require.Nil(t, p.NextCursor)
+
+	page, err = NewCursorPageIndex(10, testutil.RandSHA256(t))
+	require.NoError(t, err)
+	_, _, err = c.Pagination(page)
+	require.Equal(t, ErrTxnCursorNotFound, err)
+
+	_, err = NewCursorPageIndex(0, hashes[0])
+	require.Equal(t, ErrZeroPageSize, err)
+	_, err = NewCursorPageIndex(MaxTxnPageSize+1, hashes[0])
+	require.Equal(t, ErrMaxTxnPageSize, err)
+}
+
+func TestTxnHashesContainerFilterContext(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	c := newTxnHashesContainer()
+	for i := 0; i < 5; i++ {
+		c.Add(testutil.RandSHA256(t), true, uint64(i))
+	}
+
+	flts := []TxFilter{NewConfirmedTxFilter(true)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	err := db.ViewContext(ctx, "TestTxnHashesContainerFilterContext", func(tx *dbutil.Tx) error {
+		_, err := c.Filter(tx, flts, func(tx *dbutil.Tx, item txnHashConfirm) (*Transaction, error) {
+			// The client disconnects while the transactions are filtered
+			n++
+			if n == 2 {
+				cancel()
+			}
+			return &Transaction{Status: TransactionStatus{Confirmed: true}}, nil
+		})
+		return err
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 2, n)
+
+	// The transaction of a done context is not started
+	err = db.ViewContext(ctx, "TestTxnHashesContainerFilterContext", func(tx *dbutil.Tx) error {
+		t.Fatal("transaction started")
+		return nil
+	})
+	require.Equal(t, context.Canceled, err)
+}
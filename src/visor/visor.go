@@ -11,9 +11,11 @@ Wallets are conceptually a second database.
 package visor
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"sort"
 	"time"
 
 	"github.com/skycoin/skycoin/src/cipher"
@@ -42,6 +44,7 @@ type Visor struct {
 	wallets     *wallet.Service
 	txns        transactionsGetter
 	tf          wallet.TransactionsFinder
+	events      *eventBus
 }
 
 // New creates a Visor for managing the blockchain database
@@ -117,6 +120,7 @@ func New(c Config, db *dbutil.DB, wltServ *wallet.Service) (*Visor, error) {
 		history:     history,
 		wallets:     wltServ,
 		txns:        &txns,
+		events:      newEventBus(),
 	}
 
 	v.tf = newTransactionsFinder(v)
@@ -161,7 +165,7 @@ func initHistory(tx *dbutil.Tx, bc *Blockchain, history *historydb.HistoryDB) er
 	}
 
 	if !shouldReset {
-		return nil
+		return history.MaybeBuildIndexes(tx)
 	}
 
 	logger.Info("Resetting historyDB")
@@ -257,6 +261,12 @@ func (vs *Visor) StartedAt() time.Time {
 	return vs.startedAt
 }
 
+// GetDBVerifiedAt returns the time of the last successful integrity check of the database,
+// or a zero time if it was never checked
+func (vs *Visor) GetDBVerifiedAt() (time.Time, error) {
+	return GetDBVerifiedAt(vs.db)
+}
+
 // RefreshUnconfirmed checks unconfirmed txns against the blockchain and returns
 // all transaction that turn to valid.
 func (vs *Visor) RefreshUnconfirmed() ([]cipher.SHA256, error) {
@@ -394,6 +404,9 @@ func (vs *Visor) CreateAndExecuteBlock() (coin.SignedBlock, error) {
 
 		return vs.executeSignedBlock(tx, sb)
 	})
+	if err == nil {
+		vs.events.publishBlock(sb)
+	}
 
 	return sb, err
 }
@@ -425,17 +438,62 @@ func (vs *Visor) VerifyBlock(b coin.SignedBlock) error {
 // ExecuteSignedBlock adds a block to the blockchain, or returns error.
 // Blocks must be executed in sequence, and be signed by a block publisher node.
 func (vs *Visor) ExecuteSignedBlock(b coin.SignedBlock) error {
-	return vs.db.Update("ExecuteSignedBlock", func(tx *dbutil.Tx) error {
+	if err := vs.db.Update("ExecuteSignedBlock", func(tx *dbutil.Tx) error {
 		return vs.executeSignedBlock(tx, b)
-	})
+	}); err != nil {
+		return err
+	}
+
+	vs.events.publishBlock(b)
+	return nil
+}
+
+// ExecuteSignedBlocks adds blocks to the blockchain in sequence and returns the number of blocks executed.
+// The signatures of all blocks are verified at once with cipher.VerifySignaturesBatch before the blocks are executed,
+// which is faster than verifying them one by one when syncing many blocks.
+// If a block signature is invalid, the blocks before it are executed and the signature error is returned.
+// Each block is executed in its own db transaction.
+func (vs *Visor) ExecuteSignedBlocks(blocks []coin.SignedBlock) (int, error) {
+	pubkeys := make([]cipher.PubKey, len(blocks))
+	sigs := make([]cipher.Sig, len(blocks))
+	hashes := make([]cipher.SHA256, len(blocks))
+	for i, b := range blocks {
+		pubkeys[i] = vs.Config.BlockchainPubkey
+		sigs[i] = b.Sig
+		hashes[i] = b.HashHeader()
+	}
+
+	valid := blocks
+	sigErr := cipher.VerifySignaturesBatch(pubkeys, sigs, hashes)
+	if sigErr != nil {
+		batchErr, ok := sigErr.(cipher.BatchVerifyError)
+		if !ok {
+			return 0, sigErr
+		}
+		valid = blocks[:batchErr.Index]
+		sigErr = batchErr.Err
+	}
+
+	for i, b := range valid {
+		if err := vs.ExecuteSignedBlockUnsafe(b); err != nil {
+			return i, err
+		}
+	}
+
+	return len(valid), sigErr
 }
 
 // ExecuteSignedBlockUnsafe adds block to the blockchain, or returns error.
 // Blocks must be executed in sequence. Block signature is not verified.
 func (vs *Visor) ExecuteSignedBlockUnsafe(b coin.SignedBlock) error {
-	return vs.db.Update("ExecuteSignedBlockUnsafe", func(tx *dbutil.Tx) error {
+	if err := vs.db.Update("ExecuteSignedBlockUnsafe", func(tx *dbutil.Tx) error {
 		return vs.executeSignedBlockUnsafe(tx, b)
-	})
+	}); err != nil {
+		return err
+	}
+
+	vs.events.publishBlock(b)
+	return nil
 }
 
 // executeSignedBlock adds a block to the blockchain, or returns error.
@@ -710,11 +768,11 @@ func (vs *Visor) GetBlocksVerbose(seqs []uint64) ([]coin.SignedBlock, [][][]Tran
 }
 
 // GetBlocksInRange returns multiple blocks between start and end, including both start and end.
-// Returns the empty slice if unable to fulfill request.
-func (vs *Visor) GetBlocksInRange(start, end uint64) ([]coin.SignedBlock, error) {
+// Returns the empty slice if unable to fulfill request. Aborts with the error of ctx when ctx is done.
+func (vs *Visor) GetBlocksInRange(ctx context.Context, start, end uint64) ([]coin.SignedBlock, error) {
 	var blocks []coin.SignedBlock
 
-	if err := vs.db.View("GetBlocksInRange", func(tx *dbutil.Tx) error {
+	if err := vs.db.ViewContext(ctx, "GetBlocksInRange", func(tx *dbutil.Tx) error {
 		var err error
 		blocks, err = vs.blockchain.GetBlocksInRange(tx, start, end)
 		return err
@@ -727,12 +785,12 @@ func (vs *Visor) GetBlocksInRange(start, end uint64) ([]coin.SignedBlock, error)
 
 // GetBlocksInRangeVerbose returns multiple blocks between start and end, including both start and end.
 // Also returns the verbose transaction input data for transactions in these blocks.
-// Returns the empty slice if unable to fulfill request.
-func (vs *Visor) GetBlocksInRangeVerbose(start, end uint64) ([]coin.SignedBlock, [][][]TransactionInput, error) {
+// Returns the empty slice if unable to fulfill request. Aborts with the error of ctx when ctx is done.
+func (vs *Visor) GetBlocksInRangeVerbose(ctx context.Context, start, end uint64) ([]coin.SignedBlock, [][][]TransactionInput, error) {
 	var blocks []coin.SignedBlock
 	var inputs [][][]TransactionInput
 
-	if err := vs.db.View("GetBlocksInRangeVerbose", func(tx *dbutil.Tx) error {
+	if err := vs.db.ViewContext(ctx, "GetBlocksInRangeVerbose", func(tx *dbutil.Tx) error {
 		var err error
 		blocks, inputs, err = vs.getBlocksVerbose(tx, func(tx *dbutil.Tx) ([]coin.SignedBlock, error) {
 			return vs.blockchain.GetBlocksInRange(tx, start, end)
@@ -790,6 +848,10 @@ func (vs *Visor) getBlocksVerbose(tx *dbutil.Tx, getBlocks func(*dbutil.Tx) ([]c
 
 	inputs := make([][][]TransactionInput, len(blocks))
 	for i, b := range blocks {
+		if err := tx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		blockInputs, err := vs.getBlockInputs(tx, &b)
 		if err != nil {
 			return nil, nil, err
@@ -818,6 +880,10 @@ func (vs *Visor) InjectForeignTransaction(txn coin.Transaction) (bool, *ErrTxnVi
 		return false, nil, err
 	}
 
+	if !known {
+		vs.events.publishUnconfirmedTxn(txn)
+	}
+
 	return known, softErr, nil
 }
 
@@ -838,9 +904,27 @@ func (vs *Visor) InjectUserTransaction(txn coin.Transaction) (bool, *coin.Signed
 		return false, nil, nil, err
 	}
 
+	if !known {
+		vs.events.publishUnconfirmedTxn(txn)
+	}
+
 	return known, head, inputs, nil
 }
 
+// PublishUnconfirmedTxnInjected emits an EventUnconfirmedTxnInjected event for a transaction that was
+// injected with InjectUserTransactionTx, once the database transaction is committed.
+// This method is only exported for use by the daemon gateway's InjectBroadcastTransaction method.
+func (vs *Visor) PublishUnconfirmedTxnInjected(txn coin.Transaction) {
+	vs.events.publishUnconfirmedTxn(txn)
+}
+
+// SubscribeBlockchain subscribes to the blockchain events, the events are delivered on the returned channel.
+// Events are dropped if the channel buffer is full, a bufferSize of 0 uses DefaultEventBufferSize.
+// The returned function unsubscribes and closes the channel.
+func (vs *Visor) SubscribeBlockchain(bufferSize int) (<-chan Event, func()) {
+	return vs.events.subscribe(bufferSize)
+}
+
 // InjectUserTransactionTx records a coin.Transaction to the UnconfirmedTransactionPool if the txn is not
 // already in the blockchain.
 // The bool return value is whether or not the transaction was already in the pool.
@@ -1009,35 +1093,39 @@ func NewConfirmedTxFilter(isConfirmed bool) TxFilter {
 }
 
 // GetTransactions returns transactions that can pass the filters with page.
-// If no filters is provided, returns all transactions.
-func (vs *Visor) GetTransactions(flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, uint64, error) {
+// If no filters is provided, returns all transactions. Aborts with the error of ctx when ctx is done.
+func (vs *Visor) GetTransactions(ctx context.Context, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, TxnPage, error) {
 	var txns []Transaction
-	var pages uint64
-	if err := vs.db.View("GetTransactions", func(tx *dbutil.Tx) error {
+	var txnPage TxnPage
+	if err := vs.db.ViewContext(ctx, "GetTransactions", func(tx *dbutil.Tx) error {
 		var err error
-		txns, pages, err = vs.txns.GetTransactions(tx, flts, order, page)
+		txns, txnPage, err = vs.txns.GetTransactions(tx, flts, order, page)
 		return err
 	}); err != nil {
-		return nil, 0, err
+		return nil, TxnPage{}, err
 	}
 
-	return txns, pages, nil
+	return txns, txnPage, nil
 }
 
 // GetTransactionsWithInputs is the same as GetTransactions but also returns verbose transaction input data
-func (vs *Visor) GetTransactionsWithInputs(flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, [][]TransactionInput, uint64, error) {
+func (vs *Visor) GetTransactionsWithInputs(ctx context.Context, flts []TxFilter, order SortOrder, page *PageIndex) ([]Transaction, [][]TransactionInput, TxnPage, error) {
 	var txns []Transaction
 	var inputs [][]TransactionInput
-	var pages uint64
-	if err := vs.db.View("GetTransactionsWithInputs", func(tx *dbutil.Tx) error {
+	var txnPage TxnPage
+	if err := vs.db.ViewContext(ctx, "GetTransactionsWithInputs", func(tx *dbutil.Tx) error {
 		var err error
-		txns, pages, err = vs.txns.GetTransactions(tx, flts, order, page)
+		txns, txnPage, err = vs.txns.GetTransactions(tx, flts, order, page)
 		if err != nil {
 			return err
 		}
 
 		inputs = make([][]TransactionInput, len(txns))
 		for i, txn := range txns {
+			if err := tx.Err(); err != nil {
+				return err
+			}
+
 			feeCalcTime, err := vs.getFeeCalcTimeForTransaction(tx, txn)
 			if err != nil {
 				return err
@@ -1056,10 +1144,10 @@ func (vs *Visor) GetTransactionsWithInputs(flts []TxFilter, order SortOrder, pag
 
 		return nil
 	}); err != nil {
-		return nil, nil, 0, err
+		return nil, nil, TxnPage{}, err
 	}
 
-	return txns, inputs, pages, nil
+	return txns, inputs, txnPage, nil
 }
 
 // AddressBalances computes the total balance for cipher.Addresses and their coin.UxOuts
@@ -1688,6 +1776,10 @@ func (vs *Visor) SetTransactionsAnnounced(hashes map[cipher.SHA256]int64) error
 
 // GetBalanceOfAddresses returns balance pairs of given addreses
 func (vs Visor) GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet.BalancePair, error) {
+	return vs.getBalanceOfAddresses(context.Background(), addrs)
+}
+
+func (vs Visor) getBalanceOfAddresses(ctx context.Context, addrs []cipher.Address) ([]wallet.BalancePair, error) {
 	if len(addrs) == 0 {
 		return nil, nil
 	}
@@ -1697,7 +1789,7 @@ func (vs Visor) GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet.BalanceP
 	var uxa coin.UxArray
 	var head *coin.SignedBlock
 
-	if err := vs.db.View("GetBalanceOfAddresses", func(tx *dbutil.Tx) error {
+	if err := vs.db.ViewContext(ctx, "GetBalanceOfAddresses", func(tx *dbutil.Tx) error {
 		var err error
 		head, err = vs.blockchain.Head(tx)
 		if err != nil {
@@ -1813,6 +1905,41 @@ func (vs Visor) GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet.BalanceP
 	return bps, nil
 }
 
+// GetAddressesLastUsedHeight returns the block seq of the last confirmed transaction of each address,
+// nil if the address has no confirmed transactions
+func (vs Visor) GetAddressesLastUsedHeight(addrs []cipher.Address) ([]*uint64, error) {
+	heights := make([]*uint64, len(addrs))
+	if err := vs.db.View("GetAddressesLastUsedHeight", func(tx *dbutil.Tx) error {
+		for i, addr := range addrs {
+			hashes, err := vs.history.GetTransactionHashesForAddresses(tx, []cipher.Address{addr})
+			if err != nil {
+				return err
+			}
+
+			for _, h := range hashes {
+				txn, err := vs.history.GetTransaction(tx, h)
+				if err != nil {
+					return err
+				}
+
+				if txn == nil {
+					return fmt.Errorf("transaction %s of address %s not found in historydb", h.Hex(), addr)
+				}
+
+				if heights[i] == nil || txn.BlockSeq > *heights[i] {
+					seq := txn.BlockSeq
+					heights[i] = &seq
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return heights, nil
+}
+
 // GetUnspentsOfAddrs returns unspent outputs of multiple addresses
 func (vs *Visor) GetUnspentsOfAddrs(addrs []cipher.Address) (coin.AddressUxOuts, error) {
 	var uxa coin.AddressUxOuts
@@ -2080,13 +2207,14 @@ func newSHA256Set(keys []cipher.SHA256) map[cipher.SHA256]struct{} {
 
 // GetUnspentOutputsSummary gets unspent outputs and returns the filtered results,
 // Note: all filters will be executed as the pending sequence in 'AND' mode.
-func (vs *Visor) GetUnspentOutputsSummary(filters []OutputsFilter) (*UnspentOutputsSummary, error) {
+// Aborts with the error of ctx when ctx is done.
+func (vs *Visor) GetUnspentOutputsSummary(ctx context.Context, filters []OutputsFilter) (*UnspentOutputsSummary, error) {
 	var confirmedOutputs []coin.UxOut
 	var outgoingOutputs coin.UxArray
 	var incomingOutputs coin.UxArray
 	var head *coin.SignedBlock
 
-	if err := vs.db.View("GetUnspentOutputsSummary", func(tx *dbutil.Tx) error {
+	if err := vs.db.ViewContext(ctx, "GetUnspentOutputsSummary", func(tx *dbutil.Tx) error {
 		var err error
 		head, err = vs.blockchain.Head(tx)
 		if err != nil {
@@ -2142,47 +2270,108 @@ func (vs *Visor) GetUnspentOutputsSummary(filters []OutputsFilter) (*UnspentOutp
 	}, nil
 }
 
-// GetRichlist returns a Richlist
-func (vs *Visor) GetRichlist(includeDistribution bool) (Richlist, error) {
-	rbOuts, err := vs.GetUnspentOutputsSummary(nil)
-	if err != nil {
-		return nil, err
+// GetRichlist returns a page of the richlist, read from the richlist index of the unspent pool
+func (vs *Visor) GetRichlist(p RichlistParams) (*RichlistPage, error) {
+	lockedAddrs := make(map[cipher.Address]struct{})
+	for _, a := range vs.Config.Distribution.LockedAddressesDecoded() {
+		lockedAddrs[a] = struct{}{}
 	}
 
-	// Build a map from addresses to total coins held
-	allAccounts := map[cipher.Address]uint64{}
-	for _, out := range rbOuts.Confirmed {
-		if _, ok := allAccounts[out.Body.Address]; ok {
-			var err error
-			allAccounts[out.Body.Address], err = mathutil.AddUint64(allAccounts[out.Body.Address], out.Body.Coins)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			allAccounts[out.Body.Address] = out.Body.Coins
-		}
+	excludedAddrs := make(map[cipher.Address]struct{}, len(p.Exclude))
+	for _, a := range p.Exclude {
+		excludedAddrs[a] = struct{}{}
 	}
 
-	lockedAddrs := vs.Config.Distribution.LockedAddressesDecoded()
-	addrsMap := make(map[cipher.Address]struct{}, len(lockedAddrs))
-	for _, a := range lockedAddrs {
-		addrsMap[a] = struct{}{}
+	if !p.IncludeDistribution {
+		for _, a := range vs.Config.Distribution.AddressesDecoded() {
+			excludedAddrs[a] = struct{}{}
+		}
 	}
 
-	richlist, err := NewRichlist(allAccounts, addrsMap)
-	if err != nil {
-		return nil, err
+	excluded := make([]cipher.Address, 0, len(excludedAddrs))
+	for a := range excludedAddrs {
+		excluded = append(excluded, a)
 	}
 
-	if !includeDistribution {
-		unlockedAddrs := vs.Config.Distribution.UnlockedAddressesDecoded()
-		for _, a := range unlockedAddrs {
-			addrsMap[a] = struct{}{}
+	var page RichlistPage
+	if err := vs.db.View("GetRichlist", func(tx *dbutil.Tx) error {
+		total, err := vs.blockchain.Unspent().AddressCount(tx)
+		if err != nil {
+			return err
+		}
+
+		// Only the excluded addresses with unspent outputs are in the richlist
+		addrHashes, err := vs.blockchain.Unspent().GetUnspentHashesOfAddrs(tx, excluded)
+		if err != nil {
+			return err
 		}
-		richlist = richlist.FilterAddresses(addrsMap)
+		for _, hashes := range addrHashes {
+			if len(hashes) != 0 {
+				total--
+			}
+		}
+		page.Total = total
+
+		var skipped uint64
+		page.Richlist = Richlist{}
+
+		// The index sorts the addresses with the same coins by their bytes.
+		// The richlist sorts the locked addresses first, so the entries with the same coins
+		// are collected before they are added to the page.
+		var group Richlist
+		addGroup := func() bool {
+			defer func() {
+				group = group[:0]
+			}()
+
+			sort.SliceStable(group, func(i, j int) bool {
+				return group[i].Locked && !group[j].Locked
+			})
+
+			for _, b := range group {
+				if skipped < p.Offset {
+					skipped++
+					continue
+				}
+
+				page.Richlist = append(page.Richlist, b)
+				if p.Limit != 0 && uint64(len(page.Richlist)) == p.Limit {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if err := vs.blockchain.Unspent().ForEachRichlistEntry(tx, func(addr cipher.Address, coins uint64) error {
+			if _, ok := excludedAddrs[addr]; ok {
+				return nil
+			}
+
+			if len(group) != 0 && group[0].Coins != coins && addGroup() {
+				return blockdb.ErrStopIteration
+			}
+
+			_, locked := lockedAddrs[addr]
+			group = append(group, RichlistBalance{
+				Address: addr,
+				Coins:   coins,
+				Locked:  locked,
+			})
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		addGroup()
+
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	return richlist, nil
+	return &page, nil
 }
 
 // WithUpdateTx executes a function inside of a db.Update transaction.
@@ -2,6 +2,7 @@ package visor
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -485,6 +486,66 @@ func TestVisorCreateBlock(t *testing.T) {
 	}
 }
 
+func TestVisorExecuteSignedBlocks(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	bc, err := NewBlockchain(db, BlockchainConfig{
+		Pubkey: genPublic,
+	})
+	require.NoError(t, err)
+
+	unconfirmed, err := NewUnconfirmedTransactionPool(db)
+	require.NoError(t, err)
+
+	cfg := NewConfig()
+	cfg.BlockchainPubkey = genPublic
+
+	v := &Visor{
+		Config:      cfg,
+		unconfirmed: unconfirmed,
+		blockchain:  bc,
+		db:          db,
+		history:     historydb.New(),
+	}
+
+	n, err := v.ExecuteSignedBlocks(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	gb, err := coin.NewGenesisBlock(genAddress, genCoins, genTime)
+	require.NoError(t, err)
+
+	// A block signed by another key is not executed
+	_, badSecret := cipher.GenerateKeyPair()
+	n, err = v.ExecuteSignedBlocks([]coin.SignedBlock{
+		{
+			Block: *gb,
+			Sig:   cipher.MustSignHash(gb.HashHeader(), badSecret),
+		},
+	})
+	require.Equal(t, cipher.ErrPubKeyRecoverMismatch, err)
+	require.Equal(t, 0, n)
+
+	_, ok, err := v.HeadBkSeq()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	n, err = v.ExecuteSignedBlocks([]coin.SignedBlock{
+		{
+			Block: *gb,
+			Sig:   cipher.MustSignHash(gb.HashHeader(), genSecret),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	seq, ok, err := v.HeadBkSeq()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(0), seq)
+}
+
 func TestVisorInjectTransaction(t *testing.T) {
 	when := uint64(time.Now().UTC().Unix())
 
@@ -512,6 +573,7 @@ func TestVisorInjectTransaction(t *testing.T) {
 		blockchain:  bc,
 		db:          db,
 		history:     his,
+		events:      newEventBus(),
 	}
 
 	// CreateBlock panics if called when not a block publisher
@@ -549,18 +611,35 @@ func TestVisorInjectTransaction(t *testing.T) {
 	toAddr := testutil.MakeAddress()
 	var coins uint64 = 10e6
 
+	events, unsubscribe := v.SubscribeBlockchain(0)
+	defer unsubscribe()
+
 	// Create a transaction with valid decimal places
 	txn := makeSpendTxn(t, uxs, []cipher.SecKey{genSecret}, genAddress, coins)
 	known, softErr, err := v.InjectForeignTransaction(txn)
 	require.False(t, known)
 	require.Nil(t, softErr)
 	require.NoError(t, err)
+	require.Equal(t, Event{
+		Type:        EventUnconfirmedTxnInjected,
+		Transaction: &txn,
+	}, <-events)
+
+	// A known transaction is not published again
+	known, _, err = v.InjectForeignTransaction(txn)
+	require.True(t, known)
+	require.NoError(t, err)
+	require.Len(t, events, 0)
 
 	// Execute a block to clear this transaction from the pool
 	sb, err := v.CreateAndExecuteBlock()
 	require.NoError(t, err)
 	require.Equal(t, 1, len(sb.Body.Transactions))
 	require.Equal(t, 2, len(sb.Body.Transactions[0].Out))
+	require.Equal(t, Event{
+		Type:  EventBlockExecuted,
+		Block: &sb,
+	}, <-events)
 
 	err = db.View("", func(tx *dbutil.Tx) error {
 		length, err := unconfirmed.Len(tx)
@@ -1931,7 +2010,7 @@ func TestGetTransactions(t *testing.T) {
 				txns:        &txnModel,
 			}
 
-			retTxns, _, err := v.GetTransactions(tc.filters, AscOrder, nil)
+			retTxns, _, err := v.GetTransactions(context.Background(), tc.filters, AscOrder, nil)
 			require.Equal(t, tc.expect.err, err)
 			if err != nil {
 				return
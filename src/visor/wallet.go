@@ -3,6 +3,7 @@ package visor
 // This file contains Visor method that require wallet access
 
 import (
+	"context"
 	"errors"
 
 	"github.com/skycoin/skycoin/src/cipher"
@@ -12,7 +13,8 @@ import (
 	"github.com/skycoin/skycoin/src/util/mathutil"
 	"github.com/skycoin/skycoin/src/visor/dbutil"
 	"github.com/skycoin/skycoin/src/wallet"
-	"github.com/skycoin/skycoin/src/wallet/bip44wallet"
+	// Registers the bip44 wallet creator and loader
+	_ "github.com/skycoin/skycoin/src/wallet/bip44wallet"
 )
 
 // UserError wraps user input-related errors.
@@ -47,10 +49,24 @@ var (
 	ErrUxOutsOrAddressesRequired = NewUserError(errors.New("UxOuts or Addresses must not be empty"))
 	// ErrNoSpendableOutputs after filtering unconfirmed spend outputs, there are no remaining outputs available for transaction creation
 	ErrNoSpendableOutputs = NewUserError(errors.New("All selected outputs are unavailable for spending"))
+	// ErrWalletsRequired no wallets to spend from
+	ErrWalletsRequired = NewUserError(errors.New("Wallets must not be empty"))
+	// ErrDuplicateWallets Wallets contains duplicate values
+	ErrDuplicateWallets = NewUserError(errors.New("Wallets contains duplicate values"))
+	// ErrDuplicateExcludeUxOuts ExcludeUxOuts contains duplicate values
+	ErrDuplicateExcludeUxOuts = NewUserError(errors.New("ExcludeUxOuts contains duplicate values"))
+	// ErrExcludeUxOutsConflict UxOuts and ExcludeUxOuts cannot be combined
+	ErrExcludeUxOutsConflict = NewUserError(errors.New("UxOuts and ExcludeUxOuts cannot be combined"))
+	// ErrChangeAddressRequired the change address is not set for a transaction that avoids address reuse
+	ErrChangeAddressRequired = NewUserError(errors.New("ChangeAddress is required to avoid address reuse"))
+	// ErrChangeAddressReused the change address of a transaction that avoids address reuse has already been used
+	ErrChangeAddressReused = NewUserError(errors.New("ChangeAddress has already been used, choose an unused change address to avoid address reuse"))
+	// ErrReceiverAddressReused a receiver of a transaction that avoids address reuse is the address of a spent output
+	ErrReceiverAddressReused = NewUserError(errors.New("To contains the address of a spent output, which would reuse the address"))
 )
 
-// GetWalletBalance returns balance pairs of specific wallet
-func (vs *Visor) GetWalletBalance(wltID string) (wallet.BalancePair, wallet.AddressBalances, error) {
+// GetWalletBalance returns balance pairs of specific wallet. Aborts with the error of ctx when ctx is done.
+func (vs *Visor) GetWalletBalance(ctx context.Context, wltID string) (wallet.BalancePair, wallet.AddressBalances, error) {
 	var addressBalances wallet.AddressBalances
 	var walletBalance wallet.BalancePair
 	var addrsBalanceList []wallet.BalancePair
@@ -66,7 +82,7 @@ func (vs *Visor) GetWalletBalance(wltID string) (wallet.BalancePair, wallet.Addr
 			return wallet.SkycoinAddresses(addrs), nil
 		}()
 
-		addrsBalanceList, err = vs.GetBalanceOfAddresses(addrs)
+		addrsBalanceList, err = vs.getBalanceOfAddresses(ctx, addrs)
 		return err
 	}); err != nil {
 		return walletBalance, addressBalances, err
@@ -102,9 +118,17 @@ func (vs *Visor) GetWalletBalance(wltID string) (wallet.BalancePair, wallet.Addr
 		}
 	}
 
+	// Notifies the wallet event subscribers if the balance changed
+	vs.wallets.ReportBalance(wltID, walletBalance)
+
 	return walletBalance, addressBalances, nil
 }
 
+// ExportWalletEntries exports the addresses of the wallet with their balances and last used block heights
+func (vs *Visor) ExportWalletEntries(wltID string, format wallet.ExportFormat) ([]byte, error) {
+	return vs.wallets.ExportEntries(wltID, format, vs)
+}
+
 // GetWalletUnconfirmedTransactions returns all unconfirmed transactions in given wallet
 func (vs *Visor) GetWalletUnconfirmedTransactions(wltID string) ([]UnconfirmedTransaction, error) {
 	var txns []UnconfirmedTransaction
@@ -160,8 +184,9 @@ func (vs *Visor) WalletSignTransaction(wltID string, password []byte, txn *coin.
 		return nil, nil, ErrTransactionAlreadySigned
 	}
 
-	if err := vs.wallets.ViewSecrets(wltID, password, func(w wallet.Wallet) error {
-		return vs.db.View("WalletSignTransaction", func(tx *dbutil.Tx) error {
+	// Signing is subject to the spend policy of the wallet
+	if _, err := vs.wallets.CreateTransactionSigned(wltID, password, func(w wallet.Wallet) (*coin.Transaction, error) {
+		err := vs.db.View("WalletSignTransaction", func(tx *dbutil.Tx) error {
 			// Verify the transaction before signing
 			if err := VerifySingleTxnUserConstraints(*txn); err != nil {
 				return err
@@ -211,6 +236,7 @@ func (vs *Visor) WalletSignTransaction(wltID string, password []byte, txn *coin.
 
 			return nil
 		})
+		return signedTxn, err
 	}); err != nil {
 		return nil, nil, err
 	}
@@ -222,9 +248,17 @@ func (vs *Visor) WalletSignTransaction(wltID string, password []byte, txn *coin.
 type CreateTransactionParams struct {
 	UxOuts    []cipher.SHA256
 	Addresses []cipher.Address
+	// ExcludeUxOuts are outputs of Addresses, or of the wallet, that are never spent.
+	// Cannot be combined with UxOuts.
+	ExcludeUxOuts []cipher.SHA256
 	// IgnoreUnconfirmed if true, outputs matching Addresses or UxOuts spent by
 	// an unconfirmed transactions will be ignored, otherwise an error will be returned
 	IgnoreUnconfirmed bool
+	// AvoidAddressReuse if true, the change is sent to an address that has never been used,
+	// and the receivers must not be the addresses of the spent outputs.
+	// Wallet transactions choose an unused change address when ChangeAddress is not set,
+	// overriding the change policy of the wallet.
+	AvoidAddressReuse bool
 }
 
 // Validate validates params
@@ -256,6 +290,18 @@ func (p CreateTransactionParams) Validate() error {
 		uxOuts[o] = struct{}{}
 	}
 
+	if len(p.UxOuts) != 0 && len(p.ExcludeUxOuts) != 0 {
+		return ErrExcludeUxOutsConflict
+	}
+
+	excludeUxOuts := make(map[cipher.SHA256]struct{}, len(p.ExcludeUxOuts))
+	for _, o := range p.ExcludeUxOuts {
+		if _, ok := excludeUxOuts[o]; ok {
+			return ErrDuplicateExcludeUxOuts
+		}
+		excludeUxOuts[o] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -276,30 +322,214 @@ func (vs *Visor) WalletCreateTransactionSigned(wltID string, password []byte, p
 		return nil, nil, err
 	}
 
-	if p.ChangeAddress == nil && w.Type() == wallet.WalletTypeBip44 {
-		// TODO: Maybe add the `PeekChangeAddress` to wallet.Wallet interface, and
-		// only bip44 wallet will implement it, all others do nothing. In this way
-		// we don't have to explicitly check the wallet type here.
-		//
-		// For bip44 wallet, peek a change address if p.ChangeAddress is nill
-		if err := vs.wallets.Update(wltID, func(w wallet.Wallet) error {
-			addr, err := w.(*bip44wallet.Wallet).PeekChangeAddress(vs.tf)
-			if err != nil {
-				logger.Critical().WithError(err).Error("PeekChangeAddress failed")
-				return err
-			}
-			skyAddr := addr.(cipher.Address)
-			p.ChangeAddress = &skyAddr
-			return nil
-		}); err != nil {
+	if p.ChangeAddress == nil {
+		p.ChangeAddress, err = vs.walletChangeAddress(wltID, password, w, wp.AvoidAddressReuse)
+		if err != nil {
 			return nil, nil, err
 		}
 	}
 
-	if err := vs.wallets.ViewSecrets(wltID, password, func(w wallet.Wallet) error {
+	// Creating the transaction is subject to the spend policy of the wallet
+	if _, err := vs.wallets.CreateTransactionSigned(wltID, password, func(w wallet.Wallet) (*coin.Transaction, error) {
 		var err error
 		txn, inputs, err = vs.walletCreateTransaction("WalletCreateTransactionSigned", w, p, wp, TxnSigned)
+		return txn, err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, inputs, nil
+}
+
+// walletChangeAddress returns the change address chosen by the wallet change policy, which may generate
+// a new address. Generating an address of a deterministic wallet requires the secrets.
+// If unused is true, an unused change address is returned regardless of the change policy.
+// Returns nil if the wallet does not use a change policy.
+func (vs *Visor) walletChangeAddress(wltID string, password []byte, w wallet.Wallet, unused bool) (*cipher.Address, error) {
+	if !unused && !wallet.UsesChangePolicy(w) {
+		return nil, nil
+	}
+
+	update := vs.wallets.Update
+	if unused || w.ChangePolicy() == wallet.ChangePolicyFresh {
+		update = func(wltID string, f func(wallet.Wallet) error) error {
+			return vs.wallets.UpdateSecrets(wltID, password, f)
+		}
+	}
+
+	var addr *cipher.Address
+	if err := update(wltID, func(w wallet.Wallet) error {
+		var err error
+		addr, err = chooseChangeAddress(w, vs.tf, unused)
 		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// chooseChangeAddress returns the change address of the wallet, an unused one if unused is true
+func chooseChangeAddress(w wallet.Wallet, tf wallet.TransactionsFinder, unused bool) (*cipher.Address, error) {
+	if unused {
+		addr, err := wallet.SkycoinUnusedChangeAddress(w, tf)
+		if err != nil {
+			logger.WithError(err).Error("SkycoinUnusedChangeAddress failed")
+			return nil, err
+		}
+		return addr, nil
+	}
+
+	addr, err := wallet.SkycoinChangeAddress(w, tf)
+	if err != nil {
+		logger.WithError(err).Error("SkycoinChangeAddress failed")
+		return nil, err
+	}
+	return addr, nil
+}
+
+// WalletSpend is a wallet that spends the outputs of its addresses in a transaction
+// created by WalletsCreateTransactionSigned
+type WalletSpend struct {
+	WalletID string
+	Password []byte
+	// Addresses are the wallet addresses to spend from. If empty, all wallet addresses are used.
+	Addresses []cipher.Address
+}
+
+// WalletsCreateTransactionSigned creates a transaction that spends the outputs of several wallets,
+// for funds that are fragmented across wallets. The outputs are chosen from the addresses of all wallets,
+// then each wallet signs its inputs, subject to its spend policy.
+// If the change address is not set, it is chosen by the change policy of the first wallet.
+// A wallet with a daily spend limit is charged the coins sent out of that wallet, including those spent
+// from the other wallets.
+func (vs *Visor) WalletsCreateTransactionSigned(spends []WalletSpend, p transaction.Params, ignoreUnconfirmed bool) (*coin.Transaction, []TransactionInput, error) {
+	if err := p.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if len(spends) == 0 {
+		return nil, nil, ErrWalletsRequired
+	}
+
+	// The addresses of the wallets, mapped to the index of the wallet spending them
+	addrsWallet := make(map[cipher.Address]int)
+	var addrs []cipher.Address
+	wallets := make([]wallet.Wallet, len(spends))
+
+	for i, s := range spends {
+		for _, x := range spends[:i] {
+			if x.WalletID == s.WalletID {
+				return nil, nil, ErrDuplicateWallets
+			}
+		}
+
+		if err := (CreateTransactionParams{Addresses: s.Addresses}).Validate(); err != nil {
+			return nil, nil, err
+		}
+
+		w, err := vs.wallets.GetWallet(s.WalletID)
+		if err != nil {
+			return nil, nil, err
+		}
+		wallets[i] = w
+
+		walletAddrs, err := w.GetAddresses()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		spendAddrs := wallet.SkycoinAddresses(walletAddrs)
+		if len(s.Addresses) != 0 {
+			walletAddrsMap := make(map[cipher.Address]struct{}, len(spendAddrs))
+			for _, a := range spendAddrs {
+				walletAddrsMap[a] = struct{}{}
+			}
+
+			for _, a := range s.Addresses {
+				if _, ok := walletAddrsMap[a]; !ok {
+					return nil, nil, wallet.ErrUnknownAddress
+				}
+			}
+
+			spendAddrs = s.Addresses
+		}
+
+		// An address of several wallets is signed by the first of them
+		for _, a := range spendAddrs {
+			if _, ok := addrsWallet[a]; ok {
+				continue
+			}
+			addrsWallet[a] = i
+			addrs = append(addrs, a)
+		}
+	}
+
+	if p.ChangeAddress == nil {
+		var err error
+		p.ChangeAddress, err = vs.walletChangeAddress(spends[0].WalletID, spends[0].Password, wallets[0], false)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var txn *coin.Transaction
+	var uxb []transaction.UxBalance
+	if err := vs.db.View("WalletsCreateTransactionSigned", func(tx *dbutil.Tx) error {
+		var err error
+		txn, uxb, err = vs.createTransactionTx(tx, p, CreateTransactionParams{
+			Addresses:         addrs,
+			IgnoreUnconfirmed: ignoreUnconfirmed,
+		})
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	inputs := NewTransactionInputsFromUxBalance(uxb)
+	uxOuts := make([]coin.UxOut, len(inputs))
+	for i, in := range inputs {
+		uxOuts[i] = in.UxOut
+	}
+
+	// Each wallet signs the inputs of its addresses
+	for i, s := range spends {
+		var signIndexes []int
+		for j, ux := range uxOuts {
+			if addrsWallet[ux.Body.Address] == i {
+				signIndexes = append(signIndexes, j)
+			}
+		}
+
+		if len(signIndexes) == 0 {
+			continue
+		}
+
+		var err error
+		txn, err = vs.wallets.CreateTransactionSigned(s.WalletID, s.Password, func(w wallet.Wallet) (*coin.Transaction, error) {
+			signedTxn, err := wallet.SignTransaction(w, txn, signIndexes, uxOuts)
+			if err != nil {
+				logger.WithError(err).Error("wallet.SignTransaction failed")
+				return nil, err
+			}
+			return signedTxn, nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := vs.db.View("WalletsCreateTransactionSigned", func(tx *dbutil.Tx) error {
+		if err := VerifySingleTxnUserConstraints(*txn); err != nil {
+			logger.WithError(err).Error("Signed transaction violates transaction user constraints")
+			return err
+		}
+
+		if _, _, err := vs.blockchain.VerifySingleTxnSoftHardConstraints(tx, *txn, vs.Config.Distribution, params.UserVerifyTxn, TxnSigned); err != nil {
+			logger.WithError(err).Error("Signed transaction violates transaction soft/hard constraints")
+			return err
+		}
+
+		return nil
 	}); err != nil {
 		return nil, nil, err
 	}
@@ -307,6 +537,61 @@ func (vs *Visor) WalletCreateTransactionSigned(wltID string, password []byte, p
 	return txn, inputs, nil
 }
 
+// WalletCreateSweepTransaction creates a signed transaction that sends all the coins and hours of the wallet
+// to the address, e.g. to the bip44 wallet that a deterministic wallet was migrated to.
+// The outputs in the unconfirmed pool are not spent. Migrated wallets can be swept although they are read-only.
+func (vs *Visor) WalletCreateSweepTransaction(wltID string, password []byte, to cipher.Address) (*coin.Transaction, []TransactionInput, error) {
+	if to.Null() {
+		return nil, nil, transaction.ErrNullAddressReceiver
+	}
+
+	var txn *coin.Transaction
+	var uxb []transaction.UxBalance
+
+	if _, err := vs.wallets.CreateSweepTransactionSigned(wltID, password, func(w wallet.Wallet) (*coin.Transaction, error) {
+		addrs, err := w.GetAddresses()
+		if err != nil {
+			return nil, err
+		}
+
+		err = vs.db.View("WalletCreateSweepTransaction", func(tx *dbutil.Tx) error {
+			head, err := vs.blockchain.Head(tx)
+			if err != nil {
+				logger.WithError(err).Error("blockchain.Head failed")
+				return err
+			}
+
+			auxs, err := vs.getCreateTransactionAuxsAddress(tx, wallet.SkycoinAddresses(addrs), nil, true)
+			if err != nil {
+				return err
+			}
+
+			txn, uxb, err = wallet.CreateSweepTransaction(w, to, auxs, head.Time())
+			if err != nil {
+				logger.WithError(err).Error("wallet.CreateSweepTransaction failed")
+				return err
+			}
+
+			if err := VerifySingleTxnUserConstraints(*txn); err != nil {
+				logger.WithError(err).Error("Created transaction violates transaction user constraints")
+				return err
+			}
+
+			if _, _, err := vs.blockchain.VerifySingleTxnSoftHardConstraints(tx, *txn, vs.Config.Distribution, params.UserVerifyTxn, TxnSigned); err != nil {
+				logger.WithError(err).Error("Created transaction violates transaction soft/hard constraints")
+				return err
+			}
+
+			return nil
+		})
+		return txn, err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, NewTransactionInputsFromUxBalance(uxb), nil
+}
+
 // WalletCreateTransaction creates a transaction based upon the parameters in CreateTransactionParams
 // TODO: Only referenced by tests, vs.walletCreateTransaction
 func (vs *Visor) WalletCreateTransaction(wltID string, p transaction.Params, wp CreateTransactionParams) (*coin.Transaction, []TransactionInput, error) {
@@ -322,19 +607,13 @@ func (vs *Visor) WalletCreateTransaction(wltID string, p transaction.Params, wp
 	var inputs []TransactionInput
 
 	if err := vs.wallets.Update(wltID, func(w wallet.Wallet) error {
-		if p.ChangeAddress == nil && w.Type() == wallet.WalletTypeBip44 {
-			// TODO: Maybe add the `PeekChangeAddress` to wallet.Wallet interface, and
-			// only bip44 wallet will implement it, all others do nothing. In this way
-			// we don't have to explicitly check the wallet type here.
-			//
-			// For bip44 wallet, peek a change address if p.ChangeAddress is nill
-			addr, err := w.(*bip44wallet.Wallet).PeekChangeAddress(vs.tf)
+		// Choose the change address by the wallet change policy
+		if p.ChangeAddress == nil && (wp.AvoidAddressReuse || wallet.UsesChangePolicy(w)) {
+			addr, err := chooseChangeAddress(w, vs.tf, wp.AvoidAddressReuse)
 			if err != nil {
-				logger.Critical().WithError(err).Error("PeekChangeAddress failed")
 				return err
 			}
-			skyAddr := addr.(cipher.Address)
-			p.ChangeAddress = &skyAddr
+			p.ChangeAddress = addr
 		}
 
 		var err error
@@ -429,7 +708,7 @@ func (vs *Visor) walletCreateTransactionTx(tx *dbutil.Tx, methodName string,
 		}
 	} else {
 		var err error
-		auxs, err = vs.getCreateTransactionAuxsAddress(tx, addrs, wp.IgnoreUnconfirmed)
+		auxs, err = vs.getCreateTransactionAuxsAddress(tx, addrs, wp.ExcludeUxOuts, wp.IgnoreUnconfirmed)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -452,6 +731,12 @@ func (vs *Visor) walletCreateTransactionTx(tx *dbutil.Tx, methodName string,
 		return nil, nil, err
 	}
 
+	if wp.AvoidAddressReuse {
+		if err := vs.verifyNoAddressReuse(tx, p, uxb); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if err := VerifySingleTxnUserConstraints(*txn); err != nil {
 		logger.WithError(err).Error("Created transaction violates transaction user constraints")
 		return nil, nil, err
@@ -511,7 +796,7 @@ func (vs *Visor) createTransactionTx(tx *dbutil.Tx, p transaction.Params, wp Cre
 	if len(wp.UxOuts) != 0 {
 		auxs, err = vs.getCreateTransactionAuxsUxOut(tx, wp.UxOuts, wp.IgnoreUnconfirmed)
 	} else {
-		auxs, err = vs.getCreateTransactionAuxsAddress(tx, wp.Addresses, wp.IgnoreUnconfirmed)
+		auxs, err = vs.getCreateTransactionAuxsAddress(tx, wp.Addresses, wp.ExcludeUxOuts, wp.IgnoreUnconfirmed)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -522,6 +807,12 @@ func (vs *Visor) createTransactionTx(tx *dbutil.Tx, p transaction.Params, wp Cre
 		return nil, nil, err
 	}
 
+	if wp.AvoidAddressReuse {
+		if err := vs.verifyNoAddressReuse(tx, p, uxb); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if err := VerifySingleTxnUserConstraints(*txn); err != nil {
 		logger.WithError(err).Error("Created transaction violates transaction user constraints")
 		return nil, nil, err
@@ -597,9 +888,9 @@ func (vs *Visor) getCreateTransactionAuxsUxOut(tx *dbutil.Tx, uxOutHashes []ciph
 	return coin.NewAddressUxOuts(uxOuts), nil
 }
 
-// getCreateTransactionAuxsAddress returns a map of the addresses to their unspent outputs,
+// getCreateTransactionAuxsAddress returns a map of the addresses to their unspent outputs, without the excluded outputs,
 // filtering or erroring on unconfirmed outputs depending on the value of ignoreUnconfirmed
-func (vs *Visor) getCreateTransactionAuxsAddress(tx *dbutil.Tx, addrs []cipher.Address, ignoreUnconfirmed bool) (coin.AddressUxOuts, error) {
+func (vs *Visor) getCreateTransactionAuxsAddress(tx *dbutil.Tx, addrs []cipher.Address, exclude []cipher.SHA256, ignoreUnconfirmed bool) (coin.AddressUxOuts, error) {
 	// Get all address unspent hashes
 	addrHashes, err := vs.blockchain.Unspent().GetUnspentHashesOfAddrs(tx, addrs)
 	if err != nil {
@@ -611,5 +902,68 @@ func (vs *Visor) getCreateTransactionAuxsAddress(tx *dbutil.Tx, addrs []cipher.A
 		return nil, transaction.ErrNoUnspents
 	}
 
+	if len(exclude) != 0 {
+		excludeMap := make(map[cipher.SHA256]struct{}, len(exclude))
+		for _, h := range exclude {
+			excludeMap[h] = struct{}{}
+		}
+
+		filteredHashes := hashes[:0]
+		for _, h := range hashes {
+			if _, ok := excludeMap[h]; !ok {
+				filteredHashes = append(filteredHashes, h)
+			}
+		}
+		hashes = filteredHashes
+
+		if len(hashes) == 0 {
+			return nil, ErrNoSpendableOutputs
+		}
+	}
+
 	return vs.getCreateTransactionAuxsUxOut(tx, hashes, ignoreUnconfirmed)
 }
+
+// verifyNoAddressReuse checks that a created transaction does not send coins to an address that has been used.
+// The change address must not be a spent address, nor have appeared in the blockchain or the unconfirmed pool,
+// and the receivers must not be the addresses of the spent outputs.
+func (vs *Visor) verifyNoAddressReuse(tx *dbutil.Tx, p transaction.Params, uxb []transaction.UxBalance) error {
+	if p.ChangeAddress == nil {
+		return ErrChangeAddressRequired
+	}
+
+	spentAddrs := make(map[cipher.Address]struct{}, len(uxb))
+	for _, ux := range uxb {
+		spentAddrs[ux.Address] = struct{}{}
+	}
+
+	for _, to := range p.To {
+		if _, ok := spentAddrs[to.Address]; ok {
+			return ErrReceiverAddressReused
+		}
+	}
+
+	changeAddr := *p.ChangeAddress
+	if _, ok := spentAddrs[changeAddr]; ok {
+		return ErrChangeAddressReused
+	}
+
+	seen, err := vs.history.AddressSeen(tx, changeAddr)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrChangeAddressReused
+	}
+
+	// Only the outputs of the unconfirmed transactions need to be checked, an address of an
+	// input must have appeared in the blockchain history
+	return vs.unconfirmed.ForEach(tx, func(_ cipher.SHA256, txn UnconfirmedTransaction) error {
+		for _, o := range txn.Transaction.Out {
+			if o.Address == changeAddr {
+				return ErrChangeAddressReused
+			}
+		}
+		return nil
+	})
+}
@@ -355,7 +355,7 @@ func makeEntries(n int) ([]wallet.Entry, []cipher.Address) {
 		entries[i] = wallet.Entry{
 			Address: a,
 			Public:  p,
-			Secret:  s,
+			Secret:  wallet.NewSecretKey(s),
 		}
 		addrs[i] = a
 	}
@@ -1013,6 +1013,262 @@ func TestWalletCreateTransaction(t *testing.T) {
 	}
 }
 
+func TestWalletsCreateTransactionSigned(t *testing.T) {
+	headBlock := &coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				Time: uint64(time.Now().Unix()),
+			},
+		},
+	}
+
+	params2500 := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   25e5,
+				Hours:   7,
+			},
+		},
+	}
+
+	params500 := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   5e5,
+				Hours:   7,
+			},
+		},
+	}
+
+	type testCase struct {
+		name          string
+		spends        []WalletSpend
+		p             transaction.Params
+		nInputs       int
+		nWalletInputs []int
+		err           error
+	}
+
+	cases := []testCase{
+		{
+			name: "spends the outputs of both wallets",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+				{
+					WalletID: "bar.wlt",
+					Password: []byte("bar"),
+				},
+			},
+			p:             params2500,
+			nWalletInputs: []int{1, 1},
+		},
+		{
+			name: "spends the outputs of one wallet",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+				{
+					WalletID: "bar.wlt",
+					Password: []byte("bar"),
+				},
+			},
+			p:             params500,
+			nWalletInputs: []int{1, 0},
+		},
+		{
+			name:   "no wallets",
+			spends: nil,
+			p:      params2500,
+			err:    ErrWalletsRequired,
+		},
+		{
+			name: "duplicate wallets",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+				{
+					WalletID: "foo.wlt",
+				},
+			},
+			p:   params2500,
+			err: ErrDuplicateWallets,
+		},
+		{
+			name: "wallet does not exist",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+				{
+					WalletID: "baz.wlt",
+				},
+			},
+			p:   params2500,
+			err: wallet.ErrWalletNotExist,
+		},
+		{
+			name: "unknown wallet address",
+			spends: []WalletSpend{
+				{
+					WalletID:  "foo.wlt",
+					Addresses: []cipher.Address{testutil.MakeAddress()},
+				},
+			},
+			p:   params2500,
+			err: wallet.ErrUnknownAddress,
+		},
+		{
+			name: "missing password",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+				{
+					WalletID: "bar.wlt",
+				},
+			},
+			p:   params2500,
+			err: wallet.ErrMissingPassword,
+		},
+		{
+			name: "insufficient balance",
+			spends: []WalletSpend{
+				{
+					WalletID: "foo.wlt",
+				},
+			},
+			p:   params2500,
+			err: transaction.ErrInsufficientBalance,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := wallet.NewService(wallet.Config{
+				EnableWalletAPI: true,
+				CryptoType:      crypto.CryptoTypeScryptChacha20poly1305Insecure,
+				WalletDir:       prepareWltDir(),
+			})
+			require.NoError(t, err)
+
+			_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+				Coin:      wallet.CoinTypeSkycoin,
+				Type:      wallet.WalletTypeBip44,
+				Seed:      "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+				GenerateN: 1,
+			})
+			require.NoError(t, err)
+
+			_, err = ws.CreateWallet("bar.wlt", wallet.Options{
+				Coin:       wallet.CoinTypeSkycoin,
+				Type:       wallet.WalletTypeBip44,
+				Seed:       "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+				GenerateN:  1,
+				Encrypt:    true,
+				Password:   []byte("bar"),
+				CryptoType: crypto.CryptoTypeScryptChacha20poly1305Insecure,
+			})
+			require.NoError(t, err)
+
+			var addrs []cipher.Address
+			var uxa coin.UxArray
+			var uxOuts []cipher.SHA256
+			walletAddrs := make(map[cipher.Address]int)
+			for i, s := range tc.spends {
+				wAddrs, err := ws.GetAddresses(s.WalletID)
+				if err != nil {
+					continue
+				}
+
+				for _, a := range wAddrs {
+					walletAddrs[a] = i
+					addrs = append(addrs, a)
+				}
+
+				// The first address of each wallet has an output, the first wallet has the most coins
+				ux := coin.UxOut{
+					Head: coin.UxHead{
+						Time:  uint64(time.Now().Unix()) - 3700,
+						BkSeq: 100,
+					},
+					Body: coin.UxBody{
+						SrcTransaction: testutil.RandSHA256(t),
+						Address:        wAddrs[0],
+						Coins:          uint64(2-i) * 1e6,
+						Hours:          100,
+					},
+				}
+				uxa = append(uxa, ux)
+				uxOuts = append(uxOuts, ux.Hash())
+			}
+
+			hashes := make(blockdb.AddressHashes)
+			for _, ux := range uxa {
+				hashes[ux.Body.Address] = []cipher.SHA256{ux.Hash()}
+			}
+
+			b := &MockBlockchainer{}
+			ut := &MockUnconfirmedTransactionPooler{}
+			up := &MockUnspentPooler{}
+
+			b.On("Head", matchDBTx).Return(headBlock, nil)
+			b.On("Unspent").Return(up)
+			up.On("GetUnspentHashesOfAddrs", matchDBTx, addrs).Return(hashes, nil)
+			up.On("GetArray", matchDBTx, mock.MatchedBy(matchUxOutsAnyOrder(uxOuts))).Return(uxa, nil)
+			ut.On("ForEach", matchDBTx, mock.MatchedBy(func(f func(cipher.SHA256, UnconfirmedTransaction) error) bool {
+				return true
+			})).Return(nil).Run(unconfirmedForEachMockRun(t, nil, nil, false))
+			b.On("VerifySingleTxnSoftHardConstraints", matchDBTx, mock.Anything, params.MainNetDistribution, params.UserVerifyTxn, TxnUnsigned).Return(nil, nil, nil)
+			b.On("VerifySingleTxnSoftHardConstraints", matchDBTx, mock.Anything, params.MainNetDistribution, params.UserVerifyTxn, TxnSigned).Return(nil, nil, nil)
+
+			db, shutdown := prepareDB(t)
+			defer shutdown()
+
+			v := &Visor{
+				db:          db,
+				blockchain:  b,
+				unconfirmed: ut,
+				wallets:     ws,
+				Config: Config{
+					Distribution: params.MainNetDistribution,
+				},
+				tf: mockTxnsFinder{},
+			}
+
+			txn, inputs, err := v.WalletsCreateTransactionSigned(tc.spends, tc.p, false)
+			require.Equal(t, tc.err, err, "%v != %v", tc.err, err)
+			if tc.err != nil {
+				return
+			}
+
+			require.True(t, txn.IsFullySigned())
+			require.Len(t, inputs, len(txn.In))
+
+			nWalletInputs := make([]int, len(tc.spends))
+			for i, in := range inputs {
+				require.Equal(t, txn.In[i], in.UxOut.Hash())
+				nWalletInputs[walletAddrs[in.UxOut.Body.Address]]++
+			}
+			require.Equal(t, tc.nWalletInputs, nWalletInputs)
+
+			err = txn.Verify()
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestCreateTransactionParamsValidate(t *testing.T) {
 	var nullAddress cipher.Address
 	addr := testutil.MakeAddress()
@@ -1056,6 +1312,32 @@ func TestCreateTransactionParamsValidate(t *testing.T) {
 			err: ErrDuplicateUxOuts,
 		},
 
+		{
+			name: "both uxouts and exclude uxouts specified",
+			p: CreateTransactionParams{
+				UxOuts:        []cipher.SHA256{hash},
+				ExcludeUxOuts: []cipher.SHA256{testutil.RandSHA256(t)},
+			},
+			err: ErrExcludeUxOutsConflict,
+		},
+
+		{
+			name: "duplicate hash in exclude uxouts",
+			p: CreateTransactionParams{
+				Addresses:     []cipher.Address{addr},
+				ExcludeUxOuts: []cipher.SHA256{hash, hash},
+			},
+			err: ErrDuplicateExcludeUxOuts,
+		},
+
+		{
+			name: "ok, addrs and exclude uxouts specified",
+			p: CreateTransactionParams{
+				Addresses:     []cipher.Address{addr},
+				ExcludeUxOuts: []cipher.SHA256{hash},
+			},
+		},
+
 		{
 			name: "ok, addrs specified",
 			p: CreateTransactionParams{
@@ -1389,6 +1671,7 @@ func TestGetCreateTransactionAuxsAddress(t *testing.T) {
 		name              string
 		ignoreUnconfirmed bool
 		addrs             []cipher.Address
+		exclude           []cipher.SHA256
 		expectedAuxs      coin.AddressUxOuts
 		err               error
 
@@ -1453,6 +1736,60 @@ func TestGetCreateTransactionAuxsAddress(t *testing.T) {
 			},
 		},
 
+		{
+			name:           "exclude uxouts",
+			addrs:          allAddrs,
+			exclude:        []cipher.SHA256{hashes[1], hashes[2], hashes[10]},
+			getArrayInputs: []cipher.SHA256{hashes[0], hashes[3]},
+			getArray: coin.UxArray{
+				coin.UxOut{
+					Body: coin.UxBody{
+						SrcTransaction: srcTxns[5],
+						Address:        allAddrs[1],
+					},
+				},
+				coin.UxOut{
+					Body: coin.UxBody{
+						SrcTransaction: srcTxns[6],
+						Address:        allAddrs[3],
+					},
+				},
+			},
+			getUnspentHashesOfAddrs: blockdb.AddressHashes{
+				allAddrs[1]: hashes[0:2],
+				allAddrs[3]: hashes[2:4],
+			},
+			expectedAuxs: coin.AddressUxOuts{
+				allAddrs[1]: []coin.UxOut{
+					{
+						Body: coin.UxBody{
+							SrcTransaction: srcTxns[5],
+							Address:        allAddrs[1],
+						},
+					},
+				},
+				allAddrs[3]: []coin.UxOut{
+					{
+						Body: coin.UxBody{
+							SrcTransaction: srcTxns[6],
+							Address:        allAddrs[3],
+						},
+					},
+				},
+			},
+		},
+
+		{
+			name:    "err, all uxouts excluded",
+			addrs:   allAddrs,
+			exclude: hashes[0:4],
+			err:     ErrNoSpendableOutputs,
+			getUnspentHashesOfAddrs: blockdb.AddressHashes{
+				allAddrs[1]: hashes[0:2],
+				allAddrs[3]: hashes[2:4],
+			},
+		},
+
 		{
 			name:       "err, unconfirmed spends",
 			addrs:      allAddrs,
@@ -1556,7 +1893,7 @@ func TestGetCreateTransactionAuxsAddress(t *testing.T) {
 			var auxs coin.AddressUxOuts
 			err := v.db.View("", func(tx *dbutil.Tx) error {
 				var err error
-				auxs, err = v.getCreateTransactionAuxsAddress(tx, tc.addrs, tc.ignoreUnconfirmed)
+				auxs, err = v.getCreateTransactionAuxsAddress(tx, tc.addrs, tc.exclude, tc.ignoreUnconfirmed)
 				return err
 			})
 
@@ -1572,6 +1909,115 @@ func TestGetCreateTransactionAuxsAddress(t *testing.T) {
 	}
 }
 
+func TestVerifyNoAddressReuse(t *testing.T) {
+	spentAddr := testutil.MakeAddress()
+	changeAddr := testutil.MakeAddress()
+	toAddr := testutil.MakeAddress()
+
+	uxb := []transaction.UxBalance{
+		{
+			Hash:    testutil.RandSHA256(t),
+			Address: spentAddr,
+		},
+	}
+
+	cases := []struct {
+		name            string
+		changeAddress   *cipher.Address
+		to              cipher.Address
+		seen            bool
+		unconfirmedTxns coin.Transactions
+		err             error
+	}{
+		{
+			name:          "ok",
+			changeAddress: &changeAddr,
+			to:            toAddr,
+		},
+		{
+			name: "err, no change address",
+			to:   toAddr,
+			err:  ErrChangeAddressRequired,
+		},
+		{
+			name:          "err, receiver is a spent address",
+			changeAddress: &changeAddr,
+			to:            spentAddr,
+			err:           ErrReceiverAddressReused,
+		},
+		{
+			name:          "err, change address is a spent address",
+			changeAddress: &spentAddr,
+			to:            toAddr,
+			err:           ErrChangeAddressReused,
+		},
+		{
+			name:          "err, change address seen in the blockchain",
+			changeAddress: &changeAddr,
+			to:            toAddr,
+			seen:          true,
+			err:           ErrChangeAddressReused,
+		},
+		{
+			name:          "err, change address received coins in the unconfirmed pool",
+			changeAddress: &changeAddr,
+			to:            toAddr,
+			unconfirmedTxns: coin.Transactions{
+				{
+					Out: []coin.TransactionOutput{
+						{
+							Address: changeAddr,
+							Coins:   1e6,
+						},
+					},
+				},
+			},
+			err: ErrChangeAddressReused,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, shutdown := testutil.PrepareDB(t)
+			defer shutdown()
+
+			history := &MockHistoryer{}
+			unconfirmed := &MockUnconfirmedTransactionPooler{}
+
+			v := &Visor{
+				history:     history,
+				unconfirmed: unconfirmed,
+				db:          db,
+			}
+
+			history.On("AddressSeen", matchDBTx, changeAddr).Return(tc.seen, nil)
+			unconfirmed.On("ForEach", matchDBTx, mock.Anything).Return(func(tx *dbutil.Tx, f func(cipher.SHA256, UnconfirmedTransaction) error) error {
+				for _, txn := range tc.unconfirmedTxns {
+					if err := f(txn.Hash(), UnconfirmedTransaction{Transaction: txn}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			p := transaction.Params{
+				ChangeAddress: tc.changeAddress,
+				To: []coin.TransactionOutput{
+					{
+						Address: tc.to,
+						Coins:   1e6,
+					},
+				},
+			}
+
+			err := v.db.View("", func(tx *dbutil.Tx) error {
+				return v.verifyNoAddressReuse(tx, p, uxb)
+			})
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
 var matchDBTx = mock.MatchedBy(func(tx *dbutil.Tx) bool {
 	return true
 })
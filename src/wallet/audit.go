@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// AuditReport is the result of the integrity audit of a wallet
+type AuditReport struct {
+	Type string `json:"type"`
+	// Entries is the number of audited entries
+	Entries int `json:"entries"`
+	// Derived is true if the addresses were re-derived from the seed or the xpub key of the wallet
+	Derived bool `json:"derived"`
+	// Issues are the inconsistencies found in the wallet, the wallet is consistent if there are none
+	Issues []string `json:"issues"`
+}
+
+// auditChain is a list of entries of the wallet, selected by options
+type auditChain struct {
+	name    string
+	options []Option
+}
+
+// Audit checks the integrity of a wallet, so that a corrupted wallet file is found before spending from it.
+// The keys of every entry must match its address and the addresses must be unique. The addresses of the wallets
+// generated from a seed or an xpub key must be the addresses derived at their index, and the accounts of a bip44
+// wallet must be derived from the seed and seed passphrase, i.e. match the accountsHash.
+// The wallet must be decrypted. The inconsistencies are returned in the report.
+func Audit(w Wallet) (*AuditReport, error) {
+	if w.IsEncrypted() {
+		return nil, ErrWalletEncrypted
+	}
+
+	report := &AuditReport{
+		Type:   w.Type(),
+		Issues: []string{},
+	}
+
+	derived, err := deriveAuditWallet(w)
+	if err != nil {
+		return nil, err
+	}
+	if derived != nil {
+		defer derived.Erase()
+		report.Derived = true
+	}
+
+	if v, ok := w.(SeedPassphraseVerifier); ok && w.Seed() != "" {
+		ok, err := v.VerifySeedPassphrase(w.SeedPassphrase())
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			report.Issues = append(report.Issues, "the accounts are not derived from the seed and seed passphrase, the accountsHash does not match")
+		}
+	}
+
+	// The accounts are derived at consecutive indexes
+	if derived != nil {
+		for i, a := range w.Accounts() {
+			if a.Index != uint32(i) {
+				report.Issues = append(report.Issues, fmt.Sprintf("account %d is at index %d", i, a.Index))
+				derived = nil
+				break
+			}
+		}
+	}
+
+	addrs := make(map[string]string)
+	for _, c := range auditChains(w) {
+		entries, err := w.GetEntries(c.options...)
+		if err != nil {
+			return nil, err
+		}
+		report.Entries += len(entries)
+
+		var derivedEntries Entries
+		if derived != nil {
+			derivedEntries, err = deriveAuditEntries(derived, c, len(entries))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for i, e := range entries {
+			name := fmt.Sprintf("%s entry %d", c.name, i)
+			issue := func(format string, a ...interface{}) {
+				report.Issues = append(report.Issues, fmt.Sprintf("%s %s: %s", name, e.Address, fmt.Sprintf(format, a...)))
+			}
+
+			if err := auditEntry(e); err != nil {
+				issue("%v", err)
+			}
+
+			if first, ok := addrs[e.Address.String()]; ok {
+				issue("duplicate of %s", first)
+			} else {
+				addrs[e.Address.String()] = name
+			}
+
+			if derivedEntries == nil {
+				continue
+			}
+
+			de := derivedEntries[i]
+			switch {
+			case e.Address.String() != de.Address.String():
+				issue("the derived address is %s", de.Address)
+			case e.Public != de.Public:
+				issue("the public key is not the derived public key")
+			case !e.Secret.Equal(de.Secret):
+				issue("the secret key is not the derived secret key")
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// auditEntry checks that the keys of the entry match its address, the entries of watch-only wallets have no keys
+func auditEntry(e Entry) error {
+	switch {
+	case !e.Secret.Null():
+		return e.Verify()
+	case e.Public != cipher.PubKey{}:
+		return e.VerifyPublic()
+	default:
+		return nil
+	}
+}
+
+// auditChains returns the chains of entries of the wallet
+func auditChains(w Wallet) []auditChain {
+	switch {
+	case w.Type() == WalletTypeBip44:
+		var chains []auditChain
+		for _, a := range w.Accounts() {
+			chains = append(chains, auditChain{
+				name:    fmt.Sprintf("account %d external chain", a.Index),
+				options: []Option{OptionAccount(a.Index), OptionExternal()},
+			}, auditChain{
+				name:    fmt.Sprintf("account %d change chain", a.Index),
+				options: []Option{OptionAccount(a.Index), OptionChange()},
+			})
+		}
+		return chains
+	case w.Type() == WalletTypeXPub && w.IsXPubAccount():
+		return []auditChain{
+			{name: "external chain", options: []Option{OptionExternal()}},
+			{name: "change chain", options: []Option{OptionChange()}},
+		}
+	default:
+		return []auditChain{{name: "address"}}
+	}
+}
+
+// deriveAuditWallet creates a wallet from the seed or the xpub key of the wallet, with the accounts of the wallet.
+// Returns nil if the wallet type does not derive its addresses.
+func deriveAuditWallet(w Wallet) (Wallet, error) {
+	options := Options{
+		Type:            w.Type(),
+		Coin:            w.Coin(),
+		AddressEncoding: w.AddressEncoding(),
+	}
+
+	switch w.Type() {
+	case WalletTypeDeterministic:
+		options.Seed = w.Seed()
+	case WalletTypeBip44:
+		options.Seed = w.Seed()
+		options.SeedPassphrase = w.SeedPassphrase()
+		options.SeedLanguage = w.SeedLanguage()
+		options.Bip44Coin = w.Bip44Coin()
+		options.Bip44PathTemplate = w.Bip44PathTemplate()
+	case WalletTypeXPub:
+		options.XPub = w.XPub()
+		options.XPubAccount = w.IsXPubAccount()
+	default:
+		return nil, nil
+	}
+
+	if options.Type != WalletTypeXPub && options.Seed == "" {
+		return nil, ErrMissingSeed
+	}
+
+	dw, err := NewWallet(w.Filename(), w.Label(), options.Seed, options)
+	if err != nil {
+		return nil, fmt.Errorf("derive the wallet failed: %v", err)
+	}
+
+	if am, ok := dw.(AccountManager); ok {
+		for _, a := range w.Accounts() {
+			if a.Index == 0 {
+				continue
+			}
+			if _, err := am.NewAccount(a.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return dw, nil
+}
+
+// deriveAuditEntries returns the first n entries of the chain of the derived wallet
+func deriveAuditEntries(dw Wallet, c auditChain, n int) (Entries, error) {
+	existing, err := dw.EntriesLen(c.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if n > existing {
+		if _, err := dw.GenerateAddresses(uint64(n-existing), c.options...); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := dw.GetEntries(c.options...)
+	if err != nil {
+		return nil, err
+	}
+	return entries[:n], nil
+}
@@ -0,0 +1,147 @@
+package wallet_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/bip44wallet"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+	"github.com/skycoin/skycoin/src/wallet/deterministic"
+)
+
+func TestAudit(t *testing.T) {
+	seed := bip39.MustNewDefaultMnemonic()
+
+	t.Run("deterministic", func(t *testing.T) {
+		w, err := wallet.NewWallet("t.wlt", "t", "foo", wallet.Options{
+			Type:      wallet.WalletTypeDeterministic,
+			Seed:      "foo",
+			GenerateN: 3,
+		})
+		require.NoError(t, err)
+
+		report, err := wallet.Audit(w)
+		require.NoError(t, err)
+		require.Equal(t, &wallet.AuditReport{
+			Type:    wallet.WalletTypeDeterministic,
+			Entries: 3,
+			Derived: true,
+			Issues:  []string{},
+		}, report)
+
+		entries, err := w.GetEntries()
+		require.NoError(t, err)
+
+		// Replace the second entry with a key pair of another seed, and the third entry with the first entry
+		_, seckeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("bar"), 1)
+		other := wallet.Entry{
+			Secret: wallet.NewSecretKey(seckeys[0]),
+			Public: cipher.MustPubKeyFromSecKey(seckeys[0]),
+		}
+		other.Address = cipher.AddressFromPubKey(other.Public)
+
+		data, err := w.Serialize()
+		require.NoError(t, err)
+		var rw map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &rw))
+		res := rw["entries"].([]interface{})
+		res[1] = map[string]interface{}{
+			"address":    other.Address.String(),
+			"public_key": other.Public.Hex(),
+			"secret_key": other.Secret.Hex(),
+		}
+		res[2] = res[0]
+		data, err = json.Marshal(rw)
+		require.NoError(t, err)
+
+		corrupted := &deterministic.Wallet{}
+		require.NoError(t, corrupted.Deserialize(data))
+
+		report, err = wallet.Audit(corrupted)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"address entry 1 " + other.Address.String() + ": the derived address is " + entries[1].Address.String(),
+			"address entry 2 " + entries[0].Address.String() + ": duplicate of address entry 0",
+			"address entry 2 " + entries[0].Address.String() + ": the derived address is " + entries[2].Address.String(),
+		}, report.Issues)
+	})
+
+	t.Run("bip44", func(t *testing.T) {
+		w, err := wallet.NewWallet("t.wlt", "t", seed, wallet.Options{
+			Type:           wallet.WalletTypeBip44,
+			Seed:           seed,
+			SeedPassphrase: "pass",
+			GenerateN:      2,
+		})
+		require.NoError(t, err)
+
+		_, err = w.(wallet.AccountManager).NewAccount("second")
+		require.NoError(t, err)
+		_, err = w.GenerateAddresses(3, wallet.OptionAccount(1))
+		require.NoError(t, err)
+
+		report, err := wallet.Audit(w)
+		require.NoError(t, err)
+		require.Equal(t, &wallet.AuditReport{
+			Type:    wallet.WalletTypeBip44,
+			Entries: 6,
+			Derived: true,
+			Issues:  []string{},
+		}, report)
+
+		// The accounts are not derived with the seed passphrase of the wallet
+		w.(*bip44wallet.Wallet).SetSeedPassphrase("other")
+
+		report, err = wallet.Audit(w)
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 7)
+		require.Equal(t, "the accounts are not derived from the seed and seed passphrase, the accountsHash does not match", report.Issues[0])
+		require.Contains(t, report.Issues[1], "account 0 external chain entry 0 ")
+		require.Contains(t, report.Issues[6], "account 1 external chain entry 2 ")
+	})
+
+	t.Run("xpub", func(t *testing.T) {
+		w, err := wallet.NewWallet("t.wlt", "t", "", wallet.Options{
+			Type:      wallet.WalletTypeXPub,
+			XPub:      "xpub6EFYYRQeAbWLdWQYbtQv8HnemieKNmYUE23RmwphgtMLjz4UaStKADSKNoSSXM5FDcq4gZec2q6n7kdNWfuMdScxK1cXm8tR37kaitHtvuJ",
+			GenerateN: 2,
+		})
+		require.NoError(t, err)
+
+		report, err := wallet.Audit(w)
+		require.NoError(t, err)
+		require.Equal(t, &wallet.AuditReport{
+			Type:    wallet.WalletTypeXPub,
+			Entries: 2,
+			Derived: true,
+			Issues:  []string{},
+		}, report)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		w, err := wallet.NewWallet("t.wlt", "t", "foo", wallet.Options{
+			Type:       wallet.WalletTypeDeterministic,
+			Seed:       "foo",
+			GenerateN:  1,
+			Encrypt:    true,
+			Password:   []byte("pwd"),
+			CryptoType: crypto.CryptoTypeSha256Xor,
+		})
+		require.NoError(t, err)
+
+		_, err = wallet.Audit(w)
+		require.Equal(t, wallet.ErrWalletEncrypted, err)
+
+		require.NoError(t, wallet.GuardView(w, []byte("pwd"), func(w wallet.Wallet) error {
+			report, err := wallet.Audit(w)
+			require.NoError(t, err)
+			require.Empty(t, report.Issues)
+			return nil
+		}))
+	})
+}
@@ -0,0 +1,255 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher/secret"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+// BackupVersion is the version of the wallet backup bundle format
+const BackupVersion = 1
+
+// BackupCryptoType is the crypto type of the created wallet backup bundles,
+// the bundles are restored with the crypto type recorded in the bundle
+var BackupCryptoType = crypto.DefaultCryptoType
+
+// BackupConflict is the resolution of a restored wallet whose filename already exists in the wallet directory
+type BackupConflict string
+
+const (
+	// BackupConflictSkip keeps the existing wallet, the wallet of the bundle is not restored
+	BackupConflictSkip BackupConflict = "skip"
+	// BackupConflictOverwrite overwrites the existing wallet with the wallet of the bundle
+	BackupConflictOverwrite BackupConflict = "overwrite"
+	// BackupConflictRename restores the wallet of the bundle with a new filename
+	BackupConflictRename BackupConflict = "rename"
+)
+
+var (
+	// ErrBackupVersion is returned when restoring a backup bundle of an unsupported version
+	ErrBackupVersion = NewError(errors.New("unsupported wallet backup version"))
+	// ErrInvalidBackupConflict is returned when restoring a backup bundle with an unknown conflict resolution
+	ErrInvalidBackupConflict = NewError(errors.New("invalid backup conflict resolution"))
+)
+
+// backupFile is the envelope of a backup bundle, the bundle is encrypted with the backup password
+type backupFile struct {
+	Version    int               `json:"version"`
+	CryptoType crypto.CryptoType `json:"crypto_type"`
+	Data       []byte            `json:"data"`
+}
+
+type backupBundle struct {
+	// CreatedAt is the unix time the bundle was created
+	CreatedAt int64          `json:"created_at"`
+	Wallets   []backupWallet `json:"wallets"`
+}
+
+// backupWallet is a wallet file of the bundle, with its index entry as the wallet metadata
+type backupWallet struct {
+	Entry IndexEntry `json:"entry"`
+	// Data is the wallet file, in the format it had in the wallet directory
+	Data []byte `json:"data"`
+}
+
+// ExportBackup packs all wallet files of the wallet directory, with their metadata,
+// into a single versioned bundle encrypted with the password.
+// The encrypted wallets stay encrypted with their own passwords in the bundle.
+func ExportBackup(dir string, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	filenames, err := filterDir(dir, "."+WalletExt)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := backupBundle{
+		CreatedAt: time.Now().Unix(),
+		Wallets:   make([]backupWallet, 0, len(filenames)),
+	}
+	for _, filename := range filenames {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		w, err := Load(filename)
+		if err != nil {
+			return nil, fmt.Errorf("load wallet %q failed: %v", filepath.Base(filename), err)
+		}
+		if w == nil {
+			logger.WithField("filename", filename).Warning("ExportBackup: skipping wallet of unknown type")
+			continue
+		}
+
+		e, err := newIndexEntry(w, fi)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Wallets = append(bundle.Wallets, backupWallet{
+			Entry: e,
+			Data:  data,
+		})
+	}
+
+	b, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bundle has the secrets of the unencrypted wallets, so it's only held in a secret.Buffer
+	buf := secret.FromBytes(b)
+	defer buf.Destroy()
+
+	cryptor, err := crypto.GetCrypto(BackupCryptoType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := cryptor.Encrypt(buf.Bytes(), password)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(backupFile{
+		Version:    BackupVersion,
+		CryptoType: BackupCryptoType,
+		Data:       data,
+	}, "", "    ")
+}
+
+// BackupEntries returns the metadata of the wallets of the backup bundle, without restoring them
+func BackupEntries(data, password []byte) ([]IndexEntry, error) {
+	bundle, err := decryptBackup(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	es := make([]IndexEntry, len(bundle.Wallets))
+	for i, bw := range bundle.Wallets {
+		es[i] = bw.Entry
+	}
+	return es, nil
+}
+
+// ImportBackup restores the wallets of the backup bundle to the wallet directory,
+// the wallets whose filename already exists are resolved with conflict.
+// All wallets of the bundle are checked before any of them is restored.
+// Returns the filenames of the restored wallets, which are new for the renamed wallets.
+// The wallet directory must not be used by a running Service, whose wallets would not be reloaded.
+func ImportBackup(dir string, data, password []byte, conflict BackupConflict) ([]string, error) {
+	switch conflict {
+	case BackupConflictSkip, BackupConflictOverwrite, BackupConflictRename:
+	default:
+		return nil, ErrInvalidBackupConflict
+	}
+
+	bundle, err := decryptBackup(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bw := range bundle.Wallets {
+		name := bw.Entry.Filename
+		if name == "" || filepath.Base(name) != name || filepath.Ext(name) != "."+WalletExt {
+			return nil, fmt.Errorf("invalid wallet filename %q in backup", name)
+		}
+
+		if _, err := loadData(bw.Data); err != nil {
+			return nil, fmt.Errorf("invalid wallet %q in backup: %v", name, err)
+		}
+	}
+
+	var restored []string
+	for _, bw := range bundle.Wallets {
+		name := bw.Entry.Filename
+		ok, err := file.Exists(filepath.Join(dir, name))
+		if err != nil {
+			return restored, err
+		}
+
+		if ok {
+			switch conflict {
+			case BackupConflictSkip:
+				logger.WithField("filename", name).Info("ImportBackup: skipping existing wallet")
+				continue
+			case BackupConflictRename:
+				name, err = uniqueWalletFilename(dir)
+				if err != nil {
+					return restored, err
+				}
+			}
+		}
+
+		if err := file.SaveBinary(filepath.Join(dir, name), bw.Data, 0600); err != nil {
+			return restored, err
+		}
+		restored = append(restored, name)
+	}
+
+	return restored, nil
+}
+
+func decryptBackup(data, password []byte) (*backupBundle, error) {
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	var f backupFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid wallet backup: %v", err)
+	}
+
+	if f.Version != BackupVersion {
+		return nil, ErrBackupVersion
+	}
+
+	cryptor, err := crypto.GetCrypto(f.CryptoType)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := cryptor.Decrypt(f.Data, password)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	buf := secret.FromBytes(b)
+	defer buf.Destroy()
+
+	var bundle backupBundle
+	if err := json.Unmarshal(buf.Bytes(), &bundle); err != nil {
+		return nil, fmt.Errorf("invalid wallet backup: %v", err)
+	}
+	return &bundle, nil
+}
+
+// uniqueWalletFilename returns a new wallet filename that does not exist in the wallet directory
+func uniqueWalletFilename(dir string) (string, error) {
+	for {
+		name := NewWalletFilename()
+		ok, err := file.Exists(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return name, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package wallet_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+func TestBackup(t *testing.T) {
+	ct := wallet.BackupCryptoType
+	wallet.BackupCryptoType = crypto.CryptoTypeScryptChacha20poly1305Insecure
+	defer func() {
+		wallet.BackupCryptoType = ct
+	}()
+
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreateWallet("a.wlt", wallet.Options{
+		Label: "a",
+		Seed:  "seed a",
+		Type:  wallet.WalletTypeDeterministic,
+	})
+	require.NoError(t, err)
+	_, err = s.CreateWallet("b.wlt", wallet.Options{
+		Label:    "b",
+		Seed:     "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+		Type:     wallet.WalletTypeBip44,
+		Encrypt:  true,
+		Password: []byte("wallet pwd"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.UnlockWalletDir())
+
+	password := []byte("backup pwd")
+	_, err = wallet.ExportBackup(dir, nil)
+	require.Equal(t, wallet.ErrMissingPassword, err)
+
+	data, err := wallet.ExportBackup(dir, password)
+	require.NoError(t, err)
+
+	// The bundle is versioned and encrypted
+	var f map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &f))
+	require.Equal(t, float64(wallet.BackupVersion), f["version"])
+	require.NotContains(t, string(data), "seed a")
+
+	es, err := wallet.BackupEntries(data, password)
+	require.NoError(t, err)
+	require.Len(t, es, 2)
+	require.Equal(t, "a.wlt", es[0].Filename)
+	require.Equal(t, "a", es[0].Label)
+	require.False(t, es[0].Encrypted)
+	require.Equal(t, "b.wlt", es[1].Filename)
+	require.Equal(t, wallet.WalletTypeBip44, es[1].Type)
+	require.True(t, es[1].Encrypted)
+
+	_, err = wallet.BackupEntries(data, []byte("wrong"))
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	readFile := func(dir, name string) []byte {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return b
+	}
+
+	tt := []struct {
+		name     string
+		conflict wallet.BackupConflict
+		existing bool
+		restored int
+		err      error
+	}{
+		{
+			name:     "empty dir",
+			conflict: wallet.BackupConflictSkip,
+			restored: 2,
+		},
+		{
+			name:     "skip",
+			conflict: wallet.BackupConflictSkip,
+			existing: true,
+		},
+		{
+			name:     "overwrite",
+			conflict: wallet.BackupConflictOverwrite,
+			existing: true,
+			restored: 2,
+		},
+		{
+			name:     "rename",
+			conflict: wallet.BackupConflictRename,
+			existing: true,
+			restored: 2,
+		},
+		{
+			name:     "invalid conflict",
+			conflict: "merge",
+			err:      wallet.ErrInvalidBackupConflict,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rdir := prepareWltDir()
+			if tc.existing {
+				for _, name := range []string{"a.wlt", "b.wlt"} {
+					require.NoError(t, ioutil.WriteFile(filepath.Join(rdir, name), []byte("existing"), 0600))
+				}
+			}
+
+			names, err := wallet.ImportBackup(rdir, data, password, tc.conflict)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+			require.Len(t, names, tc.restored)
+
+			switch {
+			case tc.conflict == wallet.BackupConflictRename:
+				require.NotContains(t, names, "a.wlt")
+				require.Equal(t, []byte("existing"), readFile(rdir, "a.wlt"))
+			case tc.restored == 0:
+				require.Equal(t, []byte("existing"), readFile(rdir, "a.wlt"))
+				require.Equal(t, []byte("existing"), readFile(rdir, "b.wlt"))
+				return
+			default:
+				require.Equal(t, []string{"a.wlt", "b.wlt"}, names)
+			}
+
+			// The restored wallets load as the wallets they were backed up from
+			for i, name := range names {
+				require.Equal(t, readFile(dir, es[i].Filename), readFile(rdir, name))
+				w, err := wallet.Load(filepath.Join(rdir, name))
+				require.NoError(t, err)
+				require.Equal(t, es[i].Label, w.Label())
+			}
+		})
+	}
+
+	_, err = wallet.ImportBackup(prepareWltDir(), data, []byte("wrong"), wallet.BackupConflictSkip)
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	_, err = wallet.ImportBackup(prepareWltDir(), []byte(`{"version":2}`), password, wallet.BackupConflictSkip)
+	require.Equal(t, wallet.ErrBackupVersion, err)
+}
@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+)
+
+var (
+	// ErrWalletMigrated is returned when migrating a wallet that was already migrated to a bip44 wallet
+	ErrWalletMigrated = NewError(errors.New("wallet was already migrated to a bip44 wallet"))
+	// ErrNoCoinsToSweep is returned when sweeping a wallet that has no coins
+	ErrNoCoinsToSweep = NewError(errors.New("wallet has no coins to sweep"))
+)
+
+// MigrateToBip44 creates a bip44 wallet from options.Seed to replace the deterministic wallet w,
+// moving the user off the legacy secp256k1 deterministic key derivation scheme.
+// The keys of w can not be derived by a bip44 wallet, so the new wallet generates as many external
+// addresses as w has entries, and the label, note and tags of each entry of w are copied to the
+// address of the same index. The coins of w are moved with CreateSweepTransaction.
+// The label of w is used if options.Label is empty. w is marked read-only and records
+// the filename of the new wallet. The caller is responsible for saving both wallets.
+func MigrateToBip44(w Wallet, filename string, options Options) (Wallet, error) {
+	if w.Type() != WalletTypeDeterministic {
+		return nil, NewError(fmt.Errorf("%s wallets can not be migrated to bip44 wallets", w.Type()))
+	}
+	if w.MigratedTo() != "" {
+		return nil, ErrWalletMigrated
+	}
+	if w.IsReadOnly() {
+		return nil, ErrWalletReadOnly
+	}
+	if options.Seed == "" {
+		return nil, ErrMissingSeed
+	}
+
+	entries, err := w.GetEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	label := options.Label
+	if label == "" {
+		label = w.Label()
+	}
+
+	options.Type = WalletTypeBip44
+	options.Coin = w.Coin()
+	options.GenerateN = uint64(len(entries))
+	if options.GenerateN == 0 {
+		options.GenerateN = 1
+	}
+
+	// The entry metas are copied before encrypting the wallet
+	encrypt := options.Encrypt
+	password := options.Password
+	options.Encrypt = false
+	options.Password = nil
+
+	nw, err := NewWallet(filename, label, options.Seed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := nw.GetAddresses(OptionExternal())
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range entries {
+		m := e.EntryMeta.Clone()
+		if m.Label == "" && m.Note == "" && len(m.Tags) == 0 {
+			continue
+		}
+
+		if err := nw.SetEntryMeta(addrs[i], m); err != nil {
+			return nil, err
+		}
+	}
+
+	if encrypt {
+		if err := nw.Lock(password); err != nil {
+			return nil, err
+		}
+	}
+
+	w.SetReadOnly(true)
+	w.SetMigratedTo(nw.Filename())
+
+	return nw, nil
+}
+
+// CreateSweepTransaction creates a transaction signed by w that sends all the coins and hours of
+// the unspent outputs auxs to the address to, e.g. to move the coins of a wallet migrated with MigrateToBip44.
+// The hours that are not burned as the fee are sent to the address, the transaction has no change output.
+// Refer to CreateTransactionSigned for the requirements of w and auxs.
+func CreateSweepTransaction(w Wallet, to cipher.Address, auxs coin.AddressUxOuts, headTime uint64) (*coin.Transaction, []transaction.UxBalance, error) {
+	var coins uint64
+	for _, uxs := range auxs {
+		for _, ux := range uxs {
+			var err error
+			coins, err = mathutil.AddUint64(coins, ux.Body.Coins)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if coins == 0 {
+		return nil, nil, ErrNoCoinsToSweep
+	}
+
+	shareFactor := decimal.New(1, 0)
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type:        transaction.HoursSelectionTypeAuto,
+			Mode:        transaction.HoursSelectionModeShare,
+			ShareFactor: &shareFactor,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: to,
+				Coins:   coins,
+			},
+		},
+		// All the coins are spent, the change address is never used
+		ChangeAddress: &to,
+	}
+
+	return CreateTransactionSigned(w, p, auxs, headTime)
+}
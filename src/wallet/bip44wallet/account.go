@@ -8,6 +8,7 @@ import (
 	"github.com/skycoin/skycoin/src/cipher/bip32"
 	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
+	"github.com/skycoin/skycoin/src/cipher/secret"
 	"github.com/skycoin/skycoin/src/util/mathutil"
 	"github.com/skycoin/skycoin/src/wallet"
 )
@@ -23,29 +24,46 @@ type bip44Account struct {
 	Index    uint32          // Account index
 	CoinType wallet.CoinType // Account coin type, determins the way to generate addresses
 	Chains   []bip44Chain    // Chains, external chain with index value of 0, and internal(change) chain with index value of 1.
+	// AddressEncoding is the address encoding of the wallet, it's not stored with the account
+	AddressEncoding wallet.AddressEncoding
 }
 
 type bip44AccountCreateOptions struct {
-	name           string
-	index          uint32
-	seed           string
-	seedPassphrase string
-	coinType       wallet.CoinType
-	bip44CoinType  *bip44.CoinType
+	name            string
+	index           uint32
+	seed            []byte
+	seedPassphrase  []byte
+	coinType        wallet.CoinType
+	addressEncoding wallet.AddressEncoding
+	bip44CoinType   *bip44.CoinType
+	pathTemplate    string // bip32 derivation path template, overrides the bip44 coin type path if not empty
 }
 
 func newBip44Account(opts bip44AccountCreateOptions) (*bip44Account, error) {
 	// opts.seed must return a valid bip39 mnemonic
-	seed, err := bip39.NewSeed(opts.seed, opts.seedPassphrase)
+	s, err := bip39.NewSeedFromBytes(opts.seed, opts.seedPassphrase)
 	if err != nil {
 		return nil, err
 	}
 
+	// The bip39 seed is only kept in guarded memory while the account is derived
+	seed := secret.FromBytes(s)
+	defer seed.Destroy()
+
 	if opts.bip44CoinType == nil {
 		return nil, errors.New("newBip44Account missing bip44 coin type")
 	}
 
-	c, err := bip44.NewCoin(seed, *opts.bip44CoinType)
+	pt := bip44.DefaultPathTemplate(*opts.bip44CoinType)
+	if opts.pathTemplate != "" {
+		t, err := bip44.ParsePathTemplate(opts.pathTemplate)
+		if err != nil {
+			return nil, err
+		}
+		pt = *t
+	}
+
+	c, err := pt.NewCoin(seed.Bytes())
 	if err != nil {
 		logger.Critical().WithError(err).Error("Failed to derive the bip44 purpose node")
 		if bip32.IsImpossibleChildError(err) {
@@ -64,10 +82,11 @@ func newBip44Account(opts bip44AccountCreateOptions) (*bip44Account, error) {
 	}
 
 	ba := &bip44Account{
-		Account:  *a,
-		Name:     opts.name,
-		Index:    opts.index,
-		CoinType: opts.coinType,
+		Account:         *a,
+		Name:            opts.name,
+		Index:           opts.index,
+		CoinType:        opts.coinType,
+		AddressEncoding: opts.addressEncoding,
 	}
 
 	// init the external chain
@@ -83,12 +102,16 @@ func newBip44Account(opts bip44AccountCreateOptions) (*bip44Account, error) {
 	return ba, nil
 }
 
-func (a *bip44Account) newAddresses(chainIndex, num uint32) ([]cipher.Addresser, error) {
+func (a *bip44Account) newAddresses(chainIndex, num uint32, options ...wallet.Option) ([]cipher.Addresser, error) {
 	// chain index can only be 0 or 1.
 	switch chainIndex {
 	case bip44.ExternalChainIndex, bip44.ChangeChainIndex:
-		ad := wallet.ResolveAddressDecoder(a.CoinType)
-		return a.Chains[chainIndex].newAddresses(num, a.PrivateKey, ad.AddressFromPubKey)
+		addressFromPubKey, err := wallet.ResolveAddressConstructor(a.CoinType, a.AddressEncoding)
+		if err != nil {
+			return nil, err
+		}
+		opts := wallet.NewGenerateOptions(options...)
+		return a.Chains[chainIndex].newAddresses(num, a.PrivateKey, addressFromPubKey, opts.Workers)
 	default:
 		return nil, fmt.Errorf("invalid chain index: %d", chainIndex)
 	}
@@ -238,15 +261,26 @@ func (a bip44Account) getEntry(address cipher.Addresser) (wallet.Entry, bool) {
 	return wallet.Entry{}, false
 }
 
+func (a *bip44Account) setEntryMeta(address cipher.Addresser, m wallet.EntryMeta) bool {
+	for _, c := range a.Chains {
+		if c.Entries.SetMeta(address, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Clone clones the bip44Account, it would also hide the
 // bip44.Account.Clone() function so that user would not
 // call it mistakenly.
 func (a bip44Account) Clone() bip44Account {
 	na := bip44Account{
-		Account:  a.Account.Clone(),
-		Name:     a.Name,
-		Index:    a.Index,
-		CoinType: a.CoinType,
+		Account:         a.Account.Clone(),
+		Name:            a.Name,
+		Index:           a.Index,
+		CoinType:        a.CoinType,
+		AddressEncoding: a.AddressEncoding,
 	}
 
 	na.Chains = make([]bip44Chain, len(a.Chains))
@@ -273,32 +307,36 @@ type bip44Chain struct {
 
 // newAddresses generates addresses on the chain.
 // private key is optional, if not provided, addresses will be generated using the public key.
-func (c *bip44Chain) newAddresses(num uint32, seckey *bip32.PrivateKey, addressFromPubKey func(key cipher.PubKey) cipher.Addresser) ([]cipher.Addresser, error) {
+// The child keys are derived by a pool of workers goroutines, one per CPU if workers is 0.
+func (c *bip44Chain) newAddresses(num uint32, seckey *bip32.PrivateKey, addressFromPubKey func(key cipher.PubKey) cipher.Addresser, workers int) ([]cipher.Addresser, error) {
 	if c == nil {
 		return nil, errors.New("can not generate new addresses on nil chain")
 	}
 
-	var addrs []cipher.Addresser
 	initLen := uint32(len(c.Entries))
 	_, err := mathutil.AddUint32(initLen, num)
 	if err != nil {
 		return nil, fmt.Errorf("can not create %d more addresses, current addresses number %d, err: %v", num, initLen, err)
 	}
 
-	for i := uint32(0); i < num; i++ {
-		index := initLen + i
+	if num == 0 {
+		return nil, nil
+	}
+
+	entries := make(wallet.Entries, num)
+	if err := wallet.ParallelDo(int(num), workers, func(i int) error {
+		index := initLen + uint32(i)
 		pk, err := c.PubKey.NewPublicChildKey(index)
 		if err != nil {
-			return nil, fmt.Errorf("bip44 chain generate address with index %d failed, err: %v", index, err)
+			return fmt.Errorf("bip44 chain generate address with index %d failed, err: %v", index, err)
 		}
 		cpk, err := cipher.NewPubKey(pk.Key)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		addr := addressFromPubKey(cpk)
 		e := wallet.Entry{
-			Address:     addr,
+			Address:     addressFromPubKey(cpk),
 			Public:      cpk,
 			ChildNumber: index,
 		}
@@ -306,15 +344,19 @@ func (c *bip44Chain) newAddresses(num uint32, seckey *bip32.PrivateKey, addressF
 		if seckey != nil {
 			k, err := secretFromPrivateKey(seckey, c.ChainIndex, index)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			e.Secret = k
+			e.Secret = wallet.NewSecretKey(k)
 		}
 
-		c.Entries = append(c.Entries, e)
-		addrs = append(addrs, addr)
+		entries[i] = e
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	return addrs, nil
+
+	c.Entries = append(c.Entries, entries...)
+	return entries.GetAddresses(), nil
 }
 
 func (c *bip44Chain) syncSecrets(ss wallet.Secrets, privateKey *bip32.PrivateKey) error {
@@ -372,13 +414,13 @@ func (a bip44Accounts) len() uint32 {
 	return uint32(len(a.accounts))
 }
 
-func (a *bip44Accounts) newAddresses(account, chain, num uint32) ([]cipher.Addresser, error) {
+func (a *bip44Accounts) newAddresses(account, chain, num uint32, options ...wallet.Option) ([]cipher.Addresser, error) {
 	act, err := a.account(account)
 	if err != nil {
 		return nil, err
 	}
 
-	return act.newAddresses(chain, num)
+	return act.newAddresses(chain, num, options...)
 }
 
 // account returns the pinter of the account by index,
@@ -420,6 +462,17 @@ func (a *bip44Accounts) new(opts bip44AccountCreateOptions) (uint32, error) {
 	return accountIndex, nil
 }
 
+// rename sets the name of the account of given index
+func (a *bip44Accounts) rename(index uint32, name string) error {
+	act, err := a.account(index)
+	if err != nil {
+		return err
+	}
+
+	act.Name = name
+	return nil
+}
+
 func (a *bip44Accounts) nextIndex() (uint32, error) {
 	// Try to get next account index, return error if the
 	// account is full.
@@ -516,6 +569,15 @@ func (a bip44Accounts) getEntry(account uint32, address cipher.Addresser) (walle
 	return e, ok, nil
 }
 
+func (a *bip44Accounts) setEntryMeta(account uint32, address cipher.Addresser, m wallet.EntryMeta) (bool, error) {
+	act, err := a.account(account)
+	if err != nil {
+		return false, err
+	}
+
+	return act.setEntryMeta(address, m), nil
+}
+
 func (a *bip44Accounts) syncSecrets(ss wallet.Secrets) error {
 	for _, act := range a.accounts {
 		if err := act.syncSecrets(ss); err != nil {
@@ -542,3 +604,15 @@ func (a *bip44Accounts) reset() {
 		act.reset()
 	}
 }
+
+// hash returns the hex encoded SHA256 hash of the chain public keys of all accounts,
+// the hash identifies the accounts derived from a seed and seed passphrase
+func (a bip44Accounts) hash() string {
+	var b []byte
+	for _, act := range a.accounts {
+		for i := range act.Chains {
+			b = append(b, act.Chains[i].PubKey.Serialize()...)
+		}
+	}
+	return cipher.SumSHA256(b).Hex()
+}
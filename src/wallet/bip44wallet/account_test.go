@@ -177,8 +177,8 @@ func TestNewBip44Account(t *testing.T) {
 			ba, err := newBip44Account(bip44AccountCreateOptions{
 				name:           tc.accountName,
 				index:          uint32(tc.index),
-				seed:           tc.seed,
-				seedPassphrase: testSeedPassphrase,
+				seed:           []byte(tc.seed),
+				seedPassphrase: []byte(testSeedPassphrase),
 				coinType:       tc.coinType,
 				bip44CoinType:  &tc.bip44CoinType,
 			})
@@ -303,8 +303,8 @@ func TestBip44AccountsNewAddresses(t *testing.T) {
 				name:           "Test",
 				coinType:       tc.coinType,
 				bip44CoinType:  &tc.bip44CoinType,
-				seed:           tc.seed,
-				seedPassphrase: tc.seedPassphrase,
+				seed:           []byte(tc.seed),
+				seedPassphrase: []byte(tc.seedPassphrase),
 			})
 			require.NoError(t, err)
 
@@ -332,14 +332,14 @@ func TestBip44AccountsNewAddresses(t *testing.T) {
 					_, err := cipher.DecodeBase58Address(addr.String())
 					require.NoError(t, err)
 
-					addrFromSecKey, err := cipher.AddressFromSecKey(secKey)
+					addrFromSecKey, err := cipher.AddressFromSecKey(secKey.SecKey())
 					require.NoError(t, err)
 					require.Equal(t, addrFromSecKey.String(), addr.String())
 				case wallet.CoinTypeBitcoin:
 					_, err := cipher.DecodeBase58BitcoinAddress(addr.String())
 					require.NoError(t, err)
 
-					addrFromSecKey, err := cipher.BitcoinAddressFromSecKey(secKey)
+					addrFromSecKey, err := cipher.BitcoinAddressFromSecKey(secKey.SecKey())
 					require.NoError(t, err)
 					require.Equal(t, addrFromSecKey.String(), addr.String())
 				}
@@ -369,7 +369,7 @@ func requireBip44AccountEqual(t *testing.T, a, b *bip44Account) {
 			ce := cc.Entries[i]
 			require.Equal(t, e.Address.String(), ce.Address.String())
 			require.Equal(t, e.Public[:], ce.Public[:])
-			require.Equal(t, e.Secret[:], ce.Secret[:])
+			require.Equal(t, e.Secret.SecKey(), ce.Secret.SecKey())
 			require.Equal(t, e.ChildNumber, ce.ChildNumber)
 			require.Equal(t, e.Change, ce.Change)
 		}
@@ -383,8 +383,8 @@ func TestBip44AccountsClone(t *testing.T) {
 		name:           "Test",
 		coinType:       wallet.CoinTypeSkycoin,
 		bip44CoinType:  &bip44CoinType,
-		seed:           testSeed,
-		seedPassphrase: testSeedPassphrase,
+		seed:           []byte(testSeed),
+		seedPassphrase: []byte(testSeedPassphrase),
 	})
 	require.NoError(t, err)
 
@@ -415,8 +415,8 @@ func TestBip44AccountErase(t *testing.T) {
 		name:           "Test",
 		coinType:       wallet.CoinTypeSkycoin,
 		bip44CoinType:  &bip44CoinType,
-		seed:           testSeed,
-		seedPassphrase: testSeedPassphrase,
+		seed:           []byte(testSeed),
+		seedPassphrase: []byte(testSeedPassphrase),
 	})
 	require.NoError(t, err)
 
@@ -443,7 +443,7 @@ func TestBip44AccountErase(t *testing.T) {
 	// Confirms that the secrets in chains are empty
 	for _, c := range a.Chains {
 		for _, e := range c.Entries {
-			require.Equal(t, cipher.SecKey{}, e.Secret)
+			require.True(t, e.Secret.Null())
 		}
 	}
 	// erase multiple times should have no side effect
@@ -459,8 +459,8 @@ func TestBip44AccountPackSecrets(t *testing.T) {
 		name:           "Test",
 		coinType:       wallet.CoinTypeSkycoin,
 		bip44CoinType:  &bip44CoinType,
-		seed:           testSeed,
-		seedPassphrase: testSeedPassphrase,
+		seed:           []byte(testSeed),
+		seedPassphrase: []byte(testSeedPassphrase),
 	})
 	require.NoError(t, err)
 
@@ -495,8 +495,8 @@ func TestBip44AccountUnpackSecrets(t *testing.T) {
 		name:           "Test",
 		coinType:       wallet.CoinTypeSkycoin,
 		bip44CoinType:  &bip44CoinType,
-		seed:           testSeed,
-		seedPassphrase: testSeedPassphrase,
+		seed:           []byte(testSeed),
+		seedPassphrase: []byte(testSeedPassphrase),
 	})
 	require.NoError(t, err)
 
@@ -526,8 +526,8 @@ func TestAccountSyncSecrets(t *testing.T) {
 		name:           "Test",
 		coinType:       wallet.CoinTypeSkycoin,
 		bip44CoinType:  &bip44CoinType,
-		seed:           testSeed,
-		seedPassphrase: testSeedPassphrase,
+		seed:           []byte(testSeed),
+		seedPassphrase: []byte(testSeedPassphrase),
 	})
 	require.NoError(t, err)
 
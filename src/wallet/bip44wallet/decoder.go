@@ -2,16 +2,16 @@ package bip44wallet
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/wallet"
 )
 
-const metaAccountsHash = "metaAccountsHash"
-
 // JSONDecoder implements the Decoder interface,
 // which provides methods for encoding and decoding a bip44 wallet in JSON format.
 type JSONDecoder struct{}
@@ -51,14 +51,28 @@ func newReadableBip44WalletNew(w *Wallet) (*readableBip44WalletNew, error) {
 		return nil, err
 	}
 
+	m := w.Meta.Clone()
+	w.Seeds.ToMeta(m)
 	return &readableBip44WalletNew{
-		Meta:     w.Meta.Clone(),
+		Meta:     m,
 		Accounts: *ra,
 	}, nil
 }
 
 // toWallet converts the readable bip44 wallet to a bip44 wallet
 func (rw readableBip44WalletNew) toWallet() (*Wallet, error) {
+	if s := rw.Meta.Bip44PathTemplate(); s != "" {
+		if _, err := bip44.ParsePathTemplate(s); err != nil {
+			return nil, fmt.Errorf("invalid bip44 path template: %v", err)
+		}
+	}
+
+	if s, ok := rw.Meta[wallet.MetaSeedLanguage]; ok {
+		if l, err := bip39.ParseLanguage(s); err != nil || string(l) != s {
+			return nil, fmt.Errorf("invalid seed language %q", s)
+		}
+	}
+
 	// resolve the coin adapter base on coin type
 	d := wallet.ResolveAddressSecKeyDecoder(rw.Coin())
 
@@ -67,11 +81,22 @@ func (rw readableBip44WalletNew) toWallet() (*Wallet, error) {
 		return nil, err
 	}
 
-	return &Wallet{
+	for _, a := range accounts.accounts {
+		a.AddressEncoding = rw.Meta.AddressEncoding()
+	}
+
+	// wallets created before the accountsHash was added do not have it
+	if h, ok := rw.Meta[wallet.MetaAccountsHash]; ok && h != accounts.hash() {
+		return nil, errors.New("accounts hash mismatch, the wallet accounts are corrupted")
+	}
+
+	w := &Wallet{
 		Meta:           rw.Meta.Clone(),
 		accountManager: accounts,
 		decoder:        &JSONDecoder{},
-	}, nil
+	}
+	w.Seeds = wallet.SeedsFromMeta(w.Meta)
+	return w, nil
 }
 
 // readableBip44Accounts is the JSON representation of accounts
@@ -197,8 +222,9 @@ func newBip44EntryFromReadable(re readableBip44Entry, d wallet.AddressSecKeyDeco
 	return &wallet.Entry{
 		Address:     addr,
 		Public:      p,
-		Secret:      secKey,
+		Secret:      wallet.NewSecretKey(secKey),
 		ChildNumber: re.ChildNumber,
+		EntryMeta:   re.ToEntryMeta(),
 	}, nil
 }
 
@@ -208,6 +234,7 @@ type readableBip44Entry struct {
 	Public      string `json:"public"`
 	Secret      string `json:"secret"`
 	ChildNumber uint32 `json:"child_number"` // For bip32/bip44
+	wallet.ReadableEntryMeta
 }
 
 // newReadableBip44Accounts converts bip44Accounts to ReadableBip44Accounts
@@ -251,14 +278,15 @@ func newReadableBip44Chains(cs []bip44Chain, d wallet.SecKeyDecoder) ([]readable
 		for _, e := range c.Entries {
 			var secret string
 			if !e.Secret.Null() {
-				secret = d.SecKeyToHex(e.Secret)
+				secret = d.SecKeyToHex(e.Secret.SecKey())
 			}
 
 			rc.Entries = append(rc.Entries, readableBip44Entry{
-				Address:     e.Address.String(),
-				Public:      e.Public.Hex(),
-				ChildNumber: e.ChildNumber,
-				Secret:      secret,
+				Address:           e.Address.String(),
+				Public:            e.Public.Hex(),
+				ChildNumber:       e.ChildNumber,
+				Secret:            secret,
+				ReadableEntryMeta: wallet.NewReadableEntryMeta(e.EntryMeta),
 			})
 		}
 		rcs = append(rcs, rc)
@@ -6,6 +6,7 @@ package bip44wallet
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -48,6 +49,8 @@ func init() {
 type Wallet struct {
 	//Meta wallet meta data
 	wallet.Meta
+	// Seeds wallet seed and seed passphrase
+	wallet.Seeds
 	// accounts bip44 wallet accounts
 	accountManager
 	// decoder is used to encode/decode bip44 wallet to/from []byte
@@ -58,8 +61,10 @@ type Wallet struct {
 type accountManager interface {
 	// new creates a new account, returns the account index, and error, if any
 	new(opts bip44AccountCreateOptions) (uint32, error)
+	// rename sets the name of the account of given index
+	rename(index uint32, name string) error
 	// newAddresses generates addresses on selected account
-	newAddresses(account, chain, num uint32) ([]cipher.Addresser, error)
+	newAddresses(account, chain, num uint32, options ...wallet.Option) ([]cipher.Addresser, error)
 	// entries returns entries of specific chain of the selected account
 	entries(account, chain uint32) (wallet.Entries, error)
 	// entriesLen returns the entries length of specific chain of selected account
@@ -68,6 +73,8 @@ type accountManager interface {
 	entryAt(account, chain, index uint32) (wallet.Entry, error)
 	// getEntry returns the entry of given address
 	getEntry(account uint32, address cipher.Addresser) (wallet.Entry, bool, error)
+	// setEntryMeta sets the meta of the entry of given address
+	setEntryMeta(account uint32, address cipher.Addresser, m wallet.EntryMeta) (bool, error)
 	// len returns the account number
 	len() uint32
 	// clone returns a deep clone accounts manager
@@ -86,6 +93,8 @@ type accountManager interface {
 	all() []wallet.Bip44Account
 	// reset reset all accounts' entries
 	reset()
+	// hash returns the hash of the accounts
+	hash() string
 }
 
 // ChainEntry represents an item on the bip44 wallet chain
@@ -100,8 +109,8 @@ func NewWallet(filename, label, seed, seedPassphrase string, options ...wallet.O
 		Meta: wallet.Meta{
 			wallet.MetaFilename:       filename,
 			wallet.MetaLabel:          label,
-			wallet.MetaSeed:           seed,
-			wallet.MetaSeedPassphrase: seedPassphrase,
+			wallet.MetaSeed:           "",
+			wallet.MetaSeedPassphrase: "",
 			wallet.MetaEncrypted:      "false",
 			wallet.MetaType:           WalletType,
 			wallet.MetaVersion:        wallet.Version,
@@ -112,6 +121,8 @@ func NewWallet(filename, label, seed, seedPassphrase string, options ...wallet.O
 		accountManager: &bip44Accounts{},
 		decoder:        defaultWalletDecoder,
 	}
+	wlt.SetSeed(seed)
+	wlt.SetSeedPassphrase(seedPassphrase)
 
 	advOpts := wallet.AdvancedOptions{}
 	// applies options to wallet and AdvancedOptions
@@ -121,18 +132,24 @@ func NewWallet(filename, label, seed, seedPassphrase string, options ...wallet.O
 	}
 
 	if wlt.Bip44Coin() == nil {
-		switch wlt.Coin() {
-		case wallet.CoinTypeSkycoin:
-			wlt.SetBip44Coin(bip44.CoinTypeSkycoin)
-		case wallet.CoinTypeBitcoin:
-			wlt.SetBip44Coin(bip44.CoinTypeBitcoin)
-		default:
+		bc, ok := wallet.ResolveBip44CoinType(wlt.Coin())
+		if !ok {
 			return nil, errors.New("bip44 coin type not set")
 		}
+		wlt.SetBip44Coin(bc)
+	}
+
+	// detects the seed language if it is not set
+	if _, ok := wlt.Meta[wallet.MetaSeedLanguage]; !ok && seed != "" {
+		l, err := bip39.DetectLanguage(seed)
+		if err != nil {
+			return nil, err
+		}
+		wlt.SetSeedLanguage(l)
 	}
 
 	// validateMeta wallet before encrypting
-	if err := validateMeta(wlt.Meta); err != nil {
+	if err := validateMeta(wlt.Meta, wlt.Seeds); err != nil {
 		return nil, err
 	}
 
@@ -179,6 +196,13 @@ func NewWallet(filename, label, seed, seedPassphrase string, options ...wallet.O
 		}
 	}
 
+	// scans all used addresses if options.GapLimit > 0
+	if advOpts.GapLimit > 0 {
+		if _, err := wlt.ScanAddressesGapLimit(advOpts.GapLimit, advOpts.TF); err != nil {
+			return nil, err
+		}
+	}
+
 	// encrypts wallet if options.Encrypt is true
 	if advOpts.Encrypt {
 		if len(advOpts.Password) == 0 {
@@ -191,13 +215,13 @@ func NewWallet(filename, label, seed, seedPassphrase string, options ...wallet.O
 	}
 
 	// validateMeta the wallet again after encrypted
-	if err := validateMeta(wlt.Meta); err != nil {
+	if err := validateMeta(wlt.Meta, wlt.Seeds); err != nil {
 		return nil, err
 	}
 	return wlt, nil
 }
 
-func validateMeta(m wallet.Meta) error {
+func validateMeta(m wallet.Meta, seeds wallet.Seeds) error {
 	if m[wallet.MetaType] != WalletType {
 		return wallet.ErrInvalidWalletType
 	}
@@ -208,12 +232,24 @@ func validateMeta(m wallet.Meta) error {
 		return fmt.Errorf("invalid bip44 coin type: %v", err)
 	}
 
+	if s := m[wallet.MetaBip44PathTemplate]; s != "" {
+		if _, err := bip44.ParsePathTemplate(s); err != nil {
+			return fmt.Errorf("invalid bip44 path template: %v", err)
+		}
+	}
+
+	if s, ok := m[wallet.MetaSeedLanguage]; ok {
+		if l, err := bip39.ParseLanguage(s); err != nil || string(l) != s {
+			return fmt.Errorf("invalid seed language %q", s)
+		}
+	}
+
 	if err := wallet.ValidateMeta(m); err != nil {
 		return err
 	}
 
-	if s := m[wallet.MetaSeed]; s != "" {
-		if err := bip39.ValidateMnemonic(s); err != nil {
+	if s := seeds.SeedBytes(); len(s) != 0 {
+		if err := bip39.ValidateMnemonicBytesInLanguage(s, m.SeedLanguage()); err != nil {
 			return err
 		}
 	}
@@ -222,7 +258,7 @@ func validateMeta(m wallet.Meta) error {
 		return err
 	}
 
-	return wallet.ValidateMetaSeed(m)
+	return wallet.ValidateMetaSeed(m, seeds)
 }
 
 // SetDecoder sets the wallet decoder
@@ -231,15 +267,69 @@ func (w *Wallet) SetDecoder(d wallet.Decoder) {
 }
 
 // NewAccount create a bip44 wallet account, returns account index and
-// error, if any.
+// error, if any. The account private key is derived from the seed, so
+// encrypted wallets must be unlocked.
 func (w *Wallet) NewAccount(name string) (uint32, error) {
-	return w.accountManager.new(bip44AccountCreateOptions{
-		name:           name,
-		seed:           w.Seed(),
-		seedPassphrase: w.SeedPassphrase(),
-		coinType:       w.Coin(),
-		bip44CoinType:  w.Bip44Coin(),
+	if w.IsEncrypted() {
+		return 0, wallet.ErrWalletEncrypted
+	}
+
+	index, err := w.accountManager.new(bip44AccountCreateOptions{
+		name:            name,
+		seed:            w.SeedBytes(),
+		seedPassphrase:  w.SeedPassphraseBytes(),
+		coinType:        w.Coin(),
+		addressEncoding: w.AddressEncoding(),
+		bip44CoinType:   w.Bip44Coin(),
+		pathTemplate:    w.Bip44PathTemplate(),
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	w.Meta[wallet.MetaAccountsHash] = w.accountManager.hash()
+	return index, nil
+}
+
+// SetAccountName sets the name of the account of given index
+func (w *Wallet) SetAccountName(index uint32, name string) error {
+	return w.accountManager.rename(index, name)
+}
+
+// VerifySeedPassphrase returns whether the seed passphrase is the one the accounts of the wallet
+// were derived with, by comparing the hash of the accounts derived from the seed and the passphrase
+// with the accountsHash of the wallet. The seed is required, so encrypted wallets must be unlocked.
+func (w *Wallet) VerifySeedPassphrase(passphrase string) (bool, error) {
+	if w.IsEncrypted() {
+		return false, wallet.ErrWalletEncrypted
+	}
+
+	if len(w.SeedBytes()) == 0 {
+		return false, wallet.ErrMissingSeed
+	}
+
+	// wallets created before the accountsHash was added do not have it
+	h, ok := w.Meta[wallet.MetaAccountsHash]
+	if !ok {
+		h = w.accountManager.hash()
+	}
+
+	as := &bip44Accounts{}
+	for _, a := range w.accountManager.all() {
+		if _, err := as.new(bip44AccountCreateOptions{
+			name:            a.Name,
+			seed:            w.SeedBytes(),
+			seedPassphrase:  []byte(passphrase),
+			coinType:        w.Coin(),
+			addressEncoding: w.AddressEncoding(),
+			bip44CoinType:   w.Bip44Coin(),
+			pathTemplate:    w.Bip44PathTemplate(),
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	return as.hash() == h, nil
 }
 
 // newExternalAddresses generates addresses on external chain of selected account
@@ -338,22 +428,17 @@ func (w *Wallet) Lock(password []byte) error {
 
 	wlt.packSecrets(ss)
 
-	sb, err := ss.Serialize()
-	if err != nil {
-		return err
-	}
-
 	cryptoType := wlt.Meta.CryptoType()
 	if cryptoType == "" {
 		cryptoType = crypto.DefaultCryptoType
 	}
 
-	cryptor, err := crypto.GetCrypto(cryptoType)
+	cryptor, err := wallet.GetCryptor(cryptoType, wlt.Meta)
 	if err != nil {
 		return err
 	}
 
-	encSecret, err := cryptor.Encrypt(sb, password)
+	encSecret, err := ss.Encrypt(cryptor, password)
 	if err != nil {
 		return err
 	}
@@ -391,45 +476,26 @@ func (w *Wallet) Unlock(password []byte) (wallet.Wallet, error) {
 		return nil, errors.New("missing crypto type")
 	}
 
-	cryptor, err := crypto.GetCrypto(ct)
+	cryptor, err := wallet.GetCryptor(ct, w.Meta)
 	if err != nil {
 		return nil, err
 	}
 
-	sb, err := cryptor.Decrypt([]byte(sstr), password)
+	ss, err := wallet.DecryptSecrets(cryptor, []byte(sstr), password)
 	if err != nil {
-		return nil, wallet.ErrInvalidPassword
-	}
-
-	defer func() {
-		// Wipes the data from secrets bytes buffer
-		for i := range sb {
-			sb[i] = 0
-		}
-	}()
-
-	ss := make(wallet.Secrets)
-	defer ss.Erase()
-	if err := ss.Deserialize(sb); err != nil {
 		return nil, err
 	}
-
-	cw := w.Clone().(*Wallet)
+	defer ss.Erase()
 
 	initSSLen := len(ss)
-	// fills secrets for those new generated addresses
-	if err := cw.syncSecrets(ss); err != nil {
+	cw, err := w.UnlockSecrets(ss)
+	if err != nil {
 		return nil, err
 	}
 
 	if len(ss) > initSSLen {
 		// new secrets generated, update the secrets field of the locked wallet
-		sb, err := ss.Serialize()
-		if err != nil {
-			return nil, err
-		}
-
-		encSecret, err := cryptor.Encrypt(sb, password)
+		encSecret, err := ss.Encrypt(cryptor, password)
 		if err != nil {
 			return nil, err
 		}
@@ -438,6 +504,23 @@ func (w *Wallet) Unlock(password []byte) (wallet.Wallet, error) {
 		w.SetEncrypted(ct, string(encSecret))
 	}
 
+	return cw, nil
+}
+
+// UnlockSecrets returns a decrypted copy of the encrypted wallet with the decrypted secrets,
+// the secrets of the addresses that do not have secrets yet are generated and added to ss
+func (w *Wallet) UnlockSecrets(ss wallet.Secrets) (wallet.Wallet, error) {
+	if !w.IsEncrypted() {
+		return nil, wallet.ErrWalletNotEncrypted
+	}
+
+	cw := w.Clone().(*Wallet)
+
+	// fills secrets for those new generated addresses
+	if err := cw.syncSecrets(ss); err != nil {
+		return nil, err
+	}
+
 	if err := cw.unpackSecrets(ss); err != nil {
 		return nil, err
 	}
@@ -482,6 +565,7 @@ func (w Wallet) Fingerprint() string {
 func (w Wallet) Clone() wallet.Wallet {
 	return &Wallet{
 		Meta:           w.Meta.Clone(),
+		Seeds:          w.Seeds.Clone(),
 		accountManager: w.accountManager.clone(),
 		decoder:        w.decoder,
 	}
@@ -489,6 +573,7 @@ func (w Wallet) Clone() wallet.Wallet {
 
 func (w *Wallet) copyFrom(wlt *Wallet) {
 	w.Meta = wlt.Meta.Clone()
+	w.Seeds = wlt.Seeds.Clone()
 	w.accountManager = wlt.accountManager.clone()
 	w.decoder = wlt.decoder
 }
@@ -541,8 +626,7 @@ func (w *Wallet) GetEntries(options ...wallet.Option) (wallet.Entries, error) {
 
 // Erase wipes all sensitive data
 func (w *Wallet) Erase() {
-	w.SetSeed("")
-	w.SetSeedPassphrase("")
+	w.Seeds.Erase()
 	w.accountManager.erase()
 }
 
@@ -554,8 +638,8 @@ func (w Wallet) syncSecrets(ss wallet.Secrets) error {
 
 // packSecrets saves all sensitive data to the secrets map.
 func (w Wallet) packSecrets(ss wallet.Secrets) {
-	ss.Set(wallet.SecretSeed, w.Meta.Seed())
-	ss.Set(wallet.SecretSeedPassphrase, w.Meta.SeedPassphrase())
+	ss.Set(wallet.SecretSeed, w.Seed())
+	ss.Set(wallet.SecretSeedPassphrase, w.SeedPassphrase())
 	w.accountManager.packSecrets(ss)
 }
 
@@ -564,10 +648,10 @@ func (w *Wallet) unpackSecrets(ss wallet.Secrets) error {
 	if !ok {
 		return errors.New("seed does not exist in secrets")
 	}
-	w.Meta.SetSeed(seed)
+	w.SetSeed(seed)
 
 	passphrase, _ := ss.Get(wallet.SecretSeedPassphrase)
-	w.Meta.SetSeedPassphrase(passphrase)
+	w.SetSeedPassphrase(passphrase)
 
 	return w.accountManager.unpackSecrets(ss)
 }
@@ -662,6 +746,108 @@ func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]ci
 	return retAddrs, nil
 }
 
+// ScanAddressesGapLimit scans the external and change chains of all accounts to find
+// all the addresses with transactions, the chains are scanned until gapLimit consecutive
+// addresses without transactions are found after the last used address, as specified
+// in bip44. DefaultGapLimit is used if gapLimit is 0.
+// All used addresses are generated, only the new external addresses will be returned.
+func (w *Wallet) ScanAddressesGapLimit(gapLimit uint64, tf wallet.TransactionsFinder) ([]cipher.Addresser, error) {
+	if tf == nil {
+		return nil, wallet.ErrNilTransactionsFinder
+	}
+
+	if gapLimit == 0 {
+		gapLimit = wallet.DefaultGapLimit
+	}
+
+	if gapLimit > math.MaxUint32 {
+		return nil, fmt.Errorf("gap limit %d is too large", gapLimit)
+	}
+
+	w2 := w.Clone().(*Wallet)
+
+	// scanChain returns the number of existing addresses and the number of addresses
+	// to keep on the chain, which ends with the last used address.
+	scanChain := func(account, chain uint32) (uint32, uint32, error) {
+		nExistingAddrs, err := w2.entriesLen(account, chain)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		keepNum := nExistingAddrs
+		scannedNum := nExistingAddrs
+		for {
+			// generates the next addresses to scan
+			addrs, err := w2.accountManager.newAddresses(account, chain, uint32(gapLimit))
+			if err != nil {
+				return 0, 0, err
+			}
+
+			active, err := tf.AddressesActivity(addrs)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			var found bool
+			for i := len(active) - 1; i >= 0; i-- {
+				if active[i] {
+					keepNum = scannedNum + uint32(i+1)
+					found = true
+					break
+				}
+			}
+
+			// stops if gapLimit consecutive addresses have no activity
+			if !found {
+				return nExistingAddrs, keepNum, nil
+			}
+
+			scannedNum += uint32(len(addrs))
+		}
+	}
+
+	accounts := w2.Accounts()
+
+	// [accounts][chains] array
+	generateAddresses := make([][]uint32, len(accounts))
+	externalStart := make([]uint32, len(accounts))
+
+	for i, a := range accounts {
+		for _, c := range []uint32{bip44.ExternalChainIndex, bip44.ChangeChainIndex} {
+			initLen, keepNum, err := scanChain(a.Index, c)
+			if err != nil {
+				return nil, err
+			}
+
+			if c == bip44.ExternalChainIndex {
+				externalStart[i] = initLen
+			}
+			generateAddresses[i] = append(generateAddresses[i], keepNum)
+		}
+	}
+
+	// only external addresses will be returned
+	var retAddrs []cipher.Addresser
+
+	w2.reset()
+	for i, a := range accounts {
+		for _, c := range []uint32{bip44.ExternalChainIndex, bip44.ChangeChainIndex} {
+			addrs, err := w2.newAddresses(a.Index, c, generateAddresses[i][c])
+			if err != nil {
+				return nil, err
+			}
+
+			if c == bip44.ExternalChainIndex {
+				retAddrs = append(retAddrs, addrs[externalStart[i]:]...)
+			}
+		}
+	}
+
+	*w = *w2
+
+	return retAddrs, nil
+}
+
 // GetAddresses returns all addresses on selected account and chain,
 // if no options ware provided, addresses on external chain of account 0 will be returned.
 func (w *Wallet) GetAddresses(options ...wallet.Option) ([]cipher.Addresser, error) {
@@ -684,9 +870,9 @@ func (w *Wallet) GenerateAddresses(num uint64, options ...wallet.Option) ([]ciph
 	opts := getBip44Options(options...)
 	switch opts.ChainMode {
 	case wallet.DefaultChain, wallet.ExternalChain:
-		return w.newAddresses(opts.Account, bip44.ExternalChainIndex, uint32(num))
+		return w.newAddresses(opts.Account, bip44.ExternalChainIndex, uint32(num), options...)
 	case wallet.ChangeChain:
-		return w.newAddresses(opts.Account, bip44.ChangeChainIndex, uint32(num))
+		return w.newAddresses(opts.Account, bip44.ChangeChainIndex, uint32(num), options...)
 	case wallet.AllChains:
 		return nil, errors.New("could not generate new addresses on both external and internal chains at once")
 	default:
@@ -725,6 +911,33 @@ func (w *Wallet) GetEntry(addr cipher.Addresser, options ...wallet.Option) (wall
 	return e, nil
 }
 
+// GetEntryMeta returns the label, note and tags of the entry of given address on selected account,
+// if no options are provided, check the account 0.
+func (w *Wallet) GetEntryMeta(addr cipher.Addresser, options ...wallet.Option) (wallet.EntryMeta, error) {
+	e, err := w.GetEntry(addr, options...)
+	if err != nil {
+		return wallet.EntryMeta{}, err
+	}
+
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address on selected account,
+// if no options are provided, check the account 0.
+func (w *Wallet) SetEntryMeta(addr cipher.Addresser, m wallet.EntryMeta, options ...wallet.Option) error {
+	opts := getBip44Options(options...)
+	ok, err := w.setEntryMeta(opts.Account, addr, m)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return wallet.ErrEntryNotFound
+	}
+
+	return nil
+}
+
 // HasEntry checks whether the entry of given address exists on selected account and chain,
 // if no options are provided, check the external chain of account 0.
 func (w *Wallet) HasEntry(addr cipher.Addresser, options ...wallet.Option) (bool, error) {
@@ -860,6 +1073,18 @@ func convertOptions(options wallet.Options) []wallet.Option {
 		opts = append(opts, wallet.OptionBip44Coin(options.Bip44Coin))
 	}
 
+	if options.Bip44PathTemplate != "" {
+		opts = append(opts, wallet.OptionBip44PathTemplate(options.Bip44PathTemplate))
+	}
+
+	if options.SeedLanguage != "" {
+		opts = append(opts, wallet.OptionSeedLanguage(options.SeedLanguage))
+	}
+
+	if options.AddressEncoding != "" {
+		opts = append(opts, wallet.OptionAddressEncoding(options.AddressEncoding))
+	}
+
 	if options.CryptoType != "" {
 		opts = append(opts, wallet.OptionCryptoType(options.CryptoType))
 	}
@@ -882,5 +1107,10 @@ func convertOptions(options wallet.Options) []wallet.Option {
 		opts = append(opts, wallet.OptionTransactionsFinder(options.TF))
 	}
 
+	if options.GapLimit > 0 {
+		opts = append(opts, wallet.OptionGapLimit(options.GapLimit))
+		opts = append(opts, wallet.OptionTransactionsFinder(options.TF))
+	}
+
 	return opts
 }
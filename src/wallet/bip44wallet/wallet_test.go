@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/wallet"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
@@ -336,8 +338,8 @@ func TestBip44NewWallet(t *testing.T) {
 						return
 					}
 
-					require.Equal(t, tc.seed, w.Meta.Seed())
-					require.Equal(t, tc.seedPassphrase, w.Meta.SeedPassphrase())
+					require.Equal(t, tc.seed, w.Seed())
+					require.Equal(t, tc.seedPassphrase, w.SeedPassphrase())
 					require.False(t, w.Meta.IsEncrypted())
 					require.Empty(t, w.Meta.Secrets())
 					el, err := w.EntriesLen()
@@ -393,8 +395,8 @@ func TestBip44NewWalletDefaultCrypto(t *testing.T) {
 					return
 				}
 
-				require.Equal(t, testSeed, w.Meta.Seed())
-				require.Equal(t, testSeedPassphrase, w.Meta.SeedPassphrase())
+				require.Equal(t, testSeed, w.Seed())
+				require.Equal(t, testSeedPassphrase, w.SeedPassphrase())
 				require.False(t, w.Meta.IsEncrypted())
 				require.Empty(t, w.Meta.Secrets())
 			})
@@ -417,7 +419,7 @@ func checkNoSensitiveData(t *testing.T, w *Wallet) {
 
 			// confirms no secrets in the entries
 			for _, e := range entries {
-				require.Equal(t, cipher.SecKey{}, e.Secret)
+				require.True(t, e.Secret.Null())
 			}
 		}
 	}
@@ -639,6 +641,39 @@ func TestWalletCreateAccount(t *testing.T) {
 	require.Equal(t, uint32(3), w.accountManager.len())
 }
 
+func TestWalletSetAccountName(t *testing.T) {
+	w, err := NewWallet(
+		"test.wlt",
+		"test",
+		testSeed,
+		testSeedPassphrase,
+		wallet.OptionCoinType(wallet.CoinTypeSkycoin))
+	require.NoError(t, err)
+
+	_, err = w.NewAccount("account1")
+	require.NoError(t, err)
+
+	require.NoError(t, w.SetAccountName(1, "savings"))
+	require.Equal(t, []wallet.Bip44Account{
+		{Name: DefaultAccountName, Index: 0},
+		{Name: "savings", Index: 1},
+	}, w.Accounts())
+
+	err = w.SetAccountName(2, "missing")
+	require.Equal(t, errors.New("account index 2 out of range"), err)
+
+	// Renaming does not change the accounts hash
+	h := w.Meta[wallet.MetaAccountsHash]
+	require.NoError(t, w.SetAccountName(0, "spending"))
+	require.Equal(t, h, w.Meta[wallet.MetaAccountsHash])
+
+	// New accounts of an encrypted wallet require unlocking it
+	require.NoError(t, w.Lock([]byte("pwd")))
+	_, err = w.NewAccount("account2")
+	require.Equal(t, wallet.ErrWalletEncrypted, err)
+	require.NoError(t, w.SetAccountName(1, "cold"))
+}
+
 func TestWalletAccountCreateAddresses(t *testing.T) {
 	w, err := NewWallet(
 		"test.wlt",
@@ -755,6 +790,50 @@ func TestWalletGenerateAddress(t *testing.T) {
 	}
 }
 
+func TestWalletGenerateAddressesParallel(t *testing.T) {
+	num := uint64(wallet.ParallelThreshold + 10)
+
+	w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	_, err = w.GenerateAddresses(num, wallet.OptionWorkers(4))
+	require.NoError(t, err)
+	_, err = w.GenerateAddresses(num, wallet.OptionChange(), wallet.OptionWorkers(4))
+	require.NoError(t, err)
+
+	sw, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	_, err = sw.GenerateAddresses(num, wallet.OptionWorkers(1))
+	require.NoError(t, err)
+	_, err = sw.GenerateAddresses(num, wallet.OptionChange(), wallet.OptionWorkers(1))
+	require.NoError(t, err)
+
+	es, err := w.GetEntries()
+	require.NoError(t, err)
+	ses, err := sw.GetEntries()
+	require.NoError(t, err)
+	require.Equal(t, ses, es)
+
+	addrs, err := w.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+	require.Equal(t, skycoinExternalAddrs, addrs[:len(skycoinExternalAddrs)])
+	for _, e := range es {
+		require.Equal(t, cipher.MustAddressFromSecKey(e.Secret.SecKey()), e.Address)
+	}
+}
+
+func BenchmarkWalletGenerateAddresses(b *testing.B) {
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+				require.NoError(b, err)
+				_, err = w.GenerateAddresses(1000, wallet.OptionWorkers(workers))
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
 func TestBip44WalletNewSerializeDeserialize(t *testing.T) {
 	w, err := NewWallet(
 		"test.wlt",
@@ -804,6 +883,60 @@ func TestBip44WalletNewSerializeDeserialize(t *testing.T) {
 	}
 }
 
+func TestWalletEntryMeta(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+
+	ai, err := w.NewAccount("account1")
+	require.NoError(t, err)
+
+	externalAddrs, err := w.newExternalAddresses(ai, 2)
+	require.NoError(t, err)
+
+	changeAddrs, err := w.newChangeAddresses(ai, 1)
+	require.NoError(t, err)
+
+	external := wallet.EntryMeta{
+		Label: "donations",
+		Tags:  map[string]string{"source": "website"},
+	}
+	change := wallet.EntryMeta{
+		Note: "change of the first payment",
+	}
+	require.NoError(t, w.SetEntryMeta(externalAddrs[1], external, wallet.OptionAccount(ai)))
+	require.NoError(t, w.SetEntryMeta(changeAddrs[0], change, wallet.OptionAccount(ai)))
+
+	// The addresses are not in the default account
+	err = w.SetEntryMeta(externalAddrs[1], external)
+	require.Equal(t, wallet.ErrEntryNotFound, err)
+	err = w.SetEntryMeta(externalAddrs[1], external, wallet.OptionAccount(ai+1))
+	require.Equal(t, fmt.Errorf("account index %d out of range", ai+1), err)
+	err = w.SetEntryMeta(cipher.MustDecodeBase58Address("2ULfxDUuenUY5V4Pr8whmoAwFdUseXNyjXC"), external, wallet.OptionAccount(ai))
+	require.Equal(t, wallet.ErrEntryNotFound, err)
+
+	// Entry meta is persisted in the wallet file
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+
+	m, err := wlt.GetEntryMeta(externalAddrs[1], wallet.OptionAccount(ai))
+	require.NoError(t, err)
+	require.Equal(t, external, m)
+
+	m, err = wlt.GetEntryMeta(changeAddrs[0], wallet.OptionAccount(ai))
+	require.NoError(t, err)
+	require.Equal(t, change, m)
+
+	m, err = wlt.GetEntryMeta(externalAddrs[0], wallet.OptionAccount(ai))
+	require.NoError(t, err)
+	require.Equal(t, wallet.EntryMeta{}, m)
+
+	es, err := wlt.GetEntries(wallet.OptionAccount(ai))
+	require.NoError(t, err)
+	require.Equal(t, external, es[1].EntryMeta)
+}
+
 func skycoinAddressStringsToAddress(addrsStr []string) []cipher.Addresser {
 	var addrs []cipher.Addresser
 	for _, addr := range addrsStr {
@@ -841,6 +974,87 @@ func TestPeekChangeAddress(t *testing.T) {
 	require.Equal(t, skycoinChangeAddrs[2], addr)
 }
 
+func TestChangePolicy(t *testing.T) {
+	tt := []struct {
+		name    string
+		policy  wallet.ChangePolicy
+		addr    string
+		tf      wallet.TransactionsFinder
+		expect  cipher.Addresser
+		changeN int
+		err     error
+	}{
+		{
+			name:    "default uses the change chain",
+			tf:      mockTxnsFinder{skycoinChangeAddrs[0]: true},
+			expect:  skycoinChangeAddrs[1],
+			changeN: 2,
+		},
+		{
+			name:    "change chain",
+			policy:  wallet.ChangePolicyChangeChain,
+			tf:      mockTxnsFinder{},
+			expect:  skycoinChangeAddrs[0],
+			changeN: 1,
+		},
+		{
+			name:   "change chain nil transactions finder",
+			policy: wallet.ChangePolicyChangeChain,
+			err:    wallet.ErrNilTransactionsFinder,
+		},
+		{
+			name:    "reuse first",
+			policy:  wallet.ChangePolicyReuseFirst,
+			expect:  skycoinExternalAddrs[0],
+			changeN: 1,
+		},
+		{
+			name:    "fresh",
+			policy:  wallet.ChangePolicyFresh,
+			expect:  skycoinChangeAddrs[1],
+			changeN: 2,
+		},
+		{
+			name:    "address",
+			policy:  wallet.ChangePolicyAddress,
+			addr:    skycoinExternalAddrs[3].String(),
+			expect:  skycoinExternalAddrs[3],
+			changeN: 1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			// A new wallet has one address on the change chain
+			w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+			require.NoError(t, err)
+			require.True(t, wallet.UsesChangePolicy(w))
+
+			require.NoError(t, wallet.ValidateChangePolicy(w, tc.policy, tc.addr))
+			w.SetChangePolicy(tc.policy, tc.addr)
+
+			addr, err := wallet.ChangeAddress(w, tc.tf)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tc.expect, addr)
+
+			es, err := w.GetEntries(wallet.OptionChange())
+			require.NoError(t, err)
+			require.Len(t, es, tc.changeN)
+
+			// The policy is kept after serialize/deserialize
+			b, err := w.Serialize()
+			require.NoError(t, err)
+			wlt := Wallet{}
+			require.NoError(t, wlt.Deserialize(b))
+			require.Equal(t, tc.policy, wlt.ChangePolicy())
+			require.Equal(t, tc.addr, wlt.ChangePolicyAddress())
+		})
+	}
+}
+
 func TestScanAddresses(t *testing.T) {
 	eAddrs := skycoinExternalAddrs
 	cAddrs := skycoinChangeAddrs
@@ -948,6 +1162,121 @@ func TestScanAddresses(t *testing.T) {
 	}
 }
 
+func TestScanAddressesGapLimit(t *testing.T) {
+	// generates the addresses of the first 12 indexes on both chains
+	rw, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	newAddrs, err := rw.GenerateAddresses(11)
+	require.NoError(t, err)
+	eAddrs := append(skycoinExternalAddrs[:1:1], newAddrs...)
+	require.Equal(t, skycoinExternalAddrs, eAddrs[:len(skycoinExternalAddrs)])
+	newAddrs, err = rw.GenerateAddresses(11, wallet.OptionChange())
+	require.NoError(t, err)
+	cAddrs := append(skycoinChangeAddrs[:1:1], newAddrs...)
+	require.Equal(t, skycoinChangeAddrs, cAddrs[:len(skycoinChangeAddrs)])
+
+	tt := []struct {
+		name                 string
+		gapLimit             uint64
+		txnFinder            wallet.TransactionsFinder
+		expectAddrs          []cipher.Addresser
+		expectAllChangeAddrs []cipher.Addresser
+		err                  error
+	}{
+		{
+			name:                 "no txns",
+			gapLimit:             3,
+			txnFinder:            mockTxnsFinder{},
+			expectAllChangeAddrs: cAddrs[:1],
+		},
+		{
+			name:                 "external addr with txn",
+			gapLimit:             3,
+			txnFinder:            mockTxnsFinder{eAddrs[2]: true},
+			expectAddrs:          eAddrs[1:3],
+			expectAllChangeAddrs: cAddrs[:1],
+		},
+		{
+			name:     "scans beyond the gap limit of the first batch",
+			gapLimit: 3,
+			txnFinder: mockTxnsFinder{
+				eAddrs[3]: true,
+				eAddrs[6]: true,
+			},
+			expectAddrs:          eAddrs[1:7],
+			expectAllChangeAddrs: cAddrs[:1],
+		},
+		{
+			name:                 "used address after the gap",
+			gapLimit:             3,
+			txnFinder:            mockTxnsFinder{eAddrs[5]: true},
+			expectAllChangeAddrs: cAddrs[:1],
+		},
+		{
+			name:     "change addrs with txns",
+			gapLimit: 3,
+			txnFinder: mockTxnsFinder{
+				cAddrs[2]: true,
+				cAddrs[5]: true,
+			},
+			expectAllChangeAddrs: cAddrs[:6],
+		},
+		{
+			name:     "default gap limit",
+			gapLimit: 0,
+			txnFinder: mockTxnsFinder{
+				eAddrs[9]: true,
+				cAddrs[9]: true,
+			},
+			expectAddrs:          eAddrs[1:10],
+			expectAllChangeAddrs: cAddrs[:10],
+		},
+		{
+			name:                 "nil transactions finder",
+			gapLimit:             3,
+			err:                  wallet.ErrNilTransactionsFinder,
+			expectAllChangeAddrs: cAddrs[:1],
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+			require.NoError(t, err)
+
+			addrs, err := w.ScanAddressesGapLimit(tc.gapLimit, tc.txnFinder)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.expectAddrs, addrs)
+
+			changeAddrs, err := w.GetAddresses(wallet.OptionChange())
+			require.NoError(t, err)
+			require.Equal(t, tc.expectAllChangeAddrs, changeAddrs)
+
+			// The wallet created with the gap limit has the same addresses
+			if tc.gapLimit > 0 {
+				w2, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase,
+					wallet.OptionGapLimit(tc.gapLimit),
+					wallet.OptionTransactionsFinder(tc.txnFinder))
+				require.NoError(t, err)
+
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				es2, err := w2.GetEntries()
+				require.NoError(t, err)
+				require.Equal(t, es, es2)
+
+				changeAddrs2, err := w2.GetAddresses(wallet.OptionChange())
+				require.NoError(t, err)
+				require.Equal(t, changeAddrs, changeAddrs2)
+			}
+		})
+	}
+}
+
 func getExternalAddrs(t *testing.T) []cipher.Addresser {
 	return skycoinAddressStringsToAddress(testSkycoinExternalAddresses)
 }
@@ -955,3 +1284,230 @@ func getExternalAddrs(t *testing.T) []cipher.Addresser {
 func getChangeAddrs(t *testing.T) []cipher.Addresser {
 	return skycoinAddressStringsToAddress(testSkycoinChangeAddresses)
 }
+
+func TestWalletBip44PathTemplate(t *testing.T) {
+	pathTemplate := "m/44'/1000'/n'/change/index"
+
+	w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase,
+		wallet.OptionBip44PathTemplate(pathTemplate))
+	require.NoError(t, err)
+	require.Equal(t, pathTemplate, w.Bip44PathTemplate())
+
+	ai, err := w.NewAccount("account1")
+	require.NoError(t, err)
+	addrs, err := w.newExternalAddresses(ai, 1)
+	require.NoError(t, err)
+
+	// The address is derived from the custom path
+	seed, err := bip39.NewSeed(testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	pt, err := bip44.ParsePathTemplate(pathTemplate)
+	require.NoError(t, err)
+	k, err := bip32.NewPrivateKeyFromPath(seed, pt.Path(ai, bip44.ExternalChainIndex, 0))
+	require.NoError(t, err)
+	addr, err := cipher.AddressFromSecKey(cipher.MustNewSecKey(k.Key))
+	require.NoError(t, err)
+	require.Equal(t, addr, addrs[0])
+	require.NotEqual(t, skycoinExternalAddrs[0], addrs[0])
+
+	// The path template is kept after serialize/deserialize
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, pathTemplate, wlt.Bip44PathTemplate())
+
+	es, err := wlt.externalEntries(ai)
+	require.NoError(t, err)
+	require.Equal(t, addrs[0], es[0].Address)
+
+	// Invalid path templates are rejected
+	_, err = NewWallet("test.wlt", "test", testSeed, testSeedPassphrase,
+		wallet.OptionBip44PathTemplate("m/44'/1000'/n'/change"))
+	require.Equal(t, fmt.Errorf("invalid bip44 path template: %v", bip44.ErrInvalidPathTemplate), err)
+
+	wlt.Meta.SetBip44PathTemplate("m/44'/n'/n'/change/index")
+	b, err = wlt.Serialize()
+	require.NoError(t, err)
+	err = wlt.Deserialize(b)
+	require.Equal(t, fmt.Errorf("invalid bip44 path template: %v", bip44.ErrPathTemplatePlaceholder), err)
+}
+
+func TestWalletEthereum(t *testing.T) {
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	w, err := NewWallet("test.wlt", "test", seed, "", wallet.OptionCoinType(wallet.CoinTypeEthereum))
+	require.NoError(t, err)
+	require.Equal(t, bip44.CoinTypeEthereum, *w.Bip44Coin())
+
+	// m/44'/60'/0'/0/0
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+	require.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", addrs[0].String())
+
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, wallet.CoinTypeEthereum, wlt.Coin())
+
+	es, err := wlt.externalEntries(0)
+	require.NoError(t, err)
+	require.Equal(t, addrs[0], es[0].Address)
+	require.NoError(t, es[0].Address.Verify(es[0].Public))
+}
+
+func TestWalletAddressEncoding(t *testing.T) {
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	w, err := NewWallet("test.wlt", "test", seed, "",
+		wallet.OptionCoinType(wallet.CoinTypeBitcoin),
+		wallet.OptionBip44PathTemplate("m/84'/0'/n'/change/index"),
+		wallet.OptionAddressEncoding(wallet.AddressEncodingBech32))
+	require.NoError(t, err)
+	require.Equal(t, wallet.AddressEncodingBech32, w.AddressEncoding())
+
+	// BIP84 test vectors, m/84'/0'/0'/0/0 and m/84'/0'/0'/1/0
+	addrs, err := w.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+	require.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", addrs[0].String())
+	addrs, err = w.GetAddresses(wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", addrs[0].String())
+
+	// The address encoding is kept after serialize/deserialize
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, wallet.AddressEncodingBech32, wlt.AddressEncoding())
+
+	es, err := wlt.externalEntries(0)
+	require.NoError(t, err)
+	require.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", es[0].Address.String())
+	require.NoError(t, es[0].Address.Verify(es[0].Public))
+
+	// The addresses generated after loading, and on new accounts, are bech32 addresses
+	addrs, err = wlt.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Equal(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", addrs[0].String())
+
+	ai, err := wlt.NewAccount("account1")
+	require.NoError(t, err)
+	addrs, err = wlt.newExternalAddresses(ai, 1)
+	require.NoError(t, err)
+	require.Equal(t, "bc1q", addrs[0].String()[:4])
+
+	// The base58 wallet of the seed has the same pubkey hashes
+	lw, err := NewWallet("test.wlt", "test", seed, "",
+		wallet.OptionCoinType(wallet.CoinTypeBitcoin),
+		wallet.OptionBip44PathTemplate("m/84'/0'/n'/change/index"))
+	require.NoError(t, err)
+	laddrs, err := lw.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+	require.Equal(t, laddrs[0].(cipher.BitcoinAddress).Key, es[0].Address.(cipher.BitcoinBech32Address).Key)
+
+	// Skycoin wallets have no bech32 addresses
+	_, err = NewWallet("test.wlt", "test", seed, "", wallet.OptionAddressEncoding(wallet.AddressEncodingBech32))
+	require.Equal(t, wallet.ErrInvalidAddressEncoding, err)
+}
+
+func TestWalletSeedLanguage(t *testing.T) {
+	entropy, err := bip39.NewEntropy(128)
+	require.NoError(t, err)
+	seed, err := bip39.NewMnemonicInLanguage(entropy, bip39.Japanese)
+	require.NoError(t, err)
+
+	// The seed language is detected
+	w, err := NewWallet("test.wlt", "test", seed, testSeedPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, bip39.Japanese, w.SeedLanguage())
+	require.Equal(t, string(bip39.Japanese), w.Meta[wallet.MetaSeedLanguage])
+
+	// The addresses are derived from the bip39 seed of the japanese mnemonic
+	addrs, err := w.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+	s, err := bip39.NewSeed(seed, testSeedPassphrase)
+	require.NoError(t, err)
+	k, err := bip32.NewPrivateKeyFromPath(s, "m/44'/8000'/0'/0/0")
+	require.NoError(t, err)
+	addr, err := cipher.AddressFromSecKey(cipher.MustNewSecKey(k.Key))
+	require.NoError(t, err)
+	require.Equal(t, addr, addrs[0])
+
+	// The seed language is kept after serialize/deserialize
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, bip39.Japanese, wlt.SeedLanguage())
+
+	// The seed must be in the wordlist of the seed language
+	_, err = NewWallet("test.wlt", "test", seed, testSeedPassphrase, wallet.OptionSeedLanguage(bip39.Spanish))
+	require.Equal(t, bip39.ErrUnknownWord, err)
+
+	// The english seed language is not recorded
+	w, err = NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, bip39.English, w.SeedLanguage())
+	_, ok := w.Meta[wallet.MetaSeedLanguage]
+	require.False(t, ok)
+
+	wlt.Meta[wallet.MetaSeedLanguage] = "klingon"
+	b, err = wlt.Serialize()
+	require.NoError(t, err)
+	err = wlt.Deserialize(b)
+	require.Equal(t, errors.New(`invalid seed language "klingon"`), err)
+}
+
+func TestWalletVerifySeedPassphrase(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", testSeed, testSeedPassphrase)
+	require.NoError(t, err)
+	_, err = w.NewAccount("account1")
+	require.NoError(t, err)
+
+	h := w.Meta[wallet.MetaAccountsHash]
+	require.NotEmpty(t, h)
+
+	ok, err := w.VerifySeedPassphrase(testSeedPassphrase)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = w.VerifySeedPassphrase("")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = w.VerifySeedPassphrase(testSeedPassphrase + "x")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// The accounts hash is kept after serialize/deserialize
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, h, wlt.Meta[wallet.MetaAccountsHash])
+
+	// Wallets without the accounts hash are verified against their accounts
+	delete(wlt.Meta, wallet.MetaAccountsHash)
+	ok, err = wlt.VerifySeedPassphrase(testSeedPassphrase)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The seed is required
+	password := []byte("pwd")
+	require.NoError(t, w.Lock(password))
+	_, err = w.VerifySeedPassphrase(testSeedPassphrase)
+	require.Equal(t, wallet.ErrWalletEncrypted, err)
+
+	uw, err := w.Unlock(password)
+	require.NoError(t, err)
+	ok, err = uw.(*Wallet).VerifySeedPassphrase(testSeedPassphrase)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The accounts must match the accounts hash
+	wlt.Meta[wallet.MetaAccountsHash] = cipher.SumSHA256([]byte("foo")).Hex()
+	b, err = wlt.Serialize()
+	require.NoError(t, err)
+	err = wlt.Deserialize(b)
+	require.Equal(t, errors.New("accounts hash mismatch, the wallet accounts are corrupted"), err)
+}
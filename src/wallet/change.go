@@ -0,0 +1,174 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// ChangePolicy determines the address that receives the change of the transactions created by a wallet
+type ChangePolicy string
+
+const (
+	// ChangePolicyDefault sends the change to the dedicated change chain for bip44 wallets,
+	// and to the owner of the spent outputs whose address bytes are lexically sorted first for other wallets
+	ChangePolicyDefault ChangePolicy = ""
+	// ChangePolicyReuseFirst sends the change to the first address of the wallet
+	ChangePolicyReuseFirst ChangePolicy = "reuseFirst"
+	// ChangePolicyChangeChain sends the change to an unused address on the change chain [bip44 wallets]
+	ChangePolicyChangeChain ChangePolicy = "changeChain"
+	// ChangePolicyFresh sends the change to a newly generated address,
+	// which is generated on the change chain for bip44 wallets
+	ChangePolicyFresh ChangePolicy = "fresh"
+	// ChangePolicyAddress sends the change to the address configured in the wallet meta
+	ChangePolicyAddress ChangePolicy = "address"
+)
+
+var (
+	// ErrInvalidChangePolicy is returned when the change policy is unknown
+	ErrInvalidChangePolicy = NewError(errors.New("invalid change policy"))
+	// ErrMissingChangeAddress is returned when the address change policy is set without an address
+	ErrMissingChangeAddress = NewError(errors.New("change address is required by the address change policy"))
+)
+
+// changeAddressPeeker is implemented by the wallets that have a dedicated change chain
+type changeAddressPeeker interface {
+	PeekChangeAddress(tf TransactionsFinder) (cipher.Addresser, error)
+}
+
+// ValidateChangePolicy validates the change policy and change address for the wallet
+func ValidateChangePolicy(w Wallet, policy ChangePolicy, changeAddress string) error {
+	switch policy {
+	case ChangePolicyDefault, ChangePolicyReuseFirst, ChangePolicyFresh:
+	case ChangePolicyChangeChain:
+		if _, ok := w.(changeAddressPeeker); !ok {
+			return NewError(fmt.Errorf("change policy %q is not supported by %s wallets", policy, w.Type()))
+		}
+	case ChangePolicyAddress:
+		if changeAddress == "" {
+			return ErrMissingChangeAddress
+		}
+		if _, err := ResolveAddressDecoder(w.Coin()).DecodeBase58Address(changeAddress); err != nil {
+			return NewError(fmt.Errorf("invalid change address %q: %v", changeAddress, err))
+		}
+		return nil
+	default:
+		return ErrInvalidChangePolicy
+	}
+
+	if changeAddress != "" {
+		return NewError(fmt.Errorf("change address is only used by the %q change policy", ChangePolicyAddress))
+	}
+	return nil
+}
+
+// UsesChangePolicy returns true if the change address of the wallet transactions is chosen
+// by ChangeAddress, rather than from the spent outputs
+func UsesChangePolicy(w Wallet) bool {
+	if w.ChangePolicy() != ChangePolicyDefault {
+		return true
+	}
+	_, ok := w.(changeAddressPeeker)
+	return ok
+}
+
+// ChangeAddress returns the change address chosen by the change policy of the wallet.
+// The fresh and changeChain policies may generate a new address, the caller is responsible
+// for saving the wallet. tf is only required by the changeChain policy.
+// Returns nil if the change address should be chosen from the spent outputs.
+func ChangeAddress(w Wallet, tf TransactionsFinder) (cipher.Addresser, error) {
+	policy := w.ChangePolicy()
+	peeker, hasChangeChain := w.(changeAddressPeeker)
+
+	switch policy {
+	case ChangePolicyDefault:
+		if !hasChangeChain {
+			return nil, nil
+		}
+		fallthrough
+	case ChangePolicyChangeChain:
+		if !hasChangeChain {
+			return nil, NewError(fmt.Errorf("change policy %q is not supported by %s wallets", policy, w.Type()))
+		}
+		if tf == nil {
+			return nil, ErrNilTransactionsFinder
+		}
+		return peeker.PeekChangeAddress(tf)
+	case ChangePolicyReuseFirst:
+		addrs, err := w.GetAddresses()
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, errors.New("wallet has no addresses")
+		}
+		return addrs[0], nil
+	case ChangePolicyFresh:
+		var opts []Option
+		if hasChangeChain {
+			opts = append(opts, OptionChange())
+		}
+		addrs, err := w.GenerateAddresses(1, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return addrs[0], nil
+	case ChangePolicyAddress:
+		addr := w.ChangePolicyAddress()
+		if addr == "" {
+			return nil, ErrMissingChangeAddress
+		}
+		return ResolveAddressDecoder(w.Coin()).DecodeBase58Address(addr)
+	default:
+		return nil, ErrInvalidChangePolicy
+	}
+}
+
+// SkycoinChangeAddress returns the change address chosen by the change policy of the wallet
+// as a skycoin address, for building transaction.Params. Refer to ChangeAddress for the details.
+func SkycoinChangeAddress(w Wallet, tf TransactionsFinder) (*cipher.Address, error) {
+	addr, err := ChangeAddress(w, tf)
+	if err != nil || addr == nil {
+		return nil, err
+	}
+
+	a, ok := addr.(cipher.Address)
+	if !ok {
+		return nil, fmt.Errorf("change address %s is not a skycoin address", addr)
+	}
+	return &a, nil
+}
+
+// UnusedChangeAddress returns a change address that has never been used, regardless of the change policy,
+// for transactions that avoid address reuse. Wallets with a change chain return an unused address
+// of the change chain, other wallets generate a new address, the caller is responsible for saving the wallet.
+func UnusedChangeAddress(w Wallet, tf TransactionsFinder) (cipher.Addresser, error) {
+	peeker, hasChangeChain := w.(changeAddressPeeker)
+	if hasChangeChain {
+		if tf == nil {
+			return nil, ErrNilTransactionsFinder
+		}
+		return peeker.PeekChangeAddress(tf)
+	}
+
+	addrs, err := w.GenerateAddresses(1)
+	if err != nil {
+		return nil, err
+	}
+	return addrs[0], nil
+}
+
+// SkycoinUnusedChangeAddress returns the unused change address of UnusedChangeAddress as a skycoin address
+func SkycoinUnusedChangeAddress(w Wallet, tf TransactionsFinder) (*cipher.Address, error) {
+	addr, err := UnusedChangeAddress(w, tf)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ok := addr.(cipher.Address)
+	if !ok {
+		return nil, fmt.Errorf("change address %s is not a skycoin address", addr)
+	}
+	return &a, nil
+}
@@ -0,0 +1,222 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+)
+
+var registeredCoinTypes = initCoinTypes()
+
+// AddressEncoding is the encoding of the addresses generated by a wallet,
+// for the coin types that have more than one address format
+type AddressEncoding string
+
+const (
+	// AddressEncodingDefault generates the addresses with the AddressFromPubKey of the coin type
+	AddressEncodingDefault AddressEncoding = ""
+	// AddressEncodingBase58 generates base58 encoded addresses, e.g. bitcoin legacy pay-to-pubkey-hash (P2PKH) addresses
+	AddressEncodingBase58 AddressEncoding = "base58"
+	// AddressEncodingBech32 generates bech32 encoded addresses, e.g. bitcoin native segwit (P2WPKH) addresses
+	AddressEncodingBech32 AddressEncoding = "bech32"
+)
+
+var (
+	// ErrInvalidAddressEncoding is returned when the address encoding is not supported by the coin type
+	ErrInvalidAddressEncoding = NewError(errors.New("invalid address encoding for the coin type"))
+)
+
+// CoinTypeInfo describes a coin type that the wallets can manage
+type CoinTypeInfo struct {
+	// Type is the coin type name stored in the wallet meta, e.g. "skycoin"
+	Type CoinType
+	// Aliases are the alternative names accepted by ResolveCoinType, e.g. "sky"
+	Aliases []string
+	// Bip44Coin is the bip44 coin_type used by bip44 wallets of the coin type
+	Bip44Coin bip44.CoinType
+	// AddressFromPubKey creates the address of the coin type from a public key
+	AddressFromPubKey func(cipher.PubKey) cipher.Addresser
+	// AddressEncodings are the address constructors of the address encodings
+	// that can be selected with the wallet addressEncoding meta, optional
+	AddressEncodings map[AddressEncoding]func(cipher.PubKey) cipher.Addresser
+	// Decoder is the address and seckey decoder of the coin type, optional.
+	// If set, it is registered with RegisterAddressSecKeyDecoder.
+	Decoder AddressSecKeyDecoder
+}
+
+type coinTypes struct {
+	types map[CoinType]CoinTypeInfo
+	// names maps the lower case coin type names and aliases to coin types
+	names map[string]CoinType
+}
+
+func initCoinTypes() coinTypes {
+	ct := coinTypes{
+		types: make(map[CoinType]CoinTypeInfo),
+		names: make(map[string]CoinType),
+	}
+
+	for _, info := range []CoinTypeInfo{
+		{
+			Type:              CoinTypeSkycoin,
+			Aliases:           []string{"sky"},
+			Bip44Coin:         bip44.CoinTypeSkycoin,
+			AddressFromPubKey: skycoinDecoder{}.AddressFromPubKey,
+		},
+		{
+			Type:              CoinTypeBitcoin,
+			Aliases:           []string{"btc"},
+			Bip44Coin:         bip44.CoinTypeBitcoin,
+			AddressFromPubKey: bitcoinDecoder{}.AddressFromPubKey,
+			AddressEncodings: map[AddressEncoding]func(cipher.PubKey) cipher.Addresser{
+				AddressEncodingBase58: bitcoinDecoder{}.AddressFromPubKey,
+				AddressEncodingBech32: func(key cipher.PubKey) cipher.Addresser {
+					return cipher.BitcoinBech32AddressFromPubKey(key)
+				},
+			},
+		},
+		{
+			Type:              CoinTypeEthereum,
+			Aliases:           []string{"eth"},
+			Bip44Coin:         bip44.CoinTypeEthereum,
+			AddressFromPubKey: ethereumDecoder{}.AddressFromPubKey,
+		},
+	} {
+		if err := ct.add(info); err != nil {
+			panic(err)
+		}
+	}
+
+	return ct
+}
+
+func (c coinTypes) get(coinType CoinType) (CoinTypeInfo, bool) {
+	info, ok := c.types[coinType]
+	return info, ok
+}
+
+func (c coinTypes) resolve(name string) (CoinType, bool) {
+	ct, ok := c.names[strings.ToLower(name)]
+	return ct, ok
+}
+
+func (c coinTypes) add(info CoinTypeInfo) error {
+	if info.Type == "" {
+		return errors.New("coin type name is empty")
+	}
+	if info.AddressFromPubKey == nil {
+		return fmt.Errorf("address constructor of coin type %s is nil", info.Type)
+	}
+	for enc, f := range info.AddressEncodings {
+		if enc == AddressEncodingDefault || f == nil {
+			return fmt.Errorf("invalid address encoding %q of coin type %s", enc, info.Type)
+		}
+	}
+
+	names := append([]string{string(info.Type)}, info.Aliases...)
+	for _, n := range names {
+		if _, ok := c.names[strings.ToLower(n)]; ok {
+			return fmt.Errorf("coin type %s already registered", n)
+		}
+	}
+
+	for _, n := range names {
+		c.names[strings.ToLower(n)] = info.Type
+	}
+	c.types[info.Type] = info
+	return nil
+}
+
+func (c coinTypes) list() []CoinType {
+	cts := make([]CoinType, 0, len(c.types))
+	for ct := range c.types {
+		cts = append(cts, ct)
+	}
+	sort.Slice(cts, func(i, j int) bool {
+		return cts[i] < cts[j]
+	})
+	return cts
+}
+
+// RegisterCoinType registers a coin type, it should be called at init time.
+// Returns an error if the coin type name or one of its aliases is already registered.
+func RegisterCoinType(info CoinTypeInfo) error {
+	if info.Decoder != nil {
+		if _, ok := registeredAddressSecKeyDecoders.adapters[info.Type]; ok {
+			return fmt.Errorf("coin adapter for %s already registered", info.Type)
+		}
+	}
+
+	if err := registeredCoinTypes.add(info); err != nil {
+		return err
+	}
+
+	if info.Decoder != nil {
+		return RegisterAddressSecKeyDecoder(info.Type, info.Decoder)
+	}
+	return nil
+}
+
+// GetCoinTypeInfo returns the registered coin type info, returns false if the coin type is not registered
+func GetCoinTypeInfo(coinType CoinType) (CoinTypeInfo, bool) {
+	return registeredCoinTypes.get(coinType)
+}
+
+// CoinTypes returns the registered coin types, sorted by name
+func CoinTypes() []CoinType {
+	return registeredCoinTypes.list()
+}
+
+// ResolveCoinType normalizes a coin type string or alias to a registered CoinType
+func ResolveCoinType(s string) (CoinType, error) {
+	ct, ok := registeredCoinTypes.resolve(s)
+	if !ok {
+		return CoinType(""), errors.New("invalid coin type")
+	}
+	return ct, nil
+}
+
+// ResolveBip44CoinType returns the bip44 coin_type of the coin type,
+// returns false if the coin type is not registered
+func ResolveBip44CoinType(coinType CoinType) (bip44.CoinType, bool) {
+	info, ok := registeredCoinTypes.get(coinType)
+	if !ok {
+		return 0, false
+	}
+	return info.Bip44Coin, true
+}
+
+// ResolveAddressConstructor returns the address constructor of the coin type with the address encoding,
+// the default encoding uses the AddressFromPubKey of the coin type address decoder.
+// Returns ErrInvalidAddressEncoding if the coin type does not support the address encoding.
+func ResolveAddressConstructor(coinType CoinType, enc AddressEncoding) (func(cipher.PubKey) cipher.Addresser, error) {
+	if enc == AddressEncodingDefault {
+		return ResolveAddressDecoder(coinType).AddressFromPubKey, nil
+	}
+
+	info, ok := registeredCoinTypes.get(coinType)
+	if !ok {
+		return nil, ErrInvalidAddressEncoding
+	}
+
+	f, ok := info.AddressEncodings[enc]
+	if !ok {
+		return nil, ErrInvalidAddressEncoding
+	}
+	return f, nil
+}
+
+// validateAddressEncoding validates that the coin type of the wallet meta supports its address encoding
+func validateAddressEncoding(m Meta) error {
+	enc := m.AddressEncoding()
+	if enc == AddressEncodingDefault {
+		return nil
+	}
+
+	_, err := ResolveAddressConstructor(m.Coin(), enc)
+	return err
+}
@@ -35,6 +35,7 @@ func init() {
 // This wallet does not use seeds.
 type Wallet struct {
 	wallet.Meta
+	wallet.Seeds
 	entries wallet.Entries
 	decoder wallet.Decoder
 }
@@ -158,22 +159,17 @@ func (w *Wallet) Lock(password []byte) error {
 
 	wlt.packSecrets(ss)
 
-	sb, err := ss.Serialize()
-	if err != nil {
-		return err
-	}
-
 	cryptoType := wlt.CryptoType()
 	if cryptoType == "" {
 		cryptoType = crypto.DefaultCryptoType
 	}
 
-	cryptor, err := crypto.GetCrypto(cryptoType)
+	cryptor, err := wallet.GetCryptor(cryptoType, wlt.Meta)
 	if err != nil {
 		return err
 	}
 
-	encSecret, err := cryptor.Encrypt(sb, password)
+	encSecret, err := ss.Encrypt(cryptor, password)
 	if err != nil {
 		return err
 	}
@@ -206,27 +202,24 @@ func (w *Wallet) Unlock(password []byte) (wallet.Wallet, error) {
 		return nil, errors.New("missing crypto type")
 	}
 
-	cryptor, err := crypto.GetCrypto(ct)
+	cryptor, err := wallet.GetCryptor(ct, w.Meta)
 	if err != nil {
 		return nil, err
 	}
 
-	sb, err := cryptor.Decrypt([]byte(sstr), password)
+	ss, err := wallet.DecryptSecrets(cryptor, []byte(sstr), password)
 	if err != nil {
-		return nil, wallet.ErrInvalidPassword
+		return nil, err
 	}
+	defer ss.Erase()
 
-	defer func() {
-		// Wipes the data from secrets bytes buffer
-		for i := range sb {
-			sb[i] = 0
-		}
-	}()
+	return w.UnlockSecrets(ss)
+}
 
-	ss := make(wallet.Secrets)
-	defer ss.Erase()
-	if err := ss.Deserialize(sb); err != nil {
-		return nil, err
+// UnlockSecrets returns a decrypted copy of the encrypted wallet with the decrypted secrets
+func (w *Wallet) UnlockSecrets(ss wallet.Secrets) (wallet.Wallet, error) {
+	if !w.IsEncrypted() {
+		return nil, wallet.ErrWalletNotEncrypted
 	}
 
 	cw := w.Clone().(*Wallet)
@@ -347,6 +340,23 @@ func (w *Wallet) GetEntry(a cipher.Addresser, _ ...wallet.Option) (wallet.Entry,
 	return e, nil
 }
 
+// GetEntryMeta returns the label, note and tags of the entry of given address
+func (w *Wallet) GetEntryMeta(a cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	e, ok := w.entries.Get(a)
+	if !ok {
+		return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+	}
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address
+func (w *Wallet) SetEntryMeta(a cipher.Addresser, m wallet.EntryMeta, _ ...wallet.Option) error {
+	if !w.entries.SetMeta(a, m) {
+		return wallet.ErrEntryNotFound
+	}
+	return nil
+}
+
 // HasEntry returns true if the wallet has an entry.Entry with a given cipher.Address.
 func (w *Wallet) HasEntry(a cipher.Addresser, _ ...wallet.Option) (bool, error) {
 	return w.entries.Has(a), nil
@@ -372,6 +382,7 @@ func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
 //}
 
 // AddEntry adds a new entry to the wallet.
+// The wallet holds its own copy of the secret key, which it erases when it is locked.
 func (w *Wallet) AddEntry(e wallet.Entry) error {
 	if w.IsEncrypted() {
 		return wallet.ErrWalletEncrypted
@@ -382,11 +393,12 @@ func (w *Wallet) AddEntry(e wallet.Entry) error {
 	}
 
 	for _, entry := range w.entries {
-		if e.SkycoinAddress() == entry.SkycoinAddress() {
+		if e.Address == entry.Address {
 			return errors.New("wallet already contains entry with this address")
 		}
 	}
 
+	e.Secret = e.Secret.Clone()
 	w.entries = append(w.entries, e)
 	return nil
 }
@@ -234,7 +234,7 @@ func TestWalletLock(t *testing.T) {
 				require.NoError(t, err)
 
 				for _, e := range entries {
-					require.Equal(t, cipher.SecKey{}, e.Secret)
+					require.True(t, e.Secret.Null())
 				}
 			})
 
@@ -262,7 +262,7 @@ func TestWalletAddEntry(t *testing.T) {
 		entry := wallet.Entry{
 			Address: addr,
 			Public:  pubkey,
-			Secret:  keys[i],
+			Secret:  wallet.NewSecretKey(keys[i]),
 		}
 		entries[i] = entry
 		err = w.AddEntry(entry)
@@ -286,7 +286,7 @@ func TestWalletAddEntry(t *testing.T) {
 	// try to add entry with invalid seckey
 	invalidKey := keys[0]
 	invalidKey[len(invalidKey)-1] = 0
-	err = w.AddEntry(wallet.Entry{Secret: invalidKey})
+	err = w.AddEntry(wallet.Entry{Secret: wallet.NewSecretKey(invalidKey)})
 	require.EqualError(t, err, "invalid public key for secret key")
 
 	// mismatch public key
@@ -351,7 +351,7 @@ func TestWalletUnlock(t *testing.T) {
 				entry := wallet.Entry{
 					Address: addr,
 					Public:  pubkey,
-					Secret:  keys[i],
+					Secret:  wallet.NewSecretKey(keys[i]),
 				}
 				entries[i] = entry
 			}
@@ -406,7 +406,7 @@ func TestLockAndUnLock(t *testing.T) {
 		entry := wallet.Entry{
 			Address: addr,
 			Public:  pubkey,
-			Secret:  keys[i],
+			Secret:  wallet.NewSecretKey(keys[i]),
 		}
 		entries[i] = entry
 	}
@@ -435,7 +435,7 @@ func TestLockAndUnLock(t *testing.T) {
 			require.NoError(t, err)
 
 			for _, e := range es {
-				require.Equal(t, cipher.SecKey{}, e.Secret)
+				require.True(t, e.Secret.Null())
 			}
 
 			// unlock the cloned wallet
@@ -462,7 +462,7 @@ func skycoinEntries(es []readableEntry) []wallet.Entry {
 		entries[i] = wallet.Entry{
 			Address: cipher.MustDecodeBase58Address(e.Address),
 			Public:  pk,
-			Secret:  sk,
+			Secret:  wallet.NewSecretKey(sk),
 		}
 	}
 
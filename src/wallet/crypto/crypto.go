@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/skycoin/skycoin/src/cipher/encrypt"
+	"github.com/skycoin/skycoin/src/cipher/secret"
 )
 
 // Cryptor wraps the Encrypt and Decrypt method
@@ -13,6 +14,28 @@ type Cryptor interface {
 	Decrypt(data, password []byte) ([]byte, error)
 }
 
+// ErrReEncryptDecrypt is returned by ReEncrypt if the data can not be decrypted with the old password
+var ErrReEncryptDecrypt = errors.New("decrypt with the old password failed")
+
+// ReEncrypt decrypts the data with the old password and encrypts it with the new password.
+// The decrypted data is only held in a secret.Buffer that is destroyed before returning,
+// so that the plaintext is never exposed to the caller.
+func ReEncrypt(c Cryptor, data, oldPassword, newPassword []byte) ([]byte, error) {
+	if len(newPassword) == 0 {
+		return nil, errors.New("missing new password")
+	}
+
+	b, err := c.Decrypt(data, oldPassword)
+	if err != nil {
+		return nil, ErrReEncryptDecrypt
+	}
+
+	buf := secret.FromBytes(b)
+	defer buf.Destroy()
+
+	return c.Encrypt(buf.Bytes(), newPassword)
+}
+
 // CryptoType represents the type of crypto name
 type CryptoType string
 
@@ -25,6 +48,8 @@ func CryptoTypeFromString(s string) (CryptoType, error) {
 		return CryptoTypeScryptChacha20poly1305, nil
 	case CryptoTypeScryptChacha20poly1305Insecure:
 		return CryptoTypeScryptChacha20poly1305Insecure, nil
+	case CryptoTypeArgon2idChacha20poly1305:
+		return CryptoTypeArgon2idChacha20poly1305, nil
 	default:
 		return "", errors.New("unknown crypto type")
 	}
@@ -38,6 +63,9 @@ const (
 	CryptoTypeScryptChacha20poly1305 = CryptoType("scrypt-chacha20poly1305")
 	// CryptoTypeScryptChacha20poly1305Insecure uses chacha20poly1305 + scrypt key derivation with a weak work factor (unsafe)
 	CryptoTypeScryptChacha20poly1305Insecure = CryptoType("scrypt-chacha20poly1305-insecure")
+	// CryptoTypeArgon2idChacha20poly1305 uses chacha20poly1305 + argon2id key derivation,
+	// the argon2id memory and iterations are tunable
+	CryptoTypeArgon2idChacha20poly1305 = CryptoType("argon2id-chacha20poly1305")
 
 	// DefaultCryptoType is the default CryptoType used
 	DefaultCryptoType = CryptoTypeScryptChacha20poly1305
@@ -54,6 +82,7 @@ var cryptoTable = map[CryptoType]Cryptor{
 		P:      encrypt.ScryptP,
 		KeyLen: encrypt.ScryptKeyLen,
 	},
+	CryptoTypeArgon2idChacha20poly1305: encrypt.DefaultArgon2idChacha20poly1305,
 }
 
 // GetCrypto gets crypto of given type
@@ -66,12 +95,24 @@ func GetCrypto(cryptoType CryptoType) (Cryptor, error) {
 	return c, nil
 }
 
+// NewArgon2idCrypto creates an argon2id-chacha20poly1305 crypto with the given
+// number of iterations and memory size in KiB
+func NewArgon2idCrypto(iterations, memory uint32) Cryptor {
+	return encrypt.Argon2idChacha20poly1305{
+		Time:    iterations,
+		Memory:  memory,
+		Threads: encrypt.Argon2idThreads,
+		KeyLen:  encrypt.Argon2idKeyLen,
+	}
+}
+
 // Types returns all supported crypto types
 func Types() []CryptoType {
 	return []CryptoType{
 		CryptoTypeSha256Xor,
 		CryptoTypeScryptChacha20poly1305,
 		CryptoTypeScryptChacha20poly1305Insecure,
+		CryptoTypeArgon2idChacha20poly1305,
 		DefaultCryptoType,
 	}
 }
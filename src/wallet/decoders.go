@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/skycoin/skycoin/src/cipher"
 )
@@ -15,8 +16,9 @@ type addressSecKeyDecoders struct {
 func initAddressSecKeyDecoders() addressSecKeyDecoders {
 	return addressSecKeyDecoders{
 		adapters: map[CoinType]AddressSecKeyDecoder{
-			CoinTypeSkycoin: skycoinDecoder{},
-			CoinTypeBitcoin: bitcoinDecoder{},
+			CoinTypeSkycoin:  skycoinDecoder{},
+			CoinTypeBitcoin:  bitcoinDecoder{},
+			CoinTypeEthereum: ethereumDecoder{},
 		},
 	}
 }
@@ -101,7 +103,11 @@ func (b bitcoinDecoder) AddressFromPubKey(key cipher.PubKey) cipher.Addresser {
 	return cipher.BitcoinAddressFromPubKey(key)
 }
 
+// DecodeBase58Address decodes the base58 bitcoin address, or the bech32 address if it has the bech32 prefix
 func (b bitcoinDecoder) DecodeBase58Address(addr string) (cipher.Addresser, error) {
+	if strings.HasPrefix(strings.ToLower(addr), cipher.BitcoinBech32HRP+"1") {
+		return cipher.DecodeBech32BitcoinAddress(addr)
+	}
 	return cipher.DecodeBase58BitcoinAddress(addr)
 }
 
@@ -112,3 +118,22 @@ func (b bitcoinDecoder) SecKeyToHex(secKey cipher.SecKey) string {
 func (b bitcoinDecoder) SecKeyFromHex(secKey string) (cipher.SecKey, error) {
 	return cipher.SecKeyFromBitcoinWalletImportFormat(secKey)
 }
+
+type ethereumDecoder struct{}
+
+func (e ethereumDecoder) AddressFromPubKey(key cipher.PubKey) cipher.Addresser {
+	return cipher.EthereumAddressFromPubKey(key)
+}
+
+// DecodeBase58Address decodes the hex encoded ethereum address, ethereum addresses are not base58 encoded
+func (e ethereumDecoder) DecodeBase58Address(addr string) (cipher.Addresser, error) {
+	return cipher.DecodeHexEthereumAddress(addr)
+}
+
+func (e ethereumDecoder) SecKeyToHex(secKey cipher.SecKey) string {
+	return secKey.Hex()
+}
+
+func (e ethereumDecoder) SecKeyFromHex(secKey string) (cipher.SecKey, error) {
+	return cipher.SecKeyFromHex(strings.TrimPrefix(secKey, "0x"))
+}
@@ -32,11 +32,14 @@ type readableEntry struct {
 	Address string `json:"address"`
 	Public  string `json:"public_key"`
 	Secret  string `json:"secret_key"`
+	wallet.ReadableEntryMeta
 }
 
 // newReadableEntry creates readable wallet entry
 func newReadableEntry(coinType wallet.CoinType, e wallet.Entry) readableEntry {
-	re := readableEntry{}
+	re := readableEntry{
+		ReadableEntryMeta: wallet.NewReadableEntryMeta(e.EntryMeta),
+	}
 	if !e.Address.Null() {
 		re.Address = e.Address.String()
 	}
@@ -47,7 +50,7 @@ func newReadableEntry(coinType wallet.CoinType, e wallet.Entry) readableEntry {
 
 	if !e.Secret.Null() {
 		d := wallet.ResolveSecKeyDecoder(coinType)
-		re.Secret = d.SecKeyToHex(e.Secret)
+		re.Secret = d.SecKeyToHex(e.Secret.SecKey())
 	}
 
 	return re
@@ -88,18 +91,12 @@ func (res readableEntries) toWalletEntries(coinType wallet.CoinType, isEncrypted
 
 // newEntryFromReadable creates WalletEntry base one ReadableWalletEntry
 func newEntryFromReadable(coinType wallet.CoinType, re *readableEntry) (*wallet.Entry, error) {
-	var a cipher.Addresser
-	var err error
-
-	switch coinType {
-	case wallet.CoinTypeSkycoin:
-		a, err = cipher.DecodeBase58Address(re.Address)
-	case wallet.CoinTypeBitcoin:
-		a, err = cipher.DecodeBase58BitcoinAddress(re.Address)
-	default:
+	if _, ok := wallet.GetCoinTypeInfo(coinType); !ok {
 		panic(fmt.Errorf("invalid coin type %q", coinType))
 	}
 
+	d := wallet.ResolveAddressSecKeyDecoder(coinType)
+	a, err := d.DecodeBase58Address(re.Address)
 	if err != nil {
 		return nil, err
 	}
@@ -112,23 +109,17 @@ func newEntryFromReadable(coinType wallet.CoinType, re *readableEntry) (*wallet.
 	// Decodes the secret hex string if any
 	var secret cipher.SecKey
 	if re.Secret != "" {
-		switch coinType {
-		case wallet.CoinTypeSkycoin:
-			secret, err = cipher.SecKeyFromHex(re.Secret)
-		case wallet.CoinTypeBitcoin:
-			secret, err = cipher.SecKeyFromBitcoinWalletImportFormat(re.Secret)
-		default:
-			panic(fmt.Errorf("invalid coin type %q", coinType))
-		}
+		secret, err = d.SecKeyFromHex(re.Secret)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	return &wallet.Entry{
-		Address: a,
-		Public:  p,
-		Secret:  secret,
+		Address:   a,
+		Public:    p,
+		Secret:    wallet.NewSecretKey(secret),
+		EntryMeta: re.ToEntryMeta(),
 	}, nil
 }
 
@@ -140,8 +131,10 @@ type readableDeterministicWallet struct {
 
 // newReadableDeterministicWallet creates readable wallet
 func newReadableDeterministicWallet(w *Wallet) *readableDeterministicWallet {
+	m := w.Meta.Clone()
+	w.Seeds.ToMeta(m)
 	return &readableDeterministicWallet{
-		Meta:    w.Meta.Clone(),
+		Meta:    m,
 		Entries: newReadableEntries(w.entries, w.Meta.Coin()),
 	}
 }
@@ -151,6 +144,7 @@ func (rw *readableDeterministicWallet) toWallet() (wallet.Wallet, error) {
 	w := &Wallet{
 		Meta: rw.Meta.Clone(),
 	}
+	w.Seeds = wallet.SeedsFromMeta(w.Meta)
 
 	// make sure "sky", "btc" normalize to "skycoin", "bitcoin"
 	ct, err := wallet.ResolveCoinType(string(w.Meta.Coin()))
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/secret"
 	"github.com/skycoin/skycoin/src/wallet"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
@@ -33,6 +34,7 @@ func init() {
 // on the previous.
 type Wallet struct {
 	wallet.Meta
+	wallet.Seeds
 	entries wallet.Entries
 	decoder wallet.Decoder
 }
@@ -43,8 +45,8 @@ func NewWallet(filename, label, seed string, options ...wallet.Option) (*Wallet,
 		Meta: wallet.Meta{
 			wallet.MetaFilename:   filename,
 			wallet.MetaLabel:      label,
-			wallet.MetaSeed:       seed,
-			wallet.MetaLastSeed:   seed,
+			wallet.MetaSeed:       "",
+			wallet.MetaLastSeed:   "",
 			wallet.MetaEncrypted:  "false",
 			wallet.MetaType:       WalletType,
 			wallet.MetaVersion:    wallet.Version,
@@ -55,6 +57,8 @@ func NewWallet(filename, label, seed string, options ...wallet.Option) (*Wallet,
 		entries: wallet.Entries{},
 		decoder: defaultWalletDecoder,
 	}
+	wlt.SetSeed(seed)
+	wlt.SetLastSeed(seed)
 
 	advOpts := &wallet.AdvancedOptions{}
 	for _, opt := range options {
@@ -63,7 +67,7 @@ func NewWallet(filename, label, seed string, options ...wallet.Option) (*Wallet,
 	}
 
 	// validateMeta wallet before encrypting
-	if err := validateMeta(wlt.Meta); err != nil {
+	if err := validateMeta(wlt.Meta, wlt.Seeds); err != nil {
 		return nil, err
 	}
 
@@ -107,14 +111,14 @@ func NewWallet(filename, label, seed string, options ...wallet.Option) (*Wallet,
 	}
 
 	// validateMeta again after encrypted
-	if err := validateMeta(wlt.Meta); err != nil {
+	if err := validateMeta(wlt.Meta, wlt.Seeds); err != nil {
 		return nil, err
 	}
 
 	return wlt, nil
 }
 
-func validateMeta(m wallet.Meta) error {
+func validateMeta(m wallet.Meta, s wallet.Seeds) error {
 	if m[wallet.MetaType] != WalletType {
 		return wallet.ErrInvalidWalletType
 	}
@@ -127,7 +131,7 @@ func validateMeta(m wallet.Meta) error {
 		return err
 	}
 
-	return wallet.ValidateMetaSeed(m)
+	return wallet.ValidateMetaSeed(m, s)
 }
 
 // SetDecoder sets the decoder
@@ -181,22 +185,17 @@ func (w *Wallet) Lock(password []byte) error {
 
 	wlt.packSecrets(ss)
 
-	sb, err := ss.Serialize()
-	if err != nil {
-		return err
-	}
-
 	cryptoType := wlt.CryptoType()
 	if cryptoType == "" {
 		cryptoType = crypto.DefaultCryptoType
 	}
 
-	cryptor, err := crypto.GetCrypto(cryptoType)
+	cryptor, err := wallet.GetCryptor(cryptoType, wlt.Meta)
 	if err != nil {
 		return err
 	}
 
-	encSecret, err := cryptor.Encrypt(sb, password)
+	encSecret, err := ss.Encrypt(cryptor, password)
 	if err != nil {
 		return err
 	}
@@ -231,27 +230,24 @@ func (w *Wallet) Unlock(password []byte) (wallet.Wallet, error) {
 		return nil, errors.New("missing crypto type")
 	}
 
-	cryptor, err := crypto.GetCrypto(ct)
+	cryptor, err := wallet.GetCryptor(ct, w.Meta)
 	if err != nil {
 		return nil, err
 	}
 
-	sb, err := cryptor.Decrypt([]byte(sstr), password)
+	ss, err := wallet.DecryptSecrets(cryptor, []byte(sstr), password)
 	if err != nil {
-		return nil, wallet.ErrInvalidPassword
+		return nil, err
 	}
+	defer ss.Erase()
 
-	defer func() {
-		// Wipes the data from secrets bytes buffer
-		for i := range sb {
-			sb[i] = 0
-		}
-	}()
+	return w.UnlockSecrets(ss)
+}
 
-	ss := make(wallet.Secrets)
-	defer ss.Erase()
-	if err := ss.Deserialize(sb); err != nil {
-		return nil, err
+// UnlockSecrets returns a decrypted copy of the encrypted wallet with the decrypted secrets
+func (w *Wallet) UnlockSecrets(ss wallet.Secrets) (wallet.Wallet, error) {
+	if !w.IsEncrypted() {
+		return nil, wallet.ErrWalletNotEncrypted
 	}
 
 	cw := w.Clone().(*Wallet)
@@ -296,7 +292,7 @@ func (w *Wallet) Fingerprint() string {
 	addr := ""
 	if len(w.entries) == 0 {
 		if !w.IsEncrypted() {
-			_, pk, _ := cipher.MustDeterministicKeyPairIterator([]byte(w.Meta.Seed()))
+			_, pk, _ := cipher.MustDeterministicKeyPairIterator(w.SeedBytes())
 			addr = wallet.AddressConstructor(w.Meta)(pk).String()
 		}
 	} else {
@@ -309,6 +305,7 @@ func (w *Wallet) Fingerprint() string {
 func (w *Wallet) Clone() wallet.Wallet {
 	return &Wallet{
 		Meta:    w.Meta.Clone(),
+		Seeds:   w.Seeds.Clone(),
 		entries: w.entries.Clone(),
 		decoder: w.decoder,
 	}
@@ -317,6 +314,7 @@ func (w *Wallet) Clone() wallet.Wallet {
 // copyFrom copies the src wallet to w
 func (w *Wallet) copyFrom(src *Wallet) {
 	w.Meta = src.Meta.Clone()
+	w.Seeds = src.Seeds.Clone()
 	w.entries = src.entries.Clone()
 }
 
@@ -332,8 +330,7 @@ func (w *Wallet) Accounts() []wallet.Bip44Account {
 
 // Erase wipes secret fields in wallet
 func (w *Wallet) Erase() {
-	w.Meta.EraseSeeds()
-	w.Meta.SetLastSeed("")
+	w.Seeds.Erase()
 	w.entries.Erase()
 }
 
@@ -348,12 +345,20 @@ func (w *Wallet) Validate() error {
 		return wallet.ErrInvalidWalletType
 	}
 
-	if !w.IsEncrypted() {
-		if s := w.Seed(); s == "" {
+	if w.IsEncrypted() {
+		if len(w.SeedBytes()) != 0 {
+			return errors.New("seed should not be visible in encrypted wallets")
+		}
+
+		if len(w.LastSeedBytes()) != 0 {
+			return errors.New("lastSeed should not be visible in encrypted wallets")
+		}
+	} else {
+		if len(w.SeedBytes()) == 0 {
 			return errors.New("seed missing in unencrypted deterministic wallet")
 		}
 
-		if s := w.LastSeed(); s == "" {
+		if len(w.LastSeedBytes()) == 0 {
 			return errors.New("lastSeed missing in unencrypted deterministic wallet")
 		}
 	}
@@ -412,7 +417,7 @@ func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]ci
 }
 
 // GenerateAddresses generates N addresses
-func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Addresser, error) {
+func (w *Wallet) GenerateAddresses(num uint64, options ...wallet.Option) ([]cipher.Addresser, error) {
 	if w.Meta.IsEncrypted() {
 		return nil, wallet.ErrWalletEncrypted
 	}
@@ -421,33 +426,54 @@ func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Add
 		return nil, nil
 	}
 
-	var seckeys []cipher.SecKey
-	var seed []byte
-	if len(w.entries) == 0 {
-		seed, seckeys = cipher.MustGenerateDeterministicKeyPairsSeed([]byte(w.Meta.Seed()), int(num))
-	} else {
-		sd, err := hex.DecodeString(w.Meta.LastSeed())
-		if err != nil {
+	// The hex last seed is decoded to guarded memory
+	sd := w.SeedBytes()
+	if len(w.entries) != 0 {
+		b := secret.New(hex.DecodedLen(len(w.LastSeedBytes())))
+		defer b.Destroy()
+		if _, err := hex.Decode(b.Bytes(), w.LastSeedBytes()); err != nil {
 			return nil, fmt.Errorf("decode hex seed failed: %v", err)
 		}
-		seed, seckeys = cipher.MustGenerateDeterministicKeyPairsSeed(sd, int(num))
+		sd = b.Bytes()
 	}
 
-	w.Meta.SetLastSeed(hex.EncodeToString(seed))
+	// The seed sequence is computed sequentially, the key pairs are then
+	// generated by a pool of workers
+	seeds, err := cipher.DeterministicKeyPairSeeds(sd, int(num))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, sd := range seeds[1:] {
+			secret.Wipe(sd)
+		}
+	}()
 
-	addrs := make([]cipher.Addresser, len(seckeys))
+	opts := wallet.NewGenerateOptions(options...)
 	makeAddress := wallet.AddressConstructor(w.Meta)
-	for i, s := range seckeys {
-		p := cipher.MustPubKeyFromSecKey(s)
-		a := makeAddress(p)
-		addrs[i] = a
-		w.entries = append(w.entries, wallet.Entry{
-			Address: a,
-			Secret:  s,
+	entries := make(wallet.Entries, num)
+	if err := wallet.ParallelDo(int(num), opts.Workers, func(i int) error {
+		p, s, err := cipher.DeterministicKeyPairFromSeeds(seeds[i], seeds[i+1])
+		if err != nil {
+			return err
+		}
+		entries[i] = wallet.Entry{
+			Address: makeAddress(p),
+			Secret:  wallet.NewSecretKey(s),
 			Public:  p,
-		})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	return addrs, nil
+
+	lastSeed := secret.New(hex.EncodedLen(len(seeds[num])))
+	defer lastSeed.Destroy()
+	hex.Encode(lastSeed.Bytes(), seeds[num])
+	w.SetLastSeedBytes(lastSeed.Bytes())
+
+	w.entries = append(w.entries, entries...)
+	return entries.GetAddresses(), nil
 }
 
 // GetAddresses returns all addresses in wallet
@@ -478,6 +504,23 @@ func (w *Wallet) GetEntry(a cipher.Addresser, _ ...wallet.Option) (wallet.Entry,
 	return e, nil
 }
 
+// GetEntryMeta returns the label, note and tags of the entry of given address
+func (w *Wallet) GetEntryMeta(a cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	e, ok := w.entries.Get(a)
+	if !ok {
+		return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+	}
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address
+func (w *Wallet) SetEntryMeta(a cipher.Addresser, m wallet.EntryMeta, _ ...wallet.Option) error {
+	if !w.entries.SetMeta(a, m) {
+		return wallet.ErrEntryNotFound
+	}
+	return nil
+}
+
 // HasEntry returns true if the wallet has an Entry with a given cipher.Address.
 func (w *Wallet) HasEntry(a cipher.Addresser, _ ...wallet.Option) (bool, error) {
 	return w.entries.Has(a), nil
@@ -491,7 +534,7 @@ func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
 // reset resets the wallet entries and move the lastSeed to origin
 func (w *Wallet) reset() {
 	w.entries = wallet.Entries{}
-	w.Meta.SetLastSeed(w.Meta.Seed())
+	w.SetLastSeedBytes(w.SeedBytes())
 }
 
 // Loader implements the wallet.Loader interface
@@ -522,6 +565,10 @@ func convertOptions(options wallet.Options) []wallet.Option {
 		opts = append(opts, wallet.OptionCoinType(options.Coin))
 	}
 
+	if options.AddressEncoding != "" {
+		opts = append(opts, wallet.OptionAddressEncoding(options.AddressEncoding))
+	}
+
 	if options.CryptoType != "" {
 		opts = append(opts, wallet.OptionCryptoType(options.CryptoType))
 	}
@@ -61,7 +61,7 @@ func skycoinEntries(es []readableEntry) []wallet.Entry {
 		entries[i] = wallet.Entry{
 			Address: cipher.MustDecodeBase58Address(e.Address),
 			Public:  pk,
-			Secret:  sk,
+			Secret:  wallet.NewSecretKey(sk),
 		}
 	}
 
@@ -118,6 +118,25 @@ func TestNewWallet(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name:    "ok with ethereum coin, deterministic",
+			wltName: "test.wlt",
+			label:   "test",
+			seed:    "testseed123",
+			opts: []wallet.Option{
+				wallet.OptionCoinType(wallet.CoinTypeEthereum),
+			},
+			expect: expect{
+				meta: map[string]string{
+					"label":    "test",
+					"filename": "test.wlt",
+					"coin":     string(wallet.CoinTypeEthereum),
+					"type":     wallet.WalletTypeDeterministic,
+					"seed":     "testseed123",
+				},
+				err: nil,
+			},
+		},
 		{
 			name:    "ok default crypto type, deterministic",
 			wltName: "test.wlt",
@@ -201,9 +220,11 @@ func TestNewWallet(t *testing.T) {
 				}
 
 				//require.Equal(t, tc.opts.Encrypt, w.IsEncrypted())
-				// confirms the meta data
+				// confirms the meta data, with the seeds as they are serialized
+				m := w.Meta.Clone()
+				w.Seeds.ToMeta(m)
 				for k, v := range tc.expect.meta {
-					require.Equal(t, v, w.Meta[k])
+					require.Equal(t, v, m[k])
 				}
 
 				if w.IsEncrypted() {
@@ -286,7 +307,7 @@ func TestWalletLock(t *testing.T) {
 				require.NoError(t, err)
 
 				for _, e := range entries {
-					require.Equal(t, cipher.SecKey{}, e.Secret)
+					require.True(t, e.Secret.Null())
 				}
 			})
 
@@ -510,6 +531,47 @@ func TestWalletGenerateAddress(t *testing.T) {
 	}
 }
 
+func TestWalletGenerateAddressesParallel(t *testing.T) {
+	seed := bip39.MustNewDefaultMnemonic()
+	num := wallet.ParallelThreshold + 10
+
+	w, err := NewWallet("test.wlt", "test", seed)
+	require.NoError(t, err)
+	addrs, err := w.GenerateAddresses(uint64(num), wallet.OptionWorkers(4))
+	require.NoError(t, err)
+
+	sw, err := NewWallet("test.wlt", "test", seed)
+	require.NoError(t, err)
+	saddrs, err := sw.GenerateAddresses(uint64(num), wallet.OptionWorkers(1))
+	require.NoError(t, err)
+	require.Equal(t, saddrs, addrs)
+	require.Equal(t, sw.LastSeed(), w.LastSeed())
+
+	lastSeed, keys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte(seed), num)
+	require.Equal(t, hex.EncodeToString(lastSeed), w.LastSeed())
+	es, err := w.GetEntries()
+	require.NoError(t, err)
+	for i, k := range keys {
+		require.Equal(t, k, es[i].Secret.SecKey())
+		require.Equal(t, cipher.MustPubKeyFromSecKey(k), es[i].Public)
+		require.Equal(t, cipher.MustAddressFromSecKey(k), addrs[i])
+	}
+}
+
+func BenchmarkWalletGenerateAddresses(b *testing.B) {
+	seed := bip39.MustNewDefaultMnemonic()
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				w, err := NewWallet("test.wlt", "test", seed)
+				require.NoError(b, err)
+				_, err = w.GenerateAddresses(1000, wallet.OptionWorkers(workers))
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
 func TestWalletGetEntry(t *testing.T) {
 	tt := []struct {
 		name    string
@@ -557,6 +619,63 @@ func TestWalletGetEntry(t *testing.T) {
 	}
 }
 
+func TestWalletEntryMeta(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", "test123", wallet.OptionGenerateN(2))
+	require.NoError(t, err)
+
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+
+	m, err := w.GetEntryMeta(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, wallet.EntryMeta{}, m)
+
+	m = wallet.EntryMeta{
+		Label: "savings",
+		Note:  "created for the cold storage",
+		Tags:  map[string]string{"owner": "alice"},
+	}
+	require.NoError(t, w.SetEntryMeta(addrs[0], m))
+
+	// Changes to the meta after setting it won't affect the wallet
+	m.Tags["owner"] = "bob"
+
+	m1, err := w.GetEntryMeta(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, "savings", m1.Label)
+	require.Equal(t, "created for the cold storage", m1.Note)
+	require.Equal(t, map[string]string{"owner": "alice"}, m1.Tags)
+
+	// Entry meta is persisted in the wallet file
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	w1, err := Loader{}.Load(b)
+	require.NoError(t, err)
+	m2, err := w1.GetEntryMeta(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, m1, m2)
+	m2, err = w1.GetEntryMeta(addrs[1])
+	require.NoError(t, err)
+	require.Equal(t, wallet.EntryMeta{}, m2)
+
+	// Entry meta is kept after locking and unlocking the wallet
+	require.NoError(t, w.Lock([]byte("pwd")))
+	m3, err := w.GetEntryMeta(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, m1, m3)
+	uw, err := w.Unlock([]byte("pwd"))
+	require.NoError(t, err)
+	m3, err = uw.GetEntryMeta(addrs[0])
+	require.NoError(t, err)
+	require.Equal(t, m1, m3)
+
+	// Entry does not exist
+	err = w.SetEntryMeta(cipher.MustDecodeBase58Address("2ULfxDUuenUY5V4Pr8whmoAwFdUseXNyjXC"), m)
+	require.Equal(t, wallet.ErrEntryNotFound, err)
+	_, err = w.GetEntryMeta(cipher.MustDecodeBase58Address("2ULfxDUuenUY5V4Pr8whmoAwFdUseXNyjXC"))
+	require.Equal(t, wallet.ErrEntryNotFound, err)
+}
+
 func checkNoSensitiveData(t *testing.T, w wallet.Wallet) {
 	require.Empty(t, w.Seed())
 	require.Empty(t, w.LastSeed())
@@ -680,8 +799,9 @@ func TestWalletValidate(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			w := &Wallet{
-				Meta: tc.meta,
+				Meta: wallet.Meta(tc.meta).Clone(),
 			}
+			w.Seeds = wallet.SeedsFromMeta(w.Meta)
 			err := w.Validate()
 
 			if tc.err == nil {
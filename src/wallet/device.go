@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+)
+
+var (
+	// ErrDeviceRequired is returned when an operation on a hardware wallet needs the
+	// hardware device, but no signer for the device is registered or the device is not connected
+	ErrDeviceRequired = NewError(errors.New("hardware wallet device is required"))
+	// ErrDeviceSignatureInvalid is returned when the device returns a signature that does not
+	// match the wallet's public key, e.g. when a device with a different seed is connected
+	ErrDeviceSignatureInvalid = NewError(errors.New("hardware wallet device returned an invalid signature"))
+)
+
+var deviceSigners signers
+
+// DeviceSigner signs transactions with the keys held by a hardware device, e.g. a Ledger or Trezor.
+// Implementations are registered per device type with RegisterDeviceSigner.
+type DeviceSigner interface {
+	// Connected returns true if the device is connected and ready to sign
+	Connected() bool
+	// SignTransaction returns the signatures of the requested transaction inputs, in the same order as inputs
+	SignTransaction(txn *coin.Transaction, inputs []DeviceInput) ([]cipher.Sig, error)
+}
+
+// DeviceInput is a transaction input that is going to be signed by a hardware device
+type DeviceInput struct {
+	Index  int           // index of the input in the transaction
+	Path   string        // bip32 derivation path of the signing key
+	PubKey cipher.PubKey // public key of the signing key, derived from the wallet xpub
+}
+
+// RegisterDeviceSigner registers the signer of a hardware device type
+func RegisterDeviceSigner(device string, s DeviceSigner) error {
+	return deviceSigners.add(device, s)
+}
+
+// UnregisterDeviceSigner removes the signer of a hardware device type
+func UnregisterDeviceSigner(device string) {
+	deviceSigners.remove(device)
+}
+
+// checkDevice returns ErrDeviceRequired if w is a hardware wallet and its device can't sign
+func checkDevice(w Wallet) error {
+	if w.Type() != WalletTypeHardware {
+		return nil
+	}
+
+	_, err := getConnectedDeviceSigner(w.Device())
+	return err
+}
+
+func getConnectedDeviceSigner(device string) (DeviceSigner, error) {
+	s, ok := deviceSigners.get(device)
+	if !ok || !s.Connected() {
+		return nil, ErrDeviceRequired
+	}
+	return s, nil
+}
+
+// signInputsWithDevice signs the inputs of txn listed in addrsMap with the hardware device of w
+func signInputsWithDevice(w Wallet, txn *coin.Transaction, addrsMap map[cipher.Address][]int) error {
+	signer, err := getConnectedDeviceSigner(w.Device())
+	if err != nil {
+		return err
+	}
+
+	var inputs []DeviceInput
+	for addr, x := range addrsMap {
+		e, err := w.GetEntry(addr)
+		if err != nil {
+			if err == ErrEntryNotFound {
+				return NewError(errors.New("Wallet cannot sign all requested inputs"))
+			}
+			return err
+		}
+
+		for _, i := range x {
+			inputs = append(inputs, DeviceInput{
+				Index:  i,
+				Path:   fmt.Sprintf("%s/%d", w.DerivationPath(), e.ChildNumber),
+				PubKey: e.Public,
+			})
+		}
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].Index < inputs[j].Index
+	})
+
+	sigs, err := signer.SignTransaction(txn, inputs)
+	if err != nil {
+		return err
+	}
+
+	if len(sigs) != len(inputs) {
+		return NewError(fmt.Errorf("hardware wallet device returned %d signatures, expected %d", len(sigs), len(inputs)))
+	}
+
+	for i, in := range inputs {
+		if !txn.Sigs[in.Index].Null() {
+			return NewError(fmt.Errorf("Transaction is already signed at index %d", in.Index))
+		}
+
+		h := cipher.AddSHA256(txn.InnerHash, txn.In[in.Index])
+		if err := cipher.VerifyPubKeySignedHash(in.PubKey, sigs[i], h); err != nil {
+			return ErrDeviceSignatureInvalid
+		}
+
+		txn.Sigs[in.Index] = sigs[i]
+	}
+
+	return nil
+}
+
+type signers struct {
+	l  sync.Mutex
+	ss map[string]DeviceSigner
+}
+
+func (ss *signers) add(device string, s DeviceSigner) error {
+	ss.l.Lock()
+	defer ss.l.Unlock()
+	if ss.ss == nil {
+		ss.ss = map[string]DeviceSigner{}
+	}
+
+	if _, ok := ss.ss[device]; ok {
+		return fmt.Errorf("device signer for %s already exists", device)
+	}
+
+	ss.ss[device] = s
+	return nil
+}
+
+func (ss *signers) remove(device string) {
+	ss.l.Lock()
+	defer ss.l.Unlock()
+	delete(ss.ss, device)
+}
+
+func (ss *signers) get(device string) (DeviceSigner, bool) {
+	ss.l.Lock()
+	defer ss.l.Unlock()
+	s, ok := ss.ss[device]
+	return s, ok
+}
@@ -0,0 +1,175 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
+)
+
+var (
+	// ErrDuressPasswordReused is returned when the duress password is the same as the wallet password
+	ErrDuressPasswordReused = NewError(errors.New("duress password must be different from the wallet password"))
+	// ErrMissingDuressAddresses is returned when setting a duress password without decoy addresses
+	ErrMissingDuressAddresses = NewError(errors.New("missing decoy addresses of the duress password"))
+	// ErrMissingDuressPassword is returned when setting an empty duress password
+	ErrMissingDuressPassword = NewError(errors.New("missing duress password"))
+)
+
+// newDuressSecrets returns the secret keys of the decoy entries of the decrypted wallet, keyed by address
+func newDuressSecrets(w Wallet, addrs []cipher.Address) (Secrets, error) {
+	entries, err := allEntries(w)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make(Secrets, len(addrs))
+	for _, a := range addrs {
+		e, ok := findEntry(entries, a)
+		if !ok {
+			ss.Erase()
+			return nil, fmt.Errorf("decoy address %s is not in the wallet", a)
+		}
+
+		if e.Secret.Null() {
+			ss.Erase()
+			return nil, fmt.Errorf("decoy address %s has no secret key", a)
+		}
+
+		ss.Set(a.String(), e.Secret.Hex())
+	}
+	return ss, nil
+}
+
+// openDuress returns the decoy wallet opened by the duress password of the encrypted wallet.
+// It's an unencrypted collection wallet with the filename and label of the wallet,
+// holding only the decoy entries with their secret keys.
+// Returns ErrInvalidPassword if the password is not the duress password.
+func openDuress(w Wallet, password []byte) (Wallet, error) {
+	ss, err := decryptDuress(w, password)
+	if err != nil {
+		return nil, err
+	}
+	defer ss.Erase()
+
+	return newDecoyWallet(w, ss)
+}
+
+// newDecoyWallet returns the decoy wallet of the encrypted wallet with the decrypted duress secrets
+func newDecoyWallet(w Wallet, ss Secrets) (Wallet, error) {
+	entries, err := allEntries(w)
+	if err != nil {
+		return nil, err
+	}
+
+	decoy, err := NewWallet(w.Filename(), w.Label(), "", Options{
+		Type: WalletTypeCollection,
+		Coin: w.Coin(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adder, ok := decoy.(interface {
+		AddEntry(Entry) error
+	})
+	if !ok {
+		return nil, fmt.Errorf("%s wallet does not support adding entries", decoy.Type())
+	}
+
+	// The decoy entries keep the order of the wallet entries
+	for _, e := range entries {
+		sk, ok := ss.Get(e.Address.String())
+		if !ok {
+			continue
+		}
+
+		e.Secret, err = SecretKeyFromHex(sk)
+		if err != nil {
+			decoy.Erase()
+			return nil, err
+		}
+
+		if err := adder.AddEntry(e); err != nil {
+			decoy.Erase()
+			return nil, err
+		}
+	}
+
+	return decoy, nil
+}
+
+// decryptDuress decrypts the duress secrets of the wallet with the password.
+// Returns ErrInvalidPassword if the password is not the duress password.
+func decryptDuress(w Wallet, password []byte) (Secrets, error) {
+	s := w.DuressSecrets()
+	if s == "" {
+		return nil, ErrInvalidPassword
+	}
+
+	cryptor, err := walletCryptor(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecryptSecrets(cryptor, []byte(s), password)
+}
+
+// walletCryptor returns the cryptor of the wallet secrets, with the argon2id parameters of the wallet.
+// The duress secrets are encrypted like the wallet secrets.
+func walletCryptor(w Wallet) (crypto.Cryptor, error) {
+	m := make(Meta)
+	m.SetArgon2Params(w.Argon2Params())
+	return GetCryptor(w.CryptoType(), m)
+}
+
+// guardViewDuress is GuardView for the wallets with a duress password,
+// f is executed with the decoy wallet if the password is the duress password
+func guardViewDuress(w Wallet, password []byte, f func(w Wallet) error) error {
+	if !w.IsEncrypted() {
+		return ErrWalletNotEncrypted
+	}
+
+	if len(password) == 0 {
+		return ErrMissingPassword
+	}
+
+	wlt, err := w.Unlock(password)
+	if err == ErrInvalidPassword && w.DuressSecrets() != "" {
+		wlt, err = openDuress(w, password)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer wlt.Erase()
+
+	return f(wlt)
+}
+
+// allEntries returns the entries of all chains and accounts of the wallet
+func allEntries(w Wallet) (Entries, error) {
+	if w.Type() != WalletTypeBip44 {
+		return w.GetEntries()
+	}
+
+	var entries Entries
+	for _, a := range w.Accounts() {
+		es, err := w.GetEntries(OptionAccount(a.Index), OptionExternal(), OptionChange())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, es...)
+	}
+	return entries, nil
+}
+
+func findEntry(entries Entries, addr cipher.Address) (Entry, bool) {
+	for _, e := range entries {
+		if e.Address.String() == addr.String() {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
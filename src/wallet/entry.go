@@ -1,20 +1,142 @@
 package wallet
 
 import (
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"fmt"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/secret"
 )
 
+// SecretKey is the secret key of a wallet entry, held in a secret.Buffer.
+// The zero SecretKey is a null key. The copies of an Entry share its secret key,
+// Entries.Clone copies the secret keys to new buffers and Entries.Erase destroys them.
+type SecretKey struct {
+	b *secret.Buffer
+}
+
+// NewSecretKey copies the secret key to a secret.Buffer, a null key returns a null SecretKey
+func NewSecretKey(sk cipher.SecKey) SecretKey {
+	if sk.Null() {
+		return SecretKey{}
+	}
+	return SecretKey{
+		b: secret.FromBytes(sk[:]),
+	}
+}
+
+// SecretKeyFromHex decodes the hex secret key to a secret.Buffer
+func SecretKeyFromHex(s string) (SecretKey, error) {
+	sk, err := cipher.SecKeyFromHex(s)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	return NewSecretKey(sk), nil
+}
+
+// SecKey returns a copy of the secret key, to sign with it.
+// Returns a null key if the secret key is null or has been erased.
+func (k SecretKey) SecKey() cipher.SecKey {
+	var sk cipher.SecKey
+	if k.b != nil {
+		copy(sk[:], k.b.Bytes())
+	}
+	return sk
+}
+
+// Null returns true if the secret key is null or has been erased
+func (k SecretKey) Null() bool {
+	if k.b == nil {
+		return true
+	}
+	for _, c := range k.b.Bytes() {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Hex returns the hex encoding of the secret key
+func (k SecretKey) Hex() string {
+	sk := k.SecKey()
+	defer secret.Wipe(sk[:])
+	return hex.EncodeToString(sk[:])
+}
+
+// Equal returns true if the secret keys are the same
+func (k SecretKey) Equal(k2 SecretKey) bool {
+	sk, sk2 := k.SecKey(), k2.SecKey()
+	defer func() {
+		secret.Wipe(sk[:])
+		secret.Wipe(sk2[:])
+	}()
+	return subtle.ConstantTimeCompare(sk[:], sk2[:]) == 1
+}
+
+// Clone copies the secret key to a new secret.Buffer
+func (k SecretKey) Clone() SecretKey {
+	if k.b == nil {
+		return SecretKey{}
+	}
+	return SecretKey{
+		b: bytesBuffer(k.b.Bytes()),
+	}
+}
+
+// destroy wipes and frees the buffer of the secret key
+func (k *SecretKey) destroy() {
+	if k.b != nil {
+		k.b.Destroy()
+	}
+	k.b = nil
+}
+
 // Entry represents the wallet entry
 type Entry struct {
 	Address     cipher.Addresser
 	Public      cipher.PubKey
-	Secret      cipher.SecKey
+	Secret      SecretKey
 	ChildNumber uint32 // For bip32/bip44
 	Change      uint32 // For bip44
+	EntryMeta
+}
+
+// EntryMeta records the user metadata of a wallet entry
+type EntryMeta struct {
+	Label string            // user label of the address
+	Note  string            // note recorded when creating the address
+	Tags  map[string]string // arbitrary key/value tags
+}
+
+// Clone returns a copy of the entry meta
+func (m EntryMeta) Clone() EntryMeta {
+	nm := EntryMeta{
+		Label: m.Label,
+		Note:  m.Note,
+	}
+
+	if len(m.Tags) > 0 {
+		nm.Tags = make(map[string]string, len(m.Tags))
+		for k, v := range m.Tags {
+			nm.Tags[k] = v
+		}
+	}
+
+	return nm
+}
+
+// IsEmpty returns true if the entry meta has no label, note and tags
+func (m EntryMeta) IsEmpty() bool {
+	return m.Label == "" && m.Note == "" && len(m.Tags) == 0
+}
+
+// AddressLabel is the label, note and tags of an address of a wallet
+type AddressLabel struct {
+	Address cipher.Addresser
+	EntryMeta
 }
 
 // SkycoinAddress returns the Skycoin address of an entry. Panics if Address is not a Skycoin address
@@ -30,7 +152,7 @@ func (we Entry) BitcoinAddress() cipher.BitcoinAddress {
 // Verify checks that the public key is derivable from the secret key,
 // and that the public key is associated with the address
 func (we *Entry) Verify() error {
-	pk, err := cipher.PubKeyFromSecKey(we.Secret)
+	pk, err := cipher.PubKeyFromSecKey(we.Secret.SecKey())
 	if err != nil {
 		return err
 	}
@@ -53,12 +175,17 @@ func (we *Entry) VerifyPublic() error {
 // Entries are an array of wallet entries
 type Entries []Entry
 
-// Clone make an copy of the entire entries.
+// Clone make an copy of the entire entries, the secret keys are copied to new buffers
 func (entries Entries) Clone() Entries {
 	if len(entries) == 0 {
 		return nil
 	}
-	return append(Entries{}, entries...)
+	es := append(Entries{}, entries...)
+	for i := range es {
+		es[i].Secret = es[i].Secret.Clone()
+		es[i].EntryMeta = es[i].EntryMeta.Clone()
+	}
+	return es
 }
 
 // Has checks if entries contains the entry with specified address
@@ -83,6 +210,18 @@ func (entries Entries) Get(a cipher.Addresser) (Entry, bool) {
 	return Entry{}, false
 }
 
+// SetMeta sets the meta of the entry with specific address,
+// returns false if the entry does not exist
+func (entries Entries) SetMeta(a cipher.Addresser, m EntryMeta) bool {
+	for i := range entries {
+		if entries[i].Address == a {
+			entries[i].EntryMeta = m.Clone()
+			return true
+		}
+	}
+	return false
+}
+
 // GetAddresses returns all addresses
 func (entries Entries) GetAddresses() []cipher.Addresser {
 	addrs := make([]cipher.Addresser, len(entries))
@@ -92,13 +231,10 @@ func (entries Entries) GetAddresses() []cipher.Addresser {
 	return addrs
 }
 
-// Erase wipes private keys in entries
+// Erase wipes and frees the secret keys of the entries
 func (entries Entries) Erase() {
 	for i := range entries {
-		for j := range entries[i].Secret {
-			entries[i].Secret[j] = 0
-		}
-		entries[i].Secret = cipher.SecKey{}
+		entries[i].Secret.destroy()
 	}
 }
 
@@ -115,8 +251,39 @@ func (entries Entries) UnpackSecretKeys(ss Secrets) error {
 			return fmt.Errorf("decode secret hex string failed: %v", err)
 		}
 
-		copy(entries[i].Secret[:], s[:])
+		var sk cipher.SecKey
+		copy(sk[:], s)
+		secret.Wipe(s)
+		entries[i].Secret.destroy()
+		entries[i].Secret = NewSecretKey(sk)
 	}
 
 	return nil
 }
+
+// ReadableEntryMeta is the JSON representation of EntryMeta,
+// which is embedded in the readable entries of wallet decoders
+type ReadableEntryMeta struct {
+	Label string            `json:"label,omitempty"`
+	Note  string            `json:"note,omitempty"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// NewReadableEntryMeta creates a ReadableEntryMeta
+func NewReadableEntryMeta(m EntryMeta) ReadableEntryMeta {
+	m = m.Clone()
+	return ReadableEntryMeta{
+		Label: m.Label,
+		Note:  m.Note,
+		Tags:  m.Tags,
+	}
+}
+
+// ToEntryMeta converts the ReadableEntryMeta to EntryMeta
+func (rm ReadableEntryMeta) ToEntryMeta() EntryMeta {
+	return EntryMeta{
+		Label: rm.Label,
+		Note:  rm.Note,
+		Tags:  rm.Tags,
+	}.Clone()
+}
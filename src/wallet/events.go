@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"sync"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// EventType is the type of a wallet event
+type EventType string
+
+const (
+	// EventWalletCreated is emitted when a wallet is created or imported
+	EventWalletCreated EventType = "walletCreated"
+	// EventAddressGenerated is emitted when addresses are added to a wallet
+	EventAddressGenerated EventType = "addressGenerated"
+	// EventEncrypted is emitted when a wallet is encrypted
+	EventEncrypted EventType = "encrypted"
+	// EventDecrypted is emitted when a wallet is decrypted
+	EventDecrypted EventType = "decrypted"
+	// EventBalanceChanged is emitted when the reported balance of a wallet changes
+	EventBalanceChanged EventType = "balanceChanged"
+)
+
+// DefaultEventBufferSize is the channel buffer size of a subscription if none is specified
+const DefaultEventBufferSize = 32
+
+// Event is a wallet event emitted by the wallet service
+type Event struct {
+	Type     EventType
+	WalletID string
+	// Addresses are the new addresses of an EventAddressGenerated event
+	Addresses []cipher.Address
+	// Balance is the new balance of an EventBalanceChanged event
+	Balance *BalancePair
+}
+
+// eventBus delivers the wallet events to the subscribers.
+// Events are dropped for subscribers whose channel buffer is full, so that
+// a slow subscriber can not block the wallet service.
+type eventBus struct {
+	sync.Mutex
+	subs   map[uint64]chan Event
+	nextID uint64
+	// balances are the last reported balances of the wallets
+	balances map[string]BalancePair
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subs:     make(map[uint64]chan Event),
+		balances: make(map[string]BalancePair),
+	}
+}
+
+func (b *eventBus) subscribe(bufferSize int) (<-chan Event, func()) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	c := make(chan Event, bufferSize)
+	b.subs[id] = c
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			delete(b.subs, id)
+			close(c)
+		})
+	}
+
+	return c, unsubscribe
+}
+
+func (b *eventBus) publish(e Event) {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, c := range b.subs {
+		select {
+		case c <- e:
+		default:
+			logger.WithField("eventType", e.Type).Warning("Wallet event subscriber is full, dropping event")
+		}
+	}
+}
+
+// reportBalance publishes an EventBalanceChanged event if the balance differs
+// from the last reported balance of the wallet
+func (b *eventBus) reportBalance(wltID string, balance BalancePair) {
+	b.Lock()
+	last, ok := b.balances[wltID]
+	b.balances[wltID] = balance
+	b.Unlock()
+
+	if ok && last == balance {
+		return
+	}
+
+	b.publish(Event{
+		Type:     EventBalanceChanged,
+		WalletID: wltID,
+		Balance:  &balance,
+	})
+}
+
+func (b *eventBus) forget(wltID string) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.balances, wltID)
+}
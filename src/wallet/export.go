@@ -0,0 +1,207 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	"github.com/skycoin/skycoin/src/util/droplet"
+)
+
+// ExportFormat is the format of the exported wallet entries
+type ExportFormat string
+
+const (
+	// ExportFormatCSV exports the entries as CSV rows with a header row
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatJSON exports the entries as a JSON array
+	ExportFormatJSON ExportFormat = "json"
+)
+
+var (
+	// ErrInvalidExportFormat is returned when exporting the wallet entries in an unknown format
+	ErrInvalidExportFormat = NewError(errors.New("invalid export format"))
+	// ErrExportCoinType is returned when exporting the entries of a wallet whose coin is not skycoin
+	ErrExportCoinType = NewError(errors.New("only the entries of skycoin wallets can be exported"))
+)
+
+// exportHeader is the header row of the CSV export
+var exportHeader = []string{"address", "path", "label", "coins", "hours", "last_used_height"}
+
+// AddressesFinder interface for finding the balances and the last used block heights of addresses
+type AddressesFinder interface {
+	GetBalanceOfAddresses(addrs []cipher.Address) ([]BalancePair, error)
+	// GetAddressesLastUsedHeight returns the block seq of the last confirmed transaction of each address,
+	// nil if the address has no confirmed transactions
+	GetAddressesLastUsedHeight(addrs []cipher.Address) ([]*uint64, error)
+}
+
+// ExportEntry is an exported wallet entry with its confirmed balance
+type ExportEntry struct {
+	Address string `json:"address"`
+	// Path is the bip32 derivation path of the address, empty if the address is not derived by bip32
+	Path  string `json:"path"`
+	Label string `json:"label"`
+	// Coins is the confirmed coins balance, e.g. "1.500000"
+	Coins string `json:"coins"`
+	// Hours is the confirmed coin hours balance
+	Hours uint64 `json:"hours"`
+	// LastUsedHeight is the block seq of the last confirmed transaction of the address, nil if never used
+	LastUsedHeight *uint64 `json:"last_used_height"`
+}
+
+// newExportEntries returns the export entries of all addresses of the wallet, without balances
+func newExportEntries(w Wallet) ([]ExportEntry, error) {
+	switch w.Type() {
+	case WalletTypeBip44:
+		return newBip44ExportEntries(w)
+	}
+
+	entries, err := w.GetEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	es := make([]ExportEntry, len(entries))
+	for i, e := range entries {
+		es[i] = ExportEntry{
+			Address: e.Address.String(),
+			Label:   e.Label,
+		}
+
+		switch w.Type() {
+		case WalletTypeXPub:
+			// The path of the xpub key is unknown, the path is relative to the xpub key
+			if w.IsXPubAccount() {
+				es[i].Path = fmt.Sprintf("M/%d/%d", e.Change, e.ChildNumber)
+			} else {
+				es[i].Path = fmt.Sprintf("M/%d", e.ChildNumber)
+			}
+		case WalletTypeHardware:
+			es[i].Path = fmt.Sprintf("%s/%d", w.DerivationPath(), e.ChildNumber)
+		}
+	}
+	return es, nil
+}
+
+func newBip44ExportEntries(w Wallet) ([]ExportEntry, error) {
+	bip44Coin := w.Bip44Coin()
+	if bip44Coin == nil {
+		return nil, errors.New("Wallet has no Bip44Coin meta data")
+	}
+
+	pt := bip44.DefaultPathTemplate(*bip44Coin)
+	if s := w.Bip44PathTemplate(); s != "" {
+		t, err := bip44.ParsePathTemplate(s)
+		if err != nil {
+			return nil, err
+		}
+		pt = *t
+	}
+
+	var es []ExportEntry
+	for _, a := range w.Accounts() {
+		entries, err := w.GetEntries(OptionAccount(a.Index), OptionExternal(), OptionChange())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			es = append(es, ExportEntry{
+				Address: e.Address.String(),
+				Path:    pt.Path(a.Index, e.Change, e.ChildNumber),
+				Label:   e.Label,
+			})
+		}
+	}
+	return es, nil
+}
+
+// setExportBalances sets the confirmed balances and the last used heights of the export entries
+func setExportBalances(es []ExportEntry, af AddressesFinder) error {
+	if len(es) == 0 {
+		return nil
+	}
+
+	addrs := make([]cipher.Address, len(es))
+	for i, e := range es {
+		a, err := cipher.DecodeBase58Address(e.Address)
+		if err != nil {
+			return err
+		}
+		addrs[i] = a
+	}
+
+	balances, err := af.GetBalanceOfAddresses(addrs)
+	if err != nil {
+		return err
+	}
+
+	heights, err := af.GetAddressesLastUsedHeight(addrs)
+	if err != nil {
+		return err
+	}
+
+	if len(balances) != len(es) || len(heights) != len(es) {
+		return errors.New("the number of the found balances does not match the number of addresses")
+	}
+
+	for i := range es {
+		coins, err := droplet.ToString(balances[i].Confirmed.Coins)
+		if err != nil {
+			return err
+		}
+		es[i].Coins = coins
+		es[i].Hours = balances[i].Confirmed.Hours
+		es[i].LastUsedHeight = heights[i]
+	}
+	return nil
+}
+
+// encodeExportEntries encodes the export entries in the format
+func encodeExportEntries(es []ExportEntry, format ExportFormat) ([]byte, error) {
+	switch format {
+	case ExportFormatJSON:
+		if es == nil {
+			es = []ExportEntry{}
+		}
+		return json.MarshalIndent(es, "", "    ")
+	case ExportFormatCSV:
+		var buf bytes.Buffer
+		cw := csv.NewWriter(&buf)
+		if err := cw.Write(exportHeader); err != nil {
+			return nil, err
+		}
+
+		for _, e := range es {
+			var height string
+			if e.LastUsedHeight != nil {
+				height = strconv.FormatUint(*e.LastUsedHeight, 10)
+			}
+
+			if err := cw.Write([]string{
+				e.Address,
+				e.Path,
+				e.Label,
+				e.Coins,
+				strconv.FormatUint(e.Hours, 10),
+				height,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
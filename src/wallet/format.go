@@ -0,0 +1,341 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/util/file"
+)
+
+// The wallet file formats
+//
+// v1 and v2 wallet files store the meta data as a map of strings, the v1 files have
+// meta version 0.1, the v2 files have meta version 0.2 and above. The files have
+// no formatVersion field.
+//
+// v3 wallet files store the meta data as typed fields, e.g. encrypted is a JSON bool
+// and tm is a JSON number. Third-party data is kept in the extensions section, which is
+// preserved by the loaders. The file layout is described by FileSchemaV3.
+const (
+	// FileFormatVersion is the wallet file format version written by Save
+	FileFormatVersion = 3
+
+	// LegacyBackupExt is the extension appended to the backup copy of a v1/v2
+	// wallet file when it is upgraded to the v3 format
+	LegacyBackupExt = ".legacy.bak"
+
+	// MetaExtensionPrefix is the prefix of the meta keys that hold the extensions
+	// of the wallet, the extension name follows the prefix
+	MetaExtensionPrefix = "ext."
+)
+
+var (
+	// ErrUnsupportedFileFormat is returned when loading a wallet file of an unknown format version
+	ErrUnsupportedFileFormat = NewError(errors.New("unsupported wallet file format version"))
+)
+
+type metaFieldType int
+
+const (
+	metaString metaFieldType = iota
+	metaBool
+	metaInt
+	metaUint
+	metaStringList
+)
+
+// metaFieldTypes records the types of the meta fields that are not strings in v3 wallet files
+var metaFieldTypes = map[string]metaFieldType{
+	MetaTimestamp:          metaInt,
+	MetaEncrypted:          metaBool,
+	MetaBip44Coin:          metaUint,
+	MetaPubKeys:            metaStringList,
+	MetaThreshold:          metaUint,
+	MetaArgon2Iterations:   metaUint,
+	MetaArgon2Memory:       metaUint,
+	MetaRestoredFromShares: metaBool,
+	MetaSharesIdentifier:   metaUint,
+	MetaReadOnly:           metaBool,
+	MetaDailySpendLimit:    metaUint,
+	MetaRequirePassword:    metaBool,
+	MetaSpent:              metaUint,
+	MetaXPubAccount:        metaBool,
+}
+
+// File keys of the v3 wallet file
+const (
+	fileKeyFormatVersion = "formatVersion"
+	fileKeyMeta          = "meta"
+	fileKeyExtensions    = "extensions"
+)
+
+// EncodeFile encodes the wallet in the v3 wallet file format
+func EncodeFile(w Wallet) ([]byte, error) {
+	data, err := w.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeFile(data)
+}
+
+// encodeFile converts the serialized wallet, which has the meta data as a map of strings,
+// to the v3 wallet file format
+func encodeFile(data []byte) ([]byte, error) {
+	var f map[string]json.RawMessage
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	var m Meta
+	if err := json.Unmarshal(f[fileKeyMeta], &m); err != nil {
+		return nil, err
+	}
+
+	fm := make(map[string]interface{}, len(m))
+	exts := make(map[string]json.RawMessage)
+	for k, v := range m {
+		// the unlock session only lasts while the service runs
+		if k == MetaUnlockedUntil {
+			continue
+		}
+
+		if strings.HasPrefix(k, MetaExtensionPrefix) {
+			exts[strings.TrimPrefix(k, MetaExtensionPrefix)] = json.RawMessage(v)
+			continue
+		}
+
+		tv, err := typedMetaValue(k, v)
+		if err != nil {
+			return nil, err
+		}
+		if tv != nil {
+			fm[k] = tv
+		}
+	}
+
+	b, err := json.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+	f[fileKeyMeta] = b
+
+	if len(exts) > 0 {
+		b, err := json.Marshal(exts)
+		if err != nil {
+			return nil, err
+		}
+		f[fileKeyExtensions] = b
+	}
+
+	f[fileKeyFormatVersion] = json.RawMessage(strconv.Itoa(FileFormatVersion))
+
+	return json.MarshalIndent(f, "", "    ")
+}
+
+// decodeFile converts the v3 wallet file data back to the serialized wallet that the
+// wallet loaders read. The data of v1/v2 wallet files is returned unchanged.
+func decodeFile(data []byte) ([]byte, error) {
+	var f map[string]json.RawMessage
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	v, ok := f[fileKeyFormatVersion]
+	if !ok {
+		return data, nil
+	}
+
+	var version int
+	if err := json.Unmarshal(v, &version); err != nil {
+		return nil, fmt.Errorf("invalid formatVersion: %v", err)
+	}
+	if version != FileFormatVersion {
+		return nil, ErrUnsupportedFileFormat
+	}
+
+	var fm map[string]json.RawMessage
+	if err := json.Unmarshal(f[fileKeyMeta], &fm); err != nil {
+		return nil, err
+	}
+
+	m := make(Meta, len(fm))
+	for k, tv := range fm {
+		v, err := stringMetaValue(k, tv)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+
+	if exts, ok := f[fileKeyExtensions]; ok {
+		var es map[string]json.RawMessage
+		if err := json.Unmarshal(exts, &es); err != nil {
+			return nil, fmt.Errorf("invalid extensions: %v", err)
+		}
+
+		for name, e := range es {
+			if err := m.SetExtension(name, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	f[fileKeyMeta] = b
+
+	delete(f, fileKeyFormatVersion)
+	delete(f, fileKeyExtensions)
+
+	return json.Marshal(f)
+}
+
+// typedMetaValue returns the value of the meta field in the v3 wallet file,
+// nil is returned for empty values.
+func typedMetaValue(k, v string) (interface{}, error) {
+	t := metaFieldTypes[k]
+	if v == "" {
+		if t == metaString {
+			return v, nil
+		}
+		return nil, nil
+	}
+
+	var tv interface{}
+	var err error
+	switch t {
+	case metaBool:
+		tv, err = strconv.ParseBool(v)
+	case metaInt:
+		tv, err = strconv.ParseInt(v, 10, 64)
+	case metaUint:
+		tv, err = strconv.ParseUint(v, 10, 64)
+	case metaStringList:
+		tv = strings.Split(v, ",")
+	default:
+		tv = v
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid meta field %q: %v", k, err)
+	}
+
+	return tv, nil
+}
+
+// stringMetaValue converts the meta field value in the v3 wallet file to string
+func stringMetaValue(k string, tv json.RawMessage) (string, error) {
+	var err error
+	var v string
+	switch metaFieldTypes[k] {
+	case metaBool:
+		var b bool
+		err = json.Unmarshal(tv, &b)
+		v = strconv.FormatBool(b)
+	case metaInt:
+		var n int64
+		err = json.Unmarshal(tv, &n)
+		v = strconv.FormatInt(n, 10)
+	case metaUint:
+		var n uint64
+		err = json.Unmarshal(tv, &n)
+		v = strconv.FormatUint(n, 10)
+	case metaStringList:
+		var ss []string
+		err = json.Unmarshal(tv, &ss)
+		v = strings.Join(ss, ",")
+	default:
+		err = json.Unmarshal(tv, &v)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("invalid meta field %q: %v", k, err)
+	}
+
+	return v, nil
+}
+
+// isLegacyFile returns true if the wallet file data is in the v1/v2 format
+func isLegacyFile(data []byte) (bool, error) {
+	var f map[string]json.RawMessage
+	if err := json.Unmarshal(data, &f); err != nil {
+		return false, err
+	}
+
+	_, ok := f[fileKeyFormatVersion]
+	return !ok, nil
+}
+
+// backupLegacyFile copies the wallet file to filename+LegacyBackupExt if it is
+// in the v1/v2 format. An existing backup is not overwritten.
+func backupLegacyFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Nothing to back up in an empty file
+	if len(data) == 0 {
+		return nil
+	}
+
+	legacy, err := isLegacyFile(data)
+	if err != nil {
+		return err
+	}
+	if !legacy {
+		return nil
+	}
+
+	bak := filename + LegacyBackupExt
+	if ok, err := file.Exists(bak); err != nil || ok {
+		return err
+	}
+
+	return ioutil.WriteFile(bak, data, 0600)
+}
+
+// MigrateFile upgrades the v1/v2 wallet file to the v3 format in place.
+// The original file is kept in filename+LegacyBackupExt.
+// Returns false if the file is already in the v3 format.
+func MigrateFile(filename string) (bool, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+
+	legacy, err := isLegacyFile(data)
+	if err != nil {
+		return false, err
+	}
+	if !legacy {
+		return false, nil
+	}
+
+	w, err := Load(filename)
+	if err != nil {
+		return false, err
+	}
+	if w == nil {
+		return false, fmt.Errorf("wallet loader for %q not found", filename)
+	}
+
+	if err := Save(w, filepath.Dir(filename)); err != nil {
+		return false, err
+	}
+
+	logger.WithField("filename", filename).Info("Migrated wallet file to the v3 format")
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package hardwarewallet
+
+import (
+	"encoding/json"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// JSONDecoder implements the the WalletDecoder interface,
+// which provides methods for encoding and decoding a hardware wallet in JSON format.
+type JSONDecoder struct{}
+
+// Encode encodes the hardware wallet to []byte, and error if any
+func (d JSONDecoder) Encode(w wallet.Wallet) ([]byte, error) {
+	return json.MarshalIndent(newReadableWallet(w.(*Wallet)), "", "    ")
+}
+
+// Decode decodes the hardware wallet from byte slice
+func (d JSONDecoder) Decode(b []byte) (wallet.Wallet, error) {
+	rw := readableWallet{}
+	if err := json.Unmarshal(b, &rw); err != nil {
+		return nil, err
+	}
+
+	return rw.toWallet()
+}
+
+type readableWallet struct {
+	wallet.Meta `json:"meta"`
+	Entries     readableHardwareEntries `json:"entries"`
+}
+
+func (rw readableWallet) toWallet() (*Wallet, error) {
+	if err := validateMeta(rw.Meta); err != nil {
+		return nil, err
+	}
+
+	xpubStr := rw.Meta.XPub()
+	if xpubStr == "" {
+		return nil, wallet.ErrMissingXPub
+	}
+
+	xPub, err := parseXPub(xpubStr)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := wallet.ResolveAddressDecoder(rw.Coin())
+	entries, err := rw.Entries.toEntries(ad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Meta:    rw.Meta.Clone(),
+		entries: entries,
+		xpub:    xPub,
+		decoder: &JSONDecoder{},
+	}, nil
+}
+
+func newReadableWallet(w *Wallet) *readableWallet {
+	return &readableWallet{
+		Meta:    w.Meta.Clone(),
+		Entries: newReadableEntries(w.entries),
+	}
+}
+
+type readableHardwareEntries []readableHardwareEntry
+
+func (es readableHardwareEntries) toEntries(ad wallet.AddressDecoder) (wallet.Entries, error) {
+	entries := make(wallet.Entries, len(es))
+	for i, e := range es {
+		addr, err := ad.DecodeBase58Address(e.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := cipher.PubKeyFromHex(e.Public)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = wallet.Entry{
+			Address:     addr,
+			Public:      p,
+			ChildNumber: e.ChildNumber,
+			EntryMeta:   e.ToEntryMeta(),
+		}
+	}
+
+	return entries, nil
+}
+
+func newReadableEntries(entries wallet.Entries) readableHardwareEntries {
+	res := make(readableHardwareEntries, len(entries))
+	for i, e := range entries {
+		res[i] = readableHardwareEntry{
+			Address:           e.Address.String(),
+			Public:            e.Public.Hex(),
+			ChildNumber:       e.ChildNumber,
+			ReadableEntryMeta: wallet.NewReadableEntryMeta(e.EntryMeta),
+		}
+	}
+
+	return res
+}
+
+type readableHardwareEntry struct {
+	Address     string `json:"address"`
+	Public      string `json:"public"`
+	ChildNumber uint32 `json:"child_number"`
+	wallet.ReadableEntryMeta
+}
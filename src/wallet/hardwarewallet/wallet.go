@@ -0,0 +1,432 @@
+package hardwarewallet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/util/logging"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletType represents the hardware wallet type
+const WalletType = "hardware"
+
+const (
+	// DeviceLedger is the device type of Ledger hardware wallets
+	DeviceLedger = "ledger"
+	// DeviceTrezor is the device type of Trezor hardware wallets
+	DeviceTrezor = "trezor"
+
+	// DefaultDerivationPath is the bip32 path of the skycoin external chain of the first bip44 account
+	DefaultDerivationPath = "m/44'/8000'/0'/0"
+)
+
+var (
+	// ErrMissingDevice is returned when creating a hardware wallet without a device type
+	ErrMissingDevice = wallet.NewError(errors.New("missing hardware device type"))
+
+	errHardwareEncryption = errors.New("hardware wallet does not support encryption")
+)
+
+var defaultWalletDecoder = &JSONDecoder{}
+var logger = logging.MustGetLogger("hardwarewallet")
+
+func init() {
+	if err := wallet.RegisterCreator(WalletType, &Creator{}); err != nil {
+		panic(err)
+	}
+
+	if err := wallet.RegisterLoader(WalletType, &Loader{}); err != nil {
+		panic(err)
+	}
+}
+
+// Wallet is backed by a hardware device (e.g. a Ledger or Trezor). No seed or secret key
+// is stored on disk, only the xpub exported by the device and its derivation path.
+// Addresses are derived from the xpub, and transactions are signed by the wallet.DeviceSigner
+// registered for the device type.
+type Wallet struct {
+	wallet.Meta
+	wallet.Seeds
+	entries wallet.Entries
+	xpub    *bip32.PublicKey
+	decoder wallet.Decoder
+}
+
+// NewWallet creates a hardware wallet with options
+func NewWallet(filename, label, device, derivationPath, xPub string, options ...wallet.Option) (*Wallet, error) {
+	if device == "" {
+		return nil, ErrMissingDevice
+	}
+
+	if xPub == "" {
+		return nil, wallet.ErrMissingXPub
+	}
+
+	if derivationPath == "" {
+		derivationPath = DefaultDerivationPath
+	}
+
+	key, err := parseXPub(xPub)
+	if err != nil {
+		return nil, wallet.NewError(err)
+	}
+
+	wlt := &Wallet{
+		Meta: wallet.Meta{
+			wallet.MetaFilename:       filename,
+			wallet.MetaLabel:          label,
+			wallet.MetaType:           WalletType,
+			wallet.MetaVersion:        wallet.Version,
+			wallet.MetaCoin:           string(wallet.CoinTypeSkycoin),
+			wallet.MetaXPub:           xPub,
+			wallet.MetaDevice:         device,
+			wallet.MetaDerivationPath: derivationPath,
+			wallet.MetaTimestamp:      strconv.FormatInt(time.Now().Unix(), 10),
+		},
+		decoder: defaultWalletDecoder,
+		xpub:    key,
+	}
+
+	advOpts := &wallet.AdvancedOptions{}
+	for _, opt := range options {
+		opt(wlt)
+		opt(advOpts)
+	}
+
+	if err := validateMeta(wlt.Meta); err != nil {
+		return nil, err
+	}
+
+	if advOpts.Encrypt {
+		return nil, wallet.NewError(errHardwareEncryption)
+	}
+
+	generateN := advOpts.GenerateN
+	if generateN > 0 {
+		if _, err := wlt.GenerateAddresses(generateN); err != nil {
+			return nil, err
+		}
+	}
+
+	scanN := advOpts.ScanN
+	if scanN > 0 {
+		if advOpts.TF == nil {
+			return nil, errors.New("missing transaction finder for scanning addresses")
+		}
+
+		if scanN > generateN {
+			scanN = scanN - generateN
+		}
+
+		if _, err := wlt.ScanAddresses(scanN, advOpts.TF); err != nil {
+			return nil, err
+		}
+	}
+
+	return wlt, nil
+}
+
+// SetDecoder sets the wallet decoder
+func (w *Wallet) SetDecoder(d wallet.Decoder) {
+	w.decoder = d
+}
+
+func validateMeta(m wallet.Meta) error {
+	if m[wallet.MetaType] != WalletType {
+		return wallet.ErrInvalidWalletType
+	}
+
+	if m.Device() == "" {
+		return ErrMissingDevice
+	}
+
+	if _, err := bip32.ParsePath(m.DerivationPath()); err != nil {
+		return wallet.NewError(fmt.Errorf("invalid derivation path: %v", err))
+	}
+
+	if s := m[wallet.MetaSeed]; s != "" {
+		return wallet.NewError(errors.New("seed should not be in hardware wallets"))
+	}
+
+	return wallet.ValidateMeta(m)
+}
+
+// Serialize encodes the hardware wallet to []byte
+func (w Wallet) Serialize() ([]byte, error) {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	return w.decoder.Encode(&w)
+}
+
+// Deserialize decodes the []byte to a hardware wallet
+func (w *Wallet) Deserialize(b []byte) error {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	toW, err := w.decoder.Decode(b)
+	if err != nil {
+		return err
+	}
+
+	toW2 := toW.(*Wallet)
+	toW2.decoder = w.decoder
+	*w = *toW2
+	return nil
+}
+
+// Lock is not supported, the secrets of the hardware wallet are kept on the device
+func (w *Wallet) Lock(_ []byte) error {
+	return wallet.NewError(errHardwareEncryption)
+}
+
+// Unlock is not supported, the secrets of the hardware wallet are kept on the device
+func (w *Wallet) Unlock(_ []byte) (wallet.Wallet, error) {
+	return nil, wallet.NewError(errHardwareEncryption)
+}
+
+// Fingerprint returns a unique ID fingerprint for this wallet, using the first
+// child address of the xpub key
+func (w *Wallet) Fingerprint() string {
+	addr := ""
+	if len(w.entries) == 0 {
+		e, err := w.newEntry(0)
+		if err != nil {
+			logger.WithError(err).Panic("Fingerprint failed to generate initial entry for empty wallet")
+		}
+		addr = e.Address.String()
+	} else {
+		addr = w.entries[0].Address.String()
+	}
+
+	return fmt.Sprintf("%s-%s", w.Type(), addr)
+}
+
+// Clone returns a copy of the wallet
+func (w Wallet) Clone() wallet.Wallet {
+	xpub := w.xpub.Clone()
+	return &Wallet{
+		Meta:    w.Meta.Clone(),
+		entries: w.entries.Clone(),
+		xpub:    &xpub,
+		decoder: w.decoder,
+	}
+}
+
+// CopyFromRef copies the src wallet with a pointer dereference
+func (w *Wallet) CopyFromRef(src wallet.Wallet) {
+	*w = *(src.(*Wallet))
+}
+
+// Accounts is not implemented for hardware wallet
+func (w *Wallet) Accounts() []wallet.Bip44Account {
+	return nil
+}
+
+// Erase is a no-op, the hardware wallet has no sensitive data
+func (w *Wallet) Erase() {}
+
+// ScanAddresses scans ahead N addresses, truncating up to the highest address with any transaction history.
+func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]cipher.Addresser, error) {
+	if scanN == 0 {
+		return nil, nil
+	}
+
+	w2 := w.Clone().(*Wallet)
+
+	nExistingAddrs := uint64(len(w2.entries))
+
+	// Generate the addresses to scan
+	addrs, err := w2.GenerateAddresses(scanN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find if these addresses had any activity
+	active, err := tf.AddressesActivity(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check activity from the last one until we find the address that has activity
+	var keepNum uint64
+	for i := len(active) - 1; i >= 0; i-- {
+		if active[i] {
+			keepNum = uint64(i + 1)
+			break
+		}
+	}
+
+	w2.entries = w2.entries[:nExistingAddrs+keepNum]
+	*w = *w2
+
+	return addrs[:keepNum], nil
+}
+
+// GenerateAddresses generates addresses from the device xpub, and appends them to the wallet's entries array
+func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Addresser, error) {
+	if num > math.MaxUint32 {
+		return nil, wallet.NewError(errors.New("HardwareWallet.GenerateAddresses num too large"))
+	}
+
+	initLen := uint32(len(w.entries))
+	if _, err := mathutil.AddUint32(initLen, uint32(num)); err != nil {
+		return nil, fmt.Errorf("generate %d more addresses failed: %v", num, err)
+	}
+
+	var addrs []cipher.Addresser
+	for i := uint32(0); i < uint32(num); i++ {
+		e, err := w.newEntry(initLen + i)
+		if err != nil {
+			return nil, err
+		}
+
+		w.entries = append(w.entries, e)
+		addrs = append(addrs, e.Address)
+	}
+	return addrs, nil
+}
+
+func (w *Wallet) newEntry(index uint32) (wallet.Entry, error) {
+	pk, err := w.xpub.NewPublicChildKey(index)
+	if err != nil {
+		return wallet.Entry{}, err
+	}
+
+	cpk, err := cipher.NewPubKey(pk.Key)
+	if err != nil {
+		return wallet.Entry{}, err
+	}
+
+	return wallet.Entry{
+		Address:     wallet.ResolveAddressDecoder(w.Coin()).AddressFromPubKey(cpk),
+		Public:      cpk,
+		ChildNumber: index,
+	}, nil
+}
+
+func parseXPub(xp string) (*bip32.PublicKey, error) {
+	xPub, err := bip32.DeserializeEncodedPublicKey(xp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xpub key: %v", err)
+	}
+
+	return xPub, nil
+}
+
+// GetAddresses returns all addresses of the wallet
+func (w *Wallet) GetAddresses(_ ...wallet.Option) ([]cipher.Addresser, error) {
+	return w.entries.GetAddresses(), nil
+}
+
+// GetEntries returns a copy of all entries held by the wallet
+func (w *Wallet) GetEntries(_ ...wallet.Option) (wallet.Entries, error) {
+	return w.entries.Clone(), nil
+}
+
+// GetEntryAt returns the entry at a given index in the entries array
+func (w *Wallet) GetEntryAt(i int, _ ...wallet.Option) (wallet.Entry, error) {
+	if i < 0 || i >= len(w.entries) {
+		return wallet.Entry{}, fmt.Errorf("entry index %d is out of range", i)
+	}
+	return w.entries[i], nil
+}
+
+// GetEntry returns the entry of given address
+func (w *Wallet) GetEntry(addr cipher.Addresser, _ ...wallet.Option) (wallet.Entry, error) {
+	e, ok := w.entries.Get(addr)
+	if !ok {
+		return wallet.Entry{}, wallet.ErrEntryNotFound
+	}
+	return e, nil
+}
+
+// GetEntryMeta returns the label, note and tags of the entry of given address
+func (w *Wallet) GetEntryMeta(addr cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	e, ok := w.entries.Get(addr)
+	if !ok {
+		return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+	}
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address
+func (w *Wallet) SetEntryMeta(addr cipher.Addresser, m wallet.EntryMeta, _ ...wallet.Option) error {
+	if !w.entries.SetMeta(addr, m) {
+		return wallet.ErrEntryNotFound
+	}
+	return nil
+}
+
+// HasEntry returns true if the wallet has an Entry with a given address
+func (w *Wallet) HasEntry(addr cipher.Addresser, _ ...wallet.Option) (bool, error) {
+	return w.entries.Has(addr), nil
+}
+
+// EntriesLen returns the number of entries in the wallet
+func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
+	return len(w.entries), nil
+}
+
+// Loader implements the wallet.Loader interface
+type Loader struct{}
+
+// Load loads the hardware wallet from byte slice
+func (l Loader) Load(data []byte) (wallet.Wallet, error) {
+	w := &Wallet{}
+	if err := w.Deserialize(data); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Creator implements the wallet.Creator interface
+type Creator struct{}
+
+// Create creates a hardware wallet
+func (c Creator) Create(filename, label, _ string, options wallet.Options) (wallet.Wallet, error) {
+	if options.Encrypt {
+		return nil, wallet.NewError(errHardwareEncryption)
+	}
+
+	return NewWallet(
+		filename,
+		label,
+		options.Device,
+		options.DerivationPath,
+		options.XPub,
+		convertOptions(options)...)
+}
+
+func convertOptions(options wallet.Options) []wallet.Option {
+	var opts []wallet.Option
+
+	if options.Coin != "" {
+		opts = append(opts, wallet.OptionCoinType(options.Coin))
+	}
+
+	if options.Decoder != nil {
+		opts = append(opts, wallet.OptionDecoder(options.Decoder))
+	}
+
+	if options.GenerateN > 0 {
+		opts = append(opts, wallet.OptionGenerateN(options.GenerateN))
+	}
+
+	if options.ScanN > 0 {
+		opts = append(opts, wallet.OptionScanN(options.ScanN))
+		opts = append(opts, wallet.OptionTransactionsFinder(options.TF))
+	}
+
+	return opts
+}
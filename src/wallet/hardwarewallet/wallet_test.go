@@ -0,0 +1,229 @@
+package hardwarewallet
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// fakeDevice is a DeviceSigner holding a bip32 master key, as a hardware device would
+type fakeDevice struct {
+	master    *bip32.PrivateKey
+	connected bool
+}
+
+func newFakeDevice(t *testing.T, seed string) *fakeDevice {
+	h := cipher.SumSHA256([]byte(seed))
+	k, err := bip32.NewMasterKey(h[:])
+	require.NoError(t, err)
+	return &fakeDevice{
+		master:    k,
+		connected: true,
+	}
+}
+
+func (d *fakeDevice) Connected() bool {
+	return d.connected
+}
+
+func (d *fakeDevice) SignTransaction(txn *coin.Transaction, inputs []wallet.DeviceInput) ([]cipher.Sig, error) {
+	sigs := make([]cipher.Sig, len(inputs))
+	for i, in := range inputs {
+		p, err := bip32.ParsePath(in.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := d.master.DeriveSubpath(p.Elements[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		sk, err := cipher.NewSecKey(k.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		sigs[i] = cipher.MustSignHash(cipher.AddSHA256(txn.InnerHash, txn.In[in.Index]), sk)
+	}
+	return sigs, nil
+}
+
+func (d *fakeDevice) xpub(t *testing.T, path string) string {
+	p, err := bip32.ParsePath(path)
+	require.NoError(t, err)
+	k, err := d.master.DeriveSubpath(p.Elements[1:])
+	require.NoError(t, err)
+	return k.PublicKey().String()
+}
+
+func TestNewWallet(t *testing.T) {
+	d := newFakeDevice(t, "device seed")
+	xpub := d.xpub(t, DefaultDerivationPath)
+
+	tt := []struct {
+		name           string
+		device         string
+		derivationPath string
+		xpub           string
+		opts           []wallet.Option
+		err            error
+	}{
+		{
+			name:   "ok default derivation path",
+			device: DeviceLedger,
+			xpub:   xpub,
+		},
+		{
+			name:           "ok custom derivation path",
+			device:         DeviceTrezor,
+			derivationPath: "m/44'/8000'/1'/0",
+			xpub:           xpub,
+			opts:           []wallet.Option{wallet.OptionGenerateN(2)},
+		},
+		{
+			name: "missing device",
+			xpub: xpub,
+			err:  ErrMissingDevice,
+		},
+		{
+			name:   "missing xpub",
+			device: DeviceLedger,
+			err:    wallet.ErrMissingXPub,
+		},
+		{
+			name:           "invalid derivation path",
+			device:         DeviceLedger,
+			derivationPath: "44'/8000'/0'/0",
+			xpub:           xpub,
+			err:            wallet.NewError(fmt.Errorf("invalid derivation path: %v", bip32.ErrPathNoMaster)),
+		},
+		{
+			name:   "encrypt not supported",
+			device: DeviceLedger,
+			xpub:   xpub,
+			opts:   []wallet.Option{wallet.OptionEncrypt(true), wallet.OptionPassword([]byte("pwd"))},
+			err:    wallet.NewError(errHardwareEncryption),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewWallet("test.wlt", "test", tc.device, tc.derivationPath, tc.xpub, tc.opts...)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, WalletType, w.Type())
+			require.Equal(t, tc.device, w.Device())
+			require.Equal(t, tc.xpub, w.XPub())
+			require.Empty(t, w.Seed())
+			require.False(t, w.IsEncrypted())
+			if tc.derivationPath == "" {
+				require.Equal(t, DefaultDerivationPath, w.DerivationPath())
+			} else {
+				require.Equal(t, tc.derivationPath, w.DerivationPath())
+			}
+		})
+	}
+}
+
+func TestWalletSerializeDeserialize(t *testing.T) {
+	d := newFakeDevice(t, "device seed")
+
+	w, err := NewWallet("test.wlt", "test", DeviceLedger, "", d.xpub(t, DefaultDerivationPath), wallet.OptionGenerateN(3))
+	require.NoError(t, err)
+
+	b, err := w.Serialize()
+	require.NoError(t, err)
+
+	w2, err := Loader{}.Load(b)
+	require.NoError(t, err)
+	require.Equal(t, w.Meta, w2.(*Wallet).Meta)
+	require.Equal(t, w.Fingerprint(), w2.Fingerprint())
+
+	es, err := w.GetEntries()
+	require.NoError(t, err)
+	es2, err := w2.GetEntries()
+	require.NoError(t, err)
+	require.Equal(t, es, es2)
+}
+
+func makeTransaction(t *testing.T, w *Wallet) (*coin.Transaction, []coin.UxOut) {
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+
+	var uxOuts []coin.UxOut
+	txn := &coin.Transaction{}
+	for i, a := range addrs {
+		ux := coin.UxOut{
+			Head: coin.UxHead{BkSeq: uint64(i)},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        a.(cipher.Address),
+				Coins:          1e6,
+				Hours:          100,
+			},
+		}
+		uxOuts = append(uxOuts, ux)
+		require.NoError(t, txn.PushInput(ux.Hash()))
+	}
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), uint64(len(addrs))*1e6, 50))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+
+	return txn, uxOuts
+}
+
+func TestSignTransaction(t *testing.T) {
+	d := newFakeDevice(t, "device seed")
+	xpub := d.xpub(t, DefaultDerivationPath)
+
+	w, err := NewWallet("test.wlt", "test", DeviceLedger, "", xpub, wallet.OptionGenerateN(3))
+	require.NoError(t, err)
+
+	txn, uxOuts := makeTransaction(t, w)
+
+	// No signer registered for the device
+	_, err = wallet.SignTransaction(w, txn, nil, uxOuts)
+	require.Equal(t, wallet.ErrDeviceRequired, err)
+
+	require.NoError(t, wallet.RegisterDeviceSigner(DeviceLedger, d))
+	defer wallet.UnregisterDeviceSigner(DeviceLedger)
+
+	require.Equal(t, errors.New("device signer for ledger already exists"), wallet.RegisterDeviceSigner(DeviceLedger, d))
+
+	// Device is disconnected
+	d.connected = false
+	_, err = wallet.SignTransaction(w, txn, nil, uxOuts)
+	require.Equal(t, wallet.ErrDeviceRequired, err)
+	d.connected = true
+
+	// Sign the first input only
+	signedTxn, err := wallet.SignTransaction(w, txn, []int{0}, uxOuts)
+	require.NoError(t, err)
+	require.False(t, signedTxn.IsFullySigned())
+	require.False(t, signedTxn.Sigs[0].Null())
+
+	// Sign the remaining inputs
+	signedTxn, err = wallet.SignTransaction(w, signedTxn, nil, uxOuts)
+	require.NoError(t, err)
+	require.True(t, signedTxn.IsFullySigned())
+	require.NoError(t, signedTxn.Verify())
+
+	// A device holding a different seed produces invalid signatures
+	d2 := newFakeDevice(t, "another device seed")
+	wallet.UnregisterDeviceSigner(DeviceLedger)
+	require.NoError(t, wallet.RegisterDeviceSigner(DeviceLedger, d2))
+	_, err = wallet.SignTransaction(w, txn, nil, uxOuts)
+	require.Equal(t, wallet.ErrDeviceSignatureInvalid, err)
+}
@@ -0,0 +1,150 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// SecKeyFormat is the format of a secret key string accepted by ImportPrivateKeys
+type SecKeyFormat string
+
+const (
+	// SecKeyFormatHex hex encoded secret key
+	SecKeyFormatHex SecKeyFormat = "hex"
+	// SecKeyFormatWIF bitcoin wallet import format, for compressed or uncompressed public keys
+	SecKeyFormatWIF SecKeyFormat = "wif"
+	// SecKeyFormatMini mini private key format, used by Casascius coins and some paper wallets
+	SecKeyFormatMini SecKeyFormat = "mini"
+	// SecKeyFormatBIP38 BIP38 passphrase encrypted key, used by paper wallets
+	SecKeyFormatBIP38 SecKeyFormat = "bip38"
+)
+
+var (
+	// ErrNoPrivateKeys is returned when importing an empty list of private keys
+	ErrNoPrivateKeys = NewError(errors.New("no private keys to import"))
+	// ErrUnknownSecKeyFormat is returned when a private key is not in a supported format
+	ErrUnknownSecKeyFormat = NewError(errors.New("unknown private key format, expected hex, WIF, mini or BIP38 private key"))
+	// ErrBIP38PassphraseRequired is returned when parsing a BIP38 encrypted key without a passphrase
+	ErrBIP38PassphraseRequired = NewError(errors.New("private key is BIP38 encrypted, a passphrase is required"))
+)
+
+// ParseSecKey parses a secret key in the hex, bitcoin WIF or mini private key format,
+// returning the format that was detected. BIP38 encrypted keys are detected but
+// ErrBIP38PassphraseRequired is returned, use ParseSecKeyWithPassphrase to decrypt them.
+func ParseSecKey(s string) (cipher.SecKey, SecKeyFormat, error) {
+	return ParseSecKeyWithPassphrase(s, nil)
+}
+
+// ParseSecKeyWithPassphrase parses a secret key like ParseSecKey, decrypting BIP38 encrypted keys with passphrase
+func ParseSecKeyWithPassphrase(s string, passphrase []byte) (cipher.SecKey, SecKeyFormat, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case cipher.IsBIP38Key(s):
+		if len(passphrase) == 0 {
+			return cipher.SecKey{}, SecKeyFormatBIP38, ErrBIP38PassphraseRequired
+		}
+		sk, err := cipher.BIP38Decrypt(s, passphrase)
+		return sk, SecKeyFormatBIP38, err
+	case len(s) == 64:
+		sk, err := cipher.SecKeyFromHex(s)
+		return sk, SecKeyFormatHex, err
+	case cipher.IsMiniPrivateKey(s):
+		sk, err := cipher.SecKeyFromMiniPrivateKey(s)
+		return sk, SecKeyFormatMini, err
+	case len(s) == 51 || len(s) == 52:
+		sk, _, err := cipher.SecKeyFromBitcoinWIF(s)
+		return sk, SecKeyFormatWIF, err
+	default:
+		return cipher.SecKey{}, "", ErrUnknownSecKeyFormat
+	}
+}
+
+// ImportPrivateKeys creates a collection wallet with the private keys, in the hex,
+// bitcoin WIF or mini private key format, so that keys can be moved from other wallet software.
+// The addresses of the entries are of the options.Coin coin type.
+// The wallet is encrypted if options.Encrypt is set.
+func ImportPrivateKeys(filename, label string, keys []string, options Options) (Wallet, error) {
+	return importPrivateKeys(filename, label, keys, nil, options)
+}
+
+// ImportBIP38Keys creates a collection wallet with BIP38 encrypted private keys, such as the keys
+// of paper wallets, which are decrypted with passphrase. Keys in the formats accepted by
+// ImportPrivateKeys can be mixed with the encrypted keys. Refer to ImportPrivateKeys for the options.
+func ImportBIP38Keys(filename, label string, keys []string, passphrase []byte, options Options) (Wallet, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrBIP38PassphraseRequired
+	}
+	return importPrivateKeys(filename, label, keys, passphrase, options)
+}
+
+func importPrivateKeys(filename, label string, keys []string, passphrase []byte, options Options) (Wallet, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoPrivateKeys
+	}
+
+	secKeys := make([]cipher.SecKey, len(keys))
+	for i, k := range keys {
+		secKey, _, err := ParseSecKeyWithPassphrase(k, passphrase)
+		if err != nil {
+			return nil, NewError(fmt.Errorf("private key %d: %v", i, err))
+		}
+		secKeys[i] = secKey
+	}
+
+	return newCollectionFromSecKeys(filename, label, secKeys, "private key", options)
+}
+
+// newCollectionFromSecKeys creates a collection wallet with an entry for each secret key.
+// name describes the source of the keys in errors.
+func newCollectionFromSecKeys(filename, label string, secKeys []cipher.SecKey, name string, options Options) (Wallet, error) {
+	options.Type = WalletTypeCollection
+	if options.Coin == "" {
+		options.Coin = CoinTypeSkycoin
+	}
+
+	// The entries are added before encrypting the wallet
+	encrypt := options.Encrypt
+	walletPassword := options.Password
+	options.Encrypt = false
+	options.Password = nil
+
+	w, err := NewWallet(filename, label, "", options)
+	if err != nil {
+		return nil, err
+	}
+
+	adder, ok := w.(interface {
+		AddEntry(Entry) error
+	})
+	if !ok {
+		return nil, fmt.Errorf("%s wallet does not support adding entries", w.Type())
+	}
+
+	newAddress := AddressConstructor(Meta{MetaCoin: string(w.Coin())})
+	for i, secKey := range secKeys {
+		pk, err := cipher.PubKeyFromSecKey(secKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := adder.AddEntry(Entry{
+			Address: newAddress(pk),
+			Public:  pk,
+			Secret:  NewSecretKey(secKey),
+		}); err != nil {
+			return nil, fmt.Errorf("%s %d: %v", name, i, err)
+		}
+	}
+
+	if encrypt {
+		if err := w.Lock(walletPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestParseSecKey(t *testing.T) {
+	wifKey := cipher.MustSecKeyFromHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	miniKey := cipher.MustSecKeyFromHex("4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab")
+
+	cases := []struct {
+		name   string
+		key    string
+		seckey cipher.SecKey
+		format SecKeyFormat
+		err    error
+	}{
+		{
+			name:   "hex",
+			key:    wifKey.Hex(),
+			seckey: wifKey,
+			format: SecKeyFormatHex,
+		},
+		{
+			name:   "uncompressed wif",
+			key:    "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+			seckey: wifKey,
+			format: SecKeyFormatWIF,
+		},
+		{
+			name:   "compressed wif",
+			key:    " KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617\n",
+			seckey: wifKey,
+			format: SecKeyFormatWIF,
+		},
+		{
+			name:   "mini",
+			key:    "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy",
+			seckey: miniKey,
+			format: SecKeyFormatMini,
+		},
+		{
+			name:   "mini typo",
+			key:    "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRz",
+			format: SecKeyFormatMini,
+			err:    cipher.ErrMiniPrivateKeyInvalidChecksum,
+		},
+		{
+			name:   "bip38 without passphrase",
+			key:    "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo",
+			format: SecKeyFormatBIP38,
+			err:    ErrBIP38PassphraseRequired,
+		},
+		{
+			name: "unknown",
+			key:  "abcdef",
+			err:  ErrUnknownSecKeyFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sk, format, err := ParseSecKey(tc.key)
+			require.Equal(t, tc.format, format)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.seckey, sk)
+		})
+	}
+}
+
+func TestParseSecKeyWithPassphrase(t *testing.T) {
+	bip38Key := "6PYNKZ1EAgYgmQfmNVamxyXVWHzK5s6DGhwP4J5o44cvXdoY7sRzhtpUeo"
+
+	sk, format, err := ParseSecKeyWithPassphrase(bip38Key, []byte("TestingOneTwoThree"))
+	require.NoError(t, err)
+	require.Equal(t, SecKeyFormatBIP38, format)
+	require.Equal(t, "cbf4b9f70470856bb4f40f80b87edb90865997ffee6df315ab166d713af433a5", sk.Hex())
+
+	_, format, err = ParseSecKeyWithPassphrase(bip38Key, []byte("wrong"))
+	require.Equal(t, SecKeyFormatBIP38, format)
+	require.Equal(t, cipher.ErrBIP38WrongPassphrase, err)
+
+	// The passphrase is ignored for unencrypted keys
+	sk, format, err = ParseSecKeyWithPassphrase("S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy", []byte("TestingOneTwoThree"))
+	require.NoError(t, err)
+	require.Equal(t, SecKeyFormatMini, format)
+	require.Equal(t, "4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab", sk.Hex())
+}
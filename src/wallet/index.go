@@ -0,0 +1,275 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/skycoin/skycoin/src/util/file"
+)
+
+const (
+	// IndexFilename is the name of the wallet index file in the wallet directory
+	IndexFilename = ".wallets.index"
+	// indexVersion is the version of the wallet index file format
+	indexVersion = 1
+)
+
+// IndexEntry records the summary of a wallet file in the wallet index,
+// so that the wallets can be listed without loading the wallet files
+type IndexEntry struct {
+	Filename  string   `json:"filename"`
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	Coin      CoinType `json:"coin"`
+	Encrypted bool     `json:"encrypted"`
+	// AddressCount is the number of addresses, the change addresses excluded for bip44 wallets
+	AddressCount int    `json:"address_count"`
+	Fingerprint  string `json:"fingerprint"`
+	// Size and ModTime are the size and the modification time in nanoseconds of the wallet file
+	// when it was indexed, the file is loaded again if they change
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mod_time"`
+}
+
+type indexFile struct {
+	Version int          `json:"version"`
+	Wallets []IndexEntry `json:"wallets"`
+}
+
+// newIndexEntry creates the index entry of the wallet, fi is the file info of the wallet file
+func newIndexEntry(w Wallet, fi os.FileInfo) (IndexEntry, error) {
+	n, err := addressCount(w)
+	if err != nil {
+		return IndexEntry{}, err
+	}
+
+	return IndexEntry{
+		Filename:     w.Filename(),
+		Label:        w.Label(),
+		Type:         w.Type(),
+		Coin:         w.Coin(),
+		Encrypted:    w.IsEncrypted(),
+		AddressCount: n,
+		Fingerprint:  w.Fingerprint(),
+		Size:         fi.Size(),
+		ModTime:      fi.ModTime().UnixNano(),
+	}, nil
+}
+
+// isCurrent returns whether the wallet file is unchanged since it was indexed
+func (e IndexEntry) isCurrent(fi os.FileInfo) bool {
+	return e.Size == fi.Size() && e.ModTime == fi.ModTime().UnixNano()
+}
+
+func addressCount(w Wallet) (int, error) {
+	if w.Type() != WalletTypeBip44 {
+		return w.EntriesLen()
+	}
+
+	var n int
+	for _, a := range w.Accounts() {
+		l, err := w.EntriesLen(OptionAccount(a.Index), OptionExternal())
+		if err != nil {
+			return 0, err
+		}
+		n += l
+	}
+	return n, nil
+}
+
+// loadIndex loads the wallet index of the wallet directory, returns an empty index if there is no index file
+func loadIndex(dir string) (map[string]IndexEntry, error) {
+	index := make(map[string]IndexEntry)
+
+	var f indexFile
+	if err := file.LoadJSON(filepath.Join(dir, IndexFilename), &f); err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, err
+	}
+
+	if f.Version != indexVersion {
+		return index, nil
+	}
+
+	for _, e := range f.Wallets {
+		index[e.Filename] = e
+	}
+	return index, nil
+}
+
+// walletStore holds the wallets of the service and maintains the wallet index.
+// The wallets that are unchanged since they were indexed are loaded from
+// the wallet directory when they are first used.
+type walletStore struct {
+	// the mutex is held when loading the wallets, so that the service
+	// can load wallets while holding its read lock
+	sync.Mutex
+	dir string
+	// wallets are the loaded wallets
+	wallets Wallets
+	// index are the entries of all the wallets of the service
+	index map[string]IndexEntry
+}
+
+func newWalletStore(dir string) *walletStore {
+	return &walletStore{
+		dir:     dir,
+		wallets: make(Wallets),
+		index:   make(map[string]IndexEntry),
+	}
+}
+
+// get returns the wallet of the given id, loading it if needed,
+// returns nil if the wallet does not exist
+func (s *walletStore) get(id string) (Wallet, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if w, ok := s.wallets[id]; ok {
+		return w, nil
+	}
+
+	if _, ok := s.index[id]; !ok {
+		return nil, nil
+	}
+
+	w, err := Load(filepath.Join(s.dir, id))
+	if err != nil {
+		logger.WithError(err).WithField("filename", id).Error("walletStore.get: load wallet failed")
+		return nil, err
+	}
+
+	logger.WithField("filename", id).Debug("walletStore.get: loaded indexed wallet")
+	s.wallets[id] = w
+	return w, nil
+}
+
+// has returns whether the wallet of the given id exists
+func (s *walletStore) has(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+	_, ok := s.index[id]
+	return ok
+}
+
+// entries returns the index entries of all wallets, sorted by filename
+func (s *walletStore) entries() []IndexEntry {
+	s.Lock()
+	defer s.Unlock()
+
+	es := make([]IndexEntry, 0, len(s.index))
+	for _, e := range s.index {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool {
+		return es[i].Filename < es[j].Filename
+	})
+	return es
+}
+
+// ids returns the ids of all wallets, sorted
+func (s *walletStore) ids() []string {
+	es := s.entries()
+	ids := make([]string, len(es))
+	for i, e := range es {
+		ids[i] = e.Filename
+	}
+	return ids
+}
+
+// all returns all wallets, loading the wallets that are not loaded yet
+func (s *walletStore) all() (Wallets, error) {
+	wlts := make(Wallets)
+	for _, id := range s.ids() {
+		w, err := s.get(id)
+		if err != nil {
+			return nil, err
+		}
+		wlts[id] = w
+	}
+	return wlts, nil
+}
+
+// add adds a new wallet, the wallet is indexed when it is saved with set
+func (s *walletStore) add(w Wallet) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, dup := s.index[w.Filename()]; dup {
+		return ErrWalletNameConflict
+	}
+
+	s.wallets[w.Filename()] = w
+	s.index[w.Filename()] = IndexEntry{Filename: w.Filename()}
+	return nil
+}
+
+// set sets a clone of the saved wallet and updates the wallet index
+func (s *walletStore) set(w Wallet) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.wallets[w.Filename()] = w.Clone()
+	s.setEntry(w)
+	s.saveIndex()
+}
+
+// setEntry indexes the saved wallet, the wallet is not indexed if it can not be,
+// so that it's loaded from its file on the next start
+func (s *walletStore) setEntry(w Wallet) {
+	fields := logrus.Fields{
+		"filename": w.Filename(),
+	}
+
+	fi, err := os.Stat(filepath.Join(s.dir, w.Filename()))
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Warning("walletStore: stat wallet file failed")
+		s.index[w.Filename()] = IndexEntry{Filename: w.Filename()}
+		return
+	}
+
+	e, err := newIndexEntry(w, fi)
+	if err != nil {
+		logger.WithError(err).WithFields(fields).Warning("walletStore: index wallet failed")
+		s.index[w.Filename()] = IndexEntry{Filename: w.Filename()}
+		return
+	}
+
+	s.index[w.Filename()] = e
+}
+
+// remove removes the wallet of the given id
+func (s *walletStore) remove(id string) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.wallets, id)
+	delete(s.index, id)
+	s.saveIndex()
+}
+
+// saveIndex saves the wallet index to the wallet directory. The index is only a cache
+// of the wallet files, so a failure is logged and the wallets are loaded from their
+// files on the next start.
+func (s *walletStore) saveIndex() {
+	f := indexFile{
+		Version: indexVersion,
+		Wallets: make([]IndexEntry, 0, len(s.index)),
+	}
+	for _, e := range s.index {
+		f.Wallets = append(f.Wallets, e)
+	}
+	sort.Slice(f.Wallets, func(i, j int) bool {
+		return f.Wallets[i].Filename < f.Wallets[j].Filename
+	})
+
+	if err := file.SaveJSON(filepath.Join(s.dir, IndexFilename), f, 0600); err != nil {
+		logger.WithError(err).WithField("dir", s.dir).Warning("walletStore: save wallet index failed")
+	}
+}
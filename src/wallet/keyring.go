@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/signer"
+	"github.com/skycoin/skycoin/src/coin"
+)
+
+var (
+	// ErrKeyringRequired is returned when signing with a collection-watch wallet whose signer
+	// keyring is not registered
+	ErrKeyringRequired = NewError(errors.New("wallet signer keyring is not registered"))
+)
+
+var keyrings keyringRegistry
+
+// RegisterKeyring registers the keyring of a signer name. Collection-watch wallets created with
+// the signer name sign transactions with the keys of the keyring, e.g. keys held in a PKCS#11 HSM,
+// see signer.NewPKCS11Signer. It is called by the programs that embed the wallet package, the node
+// doesn't register keyrings and the signer option is not exposed by the API.
+func RegisterKeyring(name string, k *signer.Keyring) error {
+	return keyrings.add(name, k)
+}
+
+// UnregisterKeyring removes the keyring of a signer name
+func UnregisterKeyring(name string) {
+	keyrings.remove(name)
+}
+
+// hasKeyringSigner returns whether the keys of w are in the keyring of its signer
+func hasKeyringSigner(w Wallet) bool {
+	return w.Type() == WalletTypeCollectionWatch && w.Signer() != ""
+}
+
+// checkKeyring returns ErrKeyringRequired if w signs with a keyring that is not registered
+func checkKeyring(w Wallet) error {
+	if !hasKeyringSigner(w) {
+		return nil
+	}
+
+	if _, ok := keyrings.get(w.Signer()); !ok {
+		return ErrKeyringRequired
+	}
+	return nil
+}
+
+// signInputsWithKeyring signs the inputs of txn listed in addrsMap with the keyring of the signer of w
+func signInputsWithKeyring(w Wallet, txn *coin.Transaction, addrsMap map[cipher.Address][]int) error {
+	k, ok := keyrings.get(w.Signer())
+	if !ok {
+		return ErrKeyringRequired
+	}
+
+	for addr := range addrsMap {
+		ok, err := w.HasEntry(addr)
+		if err != nil {
+			return err
+		}
+		if _, hasKey := k.Get(addr); !ok || !hasKey {
+			return NewError(errors.New("Wallet cannot sign all requested inputs"))
+		}
+	}
+
+	for addr, x := range addrsMap {
+		for _, i := range x {
+			if !txn.Sigs[i].Null() {
+				return NewError(fmt.Errorf("Transaction is already signed at index %d", i))
+			}
+
+			sig, err := k.SignHash(addr, cipher.AddSHA256(txn.InnerHash, txn.In[i]))
+			if err != nil {
+				return err
+			}
+
+			txn.Sigs[i] = sig
+		}
+	}
+
+	return nil
+}
+
+type keyringRegistry struct {
+	l  sync.Mutex
+	ks map[string]*signer.Keyring
+}
+
+func (r *keyringRegistry) add(name string, k *signer.Keyring) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+	if r.ks == nil {
+		r.ks = map[string]*signer.Keyring{}
+	}
+
+	if _, ok := r.ks[name]; ok {
+		return fmt.Errorf("keyring %s already exists", name)
+	}
+
+	r.ks[name] = k
+	return nil
+}
+
+func (r *keyringRegistry) remove(name string) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	delete(r.ks, name)
+}
+
+func (r *keyringRegistry) get(name string) (*signer.Keyring, bool) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	k, ok := r.ks[name]
+	return k, ok
+}
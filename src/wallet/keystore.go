@@ -0,0 +1,368 @@
+package wallet
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/pbkdf2"
+	"github.com/skycoin/skycoin/src/cipher/scrypt"
+)
+
+// Web3 Secret Storage (keystore) v3 constants
+const (
+	// KeystoreVersion is the version of the Web3 keystore format
+	KeystoreVersion = 3
+	// KeystoreKDFScrypt is the scrypt key derivation function
+	KeystoreKDFScrypt = "scrypt"
+	// KeystoreKDFPBKDF2 is the pbkdf2 key derivation function, with hmac-sha256
+	KeystoreKDFPBKDF2 = "pbkdf2"
+
+	keystoreCipher = "aes-128-ctr"
+	keystorePRF    = "hmac-sha256"
+	keystoreDKLen  = 32
+
+	// Maximum key derivation parameters of the imported keystores, so that a crafted
+	// keystore can't use unbounded memory or CPU time when it is decrypted
+	keystoreMaxScryptN      = 1 << 20
+	keystoreMaxScryptRP     = 64      // maximum scrypt r*p
+	keystoreMaxScryptMemory = 1 << 30 // maximum scrypt memory in bytes, 128*n*r
+	keystoreMaxPBKDF2Iters  = 10000000
+)
+
+var (
+	// ErrKeystoreMACMismatch is returned when the keystore password is wrong or the keystore is corrupted
+	ErrKeystoreMACMismatch = NewError(errors.New("keystore MAC mismatch, invalid password"))
+	// ErrKeystoreAddressMismatch is returned when the address of the keystore does not match its key
+	ErrKeystoreAddressMismatch = NewError(errors.New("keystore address does not match the key"))
+	// ErrNoKeystores is returned when importing an empty list of keystores
+	ErrNoKeystores = NewError(errors.New("no keystores to import"))
+)
+
+// KeystoreParams are the key derivation parameters of the exported keystores
+type KeystoreParams struct {
+	KDF string
+	// ScryptN, ScryptR and ScryptP are the scrypt cost parameters
+	ScryptN int
+	ScryptR int
+	ScryptP int
+	// PBKDF2Iterations is the pbkdf2 iteration count
+	PBKDF2Iterations int
+}
+
+// StandardKeystoreParams are the scrypt parameters used by geth for standard keystores
+var StandardKeystoreParams = KeystoreParams{
+	KDF:     KeystoreKDFScrypt,
+	ScryptN: 1 << 18,
+	ScryptR: 8,
+	ScryptP: 1,
+}
+
+// LightKeystoreParams are the scrypt parameters used by geth for light keystores,
+// which take less time and memory to decrypt
+var LightKeystoreParams = KeystoreParams{
+	KDF:     KeystoreKDFScrypt,
+	ScryptN: 1 << 12,
+	ScryptR: 8,
+	ScryptP: 6,
+}
+
+type keystoreJSON struct {
+	Address string             `json:"address"`
+	Crypto  keystoreCryptoJSON `json:"crypto"`
+	ID      string             `json:"id"`
+	Version int                `json:"version"`
+}
+
+type keystoreCryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV string `json:"iv"`
+}
+
+// ExportKeystore exports the entries of the wallet as Web3 keystore v3 JSON, one keystore per entry.
+// The keystores are encrypted with password, the wallet must be decrypted.
+func ExportKeystore(w Wallet, password []byte, params KeystoreParams) ([][]byte, error) {
+	if w.IsEncrypted() {
+		return nil, ErrWalletEncrypted
+	}
+
+	entries, err := w.GetEntries()
+	if err != nil {
+		return nil, err
+	}
+	defer entries.Erase()
+
+	keystores := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		if e.Secret.Null() {
+			return nil, NewError(fmt.Errorf("entry %s has no secret key", e.Address))
+		}
+
+		ks, err := EncryptKeystore(e.Secret.SecKey(), password, params)
+		if err != nil {
+			return nil, err
+		}
+		keystores = append(keystores, ks)
+	}
+
+	return keystores, nil
+}
+
+// EncryptKeystore encrypts the secret key as Web3 keystore v3 JSON. The address of the keystore
+// is the ethereum address of the key, as expected by other keystore tooling.
+func EncryptKeystore(secKey cipher.SecKey, password []byte, params KeystoreParams) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	addr, err := cipher.EthereumAddressFromSecKey(secKey)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := cipher.RandByte(32)
+	kdfParams, derivedKey, err := deriveKeystoreKey(params, password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := cipher.RandByte(aes.BlockSize)
+	cipherText, err := aesCTRXOR(derivedKey[:16], secKey[:], iv)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := keystoreJSON{
+		Address: hex.EncodeToString(addr[:]),
+		Crypto: keystoreCryptoJSON{
+			Cipher:     keystoreCipher,
+			CipherText: hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParams{
+				IV: hex.EncodeToString(iv),
+			},
+			KDF:       params.KDF,
+			KDFParams: kdfParams,
+			MAC:       hex.EncodeToString(keystoreMAC(derivedKey, cipherText)),
+		},
+		ID:      newUUID(),
+		Version: KeystoreVersion,
+	}
+
+	return json.Marshal(ks)
+}
+
+// DecryptKeystore decrypts the secret key of the Web3 keystore v3 JSON
+func DecryptKeystore(data, password []byte) (cipher.SecKey, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return cipher.SecKey{}, NewError(fmt.Errorf("invalid keystore: %v", err))
+	}
+
+	if ks.Version != KeystoreVersion {
+		return cipher.SecKey{}, NewError(fmt.Errorf("unsupported keystore version %d", ks.Version))
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return cipher.SecKey{}, NewError(fmt.Errorf("unsupported keystore cipher %q", ks.Crypto.Cipher))
+	}
+
+	params, salt, err := parseKeystoreKDFParams(ks.Crypto.KDF, ks.Crypto.KDFParams)
+	if err != nil {
+		return cipher.SecKey{}, err
+	}
+
+	_, derivedKey, err := deriveKeystoreKey(params, password, salt)
+	if err != nil {
+		return cipher.SecKey{}, err
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return cipher.SecKey{}, NewError(fmt.Errorf("invalid keystore ciphertext: %v", err))
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return cipher.SecKey{}, NewError(fmt.Errorf("invalid keystore mac: %v", err))
+	}
+	if subtle.ConstantTimeCompare(mac, keystoreMAC(derivedKey, cipherText)) != 1 {
+		return cipher.SecKey{}, ErrKeystoreMACMismatch
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return cipher.SecKey{}, NewError(errors.New("invalid keystore iv"))
+	}
+
+	b, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return cipher.SecKey{}, err
+	}
+
+	secKey, err := cipher.NewSecKey(b)
+	if err != nil {
+		return cipher.SecKey{}, NewError(fmt.Errorf("invalid keystore secret key: %v", err))
+	}
+
+	// The address is optional in some keystores
+	if ks.Address != "" {
+		addr, err := cipher.EthereumAddressFromSecKey(secKey)
+		if err != nil {
+			return cipher.SecKey{}, err
+		}
+		if !strings.EqualFold(strings.TrimPrefix(ks.Address, "0x"), hex.EncodeToString(addr[:])) {
+			return cipher.SecKey{}, ErrKeystoreAddressMismatch
+		}
+	}
+
+	return secKey, nil
+}
+
+// ImportKeystore creates a collection wallet with the keys of the Web3 keystore v3 JSON files,
+// which are decrypted with password. The addresses of the entries are of the options.Coin coin type.
+// The wallet is encrypted if options.Encrypt is set.
+func ImportKeystore(filename, label string, keystores [][]byte, password []byte, options Options) (Wallet, error) {
+	if len(keystores) == 0 {
+		return nil, ErrNoKeystores
+	}
+
+	secKeys := make([]cipher.SecKey, len(keystores))
+	for i, ks := range keystores {
+		secKey, err := DecryptKeystore(ks, password)
+		if err != nil {
+			return nil, fmt.Errorf("keystore %d: %v", i, err)
+		}
+		secKeys[i] = secKey
+	}
+
+	return newCollectionFromSecKeys(filename, label, secKeys, "keystore", options)
+}
+
+// deriveKeystoreKey derives the key from password, returns the kdfparams of the keystore and the derived key
+func deriveKeystoreKey(params KeystoreParams, password, salt []byte) (map[string]interface{}, []byte, error) {
+	switch params.KDF {
+	case KeystoreKDFScrypt:
+		key, err := scrypt.Key(password, salt, params.ScryptN, params.ScryptR, params.ScryptP, keystoreDKLen)
+		if err != nil {
+			return nil, nil, NewError(fmt.Errorf("invalid scrypt params: %v", err))
+		}
+		return map[string]interface{}{
+			"dklen": keystoreDKLen,
+			"n":     params.ScryptN,
+			"r":     params.ScryptR,
+			"p":     params.ScryptP,
+			"salt":  hex.EncodeToString(salt),
+		}, key, nil
+	case KeystoreKDFPBKDF2:
+		if params.PBKDF2Iterations <= 0 {
+			return nil, nil, NewError(errors.New("invalid pbkdf2 params: iteration count must be positive"))
+		}
+		key := pbkdf2.Key(password, salt, params.PBKDF2Iterations, keystoreDKLen, sha256.New)
+		return map[string]interface{}{
+			"dklen": keystoreDKLen,
+			"c":     params.PBKDF2Iterations,
+			"prf":   keystorePRF,
+			"salt":  hex.EncodeToString(salt),
+		}, key, nil
+	default:
+		return nil, nil, NewError(fmt.Errorf("unsupported keystore kdf %q", params.KDF))
+	}
+}
+
+// parseKeystoreKDFParams parses the kdfparams of a keystore
+func parseKeystoreKDFParams(kdf string, kdfParams map[string]interface{}) (KeystoreParams, []byte, error) {
+	getInt := func(k string) (int, error) {
+		v, ok := kdfParams[k].(float64)
+		if !ok || v <= 0 || v != float64(int(v)) {
+			return 0, NewError(fmt.Errorf("invalid keystore kdfparams %q", k))
+		}
+		return int(v), nil
+	}
+
+	salt, err := hex.DecodeString(fmt.Sprint(kdfParams["salt"]))
+	if err != nil || len(salt) == 0 {
+		return KeystoreParams{}, nil, NewError(errors.New("invalid keystore kdfparams salt"))
+	}
+
+	dkLen, err := getInt("dklen")
+	if err != nil {
+		return KeystoreParams{}, nil, err
+	}
+	if dkLen != keystoreDKLen {
+		return KeystoreParams{}, nil, NewError(fmt.Errorf("unsupported keystore dklen %d", dkLen))
+	}
+
+	params := KeystoreParams{KDF: kdf}
+	switch kdf {
+	case KeystoreKDFScrypt:
+		if params.ScryptN, err = getInt("n"); err != nil {
+			return KeystoreParams{}, nil, err
+		}
+		if params.ScryptR, err = getInt("r"); err != nil {
+			return KeystoreParams{}, nil, err
+		}
+		if params.ScryptP, err = getInt("p"); err != nil {
+			return KeystoreParams{}, nil, err
+		}
+		if params.ScryptN > keystoreMaxScryptN {
+			return KeystoreParams{}, nil, NewError(fmt.Errorf("keystore scrypt n must be <= %d", keystoreMaxScryptN))
+		}
+		if params.ScryptP > keystoreMaxScryptRP/params.ScryptR {
+			return KeystoreParams{}, nil, NewError(fmt.Errorf("keystore scrypt r*p must be <= %d", keystoreMaxScryptRP))
+		}
+		if params.ScryptR > keystoreMaxScryptMemory/(128*params.ScryptN) {
+			return KeystoreParams{}, nil, NewError(fmt.Errorf("keystore scrypt memory 128*n*r must be <= %d bytes", keystoreMaxScryptMemory))
+		}
+	case KeystoreKDFPBKDF2:
+		if prf := kdfParams["prf"]; prf != keystorePRF {
+			return KeystoreParams{}, nil, NewError(fmt.Errorf("unsupported keystore pbkdf2 prf %v", prf))
+		}
+		if params.PBKDF2Iterations, err = getInt("c"); err != nil {
+			return KeystoreParams{}, nil, err
+		}
+		if params.PBKDF2Iterations > keystoreMaxPBKDF2Iters {
+			return KeystoreParams{}, nil, NewError(fmt.Errorf("keystore pbkdf2 c must be <= %d", keystoreMaxPBKDF2Iters))
+		}
+	default:
+		return KeystoreParams{}, nil, NewError(fmt.Errorf("unsupported keystore kdf %q", kdf))
+	}
+
+	return params, salt, nil
+}
+
+// keystoreMAC returns keccak256(derivedKey[16:32] + cipherText)
+func keystoreMAC(derivedKey, cipherText []byte) []byte {
+	return cipher.Keccak256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+}
+
+func aesCTRXOR(key, in, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	gocipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	b := cipher.RandByte(16)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return strings.Join([]string{h[:8], h[8:12], h[12:16], h[16:20], h[20:]}, "-")
+}
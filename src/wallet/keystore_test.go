@@ -0,0 +1,212 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// testKeystoreParams are cheap scrypt parameters for tests
+var testKeystoreParams = KeystoreParams{
+	KDF:     KeystoreKDFScrypt,
+	ScryptN: 1 << 4,
+	ScryptR: 8,
+	ScryptP: 1,
+}
+
+func TestDecryptKeystoreVector(t *testing.T) {
+	// PBKDF2 test vector of the Web3 Secret Storage Definition
+	ks := `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {
+				"c": 262144,
+				"dklen": 32,
+				"prf": "hmac-sha256",
+				"salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	sk, err := DecryptKeystore([]byte(ks), []byte("testpassword"))
+	require.NoError(t, err)
+	require.Equal(t, "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d", sk.Hex())
+
+	_, err = DecryptKeystore([]byte(ks), []byte("wrongpassword"))
+	require.Equal(t, ErrKeystoreMACMismatch, err)
+}
+
+func TestEncryptDecryptKeystore(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	password := []byte("pwd")
+
+	for _, params := range []KeystoreParams{
+		testKeystoreParams,
+		{KDF: KeystoreKDFPBKDF2, PBKDF2Iterations: 16},
+	} {
+		t.Run(params.KDF, func(t *testing.T) {
+			b, err := EncryptKeystore(sk, password, params)
+			require.NoError(t, err)
+
+			var ks keystoreJSON
+			require.NoError(t, json.Unmarshal(b, &ks))
+			require.Equal(t, KeystoreVersion, ks.Version)
+			require.Equal(t, "aes-128-ctr", ks.Crypto.Cipher)
+			require.Equal(t, params.KDF, ks.Crypto.KDF)
+			require.Len(t, ks.ID, 36)
+
+			addr := cipher.MustEthereumAddressFromSecKey(sk)
+			require.Equal(t, hex.EncodeToString(addr[:]), ks.Address)
+
+			sk2, err := DecryptKeystore(b, password)
+			require.NoError(t, err)
+			require.Equal(t, sk, sk2)
+
+			_, err = DecryptKeystore(b, []byte("bad"))
+			require.Equal(t, ErrKeystoreMACMismatch, err)
+
+			// The address must match the key
+			ks.Address = "0000000000000000000000000000000000000000"
+			b, err = json.Marshal(ks)
+			require.NoError(t, err)
+			_, err = DecryptKeystore(b, password)
+			require.Equal(t, ErrKeystoreAddressMismatch, err)
+		})
+	}
+}
+
+func TestEncryptKeystoreErrors(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+
+	_, err := EncryptKeystore(sk, nil, testKeystoreParams)
+	require.Equal(t, ErrMissingPassword, err)
+
+	_, err = EncryptKeystore(sk, []byte("pwd"), KeystoreParams{KDF: "argon2"})
+	require.Equal(t, NewError(errors.New(`unsupported keystore kdf "argon2"`)), err)
+
+	_, err = EncryptKeystore(sk, []byte("pwd"), KeystoreParams{KDF: KeystoreKDFPBKDF2})
+	require.Equal(t, NewError(errors.New("invalid pbkdf2 params: iteration count must be positive")), err)
+
+	_, err = EncryptKeystore(sk, []byte("pwd"), KeystoreParams{KDF: KeystoreKDFScrypt, ScryptN: 3, ScryptR: 8, ScryptP: 1})
+	require.Error(t, err)
+}
+
+func TestDecryptKeystoreErrors(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	b, err := EncryptKeystore(sk, []byte("pwd"), testKeystoreParams)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name   string
+		modify func(ks map[string]interface{})
+		err    error
+	}{
+		{
+			name: "unsupported version",
+			modify: func(ks map[string]interface{}) {
+				ks["version"] = 1
+			},
+			err: NewError(errors.New("unsupported keystore version 1")),
+		},
+		{
+			name: "unsupported cipher",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["cipher"] = "aes-128-cbc"
+			},
+			err: NewError(errors.New(`unsupported keystore cipher "aes-128-cbc"`)),
+		},
+		{
+			name: "unsupported kdf",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdf"] = "argon2"
+			},
+			err: NewError(errors.New(`unsupported keystore kdf "argon2"`)),
+		},
+		{
+			name: "invalid salt",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["salt"] = "zz"
+			},
+			err: NewError(errors.New("invalid keystore kdfparams salt")),
+		},
+		{
+			name: "invalid n",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["n"] = "16"
+			},
+			err: NewError(errors.New(`invalid keystore kdfparams "n"`)),
+		},
+		{
+			name: "scrypt n too high",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["n"] = 1 << 21
+			},
+			err: NewError(errors.New("keystore scrypt n must be <= 1048576")),
+		},
+		{
+			name: "scrypt r*p too high",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["r"] = 8
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["p"] = 1 << 30
+			},
+			err: NewError(errors.New("keystore scrypt r*p must be <= 64")),
+		},
+		{
+			name: "scrypt memory too high",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["n"] = 1 << 20
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["r"] = 16
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["p"] = 1
+			},
+			err: NewError(errors.New("keystore scrypt memory 128*n*r must be <= 1073741824 bytes")),
+		},
+		{
+			name: "unsupported dklen",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["dklen"] = 16
+			},
+			err: NewError(errors.New("unsupported keystore dklen 16")),
+		},
+		{
+			name: "modified ciphertext",
+			modify: func(ks map[string]interface{}) {
+				ks["crypto"].(map[string]interface{})["ciphertext"] = hex.EncodeToString(make([]byte, 32))
+			},
+			err: ErrKeystoreMACMismatch,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var ks map[string]interface{}
+			require.NoError(t, json.Unmarshal(b, &ks))
+			tc.modify(ks)
+			data, err := json.Marshal(ks)
+			require.NoError(t, err)
+
+			_, err = DecryptKeystore(data, []byte("pwd"))
+			require.Equal(t, tc.err, err)
+		})
+	}
+	// The pbkdf2 iteration count is bounded
+	b, err = EncryptKeystore(sk, []byte("pwd"), KeystoreParams{KDF: KeystoreKDFPBKDF2, PBKDF2Iterations: 1})
+	require.NoError(t, err)
+	var ks map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &ks))
+	ks["crypto"].(map[string]interface{})["kdfparams"].(map[string]interface{})["c"] = 10000001
+	data, err := json.Marshal(ks)
+	require.NoError(t, err)
+	_, err = DecryptKeystore(data, []byte("pwd"))
+	require.Equal(t, NewError(errors.New("keystore pbkdf2 c must be <= 10000000")), err)
+}
@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+
+	"github.com/skycoin/skycoin/src/util/file"
+)
+
+// LockFilename is the name of the lock file in the wallet directory
+const LockFilename = ".wallets.lock"
+
+var (
+	// ErrWalletDirLocked is returned when the wallet directory is locked by another process
+	ErrWalletDirLocked = NewError(errors.New("wallet directory is locked by another process"))
+
+	dirLocksMu sync.Mutex
+	// dirLocks records the file locks of the wallet directories locked by this process,
+	// keyed by the absolute wallet directory path
+	dirLocks = make(map[string]*dirLockRef)
+)
+
+type dirLockRef struct {
+	lock *file.Lock
+	refs int
+}
+
+// DirLock is an advisory lock of a wallet directory, which prevents other
+// processes, e.g. another node instance, from loading and saving the wallets
+// in the directory. The lock is shared within the process, the directory
+// is unlocked when all of its DirLocks are unlocked.
+type DirLock struct {
+	dir string
+}
+
+// Lock takes the advisory lock of the wallet directory.
+// Returns ErrWalletDirLocked if the directory is locked by another process.
+func Lock(dir string) (*DirLock, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dirLocksMu.Lock()
+	defer dirLocksMu.Unlock()
+
+	if ref, ok := dirLocks[dir]; ok {
+		ref.refs++
+		return &DirLock{dir: dir}, nil
+	}
+
+	l, err := file.TryLock(filepath.Join(dir, LockFilename))
+	if err != nil {
+		if err == file.ErrLocked {
+			return nil, ErrWalletDirLocked
+		}
+		return nil, err
+	}
+
+	dirLocks[dir] = &dirLockRef{lock: l, refs: 1}
+	return &DirLock{dir: dir}, nil
+}
+
+// Unlock releases the lock of the wallet directory. Unlocking an unlocked DirLock is a no-op.
+func (l *DirLock) Unlock() error {
+	if l == nil || l.dir == "" {
+		return nil
+	}
+
+	dirLocksMu.Lock()
+	defer dirLocksMu.Unlock()
+
+	dir := l.dir
+	l.dir = ""
+
+	ref, ok := dirLocks[dir]
+	if !ok {
+		return nil
+	}
+
+	ref.refs--
+	if ref.refs > 0 {
+		return nil
+	}
+
+	delete(dirLocks, dir)
+	return ref.lock.Unlock()
+}
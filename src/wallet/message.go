@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+var (
+	// ErrMissingSecKey is returned when signing with an address whose secret key is not in the wallet,
+	// e.g. the addresses of watch-only, xpub and hardware wallets
+	ErrMissingSecKey = NewError(errors.New("secret key of the address is not in the wallet"))
+	// ErrMissingPubKey is returned when encrypting to an address whose public key is not in the wallet,
+	// e.g. the addresses of watch-only wallets
+	ErrMissingPubKey = NewError(errors.New("public key of the address is not in the wallet"))
+	// ErrSignMessageCoinType is returned when signing a message with a wallet whose coin is not skycoin
+	ErrSignMessageCoinType = NewError(errors.New("only skycoin wallets can sign messages"))
+	// ErrMessageCoinType is returned when encrypting or decrypting a message with a wallet whose coin is not skycoin
+	ErrMessageCoinType = NewError(errors.New("only skycoin wallets can encrypt and decrypt messages"))
+)
+
+// signMessage signs the message with the secret key of the address of the decrypted wallet, see cipher.SignMessage
+func signMessage(w Wallet, addr cipher.Address, msg []byte) (cipher.Sig, error) {
+	sk, err := findSecKey(w, addr)
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+
+	return cipher.SignMessage(sk, msg)
+}
+
+// decryptMessage decrypts the ECIES ciphertext with the secret key of the address of the decrypted wallet,
+// see cipher.ECIESDecrypt
+func decryptMessage(w Wallet, addr cipher.Address, ciphertext []byte) ([]byte, error) {
+	sk, err := findSecKey(w, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.ECIESDecrypt(sk, ciphertext)
+}
+
+// findSecKey returns the secret key of the address of the decrypted wallet
+func findSecKey(w Wallet, addr cipher.Address) (cipher.SecKey, error) {
+	entries, err := allEntries(w)
+	if err != nil {
+		return cipher.SecKey{}, err
+	}
+	defer entries.Erase()
+
+	e, ok := findEntry(entries, addr)
+	if !ok {
+		return cipher.SecKey{}, ErrUnknownAddress
+	}
+
+	if e.Secret.Null() {
+		return cipher.SecKey{}, ErrMissingSecKey
+	}
+
+	return e.Secret.SecKey(), nil
+}
@@ -1,31 +1,60 @@
 package wallet
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
 
 // wallet meta fields
 const (
-	MetaVersion        = "version"        // wallet version
-	MetaFilename       = "filename"       // wallet file name
-	MetaLabel          = "label"          // wallet label
-	MetaTimestamp      = "tm"             // the timestamp when creating the wallet
-	MetaType           = "type"           // wallet type
-	MetaCoin           = "coin"           // coin type
-	MetaEncrypted      = "encrypted"      // whether the wallet is encrypted
-	MetaCryptoType     = "cryptoType"     // encryption/decryption type
-	MetaSeed           = "seed"           // wallet seed
-	MetaLastSeed       = "lastSeed"       // seed for generating next address [deterministic wallets]
-	MetaSecrets        = "secrets"        // secrets which records the encrypted seeds and secrets of address entries
-	MetaBip44Coin      = "bip44Coin"      // bip44 coin type
-	MetaAccountsHash   = "accountsHash"   // accounts hash
-	MetaSeedPassphrase = "seedPassphrase" // seed passphrase [bip44 wallets]
-	MetaXPub           = "xpub"           // xpub key [xpub wallets]
+	MetaVersion            = "version"            // wallet version
+	MetaFilename           = "filename"           // wallet file name
+	MetaLabel              = "label"              // wallet label
+	MetaTimestamp          = "tm"                 // the timestamp when creating the wallet
+	MetaType               = "type"               // wallet type
+	MetaCoin               = "coin"               // coin type
+	MetaEncrypted          = "encrypted"          // whether the wallet is encrypted
+	MetaCryptoType         = "cryptoType"         // encryption/decryption type
+	MetaSeed               = "seed"               // wallet seed
+	MetaLastSeed           = "lastSeed"           // seed for generating next address [deterministic wallets]
+	MetaSecrets            = "secrets"            // secrets which records the encrypted seeds and secrets of address entries
+	MetaDuressSecrets      = "duressSecrets"      // secret keys of the decoy entries, encrypted with the duress password
+	MetaBip44Coin          = "bip44Coin"          // bip44 coin type
+	MetaBip44PathTemplate  = "bip44PathTemplate"  // bip32 derivation path template, e.g. m/44'/8000'/n'/change/index [bip44 wallets]
+	MetaAccountsHash       = "accountsHash"       // accounts hash
+	MetaSeedPassphrase     = "seedPassphrase"     // seed passphrase [bip44 wallets]
+	MetaXPub               = "xpub"               // xpub key [xpub wallets]
+	MetaXPubAccount        = "xpubAccount"        // whether the xpub is a bip44 account key with external and change chains [xpub wallets]
+	MetaPubKeys            = "pubKeys"            // comma separated cosigner public keys [multisig wallets]
+	MetaThreshold          = "threshold"          // number of required cosigner signatures [multisig wallets]
+	MetaDevice             = "device"             // hardware device type [hardware wallets]
+	MetaDerivationPath     = "derivationPath"     // bip32 path of the xpub key on the device [hardware wallets]
+	MetaSigner             = "signer"             // name of the registered keyring signing with the keys, e.g. in a PKCS#11 HSM [collection-watch wallets]
+	MetaArgon2Iterations   = "argon2Iterations"   // argon2id number of iterations [argon2id-chacha20poly1305 crypto type]
+	MetaArgon2Memory       = "argon2Memory"       // argon2id memory size in KiB [argon2id-chacha20poly1305 crypto type]
+	MetaRestoredFromShares = "restoredFromShares" // whether the seed was restored from SLIP-0039 shares
+	MetaSharesIdentifier   = "sharesIdentifier"   // identifier of the SLIP-0039 share set the seed was restored from
+	MetaChangePolicy       = "changePolicy"       // change address policy of the created transactions
+	MetaAddressEncoding    = "addressEncoding"    // encoding of the generated addresses, e.g. bech32 [bitcoin wallets]
+	MetaChangeAddress      = "changeAddress"      // change address [address change policy]
+	MetaReadOnly           = "readOnly"           // whether the wallet is read-only
+	MetaMigratedTo         = "migratedTo"         // file name of the bip44 wallet the wallet was migrated to
+	MetaDailySpendLimit    = "dailySpendLimit"    // maximum number of droplets sent per UTC day
+	MetaRequirePassword    = "requirePassword"    // whether the password is required for every send
+	MetaSpentDay           = "spentDay"           // UTC day of the spent droplets, e.g. 2006-01-02
+	MetaSpent              = "spent"              // number of droplets sent on the spent day
+	MetaSeedLanguage       = "seedLanguage"       // bip39 wordlist language of the seed, english if not set [bip44 wallets]
+	MetaSeedEntropy        = "seedEntropy"        // derivation description of seed entropy mixed with user entropy, e.g. dice rolls
+	MetaUnlockedUntil      = "unlockedUntil"      // expiry in unix nanoseconds of the unlock session, not saved to the wallet file
 )
 
 //const (
@@ -50,11 +79,6 @@ func (m Meta) Clone() Meta {
 	return mm
 }
 
-// EraseSeeds wipes the seed and last seed
-func (m Meta) EraseSeeds() {
-	m.SetSeed("")
-}
-
 // Find returns a key value from the metadata map
 func (m Meta) Find(k string) string {
 	return m[k]
@@ -95,36 +119,6 @@ func (m Meta) SetLabel(label string) {
 	m[MetaLabel] = label
 }
 
-// LastSeed returns the last seed
-func (m Meta) LastSeed() string {
-	return m[MetaLastSeed]
-}
-
-// SetLastSeed sets or updates the last seed
-func (m Meta) SetLastSeed(lseed string) {
-	m[MetaLastSeed] = lseed
-}
-
-// Seed returns the seed
-func (m Meta) Seed() string {
-	return m[MetaSeed]
-}
-
-// SetSeed sets the seed
-func (m Meta) SetSeed(seed string) {
-	m[MetaSeed] = seed
-}
-
-// SeedPassphrase returns the seed passphrase
-func (m Meta) SeedPassphrase() string {
-	return m[MetaSeedPassphrase]
-}
-
-// SetSeedPassphrase sets the seed passphrase
-func (m Meta) SetSeedPassphrase(p string) {
-	m[MetaSeedPassphrase] = p
-}
-
 // Coin returns the wallet's coin type
 func (m Meta) Coin() CoinType {
 	return CoinType(m[MetaCoin])
@@ -157,6 +151,45 @@ func (m Meta) SetBip44Coin(ct bip44.CoinType) {
 	m[MetaBip44Coin] = strconv.FormatUint(uint64(ct), 10)
 }
 
+// Bip44PathTemplate returns the bip32 derivation path template of the accounts,
+// empty if the default bip44 path of the bip44 coin type is used
+func (m Meta) Bip44PathTemplate() string {
+	return m[MetaBip44PathTemplate]
+}
+
+// SetBip44PathTemplate sets the bip32 derivation path template of the accounts
+func (m Meta) SetBip44PathTemplate(t string) {
+	m[MetaBip44PathTemplate] = t
+}
+
+// SeedLanguage returns the bip39 wordlist language of the seed
+func (m Meta) SeedLanguage() bip39.Language {
+	if l := m[MetaSeedLanguage]; l != "" {
+		return bip39.Language(l)
+	}
+	return bip39.English
+}
+
+// SetSeedLanguage sets the bip39 wordlist language of the seed, english is not recorded
+func (m Meta) SetSeedLanguage(l bip39.Language) {
+	if l == bip39.English {
+		delete(m, MetaSeedLanguage)
+		return
+	}
+	m[MetaSeedLanguage] = string(l)
+}
+
+// SeedEntropy returns the derivation description of the seed entropy, if it was mixed with user entropy.
+// It is parsed with bip39.ParseEntropyDescription.
+func (m Meta) SeedEntropy() string {
+	return m[MetaSeedEntropy]
+}
+
+// SetSeedEntropy records the derivation description of the seed entropy
+func (m Meta) SetSeedEntropy(description string) {
+	m[MetaSeedEntropy] = description
+}
+
 func (m Meta) setIsEncrypted(encrypt bool) {
 	m[MetaEncrypted] = strconv.FormatBool(encrypt)
 }
@@ -207,6 +240,87 @@ func (m Meta) SetCryptoType(ct crypto.CryptoType) {
 	m[MetaCryptoType] = string(ct)
 }
 
+// Argon2Params returns the argon2id iterations and memory size in KiB,
+// which are 0 if not set
+func (m Meta) Argon2Params() (iterations, memory uint32) {
+	// Intentionally ignore the errors, the values are validated when the wallet is loaded
+	t, _ := strconv.ParseUint(m[MetaArgon2Iterations], 10, 32) //nolint:errcheck
+	mem, _ := strconv.ParseUint(m[MetaArgon2Memory], 10, 32)   //nolint:errcheck
+	return uint32(t), uint32(mem)
+}
+
+// SetArgon2Params sets the argon2id iterations and memory size in KiB
+func (m Meta) SetArgon2Params(iterations, memory uint32) {
+	m[MetaArgon2Iterations] = strconv.FormatUint(uint64(iterations), 10)
+	m[MetaArgon2Memory] = strconv.FormatUint(uint64(memory), 10)
+}
+
+// RestoredFromShares returns whether the wallet seed was restored from SLIP-0039 shares
+func (m Meta) RestoredFromShares() bool {
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	b, _ := strconv.ParseBool(m[MetaRestoredFromShares]) //nolint:errcheck
+	return b
+}
+
+// SharesIdentifier returns the identifier of the SLIP-0039 share set the wallet seed was restored from
+func (m Meta) SharesIdentifier() uint16 {
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	x, _ := strconv.ParseUint(m[MetaSharesIdentifier], 10, 16) //nolint:errcheck
+	return uint16(x)
+}
+
+// SetRestoredFromShares records that the wallet seed was restored from the SLIP-0039 share set of the identifier
+func (m Meta) SetRestoredFromShares(identifier uint16) {
+	m[MetaRestoredFromShares] = strconv.FormatBool(true)
+	m[MetaSharesIdentifier] = strconv.FormatUint(uint64(identifier), 10)
+}
+
+// IsReadOnly returns whether the wallet is read-only
+func (m Meta) IsReadOnly() bool {
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	b, _ := strconv.ParseBool(m[MetaReadOnly]) //nolint:errcheck
+	return b
+}
+
+// SetReadOnly marks the wallet as read-only, or removes the mark
+func (m Meta) SetReadOnly(readOnly bool) {
+	if !readOnly {
+		delete(m, MetaReadOnly)
+		return
+	}
+	m[MetaReadOnly] = strconv.FormatBool(true)
+}
+
+// MigratedTo returns the file name of the bip44 wallet the wallet was migrated to
+func (m Meta) MigratedTo() string {
+	return m[MetaMigratedTo]
+}
+
+// SetMigratedTo records the file name of the bip44 wallet the wallet was migrated to
+func (m Meta) SetMigratedTo(filename string) {
+	m[MetaMigratedTo] = filename
+}
+
+// UnlockedUntil returns the expiry of the unlock session of the wallet,
+// the zero time if the wallet is not in an unlock session
+func (m Meta) UnlockedUntil() time.Time {
+	// Intentionally ignore the error, the value is only set by the service
+	n, _ := strconv.ParseInt(m[MetaUnlockedUntil], 10, 64) //nolint:errcheck
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// SetUnlockedUntil sets the expiry of the unlock session of the wallet, the zero time removes it
+func (m Meta) SetUnlockedUntil(t time.Time) {
+	if t.IsZero() {
+		delete(m, MetaUnlockedUntil)
+		return
+	}
+	m[MetaUnlockedUntil] = strconv.FormatInt(t.UnixNano(), 10)
+}
+
 // Secrets returns the encrypted wallet secrets
 func (m Meta) Secrets() string {
 	return m[MetaSecrets]
@@ -216,6 +330,20 @@ func (m Meta) setSecrets(s string) {
 	m[MetaSecrets] = s
 }
 
+// DuressSecrets returns the decoy secrets encrypted with the duress password
+func (m Meta) DuressSecrets() string {
+	return m[MetaDuressSecrets]
+}
+
+// SetDuressSecrets sets the encrypted decoy secrets, an empty string removes the duress password
+func (m Meta) SetDuressSecrets(s string) {
+	if s == "" {
+		delete(m, MetaDuressSecrets)
+		return
+	}
+	m[MetaDuressSecrets] = s
+}
+
 // Timestamp returns the timestamp
 func (m Meta) Timestamp() int64 {
 	// Intentionally ignore the error when parsing the timestamp,
@@ -247,6 +375,31 @@ func (m Meta) SetTimestamp(t int64) {
 // 	}
 // }
 
+// ChangePolicy returns the change address policy
+func (m Meta) ChangePolicy() ChangePolicy {
+	return ChangePolicy(m[MetaChangePolicy])
+}
+
+// ChangePolicyAddress returns the change address of the address change policy
+func (m Meta) ChangePolicyAddress() string {
+	return m[MetaChangeAddress]
+}
+
+// SetChangePolicy sets the change address policy, changeAddress is only used by the address change policy
+func (m Meta) SetChangePolicy(policy ChangePolicy, changeAddress string) {
+	if policy == ChangePolicyDefault {
+		delete(m, MetaChangePolicy)
+	} else {
+		m[MetaChangePolicy] = string(policy)
+	}
+
+	if changeAddress == "" {
+		delete(m, MetaChangeAddress)
+	} else {
+		m[MetaChangeAddress] = changeAddress
+	}
+}
+
 // SetXPub sets xpub
 func (m Meta) SetXPub(xpub string) {
 	m[MetaXPub] = xpub
@@ -257,6 +410,94 @@ func (m Meta) XPub() string {
 	return m[MetaXPub]
 }
 
+// IsXPubAccount returns whether the xpub of the wallet is a bip44 account key,
+// whose addresses are derived on its external and change chains
+func (m Meta) IsXPubAccount() bool {
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	b, _ := strconv.ParseBool(m[MetaXPubAccount]) //nolint:errcheck
+	return b
+}
+
+// SetXPubAccount marks the xpub of the wallet as a bip44 account key, or removes the mark
+func (m Meta) SetXPubAccount(account bool) {
+	if !account {
+		delete(m, MetaXPubAccount)
+		return
+	}
+	m[MetaXPubAccount] = strconv.FormatBool(true)
+}
+
+// AddressEncoding returns the encoding of the addresses generated by the wallet,
+// AddressEncodingDefault if the addresses are generated with the default encoding of the coin type
+func (m Meta) AddressEncoding() AddressEncoding {
+	return AddressEncoding(m[MetaAddressEncoding])
+}
+
+// SetAddressEncoding sets the encoding of the generated addresses, AddressEncodingDefault removes it
+func (m Meta) SetAddressEncoding(enc AddressEncoding) {
+	if enc == AddressEncodingDefault {
+		delete(m, MetaAddressEncoding)
+		return
+	}
+	m[MetaAddressEncoding] = string(enc)
+}
+
+// PubKeys returns the cosigner public keys of a multisig wallet
+func (m Meta) PubKeys() []string {
+	s := m[MetaPubKeys]
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// SetPubKeys sets the cosigner public keys
+func (m Meta) SetPubKeys(pks []string) {
+	m[MetaPubKeys] = strings.Join(pks, ",")
+}
+
+// Threshold returns the number of cosigner signatures required by a multisig wallet
+func (m Meta) Threshold() uint64 {
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	x, _ := strconv.ParseUint(m[MetaThreshold], 10, 64) //nolint:errcheck
+	return x
+}
+
+// SetThreshold sets the number of required cosigner signatures
+func (m Meta) SetThreshold(n uint64) {
+	m[MetaThreshold] = strconv.FormatUint(n, 10)
+}
+
+// Device returns the device type of a hardware wallet
+func (m Meta) Device() string {
+	return m[MetaDevice]
+}
+
+// SetDevice sets the hardware device type
+func (m Meta) SetDevice(device string) {
+	m[MetaDevice] = device
+}
+
+// Signer returns the name of the keyring that signs with the keys of the wallet
+func (m Meta) Signer() string {
+	return m[MetaSigner]
+}
+
+// SetSigner sets the name of the keyring that signs with the keys of the wallet
+func (m Meta) SetSigner(name string) {
+	m[MetaSigner] = name
+}
+
+// DerivationPath returns the bip32 path of the xpub key of a hardware wallet
+func (m Meta) DerivationPath() string {
+	return m[MetaDerivationPath]
+}
+
+// SetDerivationPath sets the bip32 path of the xpub key
+func (m Meta) SetDerivationPath(p string) {
+	m[MetaDerivationPath] = p
+}
+
 // Validate validates the meta data
 func (m Meta) Validate() error {
 	if fn := m[MetaFilename]; fn == "" {
@@ -279,6 +520,58 @@ func (m Meta) Validate() error {
 		return errors.New("coin field not set")
 	}
 
+	for _, k := range []string{MetaArgon2Iterations, MetaArgon2Memory} {
+		if v, ok := m[k]; ok {
+			if _, err := strconv.ParseUint(v, 10, 32); err != nil {
+				return fmt.Errorf("invalid %s", k)
+			}
+		}
+	}
+
+	for _, k := range []string{MetaRestoredFromShares, MetaReadOnly, MetaRequirePassword, MetaXPubAccount} {
+		if v, ok := m[k]; ok {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("invalid %s", k)
+			}
+		}
+	}
+
+	for _, k := range []string{MetaDailySpendLimit, MetaSpent} {
+		if v, ok := m[k]; ok {
+			if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+				return fmt.Errorf("invalid %s", k)
+			}
+		}
+	}
+
+	if v, ok := m[MetaSpentDay]; ok {
+		if _, err := time.Parse(spentDayLayout, v); err != nil {
+			return fmt.Errorf("invalid %s", MetaSpentDay)
+		}
+	}
+
+	if v, ok := m[MetaSeedLanguage]; ok {
+		if l, err := bip39.ParseLanguage(v); err != nil || string(l) != v {
+			return fmt.Errorf("invalid %s", MetaSeedLanguage)
+		}
+	}
+
+	if v, ok := m[MetaSeedEntropy]; ok {
+		if _, err := bip39.ParseEntropyDescription(v); err != nil {
+			return fmt.Errorf("invalid %s", MetaSeedEntropy)
+		}
+	}
+
+	if v, ok := m[MetaSharesIdentifier]; ok {
+		if _, err := strconv.ParseUint(v, 10, 16); err != nil {
+			return fmt.Errorf("invalid %s", MetaSharesIdentifier)
+		}
+	}
+
+	if err := validateAddressEncoding(m); err != nil {
+		return fmt.Errorf("invalid %s", MetaAddressEncoding)
+	}
+
 	var isEncrypted bool
 	if encStr, ok := m[MetaEncrypted]; ok {
 		// validate the encrypted value
@@ -314,18 +607,42 @@ func (m Meta) Validate() error {
 		if s := m[MetaSecrets]; s != "" {
 			return errors.New("secrets should not be in unencrypted wallets")
 		}
+
+		if s := m[MetaDuressSecrets]; s != "" {
+			return errors.New("duressSecrets should not be in unencrypted wallets")
+		}
 	}
 	return nil
 }
 
-// ResolveCoinType normalizes a coin type string to a CoinType constant
-func ResolveCoinType(s string) (CoinType, error) {
-	switch strings.ToLower(s) {
-	case "sky", "skycoin":
-		return CoinTypeSkycoin, nil
-	case "btc", "bitcoin":
-		return CoinTypeBitcoin, nil
-	default:
-		return CoinType(""), errors.New("invalid coin type")
+// Extensions returns the extensions of the wallet, keyed by extension name
+func (m Meta) Extensions() map[string]json.RawMessage {
+	exts := make(map[string]json.RawMessage)
+	for k, v := range m {
+		if strings.HasPrefix(k, MetaExtensionPrefix) {
+			exts[strings.TrimPrefix(k, MetaExtensionPrefix)] = json.RawMessage(v)
+		}
 	}
+	return exts
+}
+
+// SetExtension sets the extension data of given name, the data must be valid JSON and is stored compacted.
+// A nil data removes the extension.
+func (m Meta) SetExtension(name string, data json.RawMessage) error {
+	if name == "" {
+		return errors.New("missing extension name")
+	}
+
+	if data == nil {
+		delete(m, MetaExtensionPrefix+name)
+		return nil
+	}
+
+	var b bytes.Buffer
+	if err := json.Compact(&b, data); err != nil {
+		return fmt.Errorf("extension %q data is not valid JSON: %v", name, err)
+	}
+
+	m[MetaExtensionPrefix+name] = b.String()
+	return nil
 }
@@ -3,12 +3,16 @@
 package wallet
 
 import (
+	bip39 "github.com/skycoin/skycoin/src/cipher/bip39"
+
 	cipher "github.com/skycoin/skycoin/src/cipher"
 	bip44 "github.com/skycoin/skycoin/src/cipher/bip44"
 
 	crypto "github.com/skycoin/skycoin/src/wallet/crypto"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockWallet is an autogenerated mock type for the Wallet type
@@ -32,6 +36,55 @@ func (_m *MockWallet) Accounts() []Bip44Account {
 	return r0
 }
 
+// AddSpent provides a mock function with given fields: t, coins
+func (_m *MockWallet) AddSpent(t time.Time, coins uint64) error {
+	ret := _m.Called(t, coins)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time, uint64) error); ok {
+		r0 = rf(t, coins)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddressEncoding provides a mock function with given fields:
+func (_m *MockWallet) AddressEncoding() AddressEncoding {
+	ret := _m.Called()
+
+	var r0 AddressEncoding
+	if rf, ok := ret.Get(0).(func() AddressEncoding); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(AddressEncoding)
+	}
+
+	return r0
+}
+
+// Argon2Params provides a mock function with given fields:
+func (_m *MockWallet) Argon2Params() (uint32, uint32) {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 uint32
+	if rf, ok := ret.Get(1).(func() uint32); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(uint32)
+	}
+
+	return r0, r1
+}
+
 // Bip44Coin provides a mock function with given fields:
 func (_m *MockWallet) Bip44Coin() *bip44.CoinType {
 	ret := _m.Called()
@@ -48,6 +101,48 @@ func (_m *MockWallet) Bip44Coin() *bip44.CoinType {
 	return r0
 }
 
+// Bip44PathTemplate provides a mock function with given fields:
+func (_m *MockWallet) Bip44PathTemplate() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ChangePolicy provides a mock function with given fields:
+func (_m *MockWallet) ChangePolicy() ChangePolicy {
+	ret := _m.Called()
+
+	var r0 ChangePolicy
+	if rf, ok := ret.Get(0).(func() ChangePolicy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(ChangePolicy)
+	}
+
+	return r0
+}
+
+// ChangePolicyAddress provides a mock function with given fields:
+func (_m *MockWallet) ChangePolicyAddress() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Clone provides a mock function with given fields:
 func (_m *MockWallet) Clone() Wallet {
 	ret := _m.Called()
@@ -97,6 +192,20 @@ func (_m *MockWallet) CryptoType() crypto.CryptoType {
 	return r0
 }
 
+// DerivationPath provides a mock function with given fields:
+func (_m *MockWallet) DerivationPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Deserialize provides a mock function with given fields: data
 func (_m *MockWallet) Deserialize(data []byte) error {
 	ret := _m.Called(data)
@@ -111,6 +220,34 @@ func (_m *MockWallet) Deserialize(data []byte) error {
 	return r0
 }
 
+// Device provides a mock function with given fields:
+func (_m *MockWallet) Device() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// DuressSecrets provides a mock function with given fields:
+func (_m *MockWallet) DuressSecrets() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // EntriesLen provides a mock function with given fields: options
 func (_m *MockWallet) EntriesLen(options ...Option) (int, error) {
 	_va := make([]interface{}, len(options))
@@ -315,6 +452,34 @@ func (_m *MockWallet) GetEntryAt(i int, options ...Option) (Entry, error) {
 	return r0, r1
 }
 
+// GetEntryMeta provides a mock function with given fields: addr, options
+func (_m *MockWallet) GetEntryMeta(addr cipher.Addresser, options ...Option) (EntryMeta, error) {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, addr)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 EntryMeta
+	if rf, ok := ret.Get(0).(func(cipher.Addresser, ...Option) EntryMeta); ok {
+		r0 = rf(addr, options...)
+	} else {
+		r0 = ret.Get(0).(EntryMeta)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.Addresser, ...Option) error); ok {
+		r1 = rf(addr, options...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasEntry provides a mock function with given fields: addr, options
 func (_m *MockWallet) HasEntry(addr cipher.Addresser, options ...Option) (bool, error) {
 	_va := make([]interface{}, len(options))
@@ -357,6 +522,34 @@ func (_m *MockWallet) IsEncrypted() bool {
 	return r0
 }
 
+// IsReadOnly provides a mock function with given fields:
+func (_m *MockWallet) IsReadOnly() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsXPubAccount provides a mock function with given fields:
+func (_m *MockWallet) IsXPubAccount() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Label provides a mock function with given fields:
 func (_m *MockWallet) Label() string {
 	ret := _m.Called()
@@ -399,6 +592,34 @@ func (_m *MockWallet) Lock(password []byte) error {
 	return r0
 }
 
+// MigratedTo provides a mock function with given fields:
+func (_m *MockWallet) MigratedTo() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// RestoredFromShares provides a mock function with given fields:
+func (_m *MockWallet) RestoredFromShares() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // ScanAddresses provides a mock function with given fields: scanN, tf
 func (_m *MockWallet) ScanAddresses(scanN uint64, tf TransactionsFinder) ([]cipher.Addresser, error) {
 	ret := _m.Called(scanN, tf)
@@ -450,6 +671,34 @@ func (_m *MockWallet) Seed() string {
 	return r0
 }
 
+// SeedEntropy provides a mock function with given fields:
+func (_m *MockWallet) SeedEntropy() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// SeedLanguage provides a mock function with given fields:
+func (_m *MockWallet) SeedLanguage() bip39.Language {
+	ret := _m.Called()
+
+	var r0 bip39.Language
+	if rf, ok := ret.Get(0).(func() bip39.Language); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bip39.Language)
+	}
+
+	return r0
+}
+
 // SeedPassphrase provides a mock function with given fields:
 func (_m *MockWallet) SeedPassphrase() string {
 	ret := _m.Called()
@@ -487,11 +736,31 @@ func (_m *MockWallet) Serialize() ([]byte, error) {
 	return r0, r1
 }
 
+// SetAddressEncoding provides a mock function with given fields: enc
+func (_m *MockWallet) SetAddressEncoding(enc AddressEncoding) {
+	_m.Called(enc)
+}
+
+// SetArgon2Params provides a mock function with given fields: iterations, memory
+func (_m *MockWallet) SetArgon2Params(iterations uint32, memory uint32) {
+	_m.Called(iterations, memory)
+}
+
 // SetBip44Coin provides a mock function with given fields: ct
 func (_m *MockWallet) SetBip44Coin(ct bip44.CoinType) {
 	_m.Called(ct)
 }
 
+// SetBip44PathTemplate provides a mock function with given fields: t
+func (_m *MockWallet) SetBip44PathTemplate(t string) {
+	_m.Called(t)
+}
+
+// SetChangePolicy provides a mock function with given fields: policy, changeAddress
+func (_m *MockWallet) SetChangePolicy(policy ChangePolicy, changeAddress string) {
+	_m.Called(policy, changeAddress)
+}
+
 // SetCoin provides a mock function with given fields: coinType
 func (_m *MockWallet) SetCoin(coinType CoinType) {
 	_m.Called(coinType)
@@ -507,6 +776,37 @@ func (_m *MockWallet) SetDecoder(d Decoder) {
 	_m.Called(d)
 }
 
+// SetDuressSecrets provides a mock function with given fields: s
+func (_m *MockWallet) SetDuressSecrets(s string) {
+	_m.Called(s)
+}
+
+// SetEncrypted provides a mock function with given fields: cryptoType, encryptedSecrets
+func (_m *MockWallet) SetEncrypted(cryptoType crypto.CryptoType, encryptedSecrets string) {
+	_m.Called(cryptoType, encryptedSecrets)
+}
+
+// SetEntryMeta provides a mock function with given fields: addr, m, options
+func (_m *MockWallet) SetEntryMeta(addr cipher.Addresser, m EntryMeta, options ...Option) error {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, addr, m)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(cipher.Addresser, EntryMeta, ...Option) error); ok {
+		r0 = rf(addr, m, options...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetFilename provides a mock function with given fields: _a0
 func (_m *MockWallet) SetFilename(_a0 string) {
 	_m.Called(_a0)
@@ -517,11 +817,107 @@ func (_m *MockWallet) SetLabel(_a0 string) {
 	_m.Called(_a0)
 }
 
+// SetMigratedTo provides a mock function with given fields: filename
+func (_m *MockWallet) SetMigratedTo(filename string) {
+	_m.Called(filename)
+}
+
+// SetReadOnly provides a mock function with given fields: readOnly
+func (_m *MockWallet) SetReadOnly(readOnly bool) {
+	_m.Called(readOnly)
+}
+
+// SetRestoredFromShares provides a mock function with given fields: identifier
+func (_m *MockWallet) SetRestoredFromShares(identifier uint16) {
+	_m.Called(identifier)
+}
+
+// SetSeedEntropy provides a mock function with given fields: description
+func (_m *MockWallet) SetSeedEntropy(description string) {
+	_m.Called(description)
+}
+
+// SetSeedLanguage provides a mock function with given fields: l
+func (_m *MockWallet) SetSeedLanguage(l bip39.Language) {
+	_m.Called(l)
+}
+
+// SetSpendPolicy provides a mock function with given fields: p
+func (_m *MockWallet) SetSpendPolicy(p SpendPolicy) {
+	_m.Called(p)
+}
+
 // SetTimestamp provides a mock function with given fields: _a0
 func (_m *MockWallet) SetTimestamp(_a0 int64) {
 	_m.Called(_a0)
 }
 
+// SetUnlockedUntil provides a mock function with given fields: t
+func (_m *MockWallet) SetUnlockedUntil(t time.Time) {
+	_m.Called(t)
+}
+
+// SetXPubAccount provides a mock function with given fields: account
+func (_m *MockWallet) SetXPubAccount(account bool) {
+	_m.Called(account)
+}
+
+// SharesIdentifier provides a mock function with given fields:
+func (_m *MockWallet) SharesIdentifier() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// Signer provides a mock function with given fields:
+func (_m *MockWallet) Signer() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// SpendPolicy provides a mock function with given fields:
+func (_m *MockWallet) SpendPolicy() SpendPolicy {
+	ret := _m.Called()
+
+	var r0 SpendPolicy
+	if rf, ok := ret.Get(0).(func() SpendPolicy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(SpendPolicy)
+	}
+
+	return r0
+}
+
+// SpentOn provides a mock function with given fields: t
+func (_m *MockWallet) SpentOn(t time.Time) uint64 {
+	ret := _m.Called(t)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(time.Time) uint64); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // Timestamp provides a mock function with given fields:
 func (_m *MockWallet) Timestamp() int64 {
 	ret := _m.Called()
@@ -573,6 +969,20 @@ func (_m *MockWallet) Unlock(password []byte) (Wallet, error) {
 	return r0, r1
 }
 
+// UnlockedUntil provides a mock function with given fields:
+func (_m *MockWallet) UnlockedUntil() time.Time {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
 // Version provides a mock function with given fields:
 func (_m *MockWallet) Version() string {
 	ret := _m.Called()
@@ -0,0 +1,53 @@
+package multisig
+
+import (
+	"encoding/json"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// JSONDecoder implements the the WalletDecoder interface,
+// which provides methods for encoding and decoding a multisig wallet in JSON format.
+type JSONDecoder struct{}
+
+// Encode encodes the multisig wallet to []byte, and error if any
+func (d JSONDecoder) Encode(w wallet.Wallet) ([]byte, error) {
+	return json.MarshalIndent(newReadableWallet(w.(*Wallet)), "", "    ")
+}
+
+// Decode decodes the multisig wallet from byte slice
+func (d JSONDecoder) Decode(b []byte) (wallet.Wallet, error) {
+	rw := readableWallet{}
+	if err := json.Unmarshal(b, &rw); err != nil {
+		return nil, err
+	}
+
+	return rw.toWallet()
+}
+
+type readableWallet struct {
+	wallet.Meta `json:"meta"`
+}
+
+func (rw readableWallet) toWallet() (*Wallet, error) {
+	if err := validateMeta(rw.Meta); err != nil {
+		return nil, err
+	}
+
+	pks, err := parsePubKeys(rw.Meta.PubKeys())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Meta:    rw.Meta.Clone(),
+		pubKeys: pks,
+		decoder: &JSONDecoder{},
+	}, nil
+}
+
+func newReadableWallet(w *Wallet) *readableWallet {
+	return &readableWallet{
+		Meta: w.Meta.Clone(),
+	}
+}
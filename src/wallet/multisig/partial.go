@@ -0,0 +1,314 @@
+package multisig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+var (
+	// ErrNotCosigner is returned when signing with a key that is not one of the wallet cosigners
+	ErrNotCosigner = wallet.NewError(errors.New("secret key does not belong to a cosigner"))
+	// ErrPartialTransactionMismatch is returned when combining partially-signed transactions
+	// that do not sign the same transaction with the same cosigners
+	ErrPartialTransactionMismatch = wallet.NewError(errors.New("partially-signed transactions do not match"))
+)
+
+// PartiallySignedTransaction is the unit of exchange between cosigners of a multisig wallet.
+// It carries an unsigned transaction and, for each of its inputs, the signatures collected
+// so far. Once every input has Threshold valid signatures the transaction is complete.
+// A complete transaction can't be finalized into a transaction the blockchain accepts,
+// which verifies a single signature per input, until consensus supports multisig outputs.
+// The multisig wallet has no addresses, the inputs are not checked against the wallet.
+type PartiallySignedTransaction struct {
+	Transaction coin.Transaction
+	Threshold   uint64
+	PubKeys     []cipher.PubKey
+	Inputs      []PartialInput
+}
+
+// PartialInput records the cosigner signatures of a transaction input, ordered the same as PubKeys.
+// Missing signatures are null.
+type PartialInput struct {
+	Sigs []cipher.Sig
+}
+
+// NewPartiallySignedTransaction creates a PartiallySignedTransaction for the unsigned transaction txn,
+// to be signed by the cosigners of the wallet
+func (w *Wallet) NewPartiallySignedTransaction(txn coin.Transaction) (*PartiallySignedTransaction, error) {
+	if len(txn.In) == 0 {
+		return nil, wallet.NewError(errors.New("No transaction inputs to sign"))
+	}
+
+	if txn.InnerHash != txn.HashInner() {
+		return nil, wallet.NewError(errors.New("Transaction inner hash does not match computed inner hash"))
+	}
+
+	inputs := make([]PartialInput, len(txn.In))
+	for i := range inputs {
+		inputs[i] = PartialInput{
+			Sigs: make([]cipher.Sig, len(w.pubKeys)),
+		}
+	}
+
+	return &PartiallySignedTransaction{
+		Transaction: *copyTransaction(&txn),
+		Threshold:   w.Threshold(),
+		PubKeys:     w.CosignerPubKeys(),
+		Inputs:      inputs,
+	}, nil
+}
+
+func copyTransaction(txn *coin.Transaction) *coin.Transaction {
+	txn2 := *txn
+	txn2.Sigs = make([]cipher.Sig, len(txn.Sigs))
+	copy(txn2.Sigs, txn.Sigs)
+	txn2.In = make([]cipher.SHA256, len(txn.In))
+	copy(txn2.In, txn.In)
+	txn2.Out = make([]coin.TransactionOutput, len(txn.Out))
+	copy(txn2.Out, txn.Out)
+	return &txn2
+}
+
+// inputHash returns the hash a cosigner signs for the input of index i,
+// which is the same hash coin.Transaction.SignInput signs.
+func (p *PartiallySignedTransaction) inputHash(i int) cipher.SHA256 {
+	return cipher.AddSHA256(p.Transaction.InnerHash, p.Transaction.In[i])
+}
+
+func (p *PartiallySignedTransaction) cosignerIndex(pk cipher.PubKey) (int, bool) {
+	for i, k := range p.PubKeys {
+		if k == pk {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Sign adds the signatures of the cosigner owning key to every input that it has not signed yet.
+// Returns the number of inputs signed.
+func (p *PartiallySignedTransaction) Sign(key cipher.SecKey) (int, error) {
+	pk, err := cipher.PubKeyFromSecKey(key)
+	if err != nil {
+		return 0, wallet.NewError(err)
+	}
+
+	ci, ok := p.cosignerIndex(pk)
+	if !ok {
+		return 0, ErrNotCosigner
+	}
+
+	var n int
+	for i := range p.Inputs {
+		if !p.Inputs[i].Sigs[ci].Null() {
+			continue
+		}
+
+		sig, err := cipher.SignHash(p.inputHash(i), key)
+		if err != nil {
+			return n, err
+		}
+		p.Inputs[i].Sigs[ci] = sig
+		n++
+	}
+
+	return n, nil
+}
+
+// Combine merges the signatures collected in other into p.
+// Both must refer to the same transaction and cosigner set.
+func (p *PartiallySignedTransaction) Combine(other *PartiallySignedTransaction) error {
+	if p.Transaction.InnerHash != other.Transaction.InnerHash ||
+		p.Threshold != other.Threshold ||
+		len(p.PubKeys) != len(other.PubKeys) ||
+		len(p.Inputs) != len(other.Inputs) {
+		return ErrPartialTransactionMismatch
+	}
+
+	for i := range p.PubKeys {
+		if p.PubKeys[i] != other.PubKeys[i] {
+			return ErrPartialTransactionMismatch
+		}
+	}
+
+	if err := other.Verify(); err != nil {
+		return err
+	}
+
+	for i, in := range other.Inputs {
+		for j, sig := range in.Sigs {
+			if !sig.Null() && p.Inputs[i].Sigs[j].Null() {
+				p.Inputs[i].Sigs[j] = sig
+			}
+		}
+	}
+
+	return nil
+}
+
+// Verify checks that the partially-signed transaction is well formed and that
+// every signature present was made by its cosigner
+func (p *PartiallySignedTransaction) Verify() error {
+	sorted, err := sortPubKeys(p.PubKeys)
+	if err != nil {
+		return err
+	}
+
+	for i := range sorted {
+		if sorted[i] != p.PubKeys[i] {
+			return errors.New("cosigner public keys are not sorted")
+		}
+	}
+
+	if uint64(len(p.PubKeys)) < p.Threshold || p.Threshold == 0 {
+		return ErrInvalidThreshold
+	}
+
+	if len(p.Inputs) != len(p.Transaction.In) {
+		return errors.New("number of partial inputs does not match number of transaction inputs")
+	}
+
+	if p.Transaction.InnerHash != p.Transaction.HashInner() {
+		return wallet.NewError(errors.New("Transaction inner hash does not match computed inner hash"))
+	}
+
+	for i, in := range p.Inputs {
+		if len(in.Sigs) != len(p.PubKeys) {
+			return fmt.Errorf("input %d: number of signatures does not match number of cosigners", i)
+		}
+
+		h := p.inputHash(i)
+		for j, sig := range in.Sigs {
+			if sig.Null() {
+				continue
+			}
+
+			if err := cipher.VerifyPubKeySignedHash(p.PubKeys[j], sig, h); err != nil {
+				return wallet.NewError(fmt.Errorf("input %d: invalid signature of cosigner %s: %v", i, p.PubKeys[j].Hex(), err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// SignatureCount returns the number of signatures collected for the input of index i
+func (p *PartiallySignedTransaction) SignatureCount(i int) int {
+	var n int
+	for _, sig := range p.Inputs[i].Sigs {
+		if !sig.Null() {
+			n++
+		}
+	}
+	return n
+}
+
+// IsComplete returns true if every input has at least Threshold signatures
+func (p *PartiallySignedTransaction) IsComplete() bool {
+	for i := range p.Inputs {
+		if uint64(p.SignatureCount(i)) < p.Threshold {
+			return false
+		}
+	}
+	return true
+}
+
+// readablePartiallySignedTransaction is the JSON representation of a PartiallySignedTransaction
+type readablePartiallySignedTransaction struct {
+	Transaction string                 `json:"transaction"`
+	Threshold   uint64                 `json:"threshold"`
+	PubKeys     []string               `json:"pubkeys"`
+	Inputs      []readablePartialInput `json:"inputs"`
+}
+
+type readablePartialInput struct {
+	Sigs []string `json:"sigs"`
+}
+
+// Serialize encodes the partially-signed transaction to JSON, so it can be passed between cosigners
+func (p *PartiallySignedTransaction) Serialize() ([]byte, error) {
+	txnHex, err := p.Transaction.SerializeHex()
+	if err != nil {
+		return nil, err
+	}
+
+	rp := readablePartiallySignedTransaction{
+		Transaction: txnHex,
+		Threshold:   p.Threshold,
+		PubKeys:     pubKeysToHex(p.PubKeys),
+		Inputs:      make([]readablePartialInput, len(p.Inputs)),
+	}
+
+	for i, in := range p.Inputs {
+		sigs := make([]string, len(in.Sigs))
+		for j, sig := range in.Sigs {
+			if !sig.Null() {
+				sigs[j] = sig.Hex()
+			}
+		}
+
+		rp.Inputs[i] = readablePartialInput{
+			Sigs: sigs,
+		}
+	}
+
+	return json.MarshalIndent(rp, "", "    ")
+}
+
+// DeserializePartiallySignedTransaction decodes and verifies a partially-signed transaction
+func DeserializePartiallySignedTransaction(b []byte) (*PartiallySignedTransaction, error) {
+	var rp readablePartiallySignedTransaction
+	if err := json.Unmarshal(b, &rp); err != nil {
+		return nil, err
+	}
+
+	txn, err := coin.DeserializeTransactionHex(rp.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	pks := make([]cipher.PubKey, len(rp.PubKeys))
+	for i, s := range rp.PubKeys {
+		pk, err := cipher.PubKeyFromHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cosigner public key %q: %v", s, err)
+		}
+		pks[i] = pk
+	}
+
+	p := &PartiallySignedTransaction{
+		Transaction: txn,
+		Threshold:   rp.Threshold,
+		PubKeys:     pks,
+		Inputs:      make([]PartialInput, len(rp.Inputs)),
+	}
+
+	for i, in := range rp.Inputs {
+		sigs := make([]cipher.Sig, len(in.Sigs))
+		for j, s := range in.Sigs {
+			if s == "" {
+				continue
+			}
+
+			sig, err := cipher.SigFromHex(s)
+			if err != nil {
+				return nil, fmt.Errorf("input %d: invalid signature %q: %v", i, s, err)
+			}
+			sigs[j] = sig
+		}
+
+		p.Inputs[i] = PartialInput{
+			Sigs: sigs,
+		}
+	}
+
+	if err := p.Verify(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,450 @@
+package multisig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/musig"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletType represents the multisig wallet type
+const WalletType = "multisig"
+
+// MaxCosigners is the maximum number of cosigner public keys a multisig wallet can hold
+const MaxCosigners = 16
+
+var defaultWalletDecoder = &JSONDecoder{}
+
+var (
+	// ErrMissingPubKeys is returned when creating a multisig wallet without cosigner public keys
+	ErrMissingPubKeys = wallet.NewError(errors.New("missing cosigner public keys"))
+	// ErrTooManyPubKeys is returned when more than MaxCosigners public keys are provided
+	ErrTooManyPubKeys = wallet.NewError(fmt.Errorf("too many cosigner public keys, maximum is %d", MaxCosigners))
+	// ErrDuplicatePubKey is returned if the same cosigner public key is provided twice
+	ErrDuplicatePubKey = wallet.NewError(errors.New("duplicate cosigner public key"))
+	// ErrInvalidThreshold is returned if the threshold is 0 or larger than the number of cosigners
+	ErrInvalidThreshold = wallet.NewError(errors.New("threshold must be between 1 and the number of cosigners"))
+	// ErrAddressesNotSpendable is returned when generating the addresses of a multisig wallet.
+	// The blockchain has no multisig output type: a multisig address is the hash of the threshold
+	// and cosigner keys, no secret key matches it, so coins sent to it could never be spent.
+	ErrAddressesNotSpendable = wallet.NewError(errors.New("multisig addresses can't be generated, the blockchain has no multisig output type and coins sent to them could never be spent"))
+	// ErrMuSigSigners is returned when the MuSig signers are not threshold distinct cosigners
+	ErrMuSigSigners = wallet.NewError(errors.New("MuSig signers must be threshold distinct cosigners"))
+
+	errMultisigEncryption = errors.New("multisig wallet does not support encryption")
+)
+
+func init() {
+	if err := wallet.RegisterCreator(WalletType, &Creator{}); err != nil {
+		panic(err)
+	}
+
+	if err := wallet.RegisterLoader(WalletType, &Loader{}); err != nil {
+		panic(err)
+	}
+}
+
+// Wallet holds the public keys of N cosigners and a threshold M.
+// The wallet holds no secret keys; spending requires M cosigners to sign a
+// PartiallySignedTransaction with their own keys.
+//
+// The wallet has no addresses. The blockchain has no multisig output type and no secret
+// key matches a multisig address, coins sent to one could never be spent.
+type Wallet struct {
+	wallet.Meta
+	wallet.Seeds
+	pubKeys []cipher.PubKey
+	decoder wallet.Decoder
+}
+
+// NewWallet creates a M-of-N multisig wallet with options
+func NewWallet(filename, label string, threshold uint64, pubKeys []cipher.PubKey, options ...wallet.Option) (*Wallet, error) {
+	pks, err := sortPubKeys(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold == 0 || threshold > uint64(len(pks)) {
+		return nil, ErrInvalidThreshold
+	}
+
+	wlt := &Wallet{
+		Meta: wallet.Meta{
+			wallet.MetaFilename:  filename,
+			wallet.MetaLabel:     label,
+			wallet.MetaType:      WalletType,
+			wallet.MetaVersion:   wallet.Version,
+			wallet.MetaCoin:      string(wallet.CoinTypeSkycoin),
+			wallet.MetaTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		},
+		pubKeys: pks,
+		decoder: defaultWalletDecoder,
+	}
+	wlt.SetPubKeys(pubKeysToHex(pks))
+	wlt.SetThreshold(threshold)
+
+	advOpts := &wallet.AdvancedOptions{}
+	for _, opt := range options {
+		opt(wlt)
+		opt(advOpts)
+	}
+
+	if err := validateMeta(wlt.Meta); err != nil {
+		return nil, err
+	}
+
+	if advOpts.Encrypt {
+		return nil, wallet.NewError(errMultisigEncryption)
+	}
+
+	generateN := advOpts.GenerateN
+	if generateN > 0 {
+		if _, err := wlt.GenerateAddresses(generateN); err != nil {
+			return nil, err
+		}
+	}
+
+	scanN := advOpts.ScanN
+	if scanN > 0 {
+		if advOpts.TF == nil {
+			return nil, errors.New("missing transaction finder for scanning addresses")
+		}
+
+		if scanN > generateN {
+			scanN = scanN - generateN
+		}
+
+		if _, err := wlt.ScanAddresses(scanN, advOpts.TF); err != nil {
+			return nil, err
+		}
+	}
+
+	return wlt, nil
+}
+
+func validateMeta(m wallet.Meta) error {
+	if m[wallet.MetaType] != WalletType {
+		return wallet.ErrInvalidWalletType
+	}
+
+	if m[wallet.MetaSeed] != "" {
+		return wallet.NewError(fmt.Errorf("seed should not be provided for %q wallets", WalletType))
+	}
+
+	if m.Coin() != wallet.CoinTypeSkycoin {
+		return wallet.NewError(fmt.Errorf("%q wallets only support the %q coin type", WalletType, wallet.CoinTypeSkycoin))
+	}
+
+	pks, err := parsePubKeys(m.PubKeys())
+	if err != nil {
+		return err
+	}
+
+	t, err := strconv.ParseUint(m[wallet.MetaThreshold], 10, 64)
+	if err != nil {
+		return errors.New("invalid threshold")
+	}
+
+	if t == 0 || t > uint64(len(pks)) {
+		return ErrInvalidThreshold
+	}
+
+	return wallet.ValidateMeta(m)
+}
+
+// parsePubKeys decodes the hex public keys and validates them as a cosigner set
+func parsePubKeys(ss []string) ([]cipher.PubKey, error) {
+	pks := make([]cipher.PubKey, len(ss))
+	for i, s := range ss {
+		pk, err := cipher.PubKeyFromHex(s)
+		if err != nil {
+			return nil, wallet.NewError(fmt.Errorf("invalid cosigner public key %q: %v", s, err))
+		}
+		pks[i] = pk
+	}
+
+	return sortPubKeys(pks)
+}
+
+// sortPubKeys validates the cosigner public keys and returns a sorted copy,
+// so that the derived addresses do not depend on the order the keys were provided in.
+func sortPubKeys(pubKeys []cipher.PubKey) ([]cipher.PubKey, error) {
+	if len(pubKeys) == 0 {
+		return nil, ErrMissingPubKeys
+	}
+
+	if len(pubKeys) > MaxCosigners {
+		return nil, ErrTooManyPubKeys
+	}
+
+	pks := make([]cipher.PubKey, len(pubKeys))
+	copy(pks, pubKeys)
+	sort.Slice(pks, func(i, j int) bool {
+		return bytes.Compare(pks[i][:], pks[j][:]) < 0
+	})
+
+	for i, pk := range pks {
+		if err := pk.Verify(); err != nil {
+			return nil, wallet.NewError(fmt.Errorf("invalid cosigner public key %s: %v", pk.Hex(), err))
+		}
+
+		if i > 0 && pks[i-1] == pk {
+			return nil, ErrDuplicatePubKey
+		}
+	}
+
+	return pks, nil
+}
+
+func pubKeysToHex(pks []cipher.PubKey) []string {
+	ss := make([]string, len(pks))
+	for i, pk := range pks {
+		ss[i] = pk.Hex()
+	}
+	return ss
+}
+
+// CosignerPubKeys returns a copy of the sorted cosigner public keys
+func (w *Wallet) CosignerPubKeys() []cipher.PubKey {
+	pks := make([]cipher.PubKey, len(w.pubKeys))
+	copy(pks, w.pubKeys)
+	return pks
+}
+
+// AggregateKeys returns the MuSig2 threshold keys of the wallet, the aggregate keys of every subset
+// of threshold cosigners, see musig.ThresholdKeys. A N-of-N wallet has the single aggregate key
+// of all the cosigners, a 2-of-3 wallet has the 3 aggregate keys of the pairs of cosigners.
+// A signature of any threshold cosigners is verified with musig.VerifyThreshold.
+// The blockchain does not verify Schnorr signatures, so the aggregate signature can't spend coins,
+// transactions are still signed with a PartiallySignedTransaction.
+func (w *Wallet) AggregateKeys() ([]*musig.AggregateKey, error) {
+	keys, err := musig.ThresholdKeys(w.pubKeys, int(w.Threshold()))
+	if err != nil {
+		return nil, wallet.NewError(err)
+	}
+	return keys, nil
+}
+
+// AggregateKey returns the MuSig2 aggregate key of the threshold cosigners that sign, in any order.
+// The cosigners sign together with the musig package, producing a single Schnorr signature that
+// verifies against the aggregate key, which is one of the AggregateKeys of the wallet.
+func (w *Wallet) AggregateKey(signers []cipher.PubKey) (*musig.AggregateKey, error) {
+	if uint64(len(signers)) != w.Threshold() {
+		return nil, ErrMuSigSigners
+	}
+
+	sorted := musig.SortPubKeys(signers)
+	for i, pk := range sorted {
+		if i > 0 && sorted[i-1] == pk {
+			return nil, ErrMuSigSigners
+		}
+
+		if !w.isCosigner(pk) {
+			return nil, ErrNotCosigner
+		}
+	}
+
+	return musig.AggregatePubKeys(sorted)
+}
+
+func (w *Wallet) isCosigner(pk cipher.PubKey) bool {
+	for _, p := range w.pubKeys {
+		if p == pk {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDecoder sets the wallet decoder
+func (w *Wallet) SetDecoder(d wallet.Decoder) {
+	w.decoder = d
+}
+
+// Serialize encodes the multisig wallet to []byte
+func (w Wallet) Serialize() ([]byte, error) {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	return w.decoder.Encode(&w)
+}
+
+// Deserialize decodes the []byte to a multisig wallet
+func (w *Wallet) Deserialize(b []byte) error {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	toW, err := w.decoder.Decode(b)
+	if err != nil {
+		return err
+	}
+
+	toW2 := toW.(*Wallet)
+	toW2.decoder = w.decoder
+	*w = *toW2
+	return nil
+}
+
+// Lock is not supported, the multisig wallet has no secrets
+func (w *Wallet) Lock(_ []byte) error {
+	return wallet.NewError(errMultisigEncryption)
+}
+
+// Unlock is not supported, the multisig wallet has no secrets
+func (w *Wallet) Unlock(_ []byte) (wallet.Wallet, error) {
+	return nil, wallet.NewError(errMultisigEncryption)
+}
+
+// Fingerprint returns a unique ID fingerprint for this wallet,
+// the hash of the threshold and the sorted cosigner public keys
+func (w *Wallet) Fingerprint() string {
+	b := make([]byte, 0, 2+len(w.pubKeys)*len(cipher.PubKey{}))
+	b = append(b, byte(w.Threshold()), byte(len(w.pubKeys)))
+	for _, pk := range w.pubKeys {
+		b = append(b, pk[:]...)
+	}
+
+	return fmt.Sprintf("%s-%s", w.Type(), cipher.SumSHA256(b).Hex())
+}
+
+// Clone returns a copy of the wallet
+func (w *Wallet) Clone() wallet.Wallet {
+	return &Wallet{
+		Meta:    w.Meta.Clone(),
+		pubKeys: w.CosignerPubKeys(),
+		decoder: w.decoder,
+	}
+}
+
+// CopyFromRef copies the src wallet with a pointer dereference
+func (w *Wallet) CopyFromRef(src wallet.Wallet) {
+	*w = *(src.(*Wallet))
+}
+
+// Accounts is not implemented for multisig wallet
+func (w *Wallet) Accounts() []wallet.Bip44Account {
+	return nil
+}
+
+// Erase is a no-op, the multisig wallet has no sensitive data
+func (w *Wallet) Erase() {}
+
+// ScanAddresses returns ErrAddressesNotSpendable, the wallet does not generate addresses to scan
+func (w *Wallet) ScanAddresses(scanN uint64, _ wallet.TransactionsFinder) ([]cipher.Addresser, error) {
+	if scanN == 0 {
+		return nil, nil
+	}
+
+	return nil, ErrAddressesNotSpendable
+}
+
+// GenerateAddresses returns ErrAddressesNotSpendable, multisig addresses can't be spent
+// until the blockchain supports multisig outputs
+func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Addresser, error) {
+	if num == 0 {
+		return nil, nil
+	}
+
+	return nil, ErrAddressesNotSpendable
+}
+
+// GetAddresses returns no addresses, the wallet has no addresses
+func (w *Wallet) GetAddresses(_ ...wallet.Option) ([]cipher.Addresser, error) {
+	return nil, nil
+}
+
+// GetEntries returns no entries, the wallet has no addresses
+func (w *Wallet) GetEntries(_ ...wallet.Option) (wallet.Entries, error) {
+	return nil, nil
+}
+
+// GetEntryAt returns an error, the wallet has no entries
+func (w *Wallet) GetEntryAt(i int, _ ...wallet.Option) (wallet.Entry, error) {
+	return wallet.Entry{}, fmt.Errorf("entry index %d is out of range", i)
+}
+
+// GetEntry returns wallet.ErrEntryNotFound, the wallet has no entries
+func (w *Wallet) GetEntry(_ cipher.Addresser, _ ...wallet.Option) (wallet.Entry, error) {
+	return wallet.Entry{}, wallet.ErrEntryNotFound
+}
+
+// GetEntryMeta returns wallet.ErrEntryNotFound, the wallet has no entries
+func (w *Wallet) GetEntryMeta(_ cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+}
+
+// SetEntryMeta returns wallet.ErrEntryNotFound, the wallet has no entries
+func (w *Wallet) SetEntryMeta(_ cipher.Addresser, _ wallet.EntryMeta, _ ...wallet.Option) error {
+	return wallet.ErrEntryNotFound
+}
+
+// HasEntry returns false, the wallet has no entries
+func (w *Wallet) HasEntry(_ cipher.Addresser, _ ...wallet.Option) (bool, error) {
+	return false, nil
+}
+
+// EntriesLen returns 0, the wallet has no entries
+func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
+	return 0, nil
+}
+
+// Loader implements the wallet.Loader interface
+type Loader struct{}
+
+// Load loads the multisig wallet from byte slice
+func (l Loader) Load(data []byte) (wallet.Wallet, error) {
+	w := &Wallet{}
+	if err := w.Deserialize(data); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Creator implements the wallet.Creator interface
+type Creator struct{}
+
+// Create creates a multisig wallet
+func (c Creator) Create(filename, label, _ string, options wallet.Options) (wallet.Wallet, error) {
+	if options.Encrypt {
+		return nil, wallet.NewError(errMultisigEncryption)
+	}
+
+	pks, err := parsePubKeys(options.PubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWallet(filename, label, options.Threshold, pks, convertOptions(options)...)
+}
+
+func convertOptions(options wallet.Options) []wallet.Option {
+	var opts []wallet.Option
+
+	if options.Coin != "" {
+		opts = append(opts, wallet.OptionCoinType(options.Coin))
+	}
+
+	if options.Decoder != nil {
+		opts = append(opts, wallet.OptionDecoder(options.Decoder))
+	}
+
+	if options.GenerateN > 0 {
+		opts = append(opts, wallet.OptionGenerateN(options.GenerateN))
+	}
+
+	if options.ScanN > 0 {
+		opts = append(opts, wallet.OptionScanN(options.ScanN))
+		opts = append(opts, wallet.OptionTransactionsFinder(options.TF))
+	}
+
+	return opts
+}
@@ -0,0 +1,300 @@
+package multisig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/musig"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func makeCosigners(t *testing.T, n int) ([]cipher.PubKey, []cipher.SecKey) {
+	pks := make([]cipher.PubKey, n)
+	sks := make([]cipher.SecKey, n)
+	for i := 0; i < n; i++ {
+		pk, sk, err := cipher.GenerateDeterministicKeyPair([]byte{byte(i + 1)})
+		require.NoError(t, err)
+		pks[i], sks[i] = pk, sk
+	}
+	return pks, sks
+}
+
+func TestNewWallet(t *testing.T) {
+	pks, _ := makeCosigners(t, 3)
+
+	tt := []struct {
+		name      string
+		threshold uint64
+		pubKeys   []cipher.PubKey
+		opts      []wallet.Option
+		err       error
+	}{
+		{
+			name:      "2-of-3",
+			threshold: 2,
+			pubKeys:   pks,
+		},
+		{
+			name:      "3-of-3",
+			threshold: 3,
+			pubKeys:   pks,
+		},
+		{
+			name:      "generate addresses not supported",
+			threshold: 2,
+			pubKeys:   pks,
+			opts:      []wallet.Option{wallet.OptionGenerateN(5)},
+			err:       ErrAddressesNotSpendable,
+		},
+		{
+			name:      "missing pubkeys",
+			threshold: 1,
+			err:       ErrMissingPubKeys,
+		},
+		{
+			name:      "threshold 0",
+			threshold: 0,
+			pubKeys:   pks,
+			err:       ErrInvalidThreshold,
+		},
+		{
+			name:      "threshold too large",
+			threshold: 4,
+			pubKeys:   pks,
+			err:       ErrInvalidThreshold,
+		},
+		{
+			name:      "duplicate pubkey",
+			threshold: 2,
+			pubKeys:   []cipher.PubKey{pks[0], pks[1], pks[0]},
+			err:       ErrDuplicatePubKey,
+		},
+		{
+			name:      "encrypt not supported",
+			threshold: 2,
+			pubKeys:   pks,
+			opts:      []wallet.Option{wallet.OptionEncrypt(true), wallet.OptionPassword([]byte("pwd"))},
+			err:       wallet.NewError(errMultisigEncryption),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewWallet("test.wlt", "test", tc.threshold, tc.pubKeys, tc.opts...)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, WalletType, w.Type())
+			require.Equal(t, tc.threshold, w.Threshold())
+			require.Len(t, w.CosignerPubKeys(), len(tc.pubKeys))
+			require.False(t, w.IsEncrypted())
+		})
+	}
+}
+
+func TestWalletFingerprint(t *testing.T) {
+	pks, _ := makeCosigners(t, 3)
+
+	w1, err := NewWallet("a.wlt", "a", 2, []cipher.PubKey{pks[0], pks[1], pks[2]})
+	require.NoError(t, err)
+	w2, err := NewWallet("b.wlt", "b", 2, []cipher.PubKey{pks[2], pks[0], pks[1]})
+	require.NoError(t, err)
+	require.Equal(t, w1.Fingerprint(), w2.Fingerprint())
+
+	// A different threshold or cosigner set has a different fingerprint
+	w3, err := NewWallet("c.wlt", "c", 3, pks)
+	require.NoError(t, err)
+	require.NotEqual(t, w1.Fingerprint(), w3.Fingerprint())
+
+	w4, err := NewWallet("d.wlt", "d", 2, pks[:2])
+	require.NoError(t, err)
+	require.NotEqual(t, w1.Fingerprint(), w4.Fingerprint())
+}
+
+func TestWalletGenerateAddresses(t *testing.T) {
+	pks, _ := makeCosigners(t, 3)
+
+	w, err := NewWallet("a.wlt", "a", 2, pks)
+	require.NoError(t, err)
+
+	// Multisig addresses are not spendable, no receive address is generated
+	addrs, err := w.GenerateAddresses(1)
+	require.Equal(t, ErrAddressesNotSpendable, err)
+	require.Empty(t, addrs)
+
+	_, err = w.ScanAddresses(5, nil)
+	require.Equal(t, ErrAddressesNotSpendable, err)
+
+	n, err := w.EntriesLen()
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	addrs, err = w.GenerateAddresses(0)
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+}
+
+func TestWalletAggregateKey(t *testing.T) {
+	pks, sks := makeCosigners(t, 3)
+
+	// A 2-of-3 wallet has the aggregate keys of the 3 pairs of cosigners
+	w, err := NewWallet("a.wlt", "a", 2, []cipher.PubKey{pks[2], pks[0], pks[1]})
+	require.NoError(t, err)
+	keys, err := w.AggregateKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	_, err = w.AggregateKey(pks)
+	require.Equal(t, ErrMuSigSigners, err)
+	_, err = w.AggregateKey([]cipher.PubKey{pks[0], pks[0]})
+	require.Equal(t, ErrMuSigSigners, err)
+	other, _ := cipher.GenerateKeyPair()
+	_, err = w.AggregateKey([]cipher.PubKey{pks[0], other})
+	require.Equal(t, ErrNotCosigner, err)
+
+	// The first and third cosigners sign with MuSig2, producing a single signature
+	hash := cipher.SumSHA256([]byte("multisig"))
+	signers := []cipher.SecKey{sks[2], sks[0]}
+	aggKey, err := w.AggregateKey([]cipher.PubKey{pks[2], pks[0]})
+	require.NoError(t, err)
+
+	secNonces := make([]*musig.SecNonce, len(signers))
+	pubNonces := make([]musig.PubNonce, len(signers))
+	for i, sk := range signers {
+		secNonces[i], pubNonces[i], err = musig.NewNonce(sk, aggKey, hash)
+		require.NoError(t, err)
+	}
+
+	aggNonce, err := musig.AggregateNonces(pubNonces)
+	require.NoError(t, err)
+	session, err := musig.NewSession(aggKey, aggNonce, hash)
+	require.NoError(t, err)
+
+	psigs := make([]musig.PartialSig, len(signers))
+	for i, sk := range signers {
+		psigs[i], err = session.Sign(secNonces[i], sk)
+		require.NoError(t, err)
+	}
+
+	sig, err := session.AggregatePartialSigs(psigs)
+	require.NoError(t, err)
+	require.NoError(t, cipher.VerifySchnorrSignedHash(aggKey.SchnorrPubKey(), sig, hash))
+
+	// The signature verifies against the threshold keys of the wallet
+	k, err := musig.VerifyThreshold(keys, sig, hash)
+	require.NoError(t, err)
+	require.Equal(t, aggKey.PubKeys(), k.PubKeys())
+
+	// A 3-of-3 wallet has a single aggregate key of all the cosigners
+	w, err = NewWallet("b.wlt", "b", 3, pks)
+	require.NoError(t, err)
+	keys, err = w.AggregateKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, w.CosignerPubKeys(), keys[0].PubKeys())
+	_, err = musig.VerifyThreshold(keys, sig, hash)
+	require.Equal(t, musig.ErrNoThresholdKey, err)
+}
+
+func TestWalletSerializeDeserialize(t *testing.T) {
+	pks, _ := makeCosigners(t, 3)
+
+	w, err := NewWallet("test.wlt", "test", 2, pks)
+	require.NoError(t, err)
+
+	b, err := w.Serialize()
+	require.NoError(t, err)
+
+	w2, err := Loader{}.Load(b)
+	require.NoError(t, err)
+	require.Equal(t, w.Meta, w2.(*Wallet).Meta)
+	require.Equal(t, w.CosignerPubKeys(), w2.(*Wallet).CosignerPubKeys())
+
+	// A threshold larger than the number of cosigners is invalid
+	w.SetThreshold(4)
+	b, err = w.Serialize()
+	require.NoError(t, err)
+	_, err = Loader{}.Load(b)
+	require.Error(t, err)
+}
+
+func makePartialTransaction(t *testing.T, w *Wallet) *PartiallySignedTransaction {
+	var txn coin.Transaction
+	for i := 0; i < 2; i++ {
+		require.NoError(t, txn.PushInput(testutil.RandSHA256(t)))
+	}
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 2e6, 50))
+	require.NoError(t, txn.UpdateHeader())
+
+	p, err := w.NewPartiallySignedTransaction(txn)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPartiallySignedTransaction(t *testing.T) {
+	pks, sks := makeCosigners(t, 3)
+
+	w, err := NewWallet("test.wlt", "test", 2, pks)
+	require.NoError(t, err)
+
+	_, err = w.NewPartiallySignedTransaction(coin.Transaction{})
+	require.Equal(t, wallet.NewError(errors.New("No transaction inputs to sign")), err)
+
+	p1 := makePartialTransaction(t, w)
+	require.NoError(t, p1.Verify())
+	require.False(t, p1.IsComplete())
+	require.Len(t, p1.Inputs, 2)
+
+	// Non-cosigners cannot sign
+	_, sk := cipher.GenerateKeyPair()
+	_, err = p1.Sign(sk)
+	require.Equal(t, ErrNotCosigner, err)
+
+	// The first cosigner signs and passes the transaction to the second cosigner
+	n, err := p1.Sign(sks[0])
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.False(t, p1.IsComplete())
+
+	// Signing twice is a no-op
+	n, err = p1.Sign(sks[0])
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	b, err := p1.Serialize()
+	require.NoError(t, err)
+	p2, err := DeserializePartiallySignedTransaction(b)
+	require.NoError(t, err)
+	require.Equal(t, p1.Inputs, p2.Inputs)
+	require.Equal(t, p1.Transaction.Hash(), p2.Transaction.Hash())
+
+	_, err = p2.Sign(sks[2])
+	require.NoError(t, err)
+	require.True(t, p2.IsComplete())
+	require.Equal(t, 2, p2.SignatureCount(0))
+
+	// The first cosigner combines the signatures back
+	require.NoError(t, p1.Combine(p2))
+	require.True(t, p1.IsComplete())
+	require.Equal(t, p1.Inputs, p2.Inputs)
+
+	// Combining a different transaction fails
+	p3 := makePartialTransaction(t, w)
+	require.Equal(t, ErrPartialTransactionMismatch, p1.Combine(p3))
+
+	// Corrupted signatures fail verification
+	for j, sig := range p2.Inputs[0].Sigs {
+		if !sig.Null() {
+			p2.Inputs[0].Sigs[j] = p2.Inputs[1].Sigs[j]
+			break
+		}
+	}
+	require.Error(t, p2.Verify())
+}
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
@@ -62,6 +63,32 @@ func OptionExternal() Option {
 	}
 }
 
+// GenerateOptions represents the options of generating addresses
+type GenerateOptions struct {
+	// Workers is the number of goroutines deriving the addresses, one per CPU if 0
+	Workers int
+}
+
+// NewGenerateOptions returns the generate options set by the options
+func NewGenerateOptions(options ...Option) GenerateOptions {
+	var o GenerateOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+	return o
+}
+
+// OptionWorkers is the option type for setting the number of goroutines deriving the addresses
+func OptionWorkers(n int) Option {
+	return func(opts interface{}) {
+		o, ok := opts.(*GenerateOptions)
+		if !ok {
+			return
+		}
+		o.Workers = n
+	}
+}
+
 func walletOptionFunc(f func(Wallet)) Option {
 	return func(v interface{}) {
 		w, ok := v.(Wallet)
@@ -79,6 +106,14 @@ func OptionCryptoType(cryptoType crypto.CryptoType) Option {
 	})
 }
 
+// OptionArgon2Params is the option type for setting the argon2id iterations and memory size in KiB,
+// used by the argon2id-chacha20poly1305 crypto type
+func OptionArgon2Params(iterations, memory uint32) Option {
+	return walletOptionFunc(func(w Wallet) {
+		w.SetArgon2Params(iterations, memory)
+	})
+}
+
 // OptionCoinType is the option type for setting  wallet coin  type
 func OptionCoinType(coinType CoinType) Option {
 	return walletOptionFunc(func(w Wallet) {
@@ -100,6 +135,34 @@ func OptionBip44Coin(ct *bip44.CoinType) Option {
 	})
 }
 
+// OptionBip44PathTemplate is the option type for setting the bip32 derivation path template for bip44 wallet
+func OptionBip44PathTemplate(t string) Option {
+	return walletOptionFunc(func(w Wallet) {
+		w.SetBip44PathTemplate(t)
+	})
+}
+
+// OptionSeedLanguage is the option type for setting the bip39 wordlist language of the seed for bip44 wallet
+func OptionSeedLanguage(l bip39.Language) Option {
+	return walletOptionFunc(func(w Wallet) {
+		w.SetSeedLanguage(l)
+	})
+}
+
+// OptionXPubAccount is the option type for marking the xpub of xpub wallet as a bip44 account key
+func OptionXPubAccount(account bool) Option {
+	return walletOptionFunc(func(w Wallet) {
+		w.SetXPubAccount(account)
+	})
+}
+
+// OptionAddressEncoding is the option type for setting the encoding of the generated addresses
+func OptionAddressEncoding(enc AddressEncoding) Option {
+	return walletOptionFunc(func(w Wallet) {
+		w.SetAddressEncoding(enc)
+	})
+}
+
 // AdvancedOptions are advanced options that can be used when creating a new wallet
 type AdvancedOptions struct {
 	DefaultBip44AccountName string
@@ -107,6 +170,7 @@ type AdvancedOptions struct {
 	Password                []byte
 	GenerateN               uint64
 	ScanN                   uint64
+	GapLimit                uint64
 	TF                      TransactionsFinder
 }
 
@@ -151,6 +215,13 @@ func OptionScanN(n uint64) Option {
 	})
 }
 
+// OptionGapLimit can be used to set the gap limit of scanning all used addresses when creating a new bip44 wallet
+func OptionGapLimit(n uint64) Option {
+	return advancedOptionFunc(func(opts *AdvancedOptions) {
+		opts.GapLimit = n
+	})
+}
+
 // OptionTransactionsFinder can be used to set the transactions finder when creating a new wallet
 func OptionTransactionsFinder(tf TransactionsFinder) Option {
 	return advancedOptionFunc(func(opts *AdvancedOptions) {
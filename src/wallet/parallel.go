@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelThreshold is the number of addresses from which the wallets derive
+// the addresses with a pool of workers, fewer addresses are derived sequentially
+const ParallelThreshold = 256
+
+// ParallelDo calls f for every index in [0, n), the calls are spread over a pool of workers goroutines.
+// f must only write the results of index i, at index i, so that the results are in order
+// regardless of the scheduling. workers is the number of goroutines, one per CPU if 0.
+// If n is below ParallelThreshold f is called sequentially.
+// Returns the error of the lowest failing index.
+func ParallelDo(n, workers int, f func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if n < ParallelThreshold || workers == 1 {
+		for i := 0; i < n; i++ {
+			if err := f(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	errs := make([]error, n)
+	indexC := make(chan int, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexC {
+				errs[i] = f(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexC <- i
+	}
+	close(indexC)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelDo(t *testing.T) {
+	tt := []struct {
+		name    string
+		n       int
+		workers int
+		fail    []int
+		err     error
+	}{
+		{
+			name: "none",
+			n:    0,
+		},
+		{
+			name: "sequential below threshold",
+			n:    ParallelThreshold - 1,
+		},
+		{
+			name:    "one worker",
+			n:       ParallelThreshold * 2,
+			workers: 1,
+		},
+		{
+			name:    "workers",
+			n:       ParallelThreshold * 2,
+			workers: 4,
+		},
+		{
+			name: "workers per cpu",
+			n:    ParallelThreshold * 2,
+		},
+		{
+			name:    "lowest failing index",
+			n:       ParallelThreshold * 2,
+			workers: 4,
+			fail:    []int{ParallelThreshold + 3, 10, ParallelThreshold},
+			err:     errors.New("index 10 failed"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fail := make(map[int]bool)
+			for _, i := range tc.fail {
+				fail[i] = true
+			}
+
+			results := make([]int, tc.n)
+			err := ParallelDo(tc.n, tc.workers, func(i int) error {
+				if fail[i] {
+					return fmt.Errorf("index %d failed", i)
+				}
+				results[i] = i * i
+				return nil
+			})
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			for i, r := range results {
+				require.Equal(t, i*i, r)
+			}
+		})
+	}
+}
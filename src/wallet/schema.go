@@ -0,0 +1,65 @@
+package wallet
+
+// FileSchemaV3 is the JSON Schema of the v3 wallet file format.
+// The wallet type specific sections, e.g. entries or accounts, are not constrained.
+const FileSchemaV3 = `{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "https://skycoin.com/schemas/wallet-v3.json",
+    "title": "Skycoin wallet file v3",
+    "type": "object",
+    "required": ["formatVersion", "meta"],
+    "properties": {
+        "formatVersion": {
+            "const": 3
+        },
+        "meta": {
+            "type": "object",
+            "required": ["filename", "type", "version", "coin"],
+            "properties": {
+                "version": {"type": "string"},
+                "filename": {"type": "string"},
+                "label": {"type": "string"},
+                "tm": {"type": "integer"},
+                "type": {"type": "string"},
+                "coin": {"type": "string"},
+                "encrypted": {"type": "boolean"},
+                "cryptoType": {"type": "string"},
+                "seed": {"type": "string"},
+                "lastSeed": {"type": "string"},
+                "secrets": {"type": "string"},
+                "duressSecrets": {"type": "string"},
+                "bip44Coin": {"type": "integer", "minimum": 0},
+                "bip44PathTemplate": {"type": "string"},
+                "accountsHash": {"type": "string"},
+                "seedPassphrase": {"type": "string"},
+                "seedLanguage": {"type": "string"},
+                "seedEntropy": {"type": "string"},
+                "xpub": {"type": "string"},
+                "xpubAccount": {"type": "boolean"},
+                "addressEncoding": {"type": "string", "enum": ["base58", "bech32"]},
+                "pubKeys": {"type": "array", "items": {"type": "string"}},
+                "threshold": {"type": "integer", "minimum": 0},
+                "device": {"type": "string"},
+                "signer": {"type": "string"},
+                "derivationPath": {"type": "string"},
+                "argon2Iterations": {"type": "integer", "minimum": 0},
+                "argon2Memory": {"type": "integer", "minimum": 0},
+                "restoredFromShares": {"type": "boolean"},
+                "sharesIdentifier": {"type": "integer", "minimum": 0, "maximum": 65535},
+                "changePolicy": {"type": "string", "enum": ["reuseFirst", "changeChain", "fresh", "address"]},
+                "changeAddress": {"type": "string"},
+                "readOnly": {"type": "boolean"},
+                "migratedTo": {"type": "string"},
+                "dailySpendLimit": {"type": "integer", "minimum": 0},
+                "requirePassword": {"type": "boolean"},
+                "spentDay": {"type": "string", "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}$"},
+                "spent": {"type": "integer", "minimum": 0}
+            },
+            "additionalProperties": {"type": "string"}
+        },
+        "extensions": {
+            "type": "object",
+            "description": "Third-party data keyed by extension name, preserved when the wallet is loaded and saved"
+        }
+    }
+}`
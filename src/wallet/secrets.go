@@ -2,6 +2,9 @@ package wallet
 
 import (
 	"encoding/json"
+
+	"github.com/skycoin/skycoin/src/cipher/secret"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
 
 const (
@@ -44,3 +47,35 @@ func (s Secrets) Erase() {
 		delete(s, k)
 	}
 }
+
+// Encrypt serializes the secrets and encrypts them with the password,
+// the serialized secrets are only held in a secret.Buffer
+func (s Secrets) Encrypt(c crypto.Cryptor, password []byte) ([]byte, error) {
+	b, err := s.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := secret.FromBytes(b)
+	defer buf.Destroy()
+
+	return c.Encrypt(buf.Bytes(), password)
+}
+
+// DecryptSecrets decrypts the secrets data with the password,
+// the decrypted data is only held in a secret.Buffer
+func DecryptSecrets(c crypto.Cryptor, data, password []byte) (Secrets, error) {
+	b, err := c.Decrypt(data, password)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	buf := secret.FromBytes(b)
+	defer buf.Destroy()
+
+	ss := make(Secrets)
+	if err := ss.Deserialize(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
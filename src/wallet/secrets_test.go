@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
 
 func TestSecrets(t *testing.T) {
@@ -27,3 +29,22 @@ func TestSecrets(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, s, s1)
 }
+
+func TestSecretsEncrypt(t *testing.T) {
+	s := make(Secrets)
+	s.Set(SecretSeed, "seed")
+	s.Set(SecretLastSeed, "last seed")
+
+	c, err := crypto.GetCrypto(crypto.CryptoTypeSha256Xor)
+	require.NoError(t, err)
+
+	b, err := s.Encrypt(c, []byte("pwd"))
+	require.NoError(t, err)
+
+	s1, err := DecryptSecrets(c, b, []byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, s, s1)
+
+	_, err = DecryptSecrets(c, b, []byte("wrong"))
+	require.Equal(t, ErrInvalidPassword, err)
+}
@@ -0,0 +1,160 @@
+package wallet
+
+import (
+	"github.com/skycoin/skycoin/src/cipher/secret"
+)
+
+// Seeds holds the seed, last seed and seed passphrase of a wallet in secret.Buffers.
+// The zero Seeds has no seeds, it is embedded by the wallet types that aren't derived from a seed.
+// The copies of a Seeds share its buffers, Clone copies them to new buffers and Erase destroys them.
+//
+// The Meta of a wallet keeps the seed keys with empty values, so that the wallet
+// file has the same fields, the seeds are set to them when the wallet is serialized.
+type Seeds struct {
+	seed           *secret.Buffer
+	lastSeed       *secret.Buffer
+	seedPassphrase *secret.Buffer
+}
+
+// SeedsFromMeta moves the seed, last seed and seed passphrase of the Meta to a Seeds,
+// the keys of the Meta are kept with empty values
+func SeedsFromMeta(m Meta) Seeds {
+	var s Seeds
+	s.seed = takeMetaSecret(m, MetaSeed)
+	s.lastSeed = takeMetaSecret(m, MetaLastSeed)
+	s.seedPassphrase = takeMetaSecret(m, MetaSeedPassphrase)
+	return s
+}
+
+// ToMeta sets the seeds to the seed keys of the Meta, to serialize the wallet
+func (s Seeds) ToMeta(m Meta) {
+	putMetaSecret(m, MetaSeed, s.seed)
+	putMetaSecret(m, MetaLastSeed, s.lastSeed)
+	putMetaSecret(m, MetaSeedPassphrase, s.seedPassphrase)
+}
+
+// Seed returns a copy of the seed as a string, to export or encrypt it. The string
+// stays on the Go heap, the seed is read with SeedBytes to derive the keys
+func (s Seeds) Seed() string {
+	return string(bufferBytes(s.seed))
+}
+
+// LastSeed returns a copy of the last seed as a string, to export or encrypt it
+func (s Seeds) LastSeed() string {
+	return string(bufferBytes(s.lastSeed))
+}
+
+// SeedPassphrase returns a copy of the seed passphrase as a string, to export or encrypt it
+func (s Seeds) SeedPassphrase() string {
+	return string(bufferBytes(s.seedPassphrase))
+}
+
+// SeedBytes returns the seed, which is only valid until the seeds are erased
+func (s Seeds) SeedBytes() []byte {
+	return bufferBytes(s.seed)
+}
+
+// LastSeedBytes returns the last seed, which is only valid until the seeds are erased
+func (s Seeds) LastSeedBytes() []byte {
+	return bufferBytes(s.lastSeed)
+}
+
+// SeedPassphraseBytes returns the seed passphrase, which is only valid until the seeds are erased
+func (s Seeds) SeedPassphraseBytes() []byte {
+	return bufferBytes(s.seedPassphrase)
+}
+
+// SetSeed copies the seed to a secret.Buffer
+func (s *Seeds) SetSeed(seed string) {
+	replaceBuffer(&s.seed, stringBuffer(seed))
+}
+
+// SetLastSeed copies the last seed to a secret.Buffer
+func (s *Seeds) SetLastSeed(lastSeed string) {
+	replaceBuffer(&s.lastSeed, stringBuffer(lastSeed))
+}
+
+// SetLastSeedBytes copies the last seed to a secret.Buffer, lastSeed is not wiped
+func (s *Seeds) SetLastSeedBytes(lastSeed []byte) {
+	replaceBuffer(&s.lastSeed, bytesBuffer(lastSeed))
+}
+
+// SetSeedPassphrase copies the seed passphrase to a secret.Buffer
+func (s *Seeds) SetSeedPassphrase(p string) {
+	replaceBuffer(&s.seedPassphrase, stringBuffer(p))
+}
+
+// Clone copies the seeds to new secret.Buffers
+func (s Seeds) Clone() Seeds {
+	return Seeds{
+		seed:           cloneBuffer(s.seed),
+		lastSeed:       cloneBuffer(s.lastSeed),
+		seedPassphrase: cloneBuffer(s.seedPassphrase),
+	}
+}
+
+// Erase wipes and frees the seeds
+func (s *Seeds) Erase() {
+	replaceBuffer(&s.seed, nil)
+	replaceBuffer(&s.lastSeed, nil)
+	replaceBuffer(&s.seedPassphrase, nil)
+}
+
+// takeMetaSecret moves the value of the key of the Meta to a secret.Buffer, the key
+// is kept with an empty value if the Meta has it. The string of the value stays on the Go
+// heap until it's garbage collected, it's the one decoded from the wallet file or set by the
+// options, which the caller holds anyway.
+func takeMetaSecret(m Meta, k string) *secret.Buffer {
+	v, ok := m[k]
+	if !ok {
+		return nil
+	}
+	m[k] = ""
+	return stringBuffer(v)
+}
+
+// putMetaSecret sets the secret to the key of the Meta, if the Meta has the key or the secret is not empty
+func putMetaSecret(m Meta, k string, b *secret.Buffer) {
+	if _, ok := m[k]; ok || len(bufferBytes(b)) > 0 {
+		m[k] = string(bufferBytes(b))
+	}
+}
+
+// stringBuffer copies s to a secret.Buffer, an empty s returns nil
+func stringBuffer(s string) *secret.Buffer {
+	if s == "" {
+		return nil
+	}
+	b := secret.New(len(s))
+	copy(b.Bytes(), s)
+	return b
+}
+
+// bytesBuffer copies p to a secret.Buffer, an empty p returns nil
+func bytesBuffer(p []byte) *secret.Buffer {
+	if len(p) == 0 {
+		return nil
+	}
+	b := secret.New(len(p))
+	copy(b.Bytes(), p)
+	return b
+}
+
+func bufferBytes(b *secret.Buffer) []byte {
+	if b == nil {
+		return nil
+	}
+	return b.Bytes()
+}
+
+func cloneBuffer(b *secret.Buffer) *secret.Buffer {
+	return bytesBuffer(bufferBytes(b))
+}
+
+// replaceBuffer destroys the buffer *p and replaces it with b
+func replaceBuffer(p **secret.Buffer, b *secret.Buffer) {
+	if *p != nil {
+		(*p).Destroy()
+	}
+	*p = b
+}
@@ -0,0 +1,68 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeeds(t *testing.T) {
+	m := Meta{
+		MetaSeed:     "fooseed",
+		MetaLastSeed: "foolastseed",
+		MetaLabel:    "foo",
+	}
+
+	s := SeedsFromMeta(m)
+	require.Equal(t, Meta{
+		MetaSeed:     "",
+		MetaLastSeed: "",
+		MetaLabel:    "foo",
+	}, m)
+	require.Equal(t, "fooseed", s.Seed())
+	require.Equal(t, []byte("foolastseed"), s.LastSeedBytes())
+	require.Empty(t, s.SeedPassphrase())
+
+	// The seeds are set to the keys of the meta, the seed passphrase is empty and not added
+	m2 := m.Clone()
+	s.ToMeta(m2)
+	require.Equal(t, Meta{
+		MetaSeed:     "fooseed",
+		MetaLastSeed: "foolastseed",
+		MetaLabel:    "foo",
+	}, m2)
+
+	// The clone has its own buffers
+	c := s.Clone()
+	require.True(t, &s.seed.Bytes()[0] != &c.seed.Bytes()[0])
+	c.SetSeed("barseed")
+	c.SetLastSeedBytes(c.SeedBytes())
+	c.SetSeedPassphrase("bar")
+	require.Equal(t, "fooseed", s.Seed())
+	require.Equal(t, "barseed", c.Seed())
+	require.Equal(t, "barseed", c.LastSeed())
+	require.Equal(t, "bar", c.SeedPassphrase())
+
+	// Erase wipes the buffers, the small buffers are pool slots that stay mapped
+	seed := s.SeedBytes()
+	s.Erase()
+	require.Empty(t, s.Seed())
+	require.Empty(t, s.LastSeed())
+	require.Nil(t, s.seed)
+	require.Equal(t, make([]byte, len(seed)), seed)
+	require.Equal(t, "barseed", c.Seed())
+
+	// An erased seeds is serialized with empty keys
+	s.ToMeta(m2)
+	require.Equal(t, Meta{
+		MetaSeed:     "",
+		MetaLastSeed: "",
+		MetaLabel:    "foo",
+	}, m2)
+
+	// The zero Seeds has no seeds
+	var z Seeds
+	require.Empty(t, z.Seed())
+	require.Nil(t, z.SeedBytes())
+	z.Erase()
+}
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -8,11 +9,15 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
+	"github.com/skycoin/skycoin/src/cipher/slip39"
+	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/util/file"
 	"github.com/skycoin/skycoin/src/wallet/crypto"
 )
@@ -25,10 +30,16 @@ type TransactionsFinder interface {
 // Service wallet service struct
 type Service struct {
 	sync.RWMutex
-	wallets Wallets
+	wallets *walletStore
 	config  Config
 	// fingerprints is used to check for duplicate deterministic wallets
 	fingerprints map[string]string
+	// dirLock is the advisory lock of the wallet directory
+	dirLock *DirLock
+	// events delivers the wallet events to the subscribers
+	events *eventBus
+	// sessions are the unlock sessions of the encrypted wallets, keyed by wallet id
+	sessions map[string]*unlockSession
 }
 
 // Config wallet service config
@@ -55,8 +66,11 @@ func NewConfig() Config {
 // NewService new wallet service
 func NewService(c Config) (*Service, error) {
 	serv := &Service{
+		wallets:      newWalletStore(c.WalletDir),
 		config:       c,
 		fingerprints: make(map[string]string),
+		events:       newEventBus(),
+		sessions:     make(map[string]*unlockSession),
 	}
 
 	if !serv.config.EnableWalletAPI {
@@ -67,38 +81,105 @@ func NewService(c Config) (*Service, error) {
 		return nil, fmt.Errorf("failed to create wallet directory %s: %v", c.WalletDir, err)
 	}
 
+	// Locks the wallet directory so that other processes can not load and save the wallets
+	dirLock, err := Lock(c.WalletDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock wallet directory %s: %v", c.WalletDir, err)
+	}
+
+	w, err := serv.initWallets()
+	if err != nil {
+		dirLock.Unlock() //nolint:errcheck
+		return nil, err
+	}
+
+	serv.dirLock = dirLock
+	serv.setWallets(w)
+
+	fields := logrus.Fields{
+		"walletDir": serv.config.WalletDir,
+	}
+	if serv.config.Bip44Coin != nil {
+		fields["bip44Coin"] = *serv.config.Bip44Coin
+	}
+	logger.WithFields(fields).Debug("wallet.NewService complete")
+
+	return serv, nil
+}
+
+func (serv *Service) initWallets() (*walletStore, error) {
 	// Removes .wlt.bak files before loading wallets
 	if err := removeBackupFiles(serv.config.WalletDir); err != nil {
 		return nil, fmt.Errorf("remove .wlt.bak files in %v failed: %v", serv.config.WalletDir, err)
 	}
 
-	// Load all wallets from disk
-	w, err := serv.loadWallets()
+	// Load the wallet index and the wallets that are not indexed from disk
+	s, err := serv.loadWallets()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load all wallets: %v", err)
 	}
 
+	entries := s.entries()
+
 	// Abort if there are duplicate wallets (identified by fingerprint) on disk
-	if wltID, fp, hasDup := w.containsDuplicate(); hasDup {
-		return nil, fmt.Errorf("duplicate wallet found with fingerprint %s in file %q", fp, wltID)
+	fps := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if e.Fingerprint == "" {
+			continue
+		}
+
+		if _, ok := fps[e.Fingerprint]; ok {
+			return nil, fmt.Errorf("duplicate wallet found with fingerprint %s in file %q", e.Fingerprint, e.Filename)
+		}
+		fps[e.Fingerprint] = struct{}{}
 	}
 
-	// Abort if there are empty deterministic wallets on disk
-	if wltID, hasEmpty := w.containsEmpty(); hasEmpty {
-		return nil, fmt.Errorf("empty wallet file found: %q", wltID)
+	// Abort if there are empty deterministic wallets on disk.
+	// Does not apply to collection wallets
+	for _, e := range entries {
+		if e.Type != WalletTypeCollection && e.AddressCount == 0 {
+			return nil, fmt.Errorf("empty wallet file found: %q", e.Filename)
+		}
 	}
 
-	serv.setWallets(w)
+	return s, nil
+}
 
-	fields := logrus.Fields{
-		"walletDir": serv.config.WalletDir,
+// LockWalletDir takes the advisory lock of the wallet directory, which is
+// taken by NewService and held until UnlockWalletDir is called.
+// Returns ErrWalletDirLocked if the directory is locked by another process.
+func (serv *Service) LockWalletDir() error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
 	}
-	if serv.config.Bip44Coin != nil {
-		fields["bip44Coin"] = *serv.config.Bip44Coin
+
+	if serv.dirLock != nil {
+		return nil
 	}
-	logger.WithFields(fields).Debug("wallet.NewService complete")
 
-	return serv, nil
+	l, err := Lock(serv.config.WalletDir)
+	if err != nil {
+		return err
+	}
+
+	serv.dirLock = l
+	return nil
+}
+
+// UnlockWalletDir releases the advisory lock of the wallet directory,
+// it should be called when the service is shut down.
+func (serv *Service) UnlockWalletDir() error {
+	serv.Lock()
+	defer serv.Unlock()
+
+	if err := serv.dirLock.Unlock(); err != nil {
+		return err
+	}
+
+	serv.dirLock = nil
+	return nil
 }
 
 // WalletDir returns the configured wallet directory
@@ -116,7 +197,9 @@ func (serv *Service) SetEnableWalletAPI(enable bool) {
 	serv.config.EnableWalletAPI = enable
 }
 
-func (serv *Service) loadWallets() (Wallets, error) {
+// loadWallets loads the wallet index and the wallet files that are not indexed,
+// or that changed since they were indexed. The other wallets are loaded when they are first used.
+func (serv *Service) loadWallets() (*walletStore, error) {
 	dir := serv.config.WalletDir
 	entries, err := ioutil.ReadDir(dir)
 	if err != nil {
@@ -124,7 +207,13 @@ func (serv *Service) loadWallets() (Wallets, error) {
 		return nil, err
 	}
 
-	wallets := Wallets{}
+	index, err := loadIndex(dir)
+	if err != nil {
+		logger.WithError(err).WithField("dir", dir).Warning("loadWallets: invalid wallet index, rebuilding it")
+		index = make(map[string]IndexEntry)
+	}
+
+	s := newWalletStore(dir)
 	for _, e := range entries {
 		if e.Mode().IsRegular() {
 			name := e.Name()
@@ -133,6 +222,11 @@ func (serv *Service) loadWallets() (Wallets, error) {
 				continue
 			}
 
+			if ie, ok := index[name]; ok && ie.isCurrent(e) {
+				s.index[name] = ie
+				continue
+			}
+
 			fullPath := filepath.Join(serv.config.WalletDir, name)
 			w, err := serv.Load(fullPath)
 			if err != nil {
@@ -147,19 +241,26 @@ func (serv *Service) loadWallets() (Wallets, error) {
 
 			logger.WithField("filename", fullPath).Info("loadWallets: loaded wallet")
 
-			wallets[name] = w
+			ie, err := newIndexEntry(w, e)
+			if err != nil {
+				return nil, err
+			}
+
+			s.wallets[name] = w
+			s.index[name] = ie
 		}
 	}
 
-	for name, w := range wallets {
-		if w.Coin() != CoinTypeSkycoin {
-			err := fmt.Errorf("LoadWallets only support skycoin wallets, %s is a %s wallet", name, w.Coin())
+	for name, e := range s.index {
+		if e.Coin != CoinTypeSkycoin {
+			err := fmt.Errorf("LoadWallets only support skycoin wallets, %s is a %s wallet", name, e.Coin)
 			logger.WithError(err).WithField("name", name).Error()
 			return nil, err
 		}
 	}
 
-	return wallets, nil
+	s.saveIndex()
+	return s, nil
 }
 
 // Load loads wallet from the given wallet file, it won't not affect the
@@ -179,8 +280,9 @@ func (serv *Service) updateOptions(opts Options) Options {
 		opts.Bip44Coin = &c
 	}
 
-	// generate one default address if options.GenerateN is 0
-	if opts.GenerateN == 0 {
+	// generate one default address if options.GenerateN is 0,
+	// multisig wallets do not generate addresses
+	if opts.GenerateN == 0 && opts.Type != WalletTypeMultisig {
 		opts.GenerateN = 1
 	}
 	return opts
@@ -212,7 +314,84 @@ func (serv *Service) createWallet(wltName string, options Options) (Wallet, erro
 		return nil, ErrInvalidWalletType
 	}
 
-	return creator.Create(wltName, options.Label, options.Seed, options)
+	var w Wallet
+	var err error
+	switch {
+	case len(options.SeedShares) > 0:
+		w, err = createFromSeedShares(creator, wltName, options.Label, options)
+	case options.TrySeedPassphrase:
+		w, err = createTrySeedPassphrase(creator, wltName, options)
+	default:
+		w, err = creator.Create(wltName, options.Label, options.Seed, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SeedEntropy != "" {
+		w.SetSeedEntropy(options.SeedEntropy)
+	}
+
+	return w, nil
+}
+
+// createTrySeedPassphrase creates the wallet with the seed passphrase, unless only the addresses
+// of the wallet created without it have transactions, e.g. when the passphrase was entered for
+// a seed that never had one
+func createTrySeedPassphrase(c Creator, wltName string, options Options) (Wallet, error) {
+	w, err := c.Create(wltName, options.Label, options.Seed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := hasActivity(w, options.TF)
+	if err != nil || ok {
+		return w, err
+	}
+
+	noPassphrase := options
+	noPassphrase.SeedPassphrase = ""
+	w2, err := c.Create(wltName, options.Label, options.Seed, noPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err = hasActivity(w2, options.TF)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		logger.WithField("filename", wltName).Info("Wallet addresses without the seed passphrase have transactions, ignoring the seed passphrase")
+		return w2, nil
+	}
+
+	return w, nil
+}
+
+// hasActivity returns whether any of the external and change addresses of the bip44 wallet have transactions
+func hasActivity(w Wallet, tf TransactionsFinder) (bool, error) {
+	addrs, err := w.GetAddresses(OptionExternal())
+	if err != nil {
+		return false, err
+	}
+
+	change, err := w.GetAddresses(OptionChange())
+	if err != nil {
+		return false, err
+	}
+
+	active, err := tf.AddressesActivity(append(addrs, change...))
+	if err != nil {
+		return false, err
+	}
+
+	for _, a := range active {
+		if a {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // loadWallet loads wallet from seed and scan the first N addresses
@@ -224,6 +403,11 @@ func (serv *Service) loadWallet(wltName string, options Options) (Wallet, error)
 		return nil, err
 	}
 
+	return serv.addWallet(w)
+}
+
+// addWallet adds the new wallet to the service and saves it, returns a copy of the wallet
+func (serv *Service) addWallet(w Wallet) (Wallet, error) {
 	fingerprint := w.Fingerprint()
 	// Note: collection wallets do not have fingerprints
 	if fingerprint != "" {
@@ -246,17 +430,25 @@ func (serv *Service) loadWallet(wltName string, options Options) (Wallet, error)
 		return nil, err
 	}
 
+	// Indexes the saved wallet
+	serv.wallets.set(w)
+
 	if fingerprint != "" {
 		serv.fingerprints[fingerprint] = w.Filename()
 	}
 
+	serv.events.publish(Event{
+		Type:     EventWalletCreated,
+		WalletID: w.Filename(),
+	})
+
 	return w.Clone(), nil
 }
 
 func (serv *Service) generateUniqueWalletFilename() string {
 	wltName := NewWalletFilename()
 	for {
-		if w := serv.wallets.get(wltName); w == nil {
+		if !serv.wallets.has(wltName) {
 			break
 		}
 		wltName = NewWalletFilename()
@@ -278,6 +470,10 @@ func (serv *Service) EncryptWallet(wltID string, password []byte) (Wallet, error
 		return nil, err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
 	if w.IsEncrypted() {
 		return nil, ErrWalletEncrypted
 	}
@@ -293,9 +489,84 @@ func (serv *Service) EncryptWallet(wltID string, password []byte) (Wallet, error
 
 	// Updates wallets in memory
 	serv.wallets.set(w)
+
+	serv.events.publish(Event{
+		Type:     EventEncrypted,
+		WalletID: wltID,
+	})
 	return w, nil
 }
 
+// ChangePassword changes the password of the encrypted wallet. The secrets are re-encrypted
+// in memory with crypto.ReEncrypt, the wallet is never decrypted nor saved unencrypted.
+func (serv *Service) ChangePassword(wltID string, password, newPassword []byte) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
+	if !w.IsEncrypted() {
+		return nil, ErrWalletNotEncrypted
+	}
+
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	if len(newPassword) == 0 {
+		return nil, ErrMissingNewPassword
+	}
+
+	// The new password is used with the argon2id parameters of the wallet
+	m := make(Meta)
+	m.SetArgon2Params(w.Argon2Params())
+
+	ct := w.CryptoType()
+	cryptor, err := GetCryptor(ct, m)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := crypto.ReEncrypt(cryptor, []byte(w.Secrets()), password, newPassword)
+	if err != nil {
+		if err == crypto.ErrReEncryptDecrypt {
+			return nil, ErrInvalidPassword
+		}
+		return nil, err
+	}
+
+	// The new password must not be the duress password
+	if ss, err := decryptDuress(w, newPassword); err == nil {
+		ss.Erase()
+		return nil, ErrDuressPasswordReused
+	} else if err != ErrInvalidPassword {
+		return nil, err
+	}
+
+	w.SetEncrypted(ct, string(secrets))
+
+	// Saves to disk
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return nil, err
+	}
+
+	serv.endSession(wltID)
+
+	// Updates wallets in memory
+	serv.wallets.set(w)
+	return w.Clone(), nil
+}
+
 // DecryptWallet decrypts wallet with password
 // TODO: this function will be deprecated in future.
 func (serv *Service) DecryptWallet(wltID string, password []byte) (Wallet, error) {
@@ -310,85 +581,112 @@ func (serv *Service) DecryptWallet(wltID string, password []byte) (Wallet, error
 		return nil, err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
 	// Returns error if wallet is not encrypted
 	if !w.IsEncrypted() {
 		return nil, ErrWalletNotEncrypted
 	}
 
+	if w.SpendPolicy().RequirePassword {
+		return nil, ErrRequirePasswordDecrypt
+	}
+
 	// Unlocks the wallet
 	unlockWlt, err := w.Unlock(password)
 	if err != nil {
 		return nil, err
 	}
 
+	// The duress password is only used by encrypted wallets
+	unlockWlt.SetDuressSecrets("")
+
 	// Updates the wallet file
 	if err := Save(unlockWlt, serv.config.WalletDir); err != nil {
 		return nil, err
 	}
 
+	serv.endSession(wltID)
+
 	// Sets the decrypted wallet in memory
 	serv.wallets.set(unlockWlt)
+
+	serv.events.publish(Event{
+		Type:     EventDecrypted,
+		WalletID: wltID,
+	})
 	return unlockWlt, nil
 }
 
-// NewAddresses generate address entries in given wallet,
-// return nil if wallet does not exist.
-// Set password as nil if the wallet is not encrypted, otherwise the password must be provided.
-// func (serv *Service) NewAddresses(wltID string, password []byte, num uint64) ([]cipher.Address, error) {
-// 	serv.Lock()
-// 	defer serv.Unlock()
+// UpgradeCrypto re-encrypts an encrypted wallet with a new crypto type.
+// The options are applied to the wallet before encrypting, e.g. OptionArgon2Params
+// tunes the argon2id-chacha20poly1305 crypto type.
+func (serv *Service) UpgradeCrypto(wltID string, password []byte, cryptoType crypto.CryptoType, options ...Option) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
 
-// 	if !serv.config.EnableWalletAPI {
-// 		return nil, ErrWalletAPIDisabled
-// 	}
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
 
-// 	w, err := serv.getWallet(wltID)
-// 	if err != nil {
-// 		return nil, err
-// 	}
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
 
-// 	var addrs []cipher.Address
-// 	f := func(wlt Wallet) error {
-// 		var err error
-// 		addrs, err = wlt.GenerateSkycoinAddresses(num)
-// 		return err
-// 	}
+	if !w.IsEncrypted() {
+		return nil, ErrWalletNotEncrypted
+	}
 
-// 	if w.IsEncrypted() {
-// 		if err := GuardUpdate(w, password, f); err != nil {
-// 			return nil, err
-// 		}
-// 	} else {
-// 		if len(password) != 0 {
-// 			return nil, ErrWalletNotEncrypted
-// 		}
+	if _, err := crypto.GetCrypto(cryptoType); err != nil {
+		return nil, NewError(err)
+	}
 
-// 		if err := f(w); err != nil {
-// 			return nil, err
-// 		}
-// 	}
+	// Decrypts the wallet with its current crypto type
+	unlockWlt, err := w.Unlock(password)
+	if err != nil {
+		return nil, err
+	}
 
-// 	// Checks if the wallet file is writable
-// 	wf := filepath.Join(serv.config.WalletDir, w.Filename())
-// 	if !file.IsWritable(wf) {
-// 		return nil, ErrWalletPermission
-// 	}
+	unlockWlt.SetCryptoType(cryptoType)
+	for _, opt := range options {
+		opt(unlockWlt)
+	}
 
-// 	// Save the wallet first
-// 	if err := Save(w, serv.config.WalletDir); err != nil {
-// 		return nil, err
-// 	}
+	// The decoy secrets are encrypted with the old crypto type, the duress password has to be set again
+	unlockWlt.SetDuressSecrets("")
 
-// 	serv.wallets.set(w)
+	// Encrypts the wallet with the new crypto type
+	if err := unlockWlt.Lock(password); err != nil {
+		unlockWlt.Erase()
+		return nil, err
+	}
 
-// 	return addrs, nil
-// }
+	// Saves to disk
+	if err := Save(unlockWlt, serv.config.WalletDir); err != nil {
+		return nil, err
+	}
 
-// NewAddresses generate addresses
-func (serv *Service) NewAddresses(wltID string, password []byte, num uint64, options ...Option) ([]cipher.Address, error) {
+	serv.endSession(wltID)
+
+	// Updates wallets in memory
+	serv.wallets.set(unlockWlt)
+	return unlockWlt.Clone(), nil
+}
+
+// SetDuressPassword sets the duress password of the encrypted wallet, which opens a decoy wallet
+// holding only the entries of the decoy addresses instead of the wallet, e.g. a few addresses with a small balance.
+// Signing with the duress password can only spend the coins of the decoy addresses,
+// and GetWalletEntries with the duress password only lists them.
+// The decoy addresses are replaced if the wallet already has a duress password.
+func (serv *Service) SetDuressPassword(wltID string, password, duressPassword []byte, addrs []cipher.Address) (Wallet, error) {
 	serv.Lock()
 	defer serv.Unlock()
-
 	if !serv.config.EnableWalletAPI {
 		return nil, ErrWalletAPIDisabled
 	}
@@ -398,11 +696,170 @@ func (serv *Service) NewAddresses(wltID string, password []byte, num uint64, opt
 		return nil, err
 	}
 
-	var addrs []cipher.Addresser
-	f := func(w Wallet) error {
-		var err error
-		addrs, err = w.GenerateAddresses(num, options...)
-		return err
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
+	if !w.IsEncrypted() {
+		return nil, ErrWalletNotEncrypted
+	}
+
+	if len(duressPassword) == 0 {
+		return nil, ErrMissingDuressPassword
+	}
+
+	if bytes.Equal(password, duressPassword) {
+		return nil, ErrDuressPasswordReused
+	}
+
+	if len(addrs) == 0 {
+		return nil, ErrMissingDuressAddresses
+	}
+
+	var ss Secrets
+	if err := GuardView(w, password, func(wlt Wallet) error {
+		var err error
+		ss, err = newDuressSecrets(wlt, addrs)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	defer ss.Erase()
+
+	cryptor, err := walletCryptor(w)
+	if err != nil {
+		return nil, err
+	}
+
+	encSecrets, err := ss.Encrypt(cryptor, duressPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	w.SetDuressSecrets(string(encSecrets))
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return nil, err
+	}
+
+	serv.wallets.set(w)
+	return w.Clone(), nil
+}
+
+// RemoveDuressPassword removes the duress password of the encrypted wallet, the wallet password is required
+func (serv *Service) RemoveDuressPassword(wltID string, password []byte) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
+	if !w.IsEncrypted() {
+		return nil, ErrWalletNotEncrypted
+	}
+
+	// The password is verified by unlocking the wallet
+	if err := GuardView(w, password, func(Wallet) error {
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	w.SetDuressSecrets("")
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return nil, err
+	}
+
+	serv.wallets.set(w)
+	return w.Clone(), nil
+}
+
+// NewAddresses generate address entries in given wallet,
+// return nil if wallet does not exist.
+// Set password as nil if the wallet is not encrypted, otherwise the password must be provided.
+// func (serv *Service) NewAddresses(wltID string, password []byte, num uint64) ([]cipher.Address, error) {
+// 	serv.Lock()
+// 	defer serv.Unlock()
+
+// 	if !serv.config.EnableWalletAPI {
+// 		return nil, ErrWalletAPIDisabled
+// 	}
+
+// 	w, err := serv.getWallet(wltID)
+// 	if err != nil {
+// 		return nil, err
+// 	}
+
+// 	var addrs []cipher.Address
+// 	f := func(wlt Wallet) error {
+// 		var err error
+// 		addrs, err = wlt.GenerateSkycoinAddresses(num)
+// 		return err
+// 	}
+
+// 	if w.IsEncrypted() {
+// 		if err := GuardUpdate(w, password, f); err != nil {
+// 			return nil, err
+// 		}
+// 	} else {
+// 		if len(password) != 0 {
+// 			return nil, ErrWalletNotEncrypted
+// 		}
+
+// 		if err := f(w); err != nil {
+// 			return nil, err
+// 		}
+// 	}
+
+// 	// Checks if the wallet file is writable
+// 	wf := filepath.Join(serv.config.WalletDir, w.Filename())
+// 	if !file.IsWritable(wf) {
+// 		return nil, ErrWalletPermission
+// 	}
+
+// 	// Save the wallet first
+// 	if err := Save(w, serv.config.WalletDir); err != nil {
+// 		return nil, err
+// 	}
+
+// 	serv.wallets.set(w)
+
+// 	return addrs, nil
+// }
+
+// NewAddresses generate addresses
+func (serv *Service) NewAddresses(wltID string, password []byte, num uint64, options ...Option) ([]cipher.Address, error) {
+	serv.Lock()
+	defer serv.Unlock()
+
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
+	var addrs []cipher.Addresser
+	f := func(w Wallet) error {
+		var err error
+		addrs, err = w.GenerateAddresses(num, options...)
+		return err
 	}
 
 	// TODO: Test this
@@ -439,7 +896,10 @@ func (serv *Service) NewAddresses(wltID string, password []byte, num uint64, opt
 		return nil, err
 	}
 
+	serv.endSession(wltID)
 	serv.wallets.set(w)
+
+	serv.publishAddresses(wltID, addrs)
 	return SkycoinAddresses(addrs), nil
 }
 
@@ -456,6 +916,10 @@ func (serv *Service) ScanAddresses(wltID string, password []byte, num uint64, tf
 		return nil, err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
 	var addrs []cipher.Addresser
 	f := func(w Wallet) error {
 		var err error
@@ -499,9 +963,13 @@ func (serv *Service) ScanAddresses(wltID string, password []byte, num uint64, tf
 		return nil, err
 	}
 
+	serv.endSession(wltID)
+
 	// Updates wallet in memory
 	serv.wallets.set(w)
 
+	serv.publishAddresses(wltID, addrs)
+
 	// return new generated addresses
 	return SkycoinAddresses(addrs), nil
 }
@@ -550,19 +1018,200 @@ func (serv *Service) GetWallet(wltID string) (Wallet, error) {
 		return nil, ErrWalletAPIDisabled
 	}
 
-	return serv.getWallet(wltID)
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	serv.setUnlockedUntil(w)
+	return w, nil
+}
+
+// GetWalletEntries returns the entries of all chains and accounts of the wallet, without their secret keys.
+// The password is required if the wallet is encrypted, the duress password of an encrypted wallet
+// only lists the decoy entries.
+func (serv *Service) GetWalletEntries(wltID string, password []byte) (Entries, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries Entries
+	f := func(w Wallet) error {
+		es, err := allEntries(w)
+		if err != nil {
+			return err
+		}
+
+		entries = es.Clone()
+		entries.Erase()
+		return nil
+	}
+
+	if w.IsEncrypted() {
+		err = guardViewDuress(w, password, f)
+	} else if len(password) != 0 {
+		err = ErrWalletNotEncrypted
+	} else {
+		err = f(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExportEntries exports all addresses of the wallet with their derivation paths, labels,
+// confirmed balances and last used block heights, which are found by the AddressesFinder.
+// The entries of encrypted wallets are exported without the password, no secrets are exported.
+func (serv *Service) ExportEntries(wltID string, format ExportFormat, af AddressesFinder) ([]byte, error) {
+	switch format {
+	case ExportFormatCSV, ExportFormatJSON:
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+
+	es, err := func() ([]ExportEntry, error) {
+		serv.RLock()
+		defer serv.RUnlock()
+		if !serv.config.EnableWalletAPI {
+			return nil, ErrWalletAPIDisabled
+		}
+
+		w, err := serv.getWallet(wltID)
+		if err != nil {
+			return nil, err
+		}
+
+		if w.Coin() != CoinTypeSkycoin {
+			return nil, ErrExportCoinType
+		}
+
+		return newExportEntries(w)
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	// The balances are found without holding the lock of the wallets
+	if err := setExportBalances(es, af); err != nil {
+		return nil, err
+	}
+
+	return encodeExportEntries(es, format)
+}
+
+// GetAddressLabels returns the label, note and tags of the addresses of the wallet that have any,
+// on all accounts and chains of bip44 wallets
+func (serv *Service) GetAddressLabels(wltID string) ([]AddressLabel, error) {
+	var labels []AddressLabel
+	if err := serv.View(wltID, func(w Wallet) error {
+		var options [][]Option
+		switch w.Type() {
+		case WalletTypeBip44:
+			for _, a := range w.Accounts() {
+				options = append(options, []Option{OptionAccount(a.Index), OptionExternal(), OptionChange()})
+			}
+		default:
+			options = [][]Option{nil}
+		}
+
+		for _, opts := range options {
+			entries, err := w.GetEntries(opts...)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range entries {
+				if e.EntryMeta.IsEmpty() {
+					continue
+				}
+
+				labels = append(labels, AddressLabel{
+					Address:   e.Address,
+					EntryMeta: e.EntryMeta.Clone(),
+				})
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// SetAddressLabels sets the label, note and tags of the addresses of the wallet, replacing their metadata.
+// The addresses that the wallet does not have are not set and are returned, e.g. the addresses
+// that are not generated yet by a wallet recreated from its seed.
+func (serv *Service) SetAddressLabels(wltID string, labels []AddressLabel) ([]cipher.Addresser, error) {
+	var notFound []cipher.Addresser
+	if err := serv.Update(wltID, func(w Wallet) error {
+		var options [][]Option
+		switch w.Type() {
+		case WalletTypeBip44:
+			for _, a := range w.Accounts() {
+				options = append(options, []Option{OptionAccount(a.Index)})
+			}
+		default:
+			options = [][]Option{nil}
+		}
+
+		for _, l := range labels {
+			found := false
+			for _, opts := range options {
+				err := w.SetEntryMeta(l.Address, l.EntryMeta, opts...)
+				if err == ErrEntryNotFound {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+
+				found = true
+				break
+			}
+
+			if !found {
+				notFound = append(notFound, l.Address)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return notFound, nil
+}
+
+// checkReadOnly returns ErrWalletReadOnly if the wallet is read-only
+func checkReadOnly(w Wallet) error {
+	if w.IsReadOnly() {
+		return ErrWalletReadOnly
+	}
+	return nil
 }
 
 // returns the clone of the wallet of given id
 func (serv *Service) getWallet(wltID string) (Wallet, error) {
-	w := serv.wallets.get(wltID)
+	w, err := serv.wallets.get(wltID)
+	if err != nil {
+		return nil, err
+	}
 	if w == nil {
 		return nil, ErrWalletNotExist
 	}
 	return w.Clone(), nil
 }
 
-// GetWallets returns all wallet clones
+// GetWallets returns all wallet clones, the wallets that are not loaded yet are loaded
 func (serv *Service) GetWallets() (Wallets, error) {
 	serv.RLock()
 	defer serv.RUnlock()
@@ -570,34 +1219,530 @@ func (serv *Service) GetWallets() (Wallets, error) {
 		return nil, ErrWalletAPIDisabled
 	}
 
-	wlts := make(Wallets, len(serv.wallets))
-	for k, w := range serv.wallets {
-		wlts[k] = w.Clone()
+	all, err := serv.wallets.all()
+	if err != nil {
+		return nil, err
+	}
+
+	wlts := make(Wallets, len(all))
+	for k, w := range all {
+		wlts[k] = w.Clone()
+		serv.setUnlockedUntil(wlts[k])
+	}
+	return wlts, nil
+}
+
+// ListWallets returns the index entries of all wallets, sorted by filename.
+// The wallets are listed from the wallet index, without loading the wallet files.
+func (serv *Service) ListWallets() ([]IndexEntry, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	return serv.wallets.entries(), nil
+}
+
+// UpdateWalletLabel updates the wallet label
+func (serv *Service) UpdateWalletLabel(wltID, label string) error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return err
+	}
+
+	w.SetLabel(label)
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return err
+	}
+
+	serv.wallets.set(w)
+	return nil
+}
+
+// SetChangePolicy sets the change address policy of the wallet,
+// changeAddress is required by and only used by the address change policy
+func (serv *Service) SetChangePolicy(wltID string, policy ChangePolicy, changeAddress string) error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return err
+	}
+
+	if err := ValidateChangePolicy(w, policy, changeAddress); err != nil {
+		return err
+	}
+
+	w.SetChangePolicy(policy, changeAddress)
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return err
+	}
+
+	serv.wallets.set(w)
+	return nil
+}
+
+// SetSpendPolicy sets the spend policy of the wallet, which is enforced by CreateTransactionSigned
+func (serv *Service) SetSpendPolicy(wltID string, policy SpendPolicy) error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkReadOnly(w); err != nil {
+		return err
+	}
+
+	if err := ValidateSpendPolicy(w, policy); err != nil {
+		return err
+	}
+
+	w.SetSpendPolicy(policy)
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return err
+	}
+
+	serv.wallets.set(w)
+	return nil
+}
+
+// SetReadOnly marks the wallet as read-only, or removes the mark. No addresses can be generated,
+// no transactions can be signed and no other changes can be made to a read-only wallet.
+func (serv *Service) SetReadOnly(wltID string, readOnly bool) error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return err
+	}
+
+	if w.IsReadOnly() == readOnly {
+		return nil
+	}
+
+	w.SetReadOnly(readOnly)
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return err
+	}
+
+	serv.wallets.set(w)
+	return nil
+}
+
+// CreateTransactionSigned opens a wallet for creating or signing a transaction with f, enforcing the spend policy
+// of the wallet. The password is always required if the policy requires it, otherwise refer to ViewSecrets.
+// The coins that the transaction sends to addresses not in the wallet are checked against the daily spend limit,
+// returning a SpendLimitError if the limit would be exceeded, and are recorded as spent.
+// Returns ErrWalletReadOnly if the wallet is read-only.
+func (serv *Service) CreateTransactionSigned(wltID string, password []byte, f func(Wallet) (*coin.Transaction, error)) (*coin.Transaction, error) {
+	return serv.createTransactionSigned(wltID, password, false, f)
+}
+
+// CreateSweepTransactionSigned is CreateTransactionSigned for sweeping the coins of a wallet with
+// CreateSweepTransaction, which is also allowed for the read-only wallets migrated with MigrateToBip44
+func (serv *Service) CreateSweepTransactionSigned(wltID string, password []byte, f func(Wallet) (*coin.Transaction, error)) (*coin.Transaction, error) {
+	return serv.createTransactionSigned(wltID, password, true, f)
+}
+
+func (serv *Service) createTransactionSigned(wltID string, password []byte, sweep bool, f func(Wallet) (*coin.Transaction, error)) (*coin.Transaction, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sweep || w.MigratedTo() == "" {
+		if err := checkReadOnly(w); err != nil {
+			return nil, err
+		}
+	}
+
+	// The secrets of hardware wallets are on the device
+	if err := checkDevice(w); err != nil {
+		return nil, err
+	}
+
+	if err := checkKeyring(w); err != nil {
+		return nil, err
+	}
+
+	policy := w.SpendPolicy()
+	if policy.RequirePassword && len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	var txn *coin.Transaction
+	g := func(w Wallet) error {
+		var err error
+		txn, err = f(w)
+		return err
+	}
+
+	if w.IsEncrypted() {
+		if session := serv.session(wltID); session != nil && len(password) == 0 {
+			err = session.view(w, g)
+		} else {
+			// The duress password signs with the decoy wallet
+			err = guardViewDuress(w, password, g)
+		}
+	} else if len(password) != 0 {
+		err = ErrWalletNotEncrypted
+	} else {
+		err = g(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.DailyLimit == 0 {
+		return txn, nil
+	}
+
+	now := time.Now()
+	amount, err := CheckSpend(w, txn, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.AddSpent(now, amount); err != nil {
+		return nil, err
+	}
+
+	if err := Save(w, serv.config.WalletDir); err != nil {
+		return nil, err
+	}
+
+	serv.wallets.set(w)
+	return txn, nil
+}
+
+// SignMessage signs the message with the secret key of the address of the wallet, proving the ownership
+// of the address, see cipher.SignMessage. The password is not required if the wallet is in an unlock session.
+// With the duress password only the decoy addresses sign.
+func (serv *Service) SignMessage(wltID string, password []byte, addr cipher.Address, msg []byte) (cipher.Sig, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return cipher.Sig{}, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+
+	if w.Coin() != CoinTypeSkycoin {
+		return cipher.Sig{}, ErrSignMessageCoinType
+	}
+
+	var sig cipher.Sig
+	f := func(w Wallet) error {
+		var err error
+		sig, err = signMessage(w, addr, msg)
+		return err
+	}
+
+	if w.IsEncrypted() {
+		if session := serv.session(wltID); session != nil && len(password) == 0 {
+			err = session.view(w, f)
+		} else {
+			err = guardViewDuress(w, password, f)
+		}
+	} else if len(password) != 0 {
+		err = ErrWalletNotEncrypted
+	} else {
+		err = f(w)
+	}
+	if err != nil {
+		return cipher.Sig{}, err
+	}
+
+	return sig, nil
+}
+
+// EncryptMessage encrypts the plaintext to the public key of the address of the wallet with ECIES,
+// see cipher.ECIESEncrypt. The message is decrypted with DecryptMessage. The password is not required,
+// the public keys of encrypted wallets are not encrypted.
+func (serv *Service) EncryptMessage(wltID string, addr cipher.Address, plaintext []byte) ([]byte, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.Coin() != CoinTypeSkycoin {
+		return nil, ErrMessageCoinType
+	}
+
+	entries, err := allEntries(w)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := findEntry(entries, addr)
+	if !ok {
+		return nil, ErrUnknownAddress
+	}
+
+	if e.Public.Null() {
+		return nil, ErrMissingPubKey
+	}
+
+	return cipher.ECIESEncrypt(e.Public, plaintext)
+}
+
+// DecryptMessage decrypts the ECIES ciphertext encrypted to the public key of the address of the wallet,
+// see cipher.ECIESDecrypt. The password is not required if the wallet is in an unlock session.
+func (serv *Service) DecryptMessage(wltID string, password []byte, addr cipher.Address, ciphertext []byte) ([]byte, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.Coin() != CoinTypeSkycoin {
+		return nil, ErrMessageCoinType
+	}
+
+	var plaintext []byte
+	f := func(w Wallet) error {
+		var err error
+		plaintext, err = decryptMessage(w, addr, ciphertext)
+		return err
+	}
+
+	if w.IsEncrypted() {
+		if session := serv.session(wltID); session != nil && len(password) == 0 {
+			err = session.view(w, f)
+		} else {
+			err = guardViewDuress(w, password, f)
+		}
+	} else if len(password) != 0 {
+		err = ErrWalletNotEncrypted
+	} else {
+		err = f(w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+// ExportKeystore exports the entries of the wallet as Web3 keystore v3 JSON, one keystore per entry,
+// encrypted with keystorePassword. password is the wallet password if the wallet is encrypted.
+func (serv *Service) ExportKeystore(wltID string, password, keystorePassword []byte, params KeystoreParams) ([][]byte, error) {
+	var keystores [][]byte
+	if err := serv.ViewSecrets(wltID, password, func(w Wallet) error {
+		var err error
+		keystores, err = ExportKeystore(w, keystorePassword, params)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return keystores, nil
+}
+
+// ImportKeystore creates a collection wallet with the keys of the Web3 keystore v3 JSON files,
+// which are decrypted with keystorePassword. Refer to ImportKeystore for the options.
+func (serv *Service) ImportKeystore(wltName string, keystores [][]byte, keystorePassword []byte, options Options) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+	if wltName == "" {
+		wltName = serv.generateUniqueWalletFilename()
+	}
+
+	// The service only loads skycoin wallets
+	if options.Coin != "" && options.Coin != CoinTypeSkycoin {
+		return nil, NewError(fmt.Errorf("%s wallets are not supported by the wallet service", options.Coin))
+	}
+
+	if options.Encrypt && options.CryptoType == "" {
+		options.CryptoType = serv.config.CryptoType
+	}
+
+	w, err := ImportKeystore(wltName, options.Label, keystores, keystorePassword, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.addWallet(w)
+}
+
+// ImportPrivateKeys creates a collection wallet with the private keys, in the hex, bitcoin WIF
+// or mini private key format. Refer to ImportPrivateKeys for the options.
+func (serv *Service) ImportPrivateKeys(wltName string, keys []string, options Options) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+	if wltName == "" {
+		wltName = serv.generateUniqueWalletFilename()
+	}
+
+	// The service only loads skycoin wallets
+	if options.Coin != "" && options.Coin != CoinTypeSkycoin {
+		return nil, NewError(fmt.Errorf("%s wallets are not supported by the wallet service", options.Coin))
+	}
+
+	if options.Encrypt && options.CryptoType == "" {
+		options.CryptoType = serv.config.CryptoType
+	}
+
+	w, err := ImportPrivateKeys(wltName, options.Label, keys, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.addWallet(w)
+}
+
+// ImportBIP38Keys creates a collection wallet with BIP38 encrypted private keys, which are decrypted
+// with passphrase. Refer to ImportBIP38Keys for the options.
+func (serv *Service) ImportBIP38Keys(wltName string, keys []string, passphrase []byte, options Options) (Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+	if wltName == "" {
+		wltName = serv.generateUniqueWalletFilename()
+	}
+
+	// The service only loads skycoin wallets
+	if options.Coin != "" && options.Coin != CoinTypeSkycoin {
+		return nil, NewError(fmt.Errorf("%s wallets are not supported by the wallet service", options.Coin))
 	}
-	return wlts, nil
+
+	if options.Encrypt && options.CryptoType == "" {
+		options.CryptoType = serv.config.CryptoType
+	}
+
+	w, err := ImportBIP38Keys(wltName, options.Label, keys, passphrase, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.addWallet(w)
 }
 
-// UpdateWalletLabel updates the wallet label
-func (serv *Service) UpdateWalletLabel(wltID, label string) error {
+// MigrateToBip44 creates a bip44 wallet to replace the deterministic wallet, marking the deterministic
+// wallet read-only. The bip44 wallet is created from options.Seed, refer to MigrateToBip44 for the details.
+// The coins of the deterministic wallet are not moved, use CreateSweepTransaction.
+func (serv *Service) MigrateToBip44(wltID, wltName string, options Options) (Wallet, error) {
 	serv.Lock()
 	defer serv.Unlock()
 	if !serv.config.EnableWalletAPI {
-		return ErrWalletAPIDisabled
+		return nil, ErrWalletAPIDisabled
 	}
 
 	w, err := serv.getWallet(wltID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	w.SetLabel(label)
+	if wltName == "" {
+		wltName = serv.generateUniqueWalletFilename()
+	}
+
+	options.Type = WalletTypeBip44
+	options = serv.updateOptions(options)
+
+	nw, err := MigrateToBip44(w, wltName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	nw, err = serv.addWallet(nw)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := Save(w, serv.config.WalletDir); err != nil {
-		return err
+		return nil, err
 	}
 
 	serv.wallets.set(w)
-	return nil
+	return nw, nil
+}
+
+// Subscribe subscribes to the wallet events, the events are delivered on the returned channel.
+// Events are dropped if the channel buffer is full, a bufferSize of 0 uses DefaultEventBufferSize.
+// The returned function unsubscribes and closes the channel.
+func (serv *Service) Subscribe(bufferSize int) (<-chan Event, func()) {
+	return serv.events.subscribe(bufferSize)
+}
+
+// ReportBalance records the balance of a wallet, emitting an EventBalanceChanged event
+// if it differs from the last reported balance of the wallet.
+// The wallet service does not track balances, they are reported by the caller that computes them.
+func (serv *Service) ReportBalance(wltID string, balance BalancePair) {
+	serv.events.reportBalance(wltID, balance)
+}
+
+func (serv *Service) publishAddresses(wltID string, addrs []cipher.Addresser) {
+	if len(addrs) == 0 {
+		return
+	}
+
+	serv.events.publish(Event{
+		Type:      EventAddressGenerated,
+		WalletID:  wltID,
+		Addresses: SkycoinAddresses(addrs),
+	})
 }
 
 // UnloadWallet removes wallet of given wallet id from the service
@@ -608,23 +1753,51 @@ func (serv *Service) UnloadWallet(wltID string) error {
 		return ErrWalletAPIDisabled
 	}
 
-	wlt := serv.wallets.get(wltID)
-	if wlt != nil {
-		if fp := wlt.Fingerprint(); fp != "" {
+	for fp, id := range serv.fingerprints {
+		if id == wltID {
 			delete(serv.fingerprints, fp)
 		}
 	}
 
+	serv.endSession(wltID)
 	serv.wallets.remove(wltID)
+	serv.events.forget(wltID)
 	return nil
 }
 
-func (serv *Service) setWallets(wlts Wallets) {
-	serv.wallets = wlts
+// MigrateWallets upgrades the v1/v2 files of the loaded wallets to the v3 wallet file format,
+// the original files are kept with the LegacyBackupExt extension.
+// Returns the ids of the migrated wallets.
+func (serv *Service) MigrateWallets() ([]string, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	ids := serv.wallets.ids()
+
+	var migrated []string
+	for _, wltID := range ids {
+		ok, err := MigrateFile(filepath.Join(serv.config.WalletDir, wltID))
+		if err != nil {
+			return migrated, fmt.Errorf("migrate wallet %q failed: %v", wltID, err)
+		}
+
+		if ok {
+			migrated = append(migrated, wltID)
+		}
+	}
+
+	return migrated, nil
+}
+
+func (serv *Service) setWallets(s *walletStore) {
+	serv.wallets = s
 
-	for wltID, wlt := range wlts {
-		if fp := wlt.Fingerprint(); fp != "" {
-			serv.fingerprints[fp] = wltID
+	for _, e := range s.entries() {
+		if e.Fingerprint != "" {
+			serv.fingerprints[e.Fingerprint] = e.Filename
 		}
 	}
 }
@@ -663,6 +1836,204 @@ func (serv *Service) GetWalletSeed(wltID string, password []byte) (string, strin
 	return seed, seedPassphrase, nil
 }
 
+// VerifySeedPassphrase verifies the seed passphrase against the accounts of the wallet of given wallet id,
+// e.g. before signing with a wallet whose passphrase was entered on a different device.
+// The password is required if the wallet is encrypted.
+// Returns ErrSeedPassphraseWrong if the passphrase does not match the wallet.
+func (serv *Service) VerifySeedPassphrase(wltID string, password []byte, seedPassphrase string) error {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := w.(SeedPassphraseVerifier); !ok {
+		return ErrWalletSeedPassphrase
+	}
+
+	verify := func(wlt Wallet) error {
+		ok, err := wlt.(SeedPassphraseVerifier).VerifySeedPassphrase(seedPassphrase)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrSeedPassphraseWrong
+		}
+		return nil
+	}
+
+	if !w.IsEncrypted() {
+		return verify(w)
+	}
+
+	return GuardView(w, password, verify)
+}
+
+// VerifyWalletSeed returns whether the seed and seed passphrase are the ones of the wallet of given wallet id,
+// e.g. to validate a backup of the seed. A wallet is created from the seed and its fingerprint, composed of
+// its first address, is compared with the fingerprint of the wallet, so the wallet is never decrypted.
+func (serv *Service) VerifyWalletSeed(wltID, seed, seedPassphrase string) (bool, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return false, ErrWalletAPIDisabled
+	}
+
+	if seed == "" {
+		return false, ErrMissingSeed
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return false, err
+	}
+
+	options := Options{
+		Type:              w.Type(),
+		Coin:              w.Coin(),
+		Bip44Coin:         w.Bip44Coin(),
+		Bip44PathTemplate: w.Bip44PathTemplate(),
+		AddressEncoding:   w.AddressEncoding(),
+		Seed:              seed,
+		SeedPassphrase:    seedPassphrase,
+		GenerateN:         1,
+	}
+
+	switch w.Type() {
+	case WalletTypeBip44:
+		options.SeedLanguage = w.SeedLanguage()
+		if err := bip39.ValidateMnemonicInLanguage(seed, options.SeedLanguage); err != nil {
+			return false, nil
+		}
+	case WalletTypeDeterministic:
+		if seedPassphrase != "" {
+			return false, ErrWalletSeedPassphrase
+		}
+	default:
+		return false, ErrWalletSeedVerify
+	}
+
+	// The fingerprint of an encrypted wallet without addresses has no address to compare
+	if w.IsEncrypted() {
+		n, err := w.EntriesLen()
+		if err != nil {
+			return false, err
+		}
+		if n == 0 {
+			return false, ErrWalletNoAddresses
+		}
+	}
+
+	w2, err := serv.createWallet(wltID, options)
+	if err != nil {
+		return false, err
+	}
+	defer w2.Erase()
+
+	return w.Fingerprint() == w2.Fingerprint(), nil
+}
+
+// NewAccount creates a named account on the wallet of given wallet id, and returns the account.
+// The account private key is derived from the seed, so the password is required if the wallet is encrypted.
+// Returns ErrWalletAccounts if the wallet does not have accounts.
+func (serv *Service) NewAccount(wltID string, password []byte, name string) (Bip44Account, error) {
+	var account Bip44Account
+	if err := serv.UpdateSecrets(wltID, password, func(w Wallet) error {
+		am, ok := w.(AccountManager)
+		if !ok {
+			return ErrWalletAccounts
+		}
+
+		index, err := am.NewAccount(name)
+		if err != nil {
+			return err
+		}
+
+		account = Bip44Account{
+			Name:  name,
+			Index: index,
+		}
+		return nil
+	}); err != nil {
+		return Bip44Account{}, err
+	}
+
+	return account, nil
+}
+
+// SetAccountName renames the account of given index of the wallet of given wallet id.
+// Returns ErrWalletAccounts if the wallet does not have accounts.
+func (serv *Service) SetAccountName(wltID string, index uint32, name string) error {
+	return serv.Update(wltID, func(w Wallet) error {
+		am, ok := w.(AccountManager)
+		if !ok {
+			return ErrWalletAccounts
+		}
+
+		return am.SetAccountName(index, name)
+	})
+}
+
+// GetAccounts returns the accounts of the wallet of given wallet id
+func (serv *Service) GetAccounts(wltID string) ([]Bip44Account, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := w.(AccountManager); !ok {
+		return nil, ErrWalletAccounts
+	}
+
+	return w.Accounts(), nil
+}
+
+// GetWalletSeedShares splits the seed of encrypted wallet of given wallet id into
+// SLIP-0039 mnemonic shares, see SplitSeed.
+// Returns ErrWalletNotEncrypted if it's not encrypted
+func (serv *Service) GetWalletSeedShares(wltID string, password []byte, groupThreshold int, groups []slip39.Group) ([][]string, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.config.EnableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	if !serv.config.EnableSeedAPI {
+		return nil, ErrSeedAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !w.IsEncrypted() {
+		return nil, ErrWalletNotEncrypted
+	}
+
+	var shares [][]string
+	if err := GuardView(w, password, func(wlt Wallet) error {
+		var err error
+		shares, err = SplitSeed(wlt.Seed(), groupThreshold, groups)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
 // UpdateSecrets opens a wallet for modification of secret data and saves it safely
 func (serv *Service) UpdateSecrets(wltID string, password []byte, f func(Wallet) error) error {
 	serv.Lock()
@@ -676,6 +2047,10 @@ func (serv *Service) UpdateSecrets(wltID string, password []byte, f func(Wallet)
 		return err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return err
+	}
+
 	if w.IsEncrypted() {
 		if err := GuardUpdate(w, password, f); err != nil {
 			return err
@@ -693,6 +2068,7 @@ func (serv *Service) UpdateSecrets(wltID string, password []byte, f func(Wallet)
 		return err
 	}
 
+	serv.endSession(wltID)
 	serv.wallets.set(w)
 
 	return nil
@@ -711,6 +2087,10 @@ func (serv *Service) Update(wltID string, f func(Wallet) error) error {
 		return err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return err
+	}
+
 	if err := f(w); err != nil {
 		return err
 	}
@@ -738,6 +2118,11 @@ func (serv *Service) ViewSecrets(wltID string, password []byte, f func(Wallet) e
 		return err
 	}
 
+	// The secrets of hardware wallets are on the device
+	if err := checkDevice(w); err != nil {
+		return err
+	}
+
 	if w.IsEncrypted() {
 		return GuardView(w, password, f)
 	} else if len(password) != 0 {
@@ -763,6 +2148,124 @@ func (serv *Service) View(wltID string, f func(Wallet) error) error {
 	return f(w)
 }
 
+// UnlockWallet starts an unlock session of the encrypted wallet, the decrypted secrets are held
+// in guarded memory until the ttl elapses or LockWallet is called. Within the session the wallet
+// signs transactions without the password, unless its spend policy requires the password.
+// The duress password starts a session that signs with the decoy wallet.
+// Unlocking a wallet in session renews the session. Returns the expiry of the session.
+// It's not named Unlock because the service embeds its sync.RWMutex.
+func (serv *Service) UnlockWallet(wltID string, password []byte, ttl time.Duration) (time.Time, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return time.Time{}, ErrWalletAPIDisabled
+	}
+
+	if ttl <= 0 {
+		return time.Time{}, ErrInvalidUnlockTTL
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !w.IsEncrypted() {
+		return time.Time{}, ErrWalletNotEncrypted
+	}
+
+	if _, ok := w.(SecretsUnlocker); !ok {
+		return time.Time{}, ErrUnlockSessionNotSupported
+	}
+
+	if len(password) == 0 {
+		return time.Time{}, ErrMissingPassword
+	}
+
+	cryptor, err := walletCryptor(w)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var duress bool
+	ss, err := DecryptSecrets(cryptor, []byte(w.Secrets()), password)
+	if err == ErrInvalidPassword && w.DuressSecrets() != "" {
+		ss, err = decryptDuress(w, password)
+		duress = err == nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Checks that the wallet can be unlocked with the secrets before starting the session
+	wlt, err := unlockWithSecrets(w, ss, duress)
+	if err != nil {
+		ss.Erase()
+		return time.Time{}, err
+	}
+	wlt.Erase()
+
+	expires := time.Now().Add(ttl)
+	session, err := newUnlockSession(ss, duress, expires)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	serv.endSession(wltID)
+	session.timer = time.AfterFunc(ttl, func() {
+		serv.Lock()
+		defer serv.Unlock()
+		if serv.sessions[wltID] == session {
+			serv.endSession(wltID)
+		}
+	})
+	serv.sessions[wltID] = session
+	return expires, nil
+}
+
+// LockWallet ends the unlock session of the wallet and wipes its decrypted secrets,
+// it's a no-op if the wallet is not in an unlock session.
+// It's not named Lock because the service embeds its sync.RWMutex.
+func (serv *Service) LockWallet(wltID string) error {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.config.EnableWalletAPI {
+		return ErrWalletAPIDisabled
+	}
+
+	if _, err := serv.getWallet(wltID); err != nil {
+		return err
+	}
+
+	serv.endSession(wltID)
+	return nil
+}
+
+// session returns the unexpired unlock session of the wallet, or nil
+func (serv *Service) session(wltID string) *unlockSession {
+	s, ok := serv.sessions[wltID]
+	if !ok || s.expired(time.Now()) {
+		return nil
+	}
+	return s
+}
+
+// endSession ends the unlock session of the wallet and wipes its decrypted secrets,
+// it's called when the wallet secrets change or the wallet is unloaded
+func (serv *Service) endSession(wltID string) {
+	if s, ok := serv.sessions[wltID]; ok {
+		s.destroy()
+		delete(serv.sessions, wltID)
+	}
+}
+
+// setUnlockedUntil annotates the wallet with the expiry of its unlock session
+func (serv *Service) setUnlockedUntil(w Wallet) {
+	if s := serv.session(w.Filename()); s != nil {
+		w.SetUnlockedUntil(s.expires)
+	}
+}
+
 // RecoverWallet recovers an encrypted wallet from seed.
 // The recovered wallet will be encrypted with the new password, if provided.
 func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, password []byte) (Wallet, error) {
@@ -777,6 +2280,10 @@ func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, passwor
 		return nil, err
 	}
 
+	if err := checkReadOnly(w); err != nil {
+		return nil, err
+	}
+
 	if !w.IsEncrypted() {
 		return nil, ErrWalletNotEncrypted
 	}
@@ -787,21 +2294,39 @@ func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, passwor
 		return nil, ErrWalletTypeNotRecoverable
 	}
 
-	// Create a wallet from this seed and compare the fingerprint
-	w2, err := serv.createWallet(wltName, Options{
-		Type:           w.Type(),
-		Coin:           w.Coin(),
-		Bip44Coin:      w.Bip44Coin(),
-		Seed:           seed,
-		SeedPassphrase: seedPassphrase,
-		GenerateN:      1,
-	})
-	if err != nil {
-		err = NewError(fmt.Errorf("RecoverWallet failed to create temporary wallet for fingerprint comparison: %v", err))
-		logger.Critical().WithError(err).Error()
-		return nil, err
+	// Create a wallet from this seed and compare the fingerprint.
+	// A bip44 wallet is also tried without the seed passphrase, in case
+	// the passphrase was entered for a wallet that does not have one.
+	passphrases := []string{seedPassphrase}
+	if w.Type() == WalletTypeBip44 && seedPassphrase != "" {
+		passphrases = append(passphrases, "")
+	}
+
+	var matched bool
+	for _, p := range passphrases {
+		w2, err := serv.createWallet(wltName, Options{
+			Type:              w.Type(),
+			Coin:              w.Coin(),
+			Bip44Coin:         w.Bip44Coin(),
+			Bip44PathTemplate: w.Bip44PathTemplate(),
+			Seed:              seed,
+			SeedPassphrase:    p,
+			GenerateN:         1,
+		})
+		if err != nil {
+			err = NewError(fmt.Errorf("RecoverWallet failed to create temporary wallet for fingerprint comparison: %v", err))
+			logger.Critical().WithError(err).Error()
+			return nil, err
+		}
+
+		if w.Fingerprint() == w2.Fingerprint() {
+			seedPassphrase = p
+			matched = true
+			break
+		}
 	}
-	if w.Fingerprint() != w2.Fingerprint() {
+
+	if !matched {
 		return nil, ErrWalletRecoverSeedWrong
 	}
 
@@ -818,16 +2343,17 @@ func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, passwor
 
 	// Create a new wallet with the same number of addresses, encrypting if needed
 	w3, err := serv.createWallet(wltName, Options{
-		Type:           w.Type(),
-		Coin:           w.Coin(),
-		Label:          w.Label(),
-		Seed:           seed,
-		SeedPassphrase: seedPassphrase,
-		Encrypt:        len(password) != 0,
-		Password:       password,
-		CryptoType:     w.CryptoType(),
-		Bip44Coin:      w.Bip44Coin(),
-		GenerateN:      uint64(l),
+		Type:              w.Type(),
+		Coin:              w.Coin(),
+		Label:             w.Label(),
+		Seed:              seed,
+		SeedPassphrase:    seedPassphrase,
+		Encrypt:           len(password) != 0,
+		Password:          password,
+		CryptoType:        w.CryptoType(),
+		Bip44Coin:         w.Bip44Coin(),
+		Bip44PathTemplate: w.Bip44PathTemplate(),
+		GenerateN:         uint64(l),
 	})
 	if err != nil {
 		return nil, err
@@ -857,6 +2383,7 @@ func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, passwor
 		return nil, err
 	}
 
+	serv.endSession(w3.Filename())
 	serv.wallets.set(w3)
 
 	return w3.Clone(), nil
@@ -1,7 +1,9 @@
 package wallet_test
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,12 +12,18 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/skycoin/skycoin/src/cipher/bip39"
+	"github.com/skycoin/skycoin/src/cipher/slip39"
+	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/file"
 	"github.com/skycoin/skycoin/src/wallet/bip44wallet"
 	"github.com/skycoin/skycoin/src/wallet/collection"
 	_ "github.com/skycoin/skycoin/src/wallet/deterministic"
+	"github.com/skycoin/skycoin/src/wallet/hardwarewallet"
+	"github.com/skycoin/skycoin/src/wallet/watchwallet"
 	_ "github.com/skycoin/skycoin/src/wallet/xpubwallet"
 	"github.com/stretchr/testify/require"
 
@@ -231,11 +239,11 @@ func TestServiceCreateWallet(t *testing.T) {
 					_, err = os.Stat(filepath.Join(dir, tc.filename))
 					require.False(t, os.IsNotExist(err))
 
-					// Confirms that the data saved to the disk is the same as the wallet.Deserialize()
+					// Confirms that the data saved to the disk is the same as the wallet.EncodeFile()
 					data, err := ioutil.ReadFile(filepath.Join(dir, tc.filename))
 					require.NoError(t, err)
 
-					sd, err := w.Serialize()
+					sd, err := wallet.EncodeFile(w)
 					require.NoError(t, err)
 
 					require.Equal(t, sd, data)
@@ -1082,6 +1090,113 @@ func TestServiceGetWallets(t *testing.T) {
 	}
 }
 
+func TestServiceListWallets(t *testing.T) {
+	dir := prepareWltDir()
+	newService := func() *wallet.Service {
+		s, err := wallet.NewService(wallet.Config{
+			WalletDir:       dir,
+			CryptoType:      crypto.CryptoTypeSha256Xor,
+			EnableWalletAPI: true,
+		})
+		require.NoError(t, err)
+		return s
+	}
+
+	s := newService()
+	w1, err := s.CreateWallet("t1.wlt", wallet.Options{
+		Label:     "label1",
+		Seed:      bip39.MustNewDefaultMnemonic(),
+		Type:      wallet.WalletTypeBip44,
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+
+	w2, err := s.CreateWallet("t2.wlt", wallet.Options{
+		Label:    "label2",
+		Seed:     bip39.MustNewDefaultMnemonic(),
+		Type:     wallet.WalletTypeDeterministic,
+		Encrypt:  true,
+		Password: []byte("pwd"),
+	})
+	require.NoError(t, err)
+
+	_, err = s.NewAddresses("t2.wlt", []byte("pwd"), 2)
+	require.NoError(t, err)
+
+	checkEntries := func(es []wallet.IndexEntry) {
+		require.Len(t, es, 2)
+		require.Equal(t, "t1.wlt", es[0].Filename)
+		require.Equal(t, "label1", es[0].Label)
+		require.Equal(t, wallet.WalletTypeBip44, es[0].Type)
+		require.Equal(t, wallet.CoinTypeSkycoin, es[0].Coin)
+		require.False(t, es[0].Encrypted)
+		// The change address is not counted
+		require.Equal(t, 3, es[0].AddressCount)
+		require.Equal(t, w1.Fingerprint(), es[0].Fingerprint)
+
+		require.Equal(t, "t2.wlt", es[1].Filename)
+		require.Equal(t, "label2", es[1].Label)
+		require.True(t, es[1].Encrypted)
+		require.Equal(t, 3, es[1].AddressCount)
+		require.Equal(t, w2.Fingerprint(), es[1].Fingerprint)
+	}
+
+	es, err := s.ListWallets()
+	require.NoError(t, err)
+	checkEntries(es)
+
+	// The indexed wallets are not loaded on start. The content of t1.wlt is broken
+	// without changing its size and modification time, so that loading it would fail.
+	fn := filepath.Join(dir, "t1.wlt")
+	fi, err := os.Stat(fn)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(fn, bytes.Repeat([]byte(" "), int(fi.Size())), 0600))
+	require.NoError(t, os.Chtimes(fn, fi.ModTime(), fi.ModTime()))
+
+	s = newService()
+	es, err = s.ListWallets()
+	require.NoError(t, err)
+	checkEntries(es)
+
+	// The wallet is loaded when it is used
+	w, err := s.GetWallet("t2.wlt")
+	require.NoError(t, err)
+	require.Equal(t, w2.Fingerprint(), w.Fingerprint())
+	_, err = s.GetWallet("t1.wlt")
+	require.Error(t, err)
+
+	// A changed wallet file is loaded on start
+	require.NoError(t, os.Chtimes(fn, fi.ModTime(), fi.ModTime().Add(time.Second)))
+	_, err = wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.Error(t, err)
+
+	// The duplicate wallets are detected from the index
+	require.NoError(t, os.Remove(fn))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "t2.wlt"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "t3.wlt"), b, 0600))
+	_, err = wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.EqualError(t, err, fmt.Sprintf("duplicate wallet found with fingerprint %s in file %q", w2.Fingerprint(), "t3.wlt"))
+
+	// The index is rebuilt if it is invalid
+	require.NoError(t, os.Remove(filepath.Join(dir, "t3.wlt")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, wallet.IndexFilename), []byte("{"), 0600))
+	s = newService()
+	es, err = s.ListWallets()
+	require.NoError(t, err)
+	require.Len(t, es, 1)
+	require.Equal(t, "t2.wlt", es[0].Filename)
+	require.Equal(t, 3, es[0].AddressCount)
+}
+
 func TestServiceUpdateWalletLabel(t *testing.T) {
 	tt := []struct {
 		name             string
@@ -1172,6 +1287,156 @@ func TestServiceUpdateWalletLabel(t *testing.T) {
 	}
 }
 
+func TestServiceSetChangePolicy(t *testing.T) {
+	changeAddr := testutil.MakeAddress().String()
+
+	tt := []struct {
+		name             string
+		walletType       string
+		updateWltName    string
+		policy           wallet.ChangePolicy
+		changeAddress    string
+		disableWalletAPI bool
+		err              error
+	}{
+		{
+			name:       "ok reuse first",
+			walletType: wallet.WalletTypeDeterministic,
+			policy:     wallet.ChangePolicyReuseFirst,
+		},
+		{
+			name:       "ok fresh",
+			walletType: wallet.WalletTypeDeterministic,
+			policy:     wallet.ChangePolicyFresh,
+		},
+		{
+			name:          "ok address",
+			walletType:    wallet.WalletTypeDeterministic,
+			policy:        wallet.ChangePolicyAddress,
+			changeAddress: changeAddr,
+		},
+		{
+			name:       "ok change chain bip44",
+			walletType: wallet.WalletTypeBip44,
+			policy:     wallet.ChangePolicyChangeChain,
+		},
+		{
+			name:       "change chain not supported",
+			walletType: wallet.WalletTypeDeterministic,
+			policy:     wallet.ChangePolicyChangeChain,
+			err:        wallet.NewError(fmt.Errorf("change policy %q is not supported by %s wallets", wallet.ChangePolicyChangeChain, wallet.WalletTypeDeterministic)),
+		},
+		{
+			name:       "invalid policy",
+			walletType: wallet.WalletTypeDeterministic,
+			policy:     "foo",
+			err:        wallet.ErrInvalidChangePolicy,
+		},
+		{
+			name:       "missing change address",
+			walletType: wallet.WalletTypeDeterministic,
+			policy:     wallet.ChangePolicyAddress,
+			err:        wallet.ErrMissingChangeAddress,
+		},
+		{
+			name:          "invalid change address",
+			walletType:    wallet.WalletTypeDeterministic,
+			policy:        wallet.ChangePolicyAddress,
+			changeAddress: "foo",
+			err:           wallet.NewError(fmt.Errorf("invalid change address %q: %v", "foo", "Invalid address length")),
+		},
+		{
+			name:          "change address without address policy",
+			walletType:    wallet.WalletTypeDeterministic,
+			policy:        wallet.ChangePolicyReuseFirst,
+			changeAddress: changeAddr,
+			err:           wallet.NewError(fmt.Errorf("change address is only used by the %q change policy", wallet.ChangePolicyAddress)),
+		},
+		{
+			name:          "wallet doesn't exist",
+			walletType:    wallet.WalletTypeDeterministic,
+			updateWltName: "t1.wlt",
+			policy:        wallet.ChangePolicyReuseFirst,
+			err:           wallet.ErrWalletNotExist,
+		},
+		{
+			name:             "wallet api disabled",
+			walletType:       wallet.WalletTypeDeterministic,
+			disableWalletAPI: true,
+			err:              wallet.ErrWalletAPIDisabled,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: !tc.disableWalletAPI,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			if tc.disableWalletAPI {
+				err = s.SetChangePolicy("t.wlt", tc.policy, tc.changeAddress)
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			w, err := s.CreateWallet("t.wlt", wallet.Options{
+				Seed: bip39.MustNewDefaultMnemonic(),
+				Type: tc.walletType,
+			})
+			require.NoError(t, err)
+
+			wltName := tc.updateWltName
+			if wltName == "" {
+				wltName = w.Filename()
+			}
+
+			err = s.SetChangePolicy(wltName, tc.policy, tc.changeAddress)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			nw, err := s.GetWallet(w.Filename())
+			require.NoError(t, err)
+			require.Equal(t, tc.policy, nw.ChangePolicy())
+			require.Equal(t, tc.changeAddress, nw.ChangePolicyAddress())
+
+			// The policy is saved in the wallet file
+			lw, err := wallet.Load(filepath.Join(dir, w.Filename()))
+			require.NoError(t, err)
+			require.Equal(t, tc.policy, lw.ChangePolicy())
+			require.Equal(t, tc.changeAddress, lw.ChangePolicyAddress())
+
+			if tc.walletType != wallet.WalletTypeDeterministic {
+				return
+			}
+
+			// The change address is chosen by the policy
+			addrs, err := nw.GetAddresses()
+			require.NoError(t, err)
+			addr, err := wallet.ChangeAddress(nw, nil)
+			require.NoError(t, err)
+
+			switch tc.policy {
+			case wallet.ChangePolicyReuseFirst:
+				require.Equal(t, addrs[0], addr)
+			case wallet.ChangePolicyFresh:
+				naddrs, err := nw.GetAddresses(wallet.OptionExternal())
+				require.NoError(t, err)
+				require.Len(t, naddrs, len(addrs)+1)
+				require.Equal(t, naddrs[len(addrs)], addr)
+			case wallet.ChangePolicyAddress:
+				require.Equal(t, tc.changeAddress, addr.String())
+			}
+		})
+	}
+}
+
 func TestServiceEncryptWallet(t *testing.T) {
 	tt := []struct {
 		name             string
@@ -1294,7 +1559,7 @@ func TestServiceEncryptWallet(t *testing.T) {
 						p, s := cipher.GenerateKeyPair()
 						return w.(*collection.Wallet).AddEntry(wallet.Entry{
 							Public:  p,
-							Secret:  s,
+							Secret:  wallet.NewSecretKey(s),
 							Address: cipher.AddressFromPubKey(p),
 						})
 					})
@@ -1513,7 +1778,7 @@ func TestServiceDecryptWallet(t *testing.T) {
 		for i := range seckeys {
 			a := cipher.MustAddressFromSecKey(seckeys[i])
 			require.Equal(t, a, entries[i].Address)
-			require.Equal(t, seckeys[i], entries[i].Secret)
+			require.Equal(t, seckeys[i], entries[i].Secret.SecKey())
 		}
 
 		require.Empty(t, wlt.Secrets())
@@ -1531,9 +1796,9 @@ func TestServiceDecryptWallet(t *testing.T) {
 
 		for _, e := range entries {
 			require.False(t, e.Secret.Null())
-			a := cipher.MustAddressFromSecKey(e.Secret)
+			a := cipher.MustAddressFromSecKey(e.Secret.SecKey())
 			require.Equal(t, a, e.Address)
-			p := cipher.MustPubKeyFromSecKey(e.Secret)
+			p := cipher.MustPubKeyFromSecKey(e.Secret.SecKey())
 			require.Equal(t, p, e.Public)
 		}
 
@@ -1552,9 +1817,9 @@ func TestServiceDecryptWallet(t *testing.T) {
 		require.NoError(t, err)
 		for _, e := range entries {
 			require.False(t, e.Secret.Null())
-			a := cipher.MustAddressFromSecKey(e.Secret)
+			a := cipher.MustAddressFromSecKey(e.Secret.SecKey())
 			require.Equal(t, a, e.Address)
-			p := cipher.MustPubKeyFromSecKey(e.Secret)
+			p := cipher.MustPubKeyFromSecKey(e.Secret.SecKey())
 			require.Equal(t, p, e.Public)
 		}
 
@@ -1626,971 +1891,1149 @@ func TestServiceDecryptWallet(t *testing.T) {
 	}
 }
 
-func TestServiceCreateWalletWithScan(t *testing.T) {
-	seed := "seed1"
-	addrs := make([]cipher.Address, 20)
-	childSeeds := make([]string, 20)
-	lastSeed := []byte(seed)
-	for i := range addrs {
-		s, pk, _, err := cipher.DeterministicKeyPairIterator(lastSeed)
-		require.NoError(t, err)
-		addrs[i] = cipher.AddressFromPubKey(pk)
-		childSeeds[i] = hex.EncodeToString(s)
-		lastSeed = s
-	}
-
-	bip44Seed := "voyage say extend find sheriff surge priority merit ignore maple cash argue"
-	bip44AddrStrs := []string{
-		"9BSEAEE3XGtQ2X43BCT2XCYgheGLQQigEG",
-		"29cnQPHuWHCRF26LEAb2gR83ywnF3F9HduW",
-		"2ZUAv9MGSpDKR3dnKMUnrKqLenV22JXAxzP",
-		"fwNVThqdzH7JMsStoLrTpkVsemesbdGftm",
-		"eyr5KDLTnN6ZZeggeHqDcXnrwmNUi7sGk2",
-		"Aee3J9qoFPLoUEJes6YVzdKHdeuvCrMZeJ",
-		"29MZS8aiYUdEwcruwCPggVJG9YJLsm92FHa",
-		"2Hbm3bwKiEwqNAMAzVJmz5hL1dNTfaA3ju7",
-		"WCaSCwSZnVqtkYeiKryeHjR8LbzE3KbkzJ",
-		"baRjCy1yHfishGdZi3bVaPaL7VJM7FZCSd",
-		"296oQmJJgx35NDApi7YYzj1AryM8fZcjwf3",
-		"cxxxRfy3RRy2YbFTcptRbVTQYcHY1ejRB5",
-		"omLGQm1Z2Y9Bga8v6NQ2hgrpRm1nATzGK9",
-		"2EpZP1E8gTJy799t5CVrZUcxjyFFHwshr6X",
-		"2hgaPG2oNVrkonPxjv4Sx9au6ruw1Y8pjUi",
-		"2bHfa8yjhWB5mTip8j1FjNhB1TGbSBkX3Xu",
-		"VYu5ePSB7ReKm2pysC5JRdCUiTBgDn5Tkw",
-		"2crRqwG3BaurEqNa7eiB5oUNKTQPETfKrFW",
-		"2LVTqqNSTBKE51UC7bZ39bZ6wwmR3sibHBX",
-		"2A8C3h1gsw92Q4Uhn4b385onKrhzuH8UTwE",
-	}
-	bip44Addrs := make([]cipher.Address, len(bip44AddrStrs))
-	for i, a := range bip44AddrStrs {
-		bip44Addrs[i] = cipher.MustDecodeBase58Address(a)
-	}
-
-	bip44ChangeAddrStrs := []string{
-		"oHvj7oy8maES9HJiQHJTp4GvcUcpz3voDq",
-		"2SGMfTFV2zbQzGw7aJm1D5EeEPgych5ixuC",
-		"2ymjULRdbiFoUNJKNhWbQ3JqdE8TXnZkyU",
-		"muvdio7V8vkbUPPJsumVWEqScKHZho6Xmx",
-		"qyQuA2RW4H6NFqhRQmHDt2q28E654PoBsH",
-		"24UcBj42Q7GBx1rmeU5AE7JhcRbpDq9utCV",
-		"2TwjgBxKhK84Qa34HoNc5KbsYfp1NBZyPXk",
-		"fDYKWyDnDaQmpigGAJNK4ZoBZ4WTg5dtRN",
-		"rRMXXm8ufMcqqnLqRX5CoJ8wA6rdWPJEiW",
-		"2D6HN4fSHZqsGaPzCDUjddF6NS4hBsVGRag",
-		"2bXjTvatPW4Z3SaQBr96zJwwYcxp7iemeGC",
-		"tbmXTJWtqtuaAdVvFWS1rmL42omdD4Hkd3",
-		"27NT6t4xJoqcs6PsM2EgNNhx4MMw3ioFVER",
-		"Xym6pAVg2Xjp9sXvCUKrLgq6PSEt1izsRd",
-		"49EqkHPgWPS9W1jDiJXSKdKdNur3pJjADw",
-		"f52mwYtpVNhgMMmJZHQYVBQVHwHXCbP5yX",
-		"2ffeXAKtVUmxucs8mpM2EKMqzYCN3TB7DYx",
-		"2NJERX84xLHMidXtYDtT1rGzhf4HJmCkz47",
-		"wik4VEcja3pz2Wo6SwwfoDGzVzB7ZoosQB",
-		"2gj3qjYQ4MMkv7CdB3PvkbEEPhXikZBD4q6",
-	}
-	bip44ChangeAddrs := make([]cipher.Address, len(bip44ChangeAddrStrs))
-	for i, a := range bip44ChangeAddrStrs {
-		bip44ChangeAddrs[i] = cipher.MustDecodeBase58Address(a)
-	}
-
-	xpub := "xpub6E5WPk37XdM79dy6oJ7iH6NkCvVzxmrCo4zMFFHSZMc5ymZYhReQFWaDcGNZeYYe1ahY2e3RcRZDHLHC98FfzPRfNRcU6ecURpS4RCQRP2w"
-	xpubAddrStrs := []string{
-		"2mhaS6SE2TPSmRRbJvngWQSNXCCVuTic5Zg",
-		"2bq2itwDKteqigxVS9eYJv4Ww9SEfuyGcib",
-		"B7eMXM6nLUqqzkFcosXR3HSVkQ6yUz53n4",
-		"niAy17kBb8vB2pFey8eZnE92e6x9bFGLHp",
-		"N8JbzcqWEPkn6CF3JdZDmEydzECyZ4NhRv",
-		"TcyY3F4xHPCtFFkdDBTC93y684Fmxg2rPd",
-		"kcVFbcrVqAVUHrirp7r3HYHUzrtdFuybez",
-		"bzA7UeUmkuFWn3waGh3z1eQ5xV3TAZpgX2",
-		"2K29ZX6vaqrTRZJbFKX7hzu646wL3pJfF6H",
-		"VKpFCpN4yp46uYbffaeCg7XEvQd6pHNSkV",
-		"2bGUCmS8BcFiX6VQbq6DVvjDsCdu5fgoSSr",
-		"2Yx8dzMgzU5Y2vrAomAtkajWNmUCJ31xNF",
-		"wd2NGBkCygq7cCP899gbiBciM7ZFqphRDM",
-		"2akitSsnetXoc3ejzY4pA8dGjWM76uzPxo3",
-		"WFTbYvNJGAq9wWG644sMvgt4EJ6CvkbvDK",
-		"2FReiyjcQuQBCvjKQzyrhc7QMwdbdBwLvCT",
-		"2RnSh4sZrxCEK5fUSCLR8JuwzGd6K5mzdrA",
-		"ujJHitcLhQZB393qrdJvdfM4AyEPMmDhcV",
-		"25jWWmrgU8Z9HYVCsAXYmCjF1jQqe5DDnqr",
-		"rsaKzohU5erbR6FX1whWu9Ke4q2jLkLBeJ",
-	}
-	xpubAddrs := make([]cipher.Address, len(xpubAddrStrs))
-	for i, a := range xpubAddrStrs {
-		xpubAddrs[i] = cipher.MustDecodeBase58Address(a)
-	}
-
-	tf := make(mockTxnsFinder, 20)
-
-	type exp struct {
-		err      error
-		seed     string
-		lastSeed string
-		xpub     string
-		entryNum int
-		addrs    []cipher.Address
-	}
-
+func TestServiceUpgradeCrypto(t *testing.T) {
 	tt := []struct {
 		name             string
 		opts             wallet.Options
+		upgradeWltName   string
+		password         []byte
+		cryptoType       crypto.CryptoType
+		upgradeOpts      []wallet.Option
 		disableWalletAPI bool
-		expect           exp
+		err              error
 	}{
 		{
-			name: "no coins and scan 0, unencrypted",
+			name: "ok deterministic argon2id",
 			opts: wallet.Options{
-				Seed: seed,
-				Type: wallet.WalletTypeDeterministic,
-				TF:   tf,
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+				Seed:       "seed",
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeSha256Xor,
+				Type:       wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			upgradeOpts:    []wallet.Option{wallet.OptionArgon2Params(1, 64)},
 		},
 		{
-			name: "no coins and scan 0, encrypted",
+			name: "ok bip44 argon2id",
 			opts: wallet.Options{
-				Seed:     seed,
-				Encrypt:  true,
-				Password: []byte("pwd"),
-				Type:     wallet.WalletTypeDeterministic,
-				TF:       tf,
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+				Seed:       "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeScryptChacha20poly1305Insecure,
+				Type:       wallet.WalletTypeBip44,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			upgradeOpts:    []wallet.Option{wallet.OptionArgon2Params(1, 64)},
 		},
 		{
-			name: "no coins and scan 1, unencrypted",
+			name: "ok collection argon2id",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 1,
-				Type:  wallet.WalletTypeDeterministic,
-				TF:    tf,
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeSha256Xor,
+				Type:       wallet.WalletTypeCollection,
 			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			upgradeOpts:    []wallet.Option{wallet.OptionArgon2Params(1, 64)},
+		},
+		{
+			name: "ok deterministic back to sha256-xor",
+			opts: wallet.Options{
+				Seed:       "seed",
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeScryptChacha20poly1305Insecure,
+				Type:       wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeSha256Xor,
 		},
 		{
-			name: "no coins and scan 1, encrypted",
+			name: "wallet not exist",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    1,
 				Type:     wallet.WalletTypeDeterministic,
-				TF:       tf,
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
 			},
+			upgradeWltName: "t.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			err:            wallet.ErrWalletNotExist,
 		},
 		{
-			name: "no coins and scan 10, unencrypted",
+			name: "wallet not encrypted",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 10,
-				Type:  wallet.WalletTypeDeterministic,
-				TF:    tf,
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+				Seed: "seed",
+				Type: wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			err:            wallet.ErrWalletNotEncrypted,
 		},
 		{
-			name: "scan 1 get 1, unencrypted",
+			name: "unknown crypto type",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 1,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[0]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("pwd"),
+			cryptoType:     crypto.CryptoType("foo"),
+			err:            wallet.NewError(errors.New("can not find crypto foo in crypto table")),
 		},
 		{
-			name: "scan 5 get 5, unencrypted",
+			name: "invalid password",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[4]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[4],
-				entryNum: 5,
-				addrs:    addrs,
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName: "test.wlt",
+			password:       []byte("wrong password"),
+			cryptoType:     crypto.CryptoTypeArgon2idChacha20poly1305,
+			err:            wallet.ErrInvalidPassword,
 		},
 		{
-			name: "scan 5 get 1, unencrypted",
+			name: "wallet api disabled",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[0]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeDeterministic,
 			},
+			upgradeWltName:   "test.wlt",
+			password:         []byte("pwd"),
+			cryptoType:       crypto.CryptoTypeArgon2idChacha20poly1305,
+			disableWalletAPI: true,
+			err:              wallet.ErrWalletAPIDisabled,
 		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: !tc.disableWalletAPI,
+			})
+			require.NoError(t, err)
+
+			if tc.disableWalletAPI {
+				_, err = s.UpgradeCrypto(tc.upgradeWltName, tc.password, tc.cryptoType, tc.upgradeOpts...)
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			w, err := s.CreateWallet("test.wlt", tc.opts)
+			require.NoError(t, err)
+			addrs, err := w.GetAddresses()
+			require.NoError(t, err)
+
+			w, err = s.UpgradeCrypto(tc.upgradeWltName, tc.password, tc.cryptoType, tc.upgradeOpts...)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.True(t, w.IsEncrypted())
+			require.Equal(t, tc.cryptoType, w.CryptoType())
+			require.Empty(t, w.Seed())
+
+			// Loads the wallet from the file and checks the secrets are decrypted with the new crypto type
+			w1, err := s.Load(filepath.Join(dir, "test.wlt"))
+			require.NoError(t, err)
+			require.Equal(t, tc.cryptoType, w1.CryptoType())
+			if len(tc.upgradeOpts) > 0 {
+				iterations, memory := w1.Argon2Params()
+				require.Equal(t, uint32(1), iterations)
+				require.Equal(t, uint32(64), memory)
+			}
+
+			err = s.ViewSecrets("test.wlt", tc.password, func(w wallet.Wallet) error {
+				require.Equal(t, tc.opts.Seed, w.Seed())
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.Equal(t, addrs, es.GetAddresses())
+				for _, e := range es {
+					require.Equal(t, cipher.MustAddressFromSecKey(e.Secret.SecKey()), e.Address)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceChangePassword(t *testing.T) {
+	tt := []struct {
+		name        string
+		opts        wallet.Options
+		wltName     string
+		password    []byte
+		newPassword []byte
+		readOnly    bool
+		err         error
+	}{
 		{
-			name: "scan 5 get 2, unencrypted",
+			name: "ok deterministic",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[1]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[1],
-				entryNum: 2,
-				addrs:    addrs,
+				Seed:       "seed",
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeSha256Xor,
+				Type:       wallet.WalletTypeDeterministic,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
 		},
-
 		{
-			name: "scan 5 get 3, encrypted",
+			name: "ok bip44",
 			opts: wallet.Options{
-				Seed:     seed,
-				Encrypt:  true,
-				Password: []byte("pwd"),
-				ScanN:    5,
-				Type:     wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[2]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[2],
-				entryNum: 3,
-				addrs:    addrs,
+				Seed:       "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeScryptChacha20poly1305Insecure,
+				Type:       wallet.WalletTypeBip44,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
 		},
 		{
-			name: "scan 5 get 4, unencrypted",
+			name: "ok collection",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[2]: true,
-					addrs[3]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[3],
-				entryNum: 4,
-				addrs:    addrs,
+				Encrypt:    true,
+				Password:   []byte("pwd"),
+				CryptoType: crypto.CryptoTypeSha256Xor,
+				Type:       wallet.WalletTypeCollection,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
 		},
 		{
-			name: "scan 5 get 5, unencrypted",
+			name: "wallet not encrypted",
 			opts: wallet.Options{
-				Seed:  seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[3]: true,
-					addrs[4]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[4],
-				entryNum: 5,
-				addrs:    addrs,
+				Seed: "seed",
+				Type: wallet.WalletTypeDeterministic,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			err:         wallet.ErrWalletNotEncrypted,
 		},
 		{
-			name: "scan 0 get 1, encrypted",
+			name: "wallet not exist",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    0,
 				Type:     wallet.WalletTypeDeterministic,
-				TF:       mockTxnsFinder{},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
 			},
+			wltName:     "t.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			err:         wallet.ErrWalletNotExist,
 		},
 		{
-			name: "scan 1 get 1, encrypted",
+			name: "invalid password",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    1,
 				Type:     wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[0]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("wrong password"),
+			newPassword: []byte("new pwd"),
+			err:         wallet.ErrInvalidPassword,
 		},
 		{
-			name: "scan 2 get 1, encrypted",
+			name: "missing password",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    2,
 				Type:     wallet.WalletTypeDeterministic,
-				TF:       mockTxnsFinder{},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[0],
-				entryNum: 1,
-				addrs:    addrs,
 			},
+			wltName:     "test.wlt",
+			newPassword: []byte("new pwd"),
+			err:         wallet.ErrMissingPassword,
 		},
 		{
-			name: "scan 2 get 2, encrypted",
+			name: "missing new password",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    2,
 				Type:     wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[1]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[1],
-				entryNum: 2,
-				addrs:    addrs,
 			},
+			wltName:  "test.wlt",
+			password: []byte("pwd"),
+			err:      wallet.ErrMissingNewPassword,
 		},
 		{
-			name: "scan 5 get 5, encrypted",
+			name: "wallet read-only",
 			opts: wallet.Options{
-				Seed:     seed,
+				Seed:     "seed",
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    5,
 				Type:     wallet.WalletTypeDeterministic,
-				TF: mockTxnsFinder{
-					addrs[3]: true,
-					addrs[4]: true,
-				},
-			},
-			expect: exp{
-				err:      nil,
-				seed:     seed,
-				lastSeed: childSeeds[4],
-				entryNum: 5,
-				addrs:    addrs,
 			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			readOnly:    true,
+			err:         wallet.ErrWalletReadOnly,
 		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+
+			w, err := s.CreateWallet("test.wlt", tc.opts)
+			require.NoError(t, err)
+			addrs, err := w.GetAddresses()
+			require.NoError(t, err)
+
+			if tc.readOnly {
+				require.NoError(t, s.SetReadOnly("test.wlt", true))
+			}
+
+			w, err = s.ChangePassword(tc.wltName, tc.password, tc.newPassword)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.True(t, w.IsEncrypted())
+			require.Equal(t, tc.opts.CryptoType, w.CryptoType())
+			require.Empty(t, w.Seed())
+
+			// The wallet file is saved encrypted with the new password
+			w1, err := s.Load(filepath.Join(dir, "test.wlt"))
+			require.NoError(t, err)
+			require.True(t, w1.IsEncrypted())
+			require.Empty(t, w1.Seed())
+
+			err = s.ViewSecrets("test.wlt", tc.password, func(w wallet.Wallet) error {
+				return nil
+			})
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+
+			err = s.ViewSecrets("test.wlt", tc.newPassword, func(w wallet.Wallet) error {
+				require.Equal(t, tc.opts.Seed, w.Seed())
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.Equal(t, addrs, es.GetAddresses())
+				for _, e := range es {
+					require.Equal(t, cipher.MustAddressFromSecKey(e.Secret.SecKey()), e.Address)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceCreateWalletWithScan(t *testing.T) {
+	seed := "seed1"
+	addrs := make([]cipher.Address, 20)
+	childSeeds := make([]string, 20)
+	lastSeed := []byte(seed)
+	for i := range addrs {
+		s, pk, _, err := cipher.DeterministicKeyPairIterator(lastSeed)
+		require.NoError(t, err)
+		addrs[i] = cipher.AddressFromPubKey(pk)
+		childSeeds[i] = hex.EncodeToString(s)
+		lastSeed = s
+	}
+
+	bip44Seed := "voyage say extend find sheriff surge priority merit ignore maple cash argue"
+	bip44AddrStrs := []string{
+		"9BSEAEE3XGtQ2X43BCT2XCYgheGLQQigEG",
+		"29cnQPHuWHCRF26LEAb2gR83ywnF3F9HduW",
+		"2ZUAv9MGSpDKR3dnKMUnrKqLenV22JXAxzP",
+		"fwNVThqdzH7JMsStoLrTpkVsemesbdGftm",
+		"eyr5KDLTnN6ZZeggeHqDcXnrwmNUi7sGk2",
+		"Aee3J9qoFPLoUEJes6YVzdKHdeuvCrMZeJ",
+		"29MZS8aiYUdEwcruwCPggVJG9YJLsm92FHa",
+		"2Hbm3bwKiEwqNAMAzVJmz5hL1dNTfaA3ju7",
+		"WCaSCwSZnVqtkYeiKryeHjR8LbzE3KbkzJ",
+		"baRjCy1yHfishGdZi3bVaPaL7VJM7FZCSd",
+		"296oQmJJgx35NDApi7YYzj1AryM8fZcjwf3",
+		"cxxxRfy3RRy2YbFTcptRbVTQYcHY1ejRB5",
+		"omLGQm1Z2Y9Bga8v6NQ2hgrpRm1nATzGK9",
+		"2EpZP1E8gTJy799t5CVrZUcxjyFFHwshr6X",
+		"2hgaPG2oNVrkonPxjv4Sx9au6ruw1Y8pjUi",
+		"2bHfa8yjhWB5mTip8j1FjNhB1TGbSBkX3Xu",
+		"VYu5ePSB7ReKm2pysC5JRdCUiTBgDn5Tkw",
+		"2crRqwG3BaurEqNa7eiB5oUNKTQPETfKrFW",
+		"2LVTqqNSTBKE51UC7bZ39bZ6wwmR3sibHBX",
+		"2A8C3h1gsw92Q4Uhn4b385onKrhzuH8UTwE",
+	}
+	bip44Addrs := make([]cipher.Address, len(bip44AddrStrs))
+	for i, a := range bip44AddrStrs {
+		bip44Addrs[i] = cipher.MustDecodeBase58Address(a)
+	}
+
+	bip44ChangeAddrStrs := []string{
+		"oHvj7oy8maES9HJiQHJTp4GvcUcpz3voDq",
+		"2SGMfTFV2zbQzGw7aJm1D5EeEPgych5ixuC",
+		"2ymjULRdbiFoUNJKNhWbQ3JqdE8TXnZkyU",
+		"muvdio7V8vkbUPPJsumVWEqScKHZho6Xmx",
+		"qyQuA2RW4H6NFqhRQmHDt2q28E654PoBsH",
+		"24UcBj42Q7GBx1rmeU5AE7JhcRbpDq9utCV",
+		"2TwjgBxKhK84Qa34HoNc5KbsYfp1NBZyPXk",
+		"fDYKWyDnDaQmpigGAJNK4ZoBZ4WTg5dtRN",
+		"rRMXXm8ufMcqqnLqRX5CoJ8wA6rdWPJEiW",
+		"2D6HN4fSHZqsGaPzCDUjddF6NS4hBsVGRag",
+		"2bXjTvatPW4Z3SaQBr96zJwwYcxp7iemeGC",
+		"tbmXTJWtqtuaAdVvFWS1rmL42omdD4Hkd3",
+		"27NT6t4xJoqcs6PsM2EgNNhx4MMw3ioFVER",
+		"Xym6pAVg2Xjp9sXvCUKrLgq6PSEt1izsRd",
+		"49EqkHPgWPS9W1jDiJXSKdKdNur3pJjADw",
+		"f52mwYtpVNhgMMmJZHQYVBQVHwHXCbP5yX",
+		"2ffeXAKtVUmxucs8mpM2EKMqzYCN3TB7DYx",
+		"2NJERX84xLHMidXtYDtT1rGzhf4HJmCkz47",
+		"wik4VEcja3pz2Wo6SwwfoDGzVzB7ZoosQB",
+		"2gj3qjYQ4MMkv7CdB3PvkbEEPhXikZBD4q6",
+	}
+	bip44ChangeAddrs := make([]cipher.Address, len(bip44ChangeAddrStrs))
+	for i, a := range bip44ChangeAddrStrs {
+		bip44ChangeAddrs[i] = cipher.MustDecodeBase58Address(a)
+	}
+
+	xpub := "xpub6E5WPk37XdM79dy6oJ7iH6NkCvVzxmrCo4zMFFHSZMc5ymZYhReQFWaDcGNZeYYe1ahY2e3RcRZDHLHC98FfzPRfNRcU6ecURpS4RCQRP2w"
+	xpubAddrStrs := []string{
+		"2mhaS6SE2TPSmRRbJvngWQSNXCCVuTic5Zg",
+		"2bq2itwDKteqigxVS9eYJv4Ww9SEfuyGcib",
+		"B7eMXM6nLUqqzkFcosXR3HSVkQ6yUz53n4",
+		"niAy17kBb8vB2pFey8eZnE92e6x9bFGLHp",
+		"N8JbzcqWEPkn6CF3JdZDmEydzECyZ4NhRv",
+		"TcyY3F4xHPCtFFkdDBTC93y684Fmxg2rPd",
+		"kcVFbcrVqAVUHrirp7r3HYHUzrtdFuybez",
+		"bzA7UeUmkuFWn3waGh3z1eQ5xV3TAZpgX2",
+		"2K29ZX6vaqrTRZJbFKX7hzu646wL3pJfF6H",
+		"VKpFCpN4yp46uYbffaeCg7XEvQd6pHNSkV",
+		"2bGUCmS8BcFiX6VQbq6DVvjDsCdu5fgoSSr",
+		"2Yx8dzMgzU5Y2vrAomAtkajWNmUCJ31xNF",
+		"wd2NGBkCygq7cCP899gbiBciM7ZFqphRDM",
+		"2akitSsnetXoc3ejzY4pA8dGjWM76uzPxo3",
+		"WFTbYvNJGAq9wWG644sMvgt4EJ6CvkbvDK",
+		"2FReiyjcQuQBCvjKQzyrhc7QMwdbdBwLvCT",
+		"2RnSh4sZrxCEK5fUSCLR8JuwzGd6K5mzdrA",
+		"ujJHitcLhQZB393qrdJvdfM4AyEPMmDhcV",
+		"25jWWmrgU8Z9HYVCsAXYmCjF1jQqe5DDnqr",
+		"rsaKzohU5erbR6FX1whWu9Ke4q2jLkLBeJ",
+	}
+	xpubAddrs := make([]cipher.Address, len(xpubAddrStrs))
+	for i, a := range xpubAddrStrs {
+		xpubAddrs[i] = cipher.MustDecodeBase58Address(a)
+	}
+
+	tf := make(mockTxnsFinder, 20)
+
+	type exp struct {
+		err      error
+		seed     string
+		lastSeed string
+		xpub     string
+		entryNum int
+		addrs    []cipher.Address
+	}
+
+	tt := []struct {
+		name             string
+		opts             wallet.Options
+		disableWalletAPI bool
+		expect           exp
+	}{
 		{
-			name: "bip44 no coins and scan 0, unencrypted",
+			name: "no coins and scan 0, unencrypted",
 			opts: wallet.Options{
-				Seed: bip44Seed,
-				Type: wallet.WalletTypeBip44,
+				Seed: seed,
+				Type: wallet.WalletTypeDeterministic,
 				TF:   tf,
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 no coins and scan 0, encrypted",
+			name: "no coins and scan 0, encrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
+				Seed:     seed,
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				Type:     wallet.WalletTypeBip44,
+				Type:     wallet.WalletTypeDeterministic,
 				TF:       tf,
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 no coins and scan 1, unencrypted",
+			name: "no coins and scan 1, unencrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
+				Seed:  seed,
 				ScanN: 1,
-				Type:  wallet.WalletTypeBip44,
+				Type:  wallet.WalletTypeDeterministic,
 				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 no coins and scan 1, encrypted",
+			name: "no coins and scan 1, encrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
+				Seed:     seed,
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				ScanN:    2,
-				Type:     wallet.WalletTypeBip44,
+				ScanN:    1,
+				Type:     wallet.WalletTypeDeterministic,
 				TF:       tf,
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 no coins and scan 10, unencrypted",
+			name: "no coins and scan 10, unencrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
+				Seed:  seed,
 				ScanN: 10,
-				Type:  wallet.WalletTypeBip44,
+				Type:  wallet.WalletTypeDeterministic,
 				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 0 get 1, unencrypted",
+			name: "scan 1 get 1, unencrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
+				Seed:  seed,
 				ScanN: 1,
-				Type:  wallet.WalletTypeBip44,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
+					addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 0 get 1, encrypted",
+			name: "scan 5 get 5, unencrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
-				ScanN:    1,
-				Type:     wallet.WalletTypeBip44,
-				Encrypt:  true,
-				Password: []byte("pwd"),
+				Seed:  seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
+					addrs[4]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[4],
+				entryNum: 5,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 1 get 1, unencrypted",
+			name: "scan 5 get 1, unencrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
-				ScanN: 1,
-				Type:  wallet.WalletTypeBip44,
+				Seed:  seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
+					addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 1 get 1, encrypted",
+			name: "scan 5 get 2, unencrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
-				ScanN:    1,
-				Type:     wallet.WalletTypeBip44,
-				Encrypt:  true,
-				Password: []byte("pwd"),
+				Seed:  seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
+					addrs[1]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
+				seed:     seed,
+				lastSeed: childSeeds[1],
 				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				addrs:    addrs,
 			},
 		},
+
 		{
-			name: "bip44 scan 2 get 2, unencrypted",
+			name: "scan 5 get 3, encrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
-				ScanN: 2,
-				Type:  wallet.WalletTypeBip44,
+				Seed:     seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    5,
+				Type:     wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
-					bip44Addrs[1]: true,
+					addrs[2]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
+				seed:     seed,
+				lastSeed: childSeeds[2],
 				entryNum: 3,
-				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 2 get 2, encrypted",
+			name: "scan 5 get 4, unencrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
-				ScanN:    2,
-				Type:     wallet.WalletTypeBip44,
-				Encrypt:  true,
-				Password: []byte("pwd"),
+				Seed:  seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
-					bip44Addrs[1]: true,
+					addrs[2]: true,
+					addrs[3]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 3,
-				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[3],
+				entryNum: 4,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 5 get 1, unencrypted",
+			name: "scan 5 get 5, unencrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
+				Seed:  seed,
 				ScanN: 5,
-				Type:  wallet.WalletTypeBip44,
+				Type:  wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[0]: true,
+					addrs[3]: true,
+					addrs[4]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 2,
-				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[4],
+				entryNum: 5,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 5 get 2, unencrypted",
+			name: "scan 0 get 1, encrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeBip44,
+				Seed:     seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    0,
+				Type:     wallet.WalletTypeDeterministic,
+				TF:       mockTxnsFinder{},
+			},
+			expect: exp{
+				err:      nil,
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
+			},
+		},
+		{
+			name: "scan 1 get 1, encrypted",
+			opts: wallet.Options{
+				Seed:     seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    1,
+				Type:     wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[1]: true,
+					addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 3,
-				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 5 get 5, unencrypted",
+			name: "scan 2 get 1, encrypted",
 			opts: wallet.Options{
-				Seed:  bip44Seed,
-				ScanN: 5,
-				Type:  wallet.WalletTypeBip44,
+				Seed:     seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    2,
+				Type:     wallet.WalletTypeDeterministic,
+				TF:       mockTxnsFinder{},
+			},
+			expect: exp{
+				err:      nil,
+				seed:     seed,
+				lastSeed: childSeeds[0],
+				entryNum: 1,
+				addrs:    addrs,
+			},
+		},
+		{
+			name: "scan 2 get 2, encrypted",
+			opts: wallet.Options{
+				Seed:     seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    2,
+				Type:     wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[4]: true,
+					addrs[1]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 6,
-				addrs:    combineAddrs(bip44Addrs[:5], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[1],
+				entryNum: 2,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "bip44 scan 5 get 5, encrypted",
+			name: "scan 5 get 5, encrypted",
 			opts: wallet.Options{
-				Seed:     bip44Seed,
+				Seed:     seed,
 				Encrypt:  true,
 				Password: []byte("pwd"),
 				ScanN:    5,
-				Type:     wallet.WalletTypeBip44,
+				Type:     wallet.WalletTypeDeterministic,
 				TF: mockTxnsFinder{
-					bip44Addrs[3]: true,
-					bip44Addrs[4]: true,
+					addrs[3]: true,
+					addrs[4]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				seed:     bip44Seed,
-				entryNum: 6,
-				addrs:    combineAddrs(bip44Addrs[:5], bip44ChangeAddrs[:1]),
+				seed:     seed,
+				lastSeed: childSeeds[4],
+				entryNum: 5,
+				addrs:    addrs,
 			},
 		},
 		{
-			name: "xpub no coins and scan 0, unencrypted",
+			name: "bip44 no coins and scan 0, unencrypted",
 			opts: wallet.Options{
-				XPub: xpub,
-				Type: wallet.WalletTypeXPub,
+				Seed: bip44Seed,
+				Type: wallet.WalletTypeBip44,
 				TF:   tf,
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub no coins and scan 0",
+			name: "bip44 no coins and scan 0, encrypted",
 			opts: wallet.Options{
-				XPub: xpub,
-				Type: wallet.WalletTypeXPub,
-				TF:   tf,
+				Seed:     bip44Seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeBip44,
+				TF:       tf,
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub no coins and scan 1, unencrypted",
+			name: "bip44 no coins and scan 1, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
+				Seed:  bip44Seed,
 				ScanN: 1,
-				Type:  wallet.WalletTypeXPub,
+				Type:  wallet.WalletTypeBip44,
 				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub no coins and scan 1",
+			name: "bip44 no coins and scan 1, encrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 1,
-				Type:  wallet.WalletTypeXPub,
-				TF:    tf,
+				Seed:     bip44Seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    2,
+				Type:     wallet.WalletTypeBip44,
+				TF:       tf,
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub no coins and scan 10, unencrypted",
+			name: "bip44 no coins and scan 10, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
+				Seed:  bip44Seed,
 				ScanN: 10,
-				Type:  wallet.WalletTypeXPub,
+				Type:  wallet.WalletTypeBip44,
 				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 0 get 1, unencrypted",
+			name: "bip44 scan 0 get 1, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 0,
-				Type:  wallet.WalletTypeXPub,
+				Seed:  bip44Seed,
+				ScanN: 1,
+				Type:  wallet.WalletTypeBip44,
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 0 get 1",
+			name: "bip44 scan 0 get 1, encrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 0,
-				Type:  wallet.WalletTypeXPub,
+				Seed:     bip44Seed,
+				ScanN:    1,
+				Type:     wallet.WalletTypeBip44,
+				Encrypt:  true,
+				Password: []byte("pwd"),
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 1 get 1, unencrypted",
+			name: "bip44 scan 1 get 1, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
+				Seed:  bip44Seed,
 				ScanN: 1,
-				Type:  wallet.WalletTypeXPub,
+				Type:  wallet.WalletTypeBip44,
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 1 get 1",
+			name: "bip44 scan 1 get 1, encrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 1,
-				Type:  wallet.WalletTypeXPub,
+				Seed:     bip44Seed,
+				ScanN:    1,
+				Type:     wallet.WalletTypeBip44,
+				Encrypt:  true,
+				Password: []byte("pwd"),
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 2,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 2 get 1, unencrypted",
+			name: "bip44 scan 2 get 2, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
+				Seed:  bip44Seed,
 				ScanN: 2,
-				Type:  wallet.WalletTypeXPub,
+				Type:  wallet.WalletTypeBip44,
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
+					bip44Addrs[1]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 3,
+				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 2 get 1",
+			name: "bip44 scan 2 get 2, encrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 2,
-				Type:  wallet.WalletTypeXPub,
+				Seed:     bip44Seed,
+				ScanN:    2,
+				Type:     wallet.WalletTypeBip44,
+				Encrypt:  true,
+				Password: []byte("pwd"),
 				TF: mockTxnsFinder{
-					xpubAddrs[0]: true,
+					bip44Addrs[0]: true,
+					bip44Addrs[1]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
-				entryNum: 1,
-				addrs:    xpubAddrs,
+				seed:     bip44Seed,
+				entryNum: 3,
+				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 2 get 2, unencrypted",
+			name: "bip44 scan 5 get 1, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 2,
-				Type:  wallet.WalletTypeXPub,
+				Seed:  bip44Seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeBip44,
 				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
+					bip44Addrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
-				xpub:     xpub,
+				seed:     bip44Seed,
 				entryNum: 2,
-				addrs:    xpubAddrs,
+				addrs:    combineAddrs(bip44Addrs[:1], bip44ChangeAddrs[:1]),
 			},
 		},
 		{
-			name: "xpub scan 2 get 2",
+			name: "bip44 scan 5 get 2, unencrypted",
 			opts: wallet.Options{
-				XPub:  xpub,
-				ScanN: 2,
-				Type:  wallet.WalletTypeXPub,
+				Seed:  bip44Seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeBip44,
 				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
+					bip44Addrs[1]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				seed:     bip44Seed,
+				entryNum: 3,
+				addrs:    combineAddrs(bip44Addrs[:2], bip44ChangeAddrs[:1]),
+			},
+		},
+		{
+			name: "bip44 scan 5 get 5, unencrypted",
+			opts: wallet.Options{
+				Seed:  bip44Seed,
+				ScanN: 5,
+				Type:  wallet.WalletTypeBip44,
+				TF: mockTxnsFinder{
+					bip44Addrs[4]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				seed:     bip44Seed,
+				entryNum: 6,
+				addrs:    combineAddrs(bip44Addrs[:5], bip44ChangeAddrs[:1]),
+			},
+		},
+		{
+			name: "bip44 scan 5 get 5, encrypted",
+			opts: wallet.Options{
+				Seed:     bip44Seed,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				ScanN:    5,
+				Type:     wallet.WalletTypeBip44,
+				TF: mockTxnsFinder{
+					bip44Addrs[3]: true,
+					bip44Addrs[4]: true,
 				},
 			},
+			expect: exp{
+				err:      nil,
+				seed:     bip44Seed,
+				entryNum: 6,
+				addrs:    combineAddrs(bip44Addrs[:5], bip44ChangeAddrs[:1]),
+			},
+		},
+		{
+			name: "xpub no coins and scan 0, unencrypted",
+			opts: wallet.Options{
+				XPub: xpub,
+				Type: wallet.WalletTypeXPub,
+				TF:   tf,
+			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 2,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 5, unencrypted",
+			name: "xpub no coins and scan 0",
+			opts: wallet.Options{
+				XPub: xpub,
+				Type: wallet.WalletTypeXPub,
+				TF:   tf,
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 1,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub no coins and scan 1, unencrypted",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 1,
 				Type:  wallet.WalletTypeXPub,
-				TF: mockTxnsFinder{
-					xpubAddrs[4]: true,
-				},
+				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 5,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 4, unencrypted",
+			name: "xpub no coins and scan 1",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 1,
 				Type:  wallet.WalletTypeXPub,
-				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
-					xpubAddrs[3]: true,
-				},
+				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 4,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 3, unencrypted",
+			name: "xpub no coins and scan 10, unencrypted",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 10,
 				Type:  wallet.WalletTypeXPub,
-				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
-					xpubAddrs[2]: true,
-				},
+				TF:    tf,
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 3,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 2, unencrypted",
+			name: "xpub scan 0 get 1, unencrypted",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 0,
 				Type:  wallet.WalletTypeXPub,
 				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
+					xpubAddrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 2,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 1, unencrypted",
+			name: "xpub scan 0 get 1",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 0,
 				Type:  wallet.WalletTypeXPub,
 				TF: mockTxnsFinder{
 					xpubAddrs[0]: true,
@@ -2603,39 +3046,228 @@ func TestServiceCreateWalletWithScan(t *testing.T) {
 				addrs:    xpubAddrs,
 			},
 		},
-
 		{
-			name: "xpub scan 5 get 2",
+			name: "xpub scan 1 get 1, unencrypted",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 1,
 				Type:  wallet.WalletTypeXPub,
 				TF: mockTxnsFinder{
-					xpubAddrs[1]: true,
+					xpubAddrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 2,
+				entryNum: 1,
 				addrs:    xpubAddrs,
 			},
 		},
 		{
-			name: "xpub scan 5 get 5",
+			name: "xpub scan 1 get 1",
 			opts: wallet.Options{
 				XPub:  xpub,
-				ScanN: 5,
+				ScanN: 1,
 				Type:  wallet.WalletTypeXPub,
 				TF: mockTxnsFinder{
-					xpubAddrs[3]: true,
-					xpubAddrs[4]: true,
+					xpubAddrs[0]: true,
 				},
 			},
 			expect: exp{
 				err:      nil,
 				xpub:     xpub,
-				entryNum: 5,
+				entryNum: 1,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 2 get 1, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 2,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[0]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 1,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 2 get 1",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 2,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[0]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 1,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 2 get 2, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 2,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 2,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 2 get 2",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 2,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 2,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 5, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[4]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 5,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 4, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+					xpubAddrs[3]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 4,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 3, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+					xpubAddrs[2]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 3,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 2, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 2,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 1, unencrypted",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[0]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 1,
+				addrs:    xpubAddrs,
+			},
+		},
+
+		{
+			name: "xpub scan 5 get 2",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[1]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 2,
+				addrs:    xpubAddrs,
+			},
+		},
+		{
+			name: "xpub scan 5 get 5",
+			opts: wallet.Options{
+				XPub:  xpub,
+				ScanN: 5,
+				Type:  wallet.WalletTypeXPub,
+				TF: mockTxnsFinder{
+					xpubAddrs[3]: true,
+					xpubAddrs[4]: true,
+				},
+			},
+			expect: exp{
+				err:      nil,
+				xpub:     xpub,
+				entryNum: 5,
 				addrs:    xpubAddrs,
 			},
 		},
@@ -3042,6 +3674,62 @@ func TestServiceScanAddresses(t *testing.T) {
 	}
 }
 
+func TestServiceCollectionWatchWallet(t *testing.T) {
+	addrs := []cipher.Address{testutil.MakeAddress(), testutil.MakeAddress()}
+	addrStrs := []string{addrs[0].String(), addrs[1].String()}
+
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreateWallet("watch.wlt", wallet.Options{
+		Type:  wallet.WalletTypeCollectionWatch,
+		Label: "deposits",
+	})
+	require.Equal(t, watchwallet.ErrMissingAddresses, err)
+
+	w, err := s.CreateWallet("watch.wlt", wallet.Options{
+		Type:      wallet.WalletTypeCollectionWatch,
+		Label:     "deposits",
+		Addresses: addrStrs,
+		ScanN:     5,
+		TF:        mockTxnsFinder{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.WalletTypeCollectionWatch, w.Type())
+
+	// Scanning a wallet with no keys does not error
+	scanned, err := s.ScanAddresses("watch.wlt", nil, 5, mockTxnsFinder{})
+	require.NoError(t, err)
+	require.Empty(t, scanned)
+
+	// The wallet can not generate addresses
+	_, err = s.NewAddresses("watch.wlt", nil, 1)
+	require.Equal(t, wallet.NewError(errors.New("A collection-watch wallet does not implement GenerateAddresses")), err)
+
+	// The wallet can not be encrypted
+	_, err = s.EncryptWallet("watch.wlt", []byte("pwd"))
+	require.Equal(t, wallet.NewError(errors.New("collection-watch wallet does not support encryption")), err)
+
+	// Reloads the wallets from disk
+	s, err = wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err = s.GetWallet("watch.wlt")
+	require.NoError(t, err)
+	wAddrs, err := w.GetAddresses()
+	require.NoError(t, err)
+	require.Equal(t, addrs, wallet.SkycoinAddresses(wAddrs))
+}
+
 func TestGetWalletSeed(t *testing.T) {
 	tt := []struct {
 		name             string
@@ -3174,49 +3862,422 @@ func TestGetWalletSeed(t *testing.T) {
 	}
 }
 
-func TestServiceView(t *testing.T) {
+func TestGetWalletSeedShares(t *testing.T) {
+	groups := []slip39.Group{{MemberThreshold: 1, MemberCount: 1}, {MemberThreshold: 2, MemberCount: 3}}
+
 	tt := []struct {
 		name             string
-		wltName          string
 		opts             wallet.Options
-		viewWltName      string
-		action           func(*testing.T) func(wallet.Wallet) error
+		id               string
+		pwd              []byte
+		groupThreshold   int
 		disableWalletAPI bool
-		err              error
+		disableSeedAPI   bool
+		expectErr        error
 	}{
 		{
-			name:        "ok, encrypted collection wallet",
-			wltName:     "test-view-collection-encrypted.wlt",
-			viewWltName: "test-view-collection-encrypted.wlt",
+			name: "ok bip44",
 			opts: wallet.Options{
-				Type:     wallet.WalletTypeCollection,
+				Seed:           bip39.MustNewDefaultMnemonic(),
+				SeedPassphrase: "seed-passphrase",
+				Encrypt:        true,
+				Password:       []byte("pwd"),
+				Type:           wallet.WalletTypeBip44,
+			},
+			id:             "wallet.wlt",
+			pwd:            []byte("pwd"),
+			groupThreshold: 1,
+		},
+		{
+			name: "ok deterministic with bip39 seed, group threshold 2",
+			opts: wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
 				Encrypt:  true,
 				Password: []byte("pwd"),
-				Label:    "foowlt",
-			},
-			action: func(t *testing.T) func(wallet.Wallet) error {
-				return func(w wallet.Wallet) error {
-					require.Equal(t, "foowlt", w.Label())
-					checkNoSensitiveData(t, w)
-
-					// Modify the wallet pointer in order to check that this references a clone and not the original
-					w.SetLabel(w.Label() + "foo")
-
-					return nil
-				}
+				Type:     wallet.WalletTypeDeterministic,
 			},
+			id:             "wallet.wlt",
+			pwd:            []byte("pwd"),
+			groupThreshold: 2,
 		},
-
 		{
-			name:        "ok, unencrypted collection wallet",
-			wltName:     "test-view-collection-unencrypted.wlt",
-			viewWltName: "test-view-collection-unencrypted.wlt",
+			name: "seed is not bip39",
 			opts: wallet.Options{
-				Label: "foowlt",
-				Type:  wallet.WalletTypeCollection,
-			},
-			action: func(t *testing.T) func(wallet.Wallet) error {
-				return func(w wallet.Wallet) error {
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeDeterministic,
+			},
+			id:             "wallet.wlt",
+			pwd:            []byte("pwd"),
+			groupThreshold: 1,
+			expectErr:      wallet.ErrSeedNotBip39,
+		},
+		{
+			name: "invalid group threshold",
+			opts: wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeBip44,
+			},
+			id:             "wallet.wlt",
+			pwd:            []byte("pwd"),
+			groupThreshold: 3,
+			expectErr:      wallet.NewError(slip39.ErrInvalidGroupThreshold),
+		},
+		{
+			name: "wallet is not encrypted",
+			opts: wallet.Options{
+				Seed: bip39.MustNewDefaultMnemonic(),
+				Type: wallet.WalletTypeBip44,
+			},
+			id:             "wallet.wlt",
+			groupThreshold: 1,
+			expectErr:      wallet.ErrWalletNotEncrypted,
+		},
+		{
+			name: "invalid password",
+			opts: wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeBip44,
+			},
+			id:             "wallet.wlt",
+			pwd:            []byte("wrong"),
+			groupThreshold: 1,
+			expectErr:      wallet.ErrInvalidPassword,
+		},
+		{
+			name: "disable seed api",
+			opts: wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeBip44,
+			},
+			id:             "wallet.wlt",
+			pwd:            []byte("pwd"),
+			groupThreshold: 1,
+			disableSeedAPI: true,
+			expectErr:      wallet.ErrSeedAPIDisabled,
+		},
+		{
+			name: "wallet api disabled",
+			opts: wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Type:     wallet.WalletTypeBip44,
+			},
+			id:               "wallet.wlt",
+			pwd:              []byte("pwd"),
+			groupThreshold:   1,
+			disableWalletAPI: true,
+			expectErr:        wallet.ErrWalletAPIDisabled,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: !tc.disableWalletAPI,
+				EnableSeedAPI:   !tc.disableSeedAPI,
+			})
+			require.NoError(t, err)
+
+			if tc.disableWalletAPI {
+				_, err = s.GetWalletSeedShares(tc.id, tc.pwd, tc.groupThreshold, groups)
+				require.Equal(t, tc.expectErr, err)
+				return
+			}
+
+			w, err := s.CreateWallet("wallet.wlt", tc.opts)
+			require.NoError(t, err)
+
+			shares, err := s.GetWalletSeedShares(tc.id, tc.pwd, tc.groupThreshold, groups)
+			require.Equal(t, tc.expectErr, err)
+			if err != nil {
+				return
+			}
+
+			require.Len(t, shares, len(groups))
+			require.Len(t, shares[0], 1)
+			require.Len(t, shares[1], 3)
+
+			// Restore the wallet from a quorum of the shares
+			quorum := []string{shares[1][2], shares[1][0]}
+			if tc.groupThreshold == 2 {
+				quorum = append(quorum, shares[0][0])
+			}
+
+			_, err = s.CreateWallet("restored.wlt", wallet.Options{
+				Type:           tc.opts.Type,
+				Seed:           tc.opts.Seed,
+				SeedShares:     quorum,
+				SeedPassphrase: tc.opts.SeedPassphrase,
+			})
+			require.Equal(t, wallet.ErrSeedAndSeedShares, err)
+
+			_, err = s.CreateWallet("restored.wlt", wallet.Options{
+				Type:           tc.opts.Type,
+				SeedShares:     quorum,
+				SeedPassphrase: tc.opts.SeedPassphrase,
+			})
+			// The restored wallet has the same seed, so it conflicts with the original wallet
+			require.Equal(t, wallet.NewError(fmt.Errorf("fingerprint conflict for %q wallet", tc.opts.Type)), err)
+
+			require.NoError(t, s.UnloadWallet(w.Filename()))
+			w2, err := s.CreateWallet("restored.wlt", wallet.Options{
+				Type:           tc.opts.Type,
+				SeedShares:     quorum,
+				SeedPassphrase: tc.opts.SeedPassphrase,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.opts.Seed, w2.Seed())
+			require.Equal(t, w.Fingerprint(), w2.Fingerprint())
+			require.True(t, w2.RestoredFromShares())
+
+			id, err := slip39.Identifier(quorum)
+			require.NoError(t, err)
+			require.Equal(t, id, w2.SharesIdentifier())
+
+			// The restored from shares meta is persisted
+			w3, err := wallet.Load(filepath.Join(dir, "restored.wlt"))
+			require.NoError(t, err)
+			require.True(t, w3.RestoredFromShares())
+			require.Equal(t, id, w3.SharesIdentifier())
+
+			// Not enough shares
+			_, err = s.CreateWallet("restored2.wlt", wallet.Options{
+				Type:       tc.opts.Type,
+				SeedShares: quorum[:1],
+			})
+			require.Equal(t, wallet.NewError(slip39.ErrInsufficientShares), err)
+
+			// Seed shares are not supported by other wallet types
+			_, err = s.CreateWallet("restored2.wlt", wallet.Options{
+				Type:       wallet.WalletTypeCollection,
+				SeedShares: quorum,
+			})
+			require.Equal(t, wallet.ErrWalletSeedShares, err)
+		})
+	}
+}
+
+func TestServiceCreateWalletSeedLanguage(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+		EnableSeedAPI:   true,
+	})
+	require.NoError(t, err)
+
+	seed, err := bip39.NewDefaultMnemonicInLanguage(bip39.Spanish)
+	require.NoError(t, err)
+
+	// The seed language is detected and persisted
+	w, err := s.CreateWallet("spanish.wlt", wallet.Options{
+		Type: wallet.WalletTypeBip44,
+		Seed: seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, bip39.Spanish, w.SeedLanguage())
+
+	w2, err := wallet.Load(filepath.Join(dir, "spanish.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, bip39.Spanish, w2.SeedLanguage())
+
+	// The seed is restored from shares in the seed language
+	shares, err := wallet.SplitSeed(seed, 1, []slip39.Group{{MemberThreshold: 1, MemberCount: 1}})
+	require.NoError(t, err)
+	require.NoError(t, s.UnloadWallet(w.Filename()))
+	w3, err := s.CreateWallet("restored.wlt", wallet.Options{
+		Type:         wallet.WalletTypeBip44,
+		SeedShares:   shares[0],
+		SeedLanguage: bip39.Spanish,
+	})
+	require.NoError(t, err)
+	require.Equal(t, seed, w3.Seed())
+	require.Equal(t, w.Fingerprint(), w3.Fingerprint())
+
+	_, err = s.CreateWallet("klingon.wlt", wallet.Options{
+		Type:         wallet.WalletTypeBip44,
+		Seed:         seed,
+		SeedLanguage: "klingon",
+	})
+	require.Equal(t, wallet.NewError(bip39.ErrUnknownLanguage), err)
+
+	// The seed language is only used by bip44 wallets
+	_, err = s.CreateWallet("deterministic.wlt", wallet.Options{
+		Type:         wallet.WalletTypeDeterministic,
+		Seed:         seed,
+		SeedLanguage: bip39.Spanish,
+	})
+	require.Equal(t, wallet.ErrWalletSeedLanguage, err)
+}
+
+func TestServiceCreateWalletSeedEntropy(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+		EnableSeedAPI:   true,
+	})
+	require.NoError(t, err)
+
+	dice := "3615 2423 6162 5341 2366 5412 3651 4236 1524 3612 5436 1254 6321"
+	entropy, desc, err := bip39.NewEntropyWithUserEntropy(128, bip39.UserEntropyDice, dice)
+	require.NoError(t, err)
+	seed, err := bip39.NewMnemonic(entropy)
+	require.NoError(t, err)
+
+	for _, walletType := range []string{wallet.WalletTypeBip44, wallet.WalletTypeDeterministic} {
+		w, err := s.CreateWallet(walletType+".wlt", wallet.Options{
+			Type:        walletType,
+			Seed:        seed,
+			SeedEntropy: desc.String(),
+			Encrypt:     true,
+			Password:    []byte("pwd"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, desc.String(), w.SeedEntropy())
+
+		// The description is persisted and lets the user verify the dice rolls were used
+		w2, err := wallet.Load(filepath.Join(dir, walletType+".wlt"))
+		require.NoError(t, err)
+		desc2, err := bip39.ParseEntropyDescription(w2.SeedEntropy())
+		require.NoError(t, err)
+		require.NoError(t, desc2.VerifyUserEntropy(dice))
+	}
+
+	_, err = s.CreateWallet("invalid.wlt", wallet.Options{
+		Type:        wallet.WalletTypeBip44,
+		Seed:        seed,
+		SeedEntropy: "dice",
+	})
+	require.Equal(t, wallet.NewError(bip39.ErrInvalidEntropyDescription), err)
+
+	_, err = s.CreateWallet("collection.wlt", wallet.Options{
+		Type:        wallet.WalletTypeCollection,
+		SeedEntropy: desc.String(),
+	})
+	require.Equal(t, wallet.ErrWalletSeedEntropy, err)
+}
+
+func TestServiceAccounts(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	seed := bip39.MustNewDefaultMnemonic()
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Type:     wallet.WalletTypeBip44,
+		Seed:     seed,
+		Encrypt:  true,
+		Password: []byte("pwd"),
+	})
+	require.NoError(t, err)
+
+	_, err = s.NewAccount(w.Filename(), nil, "savings")
+	require.Equal(t, wallet.ErrMissingPassword, err)
+
+	a, err := s.NewAccount(w.Filename(), []byte("pwd"), "savings")
+	require.NoError(t, err)
+	require.Equal(t, wallet.Bip44Account{Name: "savings", Index: 1}, a)
+
+	require.NoError(t, s.SetAccountName(w.Filename(), 1, "cold"))
+	require.Error(t, s.SetAccountName(w.Filename(), 2, "missing"))
+
+	accounts, err := s.GetAccounts(w.Filename())
+	require.NoError(t, err)
+	require.Equal(t, []wallet.Bip44Account{
+		{Name: "default", Index: 0},
+		{Name: "cold", Index: 1},
+	}, accounts)
+
+	// The accounts are persisted, and addresses are generated per account without the password
+	w2, err := wallet.Load(filepath.Join(dir, w.Filename()))
+	require.NoError(t, err)
+	require.Equal(t, accounts, w2.Accounts())
+
+	addrs, err := s.NewAddresses(w.Filename(), nil, 2, wallet.OptionAccount(1))
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+
+	// The new account private key is in the secrets, so the wallet can sign with it
+	require.NoError(t, s.ViewSecrets(w.Filename(), []byte("pwd"), func(w wallet.Wallet) error {
+		e, err := w.GetEntry(addrs[0], wallet.OptionAccount(1))
+		require.NoError(t, err)
+		require.NoError(t, cipher.CheckSecKey(e.Secret.SecKey()))
+		return nil
+	}))
+
+	cw, err := s.CreateWallet("c.wlt", wallet.Options{
+		Type: wallet.WalletTypeCollection,
+	})
+	require.NoError(t, err)
+	_, err = s.NewAccount(cw.Filename(), nil, "savings")
+	require.Equal(t, wallet.ErrWalletAccounts, err)
+	require.Equal(t, wallet.ErrWalletAccounts, s.SetAccountName(cw.Filename(), 0, "x"))
+	_, err = s.GetAccounts(cw.Filename())
+	require.Equal(t, wallet.ErrWalletAccounts, err)
+}
+
+func TestServiceView(t *testing.T) {
+	tt := []struct {
+		name             string
+		wltName          string
+		opts             wallet.Options
+		viewWltName      string
+		action           func(*testing.T) func(wallet.Wallet) error
+		disableWalletAPI bool
+		err              error
+	}{
+		{
+			name:        "ok, encrypted collection wallet",
+			wltName:     "test-view-collection-encrypted.wlt",
+			viewWltName: "test-view-collection-encrypted.wlt",
+			opts: wallet.Options{
+				Type:     wallet.WalletTypeCollection,
+				Encrypt:  true,
+				Password: []byte("pwd"),
+				Label:    "foowlt",
+			},
+			action: func(t *testing.T) func(wallet.Wallet) error {
+				return func(w wallet.Wallet) error {
+					require.Equal(t, "foowlt", w.Label())
+					checkNoSensitiveData(t, w)
+
+					// Modify the wallet pointer in order to check that this references a clone and not the original
+					w.SetLabel(w.Label() + "foo")
+
+					return nil
+				}
+			},
+		},
+
+		{
+			name:        "ok, unencrypted collection wallet",
+			wltName:     "test-view-collection-unencrypted.wlt",
+			viewWltName: "test-view-collection-unencrypted.wlt",
+			opts: wallet.Options{
+				Label: "foowlt",
+				Type:  wallet.WalletTypeCollection,
+			},
+			action: func(t *testing.T) func(wallet.Wallet) error {
+				return func(w wallet.Wallet) error {
 					require.Equal(t, "foowlt", w.Label())
 					// Collection wallets don't have seeds
 					require.Empty(t, w.Seed())
@@ -3397,7 +4458,7 @@ func TestServiceView(t *testing.T) {
 					p, s := cipher.GenerateKeyPair()
 					return w.(*collection.Wallet).AddEntry(wallet.Entry{
 						Public:  p,
-						Secret:  s,
+						Secret:  wallet.NewSecretKey(s),
 						Address: cipher.AddressFromPubKey(p),
 					})
 				})
@@ -3638,6 +4699,19 @@ func TestServiceViewSecrets(t *testing.T) {
 			err: wallet.ErrWalletNotExist,
 		},
 
+		{
+			name:        "hardware wallet device not connected",
+			wltName:     "test-view-secrets-hardware.wlt",
+			viewWltName: "test-view-secrets-hardware.wlt",
+			opts: wallet.Options{
+				XPub:   "xpub6EFYYRQeAbWLdWQYbtQv8HnemieKNmYUE23RmwphgtMLjz4UaStKADSKNoSSXM5FDcq4gZec2q6n7kdNWfuMdScxK1cXm8tR37kaitHtvuJ",
+				Device: hardwarewallet.DeviceLedger,
+				Label:  "foowlt",
+				Type:   wallet.WalletTypeHardware,
+			},
+			err: wallet.ErrDeviceRequired,
+		},
+
 		{
 			name:        "api disabled",
 			wltName:     "test-view-secrets-api-disabled.wlt",
@@ -4140,7 +5214,7 @@ func TestServiceUpdateSecrets(t *testing.T) {
 				require.Equal(t, 2, el)
 				entries, err := w.GetEntries()
 				require.NoError(t, err)
-				require.Equal(t, entries[1].Secret, cipher.SecKey{})
+				require.True(t, entries[1].Secret.Null())
 			},
 		},
 
@@ -4172,7 +5246,7 @@ func TestServiceUpdateSecrets(t *testing.T) {
 						err = w.(*collection.Wallet).AddEntry(wallet.Entry{
 							Address: addr,
 							Public:  pk,
-							Secret:  keys[i],
+							Secret:  wallet.NewSecretKey(keys[i]),
 						})
 						require.NoError(t, err)
 					}
@@ -4328,3 +5402,1580 @@ func combineAddrs(a []cipher.Address, b []cipher.Address) []cipher.Address {
 	copy(addrs[len(a):], b[:])
 	return addrs
 }
+
+func TestServiceMigrateWallets(t *testing.T) {
+	dir := prepareWltDir()
+	files := map[string]string{
+		"v1.wlt": "./testdata/test1.wlt",
+		"v2.wlt": "./testdata/v2_no_encrypt.wlt",
+	}
+	originData := make(map[string][]byte, len(files))
+	for name, src := range files {
+		b, err := ioutil.ReadFile(src)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), b, 0600))
+		originData[name] = b
+	}
+
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	wlts, err := s.GetWallets()
+	require.NoError(t, err)
+
+	migrated, err := s.MigrateWallets()
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.wlt", "v2.wlt"}, migrated)
+
+	for name, b := range originData {
+		// The original file is backed up
+		bak, err := ioutil.ReadFile(filepath.Join(dir, name+wallet.LegacyBackupExt))
+		require.NoError(t, err)
+		require.Equal(t, b, bak)
+
+		// The wallet file is in the v3 format and loads the same wallet
+		var f struct {
+			FormatVersion int `json:"formatVersion"`
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &f))
+		require.Equal(t, wallet.FileFormatVersion, f.FormatVersion)
+
+		w, err := wallet.Load(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, wlts[name].Fingerprint(), w.Fingerprint())
+
+		es, err := wlts[name].GetEntries()
+		require.NoError(t, err)
+		es2, err := w.GetEntries()
+		require.NoError(t, err)
+		require.Equal(t, es, es2)
+	}
+
+	// Migrated wallets are not migrated again
+	migrated, err = s.MigrateWallets()
+	require.NoError(t, err)
+	require.Empty(t, migrated)
+}
+
+func TestSaveBackupLegacyFile(t *testing.T) {
+	dir := prepareWltDir()
+	b, err := ioutil.ReadFile("./testdata/test1.wlt")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test1.wlt"), b, 0600))
+
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	// Updating a v1/v2 wallet backs up the original file
+	require.NoError(t, s.UpdateWalletLabel("test1.wlt", "foo"))
+	bak, err := ioutil.ReadFile(filepath.Join(dir, "test1.wlt"+wallet.LegacyBackupExt))
+	require.NoError(t, err)
+	require.Equal(t, b, bak)
+
+	// The backup is not overwritten by saving the wallet again
+	require.NoError(t, s.UpdateWalletLabel("test1.wlt", "bar"))
+	bak, err = ioutil.ReadFile(filepath.Join(dir, "test1.wlt"+wallet.LegacyBackupExt))
+	require.NoError(t, err)
+	require.Equal(t, b, bak)
+
+	w, err := wallet.Load(filepath.Join(dir, "test1.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", w.Label())
+}
+
+func TestServiceWalletDirLock(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	// The wallet directory is locked by the service
+	lockFile := filepath.Join(dir, wallet.LockFilename)
+	_, err = file.TryLock(lockFile)
+	require.Equal(t, file.ErrLocked, err)
+
+	require.NoError(t, s.UnlockWalletDir())
+	require.NoError(t, s.UnlockWalletDir())
+
+	// Another process holds the lock
+	l, err := file.TryLock(lockFile)
+	require.NoError(t, err)
+	require.Equal(t, wallet.ErrWalletDirLocked, s.LockWalletDir())
+
+	_, err = wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.Equal(t, fmt.Errorf("failed to lock wallet directory %s: %v", dir, wallet.ErrWalletDirLocked), err)
+
+	require.NoError(t, l.Unlock())
+	require.NoError(t, s.LockWalletDir())
+	_, err = file.TryLock(lockFile)
+	require.Equal(t, file.ErrLocked, err)
+	require.NoError(t, s.UnlockWalletDir())
+}
+
+func TestServiceExportImportKeystore(t *testing.T) {
+	params := wallet.KeystoreParams{
+		KDF:     wallet.KeystoreKDFScrypt,
+		ScryptN: 1 << 4,
+		ScryptR: 8,
+		ScryptP: 1,
+	}
+	keystorePassword := []byte("keystore-pwd")
+
+	for _, encrypt := range []bool{false, true} {
+		t.Run(fmt.Sprintf("encrypt=%v", encrypt), func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			var password []byte
+			if encrypt {
+				password = []byte("pwd")
+			}
+
+			w, err := s.CreateWallet("t.wlt", wallet.Options{
+				Seed:      bip39.MustNewDefaultMnemonic(),
+				Type:      wallet.WalletTypeDeterministic,
+				GenerateN: 3,
+				Encrypt:   encrypt,
+				Password:  password,
+			})
+			require.NoError(t, err)
+
+			keystores, err := s.ExportKeystore(w.Filename(), password, keystorePassword, params)
+			require.NoError(t, err)
+			require.Len(t, keystores, 3)
+
+			iw, err := s.ImportKeystore("imported.wlt", keystores, keystorePassword, wallet.Options{
+				Label:    "imported",
+				Encrypt:  encrypt,
+				Password: password,
+			})
+			require.NoError(t, err)
+			require.Equal(t, wallet.WalletTypeCollection, iw.Type())
+			require.Equal(t, "imported", iw.Label())
+			require.Equal(t, encrypt, iw.IsEncrypted())
+
+			addrs, err := w.GetAddresses()
+			require.NoError(t, err)
+			iaddrs, err := iw.GetAddresses()
+			require.NoError(t, err)
+			require.Equal(t, addrs, iaddrs)
+
+			// The imported wallet is saved
+			_, err = os.Stat(filepath.Join(dir, "imported.wlt"))
+			require.NoError(t, err)
+
+			// The secrets of the imported wallet match
+			secrets := func(wltID string) []cipher.SecKey {
+				var sks []cipher.SecKey
+				require.NoError(t, s.ViewSecrets(wltID, password, func(w wallet.Wallet) error {
+					es, err := w.GetEntries()
+					require.NoError(t, err)
+					for _, e := range es {
+						sks = append(sks, e.Secret.SecKey())
+					}
+					return nil
+				}))
+				return sks
+			}
+			require.Equal(t, secrets(w.Filename()), secrets(iw.Filename()))
+
+			_, err = s.ImportKeystore("imported2.wlt", keystores, []byte("bad"), wallet.Options{})
+			require.Equal(t, fmt.Errorf("keystore 0: %v", wallet.ErrKeystoreMACMismatch), err)
+
+			_, err = s.ImportKeystore("imported2.wlt", nil, keystorePassword, wallet.Options{})
+			require.Equal(t, wallet.ErrNoKeystores, err)
+
+			_, err = s.ImportKeystore("imported2.wlt", keystores, keystorePassword, wallet.Options{Coin: wallet.CoinTypeEthereum})
+			require.Equal(t, wallet.NewError(errors.New("ethereum wallets are not supported by the wallet service")), err)
+		})
+	}
+}
+
+func TestServiceImportPrivateKeys(t *testing.T) {
+	keys := []string{
+		"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+		"S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy",
+		"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+	}
+	secKeys := []cipher.SecKey{
+		cipher.MustSecKeyFromHex("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d"),
+		cipher.MustSecKeyFromHex("4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab"),
+		cipher.MustSecKeyFromHex("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"),
+	}
+
+	for _, encrypt := range []bool{false, true} {
+		t.Run(fmt.Sprintf("encrypt=%v", encrypt), func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			var password []byte
+			if encrypt {
+				password = []byte("pwd")
+			}
+
+			w, err := s.ImportPrivateKeys("imported.wlt", keys, wallet.Options{
+				Label:    "imported",
+				Encrypt:  encrypt,
+				Password: password,
+			})
+			require.NoError(t, err)
+			require.Equal(t, wallet.WalletTypeCollection, w.Type())
+			require.Equal(t, "imported", w.Label())
+			require.Equal(t, encrypt, w.IsEncrypted())
+
+			addrs, err := w.GetAddresses()
+			require.NoError(t, err)
+			require.Len(t, addrs, len(secKeys))
+			for i, sk := range secKeys {
+				require.Equal(t, cipher.MustAddressFromSecKey(sk), addrs[i])
+			}
+
+			// The imported wallet is saved
+			_, err = os.Stat(filepath.Join(dir, "imported.wlt"))
+			require.NoError(t, err)
+
+			var sks []cipher.SecKey
+			require.NoError(t, s.ViewSecrets(w.Filename(), password, func(w wallet.Wallet) error {
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				for _, e := range es {
+					sks = append(sks, e.Secret.SecKey())
+				}
+				return nil
+			}))
+			require.Equal(t, secKeys, sks)
+
+			_, err = s.ImportPrivateKeys("imported2.wlt", nil, wallet.Options{})
+			require.Equal(t, wallet.ErrNoPrivateKeys, err)
+
+			_, err = s.ImportPrivateKeys("imported2.wlt", []string{keys[0], "S6c56bnXQiBjk9mqSYE7ykVQ7NzrRz"}, wallet.Options{})
+			require.Equal(t, wallet.NewError(fmt.Errorf("private key 1: %v", cipher.ErrMiniPrivateKeyInvalidChecksum)), err)
+
+			_, err = s.ImportPrivateKeys("imported2.wlt", []string{keys[0], keys[0]}, wallet.Options{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestServiceImportBIP38Keys(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	passphrase := []byte("paper wallet")
+	bip38Key, err := cipher.BIP38Encrypt(sk, passphrase)
+	require.NoError(t, err)
+
+	keys := []string{
+		bip38Key,
+		"S6c56bnXQiBjk9mqSYE7ykVQ7NzrRy",
+	}
+	secKeys := []cipher.SecKey{
+		sk,
+		cipher.MustSecKeyFromHex("4c7a9640c72dc2099f23715d0c8a0d8a35f8906e3cab61dd3f78b67bf887c9ab"),
+	}
+
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	w, err := s.ImportBIP38Keys("paper.wlt", keys, passphrase, wallet.Options{
+		Label: "paper",
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.WalletTypeCollection, w.Type())
+
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+	require.Len(t, addrs, len(secKeys))
+	for i, sk := range secKeys {
+		require.Equal(t, cipher.MustAddressFromSecKey(sk), addrs[i])
+	}
+
+	_, err = s.ImportBIP38Keys("paper2.wlt", keys, nil, wallet.Options{})
+	require.Equal(t, wallet.ErrBIP38PassphraseRequired, err)
+
+	_, err = s.ImportBIP38Keys("paper2.wlt", keys, []byte("wrong"), wallet.Options{})
+	require.Equal(t, wallet.NewError(fmt.Errorf("private key 0: %v", cipher.ErrBIP38WrongPassphrase)), err)
+
+	// BIP38 keys require a passphrase
+	_, err = s.ImportPrivateKeys("paper2.wlt", keys, wallet.Options{})
+	require.Equal(t, wallet.NewError(fmt.Errorf("private key 0: %v", wallet.ErrBIP38PassphraseRequired)), err)
+}
+
+func TestImportKeystoreEthereum(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	ks, err := wallet.EncryptKeystore(sk, []byte("pwd"), wallet.KeystoreParams{
+		KDF:              wallet.KeystoreKDFPBKDF2,
+		PBKDF2Iterations: 16,
+	})
+	require.NoError(t, err)
+
+	w, err := wallet.ImportKeystore("eth.wlt", "eth", [][]byte{ks}, []byte("pwd"), wallet.Options{
+		Coin: wallet.CoinTypeEthereum,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.CoinTypeEthereum, w.Coin())
+
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+	require.Equal(t, []cipher.Addresser{cipher.MustEthereumAddressFromSecKey(sk)}, addrs)
+}
+
+func TestServiceSubscribe(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	events, unsubscribe := s.Subscribe(0)
+
+	next := func() wallet.Event {
+		select {
+		case e := <-events:
+			return e
+		default:
+			t.Fatal("expected a wallet event")
+			return wallet.Event{}
+		}
+	}
+
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed: bip39.MustNewDefaultMnemonic(),
+		Type: wallet.WalletTypeDeterministic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.Event{Type: wallet.EventWalletCreated, WalletID: w.Filename()}, next())
+
+	addrs, err := s.NewAddresses(w.Filename(), nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, wallet.Event{
+		Type:      wallet.EventAddressGenerated,
+		WalletID:  w.Filename(),
+		Addresses: addrs,
+	}, next())
+
+	_, err = s.EncryptWallet(w.Filename(), []byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, wallet.Event{Type: wallet.EventEncrypted, WalletID: w.Filename()}, next())
+
+	_, err = s.DecryptWallet(w.Filename(), []byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, wallet.Event{Type: wallet.EventDecrypted, WalletID: w.Filename()}, next())
+
+	// Only balance changes are emitted
+	b := wallet.BalancePair{Confirmed: wallet.NewBalance(1e6, 10)}
+	s.ReportBalance(w.Filename(), b)
+	require.Equal(t, wallet.Event{Type: wallet.EventBalanceChanged, WalletID: w.Filename(), Balance: &b}, next())
+	s.ReportBalance(w.Filename(), b)
+	require.Empty(t, events)
+
+	b2 := wallet.BalancePair{Confirmed: wallet.NewBalance(2e6, 10)}
+	s.ReportBalance(w.Filename(), b2)
+	require.Equal(t, wallet.Event{Type: wallet.EventBalanceChanged, WalletID: w.Filename(), Balance: &b2}, next())
+
+	// Events are dropped when the subscriber buffer is full
+	full, unsubscribeFull := s.Subscribe(1)
+	defer unsubscribeFull()
+	_, err = s.NewAddresses(w.Filename(), nil, 1)
+	require.NoError(t, err)
+	_, err = s.NewAddresses(w.Filename(), nil, 1)
+	require.NoError(t, err)
+	require.Len(t, full, 1)
+	require.Len(t, events, 2)
+
+	// Unsubscribing closes the channel
+	unsubscribe()
+	unsubscribe()
+	for range events {
+	}
+	_, ok := <-events
+	require.False(t, ok)
+}
+
+func TestServiceSetReadOnly(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	seed := bip39.MustNewDefaultMnemonic()
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed: seed,
+		Type: wallet.WalletTypeDeterministic,
+	})
+	require.NoError(t, err)
+	require.False(t, w.IsReadOnly())
+
+	require.NoError(t, s.SetReadOnly(w.Filename(), true))
+
+	// The read-only mark is saved
+	lw, err := wallet.Load(filepath.Join(dir, w.Filename()))
+	require.NoError(t, err)
+	require.True(t, lw.IsReadOnly())
+
+	txnF := func(w wallet.Wallet) (*coin.Transaction, error) {
+		return &coin.Transaction{}, nil
+	}
+	noop := func(w wallet.Wallet) error {
+		return nil
+	}
+
+	// All changes to the wallet are refused
+	_, err = s.NewAddresses(w.Filename(), nil, 1)
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+	_, err = s.ScanAddresses(w.Filename(), nil, 1, mockTxnsFinder{})
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+	_, err = s.CreateTransactionSigned(w.Filename(), nil, txnF)
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+	// Only the migrated wallets can be swept
+	_, err = s.CreateSweepTransactionSigned(w.Filename(), nil, txnF)
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+	_, err = s.EncryptWallet(w.Filename(), []byte("pwd"))
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+	require.Equal(t, wallet.ErrWalletReadOnly, s.UpdateWalletLabel(w.Filename(), "label"))
+	require.Equal(t, wallet.ErrWalletReadOnly, s.SetChangePolicy(w.Filename(), wallet.ChangePolicyReuseFirst, ""))
+	require.Equal(t, wallet.ErrWalletReadOnly, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{DailyLimit: 1}))
+	require.Equal(t, wallet.ErrWalletReadOnly, s.Update(w.Filename(), noop))
+	require.Equal(t, wallet.ErrWalletReadOnly, s.UpdateSecrets(w.Filename(), nil, noop))
+	_, err = s.MigrateToBip44(w.Filename(), "bip44.wlt", wallet.Options{Seed: bip39.MustNewDefaultMnemonic()})
+	require.Equal(t, wallet.ErrWalletReadOnly, err)
+
+	// The wallet can be read
+	addrs, err := s.GetAddresses(w.Filename())
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+
+	// The wallet can be changed again once the mark is removed
+	require.NoError(t, s.SetReadOnly(w.Filename(), false))
+	_, err = s.NewAddresses(w.Filename(), nil, 1)
+	require.NoError(t, err)
+
+	lw, err = wallet.Load(filepath.Join(dir, w.Filename()))
+	require.NoError(t, err)
+	require.False(t, lw.IsReadOnly())
+
+	require.Equal(t, wallet.ErrWalletNotExist, s.SetReadOnly("not-exist.wlt", true))
+}
+
+func TestServiceMigrateToBip44(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		t.Run(fmt.Sprintf("encrypt=%v", encrypt), func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			var password []byte
+			if encrypt {
+				password = []byte("pwd")
+			}
+
+			w, err := s.CreateWallet("t.wlt", wallet.Options{
+				Seed:      "seed",
+				Label:     "legacy",
+				Type:      wallet.WalletTypeDeterministic,
+				GenerateN: 3,
+				Encrypt:   encrypt,
+				Password:  password,
+			})
+			require.NoError(t, err)
+
+			addrs, err := w.GetAddresses()
+			require.NoError(t, err)
+			meta := wallet.EntryMeta{Label: "savings", Note: "note"}
+			require.NoError(t, s.Update(w.Filename(), func(w wallet.Wallet) error {
+				return w.SetEntryMeta(addrs[1], meta)
+			}))
+
+			seed := bip39.MustNewDefaultMnemonic()
+			nw, err := s.MigrateToBip44(w.Filename(), "bip44.wlt", wallet.Options{
+				Seed:     seed,
+				Encrypt:  encrypt,
+				Password: password,
+			})
+			require.NoError(t, err)
+			require.Equal(t, wallet.WalletTypeBip44, nw.Type())
+			require.Equal(t, "bip44.wlt", nw.Filename())
+			require.Equal(t, "legacy", nw.Label())
+			require.Equal(t, encrypt, nw.IsEncrypted())
+
+			// The entry labels are copied to the addresses of the same index
+			naddrs, err := nw.GetAddresses(wallet.OptionExternal())
+			require.NoError(t, err)
+			require.Len(t, naddrs, 3)
+			m, err := nw.GetEntryMeta(naddrs[1])
+			require.NoError(t, err)
+			require.Equal(t, meta, m)
+			m, err = nw.GetEntryMeta(naddrs[0])
+			require.NoError(t, err)
+			require.Equal(t, wallet.EntryMeta{}, m)
+
+			// The deterministic wallet is marked read-only, in memory and on disk
+			ow, err := s.GetWallet(w.Filename())
+			require.NoError(t, err)
+			require.True(t, ow.IsReadOnly())
+			require.Equal(t, "bip44.wlt", ow.MigratedTo())
+
+			lw, err := wallet.Load(filepath.Join(dir, w.Filename()))
+			require.NoError(t, err)
+			require.True(t, lw.IsReadOnly())
+			require.Equal(t, "bip44.wlt", lw.MigratedTo())
+
+			_, err = os.Stat(filepath.Join(dir, "bip44.wlt"))
+			require.NoError(t, err)
+
+			_, err = s.MigrateToBip44(w.Filename(), "bip44-2.wlt", wallet.Options{Seed: seed})
+			require.Equal(t, wallet.ErrWalletMigrated, err)
+
+			_, err = s.MigrateToBip44(nw.Filename(), "bip44-2.wlt", wallet.Options{Seed: seed})
+			require.Equal(t, wallet.NewError(errors.New("bip44 wallets can not be migrated to bip44 wallets")), err)
+
+			// The read-only deterministic wallet can only sign sweep transactions
+			f := func(w wallet.Wallet) (*coin.Transaction, error) {
+				return &coin.Transaction{}, nil
+			}
+			_, err = s.CreateTransactionSigned(w.Filename(), password, f)
+			require.Equal(t, wallet.ErrWalletReadOnly, err)
+			_, err = s.CreateSweepTransactionSigned(w.Filename(), password, f)
+			require.NoError(t, err)
+		})
+	}
+
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed: "seed",
+		Type: wallet.WalletTypeDeterministic,
+	})
+	require.NoError(t, err)
+
+	_, err = s.MigrateToBip44(w.Filename(), "bip44.wlt", wallet.Options{})
+	require.Equal(t, wallet.ErrMissingSeed, err)
+
+	_, err = s.MigrateToBip44("unknown.wlt", "bip44.wlt", wallet.Options{Seed: bip39.MustNewDefaultMnemonic()})
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
+
+func TestServiceCreateTransactionSigned(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	password := []byte("pwd")
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed:     "seed",
+		Type:     wallet.WalletTypeDeterministic,
+		Encrypt:  true,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	addrs, err := w.GetAddresses()
+	require.NoError(t, err)
+
+	// The coins sent back to the wallet are not spent
+	txn := &coin.Transaction{
+		Out: []coin.TransactionOutput{
+			{Address: testutil.MakeAddress(), Coins: 2e6},
+			{Address: addrs[0].(cipher.Address), Coins: 5e6},
+		},
+	}
+	create := func(w wallet.Wallet) (*coin.Transaction, error) {
+		require.False(t, w.IsEncrypted())
+		return txn, nil
+	}
+
+	// No spend policy
+	_, err = s.CreateTransactionSigned(w.Filename(), password, create)
+	require.NoError(t, err)
+	w, err = s.GetWallet(w.Filename())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), w.SpentOn(time.Now()))
+
+	policy := wallet.SpendPolicy{DailyLimit: 3e6, RequirePassword: true}
+	require.NoError(t, s.SetSpendPolicy(w.Filename(), policy))
+
+	ctxn, err := s.CreateTransactionSigned(w.Filename(), password, create)
+	require.NoError(t, err)
+	require.Equal(t, txn, ctxn)
+
+	// The spent coins are saved
+	lw, err := wallet.Load(filepath.Join(dir, w.Filename()))
+	require.NoError(t, err)
+	require.Equal(t, policy, lw.SpendPolicy())
+	require.Equal(t, uint64(2e6), lw.SpentOn(time.Now()))
+
+	_, err = s.CreateTransactionSigned(w.Filename(), password, create)
+	require.Equal(t, wallet.SpendLimitError{Limit: 3e6, Spent: 2e6, Amount: 2e6}, err)
+
+	_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+	require.Equal(t, wallet.ErrMissingPassword, err)
+
+	_, err = s.CreateTransactionSigned(w.Filename(), []byte("wrong"), create)
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	// f errors are returned and no coins are recorded as spent
+	require.NoError(t, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{DailyLimit: 10e6}))
+	_, err = s.CreateTransactionSigned(w.Filename(), password, func(w wallet.Wallet) (*coin.Transaction, error) {
+		return nil, errors.New("create failed")
+	})
+	require.Equal(t, errors.New("create failed"), err)
+	w, err = s.GetWallet(w.Filename())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2e6), w.SpentOn(time.Now()))
+
+	// A wallet that requires a password for every send can not be decrypted
+	require.NoError(t, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{RequirePassword: true}))
+	_, err = s.DecryptWallet(w.Filename(), password)
+	require.Equal(t, wallet.ErrRequirePasswordDecrypt, err)
+
+	require.NoError(t, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{}))
+	_, err = s.DecryptWallet(w.Filename(), password)
+	require.NoError(t, err)
+
+	err = s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{RequirePassword: true})
+	require.Equal(t, wallet.ErrRequirePasswordNotEncrypted, err)
+
+	_, err = s.CreateTransactionSigned("unknown.wlt", nil, create)
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
+
+func TestServiceUnlockWallet(t *testing.T) {
+	tt := []struct {
+		name string
+		opts wallet.Options
+	}{
+		{
+			name: "deterministic",
+			opts: wallet.Options{
+				Seed: "seed",
+				Type: wallet.WalletTypeDeterministic,
+			},
+		},
+		{
+			name: "bip44",
+			opts: wallet.Options{
+				Seed:       "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+				Type:       wallet.WalletTypeBip44,
+				CryptoType: crypto.CryptoTypeScryptChacha20poly1305Insecure,
+			},
+		},
+		{
+			name: "collection",
+			opts: wallet.Options{
+				Type: wallet.WalletTypeCollection,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			password := []byte("pwd")
+			tc.opts.Encrypt = true
+			tc.opts.Password = password
+			w, err := s.CreateWallet("t.wlt", tc.opts)
+			require.NoError(t, err)
+
+			if tc.opts.Type == wallet.WalletTypeCollection {
+				err := s.UpdateSecrets(w.Filename(), password, func(w wallet.Wallet) error {
+					p, sk := cipher.GenerateKeyPair()
+					return w.(*collection.Wallet).AddEntry(wallet.Entry{
+						Address: cipher.AddressFromPubKey(p),
+						Public:  p,
+						Secret:  wallet.NewSecretKey(sk),
+					})
+				})
+				require.NoError(t, err)
+			}
+
+			txn := &coin.Transaction{}
+			create := func(w wallet.Wallet) (*coin.Transaction, error) {
+				require.False(t, w.IsEncrypted())
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.NotEmpty(t, es)
+				for _, e := range es {
+					require.Equal(t, cipher.MustAddressFromSecKey(e.Secret.SecKey()), e.Address)
+				}
+				return txn, nil
+			}
+
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+
+			_, err = s.UnlockWallet(w.Filename(), []byte("wrong"), time.Hour)
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+			_, err = s.UnlockWallet(w.Filename(), nil, time.Hour)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+			_, err = s.UnlockWallet(w.Filename(), password, 0)
+			require.Equal(t, wallet.ErrInvalidUnlockTTL, err)
+			_, err = s.UnlockWallet("unknown.wlt", password, time.Hour)
+			require.Equal(t, wallet.ErrWalletNotExist, err)
+
+			expires, err := s.UnlockWallet(w.Filename(), password, time.Hour)
+			require.NoError(t, err)
+
+			// The session is exposed on the wallet but not saved
+			w, err = s.GetWallet(w.Filename())
+			require.NoError(t, err)
+			require.True(t, w.UnlockedUntil().Equal(expires))
+			require.True(t, w.IsEncrypted())
+			require.Empty(t, w.Seed())
+
+			// Signing in the session does not need the password
+			ctxn, err := s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.NoError(t, err)
+			require.Equal(t, txn, ctxn)
+
+			// The password is still checked if given
+			_, err = s.CreateTransactionSigned(w.Filename(), []byte("wrong"), create)
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+
+			// The session does not bypass the password requirement of the spend policy
+			require.NoError(t, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{RequirePassword: true}))
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+			require.NoError(t, s.SetSpendPolicy(w.Filename(), wallet.SpendPolicy{}))
+
+			lw, err := wallet.Load(filepath.Join(dir, w.Filename()))
+			require.NoError(t, err)
+			require.True(t, lw.UnlockedUntil().IsZero())
+
+			// LockWallet ends the session
+			require.NoError(t, s.LockWallet(w.Filename()))
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+			w, err = s.GetWallet(w.Filename())
+			require.NoError(t, err)
+			require.True(t, w.UnlockedUntil().IsZero())
+
+			// The session ends when it expires
+			_, err = s.UnlockWallet(w.Filename(), password, 50*time.Millisecond)
+			require.NoError(t, err)
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.NoError(t, err)
+			time.Sleep(100 * time.Millisecond)
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+
+			// Changing the password ends the session
+			_, err = s.UnlockWallet(w.Filename(), password, time.Hour)
+			require.NoError(t, err)
+			_, err = s.ChangePassword(w.Filename(), password, []byte("new pwd"))
+			require.NoError(t, err)
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, create)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+		})
+	}
+}
+
+func TestServiceUnlockWalletNotEncrypted(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed: "seed",
+		Type: wallet.WalletTypeDeterministic,
+	})
+	require.NoError(t, err)
+
+	_, err = s.UnlockWallet(w.Filename(), []byte("pwd"), time.Hour)
+	require.Equal(t, wallet.ErrWalletNotEncrypted, err)
+	require.NoError(t, s.LockWallet(w.Filename()))
+	require.Equal(t, wallet.ErrWalletNotExist, s.LockWallet("unknown.wlt"))
+}
+
+func TestServiceSeedPassphrase(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	seed := bip39.MustNewDefaultMnemonic()
+	password := []byte("pwd")
+
+	w, err := s.CreateWallet("passphrase.wlt", wallet.Options{
+		Type:           wallet.WalletTypeBip44,
+		Seed:           seed,
+		SeedPassphrase: "passphrase",
+		Encrypt:        true,
+		Password:       password,
+	})
+	require.NoError(t, err)
+
+	// The seed passphrase is verified against the accounts of the wallet
+	require.NoError(t, s.VerifySeedPassphrase(w.Filename(), password, "passphrase"))
+	require.Equal(t, wallet.ErrSeedPassphraseWrong, s.VerifySeedPassphrase(w.Filename(), password, ""))
+	require.Equal(t, wallet.ErrInvalidPassword, s.VerifySeedPassphrase(w.Filename(), []byte("wrong"), "passphrase"))
+	require.Equal(t, wallet.ErrMissingPassword, s.VerifySeedPassphrase(w.Filename(), nil, "passphrase"))
+	require.Equal(t, wallet.ErrWalletNotExist, s.VerifySeedPassphrase("unknown.wlt", password, "passphrase"))
+
+	w2, err := s.CreateWallet("deterministic.wlt", wallet.Options{
+		Type: wallet.WalletTypeDeterministic,
+		Seed: seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, wallet.ErrWalletSeedPassphrase, s.VerifySeedPassphrase(w2.Filename(), nil, ""))
+
+	// A wallet without a seed passphrase is recovered when a passphrase is entered
+	seed2 := bip39.MustNewDefaultMnemonic()
+	w3, err := s.CreateWallet("nopassphrase.wlt", wallet.Options{
+		Type:     wallet.WalletTypeBip44,
+		Seed:     seed2,
+		Encrypt:  true,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	w4, err := s.RecoverWallet(w3.Filename(), seed2, "passphrase", nil)
+	require.NoError(t, err)
+	require.Equal(t, w3.Fingerprint(), w4.Fingerprint())
+	require.Empty(t, w4.SeedPassphrase())
+	require.NoError(t, s.VerifySeedPassphrase(w4.Filename(), nil, ""))
+
+	// The seed passphrase is still required when the wallet has one
+	_, err = s.RecoverWallet(w.Filename(), seed, "", nil)
+	require.Equal(t, wallet.ErrWalletRecoverSeedWrong, err)
+
+	w5, err := s.RecoverWallet(w.Filename(), seed, "passphrase", nil)
+	require.NoError(t, err)
+	require.Equal(t, "passphrase", w5.SeedPassphrase())
+
+	// The wallet is created without the seed passphrase if only those addresses have transactions
+	seed3 := bip39.MustNewDefaultMnemonic()
+	noPassphrase, err := bip44wallet.NewWallet("tmp.wlt", "", seed3, "")
+	require.NoError(t, err)
+	addrs, err := noPassphrase.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+
+	w6, err := s.CreateWallet("try.wlt", wallet.Options{
+		Type:              wallet.WalletTypeBip44,
+		Seed:              seed3,
+		SeedPassphrase:    "passphrase",
+		TrySeedPassphrase: true,
+		TF:                mockTxnsFinder{addrs[0]: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, w6.SeedPassphrase())
+	require.Equal(t, noPassphrase.Fingerprint(), w6.Fingerprint())
+
+	// The seed passphrase is kept if neither have transactions
+	seed4 := bip39.MustNewDefaultMnemonic()
+	w7, err := s.CreateWallet("try2.wlt", wallet.Options{
+		Type:              wallet.WalletTypeBip44,
+		Seed:              seed4,
+		SeedPassphrase:    "passphrase",
+		TrySeedPassphrase: true,
+		TF:                mockTxnsFinder{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "passphrase", w7.SeedPassphrase())
+
+	_, err = s.CreateWallet("try3.wlt", wallet.Options{
+		Type:              wallet.WalletTypeBip44,
+		Seed:              seed4,
+		TrySeedPassphrase: true,
+		TF:                mockTxnsFinder{},
+	})
+	require.Equal(t, wallet.ErrTrySeedPassphrase, err)
+
+	_, err = s.CreateWallet("try4.wlt", wallet.Options{
+		Type:              wallet.WalletTypeBip44,
+		Seed:              seed4,
+		SeedPassphrase:    "passphrase",
+		TrySeedPassphrase: true,
+	})
+	require.Equal(t, wallet.ErrNilTransactionsFinder, err)
+}
+
+func TestServiceVerifyWalletSeed(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	seed := bip39.MustNewDefaultMnemonic()
+	password := []byte("pwd")
+
+	w, err := s.CreateWallet("bip44.wlt", wallet.Options{
+		Type:           wallet.WalletTypeBip44,
+		Seed:           seed,
+		SeedPassphrase: "passphrase",
+		Encrypt:        true,
+		Password:       password,
+	})
+	require.NoError(t, err)
+
+	// The seed is verified without the password of the encrypted wallet
+	match, err := s.VerifyWalletSeed(w.Filename(), seed, "passphrase")
+	require.NoError(t, err)
+	require.True(t, match)
+
+	match, err = s.VerifyWalletSeed(w.Filename(), seed, "")
+	require.NoError(t, err)
+	require.False(t, match)
+
+	match, err = s.VerifyWalletSeed(w.Filename(), bip39.MustNewDefaultMnemonic(), "passphrase")
+	require.NoError(t, err)
+	require.False(t, match)
+
+	match, err = s.VerifyWalletSeed(w.Filename(), "not a mnemonic", "passphrase")
+	require.NoError(t, err)
+	require.False(t, match)
+
+	_, err = s.VerifyWalletSeed(w.Filename(), "", "passphrase")
+	require.Equal(t, wallet.ErrMissingSeed, err)
+
+	_, err = s.VerifyWalletSeed("unknown.wlt", seed, "passphrase")
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+
+	// The wallet is not changed by the verification
+	w1, err := s.GetWallet(w.Filename())
+	require.NoError(t, err)
+	require.True(t, w1.IsEncrypted())
+	require.Equal(t, w.Fingerprint(), w1.Fingerprint())
+
+	w2, err := s.CreateWallet("deterministic.wlt", wallet.Options{
+		Type:     wallet.WalletTypeDeterministic,
+		Seed:     "deterministic seed",
+		Encrypt:  true,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	match, err = s.VerifyWalletSeed(w2.Filename(), "deterministic seed", "")
+	require.NoError(t, err)
+	require.True(t, match)
+
+	match, err = s.VerifyWalletSeed(w2.Filename(), "other seed", "")
+	require.NoError(t, err)
+	require.False(t, match)
+
+	_, err = s.VerifyWalletSeed(w2.Filename(), "deterministic seed", "passphrase")
+	require.Equal(t, wallet.ErrWalletSeedPassphrase, err)
+
+	w3, err := s.CreateWallet("collection.wlt", wallet.Options{
+		Type: wallet.WalletTypeCollection,
+	})
+	require.NoError(t, err)
+
+	_, err = s.VerifyWalletSeed(w3.Filename(), seed, "")
+	require.Equal(t, wallet.ErrWalletSeedVerify, err)
+
+	s.SetEnableWalletAPI(false)
+	_, err = s.VerifyWalletSeed(w.Filename(), seed, "passphrase")
+	require.Equal(t, wallet.ErrWalletAPIDisabled, err)
+}
+
+type mockAddressesFinder struct {
+	balances map[cipher.Address]wallet.BalancePair
+	heights  map[cipher.Address]uint64
+}
+
+func (f mockAddressesFinder) GetBalanceOfAddresses(addrs []cipher.Address) ([]wallet.BalancePair, error) {
+	bps := make([]wallet.BalancePair, len(addrs))
+	for i, a := range addrs {
+		bps[i] = f.balances[a]
+	}
+	return bps, nil
+}
+
+func (f mockAddressesFinder) GetAddressesLastUsedHeight(addrs []cipher.Address) ([]*uint64, error) {
+	heights := make([]*uint64, len(addrs))
+	for i, a := range addrs {
+		if h, ok := f.heights[a]; ok {
+			heights[i] = &h
+		}
+	}
+	return heights, nil
+}
+
+func TestServiceExportEntries(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed:           "attitude coach wet rely typical habit alien security deny imitate spike slab",
+		SeedPassphrase: "pwd",
+		Type:           wallet.WalletTypeBip44,
+		Encrypt:        true,
+		Password:       []byte("pwd"),
+	})
+	require.NoError(t, err)
+
+	// The bip44 wallet is created with an external and a change address
+	external := cipher.MustDecodeBase58Address("2JBfeo6y6FQn2rCiuhdQ8F1E6bj6rpnHo5U")
+	change := cipher.MustDecodeBase58Address("WFonrBarSSMPwFzcE9CS8vDbqmLjLZaJbT")
+	err = s.Update(w.Filename(), func(w wallet.Wallet) error {
+		return w.SetEntryMeta(external, wallet.EntryMeta{Label: "savings, 2020"})
+	})
+	require.NoError(t, err)
+	af := mockAddressesFinder{
+		balances: map[cipher.Address]wallet.BalancePair{
+			external: {Confirmed: wallet.Balance{Coins: 1500000, Hours: 10}, Predicted: wallet.Balance{Coins: 1000000}},
+		},
+		heights: map[cipher.Address]uint64{
+			external: 12,
+		},
+	}
+
+	b, err := s.ExportEntries(w.Filename(), wallet.ExportFormatJSON, af)
+	require.NoError(t, err)
+
+	var es []wallet.ExportEntry
+	require.NoError(t, json.Unmarshal(b, &es))
+
+	height := uint64(12)
+	require.Equal(t, []wallet.ExportEntry{
+		{
+			Address:        external.String(),
+			Path:           "m/44'/8000'/0'/0/0",
+			Label:          "savings, 2020",
+			Coins:          "1.500000",
+			Hours:          10,
+			LastUsedHeight: &height,
+		},
+		{
+			Address: change.String(),
+			Path:    "m/44'/8000'/0'/1/0",
+			Coins:   "0.000000",
+		},
+	}, es)
+
+	b, err = s.ExportEntries(w.Filename(), wallet.ExportFormatCSV, af)
+	require.NoError(t, err)
+	require.Equal(t, "address,path,label,coins,hours,last_used_height\n"+
+		external.String()+",m/44'/8000'/0'/0/0,\"savings, 2020\",1.500000,10,12\n"+
+		change.String()+",m/44'/8000'/0'/1/0,,0.000000,0,\n", string(b))
+
+	_, err = s.ExportEntries(w.Filename(), "xml", af)
+	require.Equal(t, wallet.ErrInvalidExportFormat, err)
+
+	_, err = s.ExportEntries("unknown.wlt", wallet.ExportFormatCSV, af)
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
+
+func TestServiceAddressLabels(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	seed := "attitude coach wet rely typical habit alien security deny imitate spike slab"
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed:           seed,
+		SeedPassphrase: "pwd",
+		Type:           wallet.WalletTypeBip44,
+	})
+	require.NoError(t, err)
+
+	labels, err := s.GetAddressLabels(w.Filename())
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	// The bip44 wallet is created with an external and a change address
+	external := cipher.MustDecodeBase58Address("2JBfeo6y6FQn2rCiuhdQ8F1E6bj6rpnHo5U")
+	change := cipher.MustDecodeBase58Address("WFonrBarSSMPwFzcE9CS8vDbqmLjLZaJbT")
+	unknown := testutil.MakeAddress()
+
+	notFound, err := s.SetAddressLabels(w.Filename(), []wallet.AddressLabel{
+		{
+			Address: external,
+			EntryMeta: wallet.EntryMeta{
+				Label: "savings",
+				Note:  "for the holidays",
+				Tags:  map[string]string{"category": "savings"},
+			},
+		},
+		{
+			Address:   change,
+			EntryMeta: wallet.EntryMeta{Label: "change"},
+		},
+		{
+			Address:   unknown,
+			EntryMeta: wallet.EntryMeta{Label: "unknown"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []cipher.Addresser{unknown}, notFound)
+
+	labels, err = s.GetAddressLabels(w.Filename())
+	require.NoError(t, err)
+	require.Equal(t, []wallet.AddressLabel{
+		{
+			Address: external,
+			EntryMeta: wallet.EntryMeta{
+				Label: "savings",
+				Note:  "for the holidays",
+				Tags:  map[string]string{"category": "savings"},
+			},
+		},
+		{
+			Address:   change,
+			EntryMeta: wallet.EntryMeta{Label: "change"},
+		},
+	}, labels)
+
+	// The labels are replaced, an empty meta removes the label
+	notFound, err = s.SetAddressLabels(w.Filename(), []wallet.AddressLabel{
+		{
+			Address: change,
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, notFound)
+
+	labels, err = s.GetAddressLabels(w.Filename())
+	require.NoError(t, err)
+	require.Len(t, labels, 1)
+	require.Equal(t, external, labels[0].Address)
+
+	// The labels are imported to the wallet recreated from the seed
+	require.NoError(t, s.UnloadWallet(w.Filename()))
+	w2, err := s.CreateWallet("t2.wlt", wallet.Options{
+		Seed:           seed,
+		SeedPassphrase: "pwd",
+		Type:           wallet.WalletTypeBip44,
+	})
+	require.NoError(t, err)
+
+	notFound, err = s.SetAddressLabels(w2.Filename(), labels)
+	require.NoError(t, err)
+	require.Empty(t, notFound)
+
+	labels2, err := s.GetAddressLabels(w2.Filename())
+	require.NoError(t, err)
+	require.Equal(t, labels, labels2)
+
+	_, err = s.GetAddressLabels("unknown.wlt")
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+
+	_, err = s.SetAddressLabels("unknown.wlt", labels)
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
+
+func TestServiceDuressPassword(t *testing.T) {
+	for _, walletType := range []string{wallet.WalletTypeDeterministic, wallet.WalletTypeBip44} {
+		t.Run(walletType, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+
+			password := []byte("pwd")
+			duressPassword := []byte("duress")
+			w, err := s.CreateWallet("t.wlt", wallet.Options{
+				Seed:     bip39.MustNewDefaultMnemonic(),
+				Type:     walletType,
+				Encrypt:  true,
+				Password: password,
+			})
+			require.NoError(t, err)
+
+			_, err = s.NewAddresses(w.Filename(), password, 3)
+			require.NoError(t, err)
+
+			entries, err := s.GetWalletEntries(w.Filename(), password)
+			require.NoError(t, err)
+			require.True(t, len(entries) > 3)
+			for _, e := range entries {
+				require.True(t, e.Secret.Null())
+			}
+
+			decoy := []cipher.Address{entries[3].SkycoinAddress(), entries[1].SkycoinAddress()}
+
+			_, err = s.SetDuressPassword(w.Filename(), password, password, decoy)
+			require.Equal(t, wallet.ErrDuressPasswordReused, err)
+			_, err = s.SetDuressPassword(w.Filename(), password, nil, decoy)
+			require.Equal(t, wallet.ErrMissingDuressPassword, err)
+			_, err = s.SetDuressPassword(w.Filename(), password, duressPassword, nil)
+			require.Equal(t, wallet.ErrMissingDuressAddresses, err)
+			_, err = s.SetDuressPassword(w.Filename(), []byte("wrong"), duressPassword, decoy)
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+			_, err = s.SetDuressPassword(w.Filename(), password, duressPassword, []cipher.Address{testutil.MakeAddress()})
+			require.Error(t, err)
+
+			w, err = s.SetDuressPassword(w.Filename(), password, duressPassword, decoy)
+			require.NoError(t, err)
+			require.NotEmpty(t, w.DuressSecrets())
+			checkNoSensitiveData(t, w)
+
+			// The duress password only lists the decoy entries, in the order of the wallet entries
+			decoyEntries, err := s.GetWalletEntries(w.Filename(), duressPassword)
+			require.NoError(t, err)
+			require.Len(t, decoyEntries, 2)
+			require.Equal(t, entries[1], decoyEntries[0])
+			require.Equal(t, entries[3], decoyEntries[1])
+
+			es, err := s.GetWalletEntries(w.Filename(), password)
+			require.NoError(t, err)
+			require.Equal(t, entries, es)
+
+			_, err = s.GetWalletEntries(w.Filename(), []byte("wrong"))
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+
+			// Signing with the duress password only has the secret keys of the decoy entries
+			_, err = s.CreateTransactionSigned(w.Filename(), duressPassword, func(w wallet.Wallet) (*coin.Transaction, error) {
+				require.False(t, w.IsEncrypted())
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.Len(t, es, 2)
+				for i, e := range es {
+					require.Equal(t, decoyEntries[i].Address, e.Address)
+					require.NoError(t, e.Verify())
+				}
+				return &coin.Transaction{}, nil
+			})
+			require.NoError(t, err)
+
+			_, err = s.CreateTransactionSigned(w.Filename(), password, func(w wallet.Wallet) (*coin.Transaction, error) {
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.NotEqual(t, 2, len(es))
+				return &coin.Transaction{}, nil
+			})
+			require.NoError(t, err)
+
+			// Unlocking with the duress password starts a session that signs with the decoy wallet
+			_, err = s.UnlockWallet(w.Filename(), duressPassword, time.Hour)
+			require.NoError(t, err)
+			_, err = s.CreateTransactionSigned(w.Filename(), nil, func(w wallet.Wallet) (*coin.Transaction, error) {
+				require.False(t, w.IsEncrypted())
+				es, err := w.GetEntries()
+				require.NoError(t, err)
+				require.Len(t, es, 2)
+				for i, e := range es {
+					require.Equal(t, decoyEntries[i].Address, e.Address)
+					require.NoError(t, e.Verify())
+				}
+				return &coin.Transaction{}, nil
+			})
+			require.NoError(t, err)
+			require.NoError(t, s.LockWallet(w.Filename()))
+
+			_, err = s.ChangePassword(w.Filename(), password, duressPassword)
+			require.Equal(t, wallet.ErrDuressPasswordReused, err)
+
+			// The duress password is loaded from the wallet file
+			require.NoError(t, s.UnlockWalletDir())
+			s, err = wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			es, err = s.GetWalletEntries(w.Filename(), duressPassword)
+			require.NoError(t, err)
+			require.Equal(t, decoyEntries, es)
+
+			w, err = s.RemoveDuressPassword(w.Filename(), password)
+			require.NoError(t, err)
+			require.Empty(t, w.DuressSecrets())
+
+			_, err = s.GetWalletEntries(w.Filename(), duressPassword)
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+
+			// Decrypting the wallet removes the duress password
+			_, err = s.SetDuressPassword(w.Filename(), password, duressPassword, decoy)
+			require.NoError(t, err)
+			w, err = s.DecryptWallet(w.Filename(), password)
+			require.NoError(t, err)
+			require.Empty(t, w.DuressSecrets())
+
+			es, err = s.GetWalletEntries(w.Filename(), nil)
+			require.NoError(t, err)
+			require.Equal(t, entries, es)
+		})
+	}
+}
+
+func TestServiceSignMessage(t *testing.T) {
+	tt := []struct {
+		name    string
+		opts    wallet.Options
+		encrypt bool
+	}{
+		{
+			name: "deterministic",
+			opts: wallet.Options{
+				Seed: "seed",
+				Type: wallet.WalletTypeDeterministic,
+			},
+		},
+		{
+			name: "deterministic encrypted",
+			opts: wallet.Options{
+				Seed: "seed",
+				Type: wallet.WalletTypeDeterministic,
+			},
+			encrypt: true,
+		},
+		{
+			name: "bip44 encrypted",
+			opts: wallet.Options{
+				Seed: "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+				Type: wallet.WalletTypeBip44,
+			},
+			encrypt: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := wallet.NewService(wallet.Config{
+				WalletDir:       dir,
+				CryptoType:      crypto.CryptoTypeSha256Xor,
+				EnableWalletAPI: true,
+			})
+			require.NoError(t, err)
+			defer s.UnlockWalletDir() //nolint:errcheck
+
+			var password []byte
+			if tc.encrypt {
+				password = []byte("pwd")
+				tc.opts.Encrypt = true
+				tc.opts.Password = password
+			}
+			w, err := s.CreateWallet("t.wlt", tc.opts)
+			require.NoError(t, err)
+
+			es, err := w.GetEntries()
+			require.NoError(t, err)
+			addrs := []cipher.Address{es[0].SkycoinAddress()}
+			msg := []byte("I own this address")
+
+			sig, err := s.SignMessage(w.Filename(), password, addrs[0], msg)
+			require.NoError(t, err)
+			require.NoError(t, cipher.VerifyMessage(addrs[0], sig, msg))
+
+			_, err = s.SignMessage(w.Filename(), password, testutil.MakeAddress(), msg)
+			require.Equal(t, wallet.ErrUnknownAddress, err)
+
+			_, err = s.SignMessage("unknown.wlt", password, addrs[0], msg)
+			require.Equal(t, wallet.ErrWalletNotExist, err)
+
+			if !tc.encrypt {
+				_, err = s.SignMessage(w.Filename(), []byte("pwd"), addrs[0], msg)
+				require.Equal(t, wallet.ErrWalletNotEncrypted, err)
+				return
+			}
+
+			_, err = s.SignMessage(w.Filename(), nil, addrs[0], msg)
+			require.Equal(t, wallet.ErrMissingPassword, err)
+			_, err = s.SignMessage(w.Filename(), []byte("wrong"), addrs[0], msg)
+			require.Equal(t, wallet.ErrInvalidPassword, err)
+
+			// Signing in an unlock session does not need the password
+			_, err = s.UnlockWallet(w.Filename(), password, time.Hour)
+			require.NoError(t, err)
+			sig, err = s.SignMessage(w.Filename(), nil, addrs[0], msg)
+			require.NoError(t, err)
+			require.NoError(t, cipher.VerifyMessage(addrs[0], sig, msg))
+		})
+	}
+}
+
+func TestServiceSignMessageErrors(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	// The addresses of watch wallets have no secret keys
+	addr := testutil.MakeAddress()
+	w, err := s.CreateWallet("watch.wlt", wallet.Options{
+		Type:      wallet.WalletTypeCollectionWatch,
+		Addresses: []string{addr.String()},
+	})
+	require.NoError(t, err)
+	_, err = s.SignMessage(w.Filename(), nil, addr, []byte("msg"))
+	require.Equal(t, wallet.ErrMissingSecKey, err)
+
+	w, err = s.CreateWallet("btc.wlt", wallet.Options{
+		Seed: "seed",
+		Type: wallet.WalletTypeDeterministic,
+		Coin: wallet.CoinTypeBitcoin,
+	})
+	require.NoError(t, err)
+	_, err = s.SignMessage(w.Filename(), nil, addr, []byte("msg"))
+	require.Equal(t, wallet.ErrSignMessageCoinType, err)
+
+	s, err = wallet.NewService(wallet.Config{
+		WalletDir:  prepareWltDir(),
+		CryptoType: crypto.CryptoTypeSha256Xor,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+	_, err = s.SignMessage(w.Filename(), nil, addr, []byte("msg"))
+	require.Equal(t, wallet.ErrWalletAPIDisabled, err)
+}
+
+func TestServiceEncryptDecryptMessage(t *testing.T) {
+	dir := prepareWltDir()
+	s, err := wallet.NewService(wallet.Config{
+		WalletDir:       dir,
+		CryptoType:      crypto.CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	defer s.UnlockWalletDir() //nolint:errcheck
+
+	password := []byte("pwd")
+	w, err := s.CreateWallet("t.wlt", wallet.Options{
+		Seed:     "voyage say extend find sheriff surge priority merit ignore maple cash argue",
+		Type:     wallet.WalletTypeBip44,
+		Encrypt:  true,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	es, err := w.GetEntries()
+	require.NoError(t, err)
+	addr := es[0].SkycoinAddress()
+	plaintext := []byte("secret message")
+
+	// Encrypting to the wallet address does not need the password
+	ciphertext, err := s.EncryptMessage(w.Filename(), addr, plaintext)
+	require.NoError(t, err)
+
+	// Messages encrypted to the public key of the address outside of the wallet are decrypted too
+	ciphertext2 := cipher.MustECIESEncrypt(es[0].Public, plaintext)
+
+	for _, c := range [][]byte{ciphertext, ciphertext2} {
+		decrypted, err := s.DecryptMessage(w.Filename(), password, addr, c)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	}
+
+	_, err = s.DecryptMessage(w.Filename(), nil, addr, ciphertext)
+	require.Equal(t, wallet.ErrMissingPassword, err)
+	_, err = s.DecryptMessage(w.Filename(), []byte("wrong"), addr, ciphertext)
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	// Another address of the wallet can't decrypt
+	addrs, err := s.NewAddresses(w.Filename(), password, 1)
+	require.NoError(t, err)
+	_, err = s.DecryptMessage(w.Filename(), password, addrs[0], ciphertext)
+	require.Equal(t, cipher.ErrECIESDecrypt, err)
+
+	_, err = s.EncryptMessage(w.Filename(), testutil.MakeAddress(), plaintext)
+	require.Equal(t, wallet.ErrUnknownAddress, err)
+	_, err = s.DecryptMessage(w.Filename(), password, testutil.MakeAddress(), ciphertext)
+	require.Equal(t, wallet.ErrUnknownAddress, err)
+
+	// Decrypting in an unlock session does not need the password
+	_, err = s.UnlockWallet(w.Filename(), password, time.Hour)
+	require.NoError(t, err)
+	decrypted, err := s.DecryptMessage(w.Filename(), nil, addr, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	// The addresses of watch wallets have no public keys
+	watchAddr := testutil.MakeAddress()
+	ww, err := s.CreateWallet("watch.wlt", wallet.Options{
+		Type:      wallet.WalletTypeCollectionWatch,
+		Addresses: []string{watchAddr.String()},
+	})
+	require.NoError(t, err)
+	_, err = s.EncryptMessage(ww.Filename(), watchAddr, plaintext)
+	require.Equal(t, wallet.ErrMissingPubKey, err)
+	_, err = s.DecryptMessage(ww.Filename(), nil, watchAddr, ciphertext)
+	require.Equal(t, wallet.ErrMissingSecKey, err)
+}
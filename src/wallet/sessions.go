@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher/secret"
+)
+
+var (
+	// ErrInvalidUnlockTTL is returned when unlocking a wallet with a none positive session ttl
+	ErrInvalidUnlockTTL = NewError(errors.New("unlock session ttl must be positive"))
+	// ErrUnlockSessionNotSupported is returned when unlocking a wallet type that does not support unlock sessions
+	ErrUnlockSessionNotSupported = NewError(errors.New("wallet type does not support unlock sessions"))
+)
+
+// unlockSession holds the decrypted secrets of an unlocked wallet until the session expires,
+// the serialized secrets are only held in a secret.Buffer. The session of the duress password
+// holds the duress secrets and signs with the decoy wallet.
+type unlockSession struct {
+	secrets *secret.Buffer
+	duress  bool
+	expires time.Time
+	timer   *time.Timer
+}
+
+// newUnlockSession creates an unlock session of the decrypted secrets, the secrets are erased.
+// duress is true if the secrets are the duress secrets.
+func newUnlockSession(ss Secrets, duress bool, expires time.Time) (*unlockSession, error) {
+	defer ss.Erase()
+
+	b, err := ss.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &unlockSession{
+		secrets: secret.FromBytes(b),
+		duress:  duress,
+		expires: expires,
+	}, nil
+}
+
+// expired returns whether the session is expired at the time
+func (s *unlockSession) expired(t time.Time) bool {
+	return !t.Before(s.expires)
+}
+
+// destroy stops the expiry timer and wipes the secrets
+func (s *unlockSession) destroy() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.secrets.Destroy()
+}
+
+// view executes a function within the context of the decrypted copy of the encrypted wallet,
+// or of the decoy wallet if the session is of the duress password.
+// The decrypted copy is erased when the function returns
+func (s *unlockSession) view(w Wallet, f func(w Wallet) error) error {
+	ss := make(Secrets)
+	if err := ss.Deserialize(s.secrets.Bytes()); err != nil {
+		return err
+	}
+	defer ss.Erase()
+
+	wlt, err := unlockWithSecrets(w, ss, s.duress)
+	if err != nil {
+		return err
+	}
+	defer wlt.Erase()
+
+	return f(wlt)
+}
+
+// unlockWithSecrets returns the decrypted copy of the encrypted wallet with the decrypted secrets,
+// or the decoy wallet if the secrets are the duress secrets
+func unlockWithSecrets(w Wallet, ss Secrets, duress bool) (Wallet, error) {
+	if duress {
+		return newDecoyWallet(w, ss)
+	}
+
+	u, ok := w.(SecretsUnlocker)
+	if !ok {
+		return nil, ErrUnlockSessionNotSupported
+	}
+
+	return u.UnlockSecrets(ss)
+}
@@ -0,0 +1,76 @@
+package wallet
+
+import (
+	"github.com/skycoin/skycoin/src/cipher/bip39"
+	"github.com/skycoin/skycoin/src/cipher/slip39"
+)
+
+// SplitSeed splits the bip39 mnemonic seed into SLIP-0039 mnemonic shares of the groups.
+// The seed can be restored from groupThreshold groups, with at least the member threshold
+// of shares of each group.
+// Only the mnemonic is shared, a bip44 seed passphrase is not part of the shares.
+func SplitSeed(seed string, groupThreshold int, groups []slip39.Group) ([][]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(seed)
+	if err != nil {
+		return nil, ErrSeedNotBip39
+	}
+
+	shares, err := slip39.GenerateMnemonics(groupThreshold, groups, entropy, nil, slip39.DefaultIterationExponent)
+	if err != nil {
+		return nil, NewError(err)
+	}
+
+	return shares, nil
+}
+
+// SeedFromShares restores the bip39 mnemonic seed from a quorum of SLIP-0039 mnemonic shares,
+// returns the seed and the identifier of the share set
+func SeedFromShares(shares []string) (string, uint16, error) {
+	entropy, err := slip39.CombineMnemonics(shares, nil)
+	if err != nil {
+		return "", 0, NewError(err)
+	}
+
+	id, err := slip39.Identifier(shares)
+	if err != nil {
+		return "", 0, NewError(err)
+	}
+
+	seed, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", 0, NewError(err)
+	}
+
+	return seed, id, nil
+}
+
+// createFromSeedShares creates a wallet with the seed restored from options.SeedShares,
+// in the language of options.SeedLanguage, and records in the wallet meta that the seed was
+// restored from shares
+func createFromSeedShares(c Creator, filename, label string, options Options) (Wallet, error) {
+	seed, id, err := SeedFromShares(options.SeedShares)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SeedLanguage != "" && options.SeedLanguage != bip39.English {
+		entropy, err := bip39.EntropyFromMnemonic(seed)
+		if err != nil {
+			return nil, NewError(err)
+		}
+
+		seed, err = bip39.NewMnemonicInLanguage(entropy, options.SeedLanguage)
+		if err != nil {
+			return nil, NewError(err)
+		}
+	}
+
+	options.Seed = seed
+	w, err := c.Create(filename, label, seed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	w.SetRestoredFromShares(id)
+	return w, nil
+}
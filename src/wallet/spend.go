@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+)
+
+// spentDayLayout is the layout of the MetaSpentDay date
+const spentDayLayout = "2006-01-02"
+
+var (
+	// ErrRequirePasswordNotEncrypted is returned when requiring a password for every send from an unencrypted wallet
+	ErrRequirePasswordNotEncrypted = NewError(errors.New("requirePassword is only used for encrypted wallets"))
+	// ErrRequirePasswordDecrypt is returned when decrypting a wallet that requires a password for every send
+	ErrRequirePasswordDecrypt = NewError(errors.New("wallet requires a password for every send, remove the requirePassword spend policy before decrypting it"))
+)
+
+// SpendPolicy limits the transactions signed by a wallet
+type SpendPolicy struct {
+	// DailyLimit is the maximum number of droplets that the wallet can send per UTC day, 0 means no limit
+	DailyLimit uint64
+	// RequirePassword requires the password for every send, the wallet must be encrypted
+	RequirePassword bool
+}
+
+// SpendLimitError is returned when a transaction would exceed the daily spend limit of the wallet
+type SpendLimitError struct {
+	// Limit is the daily spend limit in droplets
+	Limit uint64
+	// Spent is the number of droplets already sent today
+	Spent uint64
+	// Amount is the number of droplets sent by the transaction
+	Amount uint64
+}
+
+func (e SpendLimitError) Error() string {
+	return fmt.Sprintf("transaction sends %s coins, exceeding the daily spend limit of %s coins (%s coins already sent today)",
+		formatDroplets(e.Amount), formatDroplets(e.Limit), formatDroplets(e.Spent))
+}
+
+func formatDroplets(n uint64) string {
+	s, err := droplet.ToString(n)
+	if err != nil {
+		return strconv.FormatUint(n, 10) + " droplets"
+	}
+	return s
+}
+
+// SpendPolicy returns the spend policy of the wallet
+func (m Meta) SpendPolicy() SpendPolicy {
+	// Intentionally ignore the errors, the values are validated when the wallet is loaded
+	limit, _ := strconv.ParseUint(m[MetaDailySpendLimit], 10, 64)   //nolint:errcheck
+	requirePassword, _ := strconv.ParseBool(m[MetaRequirePassword]) //nolint:errcheck
+	return SpendPolicy{
+		DailyLimit:      limit,
+		RequirePassword: requirePassword,
+	}
+}
+
+// SetSpendPolicy sets the spend policy of the wallet, the zero SpendPolicy removes it
+func (m Meta) SetSpendPolicy(p SpendPolicy) {
+	if p.DailyLimit == 0 {
+		delete(m, MetaDailySpendLimit)
+	} else {
+		m[MetaDailySpendLimit] = strconv.FormatUint(p.DailyLimit, 10)
+	}
+
+	if !p.RequirePassword {
+		delete(m, MetaRequirePassword)
+	} else {
+		m[MetaRequirePassword] = strconv.FormatBool(true)
+	}
+}
+
+// SpentOn returns the number of droplets sent on the UTC day of t
+func (m Meta) SpentOn(t time.Time) uint64 {
+	if m[MetaSpentDay] != t.UTC().Format(spentDayLayout) {
+		return 0
+	}
+
+	// Intentionally ignore the error, the value is validated when the wallet is loaded
+	spent, _ := strconv.ParseUint(m[MetaSpent], 10, 64) //nolint:errcheck
+	return spent
+}
+
+// AddSpent records the droplets sent on the UTC day of t, the droplets sent on previous days are discarded
+func (m Meta) AddSpent(t time.Time, coins uint64) error {
+	spent, err := mathutil.AddUint64(m.SpentOn(t), coins)
+	if err != nil {
+		return err
+	}
+
+	m[MetaSpentDay] = t.UTC().Format(spentDayLayout)
+	m[MetaSpent] = strconv.FormatUint(spent, 10)
+	return nil
+}
+
+// ValidateSpendPolicy validates the spend policy for the wallet
+func ValidateSpendPolicy(w Wallet, p SpendPolicy) error {
+	if p.RequirePassword && !w.IsEncrypted() {
+		return ErrRequirePasswordNotEncrypted
+	}
+	return nil
+}
+
+// CheckSpend returns the number of droplets that the transaction sends to addresses
+// that are not in the wallet, and a SpendLimitError if they exceed the daily spend limit
+// of the wallet on the UTC day of now. The change chain is also checked for bip44 wallets.
+func CheckSpend(w Wallet, txn *coin.Transaction, now time.Time) (uint64, error) {
+	var amount uint64
+	for _, o := range txn.Out {
+		ok, err := isWalletAddress(w, o.Address)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			continue
+		}
+
+		amount, err = mathutil.AddUint64(amount, o.Coins)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	limit := w.SpendPolicy().DailyLimit
+	if limit == 0 {
+		return amount, nil
+	}
+
+	spent := w.SpentOn(now)
+	total, err := mathutil.AddUint64(spent, amount)
+	if err != nil || total > limit {
+		return amount, SpendLimitError{
+			Limit:  limit,
+			Spent:  spent,
+			Amount: amount,
+		}
+	}
+
+	return amount, nil
+}
+
+func isWalletAddress(w Wallet, addr cipher.Address) (bool, error) {
+	ok, err := w.HasEntry(addr)
+	if err != nil || ok || w.Type() != WalletTypeBip44 {
+		return ok, err
+	}
+
+	return w.HasEntry(addr, OptionChange())
+}
@@ -63,14 +63,26 @@ func copyTransaction(txn *coin.Transaction) *coin.Transaction {
 	return &txn2
 }
 
+// canSign returns whether the wallet has the signing capability. Collection-watch wallets
+// sign with the keys of their signer keyring, if they have one.
+func canSign(w Wallet) bool {
+	switch w.Type() {
+	case WalletTypeXPub, WalletTypeMultisig:
+		return false
+	case WalletTypeCollectionWatch:
+		return hasKeyringSigner(w)
+	default:
+		return true
+	}
+}
+
 // SignTransaction signs a transaction. Specific inputs may be signed by specifying signIndexes.
 // If signIndexes is empty, all inputs will be signed.
 // The transaction should already have a valid header. The transaction may be partially signed,
 // but a valid existing signature cannot be overwritten.
 // Clients should avoid signing the same transaction multiple times.
 func SignTransaction(w Wallet, txn *coin.Transaction, signIndexes []int, uxOuts []coin.UxOut) (*coin.Transaction, error) {
-	switch w.Type() {
-	case WalletTypeXPub:
+	if !canSign(w) {
 		return nil, ErrWalletCantSign
 	}
 
@@ -127,37 +139,17 @@ func SignTransaction(w Wallet, txn *coin.Transaction, signIndexes []int, uxOuts
 		}
 	}
 
-	// Check that the wallet has all addresses needed for signing
-	toSign := make(map[cipher.SecKey][]int)
-	entries, err := w.GetEntries()
-	if err != nil {
-		return nil, err
-	}
-	for _, e := range entries {
-		if len(toSign) == len(addrsMap) {
-			break
+	// Hardware wallets hold no secret keys, the inputs are signed by the device
+	if w.Type() == WalletTypeHardware {
+		if err := signInputsWithDevice(w, signedTxn, addrsMap); err != nil {
+			return nil, err
 		}
-		addr := e.SkycoinAddress()
-		if x, ok := addrsMap[addr]; ok {
-			toSign[e.Secret] = x
-		}
-	}
-
-	if len(toSign) != len(addrsMap) {
-		return nil, NewError(errors.New("Wallet cannot sign all requested inputs"))
-	}
-
-	// Sign the selected inputs
-	for k, v := range toSign {
-		for _, x := range v {
-			if !signedTxn.Sigs[x].Null() {
-				return nil, NewError(fmt.Errorf("Transaction is already signed at index %d", x))
-			}
-
-			if err := signedTxn.SignInput(k, x); err != nil {
-				return nil, err
-			}
+	} else if hasKeyringSigner(w) {
+		if err := signInputsWithKeyring(w, signedTxn, addrsMap); err != nil {
+			return nil, err
 		}
+	} else if err := signInputs(w, signedTxn, addrsMap); err != nil {
+		return nil, err
 	}
 
 	if err := signedTxn.UpdateHeader(); err != nil {
@@ -184,6 +176,45 @@ func SignTransaction(w Wallet, txn *coin.Transaction, signIndexes []int, uxOuts
 	return signedTxn, nil
 }
 
+// signInputs signs the inputs of txn listed in addrsMap with the secret keys of the wallet entries
+func signInputs(w Wallet, txn *coin.Transaction, addrsMap map[cipher.Address][]int) error {
+	// Check that the wallet has all addresses needed for signing
+	toSign := make(map[cipher.SecKey][]int)
+	entries, err := w.GetEntries()
+	if err != nil {
+		return err
+	}
+	defer entries.Erase()
+	for _, e := range entries {
+		if len(toSign) == len(addrsMap) {
+			break
+		}
+		addr := e.SkycoinAddress()
+		if x, ok := addrsMap[addr]; ok {
+			toSign[e.Secret.SecKey()] = x
+		}
+	}
+
+	if len(toSign) != len(addrsMap) {
+		return NewError(errors.New("Wallet cannot sign all requested inputs"))
+	}
+
+	// Sign the selected inputs
+	for k, v := range toSign {
+		for _, x := range v {
+			if !txn.Sigs[x].Null() {
+				return NewError(fmt.Errorf("Transaction is already signed at index %d", x))
+			}
+
+			if err := txn.SignInput(k, x); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // CreateTransaction creates an unsigned transaction based upon transaction.Params.
 // Set the password as nil if the wallet is not encrypted, otherwise the password must be provided.
 // NOTE: Caller must ensure that auxs correspond to params.Wallet.Addresses and params.Wallet.UxOuts options
@@ -196,7 +227,9 @@ func SignTransaction(w Wallet, txn *coin.Transaction, signIndexes []int, uxOuts
 //     such that there would be no change output but hours remain as change, another output will be chosen to create change,
 //     if the coinhour cost of adding that output is less than the coinhours that would be lost as change
 // If receiving hours are not explicitly specified, hours are allocated amongst the receiving outputs proportional to the number of coins being sent to them.
-// If the change address is not specified, the address whose bytes are lexically sorted first is chosen from the owners of the outputs being spent.
+// If the change address is not specified, it is chosen by the change policy of the wallet, see ChangeAddress.
+// With the default change policy, the address whose bytes are lexically sorted first is chosen from the owners of the outputs being spent.
+// The fresh change policy generates a new address in w, the caller is responsible for saving the wallet.
 // WARNING: This method is not concurrent-safe if operating on the same wallet. Use Service.View or Service.ViewSecrets to lock the wallet, or use your own lock.
 func CreateTransaction(w Wallet, p transaction.Params, auxs coin.AddressUxOuts, headTime uint64) (*coin.Transaction, []transaction.UxBalance, error) {
 	if err := p.Validate(); err != nil {
@@ -214,6 +247,16 @@ func CreateTransaction(w Wallet, p transaction.Params, auxs coin.AddressUxOuts,
 		}
 	}
 
+	// Choose the change address by the wallet change policy. The change chain of bip44
+	// wallets requires a TransactionsFinder, its change address must be set by the caller.
+	if p.ChangeAddress == nil && w.ChangePolicy() != ChangePolicyDefault && w.ChangePolicy() != ChangePolicyChangeChain {
+		addr, err := SkycoinChangeAddress(w, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.ChangeAddress = addr
+	}
+
 	// Generate a new change address for bip44 wallets
 	if p.ChangeAddress == nil && w.Type() == WalletTypeBip44 {
 		err := errors.New("change address must not be nil")
@@ -228,6 +271,10 @@ func CreateTransaction(w Wallet, p transaction.Params, auxs coin.AddressUxOuts,
 // Set the password as nil if the wallet is not encrypted, otherwise the password must be provided.
 // Refer to CreateTransaction for information about transaction creation.
 func CreateTransactionSigned(w Wallet, p transaction.Params, auxs coin.AddressUxOuts, headTime uint64) (*coin.Transaction, []transaction.UxBalance, error) {
+	if !canSign(w) {
+		return nil, nil, ErrWalletCantSign
+	}
+
 	txn, uxb, err := CreateTransaction(w, p, auxs, headTime)
 	if err != nil {
 		return nil, nil, err
@@ -235,6 +282,29 @@ func CreateTransactionSigned(w Wallet, p transaction.Params, auxs coin.AddressUx
 
 	logger.Infof("CreateTransactionSigned: signing %d inputs", len(uxb))
 
+	// Hardware wallets and keyring signed wallets hold no secret keys, the inputs are signed by the device or keyring
+	if w.Type() == WalletTypeHardware || hasKeyringSigner(w) {
+		addrsMap := make(map[cipher.Address][]int)
+		for i, s := range uxb {
+			addrsMap[s.Address] = append(addrsMap[s.Address], i)
+		}
+
+		sign := signInputsWithDevice
+		if hasKeyringSigner(w) {
+			sign = signInputsWithKeyring
+		}
+
+		if err := sign(w, txn, addrsMap); err != nil {
+			return nil, nil, err
+		}
+
+		if err := verifyCreatedSignedInvariants(p, txn, uxb); err != nil {
+			return nil, nil, err
+		}
+
+		return txn, uxb, nil
+	}
+
 	// Sign the transaction
 	entriesMap := make(map[cipher.Address]Entry)
 	for i, s := range uxb {
@@ -251,7 +321,7 @@ func CreateTransactionSigned(w Wallet, p transaction.Params, auxs coin.AddressUx
 			entriesMap[s.Address] = entry
 		}
 
-		if err := txn.SignInput(entry.Secret, i); err != nil {
+		if err := txn.SignInput(entry.Secret.SecKey(), i); err != nil {
 			logger.Critical().WithError(err).Errorf("CreateTransaction SignInput(%d) failed", i)
 			return nil, nil, err
 		}
@@ -14,12 +14,16 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/testutil"
 	"github.com/skycoin/skycoin/src/transaction"
 	"github.com/skycoin/skycoin/src/util/fee"
 	"github.com/skycoin/skycoin/src/wallet"
+	"github.com/skycoin/skycoin/src/wallet/bip44wallet"
 	"github.com/skycoin/skycoin/src/wallet/collection"
+	"github.com/skycoin/skycoin/src/wallet/deterministic"
 )
 
 func TestWalletSignTransaction(t *testing.T) {
@@ -36,7 +40,7 @@ func TestWalletSignTransaction(t *testing.T) {
 		err := w.AddEntry(wallet.Entry{
 			Address: a,
 			Public:  p,
-			Secret:  x,
+			Secret:  wallet.NewSecretKey(x),
 		})
 		require.NoError(t, err)
 
@@ -91,7 +95,7 @@ func TestWalletSignTransaction(t *testing.T) {
 		err := otherWallet.AddEntry(wallet.Entry{
 			Address: a,
 			Public:  p,
-			Secret:  secKeysOtherWallet[i],
+			Secret:  wallet.NewSecretKey(secKeysOtherWallet[i]),
 		})
 		require.NoError(t, err)
 	}
@@ -348,7 +352,7 @@ func TestWalletCreateTransaction(t *testing.T) {
 		err := w.AddEntry(wallet.Entry{
 			Address: a,
 			Public:  p,
-			Secret:  x,
+			Secret:  wallet.NewSecretKey(x),
 		})
 		require.NoError(t, err)
 
@@ -768,6 +772,151 @@ func makeTransaction(t *testing.T, nInputs int) (coin.Transaction, []coin.UxOut,
 	return txn, uxs, toSign
 }
 
+func TestWalletCreateTransactionChangePolicy(t *testing.T) {
+	headTime := uint64(time.Now().UTC().Unix())
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 2)
+	firstAddr := cipher.MustAddressFromSecKey(secKeys[0])
+	spendAddr := cipher.MustAddressFromSecKey(secKeys[1])
+	changeAddr := testutil.MakeAddress()
+
+	tt := []struct {
+		name          string
+		policy        wallet.ChangePolicy
+		changeAddress string
+		expect        cipher.Address
+	}{
+		{
+			name:   "default",
+			expect: spendAddr,
+		},
+		{
+			name:   "reuse first",
+			policy: wallet.ChangePolicyReuseFirst,
+			expect: firstAddr,
+		},
+		{
+			name:          "address",
+			policy:        wallet.ChangePolicyAddress,
+			changeAddress: changeAddr.String(),
+			expect:        changeAddr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := collection.NewWallet("test.wlt", "test")
+			require.NoError(t, err)
+			for _, sk := range secKeys {
+				p := cipher.MustPubKeyFromSecKey(sk)
+				require.NoError(t, w.AddEntry(wallet.Entry{
+					Address: cipher.AddressFromPubKey(p),
+					Public:  p,
+					Secret:  wallet.NewSecretKey(sk),
+				}))
+			}
+
+			require.NoError(t, wallet.ValidateChangePolicy(w, tc.policy, tc.changeAddress))
+			w.SetChangePolicy(tc.policy, tc.changeAddress)
+
+			uxout := makeUxOut(t, secKeys[1], 2e6, 100)
+			uxout.Head.Time = headTime
+			// A zero block seq would make it the genesis output
+			uxout.Head.BkSeq = 1
+
+			txn, _, err := wallet.CreateTransaction(w, transaction.Params{
+				HoursSelection: transaction.HoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				},
+				To: []coin.TransactionOutput{
+					{
+						Address: testutil.MakeAddress(),
+						Hours:   10,
+						Coins:   1e6,
+					},
+				},
+			}, coin.AddressUxOuts{spendAddr: []coin.UxOut{uxout}}, headTime)
+			require.NoError(t, err)
+			require.Len(t, txn.Out, 2)
+			require.Equal(t, tc.expect, txn.Out[1].Address)
+		})
+	}
+}
+
+func TestUnusedChangeAddress(t *testing.T) {
+	// Wallets without a change chain generate a new address, regardless of the change policy
+	dw, err := deterministic.NewWallet("test.wlt", "test", "foo bar baz", wallet.OptionGenerateN(1))
+	require.NoError(t, err)
+	dw.SetChangePolicy(wallet.ChangePolicyReuseFirst, "")
+
+	addr, err := wallet.SkycoinUnusedChangeAddress(dw, nil)
+	require.NoError(t, err)
+	addrs, err := dw.GetAddresses()
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	require.Equal(t, addrs[1], *addr)
+
+	// Wallets with a change chain return an unused change address
+	bw, err := bip44wallet.NewWallet("test.wlt", "test", bip39.MustNewDefaultMnemonic(), "")
+	require.NoError(t, err)
+
+	_, err = wallet.UnusedChangeAddress(bw, nil)
+	require.Equal(t, wallet.ErrNilTransactionsFinder, err)
+
+	changeAddr, err := wallet.UnusedChangeAddress(bw, mockTxnsFinder{})
+	require.NoError(t, err)
+	changeAddrs, err := bw.GetAddresses(wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, changeAddrs, []cipher.Addresser{changeAddr})
+
+	// The unused change address is returned again until it is used
+	again, err := wallet.UnusedChangeAddress(bw, mockTxnsFinder{})
+	require.NoError(t, err)
+	require.Equal(t, changeAddr, again)
+
+	next, err := wallet.UnusedChangeAddress(bw, mockTxnsFinder{changeAddr: true})
+	require.NoError(t, err)
+	require.NotEqual(t, changeAddr, next)
+}
+
+func TestCreateSweepTransaction(t *testing.T) {
+	headTime := uint64(time.Now().UTC().Unix())
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 2)
+	to := testutil.MakeAddress()
+
+	w, err := collection.NewWallet("test.wlt", "test")
+	require.NoError(t, err)
+	auxs := make(coin.AddressUxOuts)
+	for i, sk := range secKeys {
+		p := cipher.MustPubKeyFromSecKey(sk)
+		addr := cipher.AddressFromPubKey(p)
+		require.NoError(t, w.AddEntry(wallet.Entry{
+			Address: addr,
+			Public:  p,
+			Secret:  wallet.NewSecretKey(sk),
+		}))
+
+		uxout := makeUxOut(t, sk, uint64(i+1)*1e6, 100)
+		uxout.Head.Time = headTime
+		uxout.Head.BkSeq = 1
+		auxs[addr] = []coin.UxOut{uxout}
+	}
+
+	txn, uxb, err := wallet.CreateSweepTransaction(w, to, auxs, headTime)
+	require.NoError(t, err)
+	require.Len(t, uxb, 2)
+	require.Len(t, txn.In, 2)
+	require.NoError(t, txn.Verify())
+
+	// All the coins and the hours left after the fee are sent to the address
+	require.Len(t, txn.Out, 1)
+	require.Equal(t, to, txn.Out[0].Address)
+	require.Equal(t, uint64(3e6), txn.Out[0].Coins)
+	require.Equal(t, 200-fee.RequiredFee(200, params.UserVerifyTxn.BurnFactor), txn.Out[0].Hours)
+
+	_, _, err = wallet.CreateSweepTransaction(w, to, coin.AddressUxOuts{}, headTime)
+	require.Equal(t, wallet.ErrNoCoinsToSweep, err)
+}
+
 func makeUxOut(t *testing.T, s cipher.SecKey, coins, hours uint64) coin.UxOut { //nolint:unparam
 	body := makeUxBody(t, s, coins, hours)
 	tm := rand.Int31n(1000)
@@ -821,7 +970,7 @@ func makeEntry() wallet.Entry {
 	p, s := cipher.GenerateKeyPair()
 	a := cipher.AddressFromPubKey(p)
 	return wallet.Entry{
-		Secret:  s,
+		Secret:  wallet.NewSecretKey(s),
 		Public:  p,
 		Address: a,
 	}
@@ -3,7 +3,7 @@ Package wallet implements wallets and the wallet database service
 
 Values of the Wallet interface can be created by calling function NewWallet,
 or by loading from `[]byte` that containing wallet data of type such as
-"deterministic", "collection", "bip44" or "xpubwallet". Loading any particular
+"deterministic", "collection", "bip44", "xpubwallet", "multisig", "hardware" or "collection-watch". Loading any particular
 type of wallet requires the prior registration of a loader. Registration is typically
 automatic as a side effect of initializing that wallet's package so that, to load a
 "deterministic" wallet, it suffices to have
@@ -15,6 +15,7 @@ package wallet
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -25,6 +26,7 @@ import (
 	"time"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip39"
 	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/util/file"
 	"github.com/skycoin/skycoin/src/util/logging"
@@ -63,6 +65,8 @@ var (
 	ErrMissingEncrypt = NewError(errors.New("missing encrypt"))
 	// ErrInvalidPassword is returned if decrypts secrets failed
 	ErrInvalidPassword = NewError(errors.New("invalid password"))
+	// ErrMissingNewPassword is returned when changing the wallet password without a new password
+	ErrMissingNewPassword = NewError(errors.New("missing new password"))
 	// ErrMissingSeed is returned when trying to create wallet without a seed
 	ErrMissingSeed = NewError(errors.New("missing seed"))
 	// ErrMissingAuthenticated is returned if try to decrypt a scrypt chacha20poly1305 encrypted wallet, and find no authenticated metadata.
@@ -87,8 +91,40 @@ var (
 	ErrWalletRecoverSeedWrong = NewError(errors.New("wallet recovery seed or seed passphrase is wrong"))
 	// ErrWalletSeedPassphrase is returned when using seed passphrase for none bip44 wallet
 	ErrWalletSeedPassphrase = NewError(errors.New("seedPassphrase is only used for \"bip44\" wallets"))
+	// ErrWalletBip44PathTemplate is returned when using a derivation path template for none bip44 wallet
+	ErrWalletBip44PathTemplate = NewError(errors.New("bip44PathTemplate is only used for \"bip44\" wallets"))
+	// ErrWalletReadOnly is returned when changing a read-only wallet
+	ErrWalletReadOnly = NewError(errors.New("wallet is read-only"))
+	// ErrWalletSeedLanguage is returned when using a seed language for none bip44 wallet
+	ErrWalletSeedLanguage = NewError(errors.New("seedLanguage is only used for \"bip44\" wallets"))
+	// ErrWalletSeedEntropy is returned when using a seed entropy description for wallets other than bip44 and deterministic wallets
+	ErrWalletSeedEntropy = NewError(errors.New("seedEntropy is only used for \"bip44\" and \"deterministic\" wallets"))
+	// ErrWalletSeedShares is returned when using seed shares for wallets other than bip44 and deterministic wallets
+	ErrWalletSeedShares = NewError(errors.New("seedShares is only used for \"bip44\" and \"deterministic\" wallets"))
+	// ErrSeedAndSeedShares is returned when both the seed and the seed shares are provided
+	ErrSeedAndSeedShares = NewError(errors.New("seed and seedShares can not be used together"))
+	// ErrSeedNotBip39 is returned when splitting a wallet seed that is not a bip39 mnemonic into shares
+	ErrSeedNotBip39 = NewError(errors.New("only bip39 mnemonic seeds can be split into shares"))
+	// ErrWalletXPubAccount is returned when using an xpub account key for none xpub wallet
+	ErrWalletXPubAccount = NewError(errors.New("xpubAccount is only used for \"xpub\" wallets"))
+	// ErrWalletAddressEncoding is returned when setting the address encoding of a wallet that does not generate its addresses
+	ErrWalletAddressEncoding = NewError(errors.New("addressEncoding is only used for \"bip44\", \"deterministic\" and \"xpub\" wallets"))
+	// ErrWalletGapLimit is returned when using a gap limit for none bip44 wallet
+	ErrWalletGapLimit = NewError(errors.New("gapLimit is only used for \"bip44\" wallets"))
 	// ErrNilTransactionsFinder is returned if Options.ScanN > 0 but a nil TransactionsFinder was provided
 	ErrNilTransactionsFinder = NewError(errors.New("scan ahead requested but balance getter is nil"))
+	// ErrTrySeedPassphrase is returned when trying the seed passphrase without a seed passphrase, for none bip44 wallet
+	ErrTrySeedPassphrase = NewError(errors.New("trySeedPassphrase is only used for \"bip44\" wallets with a seed passphrase"))
+	// ErrSeedPassphraseWrong is returned if the seed passphrase does not match the wallet
+	ErrSeedPassphraseWrong = NewError(errors.New("seed passphrase does not match the wallet"))
+	// ErrWalletSeedVerify is returned when verifying the seed of a wallet that is not generated from a seed
+	ErrWalletSeedVerify = NewError(errors.New("seed can only be verified for \"bip44\" and \"deterministic\" wallets"))
+	// ErrWalletNoAddresses is returned when verifying the seed of an encrypted wallet without addresses
+	ErrWalletNoAddresses = NewError(errors.New("wallet has no addresses to verify the seed with"))
+	// ErrWalletSigner is returned when using a keyring signer for none collection-watch wallet
+	ErrWalletSigner = NewError(errors.New("signer is only used for \"collection-watch\" wallets"))
+	// ErrWalletAccounts is returned when managing the accounts of a none bip44 wallet
+	ErrWalletAccounts = NewError(errors.New("accounts are only managed for \"bip44\" wallets"))
 	// ErrInvalidCoinType is returned for invalid coin types
 	ErrInvalidCoinType = NewError(errors.New("invalid coin type"))
 	// ErrInvalidWalletType is returned for invalid wallet types
@@ -103,6 +139,10 @@ var (
 )
 
 const (
+	// DefaultGapLimit is the default number of consecutive unused addresses after which
+	// the scanning of the used addresses of a bip44 wallet chain stops
+	DefaultGapLimit = 20
+
 	// WalletExt wallet file extension
 	WalletExt = "wlt"
 
@@ -113,6 +153,8 @@ const (
 	CoinTypeSkycoin CoinType = "skycoin"
 	// CoinTypeBitcoin bitcoin type
 	CoinTypeBitcoin CoinType = "bitcoin"
+	// CoinTypeEthereum ethereum style coin type, uses keccak256 addresses
+	CoinTypeEthereum CoinType = "ethereum"
 
 	// WalletTypeDeterministic deterministic wallet type.
 	// Uses the original Skycoin deterministic key generator.
@@ -126,6 +168,15 @@ const (
 	// WalletTypeXPub xpub HD wallet type.
 	// Allows generating addresses without a secret key
 	WalletTypeXPub = "xpub"
+	// WalletTypeMultisig M-of-N multisig wallet type.
+	// Holds cosigner public keys only; spending requires signatures from M cosigners
+	WalletTypeMultisig = "multisig"
+	// WalletTypeHardware hardware device backed HD wallet type.
+	// Holds the device xpub only; transactions are signed by the device
+	WalletTypeHardware = "hardware"
+	// WalletTypeCollectionWatch watch-only wallet type.
+	// Holds an arbitrary list of addresses with no keys; addresses must be added explicitly
+	WalletTypeCollectionWatch = "collection-watch"
 )
 
 // CoinType represents the wallet coin type, which refers to the pubkey2addr method used
@@ -141,37 +192,169 @@ func NewWalletFilename() string {
 
 // Options options that could be used when creating a wallet
 type Options struct {
-	Version        string
-	Type           string            // wallet type: deterministic, collection. Refers to which key generation mechanism is used.
-	Coin           CoinType          // coin type: skycoin, bitcoin, etc. Refers to which pubkey2addr method is used.
-	Bip44Coin      *bip44.CoinType   // bip44 path coin type
-	Label          string            // wallet label
-	Seed           string            // wallet seed
-	SeedPassphrase string            // wallet seed passphrase (bip44 wallets only)
-	Encrypt        bool              // whether the wallet need to be encrypted.
-	Password       []byte            // password that would be used for encryption, and would only be used when 'Encrypt' is true.
-	CryptoType     crypto.CryptoType // wallet encryption type, scrypt-chacha20poly1305 or sha256-xor.
-	ScanN          uint64            // number of addresses that're going to be scanned for a balance. The highest address with a balance will be used.
-	GenerateN      uint64            // number of addresses to generate, regardless of balance
-	XPub           string            // xpub key (xpub wallets only)
-	Decoder        Decoder
-	TF             TransactionsFinder
+	Version           string
+	Type              string            // wallet type: deterministic, collection. Refers to which key generation mechanism is used.
+	Coin              CoinType          // coin type: skycoin, bitcoin, etc. Refers to which pubkey2addr method is used.
+	Bip44Coin         *bip44.CoinType   // bip44 path coin type
+	Bip44PathTemplate string            // bip32 derivation path template of the accounts, e.g. m/44'/8000'/n'/change/index (bip44 wallets only)
+	Label             string            // wallet label
+	Seed              string            // wallet seed
+	SeedPassphrase    string            // wallet seed passphrase (bip44 wallets only)
+	SeedLanguage      bip39.Language    // bip39 wordlist language of the seed, detected if not set (bip44 wallets only)
+	TrySeedPassphrase bool              // creates the wallet without SeedPassphrase if only those addresses have transactions, requires TF (bip44 wallets only)
+	Encrypt           bool              // whether the wallet need to be encrypted.
+	Password          []byte            // password that would be used for encryption, and would only be used when 'Encrypt' is true.
+	CryptoType        crypto.CryptoType // wallet encryption type, scrypt-chacha20poly1305 or sha256-xor.
+	ScanN             uint64            // number of addresses that're going to be scanned for a balance. The highest address with a balance will be used.
+	GapLimit          uint64            // scans all used addresses until GapLimit consecutive unused addresses are found, e.g. DefaultGapLimit (bip44 wallets only)
+	GenerateN         uint64            // number of addresses to generate, regardless of balance
+	XPub              string            // xpub key (xpub wallets only)
+	XPubAccount       bool              // the xpub is a bip44 account key, the addresses are derived on its external and change chains (xpub wallets only)
+	AddressEncoding   AddressEncoding   // encoding of the generated addresses, e.g. bech32 for bitcoin (bip44, deterministic and xpub wallets only)
+	PubKeys           []string          // cosigner public keys (multisig wallets only)
+	Threshold         uint64            // number of cosigner signatures required to spend (multisig wallets only)
+	Device            string            // hardware device type, e.g. ledger, trezor (hardware wallets only)
+	DerivationPath    string            // bip32 path of the xpub key on the device (hardware wallets only)
+	Addresses         []string          // watch addresses (collection-watch wallets only)
+	Signer            string            // name of the registered keyring signing with the keys of the addresses, see RegisterKeyring (collection-watch wallets only)
+	SeedShares        []string          // SLIP-0039 mnemonic shares to restore the seed from, instead of Seed (bip44 and deterministic wallets only)
+	SeedEntropy       string            // derivation description of the seed entropy mixed with user entropy, see bip39.EntropyDescription (bip44 and deterministic wallets only)
+	Decoder           Decoder
+	TF                TransactionsFinder
 }
 
 func (opts Options) Validate() error {
 	if opts.Type == WalletTypeDeterministic && opts.SeedPassphrase != "" {
 		return ErrWalletSeedPassphrase
 	}
+
+	if opts.SeedLanguage != "" {
+		if opts.Type != WalletTypeBip44 {
+			return ErrWalletSeedLanguage
+		}
+
+		if _, err := bip39.ParseLanguage(string(opts.SeedLanguage)); err != nil {
+			return NewError(err)
+		}
+	}
+
+	if opts.SeedEntropy != "" {
+		switch opts.Type {
+		case WalletTypeBip44, WalletTypeDeterministic:
+		default:
+			return ErrWalletSeedEntropy
+		}
+
+		if _, err := bip39.ParseEntropyDescription(opts.SeedEntropy); err != nil {
+			return NewError(err)
+		}
+	}
+
+	if opts.Signer != "" && opts.Type != WalletTypeCollectionWatch {
+		return ErrWalletSigner
+	}
+
+	if opts.TrySeedPassphrase {
+		if opts.Type != WalletTypeBip44 || opts.SeedPassphrase == "" {
+			return ErrTrySeedPassphrase
+		}
+
+		if opts.TF == nil {
+			return ErrNilTransactionsFinder
+		}
+	}
+
+	if opts.Bip44PathTemplate != "" {
+		if opts.Type != WalletTypeBip44 {
+			return ErrWalletBip44PathTemplate
+		}
+
+		if _, err := bip44.ParsePathTemplate(opts.Bip44PathTemplate); err != nil {
+			return NewError(err)
+		}
+	}
+
+	if opts.GapLimit > 0 {
+		if opts.Type != WalletTypeBip44 {
+			return ErrWalletGapLimit
+		}
+
+		if opts.TF == nil {
+			return ErrNilTransactionsFinder
+		}
+	}
+
+	if opts.XPubAccount && opts.Type != WalletTypeXPub {
+		return ErrWalletXPubAccount
+	}
+
+	if opts.AddressEncoding != AddressEncodingDefault {
+		switch opts.Type {
+		case WalletTypeBip44, WalletTypeDeterministic, WalletTypeXPub:
+		default:
+			return ErrWalletAddressEncoding
+		}
+
+		coin := opts.Coin
+		if coin == "" {
+			coin = CoinTypeSkycoin
+		}
+		if _, err := ResolveAddressConstructor(coin, opts.AddressEncoding); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.SeedShares) > 0 {
+		switch opts.Type {
+		case WalletTypeBip44, WalletTypeDeterministic:
+		default:
+			return ErrWalletSeedShares
+		}
+
+		if opts.Seed != "" {
+			return ErrSeedAndSeedShares
+		}
+	}
 	return nil
 }
 
+// SeedPassphraseVerifier is implemented by the wallets whose seed passphrase can be verified
+type SeedPassphraseVerifier interface {
+	// VerifySeedPassphrase returns whether the seed passphrase is the one of the wallet
+	VerifySeedPassphrase(passphrase string) (bool, error)
+}
+
+// AccountManager is implemented by the wallets with hierarchical accounts, e.g. the bip44 wallet.
+// The accounts are listed with Wallet.Accounts, and addresses are generated on an account with OptionAccount.
+type AccountManager interface {
+	// NewAccount creates the account of the next index with a name, and returns the index
+	NewAccount(name string) (uint32, error)
+	// SetAccountName sets the name of the account of given index
+	SetAccountName(index uint32, name string) error
+}
+
+// SecretsUnlocker is implemented by the encryptable wallets that can be unlocked
+// with the decrypted secrets, it's used by the unlock sessions of the service
+type SecretsUnlocker interface {
+	// UnlockSecrets returns a decrypted copy of the encrypted wallet with the decrypted secrets
+	UnlockSecrets(ss Secrets) (Wallet, error)
+}
+
 //go:generate mockery -name Wallet -case underscore -inpkg -testonly
 
 // Wallet defines the wallet API
 type Wallet interface {
+	// Seed, LastSeed and SeedPassphrase return string copies of the seeds, which the
+	// wallets hold in secret.Buffers, see Seeds
 	Seed() string
 	LastSeed() string
 	SeedPassphrase() string
+	// SeedLanguage returns the bip39 wordlist language of the seed
+	SeedLanguage() bip39.Language
+	SetSeedLanguage(l bip39.Language)
+	// SeedEntropy returns the derivation description of the seed entropy, if it was mixed with user entropy
+	SeedEntropy() string
+	SetSeedEntropy(description string)
 	Timestamp() int64
 	SetTimestamp(int64)
 	Coin() CoinType
@@ -181,6 +364,10 @@ type Wallet interface {
 	// Bip44Coin returns the coin_type part of bip44 path
 	Bip44Coin() *bip44.CoinType
 	SetBip44Coin(ct bip44.CoinType)
+	// Bip44PathTemplate returns the bip32 derivation path template of the accounts,
+	// empty if the default bip44 path of the bip44 coin type is used
+	Bip44PathTemplate() string
+	SetBip44PathTemplate(t string)
 	Label() string
 	SetLabel(string)
 	Filename() string
@@ -189,19 +376,74 @@ type Wallet interface {
 	// CryptoType returns the crypto type for encrypting/decrypting the wallet
 	CryptoType() crypto.CryptoType
 	SetCryptoType(ct crypto.CryptoType)
+	// SetEncrypted sets the encrypted secrets of the wallet
+	SetEncrypted(cryptoType crypto.CryptoType, encryptedSecrets string)
+	// Argon2Params returns the argon2id iterations and memory size in KiB,
+	// used when encrypting with the argon2id-chacha20poly1305 crypto type
+	Argon2Params() (iterations, memory uint32)
+	SetArgon2Params(iterations, memory uint32)
+	// RestoredFromShares returns whether the seed was restored from SLIP-0039 shares
+	RestoredFromShares() bool
+	// SharesIdentifier returns the identifier of the SLIP-0039 share set the seed was restored from
+	SharesIdentifier() uint16
+	SetRestoredFromShares(identifier uint16)
+	// ChangePolicy returns the change address policy of the created transactions
+	ChangePolicy() ChangePolicy
+	// ChangePolicyAddress returns the change address of the address change policy
+	ChangePolicyAddress() string
+	SetChangePolicy(policy ChangePolicy, changeAddress string)
+	// IsReadOnly returns whether the wallet is read-only
+	IsReadOnly() bool
+	// SetReadOnly marks the wallet as read-only, or removes the mark
+	SetReadOnly(readOnly bool)
+	// MigratedTo returns the file name of the bip44 wallet the wallet was migrated to
+	MigratedTo() string
+	// SetMigratedTo records the file name of the bip44 wallet the wallet was migrated to
+	SetMigratedTo(filename string)
+	// UnlockedUntil returns the expiry of the unlock session of the wallet,
+	// the zero time if the wallet is not in an unlock session
+	UnlockedUntil() time.Time
+	// SetUnlockedUntil sets the expiry of the unlock session of the wallet, the zero time removes it
+	SetUnlockedUntil(t time.Time)
+	// SpendPolicy returns the spend policy of the wallet
+	SpendPolicy() SpendPolicy
+	// SetSpendPolicy sets the spend policy of the wallet
+	SetSpendPolicy(p SpendPolicy)
+	// SpentOn returns the number of droplets sent on the UTC day of t
+	SpentOn(t time.Time) uint64
+	// AddSpent records the droplets sent on the UTC day of t
+	AddSpent(t time.Time, coins uint64) error
 	// SetDecoder sets the wallet decoder
 	SetDecoder(d Decoder)
 	// Version returns the wallet version
 	Version() string
 	// Secrets returns the wallet secrets data
 	Secrets() string
+	// DuressSecrets returns the decoy secrets data encrypted with the duress password
+	DuressSecrets() string
+	// SetDuressSecrets sets the decoy secrets data, an empty string removes it
+	SetDuressSecrets(s string)
 	// XPub returns the xpub key of a xpub wallet
 	XPub() string
+	// IsXPubAccount returns whether the xpub of the wallet is a bip44 account key
+	IsXPubAccount() bool
+	// SetXPubAccount marks the xpub of the wallet as a bip44 account key, or removes the mark
+	SetXPubAccount(account bool)
+	// AddressEncoding returns the encoding of the addresses generated by the wallet
+	AddressEncoding() AddressEncoding
+	// SetAddressEncoding sets the encoding of the generated addresses
+	SetAddressEncoding(enc AddressEncoding)
+	// Device returns the device type of a hardware wallet
+	Device() string
+	// DerivationPath returns the bip32 path of the xpub key of a hardware wallet
+	DerivationPath() string
+	// Signer returns the name of the keyring that signs with the keys of a collection-watch wallet
+	Signer() string
 	// Lock encrypts the wallet
 	Lock(password []byte) error
 	// Unlock decrypts the wallets, returns an copy of the decrypted wallet
 	Unlock(password []byte) (Wallet, error)
-	// Erase wipes sensitive data
+	// Erase wipes sensitive data, the buffers of the seeds and entry secret keys are destroyed
 	Erase()
 	// Clone returns a copy of the wallet
 	Clone() Wallet
@@ -232,6 +474,12 @@ type Wallet interface {
 	// for bip44 wallet, if no options are specified, it will search the external chain of account
 	// of index 0.
 	GetEntry(addr cipher.Addresser, options ...Option) (Entry, error)
+	// GetEntryMeta returns the label, note and tags of the address
+	// for bip44 wallet, if no options are specified, it will search the account of index 0.
+	GetEntryMeta(addr cipher.Addresser, options ...Option) (EntryMeta, error)
+	// SetEntryMeta sets the label, note and tags of the address
+	// for bip44 wallet, if no options are specified, it will search the account of index 0.
+	SetEntryMeta(addr cipher.Addresser, m EntryMeta, options ...Option) error
 	// HasEntry returns whether the entry exists in the wallet
 	// for bip44 wallet, if no options are specified, it will check the external chain of account
 	// of index 0.
@@ -266,7 +514,25 @@ func NewWallet(filename, label, seed string, options Options) (Wallet, error) {
 		return nil, fmt.Errorf("wallet.NewWallet failed, wallet type %q is not supported", options.Type)
 	}
 
-	return c.Create(filename, label, seed, options)
+	var w Wallet
+	var err error
+	if len(options.SeedShares) > 0 {
+		if seed != "" {
+			return nil, ErrSeedAndSeedShares
+		}
+		w, err = createFromSeedShares(c, filename, label, options)
+	} else {
+		w, err = c.Create(filename, label, seed, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SeedEntropy != "" {
+		w.SetSeedEntropy(options.SeedEntropy)
+	}
+
+	return w, nil
 }
 
 // Bip44Account represents the wallet account
@@ -339,13 +605,20 @@ type walletLoadMeta struct {
 	} `json:"meta"`
 }
 
-// Save saves the wallet to a directory. The wallet's filename is read from its metadata.
+// Save saves the wallet to a directory in the v3 wallet file format. The wallet's filename is read from its metadata.
+// If the existing wallet file is in the v1/v2 format, it is backed up before being overwritten.
 func Save(w Wallet, dir string) error {
-	data, err := w.Serialize()
+	data, err := EncodeFile(w)
 	if err != nil {
 		return err
 	}
-	return file.SaveBinary(filepath.Join(dir, w.Filename()), data, 0600)
+
+	filename := filepath.Join(dir, w.Filename())
+	if err := backupLegacyFile(filename); err != nil {
+		return fmt.Errorf("backup wallet file failed: %v", err)
+	}
+
+	return file.SaveBinary(filename, data, 0600)
 }
 
 // Load loads wallet from a file
@@ -354,18 +627,37 @@ func Load(filename string) (Wallet, error) {
 		return nil, fmt.Errorf("wallet %q doesn't exist", filename)
 	}
 
-	// Load the wallet meta type field from JSON
-	m, err := loadWalletMeta(filename)
+	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	if m.Meta.Type == "" {
-		err := errors.New("missing meta.type field")
-		logger.WithError(err).WithField("filename", filename)
+	w, err := loadData(data)
+	if err != nil {
+		logger.WithError(err).WithField("filename", filename).Error("Load: load wallet data failed")
+		return nil, err
+	}
+	if w == nil {
+		return nil, nil
+	}
+
+	w.SetFilename(filepath.Base(filename))
+	return w, nil
+}
+
+// loadData loads the wallet from the data of a wallet file,
+// returns nil if there is no loader of the wallet type
+func loadData(data []byte) (Wallet, error) {
+	// Load the wallet meta type field from JSON
+	var m walletLoadMeta
+	if err := json.Unmarshal(data, &m); err != nil {
 		return nil, err
 	}
 
+	if m.Meta.Type == "" {
+		return nil, errors.New("missing meta.type field")
+	}
+
 	// Depending on the wallet type in the wallet metadata header, load the full wallet data
 	l, ok := getLoader(m.Meta.Type)
 	if !ok {
@@ -373,18 +665,12 @@ func Load(filename string) (Wallet, error) {
 		return nil, nil
 	}
 
-	data, err := ioutil.ReadFile(filename)
+	data, err := decodeFile(data)
 	if err != nil {
 		return nil, err
 	}
 
-	w, err := l.Load(data)
-	if err != nil {
-		return nil, err
-	}
-
-	w.SetFilename(filepath.Base(filename))
-	return w, nil
+	return l.Load(data)
 }
 
 // removeBackupFiles removes any *.wlt.bak files whom have version 0.1 and *.wlt matched in the given directory
@@ -457,28 +743,34 @@ func IsValidWalletType(t string) bool {
 	case WalletTypeDeterministic,
 		WalletTypeCollection,
 		WalletTypeBip44,
-		WalletTypeXPub:
+		WalletTypeXPub,
+		WalletTypeMultisig,
+		WalletTypeHardware,
+		WalletTypeCollectionWatch:
 		return true
 	default:
 		return false
 	}
 }
 
-// AddressConstructor returns a function to create a cipher.Addresser from a cipher.PubKey
+// AddressConstructor returns a function to create a cipher.Addresser from a cipher.PubKey,
+// the constructor is the one registered for the wallet coin type and address encoding
 func AddressConstructor(m Meta) func(cipher.PubKey) cipher.Addresser {
-	switch m.Coin() {
-	case CoinTypeSkycoin:
-		return func(pk cipher.PubKey) cipher.Addresser {
-			return cipher.AddressFromPubKey(pk)
-		}
-	case CoinTypeBitcoin:
-		return func(pk cipher.PubKey) cipher.Addresser {
-			return cipher.BitcoinAddressFromPubKey(pk)
-		}
-	default:
+	info, ok := GetCoinTypeInfo(m.Coin())
+	if !ok {
 		logger.Panicf("Invalid wallet coin type %q", m.Coin())
 		return nil
 	}
+
+	if enc := m.AddressEncoding(); enc != AddressEncodingDefault {
+		f, ok := info.AddressEncodings[enc]
+		if !ok {
+			logger.Panicf("Invalid address encoding %q of wallet coin type %q", enc, m.Coin())
+			return nil
+		}
+		return f
+	}
+	return info.AddressFromPubKey
 }
 
 // ValidateMeta validates the common meta data when initializing a wallet
@@ -502,6 +794,10 @@ func ValidateMeta(m Meta) error {
 		return errors.New("coin field not set")
 	}
 
+	if err := validateAddressEncoding(m); err != nil {
+		return err
+	}
+
 	var isEncrypted bool
 	if encStr, ok := m[MetaEncrypted]; ok {
 		// validate the encrypted value
@@ -525,6 +821,18 @@ func ValidateMeta(m Meta) error {
 	return nil
 }
 
+// GetCryptor returns the Cryptor of the crypto type. The argon2id-chacha20poly1305
+// Cryptor is configured with the argon2id parameters of the wallet meta, if any.
+func GetCryptor(cryptoType crypto.CryptoType, m Meta) (crypto.Cryptor, error) {
+	if cryptoType == crypto.CryptoTypeArgon2idChacha20poly1305 {
+		if iterations, memory := m.Argon2Params(); iterations != 0 && memory != 0 {
+			return crypto.NewArgon2idCrypto(iterations, memory), nil
+		}
+	}
+
+	return crypto.GetCrypto(cryptoType)
+}
+
 // ValidateMetaCryptoType validates meta crypto type
 func ValidateMetaCryptoType(m Meta) error {
 	cryptoType, ok := m[MetaCryptoType]
@@ -539,14 +847,14 @@ func ValidateMetaCryptoType(m Meta) error {
 	return nil
 }
 
-// ValidateMetaSeed validate meta seed
-func ValidateMetaSeed(m Meta) error {
+// ValidateMetaSeed validates the seed of the wallet of the meta
+func ValidateMetaSeed(m Meta, s Seeds) error {
 	if m.IsEncrypted() {
-		if s := m[MetaSeed]; s != "" {
+		if len(s.SeedBytes()) != 0 {
 			return errors.New("seed should not be visible in encrypted wallets")
 		}
 	} else {
-		if s := m[MetaSeed]; s == "" {
+		if len(s.SeedBytes()) == 0 {
 			return ErrMissingSeed
 		}
 	}
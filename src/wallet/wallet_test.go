@@ -1,13 +1,24 @@
 package wallet
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/bip39"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	"github.com/skycoin/skycoin/src/cipher/encrypt"
+	"github.com/skycoin/skycoin/src/util/file"
+	"github.com/skycoin/skycoin/src/wallet/crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -406,3 +417,470 @@ func prepareWltDir() string {
 
 	return dir
 }
+
+func TestEncodeDecodeFile(t *testing.T) {
+	m := Meta{
+		MetaVersion:            Version,
+		MetaFilename:           "test.wlt",
+		MetaLabel:              "",
+		MetaTimestamp:          "1503458909",
+		MetaType:               WalletTypeMultisig,
+		MetaCoin:               string(CoinTypeSkycoin),
+		MetaEncrypted:          "false",
+		MetaPubKeys:            "pk1,pk2",
+		MetaThreshold:          "2",
+		MetaRestoredFromShares: "true",
+		MetaSharesIdentifier:   "1234",
+		"unknownField":         "foo",
+	}
+	require.NoError(t, m.SetExtension("com.example.tracker", []byte(`{"id":1,"tags":["a"]}`)))
+
+	data, err := json.Marshal(map[string]interface{}{
+		"meta":    m,
+		"entries": []string{"x"},
+	})
+	require.NoError(t, err)
+
+	b, err := encodeFile(data)
+	require.NoError(t, err)
+
+	var f struct {
+		FormatVersion int                        `json:"formatVersion"`
+		Meta          map[string]interface{}     `json:"meta"`
+		Extensions    map[string]json.RawMessage `json:"extensions"`
+		Entries       []string                   `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(b, &f))
+	require.Equal(t, FileFormatVersion, f.FormatVersion)
+	require.Equal(t, float64(1503458909), f.Meta[MetaTimestamp])
+	require.Equal(t, false, f.Meta[MetaEncrypted])
+	require.Equal(t, []interface{}{"pk1", "pk2"}, f.Meta[MetaPubKeys])
+	require.Equal(t, float64(2), f.Meta[MetaThreshold])
+	require.Equal(t, true, f.Meta[MetaRestoredFromShares])
+	require.Equal(t, float64(1234), f.Meta[MetaSharesIdentifier])
+	require.Equal(t, "", f.Meta[MetaLabel])
+	require.Equal(t, "foo", f.Meta["unknownField"])
+	require.NotContains(t, f.Meta, MetaExtensionPrefix+"com.example.tracker")
+	require.JSONEq(t, `{"id":1,"tags":["a"]}`, string(f.Extensions["com.example.tracker"]))
+	require.Equal(t, []string{"x"}, f.Entries)
+
+	// Decodes back to the serialized wallet
+	lb, err := decodeFile(b)
+	require.NoError(t, err)
+
+	var lf struct {
+		Meta          Meta     `json:"meta"`
+		Entries       []string `json:"entries"`
+		FormatVersion *int     `json:"formatVersion"`
+	}
+	require.NoError(t, json.Unmarshal(lb, &lf))
+	require.Equal(t, m, lf.Meta)
+	require.Equal(t, []string{"x"}, lf.Entries)
+	require.Nil(t, lf.FormatVersion)
+
+	// The v1/v2 wallet files are not changed
+	lb, err = decodeFile(data)
+	require.NoError(t, err)
+	require.Equal(t, data, lb)
+}
+
+func TestDecodeFileErrors(t *testing.T) {
+	tt := []struct {
+		name      string
+		data      string
+		err       error
+		errPrefix string
+	}{
+		{
+			name: "unsupported format version",
+			data: `{"formatVersion": 4, "meta": {}}`,
+			err:  ErrUnsupportedFileFormat,
+		},
+		{
+			name:      "invalid format version",
+			data:      `{"formatVersion": "3", "meta": {}}`,
+			errPrefix: "invalid formatVersion:",
+		},
+		{
+			name:      "invalid typed meta field",
+			data:      `{"formatVersion": 3, "meta": {"encrypted": "false"}}`,
+			errPrefix: `invalid meta field "encrypted":`,
+		},
+		{
+			name:      "invalid extensions",
+			data:      `{"formatVersion": 3, "meta": {}, "extensions": []}`,
+			errPrefix: "invalid extensions:",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeFile([]byte(tc.data))
+			if tc.errPrefix != "" {
+				require.Error(t, err)
+				require.True(t, strings.HasPrefix(err.Error(), tc.errPrefix), err.Error())
+				return
+			}
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestMetaExtensions(t *testing.T) {
+	m := Meta{}
+	require.Empty(t, m.Extensions())
+
+	require.NoError(t, m.SetExtension("foo", []byte(`"bar"`)))
+	require.Equal(t, map[string]json.RawMessage{"foo": json.RawMessage(`"bar"`)}, m.Extensions())
+
+	require.Error(t, m.SetExtension("foo", []byte(`{`)))
+	require.Error(t, m.SetExtension("", []byte(`1`)))
+
+	require.NoError(t, m.SetExtension("foo", nil))
+	require.Empty(t, m.Extensions())
+}
+
+func TestMetaSpendPolicy(t *testing.T) {
+	m := Meta{
+		MetaFilename: "test.wlt",
+		MetaType:     WalletTypeDeterministic,
+		MetaCoin:     string(CoinTypeSkycoin),
+	}
+	require.Equal(t, SpendPolicy{}, m.SpendPolicy())
+
+	p := SpendPolicy{DailyLimit: 10e6, RequirePassword: true}
+	m.SetSpendPolicy(p)
+	require.Equal(t, p, m.SpendPolicy())
+	require.NoError(t, m.Validate())
+
+	m.SetSpendPolicy(SpendPolicy{})
+	require.NotContains(t, m, MetaDailySpendLimit)
+	require.NotContains(t, m, MetaRequirePassword)
+
+	// The spent droplets are recorded per UTC day
+	day := time.Date(2020, 5, 1, 23, 0, 0, 0, time.UTC)
+	require.Equal(t, uint64(0), m.SpentOn(day))
+	require.NoError(t, m.AddSpent(day, 2e6))
+	require.NoError(t, m.AddSpent(day.Add(30*time.Minute), 3e6))
+	require.Equal(t, "2020-05-01", m[MetaSpentDay])
+	require.Equal(t, uint64(5e6), m.SpentOn(day))
+
+	// The droplets spent on the previous day are discarded
+	require.NoError(t, m.AddSpent(day.Add(time.Hour), 1e6))
+	require.Equal(t, "2020-05-02", m[MetaSpentDay])
+	require.Equal(t, uint64(1e6), m.SpentOn(day.Add(2*time.Hour)))
+	require.Equal(t, uint64(0), m.SpentOn(day))
+	require.Equal(t, uint64(0), m.SpentOn(day.Add(25*time.Hour)))
+	require.NoError(t, m.Validate())
+
+	require.Error(t, m.AddSpent(day.Add(time.Hour), math.MaxUint64))
+
+	for k, v := range map[string]string{
+		MetaDailySpendLimit: "-1",
+		MetaRequirePassword: "yes",
+		MetaSpent:           "x",
+		MetaSpentDay:        "05/02/2020",
+	} {
+		mm := m.Clone()
+		mm[k] = v
+		require.Equal(t, fmt.Errorf("invalid %s", k), mm.Validate())
+	}
+}
+
+func TestFileSchemaV3(t *testing.T) {
+	var s struct {
+		Properties struct {
+			Meta struct {
+				Properties map[string]struct {
+					Type string `json:"type"`
+				} `json:"properties"`
+			} `json:"meta"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(FileSchemaV3), &s))
+
+	// The schema types of the meta fields match the types written to the wallet files
+	schemaTypes := map[metaFieldType]string{
+		metaBool:       "boolean",
+		metaInt:        "integer",
+		metaUint:       "integer",
+		metaStringList: "array",
+	}
+	props := s.Properties.Meta.Properties
+	for k, ft := range metaFieldTypes {
+		require.Contains(t, props, k)
+		require.Equal(t, schemaTypes[ft], props[k].Type, k)
+	}
+
+	for k, p := range props {
+		if _, ok := metaFieldTypes[k]; !ok {
+			require.Equal(t, "string", p.Type, k)
+		}
+	}
+}
+
+func TestLockDir(t *testing.T) {
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+
+	l, err := Lock(dir)
+	require.NoError(t, err)
+
+	// The lock is shared within the process
+	l2, err := Lock(dir)
+	require.NoError(t, err)
+
+	// Another process can not take the lock of the directory
+	_, err = file.TryLock(filepath.Join(dir, LockFilename))
+	require.Equal(t, file.ErrLocked, err)
+
+	require.NoError(t, l.Unlock())
+	require.NoError(t, l.Unlock())
+	_, err = file.TryLock(filepath.Join(dir, LockFilename))
+	require.Equal(t, file.ErrLocked, err)
+
+	// The directory is unlocked when all of its locks are unlocked
+	require.NoError(t, l2.Unlock())
+	fl, err := file.TryLock(filepath.Join(dir, LockFilename))
+	require.NoError(t, err)
+
+	_, err = Lock(dir)
+	require.Equal(t, ErrWalletDirLocked, err)
+	require.NoError(t, fl.Unlock())
+}
+
+func TestOptionsValidateGapLimit(t *testing.T) {
+	tt := []struct {
+		name string
+		opts Options
+		err  error
+	}{
+		{
+			name: "ok",
+			opts: Options{Type: WalletTypeBip44, GapLimit: DefaultGapLimit, TF: &fakeTransactionsFinder{}},
+		},
+		{
+			name: "not bip44 wallet",
+			opts: Options{Type: WalletTypeDeterministic, GapLimit: DefaultGapLimit, TF: &fakeTransactionsFinder{}},
+			err:  ErrWalletGapLimit,
+		},
+		{
+			name: "missing transactions finder",
+			opts: Options{Type: WalletTypeBip44, GapLimit: DefaultGapLimit},
+			err:  ErrNilTransactionsFinder,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, tc.opts.Validate())
+		})
+	}
+}
+
+type fakeTransactionsFinder struct{}
+
+func (fakeTransactionsFinder) AddressesActivity(addrs []cipher.Addresser) ([]bool, error) {
+	return make([]bool, len(addrs)), nil
+}
+
+func TestResolveCoinType(t *testing.T) {
+	for s, ct := range map[string]CoinType{
+		"sky":      CoinTypeSkycoin,
+		"Skycoin":  CoinTypeSkycoin,
+		"btc":      CoinTypeBitcoin,
+		"bitcoin":  CoinTypeBitcoin,
+		"eth":      CoinTypeEthereum,
+		"ETHEREUM": CoinTypeEthereum,
+	} {
+		c, err := ResolveCoinType(s)
+		require.NoError(t, err)
+		require.Equal(t, ct, c)
+	}
+
+	_, err := ResolveCoinType("doge")
+	require.Equal(t, errors.New("invalid coin type"), err)
+}
+
+func TestEthereumDecoder(t *testing.T) {
+	sk := cipher.MustSecKeyFromHex("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	pk := cipher.MustPubKeyFromSecKey(sk)
+
+	d := ResolveAddressSecKeyDecoder(CoinTypeEthereum)
+	addr := d.AddressFromPubKey(pk)
+	require.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", addr.String())
+	require.Equal(t, addr, AddressConstructor(Meta{MetaCoin: string(CoinTypeEthereum)})(pk))
+
+	a, err := d.DecodeBase58Address(addr.String())
+	require.NoError(t, err)
+	require.Equal(t, addr, a)
+
+	require.Equal(t, sk.Hex(), d.SecKeyToHex(sk))
+	for _, s := range []string{sk.Hex(), "0x" + sk.Hex()} {
+		k, err := d.SecKeyFromHex(s)
+		require.NoError(t, err)
+		require.Equal(t, sk, k)
+	}
+}
+
+func TestBitcoinAddressEncoding(t *testing.T) {
+	sk := cipher.MustSecKeyFromHex("0000000000000000000000000000000000000000000000000000000000000001")
+	pk := cipher.MustPubKeyFromSecKey(sk)
+
+	m := Meta{MetaCoin: string(CoinTypeBitcoin)}
+	require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", AddressConstructor(m)(pk).String())
+
+	m.SetAddressEncoding(AddressEncodingBase58)
+	require.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", AddressConstructor(m)(pk).String())
+
+	m.SetAddressEncoding(AddressEncodingBech32)
+	require.Equal(t, AddressEncodingBech32, m.AddressEncoding())
+	addr := AddressConstructor(m)(pk)
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", addr.String())
+
+	// The bitcoin decoder decodes both encodings
+	d := ResolveAddressSecKeyDecoder(CoinTypeBitcoin)
+	for _, s := range []string{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4"} {
+		a, err := d.DecodeBase58Address(s)
+		require.NoError(t, err)
+		require.Equal(t, addr, a)
+	}
+	a, err := d.DecodeBase58Address("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH")
+	require.NoError(t, err)
+	require.Equal(t, cipher.BitcoinAddressFromPubKey(pk), a)
+
+	m.SetAddressEncoding(AddressEncodingDefault)
+	_, ok := m[MetaAddressEncoding]
+	require.False(t, ok)
+
+	// Skycoin has a single address encoding
+	_, err = ResolveAddressConstructor(CoinTypeSkycoin, AddressEncodingBech32)
+	require.Equal(t, ErrInvalidAddressEncoding, err)
+	_, err = ResolveAddressConstructor(CoinTypeBitcoin, AddressEncoding("base32"))
+	require.Equal(t, ErrInvalidAddressEncoding, err)
+	require.Panics(t, func() {
+		AddressConstructor(Meta{MetaCoin: string(CoinTypeSkycoin), MetaAddressEncoding: string(AddressEncodingBech32)})
+	})
+
+	tt := []struct {
+		name string
+		opts Options
+		err  error
+	}{
+		{
+			name: "bip44",
+			opts: Options{Type: WalletTypeBip44, Coin: CoinTypeBitcoin, AddressEncoding: AddressEncodingBech32},
+		},
+		{
+			name: "deterministic",
+			opts: Options{Type: WalletTypeDeterministic, Coin: CoinTypeBitcoin, AddressEncoding: AddressEncodingBase58},
+		},
+		{
+			name: "skycoin",
+			opts: Options{Type: WalletTypeBip44, AddressEncoding: AddressEncodingBech32},
+			err:  ErrInvalidAddressEncoding,
+		},
+		{
+			name: "collection wallet",
+			opts: Options{Type: WalletTypeCollection, Coin: CoinTypeBitcoin, AddressEncoding: AddressEncodingBech32},
+			err:  ErrWalletAddressEncoding,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, tc.opts.Validate())
+		})
+	}
+
+	// Loaded wallets are validated
+	vm := Meta{
+		MetaFilename:        "test.wlt",
+		MetaType:            string(WalletTypeDeterministic),
+		MetaCoin:            string(CoinTypeSkycoin),
+		MetaAddressEncoding: string(AddressEncodingBech32),
+	}
+	require.Equal(t, ErrInvalidAddressEncoding, ValidateMeta(vm))
+	require.Equal(t, fmt.Errorf("invalid %s", MetaAddressEncoding), vm.Validate())
+
+	vm.SetCoin(CoinTypeBitcoin)
+	require.NoError(t, ValidateMeta(vm))
+}
+
+func TestRegisterCoinType(t *testing.T) {
+	ct := CoinType("testcoin")
+	info := CoinTypeInfo{
+		Type:      ct,
+		Aliases:   []string{"TST"},
+		Bip44Coin: bip44.CoinType(99999),
+		AddressFromPubKey: func(pk cipher.PubKey) cipher.Addresser {
+			return cipher.BitcoinAddressFromPubKey(pk)
+		},
+		Decoder: bitcoinDecoder{},
+	}
+	require.NoError(t, RegisterCoinType(info))
+
+	c, err := ResolveCoinType("tst")
+	require.NoError(t, err)
+	require.Equal(t, ct, c)
+	require.Contains(t, CoinTypes(), ct)
+
+	bc, ok := ResolveBip44CoinType(ct)
+	require.True(t, ok)
+	require.Equal(t, bip44.CoinType(99999), bc)
+
+	pk, sk := cipher.GenerateKeyPair()
+	require.Equal(t, cipher.BitcoinAddressFromPubKey(pk), AddressConstructor(Meta{MetaCoin: string(ct)})(pk))
+	require.Equal(t, cipher.BitcoinWalletImportFormatFromSeckey(sk), ResolveSecKeyDecoder(ct).SecKeyToHex(sk))
+
+	// Names and aliases can not be registered twice
+	require.Equal(t, errors.New("coin type testcoin already registered"), RegisterCoinType(CoinTypeInfo{
+		Type:              ct,
+		AddressFromPubKey: info.AddressFromPubKey,
+	}))
+	require.Equal(t, errors.New("coin type sky already registered"), RegisterCoinType(CoinTypeInfo{
+		Type:              "othercoin",
+		Aliases:           []string{"sky"},
+		AddressFromPubKey: info.AddressFromPubKey,
+	}))
+	require.Equal(t, errors.New("address constructor of coin type othercoin is nil"), RegisterCoinType(CoinTypeInfo{
+		Type: "othercoin",
+	}))
+
+	_, ok = ResolveBip44CoinType("othercoin")
+	require.False(t, ok)
+	require.Panics(t, func() {
+		AddressConstructor(Meta{MetaCoin: "othercoin"})
+	})
+}
+
+func TestWalletCryptor(t *testing.T) {
+	// The argon2id cryptor uses the parameters of the wallet
+	w := &MockWallet{}
+	w.On("CryptoType").Return(crypto.CryptoTypeArgon2idChacha20poly1305)
+	w.On("Argon2Params").Return(uint32(2), uint32(1024))
+
+	c, err := walletCryptor(w)
+	require.NoError(t, err)
+	require.Equal(t, crypto.NewArgon2idCrypto(2, 1024), c)
+
+	// The wallets without argon2id parameters use the default cryptor
+	w = &MockWallet{}
+	w.On("CryptoType").Return(crypto.CryptoTypeArgon2idChacha20poly1305)
+	w.On("Argon2Params").Return(uint32(0), uint32(0))
+
+	c, err = walletCryptor(w)
+	require.NoError(t, err)
+	defaultCryptor, err := crypto.GetCrypto(crypto.CryptoTypeArgon2idChacha20poly1305)
+	require.NoError(t, err)
+	require.Equal(t, defaultCryptor, c)
+
+	// The other crypto types ignore the parameters
+	c, err = GetCryptor(crypto.CryptoTypeScryptChacha20poly1305, Meta{
+		MetaArgon2Iterations: "2",
+		MetaArgon2Memory:     "1024",
+	})
+	require.NoError(t, err)
+	_, ok := c.(encrypt.ScryptChacha20poly1305)
+	require.True(t, ok)
+}
@@ -34,6 +34,10 @@ func loadWallets(dir string, loader Loader) (Wallets, error) {
 			if err != nil {
 				return nil, err
 			}
+			data, err = decodeFile(data)
+			if err != nil {
+				return nil, err
+			}
 			w, err := loader.Load(data)
 			if err != nil {
 				logger.WithError(err).WithField("filename", fullpath).Error("loadWallets: loadWallet failed")
@@ -62,85 +66,3 @@ func loadWallets(dir string, loader Loader) (Wallets, error) {
 
 	return wallets, nil
 }
-
-// add add wallet to current wallet
-func (wlts Wallets) add(w Wallet) error {
-	if _, dup := wlts[w.Filename()]; dup {
-		return ErrWalletNameConflict
-	}
-
-	wlts[w.Filename()] = w
-	return nil
-}
-
-// remove wallet of specific id
-func (wlts Wallets) remove(id string) {
-	delete(wlts, id)
-}
-
-// get returns wallet by wallet id
-func (wlts Wallets) get(id string) Wallet {
-	return wlts[id]
-}
-
-// set sets a wallet into the map
-func (wlts Wallets) set(w Wallet) {
-	wlts[w.Filename()] = w.Clone()
-}
-
-// containsDuplicate returns true if there is a duplicate wallet identified by
-// the wallet's fingerprint. This is to detect duplicate generative wallets;
-// wallets with no defined generation method do not have a concept of being
-// a duplicate of another wallet
-func (wlts Wallets) containsDuplicate() (string, string, bool) {
-	m := make(map[string]struct{}, len(wlts))
-	for wltID, wlt := range wlts {
-		fp := wlt.Fingerprint()
-		if fp == "" {
-			continue
-		}
-
-		if _, ok := m[fp]; ok {
-			return wltID, fp, true
-		}
-
-		m[fp] = struct{}{}
-	}
-
-	return "", "", false
-}
-
-// containsEmpty returns true there is an empty wallet and the ID of that wallet if true.
-// Does not apply to collection wallets
-func (wlts Wallets) containsEmpty() (string, bool) {
-	for wltID, wlt := range wlts {
-		switch wlt.Type() {
-		case WalletTypeCollection:
-			continue
-		case WalletTypeBip44:
-			var l int
-			// gets the external entries length
-			for _, a := range wlt.Accounts() {
-				el, err := wlt.EntriesLen(OptionAccount(a.Index))
-				if err != nil {
-					panic(err)
-				}
-				l += el
-			}
-
-			if l == 0 {
-				return wltID, true
-			}
-		default:
-			l, err := wlt.EntriesLen()
-			if err != nil {
-				panic(err)
-			}
-
-			if l == 0 {
-				return wltID, true
-			}
-		}
-	}
-	return "", false
-}
@@ -0,0 +1,91 @@
+package watchwallet
+
+import (
+	"encoding/json"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// JSONDecoder implements the the WalletDecoder interface,
+// which provides methods for encoding and decoding a collection-watch wallet in JSON format.
+type JSONDecoder struct{}
+
+// Encode encodes the collection-watch wallet to []byte, and error if any
+func (d JSONDecoder) Encode(w wallet.Wallet) ([]byte, error) {
+	return json.MarshalIndent(newReadableWallet(w.(*Wallet)), "", "    ")
+}
+
+// Decode decodes the collection-watch wallet from byte slice
+func (d JSONDecoder) Decode(b []byte) (wallet.Wallet, error) {
+	rw := readableWallet{}
+	if err := json.Unmarshal(b, &rw); err != nil {
+		return nil, err
+	}
+
+	return rw.toWallet()
+}
+
+type readableWallet struct {
+	wallet.Meta `json:"meta"`
+	Entries     readableWatchEntries `json:"entries"`
+}
+
+func (rw readableWallet) toWallet() (*Wallet, error) {
+	if err := validateMeta(rw.Meta); err != nil {
+		return nil, err
+	}
+
+	entries, err := rw.Entries.toEntries(wallet.ResolveAddressDecoder(rw.Coin()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Meta:    rw.Meta.Clone(),
+		entries: entries,
+		decoder: &JSONDecoder{},
+	}, nil
+}
+
+func newReadableWallet(w *Wallet) *readableWallet {
+	return &readableWallet{
+		Meta:    w.Meta.Clone(),
+		Entries: newReadableEntries(w.entries),
+	}
+}
+
+type readableWatchEntries []readableWatchEntry
+
+func (es readableWatchEntries) toEntries(ad wallet.AddressDecoder) (wallet.Entries, error) {
+	entries := make(wallet.Entries, len(es))
+	for i, e := range es {
+		addr, err := ad.DecodeBase58Address(e.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i] = wallet.Entry{
+			Address:   addr,
+			EntryMeta: e.ToEntryMeta(),
+		}
+	}
+
+	return entries, nil
+}
+
+func newReadableEntries(entries wallet.Entries) readableWatchEntries {
+	res := make(readableWatchEntries, len(entries))
+	for i, e := range entries {
+		res[i] = readableWatchEntry{
+			Address:           e.Address.String(),
+			ReadableEntryMeta: wallet.NewReadableEntryMeta(e.EntryMeta),
+		}
+	}
+
+	return res
+}
+
+type readableWatchEntry struct {
+	Address string `json:"address"`
+	wallet.ReadableEntryMeta
+}
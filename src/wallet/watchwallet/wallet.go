@@ -0,0 +1,328 @@
+package watchwallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletType represents the collection-watch wallet type
+const WalletType = "collection-watch"
+
+var defaultWalletDecoder = &JSONDecoder{}
+
+var (
+	// ErrMissingAddresses is returned when creating a collection-watch wallet without addresses
+	ErrMissingAddresses = wallet.NewError(errors.New("missing watch addresses"))
+	// ErrNullAddress is returned when adding a null address to the wallet
+	ErrNullAddress = wallet.NewError(errors.New("null address"))
+	// ErrDuplicateAddress is returned if the same address is added to the wallet twice
+	ErrDuplicateAddress = wallet.NewError(errors.New("wallet already contains entry with this address"))
+
+	errWatchEncryption    = errors.New("collection-watch wallet does not support encryption")
+	errWatchGenerateAddrs = errors.New("A collection-watch wallet does not implement GenerateAddresses")
+)
+
+func init() {
+	if err := wallet.RegisterCreator(WalletType, &Creator{}); err != nil {
+		panic(err)
+	}
+
+	if err := wallet.RegisterLoader(WalletType, &Loader{}); err != nil {
+		panic(err)
+	}
+}
+
+// Wallet holds an arbitrary list of addresses with no keys at all, so
+// that the balances and transactions of the addresses can be monitored,
+// e.g. by exchanges watching their deposit addresses.
+// The wallet can not generate addresses. It signs transactions only if it is
+// created with a signer, the name of a keyring registered with wallet.RegisterKeyring,
+// e.g. of keys held in a PKCS#11 HSM.
+type Wallet struct {
+	wallet.Meta
+	wallet.Seeds
+	entries wallet.Entries
+	decoder wallet.Decoder
+}
+
+// NewWallet creates a collection-watch wallet of the addresses with options
+func NewWallet(filename, label string, addrs []cipher.Addresser, options ...wallet.Option) (*Wallet, error) {
+	if len(addrs) == 0 {
+		return nil, ErrMissingAddresses
+	}
+
+	wlt := &Wallet{
+		Meta: wallet.Meta{
+			wallet.MetaFilename:  filename,
+			wallet.MetaLabel:     label,
+			wallet.MetaType:      WalletType,
+			wallet.MetaVersion:   wallet.Version,
+			wallet.MetaCoin:      string(wallet.CoinTypeSkycoin),
+			wallet.MetaTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		},
+		decoder: defaultWalletDecoder,
+	}
+
+	advOpts := &wallet.AdvancedOptions{}
+	for _, opt := range options {
+		opt(wlt)
+		opt(advOpts)
+	}
+
+	if err := validateMeta(wlt.Meta); err != nil {
+		return nil, err
+	}
+
+	if advOpts.Encrypt {
+		return nil, wallet.NewError(errWatchEncryption)
+	}
+
+	if err := wlt.AddAddresses(addrs...); err != nil {
+		return nil, err
+	}
+
+	return wlt, nil
+}
+
+func validateMeta(m wallet.Meta) error {
+	if m[wallet.MetaType] != WalletType {
+		return wallet.ErrInvalidWalletType
+	}
+
+	if m[wallet.MetaSeed] != "" {
+		return wallet.NewError(fmt.Errorf("seed should not be provided for %q wallets", WalletType))
+	}
+
+	return wallet.ValidateMeta(m)
+}
+
+// AddAddresses adds watch addresses to the wallet
+func (w *Wallet) AddAddresses(addrs ...cipher.Addresser) error {
+	entries := w.entries.Clone()
+	for _, a := range addrs {
+		if a.Null() {
+			return ErrNullAddress
+		}
+
+		if entries.Has(a) {
+			return ErrDuplicateAddress
+		}
+
+		entries = append(entries, wallet.Entry{
+			Address: a,
+		})
+	}
+
+	w.entries = entries
+	return nil
+}
+
+// SetDecoder sets the wallet decoder
+func (w *Wallet) SetDecoder(d wallet.Decoder) {
+	w.decoder = d
+}
+
+// Serialize encodes the collection-watch wallet to []byte
+func (w Wallet) Serialize() ([]byte, error) {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	return w.decoder.Encode(&w)
+}
+
+// Deserialize decodes the []byte to a collection-watch wallet
+func (w *Wallet) Deserialize(b []byte) error {
+	if w.decoder == nil {
+		w.decoder = defaultWalletDecoder
+	}
+
+	toW, err := w.decoder.Decode(b)
+	if err != nil {
+		return err
+	}
+
+	toW2 := toW.(*Wallet)
+	toW2.decoder = w.decoder
+	*w = *toW2
+	return nil
+}
+
+// Lock is not supported, the collection-watch wallet has no secrets
+func (w *Wallet) Lock(_ []byte) error {
+	return wallet.NewError(errWatchEncryption)
+}
+
+// Unlock is not supported, the collection-watch wallet has no secrets
+func (w *Wallet) Unlock(_ []byte) (wallet.Wallet, error) {
+	return nil, wallet.NewError(errWatchEncryption)
+}
+
+// Fingerprint returns an empty string, the collection-watch wallets
+// are arbitrary address lists and do not have fingerprints
+func (w *Wallet) Fingerprint() string {
+	return ""
+}
+
+// Clone returns a copy of the wallet
+func (w *Wallet) Clone() wallet.Wallet {
+	return &Wallet{
+		Meta:    w.Meta.Clone(),
+		entries: w.entries.Clone(),
+		decoder: w.decoder,
+	}
+}
+
+// CopyFromRef copies the src wallet with a pointer dereference
+func (w *Wallet) CopyFromRef(src wallet.Wallet) {
+	*w = *(src.(*Wallet))
+}
+
+// Accounts is not implemented for collection-watch wallet
+func (w *Wallet) Accounts() []wallet.Bip44Account {
+	return nil
+}
+
+// Erase is a no-op, the collection-watch wallet has no sensitive data
+func (w *Wallet) Erase() {}
+
+// ScanAddresses is a no-op for collection-watch wallets, there are no addresses
+// to derive. All addresses of the wallet are always watched.
+func (w *Wallet) ScanAddresses(_ uint64, _ wallet.TransactionsFinder) ([]cipher.Addresser, error) {
+	return nil, nil
+}
+
+// GenerateAddresses is not supported, addresses must be added explicitly with AddAddresses
+func (w *Wallet) GenerateAddresses(_ uint64, _ ...wallet.Option) ([]cipher.Addresser, error) {
+	return nil, wallet.NewError(errWatchGenerateAddrs)
+}
+
+// GetAddresses returns all addresses of the wallet
+func (w *Wallet) GetAddresses(_ ...wallet.Option) ([]cipher.Addresser, error) {
+	return w.entries.GetAddresses(), nil
+}
+
+// GetEntries returns a copy of all entries held by the wallet
+func (w *Wallet) GetEntries(_ ...wallet.Option) (wallet.Entries, error) {
+	return w.entries.Clone(), nil
+}
+
+// GetEntryAt returns the entry at a given index in the entries array
+func (w *Wallet) GetEntryAt(i int, _ ...wallet.Option) (wallet.Entry, error) {
+	if i < 0 || i >= len(w.entries) {
+		return wallet.Entry{}, fmt.Errorf("entry index %d is out of range", i)
+	}
+	return w.entries[i], nil
+}
+
+// GetEntry returns the entry of given address
+func (w *Wallet) GetEntry(addr cipher.Addresser, _ ...wallet.Option) (wallet.Entry, error) {
+	e, ok := w.entries.Get(addr)
+	if !ok {
+		return wallet.Entry{}, wallet.ErrEntryNotFound
+	}
+	return e, nil
+}
+
+// GetEntryMeta returns the label, note and tags of the entry of given address
+func (w *Wallet) GetEntryMeta(addr cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	e, ok := w.entries.Get(addr)
+	if !ok {
+		return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+	}
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address
+func (w *Wallet) SetEntryMeta(addr cipher.Addresser, m wallet.EntryMeta, _ ...wallet.Option) error {
+	if !w.entries.SetMeta(addr, m) {
+		return wallet.ErrEntryNotFound
+	}
+	return nil
+}
+
+// HasEntry returns true if the wallet has an Entry with a given address
+func (w *Wallet) HasEntry(addr cipher.Addresser, _ ...wallet.Option) (bool, error) {
+	return w.entries.Has(addr), nil
+}
+
+// EntriesLen returns the number of entries in the wallet
+func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
+	return len(w.entries), nil
+}
+
+// Loader implements the wallet.Loader interface
+type Loader struct{}
+
+// Load loads the collection-watch wallet from byte slice
+func (l Loader) Load(data []byte) (wallet.Wallet, error) {
+	w := &Wallet{}
+	if err := w.Deserialize(data); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Creator implements the wallet.Creator interface
+type Creator struct{}
+
+// Create creates a collection-watch wallet
+func (c Creator) Create(filename, label, _ string, options wallet.Options) (wallet.Wallet, error) {
+	if options.Encrypt {
+		return nil, wallet.NewError(errWatchEncryption)
+	}
+
+	coin := options.Coin
+	if coin == "" {
+		coin = wallet.CoinTypeSkycoin
+	}
+
+	addrs, err := decodeAddresses(wallet.ResolveAddressDecoder(coin), options.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := NewWallet(filename, label, addrs, convertOptions(options)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Signer != "" {
+		w.SetSigner(options.Signer)
+	}
+
+	return w, nil
+}
+
+func decodeAddresses(ad wallet.AddressDecoder, ss []string) ([]cipher.Addresser, error) {
+	addrs := make([]cipher.Addresser, len(ss))
+	for i, s := range ss {
+		a, err := ad.DecodeBase58Address(s)
+		if err != nil {
+			return nil, wallet.NewError(fmt.Errorf("invalid address %q: %v", s, err))
+		}
+		addrs[i] = a
+	}
+
+	return addrs, nil
+}
+
+func convertOptions(options wallet.Options) []wallet.Option {
+	var opts []wallet.Option
+
+	if options.Coin != "" {
+		opts = append(opts, wallet.OptionCoinType(options.Coin))
+	}
+
+	if options.Decoder != nil {
+		opts = append(opts, wallet.OptionDecoder(options.Decoder))
+	}
+
+	return opts
+}
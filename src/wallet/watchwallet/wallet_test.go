@@ -0,0 +1,276 @@
+package watchwallet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/signer"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func makeAddresses(n int) []cipher.Addresser {
+	addrs := make([]cipher.Addresser, n)
+	for i := range addrs {
+		addrs[i] = testutil.MakeAddress()
+	}
+	return addrs
+}
+
+func TestNewWallet(t *testing.T) {
+	addrs := makeAddresses(3)
+
+	tt := []struct {
+		name  string
+		addrs []cipher.Addresser
+		opts  []wallet.Option
+		err   error
+	}{
+		{
+			name:  "ok",
+			addrs: addrs,
+		},
+		{
+			name:  "ok generate and scan options are ignored",
+			addrs: addrs,
+			opts:  []wallet.Option{wallet.OptionGenerateN(2), wallet.OptionScanN(5)},
+		},
+		{
+			name: "missing addresses",
+			err:  ErrMissingAddresses,
+		},
+		{
+			name:  "duplicate address",
+			addrs: []cipher.Addresser{addrs[0], addrs[1], addrs[0]},
+			err:   ErrDuplicateAddress,
+		},
+		{
+			name:  "null address",
+			addrs: []cipher.Addresser{addrs[0], cipher.Address{}},
+			err:   ErrNullAddress,
+		},
+		{
+			name:  "encrypt not supported",
+			addrs: addrs,
+			opts:  []wallet.Option{wallet.OptionEncrypt(true), wallet.OptionPassword([]byte("pwd"))},
+			err:   wallet.NewError(errWatchEncryption),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewWallet("test.wlt", "test", tc.addrs, tc.opts...)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, WalletType, w.Type())
+			require.Empty(t, w.Fingerprint())
+			require.False(t, w.IsEncrypted())
+
+			wAddrs, err := w.GetAddresses()
+			require.NoError(t, err)
+			require.Equal(t, tc.addrs, wAddrs)
+
+			entries, err := w.GetEntries()
+			require.NoError(t, err)
+			for _, e := range entries {
+				require.True(t, e.Public.Null())
+				require.True(t, e.Secret.Null())
+			}
+		})
+	}
+}
+
+func TestAddAddresses(t *testing.T) {
+	addrs := makeAddresses(3)
+	w, err := NewWallet("test.wlt", "test", addrs[:1])
+	require.NoError(t, err)
+
+	require.NoError(t, w.AddAddresses(addrs[1:]...))
+	l, err := w.EntriesLen()
+	require.NoError(t, err)
+	require.Equal(t, 3, l)
+
+	// A failed add leaves the wallet unchanged
+	newAddr := testutil.MakeAddress()
+	require.Equal(t, ErrDuplicateAddress, w.AddAddresses(newAddr, addrs[2]))
+	ok, err := w.HasEntry(newAddr)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Addresses can not be generated
+	_, err = w.GenerateAddresses(1)
+	require.Equal(t, wallet.NewError(errWatchGenerateAddrs), err)
+}
+
+func TestWalletSerializeDeserialize(t *testing.T) {
+	addrs := makeAddresses(3)
+	w, err := NewWallet("test.wlt", "test", addrs)
+	require.NoError(t, err)
+	require.NoError(t, w.SetEntryMeta(addrs[1], wallet.EntryMeta{Label: "deposit"}))
+
+	b, err := w.Serialize()
+	require.NoError(t, err)
+
+	w2, err := Loader{}.Load(b)
+	require.NoError(t, err)
+	require.Equal(t, w.Meta, w2.(*Wallet).Meta)
+
+	es, err := w.GetEntries()
+	require.NoError(t, err)
+	es2, err := w2.GetEntries()
+	require.NoError(t, err)
+	require.Equal(t, es, es2)
+	require.Equal(t, "deposit", es2[1].Label)
+}
+
+func TestCreator(t *testing.T) {
+	addrs := makeAddresses(2)
+
+	tt := []struct {
+		name      string
+		addresses []string
+		encrypt   bool
+		err       error
+	}{
+		{
+			name:      "ok",
+			addresses: []string{addrs[0].String(), addrs[1].String()},
+		},
+		{
+			name:      "invalid address",
+			addresses: []string{addrs[0].String(), "foo"},
+			err:       wallet.NewError(fmt.Errorf("invalid address %q: %v", "foo", "Invalid address length")),
+		},
+		{
+			name: "missing addresses",
+			err:  ErrMissingAddresses,
+		},
+		{
+			name:      "encrypt not supported",
+			addresses: []string{addrs[0].String()},
+			encrypt:   true,
+			err:       wallet.NewError(errWatchEncryption),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := Creator{}.Create("test.wlt", "test", "", wallet.Options{
+				Addresses: tc.addresses,
+				Encrypt:   tc.encrypt,
+				Password:  []byte("pwd"),
+				GenerateN: 1,
+			})
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			wAddrs, err := w.GetAddresses()
+			require.NoError(t, err)
+			require.Len(t, wAddrs, len(tc.addresses))
+			for i, a := range wAddrs {
+				require.Equal(t, tc.addresses[i], a.String())
+			}
+		})
+	}
+}
+
+func TestScanAddresses(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", makeAddresses(2))
+	require.NoError(t, err)
+
+	// Scanning a wallet with no keys is a no-op
+	addrs, err := w.ScanAddresses(10, nil)
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+
+	l, err := w.EntriesLen()
+	require.NoError(t, err)
+	require.Equal(t, 2, l)
+}
+
+func TestSignTransaction(t *testing.T) {
+	addrs := makeAddresses(1)
+	w, err := NewWallet("test.wlt", "test", addrs)
+	require.NoError(t, err)
+
+	ux := coin.UxOut{
+		Body: coin.UxBody{
+			SrcTransaction: testutil.RandSHA256(t),
+			Address:        addrs[0].(cipher.Address),
+			Coins:          1e6,
+			Hours:          100,
+		},
+	}
+	txn := &coin.Transaction{}
+	require.NoError(t, txn.PushInput(ux.Hash()))
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 1e6, 50))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+
+	_, err = wallet.SignTransaction(w, txn, nil, []coin.UxOut{ux})
+	require.Equal(t, wallet.ErrWalletCantSign, err)
+}
+
+func TestSignTransactionKeyring(t *testing.T) {
+	_, sk := cipher.GenerateKeyPair()
+	s, err := signer.NewSecKeySigner(sk)
+	require.NoError(t, err)
+	addr := cipher.AddressFromPubKey(s.PubKey())
+
+	w, err := Creator{}.Create("test.wlt", "test", "", wallet.Options{
+		Addresses: []string{addr.String()},
+		Signer:    "hsm",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "hsm", w.Signer())
+
+	makeTxn := func(addr cipher.Address) (*coin.Transaction, coin.UxOut) {
+		ux := coin.UxOut{
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        addr,
+				Coins:          1e6,
+				Hours:          100,
+			},
+		}
+		txn := &coin.Transaction{}
+		require.NoError(t, txn.PushInput(ux.Hash()))
+		require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 1e6, 50))
+		txn.Sigs = make([]cipher.Sig, len(txn.In))
+		require.NoError(t, txn.UpdateHeader())
+		return txn, ux
+	}
+
+	txn, ux := makeTxn(addr)
+	_, err = wallet.SignTransaction(w, txn, nil, []coin.UxOut{ux})
+	require.Equal(t, wallet.ErrKeyringRequired, err)
+
+	require.NoError(t, wallet.RegisterKeyring("hsm", signer.NewKeyring(s)))
+	defer wallet.UnregisterKeyring("hsm")
+
+	signedTxn, err := wallet.SignTransaction(w, txn, nil, []coin.UxOut{ux})
+	require.NoError(t, err)
+	require.True(t, signedTxn.IsFullySigned())
+	require.NoError(t, signedTxn.VerifyInputSignatures([]coin.UxOut{ux}))
+
+	// The keyring has no key of other watch addresses
+	other := testutil.MakeAddress()
+	require.NoError(t, w.(*Wallet).AddAddresses(other))
+	txn, ux = makeTxn(other)
+	_, err = wallet.SignTransaction(w, txn, nil, []coin.UxOut{ux})
+	require.Equal(t, wallet.NewError(fmt.Errorf("Wallet cannot sign all requested inputs")), err)
+
+	require.Equal(t, wallet.ErrWalletSigner, wallet.Options{
+		Type:   wallet.WalletTypeBip44,
+		Signer: "hsm",
+	}.Validate())
+}
@@ -85,6 +85,8 @@ func (es readableXPubEntries) toXPubEntries(ad wallet.AddressDecoder) (wallet.En
 			Address:     addr,
 			Public:      p,
 			ChildNumber: e.ChildNumber,
+			Change:      e.Change,
+			EntryMeta:   e.ToEntryMeta(),
 		}
 	}
 
@@ -96,9 +98,11 @@ func newReadableEntries(entries wallet.Entries) readableXPubEntries {
 	res = make([]readableXPubEntry, len(entries))
 	for i, e := range entries {
 		res[i] = readableXPubEntry{
-			Address:     e.Address.String(),
-			Public:      e.Public.Hex(),
-			ChildNumber: e.ChildNumber,
+			Address:           e.Address.String(),
+			Public:            e.Public.Hex(),
+			ChildNumber:       e.ChildNumber,
+			Change:            e.Change,
+			ReadableEntryMeta: wallet.NewReadableEntryMeta(e.EntryMeta),
 		}
 	}
 
@@ -108,5 +112,7 @@ func newReadableEntries(entries wallet.Entries) readableXPubEntries {
 type readableXPubEntry struct {
 	Address     string `json:"address"`
 	Public      string `json:"public"`
-	ChildNumber uint32 `json:"child_number"` // For bip32/bip44
+	ChildNumber uint32 `json:"child_number"`     // For bip32/bip44
+	Change      uint32 `json:"change,omitempty"` // 1 for the change chain of the account xpub wallets
+	wallet.ReadableEntryMeta
 }
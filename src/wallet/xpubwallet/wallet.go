@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
 	"github.com/skycoin/skycoin/src/util/logging"
 	"github.com/skycoin/skycoin/src/util/mathutil"
 	"github.com/skycoin/skycoin/src/wallet"
@@ -19,6 +20,9 @@ import (
 const WalletType = "xpub"
 
 var defaultWalletDecoder = &JSONDecoder{}
+
+// errNoChangeChain is returned when using the change chain of a none account xpub wallet
+var errNoChangeChain = wallet.NewError(errors.New("xpub wallet has no change chain, the xpub is not an account key"))
 var logger = logging.MustGetLogger("xpubwallet")
 
 func init() {
@@ -35,8 +39,12 @@ func init() {
 // Refer to the bip32 spec to understand xpub keys.
 // XPub wallets can generate new addresses and receive coins, but can't spend coins
 // because the private keys are not available.
+// If the xpub is a bip44 account key, the addresses are derived on its external
+// and change chains, the same way the corresponding bip44 wallet derives them.
+// Otherwise the addresses are the children of the xpub key.
 type Wallet struct {
 	wallet.Meta
+	wallet.Seeds
 	entries wallet.Entries
 	xpub    *bip32.PublicKey
 	decoder wallet.Decoder
@@ -163,20 +171,76 @@ func (w *Wallet) Fingerprint() string {
 	// Note: the xpub key is not used as the fingerprint, because it is
 	// partially sensitive data
 	addr := ""
-	if len(w.entries) == 0 {
-		entries, err := w.generateEntries(1, 0)
+	if es := w.chainEntries(bip44.ExternalChainIndex); len(es) == 0 {
+		key, err := w.chainKey(bip44.ExternalChainIndex)
+		if err != nil {
+			logger.WithError(err).Panic("Fingerprint failed to derive the external chain key")
+		}
+		entries, err := generateEntries(key, w.Coin(), w.AddressEncoding(), 1, 0)
 		if err != nil {
 			logger.WithError(err).Panic("Fingerprint failed to generate initial entry for empty wallet")
 		}
 		addr = entries[0].Address.String()
 	} else {
-		addr = w.entries[0].Address.String()
+		addr = es[0].Address.String()
 	}
 
 	return fmt.Sprintf("%s-%s", w.Type(), addr)
 }
 
-func (w *Wallet) generateEntries(num uint64, initialChildIdx uint32) (wallet.Entries, error) {
+// chainKey returns the key of the chain, which is the xpub key for the external chain of none account xpub wallets
+func (w *Wallet) chainKey(chain uint32) (*bip32.PublicKey, error) {
+	if !w.IsXPubAccount() {
+		if chain != bip44.ExternalChainIndex {
+			return nil, errNoChangeChain
+		}
+		return w.xpub, nil
+	}
+
+	switch chain {
+	case bip44.ExternalChainIndex, bip44.ChangeChainIndex:
+		return w.xpub.NewPublicChildKey(chain)
+	default:
+		return nil, fmt.Errorf("invalid chain index: %d", chain)
+	}
+}
+
+// chainEntries returns the entries of the chain
+func (w *Wallet) chainEntries(chain uint32) wallet.Entries {
+	var es wallet.Entries
+	for _, e := range w.entries {
+		if e.Change == chain {
+			es = append(es, e)
+		}
+	}
+	return es
+}
+
+// selectEntries returns the entries of the chains selected by the options,
+// the entries of the external chain are followed by the entries of the change chain
+func (w *Wallet) selectEntries(options ...wallet.Option) (wallet.Entries, error) {
+	opts := getOptions(options...)
+	switch opts.ChainMode {
+	case wallet.DefaultChain, wallet.AllChains:
+		return append(w.chainEntries(bip44.ExternalChainIndex), w.chainEntries(bip44.ChangeChainIndex)...), nil
+	case wallet.ExternalChain:
+		return w.chainEntries(bip44.ExternalChainIndex), nil
+	case wallet.ChangeChain:
+		return w.chainEntries(bip44.ChangeChainIndex), nil
+	default:
+		return nil, fmt.Errorf("unknown chain mode: %d", opts.ChainMode)
+	}
+}
+
+func getOptions(options ...wallet.Option) *wallet.Bip44EntriesOptions {
+	v := &wallet.Bip44EntriesOptions{}
+	for _, opt := range options {
+		opt(v)
+	}
+	return v
+}
+
+func generateEntries(key *bip32.PublicKey, coin wallet.CoinType, enc wallet.AddressEncoding, num uint64, initialChildIdx uint32) (wallet.Entries, error) {
 	if num > math.MaxUint32 {
 		return nil, wallet.NewError(errors.New("XPubWallet.generateEntries num too large"))
 	}
@@ -196,7 +260,7 @@ func (w *Wallet) generateEntries(num uint64, initialChildIdx uint32) (wallet.Ent
 	var addressIndices []uint32
 	j := initialChildIdx
 	for i := uint32(0); i < uint32(num); i++ {
-		k, err := w.xpub.NewPublicChildKey(j)
+		k, err := key.NewPublicChildKey(j)
 
 		var addErr error
 		j, addErr = mathutil.AddUint32(j, 1)
@@ -224,8 +288,12 @@ func (w *Wallet) generateEntries(num uint64, initialChildIdx uint32) (wallet.Ent
 		addressIndices = append(addressIndices, j-1)
 	}
 
+	addressFromPubKey, err := wallet.ResolveAddressConstructor(coin, enc)
+	if err != nil {
+		return nil, err
+	}
+
 	entries := make(wallet.Entries, len(pubkeys))
-	addressFromPubKey := wallet.ResolveAddressDecoder(w.Coin()).AddressFromPubKey
 	for i, xp := range pubkeys {
 		pk := cipher.MustNewPubKey(xp.Key)
 		entries[i] = wallet.Entry{
@@ -270,9 +338,14 @@ func (w *Wallet) Accounts() []wallet.Bip44Account {
 	return nil
 }
 
-// GetEntries returns a copy of all entries held by the wallet
-func (w *Wallet) GetEntries(_ ...wallet.Option) (wallet.Entries, error) {
-	return w.entries.Clone(), nil
+// GetEntries returns a copy of the entries of the chains selected by the options,
+// the entries of both chains if no chain is selected
+func (w *Wallet) GetEntries(options ...wallet.Option) (wallet.Entries, error) {
+	es, err := w.selectEntries(options...)
+	if err != nil {
+		return nil, err
+	}
+	return es.Clone(), nil
 }
 
 // Erase removes sensitive data
@@ -280,6 +353,8 @@ func (w *Wallet) Erase() {
 }
 
 // ScanAddresses scans ahead N addresses, truncating up to the highest address with any transaction history.
+// The external and change chains of the account xpub wallets are both scanned,
+// only the external addresses are returned.
 func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]cipher.Addresser, error) {
 	if scanN == 0 {
 		return nil, nil
@@ -287,10 +362,29 @@ func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]ci
 
 	w2 := w.Clone().(*Wallet)
 
-	nExistingAddrs := uint64(len(w2.entries))
+	addrs, err := w2.scanChain(bip44.ExternalChainIndex, scanN, tf)
+	if err != nil {
+		return nil, err
+	}
+
+	if w2.IsXPubAccount() {
+		if _, err := w2.scanChain(bip44.ChangeChainIndex, scanN, tf); err != nil {
+			return nil, err
+		}
+	}
+
+	*w = *w2
+
+	return addrs, nil
+}
+
+// scanChain scans ahead N addresses on the chain, keeping the addresses up to the highest address
+// with any transaction history. Returns the kept addresses.
+func (w *Wallet) scanChain(chain uint32, scanN uint64, tf wallet.TransactionsFinder) ([]cipher.Addresser, error) {
+	nExistingEntries := len(w.entries)
 
 	// Generate the addresses to scan
-	addrs, err := w2.GenerateAddresses(scanN)
+	addrs, err := w.generateAddresses(chain, scanN)
 	if err != nil {
 		return nil, err
 	}
@@ -302,47 +396,71 @@ func (w *Wallet) ScanAddresses(scanN uint64, tf wallet.TransactionsFinder) ([]ci
 	}
 
 	// Check activity from the last one until we find the address that has activity
-	var keepNum uint64
+	var keepNum int
 	for i := len(active) - 1; i >= 0; i-- {
 		if active[i] {
-			keepNum = uint64(i + 1)
+			keepNum = i + 1
 			break
 		}
 	}
 
-	w2.reset()
-	if _, err := w2.GenerateAddresses(nExistingAddrs + keepNum); err != nil {
-		return nil, err
-	}
-
-	*w = *w2
+	// The scanned entries are the last entries of the wallet
+	w.entries = w.entries[:nExistingEntries+keepNum]
 
 	return addrs[:keepNum], nil
 }
 
-// GetAddresses returns all addresses of the wallet
-func (w *Wallet) GetAddresses(_ ...wallet.Option) ([]cipher.Addresser, error) {
-	return w.entries.GetAddresses(), nil
+// GetAddresses returns the addresses of the chains selected by the options,
+// the addresses of both chains if no chain is selected
+func (w *Wallet) GetAddresses(options ...wallet.Option) ([]cipher.Addresser, error) {
+	es, err := w.selectEntries(options...)
+	if err != nil {
+		return nil, err
+	}
+	return es.GetAddresses(), nil
 }
 
-// GenerateAddresses generates addresses for the external chain, and appends them to the wallet's entries array
-func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Addresser, error) {
+// GenerateAddresses generates addresses for the external chain, or the change chain of the account
+// xpub wallets if it is selected by the options, and appends them to the wallet's entries array
+func (w *Wallet) GenerateAddresses(num uint64, options ...wallet.Option) ([]cipher.Addresser, error) {
+	opts := getOptions(options...)
+	switch opts.ChainMode {
+	case wallet.DefaultChain, wallet.ExternalChain:
+		return w.generateAddresses(bip44.ExternalChainIndex, num)
+	case wallet.ChangeChain:
+		return w.generateAddresses(bip44.ChangeChainIndex, num)
+	case wallet.AllChains:
+		return nil, errors.New("could not generate new addresses on both external and internal chains at once")
+	default:
+		return nil, fmt.Errorf("unknown chain mode: %d", opts.ChainMode)
+	}
+}
+
+func (w *Wallet) generateAddresses(chain uint32, num uint64) ([]cipher.Addresser, error) {
 	if num > math.MaxUint32 {
 		return nil, wallet.NewError(errors.New("XPubWallet.GenerateAddresses num too large"))
 	}
 
+	key, err := w.chainKey(chain)
+	if err != nil {
+		return nil, err
+	}
+
 	var addrs []cipher.Addresser
-	initLen := uint32(len(w.entries))
-	_, err := mathutil.AddUint32(initLen, uint32(num))
+	initLen := uint32(len(w.chainEntries(chain)))
+	_, err = mathutil.AddUint32(initLen, uint32(num))
 	if err != nil {
 		return nil, fmt.Errorf("generate %d more addresses failed: %v", num, err)
 	}
 
-	makeAddress := wallet.ResolveAddressDecoder(w.Coin())
+	addressFromPubKey, err := wallet.ResolveAddressConstructor(w.Coin(), w.AddressEncoding())
+	if err != nil {
+		return nil, err
+	}
 
 	for i := uint32(0); i < uint32(num); i++ {
 		index := initLen + i
-		pk, err := w.xpub.NewPublicChildKey(index)
+		pk, err := key.NewPublicChildKey(index)
 		if err != nil {
 			return nil, err
 		}
@@ -351,11 +469,12 @@ func (w *Wallet) GenerateAddresses(num uint64, _ ...wallet.Option) ([]cipher.Add
 			return nil, err
 		}
 
-		addr := makeAddress.AddressFromPubKey(cpk)
+		addr := addressFromPubKey(cpk)
 		e := wallet.Entry{
 			Address:     addr,
 			Public:      cpk,
 			ChildNumber: index,
+			Change:      chain,
 		}
 
 		w.entries = append(w.entries, e)
@@ -373,12 +492,17 @@ func parseXPub(xp string) (*bip32.PublicKey, error) {
 	return xPub, nil
 }
 
-// GetEntryAt returns the entry at a given index in the entries array
-func (w *Wallet) GetEntryAt(i int, _ ...wallet.Option) (wallet.Entry, error) {
-	if i < 0 || i >= len(w.entries) {
+// GetEntryAt returns the entry at a given index of the entries of the chains selected by the options
+func (w *Wallet) GetEntryAt(i int, options ...wallet.Option) (wallet.Entry, error) {
+	es, err := w.selectEntries(options...)
+	if err != nil {
+		return wallet.Entry{}, err
+	}
+
+	if i < 0 || i >= len(es) {
 		return wallet.Entry{}, fmt.Errorf("entry index %d is out of range", i)
 	}
-	return w.entries[i], nil
+	return es[i], nil
 }
 
 // GetEntry returns a entry of given address
@@ -390,14 +514,35 @@ func (w *Wallet) GetEntry(addr cipher.Addresser, _ ...wallet.Option) (wallet.Ent
 	return e, nil
 }
 
+// GetEntryMeta returns the label, note and tags of the entry of given address
+func (w *Wallet) GetEntryMeta(addr cipher.Addresser, _ ...wallet.Option) (wallet.EntryMeta, error) {
+	e, ok := w.entries.Get(addr)
+	if !ok {
+		return wallet.EntryMeta{}, wallet.ErrEntryNotFound
+	}
+	return e.EntryMeta.Clone(), nil
+}
+
+// SetEntryMeta sets the label, note and tags of the entry of given address
+func (w *Wallet) SetEntryMeta(addr cipher.Addresser, m wallet.EntryMeta, _ ...wallet.Option) error {
+	if !w.entries.SetMeta(addr, m) {
+		return wallet.ErrEntryNotFound
+	}
+	return nil
+}
+
 // HasEntry returns true if the wallet has an Entry with a given address
 func (w *Wallet) HasEntry(addr cipher.Addresser, _ ...wallet.Option) (bool, error) {
 	return w.entries.Has(addr), nil
 }
 
-// EntriesLen returns the number of entries in the wallet
-func (w *Wallet) EntriesLen(_ ...wallet.Option) (int, error) {
-	return len(w.entries), nil
+// EntriesLen returns the number of entries of the chains selected by the options
+func (w *Wallet) EntriesLen(options ...wallet.Option) (int, error) {
+	es, err := w.selectEntries(options...)
+	if err != nil {
+		return 0, err
+	}
+	return len(es), nil
 }
 
 // reset resets the wallet entries and move the lastSeed to origin
@@ -457,6 +602,14 @@ func convertOptions(options wallet.Options) []wallet.Option {
 		opts = append(opts, wallet.OptionGenerateN(options.GenerateN))
 	}
 
+	if options.XPubAccount {
+		opts = append(opts, wallet.OptionXPubAccount(true))
+	}
+
+	if options.AddressEncoding != "" {
+		opts = append(opts, wallet.OptionAddressEncoding(options.AddressEncoding))
+	}
+
 	if options.ScanN > 0 {
 		opts = append(opts, wallet.OptionScanN(options.ScanN))
 		opts = append(opts, wallet.OptionTransactionsFinder(options.TF))
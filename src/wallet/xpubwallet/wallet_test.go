@@ -15,6 +15,9 @@ import (
 
 var testXPub = "xpub6EMRsT95ntbCFRR2Z6WppnGss1SijAkarfKoRM8tft66tuJh2nt4aJi13S21hUCLZL4cbFBXgHuxipmsS7dj1DW1s4NRup3hzxWfqUdGYv7"
 
+// testAccountXPub is the bip44 account key whose external chain key is testXPub
+var testAccountXPub = "xpub6CfqefC5a2H3FzGXDwrnvCZPxLYMGjBamh1Kqky32oHPK7YM8hK8nemGfUSw1rVqzEABNR1AQA48ijjexK5hs3rMobAkr9UwgZpe6B22MV1"
+
 type fakeWalletDecoder struct{}
 
 func (d fakeWalletDecoder) Encode(w wallet.Wallet) ([]byte, error) {
@@ -33,6 +36,15 @@ var (
 		"2WNKEdCvoR8Mv5a7J5bLeE9syq7vHSzACmk",
 		"2Z1ZcRWwsyiRqTYLm6VJF914FAE8uhfgmkX",
 	})
+
+	// testSkycoinChangeAddresses are the change chain addresses of testAccountXPub
+	testSkycoinChangeAddresses = stringsToAddresses([]string{
+		"WFonrBarSSMPwFzcE9CS8vDbqmLjLZaJbT",
+		"hiCAv4i9xxtMYXz6Dpwgi1d5Tu1uGzk3Xd",
+		"LvV4KEy2pyAmtWXuqYNB2yqAFzN7m6FPme",
+		"2Y8fDayjHFSTkVQkCBAAHFuw7gLqZTNmdwr",
+		"28JNdPAxvc7yif4gnMhSVLjWh94WTxq9X8y",
+	})
 )
 
 func stringsToAddresses(addrsStr []string) []cipher.Addresser {
@@ -299,6 +311,37 @@ func TestWalletDeserialize(t *testing.T) {
 	require.Equal(t, testXPub, w.XPub())
 }
 
+func TestWalletAddressEncoding(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", testXPub,
+		wallet.OptionCoinType(wallet.CoinTypeBitcoin),
+		wallet.OptionAddressEncoding(wallet.AddressEncodingBech32))
+	require.NoError(t, err)
+
+	addrs, err := w.GenerateAddresses(3)
+	require.NoError(t, err)
+
+	// The bech32 addresses have the pubkey hashes of the base58 addresses
+	lw, err := NewWallet("test.wlt", "test", testXPub, wallet.OptionCoinType(wallet.CoinTypeBitcoin))
+	require.NoError(t, err)
+	baddrs, err := lw.GenerateAddresses(3)
+	require.NoError(t, err)
+	for i, a := range addrs {
+		require.Equal(t, "bc1q", a.String()[:4])
+		require.Equal(t, baddrs[i].(cipher.BitcoinAddress).Key, a.(cipher.BitcoinBech32Address).Key)
+	}
+
+	b, err := w.Serialize()
+	require.NoError(t, err)
+	wlt := Wallet{}
+	require.NoError(t, wlt.Deserialize(b))
+	require.Equal(t, wallet.AddressEncodingBech32, wlt.AddressEncoding())
+
+	laddrs, err := wlt.GetAddresses()
+	require.NoError(t, err)
+	require.Equal(t, addrs, laddrs)
+	require.Equal(t, "xpub-"+addrs[0].String(), wlt.Fingerprint())
+}
+
 type mockTxnsFinder map[cipher.Addresser]bool
 
 func (mb mockTxnsFinder) AddressesActivity(addrs []cipher.Addresser) ([]bool, error) {
@@ -438,3 +481,145 @@ func TestScanAddresses(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletXPubAccount(t *testing.T) {
+	w, err := NewWallet("test.wlt", "test", testAccountXPub, wallet.OptionXPubAccount(true))
+	require.NoError(t, err)
+	require.True(t, w.IsXPubAccount())
+
+	// the account wallet has the same fingerprint as the wallet of the external chain key
+	w2, err := NewWallet("test.wlt", "test", testXPub)
+	require.NoError(t, err)
+	require.Equal(t, w2.Fingerprint(), w.Fingerprint())
+
+	addrs, err := w.GenerateAddresses(3)
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinAddresses[:3], addrs)
+
+	addrs, err = w.GenerateAddresses(2, wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinChangeAddresses[:2], addrs)
+
+	_, err = w.GenerateAddresses(1, wallet.OptionExternal(), wallet.OptionChange())
+	require.Error(t, err)
+
+	addrs, err = w.GetAddresses(wallet.OptionExternal())
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinAddresses[:3], addrs)
+
+	addrs, err = w.GetAddresses(wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinChangeAddresses[:2], addrs)
+
+	addrs, err = w.GetAddresses()
+	require.NoError(t, err)
+	require.Equal(t, append(testSkycoinAddresses[:3:3], testSkycoinChangeAddresses[:2]...), addrs)
+
+	n, err := w.EntriesLen(wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	e, err := w.GetEntryAt(1, wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinChangeAddresses[1], e.Address)
+	require.Equal(t, uint32(1), e.ChildNumber)
+	require.Equal(t, uint32(1), e.Change)
+
+	e, err = w.GetEntry(testSkycoinChangeAddresses[0])
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), e.Change)
+
+	// the chains are kept after a serialization round trip
+	b, err := w.Serialize()
+	require.NoError(t, err)
+
+	w3 := Wallet{}
+	err = w3.Deserialize(b)
+	require.NoError(t, err)
+	require.True(t, w3.IsXPubAccount())
+
+	addrs, err = w3.GenerateAddresses(1, wallet.OptionChange())
+	require.NoError(t, err)
+	require.Equal(t, testSkycoinChangeAddresses[2:3], addrs)
+
+	// the wallet of a none account xpub has no change chain
+	_, err = w2.GenerateAddresses(1, wallet.OptionChange())
+	require.Equal(t, errNoChangeChain, err)
+
+	_, err = NewWallet("test.wlt", "test", testAccountXPub, wallet.OptionXPubAccount(false))
+	require.NoError(t, err)
+}
+
+func TestScanAddressesXPubAccount(t *testing.T) {
+	tt := []struct {
+		name              string
+		opts              []wallet.Option
+		scanN             uint64
+		txnFinder         wallet.TransactionsFinder
+		expectAddrs       []cipher.Addresser
+		expectChangeAddrs []cipher.Addresser
+	}{
+		{
+			name:              "no txns",
+			scanN:             10,
+			txnFinder:         mockTxnsFinder{},
+			expectAddrs:       []cipher.Addresser{},
+			expectChangeAddrs: []cipher.Addresser{},
+		},
+		{
+			name:  "external and change addrs with txns",
+			scanN: 10,
+			txnFinder: mockTxnsFinder{
+				testSkycoinAddresses[1]:       true,
+				testSkycoinChangeAddresses[3]: true,
+			},
+			expectAddrs:       testSkycoinAddresses[:2],
+			expectChangeAddrs: testSkycoinChangeAddresses[:4],
+		},
+		{
+			name:  "change addrs with txns only",
+			scanN: 10,
+			txnFinder: mockTxnsFinder{
+				testSkycoinChangeAddresses[0]: true,
+			},
+			expectAddrs:       []cipher.Addresser{},
+			expectChangeAddrs: testSkycoinChangeAddresses[:1],
+		},
+		{
+			name: "init 1, external and change addrs with txns",
+			opts: []wallet.Option{
+				wallet.OptionGenerateN(1),
+			},
+			scanN: 10,
+			txnFinder: mockTxnsFinder{
+				testSkycoinAddresses[2]:       true,
+				testSkycoinChangeAddresses[1]: true,
+			},
+			expectAddrs:       testSkycoinAddresses[:3],
+			expectChangeAddrs: testSkycoinChangeAddresses[:2],
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]wallet.Option{wallet.OptionXPubAccount(true)}, tc.opts...)
+			w, err := NewWallet("test.wlt", "test", testAccountXPub, opts...)
+			require.NoError(t, err)
+
+			n, err := w.EntriesLen(wallet.OptionExternal())
+			require.NoError(t, err)
+
+			addrs, err := w.ScanAddresses(tc.scanN, tc.txnFinder)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectAddrs[n:], addrs)
+
+			addrs, err = w.GetAddresses(wallet.OptionExternal())
+			require.NoError(t, err)
+			require.Equal(t, tc.expectAddrs, addrs)
+
+			addrs, err = w.GetAddresses(wallet.OptionChange())
+			require.NoError(t, err)
+			require.Equal(t, tc.expectChangeAddrs, addrs)
+		})
+	}
+}